@@ -0,0 +1,233 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a JSON schema as OpenAPI 3.0 describes one
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Enum                 []any              `yaml:"enum"`
+	Required             []string           `yaml:"required"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	AdditionalProperties Additional         `yaml:"additionalProperties"`
+	Items                *Schema            `yaml:"items"`
+	Minimum              *float64           `yaml:"minimum"`
+	Maximum              *float64           `yaml:"maximum"`
+	MinLength            *int               `yaml:"minLength"`
+	MaxLength            *int               `yaml:"maxLength"`
+}
+
+// Additional is the additionalProperties of an object schema: true, or a
+// schema every property not listed in properties must match. Left unset,
+// objects with listed properties may have no others.
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalYAML decodes a boolean or a schema
+func (a *Additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+	a.Allowed = true
+	return node.Decode(&a.Schema)
+}
+
+// ValidationError is a value that does not match its schema
+type ValidationError struct {
+	Path    string // JSON path of the value, such as tags[0].key
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// validate checks a value decoded from JSON with UseNumber against schema
+func (s *Spec) validate(schema *Schema, v any, path string) error {
+	schema = s.schema(schema)
+	if schema == nil {
+		return nil
+	}
+	fail := func(format string, args ...any) error {
+		return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, e := range schema.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fail("must be one of %s", enumList(schema.Enum))
+		}
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fail("expected an object, got %s", kind(v))
+		}
+		return s.validateObject(schema, obj, path)
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return fail("expected an array, got %s", kind(v))
+		}
+		for i, item := range items {
+			if err := s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fail("expected a string, got %s", kind(v))
+		}
+		return checkString(schema, str, fail)
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fail("expected %s, got %s", article(schema.Type), kind(v))
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return fail("expected %s, got %s", article(schema.Type), n)
+		}
+		if schema.Type == "integer" {
+			if _, err := n.Int64(); err != nil {
+				return fail("expected an integer, got %s", n)
+			}
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			return fail("must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			return fail("must be at most %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fail("expected a boolean, got %s", kind(v))
+		}
+	}
+	return nil
+}
+
+func (s *Spec) validateObject(schema *Schema, obj map[string]any, path string) error {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			return &ValidationError{Path: join(path, name), Message: "is required"}
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if obj[name] == nil && !slices.Contains(schema.Required, name) {
+			continue // an optional field may be null
+		}
+		prop, listed := schema.Properties[name]
+		switch {
+		case listed:
+		case schema.AdditionalProperties.Schema != nil:
+			prop = schema.AdditionalProperties.Schema
+		case schema.AdditionalProperties.Allowed || len(schema.Properties) == 0:
+			continue
+		default:
+			return &ValidationError{Path: join(path, name), Message: "is not a known field"}
+		}
+		if err := s.validate(prop, obj[name], join(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkString checks a string's format and length
+func checkString(schema *Schema, str string, fail func(format string, args ...any) error) error {
+	switch schema.Format {
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			return fail("expected a UUID, got %q", str)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			return fail("expected an RFC 3339 date-time, got %q", str)
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, str); err != nil {
+			return fail("expected a date, got %q", str)
+		}
+	}
+	n := len([]rune(str))
+	if schema.MinLength != nil && n < *schema.MinLength {
+		return fail("must be at least %d characters", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && n > *schema.MaxLength {
+		return fail("must be at most %d characters", *schema.MaxLength)
+	}
+	return nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// kind describes the JSON type of a value for error messages
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func article(typ string) string {
+	if typ == "integer" {
+		return "an integer"
+	}
+	return "a " + typ
+}
+
+func enumList(values []any) string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = fmt.Sprint(v)
+	}
+	return strings.Join(strs, ", ")
+}
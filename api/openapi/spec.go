@@ -0,0 +1,300 @@
+// Package openapi embeds the OpenAPI spec of the REST API in
+// openapi.yaml and validates HTTP requests and responses against it.
+//
+// The spec is written by hand and kept in step with the registered routes
+// by the API tests. The server serves it as JSON, rejects requests whose
+// parameters or JSON bodies do not match it and can check its own
+// responses, so the spec the clients are generated from stays the contract
+// the server enforces.
+//
+// Validation understands the subset of OpenAPI 3.0 the spec uses: path and
+// query parameters, JSON request and response bodies, and schemas built
+// from type, format, enum, required, properties, additionalProperties,
+// items, minimum, maximum, minLength, maxLength and $ref.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// Spec is a loaded OpenAPI spec
+type Spec struct {
+	json   []byte
+	routes []*route
+
+	schemas    map[string]*Schema
+	parameters map[string]*Parameter
+	responses  map[string]*Response
+}
+
+// document is the part of an OpenAPI document validation reads
+type document struct {
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Components struct {
+		Schemas    map[string]*Schema    `yaml:"schemas"`
+		Parameters map[string]*Parameter `yaml:"parameters"`
+		Responses  map[string]*Response  `yaml:"responses"`
+	} `yaml:"components"`
+}
+
+type operation struct {
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+// RequestBody is the body an operation accepts
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a response an operation returns
+type Response struct {
+	Ref     string                `yaml:"$ref"`
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// route is an operation with its path template split into segments
+type route struct {
+	method     string
+	template   string
+	segments   []string
+	parameters []*Parameter
+	body       *RequestBody
+	responses  map[string]*Response
+}
+
+// Load returns the embedded spec of the REST API. It is parsed once and
+// shared; a Spec is not modified after parsing.
+func Load() (*Spec, error) {
+	return loadEmbedded()
+}
+
+var loadEmbedded = sync.OnceValues(func() (*Spec, error) {
+	return Parse(specYAML)
+})
+
+// Parse loads a spec from its YAML or JSON source
+func Parse(data []byte) (*Spec, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	asJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert OpenAPI spec to JSON: %w", err)
+	}
+
+	s := &Spec{
+		json:       asJSON,
+		schemas:    doc.Components.Schemas,
+		parameters: doc.Components.Parameters,
+		responses:  doc.Components.Responses,
+	}
+	for template, item := range doc.Paths {
+		var shared []*Parameter
+		if node, ok := item["parameters"]; ok {
+			if err := node.Decode(&shared); err != nil {
+				return nil, fmt.Errorf("invalid parameters of %s: %w", template, err)
+			}
+		}
+		for method, node := range item {
+			if method == "parameters" {
+				continue
+			}
+			var op operation
+			if err := node.Decode(&op); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", strings.ToUpper(method), template, err)
+			}
+			r := &route{
+				method:    strings.ToUpper(method),
+				template:  template,
+				segments:  strings.Split(strings.Trim(template, "/"), "/"),
+				body:      op.RequestBody,
+				responses: op.Responses,
+			}
+			for _, p := range append(append([]*Parameter{}, shared...), op.Parameters...) {
+				resolved, err := s.parameter(p)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", r.method, template, err)
+				}
+				r.parameters = append(r.parameters, resolved)
+			}
+			s.routes = append(s.routes, r)
+		}
+	}
+	if err := s.checkRefs(); err != nil {
+		return nil, err
+	}
+
+	// Literal segments sort before parameters, so that /outages/export is
+	// matched before /outages/{id}
+	sort.Slice(s.routes, func(i, j int) bool {
+		if ki, kj := s.routes[i].sortKey(), s.routes[j].sortKey(); ki != kj {
+			return ki < kj
+		}
+		return s.routes[i].method < s.routes[j].method
+	})
+	return s, nil
+}
+
+// JSON returns the spec encoded as JSON
+func (s *Spec) JSON() []byte {
+	return s.json
+}
+
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := s.parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter %s", p.Ref)
+	}
+	return resolved, nil
+}
+
+func (s *Spec) response(r *Response) *Response {
+	if r.Ref == "" {
+		return r
+	}
+	return s.responses[strings.TrimPrefix(r.Ref, "#/components/responses/")]
+}
+
+// schema resolves a schema reference
+func (s *Spec) schema(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// checkRefs fails if a schema or response references one the spec does
+// not define, so validation never meets a dangling reference
+func (s *Spec) checkRefs() error {
+	var check func(schema *Schema, at string) error
+	seen := map[*Schema]bool{}
+	check = func(schema *Schema, at string) error {
+		if schema == nil || seen[schema] {
+			return nil
+		}
+		seen[schema] = true
+		if schema.Ref != "" {
+			if s.schema(schema) == nil {
+				return fmt.Errorf("%s: unknown schema %s", at, schema.Ref)
+			}
+			return nil
+		}
+		for name, prop := range schema.Properties {
+			if err := check(prop, at+"."+name); err != nil {
+				return err
+			}
+		}
+		if err := check(schema.Items, at+"[]"); err != nil {
+			return err
+		}
+		return check(schema.AdditionalProperties.Schema, at+"{}")
+	}
+
+	for name, schema := range s.schemas {
+		if err := check(schema, name); err != nil {
+			return err
+		}
+	}
+	for _, r := range s.routes {
+		at := r.method + " " + r.template
+		for _, p := range r.parameters {
+			if err := check(p.Schema, at+" "+p.Name); err != nil {
+				return err
+			}
+		}
+		if r.body != nil {
+			for _, media := range r.body.Content {
+				if err := check(media.Schema, at+" request body"); err != nil {
+					return err
+				}
+			}
+		}
+		for status, resp := range r.responses {
+			resolved := s.response(resp)
+			if resolved == nil {
+				return fmt.Errorf("%s %s: unknown response %s", at, status, resp.Ref)
+			}
+			for _, media := range resolved.Content {
+				if err := check(media.Schema, at+" "+status+" response"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *route) sortKey() string {
+	key := make([]string, len(r.segments))
+	for i, seg := range r.segments {
+		if isParam(seg) {
+			seg = "\xff"
+		}
+		key[i] = seg
+	}
+	return strings.Join(key, "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// match returns the route for a request and its path parameters, or nil
+// when the spec does not document the request
+func (s *Spec) match(method, path string) (*route, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, r := range s.routes {
+		if r.method != method || len(r.segments) != len(segments) {
+			continue
+		}
+		params := map[string]string{}
+		matched := true
+		for i, seg := range r.segments {
+			if isParam(seg) {
+				params[seg[1:len(seg)-1]] = segments[i]
+			} else if seg != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r, params
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testSpec = `
+openapi: 3.0.3
+paths:
+  /things:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Thing'}
+      responses:
+        '201':
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Thing'}
+  /things/export:
+    get:
+      parameters:
+        - {name: format, in: query, schema: {type: string, enum: [json, csv]}}
+      responses:
+        '200': {description: ok}
+  /things/{id}:
+    parameters:
+      - {$ref: '#/components/parameters/ThingID'}
+    get:
+      parameters:
+        - {name: verbose, in: query, schema: {type: boolean}}
+        - {name: limit, in: query, schema: {type: integer, minimum: 1, maximum: 10}}
+      responses:
+        '200': {description: ok}
+components:
+  parameters:
+    ThingID: {name: id, in: path, required: true, schema: {type: string, format: uuid}}
+  schemas:
+    Thing:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, maxLength: 5}
+        size: {type: number, minimum: 0}
+        seen_at: {type: string, format: date-time}
+        labels: {type: object, additionalProperties: {type: string}}
+        parts: {type: array, items: {$ref: '#/components/schemas/Part'}}
+        extra: {type: object, additionalProperties: true, properties: {a: {type: integer}}}
+    Part:
+      type: object
+      properties:
+        kind: {type: string, enum: [bolt, nut]}
+`
+
+func TestParseEmbeddedSpec(t *testing.T) {
+	spec, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(spec.JSON(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("openapi = %v", doc["openapi"])
+	}
+	if r, params := spec.match("GET", "/api/v1/outages/export"); r == nil || r.template != "/api/v1/outages/export" || len(params) != 0 {
+		t.Errorf("match export = %+v, %v", r, params)
+	}
+	if r, params := spec.match("GET", "/api/v1/outages/abc"); r == nil || r.template != "/api/v1/outages/{id}" || params["id"] != "abc" {
+		t.Errorf("match outage = %+v, %v", r, params)
+	}
+}
+
+func TestParseRejectsUnknownRefs(t *testing.T) {
+	_, err := Parse([]byte(strings.Replace(testSpec, "'#/components/schemas/Part'", "'#/components/schemas/Missing'", 1)))
+	if err == nil || !strings.Contains(err.Error(), "unknown schema #/components/schemas/Missing") {
+		t.Errorf("err = %v", err)
+	}
+	_, err = Parse([]byte(strings.Replace(testSpec, "'#/components/parameters/ThingID'", "'#/components/parameters/Missing'", 1)))
+	if err == nil || !strings.Contains(err.Error(), "unknown parameter") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const id = "9b2f6c1e-2a4e-4f4c-9a53-5a0c2d9e7b11"
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		want        string // error, or empty when the request is valid
+	}{
+		{"valid body", "POST", "/things", "", `{"name":"a","size":1.5,"seen_at":"2024-01-02T03:04:05Z","labels":{"x":"y"},"parts":[{"kind":"nut"}],"extra":{"a":1,"b":"c"}}`, ""},
+		{"null optional field", "POST", "/things", "", `{"name":"a","size":null}`, ""},
+		{"missing body", "POST", "/things", "", ``, "request body is required"},
+		{"invalid JSON", "POST", "/things", "", `{`, "request body is not valid JSON"},
+		{"not an object", "POST", "/things", "", `[]`, "expected an object, got an array"},
+		{"missing required", "POST", "/things", "", `{}`, "name: is required"},
+		{"null required", "POST", "/things", "", `{"name":null}`, "name: expected a string, got null"},
+		{"unknown field", "POST", "/things", "", `{"name":"a","nme":"b"}`, "nme: is not a known field"},
+		{"too long", "POST", "/things", "", `{"name":"abcdef"}`, "name: must be at most 5 characters"},
+		{"below minimum", "POST", "/things", "", `{"name":"a","size":-1}`, "size: must be at least 0"},
+		{"bad date-time", "POST", "/things", "", `{"name":"a","seen_at":"yesterday"}`, `seen_at: expected an RFC 3339 date-time, got "yesterday"`},
+		{"additional property schema", "POST", "/things", "", `{"name":"a","labels":{"x":1}}`, "labels.x: expected a string, got a number"},
+		{"nested enum", "POST", "/things", "", `{"name":"a","parts":[{"kind":"nut"},{"kind":"gear"}]}`, "parts[1].kind: must be one of bolt, nut"},
+		{"listed property beside additional", "POST", "/things", "", `{"name":"a","extra":{"a":1.5}}`, "extra.a: expected an integer, got 1.5"},
+		{"other content type", "POST", "/things", "text/plain", `nope`, ""},
+		{"path parameter", "GET", "/things/" + id, "", "", ""},
+		{"bad path parameter", "GET", "/things/nope", "", "", `id: expected a UUID, got "nope"`},
+		{"literal before parameter", "GET", "/things/export?format=csv", "", "", ""},
+		{"query enum", "GET", "/things/export?format=xml", "", "", "format: must be one of json, csv"},
+		{"query boolean", "GET", "/things/" + id + "?verbose=1", "", "", ""},
+		{"bad query boolean", "GET", "/things/" + id + "?verbose=maybe", "", "", `verbose: expected a boolean, got a string`},
+		{"bad query integer", "GET", "/things/" + id + "?limit=2.5", "", "", "limit: expected an integer, got 2.5"},
+		{"query maximum", "GET", "/things/" + id + "?limit=11", "", "", "limit: must be at most 10"},
+		{"undocumented route", "DELETE", "/things/" + id, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			err := spec.ValidateRequest(req)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("err = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	handler := spec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var thing struct{ Name string }
+		json.NewDecoder(r.Body).Decode(&thing)
+		got = thing.Name
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/things", strings.NewReader(`{"name":"bolt"}`)))
+	if rr.Code != http.StatusOK || got != "bolt" {
+		t.Errorf("valid request: status = %d, handler read %q", rr.Code, got)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/things", strings.NewReader(`{"name":7}`)))
+	want := `{"error":"Invalid request: name: expected a string, got a number","code":"invalid_input"}`
+	if rr.Code != http.StatusBadRequest || strings.TrimSpace(rr.Body.String()) != want {
+		t.Errorf("invalid request: status = %d, body = %s", rr.Code, rr.Body)
+	}
+}
+
+func TestResponseChecker(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"matches", http.StatusCreated, `{"name":"a"}`, ""},
+		{"mismatch", http.StatusCreated, `{"size":1}`, "name: is required"},
+		{"undocumented success", http.StatusOK, `{"name":"a"}`, "status 200 is not documented for POST /things"},
+		{"undocumented error", http.StatusForbidden, `{"error":"no"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported error
+			handler := spec.ResponseChecker(func(r *http.Request, status int, err error) {
+				reported = err
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("POST", "/things", nil))
+			if rr.Body.String() != tt.body {
+				t.Errorf("body = %s, want it passed through", rr.Body)
+			}
+			if (reported == nil) != (tt.want == "") || (reported != nil && reported.Error() != tt.want) {
+				t.Errorf("reported %v, want %q", reported, tt.want)
+			}
+		})
+	}
+}
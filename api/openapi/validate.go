@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/conall/outalator/internal/apierr"
+	"github.com/conall/outalator/internal/bodylimit"
+)
+
+// maxCheckedResponse is the largest response body ResponseChecker validates.
+// Larger bodies, such as big exports, are passed through unchecked.
+const maxCheckedResponse = 1 << 20 // 1 MiB
+
+// ValidateRequest checks a request's path and query parameters and its JSON
+// body against the spec, restoring the body for the handler. Requests the
+// spec does not document are not checked. The returned error is a
+// *ValidationError, or the error reading the body.
+func (s *Spec) ValidateRequest(r *http.Request) error {
+	rt, pathParams := s.match(r.Method, r.URL.Path)
+	if rt == nil {
+		return nil
+	}
+
+	query := r.URL.Query()
+	for _, p := range rt.parameters {
+		var (
+			raw     string
+			present bool
+		)
+		switch p.In {
+		case "path":
+			raw, present = pathParams[p.Name]
+		case "query":
+			raw, present = query.Get(p.Name), query.Get(p.Name) != ""
+		default:
+			continue
+		}
+		if !present {
+			if p.Required {
+				return &ValidationError{Path: p.Name, Message: fmt.Sprintf("%s parameter is required", p.In)}
+			}
+			continue
+		}
+		if err := s.validate(p.Schema, parameterValue(s.schema(p.Schema), raw), p.Name); err != nil {
+			return err
+		}
+	}
+
+	if rt.body == nil {
+		return nil
+	}
+	media, ok := rt.body.Content["application/json"]
+	if !ok || !isJSON(r.Header.Get("Content-Type")) || r.Body == nil {
+		return nil
+	}
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if len(bytes.TrimSpace(data)) == 0 {
+		if rt.body.Required {
+			return &ValidationError{Message: "request body is required"}
+		}
+		return nil
+	}
+	v, err := decode(data)
+	if err != nil {
+		return &ValidationError{Message: "request body is not valid JSON"}
+	}
+	return s.validate(media.Schema, v, "")
+}
+
+// ValidateResponse checks a JSON response body against the schema the spec
+// documents for the request's route and the response status. Success
+// statuses the spec does not list are reported; undocumented error
+// statuses, such as the 401 of a missing session, and bodies in other
+// content types are not checked.
+func (s *Spec) ValidateResponse(r *http.Request, status int, contentType string, body []byte) error {
+	rt, _ := s.match(r.Method, r.URL.Path)
+	if rt == nil || contentType == "" || !isJSON(contentType) {
+		return nil
+	}
+	resp, ok := rt.responses[strconv.Itoa(status)]
+	if !ok {
+		if resp, ok = rt.responses["default"]; !ok {
+			if status >= http.StatusBadRequest {
+				return nil
+			}
+			return &ValidationError{Message: fmt.Sprintf("status %d is not documented for %s %s", status, rt.method, rt.template)}
+		}
+	}
+	media, ok := s.response(resp).Content["application/json"]
+	if !ok || media.Schema == nil {
+		return nil
+	}
+	v, err := decode(body)
+	if err != nil {
+		return &ValidationError{Message: "response body is not valid JSON"}
+	}
+	return s.validate(media.Schema, v, "")
+}
+
+// Middleware rejects requests that do not match the spec with 400
+func (s *Spec) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := s.ValidateRequest(r); err != nil {
+			if bodylimit.TooLarge(err) {
+				bodylimit.RespondTooLarge(w)
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				apierr.Write(w, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+			apierr.Write(w, http.StatusBadRequest, "Invalid request: "+verr.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ResponseChecker returns middleware that validates JSON responses against
+// the spec and passes mismatches to report. Responses are sent to the
+// client unchanged; the checker is meant for development and staging, where
+// a report points at a handler and spec that have drifted apart.
+func (s *Spec) ResponseChecker(report func(r *http.Request, status int, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.overflow || rec.streamed {
+				return
+			}
+			if err := s.ValidateResponse(r, rec.status, w.Header().Get("Content-Type"), rec.body.Bytes()); err != nil {
+				report(r, rec.status, err)
+			}
+		})
+	}
+}
+
+// recorder copies a response body while writing it through
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+	streamed bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if !rec.overflow && rec.body.Len()+len(p) <= maxCheckedResponse {
+		rec.body.Write(p)
+	} else {
+		rec.overflow = true
+		rec.body.Reset()
+	}
+	return rec.ResponseWriter.Write(p)
+}
+
+// Flush supports streaming handlers, whose bodies are not checked
+func (rec *recorder) Flush() {
+	rec.streamed = true
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// parameterValue converts a parameter from its string form to the JSON
+// value its schema describes. Values that do not convert are left as
+// strings, so validation reports them as the wrong type.
+func parameterValue(schema *Schema, raw string) any {
+	if schema == nil {
+		return raw
+	}
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// isJSON reports whether a Content-Type is JSON. Requests without one are
+// treated as JSON, as the API handlers decode them.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
@@ -20,11 +20,12 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	OutageService_CreateOutage_FullMethodName = "/outalator.v1.OutageService/CreateOutage"
-	OutageService_GetOutage_FullMethodName    = "/outalator.v1.OutageService/GetOutage"
-	OutageService_ListOutages_FullMethodName  = "/outalator.v1.OutageService/ListOutages"
-	OutageService_UpdateOutage_FullMethodName = "/outalator.v1.OutageService/UpdateOutage"
-	OutageService_DeleteOutage_FullMethodName = "/outalator.v1.OutageService/DeleteOutage"
+	OutageService_CreateOutage_FullMethodName      = "/outalator.v1.OutageService/CreateOutage"
+	OutageService_GetOutage_FullMethodName         = "/outalator.v1.OutageService/GetOutage"
+	OutageService_ListOutages_FullMethodName       = "/outalator.v1.OutageService/ListOutages"
+	OutageService_UpdateOutage_FullMethodName      = "/outalator.v1.OutageService/UpdateOutage"
+	OutageService_DeleteOutage_FullMethodName      = "/outalator.v1.OutageService/DeleteOutage"
+	OutageService_GetOutageTimeline_FullMethodName = "/outalator.v1.OutageService/GetOutageTimeline"
 )
 
 // OutageServiceClient is the client API for OutageService service.
@@ -36,6 +37,7 @@ type OutageServiceClient interface {
 	ListOutages(ctx context.Context, in *ListOutagesRequest, opts ...grpc.CallOption) (*ListOutagesResponse, error)
 	UpdateOutage(ctx context.Context, in *UpdateOutageRequest, opts ...grpc.CallOption) (*UpdateOutageResponse, error)
 	DeleteOutage(ctx context.Context, in *DeleteOutageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetOutageTimeline(ctx context.Context, in *GetOutageTimelineRequest, opts ...grpc.CallOption) (*GetOutageTimelineResponse, error)
 }
 
 type outageServiceClient struct {
@@ -91,6 +93,15 @@ func (c *outageServiceClient) DeleteOutage(ctx context.Context, in *DeleteOutage
 	return out, nil
 }
 
+func (c *outageServiceClient) GetOutageTimeline(ctx context.Context, in *GetOutageTimelineRequest, opts ...grpc.CallOption) (*GetOutageTimelineResponse, error) {
+	out := new(GetOutageTimelineResponse)
+	err := c.cc.Invoke(ctx, OutageService_GetOutageTimeline_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OutageServiceServer is the server API for OutageService service.
 // All implementations must embed UnimplementedOutageServiceServer
 // for forward compatibility
@@ -100,6 +111,7 @@ type OutageServiceServer interface {
 	ListOutages(context.Context, *ListOutagesRequest) (*ListOutagesResponse, error)
 	UpdateOutage(context.Context, *UpdateOutageRequest) (*UpdateOutageResponse, error)
 	DeleteOutage(context.Context, *DeleteOutageRequest) (*emptypb.Empty, error)
+	GetOutageTimeline(context.Context, *GetOutageTimelineRequest) (*GetOutageTimelineResponse, error)
 	mustEmbedUnimplementedOutageServiceServer()
 }
 
@@ -122,6 +134,9 @@ func (UnimplementedOutageServiceServer) UpdateOutage(context.Context, *UpdateOut
 func (UnimplementedOutageServiceServer) DeleteOutage(context.Context, *DeleteOutageRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteOutage not implemented")
 }
+func (UnimplementedOutageServiceServer) GetOutageTimeline(context.Context, *GetOutageTimelineRequest) (*GetOutageTimelineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOutageTimeline not implemented")
+}
 func (UnimplementedOutageServiceServer) mustEmbedUnimplementedOutageServiceServer() {}
 
 // UnsafeOutageServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -225,6 +240,24 @@ func _OutageService_DeleteOutage_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OutageService_GetOutageTimeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutageTimelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutageServiceServer).GetOutageTimeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OutageService_GetOutageTimeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutageServiceServer).GetOutageTimeline(ctx, req.(*GetOutageTimelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OutageService_ServiceDesc is the grpc.ServiceDesc for OutageService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -252,6 +285,10 @@ var OutageService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteOutage",
 			Handler:    _OutageService_DeleteOutage_Handler,
 		},
+		{
+			MethodName: "GetOutageTimeline",
+			Handler:    _OutageService_GetOutageTimeline_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/outalator.proto",
@@ -739,6 +776,8 @@ const (
 	AlertService_GetAlertByExternalID_FullMethodName = "/outalator.v1.AlertService/GetAlertByExternalID"
 	AlertService_ListAlertsByOutage_FullMethodName   = "/outalator.v1.AlertService/ListAlertsByOutage"
 	AlertService_UpdateAlert_FullMethodName          = "/outalator.v1.AlertService/UpdateAlert"
+	AlertService_AcknowledgeAlert_FullMethodName     = "/outalator.v1.AlertService/AcknowledgeAlert"
+	AlertService_ResolveAlert_FullMethodName         = "/outalator.v1.AlertService/ResolveAlert"
 )
 
 // AlertServiceClient is the client API for AlertService service.
@@ -750,6 +789,8 @@ type AlertServiceClient interface {
 	GetAlertByExternalID(ctx context.Context, in *GetAlertByExternalIDRequest, opts ...grpc.CallOption) (*GetAlertByExternalIDResponse, error)
 	ListAlertsByOutage(ctx context.Context, in *ListAlertsByOutageRequest, opts ...grpc.CallOption) (*ListAlertsByOutageResponse, error)
 	UpdateAlert(ctx context.Context, in *UpdateAlertRequest, opts ...grpc.CallOption) (*UpdateAlertResponse, error)
+	AcknowledgeAlert(ctx context.Context, in *AcknowledgeAlertRequest, opts ...grpc.CallOption) (*AcknowledgeAlertResponse, error)
+	ResolveAlert(ctx context.Context, in *ResolveAlertRequest, opts ...grpc.CallOption) (*ResolveAlertResponse, error)
 }
 
 type alertServiceClient struct {
@@ -805,6 +846,24 @@ func (c *alertServiceClient) UpdateAlert(ctx context.Context, in *UpdateAlertReq
 	return out, nil
 }
 
+func (c *alertServiceClient) AcknowledgeAlert(ctx context.Context, in *AcknowledgeAlertRequest, opts ...grpc.CallOption) (*AcknowledgeAlertResponse, error) {
+	out := new(AcknowledgeAlertResponse)
+	err := c.cc.Invoke(ctx, AlertService_AcknowledgeAlert_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) ResolveAlert(ctx context.Context, in *ResolveAlertRequest, opts ...grpc.CallOption) (*ResolveAlertResponse, error) {
+	out := new(ResolveAlertResponse)
+	err := c.cc.Invoke(ctx, AlertService_ResolveAlert_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AlertServiceServer is the server API for AlertService service.
 // All implementations must embed UnimplementedAlertServiceServer
 // for forward compatibility
@@ -814,6 +873,8 @@ type AlertServiceServer interface {
 	GetAlertByExternalID(context.Context, *GetAlertByExternalIDRequest) (*GetAlertByExternalIDResponse, error)
 	ListAlertsByOutage(context.Context, *ListAlertsByOutageRequest) (*ListAlertsByOutageResponse, error)
 	UpdateAlert(context.Context, *UpdateAlertRequest) (*UpdateAlertResponse, error)
+	AcknowledgeAlert(context.Context, *AcknowledgeAlertRequest) (*AcknowledgeAlertResponse, error)
+	ResolveAlert(context.Context, *ResolveAlertRequest) (*ResolveAlertResponse, error)
 	mustEmbedUnimplementedAlertServiceServer()
 }
 
@@ -836,6 +897,12 @@ func (UnimplementedAlertServiceServer) ListAlertsByOutage(context.Context, *List
 func (UnimplementedAlertServiceServer) UpdateAlert(context.Context, *UpdateAlertRequest) (*UpdateAlertResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateAlert not implemented")
 }
+func (UnimplementedAlertServiceServer) AcknowledgeAlert(context.Context, *AcknowledgeAlertRequest) (*AcknowledgeAlertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcknowledgeAlert not implemented")
+}
+func (UnimplementedAlertServiceServer) ResolveAlert(context.Context, *ResolveAlertRequest) (*ResolveAlertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveAlert not implemented")
+}
 func (UnimplementedAlertServiceServer) mustEmbedUnimplementedAlertServiceServer() {}
 
 // UnsafeAlertServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -939,6 +1006,42 @@ func _AlertService_UpdateAlert_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AlertService_AcknowledgeAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcknowledgeAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).AcknowledgeAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_AcknowledgeAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).AcknowledgeAlert(ctx, req.(*AcknowledgeAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_ResolveAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).ResolveAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_ResolveAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).ResolveAlert(ctx, req.(*ResolveAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AlertService_ServiceDesc is the grpc.ServiceDesc for AlertService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -966,6 +1069,252 @@ var AlertService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateAlert",
 			Handler:    _AlertService_UpdateAlert_Handler,
 		},
+		{
+			MethodName: "AcknowledgeAlert",
+			Handler:    _AlertService_AcknowledgeAlert_Handler,
+		},
+		{
+			MethodName: "ResolveAlert",
+			Handler:    _AlertService_ResolveAlert_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/proto/outalator.proto",
+}
+
+const (
+	ViewService_CreateView_FullMethodName  = "/outalator.v1.ViewService/CreateView"
+	ViewService_GetView_FullMethodName     = "/outalator.v1.ViewService/GetView"
+	ViewService_ListViews_FullMethodName   = "/outalator.v1.ViewService/ListViews"
+	ViewService_DeleteView_FullMethodName  = "/outalator.v1.ViewService/DeleteView"
+	ViewService_ExecuteView_FullMethodName = "/outalator.v1.ViewService/ExecuteView"
+)
+
+// ViewServiceClient is the client API for ViewService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ViewServiceClient interface {
+	CreateView(ctx context.Context, in *CreateViewRequest, opts ...grpc.CallOption) (*CreateViewResponse, error)
+	GetView(ctx context.Context, in *GetViewRequest, opts ...grpc.CallOption) (*GetViewResponse, error)
+	ListViews(ctx context.Context, in *ListViewsRequest, opts ...grpc.CallOption) (*ListViewsResponse, error)
+	DeleteView(ctx context.Context, in *DeleteViewRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ExecuteView(ctx context.Context, in *ExecuteViewRequest, opts ...grpc.CallOption) (*ExecuteViewResponse, error)
+}
+
+type viewServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewViewServiceClient(cc grpc.ClientConnInterface) ViewServiceClient {
+	return &viewServiceClient{cc}
+}
+
+func (c *viewServiceClient) CreateView(ctx context.Context, in *CreateViewRequest, opts ...grpc.CallOption) (*CreateViewResponse, error) {
+	out := new(CreateViewResponse)
+	err := c.cc.Invoke(ctx, ViewService_CreateView_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *viewServiceClient) GetView(ctx context.Context, in *GetViewRequest, opts ...grpc.CallOption) (*GetViewResponse, error) {
+	out := new(GetViewResponse)
+	err := c.cc.Invoke(ctx, ViewService_GetView_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *viewServiceClient) ListViews(ctx context.Context, in *ListViewsRequest, opts ...grpc.CallOption) (*ListViewsResponse, error) {
+	out := new(ListViewsResponse)
+	err := c.cc.Invoke(ctx, ViewService_ListViews_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *viewServiceClient) DeleteView(ctx context.Context, in *DeleteViewRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ViewService_DeleteView_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *viewServiceClient) ExecuteView(ctx context.Context, in *ExecuteViewRequest, opts ...grpc.CallOption) (*ExecuteViewResponse, error) {
+	out := new(ExecuteViewResponse)
+	err := c.cc.Invoke(ctx, ViewService_ExecuteView_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ViewServiceServer is the server API for ViewService service.
+// All implementations must embed UnimplementedViewServiceServer
+// for forward compatibility
+type ViewServiceServer interface {
+	CreateView(context.Context, *CreateViewRequest) (*CreateViewResponse, error)
+	GetView(context.Context, *GetViewRequest) (*GetViewResponse, error)
+	ListViews(context.Context, *ListViewsRequest) (*ListViewsResponse, error)
+	DeleteView(context.Context, *DeleteViewRequest) (*emptypb.Empty, error)
+	ExecuteView(context.Context, *ExecuteViewRequest) (*ExecuteViewResponse, error)
+	mustEmbedUnimplementedViewServiceServer()
+}
+
+// UnimplementedViewServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedViewServiceServer struct {
+}
+
+func (UnimplementedViewServiceServer) CreateView(context.Context, *CreateViewRequest) (*CreateViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateView not implemented")
+}
+func (UnimplementedViewServiceServer) GetView(context.Context, *GetViewRequest) (*GetViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetView not implemented")
+}
+func (UnimplementedViewServiceServer) ListViews(context.Context, *ListViewsRequest) (*ListViewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListViews not implemented")
+}
+func (UnimplementedViewServiceServer) DeleteView(context.Context, *DeleteViewRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteView not implemented")
+}
+func (UnimplementedViewServiceServer) ExecuteView(context.Context, *ExecuteViewRequest) (*ExecuteViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteView not implemented")
+}
+func (UnimplementedViewServiceServer) mustEmbedUnimplementedViewServiceServer() {}
+
+// UnsafeViewServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ViewServiceServer will
+// result in compilation errors.
+type UnsafeViewServiceServer interface {
+	mustEmbedUnimplementedViewServiceServer()
+}
+
+func RegisterViewServiceServer(s grpc.ServiceRegistrar, srv ViewServiceServer) {
+	s.RegisterService(&ViewService_ServiceDesc, srv)
+}
+
+func _ViewService_CreateView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ViewServiceServer).CreateView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ViewService_CreateView_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ViewServiceServer).CreateView(ctx, req.(*CreateViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ViewService_GetView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ViewServiceServer).GetView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ViewService_GetView_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ViewServiceServer).GetView(ctx, req.(*GetViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ViewService_ListViews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListViewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ViewServiceServer).ListViews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ViewService_ListViews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ViewServiceServer).ListViews(ctx, req.(*ListViewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ViewService_DeleteView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ViewServiceServer).DeleteView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ViewService_DeleteView_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ViewServiceServer).DeleteView(ctx, req.(*DeleteViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ViewService_ExecuteView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ViewServiceServer).ExecuteView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ViewService_ExecuteView_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ViewServiceServer).ExecuteView(ctx, req.(*ExecuteViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ViewService_ServiceDesc is the grpc.ServiceDesc for ViewService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ViewService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "outalator.v1.ViewService",
+	HandlerType: (*ViewServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateView",
+			Handler:    _ViewService_CreateView_Handler,
+		},
+		{
+			MethodName: "GetView",
+			Handler:    _ViewService_GetView_Handler,
+		},
+		{
+			MethodName: "ListViews",
+			Handler:    _ViewService_ListViews_Handler,
+		},
+		{
+			MethodName: "DeleteView",
+			Handler:    _ViewService_DeleteView_Handler,
+		},
+		{
+			MethodName: "ExecuteView",
+			Handler:    _ViewService_ExecuteView_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/outalator.proto",
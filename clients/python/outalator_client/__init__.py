@@ -0,0 +1,1538 @@
+# Code generated by cmd/gen-clients from api/openapi/openapi.yaml. DO NOT EDIT.
+"""Python client for the Outalator API."""
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, Dict, List, Optional, TypedDict
+
+__version__ = "0.45.0"
+API_VERSION = __version__
+
+
+class _ActionItemRequired(TypedDict):
+    created_at: str
+    description: str
+    id: str
+    outage_id: str
+    status: "ActionItemStatus"
+    updated_at: str
+
+
+class ActionItem(_ActionItemRequired, total=False):
+    assignee: str
+    closed_at: str
+    created_by: str
+    due_date: str
+
+
+class ActionItemList(TypedDict):
+    action_items: List["ActionItem"]
+
+
+class ActionItemStatus(TypedDict):
+    pass
+
+
+class _AddNoteRequestRequired(TypedDict):
+    content: str
+
+
+class AddNoteRequest(_AddNoteRequestRequired, total=False):
+    custom_fields: Dict[str, Any]
+    format: str
+    metadata: Dict[str, str]
+    parent_note_id: str
+
+
+class _AlertRequired(TypedDict):
+    created_at: str
+    description: str
+    external_id: str
+    id: str
+    outage_id: str
+    severity: str
+    source: str
+    team_name: str
+    title: str
+    triggered_at: str
+
+
+class Alert(_AlertRequired, total=False):
+    acknowledged_at: str
+    custom_fields: Dict[str, Any]
+    fingerprint: str
+    metadata: Dict[str, str]
+    resolved_at: str
+    service: str
+    source_metadata: Dict[str, Any]
+    team_names: List[str]
+
+
+class AlertList(TypedDict):
+    alerts: List["Alert"]
+
+
+class _AssignResponderRequestRequired(TypedDict):
+    role: "ResponderRole"
+
+
+class AssignResponderRequest(_AssignResponderRequestRequired, total=False):
+    responder: str
+    schedule: str
+    source: str
+
+
+class _AttachmentRequired(TypedDict):
+    content_type: str
+    created_at: str
+    filename: str
+    id: str
+    outage_id: str
+    sha256: str
+    size: int
+
+
+class Attachment(_AttachmentRequired, total=False):
+    note_id: str
+    uploaded_by: str
+
+
+class AttachmentList(TypedDict):
+    attachments: List["Attachment"]
+
+
+class ChildOutage(TypedDict):
+    id: str
+    severity: str
+    status: str
+    title: str
+
+
+class ChildOutageList(TypedDict):
+    outages: List["Outage"]
+
+
+class ConfigChange(TypedDict):
+    action: str
+    kind: str
+    name: str
+
+
+class _CreateActionItemRequestRequired(TypedDict):
+    description: str
+
+
+class CreateActionItemRequest(_CreateActionItemRequestRequired, total=False):
+    assignee: str
+    due_date: str
+    status: "ActionItemStatus"
+
+
+class _CreateOutageRelationRequestRequired(TypedDict):
+    related_outage_id: str
+    type: str
+
+
+class CreateOutageRelationRequest(_CreateOutageRelationRequestRequired, total=False):
+    description: str
+
+
+class CreateOutageRequest(TypedDict, total=False):
+    affected_services: List[str]
+    alert_ids: List[str]
+    custom_fields: Dict[str, Any]
+    customer_impact: bool
+    description: str
+    impact_ended_at: str
+    impact_started_at: str
+    metadata: Dict[str, str]
+    owning_team: str
+    severity: str
+    tags: List["TagInput"]
+    template: str
+    title: str
+    variables: Dict[str, str]
+
+
+class _CreateSavedViewRequestRequired(TypedDict):
+    name: str
+
+
+class CreateSavedViewRequest(_CreateSavedViewRequestRequired, total=False):
+    filter: "ViewFilter"
+    slack_channel: str
+    summary_period: str
+
+
+class _CustomFieldRequired(TypedDict):
+    entity: str
+    name: str
+    type: str
+
+
+class CustomField(_CustomFieldRequired, total=False):
+    allowed_values: List[str]
+    description: str
+    required: bool
+    required_for_severities: List[str]
+
+
+class CustomFieldSchemas(TypedDict):
+    schemas: Dict[str, "EntitySchema"]
+
+
+class _DigestRequired(TypedDict):
+    missing_postmortems: List["DigestOutage"]
+    mttr: List["MTTRPeriod"]
+    new: List["DigestOutage"]
+    open: List["DigestOutage"]
+    period: str
+    since: str
+    until: str
+
+
+class Digest(_DigestRequired, total=False):
+    name: str
+    team: str
+
+
+class _DigestOutageRequired(TypedDict):
+    created_at: str
+    id: str
+    severity: str
+    status: str
+    title: str
+
+
+class DigestOutage(_DigestOutageRequired, total=False):
+    owning_team: str
+    resolved_at: str
+    review_status: str
+
+
+class EntitySchema(TypedDict):
+    allow_unknown: bool
+    fields: List["FieldDefinition"]
+
+
+class Error(TypedDict):
+    code: str
+    error: str
+
+
+class _EventRequired(TypedDict):
+    id: int
+    outage: "Outage"
+    time: str
+    type: str
+
+
+class Event(_EventRequired, total=False):
+    alert: "Alert"
+    note: "Note"
+    previous_status: str
+
+
+class _FieldDefinitionRequired(TypedDict):
+    name: str
+    required: bool
+    type: str
+
+
+class FieldDefinition(_FieldDefinitionRequired, total=False):
+    allowed_values: List[str]
+    description: str
+    required_for_severities: List[str]
+
+
+class HealthStatus(TypedDict):
+    status: str
+
+
+class _ImportAlertRequestRequired(TypedDict):
+    external_id: str
+    source: str
+
+
+class ImportAlertRequest(_ImportAlertRequestRequired, total=False):
+    outage_id: str
+
+
+class _ImportRunRequired(TypedDict):
+    filter_hash: str
+    id: str
+    kind: str
+    offset: int
+    provider: str
+    since: str
+    started_at: str
+    stats: "ImportRunStats"
+    status: str
+    until: str
+    updated_at: str
+
+
+class ImportRun(_ImportRunRequired, total=False):
+    error: str
+    finished_at: str
+    last_item_at: str
+    team_ids: List[str]
+
+
+class ImportRunList(TypedDict):
+    import_runs: List["ImportRun"]
+
+
+class ImportRunStats(TypedDict):
+    alert_events: int
+    errors: int
+    new_alerts: int
+    new_outages: int
+    skipped: int
+    timeline_notes: int
+    total_fetched: int
+
+
+class MTTRPeriod(TypedDict):
+    mttr_seconds: int
+    resolved: int
+    since: str
+    until: str
+
+
+class _NoisyAlertRequired(TypedDict):
+    count: int
+    fingerprint: str
+    first_triggered_at: str
+    last_triggered_at: str
+    outages: int
+    source: str
+    title: str
+
+
+class NoisyAlert(_NoisyAlertRequired, total=False):
+    teams: List[str]
+
+
+class NoisyAlerts(TypedDict):
+    alerts: List["NoisyAlert"]
+    since: str
+    total: int
+    until: str
+
+
+class _NoteRequired(TypedDict):
+    author: str
+    content: str
+    created_at: str
+    format: str
+    id: str
+    outage_id: str
+    updated_at: str
+
+
+class Note(_NoteRequired, total=False):
+    custom_fields: Dict[str, Any]
+    deleted_at: str
+    metadata: Dict[str, str]
+    parent_note_id: str
+
+
+class NoteList(TypedDict):
+    limit: int
+    notes: List["Note"]
+    offset: int
+    total: int
+
+
+class _NoteRevisionRequired(TypedDict):
+    content: str
+    edited_at: str
+    format: str
+    id: str
+    note_id: str
+    version: int
+
+
+class NoteRevision(_NoteRevisionRequired, total=False):
+    edited_by: str
+
+
+class NoteRevisionList(TypedDict):
+    revisions: List["NoteRevision"]
+
+
+class NoteThread(TypedDict):
+    note: "Note"
+    replies: List["Note"]
+
+
+class NoteThreadList(TypedDict):
+    limit: int
+    offset: int
+    threads: List["NoteThread"]
+    total: int
+
+
+class _NotificationPreferencesRequired(TypedDict):
+    email: bool
+    slack_dm: bool
+
+
+class NotificationPreferences(_NotificationPreferencesRequired, total=False):
+    min_severity: str
+
+
+class _OnCallRequired(TypedDict):
+    schedule_id: str
+    source: str
+
+
+class OnCall(_OnCallRequired, total=False):
+    email: str
+    end: str
+    name: str
+    schedule_name: str
+    start: str
+
+
+class OnCallList(TypedDict):
+    oncall: List["OnCall"]
+
+
+class OpsConfig(TypedDict, total=False):
+    custom_fields: List["CustomField"]
+    routing_rules: List["RoutingRule"]
+    tag_schemas: List["TagSchema"]
+    teams: List["Team"]
+    templates: List["OutageTemplate"]
+
+
+class OpsConfigPlan(TypedDict):
+    applied: bool
+    changes: List["ConfigChange"]
+
+
+class _OutageRequired(TypedDict):
+    created_at: str
+    description: str
+    id: str
+    severity: str
+    status: str
+    title: str
+    updated_at: str
+
+
+class Outage(_OutageRequired, total=False):
+    affected_services: List[str]
+    alerts: List["Alert"]
+    custom_fields: Dict[str, Any]
+    customer_impact: bool
+    deleted_at: str
+    impact_ended_at: str
+    impact_started_at: str
+    investigating_at: str
+    metadata: Dict[str, str]
+    mitigated_at: str
+    notes: List["Note"]
+    owning_team: str
+    parent_id: str
+    relations: List["OutageRelation"]
+    resolved_at: str
+    rollup: "OutageRollup"
+    tags: List["Tag"]
+
+
+class _OutageExportRequired(TypedDict):
+    outages: List["Outage"]
+    version: int
+
+
+class OutageExport(_OutageExportRequired, total=False):
+    exported_at: str
+
+
+class OutageImportResult(TypedDict):
+    alerts: int
+    notes: int
+    outages: int
+    skipped_alerts: int
+    skipped_outages: int
+    tags: int
+
+
+class OutageList(TypedDict):
+    limit: int
+    offset: int
+    outages: List["Outage"]
+    teams: List[str]
+
+
+class OutagePresence(TypedDict):
+    outage_id: str
+    present: List["Presence"]
+    ttl_seconds: int
+
+
+class _OutageRelationRequired(TypedDict):
+    created_at: str
+    id: str
+    outage_id: str
+    related_outage_id: str
+    type: str
+
+
+class OutageRelation(_OutageRelationRequired, total=False):
+    created_by: str
+    description: str
+    related_outage_title: str
+
+
+class OutageRelationList(TypedDict):
+    relations: List["OutageRelation"]
+
+
+class OutageResponders(TypedDict):
+    current: List["ResponderAssignment"]
+    history: List["ResponderAssignment"]
+
+
+class _OutageReviewRequired(TypedDict):
+    created_at: str
+    outage_id: str
+    status: str
+    updated_at: str
+
+
+class OutageReview(_OutageReviewRequired, total=False):
+    reviewed_at: str
+    reviewer: str
+    scheduled_for: str
+
+
+class OutageRollup(TypedDict):
+    children: List["ChildOutage"]
+    open_children: int
+    severity: str
+    status: str
+
+
+class OutageSearchResult(TypedDict):
+    outages: List["Outage"]
+
+
+class _OutageTemplateRequired(TypedDict):
+    name: str
+
+
+class OutageTemplate(_OutageTemplateRequired, total=False):
+    checklist: List[str]
+    description: str
+    severity: str
+    tags: Dict[str, str]
+    title: str
+
+
+class OutageTemplateList(TypedDict):
+    templates: List["OutageTemplate"]
+
+
+class _OverdueActionItemRequired(TypedDict):
+    created_at: str
+    days_overdue: int
+    description: str
+    id: str
+    outage_id: str
+    outage_title: str
+    status: "ActionItemStatus"
+    updated_at: str
+
+
+class OverdueActionItem(_OverdueActionItemRequired, total=False):
+    assignee: str
+    created_by: str
+    due_date: str
+
+
+class OverdueActionItems(TypedDict):
+    generated_at: str
+    teams: List["TeamOverdueActionItems"]
+    total: int
+
+
+class _PageRequestRequired(TypedDict):
+    source: str
+
+
+class PageRequest(_PageRequestRequired, total=False):
+    service: str
+    team: str
+
+
+class PagingLoad(TypedDict):
+    off_hours: int
+    since: str
+    teams: List["TeamPagingLoad"]
+    timezone: str
+    total: int
+    until: str
+
+
+class _PresenceRequired(TypedDict):
+    activity: str
+    last_seen: str
+    user: str
+
+
+class Presence(_PresenceRequired, total=False):
+    name: str
+
+
+class PresenceHeartbeat(TypedDict, total=False):
+    activity: str
+
+
+class ReadyStatus(TypedDict):
+    checks: Dict[str, str]
+    status: str
+
+
+class RenderedNote(TypedDict):
+    format: str
+    html: str
+    note_id: str
+    slack: str
+
+
+class _ResponderAssignmentRequired(TypedDict):
+    assigned_at: str
+    id: str
+    outage_id: str
+    responder: str
+    role: "ResponderRole"
+
+
+class ResponderAssignment(_ResponderAssignmentRequired, total=False):
+    assigned_by: str
+    schedule_id: str
+    source: str
+    unassigned_at: str
+    unassigned_by: str
+
+
+class ResponderLoad(TypedDict):
+    responders: List["ResponderLoadEntry"]
+    since: str
+    total: int
+    until: str
+
+
+class ResponderLoadEntry(TypedDict):
+    assignments: int
+    outages: int
+    responder: str
+    roles: Dict[str, int]
+
+
+class ResponderRole(TypedDict):
+    pass
+
+
+class _RetentionRunRequired(TypedDict):
+    archived_before: str
+    archived_outages: int
+    id: str
+    purged_archives: int
+    started_at: str
+    status: str
+    trigger: str
+
+
+class RetentionRun(_RetentionRunRequired, total=False):
+    archive_key: str
+    archive_purged_at: str
+    error: str
+    finished_at: str
+    triggered_by: str
+
+
+class RetentionRunList(TypedDict):
+    retention_runs: List["RetentionRun"]
+
+
+class ReviewList(TypedDict):
+    reviews: List["OutageReview"]
+
+
+class _RoutingDecisionRequired(TypedDict):
+    rules: List[str]
+
+
+class RoutingDecision(_RoutingDecisionRequired, total=False):
+    attach_to_open_outage: bool
+    service: str
+    severity: str
+    suppress: bool
+    tags: Dict[str, str]
+    team: str
+
+
+class RoutingEvaluation(TypedDict):
+    alert_id: str
+    decision: "RoutingDecision"
+    external_id: str
+    outage_id: str
+    severity: str
+    source: str
+    title: str
+    triggered_at: str
+
+
+class RoutingEvaluationRequest(TypedDict, total=False):
+    rules: List["RoutingRule"]
+    since: str
+    source: str
+    until: str
+
+
+class RoutingEvaluationResult(TypedDict):
+    alerts: List["RoutingEvaluation"]
+    evaluated: int
+    matched: int
+    since: str
+    suppressed: int
+    until: str
+
+
+class RoutingMatch(TypedDict, total=False):
+    metadata: Dict[str, str]
+    severity: str
+    source: str
+    team_name: str
+    title: str
+
+
+class _RoutingRuleRequired(TypedDict):
+    match: "RoutingMatch"
+    name: str
+
+
+class RoutingRule(_RoutingRuleRequired, total=False):
+    attach_to_open_outage: bool
+    service: str
+    set_severity: str
+    suppress: bool
+    tags: Dict[str, str]
+    team: str
+
+
+class _SavedViewRequired(TypedDict):
+    created_at: str
+    filter: "ViewFilter"
+    id: str
+    name: str
+    owner: str
+    updated_at: str
+
+
+class SavedView(_SavedViewRequired, total=False):
+    last_summary_at: str
+    slack_channel: str
+    summary_period: str
+
+
+class SavedViewList(TypedDict):
+    views: List["SavedView"]
+
+
+class SavedViewOutages(TypedDict):
+    limit: int
+    offset: int
+    outages: List["Outage"]
+    view: "SavedView"
+
+
+class _ServiceRequired(TypedDict):
+    name: str
+
+
+class Service(_ServiceRequired, total=False):
+    created_at: str
+    description: str
+    owning_team: str
+    repo: str
+    runbook_url: str
+    source: str
+    statuspage_component: str
+    tier: int
+    updated_at: str
+
+
+class ServiceDowntime(TypedDict):
+    customer_impact: bool
+    services: List["ServiceDowntimeEntry"]
+    since: str
+    until: str
+
+
+class _ServiceDowntimeEntryRequired(TypedDict):
+    availability: float
+    customer_impact: int
+    downtime_seconds: int
+    outages: int
+    service: str
+
+
+class ServiceDowntimeEntry(_ServiceDowntimeEntryRequired, total=False):
+    owning_team: str
+    tier: int
+
+
+class ServiceList(TypedDict):
+    services: List["Service"]
+
+
+class SetOutageParentRequest(TypedDict):
+    parent_id: str
+
+
+class _SimilarOutageRequired(TypedDict):
+    outage: "Outage"
+    reasons: List[str]
+    score: float
+
+
+class SimilarOutage(_SimilarOutageRequired, total=False):
+    shared_tags: List[str]
+
+
+class SimilarOutageList(TypedDict):
+    similar: List["SimilarOutage"]
+
+
+class _SourceHealthRequired(TypedDict):
+    source: str
+    stale: bool
+    updated_at: str
+
+
+class SourceHealth(_SourceHealthRequired, total=False):
+    last_error: str
+    last_failure_at: str
+    last_success_at: str
+    max_silence_seconds: int
+
+
+class SourceHealthList(TypedDict):
+    sources: List["SourceHealth"]
+
+
+class _TagRequired(TypedDict):
+    created_at: str
+    id: str
+    key: str
+    outage_id: str
+    value: str
+
+
+class Tag(_TagRequired, total=False):
+    custom_fields: Dict[str, Any]
+
+
+class _TagFilterRequired(TypedDict):
+    key: str
+
+
+class TagFilter(_TagFilterRequired, total=False):
+    value: str
+
+
+class _TagInputRequired(TypedDict):
+    key: str
+    value: str
+
+
+class TagInput(_TagInputRequired, total=False):
+    custom_fields: Dict[str, Any]
+
+
+class TagKeyList(TypedDict):
+    keys: List["TagUsage"]
+
+
+class TagList(TypedDict):
+    tags: List["Tag"]
+
+
+class _TagSchemaRequired(TypedDict):
+    key: str
+
+
+class TagSchema(_TagSchemaRequired, total=False):
+    allowed_values: List[str]
+    description: str
+    unique: bool
+
+
+class _TagUsageRequired(TypedDict):
+    count: int
+    key: str
+
+
+class TagUsage(_TagUsageRequired, total=False):
+    value: str
+
+
+class TagValueList(TypedDict):
+    values: List["TagUsage"]
+
+
+class _TeamRequired(TypedDict):
+    name: str
+
+
+class Team(_TeamRequired, total=False):
+    description: str
+    external_id: str
+    members: List[str]
+    slack_channel: str
+    source: str
+
+
+class TeamList(TypedDict):
+    teams: List["Team"]
+
+
+class TeamOverdueActionItems(TypedDict):
+    items: List["OverdueActionItem"]
+    team: str
+
+
+class TeamPagingLoad(TypedDict):
+    counts: List[List[int]]
+    off_hours: int
+    team: str
+    total: int
+
+
+class Timeline(TypedDict):
+    events: List["TimelineEvent"]
+    outage_id: str
+
+
+class _TimelineEventRequired(TypedDict):
+    entity_id: str
+    summary: str
+    timestamp: str
+    type: str
+
+
+class TimelineEvent(_TimelineEventRequired, total=False):
+    actor: str
+    child_outage_id: str
+    details: Dict[str, Any]
+
+
+class _TransitionRequestRequired(TypedDict):
+    action: str
+
+
+class TransitionRequest(_TransitionRequestRequired, total=False):
+    actor: str
+    reason: str
+    resolve_alerts: bool
+
+
+class UpdateActionItemRequest(TypedDict, total=False):
+    assignee: str
+    clear_due_date: bool
+    description: str
+    due_date: str
+    status: "ActionItemStatus"
+
+
+class UpdateAlertRequest(TypedDict, total=False):
+    acknowledged_at: str
+    custom_fields: Dict[str, Any]
+    description: str
+    metadata: Dict[str, str]
+    resolved_at: str
+    service: str
+    severity: str
+    title: str
+
+
+class UpdateNoteRequest(TypedDict, total=False):
+    content: str
+    custom_fields: Dict[str, Any]
+    format: str
+    metadata: Dict[str, str]
+
+
+class UpdateOutageRequest(TypedDict, total=False):
+    affected_services: List[str]
+    custom_fields: Dict[str, Any]
+    customer_impact: bool
+    description: str
+    impact_ended_at: str
+    impact_started_at: str
+    metadata: Dict[str, str]
+    owning_team: str
+    resolve_alerts: bool
+    severity: str
+    status: str
+    title: str
+
+
+class UpdatePreferencesRequest(TypedDict, total=False):
+    default_team_filter: str
+    digest_opt_in: bool
+    notifications: "NotificationPreferences"
+    timezone: str
+
+
+class _UpdateReviewRequestRequired(TypedDict):
+    status: str
+
+
+class UpdateReviewRequest(_UpdateReviewRequestRequired, total=False):
+    scheduled_for: str
+
+
+class UpdateSLA(TypedDict):
+    due_at: str
+    interval_seconds: int
+    last_update_at: str
+    outage_id: str
+    overdue: bool
+    severity: str
+    title: str
+
+
+class UpdateSLAList(TypedDict):
+    update_slas: List["UpdateSLA"]
+
+
+class UpdateTagRequest(TypedDict, total=False):
+    custom_fields: Dict[str, Any]
+    key: str
+    value: str
+
+
+class UserPreferences(TypedDict):
+    default_team_filter: str
+    digest_opt_in: bool
+    notifications: "NotificationPreferences"
+    subject: str
+    timezone: str
+    updated_at: str
+
+
+class ViewFilter(TypedDict, total=False):
+    min_severity: str
+    statuses: List[str]
+    tags: List["TagFilter"]
+    teams: List[str]
+
+
+class WatchChannel(TypedDict):
+    pass
+
+
+class WatchOutageRequest(TypedDict, total=False):
+    channel: "WatchChannel"
+
+
+class Watcher(TypedDict):
+    channel: "WatchChannel"
+    created_at: str
+    email: str
+    outage_id: str
+
+
+class WatcherList(TypedDict):
+    watchers: List["Watcher"]
+
+
+class OutalatorError(Exception):
+    """Raised when the API responds with a non-2xx status."""
+
+    def __init__(self, status: int, message: str) -> None:
+        super().__init__(message)
+        self.status = status
+        self.message = message
+
+
+class Client:
+    """Client for the Outalator REST API.
+
+    headers are sent with every request, e.g. a session cookie.
+    """
+
+    def __init__(self, base_url: str, headers: Optional[Dict[str, str]] = None, timeout: float = 30.0) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.headers = dict(headers or {})
+        self.timeout = timeout
+
+    def _request(self, method: str, path: str, query: Optional[Dict[str, Any]] = None, body: Any = None) -> Any:
+        url = self.base_url + path
+        if query:
+            params = {k: _query_value(v) for k, v in query.items() if v is not None}
+            if params:
+                url += "?" + urllib.parse.urlencode(params)
+        data = None if body is None else json.dumps(body).encode("utf-8")
+        req = urllib.request.Request(url, data=data, method=method)
+        req.add_header("Content-Type", "application/json")
+        for key, value in self.headers.items():
+            req.add_header(key, value)
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout) as resp:
+                payload = resp.read()
+        except urllib.error.HTTPError as e:
+            message = "server returned status %d" % e.code
+            try:
+                err = json.loads(e.read())
+                if isinstance(err, dict) and isinstance(err.get("error"), str):
+                    message = err["error"]
+            except ValueError:
+                pass
+            raise OutalatorError(e.code, message) from None
+        if not payload:
+            return None
+        return json.loads(payload)
+
+    def get_action_item(self, id: str) -> "ActionItem":
+        """Get an action item"""
+        return self._request("GET", "/api/v1/action-items/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def update_action_item(self, id: str, body: "UpdateActionItemRequest") -> "ActionItem":
+        """Update an action item's description, assignee, due date or status. Unset fields are left alone. Closing an item records when it was closed."""
+        return self._request("PATCH", "/api/v1/action-items/%s" % urllib.parse.quote(id, safe=''), None, body)
+
+    def delete_action_item(self, id: str) -> None:
+        """Delete an action item"""
+        return self._request("DELETE", "/api/v1/action-items/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def search_alerts(self, source: Optional[str] = None, team: Optional[str] = None, severity: Optional[str] = None, since: Optional[str] = None, until: Optional[str] = None, resolved: Optional[bool] = None, limit: Optional[int] = None, offset: Optional[int] = None) -> "AlertList":
+        """List alerts across outages, most recently triggered first"""
+        return self._request("GET", "/api/v1/alerts", {"source": source, "team": team, "severity": severity, "since": since, "until": until, "resolved": resolved, "limit": limit, "offset": offset}, None)
+
+    def import_alert(self, body: "ImportAlertRequest") -> "Alert":
+        """Import an alert from a notification service"""
+        return self._request("POST", "/api/v1/alerts/import", None, body)
+
+    def get_alert(self, id: str) -> "Alert":
+        """Get an alert"""
+        return self._request("GET", "/api/v1/alerts/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def update_alert(self, id: str, body: "UpdateAlertRequest") -> "Alert":
+        """Update an alert. metadata and custom_fields are replaced in full."""
+        return self._request("PATCH", "/api/v1/alerts/%s" % urllib.parse.quote(id, safe=''), None, body)
+
+    def acknowledge_alert(self, id: str) -> "Alert":
+        """Acknowledge an alert with the notification service it came from, as the signed-in user"""
+        return self._request("POST", "/api/v1/alerts/%s/acknowledge" % urllib.parse.quote(id, safe=''), None, None)
+
+    def resolve_alert(self, id: str) -> "Alert":
+        """Resolve an alert with the notification service it came from, as the signed-in user"""
+        return self._request("POST", "/api/v1/alerts/%s/resolve" % urllib.parse.quote(id, safe=''), None, None)
+
+    def get_attachment(self, id: str) -> "Attachment":
+        """Get an attachment's metadata"""
+        return self._request("GET", "/api/v1/attachments/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def delete_attachment(self, id: str) -> None:
+        """Delete an attachment and remove it from its note"""
+        return self._request("DELETE", "/api/v1/attachments/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def get_ops_config(self) -> "OpsConfig":
+        """Get the operational config (teams, tag schemas, routing rules, templates, custom fields)"""
+        return self._request("GET", "/api/v1/config", None, None)
+
+    def apply_ops_config(self, body: "OpsConfig", dry_run: Optional[bool] = None, prune: Optional[bool] = None) -> "OpsConfigPlan":
+        """Reconcile the operational config with a document"""
+        return self._request("POST", "/api/v1/config/apply", {"dry_run": dry_run, "prune": prune}, body)
+
+    def evaluate_routing_rules(self, body: "RoutingEvaluationRequest") -> "RoutingEvaluationResult":
+        """Dry-run routing rules against the alerts triggered in a time range"""
+        return self._request("POST", "/api/v1/config/routing-rules/evaluate", None, body)
+
+    def list_import_runs(self, provider: Optional[str] = None) -> "ImportRunList":
+        """List historical imports run by import-history, most recently started first"""
+        return self._request("GET", "/api/v1/import-runs", {"provider": provider}, None)
+
+    def get_import_run(self, id: str) -> "ImportRun":
+        """Get a historical import run's progress and statistics"""
+        return self._request("GET", "/api/v1/import-runs/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def get_preferences(self) -> "UserPreferences":
+        """Get the authenticated user's preferences"""
+        return self._request("GET", "/api/v1/me/preferences", None, None)
+
+    def update_preferences(self, body: "UpdatePreferencesRequest") -> "UserPreferences":
+        """Update the authenticated user's preferences"""
+        return self._request("PATCH", "/api/v1/me/preferences", None, body)
+
+    def get_note(self, id: str) -> "Note":
+        """Get a note"""
+        return self._request("GET", "/api/v1/notes/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def update_note(self, id: str, body: "UpdateNoteRequest") -> "Note":
+        """Update a note. metadata and custom_fields are replaced in full."""
+        return self._request("PATCH", "/api/v1/notes/%s" % urllib.parse.quote(id, safe=''), None, body)
+
+    def delete_note(self, id: str) -> None:
+        """Move a note to the trash"""
+        return self._request("DELETE", "/api/v1/notes/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def render_note(self, id: str) -> "RenderedNote":
+        """Render a note as sanitized HTML and Slack mrkdwn. Markdown notes are rendered, log notes are preformatted and plaintext is escaped."""
+        return self._request("GET", "/api/v1/notes/%s/rendered" % urllib.parse.quote(id, safe=''), None, None)
+
+    def restore_note(self, id: str) -> "Note":
+        """Restore a note from the trash. Admin only."""
+        return self._request("POST", "/api/v1/notes/%s/restore" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_note_revisions(self, id: str) -> "NoteRevisionList":
+        """List the versions of a note that edits replaced, oldest first. Editing content or format adds a revision; metadata-only edits do not."""
+        return self._request("GET", "/api/v1/notes/%s/revisions" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_on_call(self, source: Optional[str] = None, schedule: Optional[str] = None) -> "OnCallList":
+        """List who is on call according to the notification services' schedules"""
+        return self._request("GET", "/api/v1/oncall", {"source": source, "schedule": schedule}, None)
+
+    def get_open_a_p_i_spec(self) -> Dict[str, Any]:
+        """Get this OpenAPI spec as JSON"""
+        return self._request("GET", "/api/v1/openapi.json", None, None)
+
+    def list_outages(self, limit: Optional[int] = None, offset: Optional[int] = None, include_deleted: Optional[bool] = None, team: Optional[str] = None, include_associations: Optional[bool] = None) -> "OutageList":
+        """List outages, newest first"""
+        return self._request("GET", "/api/v1/outages", {"limit": limit, "offset": offset, "include_deleted": include_deleted, "team": team, "include_associations": include_associations}, None)
+
+    def create_outage(self, body: "CreateOutageRequest") -> "Outage":
+        """Create an outage"""
+        return self._request("POST", "/api/v1/outages", None, body)
+
+    def export_outages(self, format: Optional[str] = None, since: Optional[str] = None, until: Optional[str] = None) -> "OutageExport":
+        """Export outages with their alerts, notes and tags, leaving out the trash. The JSON export can be imported into another instance; the CSV export has one row per outage."""
+        return self._request("GET", "/api/v1/outages/export", {"format": format, "since": since, "until": until}, None)
+
+    def create_outage_from_template(self, name: str, body: "CreateOutageRequest") -> "Outage":
+        """Create an outage from a template"""
+        return self._request("POST", "/api/v1/outages/from-template/%s" % urllib.parse.quote(name, safe=''), None, body)
+
+    def import_outages(self, body: "OutageExport") -> "OutageImportResult":
+        """Import a JSON export, keeping IDs and timestamps. Outages that already exist and alerts already tracked are skipped; nothing is written if any outage is invalid. Admins only."""
+        return self._request("POST", "/api/v1/outages/import", None, body)
+
+    def get_outage(self, id: str) -> "Outage":
+        """Get an outage with its alerts, notes and tags"""
+        return self._request("GET", "/api/v1/outages/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def update_outage(self, id: str, body: "UpdateOutageRequest") -> "Outage":
+        """Update an outage. metadata and custom_fields are replaced in full."""
+        return self._request("PATCH", "/api/v1/outages/%s" % urllib.parse.quote(id, safe=''), None, body)
+
+    def delete_outage(self, id: str) -> None:
+        """Move an outage to the trash"""
+        return self._request("DELETE", "/api/v1/outages/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_action_items(self, id: str) -> "ActionItemList":
+        """List an outage's action items, oldest first"""
+        return self._request("GET", "/api/v1/outages/%s/action-items" % urllib.parse.quote(id, safe=''), None, None)
+
+    def create_action_item(self, id: str, body: "CreateActionItemRequest") -> "ActionItem":
+        """Add a follow-up action item to an outage"""
+        return self._request("POST", "/api/v1/outages/%s/action-items" % urllib.parse.quote(id, safe=''), None, body)
+
+    def list_alerts(self, id: str) -> "AlertList":
+        """List the alerts linked to an outage"""
+        return self._request("GET", "/api/v1/outages/%s/alerts" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_attachments(self, id: str) -> "AttachmentList":
+        """List an outage's attachments, oldest first"""
+        return self._request("GET", "/api/v1/outages/%s/attachments" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_child_outages(self, id: str) -> "ChildOutageList":
+        """List an outage's child outages, oldest first"""
+        return self._request("GET", "/api/v1/outages/%s/children" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_notes(self, id: str, limit: Optional[int] = None, offset: Optional[int] = None, include_deleted: Optional[bool] = None) -> "NoteList":
+        """List an outage's notes, newest first"""
+        return self._request("GET", "/api/v1/outages/%s/notes" % urllib.parse.quote(id, safe=''), {"limit": limit, "offset": offset, "include_deleted": include_deleted}, None)
+
+    def add_note(self, id: str, body: "AddNoteRequest") -> "Note":
+        """Add a note to an outage. The author is the authenticated user."""
+        return self._request("POST", "/api/v1/outages/%s/notes" % urllib.parse.quote(id, safe=''), None, body)
+
+    def list_note_threads(self, id: str, limit: Optional[int] = None, offset: Optional[int] = None) -> "NoteThreadList":
+        """List an outage's notes as threads, newest thread first with replies oldest first"""
+        return self._request("GET", "/api/v1/outages/%s/notes/threads" % urllib.parse.quote(id, safe=''), {"limit": limit, "offset": offset}, None)
+
+    def page_outage(self, id: str, body: "PageRequest") -> "Alert":
+        """Page a service or team through a paging provider, linking the provider's alert to the outage. Without a service or team the outage's owning team is paged."""
+        return self._request("POST", "/api/v1/outages/%s/page" % urllib.parse.quote(id, safe=''), None, body)
+
+    def set_outage_parent(self, id: str, body: "SetOutageParentRequest") -> "Outage":
+        """Link an outage as a child of another outage"""
+        return self._request("PUT", "/api/v1/outages/%s/parent" % urllib.parse.quote(id, safe=''), None, body)
+
+    def remove_outage_parent(self, id: str) -> None:
+        """Unlink an outage from its parent"""
+        return self._request("DELETE", "/api/v1/outages/%s/parent" % urllib.parse.quote(id, safe=''), None, None)
+
+    def get_outage_presence(self, id: str) -> "OutagePresence":
+        """List the users currently viewing or working an outage"""
+        return self._request("GET", "/api/v1/outages/%s/presence" % urllib.parse.quote(id, safe=''), None, None)
+
+    def record_outage_presence(self, id: str, body: "PresenceHeartbeat") -> "OutagePresence":
+        """Heartbeat from the authenticated user. Users stay listed for ttl_seconds after their last heartbeat."""
+        return self._request("POST", "/api/v1/outages/%s/presence" % urllib.parse.quote(id, safe=''), None, body)
+
+    def leave_outage_presence(self, id: str) -> None:
+        """Remove the authenticated user from the outage's presence list"""
+        return self._request("DELETE", "/api/v1/outages/%s/presence" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_outage_relations(self, id: str) -> "OutageRelationList":
+        """List an outage's relations to other outages, oldest first"""
+        return self._request("GET", "/api/v1/outages/%s/relations" % urllib.parse.quote(id, safe=''), None, None)
+
+    def create_outage_relation(self, id: str, body: "CreateOutageRelationRequest") -> "OutageRelation":
+        """Link an outage to another outage with a typed relation"""
+        return self._request("POST", "/api/v1/outages/%s/relations" % urllib.parse.quote(id, safe=''), None, body)
+
+    def delete_outage_relation(self, id: str, relation_id: str) -> None:
+        """Remove a relation from either of its outages"""
+        return self._request("DELETE", "/api/v1/outages/%s/relations/%s" % (urllib.parse.quote(id, safe=''), urllib.parse.quote(relation_id, safe='')), None, None)
+
+    def list_outage_responders(self, id: str) -> "OutageResponders":
+        """List an outage's current responders and its full assignment history"""
+        return self._request("GET", "/api/v1/outages/%s/responders" % urllib.parse.quote(id, safe=''), None, None)
+
+    def assign_responder(self, id: str, body: "AssignResponderRequest") -> "ResponderAssignment":
+        """Assign a responder to a role, ending the previous holder's assignment. Without a responder, whoever is on call for the given source and schedule is assigned."""
+        return self._request("POST", "/api/v1/outages/%s/responders" % urllib.parse.quote(id, safe=''), None, body)
+
+    def unassign_responder(self, id: str, role: str) -> None:
+        """End the current assignment of a role"""
+        return self._request("DELETE", "/api/v1/outages/%s/responders/%s" % (urllib.parse.quote(id, safe=''), urllib.parse.quote(role, safe='')), None, None)
+
+    def restore_outage(self, id: str) -> "Outage":
+        """Restore an outage from the trash. Admin only."""
+        return self._request("POST", "/api/v1/outages/%s/restore" % urllib.parse.quote(id, safe=''), None, None)
+
+    def get_outage_review(self, id: str) -> "OutageReview":
+        """Get an outage's postmortem review state"""
+        return self._request("GET", "/api/v1/outages/%s/review" % urllib.parse.quote(id, safe=''), None, None)
+
+    def update_outage_review(self, id: str, body: "UpdateReviewRequest") -> "OutageReview":
+        """Change an outage's review state"""
+        return self._request("PATCH", "/api/v1/outages/%s/review" % urllib.parse.quote(id, safe=''), None, body)
+
+    def list_similar_outages(self, id: str, limit: Optional[int] = None, min_score: Optional[float] = None) -> "SimilarOutageList":
+        """List past outages resembling this one by title, description, tags and owning team, most similar first"""
+        return self._request("GET", "/api/v1/outages/%s/similar" % urllib.parse.quote(id, safe=''), {"limit": limit, "min_score": min_score}, None)
+
+    def summarize_outage(self, id: str) -> "Note":
+        """Write an executive summary of an outage with the configured language model"""
+        return self._request("POST", "/api/v1/outages/%s/summarize" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_tags(self, id: str) -> "TagList":
+        """List an outage's tags"""
+        return self._request("GET", "/api/v1/outages/%s/tags" % urllib.parse.quote(id, safe=''), None, None)
+
+    def add_tag(self, id: str, body: "TagInput") -> "Tag":
+        """Add a tag to an outage"""
+        return self._request("POST", "/api/v1/outages/%s/tags" % urllib.parse.quote(id, safe=''), None, body)
+
+    def get_outage_timeline(self, id: str) -> "Timeline":
+        """Get an outage's history in chronological order"""
+        return self._request("GET", "/api/v1/outages/%s/timeline" % urllib.parse.quote(id, safe=''), None, None)
+
+    def transition_outage(self, id: str, body: "TransitionRequest") -> "Outage":
+        """Move an outage through a state machine action such as mitigate or reopen"""
+        return self._request("POST", "/api/v1/outages/%s/transition" % urllib.parse.quote(id, safe=''), None, body)
+
+    def get_update_s_l_a(self, id: str) -> "UpdateSLA":
+        """Get when an active outage's next status update is due"""
+        return self._request("GET", "/api/v1/outages/%s/update-sla" % urllib.parse.quote(id, safe=''), None, None)
+
+    def watch_outage(self, id: str, body: "WatchOutageRequest") -> "Watcher":
+        """Watch an outage as the authenticated user"""
+        return self._request("POST", "/api/v1/outages/%s/watch" % urllib.parse.quote(id, safe=''), None, body)
+
+    def unwatch_outage(self, id: str) -> None:
+        """Stop watching an outage as the authenticated user"""
+        return self._request("DELETE", "/api/v1/outages/%s/watch" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_outage_watchers(self, id: str) -> "WatcherList":
+        """List the users watching an outage"""
+        return self._request("GET", "/api/v1/outages/%s/watchers" % urllib.parse.quote(id, safe=''), None, None)
+
+    def get_digest(self, period: Optional[str] = None, team: Optional[str] = None, until: Optional[str] = None) -> "Digest":
+        """Preview an outage digest: open outages, outages created in the period, resolved outages missing a postmortem and the mean time to resolve over the last four periods"""
+        return self._request("GET", "/api/v1/reports/digest", {"period": period, "team": team, "until": until}, None)
+
+    def get_noisy_alerts(self, since: Optional[str] = None, until: Optional[str] = None, team: Optional[str] = None, limit: Optional[int] = None) -> "NoisyAlerts":
+        """Rank alert fingerprints by how many alerts share them, noisiest first"""
+        return self._request("GET", "/api/v1/reports/noisy-alerts", {"since": since, "until": until, "team": team, "limit": limit}, None)
+
+    def get_overdue_action_items(self, team: Optional[str] = None) -> "OverdueActionItems":
+        """List the open and in-progress action items past their due date, grouped by the team owning their outage and most overdue first. Outages in the trash are left out."""
+        return self._request("GET", "/api/v1/reports/overdue-action-items", {"team": team}, None)
+
+    def get_paging_load(self, since: Optional[str] = None, until: Optional[str] = None, team: Optional[str] = None, tz: Optional[str] = None) -> "PagingLoad":
+        """Count alerts per team by day of week and hour of day"""
+        return self._request("GET", "/api/v1/reports/paging-load", {"since": since, "until": until, "team": team, "tz": tz}, None)
+
+    def get_responder_load(self, since: Optional[str] = None, until: Optional[str] = None) -> "ResponderLoad":
+        """Count responder assignments per responder, busiest first"""
+        return self._request("GET", "/api/v1/reports/responders", {"since": since, "until": until}, None)
+
+    def get_service_downtime(self, since: Optional[str] = None, until: Optional[str] = None, customer_impact: Optional[bool] = None, team: Optional[str] = None, tier: Optional[int] = None) -> "ServiceDowntime":
+        """Report how long each catalogued service was affected by outages, most downtime first"""
+        return self._request("GET", "/api/v1/reports/service-downtime", {"since": since, "until": until, "customer_impact": customer_impact, "team": team, "tier": tier}, None)
+
+    def list_retention_runs(self) -> "RetentionRunList":
+        """List retention runs, most recently started first"""
+        return self._request("GET", "/api/v1/retention-runs", None, None)
+
+    def start_retention_run(self) -> None:
+        """Apply the retention policy now"""
+        return self._request("POST", "/api/v1/retention-runs", None, None)
+
+    def get_retention_run(self, id: str) -> "RetentionRun":
+        """Get a retention run's progress and outcome"""
+        return self._request("GET", "/api/v1/retention-runs/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_outage_reviews(self, status: Optional[str] = None) -> "ReviewList":
+        """List outage reviews, optionally filtered by status"""
+        return self._request("GET", "/api/v1/reviews", {"status": status}, None)
+
+    def get_custom_field_schemas(self) -> "CustomFieldSchemas":
+        """Get the custom field definitions enforced on custom_fields, keyed by entity"""
+        return self._request("GET", "/api/v1/schemas/custom-fields", None, None)
+
+    def list_services(self) -> "ServiceList":
+        """List the service catalogue"""
+        return self._request("GET", "/api/v1/services", None, None)
+
+    def sync_services(self) -> "OpsConfigPlan":
+        """Sync the service catalogue from the configured YAML file and Backstage. Admin only."""
+        return self._request("POST", "/api/v1/services/sync", None, None)
+
+    def get_service(self, name: str) -> "Service":
+        """Get a catalogued service"""
+        return self._request("GET", "/api/v1/services/%s" % urllib.parse.quote(name, safe=''), None, None)
+
+    def save_service(self, name: str, body: "Service") -> "Service":
+        """Add a service to the catalogue or replace it. Admin only."""
+        return self._request("PUT", "/api/v1/services/%s" % urllib.parse.quote(name, safe=''), None, body)
+
+    def delete_service(self, name: str) -> None:
+        """Remove a service from the catalogue. Outages naming it keep it. Admin only."""
+        return self._request("DELETE", "/api/v1/services/%s" % urllib.parse.quote(name, safe=''), None, None)
+
+    def list_source_health(self) -> "SourceHealthList":
+        """When alerts were last ingested from each source, by webhook or sync, and whether the source is stale"""
+        return self._request("GET", "/api/v1/sources/health", None, None)
+
+    def get_alert_by_external_i_d(self, source: str, external_id: str) -> "Alert":
+        """Get an alert by its ID in the source that raised it"""
+        return self._request("GET", "/api/v1/sources/%s/alerts/%s" % (urllib.parse.quote(source, safe=''), urllib.parse.quote(external_id, safe='')), None, None)
+
+    def list_tag_keys(self) -> "TagKeyList":
+        """List tag keys in use, most used first"""
+        return self._request("GET", "/api/v1/tags/keys", None, None)
+
+    def search_by_tag(self, key: Optional[str] = None, value: Optional[str] = None, tag: Optional[str] = None, team: Optional[str] = None) -> "OutageSearchResult":
+        """Find outages matching every tag term"""
+        return self._request("GET", "/api/v1/tags/search", {"key": key, "value": value, "tag": tag, "team": team}, None)
+
+    def list_tag_values(self, key: str) -> "TagValueList":
+        """List the values in use for a tag key, most used first"""
+        return self._request("GET", "/api/v1/tags/values", {"key": key}, None)
+
+    def get_tag(self, id: str) -> "Tag":
+        """Get a tag"""
+        return self._request("GET", "/api/v1/tags/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def update_tag(self, id: str, body: "UpdateTagRequest") -> "Tag":
+        """Update a tag. custom_fields are replaced in full."""
+        return self._request("PATCH", "/api/v1/tags/%s" % urllib.parse.quote(id, safe=''), None, body)
+
+    def delete_tag(self, id: str) -> None:
+        """Delete a tag"""
+        return self._request("DELETE", "/api/v1/tags/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def list_teams(self) -> "TeamList":
+        """List teams, both locally managed and synced from notification services"""
+        return self._request("GET", "/api/v1/teams", None, None)
+
+    def sync_teams(self) -> "OpsConfigPlan":
+        """Sync teams from the notification services that list them. Admin only."""
+        return self._request("POST", "/api/v1/teams/sync", None, None)
+
+    def get_team(self, name: str) -> "Team":
+        """Get a team"""
+        return self._request("GET", "/api/v1/teams/%s" % urllib.parse.quote(name, safe=''), None, None)
+
+    def list_outage_templates(self) -> "OutageTemplateList":
+        """List outage templates"""
+        return self._request("GET", "/api/v1/templates", None, None)
+
+    def get_outage_template(self, name: str) -> "OutageTemplate":
+        """Get an outage template"""
+        return self._request("GET", "/api/v1/templates/%s" % urllib.parse.quote(name, safe=''), None, None)
+
+    def save_outage_template(self, name: str, body: "OutageTemplate") -> "OutageTemplate":
+        """Create or replace an outage template. Admin only."""
+        return self._request("PUT", "/api/v1/templates/%s" % urllib.parse.quote(name, safe=''), None, body)
+
+    def delete_outage_template(self, name: str) -> None:
+        """Delete an outage template. Admin only."""
+        return self._request("DELETE", "/api/v1/templates/%s" % urllib.parse.quote(name, safe=''), None, None)
+
+    def list_update_s_l_as(self, overdue: Optional[bool] = None) -> "UpdateSLAList":
+        """List status update SLAs of active outages, soonest due first"""
+        return self._request("GET", "/api/v1/update-sla", {"overdue": overdue}, None)
+
+    def list_saved_views(self) -> "SavedViewList":
+        """List saved views by name"""
+        return self._request("GET", "/api/v1/views", None, None)
+
+    def create_saved_view(self, body: "CreateSavedViewRequest") -> "SavedView":
+        """Save a named outage filter. A view with a slack_channel is summarised there every summary_period."""
+        return self._request("POST", "/api/v1/views", None, body)
+
+    def get_saved_view(self, id: str) -> "SavedView":
+        """Get a saved view"""
+        return self._request("GET", "/api/v1/views/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def delete_saved_view(self, id: str) -> None:
+        """Delete a saved view. Only its owner and admins can."""
+        return self._request("DELETE", "/api/v1/views/%s" % urllib.parse.quote(id, safe=''), None, None)
+
+    def execute_saved_view(self, id: str, limit: Optional[int] = None, offset: Optional[int] = None) -> "SavedViewOutages":
+        """List the outages currently matching a saved view, most recently created first"""
+        return self._request("GET", "/api/v1/views/%s/outages" % urllib.parse.quote(id, safe=''), {"limit": limit, "offset": offset}, None)
+
+    def health(self) -> "HealthStatus":
+        """Liveness check (same as /healthz)"""
+        return self._request("GET", "/health", None, None)
+
+    def healthz(self) -> "HealthStatus":
+        """Liveness check"""
+        return self._request("GET", "/healthz", None, None)
+
+    def readyz(self) -> "ReadyStatus":
+        """Readiness check"""
+        return self._request("GET", "/readyz", None, None)
+
+
+def _query_value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
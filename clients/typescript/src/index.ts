@@ -0,0 +1,1656 @@
+// Code generated by cmd/gen-clients from api/openapi/openapi.yaml. DO NOT EDIT.
+
+export const API_VERSION = "0.45.0";
+
+export interface ActionItem {
+  /** Email or name of whoever owns the task */
+  assignee?: string;
+  /** When the item was last done or cancelled */
+  closed_at?: string;
+  created_at: string;
+  /** Email of the user who added the item */
+  created_by?: string;
+  description: string;
+  due_date?: string;
+  id: string;
+  outage_id: string;
+  status: ActionItemStatus;
+  updated_at: string;
+}
+
+export interface ActionItemList {
+  action_items: ActionItem[];
+}
+
+export interface ActionItemStatus {
+}
+
+export interface AddNoteRequest {
+  content: string;
+  custom_fields?: Record<string, unknown>;
+  /** log notes are returned exactly as written and shown as preformatted text. markdown notes are rejected if they embed HTML other than br, kbd, sub and sup, or link to URLs other than http, https, mailto and relative ones */
+  format?: string;
+  metadata?: Record<string, string>;
+  /** Note on the same outage to reply to. Replying to a reply joins its thread. */
+  parent_note_id?: string;
+}
+
+export interface Alert {
+  acknowledged_at?: string;
+  created_at: string;
+  custom_fields?: Record<string, unknown>;
+  description: string;
+  external_id: string;
+  /** Identifies the monitor that raised the alert, from its source and title with IDs, addresses, host names and numbers stripped */
+  fingerprint?: string;
+  id: string;
+  metadata?: Record<string, string>;
+  outage_id: string;
+  resolved_at?: string;
+  /** Catalogued service the alert is about */
+  service?: string;
+  severity: string;
+  source: string;
+  source_metadata?: Record<string, unknown>;
+  /** Primary team */
+  team_name: string;
+  /** Every team the alert was routed to, team_name first */
+  team_names?: string[];
+  title: string;
+  triggered_at: string;
+}
+
+export interface AlertList {
+  alerts: Alert[];
+}
+
+export interface AssignResponderRequest {
+  /** Name or email address of the responder */
+  responder?: string;
+  role: ResponderRole;
+  schedule?: string;
+  /** Notification service to take the on-call from when no responder is given */
+  source?: string;
+}
+
+export interface Attachment {
+  /** Sniffed from the content */
+  content_type: string;
+  created_at: string;
+  filename: string;
+  id: string;
+  /** Note that references the attachment */
+  note_id?: string;
+  outage_id: string;
+  /** Hex SHA-256 digest of the content */
+  sha256: string;
+  /** Size in bytes */
+  size: number;
+  uploaded_by?: string;
+}
+
+export interface AttachmentList {
+  attachments: Attachment[];
+}
+
+export interface ChildOutage {
+  id: string;
+  severity: string;
+  status: string;
+  title: string;
+}
+
+export interface ChildOutageList {
+  outages: Outage[];
+}
+
+export interface ConfigChange {
+  /** create, update or delete */
+  action: string;
+  kind: string;
+  name: string;
+}
+
+export interface CreateActionItemRequest {
+  assignee?: string;
+  description: string;
+  due_date?: string;
+  status?: ActionItemStatus;
+}
+
+export interface CreateOutageRelationRequest {
+  description?: string;
+  related_outage_id: string;
+  type: string;
+}
+
+export interface CreateOutageRequest {
+  /** Names of catalogued services */
+  affected_services?: string[];
+  alert_ids?: string[];
+  custom_fields?: Record<string, unknown>;
+  customer_impact?: boolean;
+  description?: string;
+  /** Defaults to when the outage is resolved in reports */
+  impact_ended_at?: string;
+  /** Defaults to when the outage is created in reports */
+  impact_started_at?: string;
+  metadata?: Record<string, string>;
+  /** Name of an existing team */
+  owning_team?: string;
+  severity?: string;
+  tags?: TagInput[];
+  /** Outage template that fills unset fields and adds its tags */
+  template?: string;
+  title?: string;
+  /** Values for the template's {placeholders} */
+  variables?: Record<string, string>;
+}
+
+export interface CreateSavedViewRequest {
+  filter?: ViewFilter;
+  name: string;
+  slack_channel?: string;
+  /** Needs slack_channel, default daily */
+  summary_period?: string;
+}
+
+export interface CustomField {
+  /** Enum values of a string field */
+  allowed_values?: string[];
+  description?: string;
+  entity: string;
+  name: string;
+  required?: boolean;
+  /** Makes an outage field required on outages of these severities */
+  required_for_severities?: string[];
+  type: string;
+}
+
+export interface CustomFieldSchemas {
+  /** Schemas keyed by entity (outage, note, tag); entities without one accept any custom_fields */
+  schemas: Record<string, EntitySchema>;
+}
+
+export interface Digest {
+  missing_postmortems: DigestOutage[];
+  /** Mean time to resolve per period, oldest first, ending with this one */
+  mttr: MTTRPeriod[];
+  /** The schedule the digest was sent for */
+  name?: string;
+  new: DigestOutage[];
+  open: DigestOutage[];
+  period: string;
+  since: string;
+  team?: string;
+  until: string;
+}
+
+export interface DigestOutage {
+  created_at: string;
+  id: string;
+  owning_team?: string;
+  resolved_at?: string;
+  /** Set for outages missing a postmortem */
+  review_status?: string;
+  severity: string;
+  status: string;
+  title: string;
+}
+
+export interface EntitySchema {
+  /** Whether fields without a definition are accepted */
+  allow_unknown: boolean;
+  fields: FieldDefinition[];
+}
+
+export interface Error {
+  /** Stable machine-readable error code */
+  code: string;
+  /** Human-readable message */
+  error: string;
+}
+
+export interface Event {
+  alert?: Alert;
+  /** Increases by one per event published */
+  id: number;
+  note?: Note;
+  /** The outage without its notes and alerts */
+  outage: Outage;
+  /** Set on outage.status_changed */
+  previous_status?: string;
+  time: string;
+  type: string;
+}
+
+export interface FieldDefinition {
+  /** Enum values of a string field */
+  allowed_values?: string[];
+  description?: string;
+  name: string;
+  required: boolean;
+  /** Severities of the outages the field is required on */
+  required_for_severities?: string[];
+  type: string;
+}
+
+export interface HealthStatus {
+  status: string;
+}
+
+export interface ImportAlertRequest {
+  external_id: string;
+  /** Outage to attach the alert to; a new outage is opened when omitted */
+  outage_id?: string;
+  source: string;
+}
+
+export interface ImportRun {
+  /** Why a failed run stopped */
+  error?: string;
+  /** Identifies the kind */
+  filter_hash: string;
+  finished_at?: string;
+  id: string;
+  kind: string;
+  /** Creation time of the last alert or incident processed */
+  last_item_at?: string;
+  /** Alerts or incidents fetched and processed so far */
+  offset: number;
+  /** Notification service imported from, e.g. pagerduty */
+  provider: string;
+  since: string;
+  started_at: string;
+  stats: ImportRunStats;
+  status: string;
+  team_ids?: string[];
+  until: string;
+  updated_at: string;
+}
+
+export interface ImportRunList {
+  import_runs: ImportRun[];
+}
+
+export interface ImportRunStats {
+  alert_events: number;
+  errors: number;
+  new_alerts: number;
+  new_outages: number;
+  /** Alerts or incidents imported before */
+  skipped: number;
+  timeline_notes: number;
+  total_fetched: number;
+}
+
+export interface MTTRPeriod {
+  /** Zero when nothing was resolved */
+  mttr_seconds: number;
+  resolved: number;
+  since: string;
+  until: string;
+}
+
+export interface NoisyAlert {
+  count: number;
+  fingerprint: string;
+  first_triggered_at: string;
+  last_triggered_at: string;
+  /** Distinct outages the alerts are linked to */
+  outages: number;
+  source: string;
+  teams?: string[];
+  /** Title of the latest alert */
+  title: string;
+}
+
+export interface NoisyAlerts {
+  alerts: NoisyAlert[];
+  since: string;
+  /** Alerts counted */
+  total: number;
+  until: string;
+}
+
+export interface Note {
+  author: string;
+  content: string;
+  created_at: string;
+  custom_fields?: Record<string, unknown>;
+  /** Set while the note is in the trash */
+  deleted_at?: string;
+  /** log notes are returned exactly as written and shown as preformatted text */
+  format: string;
+  id: string;
+  /** The attachments key holds a JSON array of the IDs of attachments uploaded with the note */
+  metadata?: Record<string, string>;
+  outage_id: string;
+  /** Top-level note this note replies to */
+  parent_note_id?: string;
+  updated_at: string;
+}
+
+export interface NoteList {
+  limit: number;
+  notes: Note[];
+  offset: number;
+  /** Number of the outage's notes */
+  total: number;
+}
+
+export interface NoteRevision {
+  content: string;
+  edited_at: string;
+  /** Signed-in user whose edit replaced this version */
+  edited_by?: string;
+  format: string;
+  id: string;
+  note_id: string;
+  /** 1 is the content the note was created with */
+  version: number;
+}
+
+export interface NoteRevisionList {
+  revisions: NoteRevision[];
+}
+
+export interface NoteThread {
+  note: Note;
+  replies: Note[];
+}
+
+export interface NoteThreadList {
+  limit: number;
+  offset: number;
+  threads: NoteThread[];
+  /** Number of the outage's threads */
+  total: number;
+}
+
+export interface NotificationPreferences {
+  email: boolean;
+  min_severity?: string;
+  slack_dm: boolean;
+}
+
+export interface OnCall {
+  email?: string;
+  end?: string;
+  name?: string;
+  schedule_id: string;
+  schedule_name?: string;
+  source: string;
+  start?: string;
+}
+
+export interface OnCallList {
+  oncall: OnCall[];
+}
+
+export interface OpsConfig {
+  custom_fields?: CustomField[];
+  routing_rules?: RoutingRule[];
+  tag_schemas?: TagSchema[];
+  teams?: Team[];
+  templates?: OutageTemplate[];
+}
+
+export interface OpsConfigPlan {
+  applied: boolean;
+  changes: ConfigChange[];
+}
+
+export interface Outage {
+  /** Catalogued services the outage affects */
+  affected_services?: string[];
+  alerts?: Alert[];
+  created_at: string;
+  custom_fields?: Record<string, unknown>;
+  /** Whether customers were impacted */
+  customer_impact?: boolean;
+  /** Set while the outage is in the trash */
+  deleted_at?: string;
+  description: string;
+  id: string;
+  /** When impact ended */
+  impact_ended_at?: string;
+  /** When impact began */
+  impact_started_at?: string;
+  /** First time the outage was investigated */
+  investigating_at?: string;
+  metadata?: Record<string, string>;
+  /** First time the outage was mitigated */
+  mitigated_at?: string;
+  notes?: Note[];
+  /** Team responsible for the outage */
+  owning_team?: string;
+  /** Outage this outage is a child of */
+  parent_id?: string;
+  /** Links to other outages, returned when getting an outage */
+  relations?: OutageRelation[];
+  resolved_at?: string;
+  rollup?: OutageRollup;
+  /** critical, high, medium or low */
+  severity: string;
+  /** open, investigating, mitigated, resolved or closed */
+  status: string;
+  tags?: Tag[];
+  title: string;
+  updated_at: string;
+}
+
+export interface OutageExport {
+  exported_at?: string;
+  outages: Outage[];
+  /** Export format version */
+  version: number;
+}
+
+export interface OutageImportResult {
+  alerts: number;
+  notes: number;
+  outages: number;
+  /** Alerts already tracked under the same source and external ID */
+  skipped_alerts: number;
+  /** Outages whose ID already exists */
+  skipped_outages: number;
+  tags: number;
+}
+
+export interface OutageList {
+  limit: number;
+  offset: number;
+  outages: Outage[];
+  /** Owning teams the listing was filtered to; empty when every outage is listed */
+  teams: string[];
+}
+
+export interface OutagePresence {
+  outage_id: string;
+  present: Presence[];
+  ttl_seconds: number;
+}
+
+export interface OutageRelation {
+  created_at: string;
+  created_by?: string;
+  description?: string;
+  id: string;
+  outage_id: string;
+  related_outage_id: string;
+  related_outage_title?: string;
+  type: string;
+}
+
+export interface OutageRelationList {
+  relations: OutageRelation[];
+}
+
+export interface OutageResponders {
+  current: ResponderAssignment[];
+  history: ResponderAssignment[];
+}
+
+export interface OutageReview {
+  created_at: string;
+  outage_id: string;
+  reviewed_at?: string;
+  reviewer?: string;
+  scheduled_for?: string;
+  /** needs-review, review-scheduled or reviewed */
+  status: string;
+  updated_at: string;
+}
+
+export interface OutageRollup {
+  children: ChildOutage[];
+  /** Children not yet resolved or closed */
+  open_children: number;
+  severity: string;
+  status: string;
+}
+
+export interface OutageSearchResult {
+  outages: Outage[];
+}
+
+export interface OutageTemplate {
+  /** Steps added to new outages as a checklist note */
+  checklist?: string[];
+  /** May contain {variable} placeholders */
+  description?: string;
+  name: string;
+  severity?: string;
+  tags?: Record<string, string>;
+  /** May contain {variable} placeholders */
+  title?: string;
+}
+
+export interface OutageTemplateList {
+  templates: OutageTemplate[];
+}
+
+export interface OverdueActionItem {
+  assignee?: string;
+  created_at: string;
+  created_by?: string;
+  /** Whole days past the due date */
+  days_overdue: number;
+  description: string;
+  due_date?: string;
+  id: string;
+  outage_id: string;
+  outage_title: string;
+  status: ActionItemStatus;
+  updated_at: string;
+}
+
+export interface OverdueActionItems {
+  generated_at: string;
+  teams: TeamOverdueActionItems[];
+  total: number;
+}
+
+export interface PageRequest {
+  /** Provider service to page */
+  service?: string;
+  /** Notification service to page through */
+  source: string;
+  /** Provider team to page */
+  team?: string;
+}
+
+export interface PagingLoad {
+  /** Alerts on weekends or outside 09:00-17:00 on weekdays */
+  off_hours: number;
+  since: string;
+  teams: TeamPagingLoad[];
+  timezone: string;
+  total: number;
+  until: string;
+}
+
+export interface Presence {
+  activity: string;
+  last_seen: string;
+  name?: string;
+  /** Email of the user */
+  user: string;
+}
+
+export interface PresenceHeartbeat {
+  activity?: string;
+}
+
+export interface ReadyStatus {
+  /** ok or failed for each check: database, schema and each notification service that can check its credentials */
+  checks: Record<string, string>;
+  status: string;
+}
+
+export interface RenderedNote {
+  format: string;
+  /** Sanitized HTML for display */
+  html: string;
+  note_id: string;
+  /** Slack mrkdwn */
+  slack: string;
+}
+
+export interface ResponderAssignment {
+  assigned_at: string;
+  assigned_by?: string;
+  id: string;
+  outage_id: string;
+  responder: string;
+  role: ResponderRole;
+  schedule_id?: string;
+  /** Notification service the responder was taken from as on-call */
+  source?: string;
+  /** Absent while the responder holds the role */
+  unassigned_at?: string;
+  unassigned_by?: string;
+}
+
+export interface ResponderLoad {
+  responders: ResponderLoadEntry[];
+  since: string;
+  total: number;
+  until: string;
+}
+
+export interface ResponderLoadEntry {
+  assignments: number;
+  /** Distinct outages the responder was assigned to */
+  outages: number;
+  responder: string;
+  /** Assignments keyed by role */
+  roles: Record<string, number>;
+}
+
+export interface ResponderRole {
+}
+
+export interface RetentionRun {
+  /** Archive file the run's outages were written to */
+  archive_key?: string;
+  /** When a later run deleted the archive file */
+  archive_purged_at?: string;
+  /** Outages resolved before this time are archived */
+  archived_before: string;
+  archived_outages: number;
+  /** Why a failed run stopped */
+  error?: string;
+  finished_at?: string;
+  id: string;
+  /** Archive files of earlier runs this run deleted */
+  purged_archives: number;
+  started_at: string;
+  status: string;
+  trigger: string;
+  /** Email of the admin who started a manual run */
+  triggered_by?: string;
+}
+
+export interface RetentionRunList {
+  retention_runs: RetentionRun[];
+}
+
+export interface ReviewList {
+  reviews: OutageReview[];
+}
+
+export interface RoutingDecision {
+  attach_to_open_outage?: boolean;
+  /** Matching rules, in the order applied */
+  rules: string[];
+  service?: string;
+  severity?: string;
+  suppress?: boolean;
+  tags?: Record<string, string>;
+  team?: string;
+}
+
+export interface RoutingEvaluation {
+  alert_id: string;
+  decision: RoutingDecision;
+  external_id: string;
+  outage_id: string;
+  severity: string;
+  source: string;
+  title: string;
+  triggered_at: string;
+}
+
+export interface RoutingEvaluationRequest {
+  /** Rules to try; the stored rules when empty */
+  rules?: RoutingRule[];
+  /** Default 7 days before until */
+  since?: string;
+  source?: string;
+  /** Default now */
+  until?: string;
+}
+
+export interface RoutingEvaluationResult {
+  alerts: RoutingEvaluation[];
+  evaluated: number;
+  matched: number;
+  since: string;
+  suppressed: number;
+  until: string;
+}
+
+export interface RoutingMatch {
+  /** Source metadata fields to match; a list field matches when any element does */
+  metadata?: Record<string, string>;
+  severity?: string;
+  source?: string;
+  /** Matches any team the alert was routed to */
+  team_name?: string;
+  /** Regular expression searched for in the title */
+  title?: string;
+}
+
+export interface RoutingRule {
+  /** Link the alert to the newest unresolved outage of the routed team instead of opening one */
+  attach_to_open_outage?: boolean;
+  match: RoutingMatch;
+  name: string;
+  /** Catalogued service to link the alert to; the outage opened for it affects the service, and without a team it is routed to the service's owning team */
+  service?: string;
+  set_severity?: string;
+  /** Drop the alert; cannot be combined with other actions */
+  suppress?: boolean;
+  tags?: Record<string, string>;
+  team?: string;
+}
+
+export interface SavedView {
+  created_at: string;
+  filter: ViewFilter;
+  id: string;
+  last_summary_at?: string;
+  name: string;
+  /** Email of the user who saved the view */
+  owner: string;
+  /** Channel sent a summary of the view's outages every summary_period */
+  slack_channel?: string;
+  summary_period?: string;
+  updated_at: string;
+}
+
+export interface SavedViewList {
+  views: SavedView[];
+}
+
+export interface SavedViewOutages {
+  limit: number;
+  offset: number;
+  outages: Outage[];
+  view: SavedView;
+}
+
+export interface Service {
+  created_at?: string;
+  description?: string;
+  /** Set from the URL when saving */
+  name: string;
+  /** Team that owns the service; alerts routed to the service without a team go to it */
+  owning_team?: string;
+  /** Source repository URL */
+  repo?: string;
+  /** Linked from Slack notifications about the service */
+  runbook_url?: string;
+  /** Catalogue the service is synced from, e.g. file or backstage; absent for services managed through the API. Ignored when saving. */
+  source?: string;
+  /** Statuspage component ID published as affected when an outage affecting the service is published */
+  statuspage_component?: string;
+  /** Criticality, 1 being the most critical; 0 or absent when untiered */
+  tier?: number;
+  updated_at?: string;
+}
+
+export interface ServiceDowntime {
+  /** Only customer-impacting outages were counted */
+  customer_impact: boolean;
+  services: ServiceDowntimeEntry[];
+  since: string;
+  until: string;
+}
+
+export interface ServiceDowntimeEntry {
+  /** Fraction of the range the service was not affected */
+  availability: number;
+  /** Those outages that impacted customers */
+  customer_impact: number;
+  downtime_seconds: number;
+  /** Outages affecting the service in the range */
+  outages: number;
+  owning_team?: string;
+  service: string;
+  tier?: number;
+}
+
+export interface ServiceList {
+  services: Service[];
+}
+
+export interface SetOutageParentRequest {
+  parent_id: string;
+}
+
+export interface SimilarOutage {
+  outage: Outage;
+  reasons: string[];
+  score: number;
+  /** Tags both outages have, as key=value */
+  shared_tags?: string[];
+}
+
+export interface SimilarOutageList {
+  similar: SimilarOutage[];
+}
+
+export interface SourceHealth {
+  /** Error from the last failed webhook delivery or sync pass */
+  last_error?: string;
+  last_failure_at?: string;
+  last_success_at?: string;
+  /** Staleness threshold; absent when staleness is not checked */
+  max_silence_seconds?: number;
+  source: string;
+  stale: boolean;
+  /** Zero for sources nothing has been ingested from */
+  updated_at: string;
+}
+
+export interface SourceHealthList {
+  sources: SourceHealth[];
+}
+
+export interface Tag {
+  created_at: string;
+  custom_fields?: Record<string, unknown>;
+  id: string;
+  key: string;
+  outage_id: string;
+  value: string;
+}
+
+export interface TagFilter {
+  key: string;
+  /** Omit to match any value */
+  value?: string;
+}
+
+export interface TagInput {
+  custom_fields?: Record<string, unknown>;
+  key: string;
+  value: string;
+}
+
+export interface TagKeyList {
+  keys: TagUsage[];
+}
+
+export interface TagList {
+  tags: Tag[];
+}
+
+export interface TagSchema {
+  allowed_values?: string[];
+  description?: string;
+  key: string;
+  /** Allow an outage one tag with the key; adding it again updates that tag */
+  unique?: boolean;
+}
+
+export interface TagUsage {
+  count: number;
+  key: string;
+  /** Set when listing the values of a key */
+  value?: string;
+}
+
+export interface TagValueList {
+  values: TagUsage[];
+}
+
+export interface Team {
+  description?: string;
+  /** The team's identifier in its source */
+  external_id?: string;
+  members?: string[];
+  name: string;
+  slack_channel?: string;
+  /** Notification service the team is synced from; empty for locally managed teams */
+  source?: string;
+}
+
+export interface TeamList {
+  teams: Team[];
+}
+
+export interface TeamOverdueActionItems {
+  items: OverdueActionItem[];
+  /** Empty for outages no team owns */
+  team: string;
+}
+
+export interface TeamPagingLoad {
+  /** Alert counts indexed by day of week (0 = Sunday), then hour of day */
+  counts: number[][];
+  off_hours: number;
+  /** Empty for alerts without a team; alerts routed to several teams count towards each */
+  team: string;
+  total: number;
+}
+
+export interface Timeline {
+  events: TimelineEvent[];
+  outage_id: string;
+}
+
+export interface TimelineEvent {
+  actor?: string;
+  /** Child outage the event happened to */
+  child_outage_id?: string;
+  details?: Record<string, unknown>;
+  entity_id: string;
+  summary: string;
+  timestamp: string;
+  type: string;
+}
+
+export interface TransitionRequest {
+  /** State machine action, by default investigate, mitigate, resolve, close or reopen */
+  action: string;
+  /** Ignored when a user is signed in */
+  actor?: string;
+  reason?: string;
+  /** When the outage is resolved */
+  resolve_alerts?: boolean;
+}
+
+export interface UpdateActionItemRequest {
+  /** An empty string unassigns the item */
+  assignee?: string;
+  /** Remove the due date */
+  clear_due_date?: boolean;
+  description?: string;
+  due_date?: string;
+  status?: ActionItemStatus;
+}
+
+export interface UpdateAlertRequest {
+  acknowledged_at?: string;
+  custom_fields?: Record<string, unknown>;
+  description?: string;
+  metadata?: Record<string, string>;
+  resolved_at?: string;
+  /** A catalogued service, or "" to unlink the alert */
+  service?: string;
+  severity?: string;
+  title?: string;
+}
+
+export interface UpdateNoteRequest {
+  content?: string;
+  custom_fields?: Record<string, unknown>;
+  format?: string;
+  metadata?: Record<string, string>;
+}
+
+export interface UpdateOutageRequest {
+  /** Names of catalogued services, replacing the affected services; an empty list clears them */
+  affected_services?: string[];
+  custom_fields?: Record<string, unknown>;
+  customer_impact?: boolean;
+  description?: string;
+  impact_ended_at?: string;
+  impact_started_at?: string;
+  metadata?: Record<string, string>;
+  /** Name of an existing team; an empty string clears the owner */
+  owning_team?: string;
+  /** When this resolves the outage */
+  resolve_alerts?: boolean;
+  severity?: string;
+  /** Must be reachable through a non-explicit state machine transition */
+  status?: string;
+  title?: string;
+}
+
+export interface UpdatePreferencesRequest {
+  default_team_filter?: string;
+  digest_opt_in?: boolean;
+  notifications?: NotificationPreferences;
+  timezone?: string;
+}
+
+export interface UpdateReviewRequest {
+  scheduled_for?: string;
+  status: string;
+}
+
+export interface UpdateSLA {
+  due_at: string;
+  /** Required time between status updates */
+  interval_seconds: number;
+  /** Latest note or status change */
+  last_update_at: string;
+  outage_id: string;
+  overdue: boolean;
+  severity: string;
+  title: string;
+}
+
+export interface UpdateSLAList {
+  update_slas: UpdateSLA[];
+}
+
+export interface UpdateTagRequest {
+  custom_fields?: Record<string, unknown>;
+  key?: string;
+  value?: string;
+}
+
+export interface UserPreferences {
+  default_team_filter: string;
+  digest_opt_in: boolean;
+  notifications: NotificationPreferences;
+  subject: string;
+  timezone: string;
+  updated_at: string;
+}
+
+export interface ViewFilter {
+  /** This severity or a more severe one */
+  min_severity?: string;
+  /** Any of these statuses */
+  statuses?: string[];
+  /** Every one of these tags */
+  tags?: TagFilter[];
+  /** Owned by any of these teams */
+  teams?: string[];
+}
+
+export interface WatchChannel {
+}
+
+export interface WatchOutageRequest {
+  channel?: WatchChannel;
+}
+
+export interface Watcher {
+  channel: WatchChannel;
+  created_at: string;
+  email: string;
+  outage_id: string;
+}
+
+export interface WatcherList {
+  watchers: Watcher[];
+}
+
+/** Error returned when the API responds with a non-2xx status. */
+export class OutalatorError extends Error {
+  constructor(public readonly status: number, message: string) {
+    super(message);
+    this.name = "OutalatorError";
+  }
+}
+
+export interface ClientOptions {
+  /** Extra headers sent with every request, e.g. a session cookie. */
+  headers?: Record<string, string>;
+  /** fetch implementation; defaults to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, string | number | boolean | undefined>;
+
+export class OutalatorClient {
+  private readonly baseURL: string;
+  private readonly headers: Record<string, string>;
+  private readonly fetchImpl: typeof fetch;
+
+  constructor(baseURL: string, options: ClientOptions = {}) {
+    this.baseURL = baseURL.replace(/\/+$/, "");
+    this.headers = options.headers ?? {};
+    this.fetchImpl = options.fetch ?? fetch;
+  }
+
+  private async request<T>(method: string, path: string, query?: Query, body?: unknown): Promise<T> {
+    let url = this.baseURL + path;
+    if (query) {
+      const params = new URLSearchParams();
+      for (const [key, value] of Object.entries(query)) {
+        if (value !== undefined) {
+          params.set(key, String(value));
+        }
+      }
+      const qs = params.toString();
+      if (qs) {
+        url += "?" + qs;
+      }
+    }
+    const resp = await this.fetchImpl(url, {
+      method,
+      headers: { "Content-Type": "application/json", ...this.headers },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    if (!resp.ok) {
+      let message = "server returned status " + resp.status;
+      try {
+        const data = await resp.json();
+        if (data && typeof data.error === "string") {
+          message = data.error;
+        }
+      } catch {
+        // Keep the generic message.
+      }
+      throw new OutalatorError(resp.status, message);
+    }
+    if (resp.status === 204) {
+      return undefined as T;
+    }
+    return (await resp.json()) as T;
+  }
+
+  /** Get an action item */
+  getActionItem(id: string): Promise<ActionItem> {
+    return this.request("GET", `/api/v1/action-items/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Update an action item's description, assignee, due date or status. Unset fields are left alone. Closing an item records when it was closed. */
+  updateActionItem(id: string, body: UpdateActionItemRequest): Promise<ActionItem> {
+    return this.request("PATCH", `/api/v1/action-items/${encodeURIComponent(id)}`, undefined, body);
+  }
+
+  /** Delete an action item */
+  deleteActionItem(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/action-items/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** List alerts across outages, most recently triggered first */
+  searchAlerts(query: { source?: string; team?: string; severity?: string; since?: string; until?: string; resolved?: boolean; limit?: number; offset?: number } = {}): Promise<AlertList> {
+    return this.request("GET", `/api/v1/alerts`, query, undefined);
+  }
+
+  /** Import an alert from a notification service */
+  importAlert(body: ImportAlertRequest): Promise<Alert> {
+    return this.request("POST", `/api/v1/alerts/import`, undefined, body);
+  }
+
+  /** Get an alert */
+  getAlert(id: string): Promise<Alert> {
+    return this.request("GET", `/api/v1/alerts/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Update an alert. metadata and custom_fields are replaced in full. */
+  updateAlert(id: string, body: UpdateAlertRequest): Promise<Alert> {
+    return this.request("PATCH", `/api/v1/alerts/${encodeURIComponent(id)}`, undefined, body);
+  }
+
+  /** Acknowledge an alert with the notification service it came from, as the signed-in user */
+  acknowledgeAlert(id: string): Promise<Alert> {
+    return this.request("POST", `/api/v1/alerts/${encodeURIComponent(id)}/acknowledge`, undefined, undefined);
+  }
+
+  /** Resolve an alert with the notification service it came from, as the signed-in user */
+  resolveAlert(id: string): Promise<Alert> {
+    return this.request("POST", `/api/v1/alerts/${encodeURIComponent(id)}/resolve`, undefined, undefined);
+  }
+
+  /** Get an attachment's metadata */
+  getAttachment(id: string): Promise<Attachment> {
+    return this.request("GET", `/api/v1/attachments/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Delete an attachment and remove it from its note */
+  deleteAttachment(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/attachments/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Get the operational config (teams, tag schemas, routing rules, templates, custom fields) */
+  getOpsConfig(): Promise<OpsConfig> {
+    return this.request("GET", `/api/v1/config`, undefined, undefined);
+  }
+
+  /** Reconcile the operational config with a document */
+  applyOpsConfig(body: OpsConfig, query: { dry_run?: boolean; prune?: boolean } = {}): Promise<OpsConfigPlan> {
+    return this.request("POST", `/api/v1/config/apply`, query, body);
+  }
+
+  /** Dry-run routing rules against the alerts triggered in a time range */
+  evaluateRoutingRules(body: RoutingEvaluationRequest): Promise<RoutingEvaluationResult> {
+    return this.request("POST", `/api/v1/config/routing-rules/evaluate`, undefined, body);
+  }
+
+  /** List historical imports run by import-history, most recently started first */
+  listImportRuns(query: { provider?: string } = {}): Promise<ImportRunList> {
+    return this.request("GET", `/api/v1/import-runs`, query, undefined);
+  }
+
+  /** Get a historical import run's progress and statistics */
+  getImportRun(id: string): Promise<ImportRun> {
+    return this.request("GET", `/api/v1/import-runs/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Get the authenticated user's preferences */
+  getPreferences(): Promise<UserPreferences> {
+    return this.request("GET", `/api/v1/me/preferences`, undefined, undefined);
+  }
+
+  /** Update the authenticated user's preferences */
+  updatePreferences(body: UpdatePreferencesRequest): Promise<UserPreferences> {
+    return this.request("PATCH", `/api/v1/me/preferences`, undefined, body);
+  }
+
+  /** Get a note */
+  getNote(id: string): Promise<Note> {
+    return this.request("GET", `/api/v1/notes/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Update a note. metadata and custom_fields are replaced in full. */
+  updateNote(id: string, body: UpdateNoteRequest): Promise<Note> {
+    return this.request("PATCH", `/api/v1/notes/${encodeURIComponent(id)}`, undefined, body);
+  }
+
+  /** Move a note to the trash */
+  deleteNote(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/notes/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Render a note as sanitized HTML and Slack mrkdwn. Markdown notes are rendered, log notes are preformatted and plaintext is escaped. */
+  renderNote(id: string): Promise<RenderedNote> {
+    return this.request("GET", `/api/v1/notes/${encodeURIComponent(id)}/rendered`, undefined, undefined);
+  }
+
+  /** Restore a note from the trash. Admin only. */
+  restoreNote(id: string): Promise<Note> {
+    return this.request("POST", `/api/v1/notes/${encodeURIComponent(id)}/restore`, undefined, undefined);
+  }
+
+  /** List the versions of a note that edits replaced, oldest first. Editing content or format adds a revision; metadata-only edits do not. */
+  listNoteRevisions(id: string): Promise<NoteRevisionList> {
+    return this.request("GET", `/api/v1/notes/${encodeURIComponent(id)}/revisions`, undefined, undefined);
+  }
+
+  /** List who is on call according to the notification services' schedules */
+  listOnCall(query: { source?: string; schedule?: string } = {}): Promise<OnCallList> {
+    return this.request("GET", `/api/v1/oncall`, query, undefined);
+  }
+
+  /** Get this OpenAPI spec as JSON */
+  getOpenAPISpec(): Promise<Record<string, unknown>> {
+    return this.request("GET", `/api/v1/openapi.json`, undefined, undefined);
+  }
+
+  /** List outages, newest first */
+  listOutages(query: { limit?: number; offset?: number; include_deleted?: boolean; team?: string; include_associations?: boolean } = {}): Promise<OutageList> {
+    return this.request("GET", `/api/v1/outages`, query, undefined);
+  }
+
+  /** Create an outage */
+  createOutage(body: CreateOutageRequest): Promise<Outage> {
+    return this.request("POST", `/api/v1/outages`, undefined, body);
+  }
+
+  /** Export outages with their alerts, notes and tags, leaving out the trash. The JSON export can be imported into another instance; the CSV export has one row per outage. */
+  exportOutages(query: { format?: string; since?: string; until?: string } = {}): Promise<OutageExport> {
+    return this.request("GET", `/api/v1/outages/export`, query, undefined);
+  }
+
+  /** Create an outage from a template */
+  createOutageFromTemplate(name: string, body: CreateOutageRequest): Promise<Outage> {
+    return this.request("POST", `/api/v1/outages/from-template/${encodeURIComponent(name)}`, undefined, body);
+  }
+
+  /** Import a JSON export, keeping IDs and timestamps. Outages that already exist and alerts already tracked are skipped; nothing is written if any outage is invalid. Admins only. */
+  importOutages(body: OutageExport): Promise<OutageImportResult> {
+    return this.request("POST", `/api/v1/outages/import`, undefined, body);
+  }
+
+  /** Get an outage with its alerts, notes and tags */
+  getOutage(id: string): Promise<Outage> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Update an outage. metadata and custom_fields are replaced in full. */
+  updateOutage(id: string, body: UpdateOutageRequest): Promise<Outage> {
+    return this.request("PATCH", `/api/v1/outages/${encodeURIComponent(id)}`, undefined, body);
+  }
+
+  /** Move an outage to the trash */
+  deleteOutage(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/outages/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** List an outage's action items, oldest first */
+  listActionItems(id: string): Promise<ActionItemList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/action-items`, undefined, undefined);
+  }
+
+  /** Add a follow-up action item to an outage */
+  createActionItem(id: string, body: CreateActionItemRequest): Promise<ActionItem> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/action-items`, undefined, body);
+  }
+
+  /** List the alerts linked to an outage */
+  listAlerts(id: string): Promise<AlertList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/alerts`, undefined, undefined);
+  }
+
+  /** List an outage's attachments, oldest first */
+  listAttachments(id: string): Promise<AttachmentList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/attachments`, undefined, undefined);
+  }
+
+  /** List an outage's child outages, oldest first */
+  listChildOutages(id: string): Promise<ChildOutageList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/children`, undefined, undefined);
+  }
+
+  /** List an outage's notes, newest first */
+  listNotes(id: string, query: { limit?: number; offset?: number; include_deleted?: boolean } = {}): Promise<NoteList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/notes`, query, undefined);
+  }
+
+  /** Add a note to an outage. The author is the authenticated user. */
+  addNote(id: string, body: AddNoteRequest): Promise<Note> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/notes`, undefined, body);
+  }
+
+  /** List an outage's notes as threads, newest thread first with replies oldest first */
+  listNoteThreads(id: string, query: { limit?: number; offset?: number } = {}): Promise<NoteThreadList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/notes/threads`, query, undefined);
+  }
+
+  /** Page a service or team through a paging provider, linking the provider's alert to the outage. Without a service or team the outage's owning team is paged. */
+  pageOutage(id: string, body: PageRequest): Promise<Alert> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/page`, undefined, body);
+  }
+
+  /** Link an outage as a child of another outage */
+  setOutageParent(id: string, body: SetOutageParentRequest): Promise<Outage> {
+    return this.request("PUT", `/api/v1/outages/${encodeURIComponent(id)}/parent`, undefined, body);
+  }
+
+  /** Unlink an outage from its parent */
+  removeOutageParent(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/outages/${encodeURIComponent(id)}/parent`, undefined, undefined);
+  }
+
+  /** List the users currently viewing or working an outage */
+  getOutagePresence(id: string): Promise<OutagePresence> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/presence`, undefined, undefined);
+  }
+
+  /** Heartbeat from the authenticated user. Users stay listed for ttl_seconds after their last heartbeat. */
+  recordOutagePresence(id: string, body: PresenceHeartbeat): Promise<OutagePresence> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/presence`, undefined, body);
+  }
+
+  /** Remove the authenticated user from the outage's presence list */
+  leaveOutagePresence(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/outages/${encodeURIComponent(id)}/presence`, undefined, undefined);
+  }
+
+  /** List an outage's relations to other outages, oldest first */
+  listOutageRelations(id: string): Promise<OutageRelationList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/relations`, undefined, undefined);
+  }
+
+  /** Link an outage to another outage with a typed relation */
+  createOutageRelation(id: string, body: CreateOutageRelationRequest): Promise<OutageRelation> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/relations`, undefined, body);
+  }
+
+  /** Remove a relation from either of its outages */
+  deleteOutageRelation(id: string, relationId: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/outages/${encodeURIComponent(id)}/relations/${encodeURIComponent(relationId)}`, undefined, undefined);
+  }
+
+  /** List an outage's current responders and its full assignment history */
+  listOutageResponders(id: string): Promise<OutageResponders> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/responders`, undefined, undefined);
+  }
+
+  /** Assign a responder to a role, ending the previous holder's assignment. Without a responder, whoever is on call for the given source and schedule is assigned. */
+  assignResponder(id: string, body: AssignResponderRequest): Promise<ResponderAssignment> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/responders`, undefined, body);
+  }
+
+  /** End the current assignment of a role */
+  unassignResponder(id: string, role: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/outages/${encodeURIComponent(id)}/responders/${encodeURIComponent(role)}`, undefined, undefined);
+  }
+
+  /** Restore an outage from the trash. Admin only. */
+  restoreOutage(id: string): Promise<Outage> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/restore`, undefined, undefined);
+  }
+
+  /** Get an outage's postmortem review state */
+  getOutageReview(id: string): Promise<OutageReview> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/review`, undefined, undefined);
+  }
+
+  /** Change an outage's review state */
+  updateOutageReview(id: string, body: UpdateReviewRequest): Promise<OutageReview> {
+    return this.request("PATCH", `/api/v1/outages/${encodeURIComponent(id)}/review`, undefined, body);
+  }
+
+  /** List past outages resembling this one by title, description, tags and owning team, most similar first */
+  listSimilarOutages(id: string, query: { limit?: number; min_score?: number } = {}): Promise<SimilarOutageList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/similar`, query, undefined);
+  }
+
+  /** Write an executive summary of an outage with the configured language model */
+  summarizeOutage(id: string): Promise<Note> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/summarize`, undefined, undefined);
+  }
+
+  /** List an outage's tags */
+  listTags(id: string): Promise<TagList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/tags`, undefined, undefined);
+  }
+
+  /** Add a tag to an outage */
+  addTag(id: string, body: TagInput): Promise<Tag> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/tags`, undefined, body);
+  }
+
+  /** Get an outage's history in chronological order */
+  getOutageTimeline(id: string): Promise<Timeline> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/timeline`, undefined, undefined);
+  }
+
+  /** Move an outage through a state machine action such as mitigate or reopen */
+  transitionOutage(id: string, body: TransitionRequest): Promise<Outage> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/transition`, undefined, body);
+  }
+
+  /** Get when an active outage's next status update is due */
+  getUpdateSLA(id: string): Promise<UpdateSLA> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/update-sla`, undefined, undefined);
+  }
+
+  /** Watch an outage as the authenticated user */
+  watchOutage(id: string, body: WatchOutageRequest): Promise<Watcher> {
+    return this.request("POST", `/api/v1/outages/${encodeURIComponent(id)}/watch`, undefined, body);
+  }
+
+  /** Stop watching an outage as the authenticated user */
+  unwatchOutage(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/outages/${encodeURIComponent(id)}/watch`, undefined, undefined);
+  }
+
+  /** List the users watching an outage */
+  listOutageWatchers(id: string): Promise<WatcherList> {
+    return this.request("GET", `/api/v1/outages/${encodeURIComponent(id)}/watchers`, undefined, undefined);
+  }
+
+  /** Preview an outage digest: open outages, outages created in the period, resolved outages missing a postmortem and the mean time to resolve over the last four periods */
+  getDigest(query: { period?: string; team?: string; until?: string } = {}): Promise<Digest> {
+    return this.request("GET", `/api/v1/reports/digest`, query, undefined);
+  }
+
+  /** Rank alert fingerprints by how many alerts share them, noisiest first */
+  getNoisyAlerts(query: { since?: string; until?: string; team?: string; limit?: number } = {}): Promise<NoisyAlerts> {
+    return this.request("GET", `/api/v1/reports/noisy-alerts`, query, undefined);
+  }
+
+  /** List the open and in-progress action items past their due date, grouped by the team owning their outage and most overdue first. Outages in the trash are left out. */
+  getOverdueActionItems(query: { team?: string } = {}): Promise<OverdueActionItems> {
+    return this.request("GET", `/api/v1/reports/overdue-action-items`, query, undefined);
+  }
+
+  /** Count alerts per team by day of week and hour of day */
+  getPagingLoad(query: { since?: string; until?: string; team?: string; tz?: string } = {}): Promise<PagingLoad> {
+    return this.request("GET", `/api/v1/reports/paging-load`, query, undefined);
+  }
+
+  /** Count responder assignments per responder, busiest first */
+  getResponderLoad(query: { since?: string; until?: string } = {}): Promise<ResponderLoad> {
+    return this.request("GET", `/api/v1/reports/responders`, query, undefined);
+  }
+
+  /** Report how long each catalogued service was affected by outages, most downtime first */
+  getServiceDowntime(query: { since?: string; until?: string; customer_impact?: boolean; team?: string; tier?: number } = {}): Promise<ServiceDowntime> {
+    return this.request("GET", `/api/v1/reports/service-downtime`, query, undefined);
+  }
+
+  /** List retention runs, most recently started first */
+  listRetentionRuns(): Promise<RetentionRunList> {
+    return this.request("GET", `/api/v1/retention-runs`, undefined, undefined);
+  }
+
+  /** Apply the retention policy now */
+  startRetentionRun(): Promise<void> {
+    return this.request("POST", `/api/v1/retention-runs`, undefined, undefined);
+  }
+
+  /** Get a retention run's progress and outcome */
+  getRetentionRun(id: string): Promise<RetentionRun> {
+    return this.request("GET", `/api/v1/retention-runs/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** List outage reviews, optionally filtered by status */
+  listOutageReviews(query: { status?: string } = {}): Promise<ReviewList> {
+    return this.request("GET", `/api/v1/reviews`, query, undefined);
+  }
+
+  /** Get the custom field definitions enforced on custom_fields, keyed by entity */
+  getCustomFieldSchemas(): Promise<CustomFieldSchemas> {
+    return this.request("GET", `/api/v1/schemas/custom-fields`, undefined, undefined);
+  }
+
+  /** List the service catalogue */
+  listServices(): Promise<ServiceList> {
+    return this.request("GET", `/api/v1/services`, undefined, undefined);
+  }
+
+  /** Sync the service catalogue from the configured YAML file and Backstage. Admin only. */
+  syncServices(): Promise<OpsConfigPlan> {
+    return this.request("POST", `/api/v1/services/sync`, undefined, undefined);
+  }
+
+  /** Get a catalogued service */
+  getService(name: string): Promise<Service> {
+    return this.request("GET", `/api/v1/services/${encodeURIComponent(name)}`, undefined, undefined);
+  }
+
+  /** Add a service to the catalogue or replace it. Admin only. */
+  saveService(name: string, body: Service): Promise<Service> {
+    return this.request("PUT", `/api/v1/services/${encodeURIComponent(name)}`, undefined, body);
+  }
+
+  /** Remove a service from the catalogue. Outages naming it keep it. Admin only. */
+  deleteService(name: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/services/${encodeURIComponent(name)}`, undefined, undefined);
+  }
+
+  /** When alerts were last ingested from each source, by webhook or sync, and whether the source is stale */
+  listSourceHealth(): Promise<SourceHealthList> {
+    return this.request("GET", `/api/v1/sources/health`, undefined, undefined);
+  }
+
+  /** Get an alert by its ID in the source that raised it */
+  getAlertByExternalID(source: string, externalId: string): Promise<Alert> {
+    return this.request("GET", `/api/v1/sources/${encodeURIComponent(source)}/alerts/${encodeURIComponent(externalId)}`, undefined, undefined);
+  }
+
+  /** List tag keys in use, most used first */
+  listTagKeys(): Promise<TagKeyList> {
+    return this.request("GET", `/api/v1/tags/keys`, undefined, undefined);
+  }
+
+  /** Find outages matching every tag term */
+  searchByTag(query: { key?: string; value?: string; tag?: string; team?: string } = {}): Promise<OutageSearchResult> {
+    return this.request("GET", `/api/v1/tags/search`, query, undefined);
+  }
+
+  /** List the values in use for a tag key, most used first */
+  listTagValues(query: { key: string }): Promise<TagValueList> {
+    return this.request("GET", `/api/v1/tags/values`, query, undefined);
+  }
+
+  /** Get a tag */
+  getTag(id: string): Promise<Tag> {
+    return this.request("GET", `/api/v1/tags/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Update a tag. custom_fields are replaced in full. */
+  updateTag(id: string, body: UpdateTagRequest): Promise<Tag> {
+    return this.request("PATCH", `/api/v1/tags/${encodeURIComponent(id)}`, undefined, body);
+  }
+
+  /** Delete a tag */
+  deleteTag(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/tags/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** List teams, both locally managed and synced from notification services */
+  listTeams(): Promise<TeamList> {
+    return this.request("GET", `/api/v1/teams`, undefined, undefined);
+  }
+
+  /** Sync teams from the notification services that list them. Admin only. */
+  syncTeams(): Promise<OpsConfigPlan> {
+    return this.request("POST", `/api/v1/teams/sync`, undefined, undefined);
+  }
+
+  /** Get a team */
+  getTeam(name: string): Promise<Team> {
+    return this.request("GET", `/api/v1/teams/${encodeURIComponent(name)}`, undefined, undefined);
+  }
+
+  /** List outage templates */
+  listOutageTemplates(): Promise<OutageTemplateList> {
+    return this.request("GET", `/api/v1/templates`, undefined, undefined);
+  }
+
+  /** Get an outage template */
+  getOutageTemplate(name: string): Promise<OutageTemplate> {
+    return this.request("GET", `/api/v1/templates/${encodeURIComponent(name)}`, undefined, undefined);
+  }
+
+  /** Create or replace an outage template. Admin only. */
+  saveOutageTemplate(name: string, body: OutageTemplate): Promise<OutageTemplate> {
+    return this.request("PUT", `/api/v1/templates/${encodeURIComponent(name)}`, undefined, body);
+  }
+
+  /** Delete an outage template. Admin only. */
+  deleteOutageTemplate(name: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/templates/${encodeURIComponent(name)}`, undefined, undefined);
+  }
+
+  /** List status update SLAs of active outages, soonest due first */
+  listUpdateSLAs(query: { overdue?: boolean } = {}): Promise<UpdateSLAList> {
+    return this.request("GET", `/api/v1/update-sla`, query, undefined);
+  }
+
+  /** List saved views by name */
+  listSavedViews(): Promise<SavedViewList> {
+    return this.request("GET", `/api/v1/views`, undefined, undefined);
+  }
+
+  /** Save a named outage filter. A view with a slack_channel is summarised there every summary_period. */
+  createSavedView(body: CreateSavedViewRequest): Promise<SavedView> {
+    return this.request("POST", `/api/v1/views`, undefined, body);
+  }
+
+  /** Get a saved view */
+  getSavedView(id: string): Promise<SavedView> {
+    return this.request("GET", `/api/v1/views/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** Delete a saved view. Only its owner and admins can. */
+  deleteSavedView(id: string): Promise<void> {
+    return this.request("DELETE", `/api/v1/views/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** List the outages currently matching a saved view, most recently created first */
+  executeSavedView(id: string, query: { limit?: number; offset?: number } = {}): Promise<SavedViewOutages> {
+    return this.request("GET", `/api/v1/views/${encodeURIComponent(id)}/outages`, query, undefined);
+  }
+
+  /** Liveness check (same as /healthz) */
+  health(): Promise<HealthStatus> {
+    return this.request("GET", `/health`, undefined, undefined);
+  }
+
+  /** Liveness check */
+  healthz(): Promise<HealthStatus> {
+    return this.request("GET", `/healthz`, undefined, undefined);
+  }
+
+  /** Readiness check */
+  readyz(): Promise<ReadyStatus> {
+    return this.request("GET", `/readyz`, undefined, undefined);
+  }
+}
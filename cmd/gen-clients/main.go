@@ -0,0 +1,718 @@
+// Command gen-clients generates the TypeScript and Python client packages
+// from the OpenAPI spec in api/openapi/openapi.yaml.
+//
+// Both packages are versioned from the spec's info.version, so bump that
+// whenever the API changes and re-run the generator. Use -check in CI to
+// fail when the committed clients are out of date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// spec is the subset of OpenAPI 3.0 the generator understands.
+type spec struct {
+	Info struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	Paths      map[string]pathItem `yaml:"paths"`
+	Components struct {
+		Schemas    map[string]*schema   `yaml:"schemas"`
+		Parameters map[string]parameter `yaml:"parameters"`
+		Responses  map[string]response  `yaml:"responses"`
+	} `yaml:"components"`
+}
+
+type pathItem struct {
+	Parameters []parameter `yaml:"parameters"`
+	Get        *operation  `yaml:"get"`
+	Post       *operation  `yaml:"post"`
+	Put        *operation  `yaml:"put"`
+	Patch      *operation  `yaml:"patch"`
+	Delete     *operation  `yaml:"delete"`
+}
+
+type operation struct {
+	OperationID string              `yaml:"operationId"`
+	Summary     string              `yaml:"summary"`
+	Parameters  []parameter         `yaml:"parameters"`
+	RequestBody *requestBody        `yaml:"requestBody"`
+	Responses   map[string]response `yaml:"responses"`
+}
+
+type parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Content map[string]mediaType `yaml:"content"`
+}
+
+type response struct {
+	Ref     string               `yaml:"$ref"`
+	Content map[string]mediaType `yaml:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Description          string             `yaml:"description"`
+	Required             []string           `yaml:"required"`
+	Properties           map[string]*schema `yaml:"properties"`
+	Items                *schema            `yaml:"items"`
+	AdditionalProperties any                `yaml:"additionalProperties"`
+}
+
+// endpoint is an operation flattened with its path and resolved parameters.
+type endpoint struct {
+	Method   string
+	Path     string
+	Op       *operation
+	PathArgs []parameter
+	Query    []parameter
+	Body     *schema
+	Result   *schema // nil when the operation returns no content
+}
+
+func main() {
+	var (
+		specPath = flag.String("spec", "api/openapi/openapi.yaml", "Path to the OpenAPI spec")
+		outDir   = flag.String("out", "clients", "Directory to write the client packages to")
+		check    = flag.Bool("check", false, "Fail if the generated clients differ from those on disk instead of writing them")
+	)
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read spec: %v", err)
+	}
+	var s spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		log.Fatalf("Failed to parse spec: %v", err)
+	}
+	if s.Info.Version == "" {
+		log.Fatal("Error: spec has no info.version")
+	}
+
+	endpoints, err := collectEndpoints(&s)
+	if err != nil {
+		log.Fatalf("Invalid spec: %v", err)
+	}
+
+	files := map[string]string{
+		"typescript/package.json":             tsPackageJSON(&s),
+		"typescript/tsconfig.json":            tsConfig,
+		"typescript/src/index.ts":             tsSource(&s, endpoints),
+		"python/pyproject.toml":               pyProject(&s),
+		"python/outalator_client/__init__.py": pySource(&s, endpoints),
+		"python/outalator_client/py.typed":    "",
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var stale []string
+	for _, name := range names {
+		path := filepath.Join(*outDir, name)
+		if *check {
+			existing, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(existing, []byte(files[name])) {
+				stale = append(stale, path)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(files[name]), 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		fmt.Printf("wrote %s\n", path)
+	}
+
+	if len(stale) > 0 {
+		log.Fatalf("Generated clients are out of date (run make clients): %s", strings.Join(stale, ", "))
+	}
+	if *check {
+		fmt.Printf("Clients are up to date (version %s)\n", s.Info.Version)
+	}
+}
+
+// collectEndpoints flattens the spec's paths into endpoints ordered by path
+// and method, resolving parameter and response references.
+func collectEndpoints(s *spec) ([]endpoint, error) {
+	paths := make([]string, 0, len(s.Paths))
+	for p := range s.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var endpoints []endpoint
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		item := s.Paths[p]
+		for _, m := range []struct {
+			method string
+			op     *operation
+		}{
+			{"GET", item.Get}, {"POST", item.Post}, {"PUT", item.Put},
+			{"PATCH", item.Patch}, {"DELETE", item.Delete},
+		} {
+			if m.op == nil {
+				continue
+			}
+			if m.op.OperationID == "" {
+				return nil, fmt.Errorf("%s %s has no operationId", m.method, p)
+			}
+			if seen[m.op.OperationID] {
+				return nil, fmt.Errorf("duplicate operationId %q", m.op.OperationID)
+			}
+			seen[m.op.OperationID] = true
+			if !isJSON(m.op) {
+				// Event streams are consumed with EventSource, and file
+				// uploads and downloads are made directly, not through the
+				// clients
+				continue
+			}
+
+			ep := endpoint{Method: m.method, Path: p, Op: m.op}
+			for _, param := range append(append([]parameter{}, item.Parameters...), m.op.Parameters...) {
+				param, err := resolveParameter(s, param)
+				if err != nil {
+					return nil, err
+				}
+				switch param.In {
+				case "path":
+					ep.PathArgs = append(ep.PathArgs, param)
+				case "query":
+					ep.Query = append(ep.Query, param)
+				default:
+					return nil, fmt.Errorf("%s: unsupported parameter location %q", m.op.OperationID, param.In)
+				}
+			}
+			if m.op.RequestBody != nil {
+				ep.Body = m.op.RequestBody.Content["application/json"].Schema
+			}
+			for _, code := range []string{"200", "201"} {
+				if resp, ok := m.op.Responses[code]; ok {
+					ep.Result = resp.Content["application/json"].Schema
+					break
+				}
+			}
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints, nil
+}
+
+// isJSON reports whether op takes and returns JSON, or nothing, which is
+// all the generated clients handle
+func isJSON(op *operation) bool {
+	if op.RequestBody != nil {
+		if _, ok := op.RequestBody.Content["application/json"]; !ok {
+			return false
+		}
+	}
+	for _, code := range []string{"200", "201"} {
+		if resp, ok := op.Responses[code]; ok && len(resp.Content) > 0 {
+			_, ok := resp.Content["application/json"]
+			return ok
+		}
+	}
+	return true
+}
+
+func resolveParameter(s *spec, p parameter) (parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name := strings.TrimPrefix(p.Ref, "#/components/parameters/")
+	resolved, ok := s.Components.Parameters[name]
+	if !ok {
+		return p, fmt.Errorf("unknown parameter reference %q", p.Ref)
+	}
+	return resolved, nil
+}
+
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+func sortedSchemaNames(s *spec) []string {
+	names := make([]string, 0, len(s.Components.Schemas))
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedProperties(sc *schema) []string {
+	names := make([]string, 0, len(sc.Properties))
+	for name := range sc.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isRequired(sc *schema, prop string) bool {
+	for _, r := range sc.Required {
+		if r == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// snakeCase converts an operationId such as getOutageTimeline to
+// get_outage_timeline.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// camelCase converts a snake_case parameter name to camelCase.
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+const header = "Code generated by cmd/gen-clients from api/openapi/openapi.yaml. DO NOT EDIT."
+
+// TypeScript
+
+const tsConfig = `{
+  "compilerOptions": {
+    "target": "ES2020",
+    "module": "ES2020",
+    "moduleResolution": "node",
+    "lib": ["ES2020", "DOM"],
+    "declaration": true,
+    "strict": true,
+    "outDir": "dist"
+  },
+  "include": ["src"]
+}
+`
+
+func tsPackageJSON(s *spec) string {
+	return fmt.Sprintf(`{
+  "name": "@outalator/client",
+  "version": %q,
+  "description": "TypeScript client for the Outalator API (generated)",
+  "license": "MIT",
+  "type": "module",
+  "main": "dist/index.js",
+  "types": "dist/index.d.ts",
+  "files": ["dist"],
+  "scripts": {
+    "build": "tsc"
+  },
+  "devDependencies": {
+    "typescript": "^5.4.0"
+  }
+}
+`, s.Info.Version)
+}
+
+func tsType(sc *schema) string {
+	if sc == nil {
+		return "void"
+	}
+	if sc.Ref != "" {
+		return refName(sc.Ref)
+	}
+	switch sc.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return tsType(sc.Items) + "[]"
+	case "object":
+		if ap, ok := sc.AdditionalProperties.(map[string]any); ok {
+			return "Record<string, " + tsType(inlineSchema(ap)) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+func tsSource(s *spec, endpoints []endpoint) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", header)
+	fmt.Fprintf(&b, "export const API_VERSION = %q;\n", s.Info.Version)
+
+	for _, name := range sortedSchemaNames(s) {
+		sc := s.Components.Schemas[name]
+		fmt.Fprintf(&b, "\nexport interface %s {\n", name)
+		for _, prop := range sortedProperties(sc) {
+			p := sc.Properties[prop]
+			if p.Description != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", p.Description)
+			}
+			opt := "?"
+			if isRequired(sc, prop) {
+				opt = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", prop, opt, tsType(p))
+		}
+		b.WriteString("}\n")
+	}
+
+	b.WriteString(`
+/** Error returned when the API responds with a non-2xx status. */
+export class OutalatorError extends Error {
+  constructor(public readonly status: number, message: string) {
+    super(message);
+    this.name = "OutalatorError";
+  }
+}
+
+export interface ClientOptions {
+  /** Extra headers sent with every request, e.g. a session cookie. */
+  headers?: Record<string, string>;
+  /** fetch implementation; defaults to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, string | number | boolean | undefined>;
+
+export class OutalatorClient {
+  private readonly baseURL: string;
+  private readonly headers: Record<string, string>;
+  private readonly fetchImpl: typeof fetch;
+
+  constructor(baseURL: string, options: ClientOptions = {}) {
+    this.baseURL = baseURL.replace(/\/+$/, "");
+    this.headers = options.headers ?? {};
+    this.fetchImpl = options.fetch ?? fetch;
+  }
+
+  private async request<T>(method: string, path: string, query?: Query, body?: unknown): Promise<T> {
+    let url = this.baseURL + path;
+    if (query) {
+      const params = new URLSearchParams();
+      for (const [key, value] of Object.entries(query)) {
+        if (value !== undefined) {
+          params.set(key, String(value));
+        }
+      }
+      const qs = params.toString();
+      if (qs) {
+        url += "?" + qs;
+      }
+    }
+    const resp = await this.fetchImpl(url, {
+      method,
+      headers: { "Content-Type": "application/json", ...this.headers },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    if (!resp.ok) {
+      let message = "server returned status " + resp.status;
+      try {
+        const data = await resp.json();
+        if (data && typeof data.error === "string") {
+          message = data.error;
+        }
+      } catch {
+        // Keep the generic message.
+      }
+      throw new OutalatorError(resp.status, message);
+    }
+    if (resp.status === 204) {
+      return undefined as T;
+    }
+    return (await resp.json()) as T;
+  }
+`)
+
+	for _, ep := range endpoints {
+		var args []string
+		path := ep.Path
+		for _, p := range ep.PathArgs {
+			arg := camelCase(p.Name)
+			args = append(args, arg+": string")
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", "${encodeURIComponent("+arg+")}")
+		}
+		if ep.Body != nil {
+			args = append(args, "body: "+tsType(ep.Body))
+		}
+		query := "undefined"
+		if len(ep.Query) > 0 {
+			var fields []string
+			required := false
+			for _, p := range ep.Query {
+				opt := "?"
+				if p.Required {
+					opt = ""
+					required = true
+				}
+				fields = append(fields, fmt.Sprintf("%s%s: %s", p.Name, opt, tsType(p.Schema)))
+			}
+			arg := "query: { " + strings.Join(fields, "; ") + " }"
+			if !required {
+				arg += " = {}"
+			}
+			args = append(args, arg)
+			query = "query"
+		}
+		body := "undefined"
+		if ep.Body != nil {
+			body = "body"
+		}
+
+		fmt.Fprintf(&b, "\n  /** %s */\n", ep.Op.Summary)
+		fmt.Fprintf(&b, "  %s(%s): Promise<%s> {\n", ep.Op.OperationID, strings.Join(args, ", "), tsType(ep.Result))
+		fmt.Fprintf(&b, "    return this.request(%q, `%s`, %s, %s);\n", ep.Method, path, query, body)
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Python
+
+func pyProject(s *spec) string {
+	return fmt.Sprintf(`[build-system]
+requires = ["setuptools>=61"]
+build-backend = "setuptools.build_meta"
+
+[project]
+name = "outalator-client"
+version = %q
+description = "Python client for the Outalator API (generated)"
+license = { text = "MIT" }
+requires-python = ">=3.8"
+dependencies = []
+
+[tool.setuptools.package-data]
+outalator_client = ["py.typed"]
+`, s.Info.Version)
+}
+
+func pyType(sc *schema) string {
+	if sc == nil {
+		return "None"
+	}
+	if sc.Ref != "" {
+		return fmt.Sprintf("%q", refName(sc.Ref))
+	}
+	switch sc.Type {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "List[" + pyType(sc.Items) + "]"
+	case "object":
+		if ap, ok := sc.AdditionalProperties.(map[string]any); ok {
+			return "Dict[str, " + pyType(inlineSchema(ap)) + "]"
+		}
+		return "Dict[str, Any]"
+	}
+	return "Any"
+}
+
+func pySource(s *spec, endpoints []endpoint) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", header)
+	fmt.Fprintf(&b, `"""Python client for the %s."""
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, Dict, List, Optional, TypedDict
+
+__version__ = %q
+API_VERSION = __version__
+`, s.Info.Title, s.Info.Version)
+
+	for _, name := range sortedSchemaNames(s) {
+		sc := s.Components.Schemas[name]
+		var required, optional []string
+		for _, prop := range sortedProperties(sc) {
+			line := fmt.Sprintf("    %s: %s\n", prop, pyType(sc.Properties[prop]))
+			if isRequired(sc, prop) {
+				required = append(required, line)
+			} else {
+				optional = append(optional, line)
+			}
+		}
+		// TypedDict cannot mix required and optional keys in one class on
+		// older Pythons, so optional keys go in a total=False subclass.
+		switch {
+		case len(required) > 0 && len(optional) > 0:
+			fmt.Fprintf(&b, "\n\nclass _%sRequired(TypedDict):\n%s", name, strings.Join(required, ""))
+			fmt.Fprintf(&b, "\n\nclass %s(_%sRequired, total=False):\n%s", name, name, strings.Join(optional, ""))
+		case len(required) > 0:
+			fmt.Fprintf(&b, "\n\nclass %s(TypedDict):\n%s", name, strings.Join(required, ""))
+		case len(optional) > 0:
+			fmt.Fprintf(&b, "\n\nclass %s(TypedDict, total=False):\n%s", name, strings.Join(optional, ""))
+		default:
+			fmt.Fprintf(&b, "\n\nclass %s(TypedDict):\n    pass\n", name)
+		}
+	}
+
+	b.WriteString(`
+
+class OutalatorError(Exception):
+    """Raised when the API responds with a non-2xx status."""
+
+    def __init__(self, status: int, message: str) -> None:
+        super().__init__(message)
+        self.status = status
+        self.message = message
+
+
+class Client:
+    """Client for the Outalator REST API.
+
+    headers are sent with every request, e.g. a session cookie.
+    """
+
+    def __init__(self, base_url: str, headers: Optional[Dict[str, str]] = None, timeout: float = 30.0) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.headers = dict(headers or {})
+        self.timeout = timeout
+
+    def _request(self, method: str, path: str, query: Optional[Dict[str, Any]] = None, body: Any = None) -> Any:
+        url = self.base_url + path
+        if query:
+            params = {k: _query_value(v) for k, v in query.items() if v is not None}
+            if params:
+                url += "?" + urllib.parse.urlencode(params)
+        data = None if body is None else json.dumps(body).encode("utf-8")
+        req = urllib.request.Request(url, data=data, method=method)
+        req.add_header("Content-Type", "application/json")
+        for key, value in self.headers.items():
+            req.add_header(key, value)
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout) as resp:
+                payload = resp.read()
+        except urllib.error.HTTPError as e:
+            message = "server returned status %d" % e.code
+            try:
+                err = json.loads(e.read())
+                if isinstance(err, dict) and isinstance(err.get("error"), str):
+                    message = err["error"]
+            except ValueError:
+                pass
+            raise OutalatorError(e.code, message) from None
+        if not payload:
+            return None
+        return json.loads(payload)
+`)
+
+	for _, ep := range endpoints {
+		args := []string{"self"}
+		path := ep.Path
+		var pathFmt []string
+		for _, p := range ep.PathArgs {
+			args = append(args, p.Name+": str")
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", "%s")
+			pathFmt = append(pathFmt, fmt.Sprintf("urllib.parse.quote(%s, safe='')", p.Name))
+		}
+		if ep.Body != nil {
+			args = append(args, "body: "+pyType(ep.Body))
+		}
+		var optionalArgs, queryItems []string
+		for _, p := range ep.Query {
+			if p.Required {
+				args = append(args, p.Name+": "+pyType(p.Schema))
+			} else {
+				optionalArgs = append(optionalArgs, fmt.Sprintf("%s: Optional[%s] = None", p.Name, pyType(p.Schema)))
+			}
+			queryItems = append(queryItems, fmt.Sprintf("%q: %s", p.Name, p.Name))
+		}
+		args = append(args, optionalArgs...)
+
+		pathExpr := fmt.Sprintf("%q", path)
+		switch len(pathFmt) {
+		case 0:
+		case 1:
+			pathExpr += " % " + pathFmt[0]
+		default:
+			pathExpr += " % (" + strings.Join(pathFmt, ", ") + ")"
+		}
+		query := "None"
+		if len(queryItems) > 0 {
+			query = "{" + strings.Join(queryItems, ", ") + "}"
+		}
+		body := "None"
+		if ep.Body != nil {
+			body = "body"
+		}
+
+		fmt.Fprintf(&b, "\n    def %s(%s) -> %s:\n", snakeCase(ep.Op.OperationID), strings.Join(args, ", "), pyType(ep.Result))
+		fmt.Fprintf(&b, "        \"\"\"%s\"\"\"\n", ep.Op.Summary)
+		fmt.Fprintf(&b, "        return self._request(%q, %s, %s, %s)\n", ep.Method, pathExpr, query, body)
+	}
+
+	b.WriteString(`
+
+def _query_value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+`)
+	return b.String()
+}
+
+// inlineSchema converts an additionalProperties schema decoded as a generic
+// map into a schema.
+func inlineSchema(m map[string]any) *schema {
+	sc := &schema{}
+	if v, ok := m["$ref"].(string); ok {
+		sc.Ref = v
+	}
+	if v, ok := m["type"].(string); ok {
+		sc.Type = v
+	}
+	return sc
+}
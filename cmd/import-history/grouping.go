@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/correlation"
+	"github.com/conall/outalator/notification"
+	"github.com/conall/outalator/storage"
+	"github.com/google/uuid"
+)
+
+// importAlert is a fetched alert with the group it was assigned to, if the
+// import groups related alerts
+type importAlert struct {
+	*notification.Alert
+	group *correlation.Group
+}
+
+// groupOutage returns the outage of group, which alert belongs to. The
+// first alert of the group to be imported creates it from the group's
+// leader; every alert then widens it to cover its own severity and
+// resolution.
+func groupOutage(
+	ctx context.Context,
+	store storage.Storage,
+	group *correlation.Group,
+	alert *notification.Alert,
+	stats *domain.ImportRunStats,
+) (*domain.Outage, error) {
+	group.Lock()
+	defer group.Unlock()
+
+	if group.Outage == nil {
+		outage, err := createOutage(ctx, store, group.Leader)
+		if err != nil {
+			return nil, err
+		}
+		group.Outage = outage
+		stats.NewOutages++
+	}
+
+	outage := *group.Outage
+	if !widenOutage(&outage, alert) {
+		return group.Outage, nil
+	}
+	if err := store.UpdateOutage(ctx, &outage); err != nil {
+		return nil, fmt.Errorf("failed to update outage: %w", err)
+	}
+	group.Outage = &outage
+	return group.Outage, nil
+}
+
+// adoptOutage makes the outage of an alert imported earlier its group's
+// outage, unless the group already has one, so a resumed import adds to the
+// outages it started
+func adoptOutage(ctx context.Context, store storage.Storage, group *correlation.Group, outageID uuid.UUID) error {
+	group.Lock()
+	defer group.Unlock()
+
+	if group.Outage != nil {
+		return nil
+	}
+	outage, err := store.GetOutage(ctx, outageID)
+	if err != nil {
+		return fmt.Errorf("failed to get outage: %w", err)
+	}
+	group.Outage = outage
+	return nil
+}
+
+// widenOutage raises outage's severity to alert's if it is a more severe
+// Outalator severity, and keeps it open until all its alerts are resolved,
+// reporting whether outage changed
+func widenOutage(outage *domain.Outage, alert *notification.Alert) bool {
+	changed := false
+	if rank := slices.Index(notification.Severities, alert.Severity); rank >= 0 {
+		current := slices.Index(notification.Severities, outage.Severity)
+		if current < 0 || rank < current {
+			outage.Severity = alert.Severity
+			changed = true
+		}
+	}
+
+	switch {
+	case alert.ResolvedAt == nil:
+		if outage.ResolvedAt != nil {
+			outage.Status = "open"
+			outage.ResolvedAt = nil
+			changed = true
+		}
+	case outage.ResolvedAt != nil && alert.ResolvedAt.After(*outage.ResolvedAt):
+		outage.ResolvedAt = alert.ResolvedAt
+		changed = true
+	}
+
+	if changed {
+		if alert.TriggeredAt.After(outage.UpdatedAt) {
+			outage.UpdatedAt = alert.TriggeredAt
+		}
+		if alert.ResolvedAt != nil && alert.ResolvedAt.After(outage.UpdatedAt) {
+			outage.UpdatedAt = *alert.ResolvedAt
+		}
+	}
+	return changed
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/conall/outalator/notification/opsgenie"
+	"github.com/conall/outalator/storage"
+	"github.com/google/uuid"
+)
+
+// Tag and note metadata keys linking imported records to OpsGenie incidents
+const (
+	incidentTagKey        = "opsgenie_incident"
+	timelineEntryMetadata = "opsgenie_timeline_entry"
+)
+
+// defaultTimelineAuthor is the note author for timeline entries without an actor
+const defaultTimelineAuthor = "OpsGenie"
+
+// runIncidentImport imports OpsGenie incidents as outages, with their
+// associated alerts as the outage's alerts and their timeline entries as
+// notes, on im's workers, fetching from offset. Incidents that were
+// imported before are topped up with any alerts and timeline entries added
+// since.
+func runIncidentImport(
+	ctx context.Context,
+	im *importer,
+	svc *opsgenie.Service,
+	events notification.LogEntryFetcher,
+	notes notification.NoteFetcher,
+	severities notification.SeverityMapping,
+	store storage.Storage,
+	since, until time.Time,
+	teamIDs []string,
+	offset int,
+	dryRun bool,
+) error {
+	// Team filtering can empty a batch, so paging stops on the API's say-so
+	// rather than on an empty batch
+	fetch := func(ctx context.Context, offset int) ([]*opsgenie.Incident, bool, error) {
+		incidents, more, err := svc.FetchHistoricalIncidents(ctx, notification.HistoricalFetchOptions{
+			Since:   since,
+			Until:   until,
+			TeamIDs: teamIDs,
+			Limit:   im.batchSize,
+			Offset:  offset,
+		})
+		for _, incident := range incidents {
+			incident.Priority = severities.Normalize(svc.Name(), incident.Priority)
+		}
+		return incidents, more, err
+	}
+
+	return runPipeline(ctx, im, offset, "incidents", fetch,
+		func(i *opsgenie.Incident) time.Time { return i.CreatedAt },
+		func(i *opsgenie.Incident) string { return "incident " + i.ID },
+		func(ctx context.Context, incident *opsgenie.Incident, stats *domain.ImportRunStats) error {
+			return processIncident(ctx, svc, events, notes, severities, store, incident, dryRun, stats)
+		},
+	)
+}
+
+func processIncident(
+	ctx context.Context,
+	svc *opsgenie.Service,
+	events notification.LogEntryFetcher,
+	notes notification.NoteFetcher,
+	severities notification.SeverityMapping,
+	store storage.Storage,
+	incident *opsgenie.Incident,
+	dryRun bool,
+	stats *domain.ImportRunStats,
+) error {
+	if dryRun {
+		log.Printf("  [DRY RUN] Would import incident: #%s - %s (Status: %s, Date: %s)",
+			incident.TinyID, incident.Message, incident.Status, incident.CreatedAt.Format(time.RFC3339))
+		stats.NewOutages++
+		return nil
+	}
+
+	outage, err := incidentOutage(ctx, store, incident, severities, stats)
+	if err != nil {
+		return err
+	}
+
+	alertIDs, err := svc.FetchIncidentAlertIDs(ctx, incident.ID)
+	if err != nil {
+		return err
+	}
+	for _, alertID := range alertIDs {
+		if err := importIncidentAlert(ctx, svc, events, notes, severities, store, outage.ID, alertID, stats); err != nil {
+			return fmt.Errorf("alert %s: %w", alertID, err)
+		}
+	}
+
+	entries, err := svc.FetchIncidentTimeline(ctx, incident.ID)
+	if err != nil {
+		return err
+	}
+	return importTimeline(ctx, store, outage.ID, entries, stats)
+}
+
+// incidentOutage returns the outage imported earlier for incident, or
+// creates it
+func incidentOutage(ctx context.Context, store storage.Storage, incident *opsgenie.Incident, severities notification.SeverityMapping, stats *domain.ImportRunStats) (*domain.Outage, error) {
+	existing, err := store.FindOutagesByTag(ctx, incidentTagKey, incident.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing outage: %w", err)
+	}
+	if len(existing) > 0 {
+		log.Printf("  Updating #%s - outage already exists", incident.TinyID)
+		stats.Skipped++
+		return existing[0], nil
+	}
+
+	status := incident.Status
+	var resolvedAt *time.Time
+	switch status {
+	case "resolved", "closed":
+		// Incidents carry no resolution time; the last update is the
+		// closest available
+		updated := incident.UpdatedAt
+		resolvedAt = &updated
+	default:
+		status = "open"
+	}
+
+	outage := &domain.Outage{
+		ID:          uuid.New(),
+		Title:       incident.Message,
+		Description: incident.Description,
+		Status:      status,
+		Severity:    notification.OutageSeverity(severities.Normalize("opsgenie", incident.Priority)),
+		CreatedAt:   incident.CreatedAt,
+		UpdatedAt:   incident.UpdatedAt,
+		ResolvedAt:  resolvedAt,
+		Metadata:    map[string]string{"opsgenie_tiny_id": incident.TinyID},
+	}
+	if err := store.CreateOutage(ctx, outage); err != nil {
+		return nil, fmt.Errorf("failed to create outage: %w", err)
+	}
+
+	tag := &domain.Tag{
+		ID:        uuid.New(),
+		OutageID:  outage.ID,
+		Key:       incidentTagKey,
+		Value:     incident.ID,
+		CreatedAt: time.Now(),
+	}
+	if err := store.CreateTag(ctx, tag); err != nil {
+		// Without the tag a later run would import the incident again
+		_ = store.DeleteOutage(ctx, outage.ID)
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	stats.NewOutages++
+
+	log.Printf("  Imported incident: #%s - %s", incident.TinyID, incident.Message)
+	return outage, nil
+}
+
+// importIncidentAlert attaches an incident's alert to outageID. Alerts
+// already stored, e.g. by an earlier alert import, are left where they are.
+func importIncidentAlert(
+	ctx context.Context,
+	svc *opsgenie.Service,
+	events notification.LogEntryFetcher,
+	notes notification.NoteFetcher,
+	severities notification.SeverityMapping,
+	store storage.Storage,
+	outageID uuid.UUID,
+	alertID string,
+	stats *domain.ImportRunStats,
+) error {
+	existing, err := store.GetAlertByExternalID(ctx, alertID, svc.Name())
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("failed to check existing alert: %w", err)
+	}
+	if existing != nil {
+		if err := importAlertEvents(ctx, store, events, existing, stats); err != nil {
+			return err
+		}
+		return importAlertNotes(ctx, store, notes, existing, stats)
+	}
+
+	alert, err := svc.FetchAlert(ctx, alertID)
+	if err != nil {
+		return err
+	}
+	severities.Apply(alert)
+
+	domainAlert := &domain.Alert{
+		ID:             uuid.New(),
+		OutageID:       outageID,
+		ExternalID:     alert.ExternalID,
+		Source:         alert.Source,
+		TeamName:       alert.TeamName,
+		TeamNames:      slices.Clone(alert.TeamNames),
+		Title:          alert.Title,
+		Description:    alert.Description,
+		Severity:       alert.Severity,
+		TriggeredAt:    alert.TriggeredAt,
+		AcknowledgedAt: alert.AcknowledgedAt,
+		ResolvedAt:     alert.ResolvedAt,
+		CreatedAt:      time.Now(),
+		SourceMetadata: alert.SourceMetadata,
+	}
+	if err := store.CreateAlert(ctx, domainAlert); err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+	stats.NewAlerts++
+
+	if err := importAlertEvents(ctx, store, events, domainAlert, stats); err != nil {
+		return err
+	}
+	return importAlertNotes(ctx, store, notes, domainAlert, stats)
+}
+
+// importTimeline adds an incident's timeline entries to outageID as notes,
+// skipping entries imported before
+func importTimeline(ctx context.Context, store storage.Storage, outageID uuid.UUID, entries []*opsgenie.TimelineEntry, stats *domain.ImportRunStats) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	notes, err := store.ListNotesByOutage(ctx, outageID, true)
+	if err != nil {
+		return fmt.Errorf("failed to list notes: %w", err)
+	}
+	imported := make(map[string]bool, len(notes))
+	for _, n := range notes {
+		if id := n.Metadata[timelineEntryMetadata]; id != "" {
+			imported[id] = true
+		}
+	}
+
+	for _, entry := range entries {
+		if imported[entry.ID] {
+			continue
+		}
+		author := entry.Actor
+		if author == "" {
+			author = defaultTimelineAuthor
+		}
+		note := &domain.Note{
+			ID:        uuid.New(),
+			OutageID:  outageID,
+			Content:   entry.Content,
+			Format:    "plaintext",
+			Author:    author,
+			CreatedAt: entry.EventTime,
+			UpdatedAt: entry.EventTime,
+			Metadata:  map[string]string{timelineEntryMetadata: entry.ID},
+		}
+		if err := store.CreateNote(ctx, note); err != nil {
+			return fmt.Errorf("failed to create note: %w", err)
+		}
+		stats.TimelineNotes++
+	}
+
+	return nil
+}
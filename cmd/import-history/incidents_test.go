@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/notification"
+	"github.com/conall/outalator/notification/opsgenie"
+	"github.com/google/uuid"
+)
+
+// fakeOpsGenie serves one resolved incident, inc-1, from the OpsGenie
+// incident, alert and timeline APIs. Tests add alerts and timeline entries
+// between imports.
+type fakeOpsGenie struct {
+	mu       sync.Mutex
+	alertIDs []string
+	timeline []map[string]any
+}
+
+func (f *fakeOpsGenie) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body any
+	switch path := r.URL.Path; {
+	case path == "/v1/incidents":
+		body = map[string]any{"data": []map[string]any{{
+			"id": "inc-1", "tinyId": "7", "message": "Checkout down", "description": "Payments failing",
+			"status": "closed", "priority": "P1", "ownerTeam": "team-1",
+			"createdAt": "2024-07-01T09:00:00Z", "updatedAt": "2024-07-01T10:30:00Z",
+		}}}
+	case path == "/v1/incidents/inc-1/associated-alert-ids":
+		body = map[string]any{"data": f.alertIDs}
+	case path == "/v2/incident-timelines/inc-1/entries":
+		body = map[string]any{"data": map[string]any{"entries": f.timeline}}
+	case strings.HasSuffix(path, "/logs"):
+		body = map[string]any{"data": []map[string]any{
+			{"log": "Notified bob", "type": "AlertRecipient", "owner": "bob@example.com", "createdAt": "2024-07-01T09:01:00Z", "offset": "1"},
+		}}
+	case strings.HasSuffix(path, "/notes"):
+		body = map[string]any{"data": []map[string]any{
+			{"note": "Looking", "owner": "bob@example.com", "createdAt": "2024-07-01T09:02:00Z", "offset": "1"},
+		}}
+	case strings.HasPrefix(path, "/v2/alerts/"):
+		id := strings.TrimPrefix(path, "/v2/alerts/")
+		body = map[string]any{"data": map[string]any{
+			"id": id, "message": "Alert " + id, "priority": "P1", "owner": "bob@example.com",
+			"createdAt": "2024-07-01T08:59:00Z", "teams": []map[string]any{{"id": "team-1", "name": "Payments"}},
+		}}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// timelineEntry is a visible timeline entry of the fake incident
+func timelineEntry(id, actor, content string) map[string]any {
+	return map[string]any{
+		"id": id, "group": "notes", "type": "ResponderNote", "eventTime": "2024-07-01T09:30:00Z",
+		"actor":       map[string]any{"name": actor},
+		"description": map[string]any{"content": content},
+	}
+}
+
+func importIncidents(t *testing.T, svc *opsgenie.Service, store *testutil.MemStorage) *domain.ImportRunStats {
+	t.Helper()
+	stats := &domain.ImportRunStats{}
+	im := &importer{
+		concurrency: 2,
+		batchSize:   10,
+		checkpoint:  func(context.Context, int, time.Time) error { return nil },
+		stats:       stats,
+	}
+	err := runIncidentImport(context.Background(), im, svc, svc, svc, notification.DefaultSeverityMapping(), store,
+		time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), time.Time{}, nil, 0, false)
+	if err != nil {
+		t.Fatalf("runIncidentImport: %v", err)
+	}
+	return stats
+}
+
+func TestIncidentImport(t *testing.T) {
+	fake := &fakeOpsGenie{
+		alertIDs: []string{"al-1"},
+		timeline: []map[string]any{
+			timelineEntry("e1", "carol@example.com", "Rolled back"),
+			timelineEntry("e2", "", "Status page updated"),
+			{"id": "e3", "hidden": true, "description": map[string]any{"content": "internal"}},
+		},
+	}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	svc := opsgenie.New(opsgenie.Config{APIKey: "key", APIURL: srv.URL})
+	store := testutil.NewMemStorage()
+	ctx := context.Background()
+
+	stats := importIncidents(t, svc, store)
+	if stats.NewOutages != 1 || stats.NewAlerts != 1 || stats.Skipped != 0 {
+		t.Errorf("stats = %+v, want one new outage and alert", stats)
+	}
+
+	outages, err := store.FindOutagesByTag(ctx, incidentTagKey, "inc-1")
+	if err != nil || len(outages) != 1 {
+		t.Fatalf("FindOutagesByTag = %d outages, %v, want the incident's outage", len(outages), err)
+	}
+	outage := outages[0]
+	if outage.Title != "Checkout down" || outage.Description != "Payments failing" || outage.Status != "closed" {
+		t.Errorf("outage = %q %q %q, want the incident's message, description and status", outage.Title, outage.Description, outage.Status)
+	}
+	if outage.ResolvedAt == nil || !outage.ResolvedAt.Equal(outage.UpdatedAt) {
+		t.Errorf("ResolvedAt = %v, want the incident's last update %v", outage.ResolvedAt, outage.UpdatedAt)
+	}
+	if outage.Metadata["opsgenie_tiny_id"] != "7" {
+		t.Errorf("Metadata = %v, want the incident's tiny ID", outage.Metadata)
+	}
+
+	alert, err := store.GetAlertByExternalID(ctx, "al-1", "opsgenie")
+	if err != nil {
+		t.Fatalf("GetAlertByExternalID: %v", err)
+	}
+	if alert.OutageID != outage.ID || alert.Title != "Alert al-1" || alert.TeamName != "Payments" {
+		t.Errorf("alert = outage %v title %q team %q, want Alert al-1 from Payments on the incident's outage", alert.OutageID, alert.Title, alert.TeamName)
+	}
+	if alert.SourceMetadata["owner"] != "bob@example.com" {
+		t.Errorf("SourceMetadata = %v, want the alert's owner", alert.SourceMetadata)
+	}
+
+	if got := outageNotes(t, store, outage); got != "OpsGenie:Status page updated bob@example.com:Looking carol@example.com:Rolled back" {
+		t.Errorf("notes = %s, want the alert's note and the visible timeline entries", got)
+	}
+	events, _ := store.ListAlertEventsByOutage(ctx, outage.ID)
+	if len(events) != 1 || events[0].Target != "bob@example.com" {
+		t.Errorf("events = %v, want bob's notification", events)
+	}
+
+	// Importing again tops the outage up with the alerts and timeline
+	// entries added since, without duplicating what is already there
+	fake.mu.Lock()
+	fake.alertIDs = append(fake.alertIDs, "al-2")
+	fake.timeline = append(fake.timeline, timelineEntry("e4", "carol@example.com", "Resolved"))
+	fake.mu.Unlock()
+
+	stats = importIncidents(t, svc, store)
+	if stats.NewOutages != 0 || stats.NewAlerts != 1 || stats.Skipped != 1 {
+		t.Errorf("re-import stats = %+v, want the incident skipped and one new alert", stats)
+	}
+	if outages, _ := store.FindOutagesByTag(ctx, incidentTagKey, "inc-1"); len(outages) != 1 {
+		t.Errorf("re-import left %d outages for the incident, want 1", len(outages))
+	}
+	alerts, _ := store.ListAlertsByOutage(ctx, outage.ID)
+	if len(alerts) != 2 {
+		t.Errorf("outage has %d alerts, want 2", len(alerts))
+	}
+	want := "OpsGenie:Status page updated bob@example.com:Looking bob@example.com:Looking carol@example.com:Resolved carol@example.com:Rolled back"
+	if got := outageNotes(t, store, outage); got != want {
+		t.Errorf("notes after re-import = %s, want %s", got, want)
+	}
+	events, _ = store.ListAlertEventsByOutage(ctx, outage.ID)
+	if len(events) != 2 {
+		t.Errorf("outage has %d alert events, want one per alert", len(events))
+	}
+}
+
+// outageNotes lists the outage's notes as sorted author:content pairs
+func outageNotes(t *testing.T, store *testutil.MemStorage, outage *domain.Outage) string {
+	t.Helper()
+	notes, err := store.ListNotesByOutage(context.Background(), outage.ID, true)
+	if err != nil {
+		t.Fatalf("ListNotesByOutage: %v", err)
+	}
+	var got []string
+	for _, n := range notes {
+		got = append(got, n.Author+":"+n.Content)
+	}
+	sort.Strings(got)
+	return strings.Join(got, " ")
+}
+
+func TestIncidentAlertImportedBefore(t *testing.T) {
+	srv := httptest.NewServer(&fakeOpsGenie{alertIDs: []string{"al-1"}})
+	defer srv.Close()
+	svc := opsgenie.New(opsgenie.Config{APIKey: "key", APIURL: srv.URL})
+	store := testutil.NewMemStorage()
+	ctx := context.Background()
+
+	// An earlier alert import already filed al-1 under its own outage
+	earlier := &domain.Outage{ID: uuid.New(), Title: "Earlier"}
+	if err := store.CreateOutage(ctx, earlier); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateAlert(ctx, &domain.Alert{ID: uuid.New(), OutageID: earlier.ID, ExternalID: "al-1", Source: "opsgenie"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := importIncidents(t, svc, store)
+	if stats.NewOutages != 1 || stats.NewAlerts != 0 {
+		t.Errorf("stats = %+v, want the incident's outage and no new alerts", stats)
+	}
+	alert, err := store.GetAlertByExternalID(ctx, "al-1", "opsgenie")
+	if err != nil || alert.OutageID != earlier.ID {
+		t.Errorf("alert outage = %v, %v, want it left on %v", alert.OutageID, err, earlier.ID)
+	}
+}
@@ -2,50 +2,75 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/conall/outalator/config"
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/correlation"
+	"github.com/conall/outalator/internal/httpclient"
 	"github.com/conall/outalator/notification"
 	"github.com/conall/outalator/notification/opsgenie"
-	"github.com/conall/outalator/notification/pagerduty"
+	"github.com/conall/outalator/storage"
 	"github.com/conall/outalator/storage/postgres"
 	"github.com/google/uuid"
 )
 
-type ImportStats struct {
-	TotalFetched  int
-	NewOutages    int
-	NewAlerts     int
-	Skipped       int
-	Errors        int
-}
-
 func main() {
 	// Command-line flags
 	var (
 		configPath  = flag.String("config", "config.yaml", "Path to configuration file")
-		service     = flag.String("service", "", "Service to import from (pagerduty or opsgenie)")
+		service     = flag.String("service", "", "Service to import from (e.g. pagerduty or opsgenie)")
 		since       = flag.String("since", "", "Start date for import (RFC3339 format, e.g., 2024-01-01T00:00:00Z)")
 		until       = flag.String("until", "", "End date for import (RFC3339 format, optional)")
 		teams       = flag.String("teams", "", "Comma-separated list of team IDs to filter (optional)")
 		listTeams   = flag.Bool("list-teams", false, "List available teams and exit")
 		dryRun      = flag.Bool("dry-run", false, "Preview what would be imported without making changes")
 		batchSize   = flag.Int("batch-size", 100, "Number of incidents to fetch per API call")
+		skipEvents  = flag.Bool("skip-events", false, "Do not fetch provider log entries (notifications, escalations, reassignments) for each alert")
+		skipNotes   = flag.Bool("skip-notes", false, "Do not fetch the notes responders left on each alert")
+		incidents   = flag.Bool("incidents", false, "Import OpsGenie incidents as outages, with their alerts and timeline (opsgenie only)")
+		resume      = flag.Bool("resume", false, "Resume the most recent unfinished import with the same service and filters")
+		runID       = flag.String("run-id", "", "ID of an unfinished import run to resume, with the filters it was started with")
+		concurrency = flag.Int("concurrency", 4, "Number of alerts or incidents imported at once")
+		rateLimit   = flag.Float64("rate-limit", 10, "Maximum provider API requests per second; 0 for no limit")
+		groupBy     = flag.String("group-by", "", "Group related alerts into one outage: time-window, incident-key or service (default one outage per alert)")
+		groupWindow = flag.Duration("group-window", 30*time.Minute, "How far apart alerts grouped by time-window or service may be triggered")
 	)
 	flag.Parse()
 
 	// Validate required flags
 	if *service == "" {
-		log.Fatal("Error: -service flag is required (pagerduty or opsgenie)")
+		log.Fatal("Error: -service flag is required")
+	}
+
+	var groups *correlation.Correlator
+	if *groupBy != "" {
+		if *incidents {
+			log.Fatal("Error: -group-by cannot be used with -incidents, which groups alerts by incident")
+		}
+		strategy, err := correlation.ParseStrategy(*groupBy)
+		if err != nil {
+			log.Fatalf("Error: -group-by: %v", err)
+		}
+		groups = correlation.New(strategy, *groupWindow)
 	}
 
-	if *service != "pagerduty" && *service != "opsgenie" {
-		log.Fatal("Error: -service must be either 'pagerduty' or 'opsgenie'")
+	if *concurrency < 1 || *batchSize < 1 {
+		log.Fatal("Error: -concurrency and -batch-size must be at least 1")
+	}
+
+	if (*resume || *runID != "") && *dryRun {
+		log.Fatal("Error: -resume and -run-id cannot be used with -dry-run, which records no progress")
 	}
 
 	// Load configuration
@@ -53,44 +78,45 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if err := cfg.SeverityMapping.Check(); err != nil {
+		log.Fatalf("Invalid severity mapping: %v", err)
+	}
+	severities := cfg.SeverityMapping.WithDefaults()
 
 	// Initialize notification service
-	var notificationService interface{}
-	switch *service {
-	case "pagerduty":
-		if cfg.PagerDuty == nil || cfg.PagerDuty.APIKey == "" {
-			log.Fatal("Error: PagerDuty API key not configured")
-		}
-		notificationService = pagerduty.New(pagerduty.Config{
-			APIKey: cfg.PagerDuty.APIKey,
-			APIURL: cfg.PagerDuty.APIURL,
-		})
-	case "opsgenie":
-		if cfg.OpsGenie == nil || cfg.OpsGenie.APIKey == "" {
-			log.Fatal("Error: OpsGenie API key not configured")
-		}
-		notificationService = opsgenie.New(opsgenie.Config{
-			APIKey: cfg.OpsGenie.APIKey,
-			APIURL: cfg.OpsGenie.APIURL,
-		})
+	notificationService, err := newService(cfg, *service, httpclient.RateLimit(nil, *rateLimit))
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if _, ok := notificationService.(*opsgenie.Service); *incidents && !ok {
+		log.Fatal("Error: -incidents is only supported with -service opsgenie")
+	}
+	fetcher, ok := notificationService.(notification.HistoricalFetcher)
+	if !ok {
+		log.Fatalf("Error: %s does not support fetching historical alerts", *service)
 	}
 
-	ctx := context.Background()
+	// An interrupted import is recorded as failed, so it can be resumed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Handle list-teams flag
 	if *listTeams {
-		listAvailableTeams(ctx, notificationService, *service)
+		listAvailableTeams(ctx, notificationService)
 		return
 	}
 
-	// Validate and parse date flags
-	if *since == "" {
+	// Validate and parse date flags. A run resumed by ID keeps its own.
+	if *since == "" && *runID == "" {
 		log.Fatal("Error: -since flag is required (RFC3339 format, e.g., 2024-01-01T00:00:00Z)")
 	}
 
-	sinceTime, err := time.Parse(time.RFC3339, *since)
-	if err != nil {
-		log.Fatalf("Error: Invalid -since date format: %v", err)
+	var sinceTime time.Time
+	if *since != "" {
+		sinceTime, err = time.Parse(time.RFC3339, *since)
+		if err != nil {
+			log.Fatalf("Error: Invalid -since date format: %v", err)
+		}
 	}
 
 	var untilTime time.Time
@@ -129,20 +155,72 @@ func main() {
 		defer func() { _ = store.Close() }()
 	}
 
+	filter := importFilter{Kind: "alerts", Since: sinceTime, Until: untilTime, TeamIDs: teamIDs}
+	if *incidents {
+		filter.Kind = "incidents"
+	}
+
+	// Record the run, or pick up an unfinished one, so its progress is saved
+	// after each batch
+	var run *domain.ImportRun
+	stats := &domain.ImportRunStats{}
+	startOffset := 0
+	if !*dryRun {
+		run, err = startRun(ctx, store, *service, filter, *since != "", *until != "", *resume, *runID)
+		if err != nil {
+			log.Fatalf("Failed to start import run: %v", err)
+		}
+		filter = runFilter(run)
+		sinceTime, untilTime, teamIDs = filter.Since, filter.Until, filter.TeamIDs
+		stats = &run.Stats
+		startOffset = run.Offset
+	}
+	im := &importer{
+		concurrency: *concurrency,
+		batchSize:   *batchSize,
+		checkpoint:  checkpointer(store, run),
+		stats:       stats,
+	}
+
 	// Run import
 	log.Printf("Starting import from %s", *service)
 	log.Printf("Date range: %s to %s", sinceTime.Format(time.RFC3339), untilTime.Format(time.RFC3339))
 	if len(teamIDs) > 0 {
 		log.Printf("Team filter: %v", teamIDs)
 	}
+	log.Printf("Concurrency: %d, rate limit: %g requests/s", *concurrency, *rateLimit)
+	if groups != nil {
+		log.Printf("Grouping alerts by %s", *groupBy)
+	}
 	if *dryRun {
 		log.Println("DRY RUN MODE - No changes will be made")
 	}
 	log.Println()
 
-	stats := &ImportStats{}
-	err = runImport(ctx, notificationService, store, sinceTime, untilTime, teamIDs, *batchSize, *dryRun, stats, *service)
+	// Provider log entries are fetched per alert, so they can be skipped to
+	// save API calls on large imports
+	var events notification.LogEntryFetcher
+	if !*skipEvents {
+		events, _ = notificationService.(notification.LogEntryFetcher)
+	}
+	var notes notification.NoteFetcher
+	if !*skipNotes {
+		notes, _ = notificationService.(notification.NoteFetcher)
+	}
+
+	if filter.Kind == "incidents" {
+		ogService := notificationService.(*opsgenie.Service)
+		err = runIncidentImport(ctx, im, ogService, events, notes, severities, store, sinceTime, untilTime, teamIDs, startOffset, *dryRun)
+	} else {
+		err = runImport(ctx, im, fetcher, events, notes, severities, groups, store, sinceTime, untilTime, teamIDs, startOffset, *dryRun)
+	}
+	if run != nil {
+		finishRun(store, run, err)
+	}
 	if err != nil {
+		if run != nil {
+			log.Printf("Resume with: -service %s -resume -run-id %s", *service, run.ID)
+		}
 		log.Fatalf("Import failed: %v", err)
 	}
 
@@ -153,197 +231,153 @@ func main() {
 	log.Printf("New outages created: %d", stats.NewOutages)
 	log.Printf("New alerts created: %d", stats.NewAlerts)
 	log.Printf("Skipped (already exists): %d", stats.Skipped)
+	log.Printf("Alert log entries synced: %d", stats.AlertEvents)
+	if filter.Kind == "incidents" {
+		log.Printf("Timeline entries added as notes: %d", stats.TimelineNotes)
+	} else if notes != nil {
+		log.Printf("Provider notes added: %d", stats.TimelineNotes)
+	}
 	if stats.Errors > 0 {
 		log.Printf("Errors encountered: %d", stats.Errors)
 	}
 }
 
-func listAvailableTeams(ctx context.Context, svc interface{}, serviceName string) {
-	log.Printf("Fetching teams from %s...\n", serviceName)
-
-	var teams []interface{ GetID() string; GetName() string }
-	var err error
+func listAvailableTeams(ctx context.Context, svc notification.Service) {
+	log.Printf("Fetching teams from %s...\n", svc.Name())
 
-	switch serviceName {
-	case "pagerduty":
-		pdService, ok := svc.(*pagerduty.Service)
-		if !ok {
-			log.Fatalf("Expected *pagerduty.Service but got unexpected type")
-		}
-		pdTeams, err := pdService.ListTeams(ctx)
-		if err != nil {
-			log.Fatalf("Failed to list teams: %v", err)
-		}
-		teams = make([]interface{ GetID() string; GetName() string }, len(pdTeams))
-		for i := range pdTeams {
-			teams[i] = &teamAdapter{id: pdTeams[i].ID, name: pdTeams[i].Name}
-		}
-	case "opsgenie":
-		ogService, ok := svc.(*opsgenie.Service)
-		if !ok {
-			log.Fatalf("Expected *opsgenie.Service but got unexpected type")
-		}
-		ogTeams, err := ogService.ListTeams(ctx)
-		if err != nil {
-			log.Fatalf("Failed to list teams: %v", err)
-		}
-		teams = make([]interface{ GetID() string; GetName() string }, len(ogTeams))
-		for i := range ogTeams {
-			teams[i] = &teamAdapter{id: ogTeams[i].ID, name: ogTeams[i].Name}
-		}
+	fetcher, ok := svc.(notification.TeamFetcher)
+	if !ok {
+		log.Fatalf("Error: %s does not support listing teams", svc.Name())
 	}
-
+	teams, err := fetcher.FetchTeams(ctx)
 	if err != nil {
 		log.Fatalf("Failed to list teams: %v", err)
 	}
 
 	log.Println("\nAvailable teams:")
 	for _, team := range teams {
-		fmt.Printf("  ID: %s\tName: %s\n", team.GetID(), team.GetName())
+		fmt.Printf("  ID: %s\tName: %s\n", team.ExternalID, team.Name)
 	}
 }
 
-type teamAdapter struct {
-	id   string
-	name string
-}
-
-func (t *teamAdapter) GetID() string   { return t.id }
-func (t *teamAdapter) GetName() string { return t.name }
-
+// runImport imports alerts from svc on im's workers, fetching from offset.
+// Alerts related according to groups share an outage; with no groups, each
+// alert gets its own.
 func runImport(
 	ctx context.Context,
-	svc interface{},
-	store *postgres.PostgresStorage,
+	im *importer,
+	svc notification.HistoricalFetcher,
+	events notification.LogEntryFetcher,
+	notes notification.NoteFetcher,
+	severities notification.SeverityMapping,
+	groups *correlation.Correlator,
+	store storage.Storage,
 	since, until time.Time,
 	teamIDs []string,
-	batchSize int,
+	offset int,
 	dryRun bool,
-	stats *ImportStats,
-	serviceName string,
 ) error {
-	offset := 0
-	hasMore := true
-
-	for hasMore {
-		var alerts []*notification.Alert
-		var err error
-
-		// Fetch batch based on service type
-		switch serviceName {
-		case "pagerduty":
-			pdService, ok := svc.(*pagerduty.Service)
-			if !ok {
-				return fmt.Errorf("expected *pagerduty.Service but got unexpected type")
-			}
-			opts := pagerduty.HistoricalFetchOptions{
-				Since:   since,
-				Until:   until,
-				TeamIDs: teamIDs,
-				Limit:   batchSize,
-				Offset:  offset,
-			}
-			alerts, hasMore, err = pdService.FetchHistoricalIncidents(ctx, opts)
-		case "opsgenie":
-			ogService, ok := svc.(*opsgenie.Service)
-			if !ok {
-				return fmt.Errorf("expected *opsgenie.Service but got unexpected type")
-			}
-			opts := opsgenie.HistoricalFetchOptions{
-				Since:   since,
-				Until:   until,
-				TeamIDs: teamIDs,
-				Limit:   batchSize,
-				Offset:  offset,
-			}
-			alerts, hasMore, err = ogService.FetchHistoricalAlerts(ctx, opts)
-		}
-
+	fetch := func(ctx context.Context, offset int) ([]*importAlert, bool, error) {
+		alerts, more, err := svc.FetchHistoricalAlerts(ctx, notification.HistoricalFetchOptions{
+			Since:   since,
+			Until:   until,
+			TeamIDs: teamIDs,
+			Limit:   im.batchSize,
+			Offset:  offset,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to fetch alerts at offset %d: %w", offset, err)
+			return nil, false, err
 		}
-
-		if len(alerts) == 0 {
-			break
-		}
-
-		stats.TotalFetched += len(alerts)
-		log.Printf("Fetched %d incidents/alerts (offset: %d)", len(alerts), offset)
-
-		// Process each alert
-		for _, alert := range alerts {
-			if err := processAlert(ctx, store, alert, dryRun, stats); err != nil {
-				log.Printf("Error processing alert %s: %v", alert.ExternalID, err)
-				stats.Errors++
+		// Alerts are mapped and assigned to groups here, in the order they
+		// were triggered, so each group is led by its earliest alert and
+		// takes the most severe of its alerts' mapped severities
+		items := make([]*importAlert, len(alerts))
+		for i, alert := range alerts {
+			severities.Apply(alert)
+			items[i] = &importAlert{Alert: alert}
+			if groups != nil {
+				items[i].group = groups.Assign(alert)
 			}
 		}
-
-		offset += batchSize
-
-		// Small delay to avoid rate limiting
-		time.Sleep(500 * time.Millisecond)
+		return items, more, nil
 	}
 
-	return nil
+	return runPipeline(ctx, im, offset, "incidents/alerts", fetch,
+		func(a *importAlert) time.Time { return a.TriggeredAt },
+		func(a *importAlert) string { return "alert " + a.ExternalID },
+		func(ctx context.Context, alert *importAlert, stats *domain.ImportRunStats) error {
+			return processAlert(ctx, store, events, notes, alert, dryRun, stats)
+		},
+	)
 }
 
 func processAlert(
 	ctx context.Context,
-	store *postgres.PostgresStorage,
-	alert *notification.Alert,
+	store storage.Storage,
+	events notification.LogEntryFetcher,
+	notes notification.NoteFetcher,
+	item *importAlert,
 	dryRun bool,
-	stats *ImportStats,
+	stats *domain.ImportRunStats,
 ) error {
+	alert := item.Alert
 	if dryRun {
-		log.Printf("  [DRY RUN] Would import: %s - %s (Team: %s, Date: %s)",
-			alert.ExternalID, alert.Title, alert.TeamName, alert.TriggeredAt.Format(time.RFC3339))
-		stats.NewOutages++
+		if item.group == nil || item.group.Leader == alert {
+			log.Printf("  [DRY RUN] Would import: %s - %s (Team: %s, Date: %s)",
+				alert.ExternalID, alert.Title, alert.TeamName, alert.TriggeredAt.Format(time.RFC3339))
+			stats.NewOutages++
+		} else {
+			log.Printf("  [DRY RUN] Would import: %s - %s (Team: %s, Date: %s) into the outage for %s",
+				alert.ExternalID, alert.Title, alert.TeamName, alert.TriggeredAt.Format(time.RFC3339), item.group.Leader.ExternalID)
+		}
 		stats.NewAlerts++
 		return nil
 	}
 
 	// Check if alert already exists
 	existing, err := store.GetAlertByExternalID(ctx, alert.ExternalID, alert.Source)
-	if err != nil && err.Error() != "alert not found" {
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
 		return fmt.Errorf("failed to check existing alert: %w", err)
 	}
 
 	if existing != nil {
 		log.Printf("  Skipping %s - already exists", alert.ExternalID)
 		stats.Skipped++
-		return nil
-	}
-
-	// Create a new outage for this alert
-	outageID := uuid.New()
-	status := "resolved"
-	if alert.ResolvedAt == nil {
-		status = "open"
+		if item.group != nil {
+			if err := adoptOutage(ctx, store, item.group, existing.OutageID); err != nil {
+				return err
+			}
+		}
+		// Earlier imports may predate alert events and notes, so backfill
+		// them anyway
+		if err := importAlertEvents(ctx, store, events, existing, stats); err != nil {
+			return err
+		}
+		return importAlertNotes(ctx, store, notes, existing, stats)
 	}
 
-	outage := &domain.Outage{
-		ID:          outageID,
-		Title:       alert.Title,
-		Description: alert.Description,
-		Status:      status,
-		Severity:    alert.Severity,
-		CreatedAt:   alert.TriggeredAt,
-		UpdatedAt:   alert.TriggeredAt,
-		ResolvedAt:  alert.ResolvedAt,
+	var outage *domain.Outage
+	if item.group != nil {
+		outage, err = groupOutage(ctx, store, item.group, alert, stats)
+	} else {
+		// Create a new outage for this alert
+		outage, err = createOutage(ctx, store, alert)
+		if err == nil {
+			stats.NewOutages++
+		}
 	}
-
-	if err := store.CreateOutage(ctx, outage); err != nil {
-		return fmt.Errorf("failed to create outage: %w", err)
+	if err != nil {
+		return err
 	}
-	stats.NewOutages++
 
 	// Create the alert and link it to the outage
 	domainAlert := &domain.Alert{
 		ID:             uuid.New(),
-		OutageID:       outageID,
+		OutageID:       outage.ID,
 		ExternalID:     alert.ExternalID,
 		Source:         alert.Source,
 		TeamName:       alert.TeamName,
+		TeamNames:      slices.Clone(alert.TeamNames),
 		Title:          alert.Title,
 		Description:    alert.Description,
 		Severity:       alert.Severity,
@@ -351,16 +385,166 @@ func processAlert(
 		AcknowledgedAt: alert.AcknowledgedAt,
 		ResolvedAt:     alert.ResolvedAt,
 		CreatedAt:      time.Now(),
+		SourceMetadata: alert.SourceMetadata,
 	}
 
 	if err := store.CreateAlert(ctx, domainAlert); err != nil {
-		// Try to clean up the outage if alert creation fails
-		_ = store.DeleteOutage(ctx, outageID)
+		// Try to clean up the outage if alert creation fails, unless other
+		// alerts of its group may share it
+		if item.group == nil {
+			_ = store.DeleteOutage(ctx, outage.ID)
+		}
 		return fmt.Errorf("failed to create alert: %w", err)
 	}
 	stats.NewAlerts++
 
 	log.Printf("  Imported: %s - %s (Team: %s)", alert.ExternalID, alert.Title, alert.TeamName)
 
+	if err := importAlertEvents(ctx, store, events, domainAlert, stats); err != nil {
+		return err
+	}
+	return importAlertNotes(ctx, store, notes, domainAlert, stats)
+}
+
+// createOutage records a new outage for alert
+func createOutage(ctx context.Context, store storage.Storage, alert *notification.Alert) (*domain.Outage, error) {
+	status := "resolved"
+	if alert.ResolvedAt == nil {
+		status = "open"
+	}
+
+	outage := &domain.Outage{
+		ID:          uuid.New(),
+		Title:       alert.Title,
+		Description: alert.Description,
+		Status:      status,
+		Severity:    notification.OutageSeverity(alert.Severity),
+		CreatedAt:   alert.TriggeredAt,
+		UpdatedAt:   alert.TriggeredAt,
+		ResolvedAt:  alert.ResolvedAt,
+	}
+
+	if err := store.CreateOutage(ctx, outage); err != nil {
+		return nil, fmt.Errorf("failed to create outage: %w", err)
+	}
+	return outage, nil
+}
+
+// importAlertEvents records the provider log entries (notifications,
+// escalations, reassignments) of an imported alert. Entries already stored
+// are ignored by the storage layer, so re-running an import is safe.
+func importAlertEvents(
+	ctx context.Context,
+	store storage.Storage,
+	events notification.LogEntryFetcher,
+	alert *domain.Alert,
+	stats *domain.ImportRunStats,
+) error {
+	if events == nil {
+		return nil
+	}
+
+	entries, err := events.FetchLogEntries(ctx, alert.ExternalID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch log entries: %w", err)
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		event := &domain.AlertEvent{
+			ID:         uuid.New(),
+			AlertID:    alert.ID,
+			ExternalID: entry.ExternalID,
+			Type:       entry.Type,
+			Summary:    entry.Summary,
+			Actor:      entry.Actor,
+			Target:     entry.Target,
+			OccurredAt: entry.OccurredAt,
+			CreatedAt:  now,
+		}
+		if err := store.CreateAlertEvent(ctx, event); err != nil {
+			return fmt.Errorf("failed to create alert event: %w", err)
+		}
+	}
+	stats.AlertEvents += len(entries)
+
 	return nil
 }
+
+// providerNoteMetadata is the note metadata key recording which provider
+// note, as source:alert:note, a note was imported from
+const providerNoteMetadata = "provider_note"
+
+// importAlertNotes adds the notes responders left on an imported alert with
+// its provider to the alert's outage, with their original authors and
+// times, skipping notes imported before
+func importAlertNotes(
+	ctx context.Context,
+	store storage.Storage,
+	notes notification.NoteFetcher,
+	alert *domain.Alert,
+	stats *domain.ImportRunStats,
+) error {
+	if notes == nil {
+		return nil
+	}
+
+	alertNotes, err := notes.FetchNotes(ctx, alert.ExternalID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch notes: %w", err)
+	}
+	if len(alertNotes) == 0 {
+		return nil
+	}
+
+	existing, err := store.ListNotesByOutage(ctx, alert.OutageID, true)
+	if err != nil {
+		return fmt.Errorf("failed to list notes: %w", err)
+	}
+	imported := make(map[string]bool, len(existing))
+	for _, n := range existing {
+		if id := n.Metadata[providerNoteMetadata]; id != "" {
+			imported[id] = true
+		}
+	}
+
+	for _, n := range alertNotes {
+		id := alert.Source + ":" + alert.ExternalID + ":" + n.ExternalID
+		if imported[id] {
+			continue
+		}
+		author := n.Author
+		if author == "" {
+			author = alert.Source
+		}
+		note := &domain.Note{
+			ID:        uuid.New(),
+			OutageID:  alert.OutageID,
+			Content:   n.Content,
+			Format:    "plaintext",
+			Author:    author,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.CreatedAt,
+			Metadata:  map[string]string{providerNoteMetadata: id},
+		}
+		if err := store.CreateNote(ctx, note); err != nil {
+			return fmt.Errorf("failed to create note: %w", err)
+		}
+		stats.TimelineNotes++
+	}
+
+	return nil
+}
+
+// retryPolicy builds a provider's API retry policy from its configuration,
+// logging each retry so a slow import shows why
+func retryPolicy(retry config.RetryConfig) httpclient.RetryPolicy {
+	return httpclient.RetryPolicy{
+		MaxRetries: retry.MaxRetries,
+		MinBackoff: retry.MinBackoff,
+		MaxBackoff: retry.MaxBackoff,
+		OnRetry: func(req *http.Request, reason string, wait time.Duration) {
+			log.Printf("Retrying %s %s in %s (%s)", req.Method, req.URL.Path, wait.Round(time.Millisecond), reason)
+		},
+	}
+}
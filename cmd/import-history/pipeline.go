@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// importer processes fetched alerts or incidents on a pool of workers while
+// the next batch is fetched. Progress is saved once every item of a batch,
+// and of the batches before it, has been processed, so a resumed import
+// never skips an item.
+type importer struct {
+	concurrency int
+	batchSize   int
+	checkpoint  func(ctx context.Context, offset int, lastItem time.Time) error
+
+	mu    sync.Mutex // Guards stats, which checkpoint saves
+	stats *domain.ImportRunStats
+}
+
+// record adds the counts of a processed item or fetched batch to the run's
+// statistics
+func (im *importer) record(delta domain.ImportRunStats) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.stats.Add(delta)
+}
+
+// batch is a fetched batch of items awaiting processing
+type batch struct {
+	next     int       // Offset of the batch after it
+	lastItem time.Time // Creation time of its newest item
+	pending  atomic.Int64
+	done     chan struct{} // Closed once every item has been processed
+}
+
+// processed marks one of the batch's items processed
+func (b *batch) processed() {
+	if b.pending.Add(-1) == 0 {
+		close(b.done)
+	}
+}
+
+// fetchFunc fetches the batch of items starting at offset, reporting
+// whether there are more after it
+type fetchFunc[T any] func(ctx context.Context, offset int) ([]T, bool, error)
+
+// runPipeline fetches batches from offset until the provider has no more,
+// processing their items concurrently. Items that fail to process are
+// logged and counted as errors; failing to fetch or to save progress stops
+// the import. name describes the items in logs, and created and id return
+// an item's creation time and ID.
+func runPipeline[T any](
+	ctx context.Context,
+	im *importer,
+	offset int,
+	name string,
+	fetch fetchFunc[T],
+	created func(T) time.Time,
+	id func(T) string,
+	process func(ctx context.Context, item T, stats *domain.ImportRunStats) error,
+) error {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		item  T
+		batch *batch
+	}
+	jobs := make(chan job, im.batchSize)
+	// Batches wait here, in order, for their items to be processed
+	batches := make(chan *batch, im.concurrency)
+
+	var fetchErr error
+	go func() {
+		defer close(jobs)
+		defer close(batches)
+		for hasMore := true; hasMore; {
+			items, more, err := fetch(ctx, offset)
+			if err != nil {
+				fetchErr = fmt.Errorf("failed to fetch %s at offset %d: %w", name, offset, err)
+				return
+			}
+			hasMore = more
+			log.Printf("Fetched %d %s (offset: %d)", len(items), name, offset)
+			im.record(domain.ImportRunStats{TotalFetched: len(items)})
+
+			offset += im.batchSize
+			b := &batch{next: offset, done: make(chan struct{})}
+			b.pending.Store(int64(len(items)))
+			for _, item := range items {
+				if t := created(item); t.After(b.lastItem) {
+					b.lastItem = t
+				}
+			}
+			if len(items) == 0 {
+				close(b.done)
+			}
+
+			select {
+			case batches <- b:
+			case <-ctx.Done():
+				return
+			}
+			for _, item := range items {
+				select {
+				case jobs <- job{item: item, batch: b}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for i := 0; i < im.concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for j := range jobs {
+				// Once stopping, items are drained unprocessed and their
+				// batch is never saved
+				if ctx.Err() != nil {
+					continue
+				}
+				var delta domain.ImportRunStats
+				if err := process(ctx, j.item, &delta); err != nil {
+					log.Printf("Error processing %s: %v", id(j.item), err)
+					delta.Errors++
+				}
+				im.record(delta)
+				j.batch.processed()
+			}
+		}()
+	}
+
+	// Save progress batch by batch, in the order they were fetched
+	var checkpointErr error
+	for b := range batches {
+		select {
+		case <-b.done:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		im.mu.Lock()
+		checkpointErr = im.checkpoint(ctx, b.next, b.lastItem)
+		im.mu.Unlock()
+		if checkpointErr != nil {
+			break
+		}
+	}
+	cancel()
+	for range batches {
+	}
+	workers.Wait()
+
+	switch {
+	case checkpointErr != nil:
+		return checkpointErr
+	case fetchErr != nil:
+		return fetchErr
+	}
+	// Otherwise the import is done, unless the caller's context was
+	// cancelled, e.g. by an interrupt
+	return parent.Err()
+}
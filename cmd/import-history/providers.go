@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/conall/outalator/config"
+	"github.com/conall/outalator/internal/httpclient"
+	"github.com/conall/outalator/internal/providers"
+	"github.com/conall/outalator/notification"
+)
+
+// newService builds the notification service named name the same way the
+// server does, sending its API requests through transport. The import only
+// relies on notification.HistoricalFetcher, so any provider the server can
+// register can be imported from.
+func newService(cfg *config.Config, name string, transport http.RoundTripper) (notification.Service, error) {
+	services, err := providers.New(cfg, providers.Options{
+		Transport: func(string) http.RoundTripper { return transport },
+		Retry: func(_ string, retry config.RetryConfig) httpclient.RetryPolicy {
+			return retryPolicy(retry)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, svc := range services {
+		if svc.Name() == name {
+			return svc, nil
+		}
+		names = append(names, svc.Name())
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no notification services are configured")
+	}
+	return nil, fmt.Errorf("%s is not configured (configured: %s)", name, strings.Join(names, ", "))
+}
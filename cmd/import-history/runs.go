@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage"
+	"github.com/google/uuid"
+)
+
+// importFilter is what an import run imports
+type importFilter struct {
+	Kind    string // alerts or incidents
+	Since   time.Time
+	Until   time.Time
+	TeamIDs []string
+}
+
+// hash identifies the filter, so a run is only resumed with the filters it
+// was started with
+func (f importFilter) hash() string {
+	teams := append([]string(nil), f.TeamIDs...)
+	sort.Strings(teams)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s", f.Kind, f.Since.UTC().Format(time.RFC3339), f.Until.UTC().Format(time.RFC3339), strings.Join(teams, ","))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// runFilter returns the filter run was started with
+func runFilter(run *domain.ImportRun) importFilter {
+	return importFilter{Kind: run.Kind, Since: run.Since, Until: run.Until, TeamIDs: run.TeamIDs}
+}
+
+// startRun records a new import run for filter or, when resuming, marks an
+// unfinished one running again. A run named by runID is resumed with its
+// own filters; with sinceSet, filter must match them. Otherwise -resume
+// picks the most recent unfinished run from provider with the same filter,
+// taking its end date when untilSet is false, and starts a new run when
+// there is none.
+func startRun(
+	ctx context.Context,
+	store storage.Storage,
+	provider string,
+	filter importFilter,
+	sinceSet, untilSet, resume bool,
+	runID string,
+) (*domain.ImportRun, error) {
+	now := time.Now()
+
+	var run *domain.ImportRun
+	switch {
+	case runID != "":
+		id, err := uuid.Parse(runID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -run-id: %w", err)
+		}
+		if run, err = store.GetImportRun(ctx, id); err != nil {
+			return nil, err
+		}
+		if run.Provider != provider {
+			return nil, fmt.Errorf("run %s imported from %s, not %s", run.ID, run.Provider, provider)
+		}
+		if run.Status == domain.ImportRunCompleted {
+			return nil, fmt.Errorf("run %s already completed", run.ID)
+		}
+		if sinceSet {
+			if !untilSet {
+				filter.Until = run.Until
+			}
+			if filter.hash() != run.FilterHash {
+				return nil, fmt.Errorf("run %s was started with different filters; omit -since, -until, -teams and -incidents to resume it as it was", run.ID)
+			}
+		}
+	case resume:
+		runs, err := store.ListImportRuns(ctx, provider)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range runs {
+			f := filter
+			if !untilSet {
+				f.Until = r.Until
+			}
+			if r.Status != domain.ImportRunCompleted && f.hash() == r.FilterHash {
+				run = r
+				break
+			}
+		}
+		if run == nil {
+			log.Println("No unfinished import with these filters to resume; starting a new one")
+		}
+	}
+
+	if run != nil {
+		run.Status = domain.ImportRunRunning
+		run.Error = ""
+		run.FinishedAt = nil
+		run.UpdatedAt = now
+		if err := store.UpdateImportRun(ctx, run); err != nil {
+			return nil, err
+		}
+		log.Printf("Resuming import run %s at offset %d", run.ID, run.Offset)
+		return run, nil
+	}
+
+	run = &domain.ImportRun{
+		ID:         uuid.New(),
+		Provider:   provider,
+		Kind:       filter.Kind,
+		FilterHash: filter.hash(),
+		Since:      filter.Since,
+		Until:      filter.Until,
+		TeamIDs:    filter.TeamIDs,
+		Status:     domain.ImportRunRunning,
+		StartedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := store.CreateImportRun(ctx, run); err != nil {
+		return nil, err
+	}
+	log.Printf("Started import run %s (resume with -resume -run-id %s)", run.ID, run.ID)
+	return run, nil
+}
+
+// checkpointer returns a function that saves run's progress after each
+// batch: offset is where the next batch starts and lastItem the creation
+// time of the last alert or incident processed. Without a run, as in a dry
+// run, progress is not saved.
+func checkpointer(store storage.Storage, run *domain.ImportRun) func(ctx context.Context, offset int, lastItem time.Time) error {
+	return func(ctx context.Context, offset int, lastItem time.Time) error {
+		if run == nil {
+			return nil
+		}
+		run.Offset = offset
+		if !lastItem.IsZero() {
+			run.LastItemAt = &lastItem
+		}
+		run.UpdatedAt = time.Now()
+		if err := store.UpdateImportRun(ctx, run); err != nil {
+			return fmt.Errorf("failed to save import progress: %w", err)
+		}
+		return nil
+	}
+}
+
+// finishRun records how run ended: completed, or failed with importErr
+func finishRun(store storage.Storage, run *domain.ImportRun, importErr error) {
+	now := time.Now()
+	run.Status = domain.ImportRunCompleted
+	if importErr != nil {
+		run.Status = domain.ImportRunFailed
+		run.Error = importErr.Error()
+	}
+	run.UpdatedAt = now
+	run.FinishedAt = &now
+	// The import's context may have been cancelled by an interrupt
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := store.UpdateImportRun(ctx, run); err != nil {
+		log.Printf("Failed to record the end of import run %s: %v", run.ID, err)
+	}
+}
@@ -5,20 +5,24 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/conall/outalator/config"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/logging"
 	"github.com/conall/outalator/internal/mcp"
-	"github.com/conall/outalator/notification/opsgenie"
-	"github.com/conall/outalator/notification/pagerduty"
+	"github.com/conall/outalator/internal/providers"
 	"github.com/conall/outalator/service"
 	"github.com/conall/outalator/storage"
 )
 
 func main() {
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	user := flag.String("user", "", "Email address of the user the server acts as when changing outages")
 	flag.Parse()
 
 	// Load configuration
@@ -27,6 +31,16 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Stdout carries the MCP protocol, so logs go to stderr
+	logger, err := logging.New(logging.Config{
+		Level:  cfg.Logging.Level,
+		Format: cfg.Logging.Format,
+	}, os.Stderr)
+	if err != nil {
+		log.Fatalf("Invalid logging config: %v", err)
+	}
+	slog.SetDefault(logger)
+
 	// Initialize storage backend (postgres by default; sqlite with -tags sqlite)
 	db, err := storage.New(context.Background(), cfg.Database)
 	if err != nil {
@@ -35,34 +49,37 @@ func main() {
 	defer func() { _ = db.Close() }()
 
 	// Initialize service
-	svc := service.New(db)
-
-	// Register notification services
-	if cfg.PagerDuty != nil && cfg.PagerDuty.APIKey != "" {
-		pdConfig := pagerduty.Config{
-			APIKey: cfg.PagerDuty.APIKey,
-			APIURL: cfg.PagerDuty.APIURL,
+	svc := service.New(db, logger)
+	if err := svc.SetCustomFieldSchemas(cfg.CustomFields); err != nil {
+		log.Fatalf("Invalid custom field schemas: %v", err)
+	}
+	if err := svc.SetSeverityMapping(cfg.SeverityMapping); err != nil {
+		log.Fatalf("Invalid severity mapping: %v", err)
+	}
+	if cfg.Auth != nil {
+		svc.SetAdmins(cfg.Auth.Admins)
+	}
+	if len(cfg.OutageTransitions) > 0 {
+		if err := svc.SetOutageTransitions(outageTransitions(cfg.OutageTransitions)); err != nil {
+			log.Fatalf("Invalid outage transitions: %v", err)
 		}
-		pdSvc := pagerduty.New(pdConfig)
-		svc.RegisterNotificationService(pdSvc)
-		log.Println("Registered PagerDuty notification service")
 	}
 
-	if cfg.OpsGenie != nil && cfg.OpsGenie.APIKey != "" {
-		ogConfig := opsgenie.Config{
-			APIKey: cfg.OpsGenie.APIKey,
-			APIURL: cfg.OpsGenie.APIURL,
-		}
-		ogSvc := opsgenie.New(ogConfig)
-		svc.RegisterNotificationService(ogSvc)
-		log.Println("Registered OpsGenie notification service")
+	// Register notification services
+	notificationServices, err := providers.New(cfg, providers.Options{})
+	if err != nil {
+		log.Fatalf("Failed to configure notification services: %v", err)
+	}
+	for _, ns := range notificationServices {
+		svc.RegisterNotificationService(ns)
+		log.Printf("Registered %s notification service", ns.Name())
 	}
 
 	// Create MCP server
 	mcpServer := mcp.NewServer(svc)
 
 	// Set up context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(actingAs(context.Background(), cfg, *user))
 	defer cancel()
 
 	// Handle shutdown signals
@@ -83,3 +100,26 @@ func main() {
 
 	fmt.Fprintln(os.Stderr, "MCP server stopped")
 }
+
+// actingAs returns a context acting as the user, so the service applies
+// the owning team checks the web server does. With authentication enabled
+// and no user given the server acts anonymously, and can only change
+// outages no team restricts.
+func actingAs(ctx context.Context, cfg *config.Config, email string) context.Context {
+	if email != "" {
+		return auth.WithUser(ctx, &auth.UserInfo{Email: email, Sub: "mcp:" + email})
+	}
+	if cfg.Auth != nil && cfg.Auth.Enabled {
+		return auth.WithAnonymous(ctx, auth.AccessOpen)
+	}
+	return ctx
+}
+
+// outageTransitions converts the configured state machine to its domain form
+func outageTransitions(cfg []config.OutageTransitionConfig) []domain.OutageTransition {
+	transitions := make([]domain.OutageTransition, len(cfg))
+	for i, t := range cfg {
+		transitions[i] = domain.OutageTransition{Action: t.Action, From: t.From, To: t.To, Explicit: t.Explicit}
+	}
+	return transitions
+}
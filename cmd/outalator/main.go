@@ -2,24 +2,64 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/conall/outalator/internal/api"
+	"github.com/conall/outalator/api/openapi"
 	"github.com/conall/outalator/config"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/ai"
+	"github.com/conall/outalator/internal/alertexpiry"
+	"github.com/conall/outalator/internal/alertsync"
+	"github.com/conall/outalator/internal/analytics"
+	"github.com/conall/outalator/internal/api"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/blobstore"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/cache"
+	"github.com/conall/outalator/internal/catalog"
+	"github.com/conall/outalator/internal/digest"
+	"github.com/conall/outalator/internal/email"
+	"github.com/conall/outalator/internal/events"
+	"github.com/conall/outalator/internal/graphql"
 	grpcserver "github.com/conall/outalator/internal/grpc"
-	"github.com/conall/outalator/notification/opsgenie"
-	"github.com/conall/outalator/notification/pagerduty"
-	"github.com/conall/outalator/service"
+	"github.com/conall/outalator/internal/httpclient"
+	"github.com/conall/outalator/internal/integrations/github"
+	"github.com/conall/outalator/internal/integrations/jira"
+	"github.com/conall/outalator/internal/integrations/statuspage"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/mailgw"
+	"github.com/conall/outalator/internal/metrics"
+	"github.com/conall/outalator/internal/providers"
+	"github.com/conall/outalator/internal/publicstatus"
+	"github.com/conall/outalator/internal/retention"
 	"github.com/conall/outalator/internal/slack"
+	"github.com/conall/outalator/internal/sourcehealth"
+	"github.com/conall/outalator/internal/teamsync"
+	"github.com/conall/outalator/internal/tlsconfig"
+	"github.com/conall/outalator/internal/tracing"
+	"github.com/conall/outalator/internal/trash"
+	"github.com/conall/outalator/internal/updatereminder"
+	"github.com/conall/outalator/internal/viewsummary"
+	"github.com/conall/outalator/internal/warehouse"
+	"github.com/conall/outalator/internal/webhook"
+	"github.com/conall/outalator/service"
 	"github.com/conall/outalator/storage"
+	"github.com/conall/outalator/web"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
@@ -37,6 +77,15 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	logger, err := logging.New(logging.Config{
+		Level:  cfg.Logging.Level,
+		Format: cfg.Logging.Format,
+	}, os.Stderr)
+	if err != nil {
+		log.Fatalf("Invalid logging config: %v", err)
+	}
+	slog.SetDefault(logger)
+
 	// Apply CLI flag overrides for Slack
 	if *slackEnabled {
 		if cfg.Slack == nil {
@@ -66,67 +115,613 @@ func main() {
 	// Initialize storage backend (postgres by default; sqlite with -tags sqlite)
 	db, err := storage.New(context.Background(), cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal(logger, "failed to connect to database", err)
 	}
 	defer func() { _ = db.Close() }()
 
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			ServiceName: cfg.Tracing.ServiceName,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			fatal(logger, "failed to set up tracing", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Error("tracing shutdown error", "error", err)
+			}
+		}()
+		db = tracing.InstrumentStorage(db, dbSystem(cfg.Database.Driver))
+		logger.Info("OpenTelemetry tracing enabled", "endpoint", cfg.Tracing.Endpoint)
+	}
+
+	if cfg.Metrics.Enabled {
+		db = metrics.InstrumentStorage(db)
+		if err := metrics.RegisterOpenOutages(db); err != nil {
+			fatal(logger, "failed to register outage metrics", err)
+		}
+	}
+
+	// Serve repeated outage reads from a cache, in front of the metrics and
+	// tracing wrappers so that they only see queries reaching the database
+	if cfg.Cache.Enabled {
+		var backend cache.Backend
+		switch cfg.Cache.Backend {
+		case "":
+			cfg.Cache.Backend = "memory"
+			fallthrough
+		case "memory":
+			backend = cache.NewLRU(cfg.Cache.Size)
+		case "redis":
+			if cfg.Cache.Redis.Addr == "" {
+				fatal(logger, "invalid cache config", fmt.Errorf("redis backend needs cache.redis.addr"))
+			}
+			backend = cache.NewRedis(cache.RedisConfig{
+				Addr:     cfg.Cache.Redis.Addr,
+				Password: cfg.Cache.Redis.Password,
+				DB:       cfg.Cache.Redis.DB,
+			})
+		default:
+			fatal(logger, "invalid cache config", fmt.Errorf("unknown backend %q", cfg.Cache.Backend))
+		}
+		db = cache.WrapStorage(db, backend, cfg.Cache.TTL, logger)
+		logger.Info("outage cache enabled", "backend", cfg.Cache.Backend)
+	}
+
 	// Initialize service
-	svc := service.New(db)
+	svc := service.New(db, logger)
+	if cfg.Metrics.Enabled {
+		if err := metrics.RegisterSourceHealth(svc); err != nil {
+			fatal(logger, "failed to register source health metrics", err)
+		}
+	}
+	if err := svc.SetCustomFieldSchemas(cfg.CustomFields); err != nil {
+		fatal(logger, "invalid custom field schemas", err)
+	}
+	if err := svc.SetSeverityMapping(cfg.SeverityMapping); err != nil {
+		fatal(logger, "invalid severity mapping", err)
+	}
+	if cfg.Auth != nil {
+		svc.SetAdmins(cfg.Auth.Admins)
+	}
+	if len(cfg.OutageTransitions) > 0 {
+		if err := svc.SetOutageTransitions(outageTransitions(cfg.OutageTransitions)); err != nil {
+			fatal(logger, "invalid outage transitions", err)
+		}
+	}
 
 	// Register notification services
-	if cfg.PagerDuty != nil && cfg.PagerDuty.APIKey != "" {
-		pdConfig := pagerduty.Config{
-			APIKey: cfg.PagerDuty.APIKey,
-			APIURL: cfg.PagerDuty.APIURL,
-		}
-		pdSvc := pagerduty.New(pdConfig)
-		svc.RegisterNotificationService(pdSvc)
-		log.Println("Registered PagerDuty notification service")
+	notificationServices, err := providers.New(cfg, providers.Options{
+		Transport: func(provider string) http.RoundTripper { return providerTransport(cfg, provider) },
+		Retry: func(provider string, retry config.RetryConfig) httpclient.RetryPolicy {
+			return providerRetry(cfg, retry, provider, logger)
+		},
+	})
+	if err != nil {
+		fatal(logger, "failed to configure notification services", err)
+	}
+	for _, ns := range notificationServices {
+		svc.RegisterNotificationService(ns)
+		logger.Info("registered notification service", "source", ns.Name())
+	}
+	if cfg.PagerDuty != nil && cfg.PagerDuty.APIKey != "" && cfg.PagerDuty.WebhookSecret == "" {
+		logger.Warn("pagerduty.webhook_secret is not set; pagerduty webhook deliveries will be rejected")
+	}
+	if cfg.OpsGenie != nil && cfg.OpsGenie.APIKey != "" && cfg.OpsGenie.WebhookToken == "" {
+		logger.Warn("opsgenie.webhook_token is not set; opsgenie webhook deliveries will be rejected")
 	}
 
-	if cfg.OpsGenie != nil && cfg.OpsGenie.APIKey != "" {
-		ogConfig := opsgenie.Config{
-			APIKey: cfg.OpsGenie.APIKey,
-			APIURL: cfg.OpsGenie.APIURL,
+	// Email alerts are parsed by the mail gateway, registered as a source
+	// before the webhook queue starts so spooled messages can be processed
+	var mailGateway *mailgw.Gateway
+	if cfg.MailGateway != nil && cfg.MailGateway.Enabled {
+		mailGateway, err = mailgw.New(mailgw.Config{
+			Source:       cfg.MailGateway.Source,
+			Rules:        cfg.MailGateway.Rules,
+			WebhookToken: cfg.MailGateway.WebhookToken,
+		})
+		if err != nil {
+			fatal(logger, "invalid mail gateway config", err)
 		}
-		ogSvc := opsgenie.New(ogConfig)
-		svc.RegisterNotificationService(ogSvc)
-		log.Println("Registered OpsGenie notification service")
+		svc.RegisterNotificationService(mailGateway)
+		logger.Info("registered notification service", "source", mailGateway.Name(), "rules", len(cfg.MailGateway.Rules))
 	}
 
 	// Set up HTTP router
 	router := mux.NewRouter()
+	router.Use(logging.Middleware(logger))
+	router.Use(bodylimit.Middleware(bodylimit.Limits{
+		Default:    cfg.Server.BodyLimits.Default,
+		Webhook:    cfg.Server.BodyLimits.Webhook,
+		Attachment: cfg.Server.BodyLimits.Attachment,
+		Import:     cfg.Server.BodyLimits.Import,
+	}))
+	if cfg.Tracing.Enabled {
+		router.Use(otelmux.Middleware("outalator"))
+	}
+
+	if cfg.Metrics.Enabled {
+		metricsPath := cfg.Metrics.Path
+		if metricsPath == "" {
+			metricsPath = "/metrics"
+		}
+		router.Use(metrics.Middleware)
+		router.Handle(metricsPath, metrics.Handler()).Methods("GET")
+		logger.Info("serving Prometheus metrics", "path", metricsPath)
+	}
+
+	// The API, web UI and integration actions require sign-in when OIDC is
+	// configured; inbound webhooks and Slack events registered on router
+	// stay open and verify their own signatures
+	var authenticator *auth.Authenticator
+	if cfg.Auth != nil && cfg.Auth.Enabled {
+		authenticator, err = auth.NewAuthenticator(auth.Config{
+			Issuer:         cfg.Auth.Issuer,
+			ClientID:       cfg.Auth.ClientID,
+			ClientSecret:   cfg.Auth.ClientSecret,
+			RedirectURL:    cfg.Auth.RedirectURL,
+			SessionKey:     cfg.Auth.SessionKey,
+			Logger:         logger,
+			Policy:         authPolicy(cfg.Auth),
+			Groups:         authGroups(cfg.Auth),
+			ServiceTokens:  serviceTokenIssuers(cfg.Auth),
+			BearerIDTokens: cfg.Auth.BearerIDTokens,
+		})
+		if err != nil {
+			fatal(logger, "failed to configure authentication", err)
+		}
+		router.HandleFunc("/auth/login", authenticator.LoginHandler()).Methods("GET")
+		router.HandleFunc("/auth/callback", authenticator.CallbackHandler()).Methods("GET")
+		router.HandleFunc("/auth/logout", authenticator.LogoutHandler()).Methods("GET")
+		logger.Info("oidc authentication enabled", "issuer", cfg.Auth.Issuer, "service_token_issuers", len(cfg.Auth.ServiceTokens))
+	}
+	protected := protectedRouter(router, authenticator)
+
+	// Requests to documented API routes must match the OpenAPI spec the
+	// clients are generated from. Validation runs after authentication, so
+	// signed-out requests still get 401.
+	spec, err := openapi.Load()
+	if err != nil {
+		fatal(logger, "failed to load OpenAPI spec", err)
+	}
+	protected.Use(spec.Middleware)
+	if cfg.Server.ValidateResponses {
+		protected.Use(spec.ResponseChecker(func(r *http.Request, status int, err error) {
+			logger.Warn("response does not match OpenAPI spec", "method", r.Method, "path", r.URL.Path, "status", status, "error", err)
+		}))
+		logger.Info("validating API responses against the OpenAPI spec")
+	}
+
+	// Register API handlers. The event broker feeds live dashboards through
+	// the server-sent event stream.
+	eventBroker := events.NewBroker(0, logger)
+	svc.RegisterOutageListener(eventBroker)
+	apiHandler := api.NewHandler(svc, eventBroker, logger)
+	apiHandler.RegisterRoutes(protected)
+
+	// GraphQL API for clients that fetch outages with nested alerts, notes
+	// and tags in one request
+	graphqlHandler := graphql.NewHandler(svc, logger)
+	graphqlHandler.RegisterHandlers(protected)
+
+	// The gRPC services over HTTP/JSON, for clients that want the proto API
+	// without a gRPC client
+	var gateway *grpcserver.Gateway
+	if cfg.GRPC.Gateway {
+		// The HTTP router has already authenticated the caller, so the
+		// in-process server needs no credentials
+		gateway, err = grpcserver.NewServer(svc, grpcServerOptions(cfg, logger, nil)...).Gateway()
+		if err != nil {
+			fatal(logger, "failed to start gRPC gateway", err)
+		}
+		protected.PathPrefix(grpcserver.GatewayPrefix).Handler(gateway)
+		logger.Info("serving gRPC gateway", "prefix", grpcserver.GatewayPrefix)
+	}
+
+	// Serve the embedded web UI
+	ui := web.Handler()
+	protected.Handle("/", ui).Methods("GET")
+	protected.PathPrefix("/static/").Handler(ui).Methods("GET")
+
+	// Inbound webhooks are acknowledged immediately and processed on a
+	// bounded worker pool so alert storms cannot overwhelm the database.
+	webhookQueue := webhook.NewQueue(webhook.Config{
+		Workers:   cfg.Webhooks.Workers,
+		QueueSize: cfg.Webhooks.QueueSize,
+		SpoolDir:  cfg.Webhooks.SpoolDir,
+	}, func(ctx context.Context, job webhook.Job) error {
+		return svc.ProcessWebhook(ctx, job.Source, job.Payload, job.ReceivedAt)
+	}, logger)
+	if err := webhookQueue.Start(context.Background()); err != nil {
+		fatal(logger, "failed to start webhook queue", err)
+	}
+	webhook.NewReceiver(webhookQueue, svc, logger).RegisterHandlers(router)
+
+	// Open public outages are served without sign-in for status pages
+	if cfg.PublicStatus.Enabled {
+		publicstatus.NewHandler(svc, publicstatus.Config{
+			RequestsPerMinute: cfg.PublicStatus.RequestsPerMinute,
+			TrustProxy:        cfg.PublicStatus.TrustProxy,
+			CacheTTL:          cfg.PublicStatus.CacheTTL,
+		}, logger).RegisterHandlers(router)
+		logger.Info("serving public status", "path", publicstatus.Path)
+	}
 
-	// Register API handlers
-	apiHandler := api.NewHandler(svc)
-	apiHandler.RegisterRoutes(router)
+	// Background jobs such as review reminders and alert sync stop when this is cancelled
+	reminderCtx, stopReminders := context.WithCancel(context.Background())
+	defer stopReminders()
+
+	// Accept mail over SMTP and from Mailgun routes
+	if mailGateway != nil {
+		if cfg.MailGateway.MailgunSigningKey != "" {
+			mailgw.NewMailgunReceiver(mailGateway.Name(), cfg.MailGateway.MailgunSigningKey, webhookQueue, logger).RegisterHandlers(router)
+		}
+		if cfg.MailGateway.SMTPAddr != "" {
+			smtpServer := mailgw.NewSMTPServer(mailGateway.Name(), mailgw.SMTPConfig{
+				Addr:            cfg.MailGateway.SMTPAddr,
+				MaxMessageBytes: cfg.MailGateway.MaxMessageBytes,
+			}, webhookQueue, logger)
+			go func() {
+				if err := smtpServer.Run(reminderCtx); err != nil {
+					fatal(logger, "failed to start smtp listener", err)
+				}
+			}()
+			logger.Info("listening for alert email over smtp", "addr", cfg.MailGateway.SMTPAddr)
+		}
+	}
+
+	// Register GitHub integration if enabled
+	var githubIntegration *github.Integration
+	if cfg.GitHub != nil && cfg.GitHub.Enabled {
+		if cfg.GitHub.Token == "" || cfg.GitHub.Repository == "" {
+			fatal(logger, "github is enabled but token or repository is missing", nil)
+		}
+
+		githubIntegration = github.New(svc, github.Config{
+			Token:      cfg.GitHub.Token,
+			Repository: cfg.GitHub.Repository,
+			APIURL:     cfg.GitHub.APIURL,
+			Labels:     cfg.GitHub.Labels,
+			OutageURL:  cfg.GitHub.OutageURL,
+			Transport:  providerTransport(cfg, "github"),
+		}, logger)
+		githubIntegration.RegisterHandlers(protected)
+		logger.Info("github integration enabled", "repository", cfg.GitHub.Repository)
+	}
 
 	// Register Slack bot if enabled
+	var slackBot *slack.Bot
 	if cfg.Slack != nil && cfg.Slack.Enabled {
 		if cfg.Slack.BotToken == "" || cfg.Slack.SigningSecret == "" {
-			log.Fatal("Slack bot is enabled but bot_token or signing_secret is missing")
+			fatal(logger, "slack bot is enabled but bot_token or signing_secret is missing", nil)
 		}
 
 		slackConfig := slack.Config{
 			BotToken:      cfg.Slack.BotToken,
 			SigningSecret: cfg.Slack.SigningSecret,
 			ReactionEmoji: cfg.Slack.ReactionEmoji,
+
+			ArchiveChannelMessages: cfg.Slack.ArchiveChannelMessages,
+			UpdateReminderChannel:  cfg.UpdateSLA.ReminderChannel,
+			SourceAlarmChannel:     cfg.SourceHealth.AlarmChannel,
+			EventWorkers:           cfg.Slack.EventWorkers,
+			EventQueueSize:         cfg.Slack.EventQueueSize,
+			EventTTL:               cfg.Slack.EventTTL,
+		}
+		if githubIntegration != nil {
+			slackConfig.Issues = githubIntegration
 		}
 
 		if slackConfig.ReactionEmoji == "" {
 			slackConfig.ReactionEmoji = "outage_note" // Default emoji
 		}
 
-		slackBot := slack.NewBot(svc, slackConfig)
+		slackBot = slack.NewBot(svc, slackConfig, logger)
+		if err := slackBot.Start(context.Background()); err != nil {
+			fatal(logger, "failed to start slack event workers", err)
+		}
 		slackBot.RegisterHandlers(router)
-		log.Printf("Slack bot enabled with reaction emoji: %s", slackConfig.ReactionEmoji)
+		svc.RegisterMentionNotifier(slackBot)
+		svc.RegisterWatchNotifier(domain.WatchChannelSlack, slackBot)
+		svc.RegisterOutageListener(slackBot)
+		svc.RegisterUpdateReminderNotifier(slackBot)
+		svc.RegisterSourceStaleNotifier(slackBot)
+		svc.RegisterDigestNotifier(slackBot)
+		svc.RegisterViewSummaryNotifier(slackBot)
+		logger.Info("slack bot enabled", "reaction_emoji", slackConfig.ReactionEmoji,
+			"archive_channel_messages", slackConfig.ArchiveChannelMessages)
+
+		if cfg.Reviews.ReminderChannel != "" {
+			go slackBot.RunReviewReminders(reminderCtx, cfg.Reviews.ReminderChannel,
+				cfg.Reviews.ReminderInterval, cfg.Reviews.ReminderAfter)
+			logger.Info("posting overdue review reminders", "channel", cfg.Reviews.ReminderChannel)
+		}
+
+		// Summarise saved views in the channels subscribed to them
+		go viewsummary.NewScheduler(svc, 0, logger).Run(reminderCtx)
+	}
+
+	if cfg.Reviews.ReminderChannel != "" && (cfg.Slack == nil || !cfg.Slack.Enabled) {
+		logger.Warn("reviews.reminder_channel is set but the Slack bot is disabled; review reminders will not be posted")
+	}
+
+	// Register Statuspage integration if enabled
+	if cfg.Statuspage != nil && cfg.Statuspage.Enabled {
+		if cfg.Statuspage.APIKey == "" || cfg.Statuspage.PageID == "" {
+			fatal(logger, "statuspage is enabled but api_key or page_id is missing", nil)
+		}
+
+		statuspageIntegration := statuspage.New(svc, statuspage.Config{
+			APIKey:       cfg.Statuspage.APIKey,
+			PageID:       cfg.Statuspage.PageID,
+			APIURL:       cfg.Statuspage.APIURL,
+			ComponentTag: cfg.Statuspage.ComponentTag,
+			Components:   cfg.Statuspage.Components,
+			Transport:    providerTransport(cfg, "statuspage"),
+		}, logger)
+		statuspageIntegration.RegisterHandlers(protected)
+		svc.RegisterOutageListener(statuspageIntegration)
+		logger.Info("statuspage integration enabled", "page_id", cfg.Statuspage.PageID)
+	}
+
+	// Email people and teams @mentioned in notes if enabled
+	if cfg.Email != nil && cfg.Email.Enabled {
+		if cfg.Email.Host == "" || cfg.Email.From == "" {
+			fatal(logger, "email is enabled but host or from is missing", nil)
+		}
+
+		emailNotifier := email.NewNotifier(email.Config{
+			Host:      cfg.Email.Host,
+			Port:      cfg.Email.Port,
+			Username:  cfg.Email.Username,
+			Password:  cfg.Email.Password,
+			From:      cfg.Email.From,
+			OutageURL: cfg.Email.OutageURL,
+		})
+		svc.RegisterMentionNotifier(emailNotifier)
+		svc.RegisterWatchNotifier(domain.WatchChannelEmail, emailNotifier)
+		svc.RegisterUpdateReminderNotifier(emailNotifier)
+		svc.RegisterDigestNotifier(emailNotifier)
+		logger.Info("email notifications enabled", "smtp_host", cfg.Email.Host)
+	}
+
+	// Register Jira integration if enabled
+	if cfg.Jira != nil && cfg.Jira.Enabled {
+		if cfg.Jira.URL == "" || cfg.Jira.APIToken == "" || cfg.Jira.ProjectKey == "" {
+			fatal(logger, "jira is enabled but url, api_token or project_key is missing", nil)
+		}
+
+		jiraIntegration := jira.New(svc, jira.Config{
+			URL:        cfg.Jira.URL,
+			Email:      cfg.Jira.Email,
+			APIToken:   cfg.Jira.APIToken,
+			ProjectKey: cfg.Jira.ProjectKey,
+			IssueType:  cfg.Jira.IssueType,
+			Priorities: cfg.Jira.Priorities,
+			Labels:     cfg.Jira.Labels,
+			OutageURL:  cfg.Jira.OutageURL,
+			Transport:  providerTransport(cfg, "jira"),
+		}, logger)
+		jiraIntegration.RegisterHandlers(protected)
+		go jiraIntegration.RunStatusSync(reminderCtx, cfg.Jira.StatusSyncInterval)
+		logger.Info("jira integration enabled", "project", cfg.Jira.ProjectKey)
+	}
+
+	// Poll notification services for alerts that webhooks missed
+	if cfg.AlertSync.Enabled {
+		poller := alertsync.NewPoller(svc, alertsync.Config{
+			Interval:        cfg.AlertSync.Interval,
+			Lookback:        cfg.AlertSync.Lookback,
+			InitialLookback: cfg.AlertSync.InitialLookback,
+		}, logger)
+		go poller.Run(reminderCtx)
+		logger.Info("alert sync enabled", "sources", svc.NotificationSources())
+	}
+
+	// Resolve alerts the source never closed, and outages whose alerts are
+	// all resolved
+	if cfg.AlertResolution.Enabled {
+		svc.SetAlertResolutionPolicy(domain.AlertResolutionPolicy{
+			MaxOpen:        cfg.AlertResolution.MaxOpen,
+			ResolveOutages: cfg.AlertResolution.ResolveOutages,
+		})
+		if cfg.AlertResolution.MaxOpen > 0 {
+			go alertexpiry.NewSweeper(svc, cfg.AlertResolution.Interval, logger).Run(reminderCtx)
+		}
+		logger.Info("alert resolution policy enabled",
+			"max_open", cfg.AlertResolution.MaxOpen, "resolve_outages", cfg.AlertResolution.ResolveOutages)
+	}
+
+	// Point responders of new outages at similar past ones
+	if cfg.SimilarOutages.Note {
+		svc.SetSimilarOutagesPolicy(domain.SimilarOutagesPolicy{
+			MinScore: cfg.SimilarOutages.MinScore,
+			Limit:    cfg.SimilarOutages.Limit,
+		})
+		logger.Info("similar outage notes enabled", "min_score", cfg.SimilarOutages.MinScore)
+	}
+
+	// Write executive summaries of outages on request with a language model
+	if cfg.AI.Enabled {
+		if cfg.AI.Model == "" {
+			fatal(logger, "ai is enabled but model is missing", nil)
+		}
+		client := ai.NewOpenAI(cfg.AI.BaseURL, cfg.AI.APIKey, cfg.AI.Model, cfg.AI.Timeout, providerTransport(cfg, "ai"))
+		svc.SetOutageSummarizer(ai.NewSummarizer(client))
+		logger.Info("outage summaries enabled", "model", cfg.AI.Model)
+	}
+
+	// Track status update deadlines on active outages and remind their
+	// teams when one is missed
+	if cfg.UpdateSLA.Enabled {
+		if err := svc.SetUpdatePolicy(domain.UpdatePolicy(cfg.UpdateSLA.Intervals)); err != nil {
+			fatal(logger, "invalid update_sla intervals", err)
+		}
+		go updatereminder.NewScheduler(svc, cfg.UpdateSLA.CheckInterval, logger).Run(reminderCtx)
+		logger.Info("update SLA enabled", "intervals", cfg.UpdateSLA.Intervals)
+	}
+
+	// Send scheduled outage digests by email and Slack
+	if cfg.Digests.Enabled {
+		schedules := make([]domain.DigestSchedule, len(cfg.Digests.Schedules))
+		for i, d := range cfg.Digests.Schedules {
+			schedules[i] = domain.DigestSchedule{
+				Name:         d.Name,
+				Team:         d.Team,
+				Period:       d.Period,
+				Weekday:      d.Weekday,
+				Hour:         d.Hour,
+				Timezone:     d.Timezone,
+				Emails:       d.Emails,
+				SlackChannel: d.SlackChannel,
+				Template:     d.Template,
+			}
+		}
+		if err := svc.SetDigestSchedules(schedules); err != nil {
+			fatal(logger, "invalid digest schedules", err)
+		}
+		go digest.NewScheduler(svc, cfg.Digests.CheckInterval, logger).Run(reminderCtx)
+		logger.Info("outage digests enabled", "schedules", len(schedules))
+	}
+
+	// Alarm when an alert source stops delivering, e.g. after a webhook
+	// subscription is silently deleted
+	if cfg.SourceHealth.Enabled {
+		if err := svc.SetStalenessPolicy(domain.StalenessPolicy{
+			Default: cfg.SourceHealth.DefaultMaxSilence,
+			Sources: cfg.SourceHealth.MaxSilence,
+		}); err != nil {
+			fatal(logger, "invalid source_health thresholds", err)
+		}
+		go sourcehealth.NewChecker(svc, cfg.SourceHealth.CheckInterval, logger).Run(reminderCtx)
+		logger.Info("source health alarms enabled",
+			"default_max_silence", cfg.SourceHealth.DefaultMaxSilence, "max_silence", cfg.SourceHealth.MaxSilence)
+		if cfg.SourceHealth.AlarmChannel == "" || cfg.Slack == nil || !cfg.Slack.Enabled {
+			logger.Warn("source_health needs alarm_channel and the Slack bot to post alarms; stale sources will only be logged")
+		}
+	}
+
+	// Keep deleted outages and notes restorable for the retention period,
+	// then purge them for good
+	if cfg.Trash.Retention > 0 {
+		svc.SetTrashRetention(cfg.Trash.Retention)
+		go trash.NewPurger(svc, cfg.Trash.PurgeInterval, logger).Run(reminderCtx)
+		logger.Info("trash purge enabled", "retention", cfg.Trash.Retention)
+	}
+
+	// Copy PagerDuty and OpsGenie teams and their members
+	if cfg.TeamSync.Enabled {
+		go teamsync.NewSyncer(svc, cfg.TeamSync.Interval, logger).Run(reminderCtx)
+		logger.Info("team sync enabled", "interval", cfg.TeamSync.Interval)
+	}
+
+	// Sync the service catalogue from a YAML file or Backstage
+	if catalogCfg := cfg.ServiceCatalog; catalogCfg.File != "" || catalogCfg.Backstage.URL != "" {
+		if catalogCfg.File != "" {
+			svc.RegisterServiceCatalogue(catalog.NewFile(catalogCfg.File))
+		}
+		if catalogCfg.Backstage.URL != "" {
+			svc.RegisterServiceCatalogue(catalog.NewBackstage(catalogCfg.Backstage.URL, catalogCfg.Backstage.Token, providerTransport(cfg, "backstage")))
+		}
+		go catalog.NewSyncer(svc, catalogCfg.Interval, logger).Run(reminderCtx)
+		logger.Info("service catalogue sync enabled", "file", catalogCfg.File, "backstage", catalogCfg.Backstage.URL, "interval", catalogCfg.Interval)
+	}
+
+	// Store graphs, log snippets and screenshots uploaded to outages
+	if cfg.Attachments.Backend != "" {
+		blobs, err := newBlobStore(cfg, cfg.Attachments.Backend, cfg.Attachments.Dir, cfg.Attachments.S3)
+		if err != nil {
+			fatal(logger, "invalid attachments config", err)
+		}
+		svc.SetAttachmentStore(blobs, domain.AttachmentPolicy{
+			MaxBytes:     cfg.Attachments.MaxBytes,
+			AllowedTypes: cfg.Attachments.AllowedTypes,
+		})
+		logger.Info("attachments enabled", "backend", cfg.Attachments.Backend)
+	}
+
+	// Export changed outages, alerts and notes to a bucket for the data
+	// warehouse
+	if cfg.AnalyticsExport.Enabled {
+		blobs, err := newBlobStore(cfg, cfg.AnalyticsExport.Backend, cfg.AnalyticsExport.Dir, cfg.AnalyticsExport.S3)
+		if err == nil {
+			err = svc.SetAnalyticsExport(blobs, domain.AnalyticsExportConfig{
+				Format: cfg.AnalyticsExport.Format,
+				Tables: cfg.AnalyticsExport.Tables,
+			})
+		}
+		if err != nil {
+			fatal(logger, "invalid analytics_export config", err)
+		}
+		go analytics.NewScheduler(svc, cfg.AnalyticsExport.Interval, logger).Run(reminderCtx)
+		logger.Info("analytics export enabled", "backend", cfg.AnalyticsExport.Backend, "interval", cfg.AnalyticsExport.Interval)
+	}
+
+	// Archive long-resolved outages to keep the hot tables small
+	if cfg.Retention.Enabled {
+		archives, err := newBlobStore(cfg, cfg.Retention.Backend, cfg.Retention.Dir, cfg.Retention.S3)
+		if err == nil {
+			err = svc.SetRetentionPolicy(archives, domain.RetentionPolicy{
+				ArchiveAfter: cfg.Retention.ArchiveAfter,
+				PurgeAfter:   cfg.Retention.PurgeAfter,
+			})
+		}
+		if err != nil {
+			fatal(logger, "invalid retention config", err)
+		}
+		go retention.NewScheduler(svc, cfg.Retention.Interval, logger).Run(reminderCtx)
+		logger.Info("retention enabled", "archive_after", cfg.Retention.ArchiveAfter,
+			"purge_after", cfg.Retention.PurgeAfter, "backend", cfg.Retention.Backend)
+	}
+
+	// Stream outage lifecycle events into the warehouse for trend dashboards
+	if cfg.Warehouse.Enabled {
+		var writer warehouse.Writer
+		switch cfg.Warehouse.Driver {
+		case "bigquery":
+			writer, err = warehouse.NewBigQuery(warehouse.BigQueryConfig{
+				Project:         cfg.Warehouse.BigQuery.Project,
+				Dataset:         cfg.Warehouse.BigQuery.Dataset,
+				Table:           cfg.Warehouse.BigQuery.Table,
+				CredentialsFile: cfg.Warehouse.BigQuery.CredentialsFile,
+				Transport:       providerTransport(cfg, "bigquery"),
+			})
+		case "clickhouse":
+			writer, err = warehouse.NewClickHouse(warehouse.ClickHouseConfig{
+				URL:       cfg.Warehouse.ClickHouse.URL,
+				Database:  cfg.Warehouse.ClickHouse.Database,
+				Table:     cfg.Warehouse.ClickHouse.Table,
+				Username:  cfg.Warehouse.ClickHouse.Username,
+				Password:  cfg.Warehouse.ClickHouse.Password,
+				Transport: providerTransport(cfg, "clickhouse"),
+			})
+		default:
+			err = fmt.Errorf("unknown driver %q", cfg.Warehouse.Driver)
+		}
+		if err != nil {
+			fatal(logger, "invalid warehouse config", err)
+		}
+		sink := warehouse.NewSink(writer, cfg.Warehouse.FlushInterval, cfg.Warehouse.BatchSize, logger)
+		svc.RegisterOutageListener(sink)
+		go sink.Run(reminderCtx)
+		logger.Info("warehouse sink enabled", "driver", cfg.Warehouse.Driver)
 	}
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
+	httpTLS, err := tlsconfig.Server(tlsFiles(cfg.Server.TLS))
+	if err != nil {
+		fatal(logger, "invalid server TLS config", err)
+	}
 	httpServer := &http.Server{
 		Addr:         addr,
 		Handler:      router,
+		TLSConfig:    httpTLS,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -135,22 +730,41 @@ func main() {
 	// Start gRPC server if enabled
 	var grpcSrv *grpcserver.Server
 	if cfg.GRPC.Enabled {
-		grpcSrv = grpcserver.NewServer(svc)
+		creds, err := grpcCredentials(cfg.GRPC.Auth, authPolicy(cfg.Auth), authenticator)
+		if err != nil {
+			fatal(logger, "invalid gRPC auth config", err)
+		}
+		grpcOpts := grpcServerOptions(cfg, logger, creds)
+		grpcTLS, err := tlsconfig.Server(tlsFiles(cfg.GRPC.TLS))
+		if err != nil {
+			fatal(logger, "invalid gRPC TLS config", err)
+		}
+		if grpcTLS != nil {
+			grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(grpcTLS)))
+		}
+		grpcSrv = grpcserver.NewServer(svc, grpcOpts...)
 		grpcAddr := fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port)
 
 		go func() {
-			log.Printf("Starting gRPC server on %s", grpcAddr)
+			logger.Info("starting gRPC server", "addr", grpcAddr, "tls", grpcTLS != nil, "mtls", cfg.GRPC.TLS.ClientCAFile != "", "auth", creds != nil)
 			if err := grpcSrv.Start(grpcAddr); err != nil {
-				log.Fatalf("Failed to start gRPC server: %v", err)
+				fatal(logger, "failed to start gRPC server", err)
 			}
 		}()
 	}
 
 	// Start HTTP server
 	go func() {
-		log.Printf("Starting HTTP server on %s", addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start HTTP server: %v", err)
+		logger.Info("starting HTTP server", "addr", addr, "tls", httpTLS != nil, "mtls", cfg.Server.TLS.ClientCAFile != "")
+		var err error
+		if httpTLS != nil {
+			// The certificate is already loaded into TLSConfig
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			fatal(logger, "failed to start HTTP server", err)
 		}
 	}()
 
@@ -158,19 +772,214 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down servers...")
+	logger.Info("shutting down servers")
 
 	// Shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Printf("HTTP server shutdown error: %v", err)
+		logger.Error("HTTP server shutdown error", "error", err)
 	}
 
 	if grpcSrv != nil {
 		grpcSrv.Stop()
 	}
+	if gateway != nil {
+		gateway.Close()
+	}
+
+	// Finish events and deliveries accepted before the servers stopped
+	if slackBot != nil {
+		slackBot.Stop(ctx)
+	}
+	webhookQueue.Stop(ctx)
+
+	logger.Info("servers stopped")
+}
+
+// fatal logs msg, with err when non-nil, and exits
+func fatal(logger *slog.Logger, msg string, err error) {
+	if err != nil {
+		logger.Error(msg, "error", err)
+	} else {
+		logger.Error(msg)
+	}
+	os.Exit(1)
+}
+
+// tlsFiles returns the certificate files of a listener's TLS config
+func tlsFiles(cfg config.TLSConfig) tlsconfig.Files {
+	return tlsconfig.Files{
+		CertFile:     cfg.CertFile,
+		KeyFile:      cfg.KeyFile,
+		ClientCAFile: cfg.ClientCAFile,
+	}
+}
+
+// grpcServerOptions returns the interceptors and stats handler of the gRPC
+// server and the gateway's in-process server. Calls must carry creds when
+// it is set.
+func grpcServerOptions(cfg *config.Config, logger *slog.Logger, creds *grpcserver.Credentials) []grpc.ServerOption {
+	opts := grpcserver.Interceptors{
+		Logger:         logger,
+		Metrics:        cfg.Metrics.Enabled,
+		Credentials:    creds,
+		SkipRecovery:   cfg.GRPC.DisableRecovery,
+		SkipValidation: cfg.GRPC.DisableValidation,
+	}.Options()
+	if cfg.Tracing.Enabled {
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+	return opts
+}
+
+// grpcCredentials returns the credentials the gRPC port accepts, or nil when
+// gRPC auth is disabled. ID tokens and service JWTs are verified by
+// authenticator, which is nil unless OIDC sign-in is configured, and calls
+// without credentials are allowed as policy allows them over HTTP.
+func grpcCredentials(cfg config.GRPCAuthConfig, policy auth.Policy, authenticator *auth.Authenticator) (*grpcserver.Credentials, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	creds := &grpcserver.Credentials{Policy: policy}
+	for _, key := range cfg.APIKeys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("gRPC API key %q is empty", key.Name)
+		}
+		creds.APIKeys = append(creds.APIKeys, grpcserver.APIKey{Name: key.Name, Key: key.Key})
+	}
+	if cfg.OIDC {
+		if authenticator == nil {
+			return nil, errors.New("gRPC OIDC auth needs auth to be enabled")
+		}
+		creds.Tokens = authenticator
+	}
+	if len(creds.APIKeys) == 0 && creds.Tokens == nil {
+		return nil, errors.New("gRPC auth needs API keys or OIDC")
+	}
+	return creds, nil
+}
+
+// authPolicy returns the route group access policy of the auth config. It
+// requires sign-in everywhere when cfg is nil.
+func authPolicy(cfg *config.AuthConfig) auth.Policy {
+	if cfg == nil {
+		return auth.Policy{}
+	}
+	policy := auth.Policy{Groups: cfg.Routes}
+	if cfg.AnonymousRead {
+		policy.Default = auth.AccessAnonymousRead
+	}
+	return policy
+}
+
+// authGroups returns the mapping of the auth config's IdP groups to roles
+// and teams
+func authGroups(cfg *config.AuthConfig) auth.GroupMap {
+	groups := auth.GroupMap{Claim: cfg.GroupsClaim}
+	if len(cfg.Groups) > 0 {
+		groups.Groups = make(map[string]auth.GroupMapping, len(cfg.Groups))
+		for group, mapping := range cfg.Groups {
+			groups.Groups[group] = auth.GroupMapping{Roles: mapping.Roles, Teams: mapping.Teams}
+		}
+	}
+	return groups
+}
+
+// serviceTokenIssuers returns the issuers of the auth config's service
+// bearer JWTs
+func serviceTokenIssuers(cfg *config.AuthConfig) []auth.ServiceTokenIssuer {
+	var issuers []auth.ServiceTokenIssuer
+	for _, issuer := range cfg.ServiceTokens {
+		issuers = append(issuers, auth.ServiceTokenIssuer{
+			Name:     issuer.Name,
+			Issuer:   issuer.Issuer,
+			Audience: issuer.Audience,
+			JWKSURL:  issuer.JWKSURL,
+		})
+	}
+	return issuers
+}
+
+// protectedRouter returns the subrouter of router for the API, web UI and
+// integration actions, which requires a session when authenticator is set
+func protectedRouter(router *mux.Router, authenticator *auth.Authenticator) *mux.Router {
+	protected := router.NewRoute().Subrouter()
+	if authenticator != nil {
+		protected.Use(authenticator.Middleware)
+	}
+	return protected
+}
+
+// providerTransport builds the HTTP transport for a notification provider's
+// API client, layering in metrics and tracing when they are enabled
+// newBlobStore opens the local directory or S3-compatible bucket a backend
+// setting names
+func newBlobStore(cfg *config.Config, backend, dir string, s3 config.S3AttachmentConfig) (service.BlobStore, error) {
+	switch backend {
+	case "local":
+		return blobstore.NewLocal(dir)
+	case "s3":
+		return blobstore.NewS3(blobstore.S3Config{
+			Endpoint:        s3.Endpoint,
+			Region:          s3.Region,
+			Bucket:          s3.Bucket,
+			Prefix:          s3.Prefix,
+			AccessKeyID:     s3.AccessKeyID,
+			SecretAccessKey: s3.SecretAccessKey,
+			PathStyle:       s3.PathStyle,
+			Transport:       providerTransport(cfg, "s3"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown backend %q", backend)
+	}
+}
 
-	log.Println("Servers stopped")
+func providerTransport(cfg *config.Config, provider string) http.RoundTripper {
+	var rt http.RoundTripper = http.DefaultTransport
+	if cfg.Metrics.Enabled {
+		rt = metrics.Transport(provider, rt)
+	}
+	if cfg.Tracing.Enabled {
+		rt = otelhttp.NewTransport(rt)
+	}
+	return rt
+}
+
+// providerRetry builds a notification provider's API retry policy from its
+// configuration. Retries are logged and, with metrics enabled, counted.
+func providerRetry(cfg *config.Config, retry config.RetryConfig, provider string, logger *slog.Logger) httpclient.RetryPolicy {
+	return httpclient.RetryPolicy{
+		MaxRetries: retry.MaxRetries,
+		MinBackoff: retry.MinBackoff,
+		MaxBackoff: retry.MaxBackoff,
+		OnRetry: func(req *http.Request, reason string, wait time.Duration) {
+			logger.WarnContext(req.Context(), "retrying provider api request", "source", provider, "method", req.Method, "path", req.URL.Path, "reason", reason, "wait", wait)
+			if cfg.Metrics.Enabled {
+				metrics.CountRetry(provider, reason)
+			}
+		},
+	}
+}
+
+// dbSystem maps the configured database driver to the OpenTelemetry
+// db.system attribute value
+func dbSystem(driver string) string {
+	if driver == "sqlite" {
+		return "sqlite"
+	}
+	return "postgresql"
+}
+
+// outageTransitions converts the configured state machine to its domain form
+func outageTransitions(cfg []config.OutageTransitionConfig) []domain.OutageTransition {
+	transitions := make([]domain.OutageTransition, len(cfg))
+	for i, t := range cfg {
+		transitions[i] = domain.OutageTransition{Action: t.Action, From: t.From, To: t.To, Explicit: t.Explicit}
+	}
+	return transitions
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/config"
+	"github.com/conall/outalator/internal/api"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/events"
+	"github.com/conall/outalator/internal/graphql"
+	"github.com/conall/outalator/internal/integrations/github"
+	"github.com/conall/outalator/internal/integrations/jira"
+	"github.com/conall/outalator/internal/integrations/statuspage"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/gorilla/mux"
+)
+
+func TestIntegrationActionsRequireSession(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	router := mux.NewRouter()
+	protected := protectedRouter(router, testutil.NewAuthenticator(t))
+	github.New(svc, github.Config{Token: "token", Repository: "acme/ops"}, logging.Discard()).RegisterHandlers(protected)
+	jira.New(svc, jira.Config{URL: "https://jira.invalid", APIToken: "token", ProjectKey: "OPS"}, logging.Discard()).RegisterHandlers(protected)
+	statuspage.New(svc, statuspage.Config{APIKey: "key", PageID: "page"}, logging.Discard()).RegisterHandlers(protected)
+
+	id := "00000000-0000-0000-0000-000000000001"
+	for _, path := range []string{
+		"/api/v1/notes/" + id + "/github-issue",
+		"/api/v1/outages/" + id + "/jira",
+		"/api/v1/outages/" + id + "/statuspage",
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, path, nil))
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("POST %s without a session = %d, want 401", path, rr.Code)
+		}
+	}
+}
+
+func TestGraphQLRequiresSession(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	router := mux.NewRouter()
+	graphql.NewHandler(svc, logging.Discard()).RegisterHandlers(protectedRouter(router, testutil.NewAuthenticator(t)))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ outages { id } }"}`)))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("POST /graphql without a session = %d, want 401", rr.Code)
+	}
+}
+
+func TestAnonymousReadPolicy(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	router := mux.NewRouter()
+	protected := protectedRouter(router, testutil.NewAuthenticatorWithPolicy(t, authPolicy(&config.AuthConfig{
+		AnonymousRead: true,
+		Routes:        map[string]string{auth.GroupEvents: auth.AccessRequired},
+	})))
+	api.NewHandler(svc, events.NewBroker(0, logging.Discard()), logging.Discard()).RegisterRoutes(protected)
+	graphql.NewHandler(svc, logging.Discard()).RegisterHandlers(protected)
+
+	tests := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodGet, "/api/v1/outages", "", http.StatusOK},
+		{http.MethodPost, "/api/v1/outages", `{"title":"Checkout errors","severity":"high"}`, http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/outages?include_deleted=true", "", http.StatusForbidden},
+		{http.MethodGet, "/api/v1/events/stream", "", http.StatusUnauthorized},
+		{http.MethodPost, "/graphql", `{"query":"{ outages { id } }"}`, http.StatusOK},
+		{http.MethodPost, "/graphql", `{"query":"mutation { createOutage(input: {title: \"x\", severity: \"high\"}) { id } }"}`, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if rr.Code != tt.want {
+			t.Errorf("%s %s without a session = %d, want %d: %s", tt.method, tt.path, rr.Code, tt.want, rr.Body)
+		}
+	}
+}
+
+func TestProtectedRouterWithoutAuth(t *testing.T) {
+	router := mux.NewRouter()
+	protectedRouter(router, nil).HandleFunc("/api/v1/outages", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/outages", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("GET without auth configured = %d, want 204", rr.Code)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/domain"
+	grpcserver "github.com/conall/outalator/internal/grpc"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// outageClient is the subset of the API used by the outage, note, tag and
+// search commands. It is implemented over both REST and gRPC.
+type outageClient interface {
+	ListOutages(ctx context.Context, limit int) ([]*domain.Outage, error)
+	GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error)
+	CreateOutage(ctx context.Context, req domain.CreateOutageRequest) (*domain.Outage, error)
+	UpdateOutage(ctx context.Context, id uuid.UUID, req domain.UpdateOutageRequest) (*domain.Outage, error)
+	AddNote(ctx context.Context, outageID uuid.UUID, req domain.AddNoteRequest) (*domain.Note, error)
+	AddTag(ctx context.Context, outageID uuid.UUID, key, value string) (*domain.Tag, error)
+	SearchByTag(ctx context.Context, key, value string) ([]*domain.Outage, error)
+}
+
+// REST implementation
+
+func (c *apiClient) ListOutages(ctx context.Context, limit int) ([]*domain.Outage, error) {
+	var resp struct {
+		Outages []*domain.Outage `json:"outages"`
+	}
+	if err := c.doContext(ctx, http.MethodGet, "/api/v1/outages?limit="+strconv.Itoa(limit), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Outages, nil
+}
+
+func (c *apiClient) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
+	var outage domain.Outage
+	if err := c.doContext(ctx, http.MethodGet, "/api/v1/outages/"+id.String(), nil, &outage); err != nil {
+		return nil, err
+	}
+	return &outage, nil
+}
+
+func (c *apiClient) CreateOutage(ctx context.Context, req domain.CreateOutageRequest) (*domain.Outage, error) {
+	var outage domain.Outage
+	if err := c.doContext(ctx, http.MethodPost, "/api/v1/outages", req, &outage); err != nil {
+		return nil, err
+	}
+	return &outage, nil
+}
+
+func (c *apiClient) UpdateOutage(ctx context.Context, id uuid.UUID, req domain.UpdateOutageRequest) (*domain.Outage, error) {
+	var outage domain.Outage
+	if err := c.doContext(ctx, http.MethodPatch, "/api/v1/outages/"+id.String(), req, &outage); err != nil {
+		return nil, err
+	}
+	return &outage, nil
+}
+
+func (c *apiClient) AddNote(ctx context.Context, outageID uuid.UUID, req domain.AddNoteRequest) (*domain.Note, error) {
+	var note domain.Note
+	if err := c.doContext(ctx, http.MethodPost, "/api/v1/outages/"+outageID.String()+"/notes", req, &note); err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (c *apiClient) AddTag(ctx context.Context, outageID uuid.UUID, key, value string) (*domain.Tag, error) {
+	body := map[string]string{"key": key, "value": value}
+	var tag domain.Tag
+	if err := c.doContext(ctx, http.MethodPost, "/api/v1/outages/"+outageID.String()+"/tags", body, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (c *apiClient) SearchByTag(ctx context.Context, key, value string) ([]*domain.Outage, error) {
+	query := url.Values{}
+	query.Set("key", key)
+	query.Set("value", value)
+	var resp struct {
+		Outages []*domain.Outage `json:"outages"`
+	}
+	if err := c.doContext(ctx, http.MethodGet, "/api/v1/tags/search?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Outages, nil
+}
+
+// grpcClient implements outageClient over the gRPC API
+type grpcClient struct {
+	conn    *grpc.ClientConn
+	outages pb.OutageServiceClient
+	notes   pb.NoteServiceClient
+	tags    pb.TagServiceClient
+	author  string // Note author; the CLI sends no gRPC credentials
+}
+
+func newGRPCClient(addr string, useTLS bool, author string) (*grpcClient, error) {
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	return &grpcClient{
+		conn:    conn,
+		outages: pb.NewOutageServiceClient(conn),
+		notes:   pb.NewNoteServiceClient(conn),
+		tags:    pb.NewTagServiceClient(conn),
+		author:  author,
+	}, nil
+}
+
+func (c *grpcClient) ListOutages(ctx context.Context, limit int) ([]*domain.Outage, error) {
+	resp, err := c.outages.ListOutages(ctx, &pb.ListOutagesRequest{Limit: int32(limit)}) //nolint:gosec // limit is a small CLI flag
+	if err != nil {
+		return nil, err
+	}
+	return outagesFromProto(resp.Outages)
+}
+
+func (c *grpcClient) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
+	resp, err := c.outages.GetOutage(ctx, &pb.GetOutageRequest{Id: id.String()})
+	if err != nil {
+		return nil, err
+	}
+	return grpcserver.OutageProtoToDomain(resp.Outage)
+}
+
+func (c *grpcClient) CreateOutage(ctx context.Context, req domain.CreateOutageRequest) (*domain.Outage, error) {
+	if req.Template != "" {
+		return nil, fmt.Errorf("outage templates are not supported over gRPC")
+	}
+	pbReq := &pb.CreateOutageRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Severity:    req.Severity,
+		AlertIds:    req.AlertIDs,
+		Metadata:    req.Metadata,
+	}
+	for _, t := range req.Tags {
+		pbReq.Tags = append(pbReq.Tags, &pb.TagInput{Key: t.Key, Value: t.Value})
+	}
+	resp, err := c.outages.CreateOutage(ctx, pbReq)
+	if err != nil {
+		return nil, err
+	}
+	return grpcserver.OutageProtoToDomain(resp.Outage)
+}
+
+func (c *grpcClient) UpdateOutage(ctx context.Context, id uuid.UUID, req domain.UpdateOutageRequest) (*domain.Outage, error) {
+	resp, err := c.outages.UpdateOutage(ctx, &pb.UpdateOutageRequest{
+		Id:          id.String(),
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+		Severity:    req.Severity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return grpcserver.OutageProtoToDomain(resp.Outage)
+}
+
+func (c *grpcClient) AddNote(ctx context.Context, outageID uuid.UUID, req domain.AddNoteRequest) (*domain.Note, error) {
+	var parentNoteID string
+	if req.ParentNoteID != nil {
+		parentNoteID = req.ParentNoteID.String()
+	}
+	resp, err := c.notes.AddNote(ctx, &pb.AddNoteRequest{
+		OutageId:     outageID.String(),
+		Content:      req.Content,
+		Format:       req.Format,
+		Author:       c.author,
+		ParentNoteId: parentNoteID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return grpcserver.NoteProtoToDomain(resp.Note)
+}
+
+func (c *grpcClient) AddTag(ctx context.Context, outageID uuid.UUID, key, value string) (*domain.Tag, error) {
+	resp, err := c.tags.AddTag(ctx, &pb.AddTagRequest{OutageId: outageID.String(), Key: key, Value: value})
+	if err != nil {
+		return nil, err
+	}
+	return grpcserver.TagProtoToDomain(resp.Tag)
+}
+
+func (c *grpcClient) SearchByTag(ctx context.Context, key, value string) ([]*domain.Outage, error) {
+	resp, err := c.tags.SearchOutagesByTag(ctx, &pb.SearchOutagesByTagRequest{Key: key, Value: value})
+	if err != nil {
+		return nil, err
+	}
+	return outagesFromProto(resp.Outages)
+}
+
+func outagesFromProto(pbOutages []*pb.Outage) ([]*domain.Outage, error) {
+	outages := make([]*domain.Outage, 0, len(pbOutages))
+	for _, o := range pbOutages {
+		outage, err := grpcserver.OutageProtoToDomain(o)
+		if err != nil {
+			return nil, err
+		}
+		outages = append(outages, outage)
+	}
+	return outages, nil
+}
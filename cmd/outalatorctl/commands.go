@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// errUsage reports a command invoked with bad arguments; the caller prints
+// the usage text
+var errUsage = errors.New("invalid usage")
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// newFlagSet creates a subcommand flag set whose errors are returned to the
+// caller rather than exiting
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func parseID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid outage ID %q", s)
+	}
+	return id, nil
+}
+
+// parseKeyValue splits a key=value argument
+func parseKeyValue(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" || value == "" {
+		return "", "", fmt.Errorf("expected key=value, got %q", s)
+	}
+	return key, value, nil
+}
+
+// runOutage handles `outage list|get|create|resolve`
+func runOutage(ctx context.Context, c outageClient, out *output, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "list":
+		fs := newFlagSet("outage list")
+		status := fs.String("status", "", "Only outages with this status")
+		severity := fs.String("severity", "", "Only outages with this severity")
+		limit := fs.Int("limit", 50, "Most recent outages to fetch")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 {
+			return errUsage
+		}
+		outages, err := c.ListOutages(ctx, *limit)
+		if err != nil {
+			return err
+		}
+		filtered := outages[:0]
+		for _, o := range outages {
+			if (*status == "" || o.Status == *status) && (*severity == "" || o.Severity == *severity) {
+				filtered = append(filtered, o)
+			}
+		}
+		return out.outages(filtered)
+
+	case "get":
+		if len(args) != 2 {
+			return errUsage
+		}
+		id, err := parseID(args[1])
+		if err != nil {
+			return err
+		}
+		outage, err := c.GetOutage(ctx, id)
+		if err != nil {
+			return err
+		}
+		return out.outage(outage)
+
+	case "create":
+		fs := newFlagSet("outage create")
+		title := fs.String("title", "", "Outage title (required)")
+		description := fs.String("description", "", "Outage description")
+		severity := fs.String("severity", "", "critical, high, medium or low")
+		template := fs.String("template", "", "Outage template filling unset fields")
+		var tags stringList
+		fs.Var(&tags, "tag", "Tag as key=value; repeatable")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 || *title == "" {
+			return errUsage
+		}
+		req := domain.CreateOutageRequest{
+			Title:       *title,
+			Description: *description,
+			Severity:    *severity,
+			Template:    *template,
+		}
+		for _, t := range tags {
+			key, value, err := parseKeyValue(t)
+			if err != nil {
+				return err
+			}
+			req.Tags = append(req.Tags, domain.TagInput{Key: key, Value: value})
+		}
+		outage, err := c.CreateOutage(ctx, req)
+		if err != nil {
+			return err
+		}
+		return out.outage(outage)
+
+	case "resolve":
+		if len(args) != 2 {
+			return errUsage
+		}
+		id, err := parseID(args[1])
+		if err != nil {
+			return err
+		}
+		status := "resolved"
+		outage, err := c.UpdateOutage(ctx, id, domain.UpdateOutageRequest{Status: &status})
+		if err != nil {
+			return err
+		}
+		return out.outage(outage)
+	}
+	return errUsage
+}
+
+// runNote handles `note add [-format f] [-reply note-id] <outage-id> <text|->`.
+// A text of "-" reads the note from stdin, e.g. to attach a log.
+func runNote(ctx context.Context, c outageClient, out *output, args []string, stdin io.Reader) error {
+	if len(args) == 0 || args[0] != "add" {
+		return errUsage
+	}
+	fs := newFlagSet("note add")
+	format := fs.String("format", domain.NoteFormatPlaintext, "plaintext, markdown or log")
+	reply := fs.String("reply", "", "ID of the note to reply to")
+	if err := fs.Parse(args[1:]); err != nil || fs.NArg() < 2 {
+		return errUsage
+	}
+	id, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	req := domain.AddNoteRequest{Format: *format}
+	if *reply != "" {
+		parentNoteID, err := uuid.Parse(*reply)
+		if err != nil {
+			return fmt.Errorf("invalid note ID %q", *reply)
+		}
+		req.ParentNoteID = &parentNoteID
+	}
+
+	content := strings.Join(fs.Args()[1:], " ")
+	if content == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read note from stdin: %w", err)
+		}
+		content = string(data)
+	}
+	req.Content = content
+	note, err := c.AddNote(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return out.note(note)
+}
+
+// runTag handles `tag add <outage-id> <key>=<value>`
+func runTag(ctx context.Context, c outageClient, out *output, args []string) error {
+	if len(args) != 3 || args[0] != "add" {
+		return errUsage
+	}
+	id, err := parseID(args[1])
+	if err != nil {
+		return err
+	}
+	key, value, err := parseKeyValue(args[2])
+	if err != nil {
+		return err
+	}
+	tag, err := c.AddTag(ctx, id, key, value)
+	if err != nil {
+		return err
+	}
+	return out.tag(tag)
+}
+
+// runSearch handles `search <key>=<value>`, finding outages by tag
+func runSearch(ctx context.Context, c outageClient, out *output, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	key, value, err := parseKeyValue(args[0])
+	if err != nil {
+		return err
+	}
+	outages, err := c.SearchByTag(ctx, key, value)
+	if err != nil {
+		return err
+	}
+	return out.outages(outages)
+}
+
+// runProfile handles `profile list|use|set`
+func runProfile(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	path := profilePath()
+	f, err := loadProfiles(path)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		for _, name := range f.names() {
+			marker := " "
+			if name == f.Current {
+				marker = "*"
+			}
+			p := f.Profiles[name]
+			fmt.Printf("%s %s\t%s\n", marker, name, profileEndpoint(p))
+		}
+		return nil
+
+	case "use":
+		if len(args) != 2 {
+			return errUsage
+		}
+		if _, ok := f.Profiles[args[1]]; !ok {
+			return fmt.Errorf("profile %q not found in %s", args[1], path)
+		}
+		f.Current = args[1]
+		return f.save(path)
+
+	case "set":
+		if len(args) < 2 {
+			return errUsage
+		}
+		name := args[1]
+		p, ok := f.Profiles[name]
+		if !ok {
+			p = &profile{}
+		}
+		fs := newFlagSet("profile set")
+		fs.StringVar(&p.Server, "server", p.Server, "REST API base URL")
+		fs.StringVar(&p.GRPC, "grpc", p.GRPC, "gRPC address; outage commands use gRPC when set")
+		fs.BoolVar(&p.GRPCTLS, "grpc-tls", p.GRPCTLS, "Connect to the gRPC address over TLS")
+		fs.StringVar(&p.Session, "session", p.Session, "outalator-session cookie value from a signed-in browser")
+		fs.StringVar(&p.Author, "author", p.Author, "Note author for gRPC")
+		if err := fs.Parse(args[2:]); err != nil || fs.NArg() != 0 {
+			return errUsage
+		}
+		f.Profiles[name] = p
+		if f.Current == "" {
+			f.Current = name
+		}
+		return f.save(path)
+	}
+	return errUsage
+}
+
+func profileEndpoint(p *profile) string {
+	if p.GRPC != "" {
+		return "grpc://" + p.GRPC
+	}
+	return p.Server
+}
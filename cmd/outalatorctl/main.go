@@ -0,0 +1,310 @@
+// Command outalatorctl is the command-line client for outalator. It lists,
+// creates and updates outages, notes and tags over the REST or gRPC API,
+// and manages the operational config (teams, tag schemas, routing rules and
+// outage templates) declaratively from a YAML file, so it can be kept in
+// git and applied from CI.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"gopkg.in/yaml.v3"
+)
+
+const usage = `Usage: outalatorctl [flags] <command>
+
+Outage commands:
+  outage list [-status s] [-severity s] [-limit n]
+  outage get <id>
+  outage create -title t [-severity s] [-description d] [-template name] [-tag key=value ...]
+  outage resolve <id>
+  note add [-format plaintext|markdown|log] [-reply note-id] <outage-id> <text>
+                                                                ("-" reads the note from stdin)
+  tag add <outage-id> <key>=<value>
+  search <key>=<value>                                          Find outages by tag
+
+Profile commands:
+  profile list
+  profile use <name>
+  profile set <name> [-server url] [-grpc addr] [-grpc-tls] [-session cookie] [-author name]
+
+Ops config commands:
+  diff     Show the changes apply would make
+  apply    Apply the config file to the server
+  export   Print the server's current config as YAML
+
+Flags:
+`
+
+func main() {
+	var (
+		server      = flag.String("server", "", "Outalator server URL (default $OUTALATOR_SERVER, the profile's, or http://localhost:8080)")
+		grpcAddr    = flag.String("grpc", "", "gRPC address; outage commands use gRPC when set (default $OUTALATOR_GRPC or the profile's)")
+		profileName = flag.String("profile", os.Getenv("OUTALATOR_PROFILE"), "Profile from the config file (default the current profile)")
+		format      = flag.String("o", formatTable, "Output format: table or json")
+		file        = flag.String("f", "outalator-config.yaml", "Path to the declarative config file")
+		prune       = flag.Bool("prune", false, "Delete resources on the server that are not in the config file")
+	)
+	flag.Usage = func() {
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || (*format != formatTable && *format != formatJSON) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	if cmd == "profile" {
+		exitOnError(cmd, runProfile(args))
+		return
+	}
+
+	profiles, err := loadProfiles(profilePath())
+	if err != nil {
+		log.Fatalf("Failed to load profiles: %v", err)
+	}
+	prof, err := profiles.resolve(*profileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client := &apiClient{
+		baseURL: firstNonEmpty(*server, os.Getenv("OUTALATOR_SERVER"), prof.Server, "http://localhost:8080"),
+		session: firstNonEmpty(os.Getenv("OUTALATOR_SESSION"), prof.Session),
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	switch cmd {
+	case "diff", "apply":
+		desired, err := loadConfigFile(*file)
+		if err != nil {
+			log.Fatalf("Failed to load %s: %v", *file, err)
+		}
+		plan, err := client.apply(desired, cmd == "diff", *prune)
+		if err != nil {
+			log.Fatalf("%s failed: %v", cmd, err)
+		}
+		printPlan(plan)
+	case "export":
+		cfg, err := client.get()
+		if err != nil {
+			log.Fatalf("export failed: %v", err)
+		}
+		if err := writeYAML(os.Stdout, cfg); err != nil {
+			log.Fatalf("export failed: %v", err)
+		}
+	case "outage", "note", "tag", "search":
+		var c outageClient = client
+		if addr := firstNonEmpty(*grpcAddr, os.Getenv("OUTALATOR_GRPC"), prof.GRPC); addr != "" {
+			gc, err := newGRPCClient(addr, prof.GRPCTLS, firstNonEmpty(prof.Author, os.Getenv("USER")))
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer func() { _ = gc.conn.Close() }()
+			c = gc
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		out := &output{w: os.Stdout, format: *format}
+		switch cmd {
+		case "outage":
+			err = runOutage(ctx, c, out, args)
+		case "note":
+			err = runNote(ctx, c, out, args, os.Stdin)
+		case "tag":
+			err = runTag(ctx, c, out, args)
+		case "search":
+			err = runSearch(ctx, c, out, args)
+		}
+		exitOnError(cmd, err)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// exitOnError prints usage for usage errors and exits non-zero on any error.
+// Deferred calls do not run, which is fine for a finished command.
+func exitOnError(cmd string, err error) {
+	if errors.Is(err, errUsage) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%s failed: %v", cmd, err)
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// loadConfigFile reads a YAML config document. It goes through JSON so the
+// file uses the same field names as the API, and unknown fields are rejected.
+func loadConfigFile(path string) (domain.OpsConfig, error) {
+	var cfg domain.OpsConfig
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from CLI -f flag, controlled by operator
+	if err != nil {
+		return cfg, err
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return cfg, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if doc == nil {
+		return cfg, nil
+	}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return cfg, fmt.Errorf("unsupported YAML value: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// writeYAML prints cfg as YAML with the API's field names
+func writeYAML(w io.Writer, cfg *domain.OpsConfig) error {
+	encoded, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// printPlan lists changes in the style of a Terraform plan
+func printPlan(plan *domain.OpsConfigPlan) {
+	if len(plan.Changes) == 0 {
+		fmt.Println("No changes. The server matches the config file.")
+		return
+	}
+
+	var create, update, del int
+	for _, c := range plan.Changes {
+		symbol := "~"
+		switch c.Action {
+		case domain.ConfigCreate:
+			symbol = "+"
+			create++
+		case domain.ConfigUpdate:
+			update++
+		case domain.ConfigDelete:
+			symbol = "-"
+			del++
+		}
+		fmt.Printf("  %s %s %s\n", symbol, c.Kind, c.Name)
+	}
+
+	summary := fmt.Sprintf("%d to create, %d to update, %d to delete", create, update, del)
+	if plan.Applied {
+		fmt.Printf("\nApply complete: %s.\n", summary)
+	} else {
+		fmt.Printf("\nPlan: %s.\n", summary)
+	}
+}
+
+// sessionCookie is the cookie holding a signed-in user's session
+const sessionCookie = "outalator-session"
+
+// apiClient calls the server's REST API
+type apiClient struct {
+	baseURL string
+	session string // Session cookie value, when the server requires sign-in
+	http    *http.Client
+}
+
+func (c *apiClient) get() (*domain.OpsConfig, error) {
+	var cfg domain.OpsConfig
+	if err := c.do(http.MethodGet, "/api/v1/config", nil, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *apiClient) apply(desired domain.OpsConfig, dryRun, prune bool) (*domain.OpsConfigPlan, error) {
+	query := url.Values{}
+	query.Set("dry_run", fmt.Sprint(dryRun))
+	query.Set("prune", fmt.Sprint(prune))
+
+	var plan domain.OpsConfigPlan
+	if err := c.do(http.MethodPost, "/api/v1/config/apply?"+query.Encode(), desired, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (c *apiClient) do(method, path string, body, out any) error {
+	return c.doContext(context.Background(), method, path, body, out)
+}
+
+func (c *apiClient) doContext(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.session != "" {
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: c.session})
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("not signed in; set a session with `outalatorctl profile set` (status: %d)", resp.StatusCode)
+		}
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s (status: %d)", apiErr.Error, resp.StatusCode)
+		}
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
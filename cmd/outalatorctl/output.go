@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// output prints command results as aligned tables or JSON
+type output struct {
+	w      io.Writer
+	format string
+}
+
+func (o *output) json(v any) error {
+	enc := json.NewEncoder(o.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (o *output) outages(outages []*domain.Outage) error {
+	if o.format == formatJSON {
+		if outages == nil {
+			outages = []*domain.Outage{}
+		}
+		return o.json(outages)
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tSTATUS\tSEVERITY\tCREATED\tTITLE")
+	for _, outage := range outages {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			outage.ID, outage.Status, outage.Severity, formatTime(outage.CreatedAt), outage.Title)
+	}
+	return tw.Flush()
+}
+
+func (o *output) outage(outage *domain.Outage) error {
+	if o.format == formatJSON {
+		return o.json(outage)
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "ID:\t%s\n", outage.ID)
+	_, _ = fmt.Fprintf(tw, "Title:\t%s\n", outage.Title)
+	_, _ = fmt.Fprintf(tw, "Status:\t%s\n", outage.Status)
+	_, _ = fmt.Fprintf(tw, "Severity:\t%s\n", outage.Severity)
+	_, _ = fmt.Fprintf(tw, "Created:\t%s\n", formatTime(outage.CreatedAt))
+	if outage.ResolvedAt != nil {
+		_, _ = fmt.Fprintf(tw, "Resolved:\t%s\n", formatTime(*outage.ResolvedAt))
+	}
+	if outage.Description != "" {
+		_, _ = fmt.Fprintf(tw, "Description:\t%s\n", outage.Description)
+	}
+	if len(outage.Tags) > 0 {
+		tags := make([]string, len(outage.Tags))
+		for i, t := range outage.Tags {
+			tags[i] = t.Key + "=" + t.Value
+		}
+		_, _ = fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(tags, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(outage.Alerts) > 0 {
+		_, _ = fmt.Fprintf(o.w, "\nAlerts (%d):\n", len(outage.Alerts))
+		for _, a := range outage.Alerts {
+			_, _ = fmt.Fprintf(o.w, "  %s  %s/%s  %s\n", formatTime(a.TriggeredAt), a.Source, a.TeamName, a.Title)
+		}
+	}
+	if len(outage.Notes) > 0 {
+		_, _ = fmt.Fprintf(o.w, "\nNotes (%d):\n", len(outage.Notes))
+		for _, n := range outage.Notes {
+			_, _ = fmt.Fprintf(o.w, "  %s  %s\n", formatTime(n.CreatedAt), n.Author)
+			for _, line := range strings.Split(strings.TrimRight(n.Content, "\n"), "\n") {
+				_, _ = fmt.Fprintf(o.w, "    %s\n", line)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *output) note(note *domain.Note) error {
+	if o.format == formatJSON {
+		return o.json(note)
+	}
+	_, err := fmt.Fprintf(o.w, "Added note %s to outage %s\n", note.ID, note.OutageID)
+	return err
+}
+
+func (o *output) tag(tag *domain.Tag) error {
+	if o.format == formatJSON {
+		return o.json(tag)
+	}
+	_, err := fmt.Fprintf(o.w, "Tagged outage %s with %s=%s\n", tag.OutageID, tag.Key, tag.Value)
+	return err
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
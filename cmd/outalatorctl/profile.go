@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profile holds the connection settings for one outalator deployment
+type profile struct {
+	Server  string `yaml:"server,omitempty"`   // REST API base URL
+	GRPC    string `yaml:"grpc,omitempty"`     // gRPC address; when set, outage commands use gRPC
+	GRPCTLS bool   `yaml:"grpc_tls,omitempty"` // Connect to the gRPC address over TLS
+	// Session is the value of the outalator-session cookie, copied from a
+	// signed-in browser, for servers with OIDC authentication
+	Session string `yaml:"session,omitempty"`
+	Author  string `yaml:"author,omitempty"` // Note author sent over gRPC, which has no sign-in
+}
+
+// profileFile is the CLI's config file, holding named profiles
+type profileFile struct {
+	Current  string              `yaml:"current,omitempty"`
+	Profiles map[string]*profile `yaml:"profiles,omitempty"`
+}
+
+// profilePath returns the config file location: $OUTALATOR_CONFIG, or
+// outalator/config.yaml under the user's config directory
+func profilePath() string {
+	if path := os.Getenv("OUTALATOR_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "outalator.yaml"
+	}
+	return filepath.Join(dir, "outalator", "config.yaml")
+}
+
+// loadProfiles reads the config file. A missing file has no profiles.
+func loadProfiles(path string) (*profileFile, error) {
+	f := &profileFile{Profiles: map[string]*profile{}}
+	data, err := os.ReadFile(path) //nolint:gosec // path is the operator's own config file
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("invalid YAML in %s: %w", path, err)
+	}
+	if f.Profiles == nil {
+		f.Profiles = map[string]*profile{}
+	}
+	return f, nil
+}
+
+// save writes the config file, readable only by its owner as profiles hold
+// session cookies
+func (f *profileFile) save(path string) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// resolve returns the named profile, or the current one when name is
+// empty. Without a current profile the result is empty.
+func (f *profileFile) resolve(name string) (profile, error) {
+	if name == "" {
+		name = f.Current
+	}
+	if name == "" {
+		return profile{}, nil
+	}
+	p, ok := f.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("profile %q not found in %s", name, profilePath())
+	}
+	return *p, nil
+}
+
+func (f *profileFile) names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
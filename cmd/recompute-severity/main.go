@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/conall/outalator/config"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+	"github.com/conall/outalator/storage"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", "config.yaml", "Path to configuration file")
+		apply      = flag.Bool("apply", false, "Write the proposed changes (default is a dry-run report)")
+	)
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx := context.Background()
+	db, err := storage.New(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	svc := service.New(db, slog.Default())
+	if err := svc.SetSeverityMapping(cfg.SeverityMapping); err != nil {
+		log.Fatalf("Invalid severity mapping: %v", err)
+	}
+
+	if !*apply {
+		log.Println("DRY RUN MODE - No changes will be made (use -apply to write them)")
+	}
+
+	changes, err := svc.RecomputeSeverities(ctx, !*apply)
+	printReport(changes)
+	if err != nil {
+		log.Fatalf("Recompute failed after %d change(s): %v", len(changes), err)
+	}
+
+	var alerts, outages int
+	for _, c := range changes {
+		if c.Entity == "outage" {
+			outages++
+		} else {
+			alerts++
+		}
+	}
+	verb := "Would update"
+	if *apply {
+		verb = "Updated"
+	}
+	log.Printf("%s %d alert(s) and %d outage(s)", verb, alerts, outages)
+}
+
+// printReport writes one line per severity change as an aligned table
+func printReport(changes []domain.SeverityChange) {
+	if len(changes) == 0 {
+		log.Println("All severities already match the mapping")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ENTITY\tID\tOUTAGE\tSOURCE\tFROM\tTO")
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.Entity, c.ID, c.OutageID, c.Source, c.From, c.To)
+	}
+	_ = w.Flush()
+}
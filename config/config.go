@@ -4,25 +4,88 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
+	"github.com/conall/outalator/internal/mailgw"
+	"github.com/conall/outalator/notification"
+	"github.com/conall/outalator/validation"
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Server    ServerConfig     `yaml:"server"`
-	GRPC      GRPCConfig       `yaml:"grpc"`
-	Database  DatabaseConfig   `yaml:"database"`
-	Auth      *AuthConfig      `yaml:"auth,omitempty"`
-	PagerDuty *PagerDutyConfig `yaml:"pagerduty,omitempty"`
-	OpsGenie  *OpsGenieConfig  `yaml:"opsgenie,omitempty"`
-	Slack     *SlackConfig     `yaml:"slack,omitempty"`
+	Server     ServerConfig      `yaml:"server"`
+	GRPC       GRPCConfig        `yaml:"grpc"`
+	Database   DatabaseConfig    `yaml:"database"`
+	Auth       *AuthConfig       `yaml:"auth,omitempty"`
+	PagerDuty  *PagerDutyConfig  `yaml:"pagerduty,omitempty"`
+	OpsGenie   *OpsGenieConfig   `yaml:"opsgenie,omitempty"`
+	Mock       *MockConfig       `yaml:"mock,omitempty"`
+	Slack      *SlackConfig      `yaml:"slack,omitempty"`
+	Jira       *JiraConfig       `yaml:"jira,omitempty"`
+	GitHub     *GitHubConfig     `yaml:"github,omitempty"`
+	Email      *EmailConfig      `yaml:"email,omitempty"`
+	Statuspage *StatuspageConfig `yaml:"statuspage,omitempty"`
+	Webhooks   WebhookConfig     `yaml:"webhooks"`
+	Metrics    MetricsConfig     `yaml:"metrics"`
+	Tracing    TracingConfig     `yaml:"tracing"`
+	Reviews    ReviewConfig      `yaml:"reviews"`
+	Logging    LoggingConfig     `yaml:"logging"`
+	AlertSync  AlertSyncConfig   `yaml:"alert_sync"`
+
+	AlertResolution AlertResolutionConfig `yaml:"alert_resolution"`
+	UpdateSLA       UpdateSLAConfig       `yaml:"update_sla"`
+	SourceHealth    SourceHealthConfig    `yaml:"source_health"`
+	Trash           TrashConfig           `yaml:"trash"`
+	TeamSync        TeamSyncConfig        `yaml:"team_sync"`
+	ServiceCatalog  ServiceCatalogConfig  `yaml:"service_catalog"`
+	AI              AIConfig              `yaml:"ai"`
+	Attachments     AttachmentConfig      `yaml:"attachments"`
+	SimilarOutages  SimilarOutagesConfig  `yaml:"similar_outages"`
+	Digests         DigestConfig          `yaml:"digests"`
+	Cache           CacheConfig           `yaml:"cache"`
+	AnalyticsExport AnalyticsExportConfig `yaml:"analytics_export"`
+	Warehouse       WarehouseConfig       `yaml:"warehouse"`
+	Retention       RetentionConfig       `yaml:"retention"`
+	PublicStatus    PublicStatusConfig    `yaml:"public_status"`
+
+	// MailGateway turns inbound email from mail-only monitoring systems into
+	// alerts
+	MailGateway *MailGatewayConfig `yaml:"mail_gateway,omitempty"`
+
+	// CustomFields defines per-entity schemas that custom_fields on outages,
+	// notes and tags are validated against on write.
+	CustomFields validation.Schemas `yaml:"custom_fields,omitempty"`
+
+	// SeverityMapping maps each notification source's native severities
+	// (e.g. PagerDuty urgency, OpsGenie priority) to Outalator severities,
+	// layered over notification.DefaultSeverityMapping.
+	SeverityMapping notification.SeverityMapping `yaml:"severity_mapping,omitempty"`
+
+	// OutageTransitions replaces the built-in outage state machine. Empty
+	// keeps the default open/investigating/mitigated/resolved/closed flow.
+	OutageTransitions []OutageTransitionConfig `yaml:"outage_transitions,omitempty"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Host       string          `yaml:"host"`
+	Port       int             `yaml:"port"`
+	BodyLimits BodyLimitConfig `yaml:"body_limits"`
+	TLS        TLSConfig       `yaml:"tls"`
+	// ValidateResponses logs API responses that do not match the OpenAPI
+	// spec. Meant for development and staging; requests are always
+	// validated.
+	ValidateResponses bool `yaml:"validate_responses"`
+}
+
+// BodyLimitConfig holds maximum request body sizes in bytes. Larger requests
+// are rejected with 413.
+type BodyLimitConfig struct {
+	Default    int64 `yaml:"default"`    // All other routes, default 1 MiB
+	Webhook    int64 `yaml:"webhook"`    // Inbound webhooks, default 1 MiB
+	Attachment int64 `yaml:"attachment"` // Attachment uploads, default 25 MiB
+	Import     int64 `yaml:"import"`     // Outage imports, default 100 MiB
 }
 
 // GRPCConfig holds gRPC server configuration
@@ -30,6 +93,42 @@ type GRPCConfig struct {
 	Enabled bool   `yaml:"enabled"`
 	Host    string `yaml:"host"`
 	Port    int    `yaml:"port"`
+	// Gateway serves the gRPC services as HTTP/JSON under /v1/ on the HTTP
+	// port. It does not need Enabled.
+	Gateway bool           `yaml:"gateway"`
+	TLS     TLSConfig      `yaml:"tls"`
+	Auth    GRPCAuthConfig `yaml:"auth"`
+	// DisableRecovery lets a panicking handler crash the server instead of
+	// failing the call with Internal
+	DisableRecovery bool `yaml:"disable_recovery"`
+	// DisableValidation skips the checks of IDs, required fields and
+	// pagination made before requests reach the handlers
+	DisableValidation bool `yaml:"disable_validation"`
+}
+
+// GRPCAuthConfig holds the credentials the gRPC port accepts. When Enabled,
+// every call except health checks must carry "authorization: Bearer" metadata
+// with one of the APIKeys or, when OIDC is set, an ID token issued by the
+// provider configured under auth.
+type GRPCAuthConfig struct {
+	Enabled bool         `yaml:"enabled"`
+	APIKeys []GRPCAPIKey `yaml:"api_keys"`
+	OIDC    bool         `yaml:"oidc"`
+}
+
+// GRPCAPIKey is a static key a gRPC client can authenticate with
+type GRPCAPIKey struct {
+	Name string `yaml:"name"` // Identifies the client in logs
+	Key  string `yaml:"key"`
+}
+
+// TLSConfig holds a listener's TLS certificate. TLS is served when
+// CertFile and KeyFile are set; setting ClientCAFile as well requires
+// clients to present a certificate issued by one of its CAs (mTLS).
+type TLSConfig struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"`
 }
 
 // DatabaseConfig holds database configuration
@@ -45,7 +144,13 @@ type DatabaseConfig struct {
 	SSLMode  string `yaml:"sslmode"`
 	// Path is the file path for the SQLite database (e.g. "outalator.db" or ":memory:").
 	// Only used when Driver is "sqlite".
-	Path     string `yaml:"path"`
+	Path string `yaml:"path"`
+
+	// Connection pool and query limits, only used when Driver is "postgres"
+	MaxOpenConns     int           `yaml:"max_open_conns"`    // Connections open at once, default 25
+	MaxIdleConns     int           `yaml:"max_idle_conns"`    // Idle connections kept for reuse, default 5
+	ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime"` // Connections are closed after this long, default 30m
+	StatementTimeout time.Duration `yaml:"statement_timeout"` // Queries running longer are cancelled, default 30s; negative disables
 }
 
 // AuthConfig holds OIDC authentication configuration
@@ -56,18 +161,91 @@ type AuthConfig struct {
 	ClientSecret string `yaml:"client_secret"`
 	RedirectURL  string `yaml:"redirect_url"`
 	SessionKey   string `yaml:"session_key,omitempty"`
+	// Admins lists the emails allowed to see and restore deleted outages
+	// and notes. With auth disabled every caller is treated as an admin.
+	Admins []string `yaml:"admins,omitempty"`
+	// AnonymousRead lets callers who have not signed in read through every
+	// route group not listed in Routes, e.g. on an internal network.
+	// Changes still need sign-in.
+	AnonymousRead bool `yaml:"anonymous_read,omitempty"`
+	// Routes sets the access of individual route groups (api, events,
+	// graphql, grpc, ui) to required, anonymous_read or open
+	Routes map[string]string `yaml:"routes,omitempty"`
+	// GroupsClaim names the ID token claim listing the user's IdP groups,
+	// "groups" by default. A dotted path such as realm_access.roles reads
+	// a nested claim.
+	GroupsClaim string `yaml:"groups_claim,omitempty"`
+	// Groups maps IdP groups to the roles and teams their members get.
+	// They are read again at each sign-in, so access follows the IdP.
+	Groups map[string]GroupMapping `yaml:"groups,omitempty"`
+	// ServiceTokens lists the issuers whose bearer JWTs authenticate
+	// services, such as CI jobs, on REST and gRPC requests without a
+	// session. Callers are identified as service:<name>:<subject>.
+	ServiceTokens []ServiceTokenConfig `yaml:"service_tokens,omitempty"`
+	// BearerIDTokens accepts ID tokens issued to the client ID as bearer
+	// tokens on HTTP requests. Off by default, since a token taken from a
+	// browser session then works as an API credential until it expires.
+	BearerIDTokens bool `yaml:"bearer_id_tokens,omitempty"`
+}
+
+// ServiceTokenConfig configures an issuer of service bearer JWTs
+type ServiceTokenConfig struct {
+	Name     string `yaml:"name,omitempty"` // Identifies the issuer in logs, defaults to the issuer URL
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"` // Tokens must list it in their aud claim
+	// JWKSURL serves the keys tokens are signed with, found through the
+	// issuer's OIDC discovery document when empty
+	JWKSURL string `yaml:"jwks_url,omitempty"`
+}
+
+// GroupMapping sets what the members of an IdP group get: roles (admin)
+// and teams they are members of, in addition to the team's listed members
+type GroupMapping struct {
+	Roles []string `yaml:"roles,omitempty"`
+	Teams []string `yaml:"teams,omitempty"`
 }
 
 // PagerDutyConfig holds PagerDuty API configuration
 type PagerDutyConfig struct {
 	APIKey string `yaml:"api_key"`
 	APIURL string `yaml:"api_url,omitempty"`
+	// From is the email address of the PagerDuty user that incidents opened
+	// from outages are created as, when the user paging is not known
+	From string `yaml:"from,omitempty"`
+	// WebhookSecret is the V3 webhook subscription's signing secret.
+	// Webhook deliveries are rejected until it is set.
+	WebhookSecret string `yaml:"webhook_secret,omitempty"`
+	// Retry configures how failed API calls are retried
+	Retry RetryConfig `yaml:"retry,omitempty"`
 }
 
 // OpsGenieConfig holds OpsGenie API configuration
 type OpsGenieConfig struct {
 	APIKey string `yaml:"api_key"`
 	APIURL string `yaml:"api_url,omitempty"`
+	// WebhookToken is a shared secret the outgoing webhook integration
+	// sends in the X-Outalator-Webhook-Token header. Webhook deliveries are
+	// rejected until it is set.
+	WebhookToken string      `yaml:"webhook_token,omitempty"`
+	Retry        RetryConfig `yaml:"retry,omitempty"`
+}
+
+// RetryConfig configures how a provider's rate-limited (429) and failed
+// (5xx or network error) API calls are retried with exponential backoff.
+// Waits the provider asks for with Retry-After or rate limit headers are
+// honoured, up to MaxBackoff.
+type RetryConfig struct {
+	MaxRetries int           `yaml:"max_retries"` // Retries per call, default 3; negative disables
+	MinBackoff time.Duration `yaml:"min_backoff"` // Wait before the first retry, doubled for each after it, default 500ms
+	MaxBackoff time.Duration `yaml:"max_backoff"` // Longest wait between attempts, default 30s
+}
+
+// MockConfig holds configuration for the mock notification provider, which
+// serves fake alerts from a fixture file for demos and integration tests
+type MockConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Source  string `yaml:"source,omitempty"` // Alert source name, default "mock"
+	Fixture string `yaml:"fixture"`          // Path to the YAML fixture file
 }
 
 // SlackConfig holds Slack bot configuration
@@ -76,6 +254,337 @@ type SlackConfig struct {
 	BotToken      string `yaml:"bot_token"`
 	SigningSecret string `yaml:"signing_secret"`
 	ReactionEmoji string `yaml:"reaction_emoji"` // Emoji for tagging messages
+	// ArchiveChannelMessages adds messages posted in a channel bound to an
+	// open outage to that outage as notes
+	ArchiveChannelMessages bool          `yaml:"archive_channel_messages"`
+	EventWorkers           int           `yaml:"event_workers"`    // Events processed concurrently, default 4
+	EventQueueSize         int           `yaml:"event_queue_size"` // Events buffered before returning 503, default 1000
+	EventTTL               time.Duration `yaml:"event_ttl"`        // How long processed event IDs are remembered to ignore retries, default 24h
+}
+
+// JiraConfig holds Jira integration configuration
+type JiraConfig struct {
+	Enabled            bool              `yaml:"enabled"`
+	URL                string            `yaml:"url"`   // e.g. https://example.atlassian.net
+	Email              string            `yaml:"email"` // Jira Cloud account; leave empty to use api_token as a bearer token
+	APIToken           string            `yaml:"api_token"`
+	ProjectKey         string            `yaml:"project_key"`
+	IssueType          string            `yaml:"issue_type,omitempty"` // Default Task
+	Priorities         map[string]string `yaml:"priorities,omitempty"` // Outage severity to Jira priority name
+	Labels             []string          `yaml:"labels,omitempty"`     // Added to every created issue
+	OutageURL          string            `yaml:"outage_url,omitempty"` // Link back to the outage; {id} is replaced with its ID
+	StatusSyncInterval time.Duration     `yaml:"status_sync_interval"` // How often issue statuses are copied to outages, default 15m
+}
+
+// GitHubConfig holds GitHub issue integration configuration
+type GitHubConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Token      string   `yaml:"token"`
+	Repository string   `yaml:"repository"`           // owner/repo that action item issues are created in
+	APIURL     string   `yaml:"api_url,omitempty"`    // Set for GitHub Enterprise, e.g. https://github.example.com/api/v3
+	Labels     []string `yaml:"labels,omitempty"`     // Added to every created issue
+	OutageURL  string   `yaml:"outage_url,omitempty"` // Link back to the outage; {id} is replaced with its ID
+}
+
+// StatuspageConfig holds Atlassian Statuspage integration configuration
+type StatuspageConfig struct {
+	Enabled      bool              `yaml:"enabled"`
+	APIKey       string            `yaml:"api_key"`
+	PageID       string            `yaml:"page_id"`
+	APIURL       string            `yaml:"api_url,omitempty"`
+	ComponentTag string            `yaml:"component_tag,omitempty"` // Outage tag selecting components, default "service"
+	Components   map[string]string `yaml:"components,omitempty"`    // Tag value to Statuspage component ID
+}
+
+// EmailConfig holds SMTP configuration for email notifications
+type EmailConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Host      string `yaml:"host"`
+	Port      int    `yaml:"port"`     // Default 587
+	Username  string `yaml:"username"` // Leave empty to send without authentication
+	Password  string `yaml:"password"`
+	From      string `yaml:"from"`
+	OutageURL string `yaml:"outage_url,omitempty"` // Link back to the outage; {id} is replaced with its ID
+}
+
+// MailGatewayConfig holds inbound email alert ingestion configuration.
+// Messages can also be posted as raw MIME to /api/v1/webhooks/{source}.
+type MailGatewayConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Source          string `yaml:"source,omitempty"`            // Alert source name, default "email"
+	SMTPAddr        string `yaml:"smtp_addr,omitempty"`         // SMTP listen address, e.g. ":2525"; empty disables the listener
+	MaxMessageBytes int64  `yaml:"max_message_bytes,omitempty"` // Largest message accepted over SMTP, default 1 MiB
+	// MailgunSigningKey enables the Mailgun route endpoint
+	// /api/v1/webhooks/{source}/mailgun, whose requests are verified with it
+	MailgunSigningKey string `yaml:"mailgun_signing_key,omitempty"`
+	// WebhookToken enables raw messages posted to /api/v1/webhooks/{source},
+	// which must send it in the X-Outalator-Webhook-Token header
+	WebhookToken string        `yaml:"webhook_token,omitempty"`
+	Rules        []mailgw.Rule `yaml:"rules"`
+}
+
+// WebhookConfig holds inbound webhook ingestion configuration
+type WebhookConfig struct {
+	Workers   int `yaml:"workers"`    // Concurrent deliveries processed, default 4
+	QueueSize int `yaml:"queue_size"` // Deliveries buffered before returning 503, default 1000
+	// SpoolDir persists queued deliveries to disk so they survive restarts.
+	// Leave empty for an in-memory queue.
+	SpoolDir string `yaml:"spool_dir,omitempty"`
+}
+
+// MetricsConfig holds Prometheus metrics configuration
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // HTTP path the metrics are served on, default /metrics
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint is the OTLP gRPC collector address, e.g. localhost:4317.
+	// Defaults to OTEL_EXPORTER_OTLP_ENDPOINT when empty.
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`     // Connect to the collector without TLS
+	ServiceName string  `yaml:"service_name"` // Reported service.name, default outalator
+	SampleRatio float64 `yaml:"sample_ratio"` // Fraction of new traces sampled, default 1
+}
+
+// ReviewConfig holds post-resolution review reminder configuration.
+// Reminders are posted by the Slack bot, so Slack must be enabled.
+type ReviewConfig struct {
+	ReminderChannel  string        `yaml:"reminder_channel"`  // Slack channel for overdue review reminders; empty disables them
+	ReminderAfter    time.Duration `yaml:"reminder_after"`    // Time an outage may wait in needs-review, default 72h
+	ReminderInterval time.Duration `yaml:"reminder_interval"` // How often reminders are posted, default 24h
+}
+
+// LoggingConfig holds structured logging configuration
+type LoggingConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error, default info
+	Format string `yaml:"format"` // text or json, default text
+}
+
+// AlertSyncConfig holds configuration for the background poller that pulls
+// recent alerts from every registered notification service
+type AlertSyncConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	Interval        time.Duration `yaml:"interval"`         // Time between sync passes, default 5m
+	Lookback        time.Duration `yaml:"lookback"`         // Overlap re-fetched before the stored cursor to catch state changes, default 24h
+	InitialLookback time.Duration `yaml:"initial_lookback"` // Window fetched for a source with no cursor, default 24h
+}
+
+// AlertResolutionConfig holds the policy for resolving alerts, and
+// optionally their outages, without waiting for someone to close them
+type AlertResolutionConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	MaxOpen        time.Duration `yaml:"max_open"`        // Resolve alerts open for longer than this; zero disables the timeout
+	ResolveOutages bool          `yaml:"resolve_outages"` // Resolve an outage once all of its alerts are resolved
+	Interval       time.Duration `yaml:"interval"`        // Time between stale alert sweeps, default 15m
+}
+
+// DigestConfig holds the scheduled outage digests sent by email and Slack
+type DigestConfig struct {
+	Enabled       bool                   `yaml:"enabled"`
+	CheckInterval time.Duration          `yaml:"check_interval"` // Time between checks for due digests, default 5m
+	Schedules     []DigestScheduleConfig `yaml:"schedules"`
+}
+
+// AnalyticsExportConfig holds the scheduled export of outages, alerts and
+// notes to a bucket, for loading into a data warehouse. GCS buckets are
+// written through their S3-compatible endpoint, https://storage.googleapis.com,
+// with HMAC keys.
+type AnalyticsExportConfig struct {
+	Enabled  bool               `yaml:"enabled"`
+	Format   string             `yaml:"format"`   // parquet (default) or csv
+	Tables   []string           `yaml:"tables"`   // Any of outages, alerts and notes; default all three
+	Interval time.Duration      `yaml:"interval"` // Time between exports, default 1h
+	Backend  string             `yaml:"backend"`  // "local" or "s3"
+	Dir      string             `yaml:"dir"`      // Directory for the local backend
+	S3       S3AttachmentConfig `yaml:"s3"`
+}
+
+// WarehouseConfig holds the sink that streams outage lifecycle events into
+// a BigQuery or ClickHouse table as they occur
+type WarehouseConfig struct {
+	Enabled       bool                      `yaml:"enabled"`
+	Driver        string                    `yaml:"driver"`         // bigquery or clickhouse
+	FlushInterval time.Duration             `yaml:"flush_interval"` // Longest an event waits before it is written, default 5s
+	BatchSize     int                       `yaml:"batch_size"`     // Most events written in one insert, default 500
+	BigQuery      BigQueryWarehouseConfig   `yaml:"bigquery"`
+	ClickHouse    ClickHouseWarehouseConfig `yaml:"clickhouse"`
+}
+
+// BigQueryWarehouseConfig holds the BigQuery table events are streamed into
+type BigQueryWarehouseConfig struct {
+	Project         string `yaml:"project"`
+	Dataset         string `yaml:"dataset"`
+	Table           string `yaml:"table"`
+	CredentialsFile string `yaml:"credentials_file"` // Service account key file
+}
+
+// ClickHouseWarehouseConfig holds the ClickHouse table events are inserted
+// into
+type ClickHouseWarehouseConfig struct {
+	URL      string `yaml:"url"`      // HTTP interface, e.g. http://clickhouse:8123
+	Database string `yaml:"database"` // Default "default"
+	Table    string `yaml:"table"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// RetentionConfig holds the policy that moves long-resolved outages out of
+// the database into archive files, and deletes the archives once they are
+// old enough, e.g. archive_after: 17520h (2 years) and purge_after: 43800h
+// (5 years)
+type RetentionConfig struct {
+	Enabled      bool               `yaml:"enabled"`
+	ArchiveAfter time.Duration      `yaml:"archive_after"` // Archive outages resolved longer ago than this; required
+	PurgeAfter   time.Duration      `yaml:"purge_after"`   // Delete archives of outages resolved longer ago than this; zero keeps them forever
+	Interval     time.Duration      `yaml:"interval"`      // Time between runs, default 24h
+	Backend      string             `yaml:"backend"`       // "local" or "s3"
+	Dir          string             `yaml:"dir"`           // Directory for the local backend
+	S3           S3AttachmentConfig `yaml:"s3"`
+}
+
+// PublicStatusConfig holds the unauthenticated GET /public/status endpoint,
+// which lists open outages with "public" metadata set to "true"
+type PublicStatusConfig struct {
+	Enabled           bool          `yaml:"enabled"`
+	RequestsPerMinute int           `yaml:"requests_per_minute"` // Per client address, default 60
+	TrustProxy        bool          `yaml:"trust_proxy"`         // Count requests against the first X-Forwarded-For address
+	CacheTTL          time.Duration `yaml:"cache_ttl"`           // How long a response is served before it is rebuilt, default 10s
+}
+
+// CacheConfig holds the read-through cache in front of outage reads
+type CacheConfig struct {
+	Enabled bool             `yaml:"enabled"`
+	Backend string           `yaml:"backend"` // memory (default) or redis
+	TTL     time.Duration    `yaml:"ttl"`     // How long entries are served before reloading, default 30s
+	Size    int              `yaml:"size"`    // Entries held by the memory backend, default 1000
+	Redis   RedisCacheConfig `yaml:"redis"`
+}
+
+// RedisCacheConfig holds the Redis server shared by every replica when the
+// cache backend is redis
+type RedisCacheConfig struct {
+	Addr     string `yaml:"addr"` // host:port
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+// DigestScheduleConfig holds one recurring digest. A digest for a team
+// without emails or a Slack channel goes to the team's members and channel.
+type DigestScheduleConfig struct {
+	Name         string   `yaml:"name"`
+	Team         string   `yaml:"team"`          // Only summarise this team's outages; empty covers all
+	Period       string   `yaml:"period"`        // daily or weekly
+	Weekday      string   `yaml:"weekday"`       // Day weekly digests are sent, default monday
+	Hour         int      `yaml:"hour"`          // Hour of day the digest is sent, 0-23
+	Timezone     string   `yaml:"timezone"`      // IANA time zone for weekday and hour, default UTC
+	Emails       []string `yaml:"emails"`        // Requires email to be enabled
+	SlackChannel string   `yaml:"slack_channel"` // Requires the Slack bot to be enabled
+	Template     string   `yaml:"template"`      // Go text/template rendering the digest; empty uses the default
+}
+
+// SimilarOutagesConfig holds whether new outages get a note listing the
+// past outages that resemble them
+type SimilarOutagesConfig struct {
+	Note     bool    `yaml:"note"`
+	MinScore float64 `yaml:"min_score"` // Lowest similarity listed, from 0 to 1, default 0.3
+	Limit    int     `yaml:"limit"`     // Most outages listed, default 5
+}
+
+// TrashConfig holds how long deleted outages and notes are kept, so they can
+// be restored, before being purged for good
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention"`      // Purge items deleted longer ago than this; zero keeps them forever
+	PurgeInterval time.Duration `yaml:"purge_interval"` // Time between purges, default 1h
+}
+
+// TeamSyncConfig holds whether teams are synced from the notification
+// services that can list them
+type TeamSyncConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // Time between syncs, default 6h
+}
+
+// ServiceCatalogConfig holds the external catalogues the service catalogue
+// is synced from. Syncing is enabled while a file or Backstage URL is set.
+type ServiceCatalogConfig struct {
+	File      string                 `yaml:"file"` // YAML file listing services, or Backstage catalog-info documents
+	Backstage BackstageCatalogConfig `yaml:"backstage"`
+	Interval  time.Duration          `yaml:"interval"` // Time between syncs, default 1h
+}
+
+// BackstageCatalogConfig holds the Backstage instance whose Component
+// entities are synced as services
+type BackstageCatalogConfig struct {
+	URL   string `yaml:"url"`   // Base URL of the Backstage backend
+	Token string `yaml:"token"` // Optional bearer token for the catalog API
+}
+
+// AIConfig holds the language model used to write outage summaries,
+// reached through an OpenAI-compatible chat completions API
+type AIConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	BaseURL string        `yaml:"base_url"` // API base URL, default https://api.openai.com/v1
+	APIKey  string        `yaml:"api_key"`  // Sent as a bearer token; leave empty for servers without authentication
+	Model   string        `yaml:"model"`    // Model name, e.g. gpt-4o-mini
+	Timeout time.Duration `yaml:"timeout"`  // Longest a completion may take, default 2m
+}
+
+// AttachmentConfig holds where files uploaded to outages are stored and
+// what may be uploaded. Attachments are disabled while Backend is empty.
+type AttachmentConfig struct {
+	Backend      string             `yaml:"backend"`       // "local" or "s3"
+	Dir          string             `yaml:"dir"`           // Directory for the local backend
+	MaxBytes     int64              `yaml:"max_bytes"`     // Largest upload, default 25 MiB
+	AllowedTypes []string           `yaml:"allowed_types"` // Accepted content types, default images, plain text and PDF
+	S3           S3AttachmentConfig `yaml:"s3"`
+}
+
+// S3AttachmentConfig holds the bucket used by the s3 attachment backend. Any
+// S3-compatible store works; MinIO and most self-hosted stores need
+// path_style.
+type S3AttachmentConfig struct {
+	Endpoint        string `yaml:"endpoint"` // e.g. https://s3.eu-west-1.amazonaws.com
+	Region          string `yaml:"region"`   // Default us-east-1
+	Bucket          string `yaml:"bucket"`
+	Prefix          string `yaml:"prefix,omitempty"` // Prepended to object keys
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	PathStyle       bool   `yaml:"path_style"`
+}
+
+// UpdateSLAConfig holds the per-severity intervals at which active outages
+// need a status update, and the reminders sent when one is missed
+type UpdateSLAConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Intervals maps severities to the time allowed between updates, e.g.
+	// critical: 30m. Severities without an interval have no SLA.
+	Intervals       map[string]time.Duration `yaml:"intervals"`
+	ReminderChannel string                   `yaml:"reminder_channel"` // Slack channel for reminders about outages with no team or bound channel
+	CheckInterval   time.Duration            `yaml:"check_interval"`   // Time between overdue checks, default 1m
+}
+
+// SourceHealthConfig holds how long each alert source may go without a
+// successful webhook delivery or sync before it is alarmed about as stale
+type SourceHealthConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// DefaultMaxSilence applies to every source without its own threshold;
+	// zero only checks the sources listed in MaxSilence
+	DefaultMaxSilence time.Duration            `yaml:"default_max_silence"`
+	MaxSilence        map[string]time.Duration `yaml:"max_silence"`    // Per-source thresholds, e.g. pagerduty: 6h; 0 disables the check
+	AlarmChannel      string                   `yaml:"alarm_channel"`  // Slack channel stale source alarms are posted to
+	CheckInterval     time.Duration            `yaml:"check_interval"` // Time between staleness checks, default 5m
+}
+
+// OutageTransitionConfig is a named action that moves an outage from any of
+// the From statuses to To
+type OutageTransitionConfig struct {
+	Action   string   `yaml:"action"`
+	From     []string `yaml:"from"`
+	To       string   `yaml:"to"`
+	Explicit bool     `yaml:"explicit"` // Only taken through the transition endpoint, never by editing the status
 }
 
 // Load loads configuration from a YAML file
@@ -100,6 +609,16 @@ func Load(path string) (*Config, error) {
 		}
 	}
 
+	if certFile := os.Getenv("SERVER_TLS_CERT_FILE"); certFile != "" {
+		cfg.Server.TLS.CertFile = certFile
+	}
+	if keyFile := os.Getenv("SERVER_TLS_KEY_FILE"); keyFile != "" {
+		cfg.Server.TLS.KeyFile = keyFile
+	}
+	if clientCAFile := os.Getenv("SERVER_TLS_CLIENT_CA_FILE"); clientCAFile != "" {
+		cfg.Server.TLS.ClientCAFile = clientCAFile
+	}
+
 	// gRPC configuration
 	if os.Getenv("GRPC_ENABLED") == "true" {
 		cfg.GRPC.Enabled = true
@@ -112,6 +631,21 @@ func Load(path string) (*Config, error) {
 			log.Printf("config: invalid GRPC_PORT value, using default: %v", err)
 		}
 	}
+	if certFile := os.Getenv("GRPC_TLS_CERT_FILE"); certFile != "" {
+		cfg.GRPC.TLS.CertFile = certFile
+	}
+	if keyFile := os.Getenv("GRPC_TLS_KEY_FILE"); keyFile != "" {
+		cfg.GRPC.TLS.KeyFile = keyFile
+	}
+	if clientCAFile := os.Getenv("GRPC_TLS_CLIENT_CA_FILE"); clientCAFile != "" {
+		cfg.GRPC.TLS.ClientCAFile = clientCAFile
+	}
+	if os.Getenv("GRPC_AUTH_ENABLED") == "true" {
+		cfg.GRPC.Auth.Enabled = true
+	}
+	if key := os.Getenv("GRPC_AUTH_API_KEY"); key != "" {
+		cfg.GRPC.Auth.APIKeys = append(cfg.GRPC.Auth.APIKeys, GRPCAPIKey{Name: "env", Key: key})
+	}
 
 	if dbDriver := os.Getenv("DB_DRIVER"); dbDriver != "" {
 		cfg.Database.Driver = dbDriver
@@ -136,6 +670,19 @@ func Load(path string) (*Config, error) {
 	if dbName := os.Getenv("DB_NAME"); dbName != "" {
 		cfg.Database.DBName = dbName
 	}
+	if maxOpen := os.Getenv("DB_MAX_OPEN_CONNS"); maxOpen != "" {
+		if _, err := fmt.Sscanf(maxOpen, "%d", &cfg.Database.MaxOpenConns); err != nil {
+			log.Printf("config: invalid DB_MAX_OPEN_CONNS value, using default: %v", err)
+		}
+	}
+	if timeout := os.Getenv("DB_STATEMENT_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.Printf("config: invalid DB_STATEMENT_TIMEOUT value, using default: %v", err)
+		} else {
+			cfg.Database.StatementTimeout = d
+		}
+	}
 
 	if pdKey := os.Getenv("PAGERDUTY_API_KEY"); pdKey != "" {
 		if cfg.PagerDuty == nil {
@@ -143,6 +690,19 @@ func Load(path string) (*Config, error) {
 		}
 		cfg.PagerDuty.APIKey = pdKey
 	}
+	if pdFrom := os.Getenv("PAGERDUTY_FROM"); pdFrom != "" {
+		if cfg.PagerDuty == nil {
+			cfg.PagerDuty = &PagerDutyConfig{}
+		}
+		cfg.PagerDuty.From = pdFrom
+	}
+
+	if pdSecret := os.Getenv("PAGERDUTY_WEBHOOK_SECRET"); pdSecret != "" {
+		if cfg.PagerDuty == nil {
+			cfg.PagerDuty = &PagerDutyConfig{}
+		}
+		cfg.PagerDuty.WebhookSecret = pdSecret
+	}
 
 	if ogKey := os.Getenv("OPSGENIE_API_KEY"); ogKey != "" {
 		if cfg.OpsGenie == nil {
@@ -150,6 +710,20 @@ func Load(path string) (*Config, error) {
 		}
 		cfg.OpsGenie.APIKey = ogKey
 	}
+	if ogToken := os.Getenv("OPSGENIE_WEBHOOK_TOKEN"); ogToken != "" {
+		if cfg.OpsGenie == nil {
+			cfg.OpsGenie = &OpsGenieConfig{}
+		}
+		cfg.OpsGenie.WebhookToken = ogToken
+	}
+
+	if fixture := os.Getenv("MOCK_PROVIDER_FIXTURE"); fixture != "" {
+		if cfg.Mock == nil {
+			cfg.Mock = &MockConfig{}
+		}
+		cfg.Mock.Enabled = true
+		cfg.Mock.Fixture = fixture
+	}
 
 	// Auth environment variables
 	if os.Getenv("AUTH_ENABLED") == "true" {
@@ -188,6 +762,35 @@ func Load(path string) (*Config, error) {
 		}
 		cfg.Auth.SessionKey = sessionKey
 	}
+	if os.Getenv("AUTH_ANONYMOUS_READ") == "true" {
+		if cfg.Auth == nil {
+			cfg.Auth = &AuthConfig{}
+		}
+		cfg.Auth.AnonymousRead = true
+	}
+	if groupsClaim := os.Getenv("AUTH_GROUPS_CLAIM"); groupsClaim != "" {
+		if cfg.Auth == nil {
+			cfg.Auth = &AuthConfig{}
+		}
+		cfg.Auth.GroupsClaim = groupsClaim
+	}
+	if os.Getenv("AUTH_BEARER_ID_TOKENS") == "true" {
+		if cfg.Auth == nil {
+			cfg.Auth = &AuthConfig{}
+		}
+		cfg.Auth.BearerIDTokens = true
+	}
+	if issuer := os.Getenv("AUTH_SERVICE_TOKEN_ISSUER"); issuer != "" {
+		if cfg.Auth == nil {
+			cfg.Auth = &AuthConfig{}
+		}
+		cfg.Auth.ServiceTokens = append(cfg.Auth.ServiceTokens, ServiceTokenConfig{
+			Name:     "env",
+			Issuer:   issuer,
+			Audience: os.Getenv("AUTH_SERVICE_TOKEN_AUDIENCE"),
+			JWKSURL:  os.Getenv("AUTH_SERVICE_TOKEN_JWKS_URL"),
+		})
+	}
 
 	// Slack environment variables
 	if os.Getenv("SLACK_ENABLED") == "true" {
@@ -214,6 +817,340 @@ func Load(path string) (*Config, error) {
 		}
 		cfg.Slack.ReactionEmoji = reactionEmoji
 	}
+	if os.Getenv("SLACK_ARCHIVE_CHANNEL_MESSAGES") == "true" {
+		if cfg.Slack == nil {
+			cfg.Slack = &SlackConfig{}
+		}
+		cfg.Slack.ArchiveChannelMessages = true
+	}
+
+	// Mail gateway environment variables
+	if os.Getenv("MAIL_GATEWAY_ENABLED") == "true" {
+		if cfg.MailGateway == nil {
+			cfg.MailGateway = &MailGatewayConfig{}
+		}
+		cfg.MailGateway.Enabled = true
+	}
+	if smtpAddr := os.Getenv("MAIL_GATEWAY_SMTP_ADDR"); smtpAddr != "" {
+		if cfg.MailGateway == nil {
+			cfg.MailGateway = &MailGatewayConfig{}
+		}
+		cfg.MailGateway.SMTPAddr = smtpAddr
+	}
+	if signingKey := os.Getenv("MAILGUN_SIGNING_KEY"); signingKey != "" {
+		if cfg.MailGateway == nil {
+			cfg.MailGateway = &MailGatewayConfig{}
+		}
+		cfg.MailGateway.MailgunSigningKey = signingKey
+	}
+
+	// Jira environment variables
+	if os.Getenv("JIRA_ENABLED") == "true" {
+		if cfg.Jira == nil {
+			cfg.Jira = &JiraConfig{}
+		}
+		cfg.Jira.Enabled = true
+	}
+	if jiraURL := os.Getenv("JIRA_URL"); jiraURL != "" {
+		if cfg.Jira == nil {
+			cfg.Jira = &JiraConfig{}
+		}
+		cfg.Jira.URL = jiraURL
+	}
+	if jiraEmail := os.Getenv("JIRA_EMAIL"); jiraEmail != "" {
+		if cfg.Jira == nil {
+			cfg.Jira = &JiraConfig{}
+		}
+		cfg.Jira.Email = jiraEmail
+	}
+	if jiraToken := os.Getenv("JIRA_API_TOKEN"); jiraToken != "" {
+		if cfg.Jira == nil {
+			cfg.Jira = &JiraConfig{}
+		}
+		cfg.Jira.APIToken = jiraToken
+	}
+	if projectKey := os.Getenv("JIRA_PROJECT_KEY"); projectKey != "" {
+		if cfg.Jira == nil {
+			cfg.Jira = &JiraConfig{}
+		}
+		cfg.Jira.ProjectKey = projectKey
+	}
+
+	// GitHub environment variables
+	if os.Getenv("GITHUB_ENABLED") == "true" {
+		if cfg.GitHub == nil {
+			cfg.GitHub = &GitHubConfig{}
+		}
+		cfg.GitHub.Enabled = true
+	}
+	if githubToken := os.Getenv("GITHUB_TOKEN"); githubToken != "" {
+		if cfg.GitHub == nil {
+			cfg.GitHub = &GitHubConfig{}
+		}
+		cfg.GitHub.Token = githubToken
+	}
+	if repository := os.Getenv("GITHUB_REPOSITORY"); repository != "" {
+		if cfg.GitHub == nil {
+			cfg.GitHub = &GitHubConfig{}
+		}
+		cfg.GitHub.Repository = repository
+	}
+
+	// Statuspage environment variables
+	if os.Getenv("STATUSPAGE_ENABLED") == "true" {
+		if cfg.Statuspage == nil {
+			cfg.Statuspage = &StatuspageConfig{}
+		}
+		cfg.Statuspage.Enabled = true
+	}
+	if apiKey := os.Getenv("STATUSPAGE_API_KEY"); apiKey != "" {
+		if cfg.Statuspage == nil {
+			cfg.Statuspage = &StatuspageConfig{}
+		}
+		cfg.Statuspage.APIKey = apiKey
+	}
+	if pageID := os.Getenv("STATUSPAGE_PAGE_ID"); pageID != "" {
+		if cfg.Statuspage == nil {
+			cfg.Statuspage = &StatuspageConfig{}
+		}
+		cfg.Statuspage.PageID = pageID
+	}
+
+	// Email environment variables
+	if os.Getenv("EMAIL_ENABLED") == "true" {
+		if cfg.Email == nil {
+			cfg.Email = &EmailConfig{}
+		}
+		cfg.Email.Enabled = true
+	}
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		if cfg.Email == nil {
+			cfg.Email = &EmailConfig{}
+		}
+		cfg.Email.Host = smtpHost
+	}
+	if smtpPort := os.Getenv("SMTP_PORT"); smtpPort != "" {
+		if cfg.Email == nil {
+			cfg.Email = &EmailConfig{}
+		}
+		if _, err := fmt.Sscanf(smtpPort, "%d", &cfg.Email.Port); err != nil {
+			log.Printf("config: invalid SMTP_PORT value, using default: %v", err)
+		}
+	}
+	if smtpUsername := os.Getenv("SMTP_USERNAME"); smtpUsername != "" {
+		if cfg.Email == nil {
+			cfg.Email = &EmailConfig{}
+		}
+		cfg.Email.Username = smtpUsername
+	}
+	if smtpPassword := os.Getenv("SMTP_PASSWORD"); smtpPassword != "" {
+		if cfg.Email == nil {
+			cfg.Email = &EmailConfig{}
+		}
+		cfg.Email.Password = smtpPassword
+	}
+	if from := os.Getenv("EMAIL_FROM"); from != "" {
+		if cfg.Email == nil {
+			cfg.Email = &EmailConfig{}
+		}
+		cfg.Email.From = from
+	}
+
+	// Webhook environment variables
+	if workers := os.Getenv("WEBHOOK_WORKERS"); workers != "" {
+		if _, err := fmt.Sscanf(workers, "%d", &cfg.Webhooks.Workers); err != nil {
+			log.Printf("config: invalid WEBHOOK_WORKERS value, using default: %v", err)
+		}
+	}
+	if queueSize := os.Getenv("WEBHOOK_QUEUE_SIZE"); queueSize != "" {
+		if _, err := fmt.Sscanf(queueSize, "%d", &cfg.Webhooks.QueueSize); err != nil {
+			log.Printf("config: invalid WEBHOOK_QUEUE_SIZE value, using default: %v", err)
+		}
+	}
+	if spoolDir := os.Getenv("WEBHOOK_SPOOL_DIR"); spoolDir != "" {
+		cfg.Webhooks.SpoolDir = spoolDir
+	}
+
+	// Metrics environment variables
+	if os.Getenv("METRICS_ENABLED") == "true" {
+		cfg.Metrics.Enabled = true
+	}
+	if path := os.Getenv("METRICS_PATH"); path != "" {
+		cfg.Metrics.Path = path
+	}
+
+	// Tracing environment variables
+	if os.Getenv("TRACING_ENABLED") == "true" {
+		cfg.Tracing.Enabled = true
+	}
+	if endpoint := os.Getenv("TRACING_ENDPOINT"); endpoint != "" {
+		cfg.Tracing.Endpoint = endpoint
+	}
+
+	// Alert sync environment variables
+	if os.Getenv("ALERT_SYNC_ENABLED") == "true" {
+		cfg.AlertSync.Enabled = true
+	}
+	if interval := os.Getenv("ALERT_SYNC_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			log.Printf("config: invalid ALERT_SYNC_INTERVAL value, using default: %v", err)
+		} else {
+			cfg.AlertSync.Interval = d
+		}
+	}
+
+	// Alert resolution environment variables
+	if os.Getenv("ALERT_RESOLUTION_ENABLED") == "true" {
+		cfg.AlertResolution.Enabled = true
+	}
+	if maxOpen := os.Getenv("ALERT_MAX_OPEN"); maxOpen != "" {
+		d, err := time.ParseDuration(maxOpen)
+		if err != nil {
+			log.Printf("config: invalid ALERT_MAX_OPEN value, using default: %v", err)
+		} else {
+			cfg.AlertResolution.MaxOpen = d
+		}
+	}
+	if os.Getenv("ALERT_RESOLVE_OUTAGES") == "true" {
+		cfg.AlertResolution.ResolveOutages = true
+	}
+
+	if os.Getenv("SIMILAR_OUTAGES_NOTE") == "true" {
+		cfg.SimilarOutages.Note = true
+	}
+
+	// Trash environment variables
+	if retention := os.Getenv("TRASH_RETENTION"); retention != "" {
+		d, err := time.ParseDuration(retention)
+		if err != nil {
+			log.Printf("config: invalid TRASH_RETENTION value, using default: %v", err)
+		} else {
+			cfg.Trash.Retention = d
+		}
+	}
+
+	// Team sync environment variables
+	if os.Getenv("TEAM_SYNC_ENABLED") == "true" {
+		cfg.TeamSync.Enabled = true
+	}
+
+	// Service catalogue environment variables
+	if file := os.Getenv("SERVICE_CATALOG_FILE"); file != "" {
+		cfg.ServiceCatalog.File = file
+	}
+	if url := os.Getenv("BACKSTAGE_URL"); url != "" {
+		cfg.ServiceCatalog.Backstage.URL = url
+	}
+	if token := os.Getenv("BACKSTAGE_TOKEN"); token != "" {
+		cfg.ServiceCatalog.Backstage.Token = token
+	}
+
+	// AI environment variables
+	if os.Getenv("AI_ENABLED") == "true" {
+		cfg.AI.Enabled = true
+	}
+	if baseURL := os.Getenv("AI_BASE_URL"); baseURL != "" {
+		cfg.AI.BaseURL = baseURL
+	}
+	if apiKey := os.Getenv("AI_API_KEY"); apiKey != "" {
+		cfg.AI.APIKey = apiKey
+	}
+	if model := os.Getenv("AI_MODEL"); model != "" {
+		cfg.AI.Model = model
+	}
+
+	// Attachment environment variables
+	if backend := os.Getenv("ATTACHMENTS_BACKEND"); backend != "" {
+		cfg.Attachments.Backend = backend
+	}
+	if dir := os.Getenv("ATTACHMENTS_DIR"); dir != "" {
+		cfg.Attachments.Dir = dir
+	}
+	if accessKeyID := os.Getenv("ATTACHMENTS_S3_ACCESS_KEY_ID"); accessKeyID != "" {
+		cfg.Attachments.S3.AccessKeyID = accessKeyID
+	}
+	if secretAccessKey := os.Getenv("ATTACHMENTS_S3_SECRET_ACCESS_KEY"); secretAccessKey != "" {
+		cfg.Attachments.S3.SecretAccessKey = secretAccessKey
+	}
+
+	// Update SLA environment variables
+	if os.Getenv("UPDATE_SLA_ENABLED") == "true" {
+		cfg.UpdateSLA.Enabled = true
+	}
+
+	// Digest environment variables
+	if os.Getenv("DIGESTS_ENABLED") == "true" {
+		cfg.Digests.Enabled = true
+	}
+
+	// Analytics export environment variables
+	if os.Getenv("ANALYTICS_EXPORT_ENABLED") == "true" {
+		cfg.AnalyticsExport.Enabled = true
+	}
+	if accessKeyID := os.Getenv("ANALYTICS_EXPORT_S3_ACCESS_KEY_ID"); accessKeyID != "" {
+		cfg.AnalyticsExport.S3.AccessKeyID = accessKeyID
+	}
+	if secretAccessKey := os.Getenv("ANALYTICS_EXPORT_S3_SECRET_ACCESS_KEY"); secretAccessKey != "" {
+		cfg.AnalyticsExport.S3.SecretAccessKey = secretAccessKey
+	}
+
+	// Warehouse environment variables
+	if os.Getenv("WAREHOUSE_ENABLED") == "true" {
+		cfg.Warehouse.Enabled = true
+	}
+	if driver := os.Getenv("WAREHOUSE_DRIVER"); driver != "" {
+		cfg.Warehouse.Driver = driver
+	}
+	if credentialsFile := os.Getenv("WAREHOUSE_BIGQUERY_CREDENTIALS_FILE"); credentialsFile != "" {
+		cfg.Warehouse.BigQuery.CredentialsFile = credentialsFile
+	}
+	if password := os.Getenv("WAREHOUSE_CLICKHOUSE_PASSWORD"); password != "" {
+		cfg.Warehouse.ClickHouse.Password = password
+	}
+
+	// Retention environment variables
+	if os.Getenv("RETENTION_ENABLED") == "true" {
+		cfg.Retention.Enabled = true
+	}
+	if accessKeyID := os.Getenv("RETENTION_S3_ACCESS_KEY_ID"); accessKeyID != "" {
+		cfg.Retention.S3.AccessKeyID = accessKeyID
+	}
+	if secretAccessKey := os.Getenv("RETENTION_S3_SECRET_ACCESS_KEY"); secretAccessKey != "" {
+		cfg.Retention.S3.SecretAccessKey = secretAccessKey
+	}
+
+	// Cache environment variables
+	if os.Getenv("CACHE_ENABLED") == "true" {
+		cfg.Cache.Enabled = true
+	}
+	if backend := os.Getenv("CACHE_BACKEND"); backend != "" {
+		cfg.Cache.Backend = backend
+	}
+	if addr := os.Getenv("CACHE_REDIS_ADDR"); addr != "" {
+		cfg.Cache.Redis.Addr = addr
+	}
+	if password := os.Getenv("CACHE_REDIS_PASSWORD"); password != "" {
+		cfg.Cache.Redis.Password = password
+	}
+
+	// Public status environment variables
+	if os.Getenv("PUBLIC_STATUS_ENABLED") == "true" {
+		cfg.PublicStatus.Enabled = true
+	}
+
+	// Source health environment variables
+	if os.Getenv("SOURCE_HEALTH_ENABLED") == "true" {
+		cfg.SourceHealth.Enabled = true
+	}
+
+	// Logging environment variables
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		cfg.Logging.Level = level
+	}
+	if format := os.Getenv("LOG_FORMAT"); format != "" {
+		cfg.Logging.Format = format
+	}
 
 	return &cfg, nil
 }
@@ -231,12 +1168,23 @@ func Default() *Config {
 			Port:    9090,
 		},
 		Database: DatabaseConfig{
-			Host:    "localhost",
-			Port:    5432,
-			User:    "outalator",
+			Host:     "localhost",
+			Port:     5432,
+			User:     "outalator",
 			Password: "outalator",
-			DBName:  "outalator",
-			SSLMode: "disable",
+			DBName:   "outalator",
+			SSLMode:  "disable",
+		},
+		Webhooks: WebhookConfig{
+			Workers:   4,
+			QueueSize: 1000,
+		},
+		Metrics: MetricsConfig{
+			Path: "/metrics",
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "text",
 		},
 	}
 }
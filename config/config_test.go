@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func writeConfig(t *testing.T, content string) string {
@@ -82,6 +84,10 @@ pagerduty:
   api_url: "https://pd.example.com"
 opsgenie:
   api_key: "og-key"
+  retry:
+    max_retries: 5
+    min_backoff: 1s
+    max_backoff: 1m
 `
 	path := writeConfig(t, yaml)
 	cfg, err := Load(path)
@@ -100,6 +106,12 @@ opsgenie:
 	if cfg.OpsGenie.APIKey != "og-key" {
 		t.Errorf("OpsGenie.APIKey = %q, want og-key", cfg.OpsGenie.APIKey)
 	}
+	if want := (RetryConfig{MaxRetries: 5, MinBackoff: time.Second, MaxBackoff: time.Minute}); cfg.OpsGenie.Retry != want {
+		t.Errorf("OpsGenie.Retry = %+v, want %+v", cfg.OpsGenie.Retry, want)
+	}
+	if cfg.PagerDuty.Retry != (RetryConfig{}) {
+		t.Errorf("PagerDuty.Retry = %+v, want unset for the client defaults", cfg.PagerDuty.Retry)
+	}
 }
 
 func TestLoadEnvOverrides(t *testing.T) {
@@ -144,6 +156,7 @@ func TestLoadEnvPagerDutyKey(t *testing.T) {
 	path := writeConfig(t, yaml)
 
 	t.Setenv("PAGERDUTY_API_KEY", "env-pd-key")
+	t.Setenv("PAGERDUTY_FROM", "bot@example.com")
 	t.Setenv("OPSGENIE_API_KEY", "env-og-key")
 
 	cfg, err := Load(path)
@@ -153,6 +166,9 @@ func TestLoadEnvPagerDutyKey(t *testing.T) {
 	if cfg.PagerDuty == nil || cfg.PagerDuty.APIKey != "env-pd-key" {
 		t.Errorf("PagerDuty.APIKey = %v, want env-pd-key", cfg.PagerDuty)
 	}
+	if cfg.PagerDuty == nil || cfg.PagerDuty.From != "bot@example.com" {
+		t.Errorf("PagerDuty.From = %v, want bot@example.com", cfg.PagerDuty)
+	}
 	if cfg.OpsGenie == nil || cfg.OpsGenie.APIKey != "env-og-key" {
 		t.Errorf("OpsGenie.APIKey = %v, want env-og-key", cfg.OpsGenie)
 	}
@@ -167,6 +183,19 @@ auth:
   client_id: "my-client"
   client_secret: "my-secret"
   redirect_url: "https://app.example.com/callback"
+  anonymous_read: true
+  routes:
+    events: required
+  groups_claim: realm_access.roles
+  groups:
+    sre-leads:
+      roles: [admin]
+      teams: [sre]
+  service_tokens:
+    - name: github-actions
+      issuer: "https://token.actions.githubusercontent.com"
+      audience: outalator
+  bearer_id_tokens: true
 `
 	path := writeConfig(t, yaml)
 	cfg, err := Load(path)
@@ -185,6 +214,21 @@ auth:
 	if cfg.Auth.ClientID != "my-client" {
 		t.Errorf("Auth.ClientID = %q", cfg.Auth.ClientID)
 	}
+	if !cfg.Auth.AnonymousRead || cfg.Auth.Routes["events"] != "required" {
+		t.Errorf("Auth anonymous read = %t, routes = %v; want true and events required", cfg.Auth.AnonymousRead, cfg.Auth.Routes)
+	}
+	if cfg.Auth.GroupsClaim != "realm_access.roles" {
+		t.Errorf("Auth.GroupsClaim = %q", cfg.Auth.GroupsClaim)
+	}
+	if m := cfg.Auth.Groups["sre-leads"]; len(m.Roles) != 1 || m.Roles[0] != "admin" || len(m.Teams) != 1 || m.Teams[0] != "sre" {
+		t.Errorf("Auth.Groups[sre-leads] = %+v, want admin role and sre team", m)
+	}
+	if len(cfg.Auth.ServiceTokens) != 1 || cfg.Auth.ServiceTokens[0].Audience != "outalator" {
+		t.Errorf("Auth.ServiceTokens = %+v, want the github-actions issuer", cfg.Auth.ServiceTokens)
+	}
+	if !cfg.Auth.BearerIDTokens {
+		t.Error("Auth.BearerIDTokens should be true")
+	}
 }
 
 func TestLoadAuthEnvOverrides(t *testing.T) {
@@ -196,6 +240,11 @@ func TestLoadAuthEnvOverrides(t *testing.T) {
 	t.Setenv("AUTH_CLIENT_ID", "env-client")
 	t.Setenv("AUTH_CLIENT_SECRET", "env-secret")
 	t.Setenv("AUTH_REDIRECT_URL", "https://env-app.com/cb")
+	t.Setenv("AUTH_ANONYMOUS_READ", "true")
+	t.Setenv("AUTH_GROUPS_CLAIM", "roles")
+	t.Setenv("AUTH_SERVICE_TOKEN_ISSUER", "https://ci.example.com")
+	t.Setenv("AUTH_SERVICE_TOKEN_AUDIENCE", "outalator")
+	t.Setenv("AUTH_BEARER_ID_TOKENS", "true")
 
 	cfg, err := Load(path)
 	if err != nil {
@@ -210,6 +259,18 @@ func TestLoadAuthEnvOverrides(t *testing.T) {
 	if cfg.Auth.Issuer != "https://env-issuer.com" {
 		t.Errorf("Auth.Issuer = %q, want https://env-issuer.com", cfg.Auth.Issuer)
 	}
+	if !cfg.Auth.AnonymousRead {
+		t.Error("Auth.AnonymousRead should be true")
+	}
+	if cfg.Auth.GroupsClaim != "roles" {
+		t.Errorf("Auth.GroupsClaim = %q, want roles", cfg.Auth.GroupsClaim)
+	}
+	if want := (ServiceTokenConfig{Name: "env", Issuer: "https://ci.example.com", Audience: "outalator"}); len(cfg.Auth.ServiceTokens) != 1 || cfg.Auth.ServiceTokens[0] != want {
+		t.Errorf("Auth.ServiceTokens = %+v, want %+v", cfg.Auth.ServiceTokens, want)
+	}
+	if !cfg.Auth.BearerIDTokens {
+		t.Error("Auth.BearerIDTokens should be true")
+	}
 }
 
 func TestLoadMissingFile(t *testing.T) {
@@ -253,6 +314,79 @@ slack:
 	if cfg.Slack.ReactionEmoji != "outage_note" {
 		t.Errorf("Slack.ReactionEmoji = %q", cfg.Slack.ReactionEmoji)
 	}
+	if cfg.Slack.ArchiveChannelMessages {
+		t.Error("Slack.ArchiveChannelMessages should default to false")
+	}
+}
+
+func TestLoadMailGatewayConfig(t *testing.T) {
+	yaml := `
+server: {port: 8080}
+mail_gateway:
+  enabled: true
+  smtp_addr: ":2525"
+  rules:
+    - name: nagios
+      from: 'nagios@example\.com'
+      subject: '^\*\* (?P<type>PROBLEM|RECOVERY)'
+      external_id: '${host}/${service}'
+      resolve: RECOVERY
+`
+	path := writeConfig(t, yaml)
+	t.Setenv("MAILGUN_SIGNING_KEY", "mg-key")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	mg := cfg.MailGateway
+	if mg == nil || !mg.Enabled {
+		t.Fatalf("MailGateway = %+v, want enabled", mg)
+	}
+	if mg.SMTPAddr != ":2525" || mg.MailgunSigningKey != "mg-key" {
+		t.Errorf("MailGateway = %+v", mg)
+	}
+	if len(mg.Rules) != 1 || mg.Rules[0].Name != "nagios" || mg.Rules[0].ExternalID != "${host}/${service}" || mg.Rules[0].Resolve != "RECOVERY" {
+		t.Errorf("MailGateway.Rules = %+v", mg.Rules)
+	}
+}
+
+func TestLoadMockConfig(t *testing.T) {
+	path := writeConfig(t, `
+mock:
+  enabled: true
+  source: demo
+  fixture: fixtures/alerts.yaml
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Mock == nil || !cfg.Mock.Enabled || cfg.Mock.Source != "demo" || cfg.Mock.Fixture != "fixtures/alerts.yaml" {
+		t.Errorf("Mock = %+v, want enabled demo source", cfg.Mock)
+	}
+
+	t.Setenv("MOCK_PROVIDER_FIXTURE", "/tmp/fixture.yaml")
+	cfg, err = Load(writeConfig(t, `server: {port: 8080}`))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Mock == nil || !cfg.Mock.Enabled || cfg.Mock.Fixture != "/tmp/fixture.yaml" {
+		t.Errorf("Mock = %+v, want enabled from env", cfg.Mock)
+	}
+}
+
+func TestSlackArchiveEnvOverride(t *testing.T) {
+	path := writeConfig(t, `server: {port: 8080}`)
+	t.Setenv("SLACK_ARCHIVE_CHANNEL_MESSAGES", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Slack == nil || !cfg.Slack.ArchiveChannelMessages {
+		t.Error("Slack.ArchiveChannelMessages should be true")
+	}
 }
 
 func TestGRPCEnvOverride(t *testing.T) {
@@ -277,3 +411,855 @@ func TestGRPCEnvOverride(t *testing.T) {
 		t.Errorf("GRPC.Port = %d, want 9191", cfg.GRPC.Port)
 	}
 }
+
+func TestLoadWebhookConfig(t *testing.T) {
+	yaml := `
+server: {port: 8080}
+webhooks:
+  workers: 8
+  queue_size: 500
+`
+	path := writeConfig(t, yaml)
+
+	t.Setenv("WEBHOOK_QUEUE_SIZE", "2000")
+	t.Setenv("WEBHOOK_SPOOL_DIR", "/var/spool/outalator")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Webhooks.Workers != 8 {
+		t.Errorf("Webhooks.Workers = %d, want 8", cfg.Webhooks.Workers)
+	}
+	if cfg.Webhooks.QueueSize != 2000 {
+		t.Errorf("Webhooks.QueueSize = %d, want 2000", cfg.Webhooks.QueueSize)
+	}
+	if cfg.Webhooks.SpoolDir != "/var/spool/outalator" {
+		t.Errorf("Webhooks.SpoolDir = %q, want /var/spool/outalator", cfg.Webhooks.SpoolDir)
+	}
+}
+
+func TestLoadCustomFieldSchemas(t *testing.T) {
+	yaml := `
+server: {port: 8080}
+custom_fields:
+  outage:
+    fields:
+      - name: impact
+        type: string
+        required: true
+        allowed_values: [partial, full]
+  note:
+    allow_unknown: true
+    fields:
+      - name: minutes
+        type: number
+`
+	path := writeConfig(t, yaml)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	outage := cfg.CustomFields["outage"]
+	if len(outage.Fields) != 1 || outage.Fields[0].Name != "impact" || !outage.Fields[0].Required {
+		t.Fatalf("outage schema = %+v", outage)
+	}
+	if got := outage.Fields[0].AllowedValues; len(got) != 2 || got[1] != "full" {
+		t.Errorf("AllowedValues = %v, want [partial full]", got)
+	}
+	if !cfg.CustomFields["note"].AllowUnknown {
+		t.Error("note schema should allow unknown fields")
+	}
+	if err := cfg.CustomFields.Check(); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+}
+
+func TestLoadMetricsConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+metrics:
+  enabled: false
+  path: /internal/metrics
+`)
+
+	t.Setenv("METRICS_ENABLED", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Metrics.Enabled {
+		t.Error("Metrics.Enabled = false, want true from METRICS_ENABLED")
+	}
+	if cfg.Metrics.Path != "/internal/metrics" {
+		t.Errorf("Metrics.Path = %q, want /internal/metrics", cfg.Metrics.Path)
+	}
+}
+
+func TestLoadTracingConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+tracing:
+  enabled: true
+  endpoint: collector:4317
+  insecure: true
+  sample_ratio: 0.25
+`)
+
+	t.Setenv("TRACING_ENDPOINT", "otel.internal:4317")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Tracing.Enabled || !cfg.Tracing.Insecure {
+		t.Errorf("Tracing = %+v, want enabled and insecure", cfg.Tracing)
+	}
+	if cfg.Tracing.Endpoint != "otel.internal:4317" {
+		t.Errorf("Tracing.Endpoint = %q, want otel.internal:4317", cfg.Tracing.Endpoint)
+	}
+	if cfg.Tracing.SampleRatio != 0.25 {
+		t.Errorf("Tracing.SampleRatio = %v, want 0.25", cfg.Tracing.SampleRatio)
+	}
+}
+
+func TestLoadReviewConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+reviews:
+  reminder_channel: "#postmortems"
+  reminder_after: 48h
+  reminder_interval: 12h
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Reviews.ReminderChannel != "#postmortems" {
+		t.Errorf("Reviews.ReminderChannel = %q", cfg.Reviews.ReminderChannel)
+	}
+	if cfg.Reviews.ReminderAfter != 48*time.Hour || cfg.Reviews.ReminderInterval != 12*time.Hour {
+		t.Errorf("Reviews = %+v, want 48h after / 12h interval", cfg.Reviews)
+	}
+}
+
+func TestLoadSeverityMapping(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+severity_mapping:
+  opsgenie: {P1: critical, P5: low}
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.SeverityMapping.Normalize("opsgenie", "P1"); got != "critical" {
+		t.Errorf("Normalize(opsgenie, P1) = %q, want critical", got)
+	}
+	if got := cfg.SeverityMapping.Normalize("pagerduty", "high"); got != "high" {
+		t.Errorf("unmapped source should pass through, got %q", got)
+	}
+}
+
+func TestLoadOutageTransitions(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+outage_transitions:
+  - action: resolve
+    from: [open, investigating]
+    to: resolved
+  - action: reopen
+    from: [resolved]
+    to: open
+    explicit: true
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.OutageTransitions) != 2 {
+		t.Fatalf("OutageTransitions = %+v, want 2 transitions", cfg.OutageTransitions)
+	}
+	reopen := cfg.OutageTransitions[1]
+	if reopen.Action != "reopen" || reopen.To != "open" || !reopen.Explicit || len(reopen.From) != 1 {
+		t.Errorf("reopen transition = %+v", reopen)
+	}
+}
+
+func TestLoadLoggingConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+logging:
+  level: debug
+  format: text
+`)
+
+	t.Setenv("LOG_FORMAT", "json")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("Logging.Level = %q, want debug", cfg.Logging.Level)
+	}
+	if cfg.Logging.Format != "json" {
+		t.Errorf("Logging.Format = %q, want json from LOG_FORMAT", cfg.Logging.Format)
+	}
+}
+
+func TestLoadAlertSyncConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+alert_sync:
+  interval: 10m
+  lookback: 2h
+  initial_lookback: 168h
+`)
+
+	t.Setenv("ALERT_SYNC_ENABLED", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.AlertSync.Enabled {
+		t.Error("AlertSync.Enabled = false, want true from ALERT_SYNC_ENABLED")
+	}
+	if cfg.AlertSync.Interval != 10*time.Minute {
+		t.Errorf("AlertSync.Interval = %v, want 10m", cfg.AlertSync.Interval)
+	}
+	if cfg.AlertSync.Lookback != 2*time.Hour {
+		t.Errorf("AlertSync.Lookback = %v, want 2h", cfg.AlertSync.Lookback)
+	}
+	if cfg.AlertSync.InitialLookback != 168*time.Hour {
+		t.Errorf("AlertSync.InitialLookback = %v, want 168h", cfg.AlertSync.InitialLookback)
+	}
+}
+
+func TestLoadAlertResolutionConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+alert_resolution:
+  enabled: true
+  max_open: 72h
+  interval: 30m
+`)
+
+	t.Setenv("ALERT_MAX_OPEN", "48h")
+	t.Setenv("ALERT_RESOLVE_OUTAGES", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.AlertResolution.Enabled {
+		t.Error("AlertResolution.Enabled = false, want true")
+	}
+	if cfg.AlertResolution.MaxOpen != 48*time.Hour {
+		t.Errorf("AlertResolution.MaxOpen = %v, want 48h from ALERT_MAX_OPEN", cfg.AlertResolution.MaxOpen)
+	}
+	if !cfg.AlertResolution.ResolveOutages {
+		t.Error("AlertResolution.ResolveOutages = false, want true from ALERT_RESOLVE_OUTAGES")
+	}
+	if cfg.AlertResolution.Interval != 30*time.Minute {
+		t.Errorf("AlertResolution.Interval = %v, want 30m", cfg.AlertResolution.Interval)
+	}
+}
+
+func TestLoadDigestConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+digests:
+  check_interval: 10m
+  schedules:
+    - name: payments-weekly
+      team: payments
+      period: weekly
+      weekday: friday
+      hour: 16
+      timezone: Europe/Dublin
+      slack_channel: "#payments"
+    - name: ops-daily
+      period: daily
+      hour: 9
+      emails: [ops@example.com]
+      template: "{{len .Open}} open outages"
+`)
+
+	t.Setenv("DIGESTS_ENABLED", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Digests.Enabled || cfg.Digests.CheckInterval != 10*time.Minute {
+		t.Errorf("Digests = %+v", cfg.Digests)
+	}
+	if len(cfg.Digests.Schedules) != 2 {
+		t.Fatalf("Schedules = %+v, want 2", cfg.Digests.Schedules)
+	}
+	weekly, daily := cfg.Digests.Schedules[0], cfg.Digests.Schedules[1]
+	if weekly.Team != "payments" || weekly.Weekday != "friday" || weekly.Hour != 16 ||
+		weekly.Timezone != "Europe/Dublin" || weekly.SlackChannel != "#payments" {
+		t.Errorf("weekly schedule = %+v", weekly)
+	}
+	if daily.Period != "daily" || len(daily.Emails) != 1 || daily.Template != "{{len .Open}} open outages" {
+		t.Errorf("daily schedule = %+v", daily)
+	}
+}
+
+func TestLoadSimilarOutagesConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+similar_outages:
+  min_score: 0.5
+  limit: 3
+`)
+
+	t.Setenv("SIMILAR_OUTAGES_NOTE", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.SimilarOutages.Note {
+		t.Error("SimilarOutages.Note = false, want true from SIMILAR_OUTAGES_NOTE")
+	}
+	if cfg.SimilarOutages.MinScore != 0.5 || cfg.SimilarOutages.Limit != 3 {
+		t.Errorf("SimilarOutages = %+v, want min score 0.5 and limit 3", cfg.SimilarOutages)
+	}
+}
+
+func TestLoadTrashConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+auth:
+  admins: [alice@example.com]
+trash:
+  retention: 720h
+  purge_interval: 2h
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Trash.Retention != 720*time.Hour {
+		t.Errorf("Trash.Retention = %v, want 720h", cfg.Trash.Retention)
+	}
+	if cfg.Trash.PurgeInterval != 2*time.Hour {
+		t.Errorf("Trash.PurgeInterval = %v, want 2h", cfg.Trash.PurgeInterval)
+	}
+	if len(cfg.Auth.Admins) != 1 || cfg.Auth.Admins[0] != "alice@example.com" {
+		t.Errorf("Auth.Admins = %v, want [alice@example.com]", cfg.Auth.Admins)
+	}
+
+	t.Setenv("TRASH_RETENTION", "24h")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Trash.Retention != 24*time.Hour {
+		t.Errorf("Trash.Retention = %v, want 24h from TRASH_RETENTION", cfg.Trash.Retention)
+	}
+}
+
+func TestLoadTeamSyncConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+team_sync:
+  interval: 30m
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.TeamSync.Enabled || cfg.TeamSync.Interval != 30*time.Minute {
+		t.Errorf("TeamSync = %+v, want disabled with a 30m interval", cfg.TeamSync)
+	}
+
+	t.Setenv("TEAM_SYNC_ENABLED", "true")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.TeamSync.Enabled {
+		t.Error("TeamSync.Enabled = false, want true from TEAM_SYNC_ENABLED")
+	}
+}
+
+func TestLoadServiceCatalogConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+service_catalog:
+  file: /etc/outalator/services.yaml
+  interval: 15m
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ServiceCatalog.File != "/etc/outalator/services.yaml" || cfg.ServiceCatalog.Interval != 15*time.Minute {
+		t.Errorf("ServiceCatalog = %+v, want the file with a 15m interval", cfg.ServiceCatalog)
+	}
+
+	t.Setenv("BACKSTAGE_URL", "https://backstage.example.com")
+	t.Setenv("BACKSTAGE_TOKEN", "secret")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if b := cfg.ServiceCatalog.Backstage; b.URL != "https://backstage.example.com" || b.Token != "secret" {
+		t.Errorf("Backstage = %+v, want the URL and token from the environment", b)
+	}
+}
+
+func TestLoadAIConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+ai:
+  enabled: true
+  base_url: http://ollama:11434/v1
+  model: llama3.1
+  timeout: 90s
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if a := cfg.AI; !a.Enabled || a.BaseURL != "http://ollama:11434/v1" || a.Model != "llama3.1" || a.Timeout != 90*time.Second {
+		t.Errorf("AI = %+v", a)
+	}
+
+	t.Setenv("AI_API_KEY", "secret")
+	t.Setenv("AI_MODEL", "gpt-4o-mini")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.AI.APIKey != "secret" || cfg.AI.Model != "gpt-4o-mini" {
+		t.Errorf("AI = %+v, want the key and model from the environment", cfg.AI)
+	}
+}
+
+func TestLoadAttachmentConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+attachments:
+  backend: s3
+  max_bytes: 10485760
+  allowed_types: [image/png, text/plain]
+  s3:
+    endpoint: http://minio:9000
+    bucket: outalator
+    prefix: attachments/
+    path_style: true
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	a := cfg.Attachments
+	if a.Backend != "s3" || a.MaxBytes != 10<<20 || len(a.AllowedTypes) != 2 {
+		t.Errorf("Attachments = %+v", a)
+	}
+	if a.S3.Endpoint != "http://minio:9000" || a.S3.Bucket != "outalator" || a.S3.Prefix != "attachments/" || !a.S3.PathStyle {
+		t.Errorf("Attachments.S3 = %+v", a.S3)
+	}
+
+	t.Setenv("ATTACHMENTS_S3_ACCESS_KEY_ID", "minio")
+	t.Setenv("ATTACHMENTS_S3_SECRET_ACCESS_KEY", "minio-secret")
+	t.Setenv("ATTACHMENTS_BACKEND", "local")
+	t.Setenv("ATTACHMENTS_DIR", "/var/lib/outalator/attachments")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	a = cfg.Attachments
+	if a.Backend != "local" || a.Dir != "/var/lib/outalator/attachments" {
+		t.Errorf("Backend, Dir = %q, %q; want local from ATTACHMENTS_BACKEND and ATTACHMENTS_DIR", a.Backend, a.Dir)
+	}
+	if a.S3.AccessKeyID != "minio" || a.S3.SecretAccessKey != "minio-secret" {
+		t.Errorf("S3 credentials = %q, %q; want values from the environment", a.S3.AccessKeyID, a.S3.SecretAccessKey)
+	}
+}
+
+func TestLoadAnalyticsExportConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+analytics_export:
+  format: csv
+  tables: [outages, alerts]
+  interval: 6h
+  backend: s3
+  s3:
+    endpoint: https://storage.googleapis.com
+    bucket: incident-data
+    prefix: outalator/
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	e := cfg.AnalyticsExport
+	if e.Enabled || e.Format != "csv" || len(e.Tables) != 2 || e.Interval != 6*time.Hour || e.Backend != "s3" {
+		t.Errorf("AnalyticsExport = %+v", e)
+	}
+	if e.S3.Endpoint != "https://storage.googleapis.com" || e.S3.Bucket != "incident-data" || e.S3.Prefix != "outalator/" {
+		t.Errorf("AnalyticsExport.S3 = %+v", e.S3)
+	}
+
+	t.Setenv("ANALYTICS_EXPORT_ENABLED", "true")
+	t.Setenv("ANALYTICS_EXPORT_S3_ACCESS_KEY_ID", "GOOG1EXAMPLE")
+	t.Setenv("ANALYTICS_EXPORT_S3_SECRET_ACCESS_KEY", "hmac-secret")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	e = cfg.AnalyticsExport
+	if !e.Enabled || e.S3.AccessKeyID != "GOOG1EXAMPLE" || e.S3.SecretAccessKey != "hmac-secret" {
+		t.Errorf("AnalyticsExport = %+v, want enabled with credentials from the environment", e)
+	}
+}
+
+func TestLoadRetentionConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+retention:
+  archive_after: 17520h
+  purge_after: 43800h
+  backend: local
+  dir: /var/lib/outalator/archive
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	r := cfg.Retention
+	if r.Enabled || r.ArchiveAfter != 2*365*24*time.Hour || r.PurgeAfter != 5*365*24*time.Hour || r.Backend != "local" || r.Dir != "/var/lib/outalator/archive" {
+		t.Errorf("Retention = %+v", r)
+	}
+
+	t.Setenv("RETENTION_ENABLED", "true")
+	t.Setenv("RETENTION_S3_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	t.Setenv("RETENTION_S3_SECRET_ACCESS_KEY", "s3-secret")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	r = cfg.Retention
+	if !r.Enabled || r.S3.AccessKeyID != "AKIAEXAMPLE" || r.S3.SecretAccessKey != "s3-secret" {
+		t.Errorf("Retention = %+v, want enabled with credentials from the environment", r)
+	}
+}
+
+func TestLoadWarehouseConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+warehouse:
+  enabled: true
+  driver: clickhouse
+  flush_interval: 10s
+  batch_size: 200
+  clickhouse:
+    url: http://clickhouse:8123
+    database: incidents
+    table: outage_events
+    username: outalator
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	w := cfg.Warehouse
+	if !w.Enabled || w.Driver != "clickhouse" || w.FlushInterval != 10*time.Second || w.BatchSize != 200 {
+		t.Errorf("Warehouse = %+v", w)
+	}
+	if c := w.ClickHouse; c.URL != "http://clickhouse:8123" || c.Database != "incidents" || c.Table != "outage_events" || c.Username != "outalator" {
+		t.Errorf("Warehouse.ClickHouse = %+v", c)
+	}
+
+	t.Setenv("WAREHOUSE_DRIVER", "bigquery")
+	t.Setenv("WAREHOUSE_BIGQUERY_CREDENTIALS_FILE", "/etc/outalator/bigquery.json")
+	t.Setenv("WAREHOUSE_CLICKHOUSE_PASSWORD", "secret")
+	if cfg, err = Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	w = cfg.Warehouse
+	if w.Driver != "bigquery" || w.BigQuery.CredentialsFile != "/etc/outalator/bigquery.json" || w.ClickHouse.Password != "secret" {
+		t.Errorf("Warehouse = %+v, want the driver and credentials from the environment", w)
+	}
+}
+
+func TestLoadUpdateSLAConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+update_sla:
+  intervals:
+    critical: 30m
+    high: 1h
+  reminder_channel: "#incidents"
+`)
+
+	t.Setenv("UPDATE_SLA_ENABLED", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.UpdateSLA.Enabled {
+		t.Error("UpdateSLA.Enabled = false, want true from UPDATE_SLA_ENABLED")
+	}
+	if cfg.UpdateSLA.Intervals["critical"] != 30*time.Minute || cfg.UpdateSLA.Intervals["high"] != time.Hour {
+		t.Errorf("UpdateSLA.Intervals = %v, want critical 30m and high 1h", cfg.UpdateSLA.Intervals)
+	}
+	if cfg.UpdateSLA.ReminderChannel != "#incidents" {
+		t.Errorf("UpdateSLA.ReminderChannel = %q, want #incidents", cfg.UpdateSLA.ReminderChannel)
+	}
+}
+
+func TestLoadSourceHealthConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+source_health:
+  default_max_silence: 24h
+  max_silence:
+    pagerduty: 6h
+  alarm_channel: "#ops"
+`)
+
+	t.Setenv("SOURCE_HEALTH_ENABLED", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.SourceHealth.Enabled {
+		t.Error("SourceHealth.Enabled = false, want true from SOURCE_HEALTH_ENABLED")
+	}
+	if cfg.SourceHealth.DefaultMaxSilence != 24*time.Hour || cfg.SourceHealth.MaxSilence["pagerduty"] != 6*time.Hour {
+		t.Errorf("SourceHealth = %+v, want default 24h and pagerduty 6h", cfg.SourceHealth)
+	}
+	if cfg.SourceHealth.AlarmChannel != "#ops" {
+		t.Errorf("SourceHealth.AlarmChannel = %q, want #ops", cfg.SourceHealth.AlarmChannel)
+	}
+}
+
+func TestLoadJiraConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+jira:
+  enabled: true
+  url: https://example.atlassian.net
+  email: bot@example.com
+  project_key: OPS
+  priorities:
+    critical: P1
+  status_sync_interval: 30m
+`)
+
+	t.Setenv("JIRA_API_TOKEN", "secret")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Jira == nil || !cfg.Jira.Enabled {
+		t.Fatalf("Jira = %+v, want enabled", cfg.Jira)
+	}
+	if cfg.Jira.APIToken != "secret" {
+		t.Errorf("Jira.APIToken = %q, want value from JIRA_API_TOKEN", cfg.Jira.APIToken)
+	}
+	if cfg.Jira.ProjectKey != "OPS" || cfg.Jira.Priorities["critical"] != "P1" {
+		t.Errorf("Jira = %+v", cfg.Jira)
+	}
+	if cfg.Jira.StatusSyncInterval != 30*time.Minute {
+		t.Errorf("Jira.StatusSyncInterval = %v, want 30m", cfg.Jira.StatusSyncInterval)
+	}
+}
+
+func TestLoadGitHubConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+github:
+  enabled: true
+  repository: acme/ops
+  labels: [outage-follow-up]
+`)
+
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.GitHub == nil || !cfg.GitHub.Enabled {
+		t.Fatalf("GitHub = %+v, want enabled", cfg.GitHub)
+	}
+	if cfg.GitHub.Token != "ghp_secret" {
+		t.Errorf("GitHub.Token = %q, want value from GITHUB_TOKEN", cfg.GitHub.Token)
+	}
+	if cfg.GitHub.Repository != "acme/ops" || len(cfg.GitHub.Labels) != 1 {
+		t.Errorf("GitHub = %+v", cfg.GitHub)
+	}
+}
+
+func TestLoadEmailConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+email:
+  enabled: true
+  host: smtp.example.com
+  from: outalator@example.com
+`)
+
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("SMTP_PASSWORD", "secret")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Email == nil || !cfg.Email.Enabled {
+		t.Fatalf("Email = %+v, want enabled", cfg.Email)
+	}
+	if cfg.Email.Port != 2525 || cfg.Email.Password != "secret" {
+		t.Errorf("Email = %+v, want port and password from environment", cfg.Email)
+	}
+	if cfg.Email.Host != "smtp.example.com" || cfg.Email.From != "outalator@example.com" {
+		t.Errorf("Email = %+v", cfg.Email)
+	}
+}
+
+func TestLoadStatuspageConfig(t *testing.T) {
+	path := writeConfig(t, `
+server: {port: 8080}
+statuspage:
+  enabled: true
+  page_id: page1
+  components:
+    api: comp-api
+`)
+
+	t.Setenv("STATUSPAGE_API_KEY", "secret")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Statuspage == nil || !cfg.Statuspage.Enabled {
+		t.Fatalf("Statuspage = %+v, want enabled", cfg.Statuspage)
+	}
+	if cfg.Statuspage.APIKey != "secret" {
+		t.Errorf("Statuspage.APIKey = %q, want value from STATUSPAGE_API_KEY", cfg.Statuspage.APIKey)
+	}
+	if cfg.Statuspage.PageID != "page1" || cfg.Statuspage.Components["api"] != "comp-api" {
+		t.Errorf("Statuspage = %+v", cfg.Statuspage)
+	}
+}
+
+func TestLoadBodyLimits(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  port: 8080
+  body_limits:
+    default: 2048
+    attachment: 10485760
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	limits := cfg.Server.BodyLimits
+	if limits.Default != 2048 || limits.Attachment != 10485760 || limits.Webhook != 0 {
+		t.Errorf("BodyLimits = %+v", limits)
+	}
+}
+
+func TestLoadTLS(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  tls:
+    cert_file: /etc/outalator/tls.crt
+    key_file: /etc/outalator/tls.key
+grpc:
+  enabled: true
+  tls:
+    cert_file: /etc/outalator/grpc.crt
+    key_file: /etc/outalator/grpc.key
+`)
+	t.Setenv("GRPC_TLS_CLIENT_CA_FILE", "/etc/outalator/clients.pem")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := (TLSConfig{CertFile: "/etc/outalator/tls.crt", KeyFile: "/etc/outalator/tls.key"}); cfg.Server.TLS != want {
+		t.Errorf("Server.TLS = %+v, want %+v", cfg.Server.TLS, want)
+	}
+	if want := (TLSConfig{CertFile: "/etc/outalator/grpc.crt", KeyFile: "/etc/outalator/grpc.key", ClientCAFile: "/etc/outalator/clients.pem"}); cfg.GRPC.TLS != want {
+		t.Errorf("GRPC.TLS = %+v, want %+v", cfg.GRPC.TLS, want)
+	}
+}
+
+func TestLoadGRPCAuth(t *testing.T) {
+	path := writeConfig(t, `
+grpc:
+  enabled: true
+  disable_validation: true
+  auth:
+    oidc: true
+    api_keys:
+      - name: ci
+        key: ci-secret
+`)
+	t.Setenv("GRPC_AUTH_ENABLED", "true")
+	t.Setenv("GRPC_AUTH_API_KEY", "env-secret")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	auth := cfg.GRPC.Auth
+	if !auth.Enabled || !auth.OIDC {
+		t.Errorf("Auth = %+v, want enabled with OIDC", auth)
+	}
+	want := []GRPCAPIKey{{Name: "ci", Key: "ci-secret"}, {Name: "env", Key: "env-secret"}}
+	if !slices.Equal(auth.APIKeys, want) {
+		t.Errorf("APIKeys = %+v, want %+v", auth.APIKeys, want)
+	}
+	if !cfg.GRPC.DisableValidation || cfg.GRPC.DisableRecovery {
+		t.Errorf("DisableValidation = %v, DisableRecovery = %v", cfg.GRPC.DisableValidation, cfg.GRPC.DisableRecovery)
+	}
+}
+
+func TestLoadDatabasePoolConfig(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  host: dbhost
+  max_open_conns: 40
+  max_idle_conns: 10
+  conn_max_lifetime: 1h
+  statement_timeout: 5s
+`)
+	t.Setenv("DB_STATEMENT_TIMEOUT", "15s")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	db := cfg.Database
+	if db.MaxOpenConns != 40 || db.MaxIdleConns != 10 || db.ConnMaxLifetime != time.Hour {
+		t.Errorf("Database pool = %+v", db)
+	}
+	if db.StatementTimeout != 15*time.Second {
+		t.Errorf("Database.StatementTimeout = %v, want value from DB_STATEMENT_TIMEOUT", db.StatementTimeout)
+	}
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteMetadataActionItem marks a note as a follow-up action item when its
+// metadata value is "true". Action items can be pushed to an issue tracker.
+const NoteMetadataActionItem = "action_item"
+
+// IsActionItem reports whether the note is marked as an action item
+func (n *Note) IsActionItem() bool {
+	return n.Metadata[NoteMetadataActionItem] == "true"
+}
+
+// Action item statuses. Done and cancelled items are closed.
+const (
+	ActionItemOpen       = "open"
+	ActionItemInProgress = "in_progress"
+	ActionItemDone       = "done"
+	ActionItemCancelled  = "cancelled"
+)
+
+// ActionItemStatuses lists the statuses an action item can have
+var ActionItemStatuses = []string{ActionItemOpen, ActionItemInProgress, ActionItemDone, ActionItemCancelled}
+
+// ActionItem is a follow-up task attached to an outage, tracked until it is
+// done or cancelled. Action items are removed along with their outage.
+type ActionItem struct {
+	ID          uuid.UUID  `json:"id"`
+	OutageID    uuid.UUID  `json:"outage_id"`
+	Description string     `json:"description"`
+	Assignee    string     `json:"assignee,omitempty"` // Email or name of whoever owns the task
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Status      string     `json:"status"`               // open, in_progress, done or cancelled
+	CreatedBy   string     `json:"created_by,omitempty"` // Email of the user who added the item
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"` // When the item was last done or cancelled
+}
+
+// Closed reports whether the action item is done or cancelled
+func (a *ActionItem) Closed() bool {
+	return a.Status == ActionItemDone || a.Status == ActionItemCancelled
+}
+
+// Overdue reports whether the action item is still open after its due date
+func (a *ActionItem) Overdue(now time.Time) bool {
+	return !a.Closed() && a.DueDate != nil && a.DueDate.Before(now)
+}
+
+// CreateActionItemRequest represents the data needed to add an action item
+// to an outage
+type CreateActionItemRequest struct {
+	Description string     `json:"description"`
+	Assignee    string     `json:"assignee,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Status      string     `json:"status,omitempty"` // Default open
+}
+
+// UpdateActionItemRequest represents a change to an action item. Unset
+// fields are left alone.
+type UpdateActionItemRequest struct {
+	Description  *string    `json:"description,omitempty"`
+	Assignee     *string    `json:"assignee,omitempty"` // An empty string unassigns the item
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	ClearDueDate bool       `json:"clear_due_date,omitempty"`
+	Status       *string    `json:"status,omitempty"`
+}
+
+// OverdueActionItems reports the open action items past their due date,
+// grouped by the team owning their outage
+type OverdueActionItems struct {
+	GeneratedAt time.Time                `json:"generated_at"`
+	Total       int                      `json:"total"`
+	Teams       []TeamOverdueActionItems `json:"teams"` // Sorted by team name, unowned outages first
+}
+
+// TeamOverdueActionItems lists one team's overdue action items, most
+// overdue first
+type TeamOverdueActionItems struct {
+	Team  string              `json:"team"` // Empty for outages no team owns
+	Items []OverdueActionItem `json:"items"`
+}
+
+// OverdueActionItem is an overdue action item with its outage's title
+type OverdueActionItem struct {
+	ActionItem
+	OutageTitle string `json:"outage_title"`
+	DaysOverdue int    `json:"days_overdue"` // Whole days past the due date
+}
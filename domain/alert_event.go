@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertEvent is a provider-side event recorded against an alert, such as a
+// responder being notified, the alert escalating or being reassigned. Events
+// are fetched from the notification service's log for the alert.
+type AlertEvent struct {
+	ID         uuid.UUID `json:"id"`
+	AlertID    uuid.UUID `json:"alert_id"`
+	ExternalID string    `json:"external_id"` // Provider log entry ID, unique per alert
+	Type       string    `json:"type"`        // notified, escalated, reassigned
+	Summary    string    `json:"summary"`
+	Actor      string    `json:"actor,omitempty"`  // Who or what caused the event
+	Target     string    `json:"target,omitempty"` // Who was notified or assigned
+	OccurredAt time.Time `json:"occurred_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
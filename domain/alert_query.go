@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// AlertQuery selects the alerts listed across outages. Empty fields match
+// every alert.
+type AlertQuery struct {
+	Source   string
+	Team     string // Matches alerts routed to the team, not only their primary team
+	Severity string
+	Since    time.Time // Triggered at or after
+	Until    time.Time // Triggered before
+	Resolved *bool     // Only resolved alerts when true, only unresolved ones when false
+	Limit    int
+	Offset   int
+}
+
+// Matches reports whether the alert is selected by the query's filters
+func (q AlertQuery) Matches(a *Alert) bool {
+	switch {
+	case q.Source != "" && a.Source != q.Source,
+		q.Team != "" && !a.HasTeam(q.Team),
+		q.Severity != "" && a.Severity != q.Severity,
+		!q.Since.IsZero() && a.TriggeredAt.Before(q.Since),
+		!q.Until.IsZero() && !a.TriggeredAt.Before(q.Until),
+		q.Resolved != nil && (a.ResolvedAt != nil) != *q.Resolved:
+		return false
+	}
+	return true
+}
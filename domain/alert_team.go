@@ -0,0 +1,19 @@
+package domain
+
+import "slices"
+
+// Teams returns every team the alert was routed to, its primary team first
+func (a *Alert) Teams() []string {
+	if len(a.TeamNames) > 0 {
+		return a.TeamNames
+	}
+	if a.TeamName == "" {
+		return nil
+	}
+	return []string{a.TeamName}
+}
+
+// HasTeam reports whether the alert was routed to the named team
+func (a *Alert) HasTeam(name string) bool {
+	return slices.Contains(a.Teams(), name)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Analytics export file formats
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// Tables written by the analytics export
+const (
+	ExportTableOutages = "outages"
+	ExportTableAlerts  = "alerts"
+	ExportTableNotes   = "notes"
+)
+
+// ExportTables lists the tables the analytics export can write
+var ExportTables = []string{ExportTableOutages, ExportTableAlerts, ExportTableNotes}
+
+// AnalyticsExportConfig configures the scheduled export of outage data to
+// object storage for loading into a data warehouse
+type AnalyticsExportConfig struct {
+	Format string   // csv or parquet; default parquet
+	Tables []string // Tables to export; default all of ExportTables
+}
+
+// ExportWatermark records how far a table has been exported: rows changed
+// before ExportedUntil have been written, so the next export starts there
+type ExportWatermark struct {
+	Table         string    `json:"table"`
+	ExportedUntil time.Time `json:"exported_until"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableExport summarises one incremental export of a table: the rows that
+// changed at or after Since and before Until
+type TableExport struct {
+	Table string    `json:"table"`
+	Since time.Time `json:"since"` // Zero for the first export, which covers every row
+	Until time.Time `json:"until"`
+	Rows  int       `json:"rows"`
+	Key   string    `json:"key,omitempty"` // Object written; empty when no rows changed
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteMetadataAttachments holds the JSON-encoded IDs of the attachments
+// uploaded with a note
+const NoteMetadataAttachments = "attachments"
+
+// ErrAttachmentTooLarge is returned when an upload exceeds the configured
+// attachment size limit. HTTP handlers return 413 for it.
+var ErrAttachmentTooLarge = fmt.Errorf("attachment too large: %w", ErrInvalidInput)
+
+// Attachment describes a file (graph, log snippet, screenshot) uploaded to an
+// outage. The content lives in blob storage under StorageKey.
+type Attachment struct {
+	ID          uuid.UUID  `json:"id"`
+	OutageID    uuid.UUID  `json:"outage_id"`
+	NoteID      *uuid.UUID `json:"note_id,omitempty"` // Note that references the attachment, if any
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"` // Sniffed from the content, not taken from the client
+	Size        int64      `json:"size"`         // Bytes
+	SHA256      string     `json:"sha256"`       // Hex digest of the content
+	StorageKey  string     `json:"-"`
+	UploadedBy  string     `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// UploadAttachmentRequest describes an attachment upload. The content is
+// passed separately.
+type UploadAttachmentRequest struct {
+	OutageID   uuid.UUID
+	NoteID     *uuid.UUID // Optional note to reference the attachment from
+	Filename   string
+	UploadedBy string
+}
+
+// AttachmentPolicy limits what can be uploaded
+type AttachmentPolicy struct {
+	MaxBytes     int64    // Largest accepted upload
+	AllowedTypes []string // Accepted content types, e.g. image/png
+}
+
+// AttachmentIDs returns the attachments referenced by the note
+func (n *Note) AttachmentIDs() []uuid.UUID {
+	raw := n.Metadata[NoteMetadataAttachments]
+	if raw == "" {
+		return nil
+	}
+	var ids []uuid.UUID
+	if err := json.Unmarshal([]byte(raw), &ids); err != nil {
+		return nil
+	}
+	return ids
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Digest periods
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestSchedule configures a recurring summary of outages sent to email
+// addresses and a Slack channel. A digest with a team covers that team's
+// outages and, without recipients of its own, goes to the team's members
+// and channel.
+type DigestSchedule struct {
+	Name         string
+	Team         string   // Only summarise this team's outages; empty covers all
+	Period       string   // daily or weekly
+	Weekday      string   // Day weekly digests are sent, e.g. monday; default monday
+	Hour         int      // Hour of day the digest is sent, 0-23
+	Timezone     string   // IANA time zone for Weekday and Hour; default UTC
+	Emails       []string // Addresses to email the digest to
+	SlackChannel string   // Channel to post the digest in
+	Template     string   // text/template for the message; empty uses the default
+}
+
+// Digest summarises outages over the period before Until
+type Digest struct {
+	Name               string         `json:"name,omitempty"`
+	Team               string         `json:"team,omitempty"`
+	Period             string         `json:"period"`
+	Since              time.Time      `json:"since"`
+	Until              time.Time      `json:"until"`
+	Open               []DigestOutage `json:"open"`                // Outages not yet resolved
+	New                []DigestOutage `json:"new"`                 // Outages created in the period
+	MissingPostmortems []DigestOutage `json:"missing_postmortems"` // Resolved outages not yet reviewed
+	MTTR               []MTTRPeriod   `json:"mttr"`                // Mean time to resolve per period, oldest first, ending with this one
+}
+
+// DigestOutage is the part of an outage shown in a digest
+type DigestOutage struct {
+	ID           uuid.UUID  `json:"id"`
+	Title        string     `json:"title"`
+	Severity     string     `json:"severity"`
+	Status       string     `json:"status"`
+	OwningTeam   string     `json:"owning_team,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+	ReviewStatus string     `json:"review_status,omitempty"` // Set for outages missing a postmortem
+}
+
+// MTTRPeriod is the mean time to resolve the outages resolved in one digest
+// period
+type MTTRPeriod struct {
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	Resolved    int       `json:"resolved"`
+	MTTRSeconds int64     `json:"mttr_seconds"` // Zero when nothing was resolved
+}
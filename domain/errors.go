@@ -6,3 +6,18 @@ import "errors"
 // does not exist. Callers may use errors.Is to distinguish not-found from
 // other storage errors (e.g. to return HTTP 404 vs 500).
 var ErrNotFound = errors.New("not found")
+
+// ErrInvalidInput is returned by the service layer when a request fails
+// validation. It is wrapped with a description of the offending field so
+// HTTP handlers can return 400 rather than 500.
+var ErrInvalidInput = errors.New("invalid input")
+
+// ErrConflict is returned when a write clashes with existing state, such as
+// a second alert with the same source and external ID. HTTP handlers return
+// 409 for it.
+var ErrConflict = errors.New("conflict")
+
+// ErrForbidden is returned by the service layer when the caller may not
+// make a change, such as to an outage owned by a team they are not in.
+// HTTP handlers return 403 for it.
+var ErrForbidden = errors.New("forbidden")
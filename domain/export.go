@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// OutageExportVersion is the version of the outage export document written
+// by this build. Imports reject documents with a newer version.
+const OutageExportVersion = 1
+
+// OutageExportQuery selects the outages written to an export. Zero times
+// leave that end of the range open.
+type OutageExportQuery struct {
+	Since time.Time // Only outages created at or after this time
+	Until time.Time // Only outages created before this time
+}
+
+// OutageExport is a portable copy of outages with their alerts, notes and
+// tags, used for backups and for moving outages between instances
+type OutageExport struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Outages    []*Outage `json:"outages"`
+}
+
+// OutageImportResult counts what an import added. Outages whose ID already
+// exists are skipped along with their alerts, notes and tags, and alerts
+// already tracked under the same source and external ID are skipped, so an
+// export can be imported more than once.
+type OutageImportResult struct {
+	Outages        int `json:"outages"`
+	SkippedOutages int `json:"skipped_outages"`
+	Alerts         int `json:"alerts"`
+	SkippedAlerts  int `json:"skipped_alerts"`
+	Notes          int `json:"notes"`
+	Tags           int `json:"tags"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+)
+
+// Service is an entry in the service catalogue: a service or component
+// outages can be recorded as affecting. Outages name the services they
+// affect, so only catalogued names are accepted.
+type Service struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	OwningTeam  string `json:"owning_team,omitempty"` // Team that owns the service and its outages
+	Tier        int    `json:"tier,omitempty"`        // Criticality, 1 being the most critical; 0 when untiered
+	RunbookURL  string `json:"runbook_url,omitempty"`
+	Repo        string `json:"repo,omitempty"` // Source repository URL
+	// StatuspageComponent is the Statuspage component ID published as
+	// affected when an outage affecting the service is published
+	StatuspageComponent string `json:"statuspage_component,omitempty"`
+	// Source names the catalogue a synced service was copied from, e.g.
+	// "file" or "backstage". Empty for services managed through the API.
+	Source    string    `json:"source,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ImpactWindow returns when the outage's impact started and ended. Unset
+// impact times fall back to when the outage was created and resolved; an
+// outage still unresolved is impacting until now.
+func (o *Outage) ImpactWindow(now time.Time) (start, end time.Time) {
+	start = o.CreatedAt
+	if o.ImpactStartedAt != nil {
+		start = *o.ImpactStartedAt
+	}
+	end = now
+	switch {
+	case o.ImpactEndedAt != nil:
+		end = *o.ImpactEndedAt
+	case o.ResolvedAt != nil:
+		end = *o.ResolvedAt
+	}
+	return start, end
+}
+
+// ServiceDowntimeQuery selects the outages counted in a downtime report
+type ServiceDowntimeQuery struct {
+	Since time.Time
+	Until time.Time
+	// OwningTeam limits the report to services owned by the team
+	OwningTeam string
+	// MaxTier limits the report to services of this tier or more critical
+	MaxTier int
+	// CustomerImpact limits the report to customer-impacting outages
+	CustomerImpact bool
+}
+
+// ServiceDowntime reports how long each catalogued service was affected by
+// outages between Since and Until
+type ServiceDowntime struct {
+	Since          time.Time              `json:"since"`
+	Until          time.Time              `json:"until"`
+	CustomerImpact bool                   `json:"customer_impact"` // Only customer-impacting outages were counted
+	Services       []ServiceDowntimeEntry `json:"services"`        // Most downtime first
+}
+
+// ServiceDowntimeEntry holds one service's downtime. Overlapping outages
+// are only counted once, so DowntimeSeconds is never more than the range.
+type ServiceDowntimeEntry struct {
+	Service         string  `json:"service"`
+	OwningTeam      string  `json:"owning_team,omitempty"`
+	Tier            int     `json:"tier,omitempty"`
+	Outages         int     `json:"outages"`
+	CustomerImpact  int     `json:"customer_impact"` // Outages that impacted customers
+	DowntimeSeconds int64   `json:"downtime_seconds"`
+	Availability    float64 `json:"availability"` // Fraction of the range the service was not affected
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Import run statuses
+const (
+	ImportRunRunning   = "running"
+	ImportRunCompleted = "completed"
+	ImportRunFailed    = "failed"
+)
+
+// ImportRun records the progress of a historical import from a notification
+// service, so an import that stops partway can be resumed where it left off
+type ImportRun struct {
+	ID       uuid.UUID `json:"id"`
+	Provider string    `json:"provider"` // Notification service imported from, e.g. pagerduty
+	Kind     string    `json:"kind"`     // What is imported: alerts or incidents
+	// FilterHash identifies the kind, date range and teams imported; only a
+	// run with the same filters can be resumed
+	FilterHash string         `json:"filter_hash"`
+	Since      time.Time      `json:"since"`
+	Until      time.Time      `json:"until"`
+	TeamIDs    []string       `json:"team_ids,omitempty"`
+	Status     string         `json:"status"`                 // running, completed or failed
+	Offset     int            `json:"offset"`                 // Alerts or incidents fetched and processed so far
+	LastItemAt *time.Time     `json:"last_item_at,omitempty"` // Creation time of the last alert or incident processed
+	Stats      ImportRunStats `json:"stats"`
+	Error      string         `json:"error,omitempty"` // Why a failed run stopped
+	StartedAt  time.Time      `json:"started_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+}
+
+// ImportRunStats counts what an import run has done so far
+type ImportRunStats struct {
+	TotalFetched  int `json:"total_fetched"`
+	NewOutages    int `json:"new_outages"`
+	NewAlerts     int `json:"new_alerts"`
+	Skipped       int `json:"skipped"` // Alerts or incidents imported before
+	AlertEvents   int `json:"alert_events"`
+	TimelineNotes int `json:"timeline_notes"` // Provider notes and incident timeline entries added as notes
+	Errors        int `json:"errors"`
+}
+
+// Add adds the counts in o to s
+func (s *ImportRunStats) Add(o ImportRunStats) {
+	s.TotalFetched += o.TotalFetched
+	s.NewOutages += o.NewOutages
+	s.NewAlerts += o.NewAlerts
+	s.Skipped += o.Skipped
+	s.AlertEvents += o.AlertEvents
+	s.TimelineNotes += o.TimelineNotes
+	s.Errors += o.Errors
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "encoding/json"
+
+// NoteMetadataMentions holds the JSON-encoded mentions resolved from a note's
+// content
+const NoteMetadataMentions = "mentions"
+
+// Mention types
+const (
+	MentionUser = "user"
+	MentionTeam = "team"
+)
+
+// Mention is an @mention in a note resolved against the operational config.
+// Users are team members, identified by email address.
+type Mention struct {
+	Type string `json:"type"` // user or team
+	Name string `json:"name"` // Team name or user email
+}
+
+// Mentions returns the mentions recorded on the note
+func (n *Note) Mentions() []Mention {
+	raw := n.Metadata[NoteMetadataMentions]
+	if raw == "" {
+		return nil
+	}
+	var mentions []Mention
+	if err := json.Unmarshal([]byte(raw), &mentions); err != nil {
+		return nil
+	}
+	return mentions
+}
@@ -8,31 +8,57 @@ import (
 
 // Outage represents a tracked incident/outage created from one or more alerts
 type Outage struct {
-	ID           uuid.UUID         `json:"id"`
-	Title        string            `json:"title"`
-	Description  string            `json:"description"`
-	Status       string            `json:"status"` // open, investigating, resolved, closed
-	Severity     string            `json:"severity"` // critical, high, medium, low
-	CreatedAt    time.Time         `json:"created_at"`
-	UpdatedAt    time.Time         `json:"updated_at"`
-	ResolvedAt   *time.Time        `json:"resolved_at,omitempty"`
-	Alerts       []Alert           `json:"alerts,omitempty"`
-	Notes        []Note            `json:"notes,omitempty"`
-	Tags         []Tag             `json:"tags,omitempty"`
-	Metadata     map[string]string `json:"metadata,omitempty"`      // Simple key-value pairs
-	CustomFields map[string]any    `json:"custom_fields,omitempty"` // Complex structured data
+	ID               uuid.UUID         `json:"id"`
+	Title            string            `json:"title"`
+	Description      string            `json:"description"`
+	Status           string            `json:"status"`                // open, investigating, mitigated, resolved, closed
+	Severity         string            `json:"severity"`              // critical, high, medium, low
+	OwningTeam       string            `json:"owning_team,omitempty"` // Team responsible for the outage
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	InvestigatingAt  *time.Time        `json:"investigating_at,omitempty"` // First time the outage entered investigating
+	MitigatedAt      *time.Time        `json:"mitigated_at,omitempty"`     // First time the outage entered mitigated
+	ResolvedAt       *time.Time        `json:"resolved_at,omitempty"`
+	DeletedAt        *time.Time        `json:"deleted_at,omitempty"`        // Set while the outage is in the trash
+	AffectedServices []string          `json:"affected_services,omitempty"` // Catalogued services the outage affects
+	CustomerImpact   bool              `json:"customer_impact"`             // Whether customers noticed
+	ImpactStartedAt  *time.Time        `json:"impact_started_at,omitempty"` // When impact began, which may be before the outage was opened
+	ImpactEndedAt    *time.Time        `json:"impact_ended_at,omitempty"`   // When impact ended, which may be before the outage was resolved
+	ParentID         *uuid.UUID        `json:"parent_id,omitempty"`         // Outage this outage is a child of
+	Rollup           *OutageRollup     `json:"rollup,omitempty"`            // Summary of the outage's children, set when viewing an outage that has any
+	Relations        []OutageRelation  `json:"relations,omitempty"`         // Links to other outages, set when viewing an outage
+	Alerts           []Alert           `json:"alerts,omitempty"`
+	Notes            []Note            `json:"notes,omitempty"`
+	Tags             []Tag             `json:"tags,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`      // Simple key-value pairs
+	CustomFields     map[string]any    `json:"custom_fields,omitempty"` // Complex structured data
+}
+
+// OutageAssociations selects which of an outage's alerts, notes and tags
+// are loaded with it. Associations not selected are left nil and cost no
+// queries.
+type OutageAssociations struct {
+	Alerts bool
+	Notes  bool
+	Tags   bool
 }
 
+// AllOutageAssociations selects alerts, notes and tags, as GetOutage loads
+var AllOutageAssociations = OutageAssociations{Alerts: true, Notes: true, Tags: true}
+
 // Alert represents a paging alert from an oncall notification service
 type Alert struct {
 	ID               uuid.UUID         `json:"id"`
 	OutageID         uuid.UUID         `json:"outage_id"`
 	ExternalID       string            `json:"external_id"` // ID from PagerDuty/OpsGenie
 	Source           string            `json:"source"`      // pagerduty, opsgenie, etc.
-	TeamName         string            `json:"team_name"`
+	TeamName         string            `json:"team_name"`            // Primary team, the first the alert was routed to
+	TeamNames        []string          `json:"team_names,omitempty"` // Every team the alert was routed to, TeamName first
 	Title            string            `json:"title"`
 	Description      string            `json:"description"`
 	Severity         string            `json:"severity"`
+	Service          string            `json:"service,omitempty"` // Catalogued service the alert is about
+	Fingerprint      string            `json:"fingerprint,omitempty"` // Identifies the monitor, from the source and the title the alert was raised with
 	TriggeredAt      time.Time         `json:"triggered_at"`
 	AcknowledgedAt   *time.Time        `json:"acknowledged_at,omitempty"`
 	ResolvedAt       *time.Time        `json:"resolved_at,omitempty"`
@@ -42,15 +68,26 @@ type Alert struct {
 	CustomFields     map[string]any    `json:"custom_fields,omitempty"`   // Complex structured data
 }
 
+// Note formats
+const (
+	NoteFormatPlaintext = "plaintext"
+	NoteFormatMarkdown  = "markdown"
+	// NoteFormatLog is for pasted log excerpts and stack traces. Log notes
+	// are returned byte for byte as written and shown as preformatted text.
+	NoteFormatLog = "log"
+)
+
 // Note represents a free-form text or markdown note attached to an outage
 type Note struct {
 	ID           uuid.UUID         `json:"id"`
 	OutageID     uuid.UUID         `json:"outage_id"`
+	ParentNoteID *uuid.UUID        `json:"parent_note_id,omitempty"` // Top-level note this note replies to
 	Content      string            `json:"content"`
-	Format       string            `json:"format"` // plaintext, markdown
+	Format       string            `json:"format"` // plaintext, markdown, log
 	Author       string            `json:"author"`
 	CreatedAt    time.Time         `json:"created_at"`
 	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"`    // Set while the note is in the trash
 	Metadata     map[string]string `json:"metadata,omitempty"`      // Simple key-value pairs
 	CustomFields map[string]any    `json:"custom_fields,omitempty"` // Complex structured data
 }
@@ -63,6 +100,7 @@ type Tag struct {
 	Value        string         `json:"value"` // e.g., "PROJ-123", "api", "us-west-2"
 	CreatedAt    time.Time      `json:"created_at"`
 	CustomFields map[string]any `json:"custom_fields,omitempty"` // Additional structured data
+	UniqueKey    bool           `json:"-"`                       // Set while the key has a unique tag schema; an outage has one such tag per key
 }
 
 // TagInput holds the fields needed to create a tag, used in CreateOutageRequest.
@@ -72,32 +110,75 @@ type TagInput struct {
 	CustomFields map[string]any `json:"custom_fields,omitempty"`
 }
 
+// TagUsage counts the tags with a key, or with a key and value, across
+// outages not in the trash. Listing keys leaves Value empty.
+type TagUsage struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	Count int    `json:"count"`
+}
+
 // CreateOutageRequest represents the data needed to create a new outage
 type CreateOutageRequest struct {
-	Title        string            `json:"title"`
-	Description  string            `json:"description"`
-	Severity     string            `json:"severity"`
-	AlertIDs     []string          `json:"alert_ids"` // External alert IDs to associate
-	Tags         []TagInput        `json:"tags,omitempty"`
-	Metadata     map[string]string `json:"metadata,omitempty"`
-	CustomFields map[string]any    `json:"custom_fields,omitempty"`
+	Title            string            `json:"title"`
+	Description      string            `json:"description"`
+	Severity         string            `json:"severity"`
+	AlertIDs         []string          `json:"alert_ids"`           // External alert IDs to associate
+	Template         string            `json:"template,omitempty"`  // Outage template that fills unset fields and adds its tags
+	Variables        map[string]string `json:"variables,omitempty"` // Fill the template's {placeholders}
+	OwningTeam       string            `json:"owning_team,omitempty"`
+	AffectedServices []string          `json:"affected_services,omitempty"` // Must name catalogued services
+	CustomerImpact   bool              `json:"customer_impact,omitempty"`
+	ImpactStartedAt  *time.Time        `json:"impact_started_at,omitempty"`
+	ImpactEndedAt    *time.Time        `json:"impact_ended_at,omitempty"`
+	Tags             []TagInput        `json:"tags,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	CustomFields     map[string]any    `json:"custom_fields,omitempty"`
 }
 
 // AddNoteRequest represents the data needed to add a note to an outage
 type AddNoteRequest struct {
 	Content      string            `json:"content"`
-	Format       string            `json:"format"` // plaintext, markdown
+	Format       string            `json:"format"` // plaintext, markdown, log
 	Author       string            `json:"author"`
+	ParentNoteID *uuid.UUID        `json:"parent_note_id,omitempty"` // Note to reply to
 	Metadata     map[string]string `json:"metadata,omitempty"`
 	CustomFields map[string]any    `json:"custom_fields,omitempty"`
 }
 
 // UpdateOutageRequest represents the data that can be updated on an outage
 type UpdateOutageRequest struct {
-	Title        *string           `json:"title,omitempty"`
-	Description  *string           `json:"description,omitempty"`
-	Status       *string           `json:"status,omitempty"`
-	Severity     *string           `json:"severity,omitempty"`
-	Metadata     map[string]string `json:"metadata,omitempty"`
-	CustomFields map[string]any    `json:"custom_fields,omitempty"`
+	Title            *string           `json:"title,omitempty"`
+	Description      *string           `json:"description,omitempty"`
+	Status           *string           `json:"status,omitempty"`
+	Severity         *string           `json:"severity,omitempty"`
+	OwningTeam       *string           `json:"owning_team,omitempty"`       // An empty string clears the owner
+	AffectedServices *[]string         `json:"affected_services,omitempty"` // Replaces the affected services; an empty list clears them
+	CustomerImpact   *bool             `json:"customer_impact,omitempty"`
+	ImpactStartedAt  *time.Time        `json:"impact_started_at,omitempty"`
+	ImpactEndedAt    *time.Time        `json:"impact_ended_at,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	CustomFields     map[string]any    `json:"custom_fields,omitempty"`
+	ResolveAlerts    bool              `json:"resolve_alerts,omitempty"` // When this resolves the outage, also resolve its open alerts with their providers
+}
+
+// UpdateTagRequest represents the data that can be updated on a tag. Custom
+// fields replace the stored values in full.
+type UpdateTagRequest struct {
+	Key          *string        `json:"key,omitempty"`
+	Value        *string        `json:"value,omitempty"`
+	CustomFields map[string]any `json:"custom_fields,omitempty"`
+}
+
+// UpdateAlertRequest represents the data that can be updated on an alert.
+// Metadata and custom fields replace the stored values in full.
+type UpdateAlertRequest struct {
+	Title          *string           `json:"title,omitempty"`
+	Description    *string           `json:"description,omitempty"`
+	Severity       *string           `json:"severity,omitempty"`
+	Service        *string           `json:"service,omitempty"` // A catalogued service, or "" to unlink the alert
+	AcknowledgedAt *time.Time        `json:"acknowledged_at,omitempty"`
+	ResolvedAt     *time.Time        `json:"resolved_at,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	CustomFields   map[string]any    `json:"custom_fields,omitempty"`
 }
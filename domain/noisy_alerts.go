@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// NoisyAlertsQuery selects the alerts ranked in a noisy alerts report
+type NoisyAlertsQuery struct {
+	Since time.Time
+	Until time.Time
+	Team  string // Only count alerts routed to this team; empty counts all teams
+	Limit int    // Most fingerprints listed; zero uses the default of 20
+}
+
+// NoisyAlerts ranks alert fingerprints, and so the monitors behind them, by
+// how many alerts they raised in a time range
+type NoisyAlerts struct {
+	Since  time.Time    `json:"since"`
+	Until  time.Time    `json:"until"`
+	Total  int          `json:"total"` // Alerts counted, including those of fingerprints not listed
+	Alerts []NoisyAlert `json:"alerts"`
+}
+
+// NoisyAlert holds the alerts sharing one fingerprint
+type NoisyAlert struct {
+	Fingerprint      string    `json:"fingerprint"`
+	Source           string    `json:"source"`
+	Title            string    `json:"title"` // Title of the latest alert
+	Count            int       `json:"count"`
+	Outages          int       `json:"outages"` // Distinct outages the alerts are linked to
+	Teams            []string  `json:"teams,omitempty"`
+	FirstTriggeredAt time.Time `json:"first_triggered_at"`
+	LastTriggeredAt  time.Time `json:"last_triggered_at"`
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kinds of declaratively managed operational config resources
+const (
+	ResourceTeam        = "team"
+	ResourceTagSchema   = "tag_schema"
+	ResourceRoutingRule = "routing_rule"
+	ResourceTemplate    = "template"
+	ResourceCustomField = "custom_field"
+)
+
+// ConfigResource is a stored operational config resource. Spec holds the
+// JSON encoding of the kind's type (Team, TagSchema, RoutingRule,
+// OutageTemplate or CustomField).
+type ConfigResource struct {
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	Spec      json.RawMessage `json:"spec"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Team is a group of responders that outages can be routed to and owned
+// by. Teams are either managed locally through the operational config or
+// synced from a notification service, in which case Source names the
+// service and ExternalID is the team's identifier there.
+type Team struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	SlackChannel string   `json:"slack_channel,omitempty"`
+	Members      []string `json:"members,omitempty"` // Email addresses
+	Source       string   `json:"source,omitempty"`
+	ExternalID   string   `json:"external_id,omitempty"`
+}
+
+// TagSchema restricts the values allowed for a tag key. An empty
+// AllowedValues list accepts any value. An outage has at most one tag with
+// a Unique key: adding the key again updates that tag instead.
+type TagSchema struct {
+	Key           string   `json:"key"`
+	Description   string   `json:"description,omitempty"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
+	Unique        bool     `json:"unique,omitempty"`
+}
+
+// RoutingRule acts on matching alerts that arrive without an outage: it
+// can tag the outage opened for the alert with a team and extra tags,
+// change the alert's severity, link the alert to an open outage instead, or
+// drop the alert. Every matching rule is applied in name order, so a later
+// rule's team, tags or severity override an earlier one's.
+type RoutingRule struct {
+	Name        string            `json:"name"`
+	Match       RoutingMatch      `json:"match"`
+	Team        string            `json:"team,omitempty"` // Added as a "team" tag
+	Tags        map[string]string `json:"tags,omitempty"`
+	SetSeverity string            `json:"set_severity,omitempty"` // Replaces the alert's mapped severity
+	// Service links the alert to a catalogued service, which the outage
+	// opened for it is recorded as affecting. Without a team, the alert is
+	// routed to the service's owning team.
+	Service string `json:"service,omitempty"`
+	// AttachToOpenOutage links the alert to the newest unresolved outage
+	// owned by the rule's team, or by any team when the rule has none,
+	// rather than opening a new outage. A new one is opened when there is
+	// no such outage.
+	AttachToOpenOutage bool `json:"attach_to_open_outage,omitempty"`
+	Suppress           bool `json:"suppress,omitempty"` // Drops the alert without storing it
+}
+
+// RoutingMatch selects alerts for a routing rule. Empty fields match any
+// value; set fields are compared case-insensitively.
+type RoutingMatch struct {
+	Source   string `json:"source,omitempty"`
+	TeamName string `json:"team_name,omitempty"` // Matches any team the notification service routed the alert to
+	Severity string `json:"severity,omitempty"`  // Mapped Outalator severity
+	Title    string `json:"title,omitempty"`     // Regular expression searched for in the alert title
+	// Metadata matches source metadata fields, e.g. service_name for
+	// PagerDuty or entity for OpsGenie. A list field matches when any of
+	// its values does.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// OutageTemplate pre-fills outages created with CreateOutageRequest.Template
+// so recurring incident types start with the same structure. Its title and
+// description may contain {variable} placeholders, filled from the
+// request's Variables.
+type OutageTemplate struct {
+	Name        string            `json:"name"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Severity    string            `json:"severity,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Checklist   []string          `json:"checklist,omitempty"` // Steps added to the outage as a checklist note
+}
+
+// NoteMetadataTemplateChecklist marks the checklist note added from an
+// outage template; its value is the template name
+const NoteMetadataTemplateChecklist = "template_checklist"
+
+// TemplateAuthor is the author of checklist notes added from templates
+const TemplateAuthor = "outalator"
+
+// CustomField defines a typed custom field of an entity (outage, note or
+// tag). Writes whose custom_fields break a definition are rejected, and the
+// definitions are served with the custom field schemas so UIs can render
+// forms for them. Its name is unique per entity.
+type CustomField struct {
+	Entity        string   `json:"entity"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type"` // string, number, boolean, object or array
+	Description   string   `json:"description,omitempty"`
+	Required      bool     `json:"required,omitempty"`
+	AllowedValues []string `json:"allowed_values,omitempty"` // Enum values of a string field
+	// RequiredForSeverities makes an outage field required on outages of
+	// these severities
+	RequiredForSeverities []string `json:"required_for_severities,omitempty"`
+}
+
+// ResourceName is the name a custom field is stored under
+func (f CustomField) ResourceName() string {
+	return f.Entity + "." + f.Name
+}
+
+// OpsConfig is the declarative operational config document applied by
+// outalatorctl
+type OpsConfig struct {
+	Teams        []Team           `json:"teams,omitempty"`
+	TagSchemas   []TagSchema      `json:"tag_schemas,omitempty"`
+	RoutingRules []RoutingRule    `json:"routing_rules,omitempty"`
+	Templates    []OutageTemplate `json:"templates,omitempty"`
+	CustomFields []CustomField    `json:"custom_fields,omitempty"`
+}
+
+// Config change actions reported in an OpsConfigPlan
+const (
+	ConfigCreate = "create"
+	ConfigUpdate = "update"
+	ConfigDelete = "delete"
+)
+
+// ConfigChange is a single planned change to a config resource
+type ConfigChange struct {
+	Action string `json:"action"` // create, update or delete
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+}
+
+// OpsConfigPlan lists the changes needed to reach a desired OpsConfig, and
+// whether they were applied
+type OpsConfigPlan struct {
+	Changes []ConfigChange `json:"changes"`
+	Applied bool           `json:"applied"`
+}
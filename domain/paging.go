@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// Business hours used to classify pages as off-hours: pages on weekdays from
+// BusinessHoursStart up to, but not including, BusinessHoursEnd are in
+// hours, everything else is off-hours.
+const (
+	BusinessHoursStart = 9
+	BusinessHoursEnd   = 17
+)
+
+// IsOffHours reports whether t, in its own location, falls outside business
+// hours
+func IsOffHours(t time.Time) bool {
+	switch t.Weekday() {
+	case time.Saturday, time.Sunday:
+		return true
+	}
+	return t.Hour() < BusinessHoursStart || t.Hour() >= BusinessHoursEnd
+}
+
+// PagingLoadQuery selects the alerts counted in a paging load report
+type PagingLoadQuery struct {
+	Since    time.Time
+	Until    time.Time
+	Team     string         // Only count alerts routed to this team; empty counts all teams
+	Location *time.Location // Time zone for hour and day buckets; nil means UTC
+}
+
+// PagingLoad reports how many alerts were triggered in a time range, bucketed
+// per team by day of week and hour of day
+type PagingLoad struct {
+	Since    time.Time        `json:"since"`
+	Until    time.Time        `json:"until"`
+	Timezone string           `json:"timezone"`
+	Total    int              `json:"total"`
+	OffHours int              `json:"off_hours"`
+	Teams    []TeamPagingLoad `json:"teams"`
+}
+
+// TeamPagingLoad holds one team's alert counts. Alerts without a team are
+// counted under an empty team name. Counts is indexed by day of week
+// (0 = Sunday) and then by hour of day.
+type TeamPagingLoad struct {
+	Team     string     `json:"team"`
+	Total    int        `json:"total"`
+	OffHours int        `json:"off_hours"`
+	Counts   [7][24]int `json:"counts"`
+}
+
+// PageRequest asks a paging provider to page the responders of a service or
+// team about an outage. When neither is given, the outage's owning team is
+// paged.
+type PageRequest struct {
+	Source  string `json:"source"`            // Notification service to page through
+	Service string `json:"service,omitempty"` // Provider service ID, e.g. a PagerDuty service
+	Team    string `json:"team,omitempty"`    // Provider team name, e.g. an OpsGenie team
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "github.com/google/uuid"
+
+// ChildOutage summarises a child outage in its parent's roll-up
+type ChildOutage struct {
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Status   string    `json:"status"`
+	Severity string    `json:"severity"`
+}
+
+// OutageRollup summarises the children of a parent outage, such as the
+// per-service outages under a regional one. Status is the least progressed
+// of the children's statuses and Severity the most severe of their
+// severities, so the roll-up reads as resolved only once every child is.
+type OutageRollup struct {
+	Children     []ChildOutage `json:"children"`
+	OpenChildren int           `json:"open_children"` // Children not yet resolved or closed
+	Status       string        `json:"status"`
+	Severity     string        `json:"severity"`
+}
+
+// SetOutageParentRequest links an outage to the parent outage it is part of
+type SetOutageParentRequest struct {
+	ParentID uuid.UUID `json:"parent_id"`
+}
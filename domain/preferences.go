@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// UserPreferences holds per-user settings keyed by the OIDC subject
+type UserPreferences struct {
+	Subject           string                  `json:"subject"`
+	Timezone          string                  `json:"timezone"`            // IANA name, e.g. "Europe/Dublin"
+	DefaultTeamFilter string                  `json:"default_team_filter"` // Team name pre-selected in listings
+	Notifications     NotificationPreferences `json:"notifications"`
+	DigestOptIn       bool                    `json:"digest_opt_in"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+// NotificationPreferences controls which outage notifications a user receives
+type NotificationPreferences struct {
+	SlackDM     bool   `json:"slack_dm"`
+	Email       bool   `json:"email"`
+	MinSeverity string `json:"min_severity,omitempty"` // critical, high, medium, low; empty means all
+}
+
+// UpdatePreferencesRequest represents the preference fields a user can change.
+// Nil fields are left untouched.
+type UpdatePreferencesRequest struct {
+	Timezone          *string                  `json:"timezone,omitempty"`
+	DefaultTeamFilter *string                  `json:"default_team_filter,omitempty"`
+	Notifications     *NotificationPreferences `json:"notifications,omitempty"`
+	DigestOptIn       *bool                    `json:"digest_opt_in,omitempty"`
+}
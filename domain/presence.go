@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Presence activities
+const (
+	PresenceViewing = "viewing"
+	PresenceWorking = "working"
+)
+
+// PresenceTTL is how long a heartbeat keeps a user listed on an outage.
+// Clients should send heartbeats well within it, e.g. every 30 seconds.
+const PresenceTTL = 90 * time.Second
+
+// Presence records that a user is currently engaged with an outage
+type Presence struct {
+	User     string    `json:"user"` // Email of the authenticated user
+	Name     string    `json:"name,omitempty"`
+	Activity string    `json:"activity"` // viewing or working
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// PresenceHeartbeat represents a heartbeat from a user engaged with an outage
+type PresenceHeartbeat struct {
+	User     string `json:"-"`
+	Name     string `json:"-"`
+	Activity string `json:"activity,omitempty"` // Defaults to viewing
+}
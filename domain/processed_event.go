@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// ProcessedEvent records that an event delivered by an external system,
+// such as a Slack Events API callback, has been processed, so deliveries
+// the sender retries are not processed again
+type ProcessedEvent struct {
+	Source      string    `json:"source"`   // Sender of the event, e.g. slack
+	EventID     string    `json:"event_id"` // The sender's ID for the event
+	ProcessedAt time.Time `json:"processed_at"`
+}
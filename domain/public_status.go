@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// MetadataPublic marks an outage or note as public when its metadata value
+// is "true". Public notes are published to Statuspage, and open public
+// outages are listed on the public status endpoint with their latest public
+// note.
+const MetadataPublic = "public"
+
+// PublicStatus is the view of current outages shown to people without an
+// account: only open outages marked public, with the fields fit to publish
+type PublicStatus struct {
+	Outages   []PublicOutage `json:"outages"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// PublicOutage is an open public outage as the public status shows it
+type PublicOutage struct {
+	Title        string        `json:"title"`
+	Severity     string        `json:"severity"`
+	StartedAt    time.Time     `json:"started_at"` // When impact began, or when the outage was opened if that is unknown
+	LatestUpdate *PublicUpdate `json:"latest_update,omitempty"`
+}
+
+// PublicUpdate is the latest public note on a public outage
+type PublicUpdate struct {
+	Content  string    `json:"content"`
+	PostedAt time.Time `json:"posted_at"`
+}
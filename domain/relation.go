@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outage relation types. Each is stored one way round and read from the
+// other outage as its inverse, e.g. a duplicate_of link from A to B shows
+// on B as duplicated_by A.
+const (
+	RelationDuplicateOf  = "duplicate_of" // The outage reports the same incident as the related outage
+	RelationDuplicatedBy = "duplicated_by"
+	RelationCausedBy     = "caused_by" // The related outage's failure cascaded into the outage
+	RelationCauses       = "causes"
+	RelationRelatedTo    = "related_to"
+)
+
+// OutageRelationTypes lists the relation types a link can be created with
+var OutageRelationTypes = []string{RelationDuplicateOf, RelationDuplicatedBy, RelationCausedBy, RelationCauses, RelationRelatedTo}
+
+// InverseRelation returns how a relation of type t reads from the related
+// outage. related_to is its own inverse.
+func InverseRelation(t string) string {
+	switch t {
+	case RelationDuplicateOf:
+		return RelationDuplicatedBy
+	case RelationDuplicatedBy:
+		return RelationDuplicateOf
+	case RelationCausedBy:
+		return RelationCauses
+	case RelationCauses:
+		return RelationCausedBy
+	}
+	return t
+}
+
+// OutageRelation is a typed link between two outages, such as a duplicate
+// report or a cascading failure. Relations are listed from the point of
+// view of one outage: OutageID is that outage and Type reads from it to
+// RelatedOutageID. Relations are removed along with either outage.
+type OutageRelation struct {
+	ID                 uuid.UUID `json:"id"`
+	OutageID           uuid.UUID `json:"outage_id"`
+	RelatedOutageID    uuid.UUID `json:"related_outage_id"`
+	RelatedOutageTitle string    `json:"related_outage_title,omitempty"` // Set when listed for an outage
+	Type               string    `json:"type"`                           // duplicate_of, duplicated_by, caused_by, causes or related_to
+	Description        string    `json:"description,omitempty"`
+	CreatedBy          string    `json:"created_by,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// CreateOutageRelationRequest links an outage to another outage
+type CreateOutageRelationRequest struct {
+	RelatedOutageID uuid.UUID `json:"related_outage_id"`
+	Type            string    `json:"type"`
+	Description     string    `json:"description,omitempty"`
+	CreatedBy       string    `json:"created_by,omitempty"` // Set to the signed-in user when authentication is enabled
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// NoteMetadataAutoResolved marks a note recording an automatic resolution,
+// with the value "alert" or "outage" naming what was resolved
+const NoteMetadataAutoResolved = "auto_resolved"
+
+// AutoResolveAuthor is the author of notes recording automatic resolutions
+const AutoResolveAuthor = "outalator"
+
+// AlertResolutionPolicy controls automatic resolution of alerts and their
+// outages, so records from abandoned sources do not stay open forever
+type AlertResolutionPolicy struct {
+	MaxOpen        time.Duration // Alerts unresolved for longer are resolved; zero disables the timeout
+	ResolveOutages bool          // Resolve an outage once all of its alerts are resolved
+}
+
+// AlertExpiryResult summarises one pass resolving alerts that exceeded the
+// maximum open duration
+type AlertExpiryResult struct {
+	Expired         int `json:"expired"`          // Alerts resolved by the timeout
+	OutagesResolved int `json:"outages_resolved"` // Outages resolved because all their alerts were
+	Failed          int `json:"failed"`           // Alerts that could not be updated; retried next pass
+}
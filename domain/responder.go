@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Responder roles. An outage has at most one current responder per role.
+const (
+	RoleIncidentCommander = "incident_commander"
+	RoleCommsLead         = "comms_lead"
+	RoleScribe            = "scribe"
+)
+
+// ValidResponderRoles lists the roles a responder can be assigned
+var ValidResponderRoles = []string{RoleIncidentCommander, RoleCommsLead, RoleScribe}
+
+// IsValidResponderRole reports whether role is one of ValidResponderRoles
+func IsValidResponderRole(role string) bool {
+	for _, r := range ValidResponderRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponderAssignment records a responder holding a role on an outage.
+// Assignments are kept after they end so the outage's responder history
+// survives reassignment. Source and ScheduleID are set when the responder
+// was taken from an on-call schedule.
+type ResponderAssignment struct {
+	ID           uuid.UUID  `json:"id"`
+	OutageID     uuid.UUID  `json:"outage_id"`
+	Role         string     `json:"role"`
+	Responder    string     `json:"responder"`
+	Source       string     `json:"source,omitempty"`
+	ScheduleID   string     `json:"schedule_id,omitempty"`
+	AssignedBy   string     `json:"assigned_by,omitempty"`
+	AssignedAt   time.Time  `json:"assigned_at"`
+	UnassignedBy string     `json:"unassigned_by,omitempty"`
+	UnassignedAt *time.Time `json:"unassigned_at,omitempty"`
+}
+
+// Active reports whether the responder still holds the role
+func (a *ResponderAssignment) Active() bool {
+	return a.UnassignedAt == nil
+}
+
+// AssignResponderRequest assigns a responder to a role. Without a
+// Responder, whoever is on call for Source's Schedule is assigned.
+type AssignResponderRequest struct {
+	Role      string `json:"role"`
+	Responder string `json:"responder,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Schedule  string `json:"schedule,omitempty"`
+}
+
+// OutageResponders holds an outage's current responders, one per role, and
+// every assignment it has had, oldest first
+type OutageResponders struct {
+	Current []*ResponderAssignment `json:"current"`
+	History []*ResponderAssignment `json:"history"`
+}
+
+// OnCall is a user currently on call for a notification service's schedule
+type OnCall struct {
+	Source       string     `json:"source"`
+	ScheduleID   string     `json:"schedule_id"`
+	ScheduleName string     `json:"schedule_name,omitempty"`
+	Name         string     `json:"name,omitempty"`
+	Email        string     `json:"email,omitempty"`
+	Start        *time.Time `json:"start,omitempty"`
+	End          *time.Time `json:"end,omitempty"`
+}
+
+// ResponderLoad reports how often each responder was assigned to outages
+// in a time range
+type ResponderLoad struct {
+	Since      time.Time            `json:"since"`
+	Until      time.Time            `json:"until"`
+	Total      int                  `json:"total"`
+	Responders []ResponderLoadEntry `json:"responders"`
+}
+
+// ResponderLoadEntry holds one responder's assignments, counted per role.
+// Outages counts distinct outages, since a responder can hold several roles
+// on one outage.
+type ResponderLoadEntry struct {
+	Responder   string         `json:"responder"`
+	Assignments int            `json:"assignments"`
+	Outages     int            `json:"outages"`
+	Roles       map[string]int `json:"roles"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Retention run triggers
+const (
+	RetentionTriggerScheduled = "scheduled"
+	RetentionTriggerManual    = "manual"
+)
+
+// Retention run statuses
+const (
+	RetentionRunRunning   = "running"
+	RetentionRunCompleted = "completed"
+	RetentionRunFailed    = "failed"
+)
+
+// RetentionPolicy keeps the outage tables small by archiving old outages to
+// export files in cold storage and, later, deleting the files
+type RetentionPolicy struct {
+	// ArchiveAfter is how long after it is resolved or closed an outage is
+	// moved out of the database into an archive file
+	ArchiveAfter time.Duration
+	// PurgeAfter is how long after they were resolved or closed archived
+	// outages are deleted for good. Zero keeps archives forever; otherwise it
+	// must be longer than ArchiveAfter.
+	PurgeAfter time.Duration
+}
+
+// RetentionRun records one pass of the retention policy: the outages it
+// archived and the earlier archives it purged
+type RetentionRun struct {
+	ID          uuid.UUID `json:"id"`
+	Trigger     string    `json:"trigger"`                // scheduled or manual
+	TriggeredBy string    `json:"triggered_by,omitempty"` // Admin who started a manual run
+	Status      string    `json:"status"`                 // running, completed or failed
+	// ArchivedBefore is the cutoff: outages resolved or closed before it
+	// were archived by this run
+	ArchivedBefore  time.Time `json:"archived_before"`
+	ArchivedOutages int       `json:"archived_outages"`
+	// ArchiveKey is the archive file this run wrote, an outage export that
+	// can be imported again; empty when nothing was old enough
+	ArchiveKey      string     `json:"archive_key,omitempty"`
+	ArchivePurgedAt *time.Time `json:"archive_purged_at,omitempty"` // When a later run deleted this run's archive
+	PurgedArchives  int        `json:"purged_archives"`             // Archives of earlier runs this run deleted
+	Error           string     `json:"error,omitempty"`             // Why a failed run stopped
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Post-resolution review states. They track the postmortem backlog and are
+// independent of the outage's operational status.
+const (
+	ReviewNeedsReview = "needs-review"
+	ReviewScheduled   = "review-scheduled"
+	ReviewReviewed    = "reviewed"
+)
+
+// OutageReview tracks the postmortem review of a resolved outage
+type OutageReview struct {
+	OutageID     uuid.UUID  `json:"outage_id"`
+	Status       string     `json:"status"`                  // needs-review, review-scheduled, reviewed
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"` // When the review meeting is booked
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	Reviewer     string     `json:"reviewer,omitempty"` // Who marked the outage reviewed
+	CreatedAt    time.Time  `json:"created_at"`         // When the outage entered the review backlog
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// UpdateReviewRequest represents a change to an outage's review state
+type UpdateReviewRequest struct {
+	Status       string     `json:"status"`
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"` // Required when Status is review-scheduled
+}
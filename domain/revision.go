@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteRevision is a version of a note's content that an edit replaced.
+// Version 1 is the content the note was created with and the note itself
+// holds the latest version. EditedBy and EditedAt identify the edit that
+// replaced this version.
+type NoteRevision struct {
+	ID       uuid.UUID `json:"id"`
+	NoteID   uuid.UUID `json:"note_id"`
+	Version  int       `json:"version"`
+	Content  string    `json:"content"`
+	Format   string    `json:"format"`
+	EditedBy string    `json:"edited_by,omitempty"`
+	EditedAt time.Time `json:"edited_at"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoutingDecision is the combined effect of the routing rules an alert
+// matches
+type RoutingDecision struct {
+	Rules              []string          `json:"rules"`              // Matching rules, in the order applied
+	Severity           string            `json:"severity,omitempty"` // Set when a rule changes the severity
+	Team               string            `json:"team,omitempty"`
+	Service            string            `json:"service,omitempty"`
+	Tags               map[string]string `json:"tags,omitempty"` // Added to the outage opened for the alert, including the team tag
+	AttachToOpenOutage bool              `json:"attach_to_open_outage,omitempty"`
+	Suppress           bool              `json:"suppress,omitempty"`
+}
+
+// RoutingEvaluationRequest asks how routing rules would treat the alerts
+// triggered in a time range, without changing anything
+type RoutingEvaluationRequest struct {
+	Rules  []RoutingRule `json:"rules,omitempty"` // Rules to try; the stored rules when empty
+	Since  time.Time     `json:"since"`           // Default: 7 days before until
+	Until  time.Time     `json:"until"`           // Default: now
+	Source string        `json:"source,omitempty"`
+}
+
+// RoutingEvaluation reports how routing rules treat one past alert
+type RoutingEvaluation struct {
+	AlertID     uuid.UUID       `json:"alert_id"`
+	OutageID    uuid.UUID       `json:"outage_id"` // The outage the alert is linked to now
+	Source      string          `json:"source"`
+	ExternalID  string          `json:"external_id"`
+	Title       string          `json:"title"`
+	Severity    string          `json:"severity"`
+	TriggeredAt time.Time       `json:"triggered_at"`
+	Decision    RoutingDecision `json:"decision"`
+}
+
+// RoutingEvaluationResult is the outcome of a routing rule dry run
+type RoutingEvaluationResult struct {
+	Since      time.Time           `json:"since"`
+	Until      time.Time           `json:"until"`
+	Evaluated  int                 `json:"evaluated"`  // Alerts triggered in the range
+	Matched    int                 `json:"matched"`    // Alerts matching at least one rule
+	Suppressed int                 `json:"suppressed"` // Alerts a rule would drop
+	Alerts     []RoutingEvaluation `json:"alerts"`     // The matched alerts, oldest first
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedView is a named outage filter that can be listed and executed later,
+// and optionally summarised in a Slack channel every day or week
+type SavedView struct {
+	ID     uuid.UUID  `json:"id"`
+	Name   string     `json:"name"`
+	Owner  string     `json:"owner"` // Email of the user who saved the view
+	Filter ViewFilter `json:"filter"`
+	// SlackChannel, when set, is sent a summary of the view's outages every
+	// SummaryPeriod
+	SlackChannel  string     `json:"slack_channel,omitempty"`
+	SummaryPeriod string     `json:"summary_period,omitempty"`  // daily or weekly; set when SlackChannel is
+	LastSummaryAt *time.Time `json:"last_summary_at,omitempty"` // When a summary was last sent
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ViewFilter selects the outages matching every condition it sets. Unset
+// conditions match every outage.
+type ViewFilter struct {
+	Statuses    []string    `json:"statuses,omitempty"`     // Any of these statuses
+	MinSeverity string      `json:"min_severity,omitempty"` // This severity or a more severe one
+	Tags        []TagFilter `json:"tags,omitempty"`         // Every one of these tags
+	Teams       []string    `json:"teams,omitempty"`        // Owned by any of these teams
+}
+
+// TagFilter matches outages tagged with Key. An empty Value matches any
+// value.
+type TagFilter struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// CreateSavedViewRequest represents a request to save a view
+type CreateSavedViewRequest struct {
+	Name          string     `json:"name"`
+	Filter        ViewFilter `json:"filter"`
+	SlackChannel  string     `json:"slack_channel,omitempty"`
+	SummaryPeriod string     `json:"summary_period,omitempty"` // daily or weekly; default daily
+}
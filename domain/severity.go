@@ -0,0 +1,14 @@
+package domain
+
+import "github.com/google/uuid"
+
+// SeverityChange describes a severity update proposed or made by a bulk
+// severity recompute
+type SeverityChange struct {
+	Entity   string    `json:"entity"` // alert or outage
+	ID       uuid.UUID `json:"id"`
+	OutageID uuid.UUID `json:"outage_id"`
+	Source   string    `json:"source"` // Notification source whose mapping was applied
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+}
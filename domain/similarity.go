@@ -0,0 +1,36 @@
+package domain
+
+// NoteMetadataSimilarOutages marks the note listing outages possibly related
+// to a new outage, with the number of outages listed as its value
+const NoteMetadataSimilarOutages = "similar_outages"
+
+// SimilarOutagesAuthor is the author of notes listing similar outages
+const SimilarOutagesAuthor = "outalator"
+
+// Reasons an outage is considered similar to another
+const (
+	SimilarTitle       = "similar_title"
+	SimilarDescription = "similar_description"
+	SimilarTags        = "shared_tags"
+	SimilarTeam        = "same_team"
+)
+
+// DefaultSimilarMinScore is the lowest score an outage needs to be
+// suggested as similar when no minimum is given
+const DefaultSimilarMinScore = 0.3
+
+// SimilarOutage is a past outage that resembles another, scored from 0 to 1
+// by how alike their titles, descriptions, tags and owning teams are
+type SimilarOutage struct {
+	Outage     *Outage  `json:"outage"`
+	Score      float64  `json:"score"`
+	Reasons    []string `json:"reasons"`               // Which of the Similar* reasons apply
+	SharedTags []string `json:"shared_tags,omitempty"` // Tags both outages have, as key=value
+}
+
+// SimilarOutagesPolicy controls the note listing possibly related outages
+// that is added to each new outage
+type SimilarOutagesPolicy struct {
+	MinScore float64 // Lowest score listed; zero means DefaultSimilarMinScore
+	Limit    int     // Most outages listed; zero means 5
+}
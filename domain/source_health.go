@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// IngestionRecord tracks the latest attempts to ingest alerts from a source,
+// whether pushed by webhook or pulled by the alert sync poller
+type IngestionRecord struct {
+	Source        string     `json:"source"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"` // Error from the last failed attempt
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// StalenessPolicy sets how long each alert source may go without a
+// successful ingestion before it is considered stale. Sources missing from
+// Sources use Default; a zero duration disables the check.
+type StalenessPolicy struct {
+	Default time.Duration
+	Sources map[string]time.Duration
+}
+
+// MaxSilence returns the staleness threshold for a source
+func (p StalenessPolicy) MaxSilence(source string) time.Duration {
+	if d, ok := p.Sources[source]; ok {
+		return d
+	}
+	return p.Default
+}
+
+// SourceHealth reports whether alerts are still arriving from a source
+type SourceHealth struct {
+	IngestionRecord
+	MaxSilenceSeconds int  `json:"max_silence_seconds,omitempty"` // Unset when staleness is not checked
+	Stale             bool `json:"stale"`
+}
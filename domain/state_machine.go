@@ -0,0 +1,47 @@
+package domain
+
+// Outage statuses
+const (
+	StatusOpen          = "open"
+	StatusInvestigating = "investigating"
+	StatusMitigated     = "mitigated" // Impact has stopped but the cause is not yet fixed
+	StatusResolved      = "resolved"
+	StatusClosed        = "closed"
+)
+
+// OutageStatuses lists every outage status in lifecycle order
+var OutageStatuses = []string{StatusOpen, StatusInvestigating, StatusMitigated, StatusResolved, StatusClosed}
+
+// OutageTransition is a named action that moves an outage from any of the
+// From statuses to To. Explicit transitions can only be taken by name
+// through a transition request, not by setting the outage's status.
+type OutageTransition struct {
+	Action   string   `json:"action"`
+	From     []string `json:"from"`
+	To       string   `json:"to"`
+	Explicit bool     `json:"explicit,omitempty"`
+}
+
+// DefaultOutageTransitions is the state machine used when none is
+// configured. Reopening a resolved or closed outage is explicit so it
+// cannot happen by accident through a status edit.
+func DefaultOutageTransitions() []OutageTransition {
+	return []OutageTransition{
+		{Action: "investigate", From: []string{StatusOpen, StatusMitigated}, To: StatusInvestigating},
+		{Action: "mitigate", From: []string{StatusOpen, StatusInvestigating}, To: StatusMitigated},
+		{Action: "resolve", From: []string{StatusOpen, StatusInvestigating, StatusMitigated}, To: StatusResolved},
+		{Action: "close", From: []string{StatusOpen, StatusInvestigating, StatusMitigated, StatusResolved}, To: StatusClosed},
+		{Action: "reopen", From: []string{StatusResolved, StatusClosed}, To: StatusOpen, Explicit: true},
+	}
+}
+
+// TransitionRequest asks for a named transition of an outage
+type TransitionRequest struct {
+	Action string `json:"action"`
+	Actor  string `json:"actor,omitempty"`  // Who took the action; set from the signed-in user when known
+	Reason string `json:"reason,omitempty"` // Recorded in the status history
+
+	// ResolveAlerts also resolves the outage's open alerts with their
+	// providers when the transition resolves the outage
+	ResolveAlerts bool `json:"resolve_alerts,omitempty"`
+}
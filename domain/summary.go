@@ -0,0 +1,9 @@
+package domain
+
+// NoteMetadataSummary marks a generated executive summary note, with the
+// email of the user who asked for it as its value, empty when
+// authentication is disabled
+const NoteMetadataSummary = "summary"
+
+// SummaryAuthor is the author of generated summary notes
+const SummaryAuthor = "outalator"
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// SyncCursor records how far alerts from a notification service have been
+// synced, so a restarted sync resumes rather than re-fetching everything
+type SyncCursor struct {
+	Source      string    `json:"source"`
+	SyncedUntil time.Time `json:"synced_until"` // Start time of the last successful sync
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SyncResult summarises one alert sync pass for a notification service
+type SyncResult struct {
+	Source      string    `json:"source"`
+	Since       time.Time `json:"since"` // Alerts created after this time were fetched
+	Fetched     int       `json:"fetched"`
+	Created     int       `json:"created"`              // New alerts stored
+	Updated     int       `json:"updated"`              // Existing alerts whose acknowledged/resolved time changed
+	Suppressed  int       `json:"suppressed,omitempty"` // New alerts dropped by a routing rule
+	Failed      int       `json:"failed"`               // Alerts that could not be stored; the cursor is not advanced
+	SyncedUntil time.Time `json:"synced_until"`
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TagQuery selects outages by their tags. An outage matches when it matches
+// every term.
+type TagQuery struct {
+	Terms []TagTerm
+}
+
+// TagTerm matches outages with a tag that has Key and one of Values, or Key
+// and any value when Values is empty. A negated term matches the outages
+// without such a tag.
+type TagTerm struct {
+	Key    string
+	Values []string
+	Negate bool
+}
+
+// ParseTagTerm parses a term written as key, key:value or key:value,value,
+// prefixed with ! to negate it. The key ends at the first colon, so values
+// may contain colons but not commas.
+func ParseTagTerm(s string) (TagTerm, error) {
+	var term TagTerm
+	if rest, ok := strings.CutPrefix(s, "!"); ok {
+		term.Negate, s = true, rest
+	}
+	key, values, hasValues := strings.Cut(s, ":")
+	term.Key = strings.TrimSpace(key)
+	if term.Key == "" {
+		return TagTerm{}, fmt.Errorf("tag term %q has no key: %w", s, ErrInvalidInput)
+	}
+	if hasValues {
+		for _, v := range strings.Split(values, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				term.Values = append(term.Values, v)
+			}
+		}
+		if len(term.Values) == 0 {
+			return TagTerm{}, fmt.Errorf("tag term %q has no values after the colon: %w", s, ErrInvalidInput)
+		}
+	}
+	return term, nil
+}
+
+// String writes the term as ParseTagTerm reads it
+func (t TagTerm) String() string {
+	s := t.Key
+	if len(t.Values) > 0 {
+		s += ":" + strings.Join(t.Values, ",")
+	}
+	if t.Negate {
+		s = "!" + s
+	}
+	return s
+}
+
+// Matches reports whether an outage with these tags is selected by the query
+func (q TagQuery) Matches(tags []Tag) bool {
+	for _, term := range q.Terms {
+		if term.matchesAny(tags) == term.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny reports whether any of the tags has the term's key and one of
+// its values, ignoring negation
+func (t TagTerm) matchesAny(tags []Tag) bool {
+	for _, tag := range tags {
+		if tag.Key == t.Key && (len(t.Values) == 0 || slices.Contains(t.Values, tag.Value)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+package domain
+
+// NoteThread is a top-level note with its replies, oldest reply first.
+// Threads are one level deep: replying to a reply joins the same thread.
+type NoteThread struct {
+	Note    *Note   `json:"note"`
+	Replies []*Note `json:"replies"`
+}
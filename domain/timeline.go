@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Timeline event types emitted by the outage timeline.
+const (
+	TimelineOutageCreated       = "outage_created"
+	TimelineStatusChanged       = "status_changed"
+	TimelineAlertTriggered      = "alert_triggered"
+	TimelineAlertAcknowledged   = "alert_acknowledged"
+	TimelineAlertResolved       = "alert_resolved"
+	TimelineAlertEvent          = "alert_event"
+	TimelineNoteAdded           = "note_added"
+	TimelineTagAdded            = "tag_added"
+	TimelineResponderAssigned   = "responder_assigned"
+	TimelineResponderUnassigned = "responder_unassigned"
+	TimelineActionItemAdded     = "action_item_added"
+	TimelineActionItemClosed    = "action_item_closed"
+	TimelineRelationAdded       = "relation_added"
+)
+
+// StatusChange records a single transition of an outage's status
+type StatusChange struct {
+	ID         uuid.UUID `json:"id"`
+	OutageID   uuid.UUID `json:"outage_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Action     string    `json:"action,omitempty"` // State machine action taken, e.g. mitigate
+	Actor      string    `json:"actor,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// TimelineEvent is a single entry in an outage's chronological history.
+// EntityID refers to the alert, alert event, note, tag, status change,
+// responder assignment, action item, outage relation or outage that
+// produced the event, depending on Type. Events of a child outage shown in
+// its parent's timeline carry the child's ID in ChildOutageID.
+type TimelineEvent struct {
+	Timestamp     time.Time      `json:"timestamp"`
+	Type          string         `json:"type"`
+	Summary       string         `json:"summary"`
+	Actor         string         `json:"actor,omitempty"`
+	EntityID      uuid.UUID      `json:"entity_id"`
+	ChildOutageID *uuid.UUID     `json:"child_outage_id,omitempty"`
+	Details       map[string]any `json:"details,omitempty"`
+}
@@ -0,0 +1,8 @@
+package domain
+
+// PurgeResult summarises one pass permanently deleting outages and notes
+// that stayed in the trash past the retention period
+type PurgeResult struct {
+	Outages int `json:"outages"` // Outages deleted, along with everything attached to them
+	Notes   int `json:"notes"`   // Notes deleted from outages that are still live
+}
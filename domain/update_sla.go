@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdatePolicy sets how often an active outage needs a status update, keyed
+// by severity. Outages whose severity has no interval have no update SLA.
+type UpdatePolicy map[string]time.Duration
+
+// UpdateSLA is the status update deadline of an active outage. An update is
+// a note or a status change; notes recording automatic resolutions do not
+// count.
+type UpdateSLA struct {
+	OutageID        uuid.UUID `json:"outage_id"`
+	Title           string    `json:"title"`
+	Severity        string    `json:"severity"`
+	IntervalSeconds int       `json:"interval_seconds"` // Required time between updates
+	LastUpdateAt    time.Time `json:"last_update_at"`   // Latest update, or when the outage was created
+	DueAt           time.Time `json:"due_at"`
+	Overdue         bool      `json:"overdue"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Watch channels: how a watcher is notified about an outage
+const (
+	WatchChannelSlack = "slack" // Direct message from the Slack bot
+	WatchChannelEmail = "email"
+)
+
+// Watcher is a user subscribed to an outage's status changes and new notes.
+// A user watches an outage at most once; watching it again changes the
+// channel. Watchers are removed along with their outage.
+type Watcher struct {
+	OutageID  uuid.UUID `json:"outage_id"`
+	Email     string    `json:"email"`
+	Channel   string    `json:"channel"` // slack or email
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WatchOutageRequest subscribes the requesting user to an outage
+type WatchOutageRequest struct {
+	Channel string `json:"channel"` // slack or email; default email
+}
@@ -0,0 +1,24 @@
+// Package ai writes about outages with a large language model, giving teams
+// without an MCP client generated summaries inside Outalator itself. Models
+// are reached through a Client; OpenAI talks to any OpenAI-compatible chat
+// completions endpoint, hosted or self-run.
+package ai
+
+import "context"
+
+// Roles of chat messages
+const (
+	RoleSystem = "system"
+	RoleUser   = "user"
+)
+
+// Message is a chat message sent to a model
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Client sends a conversation to a language model and returns its reply
+type Client interface {
+	Complete(ctx context.Context, messages []Message) (string, error)
+}
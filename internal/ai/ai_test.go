@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestOpenAIComplete(t *testing.T) {
+	var got completionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s, want /v1/chat/completions", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "All good."}}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewOpenAI(srv.URL+"/v1/", "secret", "gpt-4o-mini", 0, nil)
+	reply, err := client.Complete(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "All good." {
+		t.Errorf("reply = %q", reply)
+	}
+	if got.Model != "gpt-4o-mini" || len(got.Messages) != 1 || got.Messages[0].Content != "hi" {
+		t.Errorf("request = %+v", got)
+	}
+}
+
+func TestOpenAICompleteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"error status", http.StatusTooManyRequests, `{"error": {"message": "rate limited"}}`},
+		{"no choices", http.StatusOK, `{"choices": []}`},
+		{"invalid body", http.StatusOK, `not json`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			if _, err := NewOpenAI(srv.URL, "", "m", 0, nil).Complete(context.Background(), nil); err == nil {
+				t.Error("Complete succeeded")
+			}
+		})
+	}
+}
+
+// fakeClient returns a canned reply and records the messages sent
+type fakeClient struct {
+	reply    string
+	messages []Message
+}
+
+func (f *fakeClient) Complete(_ context.Context, messages []Message) (string, error) {
+	f.messages = messages
+	return f.reply, nil
+}
+
+func TestSummarize(t *testing.T) {
+	created := time.Date(2024, 7, 1, 9, 45, 0, 0, time.UTC)
+	resolved := created.Add(30 * time.Minute)
+	outage := &domain.Outage{
+		Title:            "Checkout down",
+		Severity:         "critical",
+		Status:           domain.StatusResolved,
+		CreatedAt:        created,
+		ResolvedAt:       &resolved,
+		AffectedServices: []string{"checkout"},
+		CustomerImpact:   true,
+	}
+	timeline := []domain.TimelineEvent{
+		{Timestamp: created, Type: domain.TimelineOutageCreated, Summary: "Outage created: Checkout down"},
+		{Timestamp: created.Add(10 * time.Minute), Type: domain.TimelineNoteAdded, Summary: "Note added", Actor: "alice",
+			Details: map[string]any{"content": "Failed over the primary database"}},
+	}
+
+	client := &fakeClient{reply: "  Checkout was down for 30 minutes.\n"}
+	summary, err := NewSummarizer(client).Summarize(context.Background(), outage, timeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != "Checkout was down for 30 minutes." {
+		t.Errorf("summary = %q", summary)
+	}
+	if len(client.messages) != 2 || client.messages[0].Role != RoleSystem {
+		t.Fatalf("messages = %+v", client.messages)
+	}
+	record := client.messages[1].Content
+	for _, want := range []string{
+		"Outage: Checkout down",
+		"Resolved: 2024-07-01T10:15:00Z (after 30m0s)",
+		"Affected services: checkout",
+		"2024-07-01T09:55:00Z  Note added (alice)\n    Failed over the primary database",
+	} {
+		if !strings.Contains(record, want) {
+			t.Errorf("outage record missing %q:\n%s", want, record)
+		}
+	}
+
+	client.reply = " "
+	if _, err := NewSummarizer(client).Summarize(context.Background(), outage, timeline); err == nil {
+		t.Error("Summarize accepted an empty summary")
+	}
+}
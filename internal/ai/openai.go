@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the OpenAI API, used when no base URL is configured
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// defaultTimeout bounds a completion when no timeout is configured. Models
+// can take a while to write a few paragraphs.
+const defaultTimeout = 2 * time.Minute
+
+// OpenAI is a Client for OpenAI's chat completions API and the many servers
+// that implement it, such as Azure OpenAI, vLLM, Ollama and LiteLLM
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAI creates a client for the chat completions endpoint under
+// baseURL, empty for OpenAI itself. apiKey, when set, is sent as a bearer
+// token. A zero timeout falls back to the package default.
+func NewOpenAI(baseURL, apiKey, model string, timeout time.Duration, transport http.RoundTripper) *OpenAI {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &OpenAI{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+	}
+}
+
+// completionRequest is the body of a chat completions request
+type completionRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+}
+
+// completionResponse holds the parts of a chat completions response the
+// client reads
+type completionResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+}
+
+// Complete implements Client, returning the content of the first choice
+func (c *OpenAI) Complete(ctx context.Context, messages []Message) (string, error) {
+	payload, err := json.Marshal(completionRequest{Model: c.model, Messages: messages})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("chat completions API error: %s (status: %d)", string(body), resp.StatusCode)
+	}
+	var completion completionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", errors.New("chat completions API returned no choices")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
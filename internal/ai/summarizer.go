@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// summaryInstructions is the system prompt for executive summaries
+const summaryInstructions = "You write executive summaries of production outages for leadership who were not involved in the response. " +
+	"In a few short markdown paragraphs, cover what happened, the impact on services and customers, how long it lasted, " +
+	"the current status, the cause if it is known and what is being done about it. " +
+	"Only state what the outage record supports, write \"not yet known\" rather than guessing, and do not include a title."
+
+// Summarizer writes executive summaries of outages with a language model
+type Summarizer struct {
+	client Client
+}
+
+// NewSummarizer creates a summarizer using client
+func NewSummarizer(client Client) *Summarizer {
+	return &Summarizer{client: client}
+}
+
+// Summarize implements service.OutageSummarizer, sending the outage and
+// its timeline, notes included, to the model
+func (s *Summarizer) Summarize(ctx context.Context, outage *domain.Outage, timeline []domain.TimelineEvent) (string, error) {
+	summary, err := s.client.Complete(ctx, []Message{
+		{Role: RoleSystem, Content: summaryInstructions},
+		{Role: RoleUser, Content: outageRecord(outage, timeline)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize outage: %w", err)
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", errors.New("failed to summarize outage: model returned an empty summary")
+	}
+	return summary, nil
+}
+
+// outageRecord renders an outage and its timeline as plain text for a
+// prompt, with the content of notes indented under the events adding them
+func outageRecord(outage *domain.Outage, timeline []domain.TimelineEvent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Outage: %s\n", outage.Title)
+	fmt.Fprintf(&b, "Severity: %s\n", outage.Severity)
+	fmt.Fprintf(&b, "Status: %s\n", outage.Status)
+	if outage.OwningTeam != "" {
+		fmt.Fprintf(&b, "Owning team: %s\n", outage.OwningTeam)
+	}
+	fmt.Fprintf(&b, "Created: %s\n", outage.CreatedAt.UTC().Format(time.RFC3339))
+	if outage.ResolvedAt != nil {
+		fmt.Fprintf(&b, "Resolved: %s (after %s)\n", outage.ResolvedAt.UTC().Format(time.RFC3339),
+			outage.ResolvedAt.Sub(outage.CreatedAt).Round(time.Minute))
+	}
+	if len(outage.AffectedServices) > 0 {
+		fmt.Fprintf(&b, "Affected services: %s\n", strings.Join(outage.AffectedServices, ", "))
+	}
+	fmt.Fprintf(&b, "Customer impact: %t\n", outage.CustomerImpact)
+	if outage.ImpactStartedAt != nil {
+		fmt.Fprintf(&b, "Impact started: %s\n", outage.ImpactStartedAt.UTC().Format(time.RFC3339))
+	}
+	if outage.ImpactEndedAt != nil {
+		fmt.Fprintf(&b, "Impact ended: %s\n", outage.ImpactEndedAt.UTC().Format(time.RFC3339))
+	}
+	if outage.Description != "" {
+		fmt.Fprintf(&b, "\nDescription:\n%s\n", outage.Description)
+	}
+
+	b.WriteString("\nTimeline (UTC):\n")
+	for _, e := range timeline {
+		line := e.Timestamp.UTC().Format(time.RFC3339) + "  " + e.Summary
+		if e.Actor != "" {
+			line += " (" + e.Actor + ")"
+		}
+		b.WriteString(line + "\n")
+		if content, ok := e.Details["content"].(string); ok && e.Type == domain.TimelineNoteAdded {
+			for _, l := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+				b.WriteString("    " + l + "\n")
+			}
+		}
+	}
+	return b.String()
+}
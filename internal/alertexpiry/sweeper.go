@@ -0,0 +1,72 @@
+// Package alertexpiry periodically resolves alerts that have stayed open for
+// longer than the alert resolution policy allows, so alerts from abandoned
+// or misconfigured sources do not keep their outages open forever.
+package alertexpiry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// defaultInterval is the time between sweeps when none is configured
+const defaultInterval = 15 * time.Minute
+
+// Expirer is the subset of the service layer the sweeper drives
+type Expirer interface {
+	ExpireStaleAlerts(ctx context.Context, now time.Time) (*domain.AlertExpiryResult, error)
+}
+
+// Sweeper resolves stale alerts on a fixed interval
+type Sweeper struct {
+	expirer  Expirer
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewSweeper creates a sweeper for the given service. A zero interval falls
+// back to the package default.
+func NewSweeper(expirer Expirer, interval time.Duration, logger *slog.Logger) *Sweeper {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Sweeper{expirer: expirer, interval: interval, logger: logger}
+}
+
+// Run sweeps immediately and then every interval until ctx is cancelled
+func (s *Sweeper) Run(ctx context.Context) {
+	s.SweepOnce(ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SweepOnce(ctx)
+		}
+	}
+}
+
+// SweepOnce runs a single sweep, logging what it resolved
+func (s *Sweeper) SweepOnce(ctx context.Context) {
+	result, err := s.expirer.ExpireStaleAlerts(ctx, time.Now())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "stale alert sweep failed", "error", err)
+		return
+	}
+	if result.Expired == 0 && result.Failed == 0 {
+		return
+	}
+	level := slog.LevelInfo
+	if result.Failed > 0 {
+		level = slog.LevelWarn
+	}
+	s.logger.Log(ctx, level, "stale alert sweep complete",
+		"expired", result.Expired,
+		"outages_resolved", result.OutagesResolved,
+		"failed", result.Failed)
+}
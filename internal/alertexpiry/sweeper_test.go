@@ -0,0 +1,53 @@
+package alertexpiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeExpirer struct {
+	swept chan time.Time
+}
+
+func (f *fakeExpirer) ExpireStaleAlerts(_ context.Context, now time.Time) (*domain.AlertExpiryResult, error) {
+	select {
+	case f.swept <- now:
+	default:
+	}
+	return &domain.AlertExpiryResult{}, nil
+}
+
+func TestRun_SweepsImmediatelyAndStopsOnCancel(t *testing.T) {
+	expirer := &fakeExpirer{swept: make(chan time.Time, 1)}
+	s := NewSweeper(expirer, time.Hour, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-expirer.swept:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not sweep on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewSweeper_DefaultInterval(t *testing.T) {
+	s := NewSweeper(&fakeExpirer{}, 0, logging.Discard())
+	if s.interval != defaultInterval {
+		t.Errorf("interval = %v, want %v", s.interval, defaultInterval)
+	}
+}
@@ -0,0 +1,97 @@
+// Package alertsync periodically pulls recent alerts from every registered
+// notification service, so alerts missed by webhooks (or sources without
+// webhooks configured) still open outages and pick up acknowledgement and
+// resolution times.
+package alertsync
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Poller defaults
+const (
+	defaultInterval        = 5 * time.Minute
+	defaultLookback        = 24 * time.Hour
+	defaultInitialLookback = 24 * time.Hour
+)
+
+// Syncer is the subset of the service layer the poller drives
+type Syncer interface {
+	NotificationSources() []string
+	SyncAlerts(ctx context.Context, source string, now time.Time, lookback, initialLookback time.Duration) (*domain.SyncResult, error)
+}
+
+// Config controls how often and how far back the poller syncs. Zero values
+// fall back to the package defaults.
+type Config struct {
+	Interval        time.Duration // Time between sync passes
+	Lookback        time.Duration // Overlap re-fetched before each source's cursor
+	InitialLookback time.Duration // Window fetched for a source that has never been synced
+}
+
+// Poller syncs alerts from every notification service on a fixed interval
+type Poller struct {
+	syncer Syncer
+	cfg    Config
+	logger *slog.Logger
+}
+
+// NewPoller creates a poller for the given service
+func NewPoller(syncer Syncer, cfg Config, logger *slog.Logger) *Poller {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = defaultLookback
+	}
+	if cfg.InitialLookback <= 0 {
+		cfg.InitialLookback = defaultInitialLookback
+	}
+	return &Poller{syncer: syncer, cfg: cfg, logger: logger}
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled
+func (p *Poller) Run(ctx context.Context) {
+	p.SyncOnce(ctx)
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.SyncOnce(ctx)
+		}
+	}
+}
+
+// SyncOnce runs a single sync pass over every notification service. A
+// failure for one source is logged and does not stop the others.
+func (p *Poller) SyncOnce(ctx context.Context) {
+	for _, source := range p.syncer.NotificationSources() {
+		if ctx.Err() != nil {
+			return
+		}
+		result, err := p.syncer.SyncAlerts(ctx, source, time.Now(), p.cfg.Lookback, p.cfg.InitialLookback)
+		if err != nil {
+			p.logger.ErrorContext(ctx, "alert sync failed", "source", source, "error", err)
+			continue
+		}
+		level := slog.LevelInfo
+		if result.Failed > 0 {
+			level = slog.LevelWarn
+		}
+		p.logger.Log(ctx, level, "alert sync complete",
+			"source", source,
+			"since", result.Since,
+			"fetched", result.Fetched,
+			"created", result.Created,
+			"updated", result.Updated,
+			"failed", result.Failed)
+	}
+}
@@ -0,0 +1,87 @@
+package alertsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeSyncer struct {
+	mu       sync.Mutex
+	sources  []string
+	calls    map[string]int
+	lookback time.Duration
+	initial  time.Duration
+	failFor  string
+	synced   chan struct{}
+}
+
+func (f *fakeSyncer) NotificationSources() []string { return f.sources }
+
+func (f *fakeSyncer) SyncAlerts(_ context.Context, source string, _ time.Time, lookback, initialLookback time.Duration) (*domain.SyncResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[source]++
+	f.lookback, f.initial = lookback, initialLookback
+	if f.synced != nil {
+		select {
+		case f.synced <- struct{}{}:
+		default:
+		}
+	}
+	if source == f.failFor {
+		return nil, errors.New("provider unavailable")
+	}
+	return &domain.SyncResult{Source: source}, nil
+}
+
+func TestSyncOnce_ContinuesAfterSourceFailure(t *testing.T) {
+	syncer := &fakeSyncer{sources: []string{"opsgenie", "pagerduty"}, calls: map[string]int{}, failFor: "opsgenie"}
+	p := NewPoller(syncer, Config{}, logging.Discard())
+
+	p.SyncOnce(context.Background())
+
+	for _, source := range syncer.sources {
+		if syncer.calls[source] != 1 {
+			t.Errorf("SyncAlerts(%s) called %d times, want 1", source, syncer.calls[source])
+		}
+	}
+	if syncer.lookback != defaultLookback || syncer.initial != defaultInitialLookback {
+		t.Errorf("lookbacks = %v, %v; want defaults %v, %v", syncer.lookback, syncer.initial, defaultLookback, defaultInitialLookback)
+	}
+}
+
+func TestRun_SyncsImmediatelyAndStopsOnCancel(t *testing.T) {
+	syncer := &fakeSyncer{sources: []string{"pagerduty"}, calls: map[string]int{}, synced: make(chan struct{}, 1)}
+	p := NewPoller(syncer, Config{Interval: time.Hour, Lookback: time.Minute, InitialLookback: time.Hour}, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-syncer.synced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not sync on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	syncer.mu.Lock()
+	defer syncer.mu.Unlock()
+	if syncer.lookback != time.Minute || syncer.initial != time.Hour {
+		t.Errorf("lookbacks = %v, %v; want configured 1m, 1h", syncer.lookback, syncer.initial)
+	}
+}
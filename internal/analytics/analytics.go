@@ -0,0 +1,53 @@
+// Package analytics periodically exports the outages, alerts and notes
+// changed since the last export to object storage, for loading into a data
+// warehouse.
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/schedule"
+)
+
+// defaultInterval is the time between exports when none is configured
+const defaultInterval = time.Hour
+
+// Exporter is the subset of the service layer the scheduler drives
+type Exporter interface {
+	ExportAnalytics(ctx context.Context, now time.Time) ([]domain.TableExport, error)
+}
+
+// Scheduler exports changed rows on a fixed interval
+type Scheduler struct {
+	*schedule.Runner
+	exporter Exporter
+	logger   *slog.Logger
+}
+
+// NewScheduler creates a scheduler for the given service. A zero interval
+// falls back to the package default.
+func NewScheduler(exporter Exporter, interval time.Duration, logger *slog.Logger) *Scheduler {
+	s := &Scheduler{exporter: exporter, logger: logger}
+	s.Runner = schedule.New(s.ExportOnce, interval, defaultInterval)
+	return s
+}
+
+// ExportOnce exports the rows changed since the last export, logging how
+// many were written
+func (s *Scheduler) ExportOnce(ctx context.Context) {
+	exports, err := s.exporter.ExportAnalytics(ctx, time.Now())
+	rows := 0
+	for _, export := range exports {
+		rows += export.Rows
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "analytics export failed", "error", err, "rows", rows)
+		return
+	}
+	if rows > 0 {
+		s.logger.InfoContext(ctx, "exported analytics", "tables", len(exports), "rows", rows)
+	}
+}
@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeExporter struct {
+	exported chan time.Time
+}
+
+func (f *fakeExporter) ExportAnalytics(_ context.Context, now time.Time) ([]domain.TableExport, error) {
+	select {
+	case f.exported <- now:
+	default:
+	}
+	return nil, nil
+}
+
+func TestRun_ExportsImmediatelyAndStopsOnCancel(t *testing.T) {
+	exporter := &fakeExporter{exported: make(chan time.Time, 1)}
+	s := NewScheduler(exporter, time.Hour, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-exporter.exported:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not export on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewScheduler_DefaultInterval(t *testing.T) {
+	s := NewScheduler(&fakeExporter{}, 0, logging.Discard())
+	if s.Interval() != defaultInterval {
+		t.Errorf("Interval() = %v, want %v", s.Interval(), defaultInterval)
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CreateActionItem handles POST /api/v1/outages/{id}/action-items. The
+// signed-in user is recorded as having added the item.
+func (h *Handler) CreateActionItem(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	var req domain.CreateActionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	item, err := h.service.CreateActionItem(r.Context(), id, requestUser(r), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Outage not found")
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, item)
+}
+
+// ListActionItems handles GET /api/v1/outages/{id}/action-items
+func (h *Handler) ListActionItems(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	items, err := h.service.ListActionItems(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"action_items": items,
+	})
+}
+
+// GetActionItem handles GET /api/v1/action-items/{id}
+func (h *Handler) GetActionItem(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid action item ID")
+		return
+	}
+
+	item, err := h.service.GetActionItem(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Action item not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, item)
+}
+
+// UpdateActionItem handles PATCH /api/v1/action-items/{id}
+func (h *Handler) UpdateActionItem(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid action item ID")
+		return
+	}
+
+	var req domain.UpdateActionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	item, err := h.service.UpdateActionItem(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Action item not found")
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, item)
+}
+
+// DeleteActionItem handles DELETE /api/v1/action-items/{id}
+func (h *Handler) DeleteActionItem(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid action item ID")
+		return
+	}
+
+	if err := h.service.DeleteActionItem(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Action item not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOverdueActionItems handles GET /api/v1/reports/overdue-action-items,
+// listing the open action items past their due date per owning team. The
+// optional team parameter limits the report to one team.
+func (h *Handler) GetOverdueActionItems(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.GetOverdueActionItems(r.Context(), r.URL.Query().Get("team"), time.Now().UTC())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestActionItemRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	cfg := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"alice@example.com"}}}}
+	if _, err := h.service.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	due := time.Now().Add(-48 * time.Hour)
+	item, err := h.service.CreateActionItem(ctx, outage.ID, "", domain.CreateActionItemRequest{Description: "add retries", DueDate: &due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	bob := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+	url := "/api/v1/outages/" + outage.ID.String() + "/action-items"
+	itemURL := "/api/v1/action-items/" + item.ID.String()
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		user     *auth.UserInfo
+		wantCode int
+		wantBody string
+	}{
+		{"create", http.MethodPost, url, `{"description":"write runbook","assignee":"alice@example.com"}`, alice, http.StatusCreated, `"created_by":"alice@example.com"`},
+		{"create without description", http.MethodPost, url, `{"assignee":"alice@example.com"}`, alice, http.StatusBadRequest, ""},
+		{"other team cannot create", http.MethodPost, url, `{"description":"nope"}`, bob, http.StatusForbidden, ""},
+		{"list", http.MethodGet, url, "", nil, http.StatusOK, `"description":"write runbook"`},
+		{"get", http.MethodGet, itemURL, "", nil, http.StatusOK, `"status":"open"`},
+		{"overdue report", http.MethodGet, "/api/v1/reports/overdue-action-items", "", nil, http.StatusOK, `"days_overdue":2`},
+		{"overdue report for another team", http.MethodGet, "/api/v1/reports/overdue-action-items?team=search", "", nil, http.StatusOK, `"total":0`},
+		{"other team cannot update", http.MethodPatch, itemURL, `{"status":"done"}`, bob, http.StatusForbidden, ""},
+		{"unknown status", http.MethodPatch, itemURL, `{"status":"finished"}`, alice, http.StatusBadRequest, ""},
+		{"close", http.MethodPatch, itemURL, `{"status":"done"}`, alice, http.StatusOK, `"closed_at"`},
+		{"overdue report after closing", http.MethodGet, "/api/v1/reports/overdue-action-items", "", nil, http.StatusOK, `"total":0`},
+		{"other team cannot delete", http.MethodDelete, itemURL, "", bob, http.StatusForbidden, ""},
+		{"delete", http.MethodDelete, itemURL, "", alice, http.StatusNoContent, ""},
+		{"get deleted", http.MethodGet, itemURL, "", nil, http.StatusNotFound, ""},
+		{"invalid ID", http.MethodGet, "/api/v1/action-items/nope", "", nil, http.StatusBadRequest, ""},
+		{"action items of missing outage", http.MethodGet, "/api/v1/outages/00000000-0000-0000-0000-000000000000/action-items", "", nil, http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, body)
+			if tt.user != nil {
+				req = req.WithContext(testutil.WithUser(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rr.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListAlerts handles GET /api/v1/outages/{id}/alerts
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	alerts, err := h.service.ListAlertsByOutage(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if alerts == nil {
+		alerts = []*domain.Alert{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"alerts": alerts})
+}
+
+// SearchAlerts handles GET /api/v1/alerts, listing alerts across outages,
+// most recently triggered first. source, team and severity filter on exact
+// values; team matches every team an alert was routed to. since and until
+// are RFC 3339 timestamps bounding when alerts were triggered, and resolved
+// is true or false to list only resolved or unresolved alerts. limit and
+// offset page through the results.
+func (h *Handler) SearchAlerts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := domain.AlertQuery{
+		Source:   query.Get("source"),
+		Team:     query.Get("team"),
+		Severity: query.Get("severity"),
+	}
+	for name, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if v := query.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid "+name+" timestamp")
+				return
+			}
+			*t = parsed
+		}
+	}
+	if v := query.Get("resolved"); v != "" {
+		resolved, err := strconv.ParseBool(v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "resolved must be true or false")
+			return
+		}
+		q.Resolved = &resolved
+	}
+	var ok bool
+	if q.Limit, q.Offset, ok = parsePage(w, r); !ok {
+		return
+	}
+
+	alerts, err := h.service.ListAlerts(r.Context(), q)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if alerts == nil {
+		alerts = []*domain.Alert{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"alerts": alerts})
+}
+
+// GetAlert handles GET /api/v1/alerts/{id}
+func (h *Handler) GetAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid alert ID")
+		return
+	}
+
+	alert, err := h.service.GetAlert(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Alert not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alert)
+}
+
+// GetAlertByExternalID handles GET /api/v1/sources/{source}/alerts/{external_id}
+func (h *Handler) GetAlertByExternalID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	alert, err := h.service.GetAlertByExternalID(r.Context(), vars["external_id"], vars["source"])
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Alert not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alert)
+}
+
+// UpdateAlert handles PATCH /api/v1/alerts/{id}
+func (h *Handler) UpdateAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid alert ID")
+		return
+	}
+
+	var req domain.UpdateAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	alert, err := h.service.UpdateAlert(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Alert not found")
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alert)
+}
+
+// AcknowledgeAlert handles POST /api/v1/alerts/{id}/acknowledge,
+// acknowledging the alert with its provider as the signed-in user
+func (h *Handler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	h.upstreamAlertAction(w, r, h.service.AcknowledgeAlert)
+}
+
+// ResolveAlert handles POST /api/v1/alerts/{id}/resolve, resolving the alert
+// with its provider as the signed-in user
+func (h *Handler) ResolveAlert(w http.ResponseWriter, r *http.Request) {
+	h.upstreamAlertAction(w, r, h.service.ResolveAlert)
+}
+
+// upstreamAlertAction runs an action on an alert with its provider and
+// responds with the updated alert. Provider failures are reported as 502.
+func (h *Handler) upstreamAlertAction(w http.ResponseWriter, r *http.Request, action func(context.Context, uuid.UUID, string) (*domain.Alert, error)) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid alert ID")
+		return
+	}
+
+	alert, err := action(r.Context(), id, requestUserEmail(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrForbidden):
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			h.logger.ErrorContext(r.Context(), "alert action failed upstream", "alert_id", id, "path", r.URL.Path, "error", err)
+			respondError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alert)
+}
+
+// PageOutage handles POST /api/v1/outages/{id}/page, paging a service or
+// team through a paging provider and linking the resulting alert to the
+// outage. The signed-in user is sent to the provider as the requester.
+// Provider failures are reported as 502.
+func (h *Handler) PageOutage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	var req domain.PageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	alert, err := h.service.PageOutage(r.Context(), id, req, requestUserEmail(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrForbidden):
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			h.logger.ErrorContext(r.Context(), "failed to page outage", "outage_id", id, "source", req.Source, "error", err)
+			respondError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, alert)
+}
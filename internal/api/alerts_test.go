@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/notification"
+	"github.com/conall/outalator/notification/mock"
+	"github.com/google/uuid"
+)
+
+func TestAlertRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	alert, err := h.service.IngestAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "fake", Title: "db down", Severity: "high", TriggeredAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, url, nil)
+		if body != nil {
+			req = httptest.NewRequest(method, url, encodeJSON(t, body))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/api/v1/outages/"+alert.OutageID.String()+"/alerts", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list alerts = %d, want 200", rr.Code)
+	}
+	var list struct {
+		Alerts []domain.Alert `json:"alerts"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.Alerts) != 1 || list.Alerts[0].ID != alert.ID {
+		t.Errorf("alerts = %+v", list.Alerts)
+	}
+
+	if rr := serve(http.MethodGet, "/api/v1/alerts/"+alert.ID.String(), nil); rr.Code != http.StatusOK {
+		t.Errorf("get alert = %d, want 200", rr.Code)
+	}
+	if rr := serve(http.MethodGet, "/api/v1/sources/fake/alerts/A1", nil); rr.Code != http.StatusOK {
+		t.Errorf("get alert by external ID = %d, want 200", rr.Code)
+	}
+	if rr := serve(http.MethodGet, "/api/v1/sources/fake/alerts/missing", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("get unknown external ID = %d, want 404", rr.Code)
+	}
+
+	rr = serve(http.MethodPatch, "/api/v1/alerts/"+alert.ID.String(), map[string]string{"severity": "critical"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update alert = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var updated domain.Alert
+	decodeJSON(t, rr.Body, &updated)
+	if updated.Severity != "critical" {
+		t.Errorf("severity = %q, want critical", updated.Severity)
+	}
+	if rr := serve(http.MethodPatch, "/api/v1/alerts/"+uuid.New().String(), map[string]string{"severity": "low"}); rr.Code != http.StatusNotFound {
+		t.Errorf("update unknown alert = %d, want 404", rr.Code)
+	}
+}
+
+func TestSearchAlerts(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	lastWeek := time.Now().Add(-5 * 24 * time.Hour).UTC().Truncate(time.Second)
+	resolved := time.Now()
+	for _, a := range []*notification.Alert{
+		{ExternalID: "A1", Source: "pagerduty", TeamName: "payments", TeamNames: []string{"payments", "core"}, Title: "db down", Severity: "high", TriggeredAt: lastWeek},
+		{ExternalID: "A2", Source: "pagerduty", TeamName: "search", Title: "slow queries", Severity: "low", TriggeredAt: lastWeek.Add(time.Hour), ResolvedAt: &resolved},
+		{ExternalID: "A3", Source: "opsgenie", TeamName: "core", Title: "disk full", Severity: "high", TriggeredAt: time.Now().Add(-time.Minute)},
+	} {
+		if _, err := h.service.IngestAlert(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	search := func(query string) []string {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/alerts"+query, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET /api/v1/alerts%s = %d, want 200; body: %s", query, rr.Code, rr.Body.String())
+		}
+		var list struct {
+			Alerts []domain.Alert `json:"alerts"`
+		}
+		decodeJSON(t, rr.Body, &list)
+		var ids []string
+		for _, a := range list.Alerts {
+			ids = append(ids, a.ExternalID)
+		}
+		return ids
+	}
+
+	since := url.QueryEscape(lastWeek.Add(-time.Hour).Format(time.RFC3339))
+	until := url.QueryEscape(lastWeek.Add(24 * time.Hour).Format(time.RFC3339))
+	for query, want := range map[string][]string{
+		"":                                    {"A3", "A2", "A1"},
+		"?source=pagerduty&team=core":         {"A1"},
+		"?team=core":                          {"A3", "A1"},
+		"?severity=high&resolved=false":       {"A3", "A1"},
+		"?resolved=true":                      {"A2"},
+		"?since=" + since + "&until=" + until: {"A2", "A1"},
+		"?source=pagerduty&limit=1&offset=1":  {"A1"},
+		"?source=datadog":                     nil,
+	} {
+		if got := search(query); !slices.Equal(got, want) {
+			t.Errorf("GET /api/v1/alerts%s = %v, want %v", query, got, want)
+		}
+	}
+
+	for _, query := range []string{"?resolved=maybe", "?since=yesterday", "?limit=-1", "?since=" + until + "&until=" + since} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/alerts"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("GET /api/v1/alerts%s = %d, want 400", query, rr.Code)
+		}
+	}
+}
+
+// unreachableProvider is a paging provider whose API cannot be reached
+type unreachableProvider struct{ *mock.Service }
+
+func (unreachableProvider) Name() string { return "unreachable" }
+
+func (unreachableProvider) CreateIncident(context.Context, notification.IncidentRequest) (*notification.Alert, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (unreachableProvider) ResolveAlert(context.Context, string, string) error {
+	return errors.New("connection refused")
+}
+
+func TestPageOutage(t *testing.T) {
+	h, router := newTestHandler()
+	provider, err := mock.New(mock.Config{Fixture: &mock.Fixture{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.service.RegisterNotificationService(provider)
+	h.service.RegisterNotificationService(unreachableProvider{provider})
+	ctx := context.Background()
+	cfg := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"alice@example.com"}}}}
+	if _, err := h.service.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	bob := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+	url := "/api/v1/outages/" + outage.ID.String() + "/page"
+
+	tests := []struct {
+		name     string
+		url      string
+		body     map[string]string
+		user     *auth.UserInfo
+		wantCode int
+	}{
+		{"page owning team", url, map[string]string{"source": "mock"}, alice, http.StatusCreated},
+		{"other team cannot page", url, map[string]string{"source": "mock", "team": "payments"}, bob, http.StatusForbidden},
+		{"unknown source", url, map[string]string{"source": "pagerduty"}, alice, http.StatusNotFound},
+		{"provider unreachable", url, map[string]string{"source": "unreachable", "team": "payments"}, alice, http.StatusBadGateway},
+		{"missing outage", "/api/v1/outages/" + uuid.New().String() + "/page", map[string]string{"source": "mock"}, nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, encodeJSON(t, tt.body))
+			if tt.user != nil {
+				req = req.WithContext(testutil.WithUser(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+		})
+	}
+
+	alerts, err := h.service.ListAlertsByOutage(ctx, outage.ID)
+	if err != nil || len(alerts) != 1 || alerts[0].TeamName != "payments" || alerts[0].Source != "mock" {
+		t.Errorf("outage alerts = %+v, %v, want the payments page", alerts, err)
+	}
+}
+
+func TestUpstreamAlertActions(t *testing.T) {
+	h, router := newTestHandler()
+	provider, err := mock.New(mock.Config{Fixture: &mock.Fixture{Alerts: []mock.FixtureAlert{
+		{ID: "MOCK-1", Title: "db down"},
+		{ID: "MOCK-2", Title: "db replica down"},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.service.RegisterNotificationService(provider)
+	h.service.RegisterNotificationService(unreachableProvider{provider})
+	ctx := context.Background()
+	ingest := func(source, externalID string) *domain.Alert {
+		t.Helper()
+		alert, err := h.service.IngestAlert(ctx, &notification.Alert{ExternalID: externalID, Source: source, Title: externalID, TriggeredAt: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return alert
+	}
+	mocked := ingest("mock", "MOCK-1")
+	unreachable := ingest("unreachable", "U1")
+	unconfigured := ingest("nagios", "N1")
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+	}{
+		{"acknowledge", "/api/v1/alerts/" + mocked.ID.String() + "/acknowledge", http.StatusOK},
+		{"resolve", "/api/v1/alerts/" + mocked.ID.String() + "/resolve", http.StatusOK},
+		{"unconfigured source", "/api/v1/alerts/" + unconfigured.ID.String() + "/resolve", http.StatusBadRequest},
+		{"provider unreachable", "/api/v1/alerts/" + unreachable.ID.String() + "/resolve", http.StatusBadGateway},
+		{"missing alert", "/api/v1/alerts/" + uuid.New().String() + "/acknowledge", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, tt.url, nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+		})
+	}
+	if alert, err := h.service.GetAlert(ctx, mocked.ID); err != nil || alert.AcknowledgedAt == nil || alert.ResolvedAt == nil {
+		t.Errorf("alert after actions = %+v, %v, want acknowledged and resolved", alert, err)
+	}
+
+	// Resolving an outage with resolve_alerts resolves its open alerts too
+	open := ingest("mock", "MOCK-2")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/outages/"+open.OutageID.String()+"/transition",
+		encodeJSON(t, map[string]any{"action": "resolve", "resolve_alerts": true})))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("transition = %d; body: %s", rr.Code, rr.Body.String())
+	}
+	if alert, err := h.service.GetAlert(ctx, open.ID); err != nil || alert.ResolvedAt == nil {
+		t.Errorf("alert after resolving its outage = %+v, %v, want resolved", alert, err)
+	}
+}
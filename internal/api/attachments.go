@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxMultipartMemory is how much of an upload is buffered in memory before
+// the rest spills to a temporary file
+const maxMultipartMemory = 8 << 20
+
+// UploadAttachment handles POST /api/v1/outages/{id}/attachments. The body is
+// multipart/form-data with the content in a file field and an optional
+// note_id field naming the note that references it.
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	outageID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+		if bodylimit.TooLarge(err) {
+			bodylimit.RespondTooLarge(w)
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Expected a multipart/form-data body")
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Missing file field")
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	req := domain.UploadAttachmentRequest{OutageID: outageID, Filename: header.Filename}
+	if value := r.FormValue("note_id"); value != "" {
+		noteID, err := uuid.Parse(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid note ID")
+			return
+		}
+		req.NoteID = &noteID
+	}
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		req.UploadedBy = user.Identity()
+	}
+
+	attachment, err := h.service.UploadAttachment(r.Context(), req, file)
+	if err != nil {
+		if errors.Is(err, domain.ErrAttachmentTooLarge) {
+			respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, attachment)
+}
+
+// ListAttachments handles GET /api/v1/outages/{id}/attachments
+func (h *Handler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	outageID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	attachments, err := h.service.ListAttachments(r.Context(), outageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"attachments": attachments,
+	})
+}
+
+// GetAttachment handles GET /api/v1/attachments/{id}
+func (h *Handler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid attachment ID")
+		return
+	}
+
+	attachment, err := h.service.GetAttachment(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Attachment not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attachment)
+}
+
+// DownloadAttachment handles GET /api/v1/attachments/{id}/content. Images
+// are served inline so the UI can show them; everything else downloads.
+func (h *Handler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid attachment ID")
+		return
+	}
+
+	attachment, content, err := h.service.OpenAttachment(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("ETag", `"`+attachment.SHA256+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.WarnContext(r.Context(), "attachment download interrupted", "attachment_id", id, "error", err)
+	}
+}
+
+// DeleteAttachment handles DELETE /api/v1/attachments/{id}
+func (h *Handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid attachment ID")
+		return
+	}
+
+	if err := h.service.DeleteAttachment(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Attachment not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/blobstore"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+// multipartUpload builds an upload request body with a file field and any
+// extra form fields
+func multipartUpload(t *testing.T, filename, content string, fields map[string]string) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fw, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(fw, content); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf, mw.FormDataContentType()
+}
+
+func TestAttachmentRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	store, err := blobstore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.service.SetAttachmentStore(store, domain.AttachmentPolicy{MaxBytes: 1024})
+
+	ctx := context.Background()
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "api errors", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := h.service.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "log below", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+
+	upload := func(filename, content string, fields map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, contentType := multipartUpload(t, filename, content, fields)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/outages/"+outage.ID.String()+"/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	get := func(url string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return rr
+	}
+
+	rr := upload("api.log", "GET /checkout 500\n", map[string]string{"note_id": note.ID.String()})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("upload = %d %s, want 201", rr.Code, rr.Body)
+	}
+	var attachment domain.Attachment
+	decodeJSON(t, rr.Body, &attachment)
+	if attachment.Filename != "api.log" || attachment.UploadedBy != "alice@example.com" ||
+		attachment.NoteID == nil || *attachment.NoteID != note.ID {
+		t.Errorf("attachment = %+v", attachment)
+	}
+
+	attachmentURL := "/api/v1/attachments/" + attachment.ID.String()
+	rr = get(attachmentURL + "/content")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("download = %d, want 200", rr.Code)
+	}
+	if rr.Body.String() != "GET /checkout 500\n" {
+		t.Errorf("download body = %q", rr.Body)
+	}
+	if got := rr.Header().Get("Content-Disposition"); got != `attachment; filename=api.log` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if got := rr.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+
+	rr = get("/api/v1/outages/" + outage.ID.String() + "/attachments")
+	var list struct {
+		Attachments []domain.Attachment `json:"attachments"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if rr.Code != http.StatusOK || len(list.Attachments) != 1 {
+		t.Errorf("list = %d with %d attachments, want 200 with 1", rr.Code, len(list.Attachments))
+	}
+
+	if rr := upload("page.html", "<html><body>x</body></html>", nil); rr.Code != http.StatusBadRequest {
+		t.Errorf("upload html = %d, want 400", rr.Code)
+	}
+	if rr := upload("big.log", strings.Repeat("x", 2048), nil); rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("upload over policy limit = %d, want 413", rr.Code)
+	}
+
+	limited := bodylimit.Middleware(bodylimit.Limits{Attachment: 256})(router)
+	body, contentType := multipartUpload(t, "big.log", strings.Repeat("x", 512), nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/outages/"+outage.ID.String()+"/attachments", body)
+	req.Header.Set("Content-Type", contentType)
+	req.ContentLength = -1
+	rr = httptest.NewRecorder()
+	limited.ServeHTTP(rr, req)
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("upload over body limit = %d, want 413", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, attachmentURL, nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("delete = %d, want 204", rr.Code)
+	}
+	if rr := get(attachmentURL); rr.Code != http.StatusNotFound {
+		t.Errorf("get deleted attachment = %d, want 404", rr.Code)
+	}
+}
+
+func TestUploadAttachmentWithoutStore(t *testing.T) {
+	h, router := newTestHandler()
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "o", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, contentType := multipartUpload(t, "a.log", "log", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/outages/"+outage.ID.String()+"/attachments", body)
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("upload = %d, want 404", rr.Code)
+	}
+}
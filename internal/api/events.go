@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/internal/events"
+)
+
+// streamKeepAlive is how often an idle event stream sends a comment, so
+// proxies do not close it
+const streamKeepAlive = 25 * time.Second
+
+// streamRetry is the reconnection delay suggested to EventSource clients
+const streamRetry = 5 * time.Second
+
+// StreamEvents handles GET /api/v1/events/stream, a server-sent event
+// stream of outage changes. The severity and team query parameters take
+// comma-separated values to filter by. Missed events are not replayed, so
+// clients should refetch after reconnecting.
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := events.Filter{
+		Severities: splitList(query.Get("severity")),
+		Teams:      splitList(query.Get("team")),
+	}
+
+	// Streams outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	sub, cancel := h.events.Subscribe(filter)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering, e.g. in nginx
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		h.logger.WarnContext(r.Context(), "event stream cannot be flushed", "error", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case e, ok := <-sub:
+			if !ok {
+				// Dropped for falling behind; the client reconnects
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				h.logger.ErrorContext(r.Context(), "failed to encode event", "event_id", e.ID, "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// splitList splits a comma-separated query parameter, dropping empty values
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
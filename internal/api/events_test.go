@@ -0,0 +1,80 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/events"
+)
+
+func TestStreamEvents(t *testing.T) {
+	h, router := newTestHandler()
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/events/stream?severity=critical,high", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	// readMessage returns the fields of the next message on the stream
+	lines := bufio.NewScanner(resp.Body)
+	readMessage := func() map[string]string {
+		t.Helper()
+		fields := map[string]string{}
+		for lines.Scan() {
+			line := lines.Text()
+			if line == "" {
+				return fields
+			}
+			name, value, _ := strings.Cut(line, ": ")
+			fields[name] = value
+		}
+		t.Fatalf("stream ended: %v", lines.Err())
+		return nil
+	}
+
+	// The retry message is sent once the stream is subscribed
+	if msg := readMessage(); msg["retry"] == "" {
+		t.Fatalf("first message = %v, want retry", msg)
+	}
+
+	if _, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "quiet", Severity: "low"}); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "loud", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := readMessage()
+	if msg["event"] != events.TypeOutageCreated {
+		t.Fatalf("event = %q, want %s", msg["event"], events.TypeOutageCreated)
+	}
+	var e events.Event
+	if err := json.Unmarshal([]byte(msg["data"]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Outage.ID != outage.ID {
+		t.Errorf("event outage = %q, want the high severity outage %q", e.Outage.Title, outage.Title)
+	}
+	if msg["id"] != "2" {
+		t.Errorf("id = %q, want 2 after the filtered-out event", msg["id"])
+	}
+}
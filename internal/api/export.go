@@ -0,0 +1,211 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// outageCSVHeader names the columns of a CSV export. Tags are written as
+// key=value, alerts as source:external_id and affected services by name,
+// each joined with semicolons.
+var outageCSVHeader = []string{
+	"id", "title", "description", "status", "severity", "owning_team",
+	"created_at", "updated_at", "investigating_at", "mitigated_at", "resolved_at",
+	"tags", "alerts", "notes",
+	"affected_services", "customer_impact", "impact_started_at", "impact_ended_at",
+}
+
+// ExportOutages handles GET /api/v1/outages/export. format is json (the
+// default) or csv; the optional since and until parameters are RFC 3339
+// timestamps bounding when the outages were created. The JSON export can
+// be fed back to ImportOutages; the CSV export is one row per outage for
+// spreadsheets and carries note counts rather than note content.
+func (h *Handler) ExportOutages(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		respondError(w, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+	var q domain.OutageExportQuery
+	for name, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if v := query.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid "+name+" timestamp")
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	var writer outageWriter
+	if format == "csv" {
+		writer = &csvOutageWriter{w: w}
+	} else {
+		writer = &jsonOutageWriter{w: w}
+	}
+
+	// The response starts with the first outage, so an invalid query can
+	// still be answered with an error status
+	started := false
+	err := h.service.ExportOutages(r.Context(), q, func(outage *domain.Outage) error {
+		if !started {
+			started = true
+			if err := writer.start(); err != nil {
+				return err
+			}
+		}
+		return writer.write(outage)
+	})
+	if err != nil {
+		if !started {
+			h.serviceError(w, r, err)
+			return
+		}
+		// Headers are sent; all that is left is to cut the export short
+		h.logger.ErrorContext(r.Context(), "outage export failed", "error", err)
+		return
+	}
+	if !started {
+		err = writer.start()
+	}
+	if err == nil {
+		err = writer.finish()
+	}
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "outage export failed", "error", err)
+	}
+}
+
+// ImportOutages handles POST /api/v1/outages/import, adding the outages in
+// a JSON export. Only admins can import.
+func (h *Handler) ImportOutages(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can import outages")
+		return
+	}
+
+	var export domain.OutageExport
+	if err := json.NewDecoder(r.Body).Decode(&export); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	result, err := h.service.ImportOutages(r.Context(), export)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// outageWriter streams outages in one export format
+type outageWriter interface {
+	start() error
+	write(outage *domain.Outage) error
+	finish() error
+}
+
+// jsonOutageWriter writes a domain.OutageExport document one outage at a
+// time
+type jsonOutageWriter struct {
+	w     http.ResponseWriter
+	count int
+}
+
+func (j *jsonOutageWriter) start() error {
+	j.w.Header().Set("Content-Type", "application/json")
+	j.w.Header().Set("Content-Disposition", `attachment; filename=outages.json`)
+	j.w.WriteHeader(http.StatusOK)
+
+	exportedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write([]byte(`{"version":` + strconv.Itoa(domain.OutageExportVersion) +
+		`,"exported_at":` + string(exportedAt) + `,"outages":[`))
+	return err
+}
+
+func (j *jsonOutageWriter) write(outage *domain.Outage) error {
+	data, err := json.Marshal(outage)
+	if err != nil {
+		return err
+	}
+	if j.count > 0 {
+		data = append([]byte{','}, data...)
+	}
+	j.count++
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonOutageWriter) finish() error {
+	_, err := j.w.Write([]byte("]}\n"))
+	return err
+}
+
+// csvOutageWriter writes one row per outage under outageCSVHeader
+type csvOutageWriter struct {
+	w   http.ResponseWriter
+	csv *csv.Writer
+}
+
+func (c *csvOutageWriter) start() error {
+	c.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	c.w.Header().Set("Content-Disposition", `attachment; filename=outages.csv`)
+	c.w.WriteHeader(http.StatusOK)
+
+	c.csv = csv.NewWriter(c.w)
+	return c.csv.Write(outageCSVHeader)
+}
+
+func (c *csvOutageWriter) write(outage *domain.Outage) error {
+	tags := make([]string, len(outage.Tags))
+	for i, tag := range outage.Tags {
+		tags[i] = tag.Key + "=" + tag.Value
+	}
+	alerts := make([]string, len(outage.Alerts))
+	for i, alert := range outage.Alerts {
+		alerts[i] = alert.Source + ":" + alert.ExternalID
+	}
+
+	return c.csv.Write([]string{
+		outage.ID.String(), outage.Title, outage.Description, outage.Status,
+		outage.Severity, outage.OwningTeam,
+		csvTime(&outage.CreatedAt), csvTime(&outage.UpdatedAt),
+		csvTime(outage.InvestigatingAt), csvTime(outage.MitigatedAt), csvTime(outage.ResolvedAt),
+		strings.Join(tags, ";"), strings.Join(alerts, ";"), strconv.Itoa(len(outage.Notes)),
+		strings.Join(outage.AffectedServices, ";"), strconv.FormatBool(outage.CustomerImpact),
+		csvTime(outage.ImpactStartedAt), csvTime(outage.ImpactEndedAt),
+	})
+}
+
+func (c *csvOutageWriter) finish() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// csvTime formats an optional timestamp for a CSV cell
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
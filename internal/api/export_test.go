@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestExportOutages(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down, again", Severity: "high",
+		Tags: []domain.TagInput{{Key: "jira", Value: "OPS-1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.service.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "Failing over", Author: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(url string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return rr
+	}
+
+	t.Run("json", func(t *testing.T) {
+		rr := get("/api/v1/outages/export")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d; body: %s", rr.Code, rr.Body.String())
+		}
+		var export domain.OutageExport
+		decodeJSON(t, rr.Body, &export)
+		if export.Version != domain.OutageExportVersion || len(export.Outages) != 1 {
+			t.Fatalf("export = %+v", export)
+		}
+		if got := export.Outages[0]; got.ID != outage.ID || len(got.Notes) != 1 || len(got.Tags) != 1 {
+			t.Errorf("exported outage = %+v, want its note and tag", got)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		rr := get("/api/v1/outages/export?format=csv&until=2999-01-01T00:00:00Z")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d; body: %s", rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		rows, err := csv.NewReader(rr.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 2 || rows[0][0] != "id" {
+			t.Fatalf("rows = %q, want a header and one outage", rows)
+		}
+		if row := rows[1]; row[1] != "DB down, again" || row[11] != "jira=OPS-1" || row[13] != "1" {
+			t.Errorf("row = %q", row)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		rr := get("/api/v1/outages/export?since=2999-01-01T00:00:00Z")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d; body: %s", rr.Code, rr.Body.String())
+		}
+		var export domain.OutageExport
+		decodeJSON(t, rr.Body, &export)
+		if len(export.Outages) != 0 {
+			t.Errorf("exported %d outages, want 0", len(export.Outages))
+		}
+	})
+
+	for _, url := range []string{
+		"/api/v1/outages/export?format=xml",
+		"/api/v1/outages/export?since=yesterday",
+		"/api/v1/outages/export?since=2024-02-01T00:00:00Z&until=2024-01-01T00:00:00Z",
+	} {
+		if rr := get(url); rr.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", url, rr.Code)
+		}
+	}
+}
+
+func TestImportOutages(t *testing.T) {
+	src, srcRouter := newTestHandler()
+	if _, err := src.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "DB down", Severity: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	srcRouter.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/outages/export", nil))
+	export := rr.Body.Bytes()
+
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"admin@example.com"})
+	post := func(body []byte, user *auth.UserInfo) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/outages/import", bytes.NewReader(body))
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+
+	if rr := post(export, &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}); rr.Code != http.StatusForbidden {
+		t.Errorf("member import status = %d, want 403", rr.Code)
+	}
+	if rr := post([]byte(`{"version": 99, "outages": []}`), admin); rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown version status = %d, want 400", rr.Code)
+	}
+	if rr := post([]byte(`not json`), admin); rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid body status = %d, want 400", rr.Code)
+	}
+
+	rr = post(export, admin)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rr.Code, rr.Body.String())
+	}
+	var result domain.OutageImportResult
+	decodeJSON(t, rr.Body, &result)
+	if result.Outages != 1 {
+		t.Errorf("result = %+v, want one outage imported", result)
+	}
+	if outages, _ := h.service.ListOutages(context.Background(), 10, 0); len(outages) != 1 || outages[0].Title != "DB down" {
+		t.Errorf("outages after import = %+v", outages)
+	}
+}
@@ -1,12 +1,23 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/conall/outalator/internal/auth"
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/apierr"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/events"
 	"github.com/conall/outalator/service"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -15,11 +26,14 @@ import (
 // Handler handles HTTP requests
 type Handler struct {
 	service *service.Service
+	events  *events.Broker
+	logger  *slog.Logger
 }
 
-// NewHandler creates a new HTTP handler
-func NewHandler(svc *service.Service) *Handler {
-	return &Handler{service: svc}
+// NewHandler creates a new HTTP handler. broker feeds the event stream and
+// must be registered with svc as an outage listener.
+func NewHandler(svc *service.Service, broker *events.Broker, logger *slog.Logger) *Handler {
+	return &Handler{service: svc, events: broker, logger: logger}
 }
 
 // RegisterRoutes registers all HTTP routes
@@ -27,21 +41,160 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 	// Outage routes
 	r.HandleFunc("/api/v1/outages", h.CreateOutage).Methods("POST")
 	r.HandleFunc("/api/v1/outages", h.ListOutages).Methods("GET")
+	r.HandleFunc("/api/v1/outages/export", h.ExportOutages).Methods("GET")
+	r.HandleFunc("/api/v1/outages/import", h.ImportOutages).Methods("POST")
+	r.HandleFunc("/api/v1/outages/from-template/{name}", h.CreateOutageFromTemplate).Methods("POST")
 	r.HandleFunc("/api/v1/outages/{id}", h.GetOutage).Methods("GET")
 	r.HandleFunc("/api/v1/outages/{id}", h.UpdateOutage).Methods("PATCH")
 	r.HandleFunc("/api/v1/outages/{id}", h.DeleteOutage).Methods("DELETE")
+	r.HandleFunc("/api/v1/outages/{id}/transition", h.TransitionOutage).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/restore", h.RestoreOutage).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/timeline", h.GetOutageTimeline).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/parent", h.SetOutageParent).Methods("PUT")
+	r.HandleFunc("/api/v1/outages/{id}/parent", h.RemoveOutageParent).Methods("DELETE")
+	r.HandleFunc("/api/v1/outages/{id}/children", h.ListChildOutages).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/relations", h.CreateOutageRelation).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/relations", h.ListOutageRelations).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/relations/{relation_id}", h.DeleteOutageRelation).Methods("DELETE")
+	r.HandleFunc("/api/v1/outages/{id}/summarize", h.SummarizeOutage).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/watchers", h.ListWatchers).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/watch", h.WatchOutage).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/watch", h.UnwatchOutage).Methods("DELETE")
+
+	// Responder routes
+	r.HandleFunc("/api/v1/outages/{id}/similar", h.ListSimilarOutages).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/responders", h.ListResponders).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/responders", h.AssignResponder).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/responders/{role}", h.UnassignResponder).Methods("DELETE")
+	r.HandleFunc("/api/v1/oncall", h.ListOnCall).Methods("GET")
+
+	// Review workflow routes
+	r.HandleFunc("/api/v1/outages/{id}/review", h.GetOutageReview).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/review", h.UpdateOutageReview).Methods("PATCH")
+	r.HandleFunc("/api/v1/reviews", h.ListOutageReviews).Methods("GET")
+
+	// Status update SLA routes
+	r.HandleFunc("/api/v1/outages/{id}/update-sla", h.GetUpdateSLA).Methods("GET")
+	r.HandleFunc("/api/v1/update-sla", h.ListUpdateSLAs).Methods("GET")
+
+	// Alert source health
+	r.HandleFunc("/api/v1/sources/health", h.ListSourceHealth).Methods("GET")
+
+	// Historical import runs
+	r.HandleFunc("/api/v1/import-runs", h.ListImportRuns).Methods("GET")
+	r.HandleFunc("/api/v1/import-runs/{id}", h.GetImportRun).Methods("GET")
+
+	// Retention runs
+	r.HandleFunc("/api/v1/retention-runs", h.ListRetentionRuns).Methods("GET")
+	r.HandleFunc("/api/v1/retention-runs", h.StartRetentionRun).Methods("POST")
+	r.HandleFunc("/api/v1/retention-runs/{id}", h.GetRetentionRun).Methods("GET")
+
+	// Presence routes
+	r.HandleFunc("/api/v1/outages/{id}/presence", h.RecordPresence).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/presence", h.GetPresence).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/presence", h.LeavePresence).Methods("DELETE")
+
+	// Live event stream for dashboards
+	r.HandleFunc("/api/v1/events/stream", h.StreamEvents).Methods("GET")
+
+	// Saved view routes
+	r.HandleFunc("/api/v1/views", h.CreateSavedView).Methods("POST")
+	r.HandleFunc("/api/v1/views", h.ListSavedViews).Methods("GET")
+	r.HandleFunc("/api/v1/views/{id}", h.GetSavedView).Methods("GET")
+	r.HandleFunc("/api/v1/views/{id}", h.DeleteSavedView).Methods("DELETE")
+	r.HandleFunc("/api/v1/views/{id}/outages", h.ExecuteSavedView).Methods("GET")
+
+	// Report routes
+	r.HandleFunc("/api/v1/reports/paging-load", h.GetPagingLoad).Methods("GET")
+	r.HandleFunc("/api/v1/reports/noisy-alerts", h.GetNoisyAlerts).Methods("GET")
+	r.HandleFunc("/api/v1/reports/responders", h.GetResponderLoad).Methods("GET")
+	r.HandleFunc("/api/v1/reports/digest", h.GetDigest).Methods("GET")
+	r.HandleFunc("/api/v1/reports/overdue-action-items", h.GetOverdueActionItems).Methods("GET")
+	r.HandleFunc("/api/v1/reports/service-downtime", h.GetServiceDowntime).Methods("GET")
 
 	// Note routes
 	r.HandleFunc("/api/v1/outages/{id}/notes", h.AddNote).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/notes", h.ListNotes).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/notes/threads", h.ListNoteThreads).Methods("GET")
+	r.HandleFunc("/api/v1/notes/{id}", h.GetNote).Methods("GET")
+	r.HandleFunc("/api/v1/notes/{id}", h.UpdateNote).Methods("PATCH")
+	r.HandleFunc("/api/v1/notes/{id}", h.DeleteNote).Methods("DELETE")
+	r.HandleFunc("/api/v1/notes/{id}/rendered", h.RenderNote).Methods("GET")
+	r.HandleFunc("/api/v1/notes/{id}/revisions", h.ListNoteRevisions).Methods("GET")
+	r.HandleFunc("/api/v1/notes/{id}/restore", h.RestoreNote).Methods("POST")
+
+	// Action item routes
+	r.HandleFunc("/api/v1/outages/{id}/action-items", h.CreateActionItem).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/action-items", h.ListActionItems).Methods("GET")
+	r.HandleFunc("/api/v1/action-items/{id}", h.GetActionItem).Methods("GET")
+	r.HandleFunc("/api/v1/action-items/{id}", h.UpdateActionItem).Methods("PATCH")
+	r.HandleFunc("/api/v1/action-items/{id}", h.DeleteActionItem).Methods("DELETE")
+
+	// Attachment routes
+	r.HandleFunc("/api/v1/outages/{id}/attachments", h.UploadAttachment).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/attachments", h.ListAttachments).Methods("GET")
+	r.HandleFunc("/api/v1/attachments/{id}", h.GetAttachment).Methods("GET")
+	r.HandleFunc("/api/v1/attachments/{id}", h.DeleteAttachment).Methods("DELETE")
+	r.HandleFunc("/api/v1/attachments/{id}/content", h.DownloadAttachment).Methods("GET")
 
 	// Tag routes
 	r.HandleFunc("/api/v1/outages/{id}/tags", h.AddTag).Methods("POST")
+	r.HandleFunc("/api/v1/outages/{id}/tags", h.ListTags).Methods("GET")
 	r.HandleFunc("/api/v1/tags/search", h.SearchByTag).Methods("GET")
+	r.HandleFunc("/api/v1/tags/keys", h.ListTagKeys).Methods("GET")
+	r.HandleFunc("/api/v1/tags/values", h.ListTagValues).Methods("GET")
+	r.HandleFunc("/api/v1/tags/{id}", h.GetTag).Methods("GET")
+	r.HandleFunc("/api/v1/tags/{id}", h.UpdateTag).Methods("PATCH")
+	r.HandleFunc("/api/v1/tags/{id}", h.DeleteTag).Methods("DELETE")
 
 	// Alert routes
+	r.HandleFunc("/api/v1/alerts", h.SearchAlerts).Methods("GET")
 	r.HandleFunc("/api/v1/alerts/import", h.ImportAlert).Methods("POST")
-
-	// Health check
+	r.HandleFunc("/api/v1/outages/{id}/alerts", h.ListAlerts).Methods("GET")
+	r.HandleFunc("/api/v1/outages/{id}/page", h.PageOutage).Methods("POST")
+	r.HandleFunc("/api/v1/alerts/{id}", h.GetAlert).Methods("GET")
+	r.HandleFunc("/api/v1/alerts/{id}", h.UpdateAlert).Methods("PATCH")
+	r.HandleFunc("/api/v1/alerts/{id}/acknowledge", h.AcknowledgeAlert).Methods("POST")
+	r.HandleFunc("/api/v1/alerts/{id}/resolve", h.ResolveAlert).Methods("POST")
+	r.HandleFunc("/api/v1/sources/{source}/alerts/{external_id}", h.GetAlertByExternalID).Methods("GET")
+
+	// Custom field schema routes
+	r.HandleFunc("/api/v1/schemas/custom-fields", h.GetCustomFieldSchemas).Methods("GET")
+
+	// The OpenAPI spec requests are validated against
+	r.HandleFunc("/api/v1/openapi.json", h.GetOpenAPISpec).Methods("GET")
+
+	// Declarative operational config routes
+	r.HandleFunc("/api/v1/config", h.GetOpsConfig).Methods("GET")
+	r.HandleFunc("/api/v1/config/apply", h.ApplyOpsConfig).Methods("POST")
+	r.HandleFunc("/api/v1/config/routing-rules/evaluate", h.EvaluateRoutingRules).Methods("POST")
+
+	// Outage template routes
+	r.HandleFunc("/api/v1/templates", h.ListOutageTemplates).Methods("GET")
+	r.HandleFunc("/api/v1/templates/{name}", h.GetOutageTemplate).Methods("GET")
+	r.HandleFunc("/api/v1/templates/{name}", h.SaveOutageTemplate).Methods("PUT")
+	r.HandleFunc("/api/v1/templates/{name}", h.DeleteOutageTemplate).Methods("DELETE")
+
+	// Service catalogue routes
+	r.HandleFunc("/api/v1/services", h.ListServices).Methods("GET")
+	r.HandleFunc("/api/v1/services/sync", h.SyncServices).Methods("POST")
+	r.HandleFunc("/api/v1/services/{name}", h.GetService).Methods("GET")
+	r.HandleFunc("/api/v1/services/{name}", h.SaveService).Methods("PUT")
+	r.HandleFunc("/api/v1/services/{name}", h.DeleteService).Methods("DELETE")
+
+	// Team routes
+	r.HandleFunc("/api/v1/teams", h.ListTeams).Methods("GET")
+	r.HandleFunc("/api/v1/teams/sync", h.SyncTeams).Methods("POST")
+	r.HandleFunc("/api/v1/teams/{name}", h.GetTeam).Methods("GET")
+
+	// User preference routes
+	r.HandleFunc("/api/v1/me/preferences", h.GetPreferences).Methods("GET")
+	r.HandleFunc("/api/v1/me/preferences", h.UpdatePreferences).Methods("PATCH")
+
+	// Liveness and readiness probes. /health predates the Kubernetes-style
+	// names and is kept for existing liveness probes.
+	r.HandleFunc("/healthz", h.Health).Methods("GET")
+	r.HandleFunc("/readyz", h.Ready).Methods("GET")
 	r.HandleFunc("/health", h.Health).Methods("GET")
 }
 
@@ -49,20 +202,26 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 func (h *Handler) CreateOutage(w http.ResponseWriter, r *http.Request) {
 	var req domain.CreateOutageRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondInvalidBody(w, err)
 		return
 	}
 
 	outage, err := h.service.CreateOutage(r.Context(), req)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusCreated, outage)
 }
 
-// ListOutages handles GET /api/v1/outages
+// ListOutages handles GET /api/v1/outages. Admins can pass
+// include_deleted=true to list outages in the trash too, and
+// include_associations=true loads each outage's alerts, notes and tags.
 func (h *Handler) ListOutages(w http.ResponseWriter, r *http.Request) {
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
@@ -70,15 +229,46 @@ func (h *Handler) ListOutages(w http.ResponseWriter, r *http.Request) {
 	if limit <= 0 {
 		limit = 50
 	}
+	includeDeleted, ok := h.parseIncludeDeleted(w, r)
+	if !ok {
+		return
+	}
+	includeAssociations, err := parseBoolParam(r.URL.Query().Get("include_associations"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid include_associations parameter")
+		return
+	}
+	teams, err := h.teamFilter(r)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
 
-	outages, err := h.service.ListOutages(r.Context(), limit, offset)
+	var outages []*domain.Outage
+	switch {
+	case len(teams) > 0 && includeDeleted:
+		outages, err = h.service.ListTeamOutagesIncludingDeleted(r.Context(), teams, limit, offset)
+	case len(teams) > 0:
+		outages, err = h.service.ListTeamOutages(r.Context(), teams, limit, offset)
+	case includeDeleted:
+		outages, err = h.service.ListOutagesIncludingDeleted(r.Context(), limit, offset)
+	default:
+		outages, err = h.service.ListOutages(r.Context(), limit, offset)
+	}
+	if err == nil && includeAssociations {
+		err = h.service.LoadOutageAssociations(r.Context(), outages, domain.AllOutageAssociations)
+	}
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		h.serviceError(w, r, err)
 		return
 	}
+	if teams == nil {
+		teams = []string{}
+	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"outages": outages,
+		"teams":   teams,
 		"limit":   limit,
 		"offset":  offset,
 	})
@@ -113,20 +303,25 @@ func (h *Handler) UpdateOutage(w http.ResponseWriter, r *http.Request) {
 
 	var req domain.UpdateOutageRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondInvalidBody(w, err)
 		return
 	}
 
 	outage, err := h.service.UpdateOutage(r.Context(), id, req)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, outage)
 }
 
-// DeleteOutage handles DELETE /api/v1/outages/{id}
+// DeleteOutage handles DELETE /api/v1/outages/{id}, moving the outage to the
+// trash. Admins can restore it until the retention period purges it.
 func (h *Handler) DeleteOutage(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
@@ -136,13 +331,42 @@ func (h *Handler) DeleteOutage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteOutage(r.Context(), id); err != nil {
-		respondError(w, http.StatusNotFound, "Outage not found")
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage not found")
+			return
+		}
+		h.serviceError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetOutageTimeline handles GET /api/v1/outages/{id}/timeline
+func (h *Handler) GetOutageTimeline(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	events, err := h.service.GetOutageTimeline(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"outage_id": id,
+		"events":    events,
+	})
+}
+
 // AddNote handles POST /api/v1/outages/{id}/notes
 func (h *Handler) AddNote(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -161,16 +385,20 @@ func (h *Handler) AddNote(w http.ResponseWriter, r *http.Request) {
 
 	var req domain.AddNoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondInvalidBody(w, err)
 		return
 	}
 
-	// Override author with authenticated user's email
-	req.Author = user.Email
+	// Override author with the authenticated caller
+	req.Author = user.Identity()
 
 	note, err := h.service.AddNote(r.Context(), id, req)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
 		return
 	}
 
@@ -187,44 +415,82 @@ func (h *Handler) AddTag(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Key   string `json:"key"`
-		Value string `json:"value"`
+		Key          string         `json:"key"`
+		Value        string         `json:"value"`
+		CustomFields map[string]any `json:"custom_fields,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondInvalidBody(w, err)
 		return
 	}
 
-	tag, err := h.service.AddTag(r.Context(), id, req.Key, req.Value)
+	tag, err := h.service.AddTag(r.Context(), id, req.Key, req.Value, req.CustomFields)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusCreated, tag)
 }
 
-// SearchByTag handles GET /api/v1/tags/search?key=...&value=...
+// SearchByTag handles GET /api/v1/tags/search. Outages must match every
+// tag term: key with value, which may list alternatives separated by
+// commas, and each tag parameter, written key, key:value[,value...] or
+// either prefixed with ! to exclude outages with the tag. The optional team
+// parameter keeps only outages owned by the listed teams.
 func (h *Handler) SearchByTag(w http.ResponseWriter, r *http.Request) {
-	key := r.URL.Query().Get("key")
-	value := r.URL.Query().Get("value")
-
-	if key == "" || value == "" {
-		respondError(w, http.StatusBadRequest, "Both key and value parameters are required")
+	query, err := parseTagQuery(r.URL.Query())
+	if err != nil {
+		h.serviceError(w, r, err)
 		return
 	}
 
-	outages, err := h.service.FindOutagesByTag(r.Context(), key, value)
+	outages, err := h.service.FindOutagesByTagQuery(r.Context(), query)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		h.serviceError(w, r, err)
 		return
 	}
+	if teams := parseTeamFilter(r.URL.Query().Get("team")); len(teams) > 0 {
+		outages = filterOutagesByTeam(outages, teams)
+	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"outages": outages,
 	})
 }
 
+// parseTagQuery builds a tag query from the key and value parameters and
+// the tag parameters. A key without a value matches any value.
+func parseTagQuery(params url.Values) (domain.TagQuery, error) {
+	var query domain.TagQuery
+	if key := params.Get("key"); key != "" {
+		term := domain.TagTerm{Key: key}
+		for _, v := range strings.Split(params.Get("value"), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				term.Values = append(term.Values, v)
+			}
+		}
+		query.Terms = append(query.Terms, term)
+	} else if params.Get("value") != "" {
+		return query, fmt.Errorf("the value parameter needs a key: %w", domain.ErrInvalidInput)
+	}
+	for _, raw := range params["tag"] {
+		term, err := domain.ParseTagTerm(raw)
+		if err != nil {
+			return query, err
+		}
+		query.Terms = append(query.Terms, term)
+	}
+	if len(query.Terms) == 0 {
+		return query, fmt.Errorf("a key or tag parameter is required: %w", domain.ErrInvalidInput)
+	}
+	return query, nil
+}
+
 // ImportAlert handles POST /api/v1/alerts/import
 func (h *Handler) ImportAlert(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -233,26 +499,62 @@ func (h *Handler) ImportAlert(w http.ResponseWriter, r *http.Request) {
 		OutageID   *uuid.UUID `json:"outage_id,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+		respondInvalidBody(w, err)
 		return
 	}
 
 	alert, err := h.service.ImportAlert(r.Context(), req.Source, req.ExternalID, req.OutageID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		h.serviceError(w, r, err)
 		return
 	}
 
 	respondJSON(w, http.StatusCreated, alert)
 }
 
-// Health handles GET /health
+// Health handles GET /health and GET /healthz, the liveness probe. It only
+// reports the process is serving requests, so a database outage does not
+// get every replica restarted.
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{
 		"status": "healthy",
 	})
 }
 
+// readyTimeout bounds the dependency checks behind GET /readyz so a stalled
+// database fails the probe instead of hanging it
+const readyTimeout = 2 * time.Second
+
+// Ready handles GET /ready and GET /readyz, the readiness probe. Unlike
+// /healthz it checks the database can be reached, its migrations have been
+// applied and notification providers accept their credentials, responding
+// 503 naming the failed checks otherwise. Failure details are logged
+// rather than returned, as the endpoint is unauthenticated.
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	checks := make(map[string]string)
+	var failed []string
+	for _, check := range h.service.Readiness(ctx) {
+		if check.Err != nil {
+			h.logger.WarnContext(ctx, "readiness check failed", "check", check.Name, "error", check.Err)
+			checks[check.Name] = "failed"
+			failed = append(failed, check.Name)
+			continue
+		}
+		checks[check.Name] = "ok"
+	}
+	if len(failed) > 0 {
+		respondError(w, http.StatusServiceUnavailable, "not ready: "+strings.Join(failed, ", ")+" failed")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"status": "ready",
+		"checks": checks,
+	})
+}
+
 // Helper functions
 
 func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
@@ -262,7 +564,43 @@ func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
 }
 
 func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{
-		"error": message,
-	})
+	apierr.Write(w, status, message)
+}
+
+// respondInvalidBody reports a request body that could not be decoded,
+// with 413 when it exceeded the body size limit
+func respondInvalidBody(w http.ResponseWriter, err error) {
+	if bodylimit.TooLarge(err) {
+		bodylimit.RespondTooLarge(w)
+		return
+	}
+	respondError(w, http.StatusBadRequest, "Invalid request body")
+}
+
+// internalError logs err against the request and responds with a 500
+func (h *Handler) internalError(w http.ResponseWriter, r *http.Request, err error) {
+	h.logger.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	respondError(w, http.StatusInternalServerError, err.Error())
+}
+
+// serviceError responds to an error from the service layer with the status
+// its domain error maps to: 404, 400 or 409, and otherwise a logged 500
+func (h *Handler) serviceError(w http.ResponseWriter, r *http.Request, err error) {
+	status := apierr.Status(err)
+	if status == http.StatusInternalServerError {
+		h.internalError(w, r, err)
+		return
+	}
+	respondError(w, status, err.Error())
+}
+
+// filterOutagesByTeam keeps the outages owned by any of teams
+func filterOutagesByTeam(outages []*domain.Outage, teams []string) []*domain.Outage {
+	owned := outages[:0]
+	for _, o := range outages {
+		if slices.Contains(teams, o.OwningTeam) {
+			owned = append(owned, o)
+		}
+	}
+	return owned
 }
@@ -4,12 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/apierr"
 	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/events"
+	"github.com/conall/outalator/internal/logging"
 	"github.com/conall/outalator/internal/testutil"
 	"github.com/conall/outalator/service"
 	"github.com/google/uuid"
@@ -18,8 +25,10 @@ import (
 
 // newTestHandler wires up handler + router backed by an in-memory storage.
 func newTestHandler() (*Handler, *mux.Router) {
-	svc := service.New(testutil.NewMemStorage())
-	h := NewHandler(svc)
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	broker := events.NewBroker(0, logging.Discard())
+	svc.RegisterOutageListener(broker)
+	h := NewHandler(svc, broker, logging.Discard())
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
 	return h, r
@@ -58,6 +67,54 @@ func TestHealth(t *testing.T) {
 	}
 }
 
+func TestReady(t *testing.T) {
+	store := testutil.NewMemStorage()
+	h := NewHandler(service.New(store, logging.Discard()), events.NewBroker(0, logging.Discard()), logging.Discard())
+	router := mux.NewRouter()
+	h.RegisterRoutes(router)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+	rr := get("/readyz")
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rr.Code)
+	}
+	var resp struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	decodeJSON(t, rr.Body, &resp)
+	if resp.Status != "ready" || resp.Checks["database"] != "ok" || resp.Checks["schema"] != "ok" {
+		t.Errorf("body = %+v, want ready with database and schema ok", resp)
+	}
+
+	store.SchemaErr = errors.New("migration 016_add_responder_assignments not applied")
+	rr = get("/readyz")
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Ready status with a missing migration = %d, want 503", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "schema") || strings.Contains(rr.Body.String(), "database") {
+		t.Errorf("Ready body = %s, want only the schema check named", rr.Body.String())
+	}
+
+	store.PingErr = errors.New("connection refused")
+	rr = get("/readyz")
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Ready status with an unreachable database = %d, want 503", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "connection refused") {
+		t.Errorf("Ready body leaks the storage error: %s", rr.Body.String())
+	}
+
+	// Liveness does not depend on the database
+	if rr := get("/healthz"); rr.Code != http.StatusOK {
+		t.Errorf("Healthz status with an unreachable database = %d, want 200", rr.Code)
+	}
+}
+
 func TestCreateOutage(t *testing.T) {
 	_, router := newTestHandler()
 
@@ -114,6 +171,45 @@ func TestListOutages(t *testing.T) {
 	}
 }
 
+func TestListOutagesIncludeAssociations(t *testing.T) {
+	h, router := newTestHandler()
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "DB down", Severity: "high",
+		Tags: []domain.TagInput{{Key: "jira", Value: "OPS-1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(query string) []domain.Outage {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/outages"+query, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d; body: %s", query, rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			Outages []domain.Outage `json:"outages"`
+		}
+		decodeJSON(t, rr.Body, &resp)
+		if len(resp.Outages) != 1 || resp.Outages[0].ID != outage.ID {
+			t.Fatalf("outages = %+v", resp.Outages)
+		}
+		return resp.Outages
+	}
+
+	if got := list(""); len(got[0].Tags) != 0 {
+		t.Errorf("tags without include_associations = %+v, want none", got[0].Tags)
+	}
+	if got := list("?include_associations=true"); len(got[0].Tags) != 1 || got[0].Tags[0].Value != "OPS-1" {
+		t.Errorf("tags with include_associations = %+v, want the outage's tag", got[0].Tags)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/outages?include_associations=maybe", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid include_associations status = %d, want 400", rr.Code)
+	}
+}
+
 func TestGetOutage(t *testing.T) {
 	_, router := newTestHandler()
 
@@ -148,6 +244,61 @@ func TestGetOutage(t *testing.T) {
 	}
 }
 
+func TestGetOutageTimeline(t *testing.T) {
+	_, router := newTestHandler()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/outages",
+		encodeJSON(t, domain.CreateOutageRequest{Title: "test", Severity: "low"})))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create failed: %d %s", rr.Code, rr.Body.String())
+	}
+	var created domain.Outage
+	decodeJSON(t, rr.Body, &created)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/api/v1/outages/"+created.ID.String(),
+		encodeJSON(t, map[string]string{"status": "investigating"})))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update failed: %d %s", rr.Code, rr.Body.String())
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		wantCode   int
+		wantEvents int
+	}{
+		{"found", created.ID.String(), http.StatusOK, 2},
+		{"not found", uuid.New().String(), http.StatusNotFound, 0},
+		{"bad id", "not-a-uuid", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/outages/"+tt.id+"/timeline", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp struct {
+				OutageID uuid.UUID              `json:"outage_id"`
+				Events   []domain.TimelineEvent `json:"events"`
+			}
+			decodeJSON(t, rr.Body, &resp)
+			if resp.OutageID != created.ID {
+				t.Errorf("outage_id = %s, want %s", resp.OutageID, created.ID)
+			}
+			if len(resp.Events) != tt.wantEvents {
+				t.Errorf("got %d events, want %d", len(resp.Events), tt.wantEvents)
+			}
+		})
+	}
+}
+
 func TestUpdateOutage(t *testing.T) {
 	_, router := newTestHandler()
 
@@ -229,8 +380,8 @@ func TestAddNote_Unauthenticated(t *testing.T) {
 
 func TestAddNote_Authenticated(t *testing.T) {
 	mem := testutil.NewMemStorage()
-	svc := service.New(mem)
-	h := NewHandler(svc)
+	svc := service.New(mem, logging.Discard())
+	h := NewHandler(svc, events.NewBroker(0, logging.Discard()), logging.Discard())
 
 	// Create outage.
 	o, err := svc.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "test", Severity: "low"})
@@ -297,4 +448,98 @@ func TestSearchByTag(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Errorf("SearchByTag status = %d, want 200", rr.Code)
 	}
+
+	for _, target := range []string{"/api/v1/tags/search?value=OPS-1", "/api/v1/tags/search?tag=!", "/api/v1/tags/search?tag=jira:"} {
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", target, rr.Code)
+		}
+	}
+}
+
+func TestSearchByTagQuery(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	for title, services := range map[string][]string{"api down": {"api"}, "web down": {"web"}, "both down": {"api", "web"}} {
+		outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: "high"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, service := range services {
+			if _, err := h.service.AddTag(ctx, outage.ID, "service", service); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for target, want := range map[string][]string{
+		"/api/v1/tags/search?key=service&value=api,web":       {"api down", "both down", "web down"},
+		"/api/v1/tags/search?tag=service:api&tag=service:web": {"both down"},
+		"/api/v1/tags/search?key=service&tag=!service:web":    {"api down"},
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want 200", target, rr.Code)
+		}
+		var resp struct {
+			Outages []domain.Outage `json:"outages"`
+		}
+		decodeJSON(t, rr.Body, &resp)
+		var got []string
+		for _, o := range resp.Outages {
+			got = append(got, o.Title)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("GET %s = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestCreateOutage_BodyTooLarge(t *testing.T) {
+	_, router := newTestHandler()
+	router.Use(bodylimit.Middleware(bodylimit.Limits{Default: 64}))
+
+	body := `{"title":"` + strings.Repeat("x", 100) + `","severity":"high"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/outages", strings.NewReader(body))
+	req.ContentLength = -1 // Streamed, so the limit is hit while decoding
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413; body: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	_, router := newTestHandler()
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{"bad id", http.MethodGet, "/api/v1/outages/not-a-uuid", "", http.StatusBadRequest, apierr.CodeInvalidInput},
+		{"unknown outage", http.MethodGet, "/api/v1/outages/" + uuid.New().String(), "", http.StatusNotFound, apierr.CodeNotFound},
+		{"unknown source", http.MethodPost, "/api/v1/alerts/import", `{"source":"nope","external_id":"PD-1"}`, http.StatusNotFound, apierr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			var body apierr.Body
+			decodeJSON(t, rr.Body, &body)
+			if body.Code != tt.wantErr || body.Error == "" {
+				t.Errorf("body = %+v, want code %q and a message", body, tt.wantErr)
+			}
+		})
+	}
 }
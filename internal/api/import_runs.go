@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListImportRuns handles GET /api/v1/import-runs
+func (h *Handler) ListImportRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.service.ListImportRuns(r.Context(), r.URL.Query().Get("provider"))
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if runs == nil {
+		runs = []*domain.ImportRun{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"import_runs": runs,
+	})
+}
+
+// GetImportRun handles GET /api/v1/import-runs/{id}
+func (h *Handler) GetImportRun(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid import run ID")
+		return
+	}
+
+	run, err := h.service.GetImportRun(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Import run not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, run)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func TestImportRunRoutes(t *testing.T) {
+	store := testutil.NewMemStorage()
+	h := NewHandler(service.New(store, logging.Discard()), nil, logging.Discard())
+	router := mux.NewRouter()
+	h.RegisterRoutes(router)
+
+	started := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	run := &domain.ImportRun{
+		ID: uuid.New(), Provider: "pagerduty", Kind: "alerts", FilterHash: "abc",
+		Since: started.AddDate(0, -1, 0), Until: started, Status: domain.ImportRunRunning, Offset: 300,
+		Stats:     domain.ImportRunStats{TotalFetched: 300, NewOutages: 290, NewAlerts: 290, Skipped: 10},
+		StartedAt: started, UpdatedAt: started,
+	}
+	for _, r := range []*domain.ImportRun{run, {ID: uuid.New(), Provider: "opsgenie", Kind: "alerts", StartedAt: started.Add(-time.Hour)}} {
+		if err := store.CreateImportRun(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return rr
+	}
+
+	rr := get("/api/v1/import-runs?provider=pagerduty")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET import-runs = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var list struct {
+		ImportRuns []domain.ImportRun `json:"import_runs"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.ImportRuns) != 1 || list.ImportRuns[0].ID != run.ID {
+		t.Fatalf("import runs = %+v, want the pagerduty run", list.ImportRuns)
+	}
+
+	rr = get("/api/v1/import-runs/" + run.ID.String())
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET import run = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var got domain.ImportRun
+	decodeJSON(t, rr.Body, &got)
+	if got.Offset != 300 || got.Stats != run.Stats || got.Status != domain.ImportRunRunning {
+		t.Errorf("import run = %+v, want offset 300 and its stats", got)
+	}
+
+	if rr := get("/api/v1/import-runs/" + uuid.NewString()); rr.Code != http.StatusNotFound {
+		t.Errorf("GET unknown import run = %d, want 404", rr.Code)
+	}
+	if rr := get("/api/v1/import-runs/not-a-uuid"); rr.Code != http.StatusBadRequest {
+		t.Errorf("GET import run with a bad ID = %d, want 400", rr.Code)
+	}
+}
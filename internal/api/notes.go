@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/render"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListNotes handles GET /api/v1/outages/{id}/notes?limit=...&offset=...
+// Admins can pass include_deleted=true to list notes in the trash too.
+func (h *Handler) ListNotes(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	limit, offset, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	includeDeleted, ok := h.parseIncludeDeleted(w, r)
+	if !ok {
+		return
+	}
+
+	notes, total, err := h.service.ListNotesByOutagePage(r.Context(), id, limit, offset, includeDeleted)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"notes":  notes,
+		"limit":  limit,
+		"offset": offset,
+		"total":  total,
+	})
+}
+
+// ListNoteThreads handles GET /api/v1/outages/{id}/notes/threads?limit=...&offset=...
+// Limit and offset count threads, not notes.
+func (h *Handler) ListNoteThreads(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	limit, offset, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+
+	threads, total, err := h.service.ListNoteThreadsPage(r.Context(), id, limit, offset)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"threads": threads,
+		"limit":   limit,
+		"offset":  offset,
+		"total":   total,
+	})
+}
+
+// GetNote handles GET /api/v1/notes/{id}
+func (h *Handler) GetNote(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	note, err := h.service.GetNote(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Note not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, note)
+}
+
+// RenderNote handles GET /api/v1/notes/{id}/rendered, returning the note
+// content as sanitized HTML and Slack mrkdwn
+func (h *Handler) RenderNote(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	note, err := h.service.GetNote(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Note not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	rendered := render.Note(note.Format, note.Content)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"note_id": note.ID,
+		"format":  note.Format,
+		"html":    rendered.HTML,
+		"slack":   rendered.Slack,
+	})
+}
+
+// ListNoteRevisions handles GET /api/v1/notes/{id}/revisions, returning the
+// versions that edits replaced, oldest first
+func (h *Handler) ListNoteRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	revisions, err := h.service.ListNoteRevisions(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Note not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+	if revisions == nil {
+		revisions = []*domain.NoteRevision{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"revisions": revisions})
+}
+
+// UpdateNote handles PATCH /api/v1/notes/{id}
+func (h *Handler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	var req struct {
+		Content      *string           `json:"content,omitempty"`
+		Format       *string           `json:"format,omitempty"`
+		Metadata     map[string]string `json:"metadata,omitempty"`
+		CustomFields map[string]any    `json:"custom_fields,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	var editor string
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		editor = user.Identity()
+	}
+
+	note, err := h.service.UpdateNote(r.Context(), id, req.Content, req.Format, req.Metadata, req.CustomFields, editor)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Note not found")
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, note)
+}
+
+// DeleteNote handles DELETE /api/v1/notes/{id}, moving the note to the trash
+func (h *Handler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	if err := h.service.DeleteNote(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Note not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parsePage reads the optional limit and offset query parameters, writing a
+// 400 response and returning false when either is not a non-negative integer
+func parsePage(w http.ResponseWriter, r *http.Request) (limit, offset int, ok bool) {
+	for name, dst := range map[string]*int{"limit": &limit, "offset": &offset} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			respondError(w, http.StatusBadRequest, name+" must be a non-negative integer")
+			return 0, 0, false
+		}
+		*dst = n
+	}
+	return limit, offset, true
+}
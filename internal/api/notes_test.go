@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+)
+
+func TestNoteRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var note *domain.Note
+	for i := 0; i < 3; i++ {
+		if note, err = h.service.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "note", Format: "plaintext", Author: "alice"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	serve := func(method, url string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, url, nil)
+		if body != nil {
+			req = httptest.NewRequest(method, url, encodeJSON(t, body))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/api/v1/outages/"+outage.ID.String()+"/notes?limit=2&offset=1", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list notes = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var page struct {
+		Notes []domain.Note `json:"notes"`
+		Total int           `json:"total"`
+	}
+	decodeJSON(t, rr.Body, &page)
+	if len(page.Notes) != 2 || page.Total != 3 {
+		t.Errorf("page = %d notes of %d, want 2 of 3", len(page.Notes), page.Total)
+	}
+	if rr := serve(http.MethodGet, "/api/v1/outages/"+outage.ID.String()+"/notes?limit=-1", nil); rr.Code != http.StatusBadRequest {
+		t.Errorf("negative limit = %d, want 400", rr.Code)
+	}
+
+	noteURL := "/api/v1/notes/" + note.ID.String()
+	if rr := serve(http.MethodGet, noteURL, nil); rr.Code != http.StatusOK {
+		t.Errorf("get note = %d, want 200", rr.Code)
+	}
+	rr = serve(http.MethodPatch, noteURL, map[string]string{"content": "edited"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update note = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var updated domain.Note
+	decodeJSON(t, rr.Body, &updated)
+	if updated.Content != "edited" {
+		t.Errorf("content = %q, want edited", updated.Content)
+	}
+
+	if rr := serve(http.MethodDelete, noteURL, nil); rr.Code != http.StatusNoContent {
+		t.Errorf("delete note = %d, want 204", rr.Code)
+	}
+	if rr := serve(http.MethodGet, noteURL, nil); rr.Code != http.StatusNotFound {
+		t.Errorf("get deleted note = %d, want 404", rr.Code)
+	}
+	if rr := serve(http.MethodDelete, "/api/v1/notes/"+uuid.New().String(), nil); rr.Code != http.StatusNotFound {
+		t.Errorf("delete unknown note = %d, want 404", rr.Code)
+	}
+	if rr := serve(http.MethodGet, "/api/v1/notes/not-a-uuid", nil); rr.Code != http.StatusBadRequest {
+		t.Errorf("get note with bad ID = %d, want 400", rr.Code)
+	}
+}
+
+func TestNoteThreadRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	notesURL := "/api/v1/outages/" + outage.ID.String() + "/notes"
+
+	addNote := func(body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, notesURL, encodeJSON(t, body))
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := addNote(map[string]any{"content": "checking replicas"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("add note = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var root domain.Note
+	decodeJSON(t, rr.Body, &root)
+
+	rr = addNote(map[string]any{"content": "replica lag is 30s", "parent_note_id": root.ID})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("add reply = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var reply domain.Note
+	decodeJSON(t, rr.Body, &reply)
+	if reply.ParentNoteID == nil || *reply.ParentNoteID != root.ID {
+		t.Errorf("reply parent_note_id = %v, want %s", reply.ParentNoteID, root.ID)
+	}
+	if rr := addNote(map[string]any{"content": "x", "parent_note_id": uuid.New()}); rr.Code != http.StatusBadRequest {
+		t.Errorf("reply to unknown note = %d, want 400", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, notesURL+"/threads", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list threads = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var page struct {
+		Threads []domain.NoteThread `json:"threads"`
+		Total   int                 `json:"total"`
+	}
+	decodeJSON(t, rr.Body, &page)
+	if page.Total != 1 || len(page.Threads) != 1 || page.Threads[0].Note.ID != root.ID ||
+		len(page.Threads[0].Replies) != 1 || page.Threads[0].Replies[0].ID != reply.ID {
+		t.Errorf("threads = %+v, want one thread with the reply", page)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/outages/"+uuid.New().String()+"/notes/threads", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("list threads of unknown outage = %d, want 404", rr.Code)
+	}
+}
+
+func TestRenderNote(t *testing.T) {
+	h, router := newTestHandler()
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	notesURL := "/api/v1/outages/" + outage.ID.String() + "/notes"
+
+	req := httptest.NewRequest(http.MethodPost, notesURL, encodeJSON(t, map[string]any{"content": "<script>alert(1)</script>", "format": "markdown"}))
+	req = req.WithContext(testutil.WithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("add markdown note with script = %d, want 400", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, notesURL, encodeJSON(t, map[string]any{"content": "Failed over to **replica** <br> see [runbook](https://runbooks.example.com)", "format": "markdown"}))
+	req = req.WithContext(testutil.WithUser(req.Context(), user))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("add markdown note = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var note domain.Note
+	decodeJSON(t, rr.Body, &note)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+note.ID.String()+"/rendered", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("render = %d, want 200", rr.Code)
+	}
+	var rendered struct {
+		NoteID uuid.UUID `json:"note_id"`
+		Format string    `json:"format"`
+		HTML   string    `json:"html"`
+		Slack  string    `json:"slack"`
+	}
+	decodeJSON(t, rr.Body, &rendered)
+	wantHTML := `<p>Failed over to <strong>replica</strong> <br> see <a href="https://runbooks.example.com" rel="nofollow noopener noreferrer" target="_blank">runbook</a></p>`
+	if rendered.NoteID != note.ID || rendered.Format != "markdown" || rendered.HTML != wantHTML {
+		t.Errorf("rendered = %+v", rendered)
+	}
+	if want := "Failed over to *replica* \n see <https://runbooks.example.com|runbook>"; rendered.Slack != want {
+		t.Errorf("slack = %q, want %q", rendered.Slack, want)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+uuid.New().String()+"/rendered", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("render unknown note = %d, want 404", rr.Code)
+	}
+}
+
+func TestNoteRevisions(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := h.service.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "failover at 10:02", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noteURL := "/api/v1/notes/" + note.ID.String()
+
+	req := httptest.NewRequest(http.MethodPatch, noteURL, encodeJSON(t, map[string]any{"content": "failover at 10:04"}))
+	req = req.WithContext(testutil.WithUser(req.Context(), &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, noteURL+"/revisions", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("revisions = %d, want 200", rr.Code)
+	}
+	var resp struct {
+		Revisions []domain.NoteRevision `json:"revisions"`
+	}
+	decodeJSON(t, rr.Body, &resp)
+	if len(resp.Revisions) != 1 {
+		t.Fatalf("got %d revisions, want 1", len(resp.Revisions))
+	}
+	if r := resp.Revisions[0]; r.Version != 1 || r.Content != "failover at 10:02" || r.EditedBy != "bob@example.com" {
+		t.Errorf("revision = %+v", r)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+uuid.New().String()+"/revisions", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("revisions of unknown note = %d, want 404", rr.Code)
+	}
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/conall/outalator/api/openapi"
+)
+
+// GetOpenAPISpec handles GET /api/v1/openapi.json
+func (h *Handler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	spec, err := openapi.Load()
+	if err != nil {
+		h.internalError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(spec.JSON())
+}
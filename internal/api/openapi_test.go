@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/api/openapi"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+// TestOpenAPISpecCoversRoutes keeps api/openapi/openapi.yaml, which the
+// generated clients are built from, in step with the registered routes.
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	data, err := os.ReadFile("../../api/openapi/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range spec.Paths {
+		for method := range item {
+			if method != "parameters" {
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	_, router := newTestHandler()
+	registered := make(map[string]bool)
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, m := range methods {
+			registered[m+" "+path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for route := range registered {
+		if !documented[route] {
+			t.Errorf("route %s is not in the OpenAPI spec", route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			t.Errorf("OpenAPI spec documents %s, which is not registered", route)
+		}
+	}
+}
+
+// TestResponsesMatchOpenAPISpec sends representative requests through the
+// spec's request validation and checks each response against the spec.
+func TestResponsesMatchOpenAPISpec(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, router := newTestHandler()
+	checked := spec.Middleware(spec.ResponseChecker(func(r *http.Request, status int, err error) {
+		t.Errorf("%s %s responded %d: %v", r.Method, r.URL.Path, status, err)
+	})(router))
+
+	do := func(method, target string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var reader io.Reader
+		if body != nil {
+			reader = encodeJSON(t, body)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(testutil.WithUser(req.Context(), &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}))
+		rr := httptest.NewRecorder()
+		checked.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do("POST", "/api/v1/outages", map[string]any{
+		"title": "Checkout errors", "description": "5xx from checkout", "severity": "high",
+		"tags": []map[string]string{{"key": "service", "value": "checkout"}},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create outage: status = %d: %s", rr.Code, rr.Body)
+	}
+	var outage domain.Outage
+	if err := json.Unmarshal(rr.Body.Bytes(), &outage); err != nil {
+		t.Fatal(err)
+	}
+	id := outage.ID.String()
+
+	rr = do("POST", "/api/v1/outages/"+id+"/notes", map[string]any{"content": "rolling back", "format": "markdown"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("add note: status = %d: %s", rr.Code, rr.Body)
+	}
+	var note domain.Note
+	if err := json.Unmarshal(rr.Body.Bytes(), &note); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{
+		"/api/v1/outages",
+		"/api/v1/outages?limit=10&include_associations=true",
+		"/api/v1/outages/" + id,
+		"/api/v1/outages/" + id + "/timeline",
+		"/api/v1/outages/" + id + "/notes",
+		"/api/v1/outages/" + id + "/notes/threads",
+		"/api/v1/outages/" + id + "/tags",
+		"/api/v1/outages/" + id + "/alerts",
+		"/api/v1/outages/" + id + "/similar",
+		"/api/v1/outages/" + id + "/responders",
+		"/api/v1/outages/" + id + "/review",
+		"/api/v1/outages/" + id + "/update-sla",
+		"/api/v1/notes/" + note.ID.String(),
+		"/api/v1/notes/" + note.ID.String() + "/rendered",
+		"/api/v1/notes/" + note.ID.String() + "/revisions",
+		"/api/v1/outages/" + uuid.NewString(),
+		"/api/v1/tags/search?key=service&value=checkout",
+		"/api/v1/tags/search?tag=service:checkout,search&tag=!region:eu",
+		"/api/v1/reviews",
+		"/api/v1/update-sla",
+		"/api/v1/sources/health",
+		"/api/v1/import-runs",
+		"/api/v1/reports/paging-load",
+		"/api/v1/reports/responders",
+		"/api/v1/reports/digest",
+		"/api/v1/schemas/custom-fields",
+		"/api/v1/config",
+		"/api/v1/teams",
+		"/healthz",
+		"/readyz",
+	} {
+		do("GET", target, nil)
+	}
+	do("PATCH", "/api/v1/outages/"+id, map[string]any{"status": "investigating"})
+	do("POST", "/api/v1/outages/"+id+"/transition", map[string]any{"status": "mitigated"})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   any
+		want   string
+	}{
+		{"path parameter", "GET", "/api/v1/outages/nope", nil, "Invalid request: id: expected a UUID"},
+		{"query parameter", "GET", "/api/v1/outages?limit=ten", nil, "Invalid request: limit: expected an integer"},
+		{"missing query parameter", "GET", "/api/v1/tags/values", nil, "Invalid request: key: query parameter is required"},
+		{"wrong type", "POST", "/api/v1/outages", map[string]any{"title": 1, "severity": "high"}, "Invalid request: title: expected a string"},
+		{"unknown field", "POST", "/api/v1/outages", map[string]any{"title": "x", "severity": "high", "sevrity": "low"}, "Invalid request: sevrity: is not a known field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := do(tt.method, tt.target, tt.body)
+			if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), tt.want) {
+				t.Errorf("status = %d, body = %s, want 400 containing %q", rr.Code, rr.Body, tt.want)
+			}
+		})
+	}
+}
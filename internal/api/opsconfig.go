@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/bodylimit"
+)
+
+// GetOpsConfig handles GET /api/v1/config
+func (h *Handler) GetOpsConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.service.GetOpsConfig(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cfg)
+}
+
+// ApplyOpsConfig handles POST /api/v1/config/apply. The dry_run and prune
+// query parameters control whether changes are made and whether resources
+// missing from the document are deleted. Anyone can plan with dry_run, but
+// only admins can apply.
+func (h *Handler) ApplyOpsConfig(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	dryRun, err := parseBoolParam(query.Get("dry_run"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dry_run parameter")
+		return
+	}
+	prune, err := parseBoolParam(query.Get("prune"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid prune parameter")
+		return
+	}
+	if !dryRun && !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can apply config")
+		return
+	}
+
+	// Reject unknown fields so typos in a config document fail loudly
+	var desired domain.OpsConfig
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&desired); err != nil {
+		if bodylimit.TooLarge(err) {
+			bodylimit.RespondTooLarge(w)
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	plan, err := h.service.ApplyOpsConfig(r.Context(), desired, dryRun, prune)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, plan)
+}
+
+// EvaluateRoutingRules handles POST /api/v1/config/routing-rules/evaluate,
+// a dry run of routing rules against the alerts triggered in a time range.
+// The body's rules are tried instead of the stored ones when given; an
+// empty body evaluates the stored rules over the last week.
+func (h *Handler) EvaluateRoutingRules(w http.ResponseWriter, r *http.Request) {
+	var req domain.RoutingEvaluationRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	result, err := h.service.EvaluateRoutingRules(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// parseBoolParam parses an optional boolean query parameter
+func parseBoolParam(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestApplyOpsConfig(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"admin@example.com"})
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+	member := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+	const doc = `{"teams":[{"name":"payments"}],"routing_rules":[{"name":"pay","match":{"team_name":"payments"},"team":"payments"}]}`
+
+	tests := []struct {
+		name     string
+		user     *auth.UserInfo
+		query    string
+		body     string
+		wantCode int
+		applied  bool
+	}{
+		{"member dry run", member, "?dry_run=true", doc, http.StatusOK, false},
+		{"member apply", member, "", doc, http.StatusForbidden, false},
+		{"member prune", member, "?prune=true", doc, http.StatusForbidden, false},
+		{"apply", admin, "", doc, http.StatusOK, true},
+		{"unknown field", admin, "", `{"teams":[{"name":"payments","owner":"x"}]}`, http.StatusBadRequest, false},
+		{"unknown team", admin, "", `{"routing_rules":[{"name":"r","team":"search"}]}`, http.StatusBadRequest, false},
+		{"invalid dry_run", admin, "?dry_run=maybe", doc, http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/config/apply"+tt.query, strings.NewReader(tt.body))
+			req = req.WithContext(testutil.WithUser(req.Context(), tt.user))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			var plan domain.OpsConfigPlan
+			decodeJSON(t, rr.Body, &plan)
+			if plan.Applied != tt.applied || len(plan.Changes) != 2 {
+				t.Errorf("plan = %+v", plan)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/config", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", rr.Code)
+	}
+	var cfg domain.OpsConfig
+	decodeJSON(t, rr.Body, &cfg)
+	if len(cfg.Teams) != 1 || len(cfg.RoutingRules) != 1 || cfg.RoutingRules[0].Team != "payments" {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+func TestEvaluateRoutingRules(t *testing.T) {
+	_, router := newTestHandler()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"empty body", "", http.StatusOK},
+		{"draft rules", `{"rules":[{"name":"quiet","match":{"title":"^heartbeat"},"suppress":true}]}`, http.StatusOK},
+		{"unknown field", `{"rule":[]}`, http.StatusBadRequest},
+		{"invalid rule", `{"rules":[{"name":"quiet","match":{"title":"("},"suppress":true}]}`, http.StatusBadRequest},
+		{"empty range", `{"since":"2026-01-02T00:00:00Z","until":"2026-01-01T00:00:00Z"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/config/routing-rules/evaluate", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			var result domain.RoutingEvaluationResult
+			decodeJSON(t, rr.Body, &result)
+			if result.Alerts == nil || !result.Since.Before(result.Until) {
+				t.Errorf("result = %+v", result)
+			}
+		})
+	}
+}
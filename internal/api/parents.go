@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// SetOutageParent handles PUT /api/v1/outages/{id}/parent, linking the
+// outage as a child of the outage named in the body
+func (h *Handler) SetOutageParent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	var req domain.SetOutageParentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+	if req.ParentID == uuid.Nil {
+		respondError(w, http.StatusBadRequest, "parent_id is required")
+		return
+	}
+
+	outage, err := h.service.SetOutageParent(r.Context(), id, req.ParentID)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, outage)
+}
+
+// RemoveOutageParent handles DELETE /api/v1/outages/{id}/parent, making the
+// outage top-level again
+func (h *Handler) RemoveOutageParent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	if _, err := h.service.RemoveOutageParent(r.Context(), id); err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListChildOutages handles GET /api/v1/outages/{id}/children
+func (h *Handler) ListChildOutages(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	children, err := h.service.ListChildOutages(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if children == nil {
+		children = []*domain.Outage{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"outages": children})
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestOutageParentRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	parent, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "eu-west down", Severity: "medium"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "checkout down", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return rr
+	}
+	parentURL := "/api/v1/outages/" + child.ID.String() + "/parent"
+
+	rr := serve(http.MethodPut, parentURL, `{"parent_id":"`+parent.ID.String()+`"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("set parent = %d, want 200: %s", rr.Code, rr.Body)
+	}
+	var linked domain.Outage
+	decodeJSON(t, rr.Body, &linked)
+	if linked.ParentID == nil || *linked.ParentID != parent.ID {
+		t.Errorf("linked ParentID = %v, want %s", linked.ParentID, parent.ID)
+	}
+
+	for name, body := range map[string]string{
+		"missing parent_id": `{}`,
+		"own parent":        `{"parent_id":"` + child.ID.String() + `"}`,
+		"invalid body":      `{`,
+	} {
+		if rr := serve(http.MethodPut, parentURL, body); rr.Code != http.StatusBadRequest {
+			t.Errorf("set parent with %s = %d, want 400", name, rr.Code)
+		}
+	}
+
+	rr = serve(http.MethodGet, "/api/v1/outages/"+parent.ID.String(), "")
+	var got domain.Outage
+	decodeJSON(t, rr.Body, &got)
+	if got.Rollup == nil || len(got.Rollup.Children) != 1 || got.Rollup.Severity != "critical" {
+		t.Errorf("parent rollup = %+v, want the critical child", got.Rollup)
+	}
+
+	rr = serve(http.MethodGet, "/api/v1/outages/"+parent.ID.String()+"/children", "")
+	var children struct {
+		Outages []domain.Outage `json:"outages"`
+	}
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list children = %d, want 200", rr.Code)
+	}
+	decodeJSON(t, rr.Body, &children)
+	if len(children.Outages) != 1 || children.Outages[0].ID != child.ID {
+		t.Errorf("children = %+v, want %s", children.Outages, child.ID)
+	}
+
+	if rr := serve(http.MethodDelete, parentURL, ""); rr.Code != http.StatusNoContent {
+		t.Errorf("remove parent = %d, want 204", rr.Code)
+	}
+	rr = serve(http.MethodGet, "/api/v1/outages/"+parent.ID.String()+"/children", "")
+	decodeJSON(t, rr.Body, &children)
+	if len(children.Outages) != 0 {
+		t.Errorf("children after unlink = %+v, want none", children.Outages)
+	}
+	if rr := serve(http.MethodGet, "/api/v1/outages/not-a-uuid/children", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("list children of an invalid ID = %d, want 400", rr.Code)
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+)
+
+// GetPreferences handles GET /api/v1/me/preferences
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	prefs, err := h.service.GetUserPreferences(r.Context(), user.Sub)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles PATCH /api/v1/me/preferences
+func (h *Handler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req domain.UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	prefs, err := h.service.UpdateUserPreferences(r.Context(), user.Sub, req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, prefs)
+}
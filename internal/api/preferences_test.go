@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestPreferences(t *testing.T) {
+	_, router := newTestHandler()
+	user := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-123"}
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		var req *http.Request
+		if body == "" {
+			req = httptest.NewRequest(method, "/api/v1/me/preferences", nil)
+		} else {
+			req = httptest.NewRequest(method, "/api/v1/me/preferences", strings.NewReader(body))
+		}
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Defaults before anything is saved.
+	rr := do(http.MethodGet, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", rr.Code)
+	}
+	var prefs domain.UserPreferences
+	decodeJSON(t, rr.Body, &prefs)
+	if prefs.Subject != user.Sub || prefs.Timezone != "UTC" {
+		t.Errorf("defaults = %+v, want subject %s in UTC", prefs, user.Sub)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"valid", `{"timezone":"Europe/Dublin","digest_opt_in":true,"notifications":{"slack_dm":true,"min_severity":"high"}}`, http.StatusOK},
+		{"bad timezone", `{"timezone":"Mars/Olympus"}`, http.StatusBadRequest},
+		{"bad severity", `{"notifications":{"min_severity":"apocalyptic"}}`, http.StatusBadRequest},
+		{"bad body", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := do(http.MethodPatch, tt.body)
+			if rr.Code != tt.wantCode {
+				t.Errorf("PATCH status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+		})
+	}
+
+	// Only the valid update should have been persisted.
+	rr = do(http.MethodGet, "")
+	decodeJSON(t, rr.Body, &prefs)
+	if prefs.Timezone != "Europe/Dublin" || !prefs.DigestOptIn || prefs.Notifications.MinSeverity != "high" {
+		t.Errorf("saved preferences = %+v", prefs)
+	}
+}
+
+func TestPreferences_Unauthenticated(t *testing.T) {
+	_, router := newTestHandler()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/me/preferences", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rr.Code)
+	}
+}
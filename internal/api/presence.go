@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// presenceResponse lists the users currently engaged with an outage
+type presenceResponse struct {
+	OutageID   uuid.UUID         `json:"outage_id"`
+	TTLSeconds int               `json:"ttl_seconds"`
+	Present    []domain.Presence `json:"present"`
+}
+
+func newPresenceResponse(id uuid.UUID, present []domain.Presence) presenceResponse {
+	return presenceResponse{
+		OutageID:   id,
+		TTLSeconds: int(domain.PresenceTTL.Seconds()),
+		Present:    present,
+	}
+}
+
+// RecordPresence handles POST /api/v1/outages/{id}/presence, a heartbeat
+// from the authenticated user. The body is optional.
+func (h *Handler) RecordPresence(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var hb domain.PresenceHeartbeat
+	if err := json.NewDecoder(r.Body).Decode(&hb); err != nil && !errors.Is(err, io.EOF) {
+		respondInvalidBody(w, err)
+		return
+	}
+	hb.User = user.Identity()
+	hb.Name = user.Name
+
+	present, err := h.service.RecordPresence(r.Context(), id, hb)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Outage not found")
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newPresenceResponse(id, present))
+}
+
+// GetPresence handles GET /api/v1/outages/{id}/presence
+func (h *Handler) GetPresence(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newPresenceResponse(id, h.service.ListPresence(r.Context(), id)))
+}
+
+// LeavePresence handles DELETE /api/v1/outages/{id}/presence, removing the
+// authenticated user without waiting for their heartbeat to expire
+func (h *Handler) LeavePresence(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	h.service.LeavePresence(r.Context(), id, user.Identity())
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+)
+
+func TestPresence(t *testing.T) {
+	h, router := newTestHandler()
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &auth.UserInfo{Email: "alice@example.com", Name: "Alice", Sub: "sub-alice"}
+	bob := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+
+	do := func(method string, id uuid.UUID, user *auth.UserInfo, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/v1/outages/"+id.String()+"/presence", strings.NewReader(body))
+		if user != nil {
+			req = req.WithContext(testutil.WithUser(req.Context(), user))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := do(http.MethodPost, outage.ID, alice, ""); rr.Code != http.StatusOK {
+		t.Fatalf("heartbeat without body status = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	rr := do(http.MethodPost, outage.ID, bob, `{"activity":"working"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("heartbeat status = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var resp presenceResponse
+	decodeJSON(t, rr.Body, &resp)
+	if resp.TTLSeconds != int(domain.PresenceTTL.Seconds()) || len(resp.Present) != 2 {
+		t.Fatalf("response = %+v, want alice and bob", resp)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		id       uuid.UUID
+		user     *auth.UserInfo
+		body     string
+		wantCode int
+	}{
+		{"unauthenticated", http.MethodPost, outage.ID, nil, "", http.StatusUnauthorized},
+		{"bad activity", http.MethodPost, outage.ID, alice, `{"activity":"typing"}`, http.StatusBadRequest},
+		{"bad body", http.MethodPost, outage.ID, alice, `{`, http.StatusBadRequest},
+		{"unknown outage", http.MethodPost, uuid.New(), alice, "", http.StatusNotFound},
+		{"leave", http.MethodDelete, outage.ID, bob, "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := do(tt.method, tt.id, tt.user, tt.body); rr.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+		})
+	}
+
+	rr = do(http.MethodGet, outage.ID, nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", rr.Code)
+	}
+	resp = presenceResponse{}
+	decodeJSON(t, rr.Body, &resp)
+	if len(resp.Present) != 1 || resp.Present[0].User != alice.Email || resp.Present[0].Name != alice.Name {
+		t.Errorf("present = %+v, want only alice", resp.Present)
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CreateOutageRelation handles POST /api/v1/outages/{id}/relations, linking
+// the outage to another with a typed relation
+func (h *Handler) CreateOutageRelation(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	var req domain.CreateOutageRelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+	if user := requestUser(r); user != "" {
+		req.CreatedBy = user
+	}
+
+	relation, err := h.service.CreateOutageRelation(r.Context(), id, req)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, relation)
+}
+
+// ListOutageRelations handles GET /api/v1/outages/{id}/relations
+func (h *Handler) ListOutageRelations(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	relations, err := h.service.ListOutageRelations(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"relations": relations})
+}
+
+// DeleteOutageRelation handles DELETE
+// /api/v1/outages/{id}/relations/{relation_id}. Either outage of the relation
+// can remove it.
+func (h *Handler) DeleteOutageRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+	relationID, err := uuid.Parse(vars["relation_id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid relation ID")
+		return
+	}
+
+	if err := h.service.DeleteOutageRelation(r.Context(), id, relationID); err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestOutageRelationRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	gateway, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "gateway down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dns, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "dns down", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return rr
+	}
+	relationsURL := "/api/v1/outages/" + gateway.ID.String() + "/relations"
+	body := `{"related_outage_id":"` + dns.ID.String() + `","type":"caused_by"}`
+
+	rr := serve(http.MethodPost, relationsURL, body)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create relation = %d, want 201: %s", rr.Code, rr.Body)
+	}
+	var created domain.OutageRelation
+	decodeJSON(t, rr.Body, &created)
+	if created.RelatedOutageID != dns.ID || created.RelatedOutageTitle != dns.Title {
+		t.Errorf("created relation = %+v, want caused_by %s", created, dns.Title)
+	}
+	if rr := serve(http.MethodPost, relationsURL, body); rr.Code != http.StatusConflict {
+		t.Errorf("create relation again = %d, want 409", rr.Code)
+	}
+	for name, body := range map[string]string{
+		"unknown type": `{"related_outage_id":"` + dns.ID.String() + `","type":"blames"}`,
+		"itself":       `{"related_outage_id":"` + gateway.ID.String() + `","type":"related_to"}`,
+		"invalid body": `{`,
+	} {
+		if rr := serve(http.MethodPost, relationsURL, body); rr.Code != http.StatusBadRequest {
+			t.Errorf("create relation with %s = %d, want 400", name, rr.Code)
+		}
+	}
+
+	rr = serve(http.MethodGet, "/api/v1/outages/"+dns.ID.String(), "")
+	var got domain.Outage
+	decodeJSON(t, rr.Body, &got)
+	if len(got.Relations) != 1 || got.Relations[0].Type != domain.RelationCauses {
+		t.Errorf("related outage relations = %+v, want causes %s", got.Relations, gateway.Title)
+	}
+
+	rr = serve(http.MethodGet, relationsURL, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list relations = %d, want 200", rr.Code)
+	}
+	var list struct {
+		Relations []domain.OutageRelation `json:"relations"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.Relations) != 1 || list.Relations[0].ID != created.ID {
+		t.Errorf("relations = %+v, want %s", list.Relations, created.ID)
+	}
+
+	relationURL := "/api/v1/outages/" + dns.ID.String() + "/relations/" + created.ID.String()
+	if rr := serve(http.MethodDelete, relationURL, ""); rr.Code != http.StatusNoContent {
+		t.Errorf("delete relation = %d, want 204", rr.Code)
+	}
+	if rr := serve(http.MethodDelete, relationURL, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("delete relation again = %d, want 404", rr.Code)
+	}
+	if rr := serve(http.MethodDelete, relationsURL+"/not-a-uuid", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("delete relation with invalid ID = %d, want 400", rr.Code)
+	}
+}
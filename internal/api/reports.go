@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// defaultReportWindow is the range reported when since is not given
+const defaultReportWindow = 28 * 24 * time.Hour
+
+// GetPagingLoad handles GET /api/v1/reports/paging-load. The optional since
+// and until parameters are RFC 3339 timestamps, defaulting to the 28 days
+// before now; tz is an IANA time zone name, defaulting to UTC; team limits
+// the report to one team.
+func (h *Handler) GetPagingLoad(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := domain.PagingLoadQuery{Team: query.Get("team")}
+
+	var ok bool
+	if q.Since, q.Until, ok = reportRange(w, r); !ok {
+		return
+	}
+	if v := query.Get("tz"); v != "" {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid time zone")
+			return
+		}
+		q.Location = loc
+	}
+
+	load, err := h.service.GetPagingLoad(r.Context(), q)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, load)
+}
+
+// GetNoisyAlerts handles GET /api/v1/reports/noisy-alerts, ranking the
+// fingerprints of the alerts triggered between since and until by how many
+// alerts share them. The range and team parameters work as in
+// GetPagingLoad; limit caps how many fingerprints are listed.
+func (h *Handler) GetNoisyAlerts(w http.ResponseWriter, r *http.Request) {
+	q := domain.NoisyAlertsQuery{Team: r.URL.Query().Get("team")}
+
+	var ok bool
+	if q.Since, q.Until, ok = reportRange(w, r); !ok {
+		return
+	}
+	if q.Limit, _, ok = parsePage(w, r); !ok {
+		return
+	}
+
+	report, err := h.service.GetNoisyAlerts(r.Context(), q)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetResponderLoad handles GET /api/v1/reports/responders, counting the
+// responder assignments made between since and until per responder. The
+// range parameters work as in GetPagingLoad.
+func (h *Handler) GetResponderLoad(w http.ResponseWriter, r *http.Request) {
+	since, until, ok := reportRange(w, r)
+	if !ok {
+		return
+	}
+
+	load, err := h.service.GetResponderLoad(r.Context(), since, until)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, load)
+}
+
+// GetDigest handles GET /api/v1/reports/digest, previewing the digest a
+// schedule would send. period is daily or weekly, defaulting to weekly;
+// team limits the digest to one team; until is an RFC 3339 timestamp
+// ending the period, defaulting to now.
+func (h *Handler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	period := query.Get("period")
+	if period == "" {
+		period = domain.DigestWeekly
+	}
+	until := time.Now().UTC()
+	if v := query.Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid until timestamp")
+			return
+		}
+		until = t
+	}
+
+	digest, err := h.service.BuildDigest(r.Context(), query.Get("team"), period, until)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, digest)
+}
+
+// GetServiceDowntime handles GET /api/v1/reports/service-downtime,
+// reporting how long each catalogued service was affected by outages
+// between since and until. The range parameters work as in GetPagingLoad;
+// customer_impact=true counts only customer-impacting outages. The
+// optional team and tier parameters limit the report to services owned by
+// a team, and to services of that tier or more critical.
+func (h *Handler) GetServiceDowntime(w http.ResponseWriter, r *http.Request) {
+	q := domain.ServiceDowntimeQuery{
+		CustomerImpact: r.URL.Query().Get("customer_impact") == "true",
+		OwningTeam:     r.URL.Query().Get("team"),
+	}
+	if v := r.URL.Query().Get("tier"); v != "" {
+		tier, err := strconv.Atoi(v)
+		if err != nil || tier < 1 {
+			respondError(w, http.StatusBadRequest, "tier must be a positive integer")
+			return
+		}
+		q.MaxTier = tier
+	}
+
+	var ok bool
+	if q.Since, q.Until, ok = reportRange(w, r); !ok {
+		return
+	}
+
+	report, err := h.service.GetServiceDowntime(r.Context(), q)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// reportRange parses a report's since and until parameters. until defaults
+// to now and since to defaultReportWindow before until. On a malformed
+// timestamp it writes a 400 response and returns false.
+func reportRange(w http.ResponseWriter, r *http.Request) (since, until time.Time, ok bool) {
+	query := r.URL.Query()
+	until = time.Now().UTC()
+	if v := query.Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid until timestamp")
+			return since, until, false
+		}
+		until = t
+	}
+	since = until.Add(-defaultReportWindow)
+	if v := query.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid since timestamp")
+			return since, until, false
+		}
+		since = t
+	}
+	return since, until, true
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func TestGetPagingLoadRoute(t *testing.T) {
+	_, router := newTestHandler()
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"defaults", "", http.StatusOK},
+		{"range and team", "?since=2030-01-01T00:00:00Z&until=2030-01-31T00:00:00Z&team=payments&tz=UTC", http.StatusOK},
+		{"invalid since", "?since=yesterday", http.StatusBadRequest},
+		{"invalid until", "?until=2030-01-01", http.StatusBadRequest},
+		{"invalid time zone", "?tz=Not/AZone", http.StatusBadRequest},
+		{"since after until", "?since=2030-02-01T00:00:00Z&until=2030-01-01T00:00:00Z", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/paging-load"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/paging-load", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	var load domain.PagingLoad
+	decodeJSON(t, rr.Body, &load)
+	if got := load.Until.Sub(load.Since); got != defaultReportWindow {
+		t.Errorf("default window = %s, want %s", got, defaultReportWindow)
+	}
+	if load.Timezone != time.UTC.String() || load.Teams == nil {
+		t.Errorf("load = %+v", load)
+	}
+}
+
+func TestGetNoisyAlertsRoute(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	for i, title := range []string{"Disk 91% full on db-3", "Disk 97% full on db-7", "Checkout latency high"} {
+		_, err := h.service.IngestAlert(ctx, &notification.Alert{
+			ExternalID: fmt.Sprint(i), Source: "fake", TeamName: "storage", Title: title, Severity: "high",
+			TriggeredAt: time.Now().Add(-time.Hour),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+		count int
+	}{
+		{"defaults", "", http.StatusOK, 2},
+		{"limit", "?limit=1", http.StatusOK, 1},
+		{"other team", "?team=payments", http.StatusOK, 0},
+		{"invalid limit", "?limit=-1", http.StatusBadRequest, 0},
+		{"invalid since", "?since=yesterday", http.StatusBadRequest, 0},
+		{"since after until", "?since=2030-02-01T00:00:00Z&until=2030-01-01T00:00:00Z", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/noisy-alerts"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.want, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			var report domain.NoisyAlerts
+			decodeJSON(t, rr.Body, &report)
+			if len(report.Alerts) != tt.count {
+				t.Fatalf("alerts = %+v, want %d fingerprints", report.Alerts, tt.count)
+			}
+			if tt.count > 0 && report.Alerts[0].Count != 2 {
+				t.Errorf("noisiest = %+v, want the 2 disk alerts", report.Alerts[0])
+			}
+		})
+	}
+}
+
+func TestGetDigestRoute(t *testing.T) {
+	_, router := newTestHandler()
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"defaults", "", http.StatusOK},
+		{"daily for a team", "?period=daily&team=payments&until=2030-01-01T09:00:00Z", http.StatusOK},
+		{"unknown period", "?period=monthly", http.StatusBadRequest},
+		{"invalid until", "?until=tomorrow", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/digest"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/digest", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	var digest domain.Digest
+	decodeJSON(t, rr.Body, &digest)
+	if digest.Period != domain.DigestWeekly || digest.Until.Sub(digest.Since) != 7*24*time.Hour || digest.Open == nil {
+		t.Errorf("digest = %+v", digest)
+	}
+}
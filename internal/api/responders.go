@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListOnCall handles GET /api/v1/oncall. The optional source parameter
+// limits the lookup to one notification service and schedule to one of its
+// schedules.
+func (h *Handler) ListOnCall(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	oncalls, err := h.service.ListOnCall(r.Context(), query.Get("source"), query.Get("schedule"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"oncall": oncalls})
+}
+
+// ListResponders handles GET /api/v1/outages/{id}/responders
+func (h *Handler) ListResponders(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	responders, err := h.service.ListResponders(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, responders)
+}
+
+// AssignResponder handles POST /api/v1/outages/{id}/responders. The
+// signed-in user is recorded as having made the assignment.
+func (h *Handler) AssignResponder(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	var req domain.AssignResponderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	assignment, err := h.service.AssignResponder(r.Context(), id, req, requestUser(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrConflict):
+			respondError(w, http.StatusConflict, "The role was assigned concurrently; try again")
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, assignment)
+}
+
+// UnassignResponder handles DELETE /api/v1/outages/{id}/responders/{role}
+func (h *Handler) UnassignResponder(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	if err := h.service.UnassignResponder(r.Context(), id, mux.Vars(r)["role"], requestUser(r)); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requestUserEmail returns the signed-in user's email address, or an empty
+// string when authentication is off or the caller is a service
+func requestUserEmail(r *http.Request) string {
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		return user.Email
+	}
+	return ""
+}
+
+// requestUser returns the identity changes by the caller are recorded
+// under, or an empty string when authentication is off
+func requestUser(r *http.Request) string {
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		return user.Identity()
+	}
+	return ""
+}
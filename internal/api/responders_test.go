@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/notification/mock"
+)
+
+func TestResponderRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	provider, err := mock.New(mock.Config{Fixture: &mock.Fixture{Schedules: []mock.Schedule{
+		{ID: "sched-payments", Name: "Payments primary", OnCall: []string{"alice@example.com"}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.service.RegisterNotificationService(provider)
+	ctx := context.Background()
+	cfg := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"alice@example.com"}}}}
+	if _, err := h.service.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	bob := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+	url := "/api/v1/outages/" + outage.ID.String() + "/responders"
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		user     *auth.UserInfo
+		wantCode int
+		wantBody string
+	}{
+		{"list on-call", http.MethodGet, "/api/v1/oncall", "", nil, http.StatusOK, "alice@example.com"},
+		{"list on-call for unknown source", http.MethodGet, "/api/v1/oncall?source=pagerduty", "", nil, http.StatusNotFound, ""},
+		{"list on-call for unknown schedule", http.MethodGet, "/api/v1/oncall?source=mock&schedule=nope", "", nil, http.StatusNotFound, ""},
+		{"assign from schedule", http.MethodPost, url, `{"role":"incident_commander","source":"mock","schedule":"sched-payments"}`, alice, http.StatusOK, `"schedule_id":"sched-payments"`},
+		{"assign by name", http.MethodPost, url, `{"role":"comms_lead","responder":"carol@example.com"}`, alice, http.StatusOK, `"assigned_by":"alice@example.com"`},
+		{"unknown role", http.MethodPost, url, `{"role":"hero","responder":"carol@example.com"}`, alice, http.StatusBadRequest, ""},
+		{"other team cannot assign", http.MethodPost, url, `{"role":"scribe","responder":"bob@example.com"}`, bob, http.StatusForbidden, ""},
+		{"list responders", http.MethodGet, url, "", nil, http.StatusOK, `"role":"comms_lead"`},
+		{"other team cannot unassign", http.MethodDelete, url + "/comms_lead", "", bob, http.StatusForbidden, ""},
+		{"unassign", http.MethodDelete, url + "/comms_lead", "", alice, http.StatusNoContent, ""},
+		{"unassign empty role", http.MethodDelete, url + "/comms_lead", "", alice, http.StatusNotFound, ""},
+		{"responder report", http.MethodGet, "/api/v1/reports/responders", "", nil, http.StatusOK, `"total":2`},
+		{"responder report bad range", http.MethodGet, "/api/v1/reports/responders?since=yesterday", "", nil, http.StatusBadRequest, ""},
+		{"responders of missing outage", http.MethodGet, "/api/v1/outages/00000000-0000-0000-0000-000000000000/responders", "", nil, http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, body)
+			if tt.user != nil {
+				req = req.WithContext(testutil.WithUser(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rr.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListRetentionRuns handles GET /api/v1/retention-runs. Only admins can
+// inspect retention runs.
+func (h *Handler) ListRetentionRuns(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can list retention runs")
+		return
+	}
+
+	runs, err := h.service.ListRetentionRuns(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if runs == nil {
+		runs = []*domain.RetentionRun{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"retention_runs": runs,
+	})
+}
+
+// StartRetentionRun handles POST /api/v1/retention-runs, applying the
+// retention policy in the background. The run is returned while it is
+// still running; poll GetRetentionRun for its outcome.
+func (h *Handler) StartRetentionRun(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can start retention runs")
+		return
+	}
+
+	var triggeredBy string
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		triggeredBy = user.Identity()
+	}
+
+	run, err := h.service.StartRetentionRun(r.Context(), triggeredBy)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, run)
+}
+
+// GetRetentionRun handles GET /api/v1/retention-runs/{id}
+func (h *Handler) GetRetentionRun(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid retention run ID")
+		return
+	}
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can view retention runs")
+		return
+	}
+
+	run, err := h.service.GetRetentionRun(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Retention run not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, run)
+}
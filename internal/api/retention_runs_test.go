@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/blobstore"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+)
+
+func TestRetentionRunRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"admin@example.com"})
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+	member := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+
+	do := func(method, url string, user *auth.UserInfo) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, url, nil)
+		if user != nil {
+			req = req.WithContext(testutil.WithUser(req.Context(), user))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := do(http.MethodPost, "/api/v1/retention-runs", admin); rr.Code != http.StatusBadRequest {
+		t.Errorf("start without a policy = %d, want 400; body: %s", rr.Code, rr.Body.String())
+	}
+	archives, err := blobstore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.service.SetRetentionPolicy(archives, domain.RetentionPolicy{ArchiveAfter: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	if rr := do(http.MethodPost, "/api/v1/retention-runs", member); rr.Code != http.StatusForbidden {
+		t.Errorf("member start = %d, want 403", rr.Code)
+	}
+	rr := do(http.MethodPost, "/api/v1/retention-runs", admin)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("admin start = %d, want 202; body: %s", rr.Code, rr.Body.String())
+	}
+	var started domain.RetentionRun
+	decodeJSON(t, rr.Body, &started)
+	if started.Trigger != domain.RetentionTriggerManual || started.TriggeredBy != "admin@example.com" {
+		t.Errorf("started = %+v, want a manual run by the admin", started)
+	}
+
+	runURL := "/api/v1/retention-runs/" + started.ID.String()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rr = do(http.MethodGet, runURL, admin)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("get run = %d, want 200; body: %s", rr.Code, rr.Body.String())
+		}
+		var got domain.RetentionRun
+		decodeJSON(t, rr.Body, &got)
+		if got.Status == domain.RetentionRunCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run = %+v, want it completed", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rr = do(http.MethodGet, "/api/v1/retention-runs", admin)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list = %d, want 200", rr.Code)
+	}
+	var list struct {
+		RetentionRuns []domain.RetentionRun `json:"retention_runs"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.RetentionRuns) != 1 || list.RetentionRuns[0].ID != started.ID {
+		t.Errorf("retention runs = %+v, want the started run", list.RetentionRuns)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		url      string
+		user     *auth.UserInfo
+		wantCode int
+	}{
+		{"member cannot list", "/api/v1/retention-runs", member, http.StatusForbidden},
+		{"member cannot get", runURL, member, http.StatusForbidden},
+		{"unknown run", "/api/v1/retention-runs/" + uuid.New().String(), admin, http.StatusNotFound},
+		{"bad id", "/api/v1/retention-runs/not-a-uuid", admin, http.StatusBadRequest},
+	} {
+		if rr := do(http.MethodGet, tt.url, tt.user); rr.Code != tt.wantCode {
+			t.Errorf("%s = %d, want %d", tt.name, rr.Code, tt.wantCode)
+		}
+	}
+}
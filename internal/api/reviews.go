@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetOutageReview handles GET /api/v1/outages/{id}/review
+func (h *Handler) GetOutageReview(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	review, err := h.service.GetOutageReview(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage review not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, review)
+}
+
+// UpdateOutageReview handles PATCH /api/v1/outages/{id}/review
+func (h *Handler) UpdateOutageReview(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	var req domain.UpdateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	var reviewer string
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		reviewer = user.Identity()
+	}
+
+	review, err := h.service.UpdateOutageReview(r.Context(), id, req, reviewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Outage not found")
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, review)
+}
+
+// ListOutageReviews handles GET /api/v1/reviews
+func (h *Handler) ListOutageReviews(w http.ResponseWriter, r *http.Request) {
+	reviews, err := h.service.ListOutageReviews(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+	if reviews == nil {
+		reviews = []*domain.OutageReview{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"reviews": reviews,
+	})
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestOutageReviewRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewURL := "/api/v1/outages/" + outage.ID.String() + "/review"
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		var req *http.Request
+		if body == "" {
+			req = httptest.NewRequest(method, url, nil)
+		} else {
+			req = httptest.NewRequest(method, url, strings.NewReader(body))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := do(http.MethodGet, reviewURL, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("GET review of open outage = %d, want 404", rr.Code)
+	}
+	if rr := do(http.MethodPatch, reviewURL, `{"status":"reviewed"}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("PATCH review of open outage = %d, want 400", rr.Code)
+	}
+
+	resolved := "resolved"
+	if _, err := h.service.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &resolved}); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := do(http.MethodPatch, reviewURL, `{"status":"review-scheduled","scheduled_for":"2030-01-02T15:00:00Z"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PATCH review = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var review domain.OutageReview
+	decodeJSON(t, rr.Body, &review)
+	if review.Status != domain.ReviewScheduled || review.ScheduledFor == nil {
+		t.Errorf("review = %+v", review)
+	}
+
+	rr = do(http.MethodGet, "/api/v1/reviews?status=review-scheduled", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET reviews = %d, want 200", rr.Code)
+	}
+	var list struct {
+		Reviews []domain.OutageReview `json:"reviews"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.Reviews) != 1 || list.Reviews[0].OutageID != outage.ID {
+		t.Errorf("reviews = %+v", list.Reviews)
+	}
+
+	if rr := do(http.MethodGet, "/api/v1/reviews?status=bogus", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("GET reviews with bad status = %d, want 400", rr.Code)
+	}
+}
@@ -0,0 +1,15 @@
+package api
+
+import "net/http"
+
+// GetCustomFieldSchemas handles GET /api/v1/schemas/custom-fields
+func (h *Handler) GetCustomFieldSchemas(w http.ResponseWriter, r *http.Request) {
+	schemas, err := h.service.CustomFieldSchemas(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"schemas": schemas,
+	})
+}
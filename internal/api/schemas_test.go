@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/validation"
+)
+
+func TestCustomFieldSchemas(t *testing.T) {
+	h, router := newTestHandler()
+	schemas := validation.Schemas{
+		validation.EntityOutage: {Fields: []validation.FieldDefinition{
+			{Name: "impact", Type: validation.FieldTypeString, Required: true, AllowedValues: []string{"partial", "full"}},
+		}},
+	}
+	if err := h.service.SetCustomFieldSchemas(schemas); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schemas/custom-fields", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", rr.Code)
+	}
+	var resp struct {
+		Schemas validation.Schemas `json:"schemas"`
+	}
+	decodeJSON(t, rr.Body, &resp)
+	if got := resp.Schemas[validation.EntityOutage].Fields; len(got) != 1 || got[0].Name != "impact" || !got[0].Required {
+		t.Errorf("outage schema fields = %+v", got)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"conforming", `{"title":"t","description":"d","severity":"high","custom_fields":{"impact":"full"}}`, http.StatusCreated},
+		{"missing required", `{"title":"t","description":"d","severity":"high"}`, http.StatusBadRequest},
+		{"disallowed value", `{"title":"t","description":"d","severity":"high","custom_fields":{"impact":"total"}}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/outages", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Errorf("POST status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/gorilla/mux"
+)
+
+// ListServices handles GET /api/v1/services, listing the service catalogue
+func (h *Handler) ListServices(w http.ResponseWriter, r *http.Request) {
+	services, err := h.service.ListServices(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"services": services,
+	})
+}
+
+// GetService handles GET /api/v1/services/{name}
+func (h *Handler) GetService(w http.ResponseWriter, r *http.Request) {
+	svc, err := h.service.GetService(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Service not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, svc)
+}
+
+// SaveService handles PUT /api/v1/services/{name}, adding the service to
+// the catalogue or replacing it. Only admins can change the catalogue, and
+// services synced from an external catalogue cannot be replaced.
+func (h *Handler) SaveService(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can change the service catalogue")
+		return
+	}
+
+	var svc domain.Service
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&svc); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	if svc.Name != "" && svc.Name != name {
+		respondError(w, http.StatusBadRequest, "Service name does not match the URL")
+		return
+	}
+	svc.Name = name
+
+	saved, created, err := h.service.SaveService(r.Context(), svc)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	code := http.StatusOK
+	if created {
+		code = http.StatusCreated
+	}
+	respondJSON(w, code, saved)
+}
+
+// DeleteService handles DELETE /api/v1/services/{name}. Only admins can
+// change the catalogue.
+func (h *Handler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can change the service catalogue")
+		return
+	}
+
+	if err := h.service.DeleteService(r.Context(), mux.Vars(r)["name"]); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Service not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SyncServices handles POST /api/v1/services/sync, copying the services of
+// every configured external catalogue. Only admins can sync.
+func (h *Handler) SyncServices(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can sync services")
+		return
+	}
+
+	plan, err := h.service.SyncServices(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, plan)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestServiceCatalogueRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"admin@example.com"})
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+	member := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+
+	do := func(method, url, body string, user *auth.UserInfo) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	const checkout = `{"description": "Checkout API", "statuspage_component": "cmp1", "tier": 1,
+		"runbook_url": "https://runbooks.example.com/checkout", "repo": "https://github.com/example/checkout"}`
+	const impacted = `{"title": "Checkout down", "severity": "high", "affected_services": ["checkout"], "customer_impact": true,
+		"impact_started_at": "2026-01-01T10:00:00Z", "impact_ended_at": "2026-01-01T11:00:00Z"}`
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		user     *auth.UserInfo
+		wantCode int
+	}{
+		{"member save", http.MethodPut, "/api/v1/services/checkout", checkout, member, http.StatusForbidden},
+		{"mismatched name", http.MethodPut, "/api/v1/services/checkout", `{"name": "other"}`, admin, http.StatusBadRequest},
+		{"unknown field", http.MethodPut, "/api/v1/services/checkout", `{"owner": "payments"}`, admin, http.StatusBadRequest},
+		{"unknown owning team", http.MethodPut, "/api/v1/services/checkout", `{"owning_team": "payments"}`, admin, http.StatusBadRequest},
+		{"bad runbook", http.MethodPut, "/api/v1/services/checkout", `{"runbook_url": "runbooks/checkout"}`, admin, http.StatusBadRequest},
+		{"add", http.MethodPut, "/api/v1/services/checkout", checkout, admin, http.StatusCreated},
+		{"replace", http.MethodPut, "/api/v1/services/checkout", checkout, admin, http.StatusOK},
+		{"get", http.MethodGet, "/api/v1/services/checkout", "", member, http.StatusOK},
+		{"get unknown", http.MethodGet, "/api/v1/services/missing", "", member, http.StatusNotFound},
+		{"outage with unknown service", http.MethodPost, "/api/v1/outages", `{"title": "t", "severity": "low", "affected_services": ["missing"]}`, member, http.StatusBadRequest},
+		{"outage ending before it starts", http.MethodPost, "/api/v1/outages",
+			`{"title": "t", "severity": "low", "impact_started_at": "2026-01-01T11:00:00Z", "impact_ended_at": "2026-01-01T10:00:00Z"}`, member, http.StatusBadRequest},
+		{"outage", http.MethodPost, "/api/v1/outages", impacted, member, http.StatusCreated},
+		{"downtime with a bad tier", http.MethodGet, "/api/v1/reports/service-downtime?tier=0", "", member, http.StatusBadRequest},
+		{"member sync", http.MethodPost, "/api/v1/services/sync", "", member, http.StatusForbidden},
+		{"sync", http.MethodPost, "/api/v1/services/sync", "", admin, http.StatusOK},
+		{"downtime with a bad range", http.MethodGet, "/api/v1/reports/service-downtime?since=2026-01-02T00:00:00Z&until=2026-01-01T00:00:00Z", "", member, http.StatusBadRequest},
+		{"member delete", http.MethodDelete, "/api/v1/services/checkout", "", member, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rr := do(tt.method, tt.url, tt.body, tt.user)
+		if rr.Code != tt.wantCode {
+			t.Fatalf("%s: status = %d, want %d; body: %s", tt.name, rr.Code, tt.wantCode, rr.Body.String())
+		}
+		if tt.name == "outage" {
+			var outage domain.Outage
+			decodeJSON(t, rr.Body, &outage)
+			if len(outage.AffectedServices) != 1 || !outage.CustomerImpact || outage.ImpactStartedAt == nil || outage.ImpactEndedAt == nil {
+				t.Errorf("outage = %+v, want its impact recorded", outage)
+			}
+		}
+	}
+
+	rr := do(http.MethodGet, "/api/v1/reports/service-downtime?since=2026-01-01T00:00:00Z&until=2026-01-02T00:00:00Z&customer_impact=true&tier=1", "", member)
+	var report domain.ServiceDowntime
+	decodeJSON(t, rr.Body, &report)
+	if rr.Code != http.StatusOK || len(report.Services) != 1 || !report.CustomerImpact {
+		t.Fatalf("downtime = %d %+v, want the checkout service", rr.Code, report)
+	}
+	if got := report.Services[0]; got.Service != "checkout" || got.Tier != 1 || got.Outages != 1 || got.DowntimeSeconds != 3600 {
+		t.Errorf("checkout downtime = %+v, want one outage and an hour", got)
+	}
+
+	if rr := do(http.MethodDelete, "/api/v1/services/checkout", "", admin); rr.Code != http.StatusNoContent {
+		t.Fatalf("delete: status = %d, want 204", rr.Code)
+	}
+	rr = do(http.MethodGet, "/api/v1/services", "", member)
+	var list struct {
+		Services []domain.Service `json:"services"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if rr.Code != http.StatusOK || list.Services == nil || len(list.Services) != 0 {
+		t.Errorf("list = %d %+v, want an empty list", rr.Code, list)
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListSimilarOutages handles GET /api/v1/outages/{id}/similar. The optional
+// limit and min_score parameters cap how many outages are returned and how
+// alike they must be.
+func (h *Handler) ListSimilarOutages(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+	limit, _, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	var minScore float64
+	if value := r.URL.Query().Get("min_score"); value != "" {
+		if minScore, err = strconv.ParseFloat(value, 64); err != nil {
+			respondError(w, http.StatusBadRequest, "min_score must be a number")
+			return
+		}
+	}
+
+	similar, err := h.service.FindSimilarOutages(r.Context(), id, limit, minScore)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if similar == nil {
+		similar = []*domain.SimilarOutage{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"similar": similar})
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+func TestListSimilarOutages(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	past, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Checkout API timeouts", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Checkout API timing out", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "/api/v1/outages/" + outage.ID.String() + "/similar"
+
+	tests := []struct {
+		name  string
+		url   string
+		want  int
+		count int
+	}{
+		{"defaults", url, http.StatusOK, 1},
+		{"high minimum", url + "?min_score=0.95", http.StatusOK, 0},
+		{"invalid min_score", url + "?min_score=high", http.StatusBadRequest, 0},
+		{"min_score out of range", url + "?min_score=1.5", http.StatusBadRequest, 0},
+		{"invalid limit", url + "?limit=-1", http.StatusBadRequest, 0},
+		{"missing outage", "/api/v1/outages/" + uuid.New().String() + "/similar", http.StatusNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.want, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+			var body struct {
+				Similar []domain.SimilarOutage `json:"similar"`
+			}
+			decodeJSON(t, rr.Body, &body)
+			if len(body.Similar) != tt.count {
+				t.Fatalf("similar = %+v, want %d", body.Similar, tt.count)
+			}
+			if tt.count > 0 && body.Similar[0].Outage.ID != past.ID {
+				t.Errorf("similar[0] = %s, want %s", body.Similar[0].Outage.ID, past.ID)
+			}
+		})
+	}
+}
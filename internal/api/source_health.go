@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// ListSourceHealth handles GET /api/v1/sources/health
+func (h *Handler) ListSourceHealth(w http.ResponseWriter, r *http.Request) {
+	health, err := h.service.ListSourceHealth(r.Context(), time.Now())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"sources": health,
+	})
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestListSourceHealth(t *testing.T) {
+	h, router := newTestHandler()
+	if err := h.service.SetStalenessPolicy(domain.StalenessPolicy{Sources: map[string]time.Duration{"mailgw": time.Hour}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/sources/health", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET sources/health = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var list struct {
+		Sources []domain.SourceHealth `json:"sources"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.Sources) != 1 || list.Sources[0].Source != "mailgw" || list.Sources[0].MaxSilenceSeconds != 3600 {
+		t.Errorf("sources = %+v, want mailgw with a 1h threshold", list.Sources)
+	}
+	if list.Sources[0].Stale {
+		t.Error("source stale right after startup")
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// SummarizeOutage handles POST /api/v1/outages/{id}/summarize, writing an
+// executive summary of the outage with the configured language model and
+// adding it as a note. Model failures are reported as 502.
+func (h *Handler) SummarizeOutage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	note, err := h.service.SummarizeOutage(r.Context(), id, requestUser(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrForbidden):
+			respondError(w, http.StatusForbidden, err.Error())
+		default:
+			h.logger.ErrorContext(r.Context(), "failed to summarize outage", "outage_id", id, "error", err)
+			respondError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, note)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+)
+
+// stubSummarizer summarizes every outage the same way, or fails with err
+type stubSummarizer struct {
+	err error
+}
+
+func (s stubSummarizer) Summarize(_ context.Context, outage *domain.Outage, _ []domain.TimelineEvent) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	return outage.Title + " is resolved.", nil
+}
+
+func TestSummarizeOutage(t *testing.T) {
+	h, router := newTestHandler()
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "Checkout down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "/api/v1/outages/" + outage.ID.String() + "/summarize"
+	summarize := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url, nil)
+		req = req.WithContext(testutil.WithUser(req.Context(), &auth.UserInfo{Email: "alice@example.com"}))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := summarize(url); rr.Code != http.StatusNotFound {
+		t.Fatalf("without a summarizer status = %d, want 404; body: %s", rr.Code, rr.Body.String())
+	}
+
+	h.service.SetOutageSummarizer(stubSummarizer{})
+	rr := summarize(url)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var note domain.Note
+	decodeJSON(t, rr.Body, &note)
+	if note.Content != "Checkout down is resolved." || note.Author != domain.SummaryAuthor ||
+		note.Metadata[domain.NoteMetadataSummary] != "alice@example.com" {
+		t.Errorf("note = %+v", note)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		err  error
+		want int
+	}{
+		{"invalid outage ID", "/api/v1/outages/nope/summarize", nil, http.StatusBadRequest},
+		{"missing outage", "/api/v1/outages/" + uuid.New().String() + "/summarize", nil, http.StatusNotFound},
+		{"model failure", url, errors.New("model unavailable"), http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.service.SetOutageSummarizer(stubSummarizer{err: tt.err})
+			if rr := summarize(tt.url); rr.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListTags handles GET /api/v1/outages/{id}/tags
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	tags, err := h.service.ListTagsByOutage(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if tags == nil {
+		tags = []*domain.Tag{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"tags": tags})
+}
+
+// ListTagKeys handles GET /api/v1/tags/keys, listing the tag keys in use
+// with how many tags have each, for autocompletion
+func (h *Handler) ListTagKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListTagKeys(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if keys == nil {
+		keys = []domain.TagUsage{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"keys": keys})
+}
+
+// ListTagValues handles GET /api/v1/tags/values?key=..., listing the values
+// in use for a key with how many tags have each
+func (h *Handler) ListTagValues(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		respondError(w, http.StatusBadRequest, "The key parameter is required")
+		return
+	}
+
+	values, err := h.service.ListTagValues(r.Context(), key)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if values == nil {
+		values = []domain.TagUsage{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"values": values})
+}
+
+// GetTag handles GET /api/v1/tags/{id}
+func (h *Handler) GetTag(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	tag, err := h.service.GetTag(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Tag not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tag)
+}
+
+// UpdateTag handles PATCH /api/v1/tags/{id}
+func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	var req domain.UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	tag, err := h.service.UpdateTag(r.Context(), id, req)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Tag not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tag)
+}
+
+// DeleteTag handles DELETE /api/v1/tags/{id}
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	if err := h.service.DeleteTag(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Tag not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
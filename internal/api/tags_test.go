@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestTagRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := h.service.AddTag(ctx, outage.ID, "service", "db", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, url, nil))
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/api/v1/outages/"+outage.ID.String()+"/tags")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list tags = %d, want 200", rr.Code)
+	}
+	var list struct {
+		Tags []domain.Tag `json:"tags"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.Tags) != 1 || list.Tags[0].ID != tag.ID {
+		t.Errorf("tags = %+v", list.Tags)
+	}
+
+	tagURL := "/api/v1/tags/" + tag.ID.String()
+	if rr := serve(http.MethodGet, tagURL); rr.Code != http.StatusOK {
+		t.Errorf("get tag = %d, want 200", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, tagURL, strings.NewReader(`{"value":"cache"}`)))
+	var updated domain.Tag
+	if rr.Code != http.StatusOK {
+		t.Errorf("update tag = %d, want 200", rr.Code)
+	} else if decodeJSON(t, rr.Body, &updated); updated.Value != "cache" || updated.ID != tag.ID {
+		t.Errorf("updated tag = %+v, want value cache", updated)
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, tagURL, strings.NewReader(`{"key":""}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("update tag with blank key = %d, want 400", rr.Code)
+	}
+	// The search route still wins over the tag ID route
+	if rr := serve(http.MethodGet, "/api/v1/tags/search?key=service&value=db"); rr.Code != http.StatusOK {
+		t.Errorf("search tags = %d, want 200", rr.Code)
+	}
+	if rr := serve(http.MethodDelete, tagURL); rr.Code != http.StatusNoContent {
+		t.Errorf("delete tag = %d, want 204", rr.Code)
+	}
+	if rr := serve(http.MethodGet, tagURL); rr.Code != http.StatusNotFound {
+		t.Errorf("get deleted tag = %d, want 404", rr.Code)
+	}
+	if rr := serve(http.MethodDelete, tagURL); rr.Code != http.StatusNotFound {
+		t.Errorf("delete deleted tag = %d, want 404", rr.Code)
+	}
+}
+
+func TestTagUsageRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+	for _, title := range []string{"db down", "db slow"} {
+		outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: "high"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := h.service.AddTag(ctx, outage.ID, "service", "db", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/tags/keys", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list tag keys = %d, want 200", rr.Code)
+	}
+	var keys struct {
+		Keys []domain.TagUsage `json:"keys"`
+	}
+	decodeJSON(t, rr.Body, &keys)
+	if len(keys.Keys) != 1 || keys.Keys[0] != (domain.TagUsage{Key: "service", Count: 2}) {
+		t.Errorf("keys = %+v, want service used twice", keys.Keys)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/tags/values?key=service", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list tag values = %d, want 200", rr.Code)
+	}
+	var values struct {
+		Values []domain.TagUsage `json:"values"`
+	}
+	decodeJSON(t, rr.Body, &values)
+	if len(values.Values) != 1 || values.Values[0] != (domain.TagUsage{Key: "service", Value: "db", Count: 2}) {
+		t.Errorf("values = %+v, want service=db used twice", values.Values)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/tags/values", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("list tag values without key = %d, want 400", rr.Code)
+	}
+}
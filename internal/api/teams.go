@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/gorilla/mux"
+)
+
+// allTeams is the team filter value that turns team filtering off
+const allTeams = "all"
+
+// ListTeams handles GET /api/v1/teams
+func (h *Handler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.service.ListTeams(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"teams": teams,
+	})
+}
+
+// GetTeam handles GET /api/v1/teams/{name}
+func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	team, err := h.service.GetTeam(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Team not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, team)
+}
+
+// SyncTeams handles POST /api/v1/teams/sync, copying the teams of every
+// notification service that can list them. Only admins can sync.
+func (h *Handler) SyncTeams(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can sync teams")
+		return
+	}
+
+	plan, err := h.service.SyncTeams(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, plan)
+}
+
+// teamFilter returns the teams whose outages a listing should show, or nil
+// for every outage. The team query parameter takes a comma-separated list
+// of team names, or "all". Without it, signed-in users get their default
+// team filter preference and otherwise the teams they are a member of,
+// including those their IdP groups map to.
+func (h *Handler) teamFilter(r *http.Request) ([]string, error) {
+	if value, ok := r.URL.Query()["team"]; ok {
+		return parseTeamFilter(strings.Join(value, ",")), nil
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		return nil, nil
+	}
+	prefs, err := h.service.GetUserPreferences(r.Context(), user.Sub)
+	if err != nil {
+		return nil, err
+	}
+	if prefs.DefaultTeamFilter != "" {
+		return parseTeamFilter(prefs.DefaultTeamFilter), nil
+	}
+	teams, err := h.service.UserTeams(r.Context(), user.Email)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range user.Teams {
+		if !slices.Contains(teams, team) {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+// parseTeamFilter splits a comma-separated team filter, returning nil for
+// an empty filter or "all"
+func parseTeamFilter(value string) []string {
+	var teams []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == allTeams {
+			return nil
+		}
+		if name != "" {
+			teams = append(teams, name)
+		}
+	}
+	return teams
+}
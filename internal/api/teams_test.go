@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestTeamRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"admin@example.com"})
+	ctx := context.Background()
+	cfg := domain.OpsConfig{Teams: []domain.Team{
+		{Name: "payments", Members: []string{"alice@example.com"}},
+		{Name: "search", Members: []string{"bob@example.com"}},
+	}}
+	if _, err := h.service.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+	owned, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := h.service.AddNote(ctx, owned.ID, domain.AddNoteRequest{Content: "card errors", Format: "plaintext", Author: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := h.service.AddTag(ctx, owned.ID, "service", "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "slow search", Severity: "low", OwningTeam: "search"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "unowned", Severity: "low"}); err != nil {
+		t.Fatal(err)
+	}
+	// carol is in no team and pre-selects search in listings
+	carol := &auth.UserInfo{Email: "carol@example.com", Sub: "sub-carol"}
+	search := "search"
+	if _, err := h.service.UpdateUserPreferences(ctx, carol.Sub, domain.UpdatePreferencesRequest{DefaultTeamFilter: &search}); err != nil {
+		t.Fatal(err)
+	}
+	alice := &auth.UserInfo{Email: "Alice@example.com", Sub: "sub-alice"}
+	bob := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+	// dave and erin are not listed anywhere; their IdP groups map them to
+	// the payments team and the admin role
+	dave := &auth.UserInfo{Email: "dave@example.com", Sub: "sub-dave", Teams: []string{"payments"}}
+	erin := &auth.UserInfo{Email: "erin@example.com", Sub: "sub-erin", Roles: []string{auth.RoleAdmin}}
+	outageURL := "/api/v1/outages/" + owned.ID.String()
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		user     *auth.UserInfo
+		wantCode int
+		wantLen  int // Outages listed, for outage listings
+	}{
+		{"list teams", http.MethodGet, "/api/v1/teams", "", nil, http.StatusOK, 0},
+		{"get team", http.MethodGet, "/api/v1/teams/payments", "", nil, http.StatusOK, 0},
+		{"get unknown team", http.MethodGet, "/api/v1/teams/billing", "", nil, http.StatusNotFound, 0},
+		{"member cannot sync", http.MethodPost, "/api/v1/teams/sync", "", bob, http.StatusForbidden, 0},
+		{"admin syncs", http.MethodPost, "/api/v1/teams/sync", "", admin, http.StatusOK, 0},
+		{"idp admin syncs", http.MethodPost, "/api/v1/teams/sync", "", erin, http.StatusOK, 0},
+		{"member sees own team", http.MethodGet, "/api/v1/outages", "", alice, http.StatusOK, 1},
+		{"idp member sees own team", http.MethodGet, "/api/v1/outages", "", dave, http.StatusOK, 1},
+		{"preference overrides teams", http.MethodGet, "/api/v1/outages", "", carol, http.StatusOK, 1},
+		{"explicit team list", http.MethodGet, "/api/v1/outages?team=payments,search", "", alice, http.StatusOK, 2},
+		{"all teams", http.MethodGet, "/api/v1/outages?team=all", "", alice, http.StatusOK, 3},
+		{"auth disabled sees all", http.MethodGet, "/api/v1/outages", "", nil, http.StatusOK, 3},
+		{"search by tag and team", http.MethodGet, "/api/v1/tags/search?key=missing&value=x&team=payments", "", nil, http.StatusOK, 0},
+		{"other team cannot update", http.MethodPatch, outageURL, `{"title":"renamed"}`, bob, http.StatusForbidden, 0},
+		{"other team cannot transition", http.MethodPost, outageURL + "/transition", `{"action":"investigate"}`, bob, http.StatusForbidden, 0},
+		{"other team cannot delete", http.MethodDelete, outageURL, "", bob, http.StatusForbidden, 0},
+		{"other team cannot add note", http.MethodPost, outageURL + "/notes", `{"content":"hi","format":"plaintext"}`, bob, http.StatusForbidden, 0},
+		{"other team cannot edit note", http.MethodPatch, "/api/v1/notes/" + note.ID.String(), `{"content":"edited"}`, bob, http.StatusForbidden, 0},
+		{"other team cannot delete note", http.MethodDelete, "/api/v1/notes/" + note.ID.String(), "", bob, http.StatusForbidden, 0},
+		{"other team cannot tag", http.MethodPost, outageURL + "/tags", `{"key":"region","value":"eu"}`, bob, http.StatusForbidden, 0},
+		{"other team cannot untag", http.MethodDelete, "/api/v1/tags/" + tag.ID.String(), "", bob, http.StatusForbidden, 0},
+		{"other team cannot review", http.MethodPatch, outageURL + "/review", `{"summary":"x"}`, bob, http.StatusForbidden, 0},
+		{"owner adds note", http.MethodPost, outageURL + "/notes", `{"content":"hi","format":"plaintext"}`, alice, http.StatusCreated, 0},
+		{"owner untags", http.MethodDelete, "/api/v1/tags/" + tag.ID.String(), "", alice, http.StatusNoContent, 0},
+		{"unknown owner rejected", http.MethodPatch, outageURL, `{"owning_team":"billing"}`, alice, http.StatusBadRequest, 0},
+		{"owner updates", http.MethodPatch, outageURL, `{"title":"renamed"}`, alice, http.StatusOK, 0},
+		{"idp member updates", http.MethodPatch, outageURL, `{"title":"renamed again"}`, dave, http.StatusOK, 0},
+		{"idp admin updates", http.MethodPatch, outageURL, `{"severity":"critical"}`, erin, http.StatusOK, 0},
+		{"admin reassigns", http.MethodPatch, outageURL, `{"owning_team":"search"}`, admin, http.StatusOK, 0},
+		{"new owner deletes", http.MethodDelete, outageURL, "", bob, http.StatusNoContent, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, body)
+			if tt.user != nil {
+				req = req.WithContext(testutil.WithUser(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.method != http.MethodGet || !strings.Contains(tt.url, "outages") && !strings.Contains(tt.url, "tags") {
+				return
+			}
+			var resp struct {
+				Outages []domain.Outage `json:"outages"`
+			}
+			decodeJSON(t, rr.Body, &resp)
+			if len(resp.Outages) != tt.wantLen {
+				t.Errorf("listed %d outages, want %d", len(resp.Outages), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/gorilla/mux"
+)
+
+// ListOutageTemplates handles GET /api/v1/templates
+func (h *Handler) ListOutageTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.ListOutageTemplates(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"templates": templates,
+	})
+}
+
+// GetOutageTemplate handles GET /api/v1/templates/{name}
+func (h *Handler) GetOutageTemplate(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := h.service.GetOutageTemplate(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tmpl)
+}
+
+// SaveOutageTemplate handles PUT /api/v1/templates/{name}, creating or
+// replacing the template. Like applying the operational config, only
+// admins can change templates.
+func (h *Handler) SaveOutageTemplate(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can change templates")
+		return
+	}
+
+	var tmpl domain.OutageTemplate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&tmpl); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	if tmpl.Name != "" && tmpl.Name != name {
+		respondError(w, http.StatusBadRequest, "Template name does not match the URL")
+		return
+	}
+	tmpl.Name = name
+
+	saved, err := h.service.SaveOutageTemplate(r.Context(), tmpl)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, saved)
+}
+
+// DeleteOutageTemplate handles DELETE /api/v1/templates/{name}. Only admins
+// can delete templates.
+func (h *Handler) DeleteOutageTemplate(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can change templates")
+		return
+	}
+
+	if err := h.service.DeleteOutageTemplate(r.Context(), mux.Vars(r)["name"]); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateOutageFromTemplate handles POST
+// /api/v1/outages/from-template/{name}. The body is optional: its fields
+// override the template's, and its variables fill the template's
+// placeholders.
+func (h *Handler) CreateOutageFromTemplate(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateOutageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondInvalidBody(w, err)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	if req.Template != "" && req.Template != name {
+		respondError(w, http.StatusBadRequest, "Template does not match the URL")
+		return
+	}
+
+	outage, err := h.service.CreateOutageFromTemplate(r.Context(), name, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Template not found")
+		case errors.Is(err, domain.ErrInvalidInput):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.serviceError(w, r, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, outage)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestOutageTemplateRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"admin@example.com"})
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+	member := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+
+	do := func(method, url, body string, user *auth.UserInfo) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	const tmpl = `{"title": "Database failover on {cluster}", "severity": "high", "checklist": ["Confirm the new primary"]}`
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		user     *auth.UserInfo
+		wantCode int
+	}{
+		{"member save", http.MethodPut, "/api/v1/templates/db-failover", tmpl, member, http.StatusForbidden},
+		{"mismatched name", http.MethodPut, "/api/v1/templates/db-failover", `{"name": "other"}`, admin, http.StatusBadRequest},
+		{"unknown field", http.MethodPut, "/api/v1/templates/db-failover", `{"steps": []}`, admin, http.StatusBadRequest},
+		{"save", http.MethodPut, "/api/v1/templates/db-failover", tmpl, admin, http.StatusOK},
+		{"get", http.MethodGet, "/api/v1/templates/db-failover", "", member, http.StatusOK},
+		{"get unknown", http.MethodGet, "/api/v1/templates/missing", "", member, http.StatusNotFound},
+		{"create unknown", http.MethodPost, "/api/v1/outages/from-template/missing", "", member, http.StatusNotFound},
+		{"create without variables", http.MethodPost, "/api/v1/outages/from-template/db-failover", "", member, http.StatusBadRequest},
+		{"create", http.MethodPost, "/api/v1/outages/from-template/db-failover", `{"variables": {"cluster": "main"}}`, member, http.StatusCreated},
+		{"member delete", http.MethodDelete, "/api/v1/templates/db-failover", "", member, http.StatusForbidden},
+		{"delete", http.MethodDelete, "/api/v1/templates/db-failover", "", admin, http.StatusNoContent},
+		{"delete again", http.MethodDelete, "/api/v1/templates/db-failover", "", admin, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := do(tt.method, tt.url, tt.body, tt.user)
+		if rr.Code != tt.wantCode {
+			t.Fatalf("%s: status = %d, want %d; body: %s", tt.name, rr.Code, tt.wantCode, rr.Body.String())
+		}
+		if tt.name == "create" {
+			var outage domain.Outage
+			decodeJSON(t, rr.Body, &outage)
+			if outage.Title != "Database failover on main" || outage.Severity != "high" || len(outage.Notes) != 1 {
+				t.Errorf("outage = %+v, want it filled from the template", outage)
+			}
+		}
+	}
+
+	rr := do(http.MethodGet, "/api/v1/templates", "", member)
+	var list struct {
+		Templates []domain.OutageTemplate `json:"templates"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if rr.Code != http.StatusOK || list.Templates == nil || len(list.Templates) != 0 {
+		t.Errorf("list = %d %+v, want an empty list", rr.Code, list)
+	}
+}
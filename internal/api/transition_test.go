@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+func TestTransitionOutage(t *testing.T) {
+	h, router := newTestHandler()
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "/api/v1/outages/" + outage.ID.String() + "/transition"
+
+	post := func(url string, body domain.TransitionRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, url, encodeJSON(t, body)))
+		return rr
+	}
+
+	rr := post(url, domain.TransitionRequest{Action: "mitigate", Actor: "alice", Reason: "failed over"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("mitigate = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var got domain.Outage
+	decodeJSON(t, rr.Body, &got)
+	if got.Status != domain.StatusMitigated || got.MitigatedAt == nil {
+		t.Errorf("after mitigate: status %q, mitigated_at %v", got.Status, got.MitigatedAt)
+	}
+
+	if rr := post(url, domain.TransitionRequest{Action: "reopen"}); rr.Code != http.StatusBadRequest {
+		t.Errorf("reopen from mitigated = %d, want 400", rr.Code)
+	}
+	if rr := post(url, domain.TransitionRequest{Action: "explode"}); rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown action = %d, want 400", rr.Code)
+	}
+	if rr := post("/api/v1/outages/"+uuid.New().String()+"/transition", domain.TransitionRequest{Action: "resolve"}); rr.Code != http.StatusNotFound {
+		t.Errorf("unknown outage = %d, want 404", rr.Code)
+	}
+	if rr := post("/api/v1/outages/not-a-uuid/transition", domain.TransitionRequest{Action: "resolve"}); rr.Code != http.StatusBadRequest {
+		t.Errorf("bad ID = %d, want 400", rr.Code)
+	}
+
+	// Leaving closed needs the explicit reopen action, not a status edit
+	if rr := post(url, domain.TransitionRequest{Action: "close"}); rr.Code != http.StatusOK {
+		t.Fatalf("close = %d, want 200", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/api/v1/outages/"+outage.ID.String(),
+		encodeJSON(t, map[string]string{"status": "open"})))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("PATCH status out of closed = %d, want 400", rr.Code)
+	}
+	if rr := post(url, domain.TransitionRequest{Action: "reopen", Reason: "recurred"}); rr.Code != http.StatusOK {
+		t.Errorf("reopen = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// isAdmin reports whether the caller may work with the trash and import
+// outages, as decided by the service's admin list
+func (h *Handler) isAdmin(r *http.Request) bool {
+	return h.service.IsAdmin(r.Context())
+}
+
+// parseIncludeDeleted parses the include_deleted query parameter, writing
+// an error response and returning false when it is invalid or the caller
+// is not an admin
+func (h *Handler) parseIncludeDeleted(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	includeDeleted, err := parseBoolParam(r.URL.Query().Get("include_deleted"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid include_deleted parameter")
+		return false, false
+	}
+	if includeDeleted && !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can list deleted items")
+		return false, false
+	}
+	return includeDeleted, true
+}
+
+// RestoreOutage handles POST /api/v1/outages/{id}/restore
+func (h *Handler) RestoreOutage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can restore outages")
+		return
+	}
+
+	outage, err := h.service.RestoreOutage(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Outage not found in trash")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, outage)
+}
+
+// RestoreNote handles POST /api/v1/notes/{id}/restore
+func (h *Handler) RestoreNote(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+	if !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only admins can restore notes")
+		return
+	}
+
+	note, err := h.service.RestoreNote(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Note not found in trash")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, note)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+)
+
+func TestTrashRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"Admin@example.com"})
+	ctx := context.Background()
+	outage, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := h.service.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "note", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+	member := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+
+	do := func(method, url string, user *auth.UserInfo) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, url, nil)
+		if user != nil {
+			req = req.WithContext(testutil.WithUser(req.Context(), user))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	outageURL := "/api/v1/outages/" + outage.ID.String()
+	noteURL := "/api/v1/notes/" + note.ID.String()
+	if rr := do(http.MethodDelete, noteURL, member); rr.Code != http.StatusNoContent {
+		t.Fatalf("delete note = %d, want 204", rr.Code)
+	}
+	if rr := do(http.MethodDelete, outageURL, member); rr.Code != http.StatusNoContent {
+		t.Fatalf("delete outage = %d, want 204", rr.Code)
+	}
+	if rr := do(http.MethodGet, outageURL, member); rr.Code != http.StatusNotFound {
+		t.Errorf("get deleted outage = %d, want 404", rr.Code)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		user     *auth.UserInfo
+		wantCode int
+		wantLen  int
+	}{
+		{"list hides trash", http.MethodGet, "/api/v1/outages", member, http.StatusOK, 0},
+		{"member cannot list trash", http.MethodGet, "/api/v1/outages?include_deleted=true", member, http.StatusForbidden, 0},
+		{"bad include_deleted", http.MethodGet, "/api/v1/outages?include_deleted=maybe", admin, http.StatusBadRequest, 0},
+		{"admin lists trash", http.MethodGet, "/api/v1/outages?include_deleted=true", admin, http.StatusOK, 1},
+		{"auth disabled lists trash", http.MethodGet, "/api/v1/outages?include_deleted=true", nil, http.StatusOK, 1},
+		{"notes hide trash", http.MethodGet, outageURL + "/notes", member, http.StatusOK, 0},
+		{"member cannot list trashed notes", http.MethodGet, outageURL + "/notes?include_deleted=true", member, http.StatusForbidden, 0},
+		{"admin lists trashed notes", http.MethodGet, outageURL + "/notes?include_deleted=true", admin, http.StatusOK, 1},
+		{"member cannot restore", http.MethodPost, outageURL + "/restore", member, http.StatusForbidden, 0},
+		{"restore unknown outage", http.MethodPost, "/api/v1/outages/" + uuid.New().String() + "/restore", admin, http.StatusNotFound, 0},
+		{"restore bad id", http.MethodPost, "/api/v1/outages/not-a-uuid/restore", admin, http.StatusBadRequest, 0},
+		{"admin restores outage", http.MethodPost, outageURL + "/restore", admin, http.StatusOK, 0},
+		{"restore live outage", http.MethodPost, outageURL + "/restore", admin, http.StatusNotFound, 0},
+		{"member cannot restore note", http.MethodPost, noteURL + "/restore", member, http.StatusForbidden, 0},
+		{"admin restores note", http.MethodPost, noteURL + "/restore", admin, http.StatusOK, 0},
+		{"restored outage listed", http.MethodGet, "/api/v1/outages", member, http.StatusOK, 1},
+		{"restored note listed", http.MethodGet, outageURL + "/notes", member, http.StatusOK, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := do(tt.method, tt.url, tt.user)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.method != http.MethodGet || rr.Code != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Outages []domain.Outage `json:"outages"`
+				Notes   []domain.Note   `json:"notes"`
+			}
+			decodeJSON(t, rr.Body, &resp)
+			if got := len(resp.Outages) + len(resp.Notes); got != tt.wantLen {
+				t.Errorf("listed %d items, want %d", got, tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetUpdateSLA handles GET /api/v1/outages/{id}/update-sla
+func (h *Handler) GetUpdateSLA(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	sla, err := h.service.GetUpdateSLA(r.Context(), id, time.Now())
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "No update SLA for this outage")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sla)
+}
+
+// ListUpdateSLAs handles GET /api/v1/update-sla
+// Pass overdue=true to only return outages whose status update is overdue.
+func (h *Handler) ListUpdateSLAs(w http.ResponseWriter, r *http.Request) {
+	overdue, err := parseBoolParam(r.URL.Query().Get("overdue"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid overdue parameter")
+		return
+	}
+
+	slas, err := h.service.ListUpdateSLAs(r.Context(), time.Now(), overdue)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"update_slas": slas,
+	})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestUpdateSLARoutes(t *testing.T) {
+	h, router := newTestHandler()
+	ctx := context.Background()
+
+	if err := h.service.SetUpdatePolicy(domain.UpdatePolicy{"critical": 30 * time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	critical, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Description: "d", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	low, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "typo", Description: "d", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(url string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return rr
+	}
+
+	rr := get("/api/v1/outages/" + critical.ID.String() + "/update-sla")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET update-sla = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var sla domain.UpdateSLA
+	decodeJSON(t, rr.Body, &sla)
+	if sla.OutageID != critical.ID || sla.IntervalSeconds != 1800 || sla.Overdue {
+		t.Errorf("sla = %+v", sla)
+	}
+
+	if rr := get("/api/v1/outages/" + low.ID.String() + "/update-sla"); rr.Code != http.StatusNotFound {
+		t.Errorf("GET update-sla without policy = %d, want 404", rr.Code)
+	}
+	if rr := get("/api/v1/outages/not-a-uuid/update-sla"); rr.Code != http.StatusBadRequest {
+		t.Errorf("GET update-sla with bad ID = %d, want 400", rr.Code)
+	}
+
+	rr = get("/api/v1/update-sla")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET update-sla list = %d, want 200", rr.Code)
+	}
+	var list struct {
+		UpdateSLAs []domain.UpdateSLA `json:"update_slas"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.UpdateSLAs) != 1 || list.UpdateSLAs[0].OutageID != critical.ID {
+		t.Errorf("update_slas = %+v", list.UpdateSLAs)
+	}
+
+	rr = get("/api/v1/update-sla?overdue=true")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET overdue update-sla = %d, want 200", rr.Code)
+	}
+	list.UpdateSLAs = nil
+	decodeJSON(t, rr.Body, &list)
+	if len(list.UpdateSLAs) != 0 {
+		t.Errorf("overdue update_slas = %+v, want none", list.UpdateSLAs)
+	}
+
+	if rr := get("/api/v1/update-sla?overdue=maybe"); rr.Code != http.StatusBadRequest {
+		t.Errorf("GET update-sla with bad overdue = %d, want 400", rr.Code)
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CreateSavedView handles POST /api/v1/views. The signed-in user owns the
+// view.
+func (h *Handler) CreateSavedView(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateSavedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	view, err := h.service.CreateSavedView(r.Context(), viewOwner(r), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, view)
+}
+
+// ListSavedViews handles GET /api/v1/views
+func (h *Handler) ListSavedViews(w http.ResponseWriter, r *http.Request) {
+	views, err := h.service.ListSavedViews(r.Context())
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if views == nil {
+		views = []*domain.SavedView{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"views": views,
+	})
+}
+
+// GetSavedView handles GET /api/v1/views/{id}
+func (h *Handler) GetSavedView(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid view ID")
+		return
+	}
+
+	view, err := h.service.GetSavedView(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "View not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, view)
+}
+
+// DeleteSavedView handles DELETE /api/v1/views/{id}. Only the view's owner
+// and admins can delete it.
+func (h *Handler) DeleteSavedView(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid view ID")
+		return
+	}
+
+	view, err := h.service.GetSavedView(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "View not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+	if !strings.EqualFold(view.Owner, viewOwner(r)) && !h.isAdmin(r) {
+		respondError(w, http.StatusForbidden, "Only the view's owner or an admin can delete it")
+		return
+	}
+
+	if err := h.service.DeleteSavedView(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "View not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExecuteSavedView handles GET /api/v1/views/{id}/outages, listing a page
+// of the outages currently matching the view
+func (h *Handler) ExecuteSavedView(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid view ID")
+		return
+	}
+	limit, offset, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	if limit == 0 {
+		limit = 50
+	}
+
+	view, outages, err := h.service.ExecuteSavedView(r.Context(), id, limit, offset)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "View not found")
+			return
+		}
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"view":    view,
+		"outages": outages,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// viewOwner identifies the signed-in user as a view owner: their email or
+// service identity, or their subject when the identity provider gives no
+// email. It is empty when authentication is disabled.
+func viewOwner(r *http.Request) string {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		return ""
+	}
+	if identity := user.Identity(); identity != "" {
+		return identity
+	}
+	return user.Sub
+}
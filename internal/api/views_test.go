@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestSavedViewRoutes(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.SetAdmins([]string{"admin@example.com"})
+	ctx := context.Background()
+	critical, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Slow search", Severity: "low"}); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, url, body string, user *auth.UserInfo) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	alice := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	bob := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+
+	if rr := do(http.MethodPost, "/api/v1/views", `{"name": "Bad", "filter": {"min_severity": "sev1"}}`, alice); rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid view status = %d, want 400", rr.Code)
+	}
+	rr := do(http.MethodPost, "/api/v1/views", `{"name": "Severe", "filter": {"min_severity": "high"}}`, alice)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST views = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var view domain.SavedView
+	decodeJSON(t, rr.Body, &view)
+	if view.Owner != "alice@example.com" {
+		t.Errorf("owner = %q, want alice@example.com", view.Owner)
+	}
+	if rr := do(http.MethodPost, "/api/v1/views", `{"name": "Severe"}`, bob); rr.Code != http.StatusConflict {
+		t.Errorf("duplicate view status = %d, want 409", rr.Code)
+	}
+
+	rr = do(http.MethodGet, "/api/v1/views", "", bob)
+	var list struct {
+		Views []domain.SavedView `json:"views"`
+	}
+	decodeJSON(t, rr.Body, &list)
+	if len(list.Views) != 1 || list.Views[0].ID != view.ID {
+		t.Errorf("views = %+v, want the saved view", list.Views)
+	}
+
+	rr = do(http.MethodGet, "/api/v1/views/"+view.ID.String()+"/outages", "", bob)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET view outages = %d, want 200; body: %s", rr.Code, rr.Body.String())
+	}
+	var executed struct {
+		View    domain.SavedView `json:"view"`
+		Outages []domain.Outage  `json:"outages"`
+		Limit   int              `json:"limit"`
+	}
+	decodeJSON(t, rr.Body, &executed)
+	if len(executed.Outages) != 1 || executed.Outages[0].ID != critical.ID || executed.Limit != 50 {
+		t.Errorf("executed = %+v, want the critical outage with the default limit", executed)
+	}
+	if rr := do(http.MethodGet, "/api/v1/views/"+view.ID.String()+"/outages?limit=x", "", bob); rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid limit status = %d, want 400", rr.Code)
+	}
+
+	if rr := do(http.MethodDelete, "/api/v1/views/"+view.ID.String(), "", bob); rr.Code != http.StatusForbidden {
+		t.Errorf("DELETE by another user = %d, want 403", rr.Code)
+	}
+	if rr := do(http.MethodDelete, "/api/v1/views/"+view.ID.String(), "", alice); rr.Code != http.StatusNoContent {
+		t.Errorf("DELETE by owner = %d, want 204", rr.Code)
+	}
+	if rr := do(http.MethodGet, "/api/v1/views/"+view.ID.String(), "", alice); rr.Code != http.StatusNotFound {
+		t.Errorf("GET deleted view = %d, want 404", rr.Code)
+	}
+}
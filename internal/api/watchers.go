@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListWatchers handles GET /api/v1/outages/{id}/watchers
+func (h *Handler) ListWatchers(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	watchers, err := h.service.ListWatchers(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"watchers": watchers,
+	})
+}
+
+// WatchOutage handles POST /api/v1/outages/{id}/watch, subscribing the
+// authenticated user to the outage's status changes and new notes. The body
+// is optional.
+func (h *Handler) WatchOutage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req domain.WatchOutageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondInvalidBody(w, err)
+		return
+	}
+
+	watcher, err := h.service.WatchOutage(r.Context(), id, user.Email, req)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, watcher)
+}
+
+// UnwatchOutage handles DELETE /api/v1/outages/{id}/watch
+func (h *Handler) UnwatchOutage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	if err := h.service.UnwatchOutage(r.Context(), id, user.Email); err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
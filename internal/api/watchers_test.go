@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+// discardWatchNotifier accepts watcher notifications without sending them
+type discardWatchNotifier struct{}
+
+func (discardWatchNotifier) NotifyWatchers(context.Context, service.WatchEvent, []string) error {
+	return nil
+}
+
+func TestWatchOutage(t *testing.T) {
+	h, router := newTestHandler()
+	h.service.RegisterWatchNotifier(domain.WatchChannelEmail, discardWatchNotifier{})
+	outage, err := h.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "Checkout down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := "/api/v1/outages/" + outage.ID.String()
+	do := func(method, url, body string, signedIn bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		if signedIn {
+			req = req.WithContext(testutil.WithUser(req.Context(), &auth.UserInfo{Email: "alice@example.com"}))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do(http.MethodPost, base+"/watch", "", true)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("watch status = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var watcher domain.Watcher
+	decodeJSON(t, rr.Body, &watcher)
+	if watcher.Email != "alice@example.com" || watcher.Channel != domain.WatchChannelEmail {
+		t.Errorf("watcher = %+v, want alice by email", watcher)
+	}
+
+	rr = do(http.MethodGet, base+"/watchers", "", false)
+	var list struct{ Watchers []domain.Watcher }
+	decodeJSON(t, rr.Body, &list)
+	if rr.Code != http.StatusOK || len(list.Watchers) != 1 {
+		t.Errorf("watchers = %d %+v, want alice", rr.Code, list.Watchers)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		signedIn bool
+		want     int
+	}{
+		{"unconfigured channel", http.MethodPost, base + "/watch", `{"channel": "slack"}`, true, http.StatusBadRequest},
+		{"invalid body", http.MethodPost, base + "/watch", `{`, true, http.StatusBadRequest},
+		{"signed out", http.MethodPost, base + "/watch", "", false, http.StatusUnauthorized},
+		{"missing outage", http.MethodPost, "/api/v1/outages/" + uuid.NewString() + "/watch", "", true, http.StatusNotFound},
+		{"invalid ID", http.MethodGet, "/api/v1/outages/nope/watchers", "", true, http.StatusBadRequest},
+		{"unwatch signed out", http.MethodDelete, base + "/watch", "", false, http.StatusUnauthorized},
+		{"unwatch", http.MethodDelete, base + "/watch", "", true, http.StatusNoContent},
+		{"unwatch again", http.MethodDelete, base + "/watch", "", true, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := do(tt.method, tt.url, tt.body, tt.signedIn); rr.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package apierr writes the JSON error bodies shared by the REST API and
+// the integration endpoints mounted beside it, and maps domain errors to
+// HTTP statuses. Every error body carries a human-readable message and a
+// stable machine-readable code:
+//
+//	{"error": "outage 3f2c... is in the trash: not found", "code": "not_found"}
+package apierr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Error codes returned in the code field of error bodies
+const (
+	CodeInvalidInput    = "invalid_input"
+	CodeUnauthorized    = "unauthorized"
+	CodeForbidden       = "forbidden"
+	CodeNotFound        = "not_found"
+	CodeConflict        = "conflict"
+	CodePayloadTooLarge = "payload_too_large"
+	CodeInternal        = "internal"
+	CodeUpstream        = "upstream"
+)
+
+// codes maps HTTP statuses to their error code
+var codes = map[int]string{
+	http.StatusBadRequest:            CodeInvalidInput,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusBadGateway:            CodeUpstream,
+}
+
+// Body is the JSON body of an error response
+type Body struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// Code returns the error code for an HTTP status. Statuses without their
+// own code fall back to internal for 5xx and invalid_input otherwise.
+func Code(status int) string {
+	if code, ok := codes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeInvalidInput
+}
+
+// Status returns the HTTP status for an error from the service layer:
+// 404 for domain.ErrNotFound, 400 for domain.ErrInvalidInput, 403 for
+// domain.ErrForbidden, 409 for domain.ErrConflict and 500 for anything else
+func Status(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Write responds with status and an error body holding message
+func Write(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Body{Error: message, Code: Code(status)})
+}
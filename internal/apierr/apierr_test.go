@@ -0,0 +1,67 @@
+package apierr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("outage x: %w", domain.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("%w: title is required", domain.ErrInvalidInput), http.StatusBadRequest},
+		{fmt.Errorf("alert PD-1 from pagerduty already exists: %w", domain.ErrConflict), http.StatusConflict},
+		{fmt.Errorf("only members of team payments can change outage x: %w", domain.ErrForbidden), http.StatusForbidden},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := Status(tt.err); got != tt.want {
+			t.Errorf("Status(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusConflict, CodeConflict},
+		{http.StatusBadGateway, CodeUpstream},
+		{http.StatusServiceUnavailable, CodeInternal},
+		{http.StatusMethodNotAllowed, CodeInvalidInput},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.status); got != tt.want {
+			t.Errorf("Code(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Write(rr, http.StatusConflict, "already linked")
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body Body
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body != (Body{Error: "already linked", Code: CodeConflict}) {
+		t.Errorf("body = %+v", body)
+	}
+}
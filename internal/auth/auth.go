@@ -4,9 +4,10 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/gob"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -14,11 +15,37 @@ import (
 	"golang.org/x/oauth2"
 )
 
-// UserInfo represents authenticated user information
+// UserInfo represents authenticated user information. Services signing in
+// with a bearer JWT or API key have no Email and are identified by Service.
 type UserInfo struct {
 	Email string `json:"email"`
 	Name  string `json:"name"`
 	Sub   string `json:"sub"`
+	// Roles and Teams are what the configured group map grants the IdP
+	// groups listed in the user's ID token. The groups themselves are not
+	// kept, as users can be in more than fit in the session cookie.
+	Roles []string `json:"roles,omitempty"`
+	Teams []string `json:"teams,omitempty"`
+	// Service identifies callers that are services rather than people,
+	// namespaced by how they authenticated: service:<issuer>:<subject> for
+	// service tokens and api-key:<name> for gRPC API keys. Services have no
+	// email, so they never match the admins list or team members.
+	Service string `json:"service,omitempty"`
+}
+
+// Identity returns the name changes by the caller are recorded under, such
+// as the author of notes: the user's email, or the service identity of a
+// service
+func (u *UserInfo) Identity() string {
+	if u.Email != "" {
+		return u.Email
+	}
+	return u.Service
+}
+
+func init() {
+	// Sessions store the signed-in user, gob encoded in the cookie
+	gob.Register(UserInfo{})
 }
 
 type contextKey struct{}
@@ -38,6 +65,20 @@ type Config struct {
 	ClientSecret string
 	RedirectURL  string
 	SessionKey   string
+	Logger       *slog.Logger // Defaults to slog.Default()
+	// Policy sets which route groups callers may use without signing in.
+	// The zero value requires sign-in for every route group.
+	Policy Policy
+	// Groups maps the user's IdP groups to roles and teams at each sign-in
+	Groups GroupMap
+	// ServiceTokens are the issuers whose bearer JWTs authenticate services
+	// without a session
+	ServiceTokens []ServiceTokenIssuer
+	// BearerIDTokens accepts ID tokens issued to this client as bearer
+	// tokens on HTTP requests, as well as service tokens. An ID token
+	// obtained by a browser session then works as an API credential until
+	// it expires.
+	BearerIDTokens bool
 }
 
 // Authenticator handles OIDC authentication
@@ -46,10 +87,21 @@ type Authenticator struct {
 	verifier     *oidc.IDTokenVerifier
 	oauth2Config oauth2.Config
 	store        *sessions.CookieStore
+	policy       Policy
+	groups       GroupMap
+	services     []*serviceVerifier
+	idTokens     bool
+	logger       *slog.Logger
 }
 
 // NewAuthenticator creates a new OIDC authenticator
 func NewAuthenticator(cfg Config) (*Authenticator, error) {
+	if err := cfg.Policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid auth policy: %w", err)
+	}
+	if err := cfg.Groups.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid group mapping: %w", err)
+	}
 	ctx := context.Background()
 
 	provider, err := oidc.NewProvider(ctx, cfg.Issuer)
@@ -67,6 +119,15 @@ func NewAuthenticator(cfg Config) (*Authenticator, error) {
 
 	verifier := provider.Verifier(&oidc.Config{ClientID: cfg.ClientID})
 
+	var services []*serviceVerifier
+	for _, issuer := range cfg.ServiceTokens {
+		service, err := newServiceVerifier(ctx, issuer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service token issuer: %w", err)
+		}
+		services = append(services, service)
+	}
+
 	// Use provided session key or generate one
 	sessionKey := cfg.SessionKey
 	if sessionKey == "" {
@@ -86,11 +147,21 @@ func NewAuthenticator(cfg Config) (*Authenticator, error) {
 		SameSite: http.SameSiteLaxMode,
 	}
 
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	return &Authenticator{
 		provider:     provider,
 		verifier:     verifier,
 		oauth2Config: oauth2Config,
 		store:        store,
+		policy:       cfg.Policy,
+		groups:       cfg.Groups,
+		services:     services,
+		idTokens:     cfg.BearerIDTokens,
+		logger:       logger,
 	}, nil
 }
 
@@ -103,7 +174,7 @@ func (a *Authenticator) LoginHandler() http.HandlerFunc {
 		if err != nil {
 			// gorilla/sessions returns a valid fresh session alongside a decode error
 			// (e.g. expired or rotated secret). Log and continue rather than returning 500.
-			log.Printf("auth: failed to decode session cookie (using fresh session): %v", err)
+			a.logger.WarnContext(r.Context(), "failed to decode session cookie, using fresh session", "error", err)
 		}
 		session.Values["state"] = state
 		if err := session.Save(r, w); err != nil {
@@ -120,7 +191,7 @@ func (a *Authenticator) CallbackHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		session, err := a.store.Get(r, sessionName)
 		if err != nil {
-			log.Printf("auth: failed to decode session cookie (using fresh session): %v", err)
+			a.logger.WarnContext(r.Context(), "failed to decode session cookie, using fresh session", "error", err)
 		}
 
 		// Verify state. If the session was freshly created above (decode error),
@@ -153,15 +224,15 @@ func (a *Authenticator) CallbackHandler() http.HandlerFunc {
 			return
 		}
 
-		// Extract user info
-		var userInfo UserInfo
-		if err := idToken.Claims(&userInfo); err != nil {
+		// Extract user info, mapping the user's groups afresh
+		userInfo, err := a.userInfo(idToken)
+		if err != nil {
 			http.Error(w, "Failed to parse claims", http.StatusInternalServerError)
 			return
 		}
 
 		// Store user info in session
-		session.Values["user"] = userInfo
+		session.Values["user"] = *userInfo
 		session.Values["id_token"] = rawIDToken
 		if err := session.Save(r, w); err != nil {
 			http.Error(w, "Failed to save session", http.StatusInternalServerError)
@@ -178,7 +249,7 @@ func (a *Authenticator) LogoutHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		session, err := a.store.Get(r, sessionName)
 		if err != nil {
-			log.Printf("auth: failed to decode session cookie (using fresh session): %v", err)
+			a.logger.WarnContext(r.Context(), "failed to decode session cookie, using fresh session", "error", err)
 		}
 		session.Options.MaxAge = -1
 		if err := session.Save(r, w); err != nil {
@@ -190,24 +261,51 @@ func (a *Authenticator) LogoutHandler() http.HandlerFunc {
 	}
 }
 
-// Middleware enforces authentication on routes
+// Middleware enforces authentication on routes. Signed-in callers, and
+// callers sending a service token or, with Config.BearerIDTokens, an ID
+// token as a bearer token, are added to the context; a bearer token that
+// fails verification is refused with 401.
+// Callers with neither are let through, marked anonymous, when the policy
+// allows it for the request's route group, and refused with 401 otherwise.
+// Reads are GET, HEAD and OPTIONS requests, except that any GraphQL
+// request is let through to a group allowing anonymous reads, for the
+// GraphQL handler to refuse mutations.
 func (a *Authenticator) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip auth for login/callback/health endpoints
-		if r.URL.Path == "/auth/login" || r.URL.Path == "/auth/callback" || r.URL.Path == "/health" {
+		// Skip auth for login/callback/health/readiness endpoints
+		switch r.URL.Path {
+		case "/auth/login", "/auth/callback", "/health", "/healthz", "/readyz":
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		session, err := a.store.Get(r, sessionName)
-		if err != nil {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		if rawToken, ok := bearerToken(r); ok {
+			verify := a.VerifyServiceToken
+			if a.idTokens {
+				verify = a.VerifyBearerToken
+			}
+			user, err := verify(r.Context(), rawToken)
+			if err != nil {
+				a.logger.InfoContext(r.Context(), "rejected bearer token", "error", err)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), UserContextKey, user)))
 			return
 		}
 
-		userInfoRaw, ok := session.Values["user"]
-		if !ok {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		var userInfoRaw any
+		session, err := a.store.Get(r, sessionName)
+		if err == nil {
+			userInfoRaw = session.Values["user"]
+		}
+		if userInfoRaw == nil {
+			group := RouteGroup(r.URL.Path)
+			if !a.policy.AllowsAnonymous(group, readMethod(r.Method) || group == GroupGraphQL) {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithAnonymous(r.Context(), a.policy.Access(group))))
 			return
 		}
 
@@ -229,6 +327,43 @@ func (a *Authenticator) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// VerifyIDToken verifies an ID token issued by the OIDC provider for this
+// client, such as one sent as a bearer token by an API client, and returns
+// the user it identifies, with the roles and teams their groups map to
+func (a *Authenticator) VerifyIDToken(ctx context.Context, rawIDToken string) (*UserInfo, error) {
+	idToken, err := a.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+	userInfo, err := a.userInfo(idToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ID token claims: %w", err)
+	}
+	return userInfo, nil
+}
+
+// userInfo returns the user an ID token identifies, with the roles and
+// teams their groups map to
+func (a *Authenticator) userInfo(idToken *oidc.IDToken) (*UserInfo, error) {
+	// The claims are decoded loosely, as IdPs differ in the type of the
+	// groups claim
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	userInfo := &UserInfo{Sub: idToken.Subject}
+	userInfo.Email, _ = claims["email"].(string)
+	userInfo.Name, _ = claims["name"].(string)
+	a.groups.apply(userInfo, claims)
+	return userInfo, nil
+}
+
+// WithUser returns a context acting as the user, for callers such as chat
+// integrations that identify users without an HTTP session
+func WithUser(ctx context.Context, user *UserInfo) context.Context {
+	return context.WithValue(ctx, UserContextKey, user)
+}
+
 // GetUserFromContext extracts user info from request context
 func GetUserFromContext(ctx context.Context) (*UserInfo, error) {
 	user, ok := ctx.Value(UserContextKey).(*UserInfo)
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RoleAdmin is the role of users who may change any outage and work with
+// the trash, as the emails in the admins list may
+const RoleAdmin = "admin"
+
+// Roles lists the roles IdP groups can map to
+var Roles = []string{RoleAdmin}
+
+// DefaultGroupsClaim is the ID token claim read for the user's groups when
+// GroupMap.Claim is empty
+const DefaultGroupsClaim = "groups"
+
+// GroupMapping sets the outalator roles and teams the members of an IdP
+// group get
+type GroupMapping struct {
+	Roles []string
+	Teams []string
+}
+
+// GroupMap maps the groups or roles an IdP lists in an ID token claim to
+// outalator roles and teams. Users get them afresh each time they sign in,
+// so access follows group membership in the IdP.
+type GroupMap struct {
+	// Claim names the ID token claim listing the user's groups, either an
+	// array of strings or a single string. A dotted path such as
+	// realm_access.roles reads a claim nested in an object. Defaults to
+	// DefaultGroupsClaim.
+	Claim string
+	// Groups maps IdP group names, matched exactly, to what their members get
+	Groups map[string]GroupMapping
+}
+
+// Validate checks every mapping grants known roles and names its teams
+func (m GroupMap) Validate() error {
+	for group, mapping := range m.Groups {
+		for _, role := range mapping.Roles {
+			if !knownRole(role) {
+				return fmt.Errorf("group %q: unknown role %q, must be one of %s", group, role, strings.Join(Roles, ", "))
+			}
+		}
+		for _, team := range mapping.Teams {
+			if strings.TrimSpace(team) == "" {
+				return fmt.Errorf("group %q: team name must not be empty", group)
+			}
+		}
+	}
+	return nil
+}
+
+// apply sets the roles and teams the groups in the ID token claims map to,
+// replacing any the user had before
+func (m GroupMap) apply(user *UserInfo, claims map[string]any) {
+	claim := m.Claim
+	if claim == "" {
+		claim = DefaultGroupsClaim
+	}
+	user.Roles = nil
+	user.Teams = nil
+	for _, group := range claimStrings(claims, claim) {
+		mapping, ok := m.Groups[group]
+		if !ok {
+			continue
+		}
+		user.Roles = appendNew(user.Roles, mapping.Roles...)
+		user.Teams = appendNew(user.Teams, mapping.Teams...)
+	}
+}
+
+// claimStrings returns the strings of the claim at a dotted path, or nil
+// when the claim is missing or not a string or array of strings
+func claimStrings(claims map[string]any, path string) []string {
+	var value any = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	switch value := value.(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []any:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// appendNew appends the values not already in list
+func appendNew(list []string, values ...string) []string {
+	for _, value := range values {
+		if !containsFold(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func knownRole(role string) bool {
+	return containsFold(Roles, role)
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the user's IdP groups grant the role
+func (u *UserInfo) HasRole(role string) bool {
+	return containsFold(u.Roles, role)
+}
+
+// InTeam reports whether the user's IdP groups make them a member of the
+// team. Team names are matched case-insensitively.
+func (u *UserInfo) InTeam(team string) bool {
+	return team != "" && containsFold(u.Teams, team)
+}
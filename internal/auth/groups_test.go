@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gorilla/sessions"
+)
+
+func TestGroupMapApply(t *testing.T) {
+	m := GroupMap{Groups: map[string]GroupMapping{
+		"sre-leads":    {Roles: []string{RoleAdmin}, Teams: []string{"sre"}},
+		"sre":          {Teams: []string{"SRE"}},
+		"payments-dev": {Teams: []string{"payments"}},
+	}}
+	user := &UserInfo{Email: "lead@example.com", Roles: []string{RoleAdmin}, Teams: []string{"stale"}}
+	m.apply(user, map[string]any{"groups": []any{"sre-leads", "sre", "everyone"}})
+
+	if want := []string{RoleAdmin}; !slices.Equal(user.Roles, want) {
+		t.Errorf("Roles = %v, want %v", user.Roles, want)
+	}
+	if want := []string{"sre"}; !slices.Equal(user.Teams, want) {
+		t.Errorf("Teams = %v, want %v", user.Teams, want)
+	}
+	if !user.HasRole("Admin") || !user.InTeam("SRE") || user.InTeam("payments") {
+		t.Errorf("HasRole/InTeam disagree with roles %v and teams %v", user.Roles, user.Teams)
+	}
+
+	// Signing in again without the groups drops what they granted
+	m.apply(user, map[string]any{"groups": []any{"everyone"}})
+	if user.HasRole(RoleAdmin) || len(user.Teams) != 0 {
+		t.Errorf("after losing groups: roles %v, teams %v, want none", user.Roles, user.Teams)
+	}
+}
+
+func TestClaimStrings(t *testing.T) {
+	claims := map[string]any{
+		"groups":       "sre",
+		"realm_access": map[string]any{"roles": []any{"sre-leads", 7, ""}},
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"groups", []string{"sre"}},
+		{"realm_access.roles", []string{"sre-leads"}},
+		{"realm_access.missing", nil},
+		{"groups.nested", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := claimStrings(claims, tt.path); !slices.Equal(got, tt.want) {
+			t.Errorf("claimStrings(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	m := GroupMap{Claim: "realm_access.roles", Groups: map[string]GroupMapping{"sre-leads": {Roles: []string{RoleAdmin}}}}
+	var user UserInfo
+	m.apply(&user, claims)
+	if !user.HasRole(RoleAdmin) {
+		t.Errorf("Roles = %v from a nested claim, want admin", user.Roles)
+	}
+}
+
+func TestGroupMapValidate(t *testing.T) {
+	if err := (GroupMap{Groups: map[string]GroupMapping{"sre-leads": {Roles: []string{RoleAdmin}, Teams: []string{"sre"}}}}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := (GroupMap{Groups: map[string]GroupMapping{"sre-leads": {Roles: []string{"owner"}}}}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown role")
+	}
+	if err := (GroupMap{Groups: map[string]GroupMapping{"sre": {Teams: []string{" "}}}}).Validate(); err == nil {
+		t.Error("Validate() accepted an empty team name")
+	}
+}
+
+func TestGroupMapApplyFitsSession(t *testing.T) {
+	// Users of large IdPs can be in hundreds of groups, more than fit in
+	// the session cookie, so only what they map to is stored
+	groups := make([]any, 500)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("department-%03d-all-staff", i)
+	}
+	m := GroupMap{Groups: map[string]GroupMapping{"department-042-all-staff": {Teams: []string{"payments"}}}}
+	user := UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	m.apply(&user, map[string]any{"groups": groups})
+
+	store := sessions.NewCookieStore([]byte("test-session-key"))
+	req := httptest.NewRequest("GET", "/auth/callback", nil)
+	session, _ := store.New(req, sessionName)
+	session.Values["user"] = user
+	if err := session.Save(req, httptest.NewRecorder()); err != nil {
+		t.Errorf("saving the session of a user in %d groups: %v", len(groups), err)
+	}
+	if !user.InTeam("payments") {
+		t.Errorf("Teams = %v, want payments", user.Teams)
+	}
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Access levels a Policy gives a route group
+const (
+	// AccessRequired requires a signed-in caller for every request
+	AccessRequired = "required"
+	// AccessAnonymousRead lets callers who have not signed in read, while
+	// changes still need a signed-in caller
+	AccessAnonymousRead = "anonymous_read"
+	// AccessOpen needs no sign-in at all
+	AccessOpen = "open"
+)
+
+// Route groups a Policy sets access for
+const (
+	GroupAPI     = "api"     // REST API, including integration actions
+	GroupEvents  = "events"  // Server-sent event stream
+	GroupGraphQL = "graphql" // GraphQL endpoint; queries read and mutations change
+	GroupGRPC    = "grpc"    // gRPC port and its HTTP gateway
+	GroupUI      = "ui"      // Web UI
+)
+
+// Groups lists the route groups in the order they are documented
+var Groups = []string{GroupAPI, GroupEvents, GroupGraphQL, GroupGRPC, GroupUI}
+
+// accessLevels lists the valid access levels
+var accessLevels = []string{AccessRequired, AccessAnonymousRead, AccessOpen}
+
+// Route group paths. The gRPC gateway prefix matches grpc.GatewayPrefix.
+const (
+	eventsPath        = "/api/v1/events/stream"
+	graphQLPath       = "/graphql"
+	gatewayPathPrefix = "/v1/"
+	staticPathPrefix  = "/static/"
+)
+
+// Policy sets which route groups need a signed-in caller. The zero value
+// requires sign-in everywhere, as outalator did before route groups could
+// be configured.
+type Policy struct {
+	// Default is the access of groups not in Groups. Empty means
+	// AccessRequired.
+	Default string
+	// Groups sets the access of individual route groups
+	Groups map[string]string
+}
+
+// Validate reports unknown route groups and access levels
+func (p Policy) Validate() error {
+	if p.Default != "" && !slices.Contains(accessLevels, p.Default) {
+		return fmt.Errorf("unknown default access %q (want one of %s)", p.Default, strings.Join(accessLevels, ", "))
+	}
+	for group, access := range p.Groups {
+		if !slices.Contains(Groups, group) {
+			return fmt.Errorf("unknown route group %q (want one of %s)", group, strings.Join(Groups, ", "))
+		}
+		if !slices.Contains(accessLevels, access) {
+			return fmt.Errorf("unknown access %q for route group %s (want one of %s)", access, group, strings.Join(accessLevels, ", "))
+		}
+	}
+	return nil
+}
+
+// Access returns the access level of a route group
+func (p Policy) Access(group string) string {
+	if access, ok := p.Groups[group]; ok {
+		return access
+	}
+	if p.Default != "" {
+		return p.Default
+	}
+	return AccessRequired
+}
+
+// AllowsAnonymous reports whether a caller who has not signed in may make a
+// request to group, given whether the request only reads
+func (p Policy) AllowsAnonymous(group string, read bool) bool {
+	switch p.Access(group) {
+	case AccessOpen:
+		return true
+	case AccessAnonymousRead:
+		return read
+	}
+	return false
+}
+
+// RouteGroup returns the route group of an HTTP request path. Paths outside
+// the other groups belong to the REST API.
+func RouteGroup(path string) string {
+	switch {
+	case path == eventsPath:
+		return GroupEvents
+	case path == graphQLPath:
+		return GroupGraphQL
+	case strings.HasPrefix(path, gatewayPathPrefix):
+		return GroupGRPC
+	case path == "/" || strings.HasPrefix(path, staticPathPrefix):
+		return GroupUI
+	}
+	return GroupAPI
+}
+
+// readMethod reports whether an HTTP method only reads
+func readMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// anonymousKey is the context key marking callers admitted without signing in
+type anonymousKey struct{}
+
+// WithAnonymous marks ctx as belonging to a caller admitted without signing
+// in, with the access level that admitted them
+func WithAnonymous(ctx context.Context, access string) context.Context {
+	return context.WithValue(ctx, anonymousKey{}, access)
+}
+
+// IsAnonymous reports whether the caller was admitted by a Policy without
+// signing in. Such callers are not admins, unlike callers of a server with
+// authentication disabled, who have no user either.
+func IsAnonymous(ctx context.Context) bool {
+	_, ok := ctx.Value(anonymousKey{}).(string)
+	return ok
+}
+
+// AnonymousAccess returns the access level that admitted an anonymous
+// caller, and whether the caller is anonymous
+func AnonymousAccess(ctx context.Context) (string, bool) {
+	access, ok := ctx.Value(anonymousKey{}).(string)
+	return access, ok
+}
+
+// ReadOnly reports whether the caller was admitted without signing in to a
+// group that only allows them to read. GraphQL requests are admitted this
+// way whatever their method, and mutations are refused by the handler.
+func ReadOnly(ctx context.Context) bool {
+	access, _ := ctx.Value(anonymousKey{}).(string)
+	return access == AccessAnonymousRead
+}
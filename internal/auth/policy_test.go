@@ -0,0 +1,56 @@
+package auth
+
+import "testing"
+
+func TestPolicyAccess(t *testing.T) {
+	policy := Policy{Default: AccessAnonymousRead, Groups: map[string]string{GroupEvents: AccessRequired, GroupUI: AccessOpen}}
+	tests := []struct {
+		group string
+		read  bool
+		want  bool
+	}{
+		{GroupAPI, true, true},
+		{GroupAPI, false, false},
+		{GroupEvents, true, false},
+		{GroupUI, false, true},
+	}
+	for _, tt := range tests {
+		if got := policy.AllowsAnonymous(tt.group, tt.read); got != tt.want {
+			t.Errorf("AllowsAnonymous(%s, read %t) = %t, want %t", tt.group, tt.read, got, tt.want)
+		}
+	}
+	if (Policy{}).AllowsAnonymous(GroupAPI, true) {
+		t.Error("zero Policy allows anonymous reads, want sign-in required")
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	valid := Policy{Default: AccessOpen, Groups: map[string]string{GroupGRPC: AccessAnonymousRead}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	for name, policy := range map[string]Policy{
+		"unknown default": {Default: "sometimes"},
+		"unknown group":   {Groups: map[string]string{"admin": AccessOpen}},
+		"unknown access":  {Groups: map[string]string{GroupAPI: "public"}},
+	} {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("Validate() with %s = nil, want an error", name)
+		}
+	}
+}
+
+func TestRouteGroup(t *testing.T) {
+	for path, want := range map[string]string{
+		"/api/v1/outages":       GroupAPI,
+		"/api/v1/events/stream": GroupEvents,
+		"/graphql":              GroupGraphQL,
+		"/v1/outages":           GroupGRPC,
+		"/":                     GroupUI,
+		"/static/app.js":        GroupUI,
+	} {
+		if got := RouteGroup(path); got != want {
+			t.Errorf("RouteGroup(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
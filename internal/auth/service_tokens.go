@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// ServiceTokenIssuer configures an issuer whose bearer JWTs authenticate
+// services, such as CI jobs, that can mint OIDC tokens but cannot sign in
+// through the browser
+type ServiceTokenIssuer struct {
+	Name     string // Identifies the issuer in logs, defaults to Issuer
+	Issuer   string
+	Audience string // Tokens must list it in their aud claim
+	// JWKSURL serves the keys tokens are signed with. When empty the keys
+	// are found through the issuer's OIDC discovery document.
+	JWKSURL string
+}
+
+// serviceSigningAlgs are the algorithms tokens may be signed with when an
+// issuer's keys are configured directly rather than discovered
+var serviceSigningAlgs = []string{
+	oidc.RS256, oidc.RS384, oidc.RS512,
+	oidc.ES256, oidc.ES384, oidc.ES512,
+	oidc.PS256, oidc.PS384, oidc.PS512,
+	oidc.EdDSA,
+}
+
+// serviceVerifier verifies the tokens of one service token issuer
+type serviceVerifier struct {
+	name     string
+	verifier *oidc.IDTokenVerifier
+}
+
+// newServiceVerifier returns a verifier for the issuer's tokens, fetching
+// its discovery document when no JWKS URL is set
+func newServiceVerifier(ctx context.Context, cfg ServiceTokenIssuer) (*serviceVerifier, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("issuer is required")
+	}
+	if cfg.Audience == "" {
+		return nil, fmt.Errorf("issuer %s: audience is required", cfg.Issuer)
+	}
+	name := cfg.Name
+	if name == "" {
+		name = cfg.Issuer
+	}
+
+	config := &oidc.Config{ClientID: cfg.Audience}
+	if cfg.JWKSURL != "" {
+		config.SupportedSigningAlgs = serviceSigningAlgs
+		keys := oidc.NewRemoteKeySet(ctx, cfg.JWKSURL)
+		return &serviceVerifier{name: name, verifier: oidc.NewVerifier(cfg.Issuer, keys, config)}, nil
+	}
+	provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("issuer %s: %w", cfg.Issuer, err)
+	}
+	return &serviceVerifier{name: name, verifier: provider.Verifier(config)}, nil
+}
+
+// VerifyServiceToken verifies a JWT from a configured service token issuer.
+// The caller is identified as the service service:<issuer>:<subject>, with
+// no email, so the token's subject cannot pass for a user's email address.
+func (a *Authenticator) VerifyServiceToken(ctx context.Context, rawToken string) (*UserInfo, error) {
+	if len(a.services) == 0 {
+		return nil, errors.New("no service token issuers configured")
+	}
+	var errs []error
+	for _, s := range a.services {
+		token, err := s.verifier.Verify(ctx, rawToken)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		return &UserInfo{Name: s.name, Sub: token.Subject, Service: "service:" + s.name + ":" + token.Subject}, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// VerifyBearerToken verifies a bearer token sent by an API client or
+// service instead of a session: a service token, as VerifyServiceToken
+// does, or an ID token issued to this client. The gRPC server accepts ID
+// tokens this way when grpc.auth.oidc is set, and HTTP requests when
+// Config.BearerIDTokens is.
+func (a *Authenticator) VerifyBearerToken(ctx context.Context, rawToken string) (*UserInfo, error) {
+	var errs []error
+	if len(a.services) > 0 {
+		user, err := a.VerifyServiceToken(ctx, rawToken)
+		if err == nil {
+			return user, nil
+		}
+		errs = append(errs, err)
+	}
+	user, err := a.VerifyIDToken(ctx, rawToken)
+	if err != nil {
+		return nil, errors.Join(append(errs, err)...)
+	}
+	return user, nil
+}
+
+// bearerToken returns the token of a request's "Authorization: Bearer"
+// header, if it has one
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", false
+	}
+	scheme, token, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gorilla/sessions"
+)
+
+const (
+	testServiceIssuer = "https://ci.example.com"
+	testSessionIssuer = "https://idp.example.com"
+)
+
+// testIssuer signs RS256 JWTs and serves its public key as a JWKS
+type testIssuer struct {
+	key     *rsa.PrivateKey
+	jwksURL string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "test",
+		"alg": oidc.RS256,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+	return &testIssuer{key: key, jwksURL: server.URL}
+}
+
+// sign returns a JWT with the claims
+func (i *testIssuer) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": oidc.RS256, "kid": "test", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// newTestAuthenticator returns an authenticator accepting issuer's tokens
+// as service tokens for the audience "outalator" and as ID tokens of the
+// session issuer for the client "outalator-web"
+func newTestAuthenticator(t *testing.T, issuer *testIssuer) *Authenticator {
+	t.Helper()
+	ctx := context.Background()
+	service, err := newServiceVerifier(ctx, ServiceTokenIssuer{Name: "ci", Issuer: testServiceIssuer, Audience: "outalator", JWKSURL: issuer.jwksURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Authenticator{
+		verifier: oidc.NewVerifier(testSessionIssuer, oidc.NewRemoteKeySet(ctx, issuer.jwksURL), &oidc.Config{ClientID: "outalator-web"}),
+		store:    sessions.NewCookieStore([]byte("test-session-key")),
+		services: []*serviceVerifier{service},
+		logger:   slog.New(slog.DiscardHandler),
+	}
+}
+
+func TestVerifyBearerToken(t *testing.T) {
+	issuer := newTestIssuer(t)
+	a := newTestAuthenticator(t, issuer)
+	expiry := time.Now().Add(time.Hour).Unix()
+
+	user, err := a.VerifyBearerToken(context.Background(), issuer.sign(t, map[string]any{
+		"iss": testServiceIssuer, "aud": "outalator", "sub": "repo:acme/shop:ref:refs/heads/main", "exp": expiry,
+	}))
+	if err != nil {
+		t.Fatalf("VerifyBearerToken(service token) error = %v", err)
+	}
+	if user.Service != "service:ci:repo:acme/shop:ref:refs/heads/main" || user.Identity() != user.Service || user.Name != "ci" {
+		t.Errorf("service user = %+v, want the namespaced subject as identity", user)
+	}
+
+	// A subject that looks like an email address is not taken as one
+	user, err = a.VerifyServiceToken(context.Background(), issuer.sign(t, map[string]any{
+		"iss": testServiceIssuer, "aud": "outalator", "sub": "admin@example.com", "exp": expiry,
+	}))
+	if err != nil || user.Email != "" || user.Identity() != "service:ci:admin@example.com" {
+		t.Errorf("VerifyServiceToken(email subject) = %+v, %v, want a service without an email", user, err)
+	}
+
+	user, err = a.VerifyBearerToken(context.Background(), issuer.sign(t, map[string]any{
+		"iss": testSessionIssuer, "aud": "outalator-web", "sub": "sub-alice", "email": "alice@example.com", "exp": expiry,
+	}))
+	if err != nil {
+		t.Fatalf("VerifyBearerToken(ID token) error = %v", err)
+	}
+	if user.Email != "alice@example.com" || user.Service != "" {
+		t.Errorf("ID token user = %+v, want alice", user)
+	}
+	if _, err := a.VerifyServiceToken(context.Background(), issuer.sign(t, map[string]any{
+		"iss": testSessionIssuer, "aud": "outalator-web", "sub": "sub-alice", "email": "alice@example.com", "exp": expiry,
+	})); err == nil {
+		t.Error("VerifyServiceToken() accepted an ID token")
+	}
+
+	other := newTestIssuer(t)
+	rejected := map[string]string{
+		"wrong audience": issuer.sign(t, map[string]any{"iss": testServiceIssuer, "aud": "elsewhere", "sub": "ci", "exp": expiry}),
+		"unknown issuer": issuer.sign(t, map[string]any{"iss": "https://other.example.com", "aud": "outalator", "sub": "ci", "exp": expiry}),
+		"expired":        issuer.sign(t, map[string]any{"iss": testServiceIssuer, "aud": "outalator", "sub": "ci", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"wrong key":      other.sign(t, map[string]any{"iss": testServiceIssuer, "aud": "outalator", "sub": "ci", "exp": expiry}),
+		"not a JWT":      "not-a-jwt",
+	}
+	for name, token := range rejected {
+		if user, err := a.VerifyBearerToken(context.Background(), token); err == nil {
+			t.Errorf("%s: VerifyBearerToken() = %+v, want error", name, user)
+		}
+	}
+}
+
+func TestMiddlewareBearerToken(t *testing.T) {
+	issuer := newTestIssuer(t)
+	a := newTestAuthenticator(t, issuer)
+	var got *UserInfo
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = GetUserFromContext(r.Context())
+	}))
+	expiry := time.Now().Add(time.Hour).Unix()
+	token := issuer.sign(t, map[string]any{
+		"iss": testServiceIssuer, "aud": "outalator", "sub": "deploy-bot", "exp": expiry,
+	})
+	idToken := issuer.sign(t, map[string]any{
+		"iss": testSessionIssuer, "aud": "outalator-web", "sub": "sub-alice", "email": "alice@example.com", "exp": expiry,
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"service token", "Bearer " + token, http.StatusOK},
+		{"ID token without bearer ID tokens", "Bearer " + idToken, http.StatusUnauthorized},
+		{"invalid token", "Bearer " + token + "x", http.StatusUnauthorized},
+		{"empty token", "Bearer ", http.StatusUnauthorized},
+		{"no credentials", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		got = nil
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/outages", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rr.Code, tt.want)
+		}
+		if tt.want == http.StatusOK && (got == nil || got.Identity() != "service:ci:deploy-bot") {
+			t.Errorf("%s: user = %+v, want deploy-bot", tt.name, got)
+		}
+	}
+
+	a.idTokens = true
+	got = nil
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/outages", nil)
+	req.Header.Set("Authorization", "Bearer "+idToken)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || got == nil || got.Email != "alice@example.com" {
+		t.Errorf("ID token with bearer ID tokens: status = %d, user = %+v, want alice", rr.Code, got)
+	}
+}
+
+func TestNewServiceVerifierRequiresAudience(t *testing.T) {
+	if _, err := newServiceVerifier(context.Background(), ServiceTokenIssuer{Issuer: testServiceIssuer, JWKSURL: "https://ci.example.com/keys"}); err == nil {
+		t.Error("newServiceVerifier() accepted an issuer without an audience")
+	}
+}
@@ -0,0 +1,105 @@
+// Package blobstore holds attachment content. Local keeps files on disk and
+// S3 talks to any S3-compatible object store (AWS S3, MinIO, Ceph, R2).
+// Keys are slash-separated paths chosen by the caller; both stores return
+// domain.ErrNotFound for keys they do not hold.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Local stores blobs as files under a directory
+type Local struct {
+	dir string
+}
+
+// NewLocal creates a store rooted at dir, creating the directory if needed
+func NewLocal(dir string) (*Local, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("blobstore: directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("blobstore: failed to create %s: %w", dir, err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+// Put writes content under key. The file is written to a temporary name
+// and renamed into place so readers never see a partial blob.
+func (l *Local) Put(_ context.Context, key string, content io.Reader, _ int64, _ string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("blobstore: failed to create directory for %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("blobstore: failed to create %s: %w", key, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("blobstore: failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("blobstore: failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("blobstore: failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get opens the blob stored under key
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("blob %s: %w", key, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to open %s: %w", key, err)
+	}
+	return f, nil
+}
+
+// Delete removes the blob stored under key
+func (l *Local) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("blob %s: %w", key, domain.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("blobstore: failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// path maps a key to a file under the store's directory, rejecting keys
+// that would escape it
+func (l *Local) path(key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("blobstore: invalid key %q", key)
+	}
+	return filepath.Join(l.dir, rel), nil
+}
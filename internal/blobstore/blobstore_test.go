@@ -0,0 +1,55 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+
+	const key = "outages/1/graph.png"
+	if err := store.Put(ctx, key, strings.NewReader("png bytes"), 9, "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil || string(got) != "png bytes" {
+		t.Errorf("Get = %q, %v; want %q", got, err, "png bytes")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Get after delete: got %v, want domain.ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("second Delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+	for _, key := range []string{"../secret", "/etc/passwd", "a/../../b", ""} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) succeeded, want an error", key)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// unsignedPayload is sent as the payload hash so uploads can be streamed
+// without hashing them first
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config holds the settings for an S3-compatible object store
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000
+	Region          string // Signing region, default us-east-1
+	Bucket          string
+	Prefix          string // Prepended to every key, e.g. outalator/
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses objects as endpoint/bucket/key rather than
+	// bucket.endpoint/key. Most self-hosted stores such as MinIO need it.
+	PathStyle bool
+	Transport http.RoundTripper
+}
+
+// S3 stores blobs as objects in an S3-compatible bucket, signing requests
+// with AWS Signature Version 4
+type S3 struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3 creates a store for the configured bucket
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("blobstore: s3 endpoint and bucket are required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("blobstore: s3 access key ID and secret access key are required")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("blobstore: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &S3{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client: &http.Client{
+			Timeout:   5 * time.Minute,
+			Transport: cfg.Transport,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Put uploads content under key
+func (s *S3) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("blobstore: failed to upload %s: %w", key, err)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// Get downloads the object stored under key
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to download %s: %w", key, err)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the object stored under key. S3 does not report missing
+// keys on delete, so deleting an absent blob succeeds.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("blobstore: failed to delete %s: %w", key, err)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// newRequest builds a request for the object stored under key
+func (s *S3) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	objectPath := uriEncode(s.cfg.Prefix+key, false)
+	if s.cfg.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + s.cfg.Prefix + key
+		u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + "/" + uriEncode(s.cfg.Bucket, false) + "/" + objectPath
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = "/" + s.cfg.Prefix + key
+		u.RawPath = "/" + objectPath
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to create request: %w", err)
+	}
+	return req, nil
+}
+
+// do signs and sends req, returning domain.ErrNotFound for 404 responses
+// and an error holding the body of any other non-2xx response
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	signV4(req, unsignedPayload, s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.cfg.Region, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// signV4 adds AWS Signature Version 4 headers to req, signing the host and
+// every header already set on it
+func signV4(req *http.Request, payloadHash, accessKeyID, secretAccessKey, region string, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	request, signedHeaders := canonicalRequest(req, payloadHash)
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(request),
+	}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(signingKey(secretAccessKey, date, region, "s3"), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalRequest returns the Signature Version 4 canonical form of req
+// and the list of headers it signs
+func canonicalRequest(req *http.Request, payloadHash string) (request, signedHeaders string) {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders = strings.Join(names, ";")
+
+	request = strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	return request, signedHeaders
+}
+
+// signingKey derives the Signature Version 4 key for a day, region and
+// service
+func signingKey(secretAccessKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// canonicalQuery encodes query parameters sorted by name and value
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and
+// slashes unless encodeSlash is set, as Signature Version 4 requires
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+// Package bodylimit caps HTTP request body sizes. Webhook deliveries,
+// attachment uploads and outage imports get their own limits; every other
+// route shares a default. Requests over the limit are rejected with 413.
+package bodylimit
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/conall/outalator/internal/apierr"
+)
+
+// Default limits, used when a Limits field is zero
+const (
+	DefaultMaxBytes           = 1 << 20   // 1 MiB
+	DefaultWebhookMaxBytes    = 1 << 20   // 1 MiB
+	DefaultAttachmentMaxBytes = 25 << 20  // 25 MiB
+	DefaultImportMaxBytes     = 100 << 20 // 100 MiB
+)
+
+// webhookPathPrefix identifies inbound webhook routes
+const webhookPathPrefix = "/api/v1/webhooks/"
+
+// importPath is the outage import route
+const importPath = "/api/v1/outages/import"
+
+// Limits holds the maximum request body size in bytes for each kind of route
+type Limits struct {
+	Default    int64 // Any route not covered below
+	Webhook    int64 // /api/v1/webhooks/{source}
+	Attachment int64 // Routes with an /attachments path segment
+	Import     int64 // /api/v1/outages/import
+}
+
+// withDefaults fills unset limits
+func (l Limits) withDefaults() Limits {
+	if l.Default <= 0 {
+		l.Default = DefaultMaxBytes
+	}
+	if l.Webhook <= 0 {
+		l.Webhook = DefaultWebhookMaxBytes
+	}
+	if l.Attachment <= 0 {
+		l.Attachment = DefaultAttachmentMaxBytes
+	}
+	if l.Import <= 0 {
+		l.Import = DefaultImportMaxBytes
+	}
+	return l
+}
+
+// For returns the body size limit for a request path
+func (l Limits) For(path string) int64 {
+	l = l.withDefaults()
+	switch {
+	case strings.HasPrefix(path, webhookPathPrefix):
+		return l.Webhook
+	case strings.Contains(path+"/", "/attachments/"):
+		return l.Attachment
+	case path == importPath:
+		return l.Import
+	default:
+		return l.Default
+	}
+}
+
+// Middleware enforces limits. Requests declaring a larger Content-Length are
+// rejected before the handler runs; other bodies are wrapped with
+// http.MaxBytesReader, so handlers must report read errors for which
+// TooLarge is true as 413.
+func Middleware(limits Limits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limits.For(r.URL.Path)
+			if r.ContentLength > limit {
+				RespondTooLarge(w)
+				return
+			}
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TooLarge reports whether err came from reading past a body size limit
+func TooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
+// RespondTooLarge writes the standard 413 response
+func RespondTooLarge(w http.ResponseWriter) {
+	apierr.Write(w, http.StatusRequestEntityTooLarge, "Request body too large")
+}
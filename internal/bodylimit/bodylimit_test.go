@@ -0,0 +1,68 @@
+package bodylimit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitsFor(t *testing.T) {
+	limits := Limits{Default: 10, Attachment: 1000}
+	tests := []struct {
+		path string
+		want int64
+	}{
+		{"/api/v1/outages", 10},
+		{"/api/v1/webhooks/pagerduty", DefaultWebhookMaxBytes},
+		{"/api/v1/outages/123/attachments", 1000},
+		{"/api/v1/outages/123/attachments/456", 1000},
+		{"/api/v1/outages/123/attachmentsx", 10},
+		{"/api/v1/outages/import", DefaultImportMaxBytes},
+	}
+	for _, tt := range tests {
+		if got := limits.For(tt.path); got != tt.want {
+			t.Errorf("For(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	// The handler mirrors how API handlers report read errors
+	handler := Middleware(Limits{Default: 8})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			if TooLarge(err) {
+				RespondTooLarge(w)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name     string
+		body     string
+		chunked  bool
+		wantCode int
+	}{
+		{"within limit", "12345678", false, http.StatusNoContent},
+		{"declared too large", "123456789", false, http.StatusRequestEntityTooLarge},
+		{"streamed too large", "123456789", true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/outages", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+// Package cache puts a read-through cache in front of the outage reads that
+// the MCP server and dashboards repeat most: GetOutage, ListOutages and
+// ListOutagesByTeams. Entries live in a Backend, either an in-process LRU or
+// Redis shared by every replica, and are invalidated as writes pass through
+// the wrapped storage.
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage"
+	"github.com/google/uuid"
+)
+
+// DefaultTTL is how long entries are served when no TTL is configured. It
+// bounds how stale a read can be when a write on another replica is not
+// seen, as with the memory backend.
+const DefaultTTL = 30 * time.Second
+
+// Backend stores cache entries. Get reports whether key was found; a zero
+// ttl keeps the entry until it is deleted or evicted.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Close() error
+}
+
+// Keys holding the generation tokens that cached entries are stored under.
+// Replacing a token invalidates every entry stored under the old one.
+const (
+	epochKey   = "outalator:gen:epoch" // Every entry; replaced when outages or notes are purged
+	listGenKey = "outalator:gen:lists" // Outage lists; replaced on every outage write
+)
+
+// cachedStorage serves outage reads from a Backend and invalidates entries
+// on writes. Methods it does not override go straight to the embedded
+// storage.
+type cachedStorage struct {
+	storage.Storage
+	backend Backend
+	ttl     time.Duration
+	logger  *slog.Logger
+}
+
+// WrapStorage returns s with GetOutage, ListOutages and ListOutagesByTeams
+// cached in backend for ttl, or DefaultTTL when ttl is zero. Backend
+// failures are logged and the read falls through to s, so an unreachable
+// cache slows requests down rather than failing them.
+func WrapStorage(s storage.Storage, backend Backend, ttl time.Duration, logger *slog.Logger) storage.Storage {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &cachedStorage{Storage: s, backend: backend, ttl: ttl, logger: logger}
+}
+
+// GetOutage returns the cached outage, loading and caching it on a miss.
+// Not-found results are not cached.
+func (c *cachedStorage) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
+	epoch := c.token(ctx, epochKey)
+	if epoch == "" {
+		return c.Storage.GetOutage(ctx, id)
+	}
+	key := "outalator:outage:" + epoch + ":" + id.String()
+	var outage *domain.Outage
+	if c.load(ctx, key, &outage) {
+		return outage, nil
+	}
+	outage, err := c.Storage.GetOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, key, outage)
+	return outage, nil
+}
+
+// ListOutages returns the cached page, loading and caching it on a miss
+func (c *cachedStorage) ListOutages(ctx context.Context, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	return c.list(ctx, "", limit, offset, includeDeleted, func() ([]*domain.Outage, error) {
+		return c.Storage.ListOutages(ctx, limit, offset, includeDeleted)
+	})
+}
+
+// ListOutagesByTeams returns the cached page, loading and caching it on a
+// miss
+func (c *cachedStorage) ListOutagesByTeams(ctx context.Context, teams []string, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	return c.list(ctx, strings.Join(teams, ","), limit, offset, includeDeleted, func() ([]*domain.Outage, error) {
+		return c.Storage.ListOutagesByTeams(ctx, teams, limit, offset, includeDeleted)
+	})
+}
+
+// list serves a page of outages keyed by its query from the cache
+func (c *cachedStorage) list(ctx context.Context, teams string, limit, offset int, includeDeleted bool, fetch func() ([]*domain.Outage, error)) ([]*domain.Outage, error) {
+	epoch := c.token(ctx, epochKey)
+	gen := c.token(ctx, listGenKey)
+	if epoch == "" || gen == "" {
+		return fetch()
+	}
+	key := strings.Join([]string{"outalator:outages", epoch, gen,
+		strconv.FormatBool(includeDeleted), strconv.Itoa(limit), strconv.Itoa(offset), teams}, ":")
+	var outages []*domain.Outage
+	if c.load(ctx, key, &outages) {
+		return outages, nil
+	}
+	outages, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, key, outages)
+	return outages, nil
+}
+
+// Outage writes
+
+func (c *cachedStorage) CreateOutage(ctx context.Context, outage *domain.Outage) error {
+	defer c.invalidateLists(ctx)
+	return c.Storage.CreateOutage(ctx, outage)
+}
+
+func (c *cachedStorage) UpdateOutage(ctx context.Context, outage *domain.Outage) error {
+	defer c.invalidateOutage(ctx, outage.ID, true)
+	return c.Storage.UpdateOutage(ctx, outage)
+}
+
+func (c *cachedStorage) SetOutageParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, updatedAt time.Time) error {
+	defer c.invalidateOutage(ctx, id, true)
+	return c.Storage.SetOutageParent(ctx, id, parentID, updatedAt)
+}
+
+// DeleteOutage drops every cached entry, since deleting a parent outage
+// unlinks its children and they may be cached too
+func (c *cachedStorage) DeleteOutage(ctx context.Context, id uuid.UUID) error {
+	defer c.invalidateAll(ctx)
+	return c.Storage.DeleteOutage(ctx, id)
+}
+
+func (c *cachedStorage) TrashOutage(ctx context.Context, id uuid.UUID, at time.Time) error {
+	defer c.invalidateOutage(ctx, id, true)
+	return c.Storage.TrashOutage(ctx, id, at)
+}
+
+func (c *cachedStorage) RestoreOutage(ctx context.Context, id uuid.UUID) error {
+	defer c.invalidateOutage(ctx, id, true)
+	return c.Storage.RestoreOutage(ctx, id)
+}
+
+func (c *cachedStorage) PurgeOutages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	defer c.invalidateAll(ctx)
+	return c.Storage.PurgeOutages(ctx, deletedBefore)
+}
+
+// Writes to the alerts, notes and tags loaded with an outage. These leave
+// outage lists alone, since listed outages do not carry them.
+
+func (c *cachedStorage) CreateAlert(ctx context.Context, alert *domain.Alert) error {
+	err := c.Storage.CreateAlert(ctx, alert)
+	c.invalidateOutage(ctx, alert.OutageID, false)
+	return err
+}
+
+func (c *cachedStorage) UpsertAlert(ctx context.Context, alert *domain.Alert) (*domain.Alert, bool, error) {
+	stored, created, err := c.Storage.UpsertAlert(ctx, alert)
+	if err != nil {
+		return nil, false, err
+	}
+	c.invalidateOutage(ctx, stored.OutageID, false)
+	return stored, created, nil
+}
+
+// CreateOutageWithAlert also invalidates outage lists when it keeps the
+// outage it created
+func (c *cachedStorage) CreateOutageWithAlert(ctx context.Context, outage *domain.Outage, alert *domain.Alert) (*domain.Alert, bool, error) {
+	stored, created, err := c.Storage.CreateOutageWithAlert(ctx, outage, alert)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		c.invalidateLists(ctx)
+	}
+	c.invalidateOutage(ctx, stored.OutageID, false)
+	return stored, created, nil
+}
+
+// UpdateAlert also invalidates the outage the alert belonged to before,
+// in case the update moves it
+func (c *cachedStorage) UpdateAlert(ctx context.Context, alert *domain.Alert) error {
+	if old, err := c.Storage.GetAlert(ctx, alert.ID); err == nil && old.OutageID != alert.OutageID {
+		defer c.invalidateOutage(ctx, old.OutageID, false)
+	}
+	defer c.invalidateOutage(ctx, alert.OutageID, false)
+	return c.Storage.UpdateAlert(ctx, alert)
+}
+
+func (c *cachedStorage) CreateNote(ctx context.Context, note *domain.Note) error {
+	defer c.invalidateOutage(ctx, note.OutageID, false)
+	return c.Storage.CreateNote(ctx, note)
+}
+
+func (c *cachedStorage) UpdateNote(ctx context.Context, note *domain.Note) error {
+	defer c.invalidateOutage(ctx, note.OutageID, false)
+	return c.Storage.UpdateNote(ctx, note)
+}
+
+func (c *cachedStorage) DeleteNote(ctx context.Context, id uuid.UUID) error {
+	defer c.invalidateNoteOutage(ctx, id)()
+	return c.Storage.DeleteNote(ctx, id)
+}
+
+func (c *cachedStorage) TrashNote(ctx context.Context, id uuid.UUID, at time.Time) error {
+	defer c.invalidateNoteOutage(ctx, id)()
+	return c.Storage.TrashNote(ctx, id, at)
+}
+
+func (c *cachedStorage) RestoreNote(ctx context.Context, id uuid.UUID) error {
+	defer c.invalidateNoteOutage(ctx, id)()
+	return c.Storage.RestoreNote(ctx, id)
+}
+
+func (c *cachedStorage) PurgeNotes(ctx context.Context, deletedBefore time.Time) (int, error) {
+	defer c.invalidateAll(ctx)
+	return c.Storage.PurgeNotes(ctx, deletedBefore)
+}
+
+func (c *cachedStorage) CreateTag(ctx context.Context, tag *domain.Tag) error {
+	defer c.invalidateOutage(ctx, tag.OutageID, false)
+	return c.Storage.CreateTag(ctx, tag)
+}
+
+func (c *cachedStorage) UpdateTag(ctx context.Context, tag *domain.Tag) error {
+	defer c.invalidateTagOutage(ctx, tag.ID)()
+	return c.Storage.UpdateTag(ctx, tag)
+}
+
+func (c *cachedStorage) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	defer c.invalidateTagOutage(ctx, id)()
+	return c.Storage.DeleteTag(ctx, id)
+}
+
+// Close closes the backend and the wrapped storage
+func (c *cachedStorage) Close() error {
+	if err := c.backend.Close(); err != nil {
+		c.logger.Warn("failed to close cache", "error", err)
+	}
+	return c.Storage.Close()
+}
+
+// invalidateNoteOutage looks up the outage of a note before it is written
+// and returns a func that invalidates it, or every entry when the note
+// cannot be found
+func (c *cachedStorage) invalidateNoteOutage(ctx context.Context, id uuid.UUID) func() {
+	note, err := c.Storage.GetNote(ctx, id)
+	if err != nil {
+		return func() { c.invalidateAll(ctx) }
+	}
+	return func() { c.invalidateOutage(ctx, note.OutageID, false) }
+}
+
+// invalidateTagOutage is invalidateNoteOutage for tags
+func (c *cachedStorage) invalidateTagOutage(ctx context.Context, id uuid.UUID) func() {
+	tag, err := c.Storage.GetTag(ctx, id)
+	if err != nil {
+		return func() { c.invalidateAll(ctx) }
+	}
+	return func() { c.invalidateOutage(ctx, tag.OutageID, false) }
+}
+
+// invalidateOutage drops the cached outage and, with lists set, every
+// cached outage list
+func (c *cachedStorage) invalidateOutage(ctx context.Context, id uuid.UUID, lists bool) {
+	if epoch := c.token(ctx, epochKey); epoch != "" {
+		if err := c.backend.Delete(ctx, "outalator:outage:"+epoch+":"+id.String()); err != nil {
+			c.logger.WarnContext(ctx, "failed to invalidate cached outage", "outage_id", id, "error", err)
+		}
+	}
+	if lists {
+		c.invalidateLists(ctx)
+	}
+}
+
+// invalidateLists drops every cached outage list
+func (c *cachedStorage) invalidateLists(ctx context.Context) {
+	c.renew(ctx, listGenKey)
+}
+
+// invalidateAll drops every cached entry
+func (c *cachedStorage) invalidateAll(ctx context.Context) {
+	c.renew(ctx, epochKey)
+}
+
+// token returns the generation token stored at key, starting a new
+// generation when there is none. A token evicted from the backend is never
+// reused, so entries stored under it cannot be served again. It returns ""
+// when the backend fails, and callers then skip the cache.
+func (c *cachedStorage) token(ctx context.Context, key string) string {
+	value, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		c.logger.WarnContext(ctx, "cache unavailable", "error", err)
+		return ""
+	}
+	if ok {
+		return string(value)
+	}
+	return c.renew(ctx, key)
+}
+
+// renew replaces the generation token at key, returning the new token or ""
+// when the backend fails
+func (c *cachedStorage) renew(ctx context.Context, key string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+	if err := c.backend.Set(ctx, key, []byte(token), 0); err != nil {
+		c.logger.WarnContext(ctx, "failed to invalidate cache", "key", key, "error", err)
+		return ""
+	}
+	return token
+}
+
+// load decodes the entry at key into v, reporting whether it was found
+func (c *cachedStorage) load(ctx context.Context, key string, v any) bool {
+	data, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		c.logger.WarnContext(ctx, "cache unavailable", "error", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		c.logger.WarnContext(ctx, "discarding unreadable cache entry", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+// store encodes v at key. Entries are stored as JSON so callers that modify
+// what they read never change the cached copy.
+func (c *cachedStorage) store(ctx context.Context, key string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to encode cache entry", "key", key, "error", err)
+		return
+	}
+	if err := c.backend.Set(ctx, key, data, c.ttl); err != nil {
+		c.logger.WarnContext(ctx, "failed to store cache entry", "key", key, "error", err)
+	}
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/storage"
+	"github.com/conall/outalator/storage/storagetest"
+	"github.com/google/uuid"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestCachedStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(*testing.T) storage.Storage {
+		return WrapStorage(testutil.NewMemStorage(), NewLRU(0), 0, discard)
+	})
+}
+
+// countingStorage counts the outage reads reaching the database
+type countingStorage struct {
+	storage.Storage
+	gets, lists int
+}
+
+func (s *countingStorage) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
+	s.gets++
+	return s.Storage.GetOutage(ctx, id)
+}
+
+func (s *countingStorage) ListOutages(ctx context.Context, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	s.lists++
+	return s.Storage.ListOutages(ctx, limit, offset, includeDeleted)
+}
+
+func newOutage(t *testing.T, s storage.Storage, title string) *domain.Outage {
+	t.Helper()
+	now := time.Now().UTC().Truncate(time.Second)
+	outage := &domain.Outage{
+		ID:        uuid.New(),
+		Title:     title,
+		Status:    "open",
+		Severity:  "high",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.CreateOutage(context.Background(), outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+	return outage
+}
+
+func TestReadsAreCached(t *testing.T) {
+	ctx := context.Background()
+	db := &countingStorage{Storage: testutil.NewMemStorage()}
+	c := WrapStorage(db, NewLRU(0), time.Minute, discard)
+	outage := newOutage(t, c, "Checkout down")
+
+	for range 3 {
+		got, err := c.GetOutage(ctx, outage.ID)
+		if err != nil {
+			t.Fatalf("GetOutage: %v", err)
+		}
+		if got.Title != "Checkout down" {
+			t.Errorf("Title = %q, want %q", got.Title, "Checkout down")
+		}
+		if _, err := c.ListOutages(ctx, 10, 0, false); err != nil {
+			t.Fatalf("ListOutages: %v", err)
+		}
+	}
+	if db.gets != 1 || db.lists != 1 {
+		t.Errorf("database reads = %d gets, %d lists, want 1 of each", db.gets, db.lists)
+	}
+
+	// Changing the returned outage must not change the cached copy
+	got, _ := c.GetOutage(ctx, outage.ID)
+	got.Title = "changed"
+	if got, _ := c.GetOutage(ctx, outage.ID); got.Title != "Checkout down" {
+		t.Errorf("cached Title = %q after modifying a read, want %q", got.Title, "Checkout down")
+	}
+}
+
+func TestWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := WrapStorage(testutil.NewMemStorage(), NewLRU(0), time.Minute, discard)
+	outage := newOutage(t, c, "Checkout down")
+	if _, err := c.GetOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if _, err := c.ListOutages(ctx, 10, 0, false); err != nil {
+		t.Fatalf("ListOutages: %v", err)
+	}
+
+	outage.Title = "Checkout degraded"
+	if err := c.UpdateOutage(ctx, outage); err != nil {
+		t.Fatalf("UpdateOutage: %v", err)
+	}
+	if got, _ := c.GetOutage(ctx, outage.ID); got.Title != "Checkout degraded" {
+		t.Errorf("Title after update = %q, want %q", got.Title, "Checkout degraded")
+	}
+	list, _ := c.ListOutages(ctx, 10, 0, false)
+	if len(list) != 1 || list[0].Title != "Checkout degraded" {
+		t.Errorf("listed outages after update = %+v, want the updated outage", list)
+	}
+
+	newOutage(t, c, "Search down")
+	if list, _ := c.ListOutages(ctx, 10, 0, false); len(list) != 2 {
+		t.Errorf("listed %d outages after create, want 2", len(list))
+	}
+
+	note := &domain.Note{ID: uuid.New(), OutageID: outage.ID, Content: "Rolled back", Format: "plaintext", Author: "alice",
+		CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+	if err := c.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	if got, _ := c.GetOutage(ctx, outage.ID); len(got.Notes) != 1 {
+		t.Errorf("outage has %d notes after CreateNote, want 1", len(got.Notes))
+	}
+	if err := c.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	if got, _ := c.GetOutage(ctx, outage.ID); len(got.Notes) != 0 {
+		t.Errorf("outage has %d notes after DeleteNote, want 0", len(got.Notes))
+	}
+
+	if err := c.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	if _, err := c.GetOutage(ctx, outage.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutage after delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+// failingBackend is a cache that cannot be reached
+type failingBackend struct{}
+
+func (failingBackend) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+func (failingBackend) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("connection refused")
+}
+func (failingBackend) Delete(context.Context, string) error { return errors.New("connection refused") }
+func (failingBackend) Close() error                         { return nil }
+
+func TestUnavailableBackendFallsThrough(t *testing.T) {
+	ctx := context.Background()
+	db := &countingStorage{Storage: testutil.NewMemStorage()}
+	c := WrapStorage(db, failingBackend{}, time.Minute, discard)
+	outage := newOutage(t, c, "Checkout down")
+
+	for range 2 {
+		if _, err := c.GetOutage(ctx, outage.ID); err != nil {
+			t.Fatalf("GetOutage: %v", err)
+		}
+	}
+	if db.gets != 2 {
+		t.Errorf("database gets = %d, want 2", db.gets)
+	}
+}
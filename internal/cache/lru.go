@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultSize is how many entries the memory backend holds when no size is
+// configured
+const DefaultSize = 1000
+
+// LRU is an in-process Backend that evicts the least recently used entry
+// once it holds its maximum number of entries. Each replica has its own, so
+// writes on one replica only reach the others when their entries expire.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Front is the most recently used
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+// lruEntry is the value of an LRU list element
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // Zero never expires
+}
+
+// NewLRU returns an empty LRU holding up to size entries, or DefaultSize
+// when size is zero
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &LRU{size: size, order: list.New(), entries: make(map[string]*list.Element), now: time.Now}
+}
+
+// Get returns the entry at key unless it is missing or expired
+func (l *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := el.Value.(*lruEntry)
+	if !entry.expires.IsZero() && !l.now().Before(entry.expires) {
+		l.remove(el)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+// Set stores value at key, evicting the least recently used entry when
+// the LRU is full
+func (l *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var expires time.Time
+	if ttl > 0 {
+		expires = l.now().Add(ttl)
+	}
+	if el, ok := l.entries[key]; ok {
+		el.Value = &lruEntry{key: key, value: value, expires: expires}
+		l.order.MoveToFront(el)
+		return nil
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for l.order.Len() > l.size {
+		l.remove(l.order.Back())
+	}
+	return nil
+}
+
+// Delete removes the entry at key, if any
+func (l *LRU) Delete(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.entries[key]; ok {
+		l.remove(el)
+	}
+	return nil
+}
+
+// Close does nothing; an LRU holds no resources
+func (l *LRU) Close() error { return nil }
+
+// Len returns the number of entries held, including expired entries not
+// yet evicted
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// remove drops el. The caller must hold l.mu.
+func (l *LRU) remove(el *list.Element) {
+	l.order.Remove(el)
+	delete(l.entries, el.Value.(*lruEntry).key)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	l := NewLRU(2)
+	_ = l.Set(ctx, "a", []byte("1"), 0)
+	_ = l.Set(ctx, "b", []byte("2"), 0)
+	if _, ok, _ := l.Get(ctx, "a"); !ok {
+		t.Fatal("a missing before eviction")
+	}
+	_ = l.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok, _ := l.Get(ctx, "b"); ok {
+		t.Error("b still cached, want it evicted as least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := l.Get(ctx, key); !ok {
+			t.Errorf("%s evicted, want it kept", key)
+		}
+	}
+	if n := l.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	l := NewLRU(0)
+	l.now = func() time.Time { return now }
+	_ = l.Set(ctx, "short", []byte("1"), time.Minute)
+	_ = l.Set(ctx, "forever", []byte("2"), 0)
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := l.Get(ctx, "short"); ok {
+		t.Error("short served after its ttl, want it expired")
+	}
+	if value, ok, _ := l.Get(ctx, "forever"); !ok || string(value) != "2" {
+		t.Errorf("Get(forever) = %q, %v, want %q, true", value, ok, "2")
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	ctx := context.Background()
+	l := NewLRU(0)
+	_ = l.Set(ctx, "a", []byte("1"), 0)
+	_ = l.Delete(ctx, "a")
+	_ = l.Delete(ctx, "missing")
+	if _, ok, _ := l.Get(ctx, "a"); ok {
+		t.Error("a still cached after Delete")
+	}
+}
@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// defaultRedisTimeout bounds each Redis command when the context has no
+// earlier deadline, so a stalled server falls back to the database quickly
+const defaultRedisTimeout = 500 * time.Millisecond
+
+// redisIdleConns is how many connections are kept open between commands
+const redisIdleConns = 8
+
+// RedisConfig holds the Redis server entries are shared through
+type RedisConfig struct {
+	Addr     string // host:port
+	Password string // Sent with AUTH when set
+	DB       int    // Database selected after connecting
+}
+
+// Redis is a Backend shared by every replica through a Redis server. It
+// speaks the small part of the Redis protocol the cache needs: GET, SET
+// with an expiry, and DEL.
+type Redis struct {
+	cfg  RedisConfig
+	idle chan *redisConn
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// redisConn is one connection to the server
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// NewRedis returns a Redis backend for cfg. Connections are opened on
+// first use.
+func NewRedis(cfg RedisConfig) *Redis {
+	var d net.Dialer
+	return &Redis{cfg: cfg, idle: make(chan *redisConn, redisIdleConns), dial: d.DialContext}
+}
+
+// Get returns the value at key
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores value at key, expiring it after ttl when ttl is positive
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := r.do(ctx, args...)
+	return err
+}
+
+// Delete removes key
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	_, err := r.do(ctx, "DEL", key)
+	return err
+}
+
+// Close closes the idle connections
+func (r *Redis) Close() error {
+	for {
+		select {
+		case c := <-r.idle:
+			_ = c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do sends a command and reads its reply: a string for a status, an int64
+// for an integer, []byte or nil for a bulk string. An error reply is
+// returned as a redisError, and leaves the connection usable.
+func (r *Redis) do(ctx context.Context, args ...string) (any, error) {
+	c, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := c.do(ctx, args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		_ = c.conn.Close()
+		return nil, err
+	}
+	r.release(c)
+	return reply, err
+}
+
+// conn returns an idle connection, or dials and sets up a new one
+func (r *Redis) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.idle:
+		return c, nil
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultRedisTimeout)
+	defer cancel()
+	conn, err := r.dial(ctx, "tcp", r.cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to connect: %w", err)
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if r.cfg.Password != "" {
+		if _, err := c.do(ctx, "AUTH", r.cfg.Password); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	if r.cfg.DB != 0 {
+		if _, err := c.do(ctx, "SELECT", strconv.Itoa(r.cfg.DB)); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// release returns c to the idle pool, closing it when the pool is full
+func (r *Redis) release(c *redisConn) {
+	select {
+	case r.idle <- c:
+	default:
+		_ = c.conn.Close()
+	}
+}
+
+// do writes a command as an array of bulk strings and reads the reply
+func (c *redisConn) do(ctx context.Context, args ...string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if limit := time.Now().Add(defaultRedisTimeout); !ok || limit.Before(deadline) {
+		deadline = limit
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return c.readReply()
+}
+
+// readReply reads one reply. Arrays are not read, as no command sent
+// returns one.
+func (c *redisConn) readReply() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply")
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		n, err := strconv.ParseInt(body, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed integer reply: %w", err)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk reply: %w", err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return data[:n], nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply type %q", kind)
+	}
+}
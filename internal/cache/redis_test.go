@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves GET, SET, DEL and AUTH from a map, recording the
+// commands it receives
+type fakeRedis struct {
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	f := &fakeRedis{data: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, ln.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			reply = "+OK\r\n"
+		case "GET":
+			if value, ok := f.data[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case "SET":
+			f.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case "DEL":
+			delete(f.data, args[1])
+			reply = ":1\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads one command sent as an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	f, addr := startFakeRedis(t)
+	r := NewRedis(RedisConfig{Addr: addr, Password: "secret"})
+	defer r.Close()
+
+	if _, ok, err := r.Get(ctx, "outage"); err != nil || ok {
+		t.Fatalf("Get(missing) = %v, %v, want not found", ok, err)
+	}
+	if err := r.Set(ctx, "outage", []byte("line one\r\nline two"), 30*time.Second); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	value, ok, err := r.Get(ctx, "outage")
+	if err != nil || !ok || string(value) != "line one\r\nline two" {
+		t.Fatalf("Get = %q, %v, %v, want the stored value", value, ok, err)
+	}
+	if err := r.Delete(ctx, "outage"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok, _ := r.Get(ctx, "outage"); ok {
+		t.Error("Get after Delete found the key")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	want := []string{"AUTH secret", "GET outage", "SET outage line one\r\nline two PX 30000", "GET outage", "DEL outage", "GET outage"}
+	if strings.Join(f.commands, "|") != strings.Join(want, "|") {
+		t.Errorf("commands = %q, want %q (one connection, authenticated once)", f.commands, want)
+	}
+}
+
+func TestRedisUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	r := NewRedis(RedisConfig{Addr: addr})
+	if _, _, err := r.Get(context.Background(), "outage"); err == nil {
+		t.Error("Get against a closed port succeeded, want an error")
+	}
+}
@@ -0,0 +1,164 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Backstage annotations read from component entities. The outalator.io
+// annotations take precedence over the Backstage conventions they fall back
+// to.
+const (
+	annotationTier                = "outalator.io/tier"
+	annotationRunbookURL          = "outalator.io/runbook-url"
+	annotationRepo                = "outalator.io/repo"
+	annotationStatuspageComponent = "outalator.io/statuspage-component"
+	annotationProjectSlug         = "github.com/project-slug"
+	annotationSourceLocation      = "backstage.io/source-location"
+)
+
+// entity is a Backstage catalog entity, with the fields services are built
+// from
+type entity struct {
+	Kind     string `json:"kind" yaml:"kind"`
+	Metadata struct {
+		Name        string            `json:"name" yaml:"name"`
+		Description string            `json:"description" yaml:"description"`
+		Annotations map[string]string `json:"annotations" yaml:"annotations"`
+		Labels      map[string]string `json:"labels" yaml:"labels"`
+		Links       []struct {
+			URL   string `json:"url" yaml:"url"`
+			Title string `json:"title" yaml:"title"`
+			Type  string `json:"type" yaml:"type"`
+		} `json:"links" yaml:"links"`
+	} `json:"metadata" yaml:"metadata"`
+	Spec struct {
+		Owner string `json:"owner" yaml:"owner"`
+	} `json:"spec" yaml:"spec"`
+}
+
+// isComponent reports whether the entity is a component, the kind of
+// entity Backstage uses for services
+func (e *entity) isComponent() bool {
+	return strings.EqualFold(e.Kind, "Component")
+}
+
+// service builds a catalogue service from a component entity. The owner's
+// kind and namespace are dropped, so group:default/payments is owned by
+// the payments team. A tier that is not a number is left unset.
+func (e *entity) service() domain.Service {
+	md := e.Metadata
+	svc := domain.Service{
+		Name:                md.Name,
+		Description:         md.Description,
+		OwningTeam:          entityName(e.Spec.Owner),
+		RunbookURL:          md.Annotations[annotationRunbookURL],
+		Repo:                md.Annotations[annotationRepo],
+		StatuspageComponent: md.Annotations[annotationStatuspageComponent],
+	}
+
+	tier := md.Annotations[annotationTier]
+	if tier == "" {
+		tier = md.Labels["tier"]
+	}
+	svc.Tier, _ = strconv.Atoi(strings.Trim(strings.TrimPrefix(strings.ToLower(tier), "tier"), "-_ "))
+
+	if svc.RunbookURL == "" {
+		for _, link := range md.Links {
+			if strings.EqualFold(link.Title, "runbook") || strings.EqualFold(link.Type, "runbook") {
+				svc.RunbookURL = link.URL
+				break
+			}
+		}
+	}
+	if svc.Repo == "" {
+		if slug := md.Annotations[annotationProjectSlug]; slug != "" {
+			svc.Repo = "https://github.com/" + slug
+		} else if location, ok := strings.CutPrefix(md.Annotations[annotationSourceLocation], "url:"); ok {
+			svc.Repo = location
+		}
+	}
+	return svc
+}
+
+// entityName returns the name in an entity reference such as
+// group:default/payments
+func entityName(ref string) string {
+	if _, name, ok := strings.Cut(ref, ":"); ok {
+		ref = name
+	}
+	if _, name, ok := strings.Cut(ref, "/"); ok {
+		ref = name
+	}
+	return ref
+}
+
+// Backstage is a service catalogue read from a Backstage instance's
+// catalog API, syncing its component entities
+type Backstage struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewBackstage creates a catalogue reading the Backstage instance at
+// baseURL. token, when set, is sent as a bearer token.
+func NewBackstage(baseURL, token string, transport http.RoundTripper) *Backstage {
+	return &Backstage{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+// Name implements service.ServiceCatalogue
+func (b *Backstage) Name() string {
+	return "backstage"
+}
+
+// FetchServices implements service.ServiceCatalogue, listing every
+// component entity
+func (b *Backstage) FetchServices(ctx context.Context) ([]domain.Service, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.baseURL+"/api/catalog/entities?filter=kind=component", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Backstage API error: %s (status: %d)", string(body), resp.StatusCode)
+	}
+	var entities []entity
+	if err := json.NewDecoder(resp.Body).Decode(&entities); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	services := make([]domain.Service, 0, len(entities))
+	for _, e := range entities {
+		if e.isComponent() {
+			services = append(services, e.service())
+		}
+	}
+	return services, nil
+}
@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+const catalogInfo = `
+apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: checkout
+  description: Checkout API
+  annotations:
+    github.com/project-slug: example/checkout
+    outalator.io/statuspage-component: cmp1
+  labels:
+    tier: tier-1
+  links:
+    - url: https://runbooks.example.com/checkout
+      title: Runbook
+spec:
+  type: service
+  owner: group:default/payments
+---
+apiVersion: backstage.io/v1alpha1
+kind: System
+metadata:
+  name: commerce
+`
+
+func TestFileServicesList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.yaml")
+	data := `
+services:
+  - name: search
+    owning_team: discovery
+    tier: 2
+    runbook_url: https://runbooks.example.com/search
+    repo: https://github.com/example/search
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	services, err := NewFile(path).FetchServices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.Service{{
+		Name: "search", OwningTeam: "discovery", Tier: 2,
+		RunbookURL: "https://runbooks.example.com/search", Repo: "https://github.com/example/search",
+	}}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("services = %+v, want %+v", services, want)
+	}
+
+	if _, err := NewFile(filepath.Join(t.TempDir(), "missing.yaml")).FetchServices(context.Background()); err == nil {
+		t.Error("FetchServices of a missing file succeeded, want an error")
+	}
+}
+
+func TestFileBackstageEntities(t *testing.T) {
+	services, err := parseFile([]byte(catalogInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.Service{{
+		Name: "checkout", Description: "Checkout API", OwningTeam: "payments", Tier: 1,
+		RunbookURL: "https://runbooks.example.com/checkout", Repo: "https://github.com/example/checkout",
+		StatuspageComponent: "cmp1",
+	}}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("services = %+v, want only the component, %+v", services, want)
+	}
+}
+
+func TestBackstageFetchServices(t *testing.T) {
+	var gotAuth, gotFilter string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/catalog/entities" {
+			http.NotFound(w, r)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		gotFilter = r.URL.Query().Get("filter")
+		_, _ = w.Write([]byte(`[{
+			"kind": "Component",
+			"metadata": {
+				"name": "checkout",
+				"annotations": {
+					"outalator.io/tier": "1",
+					"outalator.io/runbook-url": "https://runbooks.example.com/checkout",
+					"backstage.io/source-location": "url:https://git.example.com/checkout/"
+				}
+			},
+			"spec": {"owner": "payments"}
+		}]`))
+	}))
+	defer srv.Close()
+
+	services, err := NewBackstage(srv.URL+"/", "secret", nil).FetchServices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "Bearer secret" || gotFilter != "kind=component" {
+		t.Errorf("request auth %q filter %q, want the token and a component filter", gotAuth, gotFilter)
+	}
+	want := []domain.Service{{
+		Name: "checkout", OwningTeam: "payments", Tier: 1,
+		RunbookURL: "https://runbooks.example.com/checkout", Repo: "https://git.example.com/checkout/",
+	}}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("services = %+v, want %+v", services, want)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	if _, err := NewBackstage(failing.URL, "", nil).FetchServices(context.Background()); err == nil {
+		t.Error("FetchServices succeeded on a 401, want an error")
+	}
+}
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/conall/outalator/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// File is a service catalogue read from a YAML file. The file either lists
+// services under a services key:
+//
+//	services:
+//	  - name: checkout
+//	    owning_team: payments
+//	    tier: 1
+//	    runbook_url: https://runbooks.example.com/checkout
+//	    repo: https://github.com/example/checkout
+//
+// or holds Backstage catalog-info documents, whose Component entities are
+// synced as with Backstage. The file is read again on every sync.
+type File struct {
+	path string
+}
+
+// fileService is a service as listed in a catalogue file
+type fileService struct {
+	Name                string `yaml:"name"`
+	Description         string `yaml:"description"`
+	OwningTeam          string `yaml:"owning_team"`
+	Tier                int    `yaml:"tier"`
+	RunbookURL          string `yaml:"runbook_url"`
+	Repo                string `yaml:"repo"`
+	StatuspageComponent string `yaml:"statuspage_component"`
+}
+
+// NewFile creates a catalogue reading the YAML file at path
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Name implements service.ServiceCatalogue
+func (f *File) Name() string {
+	return "file"
+}
+
+// FetchServices implements service.ServiceCatalogue
+func (f *File) FetchServices(context.Context) ([]domain.Service, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service catalogue: %w", err)
+	}
+	services, err := parseFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service catalogue %s: %w", f.path, err)
+	}
+	return services, nil
+}
+
+// parseFile reads the services in every document of a catalogue file
+func parseFile(data []byte) ([]domain.Service, error) {
+	var services []domain.Service
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc struct {
+			Services []fileService `yaml:"services"`
+			entity   `yaml:",inline"`
+		}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return services, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range doc.Services {
+			services = append(services, domain.Service{
+				Name:                s.Name,
+				Description:         s.Description,
+				OwningTeam:          s.OwningTeam,
+				Tier:                s.Tier,
+				RunbookURL:          s.RunbookURL,
+				Repo:                s.Repo,
+				StatuspageComponent: s.StatuspageComponent,
+			})
+		}
+		if doc.isComponent() {
+			services = append(services, doc.service())
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package catalog keeps Outalator's service catalogue in sync with an
+// external catalogue, either a YAML file or a Backstage software catalogue,
+// so service owners, tiers and runbooks are maintained where teams already
+// describe their services.
+package catalog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// defaultInterval is the time between syncs when none is configured
+const defaultInterval = time.Hour
+
+// ServiceSyncer is the subset of the service layer the syncer drives
+type ServiceSyncer interface {
+	SyncServices(ctx context.Context) (*domain.OpsConfigPlan, error)
+}
+
+// Syncer syncs the service catalogue on a fixed interval
+type Syncer struct {
+	services ServiceSyncer
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewSyncer creates a syncer for the given service. A zero interval falls
+// back to the package default.
+func NewSyncer(services ServiceSyncer, interval time.Duration, logger *slog.Logger) *Syncer {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Syncer{services: services, interval: interval, logger: logger}
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled
+func (s *Syncer) Run(ctx context.Context) {
+	s.SyncOnce(ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SyncOnce(ctx)
+		}
+	}
+}
+
+// SyncOnce runs a single sync. The service logs the changes it makes.
+func (s *Syncer) SyncOnce(ctx context.Context) {
+	if _, err := s.services.SyncServices(ctx); err != nil {
+		s.logger.ErrorContext(ctx, "service catalogue sync failed", "error", err)
+	}
+}
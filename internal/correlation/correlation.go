@@ -0,0 +1,121 @@
+// Package correlation groups related alerts, so that alerts raised for one
+// problem can be recorded as a single outage rather than one outage each.
+package correlation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// Strategy is how a Correlator decides that alerts are related
+type Strategy string
+
+const (
+	// TimeWindow groups alerts from the same source and team triggered
+	// within the window of each other
+	TimeWindow Strategy = "time-window"
+	// IncidentKey groups alerts sharing the provider's incident key (PagerDuty
+	// incident key, OpsGenie alias), however far apart they were triggered
+	IncidentKey Strategy = "incident-key"
+	// Service groups alerts from the same source and service triggered within
+	// the window of each other
+	Service Strategy = "service"
+)
+
+// Strategies lists the supported strategies
+var Strategies = []Strategy{TimeWindow, IncidentKey, Service}
+
+// ParseStrategy returns the strategy named s
+func ParseStrategy(s string) (Strategy, error) {
+	for _, strategy := range Strategies {
+		if string(strategy) == s {
+			return strategy, nil
+		}
+	}
+	names := make([]string, len(Strategies))
+	for i, strategy := range Strategies {
+		names[i] = string(strategy)
+	}
+	return "", fmt.Errorf("unknown grouping %q (want one of %s): %w", s, strings.Join(names, ", "), domain.ErrInvalidInput)
+}
+
+// Group is a set of related alerts. Lock it while creating or updating the
+// group's outage, so alerts joining it at the same time share one.
+type Group struct {
+	sync.Mutex
+	Leader *notification.Alert // First alert assigned to the group
+	Outage *domain.Outage      // The group's outage, once recorded
+
+	start, end time.Time // Trigger times of the group's earliest and latest alerts
+}
+
+// Correlator assigns alerts to groups. It is safe for concurrent use.
+type Correlator struct {
+	strategy Strategy
+	window   time.Duration
+
+	mu     sync.Mutex
+	groups map[string][]*Group // By key, e.g. source and team
+}
+
+// New creates a correlator grouping alerts by strategy. window bounds how
+// far apart related alerts may be triggered, except for IncidentKey, whose
+// alerts are related whenever they were triggered.
+func New(strategy Strategy, window time.Duration) *Correlator {
+	if strategy == IncidentKey {
+		window = 0
+	}
+	return &Correlator{strategy: strategy, window: window, groups: make(map[string][]*Group)}
+}
+
+// key returns the key of the groups alert may join, or "" if it cannot be
+// related to any other alert
+func (c *Correlator) key(alert *notification.Alert) string {
+	var key string
+	switch c.strategy {
+	case TimeWindow:
+		key = alert.TeamName
+	case IncidentKey:
+		key = alert.IncidentKey
+	case Service:
+		key = alert.Service
+	}
+	if key == "" {
+		return ""
+	}
+	return alert.Source + "\x00" + key
+}
+
+// Assign returns the group alert belongs to, starting a new one, led by
+// alert, if it is not related to any alert assigned so far. Alerts should be
+// assigned oldest first, so each group's leader is its earliest alert.
+func (c *Correlator) Assign(alert *notification.Alert) *Group {
+	at := alert.TriggeredAt
+	key := c.key(alert)
+	if key == "" {
+		return &Group{Leader: alert, start: at, end: at}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, g := range c.groups[key] {
+		if c.window > 0 && (at.Before(g.start.Add(-c.window)) || at.After(g.end.Add(c.window))) {
+			continue
+		}
+		if at.Before(g.start) {
+			g.start = at
+		}
+		if at.After(g.end) {
+			g.end = at
+		}
+		return g
+	}
+	g := &Group{Leader: alert, start: at, end: at}
+	c.groups[key] = append(c.groups[key], g)
+	return g
+}
@@ -0,0 +1,61 @@
+package correlation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func TestCorrelator(t *testing.T) {
+	base := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	alert := func(minutes int, team, key, service string) *notification.Alert {
+		return &notification.Alert{
+			Source:      "pagerduty",
+			TeamName:    team,
+			IncidentKey: key,
+			Service:     service,
+			TriggeredAt: base.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+	alerts := []*notification.Alert{
+		alert(0, "payments", "db-down", "postgres"),
+		alert(20, "payments", "db-down", "api"),
+		alert(25, "payments", "", "postgres"),
+		alert(50, "search", "db-down", ""),
+		alert(300, "payments", "db-down", "postgres"),
+	}
+
+	tests := []struct {
+		strategy Strategy
+		want     []int // Index of the alert leading each alert's group
+	}{
+		// Chained by the 30m window until the gap before the last alert
+		{TimeWindow, []int{0, 0, 0, 3, 4}},
+		// The key is shared whenever triggered, and keyless alerts stand alone
+		{IncidentKey, []int{0, 0, 2, 0, 0}},
+		// Alerts without a service stand alone
+		{Service, []int{0, 1, 0, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			c := New(tt.strategy, 30*time.Minute)
+			for i, a := range alerts {
+				if got := c.Assign(a).Leader; got != alerts[tt.want[i]] {
+					t.Errorf("alert %d led by %v, want alert %d", i, got.TriggeredAt.Sub(base), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	if s, err := ParseStrategy("service"); err != nil || s != Service {
+		t.Errorf("ParseStrategy(service) = %q, %v, want %q", s, err, Service)
+	}
+	if _, err := ParseStrategy("team"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("ParseStrategy(team) error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Package email sends outage notifications over SMTP. It is used to notify
+// users and teams @mentioned in outage notes, teams whose outages are
+// overdue a status update and the watchers of outages, and to send
+// scheduled outage digests.
+package email
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+)
+
+// Config holds SMTP configuration
+type Config struct {
+	Host     string
+	Port     int    // Default 587
+	Username string // Leave empty to send without authentication
+	Password string
+	From     string
+	// OutageURL links messages back to the outage. "{id}" is replaced with
+	// the outage ID. Optional.
+	OutageURL string
+}
+
+// sendFunc matches smtp.SendMail so tests can capture messages
+type sendFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// Notifier emails mention notifications, update reminders, watcher
+// notifications and digests
+type Notifier struct {
+	cfg  Config
+	addr string
+	auth smtp.Auth
+	send sendFunc
+}
+
+// NewNotifier creates an SMTP notifier
+func NewNotifier(cfg Config) *Notifier {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	n := &Notifier{
+		cfg:  cfg,
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		send: smtp.SendMail,
+	}
+	if cfg.Username != "" {
+		n.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return n
+}
+
+// NotifyMention implements service.MentionNotifier by emailing the mentioned
+// user, or every member of the mentioned team
+func (n *Notifier) NotifyMention(_ context.Context, outage *domain.Outage, note *domain.Note, recipient service.MentionRecipient) error {
+	if len(recipient.Emails) == 0 {
+		return nil
+	}
+
+	who := "you"
+	if recipient.Mention.Type == domain.MentionTeam {
+		who = "@" + recipient.Mention.Name
+	}
+	subject := fmt.Sprintf("[%s] %s mentioned %s on outage: %s", outage.Severity, note.Author, who, outage.Title)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s mentioned %s on outage %q (%s):\r\n\r\n", note.Author, who, outage.Title, outage.ID)
+	for _, line := range strings.Split(note.Content, "\n") {
+		fmt.Fprintf(&body, "> %s\r\n", strings.TrimRight(line, "\r"))
+	}
+	if n.cfg.OutageURL != "" {
+		fmt.Fprintf(&body, "\r\n%s\r\n", strings.ReplaceAll(n.cfg.OutageURL, "{id}", outage.ID.String()))
+	}
+
+	return n.sendMail(recipient.Emails, subject, body.String())
+}
+
+// NotifyUpdateOverdue implements service.UpdateReminderNotifier by emailing
+// the members of the team routing assigned the outage
+func (n *Notifier) NotifyUpdateOverdue(_ context.Context, outage *domain.Outage, reminder service.UpdateReminder) error {
+	if len(reminder.Emails) == 0 {
+		return nil
+	}
+
+	sla := reminder.SLA
+	interval := time.Duration(sla.IntervalSeconds) * time.Second
+	subject := fmt.Sprintf("[%s] Status update overdue: %s", outage.Severity, outage.Title)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Outage %q (%s) needs a status update.\r\n\r\n", outage.Title, outage.ID)
+	fmt.Fprintf(&body, "%s outages need an update every %s. The last update was at %s and the next was due at %s.\r\n",
+		outage.Severity, interval, sla.LastUpdateAt.UTC().Format(time.RFC1123), sla.DueAt.UTC().Format(time.RFC1123))
+	body.WriteString("Add a note to the outage to reset the timer.\r\n")
+	if n.cfg.OutageURL != "" {
+		fmt.Fprintf(&body, "\r\n%s\r\n", strings.ReplaceAll(n.cfg.OutageURL, "{id}", outage.ID.String()))
+	}
+
+	return n.sendMail(reminder.Emails, subject, body.String())
+}
+
+// NotifyWatchers implements service.WatchNotifier by emailing the watchers
+// of an outage about its new status or note
+func (n *Notifier) NotifyWatchers(_ context.Context, event service.WatchEvent, emails []string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+
+	outage := event.Outage
+	var subject string
+	var body strings.Builder
+	if event.Note != nil {
+		subject = fmt.Sprintf("[%s] %s added a note to outage: %s", outage.Severity, event.Note.Author, outage.Title)
+		fmt.Fprintf(&body, "%s added a note to outage %q (%s):\r\n\r\n", event.Note.Author, outage.Title, outage.ID)
+		for _, line := range strings.Split(event.Note.Content, "\n") {
+			fmt.Fprintf(&body, "> %s\r\n", strings.TrimRight(line, "\r"))
+		}
+	} else {
+		subject = fmt.Sprintf("[%s] Outage %s: %s", outage.Severity, outage.Status, outage.Title)
+		fmt.Fprintf(&body, "Outage %q (%s) changed from %s to %s.\r\n", outage.Title, outage.ID, event.PreviousStatus, outage.Status)
+	}
+	if n.cfg.OutageURL != "" {
+		fmt.Fprintf(&body, "\r\n%s\r\n", strings.ReplaceAll(n.cfg.OutageURL, "{id}", outage.ID.String()))
+	}
+	fmt.Fprintf(&body, "\r\nYou are watching this outage. To stop these emails, unwatch it with DELETE /api/v1/outages/%s/watch.\r\n", outage.ID)
+
+	return n.sendMail(emails, subject, body.String())
+}
+
+// SendDigest implements service.DigestNotifier by emailing the digest's
+// addresses
+func (n *Notifier) SendDigest(_ context.Context, msg service.DigestMessage) error {
+	if len(msg.Emails) == 0 {
+		return nil
+	}
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Text, "\r\n", "\n"), "\n", "\r\n")
+	return n.sendMail(msg.Emails, msg.Subject, body)
+}
+
+// sendMail sends a plain text message to every address in to
+func (n *Notifier) sendMail(to []string, subject, body string) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		n.cfg.From, strings.Join(to, ", "), headerSafe(subject), body)
+	if err := n.send(n.addr, n.auth, n.cfg.From, to, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// headerSafe strips line breaks so user content cannot inject headers
+func headerSafe(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,177 @@
+package email
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+func TestNotifyMention(t *testing.T) {
+	n := NewNotifier(Config{Host: "smtp.example.com", From: "outalator@example.com", OutageURL: "https://outalator.example.com/outages/{id}"})
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  string
+	)
+	n.send = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	outage := &domain.Outage{ID: uuid.New(), Title: "Card errors\r\nBcc: x@evil.com", Severity: "high"}
+	note := &domain.Note{Author: "alice", Content: "@payments please look\nsecond line"}
+	recipient := service.MentionRecipient{
+		Mention: domain.Mention{Type: domain.MentionTeam, Name: "payments"},
+		Emails:  []string{"bob@example.com", "carol@example.com"},
+	}
+	if err := n.NotifyMention(context.Background(), outage, note, recipient); err != nil {
+		t.Fatalf("NotifyMention: %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:587" {
+		t.Errorf("addr = %q, want default port 587", gotAddr)
+	}
+	if len(gotTo) != 2 {
+		t.Errorf("to = %v", gotTo)
+	}
+	headers, body, _ := strings.Cut(gotMsg, "\r\n\r\n")
+	if strings.Contains(headers, "\r\nBcc:") {
+		t.Errorf("outage title injected a header:\n%s", headers)
+	}
+	if !strings.Contains(headers, "Subject: [high] alice mentioned @payments on outage") {
+		t.Errorf("headers = %q", headers)
+	}
+	if !strings.Contains(body, "> second line") || !strings.Contains(body, "/outages/"+outage.ID.String()) {
+		t.Errorf("body = %q", body)
+	}
+
+	// Nothing to send without addresses
+	gotMsg = ""
+	if err := n.NotifyMention(context.Background(), outage, note, service.MentionRecipient{}); err != nil || gotMsg != "" {
+		t.Errorf("empty recipient: err %v, sent %q", err, gotMsg)
+	}
+}
+
+func TestNotifyUpdateOverdue(t *testing.T) {
+	n := NewNotifier(Config{Host: "smtp.example.com", From: "outalator@example.com"})
+	var (
+		gotTo  []string
+		gotMsg string
+	)
+	n.send = func(_ string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotTo, gotMsg = to, string(msg)
+		return nil
+	}
+
+	outage := &domain.Outage{ID: uuid.New(), Title: "Card errors", Severity: "critical"}
+	due := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	reminder := service.UpdateReminder{
+		SLA: domain.UpdateSLA{
+			OutageID: outage.ID, IntervalSeconds: 1800,
+			LastUpdateAt: due.Add(-30 * time.Minute), DueAt: due, Overdue: true,
+		},
+		Team:   "payments",
+		Emails: []string{"bob@example.com"},
+	}
+	if err := n.NotifyUpdateOverdue(context.Background(), outage, reminder); err != nil {
+		t.Fatalf("NotifyUpdateOverdue: %v", err)
+	}
+
+	if len(gotTo) != 1 || gotTo[0] != "bob@example.com" {
+		t.Errorf("to = %v", gotTo)
+	}
+	headers, body, _ := strings.Cut(gotMsg, "\r\n\r\n")
+	if !strings.Contains(headers, "Subject: [critical] Status update overdue: Card errors") {
+		t.Errorf("headers = %q", headers)
+	}
+	if !strings.Contains(body, "every 30m0s") || !strings.Contains(body, "Mon, 02 Mar 2026 10:30:00 UTC") {
+		t.Errorf("body = %q", body)
+	}
+
+	// Outages without a team have no one to email
+	gotMsg = ""
+	reminder.Emails = nil
+	if err := n.NotifyUpdateOverdue(context.Background(), outage, reminder); err != nil || gotMsg != "" {
+		t.Errorf("no team: err %v, sent %q", err, gotMsg)
+	}
+}
+
+func TestNotifyWatchers(t *testing.T) {
+	n := NewNotifier(Config{Host: "smtp.example.com", From: "outalator@example.com"})
+	var (
+		gotTo  []string
+		gotMsg string
+	)
+	n.send = func(_ string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotTo, gotMsg = to, string(msg)
+		return nil
+	}
+
+	outage := &domain.Outage{ID: uuid.New(), Title: "Card errors", Severity: "high", Status: domain.StatusResolved}
+	emails := []string{"bob@example.com"}
+	if err := n.NotifyWatchers(context.Background(), service.WatchEvent{Outage: outage, PreviousStatus: domain.StatusOpen}, emails); err != nil {
+		t.Fatalf("NotifyWatchers: %v", err)
+	}
+	if len(gotTo) != 1 || gotTo[0] != "bob@example.com" {
+		t.Errorf("to = %v", gotTo)
+	}
+	headers, body, _ := strings.Cut(gotMsg, "\r\n\r\n")
+	if !strings.Contains(headers, "Subject: [high] Outage resolved: Card errors") {
+		t.Errorf("headers = %q", headers)
+	}
+	if !strings.Contains(body, "changed from open to resolved") || !strings.Contains(body, "/outages/"+outage.ID.String()+"/watch") {
+		t.Errorf("body = %q", body)
+	}
+
+	note := &domain.Note{Author: "alice", Content: "rolled back\nerrors recovering"}
+	if err := n.NotifyWatchers(context.Background(), service.WatchEvent{Outage: outage, Note: note}, emails); err != nil {
+		t.Fatalf("NotifyWatchers: %v", err)
+	}
+	headers, body, _ = strings.Cut(gotMsg, "\r\n\r\n")
+	if !strings.Contains(headers, "Subject: [high] alice added a note to outage: Card errors") || !strings.Contains(body, "> errors recovering") {
+		t.Errorf("note message = %q", gotMsg)
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	n := NewNotifier(Config{Host: "smtp.example.com", From: "outalator@example.com"})
+	var (
+		gotTo  []string
+		gotMsg string
+	)
+	n.send = func(_ string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotTo, gotMsg = to, string(msg)
+		return nil
+	}
+
+	msg := service.DigestMessage{
+		Subject: "Outage digest (payments, weekly): 1 open, 0 new",
+		Text:    "Open outages (1):\n• [high] Card errors\n",
+		Emails:  []string{"payments@example.com"},
+	}
+	if err := n.SendDigest(context.Background(), msg); err != nil {
+		t.Fatalf("SendDigest: %v", err)
+	}
+	if len(gotTo) != 1 || gotTo[0] != "payments@example.com" {
+		t.Errorf("to = %v", gotTo)
+	}
+	headers, body, _ := strings.Cut(gotMsg, "\r\n\r\n")
+	if !strings.Contains(headers, "Subject: Outage digest (payments, weekly)") {
+		t.Errorf("headers = %q", headers)
+	}
+	if body != "Open outages (1):\r\n• [high] Card errors\r\n" {
+		t.Errorf("body = %q, want CRLF line endings", body)
+	}
+
+	// Digests for Slack only are not emailed
+	gotMsg = ""
+	if err := n.SendDigest(context.Background(), service.DigestMessage{SlackChannel: "#payments"}); err != nil || gotMsg != "" {
+		t.Errorf("no emails: err %v, sent %q", err, gotMsg)
+	}
+}
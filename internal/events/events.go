@@ -0,0 +1,192 @@
+// Package events fans outage changes out to live subscribers, such as the
+// server-sent event stream used by web dashboards. The Broker is registered
+// with the service as an OutageListener; each subscriber gets its own
+// buffered channel of the events matching its filter.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Event types
+const (
+	TypeOutageCreated       = "outage.created"
+	TypeOutageStatusChanged = "outage.status_changed"
+	TypeOutageResolved      = "outage.resolved"
+	TypeNoteAdded           = "note.added"
+	TypeAlertAdded          = "alert.added"
+)
+
+// DefaultBufferSize is the number of events a subscriber may fall behind
+// before it is dropped
+const DefaultBufferSize = 64
+
+// teamTagKey is the outage tag naming the owning team, as set by routing rules
+const teamTagKey = "team"
+
+// Event is a change to an outage. Outage is a summary: its notes and alerts
+// are left out, and the note or alert an event is about is set on its own.
+type Event struct {
+	ID             uint64         `json:"id"` // Increases by one per event published
+	Type           string         `json:"type"`
+	Time           time.Time      `json:"time"`
+	Outage         *domain.Outage `json:"outage"`
+	Note           *domain.Note   `json:"note,omitempty"`
+	Alert          *domain.Alert  `json:"alert,omitempty"`
+	PreviousStatus string         `json:"previous_status,omitempty"`
+
+	teams []string // Teams the event concerns, for filtering
+}
+
+// Filter selects events for a subscriber. Empty fields match everything;
+// values are compared case-insensitively.
+type Filter struct {
+	Severities []string // Outage severities
+	Teams      []string // Matches the outage's team tags and its alerts' teams
+}
+
+// Match reports whether e passes the filter
+func (f Filter) Match(e Event) bool {
+	if len(f.Severities) > 0 && !containsFold(f.Severities, e.Outage.Severity) {
+		return false
+	}
+	if len(f.Teams) > 0 && !slices.ContainsFunc(e.teams, func(team string) bool { return containsFold(f.Teams, team) }) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, s string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// Broker publishes outage events to subscribers. It implements
+// service.OutageListener.
+type Broker struct {
+	mu         sync.Mutex
+	subs       map[chan Event]Filter
+	lastID     uint64
+	bufferSize int
+	logger     *slog.Logger
+}
+
+// NewBroker creates a broker whose subscribers buffer bufferSize events. A
+// zero bufferSize falls back to DefaultBufferSize.
+func NewBroker(bufferSize int, logger *slog.Logger) *Broker {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broker{
+		subs:       make(map[chan Event]Filter),
+		bufferSize: bufferSize,
+		logger:     logger,
+	}
+}
+
+// Subscribe returns a channel of the events matching filter and a function
+// that ends the subscription. The channel is closed when the subscription
+// ends, including when the subscriber falls too far behind and is dropped;
+// callers should then refetch whatever they display.
+func (b *Broker) Subscribe(filter Filter) (<-chan Event, func()) {
+	ch := make(chan Event, b.bufferSize)
+
+	b.mu.Lock()
+	b.subs[ch] = filter
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.removeLocked(ch)
+	}
+}
+
+// removeLocked ends a subscription if it is still active
+func (b *Broker) removeLocked(ch chan Event) {
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+func (b *Broker) publish(ctx context.Context, e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	e.ID = b.lastID
+	for ch, filter := range b.subs {
+		if !filter.Match(e) {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+			b.logger.WarnContext(ctx, "dropping slow event subscriber", "event_id", e.ID, "buffer_size", b.bufferSize)
+			b.removeLocked(ch)
+		}
+	}
+}
+
+// newEvent builds an event about outage, recording the teams it concerns
+// before the outage's notes and alerts are left out
+func newEvent(eventType string, outage *domain.Outage, alert *domain.Alert) Event {
+	var teams []string
+	for _, tag := range outage.Tags {
+		if tag.Key == teamTagKey {
+			teams = append(teams, tag.Value)
+		}
+	}
+	for _, a := range outage.Alerts {
+		teams = append(teams, a.Teams()...)
+	}
+	if alert != nil {
+		teams = append(teams, alert.Teams()...)
+	}
+
+	summary := *outage
+	summary.Notes = nil
+	summary.Alerts = nil
+	return Event{Type: eventType, Time: time.Now().UTC(), Outage: &summary, Alert: alert, teams: teams}
+}
+
+// OutageCreated implements service.OutageListener
+func (b *Broker) OutageCreated(ctx context.Context, outage *domain.Outage) error {
+	b.publish(ctx, newEvent(TypeOutageCreated, outage, nil))
+	return nil
+}
+
+// NoteAdded implements service.OutageListener
+func (b *Broker) NoteAdded(ctx context.Context, outage *domain.Outage, note *domain.Note) error {
+	e := newEvent(TypeNoteAdded, outage, nil)
+	e.Note = note
+	b.publish(ctx, e)
+	return nil
+}
+
+// AlertAdded implements service.OutageListener
+func (b *Broker) AlertAdded(ctx context.Context, outage *domain.Outage, alert *domain.Alert) error {
+	b.publish(ctx, newEvent(TypeAlertAdded, outage, alert))
+	return nil
+}
+
+// OutageStatusChanged implements service.OutageListener
+func (b *Broker) OutageStatusChanged(ctx context.Context, outage *domain.Outage, previous string) error {
+	e := newEvent(TypeOutageStatusChanged, outage, nil)
+	e.PreviousStatus = previous
+	b.publish(ctx, e)
+	return nil
+}
+
+// OutageResolved implements service.OutageListener
+func (b *Broker) OutageResolved(ctx context.Context, outage *domain.Outage) error {
+	b.publish(ctx, newEvent(TypeOutageResolved, outage, nil))
+	return nil
+}
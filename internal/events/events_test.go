@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/google/uuid"
+)
+
+func TestBrokerFilters(t *testing.T) {
+	b := NewBroker(0, logging.Discard())
+	ctx := context.Background()
+
+	all, cancelAll := b.Subscribe(Filter{})
+	defer cancelAll()
+	critical, cancelCritical := b.Subscribe(Filter{Severities: []string{"CRITICAL"}})
+	defer cancelCritical()
+	payments, cancelPayments := b.Subscribe(Filter{Teams: []string{"payments"}})
+	defer cancelPayments()
+
+	low := &domain.Outage{ID: uuid.New(), Severity: "low"}
+	routed := &domain.Outage{ID: uuid.New(), Severity: "critical", Tags: []domain.Tag{{Key: "team", Value: "Payments"}}}
+	_ = b.OutageCreated(ctx, low)
+	_ = b.OutageCreated(ctx, routed)
+	_ = b.AlertAdded(ctx, low, &domain.Alert{ID: uuid.New(), TeamName: "payments"})
+	_ = b.NoteAdded(ctx, routed, &domain.Note{ID: uuid.New(), Content: "looking"})
+
+	drain := func(ch <-chan Event) []string {
+		var got []string
+		for len(ch) > 0 {
+			e := <-ch
+			got = append(got, e.Type)
+		}
+		return got
+	}
+	tests := []struct {
+		name string
+		ch   <-chan Event
+		want []string
+	}{
+		{"all", all, []string{TypeOutageCreated, TypeOutageCreated, TypeAlertAdded, TypeNoteAdded}},
+		{"critical", critical, []string{TypeOutageCreated, TypeNoteAdded}},
+		{"payments", payments, []string{TypeOutageCreated, TypeAlertAdded, TypeNoteAdded}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := drain(tt.ch)
+			if len(got) != len(tt.want) {
+				t.Fatalf("events = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("events[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBrokerEventSummary(t *testing.T) {
+	b := NewBroker(0, logging.Discard())
+	ch, cancel := b.Subscribe(Filter{})
+	defer cancel()
+
+	outage := &domain.Outage{ID: uuid.New(), Notes: []domain.Note{{Content: "big"}}, Alerts: []domain.Alert{{Title: "a"}}}
+	_ = b.OutageStatusChanged(context.Background(), outage, "open")
+
+	e := <-ch
+	if e.ID != 1 || e.PreviousStatus != "open" || e.Outage.ID != outage.ID {
+		t.Errorf("event = %+v, want ID 1 from open for the outage", e)
+	}
+	if e.Outage.Notes != nil || e.Outage.Alerts != nil {
+		t.Error("event outage includes notes or alerts")
+	}
+	if len(outage.Notes) != 1 || len(outage.Alerts) != 1 {
+		t.Error("publishing modified the listener's outage")
+	}
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	b := NewBroker(2, logging.Discard())
+	ch, cancel := b.Subscribe(Filter{})
+	outage := &domain.Outage{ID: uuid.New()}
+
+	for range 3 {
+		_ = b.OutageResolved(context.Background(), outage)
+	}
+
+	received := 0
+	for range ch {
+		received++
+	}
+	if received != 2 {
+		t.Errorf("received %d events before the channel closed, want 2", received)
+	}
+	// Ending a dropped subscription is harmless
+	cancel()
+}
@@ -0,0 +1,61 @@
+// Package fingerprint identifies the monitor behind an alert, so repeated
+// alerts from the same flapping check can be counted together.
+//
+// An alert's title is normalized by lowercasing it and replacing the parts
+// that vary between firings of one monitor: IDs, IP addresses, host names
+// and numbers. "Disk 91% full on db-3.prod.example.com" and "Disk 97% full
+// on db-7.prod.example.com" both normalize to "disk <n>% full on <host>".
+// The fingerprint is a hash of the alert's source and normalized title.
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Replacements made when normalizing, in order. IDs and addresses go
+// before numbers so they are replaced whole. Host names need at least
+// three labels, so dotted metric names such as cpu.usage are kept.
+var replacements = []struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "<id>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b[a-z0-9][a-z0-9-]*(\.[a-z0-9-]+){2,}(:\d+)?\b`), "<host>"},
+}
+
+// hexID matches hex strings long enough to be IDs, such as commit hashes
+// and container IDs. Only those with a digit are replaced, so words like
+// "facade" are kept.
+var hexID = regexp.MustCompile(`\b(0x)?[0-9a-f]{6,}\b`)
+
+// number matches integers and decimals, including those inside words
+var number = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// Normalize returns title with the parts that vary between firings of the
+// same monitor replaced by placeholders and runs of whitespace collapsed
+func Normalize(title string) string {
+	s := strings.ToLower(title)
+	for _, r := range replacements {
+		s = r.pattern.ReplaceAllString(s, r.placeholder)
+	}
+	s = hexID.ReplaceAllStringFunc(s, func(id string) string {
+		if strings.ContainsAny(id, "0123456789") {
+			return "<id>"
+		}
+		return id
+	})
+	s = number.ReplaceAllString(s, "<n>")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Alert returns the fingerprint of an alert from source with the given
+// title: the first 16 hex digits of the SHA-256 of the source and the
+// normalized title
+func Alert(source, title string) string {
+	sum := sha256.Sum256([]byte(source + "\x00" + Normalize(title)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,40 @@
+package fingerprint
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Disk 91% full on db-3.prod.example.com", "disk <n>% full on <host>"},
+		{"  High   CPU on web-01 ", "high cpu on web-<n>"},
+		{"Connection refused to 10.0.4.12:5432", "connection refused to <ip>"},
+		{"Job 3f2c9a1e-8b7d-4c6e-9f10-2a3b4c5d6e7f failed", "job <id> failed"},
+		{"Pod checkout-7d9f8b6c5 crashlooping", "pod checkout-<id> crashlooping"},
+		{"Deploy of commit 9fceb02 failed", "deploy of commit <id> failed"},
+		{"cpu.usage above 0.95", "cpu.usage above <n>"},
+		{"Facade service degraded", "facade service degraded"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.title); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestAlert(t *testing.T) {
+	a := Alert("pagerduty", "Disk 91% full on db-3.prod.example.com")
+	if len(a) != 16 {
+		t.Errorf("fingerprint %q is not 16 hex digits", a)
+	}
+	if b := Alert("pagerduty", "Disk 97% full on db-7.prod.example.com"); b != a {
+		t.Errorf("fingerprints of one monitor differ: %s, %s", a, b)
+	}
+	if b := Alert("opsgenie", "Disk 91% full on db-3.prod.example.com"); b == a {
+		t.Error("fingerprints of different sources match")
+	}
+	if b := Alert("pagerduty", "Memory 91% used on db-3.prod.example.com"); b == a {
+		t.Error("fingerprints of different monitors match")
+	}
+}
@@ -0,0 +1,442 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Params is a GraphQL request
+type Params struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Result is the response to a GraphQL request. Data is left out of the
+// JSON response when the request failed before execution began.
+type Result struct {
+	Data   any
+	Errors []*Error
+
+	executed bool
+}
+
+// Executed reports whether execution began, as opposed to the request
+// failing to parse or validate
+func (r *Result) Executed() bool { return r.executed }
+
+// MarshalJSON encodes the result as a GraphQL response
+func (r *Result) MarshalJSON() ([]byte, error) {
+	resp := struct {
+		Errors []*Error `json:"errors,omitempty"`
+		Data   *any     `json:"data,omitempty"`
+	}{Errors: r.Errors}
+	if r.executed {
+		resp.Data = &r.Data
+	}
+	return json.Marshal(resp)
+}
+
+// Error is an error in a GraphQL response, located in the request and, for
+// errors raised while resolving a field, in the result
+type Error struct {
+	Message   string     `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+	Path      []any      `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Location is a position in a request document
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// object is a result object, which keeps its fields in the order they were
+// requested
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *object) set(key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// MarshalJSON encodes the object with its fields in order
+func (o *object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		b.Write(key)
+		b.WriteByte(':')
+		v, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// Execute parses, validates and executes a request against the schema.
+// Fields are resolved one at a time, in the order they were requested.
+func Execute(ctx context.Context, schema *Schema, params Params) *Result {
+	doc, err := parse(params.Query)
+	if err != nil {
+		return requestError(err)
+	}
+	op, err := doc.operation(params.OperationName)
+	if err != nil {
+		return requestError(err)
+	}
+	if errs := validate(schema, doc); len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+
+	root := schema.Query
+	if op.kind == "mutation" {
+		root = schema.Mutation
+	}
+	vars, errs := coerceVariables(schema, op, params.Variables)
+	if len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+
+	e := &executor{schema: schema, doc: doc, vars: vars}
+	data, ok := e.executeSelections(ctx, root, nil, op.selections, nil)
+	result := &Result{Errors: e.errors, executed: true}
+	if ok {
+		result.Data = data
+	}
+	return result
+}
+
+// requestError is the result of a request that could not be executed
+func requestError(err error) *Result {
+	gqlErr := &Error{Message: err.Error()}
+	if se, ok := err.(*SyntaxError); ok {
+		gqlErr.Locations = []Location{{Line: se.Line, Column: se.Col}}
+	}
+	return &Result{Errors: []*Error{gqlErr}}
+}
+
+// operation returns the operation to execute: the one named, or the only
+// one in the document
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, fmt.Errorf("Must provide operation name if query contains multiple operations")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown operation named %q", name)
+}
+
+// coerceVariables converts the request's variables to the types the
+// operation declares, applying defaults
+func coerceVariables(schema *Schema, op *operation, given map[string]any) (map[string]any, []*Error) {
+	vars := make(map[string]any, len(op.vars))
+	var errs []*Error
+	for _, def := range op.vars {
+		t := schema.resolveTypeRef(def.typ)
+		v, present := given[def.name]
+		if !present {
+			switch {
+			case def.def != nil:
+				c, _, err := coerceLiteral(t, def.def, map[string]any{})
+				if err != nil {
+					errs = append(errs, &Error{Message: fmt.Sprintf("Variable \"$%s\" has invalid default value: %v", def.name, err)})
+				}
+				vars[def.name] = c
+			case def.typ.nonNull:
+				errs = append(errs, &Error{Message: fmt.Sprintf("Variable \"$%s\" of required type %q was not provided.", def.name, def.typ)})
+			}
+			continue
+		}
+		c, err := coerceVariable(t, v)
+		if err != nil {
+			errs = append(errs, &Error{Message: fmt.Sprintf("Variable \"$%s\" got invalid value %s; %v", def.name, printJSON(v), err)})
+			continue
+		}
+		vars[def.name] = c
+	}
+	return vars, errs
+}
+
+// resolveTypeRef returns the schema type a variable definition names. The
+// validator has checked the named type exists.
+func (s *Schema) resolveTypeRef(ref *typeRef) Type {
+	var t Type
+	if ref.list != nil {
+		t = &List{Of: s.resolveTypeRef(ref.list)}
+	} else {
+		t = s.types[ref.name]
+	}
+	if ref.nonNull {
+		t = &NonNull{Of: t}
+	}
+	return t
+}
+
+// executor executes a validated operation
+type executor struct {
+	schema *Schema
+	doc    *document
+	vars   map[string]any
+	errors []*Error
+}
+
+// fieldError records an error raised while resolving the field at path
+func (e *executor) fieldError(node *fieldNode, path []any, err error) {
+	e.errors = append(e.errors, &Error{
+		Message:   err.Error(),
+		Locations: []Location{{Line: node.line, Column: node.col}},
+		Path:      append([]any(nil), path...),
+	})
+}
+
+// groupedFields is the fields of a selection set grouped by response key,
+// in the order the keys first appear
+type groupedFields struct {
+	keys   []string
+	fields map[string][]*fieldNode
+}
+
+// collectFields gathers the fields selected on obj, expanding fragments
+// and applying @skip and @include
+func (e *executor) collectFields(obj *Object, sels []selection, visited map[string]bool, g *groupedFields) {
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			if !e.included(sel.directives) {
+				continue
+			}
+			key := sel.responseKey()
+			if _, ok := g.fields[key]; !ok {
+				g.keys = append(g.keys, key)
+			}
+			g.fields[key] = append(g.fields[key], sel)
+		case *inlineFragment:
+			if !e.included(sel.directives) || (sel.typeCond != "" && sel.typeCond != obj.Name) {
+				continue
+			}
+			e.collectFields(obj, sel.selections, visited, g)
+		case *fragmentSpread:
+			if !e.included(sel.directives) || visited[sel.name] {
+				continue
+			}
+			visited[sel.name] = true
+			frag := e.doc.fragments[sel.name]
+			if frag.typeCond != obj.Name {
+				continue
+			}
+			e.collectFields(obj, frag.selections, visited, g)
+		}
+	}
+}
+
+// included applies the @skip and @include directives
+func (e *executor) included(dirs []*directive) bool {
+	for _, d := range dirs {
+		if d.name != "skip" && d.name != "include" {
+			continue
+		}
+		cond := false
+		for _, a := range d.args {
+			if a.name == "if" {
+				v, _, _ := coerceLiteral(&NonNull{Of: Boolean}, a.val, e.vars)
+				cond, _ = v.(bool)
+			}
+		}
+		if d.name == "skip" && cond || d.name == "include" && !cond {
+			return false
+		}
+	}
+	return true
+}
+
+// executeSelections resolves the fields selected on obj for source. It
+// returns false when a non-null field is null, making the whole object
+// null.
+func (e *executor) executeSelections(ctx context.Context, obj *Object, source any, sels []selection, path []any) (*object, bool) {
+	g := &groupedFields{fields: map[string][]*fieldNode{}}
+	e.collectFields(obj, sels, map[string]bool{}, g)
+
+	result := &object{values: make(map[string]any, len(g.keys))}
+	for _, key := range g.keys {
+		nodes := g.fields[key]
+		fieldPath := append(path[:len(path):len(path)], key)
+		v, ok := e.executeField(ctx, obj, source, nodes, fieldPath)
+		if !ok {
+			return nil, false
+		}
+		result.set(key, v)
+	}
+	return result, true
+}
+
+// executeField resolves one response key of obj. It returns false when
+// the field is non-null but resolved to null.
+func (e *executor) executeField(ctx context.Context, obj *Object, source any, nodes []*fieldNode, path []any) (any, bool) {
+	node := nodes[0]
+	if node.name == "__typename" {
+		return obj.Name, true
+	}
+	field := e.schema.fieldDef(obj, node.name)
+
+	args, err := e.argumentValues(field.Args, node.args)
+	if err != nil {
+		e.fieldError(node, path, err)
+		return nil, isNullable(field.Type)
+	}
+
+	var v any
+	if field.Resolve != nil {
+		v, err = field.Resolve(ctx, source, args)
+	} else if m, ok := source.(map[string]any); ok {
+		v = m[field.Name]
+	}
+	if err != nil {
+		e.fieldError(node, path, err)
+		return nil, isNullable(field.Type)
+	}
+	return e.completeValue(ctx, field.Type, nodes, v, path)
+}
+
+// argumentValues coerces the arguments given to a field, applying defaults
+func (e *executor) argumentValues(defs []*Argument, given []*argNode) (map[string]any, error) {
+	args := make(map[string]any, len(defs))
+	for _, def := range defs {
+		var node *argNode
+		for _, a := range given {
+			if a.name == def.Name {
+				node = a
+				break
+			}
+		}
+
+		present := false
+		if node != nil {
+			v, ok, err := coerceLiteral(def.Type, node.val, e.vars)
+			if err != nil {
+				return nil, fmt.Errorf("Argument %q has invalid value %s: %v", def.Name, printLiteral(node.val), err)
+			}
+			if ok {
+				args[def.Name] = v
+				present = true
+			}
+		}
+		if !present {
+			switch {
+			case def.Default != nil:
+				args[def.Name] = def.Default
+			case !isNullable(def.Type):
+				return nil, fmt.Errorf("Argument %q of required type %q was not provided", def.Name, def.Type)
+			}
+		}
+	}
+	return args, nil
+}
+
+// completeValue converts a resolved value to its result for type t. It
+// returns false when a non-null value is null, which the nearest nullable
+// field or list item above then becomes.
+func (e *executor) completeValue(ctx context.Context, t Type, nodes []*fieldNode, v any, path []any) (any, bool) {
+	if nn, ok := t.(*NonNull); ok {
+		result, ok := e.completeNullable(ctx, nn.Of, nodes, v, path)
+		if !ok {
+			return nil, false
+		}
+		if result == nil {
+			e.fieldError(nodes[0], path, fmt.Errorf("Cannot return null for non-nullable field"))
+			return nil, false
+		}
+		return result, true
+	}
+	result, ok := e.completeNullable(ctx, t, nodes, v, path)
+	if !ok {
+		return nil, true
+	}
+	return result, true
+}
+
+func (e *executor) completeNullable(ctx context.Context, t Type, nodes []*fieldNode, v any, path []any) (any, bool) {
+	if isNil(v) {
+		return nil, true
+	}
+
+	switch t := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fieldError(nodes[0], path, fmt.Errorf("Expected a list, got %T", v))
+			return nil, false
+		}
+		items := make([]any, rv.Len())
+		for i := range items {
+			item, ok := e.completeValue(ctx, t.Of, nodes, rv.Index(i).Interface(), append(path[:len(path):len(path)], i))
+			if !ok {
+				return nil, false
+			}
+			items[i] = item
+		}
+		return items, true
+	case *Scalar:
+		s, err := t.Serialize(v)
+		if err != nil {
+			e.fieldError(nodes[0], path, err)
+			return nil, false
+		}
+		return s, true
+	case *Enum:
+		s := fmt.Sprint(v)
+		if !t.has(s) {
+			e.fieldError(nodes[0], path, fmt.Errorf("Enum %q cannot represent value %q", t.Name, s))
+			return nil, false
+		}
+		return s, true
+	case *Object:
+		var sels []selection
+		for _, n := range nodes {
+			sels = append(sels, n.selections...)
+		}
+		return e.executeSelections(ctx, t, v, sels, path)
+	}
+	e.fieldError(nodes[0], path, fmt.Errorf("%q is not an output type", t))
+	return nil, false
+}
+
+func isNullable(t Type) bool {
+	_, nonNull := t.(*NonNull)
+	return !nonNull
+}
+
+// isNil reports whether v is nil or a nil pointer, slice, map or interface
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
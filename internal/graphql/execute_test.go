@@ -0,0 +1,320 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testSchema is a small library schema exercising every kind of type
+func testSchema(t *testing.T) *Schema {
+	t.Helper()
+	genre := &Enum{Name: "Genre", Values: []EnumValue{{Name: "FICTION"}, {Name: "HISTORY"}}}
+	author := &Object{Name: "Author", Fields: []*Field{
+		{Name: "name", Type: nonNull(String)},
+	}}
+	book := &Object{Name: "Book", Fields: []*Field{
+		{Name: "title", Type: nonNull(String)},
+		{Name: "genre", Type: genre},
+		{Name: "pages", Type: Int},
+		{Name: "author", Type: nonNull(author)},
+		{Name: "reviews", Type: &List{Of: nonNull(String)}},
+	}}
+	books := []any{
+		map[string]any{"title": "Dune", "genre": "FICTION", "pages": 412, "author": map[string]any{"name": "Herbert"}},
+		map[string]any{"title": "SPQR", "genre": "HISTORY", "pages": 608, "author": map[string]any{"name": "Beard"}},
+		map[string]any{"title": "Anonymous", "author": nil},
+		map[string]any{"title": "Mixed", "author": map[string]any{"name": "X"}, "reviews": []any{"good", nil}},
+	}
+	bookFilter := &InputObject{Name: "BookFilter", Fields: []*Argument{
+		{Name: "genre", Type: genre},
+		{Name: "minPages", Type: Int, Default: 0},
+	}}
+
+	query := &Object{Name: "Query", Fields: []*Field{
+		{
+			Name: "books",
+			Type: &List{Of: book},
+			Args: []*Argument{
+				{Name: "filter", Type: bookFilter},
+				{Name: "first", Type: Int, Default: 2},
+			},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				var matched []any
+				filter, _ := args["filter"].(map[string]any)
+				for _, b := range books[:2] {
+					b := b.(map[string]any)
+					if g, ok := filter["genre"]; ok && g != nil && g != b["genre"] {
+						continue
+					}
+					if p, ok := filter["minPages"].(int); ok && b["pages"].(int) < p {
+						continue
+					}
+					matched = append(matched, b)
+				}
+				return matched[:min(args["first"].(int), len(matched))], nil
+			},
+		},
+		{
+			Name: "book",
+			Type: book,
+			Args: []*Argument{{Name: "title", Type: nonNull(String)}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				for _, b := range books {
+					if b.(map[string]any)["title"] == args["title"] {
+						return b, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name: "fail",
+			Type: String,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return nil, errors.New("boom")
+			},
+		},
+		{
+			Name: "failRequired",
+			Type: nonNull(String),
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return nil, errors.New("boom")
+			},
+		},
+	}}
+	mutation := &Object{Name: "Mutation", Fields: []*Field{
+		{
+			Name: "echo",
+			Type: nonNull(String),
+			Args: []*Argument{{Name: "text", Type: nonNull(String)}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return args["text"], nil
+			},
+		},
+	}}
+	schema, err := NewSchema(query, mutation)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	return schema
+}
+
+// run executes a request and returns its JSON response
+func run(t *testing.T, schema *Schema, query string, vars map[string]any) string {
+	t.Helper()
+	b, err := json.Marshal(Execute(context.Background(), schema, Params{Query: query, Variables: vars}))
+	if err != nil {
+		t.Fatalf("marshal result: %v", err)
+	}
+	return string(b)
+}
+
+func TestExecute(t *testing.T) {
+	schema := testSchema(t)
+
+	tests := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  string
+	}{
+		{
+			name:  "nested selection in request order",
+			query: `{ books { title author { name } } }`,
+			want:  `{"data":{"books":[{"title":"Dune","author":{"name":"Herbert"}},{"title":"SPQR","author":{"name":"Beard"}}]}}`,
+		},
+		{
+			name:  "aliases and typename",
+			query: `{ first: book(title: "Dune") { __typename t: title } second: book(title: "SPQR") { title } }`,
+			want:  `{"data":{"first":{"__typename":"Book","t":"Dune"},"second":{"title":"SPQR"}}}`,
+		},
+		{
+			name:  "input object and enum arguments",
+			query: `{ books(filter: {genre: HISTORY}) { title genre } }`,
+			want:  `{"data":{"books":[{"title":"SPQR","genre":"HISTORY"}]}}`,
+		},
+		{
+			name:  "variables with defaults",
+			query: `query ($min: Int = 500, $n: Int) { books(filter: {minPages: $min}, first: $n) { title } }`,
+			vars:  map[string]any{"n": json.Number("1")},
+			want:  `{"data":{"books":[{"title":"SPQR"}]}}`,
+		},
+		{
+			name:  "input object variable",
+			query: `query ($f: BookFilter) { books(filter: $f) { title } }`,
+			vars:  map[string]any{"f": map[string]any{"genre": "FICTION"}},
+			want:  `{"data":{"books":[{"title":"Dune"}]}}`,
+		},
+		{
+			name:  "fragments merge",
+			query: `{ book(title: "Dune") { ...T ... on Book { pages } author { name } ... @skip(if: true) { genre } } } fragment T on Book { title author { __typename } }`,
+			want:  `{"data":{"book":{"title":"Dune","author":{"__typename":"Author","name":"Herbert"},"pages":412}}}`,
+		},
+		{
+			name:  "include and skip",
+			query: `query ($yes: Boolean!) { book(title: "Dune") { title @include(if: $yes) pages @skip(if: $yes) } }`,
+			vars:  map[string]any{"yes": true},
+			want:  `{"data":{"book":{"title":"Dune"}}}`,
+		},
+		{
+			name:  "resolver error on nullable field",
+			query: `{ fail book(title: "Dune") { title } }`,
+			want:  `{"errors":[{"message":"boom","locations":[{"line":1,"column":3}],"path":["fail"]}],"data":{"fail":null,"book":{"title":"Dune"}}}`,
+		},
+		{
+			name:  "null propagates to the nearest nullable field",
+			query: `{ book(title: "Anonymous") { title author { name } } }`,
+			want:  `{"errors":[{"message":"Cannot return null for non-nullable field","locations":[{"line":1,"column":36}],"path":["book","author"]}],"data":{"book":null}}`,
+		},
+		{
+			name:  "null list item propagates to the list",
+			query: `{ book(title: "Mixed") { reviews } }`,
+			want:  `{"errors":[{"message":"Cannot return null for non-nullable field","locations":[{"line":1,"column":26}],"path":["book","reviews",1]}],"data":{"book":{"reviews":null}}}`,
+		},
+		{
+			name:  "non-null root error nulls data",
+			query: `{ failRequired }`,
+			want:  `{"errors":[{"message":"boom","locations":[{"line":1,"column":3}],"path":["failRequired"]}],"data":null}`,
+		},
+		{
+			name:  "mutation",
+			query: `mutation { echo(text: "hi") }`,
+			want:  `{"data":{"echo":"hi"}}`,
+		},
+		{
+			name:  "syntax error",
+			query: `{ books { title }`,
+			want:  `{"errors":[{"message":"Syntax Error: unexpected \u003cEOF\u003e (line 1, column 18)","locations":[{"line":1,"column":18}]}]}`,
+		},
+		{
+			name:  "missing required variable",
+			query: `query ($t: String!) { book(title: $t) { title } }`,
+			want:  `{"errors":[{"message":"Variable \"$t\" of required type \"String!\" was not provided."}]}`,
+		},
+		{
+			name:  "invalid variable",
+			query: `query ($n: Int) { books(first: $n) { title } }`,
+			vars:  map[string]any{"n": "two"},
+			want:  `{"errors":[{"message":"Variable \"$n\" got invalid value \"two\"; Int cannot represent two"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := run(t, schema, tt.query, tt.vars); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := testSchema(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"unknown field", `{ books { isbn } }`, `Cannot query field "isbn" on type "Book".`},
+		{"missing subselection", `{ books }`, `Field "books" of type "[Book]" must have a selection of subfields.`},
+		{"subselection on leaf", `{ books { title { x } } }`, `Field "title" must not have a selection since type "String!" has no subfields.`},
+		{"unknown argument", `{ books(last: 1) { title } }`, `Unknown argument "last" on field "Query.books".`},
+		{"missing argument", `{ book { title } }`, `Argument "title" of type "String!" is required on field "Query.book", but it was not provided.`},
+		{"invalid literal", `{ books(first: "2") { title } }`, `Argument "first" has invalid value "2": Int cannot represent 2`},
+		{"invalid enum", `{ books(filter: {genre: POETRY}) { title } }`, `Argument "filter" has invalid value {genre: POETRY}: in field "genre": Value POETRY does not exist in "Genre" enum`},
+		{"undefined variable", `{ books(first: $n) { title } }`, `Variable "$n" is not defined.`},
+		{"unused variable", `query Q($n: Int) { books { title } }`, `Variable "$n" is never used in operation "Q".`},
+		{"variable type mismatch", `query ($t: String) { book(title: $t) { title } }`, `Variable "$t" of type "String" used in position expecting type "String!".`},
+		{"unknown fragment", `{ books { ...Missing } }`, `Unknown fragment "Missing".`},
+		{"unused fragment", `{ books { title } } fragment F on Book { title }`, `Fragment "F" is never used.`},
+		{"impossible spread", `{ books { ... on Author { name } } }`, `Fragment cannot be spread here as objects of type "Book" can never be of type "Author".`},
+		{"fragment cycle", `{ books { ...A } } fragment A on Book { ...B } fragment B on Book { ...A }`, `Cannot spread fragment "A" within itself.`},
+		{"unknown directive", `{ books @live { title } }`, `Unknown directive "@live".`},
+		{"conflicting fields", `{ books { x: title x: pages } }`, `Fields "x" conflict because "title" and "pages" are different fields.`},
+		{"subscription", `subscription { books { title } }`, `Schema is not configured for subscriptions.`},
+		{"anonymous with others", `{ books { title } } query Q { books { title } }`, `This anonymous operation must be the only defined operation.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parse(tt.query)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			errs := validate(schema, doc)
+			if len(errs) == 0 {
+				t.Fatalf("validate found no errors, want %q", tt.want)
+			}
+			if errs[0].Message != tt.want {
+				t.Errorf("error = %q, want %q", errs[0].Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	schema := testSchema(t)
+	query := `query A { book(title: "Dune") { title } } query B { book(title: "SPQR") { title } }`
+
+	result := Execute(context.Background(), schema, Params{Query: query, OperationName: "B"})
+	b, _ := json.Marshal(result)
+	if want := `{"data":{"book":{"title":"SPQR"}}}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	result = Execute(context.Background(), schema, Params{Query: query})
+	if result.Executed() || len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "Must provide operation name") {
+		t.Errorf("without operation name got %+v", result.Errors)
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	schema := testSchema(t)
+
+	got := run(t, schema, `{ __type(name: "Book") { kind name fields { name type { kind name ofType { name } } } } }`, nil)
+	want := `{"data":{"__type":{"kind":"OBJECT","name":"Book","fields":[` +
+		`{"name":"title","type":{"kind":"NON_NULL","name":null,"ofType":{"name":"String"}}},` +
+		`{"name":"genre","type":{"kind":"ENUM","name":"Genre","ofType":null}},` +
+		`{"name":"pages","type":{"kind":"SCALAR","name":"Int","ofType":null}},` +
+		`{"name":"author","type":{"kind":"NON_NULL","name":null,"ofType":{"name":"Author"}}},` +
+		`{"name":"reviews","type":{"kind":"LIST","name":null,"ofType":{"name":null}}}]}}}`
+	if got != want {
+		t.Errorf("__type got  %s\nwant %s", got, want)
+	}
+
+	got = run(t, schema, `{ __type(name: "BookFilter") { inputFields { name defaultValue } } }`, nil)
+	want = `{"data":{"__type":{"inputFields":[{"name":"genre","defaultValue":null},{"name":"minPages","defaultValue":"0"}]}}}`
+	if got != want {
+		t.Errorf("input fields got  %s\nwant %s", got, want)
+	}
+
+	var resp struct {
+		Data struct {
+			Schema struct {
+				QueryType    struct{ Name string }
+				MutationType struct{ Name string }
+				Types        []struct{ Name string }
+				Directives   []struct{ Name string }
+			} `json:"__schema"`
+		}
+	}
+	got = run(t, schema, `{ __schema { queryType { name } mutationType { name } types { name } directives { name } } }`, nil)
+	if err := json.Unmarshal([]byte(got), &resp); err != nil {
+		t.Fatalf("decode %s: %v", got, err)
+	}
+	s := resp.Data.Schema
+	if s.QueryType.Name != "Query" || s.MutationType.Name != "Mutation" || len(s.Directives) != 2 {
+		t.Errorf("__schema = %s", got)
+	}
+	names := map[string]bool{}
+	for _, typ := range s.Types {
+		names[typ.Name] = true
+	}
+	for _, name := range []string{"Query", "Mutation", "Book", "Author", "Genre", "BookFilter", "String", "__Type"} {
+		if !names[name] {
+			t.Errorf("__schema types missing %s", name)
+		}
+	}
+}
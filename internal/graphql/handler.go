@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/conall/outalator/internal/apierr"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/bodylimit"
+)
+
+// serveHTTP executes a request sent as a GET with query, operationName and
+// variables query parameters, or as a POST with a JSON body. Mutations
+// are only accepted over POST, so a link cannot change anything, and only
+// from callers the auth policy has not let in to read anonymously.
+func serveHTTP(w http.ResponseWriter, r *http.Request, schema *Schema) {
+	var params Params
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		params.Query = q.Get("query")
+		params.OperationName = q.Get("operationName")
+		if vars := q.Get("variables"); vars != "" {
+			if err := decodeJSON(strings.NewReader(vars), &params.Variables); err != nil {
+				apierr.Write(w, http.StatusBadRequest, "Invalid variables parameter")
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := decodeJSON(r.Body, &params); err != nil {
+			if bodylimit.TooLarge(err) {
+				bodylimit.RespondTooLarge(w)
+				return
+			}
+			apierr.Write(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		apierr.Write(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if params.Query == "" {
+		apierr.Write(w, http.StatusBadRequest, "Missing query")
+		return
+	}
+
+	readOnly := auth.ReadOnly(r.Context())
+	if r.Method == http.MethodGet || readOnly {
+		if doc, err := parse(params.Query); err == nil {
+			if op, err := doc.operation(params.OperationName); err == nil && op.kind != "query" {
+				if readOnly {
+					apierr.Write(w, http.StatusUnauthorized, "Sign in to run mutations")
+					return
+				}
+				w.Header().Set("Allow", "POST")
+				apierr.Write(w, http.StatusMethodNotAllowed, "Mutations must be sent with POST")
+				return
+			}
+		}
+	}
+
+	result := Execute(r.Context(), schema, params)
+	status := http.StatusOK
+	if !result.Executed() {
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// decodeJSON decodes a request, keeping numbers as json.Number so Int
+// variables keep their precision
+func decodeJSON(body io.Reader, v any) error {
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,240 @@
+package graphql
+
+import (
+	"context"
+)
+
+// directiveDef describes a directive the executor supports, for
+// introspection
+type directiveDef struct {
+	name        string
+	description string
+	args        []*Argument
+}
+
+var directiveDefs = []directiveDef{
+	{"include", "Directs the executor to include this field or fragment only when the `if` argument is true.", knownDirectives["include"]},
+	{"skip", "Directs the executor to skip this field or fragment when the `if` argument is true.", knownDirectives["skip"]},
+}
+
+// fieldDef returns the field of obj named name, including the
+// introspection fields of the query type
+func (s *Schema) fieldDef(obj *Object, name string) *Field {
+	if obj == s.Query {
+		switch name {
+		case "__schema":
+			return s.schemaField
+		case "__type":
+			return s.typeField
+		}
+	}
+	return obj.field(name)
+}
+
+// addIntrospection adds the introspection types and the __schema and
+// __type fields of the query type
+func (s *Schema) addIntrospection() error {
+	typeKind := &Enum{Name: "__TypeKind", Values: []EnumValue{
+		{Name: "SCALAR"}, {Name: "OBJECT"}, {Name: "INTERFACE"}, {Name: "UNION"},
+		{Name: "ENUM"}, {Name: "INPUT_OBJECT"}, {Name: "LIST"}, {Name: "NON_NULL"},
+	}}
+	directiveLocation := &Enum{Name: "__DirectiveLocation", Values: []EnumValue{
+		{Name: "QUERY"}, {Name: "MUTATION"}, {Name: "SUBSCRIPTION"}, {Name: "FIELD"},
+		{Name: "FRAGMENT_DEFINITION"}, {Name: "FRAGMENT_SPREAD"}, {Name: "INLINE_FRAGMENT"},
+	}}
+
+	typeObj := &Object{Name: "__Type"}
+	fieldObj := &Object{Name: "__Field"}
+	inputValue := &Object{Name: "__InputValue"}
+	enumValue := &Object{Name: "__EnumValue"}
+	directiveObj := &Object{Name: "__Directive"}
+	schemaObj := &Object{Name: "__Schema"}
+
+	includeDeprecated := []*Argument{{Name: "includeDeprecated", Type: Boolean, Default: false}}
+
+	schemaObj.Fields = []*Field{
+		{Name: "description", Type: String, Resolve: constant(nil)},
+		{Name: "types", Type: listOf(typeObj), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			types := make([]Type, len(s.typeOrder))
+			for i, name := range s.typeOrder {
+				types[i] = s.types[name]
+			}
+			return types, nil
+		}},
+		{Name: "queryType", Type: nonNull(typeObj), Resolve: constant(s.Query)},
+		{Name: "mutationType", Type: typeObj, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			if s.Mutation == nil {
+				return nil, nil
+			}
+			return s.Mutation, nil
+		}},
+		{Name: "subscriptionType", Type: typeObj, Resolve: constant(nil)},
+		{Name: "directives", Type: listOf(directiveObj), Resolve: constant(directiveDefs)},
+	}
+
+	typeObj.Fields = []*Field{
+		{Name: "kind", Type: nonNull(typeKind), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			switch source.(type) {
+			case *Scalar:
+				return "SCALAR", nil
+			case *Object:
+				return "OBJECT", nil
+			case *Enum:
+				return "ENUM", nil
+			case *InputObject:
+				return "INPUT_OBJECT", nil
+			case *List:
+				return "LIST", nil
+			}
+			return "NON_NULL", nil
+		}},
+		{Name: "name", Type: String, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return optional(typeName(source.(Type))), nil
+		}},
+		{Name: "description", Type: String, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			switch t := source.(type) {
+			case *Scalar:
+				return optional(t.Description), nil
+			case *Object:
+				return optional(t.Description), nil
+			case *Enum:
+				return optional(t.Description), nil
+			case *InputObject:
+				return optional(t.Description), nil
+			}
+			return nil, nil
+		}},
+		{Name: "specifiedByURL", Type: String, Resolve: constant(nil)},
+		{Name: "fields", Type: &List{Of: nonNull(fieldObj)}, Args: includeDeprecated, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			if t, ok := source.(*Object); ok {
+				return t.Fields, nil
+			}
+			return nil, nil
+		}},
+		{Name: "interfaces", Type: &List{Of: nonNull(typeObj)}, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			if _, ok := source.(*Object); ok {
+				return []Type{}, nil
+			}
+			return nil, nil
+		}},
+		{Name: "possibleTypes", Type: &List{Of: nonNull(typeObj)}, Resolve: constant(nil)},
+		{Name: "enumValues", Type: &List{Of: nonNull(enumValue)}, Args: includeDeprecated, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			if t, ok := source.(*Enum); ok {
+				return t.Values, nil
+			}
+			return nil, nil
+		}},
+		{Name: "inputFields", Type: &List{Of: nonNull(inputValue)}, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			if t, ok := source.(*InputObject); ok {
+				return t.Fields, nil
+			}
+			return nil, nil
+		}},
+		{Name: "ofType", Type: typeObj, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			switch t := source.(type) {
+			case *List:
+				return t.Of, nil
+			case *NonNull:
+				return t.Of, nil
+			}
+			return nil, nil
+		}},
+	}
+
+	fieldObj.Fields = []*Field{
+		{Name: "name", Type: nonNull(String), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(*Field).Name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return optional(source.(*Field).Description), nil
+		}},
+		{Name: "args", Type: listOf(inputValue), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(*Field).Args, nil
+		}},
+		{Name: "type", Type: nonNull(typeObj), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(*Field).Type, nil
+		}},
+		{Name: "isDeprecated", Type: nonNull(Boolean), Resolve: constant(false)},
+		{Name: "deprecationReason", Type: String, Resolve: constant(nil)},
+	}
+
+	inputValue.Fields = []*Field{
+		{Name: "name", Type: nonNull(String), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(*Argument).Name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return optional(source.(*Argument).Description), nil
+		}},
+		{Name: "type", Type: nonNull(typeObj), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(*Argument).Type, nil
+		}},
+		{Name: "defaultValue", Type: String, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			a := source.(*Argument)
+			if a.Default == nil {
+				return nil, nil
+			}
+			return printValue(a.Type, a.Default), nil
+		}},
+	}
+
+	enumValue.Fields = []*Field{
+		{Name: "name", Type: nonNull(String), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(EnumValue).Name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return optional(source.(EnumValue).Description), nil
+		}},
+		{Name: "isDeprecated", Type: nonNull(Boolean), Resolve: constant(false)},
+		{Name: "deprecationReason", Type: String, Resolve: constant(nil)},
+	}
+
+	directiveObj.Fields = []*Field{
+		{Name: "name", Type: nonNull(String), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(directiveDef).name, nil
+		}},
+		{Name: "description", Type: String, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return optional(source.(directiveDef).description), nil
+		}},
+		{Name: "locations", Type: listOf(directiveLocation), Resolve: constant([]string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"})},
+		{Name: "args", Type: listOf(inputValue), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			return source.(directiveDef).args, nil
+		}},
+		{Name: "isRepeatable", Type: nonNull(Boolean), Resolve: constant(false)},
+	}
+
+	s.schemaField = &Field{
+		Name:        "__schema",
+		Description: "Access the current type schema of this server.",
+		Type:        nonNull(schemaObj),
+		Resolve:     constant(s),
+	}
+	s.typeField = &Field{
+		Name:        "__type",
+		Description: "Request the type information of a single type.",
+		Type:        typeObj,
+		Args:        []*Argument{{Name: "name", Type: nonNull(String)}},
+		Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			t, ok := s.types[args["name"].(string)]
+			if !ok {
+				return nil, nil
+			}
+			return t, nil
+		},
+	}
+	return s.addType(schemaObj)
+}
+
+// constant returns a resolver that always resolves to v
+func constant(v any) ResolveFunc {
+	return func(ctx context.Context, source any, args map[string]any) (any, error) {
+		return v, nil
+	}
+}
+
+// optional returns s, or nil when s is empty so that it resolves to null
+func optional(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,476 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/apierr"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Outage listing limits, matching the REST API
+const (
+	defaultLimit = 50
+	maxLimit     = 100
+)
+
+// Handler serves the Outalator GraphQL API at /graphql
+type Handler struct {
+	service *service.Service
+	logger  *slog.Logger
+	schema  *Schema
+}
+
+// NewHandler creates a GraphQL handler over svc
+func NewHandler(svc *service.Service, logger *slog.Logger) *Handler {
+	h := &Handler{service: svc, logger: logger}
+	types := h.objectTypes()
+	schema, err := NewSchema(h.queryType(types), h.mutationType(types))
+	if err != nil {
+		// The schema is fixed, so this is a programming error
+		panic(err)
+	}
+	h.schema = schema
+	return h
+}
+
+// RegisterHandlers registers the /graphql route
+func (h *Handler) RegisterHandlers(router interface {
+	HandleFunc(path string, f func(http.ResponseWriter, *http.Request)) *mux.Route
+}) {
+	router.HandleFunc("/graphql", h.ServeHTTP).Methods("GET", "POST")
+}
+
+// ServeHTTP handles GET and POST /graphql
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveHTTP(w, r, h.schema)
+}
+
+// Time is an RFC 3339 timestamp
+var Time = &Scalar{
+	Name:        "Time",
+	Description: "An RFC 3339 timestamp",
+	Serialize: func(v any) (any, error) {
+		switch t := v.(type) {
+		case time.Time:
+			return t.UTC().Format(time.RFC3339Nano), nil
+		case *time.Time:
+			return t.UTC().Format(time.RFC3339Nano), nil
+		}
+		return nil, fmt.Errorf("Time cannot represent %v", v)
+	},
+	Parse: func(v any) (any, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("Time cannot represent %v", v)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("Time cannot represent %q: expected an RFC 3339 timestamp", s)
+		}
+		return t, nil
+	},
+}
+
+var outageStatus = &Enum{
+	Name:        "OutageStatus",
+	Description: "Where an outage is in its lifecycle",
+	Values:      enumValues(domain.OutageStatuses),
+}
+
+func enumValues(names []string) []EnumValue {
+	values := make([]EnumValue, len(names))
+	for i, name := range names {
+		values[i] = EnumValue{Name: name}
+	}
+	return values
+}
+
+// field returns a field resolved from a struct field of a domain type
+func field[T any](name string, t Type, get func(T) any) *Field {
+	return &Field{Name: name, Type: t, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+		return get(source.(T)), nil
+	}}
+}
+
+// objectTypes are the object types of the schema
+type objectTypes struct {
+	outage, alert, note, tag *Object
+}
+
+func (h *Handler) objectTypes() objectTypes {
+	tag := &Object{Name: "Tag", Description: "Metadata attached to an outage, such as a Jira ticket", Fields: []*Field{
+		field("id", nonNull(ID), func(t *domain.Tag) any { return t.ID }),
+		field("outageId", nonNull(ID), func(t *domain.Tag) any { return t.OutageID }),
+		field("key", nonNull(String), func(t *domain.Tag) any { return t.Key }),
+		field("value", nonNull(String), func(t *domain.Tag) any { return t.Value }),
+		field("createdAt", nonNull(Time), func(t *domain.Tag) any { return t.CreatedAt }),
+	}}
+	relation := &Object{Name: "OutageRelation", Description: "A typed link from an outage to another outage", Fields: []*Field{
+		field("id", nonNull(ID), func(r *domain.OutageRelation) any { return r.ID }),
+		field("outageId", nonNull(ID), func(r *domain.OutageRelation) any { return r.OutageID }),
+		field("relatedOutageId", nonNull(ID), func(r *domain.OutageRelation) any { return r.RelatedOutageID }),
+		field("relatedOutageTitle", nonNull(String), func(r *domain.OutageRelation) any { return r.RelatedOutageTitle }),
+		field("type", nonNull(String), func(r *domain.OutageRelation) any { return r.Type }),
+		field("description", String, func(r *domain.OutageRelation) any { return optional(r.Description) }),
+		field("createdBy", String, func(r *domain.OutageRelation) any { return optional(r.CreatedBy) }),
+		field("createdAt", nonNull(Time), func(r *domain.OutageRelation) any { return r.CreatedAt }),
+	}}
+	note := &Object{Name: "Note", Description: "A note on an outage", Fields: []*Field{
+		field("id", nonNull(ID), func(n *domain.Note) any { return n.ID }),
+		field("outageId", nonNull(ID), func(n *domain.Note) any { return n.OutageID }),
+		field("parentNoteId", ID, func(n *domain.Note) any { return n.ParentNoteID }),
+		field("content", nonNull(String), func(n *domain.Note) any { return n.Content }),
+		field("format", nonNull(String), func(n *domain.Note) any { return n.Format }),
+		field("author", nonNull(String), func(n *domain.Note) any { return n.Author }),
+		field("createdAt", nonNull(Time), func(n *domain.Note) any { return n.CreatedAt }),
+		field("updatedAt", nonNull(Time), func(n *domain.Note) any { return n.UpdatedAt }),
+	}}
+	alert := &Object{Name: "Alert", Description: "A paging alert from an on-call notification service", Fields: []*Field{
+		field("id", nonNull(ID), func(a *domain.Alert) any { return a.ID }),
+		field("externalId", nonNull(String), func(a *domain.Alert) any { return a.ExternalID }),
+		field("source", nonNull(String), func(a *domain.Alert) any { return a.Source }),
+		field("teamName", nonNull(String), func(a *domain.Alert) any { return a.TeamName }),
+		field("teamNames", listOf(String), func(a *domain.Alert) any {
+			if len(a.TeamNames) == 0 && a.TeamName != "" {
+				return []string{a.TeamName}
+			}
+			return append([]string{}, a.TeamNames...)
+		}),
+		field("title", nonNull(String), func(a *domain.Alert) any { return a.Title }),
+		field("description", nonNull(String), func(a *domain.Alert) any { return a.Description }),
+		field("severity", nonNull(String), func(a *domain.Alert) any { return a.Severity }),
+		field("service", nonNull(String), func(a *domain.Alert) any { return a.Service }),
+		field("fingerprint", nonNull(String), func(a *domain.Alert) any { return a.Fingerprint }),
+		field("triggeredAt", nonNull(Time), func(a *domain.Alert) any { return a.TriggeredAt }),
+		field("acknowledgedAt", Time, func(a *domain.Alert) any { return a.AcknowledgedAt }),
+		field("resolvedAt", Time, func(a *domain.Alert) any { return a.ResolvedAt }),
+		field("createdAt", nonNull(Time), func(a *domain.Alert) any { return a.CreatedAt }),
+	}}
+	outage := &Object{Name: "Outage", Description: "An outage and the alerts, notes and tags attached to it", Fields: []*Field{
+		field("id", nonNull(ID), func(o *domain.Outage) any { return o.ID }),
+		field("title", nonNull(String), func(o *domain.Outage) any { return o.Title }),
+		field("description", nonNull(String), func(o *domain.Outage) any { return o.Description }),
+		field("status", nonNull(outageStatus), func(o *domain.Outage) any { return o.Status }),
+		field("severity", nonNull(String), func(o *domain.Outage) any { return o.Severity }),
+		field("owningTeam", String, func(o *domain.Outage) any { return optional(o.OwningTeam) }),
+		field("createdAt", nonNull(Time), func(o *domain.Outage) any { return o.CreatedAt }),
+		field("updatedAt", nonNull(Time), func(o *domain.Outage) any { return o.UpdatedAt }),
+		field("investigatingAt", Time, func(o *domain.Outage) any { return o.InvestigatingAt }),
+		field("mitigatedAt", Time, func(o *domain.Outage) any { return o.MitigatedAt }),
+		field("resolvedAt", Time, func(o *domain.Outage) any { return o.ResolvedAt }),
+		field("affectedServices", listOf(String), func(o *domain.Outage) any { return append([]string{}, o.AffectedServices...) }),
+		field("customerImpact", nonNull(Boolean), func(o *domain.Outage) any { return o.CustomerImpact }),
+		field("impactStartedAt", Time, func(o *domain.Outage) any { return o.ImpactStartedAt }),
+		field("impactEndedAt", Time, func(o *domain.Outage) any { return o.ImpactEndedAt }),
+		field("parentId", ID, func(o *domain.Outage) any { return o.ParentID }),
+		{Name: "alerts", Type: listOf(alert), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			alerts, err := h.service.ListAlertsByOutage(ctx, source.(*domain.Outage).ID)
+			return alerts, h.resolveError(ctx, err)
+		}},
+		{Name: "notes", Type: listOf(note), Description: "Notes oldest first, leaving out those in the trash", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			notes, err := h.service.ListNotesByOutage(ctx, source.(*domain.Outage).ID)
+			return notes, h.resolveError(ctx, err)
+		}},
+		{Name: "tags", Type: listOf(tag), Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			tags, err := h.service.ListTagsByOutage(ctx, source.(*domain.Outage).ID)
+			return tags, h.resolveError(ctx, err)
+		}},
+		{Name: "relations", Type: listOf(relation), Description: "Links to other outages oldest first, leaving out those in the trash", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+			relations, err := h.service.ListOutageRelations(ctx, source.(*domain.Outage).ID)
+			return relations, h.resolveError(ctx, err)
+		}},
+	}}
+	alert.Fields = append(alert.Fields, &Field{Name: "outage", Type: outage, Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+		a := source.(*domain.Alert)
+		if a.OutageID == uuid.Nil {
+			return nil, nil
+		}
+		o, err := h.service.GetOutage(ctx, a.OutageID)
+		return o, h.resolveError(ctx, err)
+	}})
+	return objectTypes{outage: outage, alert: alert, note: note, tag: tag}
+}
+
+func (h *Handler) queryType(types objectTypes) *Object {
+	tagFilter := &InputObject{Name: "TagFilter", Description: "Matches outages with a tag", Fields: []*Argument{
+		{Name: "key", Type: nonNull(String)},
+		{Name: "value", Type: nonNull(String)},
+	}}
+	outageFilter := &InputObject{Name: "OutageFilter", Description: "Matches outages on every field given", Fields: []*Argument{
+		{Name: "status", Type: &List{Of: nonNull(outageStatus)}, Description: "Any of these statuses"},
+		{Name: "severity", Type: &List{Of: nonNull(String)}, Description: "Any of these severities"},
+		{Name: "owningTeam", Type: &List{Of: nonNull(String)}, Description: "Owned by any of these teams"},
+		{Name: "tag", Type: tagFilter},
+	}}
+
+	return &Object{Name: "Query", Fields: []*Field{
+		{
+			Name:        "outage",
+			Description: "An outage by ID",
+			Type:        types.outage,
+			Args:        []*Argument{{Name: "id", Type: nonNull(ID)}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id, err := parseUUID(args["id"], "outage")
+				if err != nil {
+					return nil, err
+				}
+				o, err := h.service.GetOutage(ctx, id)
+				if errors.Is(err, domain.ErrNotFound) {
+					return nil, nil
+				}
+				return o, h.resolveError(ctx, err)
+			},
+		},
+		{
+			Name:        "outages",
+			Description: "Outages newest first, leaving out those in the trash",
+			Type:        listOf(types.outage),
+			Args: []*Argument{
+				{Name: "filter", Type: outageFilter},
+				{Name: "limit", Type: Int, Default: defaultLimit, Description: fmt.Sprintf("At most %d", maxLimit)},
+				{Name: "offset", Type: Int, Default: 0},
+			},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				outages, err := h.listOutages(ctx, args)
+				return outages, h.resolveError(ctx, err)
+			},
+		},
+		{
+			Name:        "alert",
+			Description: "An alert by ID",
+			Type:        types.alert,
+			Args:        []*Argument{{Name: "id", Type: nonNull(ID)}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id, err := parseUUID(args["id"], "alert")
+				if err != nil {
+					return nil, err
+				}
+				a, err := h.service.GetAlert(ctx, id)
+				if errors.Is(err, domain.ErrNotFound) {
+					return nil, nil
+				}
+				return a, h.resolveError(ctx, err)
+			},
+		},
+	}}
+}
+
+func (h *Handler) mutationType(types objectTypes) *Object {
+	tagInput := &InputObject{Name: "TagInput", Fields: []*Argument{
+		{Name: "key", Type: nonNull(String)},
+		{Name: "value", Type: nonNull(String)},
+	}}
+	createOutageInput := &InputObject{Name: "CreateOutageInput", Fields: []*Argument{
+		{Name: "title", Type: nonNull(String)},
+		{Name: "description", Type: String},
+		{Name: "severity", Type: nonNull(String)},
+		{Name: "owningTeam", Type: String},
+		{Name: "template", Type: String, Description: "Outage template that fills unset fields and adds its tags"},
+		{Name: "alertIds", Type: &List{Of: nonNull(String)}, Description: "External IDs of alerts to attach"},
+		{Name: "tags", Type: &List{Of: nonNull(tagInput)}},
+	}}
+	addNoteInput := &InputObject{Name: "AddNoteInput", Fields: []*Argument{
+		{Name: "content", Type: nonNull(String)},
+		{Name: "format", Type: String, Default: domain.NoteFormatPlaintext},
+		{Name: "parentNoteId", Type: ID, Description: "Top-level note to reply to"},
+	}}
+
+	return &Object{Name: "Mutation", Fields: []*Field{
+		{
+			Name: "createOutage",
+			Type: types.outage,
+			Args: []*Argument{{Name: "input", Type: nonNull(createOutageInput)}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				input := args["input"].(map[string]any)
+				req := domain.CreateOutageRequest{
+					Title:       stringArg(input, "title"),
+					Description: stringArg(input, "description"),
+					Severity:    stringArg(input, "severity"),
+					OwningTeam:  stringArg(input, "owningTeam"),
+					Template:    stringArg(input, "template"),
+				}
+				for _, id := range listArg(input, "alertIds") {
+					req.AlertIDs = append(req.AlertIDs, id.(string))
+				}
+				for _, t := range listArg(input, "tags") {
+					tag := t.(map[string]any)
+					req.Tags = append(req.Tags, domain.TagInput{Key: stringArg(tag, "key"), Value: stringArg(tag, "value")})
+				}
+				o, err := h.service.CreateOutage(ctx, req)
+				return o, h.resolveError(ctx, err)
+			},
+		},
+		{
+			Name:        "addNote",
+			Description: "Add a note, written by the signed-in user",
+			Type:        types.note,
+			Args: []*Argument{
+				{Name: "outageId", Type: nonNull(ID)},
+				{Name: "input", Type: nonNull(addNoteInput)},
+			},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				user, err := auth.GetUserFromContext(ctx)
+				if err != nil {
+					return nil, errors.New("user not authenticated")
+				}
+				id, err := parseUUID(args["outageId"], "outage")
+				if err != nil {
+					return nil, err
+				}
+
+				input := args["input"].(map[string]any)
+				req := domain.AddNoteRequest{
+					Content: stringArg(input, "content"),
+					Format:  stringArg(input, "format"),
+					Author:  user.Identity(),
+				}
+				if parent, ok := input["parentNoteId"].(string); ok {
+					parentID, err := parseUUID(parent, "parent note")
+					if err != nil {
+						return nil, err
+					}
+					req.ParentNoteID = &parentID
+				}
+				n, err := h.service.AddNote(ctx, id, req)
+				return n, h.resolveError(ctx, err)
+			},
+		},
+		{
+			Name:        "updateStatus",
+			Description: "Move an outage to a status its state machine allows",
+			Type:        types.outage,
+			Args: []*Argument{
+				{Name: "id", Type: nonNull(ID)},
+				{Name: "status", Type: nonNull(outageStatus)},
+			},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id, err := parseUUID(args["id"], "outage")
+				if err != nil {
+					return nil, err
+				}
+				status := args["status"].(string)
+				o, err := h.service.UpdateOutage(ctx, id, domain.UpdateOutageRequest{Status: &status})
+				return o, h.resolveError(ctx, err)
+			},
+		},
+	}}
+}
+
+// listOutages resolves the outages query. Storage filters by owning team
+// and tag; status and severity are matched here, scanning pages until the
+// requested page of matches is full.
+func (h *Handler) listOutages(ctx context.Context, args map[string]any) ([]*domain.Outage, error) {
+	limit, offset := args["limit"].(int), args["offset"].(int)
+	if limit <= 0 || limit > maxLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d: %w", maxLimit, domain.ErrInvalidInput)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative: %w", domain.ErrInvalidInput)
+	}
+
+	filter, _ := args["filter"].(map[string]any)
+	statuses, severities, teams := stringsArg(filter, "status"), stringsArg(filter, "severity"), stringsArg(filter, "owningTeam")
+	match := func(o *domain.Outage) bool {
+		return (statuses == nil || slices.Contains(statuses, o.Status)) &&
+			(severities == nil || slices.Contains(severities, o.Severity)) &&
+			(teams == nil || slices.Contains(teams, o.OwningTeam))
+	}
+
+	if tag, ok := filter["tag"].(map[string]any); ok {
+		tagged, err := h.service.FindOutagesByTag(ctx, stringArg(tag, "key"), stringArg(tag, "value"))
+		if err != nil {
+			return nil, err
+		}
+		matched := slices.DeleteFunc(tagged, func(o *domain.Outage) bool { return !match(o) })
+		if offset >= len(matched) {
+			return []*domain.Outage{}, nil
+		}
+		return matched[offset:min(offset+limit, len(matched))], nil
+	}
+
+	outages := []*domain.Outage{}
+	skipped := 0
+	for page := 0; len(outages) < limit; page += maxLimit {
+		var batch []*domain.Outage
+		var err error
+		if teams != nil {
+			batch, err = h.service.ListTeamOutages(ctx, teams, maxLimit, page)
+		} else {
+			batch, err = h.service.ListOutages(ctx, maxLimit, page)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range batch {
+			if !match(o) || len(outages) == limit {
+				continue
+			}
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			outages = append(outages, o)
+		}
+		if len(batch) < maxLimit {
+			break
+		}
+	}
+	return outages, nil
+}
+
+// resolveError returns the error a resolver reports for a service error.
+// Errors that map to a server error are logged and hidden from clients.
+func (h *Handler) resolveError(ctx context.Context, err error) error {
+	if err == nil || apierr.Status(err) != http.StatusInternalServerError {
+		return err
+	}
+	h.logger.ErrorContext(ctx, "graphql resolver failed", "error", err)
+	return errors.New("internal error")
+}
+
+// parseUUID parses a UUID argument
+func parseUUID(v any, what string) (uuid.UUID, error) {
+	id, err := uuid.Parse(v.(string))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s ID: %w", what, domain.ErrInvalidInput)
+	}
+	return id, nil
+}
+
+// stringArg returns a string field of an input object, or "" when it is
+// null or left out
+func stringArg(input map[string]any, name string) string {
+	s, _ := input[name].(string)
+	return s
+}
+
+// listArg returns a list field of an input object, or nil when it is null
+// or left out
+func listArg(input map[string]any, name string) []any {
+	list, _ := input[name].([]any)
+	return list
+}
+
+// stringsArg returns a list of strings field of an input object, or nil
+// when it is null or left out
+func stringsArg(input map[string]any, name string) []string {
+	list := listArg(input, name)
+	if list == nil {
+		return nil
+	}
+	strs := make([]string, len(list))
+	for i, v := range list {
+		strs[i] = v.(string)
+	}
+	return strs
+}
@@ -0,0 +1,270 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+// graphqlResponse is a decoded GraphQL response
+type graphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// post sends a query to h as user, which may be nil, and decodes the response
+func post(t *testing.T, h *Handler, user *auth.UserInfo, query string, vars map[string]any) (int, graphqlResponse) {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	if user != nil {
+		req = req.WithContext(testutil.WithUser(req.Context(), user))
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	var resp graphqlResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %s: %v", rr.Body, err)
+	}
+	return rr.Code, resp
+}
+
+func TestOutageQueries(t *testing.T) {
+	store := testutil.NewMemStorage()
+	svc := service.New(store, logging.Discard())
+	h := NewHandler(svc, logging.Discard())
+	ctx := context.Background()
+
+	db, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "critical",
+		Tags: []domain.TagInput{{Key: "service", Value: "db"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddNote(ctx, db.ID, domain.AddNoteRequest{Content: "failing over", Format: "plaintext", Author: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	alert := &domain.Alert{ID: uuid.New(), OutageID: db.ID, ExternalID: "PD-1", Source: "pagerduty", TeamName: "dba",
+		Title: "db unreachable", Severity: "critical", TriggeredAt: time.Now(), CreatedAt: time.Now()}
+	if err := store.CreateAlert(ctx, alert); err != nil {
+		t.Fatal(err)
+	}
+	for title, severity := range map[string]string{"slow search": "low", "search errors": "medium"} {
+		if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: severity}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	replica, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Replica lag", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateOutageRelation(ctx, replica.ID, domain.CreateOutageRelationRequest{RelatedOutageID: db.ID, Type: domain.RelationCausedBy}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  string
+	}{
+		{
+			name:  "outage with nested alerts, notes and tags",
+			query: `query ($id: ID!) { outage(id: $id) { title status alerts { externalId outage { title } } notes { content author } tags { key value } } }`,
+			vars:  map[string]any{"id": db.ID.String()},
+			want:  `{"outage":{"title":"DB down","status":"open","alerts":[{"externalId":"PD-1","outage":{"title":"DB down"}}],"notes":[{"content":"failing over","author":"alice@example.com"}],"tags":[{"key":"service","value":"db"}]}}`,
+		},
+		{
+			name:  "outage relations read from either end",
+			query: `query ($db: ID!, $replica: ID!) { db: outage(id: $db) { relations { type relatedOutageTitle } } replica: outage(id: $replica) { relations { type relatedOutageTitle } } }`,
+			vars:  map[string]any{"db": db.ID.String(), "replica": replica.ID.String()},
+			want:  `{"db":{"relations":[{"type":"causes","relatedOutageTitle":"Replica lag"}]},"replica":{"relations":[{"type":"caused_by","relatedOutageTitle":"DB down"}]}}`,
+		},
+		{
+			name:  "missing outage",
+			query: `{ outage(id: "` + uuid.NewString() + `") { title } }`,
+			want:  `{"outage":null}`,
+		},
+		{
+			name:  "alert",
+			query: `query ($id: ID!) { alert(id: $id) { source teamNames } }`,
+			vars:  map[string]any{"id": alert.ID.String()},
+			want:  `{"alert":{"source":"pagerduty","teamNames":["dba"]}}`,
+		},
+		{
+			name:  "filter by severity",
+			query: `{ outages(filter: {severity: ["low"]}) { title } }`,
+			want:  `{"outages":[{"title":"slow search"}]}`,
+		},
+		{
+			name:  "filter by tag",
+			query: `{ outages(filter: {tag: {key: "service", value: "db"}}) { title } }`,
+			want:  `{"outages":[{"title":"DB down"}]}`,
+		},
+		{
+			name:  "filter by status",
+			query: `{ outages(filter: {status: [resolved]}) { title } }`,
+			want:  `{"outages":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := post(t, h, nil, tt.query, tt.vars)
+			if code != http.StatusOK || len(resp.Errors) > 0 {
+				t.Fatalf("status = %d, errors = %+v", code, resp.Errors)
+			}
+			got, _ := json.Marshal(resp.Data)
+			if string(got) != tt.want {
+				t.Errorf("data = %s\nwant   %s", got, tt.want)
+			}
+		})
+	}
+
+	// Pages of matches do not overlap
+	_, resp := post(t, h, nil, `{ first: outages(filter: {severity: ["low", "medium"]}, limit: 1) { title }
+		second: outages(filter: {severity: ["low", "medium"]}, limit: 1, offset: 1) { title } }`, nil)
+	var pages struct{ First, Second []struct{ Title string } }
+	data, _ := json.Marshal(resp.Data)
+	if err := json.Unmarshal(data, &pages); err != nil {
+		t.Fatal(err)
+	}
+	if len(pages.First) != 1 || len(pages.Second) != 1 || pages.First[0].Title == pages.Second[0].Title || pages.First[0].Title == "DB down" {
+		t.Errorf("pages = %s", data)
+	}
+
+	code, resp := post(t, h, nil, `{ outages(limit: 500) { title } }`, nil)
+	if code != http.StatusOK || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "limit must be between 1 and 100") {
+		t.Errorf("oversized limit: status = %d, errors = %+v", code, resp.Errors)
+	}
+}
+
+func TestOutageMutations(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	h := NewHandler(svc, logging.Discard())
+	svc.SetAdmins([]string{"Admin@example.com"})
+	ctx := context.Background()
+	cfg := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"alice@example.com"}}}}
+	if _, err := svc.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+	alice := &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}
+	bob := &auth.UserInfo{Email: "bob@example.com", Sub: "sub-bob"}
+	admin := &auth.UserInfo{Email: "admin@example.com", Sub: "sub-admin"}
+
+	code, resp := post(t, h, alice, `mutation ($input: CreateOutageInput!) { createOutage(input: $input) { id status owningTeam tags { key } } }`,
+		map[string]any{"input": map[string]any{"title": "card errors", "severity": "high", "owningTeam": "payments", "tags": []any{map[string]any{"key": "service", "value": "checkout"}}}})
+	if code != http.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("createOutage: status = %d, errors = %+v", code, resp.Errors)
+	}
+	var created struct {
+		ID         string
+		Status     string
+		OwningTeam string
+		Tags       []struct{ Key string }
+	}
+	if err := json.Unmarshal(resp.Data["createOutage"], &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Status != "open" || created.OwningTeam != "payments" || len(created.Tags) != 1 {
+		t.Errorf("createOutage = %+v", created)
+	}
+
+	_, resp = post(t, h, alice, `mutation { createOutage(input: {title: "x", severity: "high", owningTeam: "nobody"}) { id } }`, nil)
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "unknown owning team") || string(resp.Data["createOutage"]) != "null" {
+		t.Errorf("createOutage with unknown team: %+v", resp)
+	}
+
+	addNote := `mutation ($id: ID!) { addNote(outageId: $id, input: {content: "rolling back"}) { author format } }`
+	vars := map[string]any{"id": created.ID}
+	tests := []struct {
+		name      string
+		user      *auth.UserInfo
+		wantError string
+	}{
+		{"signed out", nil, "user not authenticated"},
+		{"not in owning team", bob, "only members of team payments can change outage"},
+		{"team member", alice, ""},
+		{"admin", admin, ""},
+	}
+	for _, tt := range tests {
+		t.Run("addNote "+tt.name, func(t *testing.T) {
+			_, resp := post(t, h, tt.user, addNote, vars)
+			if tt.wantError != "" {
+				if len(resp.Errors) != 1 || !strings.HasPrefix(resp.Errors[0].Message, tt.wantError) {
+					t.Errorf("errors = %+v, want %q", resp.Errors, tt.wantError)
+				}
+				return
+			}
+			if len(resp.Errors) > 0 {
+				t.Fatalf("errors = %+v", resp.Errors)
+			}
+			want := `{"author":"` + tt.user.Email + `","format":"plaintext"}`
+			if string(resp.Data["addNote"]) != want {
+				t.Errorf("addNote = %s, want %s", resp.Data["addNote"], want)
+			}
+		})
+	}
+
+	updateStatus := `mutation ($id: ID!, $status: OutageStatus!) { updateStatus(id: $id, status: $status) { status } }`
+	_, resp = post(t, h, bob, updateStatus, map[string]any{"id": created.ID, "status": "investigating"})
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "only members of team payments") {
+		t.Errorf("updateStatus by bob: %+v", resp)
+	}
+	_, resp = post(t, h, alice, updateStatus, map[string]any{"id": created.ID, "status": "investigating"})
+	if len(resp.Errors) > 0 || string(resp.Data["updateStatus"]) != `{"status":"investigating"}` {
+		t.Errorf("updateStatus by alice: %+v", resp)
+	}
+	_, resp = post(t, h, alice, updateStatus, map[string]any{"id": created.ID, "status": "bogus"})
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, `Value "bogus" does not exist in "OutageStatus" enum`) {
+		t.Errorf("updateStatus to bogus status: %+v", resp)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	h := NewHandler(service.New(testutil.NewMemStorage(), logging.Discard()), logging.Discard())
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"get query", http.MethodGet, "/graphql?query=" + url.QueryEscape(`{ outages { id } }`), "", http.StatusOK},
+		{"get with variables", http.MethodGet, "/graphql?query=" + url.QueryEscape(`query ($n: Int) { outages(limit: $n) { id } }`) + "&variables=" + url.QueryEscape(`{"n":5}`), "", http.StatusOK},
+		{"get mutation", http.MethodGet, "/graphql?query=" + url.QueryEscape(`mutation { updateStatus(id: "x", status: open) { id } }`), "", http.StatusMethodNotAllowed},
+		{"get invalid variables", http.MethodGet, "/graphql?query=" + url.QueryEscape(`{ outages { id } }`) + "&variables=nope", "", http.StatusBadRequest},
+		{"post", http.MethodPost, "/graphql", `{"query":"{ outages { id } }"}`, http.StatusOK},
+		{"post invalid body", http.MethodPost, "/graphql", `{"query":`, http.StatusBadRequest},
+		{"missing query", http.MethodPost, "/graphql", `{}`, http.StatusBadRequest},
+		{"validation error", http.MethodPost, "/graphql", `{"query":"{ outages { nope } }"}`, http.StatusBadRequest},
+		{"put", http.MethodPut, "/graphql", `{}`, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,788 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is a parsed GraphQL request document
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query or mutation in a document
+type operation struct {
+	kind       string // query or mutation
+	name       string
+	vars       []*varDef
+	selections []selection
+}
+
+// varDef is a variable an operation declares
+type varDef struct {
+	name string
+	typ  *typeRef
+	def  *value
+}
+
+// typeRef is a type named in a variable definition
+type typeRef struct {
+	name    string   // Named type, unless this is a list
+	list    *typeRef // Item type of a list
+	nonNull bool
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.list != nil {
+		s = "[" + t.list.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// fragment is a named fragment of a document
+type fragment struct {
+	name       string
+	typeCond   string
+	directives []*directive
+	selections []selection
+}
+
+// selection is a *fieldNode, *fragmentSpread or *inlineFragment
+type selection interface{}
+
+type fieldNode struct {
+	alias      string
+	name       string
+	args       []*argNode
+	directives []*directive
+	selections []selection
+	line, col  int
+}
+
+// responseKey returns the key of the field in the result
+func (f *fieldNode) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+}
+
+type inlineFragment struct {
+	typeCond   string // Empty without a type condition
+	directives []*directive
+	selections []selection
+}
+
+type argNode struct {
+	name string
+	val  *value
+}
+
+type directive struct {
+	name string
+	args []*argNode
+}
+
+// Kinds of value literals
+const (
+	valueVariable = iota
+	valueInt
+	valueFloat
+	valueString
+	valueBoolean
+	valueNull
+	valueEnum
+	valueList
+	valueObject
+)
+
+// value is a value literal, or a reference to a variable
+type value struct {
+	kind   int
+	raw    string // Variable name, number, string, boolean or enum value
+	list   []*value
+	fields []*argNode
+}
+
+// SyntaxError reports a request that is not valid GraphQL
+type SyntaxError struct {
+	Message   string
+	Line, Col int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("Syntax Error: %s (line %d, column %d)", e.Message, e.Line, e.Col)
+}
+
+// Kinds of tokens
+const (
+	tokEOF = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind      int
+	val       string
+	line, col int
+}
+
+// lexer splits a GraphQL document into tokens
+type lexer struct {
+	src       string
+	pos       int
+	line      int
+	lineStart int
+}
+
+func (l *lexer) errorf(format string, args ...any) error {
+	return &SyntaxError{Message: fmt.Sprintf(format, args...), Line: l.line, Col: l.pos - l.lineStart + 1}
+}
+
+// skipIgnored skips whitespace, commas, byte order marks and comments
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; c {
+		case ' ', '\t', ',':
+			l.pos++
+		case '\n':
+			l.pos++
+			l.line++
+			l.lineStart = l.pos
+		case '\r':
+			l.pos++
+			if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+				l.pos++
+			}
+			l.line++
+			l.lineStart = l.pos
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			if strings.HasPrefix(l.src[l.pos:], "\uFEFF") {
+				l.pos += len("\uFEFF")
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	tok := token{line: l.line, col: l.pos - l.lineStart + 1}
+	if l.pos >= len(l.src) {
+		tok.kind = tokEOF
+		return tok, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.ContainsRune("!$&()[]{}:=@|", rune(c)):
+		l.pos++
+		tok.kind, tok.val = tokPunct, string(c)
+		return tok, nil
+	case c == '.':
+		if !strings.HasPrefix(l.src[l.pos:], "...") {
+			return tok, l.errorf("unexpected %q", ".")
+		}
+		l.pos += 3
+		tok.kind, tok.val = tokPunct, "..."
+		return tok, nil
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		tok.kind, tok.val = tokName, l.src[start:l.pos]
+		return tok, nil
+	case c == '-' || isDigit(c):
+		return l.number(tok)
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.blockString(tok)
+		}
+		return l.string(tok)
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return tok, l.errorf("unexpected character %q", r)
+}
+
+func (l *lexer) number(tok token) (token, error) {
+	start := l.pos
+	tok.kind = tokInt
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	if !l.digits() {
+		return tok, l.errorf("invalid number")
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.pos++
+		tok.kind = tokFloat
+		if !l.digits() {
+			return tok, l.errorf("invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		l.pos++
+		tok.kind = tokFloat
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if !l.digits() {
+			return tok, l.errorf("invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || l.src[l.pos] == '.' || isLetter(l.src[l.pos])) {
+		return tok, l.errorf("invalid number")
+	}
+	tok.val = l.src[start:l.pos]
+	return tok, nil
+}
+
+// digits consumes a run of digits, reporting whether there was one
+func (l *lexer) digits() bool {
+	start := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+	return l.pos > start
+}
+
+func (l *lexer) string(tok token) (token, error) {
+	l.pos++ // Opening quote
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' || l.src[l.pos] == '\r' {
+			return tok, l.errorf("unterminated string")
+		}
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			tok.kind, tok.val = tokString, b.String()
+			return tok, nil
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return tok, l.errorf("unterminated string")
+			}
+			esc := l.src[l.pos+1]
+			l.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return tok, l.errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return tok, l.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return tok, l.errorf("invalid escape \\%c", esc)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+}
+
+func (l *lexer) blockString(tok token) (token, error) {
+	l.pos += 3
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return tok, l.errorf("unterminated string")
+		}
+		switch {
+		case strings.HasPrefix(l.src[l.pos:], `"""`):
+			l.pos += 3
+			tok.kind, tok.val = tokString, blockStringValue(b.String())
+			return tok, nil
+		case strings.HasPrefix(l.src[l.pos:], `\"""`):
+			b.WriteString(`"""`)
+			l.pos += 4
+		default:
+			if l.src[l.pos] == '\n' {
+				l.line++
+				l.lineStart = l.pos + 1
+			}
+			b.WriteByte(l.src[l.pos])
+			l.pos++
+		}
+	}
+}
+
+// blockStringValue removes the common indentation and blank first and
+// last lines of a block string
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// parser builds a document from the tokens of a lexer
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// parse parses a GraphQL request document
+func parse(src string) (*document, error) {
+	p := &parser{lex: &lexer{src: src, line: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: map[string]*fragment{}}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.peek(tokPunct, "{"):
+			sels, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: sels})
+		case p.peek(tokName, "query"), p.peek(tokName, "mutation"), p.peek(tokName, "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peek(tokName, "fragment"):
+			frag, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, fmt.Errorf("There can be only one fragment named %q", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, &SyntaxError{Message: "document has no operations", Line: 1, Col: 1}
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peek(kind int, val string) bool {
+	return p.tok.kind == kind && p.tok.val == val
+}
+
+func (p *parser) unexpected() error {
+	desc := "<EOF>"
+	if p.tok.kind != tokEOF {
+		desc = strconv.Quote(p.tok.val)
+	}
+	return &SyntaxError{Message: "unexpected " + desc, Line: p.tok.line, Col: p.tok.col}
+}
+
+// expect consumes the punctuator val
+func (p *parser) expect(val string) error {
+	if !p.peek(tokPunct, val) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+// skip consumes the punctuator val if it is next, reporting whether it was
+func (p *parser) skip(val string) (bool, error) {
+	if !p.peek(tokPunct, val) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.unexpected()
+	}
+	name := p.tok.val
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*operation, error) {
+	op := &operation{kind: p.tok.val}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokName {
+		op.name = p.tok.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if ok, err := p.skip("("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(tokPunct, ")") {
+			v, err := p.varDef()
+			if err != nil {
+				return nil, err
+			}
+			op.vars = append(op.vars, v)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	sels, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = sels
+	return op, nil
+}
+
+func (p *parser) varDef() (*varDef, error) {
+	if err := p.expect("$"); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	typ, err := p.typeRef()
+	if err != nil {
+		return nil, err
+	}
+	v := &varDef{name: name, typ: typ}
+	if ok, err := p.skip("="); err != nil {
+		return nil, err
+	} else if ok {
+		if v.def, err = p.value(true); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (p *parser) typeRef() (*typeRef, error) {
+	t := &typeRef{}
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		if t.list, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	} else {
+		if t.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	ok, err := p.skip("!")
+	t.nonNull = ok
+	return t, err
+}
+
+func (p *parser) fragment() (*fragment, error) {
+	if err := p.advance(); err != nil { // fragment
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, &SyntaxError{Message: `unexpected "on"`, Line: p.tok.line, Col: p.tok.col}
+	}
+	if !p.peek(tokName, "on") {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	frag := &fragment{name: name}
+	if frag.typeCond, err = p.name(); err != nil {
+		return nil, err
+	}
+	if frag.directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if frag.selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return frag, nil
+}
+
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var sels []selection
+	for !p.peek(tokPunct, "}") {
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	if len(sels) == 0 {
+		return nil, p.unexpected()
+	}
+	return sels, p.advance()
+}
+
+func (p *parser) selection() (selection, error) {
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		return p.fragmentSelection()
+	}
+
+	f := &fieldNode{line: p.tok.line, col: p.tok.col}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.alias = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	f.name = name
+	if f.args, err = p.arguments(false); err != nil {
+		return nil, err
+	}
+	if f.directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokPunct, "{") {
+		if f.selections, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// fragmentSelection parses a fragment spread or inline fragment, after
+// its leading ...
+func (p *parser) fragmentSelection() (selection, error) {
+	if p.tok.kind == tokName && p.tok.val != "on" {
+		spread := &fragmentSpread{name: p.tok.val}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		spread.directives, err = p.directives()
+		return spread, err
+	}
+
+	inline := &inlineFragment{}
+	if p.peek(tokName, "on") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if inline.typeCond, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if inline.directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if inline.selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return inline, nil
+}
+
+func (p *parser) arguments(constant bool) ([]*argNode, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+	var args []*argNode
+	for !p.peek(tokPunct, ")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		val, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &argNode{name: name, val: val})
+	}
+	if len(args) == 0 {
+		return nil, p.unexpected()
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]*directive, error) {
+	var dirs []*directive
+	for p.peek(tokPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.arguments(false)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, &directive{name: name, args: args})
+	}
+	return dirs, nil
+}
+
+// value parses a value literal. Constant values, such as variable
+// defaults, may not reference variables.
+func (p *parser) value(constant bool) (*value, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokPunct:
+		switch tok.val {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			return &value{kind: valueVariable, raw: name}, err
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			v := &value{kind: valueList}
+			for !p.peek(tokPunct, "]") {
+				item, err := p.value(constant)
+				if err != nil {
+					return nil, err
+				}
+				v.list = append(v.list, item)
+			}
+			return v, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			v := &value{kind: valueObject}
+			for !p.peek(tokPunct, "}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				field, err := p.value(constant)
+				if err != nil {
+					return nil, err
+				}
+				v.fields = append(v.fields, &argNode{name: name, val: field})
+			}
+			return v, p.advance()
+		}
+	case tokInt:
+		return &value{kind: valueInt, raw: tok.val}, p.advance()
+	case tokFloat:
+		return &value{kind: valueFloat, raw: tok.val}, p.advance()
+	case tokString:
+		return &value{kind: valueString, raw: tok.val}, p.advance()
+	case tokName:
+		v := &value{kind: valueEnum, raw: tok.val}
+		switch tok.val {
+		case "true", "false":
+			v.kind = valueBoolean
+		case "null":
+			v.kind = valueNull
+		}
+		return v, p.advance()
+	}
+	return nil, p.unexpected()
+}
@@ -0,0 +1,415 @@
+// Package graphql serves a GraphQL API over the service layer beside the
+// REST and gRPC APIs, so clients can fetch outages with exactly the
+// alerts, notes and tags they need in one request.
+//
+// The package carries its own small GraphQL engine: a type system, a
+// parser for the query language, a validator and a serial executor. It
+// supports queries and mutations with variables, aliases, fragments,
+// @include/@skip and introspection, but not subscriptions, interfaces or
+// unions, which the Outalator schema does not need.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Type is a GraphQL type: a *Scalar, *Enum, *Object, *InputObject, *List
+// or *NonNull
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize converts a resolved value to its JSON result
+	Serialize func(v any) (any, error)
+	// Parse converts an input value, as decoded from JSON variables or a
+	// query literal, to the value resolvers receive
+	Parse func(v any) (any, error)
+}
+
+func (t *Scalar) String() string { return t.Name }
+
+// Enum is a leaf type with a fixed set of values. Resolvers receive and
+// return enum values as strings.
+type Enum struct {
+	Name        string
+	Description string
+	Values      []EnumValue
+}
+
+// EnumValue is one value of an enum
+type EnumValue struct {
+	Name        string
+	Description string
+}
+
+func (t *Enum) String() string { return t.Name }
+
+func (t *Enum) has(name string) bool {
+	for _, v := range t.Values {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Object is an output type with fields
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (t *Object) String() string { return t.Name }
+
+func (t *Object) field(name string) *Field {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// ResolveFunc resolves a field of source, the value resolved for the
+// enclosing object, which is nil for the root query and mutation fields
+type ResolveFunc func(ctx context.Context, source any, args map[string]any) (any, error)
+
+// Field is a field of an object
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	Resolve     ResolveFunc
+}
+
+// Argument is an argument of a field or a field of an input object.
+// Arguments left out of a request take Default when it is set.
+type Argument struct {
+	Name        string
+	Description string
+	Type        Type
+	Default     any
+}
+
+func findArg(args []*Argument, name string) *Argument {
+	for _, a := range args {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// InputObject is an input type with fields, which resolvers receive as a
+// map[string]any
+type InputObject struct {
+	Name        string
+	Description string
+	Fields      []*Argument
+}
+
+func (t *InputObject) String() string { return t.Name }
+
+// List is a list of another type
+type List struct {
+	Of Type
+}
+
+func (t *List) String() string { return "[" + t.Of.String() + "]" }
+
+// NonNull is another type that may not be null
+type NonNull struct {
+	Of Type
+}
+
+func (t *NonNull) String() string { return t.Of.String() + "!" }
+
+func nonNull(t Type) Type { return &NonNull{Of: t} }
+
+// listOf returns a non-null list of non-null t
+func listOf(t Type) Type { return &NonNull{Of: &List{Of: &NonNull{Of: t}}} }
+
+// named returns the named type t wraps
+func named(t Type) Type {
+	for {
+		switch w := t.(type) {
+		case *List:
+			t = w.Of
+		case *NonNull:
+			t = w.Of
+		default:
+			return t
+		}
+	}
+}
+
+func typeName(t Type) string {
+	switch n := t.(type) {
+	case *Scalar:
+		return n.Name
+	case *Enum:
+		return n.Name
+	case *Object:
+		return n.Name
+	case *InputObject:
+		return n.Name
+	}
+	return ""
+}
+
+func isInputType(t Type) bool {
+	switch named(t).(type) {
+	case *Scalar, *Enum, *InputObject:
+		return true
+	}
+	return false
+}
+
+func isLeafType(t Type) bool {
+	switch named(t).(type) {
+	case *Scalar, *Enum:
+		return true
+	}
+	return false
+}
+
+// Schema is a GraphQL schema rooted at its query and mutation types
+type Schema struct {
+	Query    *Object
+	Mutation *Object
+
+	types     map[string]Type
+	typeOrder []string
+
+	// The introspection fields of the query type
+	schemaField *Field
+	typeField   *Field
+}
+
+// NewSchema returns the schema with the given root types, which may have
+// no mutations. It fails if two types share a name.
+func NewSchema(query, mutation *Object) (*Schema, error) {
+	s := &Schema{Query: query, Mutation: mutation, types: map[string]Type{}}
+	for _, t := range []Type{Int, Float, String, Boolean, ID} {
+		if err := s.addType(t); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.addType(query); err != nil {
+		return nil, err
+	}
+	if mutation != nil {
+		if err := s.addType(mutation); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.addIntrospection(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// addType adds t and every type reachable from it to the schema
+func (s *Schema) addType(t Type) error {
+	t = named(t)
+	name := typeName(t)
+	if existing, ok := s.types[name]; ok {
+		if existing != t {
+			return fmt.Errorf("graphql: schema has two types named %s", name)
+		}
+		return nil
+	}
+	s.types[name] = t
+	s.typeOrder = append(s.typeOrder, name)
+
+	switch n := t.(type) {
+	case *Object:
+		for _, f := range n.Fields {
+			if err := s.addType(f.Type); err != nil {
+				return err
+			}
+			for _, a := range f.Args {
+				if err := s.addType(a.Type); err != nil {
+					return err
+				}
+			}
+		}
+	case *InputObject:
+		for _, f := range n.Fields {
+			if err := s.addType(f.Type); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Type returns the named type of the schema, or nil if it has none
+func (s *Schema) Type(name string) Type {
+	return s.types[name]
+}
+
+// Built-in scalars
+var (
+	Int = &Scalar{
+		Name:        "Int",
+		Description: "A signed 32-bit integer",
+		Serialize:   serializeInt,
+		Parse:       parseInt,
+	}
+	Float = &Scalar{
+		Name:        "Float",
+		Description: "A double-precision floating point number",
+		Serialize:   serializeFloat,
+		Parse:       parseFloat,
+	}
+	String = &Scalar{
+		Name:        "String",
+		Description: "A UTF-8 character sequence",
+		Serialize:   serializeString,
+		Parse:       parseString,
+	}
+	Boolean = &Scalar{
+		Name:        "Boolean",
+		Description: "true or false",
+		Serialize:   serializeBoolean,
+		Parse:       parseBoolean,
+	}
+	ID = &Scalar{
+		Name:        "ID",
+		Description: "A unique identifier, serialized as a string",
+		Serialize:   serializeID,
+		Parse:       parseID,
+	}
+)
+
+func serializeInt(v any) (any, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int32:
+		return int(n), nil
+	case int64:
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, fmt.Errorf("Int cannot represent %d", n)
+		}
+		return int(n), nil
+	}
+	return nil, fmt.Errorf("Int cannot represent %v", v)
+}
+
+func parseInt(v any) (any, error) {
+	var n int64
+	switch x := v.(type) {
+	case json.Number:
+		i, err := strconv.ParseInt(string(x), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Int cannot represent %s", x)
+		}
+		n = i
+	case float64:
+		if x != math.Trunc(x) {
+			return nil, fmt.Errorf("Int cannot represent %v", x)
+		}
+		n = int64(x)
+	case int:
+		n = int64(x)
+	case int64:
+		n = x
+	default:
+		return nil, fmt.Errorf("Int cannot represent %v", v)
+	}
+	if n < math.MinInt32 || n > math.MaxInt32 {
+		return nil, fmt.Errorf("Int cannot represent %d", n)
+	}
+	return int(n), nil
+}
+
+func serializeFloat(v any) (any, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	}
+	return nil, fmt.Errorf("Float cannot represent %v", v)
+}
+
+func parseFloat(v any) (any, error) {
+	switch x := v.(type) {
+	case json.Number:
+		f, err := x.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("Float cannot represent %s", x)
+		}
+		return f, nil
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	}
+	return nil, fmt.Errorf("Float cannot represent %v", v)
+}
+
+func serializeString(v any) (any, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case fmt.Stringer:
+		return s.String(), nil
+	}
+	return nil, fmt.Errorf("String cannot represent %v", v)
+}
+
+func parseString(v any) (any, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("String cannot represent %v", v)
+}
+
+func serializeBoolean(v any) (any, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("Boolean cannot represent %v", v)
+}
+
+func parseBoolean(v any) (any, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("Boolean cannot represent %v", v)
+}
+
+func serializeID(v any) (any, error) {
+	switch id := v.(type) {
+	case string:
+		return id, nil
+	case fmt.Stringer:
+		return id.String(), nil
+	}
+	return nil, fmt.Errorf("ID cannot represent %v", v)
+}
+
+func parseID(v any) (any, error) {
+	switch id := v.(type) {
+	case string:
+		return id, nil
+	case json.Number:
+		return string(id), nil
+	}
+	return nil, fmt.Errorf("ID cannot represent %v", v)
+}
@@ -0,0 +1,429 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+)
+
+// validator checks a document against a schema before it is executed, so
+// that execution only meets errors raised by resolvers and variables
+type validator struct {
+	schema *Schema
+	doc    *document
+	errors []*Error
+	seen   map[string]bool
+
+	// Per operation state
+	op       *operation
+	varDefs  map[string]*varDef
+	usedVars map[string]bool
+	spread   map[string]bool
+}
+
+// validate returns the errors that keep doc from being executed
+func validate(schema *Schema, doc *document) []*Error {
+	v := &validator{schema: schema, doc: doc, seen: map[string]bool{}}
+
+	names := map[string]bool{}
+	used := map[string]bool{}
+	for _, op := range doc.operations {
+		if op.name != "" {
+			if names[op.name] {
+				v.errorf(nil, "There can be only one operation named %q.", op.name)
+			}
+			names[op.name] = true
+		} else if len(doc.operations) > 1 {
+			v.errorf(nil, "This anonymous operation must be the only defined operation.")
+		}
+		v.operation(op)
+		for name := range v.spread {
+			used[name] = true
+		}
+	}
+
+	for _, name := range sortedKeys(doc.fragments) {
+		frag := doc.fragments[name]
+		if !used[name] {
+			v.errorf(nil, "Fragment %q is never used.", name)
+		}
+		if v.reaches(name, frag.selections, map[string]bool{}) {
+			v.errorf(nil, "Cannot spread fragment %q within itself.", name)
+		}
+	}
+	return v.errors
+}
+
+// errorf records a validation error, dropping repeats of an error met
+// again through a fragment spread more than once
+func (v *validator) errorf(node *fieldNode, format string, args ...any) {
+	err := &Error{Message: fmt.Sprintf(format, args...)}
+	key := err.Message
+	if node != nil {
+		err.Locations = []Location{{Line: node.line, Column: node.col}}
+		key = fmt.Sprintf("%s@%d:%d", key, node.line, node.col)
+	}
+	if v.seen[key] {
+		return
+	}
+	v.seen[key] = true
+	v.errors = append(v.errors, err)
+}
+
+func (v *validator) operation(op *operation) {
+	v.op = op
+	v.varDefs = map[string]*varDef{}
+	v.usedVars = map[string]bool{}
+	v.spread = map[string]bool{}
+
+	var root *Object
+	switch op.kind {
+	case "query":
+		root = v.schema.Query
+	case "mutation":
+		root = v.schema.Mutation
+		if root == nil {
+			v.errorf(nil, "Schema is not configured for mutations.")
+			return
+		}
+	default:
+		v.errorf(nil, "Schema is not configured for %ss.", op.kind)
+		return
+	}
+
+	for _, def := range op.vars {
+		if _, dup := v.varDefs[def.name]; dup {
+			v.errorf(nil, "There can be only one variable named \"$%s\".", def.name)
+			continue
+		}
+		v.varDefs[def.name] = def
+		if !v.knownTypeRef(def.typ) {
+			v.errorf(nil, "Unknown type %q.", baseName(def.typ))
+			continue
+		}
+		t := v.schema.resolveTypeRef(def.typ)
+		if !isInputType(t) {
+			v.errorf(nil, "Variable \"$%s\" cannot be non-input type %q.", def.name, def.typ)
+			continue
+		}
+		if def.def != nil {
+			if _, _, err := coerceLiteral(t, def.def, map[string]any{}); err != nil {
+				v.errorf(nil, "Variable \"$%s\" has invalid default value %s: %v", def.name, printLiteral(def.def), err)
+			}
+		}
+	}
+
+	v.selections(root, op.selections)
+
+	for _, def := range op.vars {
+		if !v.usedVars[def.name] {
+			v.errorf(nil, "Variable \"$%s\" is never used%s.", def.name, v.inOperation())
+		}
+	}
+}
+
+func (v *validator) inOperation() string {
+	if v.op.name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in operation %q", v.op.name)
+}
+
+func (v *validator) knownTypeRef(ref *typeRef) bool {
+	if ref.list != nil {
+		return v.knownTypeRef(ref.list)
+	}
+	return v.schema.types[ref.name] != nil
+}
+
+func baseName(ref *typeRef) string {
+	for ref.list != nil {
+		ref = ref.list
+	}
+	return ref.name
+}
+
+// selections checks a selection set on obj, following fragment spreads
+func (v *validator) selections(obj *Object, sels []selection) {
+	v.fieldConflicts(obj, sels)
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			v.directives(sel, sel.directives)
+			v.field(obj, sel)
+		case *inlineFragment:
+			v.directives(nil, sel.directives)
+			if sel.typeCond != "" && !v.fragmentType(obj, sel.typeCond, "") {
+				continue
+			}
+			v.selections(obj, sel.selections)
+		case *fragmentSpread:
+			v.directives(nil, sel.directives)
+			frag, ok := v.doc.fragments[sel.name]
+			if !ok {
+				v.errorf(nil, "Unknown fragment %q.", sel.name)
+				continue
+			}
+			if v.spread[sel.name] {
+				continue
+			}
+			v.spread[sel.name] = true
+			if !v.fragmentType(obj, frag.typeCond, sel.name) {
+				continue
+			}
+			v.directives(nil, frag.directives)
+			v.selections(obj, frag.selections)
+		}
+	}
+}
+
+// fragmentType checks that a fragment on typeCond can be spread on obj.
+// Every type a fragment can be on is an object type, so only fragments on
+// obj itself apply.
+func (v *validator) fragmentType(obj *Object, typeCond, name string) bool {
+	t, ok := v.schema.types[typeCond]
+	if !ok {
+		v.errorf(nil, "Unknown type %q.", typeCond)
+		return false
+	}
+	if _, isObj := t.(*Object); !isObj {
+		v.errorf(nil, "Fragment cannot condition on non composite type %q.", typeCond)
+		return false
+	}
+	if t != obj {
+		if name == "" {
+			v.errorf(nil, "Fragment cannot be spread here as objects of type %q can never be of type %q.", obj.Name, typeCond)
+		} else {
+			v.errorf(nil, "Fragment %q cannot be spread here as objects of type %q can never be of type %q.", name, obj.Name, typeCond)
+		}
+		return false
+	}
+	return true
+}
+
+func (v *validator) field(obj *Object, node *fieldNode) {
+	if node.name == "__typename" {
+		if len(node.args) > 0 || len(node.selections) > 0 {
+			v.errorf(node, "Field \"__typename\" must not have arguments or a selection.")
+		}
+		return
+	}
+	field := v.schema.fieldDef(obj, node.name)
+	if field == nil {
+		v.errorf(node, "Cannot query field %q on type %q.", node.name, obj.Name)
+		return
+	}
+
+	v.arguments(node, fmt.Sprintf("field %q", obj.Name+"."+field.Name), field.Args, node.args)
+
+	switch t := named(field.Type).(type) {
+	case *Object:
+		if len(node.selections) == 0 {
+			v.errorf(node, "Field %q of type %q must have a selection of subfields.", node.name, field.Type)
+			return
+		}
+		v.selections(t, node.selections)
+	default:
+		if len(node.selections) > 0 {
+			v.errorf(node, "Field %q must not have a selection since type %q has no subfields.", node.name, field.Type)
+		}
+	}
+}
+
+var (
+	ifArg           = []*Argument{{Name: "if", Type: &NonNull{Of: Boolean}}}
+	knownDirectives = map[string][]*Argument{"include": ifArg, "skip": ifArg}
+)
+
+func (v *validator) directives(node *fieldNode, dirs []*directive) {
+	for _, d := range dirs {
+		args, ok := knownDirectives[d.name]
+		if !ok {
+			v.errorf(node, "Unknown directive \"@%s\".", d.name)
+			continue
+		}
+		v.arguments(node, fmt.Sprintf("directive \"@%s\"", d.name), args, d.args)
+	}
+}
+
+// arguments checks the arguments given to a field or directive against
+// their definitions
+func (v *validator) arguments(node *fieldNode, owner string, defs []*Argument, given []*argNode) {
+	seen := map[string]bool{}
+	for _, a := range given {
+		if seen[a.name] {
+			v.errorf(node, "There can be only one argument named %q.", a.name)
+			continue
+		}
+		seen[a.name] = true
+		def := findArg(defs, a.name)
+		if def == nil {
+			v.errorf(node, "Unknown argument %q on %s.", a.name, owner)
+			continue
+		}
+		if _, _, err := coerceLiteral(def.Type, a.val, nil); err != nil {
+			v.errorf(node, "Argument %q has invalid value %s: %v", a.name, printLiteral(a.val), err)
+			continue
+		}
+		v.variables(node, def.Type, def.Default != nil, a.val)
+	}
+	for _, def := range defs {
+		if _, required := def.Type.(*NonNull); required && def.Default == nil && !seen[def.Name] {
+			v.errorf(node, "Argument %q of type %q is required on %s, but it was not provided.", def.Name, def.Type, owner)
+		}
+	}
+}
+
+// variables checks the variables used in a value of type t are defined
+// by the operation with a type that fits
+func (v *validator) variables(node *fieldNode, t Type, hasDefault bool, val *value) {
+	switch val.kind {
+	case valueVariable:
+		v.usedVars[val.raw] = true
+		def, ok := v.varDefs[val.raw]
+		if !ok {
+			v.errorf(node, "Variable \"$%s\" is not defined%s.", val.raw, v.inOperation())
+			return
+		}
+		if !v.knownTypeRef(def.typ) {
+			return
+		}
+		varType := v.schema.resolveTypeRef(def.typ)
+		if nn, ok := t.(*NonNull); ok && (hasDefault || def.def != nil && def.def.kind != valueNull) {
+			if _, varNonNull := varType.(*NonNull); !varNonNull {
+				t = nn.Of
+			}
+		}
+		if !fitsType(varType, t) {
+			v.errorf(node, "Variable \"$%s\" of type %q used in position expecting type %q.", val.raw, def.typ, t)
+		}
+	case valueList:
+		if nn, ok := t.(*NonNull); ok {
+			t = nn.Of
+		}
+		itemType := t
+		if l, ok := t.(*List); ok {
+			itemType = l.Of
+		}
+		for _, item := range val.list {
+			v.variables(node, itemType, false, item)
+		}
+	case valueObject:
+		in, ok := named(t).(*InputObject)
+		if !ok {
+			return
+		}
+		for _, f := range val.fields {
+			if def := findArg(in.Fields, f.name); def != nil {
+				v.variables(node, def.Type, def.Default != nil, f.val)
+			}
+		}
+	default:
+		if l, ok := t.(*List); ok {
+			v.variables(node, l.Of, false, val)
+		}
+	}
+}
+
+// fitsType reports whether a variable of type varType can be used where
+// type t is expected
+func fitsType(varType, t Type) bool {
+	if nn, ok := t.(*NonNull); ok {
+		vnn, ok := varType.(*NonNull)
+		return ok && fitsType(vnn.Of, nn.Of)
+	}
+	if vnn, ok := varType.(*NonNull); ok {
+		return fitsType(vnn.Of, t)
+	}
+	if l, ok := t.(*List); ok {
+		vl, ok := varType.(*List)
+		return ok && fitsType(vl.Of, l.Of)
+	}
+	if _, ok := varType.(*List); ok {
+		return false
+	}
+	return varType == t
+}
+
+// fieldConflicts checks that fields sharing a response key in a selection
+// set select the same field with the same arguments, so they can be merged
+func (v *validator) fieldConflicts(obj *Object, sels []selection) {
+	byKey := map[string]*fieldNode{}
+	var walk func(sels []selection, visited map[string]bool)
+	walk = func(sels []selection, visited map[string]bool) {
+		for _, sel := range sels {
+			switch sel := sel.(type) {
+			case *fieldNode:
+				key := sel.responseKey()
+				prev, ok := byKey[key]
+				if !ok {
+					byKey[key] = sel
+					continue
+				}
+				if prev.name != sel.name {
+					v.errorf(sel, "Fields %q conflict because %q and %q are different fields.", key, prev.name, sel.name)
+				} else if printArgs(prev.args) != printArgs(sel.args) {
+					v.errorf(sel, "Fields %q conflict because they have differing arguments.", key)
+				}
+			case *inlineFragment:
+				if sel.typeCond == "" || sel.typeCond == obj.Name {
+					walk(sel.selections, visited)
+				}
+			case *fragmentSpread:
+				frag, ok := v.doc.fragments[sel.name]
+				if !ok || visited[sel.name] || frag.typeCond != obj.Name {
+					continue
+				}
+				visited[sel.name] = true
+				walk(frag.selections, visited)
+			}
+		}
+	}
+	walk(sels, map[string]bool{})
+}
+
+func printArgs(args []*argNode) string {
+	printed := make([]string, len(args))
+	for i, a := range args {
+		printed[i] = a.name + ": " + printLiteral(a.val)
+	}
+	sort.Strings(printed)
+	return fmt.Sprint(printed)
+}
+
+// reaches reports whether sels spread the fragment named target, directly
+// or through other fragments
+func (v *validator) reaches(target string, sels []selection, visited map[string]bool) bool {
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			if v.reaches(target, sel.selections, visited) {
+				return true
+			}
+		case *inlineFragment:
+			if v.reaches(target, sel.selections, visited) {
+				return true
+			}
+		case *fragmentSpread:
+			if sel.name == target {
+				return true
+			}
+			next, ok := v.doc.fragments[sel.name]
+			if ok && !visited[sel.name] {
+				visited[sel.name] = true
+				if v.reaches(target, next.selections, visited) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
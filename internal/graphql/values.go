@@ -0,0 +1,271 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// variableRef stands in for a variable's value while literals are checked
+// before any variables are known
+type variableRef struct{}
+
+// coerceVariable converts the JSON value of a variable to the type it was
+// declared with
+func coerceVariable(t Type, v any) (any, error) {
+	if nn, ok := t.(*NonNull); ok {
+		if v == nil {
+			return nil, fmt.Errorf("Expected non-nullable type %q not to be null", t)
+		}
+		return coerceVariable(nn.Of, v)
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items, ok := v.([]any)
+		if !ok {
+			// A single value is accepted as a list of one
+			item, err := coerceVariable(t.Of, v)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			c, err := coerceVariable(t.Of, item)
+			if err != nil {
+				return nil, fmt.Errorf("at index %d: %w", i, err)
+			}
+			list[i] = c
+		}
+		return list, nil
+	case *InputObject:
+		fields, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("Expected type %q to be an object", t.Name)
+		}
+		for name := range fields {
+			if findArg(t.Fields, name) == nil {
+				return nil, fmt.Errorf("Field %q is not defined by type %q", name, t.Name)
+			}
+		}
+		obj := make(map[string]any, len(t.Fields))
+		for _, f := range t.Fields {
+			fv, present := fields[f.Name]
+			if !present {
+				if f.Default != nil {
+					obj[f.Name] = f.Default
+				} else if _, ok := f.Type.(*NonNull); ok {
+					return nil, fmt.Errorf("Field %q of required type %q was not provided", f.Name, f.Type)
+				}
+				continue
+			}
+			c, err := coerceVariable(f.Type, fv)
+			if err != nil {
+				return nil, fmt.Errorf("in field %q: %w", f.Name, err)
+			}
+			obj[f.Name] = c
+		}
+		return obj, nil
+	case *Enum:
+		s, ok := v.(string)
+		if !ok || !t.has(s) {
+			return nil, fmt.Errorf("Value %s does not exist in %q enum", printJSON(v), t.Name)
+		}
+		return s, nil
+	case *Scalar:
+		return t.Parse(v)
+	}
+	return nil, fmt.Errorf("%q is not an input type", t)
+}
+
+// coerceLiteral converts a value literal to type t, looking variables up in
+// vars. Variables missing from vars are reported as not present. With nil
+// vars every variable is taken to be valid, which checks a literal before
+// the request's variables are known.
+func coerceLiteral(t Type, val *value, vars map[string]any) (result any, present bool, err error) {
+	if val.kind == valueVariable {
+		if vars == nil {
+			return variableRef{}, true, nil
+		}
+		v, ok := vars[val.raw]
+		if ok && v == nil {
+			if _, nonNull := t.(*NonNull); nonNull {
+				return nil, true, fmt.Errorf("Expected non-nullable type %q not to be null", t)
+			}
+		}
+		return v, ok, nil
+	}
+
+	if nn, ok := t.(*NonNull); ok {
+		if val.kind == valueNull {
+			return nil, true, fmt.Errorf("Expected value of type %q, found null", t)
+		}
+		return coerceLiteral(nn.Of, val, vars)
+	}
+	if val.kind == valueNull {
+		return nil, true, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		if val.kind != valueList {
+			item, _, err := coerceLiteral(t.Of, val, vars)
+			if err != nil {
+				return nil, true, err
+			}
+			return []any{item}, true, nil
+		}
+		list := make([]any, 0, len(val.list))
+		for _, item := range val.list {
+			c, present, err := coerceLiteral(t.Of, item, vars)
+			if err != nil {
+				return nil, true, err
+			}
+			if !present {
+				c = nil
+			}
+			list = append(list, c)
+		}
+		return list, true, nil
+	case *InputObject:
+		if val.kind != valueObject {
+			return nil, true, fmt.Errorf("Expected value of type %q, found %s", t.Name, printLiteral(val))
+		}
+		given := make(map[string]*value, len(val.fields))
+		for _, f := range val.fields {
+			if findArg(t.Fields, f.name) == nil {
+				return nil, true, fmt.Errorf("Field %q is not defined by type %q", f.name, t.Name)
+			}
+			if _, dup := given[f.name]; dup {
+				return nil, true, fmt.Errorf("There can be only one input field named %q", f.name)
+			}
+			given[f.name] = f.val
+		}
+		obj := make(map[string]any, len(t.Fields))
+		for _, f := range t.Fields {
+			fv, ok := given[f.Name]
+			var c any
+			present := false
+			if ok {
+				var err error
+				if c, present, err = coerceLiteral(f.Type, fv, vars); err != nil {
+					return nil, true, fmt.Errorf("in field %q: %w", f.Name, err)
+				}
+			}
+			switch {
+			case present:
+				obj[f.Name] = c
+			case f.Default != nil:
+				obj[f.Name] = f.Default
+			default:
+				if _, nonNull := f.Type.(*NonNull); nonNull {
+					return nil, true, fmt.Errorf("Field %q of required type %q was not provided", f.Name, f.Type)
+				}
+			}
+		}
+		return obj, true, nil
+	case *Enum:
+		if val.kind != valueEnum || !t.has(val.raw) {
+			return nil, true, fmt.Errorf("Value %s does not exist in %q enum", printLiteral(val), t.Name)
+		}
+		return val.raw, true, nil
+	case *Scalar:
+		var raw any
+		switch val.kind {
+		case valueInt, valueFloat:
+			raw = json.Number(val.raw)
+		case valueString:
+			raw = val.raw
+		case valueBoolean:
+			raw = val.raw == "true"
+		default:
+			return nil, true, fmt.Errorf("Expected value of type %q, found %s", t.Name, printLiteral(val))
+		}
+		if val.kind == valueFloat && t == Int {
+			return nil, true, fmt.Errorf("Int cannot represent non-integer value %s", val.raw)
+		}
+		c, err := t.Parse(raw)
+		return c, true, err
+	}
+	return nil, true, fmt.Errorf("%q is not an input type", t)
+}
+
+// printLiteral prints a value literal as it would appear in a query
+func printLiteral(val *value) string {
+	switch val.kind {
+	case valueVariable:
+		return "$" + val.raw
+	case valueString:
+		return strconv.Quote(val.raw)
+	case valueList:
+		items := make([]string, len(val.list))
+		for i, item := range val.list {
+			items[i] = printLiteral(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case valueObject:
+		fields := make([]string, len(val.fields))
+		for i, f := range val.fields {
+			fields[i] = f.name + ": " + printLiteral(f.val)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return val.raw
+}
+
+// printValue prints a Go input value of type t as a GraphQL literal, for
+// the default values reported by introspection
+func printValue(t Type, v any) string {
+	if v == nil {
+		return "null"
+	}
+	switch t := t.(type) {
+	case *NonNull:
+		return printValue(t.Of, v)
+	case *List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return printValue(t.Of, v)
+		}
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = printValue(t.Of, rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *InputObject:
+		m, _ := v.(map[string]any)
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(keys))
+		for _, k := range keys {
+			ft := Type(String)
+			if f := findArg(t.Fields, k); f != nil {
+				ft = f.Type
+			}
+			fields = append(fields, k+": "+printValue(ft, m[k]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case *Enum:
+		return fmt.Sprint(v)
+	}
+	return printJSON(v)
+}
+
+func printJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
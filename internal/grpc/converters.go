@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/conall/outalator/domain"
 	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -45,9 +45,14 @@ func convertProtoToTimestampPtr(t *timestamppb.Timestamp) *time.Time {
 	return &ts
 }
 
-// parseUUID parses a string UUID and returns error if invalid
+// parseUUID parses a string UUID, reporting an invalid one as
+// domain.ErrInvalidInput
 func parseUUID(s string) (uuid.UUID, error) {
-	return uuid.Parse(s)
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid id %q", domain.ErrInvalidInput, s)
+	}
+	return id, nil
 }
 
 // parseUUIDPtr parses a string UUID pointer
@@ -55,7 +60,7 @@ func parseUUIDPtr(s string) (*uuid.UUID, error) {
 	if s == "" {
 		return nil, nil
 	}
-	id, err := uuid.Parse(s)
+	id, err := parseUUID(s)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +127,7 @@ func setSourceMetadata(pbAlert *pb.Alert, source string, metadata map[string]any
 		if v, ok := metadata["escalation_policy"].(string); ok {
 			pd.EscalationPolicy = v
 		}
-		if v, ok := metadata["assignees"].([]string); ok {
+		if v, ok := stringSlice(metadata["assignees"]); ok {
 			pd.Assignees = v
 		}
 		if v, ok := metadata["urgency"].(string); ok {
@@ -141,13 +146,13 @@ func setSourceMetadata(pbAlert *pb.Alert, source string, metadata map[string]any
 		if v, ok := metadata["entity"].(string); ok {
 			og.Entity = v
 		}
-		if v, ok := metadata["responders"].([]string); ok {
+		if v, ok := stringSlice(metadata["responders"]); ok {
 			og.Responders = v
 		}
-		if v, ok := metadata["visible_to"].([]string); ok {
+		if v, ok := stringSlice(metadata["visible_to"]); ok {
 			og.VisibleTo = v
 		}
-		if v, ok := metadata["actions"].([]string); ok {
+		if v, ok := stringSlice(metadata["actions"]); ok {
 			og.Actions = v
 		}
 		if v, ok := metadata["priority"].(string); ok {
@@ -169,6 +174,24 @@ func setSourceMetadata(pbAlert *pb.Alert, source string, metadata map[string]any
 	}
 }
 
+// stringSlice returns v as a string slice. Metadata read back from storage
+// holds JSON arrays as []any rather than the []string it was written with.
+func stringSlice(v any) ([]string, bool) {
+	switch v := v.(type) {
+	case []string:
+		return v, true
+	case []any:
+		strs := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs, true
+	}
+	return nil, false
+}
+
 // protoToSourceMetadata converts the source_metadata oneof field of a pb.Alert
 // to a domain map. Accepts *pb.Alert (not the unexported isAlert_SourceMetadata
 // interface) so the caller can type-switch on the exported concrete types.
@@ -226,12 +249,19 @@ func OutageDomainToProto(o *domain.Outage) (*pb.Outage, error) {
 		return nil, fmt.Errorf("failed to convert custom_fields: %w", err)
 	}
 
+	var parentID string
+	if o.ParentID != nil {
+		parentID = o.ParentID.String()
+	}
+
 	pbOutage := &pb.Outage{
 		Id:           o.ID.String(),
 		Title:        o.Title,
 		Description:  o.Description,
 		Status:       o.Status,
 		Severity:     o.Severity,
+		OwningTeam:   o.OwningTeam,
+		ParentId:     parentID,
 		CreatedAt:    convertTimestampToProto(o.CreatedAt),
 		UpdatedAt:    convertTimestampToProto(o.UpdatedAt),
 		ResolvedAt:   convertTimestampPtrToProto(o.ResolvedAt),
@@ -266,6 +296,11 @@ func OutageDomainToProto(o *domain.Outage) (*pb.Outage, error) {
 		pbOutage.Tags = append(pbOutage.Tags, pbTag)
 	}
 
+	// Convert links to other outages
+	for i := range o.Relations {
+		pbOutage.Relations = append(pbOutage.Relations, OutageRelationDomainToProto(&o.Relations[i]))
+	}
+
 	return pbOutage, nil
 }
 
@@ -280,8 +315,14 @@ func OutageProtoToDomain(pb *pb.Outage) (*domain.Outage, error) {
 		return nil, fmt.Errorf("invalid outage id: %w", err)
 	}
 
+	parentID, err := parseUUIDPtr(pb.ParentId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parent_id: %w", err)
+	}
+
 	outage := &domain.Outage{
 		ID:           id,
+		ParentID:     parentID,
 		Title:        pb.Title,
 		Description:  pb.Description,
 		Status:       pb.Status,
@@ -320,6 +361,15 @@ func OutageProtoToDomain(pb *pb.Outage) (*domain.Outage, error) {
 		outage.Tags = append(outage.Tags, *tag)
 	}
 
+	// Convert links to other outages
+	for _, pbRelation := range pb.Relations {
+		relation, err := OutageRelationProtoToDomain(pbRelation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert relation: %w", err)
+		}
+		outage.Relations = append(outage.Relations, *relation)
+	}
+
 	return outage, nil
 }
 
@@ -344,6 +394,7 @@ func AlertDomainToProto(a *domain.Alert) (*pb.Alert, error) {
 		ExternalId:     a.ExternalID,
 		Source:         a.Source,
 		TeamName:       a.TeamName,
+		TeamNames:      a.TeamNames,
 		Title:          a.Title,
 		Description:    a.Description,
 		Severity:       a.Severity,
@@ -380,6 +431,7 @@ func AlertProtoToDomain(pb *pb.Alert) (*domain.Alert, error) {
 		ExternalID:     pb.ExternalId,
 		Source:         pb.Source,
 		TeamName:       pb.TeamName,
+		TeamNames:      pb.TeamNames,
 		Title:          pb.Title,
 		Description:    pb.Description,
 		Severity:       pb.Severity,
@@ -408,9 +460,15 @@ func NoteDomainToProto(n *domain.Note) (*pb.Note, error) {
 		return nil, fmt.Errorf("failed to convert custom_fields: %w", err)
 	}
 
+	var parentNoteID string
+	if n.ParentNoteID != nil {
+		parentNoteID = n.ParentNoteID.String()
+	}
+
 	return &pb.Note{
 		Id:           n.ID.String(),
 		OutageId:     n.OutageID.String(),
+		ParentNoteId: parentNoteID,
 		Content:      n.Content,
 		Format:       n.Format,
 		Author:       n.Author,
@@ -437,9 +495,15 @@ func NoteProtoToDomain(pb *pb.Note) (*domain.Note, error) {
 		return nil, fmt.Errorf("invalid outage_id: %w", err)
 	}
 
+	parentNoteID, err := parseUUIDPtr(pb.ParentNoteId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parent_note_id: %w", err)
+	}
+
 	return &domain.Note{
 		ID:           id,
 		OutageID:     outageID,
+		ParentNoteID: parentNoteID,
 		Content:      pb.Content,
 		Format:       pb.Format,
 		Author:       pb.Author,
@@ -501,6 +565,80 @@ func TagProtoToDomain(pb *pb.Tag) (*domain.Tag, error) {
 	}, nil
 }
 
+// OutageRelationDomainToProto converts domain.OutageRelation to pb.OutageRelation
+func OutageRelationDomainToProto(r *domain.OutageRelation) *pb.OutageRelation {
+	if r == nil {
+		return nil
+	}
+
+	return &pb.OutageRelation{
+		Id:                 r.ID.String(),
+		OutageId:           r.OutageID.String(),
+		RelatedOutageId:    r.RelatedOutageID.String(),
+		RelatedOutageTitle: r.RelatedOutageTitle,
+		Type:               r.Type,
+		Description:        r.Description,
+		CreatedBy:          r.CreatedBy,
+		CreatedAt:          convertTimestampToProto(r.CreatedAt),
+	}
+}
+
+// OutageRelationProtoToDomain converts pb.OutageRelation to domain.OutageRelation
+func OutageRelationProtoToDomain(pb *pb.OutageRelation) (*domain.OutageRelation, error) {
+	if pb == nil {
+		return nil, nil
+	}
+
+	id, err := parseUUID(pb.Id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid relation id: %w", err)
+	}
+
+	outageID, err := parseUUID(pb.OutageId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outage_id: %w", err)
+	}
+
+	relatedOutageID, err := parseUUID(pb.RelatedOutageId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid related_outage_id: %w", err)
+	}
+
+	return &domain.OutageRelation{
+		ID:                 id,
+		OutageID:           outageID,
+		RelatedOutageID:    relatedOutageID,
+		RelatedOutageTitle: pb.RelatedOutageTitle,
+		Type:               pb.Type,
+		Description:        pb.Description,
+		CreatedBy:          pb.CreatedBy,
+		CreatedAt:          convertProtoToTimestamp(pb.CreatedAt),
+	}, nil
+}
+
+// TimelineEventDomainToProto converts domain.TimelineEvent to pb.TimelineEvent
+func TimelineEventDomainToProto(e domain.TimelineEvent) (*pb.TimelineEvent, error) {
+	details, err := mapToProtoStruct(e.Details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert details: %w", err)
+	}
+
+	var childOutageID string
+	if e.ChildOutageID != nil {
+		childOutageID = e.ChildOutageID.String()
+	}
+
+	return &pb.TimelineEvent{
+		Timestamp:     convertTimestampToProto(e.Timestamp),
+		Type:          e.Type,
+		Summary:       e.Summary,
+		Actor:         e.Actor,
+		EntityId:      e.EntityID.String(),
+		ChildOutageId: childOutageID,
+		Details:       details,
+	}, nil
+}
+
 // ============================================================================
 // Request/Response converters: CreateOutage
 // ============================================================================
@@ -508,13 +646,14 @@ func TagProtoToDomain(pb *pb.Tag) (*domain.Tag, error) {
 // CreateOutageRequestProtoToDomain converts pb.CreateOutageRequest to domain.CreateOutageRequest
 func CreateOutageRequestProtoToDomain(pb *pb.CreateOutageRequest) (domain.CreateOutageRequest, error) {
 	if pb == nil {
-		return domain.CreateOutageRequest{}, fmt.Errorf("nil request")
+		return domain.CreateOutageRequest{}, fmt.Errorf("%w: nil request", domain.ErrInvalidInput)
 	}
 
 	req := domain.CreateOutageRequest{
 		Title:        pb.Title,
 		Description:  pb.Description,
 		Severity:     pb.Severity,
+		OwningTeam:   pb.OwningTeam,
 		AlertIDs:     pb.AlertIds,
 		Metadata:     copyStringMap(pb.Metadata),
 		CustomFields: protoStructToMap(pb.CustomFields),
@@ -538,12 +677,13 @@ func CreateOutageRequestProtoToDomain(pb *pb.CreateOutageRequest) (domain.Create
 // UpdateOutageRequestProtoToDomain converts pb.UpdateOutageRequest to domain.UpdateOutageRequest
 func UpdateOutageRequestProtoToDomain(pb *pb.UpdateOutageRequest) (domain.UpdateOutageRequest, error) {
 	if pb == nil {
-		return domain.UpdateOutageRequest{}, fmt.Errorf("nil request")
+		return domain.UpdateOutageRequest{}, fmt.Errorf("%w: nil request", domain.ErrInvalidInput)
 	}
 
 	req := domain.UpdateOutageRequest{
-		Metadata:     copyStringMap(pb.Metadata),
-		CustomFields: protoStructToMap(pb.CustomFields),
+		Metadata:      copyStringMap(pb.Metadata),
+		CustomFields:  protoStructToMap(pb.CustomFields),
+		ResolveAlerts: pb.ResolveAlerts,
 	}
 
 	if pb.Title != nil {
@@ -558,6 +698,9 @@ func UpdateOutageRequestProtoToDomain(pb *pb.UpdateOutageRequest) (domain.Update
 	if pb.Severity != nil {
 		req.Severity = pb.Severity
 	}
+	if pb.OwningTeam != nil {
+		req.OwningTeam = pb.OwningTeam
+	}
 
 	return req, nil
 }
@@ -569,13 +712,19 @@ func UpdateOutageRequestProtoToDomain(pb *pb.UpdateOutageRequest) (domain.Update
 // AddNoteRequestProtoToDomain converts pb.AddNoteRequest to domain.AddNoteRequest
 func AddNoteRequestProtoToDomain(pb *pb.AddNoteRequest) (domain.AddNoteRequest, error) {
 	if pb == nil {
-		return domain.AddNoteRequest{}, fmt.Errorf("nil request")
+		return domain.AddNoteRequest{}, fmt.Errorf("%w: nil request", domain.ErrInvalidInput)
+	}
+
+	parentNoteID, err := parseUUIDPtr(pb.ParentNoteId)
+	if err != nil {
+		return domain.AddNoteRequest{}, err
 	}
 
 	return domain.AddNoteRequest{
 		Content:      pb.Content,
 		Format:       pb.Format,
 		Author:       pb.Author,
+		ParentNoteID: parentNoteID,
 		Metadata:     copyStringMap(pb.Metadata),
 		CustomFields: protoStructToMap(pb.CustomFields),
 	}, nil
@@ -588,8 +737,81 @@ func AddNoteRequestProtoToDomain(pb *pb.AddNoteRequest) (domain.AddNoteRequest,
 // UpdateNoteRequestProtoToDomain converts pb.UpdateNoteRequest to update parameters
 func UpdateNoteRequestProtoToDomain(pb *pb.UpdateNoteRequest) (content, format *string, metadata map[string]string, customFields map[string]any, err error) {
 	if pb == nil {
-		return nil, nil, nil, nil, fmt.Errorf("nil request")
+		return nil, nil, nil, nil, fmt.Errorf("%w: nil request", domain.ErrInvalidInput)
 	}
 
 	return pb.Content, pb.Format, copyStringMap(pb.Metadata), protoStructToMap(pb.CustomFields), nil
 }
+
+// ============================================================================
+// Request/Response converters: UpdateAlert
+// ============================================================================
+
+// UpdateAlertRequestProtoToDomain converts pb.UpdateAlertRequest to domain.UpdateAlertRequest
+func UpdateAlertRequestProtoToDomain(pb *pb.UpdateAlertRequest) (domain.UpdateAlertRequest, error) {
+	if pb == nil {
+		return domain.UpdateAlertRequest{}, fmt.Errorf("%w: nil request", domain.ErrInvalidInput)
+	}
+
+	return domain.UpdateAlertRequest{
+		Title:          pb.Title,
+		Description:    pb.Description,
+		Severity:       pb.Severity,
+		AcknowledgedAt: convertProtoToTimestampPtr(pb.AcknowledgedAt),
+		ResolvedAt:     convertProtoToTimestampPtr(pb.ResolvedAt),
+		Metadata:       copyStringMap(pb.Metadata),
+		CustomFields:   protoStructToMap(pb.CustomFields),
+	}, nil
+}
+
+// ============================================================================
+// Saved view converters
+// ============================================================================
+
+// SavedViewDomainToProto converts domain.SavedView to pb.SavedView
+func SavedViewDomainToProto(v *domain.SavedView) *pb.SavedView {
+	if v == nil {
+		return nil
+	}
+
+	filter := &pb.ViewFilter{
+		Statuses:    v.Filter.Statuses,
+		MinSeverity: v.Filter.MinSeverity,
+		Teams:       v.Filter.Teams,
+	}
+	for _, tag := range v.Filter.Tags {
+		filter.Tags = append(filter.Tags, &pb.TagFilter{Key: tag.Key, Value: tag.Value})
+	}
+
+	return &pb.SavedView{
+		Id:            v.ID.String(),
+		Name:          v.Name,
+		Owner:         v.Owner,
+		Filter:        filter,
+		SlackChannel:  v.SlackChannel,
+		SummaryPeriod: v.SummaryPeriod,
+		LastSummaryAt: convertTimestampPtrToProto(v.LastSummaryAt),
+		CreatedAt:     convertTimestampToProto(v.CreatedAt),
+		UpdatedAt:     convertTimestampToProto(v.UpdatedAt),
+	}
+}
+
+// CreateViewRequestProtoToDomain converts pb.CreateViewRequest to domain.CreateSavedViewRequest
+func CreateViewRequestProtoToDomain(pb *pb.CreateViewRequest) domain.CreateSavedViewRequest {
+	req := domain.CreateSavedViewRequest{
+		Name:          pb.GetName(),
+		SlackChannel:  pb.GetSlackChannel(),
+		SummaryPeriod: pb.GetSummaryPeriod(),
+	}
+	if filter := pb.GetFilter(); filter != nil {
+		req.Filter = domain.ViewFilter{
+			Statuses:    filter.Statuses,
+			MinSeverity: filter.MinSeverity,
+			Teams:       filter.Teams,
+		}
+		for _, tag := range filter.Tags {
+			req.Filter.Tags = append(req.Filter.Tags, domain.TagFilter{Key: tag.GetKey(), Value: tag.GetValue()})
+		}
+	}
+	return req
+}
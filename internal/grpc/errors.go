@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/conall/outalator/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryErrorInterceptor converts the domain errors returned by handlers
+// into gRPC statuses, so clients see NotFound, InvalidArgument,
+// AlreadyExists or PermissionDenied rather than Unknown. Start installs it
+// innermost, after any interceptors passed to NewServer, so logging and
+// metrics see the codes.
+func UnaryErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		return resp, toStatus(err)
+	}
+}
+
+// toStatus maps err to a gRPC status error. Errors that already carry a
+// status are returned unchanged.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var code codes.Code
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrInvalidInput):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrConflict):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
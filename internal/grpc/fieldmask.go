@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// maskedAssociations returns the associations an outage read mask needs
+// loaded. A nil or empty mask needs them all.
+func maskedAssociations(mask *fieldmaskpb.FieldMask) domain.OutageAssociations {
+	if len(mask.GetPaths()) == 0 {
+		return domain.AllOutageAssociations
+	}
+	var associations domain.OutageAssociations
+	for _, path := range mask.GetPaths() {
+		switch path {
+		case "alerts":
+			associations.Alerts = true
+		case "notes":
+			associations.Notes = true
+		case "tags":
+			associations.Tags = true
+		}
+	}
+	return associations
+}
+
+// maskedRelations reports whether an outage read mask needs the outage's
+// relations loaded. A nil or empty mask needs them.
+func maskedRelations(mask *fieldmaskpb.FieldMask) bool {
+	if len(mask.GetPaths()) == 0 {
+		return true
+	}
+	for _, path := range mask.GetPaths() {
+		if name, _, _ := strings.Cut(path, "."); name == "relations" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMask clears the fields of m that a valid mask leaves out. A nil or
+// empty mask keeps every field.
+func applyMask(m proto.Message, mask *fieldmaskpb.FieldMask) {
+	if len(mask.GetPaths()) == 0 {
+		return
+	}
+	pruneMessage(m.ProtoReflect(), mask.GetPaths())
+}
+
+// pruneMessage keeps the fields of m named by paths relative to m, such as
+// "title" or "resolved_at.seconds"
+func pruneMessage(m protoreflect.Message, paths []string) {
+	keep := map[protoreflect.Name][]string{} // Field to its kept subpaths; nil keeps all of it
+	for _, path := range paths {
+		name, rest, nested := strings.Cut(path, ".")
+		sub, seen := keep[protoreflect.Name(name)]
+		switch {
+		case seen && sub == nil:
+			// The whole field is already kept
+		case nested:
+			keep[protoreflect.Name(name)] = append(sub, rest)
+		default:
+			keep[protoreflect.Name(name)] = nil
+		}
+	}
+
+	var clear []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if sub, ok := keep[fd.Name()]; !ok {
+			clear = append(clear, fd)
+		} else if sub != nil {
+			pruneMessage(v.Message(), sub)
+		}
+		return true
+	})
+	for _, fd := range clear {
+		m.Clear(fd)
+	}
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestOutageReadMask(t *testing.T) {
+	ctx := context.Background()
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	outages := pb.NewOutageServiceClient(dial(t, NewServer(svc, Interceptors{}.Options()...)))
+
+	created, err := outages.CreateOutage(ctx, &pb.CreateOutageRequest{
+		Title: "Checkout errors", Description: "5xx from checkout", Severity: "high",
+		Tags: []*pb.TagInput{{Key: "service", Value: "checkout"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := created.Outage.Id
+	cause, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Payments DB failover", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateOutageRelation(ctx, uuid.MustParse(id), domain.CreateOutageRelationRequest{RelatedOutageID: cause.ID, Type: domain.RelationCausedBy}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddNote(ctx, uuid.MustParse(id), domain.AddNoteRequest{Content: "rolling back", Author: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := outages.GetOutage(ctx, &pb.GetOutageRequest{Id: id, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "title", "tags"}}})
+	if err != nil {
+		t.Fatalf("GetOutage() err = %v", err)
+	}
+	o := got.Outage
+	if o.Id != id || o.Title != "Checkout errors" || len(o.Tags) != 1 {
+		t.Errorf("outage = %v, want id, title and tags", o)
+	}
+	if o.Description != "" || o.Severity != "" || o.CreatedAt != nil || len(o.Notes) != 0 || len(o.Relations) != 0 {
+		t.Errorf("outage = %v, want fields outside the mask cleared", o)
+	}
+
+	got, err = outages.GetOutage(ctx, &pb.GetOutageRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Outage.Notes) != 1 || len(got.Outage.Tags) != 1 || got.Outage.Description == "" {
+		t.Errorf("unmasked outage = %v, want every field", got.Outage)
+	}
+	if r := got.Outage.Relations; len(r) != 1 || r[0].Type != domain.RelationCausedBy || r[0].RelatedOutageTitle != "Payments DB failover" {
+		t.Errorf("relations = %v, want caused_by Payments DB failover", r)
+	}
+
+	got, err = outages.GetOutage(ctx, &pb.GetOutageRequest{Id: id, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "relations"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := got.Outage.Relations; len(r) != 1 || r[0].RelatedOutageId != cause.ID.String() || got.Outage.Title != "" {
+		t.Errorf("masked outage = %v, want only the id and relations", got.Outage)
+	}
+
+	list, err := outages.ListOutages(ctx, &pb.ListOutagesRequest{ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "notes"}}})
+	if err != nil {
+		t.Fatalf("ListOutages() err = %v", err)
+	}
+	if len(list.Outages) != 2 {
+		t.Errorf("listed %d outages, want 2", len(list.Outages))
+	}
+	for _, listed := range list.Outages {
+		if listed.Title != "" || len(listed.Tags) != 0 || len(listed.Relations) != 0 || (listed.Id == id) != (len(listed.Notes) == 1) {
+			t.Errorf("listed = %v, want only the id and notes", list.Outages)
+		}
+	}
+
+	_, err = outages.GetOutage(ctx, &pb.GetOutageRequest{Id: id, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"owner"}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown field: err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestMaskedAssociations(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  domain.OutageAssociations
+	}{
+		{nil, domain.AllOutageAssociations},
+		{[]string{"title", "status"}, domain.OutageAssociations{}},
+		{[]string{"title", "notes"}, domain.OutageAssociations{Notes: true}},
+		{[]string{"alerts", "tags"}, domain.OutageAssociations{Alerts: true, Tags: true}},
+	}
+	for _, tt := range tests {
+		if got := maskedAssociations(&fieldmaskpb.FieldMask{Paths: tt.paths}); got != tt.want {
+			t.Errorf("maskedAssociations(%q) = %+v, want %+v", tt.paths, got, tt.want)
+		}
+	}
+}
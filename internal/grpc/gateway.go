@@ -0,0 +1,295 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+
+	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GatewayPrefix is the path prefix the gateway serves the gRPC services under
+const GatewayPrefix = "/v1/"
+
+// gatewayBufferSize is the buffer of the in-process connection between the
+// gateway and its gRPC server
+const gatewayBufferSize = 1 << 20
+
+// Gateway serves the gRPC services as HTTP/JSON, translating each request
+// into a call on an in-process gRPC server. Calls pass through the same
+// interceptors as calls to the gRPC port, and messages are encoded with the
+// protobuf JSON mapping using the proto field names, so metadata,
+// custom_fields and the source_metadata oneofs look the same as over gRPC.
+type Gateway struct {
+	mux    *runtime.ServeMux
+	conn   *grpc.ClientConn
+	server *grpc.Server
+}
+
+// gatewayRoute maps an HTTP method and path template to an RPC. body is
+// true when the request body holds the fields of the request message;
+// fields not bound by the path or body are read from the query string.
+type gatewayRoute struct {
+	method  string
+	path    string
+	rpc     string
+	body    bool
+	handler func(g *Gateway, rpc, path string, body bool) runtime.HandlerFunc
+}
+
+// gatewayRoutes lists every RPC the gateway exposes. The runtime mux tries
+// routes registered later first, so /v1/tags/search is listed after
+// /v1/tags/{id}.
+var gatewayRoutes = []gatewayRoute{
+	{"POST", "/v1/outages", pb.OutageService_CreateOutage_FullMethodName, true, unary(pb.NewOutageServiceClient, pb.OutageServiceClient.CreateOutage)},
+	{"GET", "/v1/outages", pb.OutageService_ListOutages_FullMethodName, false, unary(pb.NewOutageServiceClient, pb.OutageServiceClient.ListOutages)},
+	{"GET", "/v1/outages/{id}", pb.OutageService_GetOutage_FullMethodName, false, unary(pb.NewOutageServiceClient, pb.OutageServiceClient.GetOutage)},
+	{"PATCH", "/v1/outages/{id}", pb.OutageService_UpdateOutage_FullMethodName, true, unary(pb.NewOutageServiceClient, pb.OutageServiceClient.UpdateOutage)},
+	{"DELETE", "/v1/outages/{id}", pb.OutageService_DeleteOutage_FullMethodName, false, unary(pb.NewOutageServiceClient, pb.OutageServiceClient.DeleteOutage)},
+	{"GET", "/v1/outages/{id}/timeline", pb.OutageService_GetOutageTimeline_FullMethodName, false, unary(pb.NewOutageServiceClient, pb.OutageServiceClient.GetOutageTimeline)},
+
+	{"POST", "/v1/outages/{outage_id}/notes", pb.NoteService_AddNote_FullMethodName, true, unary(pb.NewNoteServiceClient, pb.NoteServiceClient.AddNote)},
+	{"GET", "/v1/outages/{outage_id}/notes", pb.NoteService_ListNotesByOutage_FullMethodName, false, unary(pb.NewNoteServiceClient, pb.NoteServiceClient.ListNotesByOutage)},
+	{"GET", "/v1/notes/{id}", pb.NoteService_GetNote_FullMethodName, false, unary(pb.NewNoteServiceClient, pb.NoteServiceClient.GetNote)},
+	{"PATCH", "/v1/notes/{id}", pb.NoteService_UpdateNote_FullMethodName, true, unary(pb.NewNoteServiceClient, pb.NoteServiceClient.UpdateNote)},
+	{"DELETE", "/v1/notes/{id}", pb.NoteService_DeleteNote_FullMethodName, false, unary(pb.NewNoteServiceClient, pb.NoteServiceClient.DeleteNote)},
+
+	{"POST", "/v1/outages/{outage_id}/tags", pb.TagService_AddTag_FullMethodName, true, unary(pb.NewTagServiceClient, pb.TagServiceClient.AddTag)},
+	{"GET", "/v1/outages/{outage_id}/tags", pb.TagService_ListTagsByOutage_FullMethodName, false, unary(pb.NewTagServiceClient, pb.TagServiceClient.ListTagsByOutage)},
+	{"GET", "/v1/tags/{id}", pb.TagService_GetTag_FullMethodName, false, unary(pb.NewTagServiceClient, pb.TagServiceClient.GetTag)},
+	{"DELETE", "/v1/tags/{id}", pb.TagService_DeleteTag_FullMethodName, false, unary(pb.NewTagServiceClient, pb.TagServiceClient.DeleteTag)},
+	{"GET", "/v1/tags/search", pb.TagService_SearchOutagesByTag_FullMethodName, false, unary(pb.NewTagServiceClient, pb.TagServiceClient.SearchOutagesByTag)},
+
+	{"POST", "/v1/alerts", pb.AlertService_ImportAlert_FullMethodName, true, unary(pb.NewAlertServiceClient, pb.AlertServiceClient.ImportAlert)},
+	{"GET", "/v1/alerts/{id}", pb.AlertService_GetAlert_FullMethodName, false, unary(pb.NewAlertServiceClient, pb.AlertServiceClient.GetAlert)},
+	{"PATCH", "/v1/alerts/{id}", pb.AlertService_UpdateAlert_FullMethodName, true, unary(pb.NewAlertServiceClient, pb.AlertServiceClient.UpdateAlert)},
+	{"POST", "/v1/alerts/{id}/acknowledge", pb.AlertService_AcknowledgeAlert_FullMethodName, true, unary(pb.NewAlertServiceClient, pb.AlertServiceClient.AcknowledgeAlert)},
+	{"POST", "/v1/alerts/{id}/resolve", pb.AlertService_ResolveAlert_FullMethodName, true, unary(pb.NewAlertServiceClient, pb.AlertServiceClient.ResolveAlert)},
+	{"GET", "/v1/outages/{outage_id}/alerts", pb.AlertService_ListAlertsByOutage_FullMethodName, false, unary(pb.NewAlertServiceClient, pb.AlertServiceClient.ListAlertsByOutage)},
+	{"GET", "/v1/sources/{source}/alerts/{external_id}", pb.AlertService_GetAlertByExternalID_FullMethodName, false, unary(pb.NewAlertServiceClient, pb.AlertServiceClient.GetAlertByExternalID)},
+
+	{"POST", "/v1/views", pb.ViewService_CreateView_FullMethodName, true, unary(pb.NewViewServiceClient, pb.ViewServiceClient.CreateView)},
+	{"GET", "/v1/views", pb.ViewService_ListViews_FullMethodName, false, unary(pb.NewViewServiceClient, pb.ViewServiceClient.ListViews)},
+	{"GET", "/v1/views/{id}", pb.ViewService_GetView_FullMethodName, false, unary(pb.NewViewServiceClient, pb.ViewServiceClient.GetView)},
+	{"DELETE", "/v1/views/{id}", pb.ViewService_DeleteView_FullMethodName, false, unary(pb.NewViewServiceClient, pb.ViewServiceClient.DeleteView)},
+	{"GET", "/v1/views/{id}/outages", pb.ViewService_ExecuteView_FullMethodName, false, unary(pb.NewViewServiceClient, pb.ViewServiceClient.ExecuteView)},
+
+	{"GET", "/v1/health", pb.HealthService_Check_FullMethodName, false, unary(pb.NewHealthServiceClient, pb.HealthServiceClient.Check)},
+}
+
+// readRPCs are the full method names of the RPCs the gateway serves with
+// GET, which only read
+var readRPCs = func() map[string]bool {
+	rpcs := make(map[string]bool)
+	for _, route := range gatewayRoutes {
+		if route.method == http.MethodGet {
+			rpcs[route.rpc] = true
+		}
+	}
+	return rpcs
+}()
+
+// Gateway starts an in-process gRPC server with the options given to
+// NewServer and returns a gateway serving its services under
+// GatewayPrefix. It works whether or not Start serves the gRPC port. Close
+// the gateway to stop the in-process server.
+func (s *Server) Gateway() (*Gateway, error) {
+	opts := append(slices.Clip(s.opts), grpc.ChainUnaryInterceptor(gatewayCallerInterceptor(), UnaryErrorInterceptor()))
+	srv := grpc.NewServer(opts...)
+	s.RegisterServices(srv)
+
+	lis := bufconn.Listen(gatewayBufferSize)
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///outalator",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		srv.Stop()
+		return nil, fmt.Errorf("failed to connect gateway to gRPC server: %w", err)
+	}
+
+	g := &Gateway{
+		mux: runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+		})),
+		conn:   conn,
+		server: srv,
+	}
+	for _, rt := range gatewayRoutes {
+		if err := g.mux.HandlePath(rt.method, rt.path, rt.handler(g, rt.rpc, rt.path, rt.body)); err != nil {
+			g.Close()
+			return nil, fmt.Errorf("invalid gateway route %s %s: %w", rt.method, rt.path, err)
+		}
+	}
+	return g, nil
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Close closes the gateway's connection and stops its gRPC server, failing
+// calls still in flight
+func (g *Gateway) Close() {
+	g.conn.Close()
+	g.server.Stop()
+}
+
+// unary returns the HTTP handler of a route calling an RPC through a
+// generated client method, such as pb.OutageServiceClient.GetOutage on the
+// client newClient creates
+func unary[Client any, Req any, Resp proto.Message, PReq interface {
+	*Req
+	proto.Message
+}](newClient func(grpc.ClientConnInterface) Client, call func(Client, context.Context, PReq, ...grpc.CallOption) (Resp, error)) func(g *Gateway, rpc, path string, body bool) runtime.HandlerFunc {
+	return func(g *Gateway, rpc, path string, body bool) runtime.HandlerFunc {
+		client := newClient(g.conn)
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			inbound, outbound := runtime.MarshalerForRequest(g.mux, r)
+			ctx, err := runtime.AnnotateContext(r.Context(), g.mux, r, rpc, runtime.WithHTTPPathPattern(path))
+			if err != nil {
+				runtime.HTTPError(ctx, g.mux, outbound, w, r, err)
+				return
+			}
+
+			req := PReq(new(Req))
+			if err := decodeRequest(req, r, inbound, pathParams, body); err != nil {
+				runtime.HTTPError(ctx, g.mux, outbound, w, r, err)
+				return
+			}
+			signedInAs(r.Context(), req)
+			ctx = forwardCaller(r.Context(), ctx)
+
+			var md runtime.ServerMetadata
+			resp, err := call(client, ctx, req, grpc.Header(&md.HeaderMD), grpc.Trailer(&md.TrailerMD))
+			ctx = runtime.NewServerMetadataContext(ctx, md)
+			if err != nil {
+				runtime.HTTPError(ctx, g.mux, outbound, w, r, err)
+				return
+			}
+			runtime.ForwardResponseMessage(ctx, g.mux, outbound, w, r, resp)
+		}
+	}
+}
+
+// decodeRequest fills req from the request body, when the route takes one,
+// then the path parameters, then the query string. Path parameters override
+// fields of the same name in the body.
+func decodeRequest(req proto.Message, r *http.Request, inbound runtime.Marshaler, pathParams map[string]string, body bool) error {
+	var bound [][]string
+	if body {
+		if err := inbound.NewDecoder(r.Body).Decode(req); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+		}
+	}
+	for name, value := range pathParams {
+		if err := runtime.PopulateFieldFromPath(req, name, value); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid %s: %v", name, err)
+		}
+		bound = append(bound, []string{name})
+	}
+	if body {
+		// Every field can be set in the body, so the query string is ignored
+		return nil
+	}
+	if err := runtime.PopulateQueryParameters(req, r.URL.Query(), utilities.NewDoubleArray(bound)); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid query: %v", err)
+	}
+	return nil
+}
+
+// Metadata keys carrying the HTTP caller from the gateway to its in-process
+// gRPC server. They are only trusted on that connection, and any a client
+// sends as Grpc-Metadata headers are dropped.
+const (
+	gatewayUserKey      = "outalator-gateway-user-bin"
+	gatewayAnonymousKey = "outalator-gateway-anonymous"
+)
+
+// forwardCaller returns the outgoing context ctx with the user or anonymous
+// access of the HTTP request context from, so the service applies the same
+// team checks as for the REST API
+func forwardCaller(from, ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Delete(gatewayUserKey)
+	md.Delete(gatewayAnonymousKey)
+	if user, err := auth.GetUserFromContext(from); err == nil {
+		if encoded, err := json.Marshal(user); err == nil {
+			md.Set(gatewayUserKey, string(encoded))
+		}
+	}
+	if access, ok := auth.AnonymousAccess(from); ok {
+		md.Set(gatewayAnonymousKey, access)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// gatewayCallerInterceptor restores the caller forwardCaller sent. The
+// gateway's gRPC server only listens on its in-process connection, so the
+// metadata can only come from the gateway.
+func gatewayCallerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(gatewayUserKey); len(values) > 0 {
+			var user auth.UserInfo
+			if err := json.Unmarshal([]byte(values[0]), &user); err != nil {
+				return nil, status.Errorf(codes.Internal, "invalid gateway caller: %v", err)
+			}
+			ctx = auth.WithUser(ctx, &user)
+		}
+		if values := md.Get(gatewayAnonymousKey); len(values) > 0 {
+			ctx = auth.WithAnonymous(ctx, values[0])
+		}
+		return handler(ctx, req)
+	}
+}
+
+// identityFields are the request fields naming the acting user. Over the
+// gateway, and for callers authenticated by Credentials, they are set from
+// the signed-in user, as the REST API does, rather than trusted from the
+// request.
+var identityFields = []protoreflect.Name{"author", "editor", "requester"}
+
+// signedInAs sets the identity fields of req to the signed-in user's
+// identity, if any. The requester is passed on to the alert's provider,
+// which knows users by email, so it is only set for callers with one.
+func signedInAs(ctx context.Context, req proto.Message) {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return
+	}
+	msg := req.ProtoReflect()
+	for _, name := range identityFields {
+		value := user.Identity()
+		if name == "requester" {
+			value = user.Email
+		}
+		if value == "" {
+			continue
+		}
+		if fd := msg.Descriptor().Fields().ByName(name); fd != nil && fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			msg.Set(fd, protoreflect.ValueOfString(value))
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+func TestGatewayCoversEveryRPC(t *testing.T) {
+	routed := make(map[string]bool)
+	for _, rt := range gatewayRoutes {
+		routed[rt.rpc] = true
+	}
+	services := pb.File_api_proto_outalator_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			rpc := "/" + string(services.Get(i).FullName()) + "/" + string(methods.Get(j).Name())
+			if !routed[rpc] {
+				t.Errorf("%s has no gateway route", rpc)
+			}
+		}
+	}
+}
+
+func TestGateway(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	g, err := NewServer(svc).Gateway()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	do := func(method, target, body string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(testutil.WithUser(req.Context(), &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}))
+		rr := httptest.NewRecorder()
+		g.ServeHTTP(rr, req)
+		var resp map[string]any
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: decode %s: %v", method, target, rr.Body, err)
+		}
+		return rr.Code, resp
+	}
+
+	code, resp := do("POST", "/v1/outages", `{"title":"Checkout errors","severity":"high",
+		"metadata":{"region":"eu-west-1"},"custom_fields":{"customers":1200},
+		"tags":[{"key":"service","value":"checkout"}]}`)
+	if code != http.StatusOK {
+		t.Fatalf("create: status = %d: %v", code, resp)
+	}
+	outage := resp["outage"].(map[string]any)
+	id := outage["id"].(string)
+	if outage["metadata"].(map[string]any)["region"] != "eu-west-1" || outage["custom_fields"].(map[string]any)["customers"] != 1200.0 {
+		t.Errorf("outage = %v", outage)
+	}
+
+	code, resp = do("GET", "/v1/outages/"+id, "")
+	if code != http.StatusOK || resp["outage"].(map[string]any)["title"] != "Checkout errors" {
+		t.Errorf("get: status = %d: %v", code, resp)
+	}
+
+	code, resp = do("GET", "/v1/outages/"+id+"?read_mask=id,tags", "")
+	if masked, _ := resp["outage"].(map[string]any); code != http.StatusOK || masked["title"] != "" || len(masked["tags"].([]any)) != 1 {
+		t.Errorf("get with read mask: status = %d: %v", code, resp)
+	}
+
+	code, resp = do("GET", "/v1/tags/search?key=service&value=checkout", "")
+	if outages, _ := resp["outages"].([]any); code != http.StatusOK || len(outages) != 1 {
+		t.Errorf("search: status = %d: %v", code, resp)
+	}
+
+	code, resp = do("GET", "/v1/outages?limit=1&include_associations=true", "")
+	if outages, _ := resp["outages"].([]any); code != http.StatusOK || len(outages) != 1 || resp["limit"] != 1.0 {
+		t.Errorf("list: status = %d: %v", code, resp)
+	}
+
+	// The author is the signed-in user, not whoever the body names
+	code, resp = do("POST", "/v1/outages/"+id+"/notes", `{"content":"rolling back","format":"plaintext","author":"mallory@example.com"}`)
+	if code != http.StatusOK || resp["note"].(map[string]any)["author"] != "alice@example.com" {
+		t.Errorf("add note: status = %d: %v", code, resp)
+	}
+
+	code, resp = do("POST", "/v1/views", `{"name":"Checkout","filter":{"min_severity":"high","tags":[{"key":"service","value":"checkout"}]}}`)
+	if code != http.StatusOK || resp["view"].(map[string]any)["owner"] != "alice@example.com" {
+		t.Fatalf("create view: status = %d: %v", code, resp)
+	}
+	code, resp = do("GET", "/v1/views/"+resp["view"].(map[string]any)["id"].(string)+"/outages", "")
+	if outages, _ := resp["outages"].([]any); code != http.StatusOK || len(outages) != 1 {
+		t.Errorf("execute view: status = %d: %v", code, resp)
+	}
+
+	code, resp = do("GET", "/v1/outages/"+uuid.NewString(), "")
+	if code != http.StatusNotFound || resp["code"] != 5.0 {
+		t.Errorf("missing outage: status = %d: %v", code, resp)
+	}
+
+	code, resp = do("POST", "/v1/outages", `{"title":`)
+	if code != http.StatusBadRequest {
+		t.Errorf("invalid body: status = %d: %v", code, resp)
+	}
+
+	code, resp = do("GET", "/v1/health", "")
+	if code != http.StatusOK || resp["status"] == "" {
+		t.Errorf("health: status = %d: %v", code, resp)
+	}
+}
+
+func TestGatewayOwningTeam(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	ctx := context.Background()
+	teams := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"alice@example.com"}}}}
+	if _, err := svc.ApplyOpsConfig(ctx, teams, false, false); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewServer(svc).Gateway()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	do := func(user *auth.UserInfo, method, target, body string, header http.Header) int {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		maps.Copy(req.Header, header)
+		if user != nil {
+			req = req.WithContext(testutil.WithUser(req.Context(), user))
+		} else {
+			req = req.WithContext(auth.WithAnonymous(req.Context(), auth.AccessOpen))
+		}
+		rr := httptest.NewRecorder()
+		g.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	bob := &auth.UserInfo{Email: "bob@example.com"}
+	alice := &auth.UserInfo{Email: "alice@example.com"}
+	id := outage.ID.String()
+
+	// Neither non-members nor anonymous callers claiming to be an admin
+	// through gateway metadata can change the outage
+	spoofed := http.Header{"Grpc-Metadata-" + gatewayUserKey: {base64.StdEncoding.EncodeToString([]byte(`{"email":"admin@example.com","roles":["admin"]}`))}}
+	for _, tt := range []struct{ method, target, body string }{
+		{"PATCH", "/v1/outages/" + id, `{"title":"Renamed"}`},
+		{"DELETE", "/v1/outages/" + id, ""},
+		{"POST", "/v1/outages/" + id + "/notes", `{"content":"rolling back","format":"plaintext"}`},
+		{"POST", "/v1/outages/" + id + "/tags", `{"key":"service","value":"checkout"}`},
+	} {
+		if code := do(bob, tt.method, tt.target, tt.body, nil); code != http.StatusForbidden {
+			t.Errorf("%s %s by a non-member: status = %d, want 403", tt.method, tt.target, code)
+		}
+		if code := do(nil, tt.method, tt.target, tt.body, spoofed); code != http.StatusForbidden {
+			t.Errorf("%s %s with spoofed metadata: status = %d, want 403", tt.method, tt.target, code)
+		}
+	}
+	if code := do(alice, "POST", "/v1/outages/"+id+"/notes", `{"content":"rolling back","format":"plaintext"}`, nil); code != http.StatusOK {
+		t.Errorf("add note by a member: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Interceptors selects the interceptors Options installs. The zero value
+// installs panic recovery and request validation only.
+type Interceptors struct {
+	// Logger logs each call with its code and latency when set
+	Logger *slog.Logger
+	// Metrics records call counts and latency in the outalator_grpc_*
+	// Prometheus metrics
+	Metrics bool
+	// Credentials, when set, requires every call except health checks to
+	// carry an API key or token it accepts
+	Credentials *Credentials
+	// SkipRecovery lets a panicking handler crash the process instead of
+	// failing the call with Internal
+	SkipRecovery bool
+	// SkipValidation passes malformed requests on to the handlers, which
+	// check the fields they use themselves
+	SkipValidation bool
+}
+
+// Options returns the server options installing the selected unary and
+// stream interceptors, for NewServer. Calls are logged first, so the log
+// sees the final code of every call including those recovered from a panic
+// or refused by authentication.
+func (i Interceptors) Options() []grpc.ServerOption {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if i.Logger != nil {
+		unary = append(unary, logging.UnaryServerInterceptor(i.Logger))
+		stream = append(stream, logging.StreamServerInterceptor(i.Logger))
+	}
+	if i.Metrics {
+		unary = append(unary, metrics.UnaryServerInterceptor())
+		stream = append(stream, metrics.StreamServerInterceptor())
+	}
+	if !i.SkipRecovery {
+		logger := i.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		unary = append(unary, UnaryRecoveryInterceptor(logger))
+		stream = append(stream, StreamRecoveryInterceptor(logger))
+	}
+	if i.Credentials != nil {
+		unary = append(unary, i.Credentials.UnaryInterceptor())
+		stream = append(stream, i.Credentials.StreamInterceptor())
+	}
+	if !i.SkipValidation {
+		unary = append(unary, UnaryValidationInterceptor())
+	}
+	return []grpc.ServerOption{grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...)}
+}
+
+// UnaryRecoveryInterceptor fails a call whose handler panics with Internal,
+// logging the panic and its stack, rather than crashing the server
+func UnaryRecoveryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ctx, logger, info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryInterceptor is UnaryRecoveryInterceptor for streaming calls
+func StreamRecoveryInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ss.Context(), logger, info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, logger *slog.Logger, method string, p any) error {
+	logger.ErrorContext(ctx, "grpc handler panicked", "method", method, "panic", p, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// UnaryValidationInterceptor rejects requests with missing or malformed
+// fields with InvalidArgument before they reach the handler
+func UnaryValidationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := validateRequest(req); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// APIKey is a static key a client can authenticate with
+type APIKey struct {
+	Name string // Identifies the client in logs
+	Key  string
+}
+
+// TokenVerifier verifies OIDC ID tokens and service JWTs, such as
+// *auth.Authenticator
+type TokenVerifier interface {
+	VerifyBearerToken(ctx context.Context, rawToken string) (*auth.UserInfo, error)
+}
+
+// Credentials are the API keys and tokens the gRPC server accepts. Clients
+// send either as "authorization: Bearer <key or token>" metadata.
+type Credentials struct {
+	APIKeys []APIKey
+	// Tokens verifies bearer tokens that are not API keys as ID tokens or
+	// service JWTs; when nil only API keys are accepted
+	Tokens TokenVerifier
+	// Policy lets calls without credentials through, marked anonymous, when
+	// its access for the grpc route group allows them. Reads are the RPCs
+	// the gateway serves with GET, so a call is allowed over the gRPC port
+	// exactly when it is allowed over the gateway. The zero value requires
+	// credentials for every call.
+	Policy auth.Policy
+}
+
+// UnaryInterceptor authenticates unary calls. The caller is added to the
+// context as the auth package's user, and a request's author, editor and
+// requester fields are set to the caller's identity as signedInAs does.
+func (c *Credentials) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == pb.HealthService_Check_FullMethodName {
+			return handler(ctx, req)
+		}
+		ctx, err := c.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		if msg, ok := req.(proto.Message); ok {
+			signedInAs(ctx, msg)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authenticates streaming calls
+func (c *Credentials) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := c.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate returns ctx with the user its bearer credential identifies,
+// or marked anonymous when it has none and the policy allows the call
+func (c *Credentials) authenticate(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		if c.Policy.AllowsAnonymous(auth.GroupGRPC, readRPCs[method]) {
+			return auth.WithAnonymous(ctx, c.Policy.Access(auth.GroupGRPC)), nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+	scheme, credential, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || credential == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer API key or token")
+	}
+
+	for _, key := range c.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(credential), []byte(key.Key)) == 1 {
+			user := &auth.UserInfo{Name: key.Name, Sub: "api-key:" + key.Name, Service: "api-key:" + key.Name}
+			return context.WithValue(ctx, auth.UserContextKey, user), nil
+		}
+	}
+	if c.Tokens == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	user, err := c.Tokens.VerifyBearerToken(ctx, credential)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key or token")
+	}
+	return context.WithValue(ctx, auth.UserContextKey, user), nil
+}
+
+// authenticatedStream carries the caller in a stream's context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,251 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// fakeTokens accepts the token "valid-token" as alice
+type fakeTokens struct{}
+
+func (fakeTokens) VerifyBearerToken(ctx context.Context, rawToken string) (*auth.UserInfo, error) {
+	if rawToken != "valid-token" {
+		return nil, errors.New("bad token")
+	}
+	return &auth.UserInfo{Email: "alice@example.com", Sub: "sub-alice"}, nil
+}
+
+// dial serves s over an in-memory listener and returns a connection to it
+func dial(t *testing.T, s *Server) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(append(s.opts, grpc.ChainUnaryInterceptor(UnaryErrorInterceptor()))...)
+	s.RegisterServices(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///outalator",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestInterceptorsAuth(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	creds := &Credentials{APIKeys: []APIKey{{Name: "ci", Key: "secret-key"}}, Tokens: fakeTokens{}}
+	conn := dial(t, NewServer(svc, Interceptors{Logger: logging.Discard(), Credentials: creds}.Options()...))
+	outages := pb.NewOutageServiceClient(conn)
+	notes := pb.NewNoteServiceClient(conn)
+
+	bearer := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	if _, err := pb.NewHealthServiceClient(conn).Check(context.Background(), &pb.HealthCheckRequest{}); err != nil {
+		t.Errorf("health check without credentials: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"no credentials", context.Background(), codes.Unauthenticated},
+		{"not bearer", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic c2VjcmV0"), codes.Unauthenticated},
+		{"wrong key", bearer("wrong-key"), codes.Unauthenticated},
+		{"API key", bearer("secret-key"), codes.OK},
+		{"ID token", bearer("valid-token"), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := outages.ListOutages(tt.ctx, &pb.ListOutagesRequest{})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+
+	// The author of a note is the caller the ID token identifies
+	created, err := outages.CreateOutage(bearer("valid-token"), &pb.CreateOutageRequest{Title: "Checkout errors", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := notes.AddNote(bearer("valid-token"), &pb.AddNoteRequest{
+		OutageId: created.Outage.Id, Content: "rolling back", Format: "plaintext", Author: "mallory@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Note.Author != "alice@example.com" {
+		t.Errorf("author = %q, want alice@example.com", note.Note.Author)
+	}
+}
+
+func TestInterceptorsOwningTeam(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	ctx := context.Background()
+	teams := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"alice@example.com"}}}}
+	if _, err := svc.ApplyOpsConfig(ctx, teams, false, false); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := &Credentials{APIKeys: []APIKey{{Name: "ci", Key: "secret-key"}}, Tokens: fakeTokens{}}
+	conn := dial(t, NewServer(svc, Interceptors{Logger: logging.Discard(), Credentials: creds}.Options()...))
+	outages := pb.NewOutageServiceClient(conn)
+	notes := pb.NewNoteServiceClient(conn)
+
+	// The API key belongs to no team, while the ID token is alice's
+	key := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret-key")
+	title := "Renamed"
+	if _, err := outages.UpdateOutage(key, &pb.UpdateOutageRequest{Id: outage.ID.String(), Title: &title}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("UpdateOutage() by a non-member: %v, want PermissionDenied", err)
+	}
+	if _, err := outages.DeleteOutage(key, &pb.DeleteOutageRequest{Id: outage.ID.String()}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("DeleteOutage() by a non-member: %v, want PermissionDenied", err)
+	}
+	if _, err := notes.AddNote(key, &pb.AddNoteRequest{OutageId: outage.ID.String(), Content: "rolling back", Format: "plaintext", Author: "ci"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("AddNote() by a non-member: %v, want PermissionDenied", err)
+	}
+
+	token := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer valid-token")
+	if _, err := outages.UpdateOutage(token, &pb.UpdateOutageRequest{Id: outage.ID.String(), Title: &title}); err != nil {
+		t.Errorf("UpdateOutage() by a member: %v", err)
+	}
+}
+
+func TestInterceptorsAnonymousRead(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	creds := &Credentials{
+		APIKeys: []APIKey{{Name: "ci", Key: "secret-key"}},
+		Policy:  auth.Policy{Default: auth.AccessAnonymousRead},
+	}
+	conn := dial(t, NewServer(svc, Interceptors{Logger: logging.Discard(), Credentials: creds}.Options()...))
+	outages := pb.NewOutageServiceClient(conn)
+
+	if _, err := outages.ListOutages(context.Background(), &pb.ListOutagesRequest{}); err != nil {
+		t.Errorf("ListOutages without credentials: %v", err)
+	}
+	_, err := outages.CreateOutage(context.Background(), &pb.CreateOutageRequest{Title: "Checkout errors", Severity: "high"})
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Errorf("CreateOutage without credentials code = %v, want Unauthenticated", got)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong-key")
+	if _, err := outages.ListOutages(ctx, &pb.ListOutagesRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ListOutages with a wrong key code = %v, want Unauthenticated", status.Code(err))
+	}
+}
+
+func TestReadRPCsAreGatewayGETs(t *testing.T) {
+	for _, rpc := range []string{pb.OutageService_ListOutages_FullMethodName, pb.ViewService_ExecuteView_FullMethodName} {
+		if !readRPCs[rpc] {
+			t.Errorf("%s is not a read", rpc)
+		}
+	}
+	for _, rpc := range []string{pb.OutageService_CreateOutage_FullMethodName, pb.AlertService_AcknowledgeAlert_FullMethodName} {
+		if readRPCs[rpc] {
+			t.Errorf("%s is a read", rpc)
+		}
+	}
+	if got := auth.RouteGroup(GatewayPrefix + "outages"); got != auth.GroupGRPC {
+		t.Errorf("gateway route group = %q, want %q", got, auth.GroupGRPC)
+	}
+}
+
+func TestInterceptorsValidation(t *testing.T) {
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	outages := pb.NewOutageServiceClient(dial(t, NewServer(svc, Interceptors{}.Options()...)))
+
+	_, err := outages.GetOutage(context.Background(), &pb.GetOutageRequest{Id: "not-a-uuid"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid ID: err = %v", err)
+	}
+	_, err = outages.GetOutage(context.Background(), &pb.GetOutageRequest{Id: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("unknown ID: err = %v", err)
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	id := uuid.NewString()
+	empty := ""
+	tests := []struct {
+		name    string
+		req     any
+		wantErr bool
+	}{
+		{"create outage", &pb.CreateOutageRequest{Title: "Checkout errors"}, false},
+		{"create outage without title", &pb.CreateOutageRequest{}, true},
+		{"create outage with bad alert ID", &pb.CreateOutageRequest{Title: "x", AlertIds: []string{"bad"}}, true},
+		{"create outage with unnamed tag", &pb.CreateOutageRequest{Title: "x", Tags: []*pb.TagInput{{Value: "checkout"}}}, true},
+		{"list outages", &pb.ListOutagesRequest{Limit: 10}, false},
+		{"negative offset", &pb.ListOutagesRequest{Offset: -1}, true},
+		{"list outages with read mask", &pb.ListOutagesRequest{ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "notes"}}}, false},
+		{"get outage with unknown mask field", &pb.GetOutageRequest{Id: id, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"owner"}}}, true},
+		{"add note", &pb.AddNoteRequest{OutageId: id, Content: "hi"}, false},
+		{"add note without content", &pb.AddNoteRequest{OutageId: id}, true},
+		{"reply to bad parent", &pb.AddNoteRequest{OutageId: id, Content: "hi", ParentNoteId: "bad"}, true},
+		{"add tag without value", &pb.AddTagRequest{OutageId: id, Key: "service"}, true},
+		{"search without key", &pb.SearchOutagesByTagRequest{Value: "checkout"}, true},
+		{"import alert", &pb.ImportAlertRequest{Source: "pagerduty", ExternalId: "P1"}, false},
+		{"import alert with empty outage ID", &pb.ImportAlertRequest{Source: "pagerduty", ExternalId: "P1", OutageId: &empty}, true},
+		{"alert by external ID without source", &pb.GetAlertByExternalIDRequest{ExternalId: "P1"}, true},
+		{"acknowledge alert", &pb.AcknowledgeAlertRequest{Id: id}, false},
+		{"create view without name", &pb.CreateViewRequest{}, true},
+		{"create view with unnamed tag", &pb.CreateViewRequest{Name: "x", Filter: &pb.ViewFilter{Tags: []*pb.TagFilter{{Value: "api"}}}}, true},
+		{"execute view with negative limit", &pb.ExecuteViewRequest{Id: id, Limit: -1}, true},
+		{"health check", &pb.HealthCheckRequest{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRequest(tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("validateRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	interceptor := UnaryRecoveryInterceptor(logging.Discard())
+	info := &grpc.UnaryServerInfo{FullMethod: pb.OutageService_GetOutage_FullMethodName}
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("err = %v, want Internal", err)
+	}
+
+	stream := StreamRecoveryInterceptor(logging.Discard())
+	err = stream(nil, &authenticatedStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("stream err = %v, want Internal", err)
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"slices"
+	"strings"
 	"sync"
 
-	"github.com/conall/outalator/service"
 	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -18,31 +23,38 @@ type Server struct {
 	pb.UnimplementedNoteServiceServer
 	pb.UnimplementedTagServiceServer
 	pb.UnimplementedAlertServiceServer
+	pb.UnimplementedViewServiceServer
 	pb.UnimplementedHealthServiceServer
 
 	service    *service.Service
+	opts       []grpc.ServerOption
 	mu         sync.Mutex
 	grpcServer *grpc.Server
 }
 
-// NewServer creates a new gRPC server
-func NewServer(svc *service.Service) *Server {
+// NewServer creates a new gRPC server. opts are passed to grpc.NewServer
+// when the server starts (e.g. interceptors), followed by
+// UnaryErrorInterceptor.
+func NewServer(svc *service.Service, opts ...grpc.ServerOption) *Server {
 	return &Server{
 		service: svc,
+		opts:    opts,
 	}
 }
 
 // Start begins listening on addr and blocks until the server is stopped.
 // It must be called in a goroutine if the caller needs to remain responsive
 // (e.g. to call Stop). Returns an error if the server has already been started.
-// The server is created without TLS; wire up grpc.Creds for production use.
+// The server serves TLS when grpc.Creds is among the options given to
+// NewServer.
 func (s *Server) Start(addr string) error {
 	// Register services before the server is visible to Stop(), so a
 	// concurrent Stop() cannot call GracefulStop() between assignment and
 	// RegisterServices — which would cause Serve to return immediately.
 	// The TOCTOU guard (check-then-set) still holds because srv is local
 	// until after RegisterServices completes.
-	srv := grpc.NewServer()
+	opts := append(slices.Clip(s.opts), grpc.ChainUnaryInterceptor(UnaryErrorInterceptor()))
+	srv := grpc.NewServer(opts...)
 	s.RegisterServices(srv)
 
 	s.mu.Lock()
@@ -100,13 +112,15 @@ func (s *Server) Stop() {
 }
 
 // RegisterServices registers all gRPC services with an externally managed
-// *grpc.Server. Use this when the caller controls the server lifecycle.
+// *grpc.Server. Use this when the caller controls the server lifecycle, and
+// install UnaryErrorInterceptor on that server to get gRPC status codes.
 // Use Start/Stop instead when this struct should own the lifecycle.
 func (s *Server) RegisterServices(grpcServer *grpc.Server) {
 	pb.RegisterOutageServiceServer(grpcServer, s)
 	pb.RegisterNoteServiceServer(grpcServer, s)
 	pb.RegisterTagServiceServer(grpcServer, s)
 	pb.RegisterAlertServiceServer(grpcServer, s)
+	pb.RegisterViewServiceServer(grpcServer, s)
 	pb.RegisterHealthServiceServer(grpcServer, s)
 }
 
@@ -139,37 +153,68 @@ func (s *Server) CreateOutage(ctx context.Context, req *pb.CreateOutageRequest)
 	}, nil
 }
 
-// GetOutage retrieves an outage by ID
+// GetOutage retrieves an outage by ID, with only the fields in its read
+// mask when it has one
 func (s *Server) GetOutage(ctx context.Context, req *pb.GetOutageRequest) (*pb.GetOutageResponse, error) {
 	id, err := parseUUID(req.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	outage, err := s.service.GetOutage(ctx, id)
+	outage, err := s.service.GetOutageWith(ctx, id, maskedAssociations(req.ReadMask))
 	if err != nil {
 		return nil, err
 	}
+	if maskedRelations(req.ReadMask) {
+		relations, err := s.service.ListOutageRelations(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range relations {
+			outage.Relations = append(outage.Relations, *r)
+		}
+	}
 
 	pbOutage, err := OutageDomainToProto(outage)
 	if err != nil {
 		return nil, err
 	}
+	applyMask(pbOutage, req.ReadMask)
 
 	return &pb.GetOutageResponse{
 		Outage: pbOutage,
 	}, nil
 }
 
-// ListOutages lists outages with pagination
+// ListOutages lists outages with pagination. A read mask selects the
+// fields returned and the associations loaded, in place of
+// include_associations.
 func (s *Server) ListOutages(ctx context.Context, req *pb.ListOutagesRequest) (*pb.ListOutagesResponse, error) {
 	limit := int(req.Limit)
 	offset := int(req.Offset)
 
-	outages, err := s.service.ListOutages(ctx, limit, offset)
+	var outages []*domain.Outage
+	var err error
+	if len(req.Teams) > 0 {
+		outages, err = s.service.ListTeamOutages(ctx, req.Teams, limit, offset)
+	} else {
+		outages, err = s.service.ListOutages(ctx, limit, offset)
+	}
 	if err != nil {
 		return nil, err
 	}
+	associations := domain.OutageAssociations{}
+	switch {
+	case len(req.ReadMask.GetPaths()) > 0:
+		associations = maskedAssociations(req.ReadMask)
+	case req.IncludeAssociations:
+		associations = domain.AllOutageAssociations
+	}
+	if associations != (domain.OutageAssociations{}) {
+		if err := s.service.LoadOutageAssociations(ctx, outages, associations); err != nil {
+			return nil, err
+		}
+	}
 
 	var pbOutages []*pb.Outage
 	for _, outage := range outages {
@@ -177,6 +222,7 @@ func (s *Server) ListOutages(ctx context.Context, req *pb.ListOutagesRequest) (*
 		if err != nil {
 			return nil, err
 		}
+		applyMask(pbOutage, req.ReadMask)
 		pbOutages = append(pbOutages, pbOutage)
 	}
 
@@ -219,12 +265,47 @@ func (s *Server) UpdateOutage(ctx context.Context, req *pb.UpdateOutageRequest)
 	}, nil
 }
 
-// DeleteOutage deletes an outage
+// DeleteOutage deletes an outage along with its alerts, notes and tags
 func (s *Server) DeleteOutage(ctx context.Context, req *pb.DeleteOutageRequest) (*emptypb.Empty, error) {
-	// TODO: Implement DeleteOutage in service layer
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteOutage(ctx, id); err != nil {
+		return nil, err
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
+// GetOutageTimeline returns the chronological event history of an outage
+func (s *Server) GetOutageTimeline(ctx context.Context, req *pb.GetOutageTimelineRequest) (*pb.GetOutageTimelineResponse, error) {
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.service.GetOutageTimeline(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pbEvents := make([]*pb.TimelineEvent, 0, len(events))
+	for _, event := range events {
+		pbEvent, err := TimelineEventDomainToProto(event)
+		if err != nil {
+			return nil, err
+		}
+		pbEvents = append(pbEvents, pbEvent)
+	}
+
+	return &pb.GetOutageTimelineResponse{
+		OutageId: id.String(),
+		Events:   pbEvents,
+	}, nil
+}
+
 // ============================================================================
 // NoteService implementation
 // ============================================================================
@@ -261,14 +342,53 @@ func (s *Server) AddNote(ctx context.Context, req *pb.AddNoteRequest) (*pb.AddNo
 
 // GetNote retrieves a note by ID
 func (s *Server) GetNote(ctx context.Context, req *pb.GetNoteRequest) (*pb.GetNoteResponse, error) {
-	// TODO: Implement GetNote in service layer
-	return &pb.GetNoteResponse{}, nil
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.service.GetNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pbNote, err := NoteDomainToProto(note)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetNoteResponse{
+		Note: pbNote,
+	}, nil
 }
 
-// ListNotesByOutage lists notes for an outage
+// ListNotesByOutage lists notes for an outage with pagination
 func (s *Server) ListNotesByOutage(ctx context.Context, req *pb.ListNotesByOutageRequest) (*pb.ListNotesByOutageResponse, error) {
-	// TODO: Implement ListNotesByOutage in service layer
-	return &pb.ListNotesByOutageResponse{}, nil
+	outageID, err := parseUUID(req.OutageId)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, total, err := s.service.ListNotesByOutagePage(ctx, outageID, int(req.Limit), int(req.Offset), false)
+	if err != nil {
+		return nil, err
+	}
+
+	pbNotes := make([]*pb.Note, 0, len(notes))
+	for _, note := range notes {
+		pbNote, err := NoteDomainToProto(note)
+		if err != nil {
+			return nil, err
+		}
+		pbNotes = append(pbNotes, pbNote)
+	}
+
+	return &pb.ListNotesByOutageResponse{
+		Notes:  pbNotes,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+		Total:  int32(total), //nolint:gosec // note count cannot realistically exceed int32 max
+	}, nil
 }
 
 // UpdateNote updates a note
@@ -286,7 +406,7 @@ func (s *Server) UpdateNote(ctx context.Context, req *pb.UpdateNoteRequest) (*pb
 	}
 
 	// Call service layer
-	note, err := s.service.UpdateNote(ctx, noteID, content, format, metadata, customFields)
+	note, err := s.service.UpdateNote(ctx, noteID, content, format, metadata, customFields, req.Editor)
 	if err != nil {
 		return nil, err
 	}
@@ -304,7 +424,15 @@ func (s *Server) UpdateNote(ctx context.Context, req *pb.UpdateNoteRequest) (*pb
 
 // DeleteNote deletes a note
 func (s *Server) DeleteNote(ctx context.Context, req *pb.DeleteNoteRequest) (*emptypb.Empty, error) {
-	// TODO: Implement DeleteNote in service layer
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteNote(ctx, id); err != nil {
+		return nil, err
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
@@ -340,25 +468,82 @@ func (s *Server) AddTag(ctx context.Context, req *pb.AddTagRequest) (*pb.AddTagR
 
 // GetTag retrieves a tag by ID
 func (s *Server) GetTag(ctx context.Context, req *pb.GetTagRequest) (*pb.GetTagResponse, error) {
-	// TODO: Implement GetTag in service layer
-	return &pb.GetTagResponse{}, nil
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := s.service.GetTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pbTag, err := TagDomainToProto(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetTagResponse{
+		Tag: pbTag,
+	}, nil
 }
 
 // ListTagsByOutage lists tags for an outage
 func (s *Server) ListTagsByOutage(ctx context.Context, req *pb.ListTagsByOutageRequest) (*pb.ListTagsByOutageResponse, error) {
-	// TODO: Implement ListTagsByOutage in service layer
-	return &pb.ListTagsByOutageResponse{}, nil
+	outageID, err := parseUUID(req.OutageId)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.service.ListTagsByOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+
+	pbTags := make([]*pb.Tag, 0, len(tags))
+	for _, tag := range tags {
+		pbTag, err := TagDomainToProto(tag)
+		if err != nil {
+			return nil, err
+		}
+		pbTags = append(pbTags, pbTag)
+	}
+
+	return &pb.ListTagsByOutageResponse{
+		Tags: pbTags,
+	}, nil
 }
 
 // DeleteTag deletes a tag
 func (s *Server) DeleteTag(ctx context.Context, req *pb.DeleteTagRequest) (*emptypb.Empty, error) {
-	// TODO: Implement DeleteTag in service layer
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteTag(ctx, id); err != nil {
+		return nil, err
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
-// SearchOutagesByTag searches for outages by tag
+// SearchOutagesByTag searches for outages matching the key and value and
+// every tag term
 func (s *Server) SearchOutagesByTag(ctx context.Context, req *pb.SearchOutagesByTagRequest) (*pb.SearchOutagesByTagResponse, error) {
-	outages, err := s.service.FindOutagesByTag(ctx, req.Key, req.Value)
+	var query domain.TagQuery
+	if req.Key != "" {
+		term := domain.TagTerm{Key: req.Key}
+		if req.Value != "" {
+			term.Values = []string{req.Value}
+		}
+		query.Terms = append(query.Terms, term)
+	}
+	for _, term := range req.Terms {
+		query.Terms = append(query.Terms, domain.TagTerm{Key: term.Key, Values: term.Values, Negate: term.Negate})
+	}
+
+	outages, err := s.service.FindOutagesByTagQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -419,35 +604,254 @@ func (s *Server) ImportAlert(ctx context.Context, req *pb.ImportAlertRequest) (*
 
 // GetAlert retrieves an alert by ID
 func (s *Server) GetAlert(ctx context.Context, req *pb.GetAlertRequest) (*pb.GetAlertResponse, error) {
-	// TODO: Implement GetAlert in service layer
-	return &pb.GetAlertResponse{}, nil
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	alert, err := s.service.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pbAlert, err := AlertDomainToProto(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetAlertResponse{
+		Alert: pbAlert,
+	}, nil
 }
 
 // GetAlertByExternalID retrieves an alert by external ID and source
 func (s *Server) GetAlertByExternalID(ctx context.Context, req *pb.GetAlertByExternalIDRequest) (*pb.GetAlertByExternalIDResponse, error) {
-	// TODO: Implement GetAlertByExternalID (already exists in storage, need to expose in service)
-	return &pb.GetAlertByExternalIDResponse{}, nil
+	alert, err := s.service.GetAlertByExternalID(ctx, req.ExternalId, req.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	pbAlert, err := AlertDomainToProto(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetAlertByExternalIDResponse{
+		Alert: pbAlert,
+	}, nil
 }
 
 // ListAlertsByOutage lists alerts for an outage
 func (s *Server) ListAlertsByOutage(ctx context.Context, req *pb.ListAlertsByOutageRequest) (*pb.ListAlertsByOutageResponse, error) {
-	// TODO: Implement ListAlertsByOutage in service layer
-	return &pb.ListAlertsByOutageResponse{}, nil
+	outageID, err := parseUUID(req.OutageId)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.service.ListAlertsByOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+
+	pbAlerts := make([]*pb.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		pbAlert, err := AlertDomainToProto(alert)
+		if err != nil {
+			return nil, err
+		}
+		pbAlerts = append(pbAlerts, pbAlert)
+	}
+
+	return &pb.ListAlertsByOutageResponse{
+		Alerts: pbAlerts,
+	}, nil
 }
 
 // UpdateAlert updates an alert
 // NOTE: This uses FULL REPLACEMENT for metadata and custom_fields, not merging
 func (s *Server) UpdateAlert(ctx context.Context, req *pb.UpdateAlertRequest) (*pb.UpdateAlertResponse, error) {
-	// TODO: Implement UpdateAlert in service layer
-	return &pb.UpdateAlertResponse{}, nil
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert request from protobuf to domain
+	domainReq, err := UpdateAlertRequestProtoToDomain(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call service layer
+	alert, err := s.service.UpdateAlert(ctx, id, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert response from domain to protobuf
+	pbAlert, err := AlertDomainToProto(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateAlertResponse{
+		Alert: pbAlert,
+	}, nil
+}
+
+// AcknowledgeAlert acknowledges an alert with the notification service it
+// came from
+func (s *Server) AcknowledgeAlert(ctx context.Context, req *pb.AcknowledgeAlertRequest) (*pb.AcknowledgeAlertResponse, error) {
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	alert, err := s.service.AcknowledgeAlert(ctx, id, req.Requester)
+	if err != nil {
+		return nil, err
+	}
+
+	pbAlert, err := AlertDomainToProto(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AcknowledgeAlertResponse{
+		Alert: pbAlert,
+	}, nil
+}
+
+// ResolveAlert resolves an alert with the notification service it came from
+func (s *Server) ResolveAlert(ctx context.Context, req *pb.ResolveAlertRequest) (*pb.ResolveAlertResponse, error) {
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	alert, err := s.service.ResolveAlert(ctx, id, req.Requester)
+	if err != nil {
+		return nil, err
+	}
+
+	pbAlert, err := AlertDomainToProto(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ResolveAlertResponse{
+		Alert: pbAlert,
+	}, nil
+}
+
+// ============================================================================
+// ViewService implementation
+// ============================================================================
+
+// CreateView saves a view owned by the requester
+func (s *Server) CreateView(ctx context.Context, req *pb.CreateViewRequest) (*pb.CreateViewResponse, error) {
+	view, err := s.service.CreateSavedView(ctx, req.Requester, CreateViewRequestProtoToDomain(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateViewResponse{
+		View: SavedViewDomainToProto(view),
+	}, nil
+}
+
+// GetView retrieves a saved view by ID
+func (s *Server) GetView(ctx context.Context, req *pb.GetViewRequest) (*pb.GetViewResponse, error) {
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.service.GetSavedView(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetViewResponse{
+		View: SavedViewDomainToProto(view),
+	}, nil
+}
+
+// ListViews lists every saved view ordered by name
+func (s *Server) ListViews(ctx context.Context, req *pb.ListViewsRequest) (*pb.ListViewsResponse, error) {
+	views, err := s.service.ListSavedViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pbViews := make([]*pb.SavedView, 0, len(views))
+	for _, view := range views {
+		pbViews = append(pbViews, SavedViewDomainToProto(view))
+	}
+
+	return &pb.ListViewsResponse{
+		Views: pbViews,
+	}, nil
+}
+
+// DeleteView deletes a saved view
+func (s *Server) DeleteView(ctx context.Context, req *pb.DeleteViewRequest) (*emptypb.Empty, error) {
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteSavedView(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ExecuteView lists a page of the outages currently matching a saved view
+func (s *Server) ExecuteView(ctx context.Context, req *pb.ExecuteViewRequest) (*pb.ExecuteViewResponse, error) {
+	id, err := parseUUID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	view, outages, err := s.service.ExecuteSavedView(ctx, id, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	pbOutages := make([]*pb.Outage, 0, len(outages))
+	for _, outage := range outages {
+		pbOutage, err := OutageDomainToProto(outage)
+		if err != nil {
+			return nil, err
+		}
+		pbOutages = append(pbOutages, pbOutage)
+	}
+
+	return &pb.ExecuteViewResponse{
+		View:    SavedViewDomainToProto(view),
+		Outages: pbOutages,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}, nil
 }
 
 // ============================================================================
 // HealthService implementation
 // ============================================================================
 
-// Check performs a health check
+// Check runs the same dependency checks as the REST readiness probe,
+// failing with codes.Unavailable naming the failed checks
 func (s *Server) Check(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	var failed []string
+	for _, check := range s.service.Readiness(ctx) {
+		if check.Err != nil {
+			failed = append(failed, check.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return nil, status.Errorf(codes.Unavailable, "not ready: %s failed", strings.Join(failed, ", "))
+	}
 	return &pb.HealthCheckResponse{
 		Status: "healthy",
 	}, nil
@@ -0,0 +1,169 @@
+package grpc
+
+import (
+	"fmt"
+
+	pb "github.com/conall/outalator/api/proto/v1"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// validateRequest checks the fields of req that every handler relies on:
+// IDs are UUIDs, required strings are set and pagination is not negative.
+// Requests of other types are accepted unchanged.
+func validateRequest(req any) error {
+	switch r := req.(type) {
+	case *pb.CreateOutageRequest:
+		if err := required("title", r.Title); err != nil {
+			return err
+		}
+		for _, id := range r.AlertIds {
+			if err := validID("alert_ids", id); err != nil {
+				return err
+			}
+		}
+		for _, tag := range r.Tags {
+			if err := required("tags.key", tag.GetKey()); err != nil {
+				return err
+			}
+		}
+	case *pb.GetOutageRequest:
+		if err := validID("id", r.Id); err != nil {
+			return err
+		}
+		return validOutageMask("read_mask", r.ReadMask)
+	case *pb.ListOutagesRequest:
+		if err := page(r.Limit, r.Offset); err != nil {
+			return err
+		}
+		return validOutageMask("read_mask", r.ReadMask)
+	case *pb.UpdateOutageRequest:
+		return validID("id", r.Id)
+	case *pb.DeleteOutageRequest:
+		return validID("id", r.Id)
+	case *pb.GetOutageTimelineRequest:
+		return validID("id", r.Id)
+	case *pb.AddNoteRequest:
+		if err := validID("outage_id", r.OutageId); err != nil {
+			return err
+		}
+		if r.ParentNoteId != "" {
+			if err := validID("parent_note_id", r.ParentNoteId); err != nil {
+				return err
+			}
+		}
+		return required("content", r.Content)
+	case *pb.GetNoteRequest:
+		return validID("id", r.Id)
+	case *pb.ListNotesByOutageRequest:
+		if err := validID("outage_id", r.OutageId); err != nil {
+			return err
+		}
+		return page(r.Limit, r.Offset)
+	case *pb.UpdateNoteRequest:
+		return validID("id", r.Id)
+	case *pb.DeleteNoteRequest:
+		return validID("id", r.Id)
+	case *pb.AddTagRequest:
+		if err := validID("outage_id", r.OutageId); err != nil {
+			return err
+		}
+		if err := required("key", r.Key); err != nil {
+			return err
+		}
+		return required("value", r.Value)
+	case *pb.GetTagRequest:
+		return validID("id", r.Id)
+	case *pb.ListTagsByOutageRequest:
+		return validID("outage_id", r.OutageId)
+	case *pb.DeleteTagRequest:
+		return validID("id", r.Id)
+	case *pb.SearchOutagesByTagRequest:
+		if len(r.Terms) == 0 {
+			return required("key", r.Key)
+		}
+		if r.Key == "" && r.Value != "" {
+			return fmt.Errorf("value needs a key")
+		}
+		for i, term := range r.Terms {
+			if err := required(fmt.Sprintf("terms[%d].key", i), term.Key); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *pb.ImportAlertRequest:
+		if err := required("source", r.Source); err != nil {
+			return err
+		}
+		if err := required("external_id", r.ExternalId); err != nil {
+			return err
+		}
+		if r.OutageId != nil {
+			return validID("outage_id", *r.OutageId)
+		}
+	case *pb.GetAlertRequest:
+		return validID("id", r.Id)
+	case *pb.GetAlertByExternalIDRequest:
+		if err := required("source", r.Source); err != nil {
+			return err
+		}
+		return required("external_id", r.ExternalId)
+	case *pb.ListAlertsByOutageRequest:
+		return validID("outage_id", r.OutageId)
+	case *pb.UpdateAlertRequest:
+		return validID("id", r.Id)
+	case *pb.AcknowledgeAlertRequest:
+		return validID("id", r.Id)
+	case *pb.ResolveAlertRequest:
+		return validID("id", r.Id)
+	case *pb.CreateViewRequest:
+		if err := required("name", r.Name); err != nil {
+			return err
+		}
+		for _, tag := range r.GetFilter().GetTags() {
+			if err := required("filter.tags.key", tag.GetKey()); err != nil {
+				return err
+			}
+		}
+	case *pb.GetViewRequest:
+		return validID("id", r.Id)
+	case *pb.DeleteViewRequest:
+		return validID("id", r.Id)
+	case *pb.ExecuteViewRequest:
+		if err := validID("id", r.Id); err != nil {
+			return err
+		}
+		return page(r.Limit, r.Offset)
+	}
+	return nil
+}
+
+func validID(field, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("%s must be a UUID", field)
+	}
+	return nil
+}
+
+func required(field, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	return nil
+}
+
+func page(limit, offset int32) error {
+	if limit < 0 || offset < 0 {
+		return fmt.Errorf("limit and offset must not be negative")
+	}
+	return nil
+}
+
+// validOutageMask checks that every path of a read mask names an Outage
+// field. A nil mask is valid and selects every field.
+func validOutageMask(field string, mask *fieldmaskpb.FieldMask) error {
+	if mask == nil || mask.IsValid(&pb.Outage{}) {
+		return nil
+	}
+	return fmt.Errorf("%s must name Outage fields, got %q", field, mask.GetPaths())
+}
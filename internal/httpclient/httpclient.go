@@ -0,0 +1,258 @@
+// Package httpclient makes requests to third-party APIs, retrying those
+// that fail transiently with exponential backoff and pacing requests to the
+// rate limits the API reports, or to a fixed rate.
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Retry policy defaults
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// Reasons a request is retried, passed to RetryPolicy.OnRetry
+const (
+	ReasonRateLimited = "rate_limited"
+	ReasonServerError = "server_error"
+	ReasonNetwork     = "network"
+)
+
+// RetryPolicy configures how failed requests are retried
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is retried, default 3;
+	// negative disables retries
+	MaxRetries int
+	// MinBackoff is the wait before the first retry, doubled for each
+	// retry after it, default 500ms
+	MinBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, default 30s. A request the
+	// API asks to be retried after longer fails instead.
+	MaxBackoff time.Duration
+	// OnRetry, when set, is called before each retry with the reason for
+	// it and the time waited. Optional.
+	OnRetry func(req *http.Request, reason string, wait time.Duration)
+}
+
+// Client sends requests through an http.Client, retrying requests that are
+// rate limited (429) with any method and, for idempotent requests, server
+// errors (5xx) and network errors. Waits honour the Retry-After header,
+// then RateLimit-Reset and X-RateLimit-Reset, falling back to exponential
+// backoff with jitter. When a response reports the rate limit is used up,
+// later requests wait for it to reset.
+type Client struct {
+	client *http.Client
+	policy RetryPolicy
+
+	mu          sync.Mutex
+	pausedUntil time.Time // Requests wait until the rate limit resets
+
+	now   func() time.Time
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+// New creates a Client sending requests through client, whose Timeout
+// applies to each attempt
+func New(client *http.Client, policy RetryPolicy) *Client {
+	if policy.MaxRetries == 0 {
+		policy.MaxRetries = DefaultMaxRetries
+	}
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = DefaultMinBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Client{client: client, policy: policy, now: time.Now, sleep: sleepContext}
+}
+
+// Do sends req, retrying it as the policy allows. As with http.Client, the
+// caller must close the body of the response returned. When retries run
+// out the last response or error is returned.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if err := c.waitForRateLimit(req); err != nil {
+		return nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := c.client.Do(req)
+		if err == nil {
+			c.notePacing(resp)
+		}
+		reason, retryable := c.shouldRetry(req, resp, err)
+		if !retryable || attempt >= c.policy.MaxRetries || !rewindable(req) {
+			return resp, err
+		}
+
+		wait := c.backoff(attempt)
+		if resp != nil {
+			if d, ok := c.retryAfter(resp); ok {
+				wait = d
+			}
+		}
+		if wait > c.policy.MaxBackoff {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+
+		if c.policy.OnRetry != nil {
+			c.policy.OnRetry(req, reason, wait)
+		}
+		if err := c.sleep(req, wait); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// shouldRetry reports whether the outcome of an attempt is worth retrying,
+// and why
+func (c *Client) shouldRetry(req *http.Request, resp *http.Response, err error) (string, bool) {
+	if err != nil {
+		// A cancelled request is not retried; one that timed out or failed
+		// to connect may succeed on another attempt
+		if req.Context().Err() != nil {
+			return "", false
+		}
+		return ReasonNetwork, idempotent(req)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ReasonRateLimited, true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return ReasonServerError, idempotent(req)
+	}
+	return "", false
+}
+
+// idempotent reports whether req can be repeated without side effects
+// beyond those of sending it once
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// rewindable reports whether req's body can be sent again
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// backoff returns the wait before retry attempt+1: MinBackoff doubled for
+// each earlier retry, capped at MaxBackoff, with up to half of it taken off
+// at random so clients retrying together spread out
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.policy.MinBackoff
+	for i := 0; i < attempt && d < c.policy.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.policy.MaxBackoff {
+		d = c.policy.MaxBackoff
+	}
+	return d - time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// retryAfter returns how long resp asks the client to wait before
+// retrying, from the Retry-After header, else the rate limit reset headers
+func (c *Client) retryAfter(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return nonNegative(t.Sub(c.now())), true
+		}
+	}
+	return c.rateLimitReset(resp)
+}
+
+// rateLimitReset returns the time until the rate limit reported by resp
+// resets. RateLimit-Reset (as sent by PagerDuty) is in seconds from now;
+// X-RateLimit-Reset is either that or, for large values, a Unix time.
+func (c *Client) rateLimitReset(resp *http.Response) (time.Duration, bool) {
+	for _, name := range []string{"RateLimit-Reset", "X-RateLimit-Reset"} {
+		v, err := strconv.ParseInt(resp.Header.Get(name), 10, 64)
+		if err != nil || v < 0 {
+			continue
+		}
+		// Values past 2001 are Unix times rather than durations
+		if v > 1e9 {
+			return nonNegative(time.Unix(v, 0).Sub(c.now())), true
+		}
+		return time.Duration(v) * time.Second, true
+	}
+	return 0, false
+}
+
+// notePacing pauses later requests until the rate limit resets when resp
+// reports it used up
+func (c *Client) notePacing(resp *http.Response) {
+	remaining := resp.Header.Get("RateLimit-Remaining")
+	if remaining == "" {
+		remaining = resp.Header.Get("X-RateLimit-Remaining")
+	}
+	if remaining != "0" {
+		return
+	}
+	wait, ok := c.rateLimitReset(resp)
+	if !ok || wait > c.policy.MaxBackoff {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if until := c.now().Add(wait); until.After(c.pausedUntil) {
+		c.pausedUntil = until
+	}
+}
+
+// waitForRateLimit waits until the rate limit last reported used up has
+// reset
+func (c *Client) waitForRateLimit(req *http.Request) error {
+	c.mu.Lock()
+	wait := c.pausedUntil.Sub(c.now())
+	c.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	return c.sleep(req, wait)
+}
+
+// sleepContext waits for d, returning early with an error if req is
+// cancelled
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return fmt.Errorf("cancelled waiting to send request: %w", req.Context().Err())
+	}
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
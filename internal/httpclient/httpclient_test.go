@@ -0,0 +1,234 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedServer replies to successive requests with the given statuses
+// and headers, recording the bodies it receives
+type scriptedServer struct {
+	mu      sync.Mutex
+	replies []reply
+	bodies  []string
+}
+
+type reply struct {
+	status int
+	header map[string]string
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bodies = append(s.bodies, string(body))
+	rep := reply{status: http.StatusOK}
+	if len(s.replies) > 0 {
+		rep, s.replies = s.replies[0], s.replies[1:]
+	}
+	for k, v := range rep.header {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(rep.status)
+}
+
+// newTestClient returns a Client that records its waits instead of sleeping
+func newTestClient(policy RetryPolicy) (*Client, *[]time.Duration) {
+	c := New(http.DefaultClient, policy)
+	var waits []time.Duration
+	c.sleep = func(_ *http.Request, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return c, &waits
+}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		replies  []reply
+		status   int
+		attempts int
+		waits    []time.Duration // Checked when set
+		reasons  string
+	}{
+		{
+			name:     "rate limited with Retry-After",
+			method:   http.MethodPost,
+			replies:  []reply{{status: 429, header: map[string]string{"Retry-After": "2"}}},
+			status:   200,
+			attempts: 2,
+			waits:    []time.Duration{2 * time.Second},
+			reasons:  ReasonRateLimited,
+		},
+		{
+			name:     "rate limited with RateLimit-Reset",
+			method:   http.MethodGet,
+			replies:  []reply{{status: 429, header: map[string]string{"RateLimit-Reset": "5"}}},
+			status:   200,
+			attempts: 2,
+			waits:    []time.Duration{5 * time.Second},
+			reasons:  ReasonRateLimited,
+		},
+		{
+			name:     "server errors until retries run out",
+			method:   http.MethodGet,
+			replies:  []reply{{status: 503}, {status: 502}, {status: 500}, {status: 500}},
+			status:   500,
+			attempts: 4,
+			reasons:  "server_error,server_error,server_error",
+		},
+		{
+			name:     "server error on a create is not repeated",
+			method:   http.MethodPost,
+			replies:  []reply{{status: 503}},
+			status:   503,
+			attempts: 1,
+		},
+		{
+			name:     "client error",
+			method:   http.MethodGet,
+			replies:  []reply{{status: 404}},
+			status:   404,
+			attempts: 1,
+		},
+		{
+			name:     "wait longer than the maximum",
+			method:   http.MethodGet,
+			replies:  []reply{{status: 429, header: map[string]string{"Retry-After": "3600"}}},
+			status:   429,
+			attempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &scriptedServer{replies: tt.replies}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+			var reasons []string
+			c, waits := newTestClient(RetryPolicy{
+				OnRetry: func(_ *http.Request, reason string, _ time.Duration) { reasons = append(reasons, reason) },
+			})
+
+			req, _ := http.NewRequestWithContext(context.Background(), tt.method, ts.URL, strings.NewReader("payload"))
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if len(srv.bodies) != tt.attempts {
+				t.Errorf("attempts = %d, want %d", len(srv.bodies), tt.attempts)
+			}
+			for i, body := range srv.bodies {
+				if body != "payload" {
+					t.Errorf("attempt %d body = %q, want the request body resent", i+1, body)
+				}
+			}
+			if got := strings.Join(reasons, ","); got != tt.reasons {
+				t.Errorf("retry reasons = %q, want %q", got, tt.reasons)
+			}
+			if tt.waits != nil && !equalDurations(*waits, tt.waits) {
+				t.Errorf("waits = %v, want %v", *waits, tt.waits)
+			}
+		})
+	}
+}
+
+func TestDoBacksOffExponentially(t *testing.T) {
+	ts := httptest.NewServer(&scriptedServer{replies: []reply{{status: 503}, {status: 503}, {status: 503}}})
+	defer ts.Close()
+	c, waits := newTestClient(RetryPolicy{MinBackoff: time.Second, MaxBackoff: 3 * time.Second})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	limits := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(*waits) != len(limits) {
+		t.Fatalf("waits = %v, want %d", *waits, len(limits))
+	}
+	for i, w := range *waits {
+		if w < limits[i]/2 || w > limits[i] {
+			t.Errorf("wait %d = %v, want between %v and %v", i+1, w, limits[i]/2, limits[i])
+		}
+	}
+}
+
+func TestDoPausesWhenRateLimitUsedUp(t *testing.T) {
+	ts := httptest.NewServer(&scriptedServer{replies: []reply{
+		{status: 200, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "4"}},
+	}})
+	defer ts.Close()
+	c, waits := newTestClient(RetryPolicy{})
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do #%d: %v", i+1, err)
+		}
+		_ = resp.Body.Close()
+	}
+	if !equalDurations(*waits, []time.Duration{4 * time.Second}) {
+		t.Errorf("waits = %v, want the second request held until the limit resets", *waits)
+	}
+}
+
+func TestDoDisabled(t *testing.T) {
+	srv := &scriptedServer{replies: []reply{{status: 429}}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c, _ := newTestClient(RetryPolicy{MaxRetries: -1})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != 429 || len(srv.bodies) != 1 {
+		t.Errorf("status %d after %d attempts, want 429 after 1", resp.StatusCode, len(srv.bodies))
+	}
+}
+
+func TestDoCancelledWhileWaiting(t *testing.T) {
+	ts := httptest.NewServer(&scriptedServer{replies: []reply{{status: 429, header: map[string]string{"Retry-After": "10"}}}})
+	defer ts.Close()
+	c := New(http.DefaultClient, RetryPolicy{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	if _, err := c.Do(req); err == nil {
+		t.Error("Do succeeded after its context expired, want an error")
+	}
+}
+
+func equalDurations(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimitedTransport spaces out the requests sent through it
+type rateLimitedTransport struct {
+	base     http.RoundTripper
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time // When the next request may be sent
+}
+
+// RateLimit wraps base so that at most perSecond requests a second are sent
+// through it, delaying requests sent sooner. A nil base uses
+// http.DefaultTransport; a perSecond of zero or less returns base
+// unlimited.
+func RateLimit(base http.RoundTripper, perSecond float64) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if perSecond <= 0 {
+		return base
+	}
+	return &rateLimitedTransport{base: base, interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	wait := t.next.Sub(now)
+	t.next = t.next.Add(t.interval)
+	t.mu.Unlock()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("cancelled waiting for rate limit: %w", req.Context().Err())
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,33 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer ts.Close()
+	client := &http.Client{Transport: RateLimit(nil, 50)}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+		_ = resp.Body.Close()
+	}
+	// The first request goes at once and each after it 20ms later
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("5 requests at 50/s took %v, want at least 80ms", elapsed)
+	}
+}
+
+func TestRateLimitUnlimited(t *testing.T) {
+	if rt := RateLimit(http.DefaultTransport, 0); rt != http.DefaultTransport {
+		t.Errorf("RateLimit(base, 0) = %T, want base unchanged", rt)
+	}
+}
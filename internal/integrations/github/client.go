@@ -0,0 +1,95 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client is a minimal GitHub REST API client for creating issues
+type Client struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewClient creates a GitHub API client authenticated with token
+func NewClient(apiURL, token string, transport http.RoundTripper) *Client {
+	return &Client{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+// IssueInput holds the fields used to create an issue
+type IssueInput struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// Issue is a GitHub issue as seen by the integration
+type Issue struct {
+	Repository string `json:"repository"` // owner/repo
+	Number     int    `json:"number"`
+	URL        string `json:"url"` // HTML URL for people, not the API resource
+}
+
+// Ref returns the issue's cross-repository reference, e.g. owner/repo#12
+func (i *Issue) Ref() string {
+	return fmt.Sprintf("%s#%d", i.Repository, i.Number)
+}
+
+// CreateIssue creates an issue in repository (owner/repo)
+func (c *Client) CreateIssue(ctx context.Context, repository string, input IssueInput) (*Issue, error) {
+	body := map[string]any{
+		"title": input.Title,
+		"body":  input.Body,
+	}
+	if len(input.Labels) > 0 {
+		body["labels"] = input.Labels
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues", c.apiURL, repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub issue: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitHub API error: %s (status: %d)", string(respBody), resp.StatusCode)
+	}
+
+	var result struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &Issue{Repository: repository, Number: result.Number, URL: result.HTMLURL}, nil
+}
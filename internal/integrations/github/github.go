@@ -0,0 +1,138 @@
+// Package github pushes outage action items to GitHub issues. A note marked
+// as an action item becomes an issue in the configured repository, and the
+// issue reference is written back to the note's metadata so follow-ups can
+// be tracked from the outage.
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+// Note metadata keys holding the back-reference to a created issue
+const (
+	MetadataIssue    = "github_issue"     // owner/repo#number
+	MetadataIssueURL = "github_issue_url" // HTML URL of the issue
+)
+
+const maxTitleLength = 80
+
+// ErrAlreadyLinked is returned by CreateIssue when the note already has an
+// issue
+var ErrAlreadyLinked = errors.New("note is already linked to a GitHub issue")
+
+// Config holds GitHub integration configuration
+type Config struct {
+	Token      string
+	Repository string   // owner/repo that issues are created in
+	APIURL     string   // Optional, defaults to https://api.github.com; set for GitHub Enterprise
+	Labels     []string // Added to every created issue
+	// OutageURL links issues back to the outage. "{id}" is replaced with
+	// the outage ID. Optional.
+	OutageURL string
+	// Transport is the HTTP transport used for API calls. Optional,
+	// defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// Integration creates GitHub issues from action-item notes
+type Integration struct {
+	service *service.Service
+	client  *Client
+	cfg     Config
+	logger  *slog.Logger
+}
+
+// New creates a GitHub integration
+func New(svc *service.Service, cfg Config, logger *slog.Logger) *Integration {
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.github.com"
+	}
+	return &Integration{
+		service: svc,
+		client:  NewClient(cfg.APIURL, cfg.Token, cfg.Transport),
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// CreateIssue creates a GitHub issue from an action-item note and stores the
+// issue reference in the note's metadata. Notes that are not marked as
+// action items are rejected with domain.ErrInvalidInput.
+func (i *Integration) CreateIssue(ctx context.Context, noteID uuid.UUID) (*Issue, error) {
+	note, err := i.service.GetNote(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if !note.IsActionItem() {
+		return nil, fmt.Errorf("%w: note %s is not marked as an action item", domain.ErrInvalidInput, noteID)
+	}
+	if ref := note.Metadata[MetadataIssue]; ref != "" {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyLinked, ref)
+	}
+	outage, err := i.service.GetOutage(ctx, note.OutageID)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := i.client.CreateIssue(ctx, i.cfg.Repository, IssueInput{
+		Title:  issueTitle(note.Content),
+		Body:   i.issueBody(note, outage),
+		Labels: i.cfg.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	i.logger.InfoContext(ctx, "created GitHub issue", "note_id", noteID, "issue", issue.Ref())
+
+	metadata := make(map[string]string, len(note.Metadata)+2)
+	for k, v := range note.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataIssue] = issue.Ref()
+	metadata[MetadataIssueURL] = issue.URL
+	if _, err := i.service.UpdateNote(ctx, noteID, nil, nil, metadata, nil, ""); err != nil {
+		return nil, fmt.Errorf("created GitHub issue %s but failed to update note: %w", issue.Ref(), err)
+	}
+	return issue, nil
+}
+
+// issueTitle uses the first line of the note, shortened to fit a title
+func issueTitle(content string) string {
+	title := strings.TrimSpace(content)
+	if idx := strings.IndexByte(title, '\n'); idx >= 0 {
+		title = strings.TrimSpace(title[:idx])
+	}
+	title = strings.TrimLeft(title, "#-* ")
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = strings.TrimSpace(string(runes[:maxTitleLength-1])) + "…"
+	}
+	if title == "" {
+		title = "Outage action item"
+	}
+	return title
+}
+
+// issueBody builds the issue body from the note and its outage
+func (i *Integration) issueBody(note *domain.Note, outage *domain.Outage) string {
+	var sb strings.Builder
+	sb.WriteString(note.Content)
+	sb.WriteString("\n\n---\n")
+	outageRef := outage.ID.String()
+	if i.cfg.OutageURL != "" {
+		outageRef = strings.ReplaceAll(i.cfg.OutageURL, "{id}", outage.ID.String())
+	}
+	fmt.Fprintf(&sb, "Action item from outage **%s** (%s, %s): %s\n", outage.Title, outage.Severity, outage.Status, outageRef)
+	if note.Author != "" {
+		fmt.Fprintf(&sb, "Raised by %s\n", note.Author)
+	}
+	return sb.String()
+}
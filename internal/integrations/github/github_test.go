@@ -0,0 +1,149 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/gorilla/mux"
+)
+
+// fakeGitHub serves the create issue endpoint and records requests
+type fakeGitHub struct {
+	mu      sync.Mutex
+	created []map[string]any
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/ops/issues" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.created = append(f.created, body)
+	number := len(f.created) + 41
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"number":   number,
+		"html_url": fmt.Sprintf("https://github.com/acme/ops/issues/%d", number),
+	})
+}
+
+func TestCreateIssue(t *testing.T) {
+	gh := &fakeGitHub{}
+	server := httptest.NewServer(gh)
+	defer server.Close()
+
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	integration := New(svc, Config{
+		Token:      "token",
+		Repository: "acme/ops",
+		APIURL:     server.URL,
+		Labels:     []string{"outage-follow-up"},
+	}, logging.Discard())
+	router := mux.NewRouter()
+	integration.RegisterHandlers(router)
+	ctx := context.Background()
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "API down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actionItem, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{
+		Content:  "Add alerting on connection pool exhaustion\nWe only noticed from customer reports.",
+		Author:   "alice@example.com",
+		Metadata: map[string]string{domain.NoteMetadataActionItem: "true", "owner": "db-team"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainNote, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "Restarted the pool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(noteID string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/notes/"+noteID+"/github-issue", nil))
+		return rr
+	}
+
+	rr := post(actionItem.ID.String())
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST github-issue = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var issue Issue
+	if err := json.NewDecoder(rr.Body).Decode(&issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.Ref() != "acme/ops#42" {
+		t.Errorf("issue ref = %s, want acme/ops#42", issue.Ref())
+	}
+
+	created := gh.created[0]
+	if created["title"] != "Add alerting on connection pool exhaustion" {
+		t.Errorf("title = %q, want first line of the note", created["title"])
+	}
+	if body, _ := created["body"].(string); !strings.Contains(body, "API down") || !strings.Contains(body, "alice@example.com") {
+		t.Errorf("body %q does not reference the outage and author", body)
+	}
+
+	note, err := svc.GetNote(ctx, actionItem.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Metadata[MetadataIssue] != "acme/ops#42" || note.Metadata[MetadataIssueURL] != issue.URL {
+		t.Errorf("note metadata = %v, want issue back-reference", note.Metadata)
+	}
+	if note.Metadata["owner"] != "db-team" {
+		t.Errorf("existing note metadata lost: %v", note.Metadata)
+	}
+
+	if rr := post(actionItem.ID.String()); rr.Code != http.StatusConflict {
+		t.Errorf("second POST github-issue = %d, want 409", rr.Code)
+	}
+	if rr := post(plainNote.ID.String()); rr.Code != http.StatusBadRequest {
+		t.Errorf("POST github-issue for a plain note = %d, want 400", rr.Code)
+	}
+	if len(gh.created) != 1 {
+		t.Errorf("created %d issues, want 1", len(gh.created))
+	}
+}
+
+func TestIssueTitle(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"Fix the thing", "Fix the thing"},
+		{"## Heading\nbody", "Heading"},
+		{"  \n", "Outage action item"},
+		{strings.Repeat("a", 100), strings.Repeat("a", 79) + "…"},
+	}
+	for _, tt := range tests {
+		if got := issueTitle(tt.content); got != tt.want {
+			t.Errorf("issueTitle(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
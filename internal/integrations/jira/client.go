@@ -0,0 +1,152 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is a minimal Jira REST API (v2) client covering the calls the
+// integration needs: creating issues and reading their status
+type Client struct {
+	baseURL  string
+	email    string
+	apiToken string
+	client   *http.Client
+}
+
+// NewClient creates a Jira API client. When email is empty the token is sent
+// as a bearer token (Jira Data Center personal access tokens); otherwise
+// email and token are used for basic auth (Jira Cloud API tokens).
+func NewClient(baseURL, email, apiToken string, transport http.RoundTripper) *Client {
+	return &Client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		email:    email,
+		apiToken: apiToken,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+// IssueInput holds the fields used to create an issue
+type IssueInput struct {
+	ProjectKey  string
+	IssueType   string
+	Summary     string
+	Description string
+	Priority    string // Omitted when empty
+	Labels      []string
+}
+
+// Issue is a Jira issue as seen by the integration
+type Issue struct {
+	Key            string `json:"key"`
+	URL            string `json:"url"` // Browse URL for people, not the REST resource
+	Status         string `json:"status,omitempty"`
+	StatusCategory string `json:"status_category,omitempty"` // new, indeterminate or done
+}
+
+// CreateIssue creates an issue and returns its key and browse URL
+func (c *Client) CreateIssue(ctx context.Context, input IssueInput) (*Issue, error) {
+	fields := map[string]any{
+		"project":     map[string]string{"key": input.ProjectKey},
+		"issuetype":   map[string]string{"name": input.IssueType},
+		"summary":     input.Summary,
+		"description": input.Description,
+	}
+	if input.Priority != "" {
+		fields["priority"] = map[string]string{"name": input.Priority}
+	}
+	if len(input.Labels) > 0 {
+		fields["labels"] = input.Labels
+	}
+
+	var result struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]any{"fields": fields}, &result); err != nil {
+		return nil, fmt.Errorf("failed to create Jira issue: %w", err)
+	}
+	return &Issue{Key: result.Key, URL: c.BrowseURL(result.Key)}, nil
+}
+
+// GetIssue retrieves an issue's current status
+func (c *Client) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	var result struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Status struct {
+				Name           string `json:"name"`
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=status"
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get Jira issue %s: %w", key, err)
+	}
+	return &Issue{
+		Key:            result.Key,
+		URL:            c.BrowseURL(result.Key),
+		Status:         result.Fields.Status.Name,
+		StatusCategory: result.Fields.Status.StatusCategory.Key,
+	}, nil
+}
+
+// BrowseURL returns the web URL for an issue
+func (c *Client) BrowseURL(key string) string {
+	return c.baseURL + "/browse/" + key
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.apiToken)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Jira API error: %s (status: %d)", string(respBody), resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/apierr"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// RegisterHandlers registers the Jira integration's HTTP routes
+func (i *Integration) RegisterHandlers(r *mux.Router) {
+	r.HandleFunc("/api/v1/outages/{id}/jira", i.HandleCreateIssue).Methods("POST")
+}
+
+// HandleCreateIssue handles POST /api/v1/outages/{id}/jira
+func (i *Integration) HandleCreateIssue(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	issue, err := i.CreateIssue(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Outage not found")
+		case errors.Is(err, ErrAlreadyLinked):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			i.logger.ErrorContext(r.Context(), "failed to create Jira issue", "outage_id", id, "error", err)
+			respondError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, issue)
+}
+
+func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	apierr.Write(w, status, message)
+}
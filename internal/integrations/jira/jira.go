@@ -0,0 +1,234 @@
+// Package jira links outages to Jira issues. It creates an issue from an
+// outage, records the issue key as a "jira" tag, and periodically copies the
+// status of every linked issue into the outage's custom_fields.
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+const (
+	// TagKey is the tag key linking an outage to a Jira issue. Tags added by
+	// hand with this key are synced too.
+	TagKey = "jira"
+
+	// CustomFieldKey is the outage custom field holding linked issue
+	// statuses, keyed by issue key.
+	CustomFieldKey = "jira_issues"
+
+	defaultIssueType          = "Task"
+	defaultStatusSyncInterval = 15 * time.Minute
+)
+
+// defaultPriorities maps Outalator severities to Jira's default priority scheme
+var defaultPriorities = map[string]string{
+	"critical": "Highest",
+	"high":     "High",
+	"medium":   "Medium",
+	"low":      "Low",
+}
+
+// ErrAlreadyLinked is returned by CreateIssue when the outage already has a
+// Jira issue
+var ErrAlreadyLinked = errors.New("outage is already linked to a Jira issue")
+
+// Config holds Jira integration configuration
+type Config struct {
+	URL        string // Base URL, e.g. https://example.atlassian.net
+	Email      string // Account email for Jira Cloud; empty sends APIToken as a bearer token
+	APIToken   string
+	ProjectKey string
+	IssueType  string            // Optional, defaults to Task
+	Priorities map[string]string // Outage severity to Jira priority name; defaults to Highest/High/Medium/Low
+	Labels     []string          // Added to every created issue
+	// OutageURL links issues back to the outage. "{id}" is replaced with
+	// the outage ID. Optional.
+	OutageURL string
+	// Transport is the HTTP transport used for API calls. Optional,
+	// defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// Integration creates Jira issues for outages and keeps their status in sync
+type Integration struct {
+	service *service.Service
+	client  *Client
+	cfg     Config
+	logger  *slog.Logger
+}
+
+// New creates a Jira integration
+func New(svc *service.Service, cfg Config, logger *slog.Logger) *Integration {
+	if cfg.IssueType == "" {
+		cfg.IssueType = defaultIssueType
+	}
+	if cfg.Priorities == nil {
+		cfg.Priorities = defaultPriorities
+	}
+	return &Integration{
+		service: svc,
+		client:  NewClient(cfg.URL, cfg.Email, cfg.APIToken, cfg.Transport),
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// CreateIssue creates a Jira issue from an outage, tags the outage with the
+// issue key and records the issue in the outage's custom_fields
+func (i *Integration) CreateIssue(ctx context.Context, outageID uuid.UUID) (*Issue, error) {
+	outage, err := i.service.GetOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range outage.Tags {
+		if tag.Key == TagKey {
+			return nil, fmt.Errorf("%w: %s", ErrAlreadyLinked, tag.Value)
+		}
+	}
+
+	issue, err := i.client.CreateIssue(ctx, IssueInput{
+		ProjectKey:  i.cfg.ProjectKey,
+		IssueType:   i.cfg.IssueType,
+		Summary:     outage.Title,
+		Description: i.description(outage),
+		Priority:    i.cfg.Priorities[outage.Severity],
+		Labels:      i.cfg.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := i.service.AddTag(ctx, outageID, TagKey, issue.Key); err != nil {
+		return nil, fmt.Errorf("created Jira issue %s but failed to tag outage: %w", issue.Key, err)
+	}
+	i.logger.InfoContext(ctx, "created Jira issue", "outage_id", outageID, "issue", issue.Key)
+
+	// The status is filled in by the next sync; a failure here only delays it
+	if err := i.recordIssue(ctx, outage, issue); err != nil {
+		i.logger.WarnContext(ctx, "failed to record Jira issue on outage",
+			"outage_id", outageID, "issue", issue.Key, "error", err)
+	}
+	return issue, nil
+}
+
+// SyncStatuses copies the current status of every issue linked by a "jira"
+// tag into its outage's custom_fields. Failures for individual issues are
+// logged and do not stop the sync.
+func (i *Integration) SyncStatuses(ctx context.Context) error {
+	tags, err := i.service.ListTagsByKey(ctx, TagKey)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		issue, err := i.client.GetIssue(ctx, tag.Value)
+		if err != nil {
+			i.logger.WarnContext(ctx, "failed to fetch Jira issue status",
+				"outage_id", tag.OutageID, "issue", tag.Value, "error", err)
+			continue
+		}
+		outage, err := i.service.GetOutage(ctx, tag.OutageID)
+		if err != nil {
+			i.logger.WarnContext(ctx, "failed to load outage for Jira sync",
+				"outage_id", tag.OutageID, "error", err)
+			continue
+		}
+		if err := i.recordIssue(ctx, outage, issue); err != nil {
+			i.logger.WarnContext(ctx, "failed to record Jira issue status",
+				"outage_id", tag.OutageID, "issue", issue.Key, "error", err)
+		}
+	}
+	return nil
+}
+
+// RunStatusSync calls SyncStatuses every interval until ctx is cancelled
+func (i *Integration) RunStatusSync(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultStatusSyncInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := i.SyncStatuses(ctx); err != nil && ctx.Err() == nil {
+				i.logger.ErrorContext(ctx, "Jira status sync failed", "error", err)
+			}
+		}
+	}
+}
+
+// recordIssue stores an issue's URL and status under the outage's
+// jira_issues custom field, skipping the write when nothing changed
+func (i *Integration) recordIssue(ctx context.Context, outage *domain.Outage, issue *Issue) error {
+	entry := map[string]any{"url": issue.URL}
+	if issue.Status != "" {
+		entry["status"] = issue.Status
+		entry["status_category"] = issue.StatusCategory
+	}
+
+	issues := map[string]any{}
+	if existing, ok := outage.CustomFields[CustomFieldKey].(map[string]any); ok {
+		for k, v := range existing {
+			issues[k] = v
+		}
+	}
+	if previous, ok := issues[issue.Key].(map[string]any); ok && sameEntry(previous, entry) {
+		return nil
+	}
+	issues[issue.Key] = entry
+
+	fields := make(map[string]any, len(outage.CustomFields)+1)
+	for k, v := range outage.CustomFields {
+		fields[k] = v
+	}
+	fields[CustomFieldKey] = issues
+
+	_, err := i.service.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{CustomFields: fields})
+	return err
+}
+
+func sameEntry(a, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range b {
+		if a[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// description builds the issue body from the outage
+func (i *Integration) description(outage *domain.Outage) string {
+	var sb strings.Builder
+	if outage.Description != "" {
+		sb.WriteString(outage.Description)
+		sb.WriteString("\n\n")
+	}
+	fmt.Fprintf(&sb, "Severity: %s\nStatus: %s\nStarted: %s\n",
+		outage.Severity, outage.Status, outage.CreatedAt.UTC().Format(time.RFC3339))
+	if i.cfg.OutageURL != "" {
+		fmt.Fprintf(&sb, "Outage: %s\n", strings.ReplaceAll(i.cfg.OutageURL, "{id}", outage.ID.String()))
+	} else {
+		fmt.Fprintf(&sb, "Outage ID: %s\n", outage.ID)
+	}
+	return sb.String()
+}
@@ -0,0 +1,193 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/gorilla/mux"
+)
+
+// fakeJira serves the issue create and get endpoints from memory
+type fakeJira struct {
+	mu       sync.Mutex
+	created  []map[string]any
+	statuses map[string]string
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "bot@example.com" || pass != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var body struct {
+			Fields map[string]any `json:"fields"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.created = append(f.created, body.Fields)
+		key := "OPS-" + string(rune('0'+len(f.created)))
+		f.statuses[key] = "To Do"
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": "1", "key": key})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/"):
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		status, ok := f.statuses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		category := "new"
+		if status == "Done" {
+			category = "done"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"key": key,
+			"fields": map[string]any{
+				"status": map[string]any{"name": status, "statusCategory": map[string]string{"key": category}},
+			},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestIntegration(t *testing.T) (*Integration, *fakeJira, *mux.Router) {
+	t.Helper()
+	jira := &fakeJira{statuses: map[string]string{}}
+	server := httptest.NewServer(jira)
+	t.Cleanup(server.Close)
+
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	integration := New(svc, Config{
+		URL:        server.URL,
+		Email:      "bot@example.com",
+		APIToken:   "token",
+		ProjectKey: "OPS",
+		OutageURL:  "https://outalator.example.com/outages/{id}",
+	}, logging.Discard())
+	router := mux.NewRouter()
+	integration.RegisterHandlers(router)
+	return integration, jira, router
+}
+
+func TestCreateIssue(t *testing.T) {
+	integration, jira, router := newTestIntegration(t)
+	ctx := context.Background()
+
+	outage, err := integration.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "API down", Description: "5xx everywhere", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "/api/v1/outages/" + outage.ID.String() + "/jira"
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, url, nil))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST jira = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	var issue Issue
+	if err := json.NewDecoder(rr.Body).Decode(&issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.Key != "OPS-1" || !strings.HasSuffix(issue.URL, "/browse/OPS-1") {
+		t.Errorf("issue = %+v, want OPS-1 with browse URL", issue)
+	}
+
+	fields := jira.created[0]
+	if fields["summary"] != "API down" {
+		t.Errorf("summary = %v, want outage title", fields["summary"])
+	}
+	if priority, _ := fields["priority"].(map[string]any); priority["name"] != "Highest" {
+		t.Errorf("priority = %v, want Highest for critical", fields["priority"])
+	}
+	if desc, _ := fields["description"].(string); !strings.Contains(desc, "https://outalator.example.com/outages/"+outage.ID.String()) {
+		t.Errorf("description %q does not link back to the outage", desc)
+	}
+
+	got, err := integration.service.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Tags) != 1 || got.Tags[0].Key != TagKey || got.Tags[0].Value != "OPS-1" {
+		t.Errorf("tags = %+v, want jira:OPS-1", got.Tags)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, url, nil))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("second POST jira = %d, want 409", rr.Code)
+	}
+	if len(jira.created) != 1 {
+		t.Errorf("created %d issues, want 1", len(jira.created))
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/outages/not-a-uuid/jira", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("POST jira with bad ID = %d, want 400", rr.Code)
+	}
+}
+
+func TestSyncStatuses(t *testing.T) {
+	integration, jira, _ := newTestIntegration(t)
+	ctx := context.Background()
+
+	outage, err := integration.service.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title:        "DB failover",
+		Severity:     "high",
+		CustomFields: map[string]any{"impact": "partial"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A hand-added tag is synced like one created by the integration
+	if _, err := integration.service.AddTag(ctx, outage.ID, TagKey, "OPS-7"); err != nil {
+		t.Fatal(err)
+	}
+	jira.statuses["OPS-7"] = "Done"
+
+	if err := integration.SyncStatuses(ctx); err != nil {
+		t.Fatalf("SyncStatuses: %v", err)
+	}
+
+	got, err := integration.service.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CustomFields["impact"] != "partial" {
+		t.Errorf("existing custom field lost: %v", got.CustomFields)
+	}
+	issues, _ := got.CustomFields[CustomFieldKey].(map[string]any)
+	entry, _ := issues["OPS-7"].(map[string]any)
+	if entry["status"] != "Done" || entry["status_category"] != "done" {
+		t.Errorf("jira_issues = %v, want OPS-7 Done", got.CustomFields[CustomFieldKey])
+	}
+
+	// An unchanged status does not rewrite the outage
+	if err := integration.SyncStatuses(ctx); err != nil {
+		t.Fatalf("SyncStatuses: %v", err)
+	}
+	again, err := integration.service.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.UpdatedAt.Equal(got.UpdatedAt) {
+		t.Error("outage was updated although the Jira status did not change")
+	}
+}
@@ -0,0 +1,118 @@
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Statuspage incident statuses
+const (
+	StatusInvestigating = "investigating"
+	StatusResolved      = "resolved"
+)
+
+// Statuspage component statuses
+const (
+	ComponentOperational         = "operational"
+	ComponentDegradedPerformance = "degraded_performance"
+	ComponentPartialOutage       = "partial_outage"
+	ComponentMajorOutage         = "major_outage"
+)
+
+// Client is a minimal Statuspage REST API (v1) client covering incident
+// creation and updates for a single page
+type Client struct {
+	baseURL string
+	pageID  string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewClient creates a Statuspage API client for a page
+func NewClient(baseURL, pageID, apiKey string, transport http.RoundTripper) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		pageID:  pageID,
+		apiKey:  apiKey,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+// IncidentInput holds the incident fields sent on create or update. Empty
+// fields are omitted, so an update with only Body posts a message without
+// changing the incident's status.
+type IncidentInput struct {
+	Name       string            `json:"name,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Components map[string]string `json:"components,omitempty"` // Component ID to component status
+	// ComponentIDs lists the components the incident affects
+	ComponentIDs []string `json:"component_ids,omitempty"`
+}
+
+// Incident is a Statuspage incident as seen by the integration
+type Incident struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	Shortlink string `json:"shortlink,omitempty"`
+}
+
+// CreateIncident opens a new incident on the page
+func (c *Client) CreateIncident(ctx context.Context, input IncidentInput) (*Incident, error) {
+	var incident Incident
+	path := "/pages/" + url.PathEscape(c.pageID) + "/incidents"
+	if err := c.do(ctx, http.MethodPost, path, map[string]any{"incident": input}, &incident); err != nil {
+		return nil, fmt.Errorf("failed to create Statuspage incident: %w", err)
+	}
+	return &incident, nil
+}
+
+// UpdateIncident posts an update to an incident
+func (c *Client) UpdateIncident(ctx context.Context, incidentID string, input IncidentInput) (*Incident, error) {
+	var incident Incident
+	path := "/pages/" + url.PathEscape(c.pageID) + "/incidents/" + url.PathEscape(incidentID)
+	if err := c.do(ctx, http.MethodPatch, path, map[string]any{"incident": input}, &incident); err != nil {
+		return nil, fmt.Errorf("failed to update Statuspage incident %s: %w", incidentID, err)
+	}
+	return &incident, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "OAuth "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Statuspage API error: %s (status: %d)", string(respBody), resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package statuspage
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/apierr"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// RegisterHandlers registers the Statuspage integration's HTTP routes
+func (i *Integration) RegisterHandlers(r *mux.Router) {
+	r.HandleFunc("/api/v1/outages/{id}/statuspage", i.HandleCreateIncident).Methods("POST")
+}
+
+// HandleCreateIncident handles POST /api/v1/outages/{id}/statuspage
+func (i *Integration) HandleCreateIncident(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid outage ID")
+		return
+	}
+
+	incident, err := i.CreateIncident(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Outage not found")
+		case errors.Is(err, ErrAlreadyLinked):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			i.logger.ErrorContext(r.Context(), "failed to create Statuspage incident", "outage_id", id, "error", err)
+			respondError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, incident)
+}
+
+func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	apierr.Write(w, status, message)
+}
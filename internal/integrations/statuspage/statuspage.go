@@ -0,0 +1,217 @@
+// Package statuspage publishes outages to an Atlassian Statuspage page. An
+// outage is pushed as an incident on request and linked by a "statuspage"
+// tag; after that, notes marked public are posted as incident updates and the
+// incident is resolved when the outage is.
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+const (
+	// TagKey is the tag key linking an outage to a Statuspage incident ID
+	TagKey = "statuspage"
+
+	// MetadataPublic marks a note for publishing when its metadata value is
+	// "true"
+	MetadataPublic = domain.MetadataPublic
+
+	defaultAPIURL       = "https://api.statuspage.io/v1"
+	defaultComponentTag = "service"
+	resolvedMessage     = "This incident has been resolved."
+)
+
+// componentStatuses maps Outalator severities to the status affected
+// components are given while the incident is open
+var componentStatuses = map[string]string{
+	"critical": ComponentMajorOutage,
+	"high":     ComponentPartialOutage,
+	"medium":   ComponentDegradedPerformance,
+	"low":      ComponentDegradedPerformance,
+}
+
+// ErrAlreadyLinked is returned by CreateIncident when the outage already has
+// a Statuspage incident
+var ErrAlreadyLinked = errors.New("outage is already linked to a Statuspage incident")
+
+// Config holds Statuspage integration configuration
+type Config struct {
+	APIKey string
+	PageID string
+	APIURL string // Optional, defaults to https://api.statuspage.io/v1
+	// ComponentTag is the outage tag whose values select components.
+	// Optional, defaults to "service".
+	ComponentTag string
+	// Components maps ComponentTag values, and affected service names, to
+	// Statuspage component IDs. A service's catalogued component takes
+	// precedence. Outages without a mapped tag or affected service are
+	// published without components.
+	Components map[string]string
+	// Transport is the HTTP transport used for API calls. Optional,
+	// defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// Integration keeps Statuspage incidents in sync with outages
+type Integration struct {
+	service *service.Service
+	client  *Client
+	cfg     Config
+	logger  *slog.Logger
+}
+
+// New creates a Statuspage integration. Register it with the service as an
+// outage listener so updates and resolution are published.
+func New(svc *service.Service, cfg Config, logger *slog.Logger) *Integration {
+	if cfg.APIURL == "" {
+		cfg.APIURL = defaultAPIURL
+	}
+	if cfg.ComponentTag == "" {
+		cfg.ComponentTag = defaultComponentTag
+	}
+	return &Integration{
+		service: svc,
+		client:  NewClient(cfg.APIURL, cfg.PageID, cfg.APIKey, cfg.Transport),
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// CreateIncident publishes an outage as a Statuspage incident and tags the
+// outage with the incident ID
+func (i *Integration) CreateIncident(ctx context.Context, outageID uuid.UUID) (*Incident, error) {
+	outage, err := i.service.GetOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if id := incidentID(outage); id != "" {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyLinked, id)
+	}
+
+	componentIDs := i.components(ctx, outage)
+	input := IncidentInput{
+		Name:         outage.Title,
+		Status:       StatusInvestigating,
+		Body:         outage.Description,
+		ComponentIDs: componentIDs,
+	}
+	if status, ok := componentStatuses[outage.Severity]; ok && len(componentIDs) > 0 {
+		input.Components = componentStatusMap(componentIDs, status)
+	}
+
+	incident, err := i.client.CreateIncident(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := i.service.AddTag(ctx, outageID, TagKey, incident.ID); err != nil {
+		return nil, fmt.Errorf("created Statuspage incident %s but failed to tag outage: %w", incident.ID, err)
+	}
+	i.logger.InfoContext(ctx, "created Statuspage incident", "outage_id", outageID, "incident", incident.ID)
+	return incident, nil
+}
+
+// NoteAdded implements service.OutageListener by posting public notes on a
+// linked outage as incident updates
+func (i *Integration) NoteAdded(ctx context.Context, outage *domain.Outage, note *domain.Note) error {
+	id := incidentID(outage)
+	if id == "" || note.Metadata[MetadataPublic] != "true" {
+		return nil
+	}
+	if _, err := i.client.UpdateIncident(ctx, id, IncidentInput{Body: note.Content}); err != nil {
+		return err
+	}
+	i.logger.InfoContext(ctx, "posted Statuspage incident update", "outage_id", outage.ID, "incident", id, "note_id", note.ID)
+	return nil
+}
+
+// OutageCreated implements service.OutageListener. Outages are only published
+// on request, by CreateIncident.
+func (i *Integration) OutageCreated(context.Context, *domain.Outage) error {
+	return nil
+}
+
+// AlertAdded implements service.OutageListener. Alerts are internal detail
+// and are not published.
+func (i *Integration) AlertAdded(context.Context, *domain.Outage, *domain.Alert) error {
+	return nil
+}
+
+// OutageStatusChanged implements service.OutageListener. Only resolution is
+// published, by OutageResolved.
+func (i *Integration) OutageStatusChanged(context.Context, *domain.Outage, string) error {
+	return nil
+}
+
+// OutageResolved implements service.OutageListener by resolving a linked
+// outage's incident and marking its components operational
+func (i *Integration) OutageResolved(ctx context.Context, outage *domain.Outage) error {
+	id := incidentID(outage)
+	if id == "" {
+		return nil
+	}
+	input := IncidentInput{Status: StatusResolved, Body: resolvedMessage}
+	if componentIDs := i.components(ctx, outage); len(componentIDs) > 0 {
+		input.Components = componentStatusMap(componentIDs, ComponentOperational)
+	}
+	if _, err := i.client.UpdateIncident(ctx, id, input); err != nil {
+		return err
+	}
+	i.logger.InfoContext(ctx, "resolved Statuspage incident", "outage_id", outage.ID, "incident", id)
+	return nil
+}
+
+// components returns the Statuspage component IDs of the outage's affected
+// services and those mapped from its tags, without duplicates
+func (i *Integration) components(ctx context.Context, outage *domain.Outage) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, name := range outage.AffectedServices {
+		svc, err := i.service.GetService(ctx, name)
+		switch {
+		case err == nil && svc.StatuspageComponent != "":
+			add(svc.StatuspageComponent)
+		case err != nil && !errors.Is(err, domain.ErrNotFound):
+			i.logger.WarnContext(ctx, "failed to look up affected service", "outage_id", outage.ID, "service", name, "error", err)
+		default:
+			add(i.cfg.Components[name])
+		}
+	}
+	for _, tag := range outage.Tags {
+		if tag.Key == i.cfg.ComponentTag {
+			add(i.cfg.Components[tag.Value])
+		}
+	}
+	return ids
+}
+
+func componentStatusMap(ids []string, status string) map[string]string {
+	m := make(map[string]string, len(ids))
+	for _, id := range ids {
+		m[id] = status
+	}
+	return m
+}
+
+// incidentID returns the linked incident ID, or "" if there is none
+func incidentID(outage *domain.Outage) string {
+	for _, tag := range outage.Tags {
+		if tag.Key == TagKey {
+			return tag.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+	"github.com/gorilla/mux"
+)
+
+// fakeStatuspage records incident creates and updates for page "page1"
+type fakeStatuspage struct {
+	mu      sync.Mutex
+	created []IncidentInput
+	updates map[string][]IncidentInput
+}
+
+func (f *fakeStatuspage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "OAuth key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var body struct {
+		Incident IncidentInput `json:"incident"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/pages/page1/incidents":
+		f.created = append(f.created, body.Incident)
+		_ = json.NewEncoder(w).Encode(Incident{ID: "inc1", Status: body.Incident.Status})
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/pages/page1/incidents/"):
+		id := strings.TrimPrefix(r.URL.Path, "/pages/page1/incidents/")
+		f.updates[id] = append(f.updates[id], body.Incident)
+		_ = json.NewEncoder(w).Encode(Incident{ID: id, Status: body.Incident.Status})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestIntegration(t *testing.T) (*Integration, *fakeStatuspage, *mux.Router) {
+	t.Helper()
+	statuspage := &fakeStatuspage{updates: map[string][]IncidentInput{}}
+	server := httptest.NewServer(statuspage)
+	t.Cleanup(server.Close)
+
+	svc := service.New(testutil.NewMemStorage(), logging.Discard())
+	integration := New(svc, Config{
+		APIKey:     "key",
+		PageID:     "page1",
+		APIURL:     server.URL,
+		Components: map[string]string{"api": "comp-api", "web": "comp-web"},
+	}, logging.Discard())
+	svc.RegisterOutageListener(integration)
+	router := mux.NewRouter()
+	integration.RegisterHandlers(router)
+	return integration, statuspage, router
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	integration, statuspage, router := newTestIntegration(t)
+	svc := integration.service
+	ctx := context.Background()
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "API down", Description: "Elevated errors", Severity: "critical",
+		Tags: []domain.TagInput{{Key: "service", Value: "api"}, {Key: "service", Value: "billing"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Notes on an unpublished outage stay internal
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "early", Format: "plaintext", Author: "a", Metadata: map[string]string{MetadataPublic: "true"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	url := "/api/v1/outages/" + outage.ID.String() + "/statuspage"
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, url, nil))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST statuspage = %d, want 201; body: %s", rr.Code, rr.Body.String())
+	}
+	if len(statuspage.created) != 1 {
+		t.Fatalf("created %d incidents, want 1", len(statuspage.created))
+	}
+	created := statuspage.created[0]
+	if created.Name != "API down" || created.Status != StatusInvestigating {
+		t.Errorf("incident = %+v", created)
+	}
+	if len(created.ComponentIDs) != 1 || created.Components["comp-api"] != ComponentMajorOutage {
+		t.Errorf("components = %v %v, want comp-api major_outage", created.ComponentIDs, created.Components)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, url, nil))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("second POST statuspage = %d, want 409", rr.Code)
+	}
+
+	for _, req := range []domain.AddNoteRequest{
+		{Content: "internal only", Format: "plaintext", Author: "a"},
+		{Content: "We have identified the cause", Format: "plaintext", Author: "a", Metadata: map[string]string{MetadataPublic: "true"}},
+	} {
+		if _, err := svc.AddNote(ctx, outage.ID, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	updates := statuspage.updates["inc1"]
+	if len(updates) != 1 || updates[0].Body != "We have identified the cause" || updates[0].Status != "" {
+		t.Fatalf("updates after notes = %+v, want the public note only", updates)
+	}
+
+	resolved := "resolved"
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &resolved}); err != nil {
+		t.Fatal(err)
+	}
+	closed := "closed"
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &closed}); err != nil {
+		t.Fatal(err)
+	}
+	updates = statuspage.updates["inc1"]
+	if len(updates) != 2 {
+		t.Fatalf("got %d updates, want one resolution", len(updates)-1)
+	}
+	if updates[1].Status != StatusResolved || updates[1].Components["comp-api"] != ComponentOperational {
+		t.Errorf("resolution = %+v", updates[1])
+	}
+}
+
+func TestIncidentAffectedServiceComponents(t *testing.T) {
+	integration, statuspage, _ := newTestIntegration(t)
+	svc := integration.service
+	ctx := context.Background()
+
+	for _, s := range []domain.Service{{Name: "checkout", StatuspageComponent: "comp-checkout"}, {Name: "web"}} {
+		if _, _, err := svc.SaveService(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "Checkout errors", Severity: "high",
+		AffectedServices: []string{"checkout", "web"},
+		Tags:             []domain.TagInput{{Key: "service", Value: "web"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := integration.CreateIncident(ctx, outage.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	created := statuspage.created[0]
+	want := map[string]string{"comp-checkout": ComponentPartialOutage, "comp-web": ComponentPartialOutage}
+	if len(created.ComponentIDs) != 2 || len(created.Components) != 2 ||
+		created.Components["comp-checkout"] != want["comp-checkout"] || created.Components["comp-web"] != want["comp-web"] {
+		t.Errorf("components = %v %v, want %v", created.ComponentIDs, created.Components, want)
+	}
+}
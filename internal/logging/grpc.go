@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor assigns each unary call a request ID, taken from the
+// x-request-id metadata when present, returns it as a response header and
+// logs a line per completed call.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = withGRPCRequestID(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor does for streaming calls what
+// UnaryServerInterceptor does for unary ones, logging when the stream ends
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withGRPCRequestID(ss.Context())
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// withGRPCRequestID adds the call's request ID to ctx and sends it back as
+// a response header
+func withGRPCRequestID(ctx context.Context) context.Context {
+	header := strings.ToLower(RequestIDHeader)
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(header); len(values) > 0 && len(values[0]) <= maxRequestIDLength {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+	ctx = WithRequestID(ctx, id)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(header, id))
+	return ctx
+}
+
+// logCall logs a completed call, at error level for codes that suggest a
+// server fault
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK, codes.NotFound, codes.InvalidArgument, codes.AlreadyExists, codes.Canceled, codes.Unauthenticated:
+	default:
+		level = slog.LevelError
+	}
+	attrs := []any{"method", method, "code", code.String(), "duration", time.Since(start)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	logger.Log(ctx, level, "grpc request", attrs...)
+}
+
+// serverStream overrides the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header used to propagate request IDs. Incoming
+// values are honoured so IDs assigned by a load balancer or caller carry
+// through; otherwise a new ID is generated. The ID is echoed on the response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied request IDs
+const maxRequestIDLength = 128
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush event streams
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Middleware assigns each request an ID, stores it in the request context
+// and logs a line per completed request.
+func Middleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > maxRequestIDLength {
+				id = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			ctx := WithRequestID(r.Context(), id)
+
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			level := slog.LevelInfo
+			if rec.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.Log(ctx, level, "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"duration", time.Since(start),
+				"remote_addr", r.RemoteAddr,
+			)
+		})
+	}
+}
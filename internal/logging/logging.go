@@ -0,0 +1,128 @@
+// Package logging builds the structured logger shared by the server
+// components and carries per-request correlation data (request ID and
+// authenticated user) through contexts so every log line emitted while
+// handling a request can be tied back to it.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/conall/outalator/internal/auth"
+)
+
+// Supported output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config holds logger configuration
+type Config struct {
+	Level  string // debug, info, warn or error; defaults to info
+	Format string // text or json; defaults to text
+}
+
+// New creates a logger writing to w. Records logged with a context carrying
+// a request ID or authenticated user are annotated with request_id and user
+// attributes.
+func New(cfg Config, w io.Writer) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want text or json)", cfg.Format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// ParseLevel converts a level name to a slog.Level. An empty name is info.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", name)
+}
+
+// Discard returns a logger that drops every record. It is intended for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+type requestIDKey struct{}
+
+type userKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithUser returns a copy of ctx identifying the user a request is acting
+// for. It is used by callers that identify users outside the OIDC session,
+// such as Slack user IDs; authenticated HTTP users are picked up from the
+// auth package automatically.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// User returns the identity of the user a request is acting for, or "" if
+// the request is anonymous.
+func User(ctx context.Context) string {
+	if user, err := auth.GetUserFromContext(ctx); err == nil && user.Identity() != "" {
+		return user.Identity()
+	}
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// contextHandler annotates records with the correlation data carried by the
+// context they were logged with.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id := RequestID(ctx); id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if user := User(ctx); user != "" {
+			r.AddAttrs(slog.String("user", user))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,160 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// decodeLines parses JSON log output into one map per record
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		errMsg string
+	}{
+		{name: "defaults", cfg: Config{}},
+		{name: "json debug", cfg: Config{Level: "debug", Format: "json"}},
+		{name: "upper case", cfg: Config{Level: "WARN", Format: "TEXT"}},
+		{name: "unknown level", cfg: Config{Level: "verbose"}, errMsg: "unknown log level"},
+		{name: "unknown format", cfg: Config{Format: "xml"}, errMsg: "unknown log format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg, &bytes.Buffer{})
+			if (err != nil) != (tt.errMsg != "") {
+				t.Fatalf("New() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("New() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLogger_AddsRequestIDAndUser(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Config{Level: "info", Format: "json"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = context.WithValue(ctx, auth.UserContextKey, &auth.UserInfo{Email: "alice@example.com"})
+	logger.With("component", "test").InfoContext(ctx, "hello")
+	logger.DebugContext(ctx, "filtered")
+	logger.Info("no context")
+
+	records := decodeLines(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (debug filtered out)", len(records))
+	}
+	if records[0]["request_id"] != "req-1" || records[0]["user"] != "alice@example.com" {
+		t.Errorf("record = %v, want request_id and user", records[0])
+	}
+	if records[0]["component"] != "test" {
+		t.Errorf("record = %v, want attributes from With to be kept", records[0])
+	}
+	if _, ok := records[1]["request_id"]; ok {
+		t.Errorf("record without context has request_id: %v", records[1])
+	}
+}
+
+func TestUser_FallsBackToContextUser(t *testing.T) {
+	ctx := WithUser(context.Background(), "U123")
+	if got := User(ctx); got != "U123" {
+		t.Errorf("User() = %q, want U123", got)
+	}
+	ctx = context.WithValue(ctx, auth.UserContextKey, &auth.UserInfo{Email: "bob@example.com"})
+	if got := User(ctx); got != "bob@example.com" {
+		t.Errorf("User() = %q, want authenticated user to take precedence", got)
+	}
+}
+
+func TestMiddleware_PropagatesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Config{Format: "json"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var seen string
+	handler := Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/outages", nil)
+	req.Header.Set(RequestIDHeader, "abc")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen != "abc" {
+		t.Errorf("handler saw request ID %q, want abc", seen)
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != "abc" {
+		t.Errorf("response %s = %q, want abc", RequestIDHeader, got)
+	}
+	records := decodeLines(t, &buf)
+	if len(records) != 1 || records[0]["request_id"] != "abc" || records[0]["status"] != float64(http.StatusTeapot) {
+		t.Errorf("access log = %v, want one record with request_id abc and status 418", records)
+	}
+
+	// Without an incoming header an ID is generated.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if seen == "" || rec.Header().Get(RequestIDHeader) != seen {
+		t.Errorf("generated request ID %q not echoed (header %q)", seen, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestUnaryServerInterceptor_PropagatesRequestID(t *testing.T) {
+	interceptor := UnaryServerInterceptor(Discard())
+	info := &grpc.UnaryServerInfo{FullMethod: "/outalator.v1.OutageService/GetOutage"}
+
+	var seen string
+	handler := func(ctx context.Context, req any) (any, error) {
+		seen = RequestID(ctx)
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "grpc-1"))
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if seen != "grpc-1" {
+		t.Errorf("handler saw request ID %q, want grpc-1", seen)
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if seen == "" || seen == "grpc-1" {
+		t.Errorf("expected a generated request ID, got %q", seen)
+	}
+}
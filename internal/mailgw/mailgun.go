@@ -0,0 +1,130 @@
+package mailgw
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/webhook"
+	"github.com/gorilla/mux"
+)
+
+// mailgunMaxAge is how old a Mailgun signature timestamp may be
+const mailgunMaxAge = 5 * time.Minute
+
+// MailgunReceiver accepts messages forwarded by a Mailgun route and queues
+// them for the gateway
+type MailgunReceiver struct {
+	source     string
+	signingKey string
+	queue      Enqueuer
+	logger     *slog.Logger
+}
+
+// NewMailgunReceiver creates a receiver for Mailgun route deliveries,
+// verified with the account's webhook signing key
+func NewMailgunReceiver(source, signingKey string, queue Enqueuer, logger *slog.Logger) *MailgunReceiver {
+	return &MailgunReceiver{source: source, signingKey: signingKey, queue: queue, logger: logger}
+}
+
+// RegisterHandlers registers POST /api/v1/webhooks/{source}/mailgun
+func (m *MailgunReceiver) RegisterHandlers(r *mux.Router) {
+	r.HandleFunc("/api/v1/webhooks/"+m.source+"/mailgun", m.HandleMailgun).Methods("POST")
+}
+
+// HandleMailgun handles a Mailgun route delivery. Routes that forward to a
+// URL ending in "mime" send the full message as body-mime; otherwise the
+// message is rebuilt from the parsed fields.
+func (m *MailgunReceiver) HandleMailgun(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(bodylimit.DefaultWebhookMaxBytes); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		if bodylimit.TooLarge(err) {
+			bodylimit.RespondTooLarge(w)
+			return
+		}
+		respond(w, http.StatusBadRequest, map[string]string{"error": "Invalid form body"})
+		return
+	}
+	if !verifyMailgunSignature(m.signingKey, r.FormValue("timestamp"), r.FormValue("token"), r.FormValue("signature"), time.Now()) {
+		respond(w, http.StatusUnauthorized, map[string]string{"error": "Invalid signature"})
+		return
+	}
+
+	payload := []byte(r.FormValue("body-mime"))
+	if len(payload) == 0 {
+		payload = buildMessage(r)
+	}
+
+	job := webhook.Job{Source: m.source, Payload: payload, ReceivedAt: time.Now(), RequestID: logging.RequestID(r.Context())}
+	if err := m.queue.Enqueue(job); err != nil {
+		if errors.Is(err, webhook.ErrQueueFull) || errors.Is(err, webhook.ErrQueueStopped) {
+			// Mailgun retries on 5xx, so ask it to come back later.
+			w.Header().Set("Retry-After", "30")
+			respond(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+			return
+		}
+		m.logger.ErrorContext(r.Context(), "failed to enqueue inbound mail", "source", m.source, "error", err)
+		respond(w, http.StatusInternalServerError, map[string]string{"error": "Failed to queue message"})
+		return
+	}
+	respond(w, http.StatusOK, map[string]string{"status": "queued"})
+}
+
+// verifyMailgunSignature checks a Mailgun webhook signature: the hex
+// HMAC-SHA256 of timestamp and token under the signing key, with a
+// timestamp no older than mailgunMaxAge
+func verifyMailgunSignature(key, timestamp, token, signature string, now time.Time) bool {
+	if key == "" || timestamp == "" || token == "" || signature == "" {
+		return false
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > mailgunMaxAge || age < -mailgunMaxAge {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// buildMessage rebuilds a plain text message from Mailgun's parsed fields
+func buildMessage(r *http.Request) []byte {
+	from := r.FormValue("from")
+	if from == "" {
+		from = r.FormValue("sender")
+	}
+	var b strings.Builder
+	header := func(name, value string) {
+		value = strings.Join(strings.Fields(value), " ")
+		if value != "" {
+			fmt.Fprintf(&b, "%s: %s\r\n", name, value)
+		}
+	}
+	header("From", from)
+	header("Subject", mime.QEncoding.Encode("utf-8", r.FormValue("subject")))
+	header("Message-Id", r.FormValue("Message-Id"))
+	header("Date", r.FormValue("Date"))
+	header("Content-Type", "text/plain; charset=utf-8")
+	b.WriteString("\r\n")
+	b.WriteString(r.FormValue("body-plain"))
+	return []byte(b.String())
+}
+
+func respond(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
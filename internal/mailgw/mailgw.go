@@ -0,0 +1,133 @@
+// Package mailgw turns inbound email into alerts, for monitoring systems
+// that can only notify by mail. Messages arrive through an SMTP listener, a
+// Mailgun route or as raw MIME posted to the source's webhook endpoint, and
+// all of them are queued on the webhook queue. Once dequeued, the gateway,
+// registered as a notification service, matches each message against
+// configurable regex rules and the first matching rule builds the alert.
+// Messages no rule matches are dropped.
+package mailgw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/webhook"
+	"github.com/conall/outalator/notification"
+)
+
+// DefaultSource is the notification source name alerts are recorded under
+// when Config.Source is empty
+const DefaultSource = "email"
+
+// Config holds mail gateway configuration
+type Config struct {
+	Source string // Alert source name, default "email"
+	Rules  []Rule // Tried in order; the first match builds the alert
+	// WebhookToken is the shared secret raw messages posted to the webhook
+	// endpoint send in notification.WebhookTokenHeader. Without it only
+	// SMTP and Mailgun deliveries are accepted.
+	WebhookToken string
+}
+
+// Enqueuer queues a raw message for processing. It is satisfied by
+// *webhook.Queue.
+type Enqueuer interface {
+	Enqueue(job webhook.Job) error
+}
+
+// Gateway is a notification service whose webhook payloads are raw email
+// messages
+type Gateway struct {
+	source       string
+	rules        []*compiledRule
+	webhookToken string
+}
+
+// New creates a gateway, compiling its rules
+func New(cfg Config) (*Gateway, error) {
+	if cfg.Source == "" {
+		cfg.Source = DefaultSource
+	}
+	if len(cfg.Rules) == 0 {
+		return nil, errors.New("mail gateway needs at least one rule")
+	}
+	g := &Gateway{source: cfg.Source, webhookToken: cfg.WebhookToken}
+	for i, r := range cfg.Rules {
+		compiled, err := compileRule(r)
+		if err != nil {
+			name := r.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("mail rule %s: %w", name, err)
+		}
+		g.rules = append(g.rules, compiled)
+	}
+	return g, nil
+}
+
+// Name implements notification.Service
+func (g *Gateway) Name() string {
+	return g.source
+}
+
+// FetchAlert implements notification.Service. Mail cannot be fetched on
+// demand, so importing by ID is not supported.
+func (g *Gateway) FetchAlert(context.Context, string) (*notification.Alert, error) {
+	return nil, fmt.Errorf("%s alerts can only be received, not fetched", g.source)
+}
+
+// CreateIncident implements notification.Service. Mail is inbound only, so
+// nothing can be paged through it.
+func (g *Gateway) CreateIncident(context.Context, notification.IncidentRequest) (*notification.Alert, error) {
+	return nil, fmt.Errorf("%s cannot page anyone: %w", g.source, domain.ErrInvalidInput)
+}
+
+// AcknowledgeAlert implements notification.Service. Mail cannot be
+// answered, so alerts must be acknowledged in the system that sent them.
+func (g *Gateway) AcknowledgeAlert(context.Context, string, string) error {
+	return fmt.Errorf("%s alerts cannot be acknowledged: %w", g.source, notification.ErrUnsupported)
+}
+
+// ResolveAlert implements notification.Service
+func (g *Gateway) ResolveAlert(context.Context, string, string) error {
+	return fmt.Errorf("%s alerts cannot be resolved: %w", g.source, notification.ErrUnsupported)
+}
+
+// FetchRecentAlerts implements notification.Service. There is nothing to
+// poll, so no alerts are returned.
+func (g *Gateway) FetchRecentAlerts(context.Context, time.Time) ([]*notification.Alert, error) {
+	return nil, nil
+}
+
+// WebhookHandler implements notification.Service
+func (g *Gateway) WebhookHandler() interface{} {
+	return nil
+}
+
+// VerifyWebhook implements notification.WebhookParser for raw messages
+// posted to the webhook endpoint. SMTP and Mailgun deliveries are queued
+// directly and never pass through it.
+func (g *Gateway) VerifyWebhook(header http.Header, _ []byte) bool {
+	return notification.VerifyWebhookToken(header, g.webhookToken)
+}
+
+// ParseWebhook implements notification.WebhookParser. The payload is a raw
+// RFC 5322 message; it yields one alert if a rule matches and none
+// otherwise.
+func (g *Gateway) ParseWebhook(payload []byte, receivedAt time.Time) ([]*notification.Alert, error) {
+	msg, err := parseMessage(payload)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range g.rules {
+		if alert, ok := rule.apply(msg, g.source, receivedAt); ok {
+			return []*notification.Alert{alert}, nil
+		}
+	}
+	return nil, nil
+}
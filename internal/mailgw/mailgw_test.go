@@ -0,0 +1,292 @@
+package mailgw
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/internal/webhook"
+	"github.com/conall/outalator/notification"
+	"github.com/gorilla/mux"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// recordingQueue records enqueued jobs
+type recordingQueue struct {
+	mu   sync.Mutex
+	jobs []webhook.Job
+	err  error
+}
+
+func (q *recordingQueue) Enqueue(job webhook.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return q.err
+	}
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func (q *recordingQueue) received() []webhook.Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]webhook.Job(nil), q.jobs...)
+}
+
+var nagiosRule = Rule{
+	Name:       "nagios",
+	From:       `^nagios@example\.com$`,
+	Subject:    `^\*\* (?P<type>PROBLEM|RECOVERY) Service Alert: (?P<host>\S+)/(?P<service>.+) is (?P<state>\w+) \*\*$`,
+	ExternalID: "${host}/${service}",
+	Title:      "${service} on ${host} is ${state}",
+	Severity:   "${state}",
+	Team:       "infra",
+	Resolve:    "RECOVERY",
+}
+
+const problemMail = "From: Nagios <nagios@example.com>\r\n" +
+	"To: alerts@outalator.example.com\r\n" +
+	"Subject: ** PROBLEM Service Alert: db1/Disk Space is CRITICAL **\r\n" +
+	"Message-Id: <1@nagios.example.com>\r\n" +
+	"Date: Mon, 07 Jan 2030 10:00:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/alternative; boundary=b1\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"DISK CRITICAL - free space: /var 2% =\r\n" +
+	"(inode=3D90%)\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/html\r\n" +
+	"\r\n" +
+	"<p>DISK CRITICAL</p>\r\n" +
+	"--b1--\r\n"
+
+const recoveryMail = "From: nagios@example.com\r\n" +
+	"Subject: ** RECOVERY Service Alert: db1/Disk Space is OK **\r\n" +
+	"Message-Id: <2@nagios.example.com>\r\n" +
+	"Date: Mon, 07 Jan 2030 11:30:00 +0000\r\n" +
+	"\r\n" +
+	"DISK OK\r\n"
+
+func TestParseWebhook(t *testing.T) {
+	g, err := New(Config{Rules: []Rule{nagiosRule, {Name: "catch-all", Subject: "(?i)urgent"}}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if g.Name() != DefaultSource {
+		t.Errorf("Name() = %q, want %q", g.Name(), DefaultSource)
+	}
+	receivedAt := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+
+	alerts, err := g.ParseWebhook([]byte(problemMail), receivedAt)
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("ParseWebhook(problem) = %v, %v; want one alert", alerts, err)
+	}
+	problem := alerts[0]
+	if problem.ExternalID != "db1/Disk Space" || problem.Source != "email" || problem.TeamName != "infra" ||
+		problem.Title != "Disk Space on db1 is CRITICAL" || problem.Severity != "CRITICAL" {
+		t.Errorf("problem alert = %+v", problem)
+	}
+	if problem.Description != "DISK CRITICAL - free space: /var 2% (inode=90%)" {
+		t.Errorf("Description = %q", problem.Description)
+	}
+	if !problem.TriggeredAt.Equal(time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)) || problem.ResolvedAt != nil {
+		t.Errorf("problem triggered %s, resolved %v", problem.TriggeredAt, problem.ResolvedAt)
+	}
+
+	// The recovery mail maps to the same alert and resolves it
+	alerts, err = g.ParseWebhook([]byte(recoveryMail), receivedAt)
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("ParseWebhook(recovery) = %v, %v; want one alert", alerts, err)
+	}
+	if alerts[0].ExternalID != problem.ExternalID || alerts[0].ResolvedAt == nil {
+		t.Errorf("recovery alert = %+v, want resolved %s", alerts[0], problem.ExternalID)
+	}
+
+	// Later rules catch what earlier ones miss, with subject and body
+	// defaults and the Message-ID as the external ID
+	alerts, err = g.ParseWebhook([]byte("From: cron@example.com\r\nSubject: URGENT: backup failed\r\nMessage-Id: <3@example.com>\r\nContent-Type: text/html\r\n\r\n<html><body><b>Backup</b> failed</body></html>"), receivedAt)
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("ParseWebhook(catch-all) = %v, %v; want one alert", alerts, err)
+	}
+	if a := alerts[0]; a.ExternalID != "3@example.com" || a.Title != "URGENT: backup failed" ||
+		!strings.Contains(a.Description, "Backup") || strings.Contains(a.Description, "<b>") || !a.TriggeredAt.Equal(receivedAt) {
+		t.Errorf("catch-all alert = %+v", a)
+	}
+
+	// Mail no rule matches is dropped
+	alerts, err = g.ParseWebhook([]byte("From: someone@example.com\r\nSubject: lunch?\r\n\r\nhi"), receivedAt)
+	if err != nil || len(alerts) != 0 {
+		t.Errorf("ParseWebhook(unmatched) = %v, %v; want no alerts", alerts, err)
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	header := http.Header{}
+	header.Set(notification.WebhookTokenHeader, "token")
+
+	closed, err := New(Config{Rules: []Rule{nagiosRule}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if closed.VerifyWebhook(header, nil) {
+		t.Error("raw message verified without a webhook token configured")
+	}
+
+	open, err := New(Config{Rules: []Rule{nagiosRule}, WebhookToken: "token"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !open.VerifyWebhook(header, nil) {
+		t.Error("raw message with the webhook token did not verify")
+	}
+	if open.VerifyWebhook(http.Header{}, nil) {
+		t.Error("raw message without the webhook token verified")
+	}
+}
+
+func TestNewRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+	}{
+		{"no rules", nil},
+		{"no patterns", []Rule{{Name: "empty", Title: "x"}}},
+		{"invalid regex", []Rule{{Name: "bad", Subject: "("}}},
+		{"invalid resolve", []Rule{{Name: "bad", Subject: "x", Resolve: "["}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(Config{Rules: tt.rules}); err == nil {
+				t.Error("New succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestSMTPServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := &recordingQueue{}
+	srv := NewSMTPServer("email", SMTPConfig{MaxMessageBytes: 2048}, queue, discardLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	}()
+
+	addr := ln.Addr().String()
+	if err := smtp.SendMail(addr, nil, "nagios@example.com", []string{"alerts@example.com"}, []byte(recoveryMail)); err != nil {
+		t.Fatalf("SendMail: %v", err)
+	}
+	jobs := queue.received()
+	if len(jobs) != 1 || jobs[0].Source != "email" {
+		t.Fatalf("queued jobs = %+v, want one email job", jobs)
+	}
+	if got := strings.ReplaceAll(string(jobs[0].Payload), "\r\n", "\n"); got != strings.ReplaceAll(recoveryMail, "\r\n", "\n") {
+		t.Errorf("payload = %q", got)
+	}
+
+	// Oversized messages are rejected
+	big := "Subject: big\r\n\r\n" + strings.Repeat("x", 4096) + "\r\n"
+	if err := smtp.SendMail(addr, nil, "a@example.com", []string{"b@example.com"}, []byte(big)); err == nil {
+		t.Error("SendMail of oversized message succeeded, want error")
+	}
+
+	// A full queue asks the sender to retry
+	queue.err = webhook.ErrQueueFull
+	err = smtp.SendMail(addr, nil, "a@example.com", []string{"b@example.com"}, []byte(recoveryMail))
+	if err == nil || !strings.Contains(err.Error(), "451") {
+		t.Errorf("SendMail with full queue = %v, want 451", err)
+	}
+	if len(queue.received()) != 1 {
+		t.Errorf("queued %d jobs, want 1", len(queue.received()))
+	}
+}
+
+func mailgunSignature(key, timestamp, token string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestMailgunReceiver(t *testing.T) {
+	queue := &recordingQueue{}
+	router := mux.NewRouter()
+	NewMailgunReceiver("email", "key", queue, discardLogger()).RegisterHandlers(router)
+
+	post := func(form url.Values) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/email/mailgun", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	signed := func(key string, age time.Duration) url.Values {
+		timestamp := strconv.FormatInt(time.Now().Add(-age).Unix(), 10)
+		return url.Values{
+			"timestamp":  {timestamp},
+			"token":      {"tok"},
+			"signature":  {mailgunSignature(key, timestamp, "tok")},
+			"sender":     {"nagios@example.com"},
+			"subject":    {"** PROBLEM Service Alert: db1/Disk Space is CRITICAL **"},
+			"Message-Id": {"<1@nagios.example.com>"},
+			"body-plain": {"DISK CRITICAL"},
+		}
+	}
+
+	if code := post(signed("wrong", 0)); code != http.StatusUnauthorized {
+		t.Errorf("wrong key = %d, want 401", code)
+	}
+	if code := post(signed("key", time.Hour)); code != http.StatusUnauthorized {
+		t.Errorf("stale timestamp = %d, want 401", code)
+	}
+	if code := post(signed("key", 0)); code != http.StatusOK {
+		t.Fatalf("signed delivery = %d, want 200", code)
+	}
+
+	jobs := queue.received()
+	if len(jobs) != 1 {
+		t.Fatalf("queued %d jobs, want 1", len(jobs))
+	}
+	// The rebuilt message parses into the same alert as the original mail
+	g, err := New(Config{Rules: []Rule{nagiosRule}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts, err := g.ParseWebhook(jobs[0].Payload, time.Now())
+	if err != nil || len(alerts) != 1 || alerts[0].ExternalID != "db1/Disk Space" || alerts[0].Description != "DISK CRITICAL" {
+		t.Errorf("ParseWebhook(mailgun) = %+v, %v", alerts, err)
+	}
+
+	queue.err = webhook.ErrQueueFull
+	if code := post(signed("key", 0)); code != http.StatusServiceUnavailable {
+		t.Errorf("full queue = %d, want 503", code)
+	}
+}
@@ -0,0 +1,145 @@
+package mailgw
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// message is the part of an email the rules look at
+type message struct {
+	From      string // Address only, without display name
+	Subject   string
+	MessageID string // Without angle brackets
+	Date      time.Time
+	Body      string // Plain text, or HTML with tags stripped
+}
+
+var wordDecoder = &mime.WordDecoder{}
+
+// parseMessage parses a raw RFC 5322 message
+func parseMessage(raw []byte) (*message, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid email message: %w", err)
+	}
+
+	msg := &message{
+		MessageID: strings.Trim(strings.TrimSpace(m.Header.Get("Message-Id")), "<>"),
+	}
+	msg.Subject, err = wordDecoder.DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		msg.Subject = m.Header.Get("Subject")
+	}
+	msg.Subject = strings.TrimSpace(msg.Subject)
+	if from, err := mail.ParseAddress(m.Header.Get("From")); err == nil {
+		msg.From = from.Address
+	} else {
+		msg.From = strings.TrimSpace(m.Header.Get("From"))
+	}
+	if date, err := m.Header.Date(); err == nil {
+		msg.Date = date
+	}
+
+	text, html, err := readBody(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Body)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" && html != "" {
+		text = stripHTML(html)
+	}
+	msg.Body = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	return msg, nil
+}
+
+// readBody returns the first text/plain and text/html parts of a body,
+// descending into multipart bodies
+func readBody(contentType, encoding string, body io.Reader) (text, html string, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Messages without a valid Content-Type are plain text
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return text, html, nil
+			}
+			if err != nil {
+				return "", "", fmt.Errorf("invalid multipart body: %w", err)
+			}
+			t, h, err := readBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", "", err
+			}
+			if text == "" {
+				text = t
+			}
+			if html == "" {
+				html = h
+			}
+		}
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read message body: %w", err)
+	}
+	if mediaType == "text/html" {
+		return "", string(data), nil
+	}
+	return string(data), "", nil
+}
+
+// newlineStripper drops line breaks from wrapped base64 content
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	for {
+		count, err := n.r.Read(p)
+		kept := 0
+		for _, b := range p[:count] {
+			if b != '\r' && b != '\n' {
+				p[kept] = b
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+var (
+	htmlTags   = regexp.MustCompile(`(?s)<(script|style)[^>]*>.*?</(script|style)>|<[^>]*>`)
+	blankLines = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// stripHTML reduces an HTML body to its text
+func stripHTML(html string) string {
+	text := htmlTags.ReplaceAllString(html, "\n")
+	text = strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'").Replace(text)
+	return blankLines.ReplaceAllString(text, "\n")
+}
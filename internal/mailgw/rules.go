@@ -0,0 +1,141 @@
+package mailgw
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// maxDescriptionLength caps the message body copied into an alert's
+// description
+const maxDescriptionLength = 4000
+
+// Rule turns matching messages into alerts. From, Subject and Body are
+// regular expressions; every one that is set must match, and at least one
+// must be set. Named capture groups from all three can be referenced in the
+// templates as ${name}, along with ${from}, ${subject} and ${message_id}.
+type Rule struct {
+	Name    string `yaml:"name"`
+	From    string `yaml:"from,omitempty"`
+	Subject string `yaml:"subject,omitempty"`
+	Body    string `yaml:"body,omitempty"`
+
+	// ExternalID identifies the alert at the source, so that repeat and
+	// recovery mails for the same problem update one alert instead of
+	// opening new outages. Defaults to the Message-ID, making every mail a
+	// new alert.
+	ExternalID  string `yaml:"external_id,omitempty"`
+	Title       string `yaml:"title,omitempty"`       // Defaults to the subject
+	Description string `yaml:"description,omitempty"` // Defaults to the body
+	Severity    string `yaml:"severity,omitempty"`    // Mapped through severity_mapping for the source
+	Team        string `yaml:"team,omitempty"`
+
+	// Resolve, matched against the subject, marks the alert resolved, e.g.
+	// "^RECOVERY" for Nagios recovery notifications
+	Resolve string `yaml:"resolve,omitempty"`
+}
+
+type compiledRule struct {
+	Rule
+	from, subject, body, resolve *regexp.Regexp
+}
+
+func compileRule(r Rule) (*compiledRule, error) {
+	if r.From == "" && r.Subject == "" && r.Body == "" {
+		return nil, errors.New("at least one of from, subject or body is required")
+	}
+	c := &compiledRule{Rule: r}
+	for _, f := range []struct {
+		name    string
+		pattern string
+		re      **regexp.Regexp
+	}{
+		{"from", r.From, &c.from},
+		{"subject", r.Subject, &c.subject},
+		{"body", r.Body, &c.body},
+		{"resolve", r.Resolve, &c.resolve},
+	} {
+		if f.pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(f.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern: %w", f.name, err)
+		}
+		*f.re = re
+	}
+	return c, nil
+}
+
+// apply builds an alert from msg if the rule matches it
+func (c *compiledRule) apply(msg *message, source string, receivedAt time.Time) (*notification.Alert, bool) {
+	vars := map[string]string{
+		"from":       msg.From,
+		"subject":    msg.Subject,
+		"message_id": msg.MessageID,
+	}
+	for _, m := range []struct {
+		re   *regexp.Regexp
+		text string
+	}{
+		{c.from, msg.From},
+		{c.subject, msg.Subject},
+		{c.body, msg.Body},
+	} {
+		if m.re == nil {
+			continue
+		}
+		match := m.re.FindStringSubmatch(m.text)
+		if match == nil {
+			return nil, false
+		}
+		for i, name := range m.re.SubexpNames() {
+			if name != "" {
+				vars[name] = match[i]
+			}
+		}
+	}
+
+	expand := func(tmpl, fallback string) string {
+		if tmpl == "" {
+			return fallback
+		}
+		return strings.TrimSpace(os.Expand(tmpl, func(name string) string { return vars[name] }))
+	}
+
+	triggeredAt := msg.Date
+	if triggeredAt.IsZero() {
+		triggeredAt = receivedAt
+	}
+	alert := &notification.Alert{
+		ExternalID:  expand(c.ExternalID, msg.MessageID),
+		Source:      source,
+		TeamName:    expand(c.Team, ""),
+		Title:       expand(c.Title, msg.Subject),
+		Description: truncate(expand(c.Description, msg.Body), maxDescriptionLength),
+		Severity:    expand(c.Severity, ""),
+		TriggeredAt: triggeredAt,
+	}
+	if alert.ExternalID == "" {
+		// No Message-ID: fall back to something stable for the same mail
+		alert.ExternalID = fmt.Sprintf("%s|%s|%d", msg.From, msg.Subject, triggeredAt.Unix())
+	}
+	if c.resolve != nil && c.resolve.MatchString(msg.Subject) {
+		alert.ResolvedAt = &triggeredAt
+	}
+	return alert, true
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,204 @@
+package mailgw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/internal/webhook"
+)
+
+const (
+	// DefaultMaxMessageBytes caps the size of a message accepted over SMTP
+	DefaultMaxMessageBytes = 1 << 20 // 1 MiB
+
+	// smtpCommandTimeout bounds how long a client may take to send each
+	// command or message
+	smtpCommandTimeout = 5 * time.Minute
+)
+
+// SMTPConfig holds SMTP listener configuration
+type SMTPConfig struct {
+	Addr            string // Listen address, e.g. ":2525"
+	Hostname        string // Announced in the greeting, default "outalator"
+	MaxMessageBytes int64  // Default 1 MiB
+}
+
+// SMTPServer accepts mail over SMTP and queues each message for the
+// gateway. It is a receive-only relay target for monitoring systems on a
+// trusted network: it does not authenticate clients or offer TLS, and it
+// accepts mail for any recipient.
+type SMTPServer struct {
+	source string
+	cfg    SMTPConfig
+	queue  Enqueuer
+	logger *slog.Logger
+}
+
+// NewSMTPServer creates an SMTP server that queues messages as deliveries
+// for source
+func NewSMTPServer(source string, cfg SMTPConfig, queue Enqueuer, logger *slog.Logger) *SMTPServer {
+	if cfg.Hostname == "" {
+		cfg.Hostname = "outalator"
+	}
+	if cfg.MaxMessageBytes <= 0 {
+		cfg.MaxMessageBytes = DefaultMaxMessageBytes
+	}
+	return &SMTPServer{source: source, cfg: cfg, queue: queue, logger: logger}
+}
+
+// Run listens on the configured address and serves until ctx is cancelled
+func (s *SMTPServer) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for smtp on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is cancelled, then closes ln
+// and waits for open sessions to finish
+func (s *SMTPServer) Serve(ctx context.Context, ln net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn runs a single SMTP session
+func (s *SMTPServer) serveConn(ctx context.Context, conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	tp := textproto.NewConn(conn)
+	reply := func(code int, msg string) {
+		_ = tp.PrintfLine("%d %s", code, msg)
+	}
+
+	var from string
+	recipients := 0
+	reset := func() { from, recipients = "", 0 }
+
+	_ = conn.SetDeadline(time.Now().Add(smtpCommandTimeout))
+	reply(220, s.cfg.Hostname+" ESMTP ready")
+	for ctx.Err() == nil {
+		_ = conn.SetDeadline(time.Now().Add(smtpCommandTimeout))
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			reset()
+			reply(250, s.cfg.Hostname)
+		case "EHLO":
+			reset()
+			_ = tp.PrintfLine("250-%s", s.cfg.Hostname)
+			_ = tp.PrintfLine("250-SIZE %d", s.cfg.MaxMessageBytes)
+			_ = tp.PrintfLine("250 8BITMIME")
+		case "MAIL":
+			addr, ok := cutPrefixFold(arg, "FROM:")
+			if !ok {
+				reply(501, "Syntax: MAIL FROM:<address>")
+				continue
+			}
+			reset()
+			from = strings.TrimSpace(addr)
+			reply(250, "OK")
+		case "RCPT":
+			if from == "" {
+				reply(503, "MAIL first")
+				continue
+			}
+			if _, ok := cutPrefixFold(arg, "TO:"); !ok {
+				reply(501, "Syntax: RCPT TO:<address>")
+				continue
+			}
+			recipients++
+			reply(250, "OK")
+		case "DATA":
+			if recipients == 0 {
+				reply(503, "RCPT first")
+				continue
+			}
+			reply(354, "End data with <CR><LF>.<CR><LF>")
+			code, msg := s.receive(ctx, tp)
+			if code == 0 {
+				return
+			}
+			reply(code, msg)
+			reset()
+		case "RSET":
+			reset()
+			reply(250, "OK")
+		case "NOOP":
+			reply(250, "OK")
+		case "VRFY":
+			reply(252, "Cannot verify user")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, "Command not implemented")
+		}
+	}
+}
+
+// receive reads a message after DATA and queues it, returning the reply to
+// send. A zero code means the connection failed.
+func (s *SMTPServer) receive(ctx context.Context, tp *textproto.Conn) (int, string) {
+	dot := tp.DotReader()
+	data, err := io.ReadAll(io.LimitReader(dot, s.cfg.MaxMessageBytes+1))
+	if err != nil {
+		return 0, ""
+	}
+	if int64(len(data)) > s.cfg.MaxMessageBytes {
+		if _, err := io.Copy(io.Discard, dot); err != nil {
+			return 0, ""
+		}
+		return 552, "Message too large"
+	}
+
+	job := webhook.Job{Source: s.source, Payload: data, ReceivedAt: time.Now()}
+	if err := s.queue.Enqueue(job); err != nil {
+		s.logger.WarnContext(ctx, "failed to queue inbound mail", "source", s.source, "error", err)
+		return 451, "Try again later"
+	}
+	return 250, "OK queued"
+}
+
+// cutPrefixFold is strings.CutPrefix, ignoring case
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// prompt is a built-in prompt template. Every prompt takes an outage_id and
+// is sent with the outage's details and timeline appended.
+type prompt struct {
+	name         string
+	description  string
+	instructions string
+}
+
+var prompts = []prompt{
+	{
+		name:        "summarize_outage",
+		description: "Summarize an outage for stakeholders who were not involved",
+		instructions: "Summarize the outage below for stakeholders who were not involved in the response. " +
+			"Cover what happened, who and what was affected, the current status and what is being done about it. " +
+			"Keep it to a few short paragraphs and only state what the record below supports.",
+	},
+	{
+		name:        "draft_postmortem",
+		description: "Draft a blameless postmortem for an outage",
+		instructions: "Draft a blameless postmortem for the outage below in markdown, with the sections " +
+			"Summary, Impact, Timeline, Root Cause, Resolution, What Went Well, What Went Wrong and Action Items. " +
+			"Build the Timeline section from the timeline below using UTC timestamps, and list the existing action items " +
+			"under Action Items. Write TBD wherever the record does not say rather than guessing.",
+	},
+	{
+		name:        "suggest_action_items",
+		description: "Suggest follow-up action items to prevent an outage from recurring",
+		instructions: "Suggest follow-up action items that would stop the outage below from recurring, " +
+			"detect it sooner or reduce its impact. For each give a one-line title, the evidence from the timeline " +
+			"that motivates it and, when the record names one, the team that should own it. " +
+			"Do not repeat the existing action items.",
+	},
+}
+
+// findPrompt returns the built-in prompt with the given name
+func findPrompt(name string) (prompt, bool) {
+	for _, p := range prompts {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return prompt{}, false
+}
+
+// handlePromptsList returns the available prompts
+func (s *Server) handlePromptsList() map[string]interface{} {
+	list := make([]map[string]interface{}, 0, len(prompts))
+	for _, p := range prompts {
+		list = append(list, map[string]interface{}{
+			"name":        p.name,
+			"description": p.description,
+			"arguments": []map[string]interface{}{
+				{
+					"name":        "outage_id",
+					"description": "UUID of the outage",
+					"required":    true,
+				},
+			},
+		})
+	}
+	return map[string]interface{}{"prompts": list}
+}
+
+// handlePromptsGet renders a prompt for an outage
+func (s *Server) handlePromptsGet(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var getParams struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &getParams); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", domain.ErrInvalidInput)
+	}
+
+	p, ok := findPrompt(getParams.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt %q: %w", getParams.Name, domain.ErrInvalidInput)
+	}
+	outageIDStr := getParams.Arguments["outage_id"]
+	if outageIDStr == "" {
+		return nil, fmt.Errorf("outage_id is required: %w", domain.ErrInvalidInput)
+	}
+	outageID, err := uuid.Parse(outageIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outage_id: %w", domain.ErrInvalidInput)
+	}
+
+	outage, err := s.service.GetOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.service.ListActionItems(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.service.GetOutageTimeline(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"description": fmt.Sprintf("%s: %s", p.description, outage.Title),
+		"messages": []map[string]interface{}{
+			{
+				"role": "user",
+				"content": map[string]interface{}{
+					"type": "text",
+					"text": p.instructions + "\n\n" + outageContext(outage, items, events),
+				},
+			},
+		},
+	}, nil
+}
+
+// outageContext renders an outage, its action items and its timeline as
+// plain text for a prompt. Notes marked as action items are listed after
+// the outage's action items.
+func outageContext(outage *domain.Outage, items []*domain.ActionItem, events []domain.TimelineEvent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Outage: %s (%s)\n", outage.Title, outage.ID)
+	fmt.Fprintf(&b, "Severity: %s\n", outage.Severity)
+	fmt.Fprintf(&b, "Status: %s\n", outage.Status)
+	fmt.Fprintf(&b, "Created: %s\n", outage.CreatedAt.UTC().Format(time.RFC3339))
+	if outage.ResolvedAt != nil {
+		fmt.Fprintf(&b, "Resolved: %s (after %s)\n", outage.ResolvedAt.UTC().Format(time.RFC3339),
+			outage.ResolvedAt.Sub(outage.CreatedAt).Round(time.Minute))
+	}
+	if len(outage.Tags) > 0 {
+		tags := make([]string, 0, len(outage.Tags))
+		for _, t := range outage.Tags {
+			tags = append(tags, t.Key+"="+t.Value)
+		}
+		fmt.Fprintf(&b, "Tags: %s\n", strings.Join(tags, ", "))
+	}
+	fmt.Fprintf(&b, "Alerts: %d\n", len(outage.Alerts))
+	fmt.Fprintf(&b, "\nDescription:\n%s\n", outage.Description)
+
+	b.WriteString("\nExisting action items:\n")
+	found := false
+	for _, item := range items {
+		line := fmt.Sprintf("- [%s] %s", item.Status, item.Description)
+		if item.Assignee != "" {
+			line += " (assigned to " + item.Assignee + ")"
+		}
+		if item.DueDate != nil {
+			line += " due " + item.DueDate.UTC().Format("2006-01-02")
+		}
+		b.WriteString(line + "\n")
+		found = true
+	}
+	for i := range outage.Notes {
+		if outage.Notes[i].IsActionItem() {
+			fmt.Fprintf(&b, "- %s\n", outage.Notes[i].Content)
+			found = true
+		}
+	}
+	if !found {
+		b.WriteString("(none)\n")
+	}
+
+	b.WriteString("\nTimeline (UTC):\n")
+	for _, e := range events {
+		line := e.Timestamp.UTC().Format(time.RFC3339) + "  " + e.Summary
+		if e.Actor != "" {
+			line += " (" + e.Actor + ")"
+		}
+		b.WriteString(line + "\n")
+		if content, ok := e.Details["content"].(string); ok && e.Type == domain.TimelineNoteAdded {
+			for _, l := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+				b.WriteString("    " + l + "\n")
+			}
+		}
+	}
+	return b.String()
+}
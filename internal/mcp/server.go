@@ -1,11 +1,16 @@
 package mcp
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/conall/outalator/domain"
 	"github.com/conall/outalator/service"
@@ -24,7 +29,20 @@ func NewServer(svc *service.Service) *Server {
 	}
 }
 
-// Request represents an MCP request
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// maxMessageSize bounds a single newline-delimited message on stdio
+const maxMessageSize = 10 << 20
+
+// Request represents an MCP request. A request without an id is a
+// notification and gets no response.
 type Request struct {
 	Method string          `json:"method"`
 	Params json.RawMessage `json:"params,omitempty"`
@@ -33,9 +51,10 @@ type Request struct {
 
 // Response represents an MCP response
 type Response struct {
-	Result interface{} `json:"result,omitempty"`
-	Error  *Error      `json:"error,omitempty"`
-	ID     interface{} `json:"id"`
+	JSONRPC string      `json:"jsonrpc"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+	ID      interface{} `json:"id"`
 }
 
 // Error represents an MCP error
@@ -46,18 +65,36 @@ type Error struct {
 
 // Handle processes an MCP request
 func (s *Server) Handle(ctx context.Context, req *Request) *Response {
-	resp := &Response{ID: req.ID}
+	resp := &Response{JSONRPC: "2.0", ID: req.ID}
 
 	switch req.Method {
 	case "initialize":
 		resp.Result = s.handleInitialize()
+	case "ping":
+		resp.Result = map[string]interface{}{}
 	case "tools/list":
 		resp.Result = s.handleToolsList()
 	case "tools/call":
 		result, err := s.handleToolsCall(ctx, req.Params)
 		if err != nil {
 			resp.Error = &Error{
-				Code:    -32603,
+				Code:    codeInternalError,
+				Message: err.Error(),
+			}
+		} else {
+			resp.Result = result
+		}
+	case "prompts/list":
+		resp.Result = s.handlePromptsList()
+	case "prompts/get":
+		result, err := s.handlePromptsGet(ctx, req.Params)
+		if err != nil {
+			code := codeInternalError
+			if errors.Is(err, domain.ErrInvalidInput) {
+				code = codeInvalidParams
+			}
+			resp.Error = &Error{
+				Code:    code,
 				Message: err.Error(),
 			}
 		} else {
@@ -65,7 +102,7 @@ func (s *Server) Handle(ctx context.Context, req *Request) *Response {
 		}
 	default:
 		resp.Error = &Error{
-			Code:    -32601,
+			Code:    codeMethodNotFound,
 			Message: fmt.Sprintf("method not found: %s", req.Method),
 		}
 	}
@@ -78,7 +115,8 @@ func (s *Server) handleInitialize() map[string]interface{} {
 	return map[string]interface{}{
 		"protocolVersion": "2024-11-05",
 		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
+			"tools":   map[string]interface{}{},
+			"prompts": map[string]interface{}{},
 		},
 		"serverInfo": map[string]interface{}{
 			"name":    "outalator",
@@ -105,6 +143,14 @@ func (s *Server) handleToolsList() map[string]interface{} {
 							"type":        "number",
 							"description": "Offset for pagination (default: 0)",
 						},
+						"team": map[string]interface{}{
+							"type":        "string",
+							"description": "Comma-separated owning teams to list outages of (optional; default: every outage)",
+						},
+						"include_associations": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Include each outage's alerts, notes and tags (default: false)",
+						},
 					},
 				},
 			},
@@ -122,6 +168,42 @@ func (s *Server) handleToolsList() map[string]interface{} {
 					"required": []string{"outage_id"},
 				},
 			},
+			{
+				"name":        "find_similar_outages",
+				"description": "Find past outages similar to an outage by title, description, tags and owning team, to look for earlier fixes",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"outage_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of the outage",
+						},
+						"limit": map[string]interface{}{
+							"type":        "number",
+							"description": "Maximum number of outages to return (default: 5, max: 50)",
+						},
+						"min_score": map[string]interface{}{
+							"type":        "number",
+							"description": "Lowest similarity score to include, from 0 to 1 (default: 0.3)",
+						},
+					},
+					"required": []string{"outage_id"},
+				},
+			},
+			{
+				"name":        "get_outage_timeline",
+				"description": "Get the chronological history of an outage: status changes, alerts, notes and tags",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"outage_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of the outage",
+						},
+					},
+					"required": []string{"outage_id"},
+				},
+			},
 			{
 				"name":        "create_outage",
 				"description": "Create a new outage entry",
@@ -140,6 +222,10 @@ func (s *Server) handleToolsList() map[string]interface{} {
 							"type":        "string",
 							"description": "Severity level: critical, high, medium, low",
 						},
+						"owning_team": map[string]interface{}{
+							"type":        "string",
+							"description": "Name of the team that owns the outage (optional)",
+						},
 					},
 					"required": []string{"title", "description", "severity"},
 				},
@@ -164,7 +250,11 @@ func (s *Server) handleToolsList() map[string]interface{} {
 						},
 						"format": map[string]interface{}{
 							"type":        "string",
-							"description": "Format of the note: plaintext or markdown (default: plaintext)",
+							"description": "Format of the note: plaintext, markdown or log for log excerpts and stack traces (default: plaintext)",
+						},
+						"parent_note_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of a note on the same outage to reply to (optional)",
 						},
 					},
 					"required": []string{"outage_id", "content", "author"},
@@ -190,16 +280,148 @@ func (s *Server) handleToolsList() map[string]interface{} {
 						},
 						"status": map[string]interface{}{
 							"type":        "string",
-							"description": "New status: open, investigating, resolved, closed (optional)",
+							"description": "New status: open, investigating, mitigated, resolved, closed (optional); must follow the outage state machine",
 						},
 						"severity": map[string]interface{}{
 							"type":        "string",
 							"description": "New severity: critical, high, medium, low (optional)",
 						},
+						"owning_team": map[string]interface{}{
+							"type":        "string",
+							"description": "New owning team (optional); an empty string clears the owner",
+						},
 					},
 					"required": []string{"outage_id"},
 				},
 			},
+			{
+				"name":        "resolve_outage",
+				"description": "Mark an outage as resolved, optionally recording a resolution note",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"outage_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of the outage",
+						},
+						"note": map[string]interface{}{
+							"type":        "string",
+							"description": "Resolution note, e.g. the fix applied (optional)",
+						},
+						"author": map[string]interface{}{
+							"type":        "string",
+							"description": "Author of the resolution note (required with note)",
+						},
+					},
+					"required": []string{"outage_id"},
+				},
+			},
+			{
+				"name":        "add_tag",
+				"description": "Tag an outage with a key-value pair, e.g. a Jira ticket or affected service",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"outage_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of the outage",
+						},
+						"key": map[string]interface{}{
+							"type":        "string",
+							"description": "Tag key, e.g. jira, service, region",
+						},
+						"value": map[string]interface{}{
+							"type":        "string",
+							"description": "Tag value, e.g. PROJ-123",
+						},
+					},
+					"required": []string{"outage_id", "key", "value"},
+				},
+			},
+			{
+				"name":        "search_outages_by_tag",
+				"description": "Find outages carrying a tag with the given key and value",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"key": map[string]interface{}{
+							"type":        "string",
+							"description": "Tag key",
+						},
+						"value": map[string]interface{}{
+							"type":        "string",
+							"description": "Tag value",
+						},
+					},
+					"required": []string{"key", "value"},
+				},
+			},
+			{
+				"name":        "list_alerts_by_outage",
+				"description": "List the alerts linked to an outage",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"outage_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of the outage",
+						},
+					},
+					"required": []string{"outage_id"},
+				},
+			},
+			{
+				"name":        "import_alert",
+				"description": "Import an alert from a notification service (pagerduty or opsgenie), linking it to an outage or creating a new one",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"source": map[string]interface{}{
+							"type":        "string",
+							"description": "Notification service the alert comes from, e.g. pagerduty or opsgenie",
+						},
+						"external_id": map[string]interface{}{
+							"type":        "string",
+							"description": "The alert's ID in the notification service",
+						},
+						"outage_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of the outage to link the alert to; a new outage is created when omitted",
+						},
+					},
+					"required": []string{"source", "external_id"},
+				},
+			},
+			{
+				"name":        "list_views",
+				"description": "List the saved views: named outage filters on status, minimum severity, tags and owning teams",
+				"inputSchema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{},
+				},
+			},
+			{
+				"name":        "execute_view",
+				"description": "List the outages currently matching a saved view, most recently created first",
+				"inputSchema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"view_id": map[string]interface{}{
+							"type":        "string",
+							"description": "UUID of the saved view",
+						},
+						"limit": map[string]interface{}{
+							"type":        "number",
+							"description": "Maximum number of outages to return (default: 50, max: 100)",
+						},
+						"offset": map[string]interface{}{
+							"type":        "number",
+							"description": "Offset for pagination (default: 0)",
+						},
+					},
+					"required": []string{"view_id"},
+				},
+			},
 		},
 	}
 }
@@ -220,12 +442,30 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (i
 		return s.toolListOutages(ctx, callParams.Arguments)
 	case "get_outage":
 		return s.toolGetOutage(ctx, callParams.Arguments)
+	case "get_outage_timeline":
+		return s.toolGetOutageTimeline(ctx, callParams.Arguments)
+	case "find_similar_outages":
+		return s.toolFindSimilarOutages(ctx, callParams.Arguments)
 	case "create_outage":
 		return s.toolCreateOutage(ctx, callParams.Arguments)
 	case "add_note":
 		return s.toolAddNote(ctx, callParams.Arguments)
 	case "update_outage":
 		return s.toolUpdateOutage(ctx, callParams.Arguments)
+	case "resolve_outage":
+		return s.toolResolveOutage(ctx, callParams.Arguments)
+	case "add_tag":
+		return s.toolAddTag(ctx, callParams.Arguments)
+	case "search_outages_by_tag":
+		return s.toolSearchOutagesByTag(ctx, callParams.Arguments)
+	case "list_alerts_by_outage":
+		return s.toolListAlertsByOutage(ctx, callParams.Arguments)
+	case "import_alert":
+		return s.toolImportAlert(ctx, callParams.Arguments)
+	case "list_views":
+		return s.toolListViews(ctx)
+	case "execute_view":
+		return s.toolExecuteView(ctx, callParams.Arguments)
 	default:
 		return nil, fmt.Errorf("unknown tool: %s", callParams.Name)
 	}
@@ -243,10 +483,21 @@ func (s *Server) toolListOutages(ctx context.Context, args map[string]interface{
 		offset = int(o)
 	}
 
-	outages, err := s.service.ListOutages(ctx, limit, offset)
+	var outages []*domain.Outage
+	var err error
+	if teams := splitTeams(args["team"]); len(teams) > 0 {
+		outages, err = s.service.ListTeamOutages(ctx, teams, limit, offset)
+	} else {
+		outages, err = s.service.ListOutages(ctx, limit, offset)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if include, _ := args["include_associations"].(bool); include {
+		if err := s.service.LoadOutageAssociations(ctx, outages, domain.AllOutageAssociations); err != nil {
+			return nil, err
+		}
+	}
 
 	return map[string]interface{}{
 		"content": []map[string]interface{}{
@@ -259,6 +510,18 @@ func (s *Server) toolListOutages(ctx context.Context, args map[string]interface{
 	}, nil
 }
 
+// splitTeams parses a comma-separated team list argument
+func splitTeams(arg interface{}) []string {
+	value, _ := arg.(string)
+	var teams []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			teams = append(teams, name)
+		}
+	}
+	return teams
+}
+
 func (s *Server) toolGetOutage(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	outageIDStr, ok := args["outage_id"].(string)
 	if !ok {
@@ -286,6 +549,73 @@ func (s *Server) toolGetOutage(ctx context.Context, args map[string]interface{})
 	}, nil
 }
 
+func (s *Server) toolGetOutageTimeline(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	outageIDStr, ok := args["outage_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("outage_id is required")
+	}
+
+	outageID, err := uuid.Parse(outageIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outage_id: %w", err)
+	}
+
+	events, err := s.service.GetOutageTimeline(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(events))
+	for _, e := range events {
+		lines = append(lines, fmt.Sprintf("%s  %s", e.Timestamp.Format(time.RFC3339), e.Summary))
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": strings.Join(lines, "\n"),
+			},
+		},
+		"events": events,
+	}, nil
+}
+
+func (s *Server) toolFindSimilarOutages(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	outageIDStr, ok := args["outage_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("outage_id is required")
+	}
+
+	outageID, err := uuid.Parse(outageIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outage_id: %w", err)
+	}
+
+	limit, _ := args["limit"].(float64)
+	minScore, _ := args["min_score"].(float64)
+	similar, err := s.service.FindSimilarOutages(ctx, outageID, int(limit), minScore)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{fmt.Sprintf("Found %d similar outages", len(similar))}
+	for _, m := range similar {
+		lines = append(lines, fmt.Sprintf("%.2f  %s (%s, %s): %s",
+			m.Score, m.Outage.Title, m.Outage.ID, m.Outage.Status, strings.Join(m.Reasons, ", ")))
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": strings.Join(lines, "\n"),
+			},
+		},
+		"similar": similar,
+	}, nil
+}
+
 func (s *Server) toolCreateOutage(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	title, ok := args["title"].(string)
 	if !ok {
@@ -307,6 +637,9 @@ func (s *Server) toolCreateOutage(ctx context.Context, args map[string]interface
 		Description: description,
 		Severity:    severity,
 	}
+	if team, ok := args["owning_team"].(string); ok {
+		req.OwningTeam = team
+	}
 
 	outage, err := s.service.CreateOutage(ctx, req)
 	if err != nil {
@@ -355,6 +688,13 @@ func (s *Server) toolAddNote(ctx context.Context, args map[string]interface{}) (
 		Format:  format,
 		Author:  author,
 	}
+	if p, ok := args["parent_note_id"].(string); ok && p != "" {
+		parentNoteID, err := uuid.Parse(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent_note_id: %w", err)
+		}
+		req.ParentNoteID = &parentNoteID
+	}
 
 	note, err := s.service.AddNote(ctx, outageID, req)
 	if err != nil {
@@ -397,6 +737,9 @@ func (s *Server) toolUpdateOutage(ctx context.Context, args map[string]interface
 	if severity, ok := args["severity"].(string); ok {
 		req.Severity = &severity
 	}
+	if team, ok := args["owning_team"].(string); ok {
+		req.OwningTeam = &team
+	}
 
 	outage, err := s.service.UpdateOutage(ctx, outageID, req)
 	if err != nil {
@@ -414,30 +757,329 @@ func (s *Server) toolUpdateOutage(ctx context.Context, args map[string]interface
 	}, nil
 }
 
-// ServeStdio serves the MCP protocol over stdin/stdout
+func (s *Server) toolResolveOutage(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	outageIDStr, ok := args["outage_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("outage_id is required")
+	}
+
+	outageID, err := uuid.Parse(outageIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outage_id: %w", err)
+	}
+
+	noteContent, _ := args["note"].(string)
+	author, _ := args["author"].(string)
+	if noteContent != "" && author == "" {
+		return nil, fmt.Errorf("author is required with note")
+	}
+
+	status := "resolved"
+	outage, err := s.service.UpdateOutage(ctx, outageID, domain.UpdateOutageRequest{Status: &status})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Resolved outage: %s", outage.Title),
+			},
+		},
+		"outage": outage,
+	}
+
+	if noteContent != "" {
+		note, err := s.service.AddNote(ctx, outageID, domain.AddNoteRequest{
+			Content: noteContent,
+			Format:  "plaintext",
+			Author:  author,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("outage resolved but the note was not added: %w", err)
+		}
+		result["note"] = note
+	}
+
+	return result, nil
+}
+
+func (s *Server) toolAddTag(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	outageIDStr, ok := args["outage_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("outage_id is required")
+	}
+
+	outageID, err := uuid.Parse(outageIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outage_id: %w", err)
+	}
+
+	key, ok := args["key"].(string)
+	if !ok {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	value, ok := args["value"].(string)
+	if !ok {
+		return nil, fmt.Errorf("value is required")
+	}
+
+	tag, err := s.service.AddTag(ctx, outageID, key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Tagged outage %s with %s=%s", outageID, tag.Key, tag.Value),
+			},
+		},
+		"tag": tag,
+	}, nil
+}
+
+func (s *Server) toolSearchOutagesByTag(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	key, ok := args["key"].(string)
+	if !ok {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	value, ok := args["value"].(string)
+	if !ok {
+		return nil, fmt.Errorf("value is required")
+	}
+
+	outages, err := s.service.FindOutagesByTag(ctx, key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Found %d outages tagged %s=%s", len(outages), key, value),
+			},
+		},
+		"outages": outages,
+	}, nil
+}
+
+func (s *Server) toolListAlertsByOutage(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	outageIDStr, ok := args["outage_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("outage_id is required")
+	}
+
+	outageID, err := uuid.Parse(outageIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outage_id: %w", err)
+	}
+
+	alerts, err := s.service.ListAlertsByOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{fmt.Sprintf("Found %d alerts", len(alerts))}
+	for _, a := range alerts {
+		lines = append(lines, fmt.Sprintf("%s  %s %s: %s", a.TriggeredAt.Format(time.RFC3339), a.Source, a.ExternalID, a.Title))
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": strings.Join(lines, "\n"),
+			},
+		},
+		"alerts": alerts,
+	}, nil
+}
+
+func (s *Server) toolImportAlert(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	source, ok := args["source"].(string)
+	if !ok {
+		return nil, fmt.Errorf("source is required")
+	}
+
+	externalID, ok := args["external_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("external_id is required")
+	}
+
+	var outageID *uuid.UUID
+	if outageIDStr, ok := args["outage_id"].(string); ok && outageIDStr != "" {
+		id, err := uuid.Parse(outageIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outage_id: %w", err)
+		}
+		outageID = &id
+	}
+
+	alert, err := s.service.ImportAlert(ctx, source, externalID, outageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Imported %s alert %s into outage %s", alert.Source, alert.ExternalID, alert.OutageID),
+			},
+		},
+		"alert": alert,
+	}, nil
+}
+
+func (s *Server) toolListViews(ctx context.Context) (interface{}, error) {
+	views, err := s.service.ListSavedViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{fmt.Sprintf("Found %d saved views", len(views))}
+	for _, v := range views {
+		lines = append(lines, fmt.Sprintf("%s  %s (owner: %s)", v.ID, v.Name, v.Owner))
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": strings.Join(lines, "\n"),
+			},
+		},
+		"views": views,
+	}, nil
+}
+
+func (s *Server) toolExecuteView(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	viewIDStr, ok := args["view_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("view_id is required")
+	}
+
+	viewID, err := uuid.Parse(viewIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid view_id: %w", err)
+	}
+
+	limit, offset := 50, 0
+	if l, ok := args["limit"].(float64); ok {
+		limit = int(l)
+	}
+	if o, ok := args["offset"].(float64); ok {
+		offset = int(o)
+	}
+
+	view, outages, err := s.service.ExecuteSavedView(ctx, viewID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Found %d outages matching view %q", len(outages), view.Name),
+			},
+		},
+		"view":    view,
+		"outages": outages,
+	}, nil
+}
+
+// ServeStdio serves the MCP protocol over stdin/stdout. Messages are
+// newline-delimited JSON-RPC requests, notifications or batches of them.
 func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
-	decoder := json.NewDecoder(stdin)
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
 	encoder := json.NewEncoder(stdout)
 
-	for {
+	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		var req Request
-		if err := decoder.Decode(&req); err != nil {
-			if err == io.EOF {
-				return nil
-			}
-			log.Printf("Error decoding request: %v", err)
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
 			continue
 		}
-
-		resp := s.Handle(ctx, &req)
-		if err := encoder.Encode(resp); err != nil {
+		reply := s.HandleMessage(ctx, line)
+		if reply == nil {
+			continue
+		}
+		if err := encoder.Encode(reply); err != nil {
 			log.Printf("Error encoding response: %v", err)
 		}
 	}
+	return scanner.Err()
+}
+
+// HandleMessage processes a raw JSON-RPC message, which may be a single
+// request or notification or a batch of them. It returns the response, a
+// slice of responses for a batch, or nil when nothing should be sent back
+// because the message only held notifications.
+func (s *Server) HandleMessage(ctx context.Context, msg json.RawMessage) interface{} {
+	if !json.Valid(msg) {
+		return errorResponse(nil, codeParseError, "parse error")
+	}
+	if msg[0] != '[' {
+		if resp := s.handleOne(ctx, msg); resp != nil {
+			return resp
+		}
+		return nil
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err != nil || len(batch) == 0 {
+		return errorResponse(nil, codeInvalidRequest, "invalid request: empty batch")
+	}
+	responses := make([]*Response, 0, len(batch))
+	for _, m := range batch {
+		if resp := s.handleOne(ctx, m); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+// handleOne processes a single request or notification, returning nil for
+// notifications
+func (s *Server) handleOne(ctx context.Context, msg json.RawMessage) *Response {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return errorResponse(nil, codeInvalidRequest, "invalid request: not an object")
+	}
+	var req Request
+	if err := json.Unmarshal(msg, &req); err != nil || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "invalid request: missing method")
+	}
+
+	if _, hasID := fields["id"]; !hasID {
+		switch req.Method {
+		case "notifications/initialized", "notifications/cancelled":
+			// Nothing to do: requests are handled synchronously
+		default:
+			s.Handle(ctx, &req)
+		}
+		return nil
+	}
+	return s.Handle(ctx, &req)
+}
+
+// errorResponse builds a response carrying only an error
+func errorResponse(id interface{}, code int, message string) *Response {
+	return &Response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
 }
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+)
+
+func newTestServer() *Server {
+	return NewServer(service.New(testutil.NewMemStorage(), logging.Discard()))
+}
+
+func TestHandleMessage(t *testing.T) {
+	s := newTestServer()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		msg  string
+		// want lists the expected replies as "id:code", with code 0 for a
+		// result. nil means no reply; one entry without batch means a
+		// single response.
+		want  []string
+		batch bool
+	}{
+		{
+			name: "single request",
+			msg:  `{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			want: []string{"1:0"},
+		},
+		{
+			name: "unknown method",
+			msg:  `{"jsonrpc":"2.0","id":"a","method":"nope"}`,
+			want: []string{"a:-32601"},
+		},
+		{
+			name: "notification",
+			msg:  `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		},
+		{
+			name:  "mixed batch",
+			msg:   `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"nope"}]`,
+			want:  []string{"1:0", "2:-32601"},
+			batch: true,
+		},
+		{
+			name: "all-notification batch",
+			msg:  `[{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","method":"notifications/cancelled"}]`,
+		},
+		{
+			name: "empty batch",
+			msg:  `[]`,
+			want: []string{"<nil>:-32600"},
+		},
+		{
+			name:  "batch with a non-object",
+			msg:   `[1]`,
+			want:  []string{"<nil>:-32600"},
+			batch: true,
+		},
+		{
+			name: "missing method",
+			msg:  `{"jsonrpc":"2.0","id":3}`,
+			want: []string{"3:-32600"},
+		},
+		{
+			name: "invalid JSON",
+			msg:  `{"jsonrpc":"2.0","id":1,"method":`,
+			want: []string{"<nil>:-32700"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply := s.HandleMessage(ctx, json.RawMessage(tt.msg))
+			if tt.want == nil {
+				if reply != nil {
+					t.Fatalf("reply = %#v, want nil", reply)
+				}
+				return
+			}
+
+			var responses []*Response
+			switch r := reply.(type) {
+			case *Response:
+				if tt.batch {
+					t.Fatalf("reply is a single response, want a batch")
+				}
+				responses = []*Response{r}
+			case []*Response:
+				if !tt.batch {
+					t.Fatalf("reply is a batch, want a single response")
+				}
+				responses = r
+			default:
+				t.Fatalf("reply = %#v, want responses", reply)
+			}
+
+			var got []string
+			for _, resp := range responses {
+				code := 0
+				if resp.Error != nil {
+					code = resp.Error.Code
+				}
+				got = append(got, fmt.Sprintf("%v:%d", resp.ID, code))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("replies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeStdio(t *testing.T) {
+	s := newTestServer()
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n" +
+		"\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" +
+		`not json` + "\n" +
+		`[{"jsonrpc":"2.0","id":2,"method":"ping"}]` + "\n")
+	var out bytes.Buffer
+	if err := s.ServeStdio(context.Background(), in, &out); err != nil {
+		t.Fatalf("ServeStdio: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		`{"jsonrpc":"2.0","result":{},"id":1}`,
+		`{"jsonrpc":"2.0","error":{"code":-32700,"message":"parse error"},"id":null}`,
+		`[{"jsonrpc":"2.0","result":{},"id":2}]`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
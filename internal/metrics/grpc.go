@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor records call counts and latency for unary gRPC calls
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		grpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		grpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records call counts and latency for streaming gRPC
+// calls, timed until the stream ends
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		grpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		grpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
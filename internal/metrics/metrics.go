@@ -0,0 +1,110 @@
+// Package metrics exposes Prometheus metrics for the Outalator server itself:
+// HTTP and gRPC request counts and latency, storage query durations,
+// notification provider API calls, open outages by severity and when alerts
+// were last ingested from each source.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "outalator"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled, by method, route template and status code.",
+	}, []string{"method", "route", "code"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency, by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "gRPC calls handled, by full method name and status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC call latency, by full method name.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "query_duration_seconds",
+		Help:      "Storage operation latency, by operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "query_errors_total",
+		Help:      "Storage operations that returned an error other than not-found, by operation.",
+	}, []string{"operation"})
+
+	providerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "api_requests_total",
+		Help:      "Outbound notification provider API requests, by provider.",
+	}, []string{"provider"})
+
+	providerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "api_errors_total",
+		Help:      "Outbound notification provider API requests that failed or returned a 4xx/5xx status, by provider.",
+	}, []string{"provider"})
+
+	providerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "api_request_duration_seconds",
+		Help:      "Outbound notification provider API latency, by provider.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider"})
+
+	providerRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "api_retries_total",
+		Help:      "Outbound notification provider API requests retried, by provider and reason (rate_limited, server_error or network).",
+	}, []string{"provider", "reason"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		grpcRequests, grpcDuration,
+		dbQueryDuration, dbQueryErrors,
+		providerRequests, providerErrors, providerDuration, providerRetries,
+	)
+}
+
+// Handler returns the HTTP handler that serves the metrics in the
+// Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(Middleware)
+	r.HandleFunc("/api/v1/outages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+
+	before := promtest.ToFloat64(httpRequests.WithLabelValues("GET", "/api/v1/outages/{id}", "404"))
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/outages/"+uuid.NewString(), nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	after := promtest.ToFloat64(httpRequests.WithLabelValues("GET", "/api/v1/outages/{id}", "404"))
+	if after-before != 2 {
+		t.Errorf("requests counted = %v, want 2", after-before)
+	}
+}
+
+func TestInstrumentStorage_CountsErrorsButNotNotFound(t *testing.T) {
+	store := InstrumentStorage(testutil.NewMemStorage())
+	ctx := context.Background()
+
+	before := promtest.ToFloat64(dbQueryErrors.WithLabelValues("get_outage"))
+	if _, err := store.GetOutage(ctx, uuid.New()); err == nil {
+		t.Fatal("expected not-found error")
+	}
+	if got := promtest.ToFloat64(dbQueryErrors.WithLabelValues("get_outage")); got != before {
+		t.Errorf("not-found counted as query error: %v -> %v", before, got)
+	}
+	if n := promtest.CollectAndCount(dbQueryDuration, namespace+"_storage_query_duration_seconds"); n == 0 {
+		t.Error("expected query duration to be observed")
+	}
+}
+
+func TestTransport_CountsProviderErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Transport("testprovider", nil)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if got := promtest.ToFloat64(providerRequests.WithLabelValues("testprovider")); got != 1 {
+		t.Errorf("provider requests = %v, want 1", got)
+	}
+	if got := promtest.ToFloat64(providerErrors.WithLabelValues("testprovider")); got != 1 {
+		t.Errorf("provider errors = %v, want 1", got)
+	}
+}
+
+func TestOpenOutagesCollector(t *testing.T) {
+	store := testutil.NewMemStorage()
+	ctx := context.Background()
+	now := time.Now()
+	for _, o := range []struct{ status, severity string }{
+		{"open", "critical"},
+		{"investigating", "critical"},
+		{"open", "low"},
+		{"resolved", "critical"},
+	} {
+		outage := &domain.Outage{ID: uuid.New(), Title: "t", Status: o.status, Severity: o.severity, CreatedAt: now, UpdatedAt: now}
+		if err := store.CreateOutage(ctx, outage); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `
+# HELP outalator_open_outages Outages that are not yet resolved or closed, by severity.
+# TYPE outalator_open_outages gauge
+outalator_open_outages{severity="critical"} 2
+outalator_open_outages{severity="low"} 1
+`
+	if err := promtest.CollectAndCompare(&openOutagesCollector{store: store}, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+type fakeSourceHealth []domain.SourceHealth
+
+func (f fakeSourceHealth) ListSourceHealth(context.Context, time.Time) ([]domain.SourceHealth, error) {
+	return f, nil
+}
+
+func TestSourceHealthCollector(t *testing.T) {
+	success := time.Unix(1700000000, 0)
+	failure := time.Unix(1700003600, 0)
+	lister := fakeSourceHealth{
+		{IngestionRecord: domain.IngestionRecord{Source: "opsgenie"}, Stale: true},
+		{IngestionRecord: domain.IngestionRecord{Source: "pagerduty", LastSuccessAt: &success, LastFailureAt: &failure}},
+	}
+
+	expected := `
+# HELP outalator_source_last_failure_timestamp_seconds Unix time of the last failed webhook delivery or sync pass, by alert source.
+# TYPE outalator_source_last_failure_timestamp_seconds gauge
+outalator_source_last_failure_timestamp_seconds{source="pagerduty"} 1.7000036e+09
+# HELP outalator_source_last_success_timestamp_seconds Unix time of the last successful webhook delivery or sync pass, by alert source.
+# TYPE outalator_source_last_success_timestamp_seconds gauge
+outalator_source_last_success_timestamp_seconds{source="pagerduty"} 1.7e+09
+# HELP outalator_source_stale 1 if the alert source has gone without a successful ingestion for longer than its staleness threshold.
+# TYPE outalator_source_stale gauge
+outalator_source_stale{source="opsgenie"} 1
+outalator_source_stale{source="pagerduty"} 0
+`
+	if err := promtest.CollectAndCompare(&sourceHealthCollector{lister: lister}, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/storage"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	outageScrapeTimeout  = 5 * time.Second
+	outageScrapePageSize = 500
+)
+
+var openOutagesDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "open_outages"),
+	"Outages that are not yet resolved or closed, by severity.",
+	[]string{"severity"}, nil,
+)
+
+// openOutagesCollector counts unresolved outages from storage at scrape time
+// so the gauge stays correct across restarts and multiple replicas
+type openOutagesCollector struct {
+	store storage.OutageStorage
+}
+
+// RegisterOpenOutages registers the open outage gauge, computed from store
+// on each scrape
+func RegisterOpenOutages(store storage.OutageStorage) error {
+	return registry.Register(&openOutagesCollector{store: store})
+}
+
+// Describe implements prometheus.Collector
+func (c *openOutagesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- openOutagesDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *openOutagesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), outageScrapeTimeout)
+	defer cancel()
+
+	counts := make(map[string]int)
+	for offset := 0; ; offset += outageScrapePageSize {
+		outages, err := c.store.ListOutages(ctx, outageScrapePageSize, offset, false)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to count open outages", "error", err)
+			ch <- prometheus.NewInvalidMetric(openOutagesDesc, err)
+			return
+		}
+		for _, o := range outages {
+			if o.Status != "resolved" && o.Status != "closed" {
+				counts[o.Severity]++
+			}
+		}
+		if len(outages) < outageScrapePageSize {
+			break
+		}
+	}
+
+	for severity, n := range counts {
+		ch <- prometheus.MustNewConstMetric(openOutagesDesc, prometheus.GaugeValue, float64(n), severity)
+	}
+}
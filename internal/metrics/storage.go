@@ -0,0 +1,604 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage"
+	"github.com/google/uuid"
+)
+
+// instrumentedStorage times every call to the wrapped storage backend
+type instrumentedStorage struct {
+	next storage.Storage
+}
+
+// InstrumentStorage wraps s so that each storage operation is recorded in
+// the query duration histogram and, on failure, the query error counter
+func InstrumentStorage(s storage.Storage) storage.Storage {
+	return &instrumentedStorage{next: s}
+}
+
+// observe records the outcome of a storage operation that started at start
+func observe(operation string, start time.Time, err error) {
+	dbQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		dbQueryErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// Outage operations
+
+func (s *instrumentedStorage) CreateOutage(ctx context.Context, outage *domain.Outage) (err error) {
+	defer func(start time.Time) { observe("create_outage", start, err) }(time.Now())
+	return s.next.CreateOutage(ctx, outage)
+}
+
+func (s *instrumentedStorage) GetOutage(ctx context.Context, id uuid.UUID) (_ *domain.Outage, err error) {
+	defer func(start time.Time) { observe("get_outage", start, err) }(time.Now())
+	return s.next.GetOutage(ctx, id)
+}
+
+func (s *instrumentedStorage) GetOutageWith(ctx context.Context, id uuid.UUID, associations domain.OutageAssociations) (_ *domain.Outage, err error) {
+	defer func(start time.Time) { observe("get_outage_with", start, err) }(time.Now())
+	return s.next.GetOutageWith(ctx, id, associations)
+}
+
+func (s *instrumentedStorage) LoadOutageAssociations(ctx context.Context, outages []*domain.Outage, associations domain.OutageAssociations) (err error) {
+	defer func(start time.Time) { observe("load_outage_associations", start, err) }(time.Now())
+	return s.next.LoadOutageAssociations(ctx, outages, associations)
+}
+
+func (s *instrumentedStorage) ListOutages(ctx context.Context, limit, offset int, includeDeleted bool) (_ []*domain.Outage, err error) {
+	defer func(start time.Time) { observe("list_outages", start, err) }(time.Now())
+	return s.next.ListOutages(ctx, limit, offset, includeDeleted)
+}
+
+func (s *instrumentedStorage) ListOutagesByTeams(ctx context.Context, teams []string, limit, offset int, includeDeleted bool) (_ []*domain.Outage, err error) {
+	defer func(start time.Time) { observe("list_outages_by_teams", start, err) }(time.Now())
+	return s.next.ListOutagesByTeams(ctx, teams, limit, offset, includeDeleted)
+}
+
+func (s *instrumentedStorage) ListChildOutages(ctx context.Context, parentID uuid.UUID) (_ []*domain.Outage, err error) {
+	defer func(start time.Time) { observe("list_child_outages", start, err) }(time.Now())
+	return s.next.ListChildOutages(ctx, parentID)
+}
+
+func (s *instrumentedStorage) ListPublicOutages(ctx context.Context) (_ []*domain.Outage, err error) {
+	defer func(start time.Time) { observe("list_public_outages", start, err) }(time.Now())
+	return s.next.ListPublicOutages(ctx)
+}
+
+func (s *instrumentedStorage) UpdateOutage(ctx context.Context, outage *domain.Outage) (err error) {
+	defer func(start time.Time) { observe("update_outage", start, err) }(time.Now())
+	return s.next.UpdateOutage(ctx, outage)
+}
+
+func (s *instrumentedStorage) SetOutageParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, updatedAt time.Time) (err error) {
+	defer func(start time.Time) { observe("set_outage_parent", start, err) }(time.Now())
+	return s.next.SetOutageParent(ctx, id, parentID, updatedAt)
+}
+
+func (s *instrumentedStorage) DeleteOutage(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("delete_outage", start, err) }(time.Now())
+	return s.next.DeleteOutage(ctx, id)
+}
+
+func (s *instrumentedStorage) TrashOutage(ctx context.Context, id uuid.UUID, at time.Time) (err error) {
+	defer func(start time.Time) { observe("trash_outage", start, err) }(time.Now())
+	return s.next.TrashOutage(ctx, id, at)
+}
+
+func (s *instrumentedStorage) RestoreOutage(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("restore_outage", start, err) }(time.Now())
+	return s.next.RestoreOutage(ctx, id)
+}
+
+func (s *instrumentedStorage) PurgeOutages(ctx context.Context, deletedBefore time.Time) (_ int, err error) {
+	defer func(start time.Time) { observe("purge_outages", start, err) }(time.Now())
+	return s.next.PurgeOutages(ctx, deletedBefore)
+}
+
+// Alert operations
+
+func (s *instrumentedStorage) CreateAlert(ctx context.Context, alert *domain.Alert) (err error) {
+	defer func(start time.Time) { observe("create_alert", start, err) }(time.Now())
+	return s.next.CreateAlert(ctx, alert)
+}
+
+func (s *instrumentedStorage) UpsertAlert(ctx context.Context, alert *domain.Alert) (_ *domain.Alert, _ bool, err error) {
+	defer func(start time.Time) { observe("upsert_alert", start, err) }(time.Now())
+	return s.next.UpsertAlert(ctx, alert)
+}
+
+func (s *instrumentedStorage) CreateOutageWithAlert(ctx context.Context, outage *domain.Outage, alert *domain.Alert) (_ *domain.Alert, _ bool, err error) {
+	defer func(start time.Time) { observe("create_outage_with_alert", start, err) }(time.Now())
+	return s.next.CreateOutageWithAlert(ctx, outage, alert)
+}
+
+func (s *instrumentedStorage) GetAlert(ctx context.Context, id uuid.UUID) (_ *domain.Alert, err error) {
+	defer func(start time.Time) { observe("get_alert", start, err) }(time.Now())
+	return s.next.GetAlert(ctx, id)
+}
+
+func (s *instrumentedStorage) GetAlertByExternalID(ctx context.Context, externalID, source string) (_ *domain.Alert, err error) {
+	defer func(start time.Time) { observe("get_alert_by_external_id", start, err) }(time.Now())
+	return s.next.GetAlertByExternalID(ctx, externalID, source)
+}
+
+func (s *instrumentedStorage) ListAlertsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.Alert, err error) {
+	defer func(start time.Time) { observe("list_alerts_by_outage", start, err) }(time.Now())
+	return s.next.ListAlertsByOutage(ctx, outageID)
+}
+
+func (s *instrumentedStorage) ListOpenAlerts(ctx context.Context, triggeredBefore time.Time) (_ []*domain.Alert, err error) {
+	defer func(start time.Time) { observe("list_open_alerts", start, err) }(time.Now())
+	return s.next.ListOpenAlerts(ctx, triggeredBefore)
+}
+
+func (s *instrumentedStorage) ListAlertsTriggeredBetween(ctx context.Context, since, until time.Time) (_ []*domain.Alert, err error) {
+	defer func(start time.Time) { observe("list_alerts_triggered_between", start, err) }(time.Now())
+	return s.next.ListAlertsTriggeredBetween(ctx, since, until)
+}
+
+func (s *instrumentedStorage) ListAlerts(ctx context.Context, query domain.AlertQuery) (_ []*domain.Alert, err error) {
+	defer func(start time.Time) { observe("list_alerts", start, err) }(time.Now())
+	return s.next.ListAlerts(ctx, query)
+}
+
+func (s *instrumentedStorage) UpdateAlert(ctx context.Context, alert *domain.Alert) (err error) {
+	defer func(start time.Time) { observe("update_alert", start, err) }(time.Now())
+	return s.next.UpdateAlert(ctx, alert)
+}
+
+// Alert event operations
+
+func (s *instrumentedStorage) CreateAlertEvent(ctx context.Context, event *domain.AlertEvent) (err error) {
+	defer func(start time.Time) { observe("create_alert_event", start, err) }(time.Now())
+	return s.next.CreateAlertEvent(ctx, event)
+}
+
+func (s *instrumentedStorage) ListAlertEventsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.AlertEvent, err error) {
+	defer func(start time.Time) { observe("list_alert_events_by_outage", start, err) }(time.Now())
+	return s.next.ListAlertEventsByOutage(ctx, outageID)
+}
+
+// Note operations
+
+func (s *instrumentedStorage) CreateNote(ctx context.Context, note *domain.Note) (err error) {
+	defer func(start time.Time) { observe("create_note", start, err) }(time.Now())
+	return s.next.CreateNote(ctx, note)
+}
+
+func (s *instrumentedStorage) GetNote(ctx context.Context, id uuid.UUID) (_ *domain.Note, err error) {
+	defer func(start time.Time) { observe("get_note", start, err) }(time.Now())
+	return s.next.GetNote(ctx, id)
+}
+
+func (s *instrumentedStorage) ListNotesByOutage(ctx context.Context, outageID uuid.UUID, includeDeleted bool) (_ []*domain.Note, err error) {
+	defer func(start time.Time) { observe("list_notes_by_outage", start, err) }(time.Now())
+	return s.next.ListNotesByOutage(ctx, outageID, includeDeleted)
+}
+
+func (s *instrumentedStorage) UpdateNote(ctx context.Context, note *domain.Note) (err error) {
+	defer func(start time.Time) { observe("update_note", start, err) }(time.Now())
+	return s.next.UpdateNote(ctx, note)
+}
+
+func (s *instrumentedStorage) DeleteNote(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("delete_note", start, err) }(time.Now())
+	return s.next.DeleteNote(ctx, id)
+}
+
+func (s *instrumentedStorage) TrashNote(ctx context.Context, id uuid.UUID, at time.Time) (err error) {
+	defer func(start time.Time) { observe("trash_note", start, err) }(time.Now())
+	return s.next.TrashNote(ctx, id, at)
+}
+
+func (s *instrumentedStorage) RestoreNote(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("restore_note", start, err) }(time.Now())
+	return s.next.RestoreNote(ctx, id)
+}
+
+func (s *instrumentedStorage) PurgeNotes(ctx context.Context, deletedBefore time.Time) (_ int, err error) {
+	defer func(start time.Time) { observe("purge_notes", start, err) }(time.Now())
+	return s.next.PurgeNotes(ctx, deletedBefore)
+}
+
+// Note revision operations
+
+func (s *instrumentedStorage) CreateNoteRevision(ctx context.Context, revision *domain.NoteRevision) (err error) {
+	defer func(start time.Time) { observe("create_note_revision", start, err) }(time.Now())
+	return s.next.CreateNoteRevision(ctx, revision)
+}
+
+func (s *instrumentedStorage) ListNoteRevisions(ctx context.Context, noteID uuid.UUID) (_ []*domain.NoteRevision, err error) {
+	defer func(start time.Time) { observe("list_note_revisions", start, err) }(time.Now())
+	return s.next.ListNoteRevisions(ctx, noteID)
+}
+
+// Responder assignment operations
+
+func (s *instrumentedStorage) CreateResponderAssignment(ctx context.Context, assignment *domain.ResponderAssignment) (err error) {
+	defer func(start time.Time) { observe("create_responder_assignment", start, err) }(time.Now())
+	return s.next.CreateResponderAssignment(ctx, assignment)
+}
+
+func (s *instrumentedStorage) EndResponderAssignment(ctx context.Context, id uuid.UUID, by string, at time.Time) (err error) {
+	defer func(start time.Time) { observe("end_responder_assignment", start, err) }(time.Now())
+	return s.next.EndResponderAssignment(ctx, id, by, at)
+}
+
+func (s *instrumentedStorage) ListResponderAssignments(ctx context.Context, outageID uuid.UUID) (_ []*domain.ResponderAssignment, err error) {
+	defer func(start time.Time) { observe("list_responder_assignments", start, err) }(time.Now())
+	return s.next.ListResponderAssignments(ctx, outageID)
+}
+
+func (s *instrumentedStorage) ListResponderAssignmentsBetween(ctx context.Context, since, until time.Time) (_ []*domain.ResponderAssignment, err error) {
+	defer func(start time.Time) { observe("list_responder_assignments_between", start, err) }(time.Now())
+	return s.next.ListResponderAssignmentsBetween(ctx, since, until)
+}
+
+// Tag operations
+
+func (s *instrumentedStorage) CreateTag(ctx context.Context, tag *domain.Tag) (err error) {
+	defer func(start time.Time) { observe("create_tag", start, err) }(time.Now())
+	return s.next.CreateTag(ctx, tag)
+}
+
+func (s *instrumentedStorage) GetTag(ctx context.Context, id uuid.UUID) (_ *domain.Tag, err error) {
+	defer func(start time.Time) { observe("get_tag", start, err) }(time.Now())
+	return s.next.GetTag(ctx, id)
+}
+
+func (s *instrumentedStorage) UpdateTag(ctx context.Context, tag *domain.Tag) (err error) {
+	defer func(start time.Time) { observe("update_tag", start, err) }(time.Now())
+	return s.next.UpdateTag(ctx, tag)
+}
+
+func (s *instrumentedStorage) ListTagsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.Tag, err error) {
+	defer func(start time.Time) { observe("list_tags_by_outage", start, err) }(time.Now())
+	return s.next.ListTagsByOutage(ctx, outageID)
+}
+
+func (s *instrumentedStorage) ListTagsByKey(ctx context.Context, key string) (_ []*domain.Tag, err error) {
+	defer func(start time.Time) { observe("list_tags_by_key", start, err) }(time.Now())
+	return s.next.ListTagsByKey(ctx, key)
+}
+
+func (s *instrumentedStorage) DeleteTag(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("delete_tag", start, err) }(time.Now())
+	return s.next.DeleteTag(ctx, id)
+}
+
+func (s *instrumentedStorage) FindOutagesByTag(ctx context.Context, key, value string) (_ []*domain.Outage, err error) {
+	defer func(start time.Time) { observe("find_outages_by_tag", start, err) }(time.Now())
+	return s.next.FindOutagesByTag(ctx, key, value)
+}
+
+func (s *instrumentedStorage) FindOutagesByTagQuery(ctx context.Context, query domain.TagQuery) (_ []*domain.Outage, err error) {
+	defer func(start time.Time) { observe("find_outages_by_tag_query", start, err) }(time.Now())
+	return s.next.FindOutagesByTagQuery(ctx, query)
+}
+
+func (s *instrumentedStorage) ListTagKeys(ctx context.Context) (_ []domain.TagUsage, err error) {
+	defer func(start time.Time) { observe("list_tag_keys", start, err) }(time.Now())
+	return s.next.ListTagKeys(ctx)
+}
+
+func (s *instrumentedStorage) ListTagValues(ctx context.Context, key string) (_ []domain.TagUsage, err error) {
+	defer func(start time.Time) { observe("list_tag_values", start, err) }(time.Now())
+	return s.next.ListTagValues(ctx, key)
+}
+
+// Status change operations
+
+func (s *instrumentedStorage) CreateStatusChange(ctx context.Context, change *domain.StatusChange) (err error) {
+	defer func(start time.Time) { observe("create_status_change", start, err) }(time.Now())
+	return s.next.CreateStatusChange(ctx, change)
+}
+
+func (s *instrumentedStorage) ListStatusChangesByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.StatusChange, err error) {
+	defer func(start time.Time) { observe("list_status_changes_by_outage", start, err) }(time.Now())
+	return s.next.ListStatusChangesByOutage(ctx, outageID)
+}
+
+// Preference operations
+
+func (s *instrumentedStorage) GetUserPreferences(ctx context.Context, subject string) (_ *domain.UserPreferences, err error) {
+	defer func(start time.Time) { observe("get_user_preferences", start, err) }(time.Now())
+	return s.next.GetUserPreferences(ctx, subject)
+}
+
+func (s *instrumentedStorage) UpsertUserPreferences(ctx context.Context, prefs *domain.UserPreferences) (err error) {
+	defer func(start time.Time) { observe("upsert_user_preferences", start, err) }(time.Now())
+	return s.next.UpsertUserPreferences(ctx, prefs)
+}
+
+// Review operations
+
+func (s *instrumentedStorage) GetOutageReview(ctx context.Context, outageID uuid.UUID) (_ *domain.OutageReview, err error) {
+	defer func(start time.Time) { observe("get_outage_review", start, err) }(time.Now())
+	return s.next.GetOutageReview(ctx, outageID)
+}
+
+func (s *instrumentedStorage) UpsertOutageReview(ctx context.Context, review *domain.OutageReview) (err error) {
+	defer func(start time.Time) { observe("upsert_outage_review", start, err) }(time.Now())
+	return s.next.UpsertOutageReview(ctx, review)
+}
+
+func (s *instrumentedStorage) ListOutageReviews(ctx context.Context, status string) (_ []*domain.OutageReview, err error) {
+	defer func(start time.Time) { observe("list_outage_reviews", start, err) }(time.Now())
+	return s.next.ListOutageReviews(ctx, status)
+}
+
+// Sync cursor operations
+
+func (s *instrumentedStorage) GetSyncCursor(ctx context.Context, source string) (_ *domain.SyncCursor, err error) {
+	defer func(start time.Time) { observe("get_sync_cursor", start, err) }(time.Now())
+	return s.next.GetSyncCursor(ctx, source)
+}
+
+func (s *instrumentedStorage) UpsertSyncCursor(ctx context.Context, cursor *domain.SyncCursor) (err error) {
+	defer func(start time.Time) { observe("upsert_sync_cursor", start, err) }(time.Now())
+	return s.next.UpsertSyncCursor(ctx, cursor)
+}
+
+// Export watermark operations
+
+func (s *instrumentedStorage) GetExportWatermark(ctx context.Context, table string) (_ *domain.ExportWatermark, err error) {
+	defer func(start time.Time) { observe("get_export_watermark", start, err) }(time.Now())
+	return s.next.GetExportWatermark(ctx, table)
+}
+
+func (s *instrumentedStorage) UpsertExportWatermark(ctx context.Context, watermark *domain.ExportWatermark) (err error) {
+	defer func(start time.Time) { observe("upsert_export_watermark", start, err) }(time.Now())
+	return s.next.UpsertExportWatermark(ctx, watermark)
+}
+
+// Retention run operations
+
+func (s *instrumentedStorage) CreateRetentionRun(ctx context.Context, run *domain.RetentionRun) (err error) {
+	defer func(start time.Time) { observe("create_retention_run", start, err) }(time.Now())
+	return s.next.CreateRetentionRun(ctx, run)
+}
+
+func (s *instrumentedStorage) UpdateRetentionRun(ctx context.Context, run *domain.RetentionRun) (err error) {
+	defer func(start time.Time) { observe("update_retention_run", start, err) }(time.Now())
+	return s.next.UpdateRetentionRun(ctx, run)
+}
+
+func (s *instrumentedStorage) GetRetentionRun(ctx context.Context, id uuid.UUID) (_ *domain.RetentionRun, err error) {
+	defer func(start time.Time) { observe("get_retention_run", start, err) }(time.Now())
+	return s.next.GetRetentionRun(ctx, id)
+}
+
+func (s *instrumentedStorage) ListRetentionRuns(ctx context.Context) (_ []*domain.RetentionRun, err error) {
+	defer func(start time.Time) { observe("list_retention_runs", start, err) }(time.Now())
+	return s.next.ListRetentionRuns(ctx)
+}
+
+// Source ingestion operations
+
+func (s *instrumentedStorage) RecordIngestionSuccess(ctx context.Context, source string, at time.Time) (err error) {
+	defer func(start time.Time) { observe("record_ingestion_success", start, err) }(time.Now())
+	return s.next.RecordIngestionSuccess(ctx, source, at)
+}
+
+func (s *instrumentedStorage) RecordIngestionFailure(ctx context.Context, source string, at time.Time, message string) (err error) {
+	defer func(start time.Time) { observe("record_ingestion_failure", start, err) }(time.Now())
+	return s.next.RecordIngestionFailure(ctx, source, at, message)
+}
+
+func (s *instrumentedStorage) ListIngestionRecords(ctx context.Context) (_ []*domain.IngestionRecord, err error) {
+	defer func(start time.Time) { observe("list_ingestion_records", start, err) }(time.Now())
+	return s.next.ListIngestionRecords(ctx)
+}
+
+// Config resource operations
+
+func (s *instrumentedStorage) ListConfigResources(ctx context.Context, kind string) (_ []*domain.ConfigResource, err error) {
+	defer func(start time.Time) { observe("list_config_resources", start, err) }(time.Now())
+	return s.next.ListConfigResources(ctx, kind)
+}
+
+func (s *instrumentedStorage) GetConfigResource(ctx context.Context, kind, name string) (_ *domain.ConfigResource, err error) {
+	defer func(start time.Time) { observe("get_config_resource", start, err) }(time.Now())
+	return s.next.GetConfigResource(ctx, kind, name)
+}
+
+func (s *instrumentedStorage) UpsertConfigResource(ctx context.Context, resource *domain.ConfigResource) (err error) {
+	defer func(start time.Time) { observe("upsert_config_resource", start, err) }(time.Now())
+	return s.next.UpsertConfigResource(ctx, resource)
+}
+
+func (s *instrumentedStorage) DeleteConfigResource(ctx context.Context, kind, name string) (err error) {
+	defer func(start time.Time) { observe("delete_config_resource", start, err) }(time.Now())
+	return s.next.DeleteConfigResource(ctx, kind, name)
+}
+
+// Attachment operations
+
+func (s *instrumentedStorage) CreateAttachment(ctx context.Context, attachment *domain.Attachment) (err error) {
+	defer func(start time.Time) { observe("create_attachment", start, err) }(time.Now())
+	return s.next.CreateAttachment(ctx, attachment)
+}
+
+func (s *instrumentedStorage) GetAttachment(ctx context.Context, id uuid.UUID) (_ *domain.Attachment, err error) {
+	defer func(start time.Time) { observe("get_attachment", start, err) }(time.Now())
+	return s.next.GetAttachment(ctx, id)
+}
+
+func (s *instrumentedStorage) ListAttachmentsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.Attachment, err error) {
+	defer func(start time.Time) { observe("list_attachments_by_outage", start, err) }(time.Now())
+	return s.next.ListAttachmentsByOutage(ctx, outageID)
+}
+
+func (s *instrumentedStorage) DeleteAttachment(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("delete_attachment", start, err) }(time.Now())
+	return s.next.DeleteAttachment(ctx, id)
+}
+
+// Processed event operations
+
+func (s *instrumentedStorage) CreateProcessedEvent(ctx context.Context, event *domain.ProcessedEvent) (err error) {
+	defer func(start time.Time) { observe("create_processed_event", start, err) }(time.Now())
+	return s.next.CreateProcessedEvent(ctx, event)
+}
+
+func (s *instrumentedStorage) PurgeProcessedEvents(ctx context.Context, source string, processedBefore time.Time) (_ int, err error) {
+	defer func(start time.Time) { observe("purge_processed_events", start, err) }(time.Now())
+	return s.next.PurgeProcessedEvents(ctx, source, processedBefore)
+}
+
+func (s *instrumentedStorage) CreateImportRun(ctx context.Context, run *domain.ImportRun) (err error) {
+	defer func(start time.Time) { observe("create_import_run", start, err) }(time.Now())
+	return s.next.CreateImportRun(ctx, run)
+}
+
+func (s *instrumentedStorage) UpdateImportRun(ctx context.Context, run *domain.ImportRun) (err error) {
+	defer func(start time.Time) { observe("update_import_run", start, err) }(time.Now())
+	return s.next.UpdateImportRun(ctx, run)
+}
+
+func (s *instrumentedStorage) GetImportRun(ctx context.Context, id uuid.UUID) (_ *domain.ImportRun, err error) {
+	defer func(start time.Time) { observe("get_import_run", start, err) }(time.Now())
+	return s.next.GetImportRun(ctx, id)
+}
+
+func (s *instrumentedStorage) ListImportRuns(ctx context.Context, provider string) (_ []*domain.ImportRun, err error) {
+	defer func(start time.Time) { observe("list_import_runs", start, err) }(time.Now())
+	return s.next.ListImportRuns(ctx, provider)
+}
+
+func (s *instrumentedStorage) CreateSavedView(ctx context.Context, view *domain.SavedView) (err error) {
+	defer func(start time.Time) { observe("create_saved_view", start, err) }(time.Now())
+	return s.next.CreateSavedView(ctx, view)
+}
+
+func (s *instrumentedStorage) GetSavedView(ctx context.Context, id uuid.UUID) (_ *domain.SavedView, err error) {
+	defer func(start time.Time) { observe("get_saved_view", start, err) }(time.Now())
+	return s.next.GetSavedView(ctx, id)
+}
+
+func (s *instrumentedStorage) ListSavedViews(ctx context.Context) (_ []*domain.SavedView, err error) {
+	defer func(start time.Time) { observe("list_saved_views", start, err) }(time.Now())
+	return s.next.ListSavedViews(ctx)
+}
+
+func (s *instrumentedStorage) UpdateSavedView(ctx context.Context, view *domain.SavedView) (err error) {
+	defer func(start time.Time) { observe("update_saved_view", start, err) }(time.Now())
+	return s.next.UpdateSavedView(ctx, view)
+}
+
+func (s *instrumentedStorage) DeleteSavedView(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("delete_saved_view", start, err) }(time.Now())
+	return s.next.DeleteSavedView(ctx, id)
+}
+
+func (s *instrumentedStorage) CreateService(ctx context.Context, svc *domain.Service) (err error) {
+	defer func(start time.Time) { observe("create_service", start, err) }(time.Now())
+	return s.next.CreateService(ctx, svc)
+}
+
+func (s *instrumentedStorage) GetService(ctx context.Context, name string) (_ *domain.Service, err error) {
+	defer func(start time.Time) { observe("get_service", start, err) }(time.Now())
+	return s.next.GetService(ctx, name)
+}
+
+func (s *instrumentedStorage) ListServices(ctx context.Context) (_ []*domain.Service, err error) {
+	defer func(start time.Time) { observe("list_services", start, err) }(time.Now())
+	return s.next.ListServices(ctx)
+}
+
+func (s *instrumentedStorage) UpdateService(ctx context.Context, svc *domain.Service) (err error) {
+	defer func(start time.Time) { observe("update_service", start, err) }(time.Now())
+	return s.next.UpdateService(ctx, svc)
+}
+
+func (s *instrumentedStorage) DeleteService(ctx context.Context, name string) (err error) {
+	defer func(start time.Time) { observe("delete_service", start, err) }(time.Now())
+	return s.next.DeleteService(ctx, name)
+}
+
+func (s *instrumentedStorage) CreateActionItem(ctx context.Context, item *domain.ActionItem) (err error) {
+	defer func(start time.Time) { observe("create_action_item", start, err) }(time.Now())
+	return s.next.CreateActionItem(ctx, item)
+}
+
+func (s *instrumentedStorage) GetActionItem(ctx context.Context, id uuid.UUID) (_ *domain.ActionItem, err error) {
+	defer func(start time.Time) { observe("get_action_item", start, err) }(time.Now())
+	return s.next.GetActionItem(ctx, id)
+}
+
+func (s *instrumentedStorage) ListActionItemsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.ActionItem, err error) {
+	defer func(start time.Time) { observe("list_action_items_by_outage", start, err) }(time.Now())
+	return s.next.ListActionItemsByOutage(ctx, outageID)
+}
+
+func (s *instrumentedStorage) ListOverdueActionItems(ctx context.Context, before time.Time) (_ []*domain.ActionItem, err error) {
+	defer func(start time.Time) { observe("list_overdue_action_items", start, err) }(time.Now())
+	return s.next.ListOverdueActionItems(ctx, before)
+}
+
+func (s *instrumentedStorage) UpdateActionItem(ctx context.Context, item *domain.ActionItem) (err error) {
+	defer func(start time.Time) { observe("update_action_item", start, err) }(time.Now())
+	return s.next.UpdateActionItem(ctx, item)
+}
+
+func (s *instrumentedStorage) DeleteActionItem(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("delete_action_item", start, err) }(time.Now())
+	return s.next.DeleteActionItem(ctx, id)
+}
+
+func (s *instrumentedStorage) SaveWatcher(ctx context.Context, watcher *domain.Watcher) (err error) {
+	defer func(start time.Time) { observe("save_watcher", start, err) }(time.Now())
+	return s.next.SaveWatcher(ctx, watcher)
+}
+
+func (s *instrumentedStorage) RemoveWatcher(ctx context.Context, outageID uuid.UUID, email string) (err error) {
+	defer func(start time.Time) { observe("remove_watcher", start, err) }(time.Now())
+	return s.next.RemoveWatcher(ctx, outageID, email)
+}
+
+func (s *instrumentedStorage) ListWatchers(ctx context.Context, outageID uuid.UUID) (_ []*domain.Watcher, err error) {
+	defer func(start time.Time) { observe("list_watchers", start, err) }(time.Now())
+	return s.next.ListWatchers(ctx, outageID)
+}
+
+func (s *instrumentedStorage) CreateOutageRelation(ctx context.Context, relation *domain.OutageRelation) (err error) {
+	defer func(start time.Time) { observe("create_outage_relation", start, err) }(time.Now())
+	return s.next.CreateOutageRelation(ctx, relation)
+}
+
+func (s *instrumentedStorage) GetOutageRelation(ctx context.Context, id uuid.UUID) (_ *domain.OutageRelation, err error) {
+	defer func(start time.Time) { observe("get_outage_relation", start, err) }(time.Now())
+	return s.next.GetOutageRelation(ctx, id)
+}
+
+func (s *instrumentedStorage) ListOutageRelations(ctx context.Context, outageID uuid.UUID) (_ []*domain.OutageRelation, err error) {
+	defer func(start time.Time) { observe("list_outage_relations", start, err) }(time.Now())
+	return s.next.ListOutageRelations(ctx, outageID)
+}
+
+func (s *instrumentedStorage) DeleteOutageRelation(ctx context.Context, id uuid.UUID) (err error) {
+	defer func(start time.Time) { observe("delete_outage_relation", start, err) }(time.Now())
+	return s.next.DeleteOutageRelation(ctx, id)
+}
+
+func (s *instrumentedStorage) Ping(ctx context.Context) (err error) {
+	defer func(start time.Time) { observe("ping", start, err) }(time.Now())
+	return s.next.Ping(ctx)
+}
+
+func (s *instrumentedStorage) CheckSchema(ctx context.Context) (err error) {
+	defer func(start time.Time) { observe("check_schema", start, err) }(time.Now())
+	return s.next.CheckSchema(ctx)
+}
+
+// Close closes the wrapped storage
+func (s *instrumentedStorage) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+// providerTransport instruments outbound requests to a notification provider
+type providerTransport struct {
+	provider string
+	base     http.RoundTripper
+}
+
+// Transport wraps base so that requests made through it are counted and
+// timed under the given provider name. A nil base uses http.DefaultTransport.
+func Transport(provider string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &providerTransport{provider: provider, base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *providerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	providerRequests.WithLabelValues(t.provider).Inc()
+	providerDuration.WithLabelValues(t.provider).Observe(time.Since(start).Seconds())
+	if err != nil || resp.StatusCode >= 400 {
+		providerErrors.WithLabelValues(t.provider).Inc()
+	}
+	return resp, err
+}
+
+// CountRetry counts a retried request to the given provider, for the
+// reason given by the retrying client
+func CountRetry(provider, reason string) {
+	providerRetries.WithLabelValues(provider, reason).Inc()
+}
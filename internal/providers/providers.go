@@ -0,0 +1,87 @@
+// Package providers builds the notification services enabled in the
+// configuration, so the server and the command-line tools register the
+// same providers the same way.
+package providers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/conall/outalator/config"
+	"github.com/conall/outalator/internal/httpclient"
+	"github.com/conall/outalator/notification"
+	"github.com/conall/outalator/notification/mock"
+	"github.com/conall/outalator/notification/opsgenie"
+	"github.com/conall/outalator/notification/pagerduty"
+)
+
+// Options tune the API clients of the services New builds
+type Options struct {
+	// Transport returns the HTTP transport for a provider's API calls.
+	// Optional; nil uses http.DefaultTransport.
+	Transport func(provider string) http.RoundTripper
+	// Retry builds a provider's retry policy from its configuration.
+	// Optional; nil uses the configured limits without a retry hook.
+	Retry func(provider string, retry config.RetryConfig) httpclient.RetryPolicy
+}
+
+func (o Options) transport(provider string) http.RoundTripper {
+	if o.Transport == nil {
+		return nil
+	}
+	return o.Transport(provider)
+}
+
+func (o Options) retry(provider string, retry config.RetryConfig) httpclient.RetryPolicy {
+	if o.Retry == nil {
+		return httpclient.RetryPolicy{
+			MaxRetries: retry.MaxRetries,
+			MinBackoff: retry.MinBackoff,
+			MaxBackoff: retry.MaxBackoff,
+		}
+	}
+	return o.Retry(provider, retry)
+}
+
+// New builds every notification service enabled in cfg: PagerDuty and
+// OpsGenie when they have an API key, and the mock provider when it is
+// enabled. The mail gateway is not included, as it needs the webhook queue.
+func New(cfg *config.Config, opts Options) ([]notification.Service, error) {
+	var services []notification.Service
+
+	if cfg.PagerDuty != nil && cfg.PagerDuty.APIKey != "" {
+		services = append(services, pagerduty.New(pagerduty.Config{
+			APIKey:        cfg.PagerDuty.APIKey,
+			APIURL:        cfg.PagerDuty.APIURL,
+			From:          cfg.PagerDuty.From,
+			WebhookSecret: cfg.PagerDuty.WebhookSecret,
+			Transport:     opts.transport("pagerduty"),
+			Retry:         opts.retry("pagerduty", cfg.PagerDuty.Retry),
+		}))
+	}
+
+	if cfg.OpsGenie != nil && cfg.OpsGenie.APIKey != "" {
+		services = append(services, opsgenie.New(opsgenie.Config{
+			APIKey:       cfg.OpsGenie.APIKey,
+			APIURL:       cfg.OpsGenie.APIURL,
+			WebhookToken: cfg.OpsGenie.WebhookToken,
+			Transport:    opts.transport("opsgenie"),
+			Retry:        opts.retry("opsgenie", cfg.OpsGenie.Retry),
+		}))
+	}
+
+	// The mock provider serves fixture alerts in place of a real provider
+	if cfg.Mock != nil && cfg.Mock.Enabled {
+		fixture, err := mock.LoadFixture(cfg.Mock.Fixture)
+		if err != nil {
+			return nil, err
+		}
+		mockSvc, err := mock.New(mock.Config{Source: cfg.Mock.Source, Fixture: fixture})
+		if err != nil {
+			return nil, fmt.Errorf("invalid mock provider fixture: %w", err)
+		}
+		services = append(services, mockSvc)
+	}
+
+	return services, nil
+}
@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/conall/outalator/config"
+)
+
+func TestNew(t *testing.T) {
+	cfg := &config.Config{
+		PagerDuty: &config.PagerDutyConfig{APIKey: "pd-key"},
+		OpsGenie:  &config.OpsGenieConfig{}, // No API key, so not built
+		Mock:      &config.MockConfig{Enabled: true, Fixture: "../../notification/mock/testdata/fixture.yaml"},
+	}
+	var transports []string
+	services, err := New(cfg, Options{
+		Transport: func(provider string) http.RoundTripper {
+			transports = append(transports, provider)
+			return http.DefaultTransport
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, svc := range services {
+		names = append(names, svc.Name())
+	}
+	if len(names) != 2 || names[0] != "pagerduty" || names[1] != "mock" {
+		t.Errorf("services = %v, want pagerduty and mock", names)
+	}
+	if len(transports) != 1 || transports[0] != "pagerduty" {
+		t.Errorf("transports built for %v, want pagerduty", transports)
+	}
+
+	cfg.Mock.Fixture = "missing.yaml"
+	if _, err := New(cfg, Options{}); err == nil {
+		t.Error("New with a missing mock fixture succeeded, want error")
+	}
+}
@@ -0,0 +1,206 @@
+// Package publicstatus serves the open outages marked public at
+// GET /public/status without sign-in, so a status page can read them from
+// outalator directly. Requests are rate limited per client address, and the
+// response is cached for a few seconds so that a busy status page does not
+// query the database on every request.
+package publicstatus
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/gorilla/mux"
+)
+
+// Path is the public status route
+const Path = "/public/status"
+
+// DefaultRequestsPerMinute is the per-client limit used when Config leaves
+// it unset
+const DefaultRequestsPerMinute = 60
+
+// DefaultCacheTTL is how long a response is served before the status is
+// rebuilt, when Config leaves it unset
+const DefaultCacheTTL = 10 * time.Second
+
+// Source builds the public status. It is satisfied by *service.Service.
+type Source interface {
+	PublicStatus(ctx context.Context, now time.Time) (*domain.PublicStatus, error)
+}
+
+// Config holds the public status endpoint's rate limit
+type Config struct {
+	// RequestsPerMinute is how many requests a client address may make each
+	// minute before getting 429. Optional, defaults to 60.
+	RequestsPerMinute int
+	// TrustProxy takes the client address from the first X-Forwarded-For
+	// entry, for deployments behind a load balancer that sets it. Leave it
+	// off otherwise, as clients can set the header themselves.
+	TrustProxy bool
+	// CacheTTL is how long a built status is served to every client before
+	// it is rebuilt. Optional, defaults to DefaultCacheTTL.
+	CacheTTL time.Duration
+}
+
+// Handler serves the public status
+type Handler struct {
+	source     Source
+	limiter    *limiter
+	trustProxy bool
+	logger     *slog.Logger
+	now        func() time.Time
+
+	cacheTTL time.Duration
+	mu       sync.Mutex // Held while the cached response is read or rebuilt
+	body     []byte     // The cached response, built at builtAt
+	builtAt  time.Time
+}
+
+// NewHandler creates a public status handler
+func NewHandler(source Source, cfg Config, logger *slog.Logger) *Handler {
+	if cfg.RequestsPerMinute <= 0 {
+		cfg.RequestsPerMinute = DefaultRequestsPerMinute
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+	return &Handler{
+		source:     source,
+		limiter:    newLimiter(cfg.RequestsPerMinute, time.Minute),
+		trustProxy: cfg.TrustProxy,
+		logger:     logger,
+		now:        time.Now,
+		cacheTTL:   cfg.CacheTTL,
+	}
+}
+
+// RegisterHandlers registers the public status route. Register it on a
+// router without authentication.
+func (h *Handler) RegisterHandlers(r *mux.Router) {
+	r.HandleFunc(Path, h.GetStatus).Methods("GET")
+}
+
+// GetStatus handles GET /public/status. Any origin may read it, so status
+// pages hosted elsewhere can fetch it from the browser.
+func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	now := h.now()
+	if wait, ok := h.limiter.allow(h.clientAddr(r), now); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+		respond(w, http.StatusTooManyRequests, map[string]string{"error": "Too many requests"})
+		return
+	}
+
+	body, err := h.status(r.Context(), now)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to build public status", "error", err)
+		respond(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load status"})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.cacheTTL/time.Second)))
+	_, _ = w.Write(body)
+}
+
+// status returns the encoded public status, rebuilding it when the cached
+// one is older than the cache TTL. Requests arriving while it is rebuilt
+// wait for the new one rather than each querying the source.
+func (h *Handler) status(ctx context.Context, now time.Time) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.body != nil && now.Sub(h.builtAt) < h.cacheTTL {
+		return h.body, nil
+	}
+	status, err := h.source.PublicStatus(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+	h.body, h.builtAt = append(body, '\n'), now
+	return h.body, nil
+}
+
+// clientAddr returns the address requests are counted against
+func (h *Handler) clientAddr(r *http.Request) string {
+	if h.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// limiter allows each client a number of requests per fixed window
+type limiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*usage
+}
+
+// usage counts a client's requests in its current window
+type usage struct {
+	start time.Time
+	count int
+}
+
+func newLimiter(limit int, window time.Duration) *limiter {
+	return &limiter{limit: limit, window: window, clients: make(map[string]*usage)}
+}
+
+// allow counts a request from client at now, reporting whether it is within
+// the limit and, when it is not, how long until the client's window resets
+func (l *limiter) allow(client string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	u, ok := l.clients[client]
+	if !ok || now.Sub(u.start) >= l.window {
+		if !ok {
+			l.prune(now)
+		}
+		l.clients[client] = &usage{start: now, count: 1}
+		return 0, true
+	}
+	if u.count >= l.limit {
+		return u.start.Add(l.window).Sub(now), false
+	}
+	u.count++
+	return 0, true
+}
+
+// prune forgets clients whose window has ended, so the map does not grow
+// with every address ever seen. It runs when a new client arrives and the
+// map has grown past a size worth sweeping.
+func (l *limiter) prune(now time.Time) {
+	if len(l.clients) < 1024 {
+		return
+	}
+	for client, u := range l.clients {
+		if now.Sub(u.start) >= l.window {
+			delete(l.clients, client)
+		}
+	}
+}
+
+func respond(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
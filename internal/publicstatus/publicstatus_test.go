@@ -0,0 +1,133 @@
+package publicstatus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+// fakeSource returns one public outage
+type fakeSource struct{}
+
+func (fakeSource) PublicStatus(_ context.Context, now time.Time) (*domain.PublicStatus, error) {
+	return &domain.PublicStatus{
+		Outages:   []domain.PublicOutage{{Title: "checkout down", Severity: "critical", StartedAt: now}},
+		UpdatedAt: now,
+	}, nil
+}
+
+func TestHandler_GetStatus(t *testing.T) {
+	router := mux.NewRouter()
+	h := NewHandler(fakeSource{}, Config{RequestsPerMinute: 2}, logging.Discard())
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+	h.RegisterHandlers(router)
+
+	get := func(remoteAddr string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, Path, nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get("10.0.0.1:1234")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d, want 200: %s", Path, rr.Code, rr.Body)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	var status domain.PublicStatus
+	if err := json.NewDecoder(rr.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Outages) != 1 || status.Outages[0].Title != "checkout down" {
+		t.Errorf("outages = %+v, want checkout down", status.Outages)
+	}
+
+	// The second request from the address is allowed, the third is not,
+	// and other addresses have their own allowance
+	if rr := get("10.0.0.1:5678"); rr.Code != http.StatusOK {
+		t.Errorf("second request = %d, want 200", rr.Code)
+	}
+	now = now.Add(20 * time.Second)
+	rr = get("10.0.0.1:1234")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request = %d, want 429", rr.Code)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "40" {
+		t.Errorf("Retry-After = %q, want 40", got)
+	}
+	if rr := get("10.0.0.2:1234"); rr.Code != http.StatusOK {
+		t.Errorf("request from another address = %d, want 200", rr.Code)
+	}
+
+	// The allowance resets once the window has passed
+	now = now.Add(40 * time.Second)
+	if rr := get("10.0.0.1:1234"); rr.Code != http.StatusOK {
+		t.Errorf("request after the window = %d, want 200", rr.Code)
+	}
+}
+
+func TestHandler_ClientAddr(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, Path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	if got := NewHandler(fakeSource{}, Config{}, logging.Discard()).clientAddr(req); got != "10.0.0.1" {
+		t.Errorf("clientAddr without TrustProxy = %q, want the remote address", got)
+	}
+	if got := NewHandler(fakeSource{}, Config{TrustProxy: true}, logging.Discard()).clientAddr(req); got != "203.0.113.7" {
+		t.Errorf("clientAddr with TrustProxy = %q, want the first forwarded address", got)
+	}
+}
+
+// countingSource counts the statuses it builds
+type countingSource struct {
+	fakeSource
+	calls int
+}
+
+func (s *countingSource) PublicStatus(ctx context.Context, now time.Time) (*domain.PublicStatus, error) {
+	s.calls++
+	return s.fakeSource.PublicStatus(ctx, now)
+}
+
+func TestHandler_CachesStatus(t *testing.T) {
+	source := &countingSource{}
+	h := NewHandler(source, Config{CacheTTL: 10 * time.Second}, logging.Discard())
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	get := func() *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		h.GetStatus(rr, httptest.NewRequest(http.MethodGet, Path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d, want 200", Path, rr.Code)
+		}
+		return rr
+	}
+
+	first := get()
+	if got := first.Header().Get("Cache-Control"); got != "public, max-age=10" {
+		t.Errorf("Cache-Control = %q, want public, max-age=10", got)
+	}
+	now = now.Add(9 * time.Second)
+	if second := get(); second.Body.String() != first.Body.String() || source.calls != 1 {
+		t.Errorf("within the TTL: status built %d times, want the cached response", source.calls)
+	}
+	now = now.Add(time.Second)
+	if get(); source.calls != 2 {
+		t.Errorf("after the TTL: status built %d times, want 2", source.calls)
+	}
+}
@@ -0,0 +1,104 @@
+package render
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// langPattern limits code block languages to what is safe in a class name
+var langPattern = regexp.MustCompile(`^[A-Za-z0-9_+#.-]{1,32}$`)
+
+// writeHTML writes block nodes as HTML, one block per line
+func writeHTML(b *strings.Builder, nodes []*node) {
+	for _, n := range nodes {
+		switch n.kind {
+		case paragraph:
+			b.WriteString("<p>")
+			writeInlineHTML(b, n.children)
+			b.WriteString("</p>\n")
+		case heading:
+			tag := "h" + strconv.Itoa(n.level)
+			b.WriteString("<" + tag + ">")
+			writeInlineHTML(b, n.children)
+			b.WriteString("</" + tag + ">\n")
+		case codeBlock:
+			b.WriteString("<pre><code")
+			if langPattern.MatchString(n.lang) {
+				b.WriteString(` class="language-` + html.EscapeString(n.lang) + `"`)
+			}
+			b.WriteString(">")
+			if n.text != "" {
+				b.WriteString(html.EscapeString(n.text) + "\n")
+			}
+			b.WriteString("</code></pre>\n")
+		case blockquote:
+			b.WriteString("<blockquote>\n")
+			writeHTML(b, n.children)
+			b.WriteString("</blockquote>\n")
+		case list:
+			tag := "ul"
+			if n.ordered {
+				tag = "ol"
+			}
+			b.WriteString("<" + tag)
+			if n.ordered && n.start != 1 {
+				b.WriteString(` start="` + strconv.Itoa(n.start) + `"`)
+			}
+			b.WriteString(">\n")
+			for _, item := range n.children {
+				b.WriteString("<li>")
+				writeItemHTML(b, item.children)
+				b.WriteString("</li>\n")
+			}
+			b.WriteString("</" + tag + ">\n")
+		case rule:
+			b.WriteString("<hr>\n")
+		}
+	}
+}
+
+// writeItemHTML writes the blocks of a list item, leaving a leading
+// paragraph unwrapped so lists stay compact
+func writeItemHTML(b *strings.Builder, blocks []*node) {
+	if len(blocks) > 0 && blocks[0].kind == paragraph {
+		writeInlineHTML(b, blocks[0].children)
+		blocks = blocks[1:]
+		if len(blocks) > 0 {
+			b.WriteString("\n")
+		}
+	}
+	writeHTML(b, blocks)
+}
+
+func writeInlineHTML(b *strings.Builder, nodes []*node) {
+	for _, n := range nodes {
+		switch n.kind {
+		case text:
+			b.WriteString(html.EscapeString(n.text))
+		case code:
+			b.WriteString("<code>" + html.EscapeString(n.text) + "</code>")
+		case emphasis:
+			b.WriteString("<em>")
+			writeInlineHTML(b, n.children)
+			b.WriteString("</em>")
+		case strong:
+			b.WriteString("<strong>")
+			writeInlineHTML(b, n.children)
+			b.WriteString("</strong>")
+		case strike:
+			b.WriteString("<del>")
+			writeInlineHTML(b, n.children)
+			b.WriteString("</del>")
+		case link:
+			b.WriteString(`<a href="` + html.EscapeString(n.url) + `" rel="nofollow noopener noreferrer" target="_blank">`)
+			writeInlineHTML(b, n.children)
+			b.WriteString("</a>")
+		case lineBreak:
+			b.WriteString("<br>\n")
+		case rawHTML:
+			b.WriteString(n.text)
+		}
+	}
+}
@@ -0,0 +1,741 @@
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// kind is the type of a syntax tree node
+type kind int
+
+// Block kinds
+const (
+	paragraph kind = iota
+	heading
+	codeBlock
+	blockquote
+	list
+	listItem
+	rule
+)
+
+// Inline kinds
+const (
+	text kind = iota + 100
+	code
+	emphasis
+	strong
+	strike
+	link
+	lineBreak
+	rawHTML
+)
+
+// node is a block or inline element
+type node struct {
+	kind     kind
+	text     string // Literal text, code or an allowed HTML tag
+	lang     string // Code block language
+	url      string // Link destination
+	level    int    // Heading level
+	ordered  bool   // Ordered list
+	start    int    // First number of an ordered list
+	children []*node
+}
+
+// allowedTags are the HTML tags that may be embedded in markdown, without
+// attributes
+var allowedTags = []string{"br", "kbd", "sub", "sup"}
+
+// allowedSchemes are the URL schemes links may use. URLs without a scheme
+// are relative and always allowed.
+var allowedSchemes = []string{"http", "https", "mailto"}
+
+var (
+	atxHeadingPattern    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))??(?:[ \t]+#+)?[ \t]*$`)
+	setextPattern        = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	fencePattern         = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+	thematicBreakPattern = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	bulletPattern        = regexp.MustCompile(`^( {0,3})([-*+])(?:[ \t]+|$)`)
+	orderedPattern       = regexp.MustCompile(`^( {0,3})(\d{1,9})([.)])(?:[ \t]+|$)`)
+	quotePattern         = regexp.MustCompile(`^ {0,3}> ?`)
+	autolinkPattern      = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailAutolinkPattern = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)*)>`)
+	tagPattern           = regexp.MustCompile(`^</?([A-Za-z][A-Za-z0-9-]*)(\s[^<>]*)?/?>`)
+	otherHTMLPattern     = regexp.MustCompile(`^(?s)<!--.*?-->|^<[!?][^>]*>`)
+)
+
+// maxNesting limits how deeply quotes, lists, emphasis and links nest, and
+// the parentheses in a link destination
+const maxNesting = 32
+
+// maxLinkText is the longest link text, in bytes
+const maxLinkText = 1000
+
+// parser builds the syntax tree, collecting problems for Validate
+type parser struct {
+	problems []string
+	depth    int // Current nesting of quotes, list items and inline content
+}
+
+func (p *parser) problem(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if !slices.Contains(p.problems, msg) {
+		p.problems = append(p.problems, msg)
+	}
+}
+
+// blocks parses lines into block nodes
+func (p *parser) blocks(lines []string) []*node {
+	var nodes []*node
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case isFence(line):
+			var n *node
+			n, i = p.fencedCode(lines, i)
+			nodes = append(nodes, n)
+		case thematicBreakPattern.MatchString(line):
+			nodes = append(nodes, &node{kind: rule})
+			i++
+		case atxHeadingPattern.MatchString(line):
+			m := atxHeadingPattern.FindStringSubmatch(line)
+			nodes = append(nodes, &node{kind: heading, level: len(m[1]), children: p.inlines(m[2])})
+			i++
+		case quotePattern.MatchString(line) && p.depth < maxNesting:
+			var quoted []string
+			for ; i < len(lines) && quotePattern.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quotePattern.ReplaceAllString(lines[i], ""))
+			}
+			nodes = append(nodes, &node{kind: blockquote, children: p.nested(quoted)})
+		case isListItem(line) && p.depth < maxNesting:
+			var n *node
+			n, i = p.list(lines, i)
+			nodes = append(nodes, n)
+		default:
+			var n *node
+			n, i = p.paragraph(lines, i)
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// nested parses the blocks inside a quote or list item
+func (p *parser) nested(lines []string) []*node {
+	p.depth++
+	defer func() { p.depth-- }()
+	return p.blocks(lines)
+}
+
+// paragraph parses the paragraph starting at lines[i], which becomes a
+// setext heading when underlined
+func (p *parser) paragraph(lines []string, i int) (*node, int) {
+	var para []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if len(para) > 0 && setextPattern.MatchString(line) {
+			level := 2
+			if strings.TrimSpace(line)[0] == '=' {
+				level = 1
+			}
+			return &node{kind: heading, level: level, children: p.inlines(strings.Join(para, "\n"))}, i + 1
+		}
+		if len(para) > 0 && (isBlank(line) || interruptsParagraph(line)) {
+			break
+		}
+		para = append(para, strings.TrimSpace(line))
+	}
+	return &node{kind: paragraph, children: p.inlines(strings.Join(para, "\n"))}, i
+}
+
+// fencedCode parses the fenced code block starting at lines[i]. A fence
+// that is never closed runs to the end of the content.
+func (p *parser) fencedCode(lines []string, i int) (*node, int) {
+	m := fencePattern.FindStringSubmatch(lines[i])
+	indent, fence := len(m[1]), m[2]
+	n := &node{kind: codeBlock}
+	if fields := strings.Fields(m[3]); len(fields) > 0 {
+		n.lang = fields[0]
+	}
+
+	var body []string
+	for i++; i < len(lines); i++ {
+		if closesFence(lines[i], fence) {
+			i++
+			break
+		}
+		body = append(body, trimIndent(lines[i], indent))
+	}
+	n.text = strings.Join(body, "\n")
+	return n, i
+}
+
+// list parses the list starting at lines[i]. Items continue on lines
+// indented past their marker and on unindented lines that continue their
+// paragraph.
+func (p *parser) list(lines []string, i int) (*node, int) {
+	first, _ := parseListMarker(lines[i])
+	l := &node{kind: list, ordered: first.ordered, start: first.start}
+
+	for i < len(lines) {
+		m, ok := parseListMarker(lines[i])
+		if !ok || !m.sameList(first) || thematicBreakPattern.MatchString(lines[i]) {
+			break
+		}
+		item := []string{lines[i][min(m.width, len(lines[i])):]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if isBlank(line) {
+				next := nextNonBlank(lines, i)
+				if next < len(lines) && indentWidth(lines[next]) >= m.width {
+					item = append(item, "")
+					continue
+				}
+				break
+			}
+			if indentWidth(line) >= m.width {
+				item = append(item, trimIndent(line, m.width))
+				continue
+			}
+			if isListItem(line) || interruptsParagraph(line) || isBlank(item[len(item)-1]) {
+				break
+			}
+			item = append(item, strings.TrimSpace(line))
+		}
+		l.children = append(l.children, &node{kind: listItem, children: p.nested(item)})
+
+		if i < len(lines) && isBlank(lines[i]) {
+			next := nextNonBlank(lines, i)
+			if next < len(lines) {
+				if m, ok := parseListMarker(lines[next]); ok && m.sameList(first) {
+					i = next
+					continue
+				}
+			}
+			break
+		}
+	}
+	return l, i
+}
+
+// listMarker describes the marker that starts a list item
+type listMarker struct {
+	ordered bool
+	char    byte // Bullet character or ordered list delimiter
+	start   int  // Number of an ordered item
+	width   int  // Columns taken by the indent, marker and following space
+}
+
+func (m listMarker) sameList(other listMarker) bool {
+	return m.ordered == other.ordered && m.char == other.char
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	if m := bulletPattern.FindStringSubmatch(line); m != nil {
+		return listMarker{char: m[2][0], width: markerWidth(line, len(m[0]))}, true
+	}
+	if m := orderedPattern.FindStringSubmatch(line); m != nil {
+		start, _ := strconv.Atoi(m[2])
+		return listMarker{ordered: true, char: m[3][0], start: start, width: markerWidth(line, len(m[0]))}, true
+	}
+	return listMarker{}, false
+}
+
+// markerWidth returns the width of a list marker match, treating an item
+// with no content after the marker as if one space followed it
+func markerWidth(line string, matched int) int {
+	if matched == len(line) && !strings.HasSuffix(line, " ") {
+		return matched + 1
+	}
+	return matched
+}
+
+func isListItem(line string) bool {
+	_, ok := parseListMarker(line)
+	return ok
+}
+
+func isFence(line string) bool {
+	m := fencePattern.FindStringSubmatch(line)
+	return m != nil && !(m[2][0] == '`' && strings.Contains(m[3], "`"))
+}
+
+func closesFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return indentWidth(line) < 4 && len(trimmed) >= len(fence) &&
+		strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// interruptsParagraph reports whether line starts a block that ends a
+// paragraph without a blank line before it
+func interruptsParagraph(line string) bool {
+	return isFence(line) || thematicBreakPattern.MatchString(line) ||
+		atxHeadingPattern.MatchString(line) || quotePattern.MatchString(line) ||
+		bulletPattern.MatchString(line) || strings.HasPrefix(strings.TrimSpace(line), "1. ")
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func nextNonBlank(lines []string, i int) int {
+	for i < len(lines) && isBlank(lines[i]) {
+		i++
+	}
+	return i
+}
+
+// indentWidth returns the columns of leading whitespace, with tab stops
+// every four columns
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// trimIndent removes up to n columns of leading whitespace
+func trimIndent(line string, n int) string {
+	width := 0
+	for i, r := range line {
+		if width >= n || (r != ' ' && r != '\t') {
+			return line[i:]
+		}
+		if r == '\t' {
+			width += 4 - width%4
+		} else {
+			width++
+		}
+	}
+	return ""
+}
+
+// inlines parses the inline content of a block. Beyond maxNesting levels of
+// emphasis and links the content is kept as text.
+func (p *parser) inlines(s string) []*node {
+	in := &inline{parser: p, s: s, ticks: backtickRuns(s), noCloser: make(map[[2]int]int)}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	var nodes []*node
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = append(nodes, &node{kind: text, text: buf.String()})
+			buf.Reset()
+		}
+	}
+	emit := func(n ...*node) {
+		flush()
+		nodes = append(nodes, n...)
+	}
+	nested := p.depth <= maxNesting
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			emit(&node{kind: lineBreak})
+			i += 2
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			buf.WriteByte(s[i+1])
+			i += 2
+		case c == '\n':
+			emit(&node{kind: lineBreak})
+			i++
+		case c == '`':
+			if n, end, ok := in.codeSpan(i); ok {
+				emit(n)
+				i = end
+				continue
+			}
+			run := runLength(s, i)
+			buf.WriteString(s[i : i+run])
+			i += run
+		case c == '<':
+			if n, end, ok := p.angle(s, i); ok {
+				emit(n...)
+				i = end
+				continue
+			}
+			buf.WriteByte(c)
+			i++
+		case nested && (c == '[' || (c == '!' && i+1 < len(s) && s[i+1] == '[')):
+			if n, end, ok := in.link(i); ok {
+				emit(n...)
+				i = end
+				continue
+			}
+			buf.WriteByte(c)
+			i++
+		case nested && (c == '*' || c == '_' || c == '~'):
+			if n, end, ok := in.delimited(i); ok {
+				emit(n)
+				i = end
+				continue
+			}
+			run := runLength(s, i)
+			buf.WriteString(s[i : i+run])
+			i += run
+		case (c == 'h' || c == 'H') && (i == 0 || !isWordByte(s[i-1])):
+			if n, end, ok := bareURL(s, i); ok {
+				emit(n)
+				i = end
+				continue
+			}
+			buf.WriteByte(c)
+			i++
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return nodes
+}
+
+// inline is the state for parsing one run of inline content. It indexes
+// backtick runs and remembers failed delimiter searches so that parsing
+// stays close to linear on pathological input.
+type inline struct {
+	*parser
+	s        string
+	ticks    map[int][]int  // Start of each backtick run, by run length
+	noCloser map[[2]int]int // Earliest start of a failed closing delimiter search, by character and run length
+}
+
+// backtickRuns indexes the backtick runs of s by length
+func backtickRuns(s string) map[int][]int {
+	runs := make(map[int][]int)
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		n := runLength(s, i)
+		runs[n] = append(runs[n], i)
+		i += n
+	}
+	return runs
+}
+
+// codeSpan parses the code span opened by the backtick run at s[i]
+func (in *inline) codeSpan(i int) (*node, int, bool) {
+	run := runLength(in.s, i)
+	starts := in.ticks[run]
+	k, _ := slices.BinarySearch(starts, i+run)
+	if k == len(starts) {
+		return nil, 0, false
+	}
+	j := starts[k]
+	content := strings.ReplaceAll(in.s[i+run:j], "\n", " ")
+	if len(content) > 1 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.TrimSpace(content) != "" {
+		content = content[1 : len(content)-1]
+	}
+	return &node{kind: code, text: content}, j + run, true
+}
+
+// angle parses an autolink or embedded HTML starting at s[i]. Disallowed
+// HTML is reported and kept as text.
+func (p *parser) angle(s string, i int) ([]*node, int, bool) {
+	rest := s[i:]
+	if m := autolinkPattern.FindStringSubmatch(rest); m != nil {
+		return p.linkTo(m[1], []*node{{kind: text, text: m[1]}}), i + len(m[0]), true
+	}
+	if m := emailAutolinkPattern.FindStringSubmatch(rest); m != nil {
+		return []*node{{kind: link, url: "mailto:" + m[1], children: []*node{{kind: text, text: m[1]}}}}, i + len(m[0]), true
+	}
+	if m := tagPattern.FindStringSubmatch(rest); m != nil {
+		name := strings.ToLower(m[1])
+		if slices.Contains(allowedTags, name) && strings.TrimSpace(m[2]) == "" {
+			tag := "<" + name + ">"
+			if strings.HasPrefix(m[0], "</") {
+				tag = "</" + name + ">"
+			}
+			return []*node{{kind: rawHTML, text: tag}}, i + len(m[0]), true
+		}
+		p.problem("HTML <%s> is not allowed", name)
+		return []*node{{kind: text, text: m[0]}}, i + len(m[0]), true
+	}
+	if m := otherHTMLPattern.FindString(rest); m != "" {
+		p.problem("embedded HTML is not allowed")
+		return []*node{{kind: text, text: m}}, i + len(m), true
+	}
+	return nil, 0, false
+}
+
+// link parses a [text](url) link or ![alt](url) image starting at s[i].
+// Images are shown as links so rendering never loads remote content.
+func (in *inline) link(i int) ([]*node, int, bool) {
+	s := in.s
+	image := s[i] == '!'
+	open := i
+	if image {
+		open++
+	}
+	closeBracket := matchingBracket(s, open)
+	if closeBracket < 0 || closeBracket+1 >= len(s) || s[closeBracket+1] != '(' {
+		return nil, 0, false
+	}
+	dest, end, ok := linkDestination(s, closeBracket+2)
+	if !ok {
+		return nil, 0, false
+	}
+
+	label := s[open+1 : closeBracket]
+	children := in.inlines(label)
+	if image && strings.TrimSpace(label) == "" {
+		children = []*node{{kind: text, text: dest}}
+	}
+	return in.linkTo(dest, children), end, true
+}
+
+// linkTo links children to dest, or reports dest and returns the children
+// unlinked when its scheme is not allowed
+func (p *parser) linkTo(dest string, children []*node) []*node {
+	if !safeURL(dest) {
+		p.problem("link to %q is not allowed", dest)
+		return children
+	}
+	return []*node{{kind: link, url: dest, children: children}}
+}
+
+// matchingBracket returns the index of the ] closing the [ at s[i], or -1.
+// Like link reference labels, link text is limited to maxLinkText bytes.
+func matchingBracket(s string, i int) int {
+	depth := 0
+	for j := i; j < len(s) && j <= i+maxLinkText; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// linkDestination parses the destination and optional title of a link
+// starting at s[i], just after the opening parenthesis. It returns the
+// destination and the index after the closing parenthesis.
+func linkDestination(s string, i int) (string, int, bool) {
+	i = skipSpaces(s, i)
+	var dest string
+	if i < len(s) && s[i] == '<' {
+		end := strings.IndexAny(s[i+1:], ">\n")
+		if end < 0 || s[i+1+end] != '>' {
+			return "", 0, false
+		}
+		dest = s[i+1 : i+1+end]
+		i += end + 2
+	} else {
+		start, depth := i, 0
+		for ; i < len(s) && s[i] > ' '; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				continue
+			}
+			if s[i] == '(' {
+				if depth++; depth > maxNesting {
+					return "", 0, false
+				}
+			} else if s[i] == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+		}
+		dest = s[start:i]
+	}
+
+	i = skipSpaces(s, i)
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		end := strings.IndexByte(s[i+1:], s[i])
+		if end < 0 {
+			return "", 0, false
+		}
+		i = skipSpaces(s, i+end+2)
+	}
+	if i >= len(s) || s[i] != ')' {
+		return "", 0, false
+	}
+	return unescape(dest), i + 1, true
+}
+
+// delimited parses emphasis, strong emphasis or strikethrough opened by the
+// delimiter run at s[i]
+func (in *inline) delimited(i int) (*node, int, bool) {
+	s := in.s
+	c := s[i]
+	run := runLength(s, i)
+	if i+run >= len(s) || isSpace(s[i+run]) {
+		return nil, 0, false
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return nil, 0, false
+	}
+
+	var kinds []kind
+	switch {
+	case c == '~' && run == 2:
+		kinds = []kind{strike}
+	case c == '~':
+		return nil, 0, false
+	case run == 1:
+		kinds = []kind{emphasis}
+	case run == 2:
+		kinds = []kind{strong}
+	case run == 3:
+		kinds = []kind{strong, emphasis}
+	default:
+		return nil, 0, false
+	}
+
+	closing := in.closingDelimiter(i+run, c, run)
+	if closing < 0 {
+		return nil, 0, false
+	}
+	n := &node{kind: kinds[len(kinds)-1], children: in.inlines(s[i+run : closing])}
+	for k := len(kinds) - 2; k >= 0; k-- {
+		n = &node{kind: kinds[k], children: []*node{n}}
+	}
+	return n, closing + run, true
+}
+
+// closingDelimiter returns the index of the run of exactly n c characters
+// that closes a delimiter opened before from, or -1. Code spans are skipped.
+// A search that failed fails again from any later start, so failures are
+// remembered.
+func (in *inline) closingDelimiter(from int, c byte, n int) int {
+	key := [2]int{int(c), n}
+	if failed, ok := in.noCloser[key]; ok && from >= failed {
+		return -1
+	}
+	s := in.s
+	for j := from; j < len(s); {
+		switch {
+		case s[j] == '\\':
+			j += 2
+		case s[j] == '`':
+			if _, end, ok := in.codeSpan(j); ok {
+				j = end
+			} else {
+				j += runLength(s, j)
+			}
+		case s[j] == c:
+			run := runLength(s, j)
+			if run == n && !isSpace(s[j-1]) && (c != '_' || j+run >= len(s) || !isWordByte(s[j+run])) {
+				return j
+			}
+			j += run
+		default:
+			j++
+		}
+	}
+	in.noCloser[key] = from
+	return -1
+}
+
+// bareURL parses an http or https URL written without link syntax
+func bareURL(s string, i int) (*node, int, bool) {
+	lower := strings.ToLower(s[i:min(len(s), i+8)])
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return nil, 0, false
+	}
+	end := i
+	for end < len(s) && !isSpace(s[end]) && s[end] != '<' {
+		end++
+	}
+	// Trailing punctuation belongs to the sentence, and a closing
+	// parenthesis only to the URL when it opened one
+	for end > i {
+		last := s[end-1]
+		if strings.IndexByte(".,:;!?'\"*_~", last) >= 0 ||
+			(last == ')' && strings.Count(s[i:end], "(") < strings.Count(s[i:end], ")")) {
+			end--
+			continue
+		}
+		break
+	}
+	dest := s[i:end]
+	if u, err := url.Parse(dest); err != nil || u.Host == "" {
+		return nil, 0, false
+	}
+	return &node{kind: link, url: dest, children: []*node{{kind: text, text: dest}}}, end, true
+}
+
+// safeURL reports whether a link may point at dest
+func safeURL(dest string) bool {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" || slices.Contains(allowedSchemes, strings.ToLower(u.Scheme))
+}
+
+// unescape removes backslash escapes from punctuation
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func runLength(s string, i int) int {
+	n := 1
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+// isWordByte reports whether c is part of a word. Bytes of multi-byte
+// characters count as word bytes.
+func isWordByte(c byte) bool {
+	return c >= utf8.RuneSelf || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
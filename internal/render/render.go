@@ -0,0 +1,75 @@
+// Package render turns note content into HTML for the web UI and Slack
+// mrkdwn for bot messages.
+//
+// Markdown is parsed into a small syntax tree covering the common subset
+// notes use: paragraphs, ATX and setext headings, fenced code, block quotes,
+// lists, rules, emphasis, strikethrough, code spans, links and bare URLs.
+// Output is sanitized by construction: text is always escaped, links keep
+// only http, https, mailto and relative URLs, images become links, and the
+// only embedded HTML passed through is a short list of attribute-less tags.
+// Validate reports anything else so it can be rejected when a note is
+// written; rendering shows it as literal text.
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Rendered is note content ready for display
+type Rendered struct {
+	HTML  string `json:"html"`
+	Slack string `json:"slack"` // Slack mrkdwn
+}
+
+// Note renders content written in the given note format. Plaintext is
+// escaped, log content is shown as preformatted text and markdown is
+// rendered with Markdown.
+func Note(format, content string) Rendered {
+	switch format {
+	case domain.NoteFormatMarkdown:
+		return Markdown(content)
+	case domain.NoteFormatLog:
+		return Rendered{
+			HTML:  "<pre><code>" + html.EscapeString(content) + "</code></pre>",
+			Slack: "```\n" + escapeSlack(strings.TrimRight(content, "\n")) + "\n```",
+		}
+	}
+	return Rendered{
+		HTML:  "<p>" + strings.ReplaceAll(html.EscapeString(content), "\n", "<br>\n") + "</p>",
+		Slack: escapeSlack(content),
+	}
+}
+
+// Markdown renders markdown source. Disallowed HTML and unsafe links are
+// shown as text rather than failing.
+func Markdown(source string) Rendered {
+	p := &parser{}
+	doc := p.blocks(splitLines(source))
+
+	var h, s strings.Builder
+	writeHTML(&h, doc)
+	writeSlack(&s, doc)
+	return Rendered{HTML: strings.TrimSuffix(h.String(), "\n"), Slack: s.String()}
+}
+
+// Validate reports embedded HTML that is not allowed and links with unsafe
+// URLs, as domain.ErrInvalidInput
+func Validate(source string) error {
+	p := &parser{}
+	p.blocks(splitLines(source))
+	if len(p.problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: markdown %s", domain.ErrInvalidInput, strings.Join(p.problems, "; "))
+}
+
+// splitLines splits source into lines, accepting any line ending
+func splitLines(source string) []string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	return strings.Split(source, "\n")
+}
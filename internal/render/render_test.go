@@ -0,0 +1,164 @@
+package render
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestMarkdownHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"paragraph", "Rolled back to v1.4.2", "<p>Rolled back to v1.4.2</p>"},
+		{"line breaks", "first\nsecond", "<p>first<br>\nsecond</p>"},
+		{"emphasis", "*slow* and **down** and ~~fixed~~", "<p><em>slow</em> and <strong>down</strong> and <del>fixed</del></p>"},
+		{"nested emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>"},
+		{"snake case", "see db_pool_size and max_conns", "<p>see db_pool_size and max_conns</p>"},
+		{"arithmetic", "2 * 3 * 4", "<p>2 * 3 * 4</p>"},
+		{"code span", "run `kubectl get pods <ns>`", "<p>run <code>kubectl get pods &lt;ns&gt;</code></p>"},
+		{"escapes", `\*not emphasis\*`, "<p>*not emphasis*</p>"},
+		{"heading", "## Root cause ##", "<h2>Root cause</h2>"},
+		{"setext heading", "Timeline\n===", "<h1>Timeline</h1>"},
+		{"hashtag", "#incident-123", "<p>#incident-123</p>"},
+		{"fenced code", "```sql\nSELECT 1 < 2;\n```", "<pre><code class=\"language-sql\">SELECT 1 &lt; 2;\n</code></pre>"},
+		{"unclosed fence", "```\nstill code", "<pre><code>still code\n</code></pre>"},
+		{"quote", "> db is down\n> again", "<blockquote>\n<p>db is down<br>\nagain</p>\n</blockquote>"},
+		{"bullet list", "- one\n- two\n  continued", "<ul>\n<li>one</li>\n<li>two<br>\ncontinued</li>\n</ul>"},
+		{"ordered list", "3. three\n4. four", "<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>"},
+		{"nested list", "- a\n  - b", "<ul>\n<li>a\n<ul>\n<li>b</li>\n</ul>\n</li>\n</ul>"},
+		{"rule", "above\n\n---\n\nbelow", "<p>above</p>\n<hr>\n<p>below</p>"},
+		{"link", "[runbook](https://wiki.example.com/db?a=1&b=2)",
+			`<p><a href="https://wiki.example.com/db?a=1&amp;b=2" rel="nofollow noopener noreferrer" target="_blank">runbook</a></p>`},
+		{"bare url", "see https://status.example.com/incidents/1.",
+			`<p>see <a href="https://status.example.com/incidents/1" rel="nofollow noopener noreferrer" target="_blank">https://status.example.com/incidents/1</a>.</p>`},
+		{"image becomes link", "![graph](https://grafana.example.com/p.png)",
+			`<p><a href="https://grafana.example.com/p.png" rel="nofollow noopener noreferrer" target="_blank">graph</a></p>`},
+		{"allowed html", "press <kbd>Ctrl</kbd>", "<p>press <kbd>Ctrl</kbd></p>"},
+		{"less than", "p99 < 200ms", "<p>p99 &lt; 200ms</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.in).HTML; got != tt.want {
+				t.Errorf("Markdown(%q).HTML =\n%s\nwant\n%s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownSanitizes(t *testing.T) {
+	tests := []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror=alert(1)>`,
+		`<kbd onclick="alert(1)">x</kbd>`,
+		`[click](javascript:alert(1))`,
+		`[click](JaVaScRiPt:alert(1))`,
+		"[click](java\tscript:alert(1))",
+		`<javascript:alert(1)>`,
+		`![x](data:text/html;base64,PHNjcmlwdD4=)`,
+		`[x](https://example.com/"onmouseover="alert(1))`,
+		"```\"><script>\nx\n```",
+		`<!-- <script>alert(1)</script> -->`,
+	}
+	// Every tag in the output must be one the renderer writes, and every
+	// link must use an allowed scheme
+	tags := regexp.MustCompile(`<(/?)([A-Za-z0-9]+)([^>]*)>`)
+	written := []string{"p", "br", "em", "strong", "del", "code", "pre", "a", "h1", "h2", "blockquote", "ul", "ol", "li", "hr", "kbd", "sub", "sup"}
+	for _, in := range tests {
+		got := Markdown(in).HTML
+		for _, m := range tags.FindAllStringSubmatch(got, -1) {
+			if !slices.Contains(written, strings.ToLower(m[2])) {
+				t.Errorf("Markdown(%q).HTML = %s, has tag <%s>", in, got, m[2])
+			}
+			attrs := m[3]
+			if m[1] == "/" {
+				continue
+			}
+			if m[2] == "a" {
+				attrs = strings.Replace(attrs, ` rel="nofollow noopener noreferrer" target="_blank"`, "", 1)
+				href := strings.TrimSuffix(strings.TrimPrefix(attrs, ` href="`), `"`)
+				if !strings.HasPrefix(href, "https://") {
+					t.Errorf("Markdown(%q).HTML = %s, links to %s", in, got, href)
+				}
+			} else if attrs != "" && !strings.HasPrefix(attrs, ` class="language-`) {
+				t.Errorf("Markdown(%q).HTML = %s, has attributes %s", in, got, attrs)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		"**bold** and `<code>` and <br> and <sup>2</sup>",
+		"```html\n<div onclick=\"x\">fine in code</div>\n```",
+		"[relative](/api/v1/outages) and [mail](mailto:oncall@example.com)",
+		"a < b and c > d",
+	}
+	for _, in := range valid {
+		if err := Validate(in); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", in, err)
+		}
+	}
+
+	invalid := []string{
+		"<div>block</div>",
+		"<b onclick=\"x\">hi</b>",
+		"<br style=\"x\">",
+		"<!-- hidden -->",
+		"[x](javascript:alert(1))",
+		"<vbscript:msgbox>",
+	}
+	for _, in := range invalid {
+		if err := Validate(in); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Validate(%q) = %v, want ErrInvalidInput", in, err)
+		}
+	}
+}
+
+func TestMarkdownSlack(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"emphasis", "*slow* and **down** and ~~fixed~~", "_slow_ and *down* and ~fixed~"},
+		{"escaping", "p99 < 200ms & rising", "p99 &lt; 200ms &amp; rising"},
+		{"heading", "# Root cause", "*Root cause*"},
+		{"link", "[runbook](https://wiki.example.com/db)", "<https://wiki.example.com/db|runbook>"},
+		{"bare url", "https://example.com", "<https://example.com>"},
+		{"code", "`a<b`\n\n```\nx & y\n```", "`a&lt;b`\n\n```\nx &amp; y\n```"},
+		{"list", "- one\n- two", "• one\n• two"},
+		{"ordered list", "1. one\n2. two", "1. one\n2. two"},
+		{"quote", "> first\n> second", "> first\n> second"},
+		{"disallowed html", "<b>hi</b>", "&lt;b&gt;hi&lt;/b&gt;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.in).Slack; got != tt.want {
+				t.Errorf("Markdown(%q).Slack = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNote(t *testing.T) {
+	tests := []struct {
+		format, content string
+		want            Rendered
+	}{
+		{domain.NoteFormatPlaintext, "a <b>\nc", Rendered{HTML: "<p>a &lt;b&gt;<br>\nc</p>", Slack: "a &lt;b&gt;\nc"}},
+		{domain.NoteFormatLog, "\tat Foo()\n", Rendered{HTML: "<pre><code>\tat Foo()\n</code></pre>", Slack: "```\n\tat Foo()\n```"}},
+		{domain.NoteFormatMarkdown, "**x**", Rendered{HTML: "<p><strong>x</strong></p>", Slack: "*x*"}},
+	}
+	for _, tt := range tests {
+		if got := Note(tt.format, tt.content); got != tt.want {
+			t.Errorf("Note(%s, %q) = %+v, want %+v", tt.format, tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// slackEscaper escapes the characters Slack treats as control sequences
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeSlack(s string) string {
+	return slackEscaper.Replace(s)
+}
+
+// writeSlack writes block nodes as Slack mrkdwn, separating blocks with a
+// blank line. Slack has no headings or rules, so headings are bold and
+// rules a line of box-drawing characters.
+func writeSlack(b *strings.Builder, nodes []*node) {
+	for i, n := range nodes {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		switch n.kind {
+		case paragraph:
+			writeInlineSlack(b, n.children)
+		case heading:
+			b.WriteString("*")
+			writeInlineSlack(b, n.children)
+			b.WriteString("*")
+		case codeBlock:
+			b.WriteString("```\n" + escapeSlack(n.text) + "\n```")
+		case blockquote:
+			var inner strings.Builder
+			writeSlack(&inner, n.children)
+			b.WriteString("> " + strings.ReplaceAll(inner.String(), "\n", "\n> "))
+		case list:
+			for j, item := range n.children {
+				if j > 0 {
+					b.WriteString("\n")
+				}
+				marker := "• "
+				if n.ordered {
+					marker = strconv.Itoa(n.start+j) + ". "
+				}
+				var inner strings.Builder
+				writeSlack(&inner, item.children)
+				indent := strings.Repeat(" ", utf8.RuneCountInString(marker)+1)
+				b.WriteString(marker + strings.ReplaceAll(inner.String(), "\n", "\n"+indent))
+			}
+		case rule:
+			b.WriteString("────────")
+		}
+	}
+}
+
+func writeInlineSlack(b *strings.Builder, nodes []*node) {
+	for _, n := range nodes {
+		switch n.kind {
+		case text:
+			b.WriteString(escapeSlack(n.text))
+		case code:
+			b.WriteString("`" + escapeSlack(n.text) + "`")
+		case emphasis:
+			b.WriteString("_")
+			writeInlineSlack(b, n.children)
+			b.WriteString("_")
+		case strong:
+			b.WriteString("*")
+			writeInlineSlack(b, n.children)
+			b.WriteString("*")
+		case strike:
+			b.WriteString("~")
+			writeInlineSlack(b, n.children)
+			b.WriteString("~")
+		case link:
+			label := plainText(n.children)
+			if label == n.url || strings.TrimPrefix(n.url, "mailto:") == label {
+				b.WriteString("<" + escapeSlack(n.url) + ">")
+				continue
+			}
+			b.WriteString("<" + escapeSlack(n.url) + "|" + escapeSlack(strings.ReplaceAll(label, "|", "/")) + ">")
+		case lineBreak:
+			b.WriteString("\n")
+		case rawHTML:
+			if n.text == "<br>" {
+				b.WriteString("\n")
+			}
+		}
+	}
+}
+
+// plainText returns the text of inline nodes without formatting
+func plainText(nodes []*node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.kind {
+		case text, code:
+			b.WriteString(n.text)
+		case lineBreak:
+			b.WriteString(" ")
+		default:
+			b.WriteString(plainText(n.children))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+// Package retention periodically applies the retention policy, archiving
+// long-resolved outages out of the database and purging old archives, so
+// the hot tables stay small and list queries fast.
+package retention
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/schedule"
+)
+
+// defaultInterval is the time between runs when none is configured
+const defaultInterval = 24 * time.Hour
+
+// Runner is the subset of the service layer the scheduler drives
+type Runner interface {
+	RunRetention(ctx context.Context, now time.Time, trigger, triggeredBy string) (*domain.RetentionRun, error)
+}
+
+// Scheduler applies the retention policy on a fixed interval
+type Scheduler struct {
+	*schedule.Runner
+	runner Runner
+	logger *slog.Logger
+}
+
+// NewScheduler creates a scheduler for the given service. A zero interval
+// falls back to the package default.
+func NewScheduler(runner Runner, interval time.Duration, logger *slog.Logger) *Scheduler {
+	s := &Scheduler{runner: runner, logger: logger}
+	s.Runner = schedule.New(s.RunOnce, interval, defaultInterval)
+	return s
+}
+
+// RunOnce applies the policy once. A run already in progress, e.g. one
+// started by an admin, is left to finish and this one skipped. The service
+// logs each run's outcome.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	_, err := s.runner.RunRetention(ctx, time.Now(), domain.RetentionTriggerScheduled, "")
+	if errors.Is(err, domain.ErrConflict) {
+		s.logger.InfoContext(ctx, "skipping scheduled retention run", "reason", err)
+	}
+}
@@ -0,0 +1,56 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeRunner struct {
+	triggers chan string
+}
+
+func (f *fakeRunner) RunRetention(_ context.Context, _ time.Time, trigger, _ string) (*domain.RetentionRun, error) {
+	select {
+	case f.triggers <- trigger:
+	default:
+	}
+	return &domain.RetentionRun{Trigger: trigger}, nil
+}
+
+func TestRun_RunsImmediatelyAndStopsOnCancel(t *testing.T) {
+	runner := &fakeRunner{triggers: make(chan string, 1)}
+	s := NewScheduler(runner, time.Hour, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case trigger := <-runner.triggers:
+		if trigger != domain.RetentionTriggerScheduled {
+			t.Errorf("trigger = %q, want %q", trigger, domain.RetentionTriggerScheduled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not apply the policy on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewScheduler_DefaultInterval(t *testing.T) {
+	s := NewScheduler(&fakeRunner{}, 0, logging.Discard())
+	if s.Interval() != defaultInterval {
+		t.Errorf("Interval() = %v, want %v", s.Interval(), defaultInterval)
+	}
+}
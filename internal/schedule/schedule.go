@@ -0,0 +1,45 @@
+// Package schedule runs a background job immediately and then on a fixed
+// interval, for the schedulers that drive periodic service work.
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Runner runs a job on a fixed interval
+type Runner struct {
+	job      func(ctx context.Context)
+	interval time.Duration
+}
+
+// New creates a runner for job. A zero interval falls back to
+// defaultInterval.
+func New(job func(ctx context.Context), interval, defaultInterval time.Duration) *Runner {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Runner{job: job, interval: interval}
+}
+
+// Interval returns the time between runs
+func (r *Runner) Interval() time.Duration {
+	return r.interval
+}
+
+// Run runs the job immediately and then every interval until ctx is
+// cancelled
+func (r *Runner) Run(ctx context.Context) {
+	r.job(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.job(ctx)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRun_RunsImmediatelyAndStopsOnCancel(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	r := New(func(context.Context) {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+	}, time.Hour, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not run the job on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestRun_RunsEveryInterval(t *testing.T) {
+	ran := make(chan struct{}, 3)
+	r := New(func(context.Context) {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+	}, time.Millisecond, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	for i := range 3 {
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("job ran %d times, want 3", i)
+		}
+	}
+}
+
+func TestNew_DefaultInterval(t *testing.T) {
+	if got := New(func(context.Context) {}, 0, time.Minute).Interval(); got != time.Minute {
+		t.Errorf("Interval() = %v, want %v", got, time.Minute)
+	}
+	if got := New(func(context.Context) {}, time.Second, time.Minute).Interval(); got != time.Second {
+		t.Errorf("Interval() = %v, want %v", got, time.Second)
+	}
+}
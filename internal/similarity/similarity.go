@@ -0,0 +1,83 @@
+// Package similarity scores how alike two pieces of outage text are, for
+// suggesting past outages related to a new one.
+//
+// TrigramSimilarity follows PostgreSQL's pg_trgm: text is split into
+// lowercase words, each padded with two spaces in front and one behind, and
+// the score is the share of distinct three-character sequences the texts
+// have in common. It tolerates typos and word forms ("timeout", "timeouts")
+// and suits short text such as titles. WordOverlap compares the sets of
+// significant words instead, ignoring common English stop words, much like
+// matching tsvectors, and suits longer descriptions.
+package similarity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are left out of word comparisons
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "from": true, "has": true,
+	"have": true, "in": true, "is": true, "it": true, "its": true, "not": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "this": true,
+	"to": true, "was": true, "were": true, "with": true,
+}
+
+// words splits text into lowercase runs of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams returns the distinct trigrams of text's padded words
+func trigrams(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range words(text) {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// TrigramSimilarity returns the share of distinct trigrams a and b have in
+// common, from 0 for nothing in common to 1 for the same words
+func TrigramSimilarity(a, b string) float64 {
+	return jaccard(trigrams(a), trigrams(b))
+}
+
+// WordOverlap returns the share of distinct significant words a and b have
+// in common, from 0 to 1
+func WordOverlap(a, b string) float64 {
+	return jaccard(significantWords(a), significantWords(b))
+}
+
+// significantWords returns the distinct words of text that are not stop
+// words
+func significantWords(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range words(text) {
+		if !stopWords[w] {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+// jaccard returns the size of the intersection of two sets over the size of
+// their union, or 0 when both are empty
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,47 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrigramSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"word", "word", 1},
+		{"Checkout API down", "checkout api DOWN!", 1},
+		{"cat", "dog", 0},
+		{"", "", 0},
+		// pg_trgm: similarity('word', 'words') = 0.5714286
+		{"word", "words", 4.0 / 7},
+	}
+	for _, tt := range tests {
+		if got := TrigramSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("TrigramSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	related := TrigramSimilarity("Checkout API timeouts", "Checkout API timing out")
+	unrelated := TrigramSimilarity("Checkout API timeouts", "Search index rebuild stuck")
+	if related <= unrelated || related < 0.3 {
+		t.Errorf("related titles score %v, unrelated %v", related, unrelated)
+	}
+}
+
+func TestWordOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"The database is down", "database down", 1},
+		{"disk full on db-1", "db-1 disk latency", 3.0 / 5}, // disk, db and 1 of disk, full, db, 1 and latency
+		{"of the", "and a", 0},
+	}
+	for _, tt := range tests {
+		if got := WordOverlap(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("WordOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
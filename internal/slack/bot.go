@@ -6,12 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/integrations/github"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/webhook"
 	"github.com/conall/outalator/service"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -22,6 +29,35 @@ type Bot struct {
 	service       *service.Service
 	client        *Client
 	reactionEmoji string // The emoji used to tag messages for note creation
+	issues        IssueCreator
+	archive       bool // Archive messages in bound channels as notes
+	// updateReminderChannel receives overdue update reminders for outages
+	// with neither a team channel nor a bound channel
+	updateReminderChannel string
+	sourceAlarmChannel    string // Receives stale alert source alarms
+	// events processes Event API deliveries on a bounded worker pool once
+	// they have been acknowledged
+	events *webhook.Queue
+	// eventTTL is how long processed event IDs are remembered, so that
+	// redelivered events are ignored
+	eventTTL time.Duration
+	// imports serializes adding Slack messages as notes, so a message
+	// delivered or reacted to twice at once is added once
+	imports sync.Mutex
+	logger  *slog.Logger
+}
+
+// validSeverities lists the severities accepted when creating an outage
+var validSeverities = map[string]bool{
+	"critical": true,
+	"high":     true,
+	"medium":   true,
+	"low":      true,
+}
+
+// IssueCreator pushes action-item notes to an issue tracker
+type IssueCreator interface {
+	CreateIssue(ctx context.Context, noteID uuid.UUID) (*github.Issue, error)
 }
 
 // Config holds Slack bot configuration
@@ -29,32 +65,88 @@ type Config struct {
 	SigningSecret string
 	BotToken      string
 	ReactionEmoji string // e.g., "outage_note" for :outage_note:
+	// Issues enables the "issue" command. Optional.
+	Issues IssueCreator
+	// ArchiveChannelMessages adds messages posted in a channel bound to an
+	// open outage to that outage as notes
+	ArchiveChannelMessages bool
+	// UpdateReminderChannel receives overdue status update reminders for
+	// outages with neither a team channel nor a bound channel. Optional.
+	UpdateReminderChannel string
+	// SourceAlarmChannel receives alarms for alert sources that stopped
+	// delivering. Optional.
+	SourceAlarmChannel string
+	// EventWorkers is how many events are processed concurrently, default 4
+	EventWorkers int
+	// EventQueueSize is how many events are buffered before deliveries are
+	// refused for Slack to retry, default 1000
+	EventQueueSize int
+	// EventTTL is how long processed event IDs are remembered to ignore
+	// Slack's retries of events already processed, default 24h
+	EventTTL time.Duration
 }
 
+// defaultEventTTL comfortably covers Slack's retries, the last of which
+// comes about five minutes after the first delivery
+const defaultEventTTL = 24 * time.Hour
+
 // NewBot creates a new Slack bot instance
-func NewBot(svc *service.Service, cfg Config) *Bot {
+func NewBot(svc *service.Service, cfg Config, logger *slog.Logger) *Bot {
 	client := NewClient(cfg.BotToken, cfg.SigningSecret)
-	return &Bot{
+	b := &Bot{
 		service:       svc,
 		client:        client,
 		reactionEmoji: cfg.ReactionEmoji,
-	}
+		issues:        cfg.Issues,
+		archive:       cfg.ArchiveChannelMessages,
+		eventTTL:      cfg.EventTTL,
+		logger:        logger,
+
+		updateReminderChannel: cfg.UpdateReminderChannel,
+		sourceAlarmChannel:    cfg.SourceAlarmChannel,
+	}
+	if b.eventTTL <= 0 {
+		b.eventTTL = defaultEventTTL
+	}
+	b.events = webhook.NewQueue(webhook.Config{
+		Workers:   cfg.EventWorkers,
+		QueueSize: cfg.EventQueueSize,
+	}, b.processJob, logger)
+	return b
+}
+
+// Start starts the workers that process Event API deliveries. Call it
+// before serving requests.
+func (b *Bot) Start(ctx context.Context) error {
+	return b.events.Start(ctx)
+}
+
+// Stop stops accepting events and waits for those already accepted to be
+// processed, or for ctx to expire, at which point the contexts of events
+// still being processed are cancelled. Call it after the HTTP server has
+// shut down, so no more events arrive.
+func (b *Bot) Stop(ctx context.Context) {
+	b.events.Stop(ctx)
 }
 
 // SlackEvent represents a Slack event
 type SlackEvent struct {
 	Type      string          `json:"type"`
 	Challenge string          `json:"challenge,omitempty"` // For URL verification
+	EventID   string          `json:"event_id,omitempty"`  // Unique per event, kept across retries
 	Event     json.RawMessage `json:"event,omitempty"`
 }
 
 // MessageEvent represents a Slack message event
 type MessageEvent struct {
-	Type    string `json:"type"`
-	User    string `json:"user"`
-	Text    string `json:"text"`
-	Channel string `json:"channel"`
-	TS      string `json:"ts"` // Timestamp, used as message ID
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype,omitempty"` // Set for edits, joins, bot posts, etc.
+	User     string `json:"user"`
+	BotID    string `json:"bot_id,omitempty"`
+	Text     string `json:"text"`
+	Channel  string `json:"channel"`
+	TS       string `json:"ts"`                  // Timestamp, used as message ID
+	ThreadTS string `json:"thread_ts,omitempty"` // Timestamp of the thread's first message, for replies
 }
 
 // ReactionAddedEvent represents a Slack reaction_added event
@@ -74,6 +166,10 @@ func (b *Bot) HandleEvent(w http.ResponseWriter, r *http.Request) {
 	// Read body for verification
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
+		if bodylimit.TooLarge(err) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
@@ -90,7 +186,7 @@ func (b *Bot) HandleEvent(w http.ResponseWriter, r *http.Request) {
 
 	var event SlackEvent
 	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
-		log.Printf("Error decoding event: %v", err)
+		b.logger.WarnContext(r.Context(), "failed to decode slack event", "error", err)
 		http.Error(w, "Bad request", http.StatusBadRequest)
 		return
 	}
@@ -104,25 +200,57 @@ func (b *Bot) HandleEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Process event asynchronously
-	go b.processEvent(event)
+	// Slack retries deliveries not acknowledged within 3 seconds, or
+	// refused, with the same event ID
+	if retry := r.Header.Get("X-Slack-Retry-Num"); retry != "" {
+		b.logger.InfoContext(r.Context(), "received slack event retry", "event_id", event.EventID, "retry", retry, "reason", r.Header.Get("X-Slack-Retry-Reason"))
+	}
+
+	// Acknowledge now and process the event on the worker pool, which keeps
+	// the request ID for logging. Slack retries deliveries refused with a
+	// 5xx, so a full or stopping queue loses nothing.
+	job := webhook.Job{Source: "slack", Payload: bodyBytes, ReceivedAt: time.Now(), RequestID: logging.RequestID(r.Context())}
+	if err := b.events.Enqueue(job); err != nil {
+		b.logger.WarnContext(r.Context(), "refusing slack event", "error", err)
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// processJob decodes and processes a queued Event API delivery, skipping
+// events already processed
+func (b *Bot) processJob(ctx context.Context, job webhook.Job) error {
+	var event SlackEvent
+	if err := json.Unmarshal(job.Payload, &event); err != nil {
+		return fmt.Errorf("failed to decode slack event: %w", err)
+	}
+	if event.EventID != "" {
+		claimed, err := b.service.ClaimEvent(ctx, "slack", event.EventID, b.eventTTL)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			b.logger.DebugContext(ctx, "skipping slack event already processed", "event_id", event.EventID)
+			return nil
+		}
+	}
+	b.processEvent(ctx, event)
+	return nil
+}
+
 // processEvent handles different types of Slack events
-func (b *Bot) processEvent(event SlackEvent) {
+func (b *Bot) processEvent(ctx context.Context, event SlackEvent) {
 	var eventType struct {
 		Type string `json:"type"`
 	}
 
 	if err := json.Unmarshal(event.Event, &eventType); err != nil {
-		log.Printf("Error parsing event type: %v", err)
+		b.logger.WarnContext(ctx, "failed to parse slack event type", "error", err)
 		return
 	}
 
-	ctx := context.Background()
-
 	switch eventType.Type {
 	case "message":
 		b.handleMessage(ctx, event.Event)
@@ -135,28 +263,48 @@ func (b *Bot) processEvent(event SlackEvent) {
 func (b *Bot) handleMessage(ctx context.Context, eventData json.RawMessage) {
 	var msg MessageEvent
 	if err := json.Unmarshal(eventData, &msg); err != nil {
-		log.Printf("Error parsing message event: %v", err)
+		b.logger.WarnContext(ctx, "failed to parse slack message event", "error", err)
 		return
 	}
 
-	// Ignore bot messages
-	if msg.User == "" {
+	// Ignore bot messages, including the bot's own updates, and message
+	// edits and other subtypes
+	if msg.User == "" || msg.BotID != "" || msg.Subtype != "" {
 		return
 	}
+	ctx = logging.WithUser(ctx, msg.User)
 
 	// Parse outage note command
 	// Format: "note <outage_id> <content>"
 	if strings.HasPrefix(msg.Text, "note ") {
-		b.handleNoteCommand(ctx, msg)
+		b.handleNoteCommand(b.actingAs(ctx, msg.User), msg)
 		return
 	}
 
 	// Parse create outage command
 	// Format: "outage <title> | <description> | <severity>"
 	if strings.HasPrefix(msg.Text, "outage ") {
-		b.handleOutageCommand(ctx, msg)
+		b.handleOutageCommand(b.actingAs(ctx, msg.User), msg)
 		return
 	}
+
+	// Parse action item command
+	// Format: "action <outage_id> <content>"
+	if strings.HasPrefix(msg.Text, "action ") {
+		b.handleActionCommand(b.actingAs(ctx, msg.User), msg)
+		return
+	}
+
+	// Parse issue command
+	// Format: "issue <note_id>"
+	if strings.HasPrefix(msg.Text, "issue ") {
+		b.handleIssueCommand(b.actingAs(ctx, msg.User), msg)
+		return
+	}
+
+	if b.archive {
+		b.archiveMessage(ctx, msg)
+	}
 }
 
 // handleNoteCommand processes the "note" command
@@ -167,7 +315,7 @@ func (b *Bot) handleNoteCommand(ctx context.Context, msg MessageEvent) {
 
 	if len(matches) != 3 {
 		if err := b.sendMessage(msg.Channel, "Invalid format. Use: `note <outage_id> <content>`"); err != nil {
-			log.Printf("slack: failed to send message: %v", err)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
 		}
 		return
 	}
@@ -175,7 +323,7 @@ func (b *Bot) handleNoteCommand(ctx context.Context, msg MessageEvent) {
 	outageID, err := uuid.Parse(matches[1])
 	if err != nil {
 		if sendErr := b.sendMessage(msg.Channel, fmt.Sprintf("Invalid outage ID: %v", err)); sendErr != nil {
-			log.Printf("slack: failed to send message: %v", sendErr)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
 		}
 		return
 	}
@@ -183,24 +331,103 @@ func (b *Bot) handleNoteCommand(ctx context.Context, msg MessageEvent) {
 	content := matches[2]
 
 	// Get user info for author
-	author := b.getUserName(msg.User)
+	author := b.getUserName(ctx, msg.User)
 
 	req := domain.AddNoteRequest{
-		Content: content,
-		Format:  "plaintext",
-		Author:  author,
+		Content:  content,
+		Format:   "plaintext",
+		Author:   author,
+		Metadata: slackNoteMetadata(msg.Channel, msg.TS),
 	}
 
 	note, err := b.service.AddNote(ctx, outageID, req)
 	if err != nil {
 		if sendErr := b.sendMessage(msg.Channel, fmt.Sprintf("Error adding note: %v", err)); sendErr != nil {
-			log.Printf("slack: failed to send message: %v", sendErr)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
 		}
 		return
 	}
 
 	if err := b.sendMessage(msg.Channel, fmt.Sprintf("✅ Added note to outage %s (Note ID: %s)", outageID, note.ID)); err != nil {
-		log.Printf("slack: failed to send message: %v", err)
+		b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
+	}
+}
+
+// handleActionCommand processes the "action" command, which adds a note
+// marked as an action item
+func (b *Bot) handleActionCommand(ctx context.Context, msg MessageEvent) {
+	// Parse: "action <outage_id> <content>"
+	pattern := regexp.MustCompile(`^action\s+([a-fA-F0-9-]+)\s+(.+)$`)
+	matches := pattern.FindStringSubmatch(msg.Text)
+
+	if len(matches) != 3 {
+		if err := b.sendMessage(msg.Channel, "Invalid format. Use: `action <outage_id> <content>`"); err != nil {
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
+		}
+		return
+	}
+
+	outageID, err := uuid.Parse(matches[1])
+	if err != nil {
+		if sendErr := b.sendMessage(msg.Channel, fmt.Sprintf("Invalid outage ID: %v", err)); sendErr != nil {
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
+		}
+		return
+	}
+
+	req := domain.AddNoteRequest{
+		Content:  matches[2],
+		Format:   "plaintext",
+		Author:   b.getUserName(ctx, msg.User),
+		Metadata: slackNoteMetadata(msg.Channel, msg.TS),
+	}
+	req.Metadata[domain.NoteMetadataActionItem] = "true"
+
+	note, err := b.service.AddNote(ctx, outageID, req)
+	if err != nil {
+		if sendErr := b.sendMessage(msg.Channel, fmt.Sprintf("Error adding action item: %v", err)); sendErr != nil {
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
+		}
+		return
+	}
+
+	reply := fmt.Sprintf("✅ Added action item to outage %s (Note ID: %s)", outageID, note.ID)
+	if b.issues != nil {
+		reply += fmt.Sprintf(". Use `issue %s` to open a GitHub issue for it", note.ID)
+	}
+	if err := b.sendMessage(msg.Channel, reply); err != nil {
+		b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
+	}
+}
+
+// handleIssueCommand processes the "issue" command, which pushes an
+// action-item note to the issue tracker
+func (b *Bot) handleIssueCommand(ctx context.Context, msg MessageEvent) {
+	if b.issues == nil {
+		if err := b.sendMessage(msg.Channel, "The GitHub integration is not enabled"); err != nil {
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
+		}
+		return
+	}
+
+	noteID, err := uuid.Parse(strings.TrimSpace(strings.TrimPrefix(msg.Text, "issue ")))
+	if err != nil {
+		if sendErr := b.sendMessage(msg.Channel, "Invalid format. Use: `issue <note_id>`"); sendErr != nil {
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
+		}
+		return
+	}
+
+	issue, err := b.issues.CreateIssue(ctx, noteID)
+	if err != nil {
+		if sendErr := b.sendMessage(msg.Channel, fmt.Sprintf("Error creating issue: %v", err)); sendErr != nil {
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
+		}
+		return
+	}
+
+	if err := b.sendMessage(msg.Channel, fmt.Sprintf("✅ Created GitHub issue %s: %s", issue.Ref(), issue.URL)); err != nil {
+		b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
 	}
 }
 
@@ -210,7 +437,7 @@ func (b *Bot) handleOutageCommand(ctx context.Context, msg MessageEvent) {
 	parts := strings.Split(strings.TrimPrefix(msg.Text, "outage "), "|")
 	if len(parts) != 3 {
 		if err := b.sendMessage(msg.Channel, "Invalid format. Use: `outage <title> | <description> | <severity>`"); err != nil {
-			log.Printf("slack: failed to send message: %v", err)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
 		}
 		return
 	}
@@ -219,17 +446,9 @@ func (b *Bot) handleOutageCommand(ctx context.Context, msg MessageEvent) {
 	description := strings.TrimSpace(parts[1])
 	severity := strings.TrimSpace(parts[2])
 
-	// Validate severity
-	validSeverities := map[string]bool{
-		"critical": true,
-		"high":     true,
-		"medium":   true,
-		"low":      true,
-	}
-
 	if !validSeverities[severity] {
 		if err := b.sendMessage(msg.Channel, "Invalid severity. Use: critical, high, medium, or low"); err != nil {
-			log.Printf("slack: failed to send message: %v", err)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
 		}
 		return
 	}
@@ -239,7 +458,7 @@ func (b *Bot) handleOutageCommand(ctx context.Context, msg MessageEvent) {
 		Description: description,
 		Severity:    severity,
 		Tags: []domain.TagInput{
-			{Key: "slack_channel", Value: msg.Channel},
+			{Key: channelTagKey, Value: msg.Channel},
 			{Key: "slack_user", Value: msg.User},
 		},
 	}
@@ -247,13 +466,13 @@ func (b *Bot) handleOutageCommand(ctx context.Context, msg MessageEvent) {
 	outage, err := b.service.CreateOutage(ctx, req)
 	if err != nil {
 		if sendErr := b.sendMessage(msg.Channel, fmt.Sprintf("Error creating outage: %v", err)); sendErr != nil {
-			log.Printf("slack: failed to send message: %v", sendErr)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
 		}
 		return
 	}
 
 	if err := b.sendMessage(msg.Channel, fmt.Sprintf("✅ Created outage: %s (ID: %s, Severity: %s)", outage.Title, outage.ID, outage.Severity)); err != nil {
-		log.Printf("slack: failed to send message: %v", err)
+		b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
 	}
 }
 
@@ -261,65 +480,53 @@ func (b *Bot) handleOutageCommand(ctx context.Context, msg MessageEvent) {
 func (b *Bot) handleReactionAdded(ctx context.Context, eventData json.RawMessage) {
 	var reaction ReactionAddedEvent
 	if err := json.Unmarshal(eventData, &reaction); err != nil {
-		log.Printf("Error parsing reaction event: %v", err)
+		b.logger.WarnContext(ctx, "failed to parse slack reaction event", "error", err)
 		return
 	}
+	ctx = logging.WithUser(ctx, reaction.User)
 
 	// Only process if it's the configured emoji
 	if reaction.Reaction != b.reactionEmoji {
 		return
 	}
 
-	// Get the original message
-	messageText, err := b.getMessageText(reaction.Item.Channel, reaction.Item.TS)
+	ctx = b.actingAs(ctx, reaction.User)
+
+	// Fetch the reacted message along with the rest of its thread, if any
+	thread, err := b.client.GetThread(reaction.Item.Channel, reaction.Item.TS)
 	if err != nil {
-		log.Printf("Error getting message text: %v", err)
+		b.logger.ErrorContext(ctx, "failed to fetch reacted slack message", "channel", reaction.Item.Channel, "error", err)
 		return
 	}
 
-	// Try to extract outage ID from the message
-	// Expected format in thread or message: mentions outage ID like "outage abc-123-def"
-	pattern := regexp.MustCompile(`(?i)outage[:\s]+([a-fA-F0-9-]{36})`)
-	matches := pattern.FindStringSubmatch(messageText)
-
-	if len(matches) < 2 {
+	// The outage ID is taken from the reacted message or, in a thread, from
+	// any message in it. Expected format: "outage <outage_id>"
+	outageID, ok := findOutageID(thread, reaction.Item.TS)
+	if !ok {
 		// If no outage ID found in message, send a helpful message
 		if err := b.sendMessage(reaction.Item.Channel, fmt.Sprintf("<@%s> Please include the outage ID in your message. Format: `outage <outage_id>`", reaction.User)); err != nil {
-			log.Printf("slack: failed to send message: %v", err)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", err)
 		}
 		return
 	}
 
-	outageID, err := uuid.Parse(matches[1])
-	if err != nil {
-		log.Printf("Invalid outage ID in message: %v", err)
-		return
-	}
-
-	// Get user info for author
-	author := b.getUserName(reaction.User)
-
-	// Add the message as a note
-	req := domain.AddNoteRequest{
-		Content: messageText,
-		Format:  "plaintext",
-		Author:  author,
-	}
-
-	note, err := b.service.AddNote(ctx, outageID, req)
+	// Add the message, or every message in its thread, as notes
+	b.imports.Lock()
+	added, err := b.importThread(ctx, outageID, reaction.Item.Channel, thread)
+	b.imports.Unlock()
 	if err != nil {
-		log.Printf("Error adding note from reaction: %v", err)
+		b.logger.ErrorContext(ctx, "failed to add notes from reaction", "outage_id", outageID, "added", added, "error", err)
 		if sendErr := b.sendMessage(reaction.Item.Channel, fmt.Sprintf("Error adding note: %v", err)); sendErr != nil {
-			log.Printf("slack: failed to send message: %v", sendErr)
+			b.logger.ErrorContext(ctx, "failed to send slack message", "error", sendErr)
 		}
 		return
 	}
 
 	// React to confirm
 	if err := b.addReaction(reaction.Item.Channel, reaction.Item.TS, "white_check_mark"); err != nil {
-		log.Printf("slack: failed to add reaction: %v", err)
+		b.logger.ErrorContext(ctx, "failed to add slack reaction", "error", err)
 	}
-	log.Printf("Added note %s to outage %s from reaction by %s", note.ID, outageID, author)
+	b.logger.InfoContext(ctx, "added notes from reaction", "outage_id", outageID, "notes", added, "thread_messages", len(thread))
 }
 
 // Utility methods for Slack API interactions
@@ -335,10 +542,41 @@ func (b *Bot) sendMessage(channel, text string) error {
 	return nil
 }
 
-func (b *Bot) getUserName(userID string) string {
+func (b *Bot) sendThreadReply(channel, threadTS, text string) error {
+	resp, err := b.client.PostThreadReply(channel, threadTS, text)
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("slack error: %s", resp.Error)
+	}
+	return nil
+}
+
+// actingAs returns a context acting as the Slack user, so the service
+// checks they may change the outages their commands touch. Users are
+// identified by the email address of their Slack profile; without one they
+// may only change outages no team restricts.
+func (b *Bot) actingAs(ctx context.Context, userID string) context.Context {
+	user := &auth.UserInfo{Sub: "slack:" + userID, Name: userID}
+	profile, err := b.client.GetUserInfo(userID)
+	if err != nil {
+		b.logger.WarnContext(ctx, "failed to fetch slack user info", "slack_user", userID, "error", err)
+		return auth.WithUser(ctx, user)
+	}
+	user.Email = profile.Profile.Email
+	if profile.RealName != "" {
+		user.Name = profile.RealName
+	} else if profile.Name != "" {
+		user.Name = profile.Name
+	}
+	return auth.WithUser(ctx, user)
+}
+
+func (b *Bot) getUserName(ctx context.Context, userID string) string {
 	user, err := b.client.GetUserInfo(userID)
 	if err != nil {
-		log.Printf("Error getting user info: %v", err)
+		b.logger.WarnContext(ctx, "failed to fetch slack user info", "slack_user", userID, "error", err)
 		return userID
 	}
 	if user.RealName != "" {
@@ -347,10 +585,6 @@ func (b *Bot) getUserName(userID string) string {
 	return user.Name
 }
 
-func (b *Bot) getMessageText(channel, timestamp string) (string, error) {
-	return b.client.GetMessageText(channel, timestamp)
-}
-
 func (b *Bot) addReaction(channel, timestamp, emoji string) error {
 	return b.client.AddReaction(channel, timestamp, emoji)
 }
@@ -361,4 +595,6 @@ func (b *Bot) RegisterHandlers(router interface {
 	HandleFunc(path string, f func(http.ResponseWriter, *http.Request)) *mux.Route
 }) {
 	router.HandleFunc("/slack/events", b.HandleEvent)
+	router.HandleFunc("/slack/commands", b.HandleCommand)
+	router.HandleFunc("/slack/interactions", b.HandleInteraction)
 }
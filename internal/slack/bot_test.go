@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// deliverEvent posts a signed Event API delivery to the bot
+func deliverEvent(t *testing.T, b *Bot, event map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	b.HandleEvent(rec, signedRequest("/slack/events", string(body), testSigningSecret, time.Now()))
+	return rec
+}
+
+// noteEvent is a message event running the "note" command
+func noteEvent(eventID, outageID, text string) map[string]any {
+	return map[string]any{
+		"type":     "event_callback",
+		"event_id": eventID,
+		"event": map[string]any{
+			"type": "message", "user": "U1", "channel": "C1", "ts": "1700000000." + eventID,
+			"text": fmt.Sprintf("note %s %s", outageID, text),
+		},
+	}
+}
+
+func TestHandleEventURLVerification(t *testing.T) {
+	b, _ := newTestBot(t, Config{})
+	rec := deliverEvent(t, b, map[string]any{"type": "url_verification", "challenge": "abc"})
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp["challenge"] != "abc" {
+		t.Errorf("response = %q, want the challenge echoed", rec.Body.String())
+	}
+}
+
+func TestStopDrainsEvents(t *testing.T) {
+	b, fake := newTestBot(t, Config{EventWorkers: 1})
+	ctx := context.Background()
+	outage, err := b.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold the worker up so the deliveries are still queued when Stop is
+	// called. Slack's retry of the first event is acknowledged but only
+	// processed once.
+	fake.gate = make(chan struct{})
+	for _, event := range []map[string]any{
+		noteEvent("000001", outage.ID.String(), "first"),
+		noteEvent("000002", outage.ID.String(), "second"),
+		noteEvent("000003", outage.ID.String(), "third"),
+		noteEvent("000001", outage.ID.String(), "first"),
+	} {
+		if rec := deliverEvent(t, b, event); rec.Code != http.StatusOK {
+			t.Fatalf("delivery status = %d, want it acknowledged", rec.Code)
+		}
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(fake.gate)
+	}()
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	b.Stop(stopCtx)
+
+	got, err := b.service.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Notes) != 3 {
+		t.Errorf("after Stop the outage has %d notes, want every accepted event processed once", len(got.Notes))
+	}
+
+	// Deliveries after Stop are refused for Slack to retry
+	if rec := deliverEvent(t, b, noteEvent("000004", outage.ID.String(), "late")); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("delivery after Stop status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
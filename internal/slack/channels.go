@@ -0,0 +1,249 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/render"
+	"github.com/google/uuid"
+)
+
+// channelTagKey is the tag that binds an outage to a Slack channel. Outages
+// created from Slack are bound to the channel they were created in.
+const channelTagKey = "slack_channel"
+
+// Note metadata recording where a note created from Slack came from, so it
+// is not echoed back to the channel it was posted in
+const (
+	noteMetadataSlackChannel = "slack_channel"
+	noteMetadataSlackTS      = "slack_ts"
+)
+
+// boundChannel returns the Slack channel the outage is bound to, if any
+func boundChannel(outage *domain.Outage) string {
+	for _, t := range outage.Tags {
+		if t.Key == channelTagKey {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// slackNoteMetadata returns the metadata for a note created from a message
+// or command in channel
+func slackNoteMetadata(channel, ts string) map[string]string {
+	md := map[string]string{noteMetadataSlackChannel: channel}
+	if ts != "" {
+		md[noteMetadataSlackTS] = ts
+	}
+	return md
+}
+
+// NoteAdded implements service.OutageListener by posting the note to the
+// outage's bound channel, unless the note came from that channel. Replies to
+// notes imported from the channel are posted in the Slack thread.
+func (b *Bot) NoteAdded(ctx context.Context, outage *domain.Outage, note *domain.Note) error {
+	channel := boundChannel(outage)
+	if channel == "" || note.Metadata[noteMetadataSlackChannel] == channel {
+		return nil
+	}
+	label := "a note"
+	if note.IsActionItem() {
+		label = "an action item"
+	}
+	var body string
+	switch note.Format {
+	case domain.NoteFormatLog:
+		body = codeBlock(note.Content)
+	case domain.NoteFormatMarkdown:
+		body = quote(render.Markdown(note.Content).Slack)
+	default:
+		body = quote(note.Content)
+	}
+	text := fmt.Sprintf("📝 %s added %s to *%s*:\n%s", note.Author, label, outage.Title, body)
+	if threadTS := slackThreadOf(outage, note, channel); threadTS != "" {
+		return b.sendThreadReply(channel, threadTS, text)
+	}
+	return b.sendMessage(channel, text)
+}
+
+// slackThreadOf returns the timestamp of the Slack message in channel that
+// note's parent was imported from, or "" when there is none
+func slackThreadOf(outage *domain.Outage, note *domain.Note, channel string) string {
+	if note.ParentNoteID == nil {
+		return ""
+	}
+	for _, n := range outage.Notes {
+		if n.ID == *note.ParentNoteID && n.Metadata[noteMetadataSlackChannel] == channel {
+			return n.Metadata[noteMetadataSlackTS]
+		}
+	}
+	return ""
+}
+
+// AlertAdded implements service.OutageListener by posting the alert to the
+// outage's bound channel, with the runbook of the service it is about
+func (b *Bot) AlertAdded(ctx context.Context, outage *domain.Outage, alert *domain.Alert) error {
+	channel := boundChannel(outage)
+	if channel == "" {
+		return nil
+	}
+	text := fmt.Sprintf("🚨 New %s alert on *%s*: %s (severity: %s)", alert.Source, outage.Title, alert.Title, alert.Severity)
+	if alert.Service != "" {
+		text += b.runbookLinks(ctx, []string{alert.Service})
+	}
+	return b.sendMessage(channel, text)
+}
+
+// runbookLinks returns a line linking the runbooks of the named catalogued
+// services, or "" when none of them has one
+func (b *Bot) runbookLinks(ctx context.Context, services []string) string {
+	var links []string
+	for _, name := range services {
+		svc, err := b.service.GetService(ctx, name)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				b.logger.WarnContext(ctx, "failed to get service for runbook link", "service", name, "error", err)
+			}
+			continue
+		}
+		if svc.RunbookURL != "" {
+			links = append(links, fmt.Sprintf("<%s|%s>", svc.RunbookURL, svc.Name))
+		}
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	return "\n📖 Runbooks: " + strings.Join(links, ", ")
+}
+
+// OutageStatusChanged implements service.OutageListener by posting the new
+// status to the outage's bound channel
+func (b *Bot) OutageStatusChanged(ctx context.Context, outage *domain.Outage, previous string) error {
+	channel := boundChannel(outage)
+	if channel == "" {
+		return nil
+	}
+	emoji := "🔄"
+	if outage.Status == "resolved" || outage.Status == "closed" {
+		emoji = "✅"
+	}
+	text := fmt.Sprintf("%s *%s* is now *%s* (was %s)", emoji, outage.Title, outage.Status, previous)
+	return b.sendMessage(channel, text)
+}
+
+// OutageCreated implements service.OutageListener. A new outage has no bound
+// channel yet.
+func (b *Bot) OutageCreated(context.Context, *domain.Outage) error {
+	return nil
+}
+
+// OutageResolved implements service.OutageListener. Resolution is already
+// posted by OutageStatusChanged.
+func (b *Bot) OutageResolved(context.Context, *domain.Outage) error {
+	return nil
+}
+
+// bindChannel binds the outage to channel, replacing any existing binding
+func (b *Bot) bindChannel(ctx context.Context, outageID uuid.UUID, channel string) (*domain.Outage, error) {
+	outage, err := b.unbindChannel(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := b.service.AddTag(ctx, outageID, channelTagKey, channel); err != nil {
+		return nil, err
+	}
+	return outage, nil
+}
+
+// unbindChannel removes the outage's channel binding, if any
+func (b *Bot) unbindChannel(ctx context.Context, outageID uuid.UUID) (*domain.Outage, error) {
+	outage, err := b.service.GetOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range outage.Tags {
+		if t.Key == channelTagKey {
+			if err := b.service.DeleteTag(ctx, t.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return outage, nil
+}
+
+// archiveMessage adds a channel message as a note to every open outage bound
+// to the channel, unless it was added before. A thread reply is added as a
+// reply to the note archived from the thread's first message, when there is
+// one.
+func (b *Bot) archiveMessage(ctx context.Context, msg MessageEvent) {
+	outages, err := b.service.FindOutagesByTag(ctx, channelTagKey, msg.Channel)
+	if err != nil {
+		b.logger.WarnContext(ctx, "failed to find outages bound to slack channel", "channel", msg.Channel, "error", err)
+		return
+	}
+
+	b.imports.Lock()
+	defer b.imports.Unlock()
+
+	author := ""
+	for _, outage := range outages {
+		if outage.Status == "resolved" || outage.Status == "closed" {
+			continue
+		}
+		archived, err := b.archivedNoteIDs(ctx, outage.ID, msg.Channel)
+		if err != nil {
+			b.logger.WarnContext(ctx, "failed to load outage for slack message", "outage_id", outage.ID, "error", err)
+			continue
+		}
+		if _, ok := archived[msg.TS]; ok {
+			continue
+		}
+		if author == "" {
+			author = b.getUserName(ctx, msg.User)
+		}
+		req := domain.AddNoteRequest{
+			Content:  msg.Text,
+			Format:   "plaintext",
+			Author:   author,
+			Metadata: slackNoteMetadata(msg.Channel, msg.TS),
+		}
+		if msg.ThreadTS != "" && msg.ThreadTS != msg.TS {
+			req.Metadata[noteMetadataSlackThreadTS] = msg.ThreadTS
+			if root, ok := archived[msg.ThreadTS]; ok {
+				req.ParentNoteID = &root
+			}
+		}
+		_, err = b.service.AddNote(ctx, outage.ID, req)
+		if err != nil {
+			b.logger.WarnContext(ctx, "failed to archive slack message", "outage_id", outage.ID, "channel", msg.Channel, "error", err)
+		}
+	}
+}
+
+// archivedNoteIDs maps the timestamps of messages archived from channel to
+// the outage to the IDs of their notes
+func (b *Bot) archivedNoteIDs(ctx context.Context, outageID uuid.UUID, channel string) (map[string]uuid.UUID, error) {
+	outage, err := b.service.GetOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	return slackNoteIDs(outage.Notes, channel), nil
+}
+
+// quote formats text as a Slack block quote
+func quote(text string) string {
+	return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+}
+
+// maxCodeBlockRunes caps how much of a log note is posted to Slack
+const maxCodeBlockRunes = 3000
+
+// codeBlock formats text as a Slack code block, keeping its line breaks and
+// indentation. Long text is cut short; the full note stays in Outalator.
+func codeBlock(text string) string {
+	return "```\n" + truncate(strings.TrimRight(text, "\n"), maxCodeBlockRunes) + "\n```"
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
@@ -21,6 +22,7 @@ const (
 type Client struct {
 	botToken      string
 	signingSecret string
+	baseURL       string // Slack Web API URL, overridden in tests
 	httpClient    *http.Client
 }
 
@@ -29,6 +31,7 @@ func NewClient(botToken, signingSecret string) *Client {
 	return &Client{
 		botToken:      botToken,
 		signingSecret: signingSecret,
+		baseURL:       slackAPIBaseURL,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -40,6 +43,9 @@ type UserInfo struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	RealName string `json:"real_name"`
+	Profile  struct {
+		Email string `json:"email"` // Only set with the users:read.email scope
+	} `json:"profile"`
 }
 
 // MessageResponse represents a Slack API message response
@@ -62,6 +68,27 @@ type ConversationHistoryResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// ThreadMessage is a message returned by conversations.replies
+type ThreadMessage struct {
+	User     string `json:"user"`
+	BotID    string `json:"bot_id,omitempty"`
+	Username string `json:"username,omitempty"` // Display name of bot messages
+	Text     string `json:"text"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+}
+
+// ConversationRepliesResponse represents the response from conversations.replies
+type ConversationRepliesResponse struct {
+	OK               bool            `json:"ok"`
+	Messages         []ThreadMessage `json:"messages"`
+	HasMore          bool            `json:"has_more"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+	Error string `json:"error,omitempty"`
+}
+
 // UserInfoResponse represents the response from users.info
 type UserInfoResponse struct {
 	OK    bool `json:"ok"`
@@ -107,6 +134,18 @@ func (c *Client) PostMessage(channel, text string) (*MessageResponse, error) {
 	return c.postJSON("chat.postMessage", payload)
 }
 
+// PostThreadReply sends a message as a reply in the thread started by the
+// message at threadTS
+func (c *Client) PostThreadReply(channel, threadTS, text string) (*MessageResponse, error) {
+	payload := map[string]interface{}{
+		"channel":   channel,
+		"thread_ts": threadTS,
+		"text":      text,
+	}
+
+	return c.postJSON("chat.postMessage", payload)
+}
+
 // PostMessageWithBlocks sends a message with blocks to a Slack channel
 func (c *Client) PostMessageWithBlocks(channel, text string, blocks interface{}) (*MessageResponse, error) {
 	payload := map[string]interface{}{
@@ -118,6 +157,26 @@ func (c *Client) PostMessageWithBlocks(channel, text string, blocks interface{})
 	return c.postJSON("chat.postMessage", payload)
 }
 
+// OpenView opens a modal view in response to a shortcut or slash command.
+// triggerID expires three seconds after the triggering interaction.
+func (c *Client) OpenView(triggerID string, view interface{}) error {
+	payload := map[string]interface{}{
+		"trigger_id": triggerID,
+		"view":       view,
+	}
+
+	resp, err := c.postJSON("views.open", payload)
+	if err != nil {
+		return err
+	}
+
+	if !resp.OK {
+		return fmt.Errorf("slack API error: %s", resp.Error)
+	}
+
+	return nil
+}
+
 // AddReaction adds an emoji reaction to a message
 func (c *Client) AddReaction(channel, timestamp, emoji string) error {
 	payload := map[string]interface{}{
@@ -140,7 +199,36 @@ func (c *Client) AddReaction(channel, timestamp, emoji string) error {
 
 // GetUserInfo retrieves information about a user
 func (c *Client) GetUserInfo(userID string) (*UserInfo, error) {
-	url := fmt.Sprintf("%s/users.info?user=%s", slackAPIBaseURL, userID)
+	url := fmt.Sprintf("%s/users.info?user=%s", c.baseURL, userID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.botToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var userResp UserInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&userResp); err != nil {
+		return nil, err
+	}
+
+	if !userResp.OK {
+		return nil, fmt.Errorf("slack API error: %s", userResp.Error)
+	}
+
+	return &userResp.User, nil
+}
+
+// LookupUserByEmail finds the Slack user with the given email address
+func (c *Client) LookupUserByEmail(email string) (*UserInfo, error) {
+	url := fmt.Sprintf("%s/users.lookupByEmail?email=%s", c.baseURL, url.QueryEscape(email))
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -170,7 +258,7 @@ func (c *Client) GetUserInfo(userID string) (*UserInfo, error) {
 // GetMessageText retrieves the text of a specific message
 func (c *Client) GetMessageText(channel, timestamp string) (string, error) {
 	url := fmt.Sprintf("%s/conversations.history?channel=%s&latest=%s&limit=1&inclusive=true",
-		slackAPIBaseURL, channel, timestamp)
+		c.baseURL, channel, timestamp)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -201,6 +289,62 @@ func (c *Client) GetMessageText(channel, timestamp string) (string, error) {
 	return histResp.Messages[0].Text, nil
 }
 
+// GetThread retrieves every message in the thread containing the message at
+// timestamp, parent first. A message that is not part of a thread is
+// returned on its own.
+func (c *Client) GetThread(channel, timestamp string) ([]ThreadMessage, error) {
+	messages, err := c.getReplies(channel, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("message not found")
+	}
+	// Asked for a reply, Slack returns only that reply, so fetch the thread
+	// from its parent
+	if parent := messages[0].ThreadTS; parent != "" && parent != messages[0].TS {
+		return c.getReplies(channel, parent)
+	}
+	return messages, nil
+}
+
+// getReplies calls conversations.replies, following pagination
+func (c *Client) getReplies(channel, timestamp string) ([]ThreadMessage, error) {
+	var messages []ThreadMessage
+	cursor := ""
+	for {
+		params := url.Values{"channel": {channel}, "ts": {timestamp}, "limit": {"200"}}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+		req, err := http.NewRequest("GET", c.baseURL+"/conversations.replies?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.botToken)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var repliesResp ConversationRepliesResponse
+		err = json.NewDecoder(resp.Body).Decode(&repliesResp)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if !repliesResp.OK {
+			return nil, fmt.Errorf("slack API error: %s", repliesResp.Error)
+		}
+
+		messages = append(messages, repliesResp.Messages...)
+		cursor = repliesResp.ResponseMetadata.NextCursor
+		if !repliesResp.HasMore || cursor == "" {
+			return messages, nil
+		}
+	}
+}
+
 // postJSON is a helper to make POST requests with JSON payload
 func (c *Client) postJSON(endpoint string, payload map[string]interface{}) (*MessageResponse, error) {
 	jsonData, err := json.Marshal(payload)
@@ -208,7 +352,7 @@ func (c *Client) postJSON(endpoint string, payload map[string]interface{}) (*Mes
 		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/%s", slackAPIBaseURL, endpoint)
+	url := fmt.Sprintf("%s/%s", c.baseURL, endpoint)
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, err
@@ -0,0 +1,469 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/google/uuid"
+)
+
+// Slash command response types
+const (
+	responseEphemeral = "ephemeral" // Only visible to the user who ran the command
+	responseInChannel = "in_channel"
+)
+
+// maxListedOutages caps the outages shown by "/outage list"
+const maxListedOutages = 10
+
+const outageUsage = "Usage:\n" +
+	"• `/outage create` to open the outage form\n" +
+	"• `/outage create <title> | <description> | <severity>`\n" +
+	"• `/outage template <name> [variable=value ...]` to open an outage from a template, or `/outage template` to list them\n" +
+	"• `/outage list`\n" +
+	"• `/outage resolve <outage_id> [alerts]`, with `alerts` to also resolve its PagerDuty and OpsGenie alerts\n" +
+	"• `/outage who <outage_id>` to see who is viewing or working the outage\n" +
+	"• `/outage assign <outage_id> <role> <@user|email>` to assign an incident_commander, comms_lead or scribe\n" +
+	"• `/outage assign <outage_id> <role> oncall <source> <schedule>` to assign whoever is on call\n" +
+	"• `/outage oncall [source] [schedule]` to see who is on call\n" +
+	"• `/outage watch <outage_id> [email]` to be sent its status changes and new notes by direct message, or by email with `email`\n" +
+	"• `/outage unwatch <outage_id>`\n" +
+	"• `/outage bind <outage_id>` to post the outage's updates to this channel\n" +
+	"• `/outage unbind <outage_id>`"
+
+// SlashCommand is a slash command invocation as posted by Slack
+type SlashCommand struct {
+	Command   string
+	Text      string
+	UserID    string
+	ChannelID string
+	TriggerID string // Allows the command to open a modal
+}
+
+// commandResponse is the JSON reply to a slash command
+type commandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// HandleCommand handles slash commands posted to /slack/commands. Replies
+// are returned in the HTTP response: errors and listings are ephemeral, and
+// changes to outages are posted to the channel.
+func (b *Bot) HandleCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if bodylimit.TooLarge(err) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if !b.client.VerifyRequest(r, body) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	cmd := SlashCommand{
+		Command:   form.Get("command"),
+		Text:      strings.TrimSpace(form.Get("text")),
+		UserID:    form.Get("user_id"),
+		ChannelID: form.Get("channel_id"),
+		TriggerID: form.Get("trigger_id"),
+	}
+
+	ctx := b.actingAs(logging.WithUser(r.Context(), cmd.UserID), cmd.UserID)
+	responseType, text := b.runCommand(ctx, cmd)
+	if text == "" {
+		// Nothing to say, e.g. because a modal was opened instead
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(commandResponse{ResponseType: responseType, Text: text})
+}
+
+// runCommand executes a slash command and returns the reply. An empty
+// text means no reply.
+func (b *Bot) runCommand(ctx context.Context, cmd SlashCommand) (responseType, text string) {
+	switch cmd.Command {
+	case "/outage":
+		sub, args := splitCommand(cmd.Text)
+		switch sub {
+		case "create":
+			return b.slashCreateOutage(ctx, cmd, args)
+		case "template":
+			return b.slashCreateFromTemplate(ctx, cmd, args)
+		case "list":
+			return b.slashListOutages(ctx)
+		case "resolve":
+			return b.slashResolveOutage(ctx, cmd, args)
+		case "bind":
+			return b.slashBindOutage(ctx, cmd, args)
+		case "unbind":
+			return b.slashUnbindOutage(ctx, args)
+		case "who":
+			return b.slashOutagePresence(ctx, args)
+		case "assign":
+			return b.slashAssignResponder(ctx, cmd, args)
+		case "oncall":
+			return b.slashListOnCall(ctx, args)
+		case "watch":
+			return b.slashWatchOutage(ctx, cmd, args)
+		case "unwatch":
+			return b.slashUnwatchOutage(ctx, cmd, args)
+		default:
+			return responseEphemeral, outageUsage
+		}
+	case "/resolve":
+		return b.slashResolveOutage(ctx, cmd, cmd.Text)
+	case "/note":
+		return b.slashAddNote(ctx, cmd)
+	default:
+		return responseEphemeral, fmt.Sprintf("Unknown command `%s`", cmd.Command)
+	}
+}
+
+// slashCreateOutage handles "/outage create <title> | <description> | <severity>".
+// Without arguments it opens the create outage modal.
+func (b *Bot) slashCreateOutage(ctx context.Context, cmd SlashCommand, args string) (string, string) {
+	if args == "" && cmd.TriggerID != "" {
+		if err := b.openCreateOutageModal(ctx, cmd.TriggerID, cmd.ChannelID); err != nil {
+			b.logger.ErrorContext(ctx, "failed to open outage modal", "error", err)
+			return responseEphemeral, "Error opening the outage form"
+		}
+		return responseEphemeral, ""
+	}
+	parts := strings.Split(args, "|")
+	if len(parts) != 3 {
+		return responseEphemeral, "Invalid format. Use: `/outage create <title> | <description> | <severity>`"
+	}
+	title := strings.TrimSpace(parts[0])
+	description := strings.TrimSpace(parts[1])
+	severity := strings.ToLower(strings.TrimSpace(parts[2]))
+	if title == "" {
+		return responseEphemeral, "A title is required"
+	}
+	if !validSeverities[severity] {
+		return responseEphemeral, "Invalid severity. Use: critical, high, medium, or low"
+	}
+
+	outage, err := b.service.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title:       title,
+		Description: description,
+		Severity:    severity,
+		Tags: []domain.TagInput{
+			{Key: channelTagKey, Value: cmd.ChannelID},
+			{Key: "slack_user", Value: cmd.UserID},
+		},
+	})
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error creating outage: %v", err)
+	}
+	return responseInChannel, fmt.Sprintf("🚨 <@%s> opened outage *%s* (ID: `%s`, Severity: %s)", cmd.UserID, outage.Title, outage.ID, outage.Severity)
+}
+
+// slashCreateFromTemplate handles "/outage template <name> [variable=value ...]",
+// opening an outage from a template with its placeholders filled from the
+// variables. Without a name it lists the templates.
+func (b *Bot) slashCreateFromTemplate(ctx context.Context, cmd SlashCommand, args string) (string, string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return b.slashListTemplates(ctx)
+	}
+	name := fields[0]
+	variables := make(map[string]string)
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return responseEphemeral, "Invalid format. Use: `/outage template <name> [variable=value ...]`"
+		}
+		variables[key] = value
+	}
+
+	outage, err := b.service.CreateOutageFromTemplate(ctx, name, domain.CreateOutageRequest{
+		Variables: variables,
+		Tags: []domain.TagInput{
+			{Key: channelTagKey, Value: cmd.ChannelID},
+			{Key: "slack_user", Value: cmd.UserID},
+		},
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		return responseEphemeral, fmt.Sprintf("No template named `%s`. Use `/outage template` to list them.", name)
+	}
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error creating outage: %v", err)
+	}
+	return responseInChannel, fmt.Sprintf("🚨 <@%s> opened outage *%s* from template `%s` (ID: `%s`, Severity: %s)",
+		cmd.UserID, outage.Title, name, outage.ID, outage.Severity)
+}
+
+// slashListTemplates handles "/outage template" without a name
+func (b *Bot) slashListTemplates(ctx context.Context) (string, string) {
+	templates, err := b.service.ListOutageTemplates(ctx)
+	if err != nil {
+		b.logger.ErrorContext(ctx, "failed to list templates for slash command", "error", err)
+		return responseEphemeral, "Error listing templates"
+	}
+	if len(templates) == 0 {
+		return responseEphemeral, "No outage templates are defined"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Outage templates:\n")
+	for _, tmpl := range templates {
+		fmt.Fprintf(&sb, "• `%s`", tmpl.Name)
+		if tmpl.Title != "" {
+			fmt.Fprintf(&sb, " %s", tmpl.Title)
+		}
+		if tmpl.Severity != "" {
+			fmt.Fprintf(&sb, " [%s]", tmpl.Severity)
+		}
+		sb.WriteString("\n")
+	}
+	return responseEphemeral, sb.String()
+}
+
+// slashListOutages handles "/outage list", showing the most recent
+// unresolved outages
+func (b *Bot) slashListOutages(ctx context.Context) (string, string) {
+	outages, err := b.service.ListOutages(ctx, 100, 0)
+	if err != nil {
+		b.logger.ErrorContext(ctx, "failed to list outages for slash command", "error", err)
+		return responseEphemeral, "Error listing outages"
+	}
+
+	var sb strings.Builder
+	listed := 0
+	for _, o := range outages {
+		if o.Status == "resolved" || o.Status == "closed" {
+			continue
+		}
+		if listed == maxListedOutages {
+			sb.WriteString("…and more\n")
+			break
+		}
+		fmt.Fprintf(&sb, "• *%s* [%s, %s] `%s`", o.Title, o.Severity, o.Status, o.ID)
+		if present := b.service.ListPresence(ctx, o.ID); len(present) > 0 {
+			fmt.Fprintf(&sb, " 👀 %d engaged", len(present))
+		}
+		sb.WriteString("\n")
+		listed++
+	}
+	if listed == 0 {
+		return responseEphemeral, "No open outages 🎉"
+	}
+	return responseEphemeral, "Open outages:\n" + sb.String()
+}
+
+// slashResolveOutage handles "/outage resolve <id> [alerts]" and
+// "/resolve <id> [alerts]". With "alerts" the outage's open alerts are
+// resolved with their providers too. In the outage's bound channel the
+// reply is ephemeral, since the status change is posted there anyway.
+func (b *Bot) slashResolveOutage(ctx context.Context, cmd SlashCommand, args string) (string, string) {
+	idArg, option := splitCommand(args)
+	outageID, err := uuid.Parse(idArg)
+	if err != nil || (option != "" && option != "alerts") {
+		return responseEphemeral, "Invalid format. Use: `/outage resolve <outage_id> [alerts]`"
+	}
+
+	status := "resolved"
+	outage, err := b.service.UpdateOutage(ctx, outageID, domain.UpdateOutageRequest{Status: &status, ResolveAlerts: option == "alerts"})
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error resolving outage: %v", err)
+	}
+	if boundChannel(outage) == cmd.ChannelID {
+		return responseEphemeral, fmt.Sprintf("Resolved outage *%s*", outage.Title)
+	}
+	return responseInChannel, fmt.Sprintf("✅ Resolved outage *%s* (ID: `%s`)", outage.Title, outage.ID)
+}
+
+// slashAddNote handles "/note <outage_id> <text>"
+func (b *Bot) slashAddNote(ctx context.Context, cmd SlashCommand) (string, string) {
+	idArg, content := splitCommand(cmd.Text)
+	outageID, err := uuid.Parse(idArg)
+	if err != nil || content == "" {
+		return responseEphemeral, "Invalid format. Use: `/note <outage_id> <text>`"
+	}
+
+	note, err := b.service.AddNote(ctx, outageID, domain.AddNoteRequest{
+		Content:  content,
+		Format:   "plaintext",
+		Author:   b.getUserName(ctx, cmd.UserID),
+		Metadata: slackNoteMetadata(cmd.ChannelID, ""),
+	})
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error adding note: %v", err)
+	}
+	return responseInChannel, fmt.Sprintf("📝 Added note to outage `%s` (Note ID: `%s`)", outageID, note.ID)
+}
+
+// slashBindOutage handles "/outage bind <id>", binding the outage to the
+// channel the command was run in. The reply links the runbooks of the
+// services the outage affects.
+func (b *Bot) slashBindOutage(ctx context.Context, cmd SlashCommand, args string) (string, string) {
+	outageID, err := uuid.Parse(strings.TrimSpace(args))
+	if err != nil {
+		return responseEphemeral, "Invalid format. Use: `/outage bind <outage_id>`"
+	}
+	outage, err := b.bindChannel(ctx, outageID, cmd.ChannelID)
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error binding outage: %v", err)
+	}
+	return responseInChannel, fmt.Sprintf("🔗 <@%s> bound outage *%s* to this channel. Status changes, new alerts and notes will be posted here.", cmd.UserID, outage.Title) +
+		b.runbookLinks(ctx, outage.AffectedServices)
+}
+
+// slashUnbindOutage handles "/outage unbind <id>"
+func (b *Bot) slashUnbindOutage(ctx context.Context, args string) (string, string) {
+	outageID, err := uuid.Parse(strings.TrimSpace(args))
+	if err != nil {
+		return responseEphemeral, "Invalid format. Use: `/outage unbind <outage_id>`"
+	}
+	outage, err := b.unbindChannel(ctx, outageID)
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error unbinding outage: %v", err)
+	}
+	return responseInChannel, fmt.Sprintf("Outage *%s* is no longer bound to a channel", outage.Title)
+}
+
+// slashOutagePresence handles "/outage who <id>", listing the users whose
+// web UI heartbeats show them on the outage
+func (b *Bot) slashOutagePresence(ctx context.Context, args string) (string, string) {
+	outageID, err := uuid.Parse(strings.TrimSpace(args))
+	if err != nil {
+		return responseEphemeral, "Invalid format. Use: `/outage who <outage_id>`"
+	}
+	outage, err := b.service.GetOutage(ctx, outageID)
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error getting outage: %v", err)
+	}
+
+	var sb strings.Builder
+	responders, err := b.service.ListResponders(ctx, outageID)
+	if err != nil {
+		b.logger.WarnContext(ctx, "failed to list responders for slash command", "outage_id", outageID, "error", err)
+	} else if len(responders.Current) > 0 {
+		fmt.Fprintf(&sb, "Responders on outage *%s*:\n", outage.Title)
+		for _, a := range responders.Current {
+			fmt.Fprintf(&sb, "• %s: %s\n", a.Role, a.Responder)
+		}
+	}
+
+	present := b.service.ListPresence(ctx, outageID)
+	if len(present) == 0 {
+		if sb.Len() > 0 {
+			return responseEphemeral, sb.String()
+		}
+		return responseEphemeral, fmt.Sprintf("Nobody is on outage *%s* right now", outage.Title)
+	}
+	fmt.Fprintf(&sb, "Engaged on outage *%s*:\n", outage.Title)
+	for _, p := range present {
+		name := p.User
+		if p.Name != "" {
+			name = fmt.Sprintf("%s (%s)", p.Name, p.User)
+		}
+		fmt.Fprintf(&sb, "• %s, %s\n", name, p.Activity)
+	}
+	return responseEphemeral, sb.String()
+}
+
+// slashAssignResponder handles "/outage assign <id> <role> <responder>",
+// where the responder is a Slack mention, a name or email address, or
+// "oncall <source> <schedule>" for whoever is on call
+func (b *Bot) slashAssignResponder(ctx context.Context, cmd SlashCommand, args string) (string, string) {
+	const usage = "Invalid format. Use: `/outage assign <outage_id> <role> <@user|email>` or `/outage assign <outage_id> <role> oncall <source> <schedule>`"
+	idArg, rest := splitCommand(args)
+	role, responder := splitCommand(rest)
+	outageID, err := uuid.Parse(idArg)
+	if err != nil || role == "" || responder == "" {
+		return responseEphemeral, usage
+	}
+
+	req := domain.AssignResponderRequest{Role: role}
+	if first, schedule := splitCommand(responder); first == "oncall" {
+		req.Source, req.Schedule = splitCommand(schedule)
+		if req.Source == "" || req.Schedule == "" {
+			return responseEphemeral, usage
+		}
+	} else {
+		req.Responder = b.responderName(ctx, responder)
+	}
+
+	assignment, err := b.service.AssignResponder(ctx, outageID, req, b.getUserName(ctx, cmd.UserID))
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error assigning responder: %v", err)
+	}
+	return responseInChannel, fmt.Sprintf("👤 <@%s> assigned %s as %s on outage `%s`", cmd.UserID, assignment.Responder, assignment.Role, outageID)
+}
+
+// responderName resolves a Slack mention such as <@U123|alice> to the
+// user's email address, or their name when Slack does not share emails.
+// Anything else is taken as the responder's name or email as given.
+func (b *Bot) responderName(ctx context.Context, text string) string {
+	if !strings.HasPrefix(text, "<@") || !strings.HasSuffix(text, ">") {
+		return text
+	}
+	userID, _, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(text, "<@"), ">"), "|")
+	user, err := b.client.GetUserInfo(userID)
+	if err != nil {
+		b.logger.WarnContext(ctx, "failed to fetch slack user info", "slack_user", userID, "error", err)
+		return userID
+	}
+	switch {
+	case user.Profile.Email != "":
+		return user.Profile.Email
+	case user.RealName != "":
+		return user.RealName
+	}
+	return user.Name
+}
+
+// slashListOnCall handles "/outage oncall [source] [schedule]"
+func (b *Bot) slashListOnCall(ctx context.Context, args string) (string, string) {
+	source, schedule := splitCommand(args)
+	oncalls, err := b.service.ListOnCall(ctx, source, schedule)
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error looking up on-call: %v", err)
+	}
+	if len(oncalls) == 0 {
+		return responseEphemeral, "Nobody is on call"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("On call:\n")
+	for _, o := range oncalls {
+		schedule := o.ScheduleName
+		if schedule == "" {
+			schedule = o.ScheduleID
+		}
+		name := o.Name
+		if o.Email != "" && o.Email != o.Name {
+			name = fmt.Sprintf("%s (%s)", o.Name, o.Email)
+		}
+		fmt.Fprintf(&sb, "• %s %s: %s\n", o.Source, schedule, name)
+	}
+	return responseEphemeral, sb.String()
+}
+
+// splitCommand splits off the first word of a command's text
+func splitCommand(text string) (first, rest string) {
+	first, rest, _ = strings.Cut(strings.TrimSpace(text), " ")
+	return strings.ToLower(first), strings.TrimSpace(rest)
+}
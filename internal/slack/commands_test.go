@@ -0,0 +1,422 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/service"
+)
+
+const testSigningSecret = "test-signing-secret"
+
+// fakeSlack is a Slack Web API server recording the calls made to it.
+// users.info names every user and their email address after their ID,
+// once gate, if set, is closed, users.lookupByEmail finds every address
+// and conversations.replies returns thread.
+type fakeSlack struct {
+	mu     sync.Mutex
+	calls  map[string][]map[string]any // JSON payloads posted, by method
+	thread []ThreadMessage
+	posted chan string   // Receives the channel of each chat.postMessage
+	gate   chan struct{} // Holds up users.info until closed
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/")
+	w.Header().Set("Content-Type", "application/json")
+	switch method {
+	case "users.info":
+		if f.gate != nil {
+			<-f.gate
+		}
+		user := r.URL.Query().Get("user")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "user": map[string]any{
+			"id": user, "real_name": "Name of " + user, "profile": map[string]any{"email": strings.ToLower(user) + "@example.com"},
+		}})
+		return
+	case "users.lookupByEmail":
+		email := r.URL.Query().Get("email")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "user": map[string]any{"id": "U-" + email}})
+		return
+	case "conversations.replies":
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "messages": f.thread})
+		return
+	}
+
+	var payload map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	f.mu.Lock()
+	f.calls[method] = append(f.calls[method], payload)
+	f.mu.Unlock()
+	if channel, _ := payload["channel"].(string); method == "chat.postMessage" && f.posted != nil {
+		f.posted <- channel
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "ts": "1700000000.000100"})
+}
+
+// called returns the payloads posted to method
+func (f *fakeSlack) called(method string) []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// newTestBot returns a bot backed by in-memory storage that calls fake
+// instead of Slack
+func newTestBot(t *testing.T, cfg Config) (*Bot, *fakeSlack) {
+	t.Helper()
+	fake := &fakeSlack{calls: make(map[string][]map[string]any)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	cfg.SigningSecret = testSigningSecret
+	b := NewBot(service.New(testutil.NewMemStorage(), logging.Discard()), cfg, logging.Discard())
+	b.client.baseURL = srv.URL
+	return b, fake
+}
+
+// signedRequest returns a request to path carrying body, signed with secret
+// at the given time as Slack signs its requests
+func signedRequest(path, body, secret string, at time.Time) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	r.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+// commandForm encodes a slash command as Slack posts it
+func commandForm(command, text string) string {
+	return url.Values{
+		"command":    {command},
+		"text":       {text},
+		"user_id":    {"U1"},
+		"channel_id": {"C1"},
+	}.Encode()
+}
+
+// runSlashCommand posts a signed slash command to the bot and decodes its
+// reply, which is empty when the bot does not reply
+func runSlashCommand(t *testing.T, b *Bot, form string) (int, commandResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	b.HandleCommand(rec, signedRequest("/slack/commands", form, testSigningSecret, time.Now()))
+	var reply commandResponse
+	if rec.Code == http.StatusOK && rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+			t.Fatalf("decoding reply %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code, reply
+}
+
+func TestHandleCommandSignature(t *testing.T) {
+	b, _ := newTestBot(t, Config{})
+	form := commandForm("/outage", "list")
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"signed", signedRequest("/slack/commands", form, testSigningSecret, time.Now()), http.StatusOK},
+		{"wrong secret", signedRequest("/slack/commands", form, "other-secret", time.Now()), http.StatusUnauthorized},
+		{"stale timestamp", signedRequest("/slack/commands", form, testSigningSecret, time.Now().Add(-10*time.Minute)), http.StatusUnauthorized},
+		{"unsigned", httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(form)), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			b.HandleCommand(rec, tt.req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	// A body altered after signing fails verification
+	r := signedRequest("/slack/commands", form, testSigningSecret, time.Now())
+	r.Body = http.NoBody
+	rec := httptest.NewRecorder()
+	b.HandleCommand(rec, r)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("altered body status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandleCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		text     string
+		wantType string
+		wantText string
+	}{
+		{"create", "/outage", "create API down | 5xx from the gateway | High", responseInChannel, "opened outage *API down*"},
+		{"create without severity", "/outage", "create API down | 5xx", responseEphemeral, "Invalid format"},
+		{"create with bad severity", "/outage", "create API down | 5xx | urgent", responseEphemeral, "Invalid severity"},
+		{"create without title", "/outage", "create  | 5xx | high", responseEphemeral, "A title is required"},
+		{"list with no outages", "/outage", "list", responseEphemeral, "No open outages"},
+		{"no subcommand", "/outage", "", responseEphemeral, "Usage:"},
+		{"unknown subcommand", "/outage", "frobnicate", responseEphemeral, "Usage:"},
+		{"note without text", "/note", "not-an-id", responseEphemeral, "Invalid format"},
+		{"unknown command", "/status", "", responseEphemeral, "Unknown command `/status`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBot(t, Config{})
+			code, reply := runSlashCommand(t, b, commandForm(tt.command, tt.text))
+			if code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", code)
+			}
+			if reply.ResponseType != tt.wantType || !strings.Contains(reply.Text, tt.wantText) {
+				t.Errorf("reply = %s %q, want %s containing %q", reply.ResponseType, reply.Text, tt.wantType, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestSlashCreateOutage(t *testing.T) {
+	b, _ := newTestBot(t, Config{})
+	ctx := context.Background()
+
+	_, reply := runSlashCommand(t, b, commandForm("/outage", "create API down | 5xx from the gateway | High"))
+	if reply.ResponseType != responseInChannel {
+		t.Fatalf("reply = %s %q, want it posted to the channel", reply.ResponseType, reply.Text)
+	}
+	outages, err := b.service.ListOutages(ctx, 10, 0)
+	if err != nil || len(outages) != 1 {
+		t.Fatalf("ListOutages = %d outages, %v, want 1", len(outages), err)
+	}
+	o, err := b.service.GetOutage(ctx, outages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Title != "API down" || o.Description != "5xx from the gateway" || o.Severity != "high" {
+		t.Errorf("outage = %q %q %q, want the parsed title, description and lower-cased severity", o.Title, o.Description, o.Severity)
+	}
+	tags := make(map[string]string)
+	for _, tag := range o.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	if tags[channelTagKey] != "C1" || tags["slack_user"] != "U1" {
+		t.Errorf("tags = %v, want the channel and user the command came from", tags)
+	}
+
+	// Listing shows the new outage, to the user only
+	_, reply = runSlashCommand(t, b, commandForm("/outage", "list"))
+	if reply.ResponseType != responseEphemeral || !strings.Contains(reply.Text, o.ID.String()) {
+		t.Errorf("list reply = %s %q, want the outage ephemerally", reply.ResponseType, reply.Text)
+	}
+}
+
+func TestSlashCreateOutageModal(t *testing.T) {
+	b, fake := newTestBot(t, Config{})
+	form := url.Values{"command": {"/outage"}, "text": {"create"}, "user_id": {"U1"}, "channel_id": {"C1"}, "trigger_id": {"T1"}}
+
+	code, reply := runSlashCommand(t, b, form.Encode())
+	if code != http.StatusOK || reply.Text != "" {
+		t.Errorf("reply = %d %q, want an empty acknowledgement", code, reply.Text)
+	}
+	views := fake.called("views.open")
+	if len(views) != 1 || views[0]["trigger_id"] != "T1" {
+		t.Fatalf("views.open calls = %v, want the modal opened with the command's trigger", views)
+	}
+	view, _ := views[0]["view"].(map[string]any)
+	if view["callback_id"] != createOutageCallbackID || view["private_metadata"] != "C1" {
+		t.Errorf("view = %v, want the create outage modal for channel C1", view)
+	}
+}
+
+func TestSlashAddNote(t *testing.T) {
+	b, _ := newTestBot(t, Config{})
+	ctx := context.Background()
+	outage, err := b.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "API down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, reply := runSlashCommand(t, b, commandForm("/note", outage.ID.String()+" Rolled back the deploy"))
+	if reply.ResponseType != responseInChannel || !strings.Contains(reply.Text, "Added note") {
+		t.Fatalf("reply = %s %q, want the note announced in the channel", reply.ResponseType, reply.Text)
+	}
+	got, err := b.service.GetOutage(ctx, outage.ID)
+	if err != nil || len(got.Notes) != 1 {
+		t.Fatalf("GetOutage = %v, %v, want one note", got, err)
+	}
+	note := got.Notes[0]
+	if note.Content != "Rolled back the deploy" || note.Author != "Name of U1" || note.Metadata[noteMetadataSlackChannel] != "C1" {
+		t.Errorf("note = %q by %q, metadata %v, want the text by the Slack user's name from C1", note.Content, note.Author, note.Metadata)
+	}
+
+	// Errors are only shown to the user who ran the command
+	_, reply = runSlashCommand(t, b, commandForm("/note", "00000000-0000-0000-0000-000000000000 lost"))
+	if reply.ResponseType != responseEphemeral || !strings.Contains(reply.Text, "Error adding note") {
+		t.Errorf("reply = %s %q, want an ephemeral error", reply.ResponseType, reply.Text)
+	}
+}
+
+func TestSlashAddNoteOwningTeam(t *testing.T) {
+	b, _ := newTestBot(t, Config{})
+	ctx := context.Background()
+	teams := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"u2@example.com"}}}}
+	if _, err := b.service.ApplyOpsConfig(ctx, teams, false, false); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := b.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// U1's Slack profile email, u1@example.com, is not in the team
+	_, reply := runSlashCommand(t, b, commandForm("/note", outage.ID.String()+" Rolled back the deploy"))
+	if reply.ResponseType != responseEphemeral || !strings.Contains(reply.Text, "only members of team payments") {
+		t.Errorf("reply = %s %q, want the note refused", reply.ResponseType, reply.Text)
+	}
+	if got, err := b.service.GetOutage(ctx, outage.ID); err != nil || len(got.Notes) != 0 {
+		t.Errorf("GetOutage = %v, %v, want no notes", got, err)
+	}
+}
+
+func TestSlashWatchOutage(t *testing.T) {
+	b, fake := newTestBot(t, Config{})
+	b.service.RegisterWatchNotifier(domain.WatchChannelSlack, b)
+	ctx := context.Background()
+	outage, err := b.service.CreateOutage(ctx, domain.CreateOutageRequest{Title: "API down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, reply := runSlashCommand(t, b, commandForm("/outage", "watch "+outage.ID.String()))
+	if reply.ResponseType != responseEphemeral || !strings.Contains(reply.Text, "You are watching") {
+		t.Fatalf("reply = %s %q, want an ephemeral confirmation", reply.ResponseType, reply.Text)
+	}
+	watchers, err := b.service.ListWatchers(ctx, outage.ID)
+	if err != nil || len(watchers) != 1 || watchers[0].Email != "u1@example.com" || watchers[0].Channel != domain.WatchChannelSlack {
+		t.Fatalf("ListWatchers = %v, %v; want U1 watching by Slack", watchers, err)
+	}
+
+	// Email is not configured, so cannot be chosen
+	if _, reply := runSlashCommand(t, b, commandForm("/outage", "watch "+outage.ID.String()+" email")); !strings.Contains(reply.Text, "not configured") {
+		t.Errorf("watch by email reply = %q, want an error", reply.Text)
+	}
+
+	if _, err := b.service.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "Rolled back", Author: "bob@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	posts := fake.called("chat.postMessage")
+	if len(posts) != 1 || posts[0]["channel"] != "U-u1@example.com" || !strings.Contains(posts[0]["text"].(string), ">Rolled back") {
+		t.Fatalf("posts = %v, want a direct message to U1 quoting the note", posts)
+	}
+
+	_, reply = runSlashCommand(t, b, commandForm("/outage", "unwatch "+outage.ID.String()))
+	if !strings.Contains(reply.Text, "no longer watching") {
+		t.Errorf("unwatch reply = %q", reply.Text)
+	}
+	_, reply = runSlashCommand(t, b, commandForm("/outage", "unwatch "+outage.ID.String()))
+	if !strings.Contains(reply.Text, "You are not watching") {
+		t.Errorf("second unwatch reply = %q", reply.Text)
+	}
+}
+
+func TestSlashCreateFromTemplate(t *testing.T) {
+	b, _ := newTestBot(t, Config{})
+	ctx := context.Background()
+
+	_, reply := runSlashCommand(t, b, commandForm("/outage", "template"))
+	if !strings.Contains(reply.Text, "No outage templates") {
+		t.Errorf("empty list reply = %q", reply.Text)
+	}
+	_, err := b.service.SaveOutageTemplate(ctx, domain.OutageTemplate{
+		Name: "DB-Failover", Title: "Database failover on {cluster}", Severity: "high",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, reply = runSlashCommand(t, b, commandForm("/outage", "template"))
+	if reply.ResponseType != responseEphemeral || !strings.Contains(reply.Text, "`DB-Failover`") {
+		t.Errorf("list reply = %s %q, want the template listed", reply.ResponseType, reply.Text)
+	}
+
+	_, reply = runSlashCommand(t, b, commandForm("/outage", "template missing"))
+	if !strings.Contains(reply.Text, "No template named `missing`") {
+		t.Errorf("unknown template reply = %q", reply.Text)
+	}
+	_, reply = runSlashCommand(t, b, commandForm("/outage", "template DB-Failover main"))
+	if !strings.Contains(reply.Text, "Invalid format") {
+		t.Errorf("bad variable reply = %q", reply.Text)
+	}
+
+	_, reply = runSlashCommand(t, b, commandForm("/outage", "template DB-Failover cluster=main"))
+	if reply.ResponseType != responseInChannel || !strings.Contains(reply.Text, "*Database failover on main*") {
+		t.Fatalf("reply = %s %q, want the outage opened in the channel", reply.ResponseType, reply.Text)
+	}
+	outages, err := b.service.ListOutages(ctx, 10, 0)
+	if err != nil || len(outages) != 1 {
+		t.Fatalf("ListOutages = %d outages, %v, want 1", len(outages), err)
+	}
+	o, err := b.service.GetOutage(ctx, outages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Severity != "high" || boundChannel(o) != "C1" {
+		t.Errorf("outage = %+v, want the template's severity and the command's channel", o)
+	}
+}
+
+func TestRunbookLinks(t *testing.T) {
+	b, fake := newTestBot(t, Config{})
+	ctx := context.Background()
+	for _, svc := range []domain.Service{
+		{Name: "checkout", RunbookURL: "https://runbooks.example.com/checkout"},
+		{Name: "search"},
+	} {
+		if _, _, err := b.service.SaveService(ctx, svc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outage, err := b.service.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "Checkout down", Severity: "high", AffectedServices: []string{"checkout", "search"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, reply := runSlashCommand(t, b, commandForm("/outage", "bind "+outage.ID.String()))
+	if !strings.Contains(reply.Text, "Runbooks: <https://runbooks.example.com/checkout|checkout>") || strings.Contains(reply.Text, "search") {
+		t.Errorf("bind reply = %q, want only the checkout runbook linked", reply.Text)
+	}
+
+	bound, err := b.service.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := &domain.Alert{Source: "pagerduty", Title: "5xx", Severity: "high", Service: "checkout"}
+	if err := b.AlertAdded(ctx, bound, alert); err != nil {
+		t.Fatal(err)
+	}
+	posts := fake.called("chat.postMessage")
+	if len(posts) != 1 || !strings.Contains(fmt.Sprint(posts[0]["text"]), "<https://runbooks.example.com/checkout|checkout>") {
+		t.Errorf("chat.postMessage calls = %v, want the alert posted with its service's runbook", posts)
+	}
+}
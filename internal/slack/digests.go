@@ -0,0 +1,16 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/conall/outalator/service"
+)
+
+// SendDigest implements service.DigestNotifier by posting the digest in its
+// channel. Digests without a channel are left to other notifiers.
+func (b *Bot) SendDigest(_ context.Context, msg service.DigestMessage) error {
+	if msg.SlackChannel == "" {
+		return nil
+	}
+	return b.sendMessage(msg.SlackChannel, "*"+msg.Subject+"*\n\n"+msg.Text)
+}
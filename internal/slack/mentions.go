@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+)
+
+// NotifyMention implements service.MentionNotifier. A mentioned team is
+// notified in its Slack channel, or by direct message to each member when it
+// has none; a mentioned user by direct message.
+func (b *Bot) NotifyMention(ctx context.Context, outage *domain.Outage, note *domain.Note, recipient service.MentionRecipient) error {
+	who := "you"
+	if recipient.Mention.Type == domain.MentionTeam {
+		who = "@" + recipient.Mention.Name
+	}
+	text := fmt.Sprintf(":speech_balloon: %s mentioned %s on outage *%s* (`%s`):\n>%s",
+		note.Author, who, outage.Title, outage.ID, strings.ReplaceAll(note.Content, "\n", "\n>"))
+
+	if recipient.SlackChannel != "" {
+		return b.sendMessage(recipient.SlackChannel, text)
+	}
+
+	var errs []error
+	for _, email := range recipient.Emails {
+		user, err := b.client.LookupUserByEmail(email)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lookup %s: %w", email, err))
+			continue
+		}
+		// Posting to a user ID delivers a direct message from the bot
+		if err := b.sendMessage(user.ID, text); err != nil {
+			errs = append(errs, fmt.Errorf("message %s: %w", email, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,343 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/logging"
+)
+
+// createOutageCallbackID identifies both the "create outage" shortcut and
+// the modal it opens
+const createOutageCallbackID = "create_outage"
+
+// Block and action IDs of the create outage modal's inputs
+const (
+	blockTitle       = "title"
+	blockDescription = "description"
+	blockSeverity    = "severity"
+	blockTeam        = "team"
+	actionValue      = "value"
+)
+
+// teamTagKey is the tag that records an outage's owning team, matching the
+// tag added by routing rules
+const teamTagKey = "team"
+
+// maxSelectOptions is Slack's limit on options in a static select
+const maxSelectOptions = 100
+
+// interactionPayload is the part of a Slack interaction payload the bot uses
+type interactionPayload struct {
+	Type       string `json:"type"` // shortcut, message_action, view_submission, ...
+	CallbackID string `json:"callback_id"`
+	TriggerID  string `json:"trigger_id"`
+	User       struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"` // Set for message shortcuts only
+	View struct {
+		CallbackID      string `json:"callback_id"`
+		PrivateMetadata string `json:"private_metadata"`
+		State           struct {
+			Values map[string]map[string]viewStateValue `json:"values"`
+		} `json:"state"`
+	} `json:"view"`
+}
+
+// viewStateValue is the submitted value of a single modal input
+type viewStateValue struct {
+	Type           string `json:"type"`
+	Value          string `json:"value"`
+	SelectedOption *struct {
+		Value string `json:"value"`
+	} `json:"selected_option"`
+}
+
+// HandleInteraction handles interactivity requests posted to
+// /slack/interactions: the "create outage" shortcut opens the outage modal,
+// and submitting the modal creates the outage.
+func (b *Bot) HandleInteraction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if bodylimit.TooLarge(err) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if !b.client.VerifyRequest(r, body) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	var payload interactionPayload
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		b.logger.WarnContext(r.Context(), "failed to decode slack interaction", "error", err)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	ctx := logging.WithUser(r.Context(), payload.User.ID)
+	switch {
+	case (payload.Type == "shortcut" || payload.Type == "message_action") && payload.CallbackID == createOutageCallbackID:
+		if err := b.openCreateOutageModal(ctx, payload.TriggerID, payload.Channel.ID); err != nil {
+			b.logger.ErrorContext(ctx, "failed to open outage modal", "error", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	case payload.Type == "view_submission" && payload.View.CallbackID == createOutageCallbackID:
+		b.submitCreateOutage(ctx, w, payload)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// openCreateOutageModal opens the create outage modal. channelID is where
+// the summary card is posted once the outage is created; it may be empty.
+func (b *Bot) openCreateOutageModal(ctx context.Context, triggerID, channelID string) error {
+	var teams []domain.Team
+	if cfg, err := b.service.GetOpsConfig(ctx); err != nil {
+		b.logger.WarnContext(ctx, "failed to load teams for outage modal", "error", err)
+	} else {
+		teams = cfg.Teams
+	}
+	return b.client.OpenView(triggerID, createOutageModal(teams, channelID))
+}
+
+// submitCreateOutage creates an outage from a submitted modal. Validation
+// errors are returned to the modal; on success the modal closes and a
+// summary card is posted.
+func (b *Bot) submitCreateOutage(ctx context.Context, w http.ResponseWriter, payload interactionPayload) {
+	values := payload.View.State.Values
+	title := strings.TrimSpace(values[blockTitle][actionValue].Value)
+	description := strings.TrimSpace(values[blockDescription][actionValue].Value)
+	severity := selectedValue(values[blockSeverity][actionValue])
+	team := selectedValue(values[blockTeam][actionValue])
+
+	if title == "" {
+		respondViewErrors(w, map[string]string{blockTitle: "A title is required"})
+		return
+	}
+	if !validSeverities[severity] {
+		respondViewErrors(w, map[string]string{blockSeverity: "Choose a severity"})
+		return
+	}
+
+	channel := payload.View.PrivateMetadata
+	tags := []domain.TagInput{{Key: "slack_user", Value: payload.User.ID}}
+	if channel != "" {
+		tags = append(tags, domain.TagInput{Key: channelTagKey, Value: channel})
+	}
+	if team != "" {
+		tags = append(tags, domain.TagInput{Key: teamTagKey, Value: team})
+	}
+
+	outage, err := b.service.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title:       title,
+		Description: description,
+		Severity:    severity,
+		Tags:        tags,
+	})
+	if err != nil {
+		respondViewErrors(w, map[string]string{blockTitle: fmt.Sprintf("Error creating outage: %v", err)})
+		return
+	}
+
+	// Shortcuts opened outside a channel post to the team's channel, falling
+	// back to a direct message to the reporter
+	if channel == "" {
+		channel = b.teamChannel(ctx, team)
+	}
+	if channel == "" {
+		channel = payload.User.ID
+	}
+
+	// An empty response closes the modal. Post the card after responding so
+	// Slack is not kept waiting.
+	w.WriteHeader(http.StatusOK)
+	go b.postOutageSummary(context.WithoutCancel(ctx), channel, outage, payload.User.ID, team)
+}
+
+// teamChannel returns the Slack channel configured for team, if any
+func (b *Bot) teamChannel(ctx context.Context, team string) string {
+	if team == "" {
+		return ""
+	}
+	cfg, err := b.service.GetOpsConfig(ctx)
+	if err != nil {
+		b.logger.WarnContext(ctx, "failed to load teams", "error", err)
+		return ""
+	}
+	for _, t := range cfg.Teams {
+		if t.Name == team {
+			return t.SlackChannel
+		}
+	}
+	return ""
+}
+
+// postOutageSummary posts a Block Kit summary card for a new outage
+func (b *Bot) postOutageSummary(ctx context.Context, channel string, outage *domain.Outage, userID, team string) {
+	text := fmt.Sprintf("🚨 <@%s> opened outage *%s* (ID: `%s`, Severity: %s)", userID, outage.Title, outage.ID, outage.Severity)
+	resp, err := b.client.PostMessageWithBlocks(channel, text, outageSummaryBlocks(outage, userID, team))
+	if err == nil && !resp.OK {
+		err = fmt.Errorf("slack error: %s", resp.Error)
+	}
+	if err != nil {
+		b.logger.ErrorContext(ctx, "failed to post outage summary", "channel", channel, "outage_id", outage.ID, "error", err)
+	}
+}
+
+// respondViewErrors rejects a modal submission, showing each message under
+// its block
+func respondViewErrors(w http.ResponseWriter, errs map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"response_action": "errors",
+		"errors":          errs,
+	})
+}
+
+func selectedValue(v viewStateValue) string {
+	if v.SelectedOption == nil {
+		return ""
+	}
+	return v.SelectedOption.Value
+}
+
+// Block Kit builders
+
+func plainText(text string) map[string]interface{} {
+	return map[string]interface{}{"type": "plain_text", "text": text}
+}
+
+func markdown(text string) map[string]interface{} {
+	return map[string]interface{}{"type": "mrkdwn", "text": text}
+}
+
+func selectOption(label, value string) map[string]interface{} {
+	return map[string]interface{}{"text": plainText(label), "value": value}
+}
+
+func inputBlock(blockID, label string, element map[string]interface{}, optional bool) map[string]interface{} {
+	element["action_id"] = actionValue
+	return map[string]interface{}{
+		"type":     "input",
+		"block_id": blockID,
+		"label":    plainText(label),
+		"element":  element,
+		"optional": optional,
+	}
+}
+
+// createOutageModal builds the create outage modal. The team selector is
+// only shown when teams are configured.
+func createOutageModal(teams []domain.Team, channelID string) map[string]interface{} {
+	severities := []interface{}{
+		selectOption("Critical", "critical"),
+		selectOption("High", "high"),
+		selectOption("Medium", "medium"),
+		selectOption("Low", "low"),
+	}
+
+	blocks := []interface{}{
+		inputBlock(blockTitle, "Title", map[string]interface{}{
+			"type":        "plain_text_input",
+			"placeholder": plainText("What is broken?"),
+		}, false),
+		inputBlock(blockDescription, "Description", map[string]interface{}{
+			"type":      "plain_text_input",
+			"multiline": true,
+		}, true),
+		inputBlock(blockSeverity, "Severity", map[string]interface{}{
+			"type":        "static_select",
+			"placeholder": plainText("Select a severity"),
+			"options":     severities,
+		}, false),
+	}
+
+	if len(teams) > 0 {
+		options := make([]interface{}, 0, len(teams))
+		for _, t := range teams {
+			if len(options) == maxSelectOptions {
+				break
+			}
+			options = append(options, selectOption(t.Name, t.Name))
+		}
+		blocks = append(blocks, inputBlock(blockTeam, "Team", map[string]interface{}{
+			"type":        "static_select",
+			"placeholder": plainText("Select the owning team"),
+			"options":     options,
+		}, true))
+	}
+
+	return map[string]interface{}{
+		"type":             "modal",
+		"callback_id":      createOutageCallbackID,
+		"private_metadata": channelID,
+		"title":            plainText("New outage"),
+		"submit":           plainText("Create"),
+		"close":            plainText("Cancel"),
+		"blocks":           blocks,
+	}
+}
+
+// outageSummaryBlocks builds the summary card posted for a new outage
+func outageSummaryBlocks(outage *domain.Outage, userID, team string) []interface{} {
+	if team == "" {
+		team = "_none_"
+	}
+	blocks := []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": plainText(truncate("🚨 "+outage.Title, 150)),
+		},
+	}
+	if outage.Description != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": markdown(outage.Description),
+		})
+	}
+	blocks = append(blocks,
+		map[string]interface{}{
+			"type": "section",
+			"fields": []interface{}{
+				markdown("*Severity*\n" + outage.Severity),
+				markdown("*Status*\n" + outage.Status),
+				markdown("*Team*\n" + team),
+				markdown(fmt.Sprintf("*Opened by*\n<@%s>", userID)),
+			},
+		},
+		map[string]interface{}{
+			"type":     "context",
+			"elements": []interface{}{markdown(fmt.Sprintf("Outage ID: `%s`", outage.ID))},
+		},
+	)
+	return blocks
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,150 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// viewSubmission encodes a create outage modal submission as Slack posts
+// it. An empty severity or team leaves that select unset.
+func viewSubmission(channel, title, severity, team string) string {
+	selected := func(value string) map[string]any {
+		if value == "" {
+			return map[string]any{"type": "static_select"}
+		}
+		return map[string]any{"type": "static_select", "selected_option": map[string]any{"value": value}}
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"type": "view_submission",
+		"user": map[string]any{"id": "U1"},
+		"view": map[string]any{
+			"callback_id":      createOutageCallbackID,
+			"private_metadata": channel,
+			"state": map[string]any{"values": map[string]map[string]map[string]any{
+				blockTitle:       {actionValue: {"type": "plain_text_input", "value": title}},
+				blockDescription: {actionValue: {"type": "plain_text_input", "value": "From the modal"}},
+				blockSeverity:    {actionValue: selected(severity)},
+				blockTeam:        {actionValue: selected(team)},
+			}},
+		},
+	})
+	return url.Values{"payload": {string(payload)}}.Encode()
+}
+
+func submitView(b *Bot, form string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	b.HandleInteraction(rec, signedRequest("/slack/interactions", form, testSigningSecret, time.Now()))
+	return rec
+}
+
+func TestSubmitCreateOutageErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		severity  string
+		wantBlock string
+	}{
+		{"missing title", "  ", "high", blockTitle},
+		{"missing severity", "API down", "", blockSeverity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBot(t, Config{})
+			rec := submitView(b, viewSubmission("C1", tt.title, tt.severity, ""))
+
+			var resp struct {
+				ResponseAction string            `json:"response_action"`
+				Errors         map[string]string `json:"errors"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+			}
+			if resp.ResponseAction != "errors" || resp.Errors[tt.wantBlock] == "" {
+				t.Errorf("response = %+v, want an error on the %s block", resp, tt.wantBlock)
+			}
+			if outages, _ := b.service.ListOutages(context.Background(), 10, 0); len(outages) != 0 {
+				t.Errorf("created %d outages from an invalid submission", len(outages))
+			}
+		})
+	}
+}
+
+func TestSubmitCreateOutage(t *testing.T) {
+	tests := []struct {
+		name        string
+		channel     string
+		team        string
+		wantChannel string
+	}{
+		{"from a channel", "C1", "payments", "C1"},
+		{"to the team's channel", "", "payments", "#payments"},
+		{"to the reporter", "", "", "U1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, fake := newTestBot(t, Config{})
+			fake.posted = make(chan string, 1)
+			ctx := context.Background()
+			if _, err := b.service.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{
+				Name: "payments", SlackChannel: "#payments",
+			}}}, false, false); err != nil {
+				t.Fatal(err)
+			}
+
+			rec := submitView(b, viewSubmission(tt.channel, "API down", "critical", tt.team))
+			if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+				t.Fatalf("response = %d %q, want an empty 200 closing the modal", rec.Code, rec.Body.String())
+			}
+
+			outages, err := b.service.ListOutages(ctx, 10, 0)
+			if err != nil || len(outages) != 1 {
+				t.Fatalf("ListOutages = %d outages, %v, want 1", len(outages), err)
+			}
+			outage, err := b.service.GetOutage(ctx, outages[0].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if outage.Title != "API down" || outage.Description != "From the modal" || outage.Severity != "critical" {
+				t.Errorf("outage = %q %q %q, want the submitted values", outage.Title, outage.Description, outage.Severity)
+			}
+			var tags []string
+			for _, tag := range outage.Tags {
+				tags = append(tags, tag.Key+"="+tag.Value)
+			}
+			if joined := strings.Join(tags, ","); tt.team != "" && !strings.Contains(joined, teamTagKey+"="+tt.team) {
+				t.Errorf("tags = %s, want the selected team", joined)
+			}
+
+			// The summary card is posted once the modal has closed
+			select {
+			case channel := <-fake.posted:
+				if channel != tt.wantChannel {
+					t.Errorf("summary posted to %q, want %q", channel, tt.wantChannel)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no summary card posted")
+			}
+		})
+	}
+}
+
+func TestHandleInteractionSignature(t *testing.T) {
+	b, _ := newTestBot(t, Config{})
+	form := viewSubmission("C1", "API down", "high", "")
+	rec := httptest.NewRecorder()
+	b.HandleInteraction(rec, signedRequest("/slack/interactions", form, "other-secret", time.Now()))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if outages, _ := b.service.ListOutages(context.Background(), 10, 0); len(outages) != 0 {
+		t.Errorf("created %d outages from an unsigned submission", len(outages))
+	}
+}
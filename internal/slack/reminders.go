@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// Review reminder defaults
+const (
+	defaultReviewReminderAfter    = 72 * time.Hour
+	defaultReviewReminderInterval = 24 * time.Hour
+)
+
+// RunReviewReminders posts a list of overdue outage reviews to channel every
+// interval until ctx is cancelled. An outage is overdue once it has waited
+// needsReviewAfter for a review to be scheduled, or once its scheduled review
+// date has passed without it being marked reviewed.
+func (b *Bot) RunReviewReminders(ctx context.Context, channel string, interval, needsReviewAfter time.Duration) {
+	if interval <= 0 {
+		interval = defaultReviewReminderInterval
+	}
+	if needsReviewAfter <= 0 {
+		needsReviewAfter = defaultReviewReminderAfter
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.sendReviewReminder(ctx, channel, needsReviewAfter)
+		}
+	}
+}
+
+// sendReviewReminder posts a single reminder if any reviews are overdue
+func (b *Bot) sendReviewReminder(ctx context.Context, channel string, needsReviewAfter time.Duration) {
+	overdue, err := b.service.OverdueReviews(ctx, time.Now(), needsReviewAfter)
+	if err != nil {
+		b.logger.ErrorContext(ctx, "failed to list overdue reviews", "error", err)
+		return
+	}
+	if len(overdue) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ":memo: %d outage review(s) are overdue:\n", len(overdue))
+	for _, review := range overdue {
+		title := review.OutageID.String()
+		if outage, err := b.service.GetOutage(ctx, review.OutageID); err == nil {
+			title = outage.Title
+		}
+		switch review.Status {
+		case domain.ReviewScheduled:
+			fmt.Fprintf(&sb, "• %s: review was scheduled for %s but is not marked reviewed\n",
+				title, review.ScheduledFor.Format("2006-01-02"))
+		default:
+			fmt.Fprintf(&sb, "• %s: awaiting review since %s\n",
+				title, review.CreatedAt.Format("2006-01-02"))
+		}
+	}
+
+	if err := b.sendMessage(channel, sb.String()); err != nil {
+		b.logger.ErrorContext(ctx, "failed to send review reminder", "channel", channel, "error", err)
+	}
+}
@@ -0,0 +1,28 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// NotifySourceStale implements service.SourceStaleNotifier by posting to the
+// configured alarm channel. Alarms are dropped when no channel is set.
+func (b *Bot) NotifySourceStale(_ context.Context, health domain.SourceHealth) error {
+	if b.sourceAlarmChannel == "" {
+		return nil
+	}
+
+	last := "never"
+	if health.LastSuccessAt != nil {
+		last = health.LastSuccessAt.UTC().Format("2006-01-02 15:04 MST")
+	}
+	text := fmt.Sprintf(":warning: Alert source *%s* has not delivered alerts in over %s (last success: %s). Check its webhook subscription or credentials.",
+		health.Source, time.Duration(health.MaxSilenceSeconds)*time.Second, last)
+	if health.LastError != "" {
+		text += fmt.Sprintf(" Last error: `%s`", health.LastError)
+	}
+	return b.sendMessage(b.sourceAlarmChannel, text)
+}
@@ -0,0 +1,143 @@
+package slack
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// Note metadata recording the thread a note was imported from and when its
+// message was posted
+const (
+	noteMetadataSlackThreadTS = "slack_thread_ts"
+	noteMetadataSlackPostedAt = "slack_posted_at"
+)
+
+// findOutageID returns the outage ID mentioned in the message at ts or,
+// failing that, in the first other message of the thread that mentions one
+func findOutageID(thread []ThreadMessage, ts string) (uuid.UUID, bool) {
+	pattern := regexp.MustCompile(`(?i)outage[:\s]+([a-fA-F0-9-]{36})`)
+	parse := func(text string) (uuid.UUID, bool) {
+		matches := pattern.FindStringSubmatch(text)
+		if len(matches) < 2 {
+			return uuid.Nil, false
+		}
+		id, err := uuid.Parse(matches[1])
+		return id, err == nil
+	}
+
+	for _, msg := range thread {
+		if msg.TS == ts {
+			if id, ok := parse(msg.Text); ok {
+				return id, true
+			}
+		}
+	}
+	for _, msg := range thread {
+		if msg.TS != ts {
+			if id, ok := parse(msg.Text); ok {
+				return id, true
+			}
+		}
+	}
+	return uuid.Nil, false
+}
+
+// importThread adds each message of thread to the outage as a note, in the
+// order they were posted. Replies are added as replies to the note for the
+// thread's first message. Messages already imported from the channel are
+// skipped, so reacting to a thread again only picks up new replies. It
+// returns the number of notes added.
+func (b *Bot) importThread(ctx context.Context, outageID uuid.UUID, channel string, thread []ThreadMessage) (int, error) {
+	outage, err := b.service.GetOutage(ctx, outageID)
+	if err != nil {
+		return 0, err
+	}
+	imported := slackNoteIDs(outage.Notes, channel)
+
+	authors := make(map[string]string)
+	added := 0
+	for _, msg := range thread {
+		if _, ok := imported[msg.TS]; ok || strings.TrimSpace(msg.Text) == "" {
+			continue
+		}
+
+		author, ok := authors[msg.User]
+		if !ok {
+			author = b.threadAuthor(ctx, msg)
+			if msg.User != "" {
+				authors[msg.User] = author
+			}
+		}
+
+		req := domain.AddNoteRequest{
+			Content:  msg.Text,
+			Format:   "plaintext",
+			Author:   author,
+			Metadata: slackNoteMetadata(channel, msg.TS),
+		}
+		if len(thread) > 1 {
+			req.Metadata[noteMetadataSlackThreadTS] = thread[0].TS
+			if root, ok := imported[thread[0].TS]; ok && msg.TS != thread[0].TS {
+				req.ParentNoteID = &root
+			}
+		}
+		if posted, ok := slackTime(msg.TS); ok {
+			req.Metadata[noteMetadataSlackPostedAt] = posted.Format(time.RFC3339)
+		}
+
+		note, err := b.service.AddNote(ctx, outageID, req)
+		if err != nil {
+			return added, err
+		}
+		imported[msg.TS] = note.ID
+		added++
+	}
+	return added, nil
+}
+
+// slackNoteIDs maps the timestamps of messages imported from channel to the
+// IDs of their notes
+func slackNoteIDs(notes []domain.Note, channel string) map[string]uuid.UUID {
+	ids := make(map[string]uuid.UUID)
+	for _, n := range notes {
+		if n.Metadata[noteMetadataSlackChannel] == channel && n.Metadata[noteMetadataSlackTS] != "" {
+			ids[n.Metadata[noteMetadataSlackTS]] = n.ID
+		}
+	}
+	return ids
+}
+
+// threadAuthor returns the name to record as the author of msg
+func (b *Bot) threadAuthor(ctx context.Context, msg ThreadMessage) string {
+	switch {
+	case msg.User != "":
+		return b.getUserName(ctx, msg.User)
+	case msg.Username != "":
+		return msg.Username
+	case msg.BotID != "":
+		return msg.BotID
+	}
+	return "unknown"
+}
+
+// slackTime parses a Slack message timestamp ("1700000000.123456")
+func slackTime(ts string) (time.Time, bool) {
+	secs, frac, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var usec int64
+	if frac != "" {
+		if usec, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return time.Time{}, false
+		}
+	}
+	return time.Unix(sec, usec*int64(time.Microsecond)).UTC(), true
+}
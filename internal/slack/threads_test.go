@@ -0,0 +1,141 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// reactTo delivers a reaction to the message at ts in channel C1
+func reactTo(t *testing.T, b *Bot, emoji, ts string) {
+	t.Helper()
+	event, err := json.Marshal(map[string]any{
+		"type":     "reaction_added",
+		"user":     "U9",
+		"reaction": emoji,
+		"item":     map[string]any{"type": "message", "channel": "C1", "ts": ts},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.processEvent(context.Background(), SlackEvent{Type: "event_callback", Event: event})
+}
+
+// notesByTS returns the outage's notes keyed by the timestamp of the Slack
+// message each was imported from
+func notesByTS(t *testing.T, b *Bot, outageID uuid.UUID) map[string]domain.Note {
+	t.Helper()
+	outage, err := b.service.GetOutage(context.Background(), outageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := make(map[string]domain.Note)
+	for _, n := range outage.Notes {
+		notes[n.Metadata[noteMetadataSlackTS]] = n
+	}
+	return notes
+}
+
+func TestImportThread(t *testing.T) {
+	b, fake := newTestBot(t, Config{ReactionEmoji: "outage_note"})
+	outage, err := b.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "DB down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.thread = []ThreadMessage{
+		{User: "U1", Text: "outage " + outage.ID.String() + " primary is failing over", TS: "1700000000.000100", ThreadTS: "1700000000.000100"},
+		{User: "U2", Text: "replica promoted", TS: "1700000060.000200", ThreadTS: "1700000000.000100"},
+		{BotID: "B1", Username: "deploybot", Text: "rollback started", TS: "1700000120.000300", ThreadTS: "1700000000.000100"},
+		{User: "U1", Text: "   ", TS: "1700000130.000400", ThreadTS: "1700000000.000100"},
+	}
+
+	// Reacting to a reply imports the whole thread, the outage ID coming
+	// from its first message
+	reactTo(t, b, "outage_note", "1700000060.000200")
+
+	notes := notesByTS(t, b, outage.ID)
+	if len(notes) != 3 {
+		t.Fatalf("imported %d notes, want the 3 non-empty messages", len(notes))
+	}
+	root := notes["1700000000.000100"]
+	if root.ParentNoteID != nil || root.Author != "Name of U1" {
+		t.Errorf("root note = parent %v author %q, want a top-level note by U1", root.ParentNoteID, root.Author)
+	}
+	for _, ts := range []string{"1700000060.000200", "1700000120.000300"} {
+		reply := notes[ts]
+		if reply.ParentNoteID == nil || *reply.ParentNoteID != root.ID {
+			t.Errorf("reply %s parent = %v, want the thread's first message %v", ts, reply.ParentNoteID, root.ID)
+		}
+		if reply.Metadata[noteMetadataSlackThreadTS] != "1700000000.000100" {
+			t.Errorf("reply %s metadata = %v, want the thread's timestamp", ts, reply.Metadata)
+		}
+	}
+	if author := notes["1700000120.000300"].Author; author != "deploybot" {
+		t.Errorf("bot reply author = %q, want the bot's name", author)
+	}
+	if posted := notes["1700000060.000200"].Metadata[noteMetadataSlackPostedAt]; posted != "2023-11-14T22:14:20Z" {
+		t.Errorf("posted at = %q, want the message's Slack time", posted)
+	}
+	if len(fake.called("reactions.add")) != 1 {
+		t.Errorf("reactions.add calls = %v, want the import confirmed", fake.called("reactions.add"))
+	}
+
+	// Reacting again only picks up replies posted since, still under the
+	// original root note
+	fake.mu.Lock()
+	fake.thread = append(fake.thread, ThreadMessage{User: "U2", Text: "all clear", TS: "1700000200.000500", ThreadTS: "1700000000.000100"})
+	fake.mu.Unlock()
+	reactTo(t, b, "outage_note", "1700000000.000100")
+
+	notes = notesByTS(t, b, outage.ID)
+	if len(notes) != 4 {
+		t.Fatalf("after reacting again there are %d notes, want 4", len(notes))
+	}
+	if parent := notes["1700000200.000500"].ParentNoteID; parent == nil || *parent != root.ID {
+		t.Errorf("new reply parent = %v, want %v", parent, root.ID)
+	}
+}
+
+func TestImportThreadIgnoresOtherReactions(t *testing.T) {
+	b, fake := newTestBot(t, Config{ReactionEmoji: "outage_note"})
+	outage, err := b.service.CreateOutage(context.Background(), domain.CreateOutageRequest{Title: "DB down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.thread = []ThreadMessage{{User: "U1", Text: "outage " + outage.ID.String() + " looking", TS: "1700000000.000100"}}
+
+	reactTo(t, b, "thumbsup", "1700000000.000100")
+	if notes := notesByTS(t, b, outage.ID); len(notes) != 0 {
+		t.Errorf("imported %d notes from another emoji", len(notes))
+	}
+
+	// Without an outage ID the reactor is asked for one
+	fake.thread = []ThreadMessage{{User: "U1", Text: "something is wrong", TS: "1700000000.000200"}}
+	reactTo(t, b, "outage_note", "1700000000.000200")
+	if posts := fake.called("chat.postMessage"); len(posts) != 1 || posts[0]["channel"] != "C1" {
+		t.Errorf("chat.postMessage calls = %v, want a prompt for the outage ID", posts)
+	}
+}
+
+func TestSlackTime(t *testing.T) {
+	tests := []struct {
+		ts   string
+		want time.Time
+		ok   bool
+	}{
+		{"1700000000.000100", time.Date(2023, 11, 14, 22, 13, 20, 100000, time.UTC), true},
+		{"1700000000", time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC), true},
+		{"not-a-ts", time.Time{}, false},
+		{"1700000000.x", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := slackTime(tt.ts)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("slackTime(%q) = %v, %v, want %v, %v", tt.ts, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+)
+
+// NotifyUpdateOverdue implements service.UpdateReminderNotifier. The
+// reminder goes to the channel of the team routing assigned the outage,
+// then to the outage's bound channel, then to the configured default
+// reminder channel; it is dropped when none is set.
+func (b *Bot) NotifyUpdateOverdue(ctx context.Context, outage *domain.Outage, reminder service.UpdateReminder) error {
+	channel := reminder.SlackChannel
+	if channel == "" {
+		channel = boundChannel(outage)
+	}
+	if channel == "" {
+		channel = b.updateReminderChannel
+	}
+	if channel == "" {
+		return nil
+	}
+
+	sla := reminder.SLA
+	team := ""
+	if reminder.Team != "" {
+		team = fmt.Sprintf(" for team *%s*", reminder.Team)
+	}
+	text := fmt.Sprintf(":hourglass: Status update overdue on *%s* (`%s`)%s: %s outages need an update every %s and one was due at %s. Add a note to reset the timer.",
+		outage.Title, outage.ID, team, outage.Severity,
+		time.Duration(sla.IntervalSeconds)*time.Second, sla.DueAt.UTC().Format("15:04 MST"))
+	return b.sendMessage(channel, text)
+}
@@ -0,0 +1,16 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/conall/outalator/service"
+)
+
+// SendViewSummary implements service.ViewSummaryNotifier by posting the
+// summary in the view's channel
+func (b *Bot) SendViewSummary(_ context.Context, msg service.ViewSummaryMessage) error {
+	if msg.SlackChannel == "" {
+		return nil
+	}
+	return b.sendMessage(msg.SlackChannel, "*"+msg.Subject+"*\n\n"+msg.Text)
+}
@@ -0,0 +1,99 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/service"
+	"github.com/google/uuid"
+)
+
+// NotifyWatchers implements service.WatchNotifier by direct messaging each
+// watcher about the outage's new status or note
+func (b *Bot) NotifyWatchers(_ context.Context, event service.WatchEvent, emails []string) error {
+	outage := event.Outage
+	var text string
+	if event.Note != nil {
+		text = fmt.Sprintf(":eyes: %s added a note to outage *%s* (`%s`):\n>%s",
+			event.Note.Author, outage.Title, outage.ID, strings.ReplaceAll(event.Note.Content, "\n", "\n>"))
+	} else {
+		text = fmt.Sprintf(":eyes: Outage *%s* (`%s`) changed from %s to *%s*",
+			outage.Title, outage.ID, event.PreviousStatus, outage.Status)
+	}
+	text += fmt.Sprintf("\n_You are watching this outage. `/outage unwatch %s` to stop._", outage.ID)
+
+	var errs []error
+	for _, email := range emails {
+		user, err := b.client.LookupUserByEmail(email)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lookup %s: %w", email, err))
+			continue
+		}
+		if err := b.sendMessage(user.ID, text); err != nil {
+			errs = append(errs, fmt.Errorf("message %s: %w", email, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// slashWatchOutage handles "/outage watch <id> [email]", subscribing the
+// user to the outage by direct message, or by email with "email"
+func (b *Bot) slashWatchOutage(ctx context.Context, cmd SlashCommand, args string) (string, string) {
+	idArg, option := splitCommand(args)
+	outageID, err := uuid.Parse(idArg)
+	if err != nil || (option != "" && option != domain.WatchChannelEmail) {
+		return responseEphemeral, "Invalid format. Use: `/outage watch <outage_id> [email]`"
+	}
+	channel := domain.WatchChannelSlack
+	if option != "" {
+		channel = option
+	}
+
+	email, err := b.userEmail(cmd.UserID)
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error watching outage: %v", err)
+	}
+	if _, err := b.service.WatchOutage(ctx, outageID, email, domain.WatchOutageRequest{Channel: channel}); err != nil {
+		return responseEphemeral, fmt.Sprintf("Error watching outage: %v", err)
+	}
+	how := "a direct message"
+	if channel == domain.WatchChannelEmail {
+		how = "email to " + email
+	}
+	return responseEphemeral, fmt.Sprintf(":eyes: You are watching outage `%s`. Status changes and new notes will be sent by %s.", outageID, how)
+}
+
+// slashUnwatchOutage handles "/outage unwatch <id>"
+func (b *Bot) slashUnwatchOutage(ctx context.Context, cmd SlashCommand, args string) (string, string) {
+	outageID, err := uuid.Parse(strings.TrimSpace(args))
+	if err != nil {
+		return responseEphemeral, "Invalid format. Use: `/outage unwatch <outage_id>`"
+	}
+	email, err := b.userEmail(cmd.UserID)
+	if err != nil {
+		return responseEphemeral, fmt.Sprintf("Error unwatching outage: %v", err)
+	}
+	if err := b.service.UnwatchOutage(ctx, outageID, email); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return responseEphemeral, fmt.Sprintf("You are not watching outage `%s`", outageID)
+		}
+		return responseEphemeral, fmt.Sprintf("Error unwatching outage: %v", err)
+	}
+	return responseEphemeral, fmt.Sprintf("You are no longer watching outage `%s`", outageID)
+}
+
+// userEmail returns the email address of a Slack user, which identifies
+// them as a watcher
+func (b *Bot) userEmail(userID string) (string, error) {
+	user, err := b.client.GetUserInfo(userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch your Slack profile: %w", err)
+	}
+	if user.Profile.Email == "" {
+		return "", errors.New("Slack did not share your email address; the bot needs the users:read.email scope")
+	}
+	return user.Profile.Email, nil
+}
@@ -0,0 +1,62 @@
+// Package sourcehealth periodically checks when alerts were last ingested
+// from each source and raises alarms for sources that have gone quiet for
+// longer than their staleness threshold.
+package sourcehealth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// defaultInterval is the time between checks when none is configured
+const defaultInterval = 5 * time.Minute
+
+// Alarmer is the subset of the service layer the checker drives
+type Alarmer interface {
+	CheckSourceHealth(ctx context.Context, now time.Time) (int, error)
+}
+
+// Checker checks source staleness on a fixed interval
+type Checker struct {
+	alarmer  Alarmer
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewChecker creates a checker for the given service. A zero interval falls
+// back to the package default.
+func NewChecker(alarmer Alarmer, interval time.Duration, logger *slog.Logger) *Checker {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Checker{alarmer: alarmer, interval: interval, logger: logger}
+}
+
+// Run checks immediately and then every interval until ctx is cancelled
+func (c *Checker) Run(ctx context.Context) {
+	c.CheckOnce(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.CheckOnce(ctx)
+		}
+	}
+}
+
+// CheckOnce runs a single check, logging the sources it alarmed about
+func (c *Checker) CheckOnce(ctx context.Context) {
+	alarmed, err := c.alarmer.CheckSourceHealth(ctx, time.Now())
+	if err != nil {
+		c.logger.ErrorContext(ctx, "source health check failed", "error", err)
+		return
+	}
+	if alarmed > 0 {
+		c.logger.InfoContext(ctx, "raised stale source alarms", "sources", alarmed)
+	}
+}
@@ -0,0 +1,52 @@
+package sourcehealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeAlarmer struct {
+	checked chan time.Time
+}
+
+func (f *fakeAlarmer) CheckSourceHealth(_ context.Context, now time.Time) (int, error) {
+	select {
+	case f.checked <- now:
+	default:
+	}
+	return 0, nil
+}
+
+func TestRun_ChecksImmediatelyAndStopsOnCancel(t *testing.T) {
+	alarmer := &fakeAlarmer{checked: make(chan time.Time, 1)}
+	c := NewChecker(alarmer, time.Hour, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-alarmer.checked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not check on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewChecker_DefaultInterval(t *testing.T) {
+	c := NewChecker(&fakeAlarmer{}, 0, logging.Discard())
+	if c.interval != defaultInterval {
+		t.Errorf("interval = %v, want %v", c.interval, defaultInterval)
+	}
+}
@@ -0,0 +1,268 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// parquetMagic starts and ends every Parquet file
+const parquetMagic = "PAR1"
+
+// Parquet physical types, converted types, encodings and other enum values
+// used by the writer, from parquet.thrift
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetOptional = 1
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+// Thrift compact protocol field types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// WriteParquet writes the table as an uncompressed Parquet file with one
+// row group. Every column is optional, so nulls survive the round trip;
+// strings are UTF-8 byte arrays and timestamps microseconds since the Unix
+// epoch in UTC.
+func WriteParquet(w io.Writer, t *Table) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	var chunks [][]byte
+	var totalSize int64
+	if len(t.Rows) > 0 {
+		for i, c := range t.Columns {
+			offset := int64(file.Len())
+			page := columnPage(t, i)
+			file.Write(page)
+			totalSize += int64(len(page))
+			chunks = append(chunks, columnChunk(c, offset, int64(len(page)), int64(len(t.Rows))))
+		}
+	}
+
+	schema := [][]byte{
+		new(thriftWriter).binary(4, "schema").i32(5, int32(len(t.Columns))).end(),
+	}
+	for _, c := range t.Columns {
+		physical, converted := parquetTypes(c.Type)
+		s := new(thriftWriter).i32(1, physical).i32(3, parquetOptional).binary(4, c.Name)
+		if converted >= 0 {
+			s.i32(6, converted)
+		}
+		schema = append(schema, s.end())
+	}
+	var rowGroups [][]byte
+	if len(t.Rows) > 0 {
+		rowGroups = append(rowGroups, new(thriftWriter).
+			list(1, thriftStruct, chunks).
+			i64(2, totalSize).
+			i64(3, int64(len(t.Rows))).
+			end())
+	}
+	footer := new(thriftWriter).
+		i32(1, 1).
+		list(2, thriftStruct, schema).
+		i64(3, int64(len(t.Rows))).
+		list(4, thriftStruct, rowGroups).
+		binary(6, "outalator").
+		end()
+
+	file.Write(footer)
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer))))
+	file.WriteString(parquetMagic)
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// parquetTypes returns the physical and converted type of a column type. A
+// negative converted type means none.
+func parquetTypes(t Type) (physical, converted int32) {
+	switch t {
+	case Int64:
+		return parquetInt64, -1
+	case Bool:
+		return parquetBoolean, -1
+	case Timestamp:
+		return parquetInt64, parquetTimestampMicros
+	default:
+		return parquetByteArray, parquetUTF8
+	}
+}
+
+// columnPage encodes column i as a single data page: its header, the
+// definition levels marking nulls and the plain encoded values
+func columnPage(t *Table, col int) []byte {
+	var data bytes.Buffer
+
+	levels := definitionLevels(t, col)
+	data.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(levels))))
+	data.Write(levels)
+
+	var bits []bool
+	for _, row := range t.Rows {
+		switch v := row[col].(type) {
+		case string:
+			data.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v))))
+			data.WriteString(v)
+		case int64:
+			data.Write(binary.LittleEndian.AppendUint64(nil, uint64(v)))
+		case time.Time:
+			data.Write(binary.LittleEndian.AppendUint64(nil, uint64(v.UnixMicro())))
+		case bool:
+			bits = append(bits, v)
+		}
+	}
+	// Booleans are bit-packed, least significant bit first
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8 && i+j < len(bits); j++ {
+			if bits[i+j] {
+				b |= 1 << j
+			}
+		}
+		data.WriteByte(b)
+	}
+
+	header := new(thriftWriter).
+		i32(1, parquetDataPage).
+		i32(2, int32(data.Len())).
+		i32(3, int32(data.Len())).
+		structure(5, new(thriftWriter).
+			i32(1, int32(len(t.Rows))).
+			i32(2, parquetPlain).
+			i32(3, parquetRLE).
+			i32(4, parquetRLE)).
+		end()
+	return append(header, data.Bytes()...)
+}
+
+// definitionLevels encodes whether each value of column col is present (1)
+// or null (0) with the RLE/bit-packing hybrid encoding, as RLE runs only
+func definitionLevels(t *Table, col int) []byte {
+	var out []byte
+	for i := 0; i < len(t.Rows); {
+		present := t.Rows[i][col] != nil
+		run := 1
+		for i+run < len(t.Rows) && (t.Rows[i+run][col] != nil) == present {
+			run++
+		}
+		out = binary.AppendUvarint(out, uint64(run)<<1)
+		if present {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i += run
+	}
+	return out
+}
+
+// columnChunk encodes the metadata of a column chunk holding one page of
+// size bytes at offset
+func columnChunk(c Column, offset, size, rows int64) []byte {
+	physical, _ := parquetTypes(c.Type)
+	encodings := [][]byte{zigzag(nil, parquetPlain), zigzag(nil, parquetRLE)}
+	meta := new(thriftWriter).
+		i32(1, physical).
+		list(2, thriftI32, encodings).
+		list(3, thriftBinary, [][]byte{thriftString(c.Name)}).
+		i32(4, parquetUncompressed).
+		i64(5, rows).
+		i64(6, size).
+		i64(7, size).
+		i64(9, offset)
+	return new(thriftWriter).i64(2, offset).structure(3, meta).end()
+}
+
+// thriftWriter encodes a struct with the Thrift compact protocol, the
+// encoding of Parquet's page headers and footer
+type thriftWriter struct {
+	b      []byte
+	lastID int16
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.b = append(t.b, byte(delta)<<4|typ)
+	} else {
+		t.b = append(t.b, typ)
+		t.b = zigzag(t.b, int64(id))
+	}
+	t.lastID = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) *thriftWriter {
+	t.field(id, thriftI32)
+	t.b = zigzag(t.b, int64(v))
+	return t
+}
+
+func (t *thriftWriter) i64(id int16, v int64) *thriftWriter {
+	t.field(id, thriftI64)
+	t.b = zigzag(t.b, v)
+	return t
+}
+
+func (t *thriftWriter) binary(id int16, v string) *thriftWriter {
+	t.field(id, thriftBinary)
+	t.b = append(t.b, thriftString(v)...)
+	return t
+}
+
+func (t *thriftWriter) structure(id int16, v *thriftWriter) *thriftWriter {
+	t.field(id, thriftStruct)
+	t.b = append(t.b, v.end()...)
+	return t
+}
+
+// list adds a list of already encoded elements of type elemType
+func (t *thriftWriter) list(id int16, elemType byte, elems [][]byte) *thriftWriter {
+	t.field(id, thriftList)
+	if len(elems) < 15 {
+		t.b = append(t.b, byte(len(elems))<<4|elemType)
+	} else {
+		t.b = append(t.b, 0xf0|elemType)
+		t.b = binary.AppendUvarint(t.b, uint64(len(elems)))
+	}
+	for _, e := range elems {
+		t.b = append(t.b, e...)
+	}
+	return t
+}
+
+// end returns the encoded struct, closed with a stop field
+func (t *thriftWriter) end() []byte {
+	return append(t.b, 0)
+}
+
+// thriftString encodes a string as a length-prefixed binary
+func thriftString(s string) []byte {
+	return append(binary.AppendUvarint(nil, uint64(len(s))), s...)
+}
+
+// zigzag appends v as a zigzag varint, as compact Thrift encodes integers
+func zigzag(b []byte, v int64) []byte {
+	return binary.AppendUvarint(b, uint64(v<<1^v>>63))
+}
@@ -0,0 +1,109 @@
+// Package tabular writes rows of typed columns as CSV or Apache Parquet
+// files, so outage data can be loaded into a data warehouse.
+package tabular
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Type is the type of a column's values
+type Type int
+
+// Column types
+const (
+	String    Type = iota // string
+	Int64                 // int64
+	Bool                  // bool
+	Timestamp             // time.Time, written in UTC
+)
+
+// Column is a named, typed column of a table
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Table is a set of rows with the same columns. Each row holds one value
+// per column, of the Go type noted against the column's Type, or nil for
+// null.
+type Table struct {
+	Columns []Column
+	Rows    [][]any
+}
+
+// Append adds a row to the table
+func (t *Table) Append(row ...any) {
+	t.Rows = append(t.Rows, row)
+}
+
+// check reports the first value whose type does not match its column
+func (t *Table) check() error {
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return fmt.Errorf("tabular: row %d has %d values for %d columns", i, len(row), len(t.Columns))
+		}
+		for j, v := range row {
+			if v == nil {
+				continue
+			}
+			var ok bool
+			switch t.Columns[j].Type {
+			case String:
+				_, ok = v.(string)
+			case Int64:
+				_, ok = v.(int64)
+			case Bool:
+				_, ok = v.(bool)
+			case Timestamp:
+				_, ok = v.(time.Time)
+			}
+			if !ok {
+				return fmt.Errorf("tabular: row %d column %s has a %T value", i, t.Columns[j].Name, v)
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the table as CSV with a header row. Nulls are written as
+// empty fields and timestamps in RFC 3339.
+func WriteCSV(w io.Writer, t *Table) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, v := range row {
+			switch v := v.(type) {
+			case nil:
+				record[i] = ""
+			case string:
+				record[i] = v
+			case int64:
+				record[i] = strconv.FormatInt(v, 10)
+			case bool:
+				record[i] = strconv.FormatBool(v)
+			case time.Time:
+				record[i] = v.UTC().Format(time.RFC3339)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,219 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTable() *Table {
+	at := time.Date(2030, 1, 7, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	t := &Table{Columns: []Column{
+		{Name: "id", Type: String},
+		{Name: "count", Type: Int64},
+		{Name: "customer_impact", Type: Bool},
+		{Name: "resolved_at", Type: Timestamp},
+	}}
+	t.Append("a", int64(3), true, at)
+	t.Append("b, \"quoted\"", nil, false, nil)
+	t.Append(nil, int64(-1), true, at.Add(time.Second))
+	return t
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testTable()); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,count,customer_impact,resolved_at\n" +
+		"a,3,true,2030-01-07T09:00:00Z\n" +
+		"\"b, \"\"quoted\"\"\",,false,\n" +
+		",-1,true,2030-01-07T09:00:01Z\n"
+	if buf.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	bad := &Table{Columns: []Column{{Name: "n", Type: Int64}}}
+	bad.Append("not a number")
+	if err := WriteCSV(&buf, bad); err == nil {
+		t.Error("WriteCSV accepted a string in an int64 column")
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	table := testTable()
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+	file := buf.Bytes()
+	if !bytes.HasPrefix(file, []byte(parquetMagic)) || !bytes.HasSuffix(file, []byte(parquetMagic)) {
+		t.Fatal("file is not framed by PAR1")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := readThrift(t, file[len(file)-8-footerLen:len(file)-8])
+
+	if footer[3] != int64(3) {
+		t.Errorf("num_rows = %v, want 3", footer[3])
+	}
+	schema := footer[2].([]any)
+	if len(schema) != 5 || schema[0].(map[int16]any)[5] != int64(4) {
+		t.Fatalf("schema = %v, want a root with 4 children", schema)
+	}
+	var names []string
+	for _, e := range schema[1:] {
+		names = append(names, string(e.(map[int16]any)[4].([]byte)))
+	}
+	if strings.Join(names, ",") != "id,count,customer_impact,resolved_at" {
+		t.Errorf("columns = %v", names)
+	}
+
+	chunks := footer[4].([]any)[0].(map[int16]any)[1].([]any)
+	var columns [][]any
+	for i, chunk := range chunks {
+		meta := chunk.(map[int16]any)[3].(map[int16]any)
+		offset := meta[9].(int64)
+		values, err := readPage(file[offset:], table.Columns[i].Type)
+		if err != nil {
+			t.Fatalf("column %d: %v", i, err)
+		}
+		columns = append(columns, values)
+	}
+	for r, row := range table.Rows {
+		for c, want := range row {
+			got := columns[c][r]
+			if at, ok := want.(time.Time); ok {
+				want = at.UnixMicro()
+			}
+			if got != want {
+				t.Errorf("row %d column %s = %v, want %v", r, table.Columns[c].Name, got, want)
+			}
+		}
+	}
+
+	buf.Reset()
+	if err := WriteParquet(&buf, &Table{Columns: table.Columns}); err != nil {
+		t.Fatalf("WriteParquet of an empty table: %v", err)
+	}
+}
+
+// thriftReader decodes the Thrift compact protocol into maps of field ID to
+// value: int64 for integers, []byte for binaries, []any for lists and maps
+// for structs
+type thriftReader struct {
+	b   []byte
+	err error
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.err = bytes.ErrTooLarge
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		n := r.varint()
+		v := r.b[:n]
+		r.b = r.b[n:]
+		return v
+	case thriftList:
+		header := r.b[0]
+		r.b = r.b[1:]
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		fields := make(map[int16]any)
+		var id int16
+		for r.err == nil {
+			header := r.b[0]
+			r.b = r.b[1:]
+			if header == 0 {
+				break
+			}
+			if delta := int16(header >> 4); delta != 0 {
+				id += delta
+			} else {
+				id = int16(r.zigzag())
+			}
+			fields[id] = r.value(header & 0x0f)
+		}
+		return fields
+	}
+	r.err = bytes.ErrTooLarge
+	return nil
+}
+
+func readThrift(t *testing.T, b []byte) map[int16]any {
+	t.Helper()
+	r := &thriftReader{b: b}
+	v := r.value(thriftStruct)
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	return v.(map[int16]any)
+}
+
+// readPage decodes a data page written by columnPage, returning nil for
+// nulls
+func readPage(b []byte, typ Type) ([]any, error) {
+	r := &thriftReader{b: b}
+	header := r.value(thriftStruct).(map[int16]any)
+	numValues := int(header[5].(map[int16]any)[1].(int64))
+	data := r.b[:header[2].(int64)]
+
+	levelsLen := binary.LittleEndian.Uint32(data)
+	levels := &thriftReader{b: data[4 : 4+levelsLen]}
+	data = data[4+levelsLen:]
+	var present []bool
+	for len(present) < numValues {
+		run := int(levels.varint() >> 1)
+		bit := levels.b[0] == 1
+		levels.b = levels.b[1:]
+		for range run {
+			present = append(present, bit)
+		}
+	}
+
+	values := make([]any, numValues)
+	bit := 0
+	for i, ok := range present {
+		if !ok {
+			continue
+		}
+		switch typ {
+		case String:
+			n := binary.LittleEndian.Uint32(data)
+			values[i] = string(data[4 : 4+n])
+			data = data[4+n:]
+		case Int64, Timestamp:
+			values[i] = int64(binary.LittleEndian.Uint64(data))
+			data = data[8:]
+		case Bool:
+			values[i] = data[bit/8]&(1<<(bit%8)) != 0
+			bit++
+		}
+	}
+	return values, r.err
+}
@@ -0,0 +1,59 @@
+// Package teamsync periodically copies the teams of notification services
+// into Outalator, so team membership and ownership filters follow changes
+// made in PagerDuty or OpsGenie.
+package teamsync
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// defaultInterval is the time between syncs when none is configured
+const defaultInterval = 6 * time.Hour
+
+// TeamSyncer is the subset of the service layer the syncer drives
+type TeamSyncer interface {
+	SyncTeams(ctx context.Context) (*domain.OpsConfigPlan, error)
+}
+
+// Syncer syncs teams on a fixed interval
+type Syncer struct {
+	teams    TeamSyncer
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewSyncer creates a syncer for the given service. A zero interval falls
+// back to the package default.
+func NewSyncer(teams TeamSyncer, interval time.Duration, logger *slog.Logger) *Syncer {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Syncer{teams: teams, interval: interval, logger: logger}
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled
+func (s *Syncer) Run(ctx context.Context) {
+	s.SyncOnce(ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SyncOnce(ctx)
+		}
+	}
+}
+
+// SyncOnce runs a single sync. The service logs the changes it makes.
+func (s *Syncer) SyncOnce(ctx context.Context) {
+	if _, err := s.teams.SyncTeams(ctx); err != nil {
+		s.logger.ErrorContext(ctx, "team sync failed", "error", err)
+	}
+}
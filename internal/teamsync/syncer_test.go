@@ -0,0 +1,53 @@
+package teamsync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeTeamSyncer struct {
+	synced chan struct{}
+}
+
+func (f *fakeTeamSyncer) SyncTeams(context.Context) (*domain.OpsConfigPlan, error) {
+	select {
+	case f.synced <- struct{}{}:
+	default:
+	}
+	return &domain.OpsConfigPlan{}, nil
+}
+
+func TestRun_SyncsImmediatelyAndStopsOnCancel(t *testing.T) {
+	teams := &fakeTeamSyncer{synced: make(chan struct{}, 1)}
+	s := NewSyncer(teams, time.Hour, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-teams.synced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not sync on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewSyncer_DefaultInterval(t *testing.T) {
+	s := NewSyncer(&fakeTeamSyncer{}, 0, logging.Discard())
+	if s.interval != defaultInterval {
+		t.Errorf("interval = %v, want %v", s.interval, defaultInterval)
+	}
+}
@@ -2,6 +2,10 @@ package testutil
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
 
 	"github.com/conall/outalator/internal/auth"
 )
@@ -10,5 +14,46 @@ import (
 // Intended for tests that need to inject an authenticated user without a
 // live session store.
 func WithUser(ctx context.Context, u *auth.UserInfo) context.Context {
-	return context.WithValue(ctx, auth.UserContextKey, u)
+	return auth.WithUser(ctx, u)
+}
+
+// NewAuthenticator returns an OIDC authenticator backed by a fake issuer
+// that serves only its discovery document. It can enforce sessions in
+// route tests but cannot complete a sign-in.
+func NewAuthenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	return NewAuthenticatorWithPolicy(t, auth.Policy{})
+}
+
+// NewAuthenticatorWithPolicy is NewAuthenticator letting callers without a
+// session through as policy allows
+func NewAuthenticatorWithPolicy(t *testing.T, policy auth.Policy) *auth.Authenticator {
+	t.Helper()
+	var issuer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": issuer + "/authorize",
+			"token_endpoint":         issuer + "/token",
+			"jwks_uri":               issuer + "/keys",
+		})
+	}))
+	t.Cleanup(server.Close)
+	issuer = server.URL
+
+	a, err := auth.NewAuthenticator(auth.Config{
+		Issuer:     issuer,
+		ClientID:   "outalator",
+		SessionKey: "test-session-key",
+		Policy:     policy,
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	return a
 }
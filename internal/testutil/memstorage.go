@@ -4,8 +4,10 @@ package testutil
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/conall/outalator/domain"
 	"github.com/conall/outalator/storage"
@@ -28,31 +30,85 @@ func clone[T any](v T) T {
 	return out
 }
 
+// cloneTag copies a tag with clone, keeping the unique key flag JSON leaves out
+func cloneTag(t *domain.Tag) domain.Tag {
+	cp := clone(*t)
+	cp.UniqueKey = t.UniqueKey
+	return cp
+}
+
 // Compile-time assertion that MemStorage satisfies the full storage.Storage interface.
 var _ storage.Storage = (*MemStorage)(nil)
 
 // MemStorage is a thread-safe in-memory implementation of storage.Storage for tests.
 // All collections are sorted deterministically (by ID) so that pagination is stable.
 type MemStorage struct {
-	mu      sync.RWMutex
-	outages map[uuid.UUID]*domain.Outage
-	notes   map[uuid.UUID]*domain.Note
-	tags    map[uuid.UUID]*domain.Tag
-	alerts  map[uuid.UUID]*domain.Alert
+	mu            sync.RWMutex
+	outages       map[uuid.UUID]*domain.Outage
+	notes         map[uuid.UUID]*domain.Note
+	noteRevisions map[uuid.UUID][]*domain.NoteRevision // keyed by note ID
+	tags          map[uuid.UUID]*domain.Tag
+	alerts        map[uuid.UUID]*domain.Alert
+	alertEvents   map[uuid.UUID]*domain.AlertEvent
+	statusChanges map[uuid.UUID]*domain.StatusChange
+	preferences   map[string]*domain.UserPreferences
+	reviews       map[uuid.UUID]*domain.OutageReview
+	syncCursors   map[string]*domain.SyncCursor
+	ingestion     map[string]*domain.IngestionRecord
+	configs       map[string]*domain.ConfigResource // keyed by kind + "/" + name
+	attachments   map[uuid.UUID]*domain.Attachment
+	responders    map[uuid.UUID]*domain.ResponderAssignment
+	events        map[[2]string]time.Time // processed_at keyed by source and event ID
+	importRuns    map[uuid.UUID]*domain.ImportRun
+	savedViews    map[uuid.UUID]*domain.SavedView
+	actionItems   map[uuid.UUID]*domain.ActionItem
+	services      map[string]*domain.Service
+	watchers      map[[2]string]*domain.Watcher // keyed by outage ID and email
+	watermarks    map[string]*domain.ExportWatermark
+	retention     map[uuid.UUID]*domain.RetentionRun
+	relations     map[uuid.UUID]*domain.OutageRelation
+
+	// PingErr is returned by Ping, to simulate an unreachable database
+	PingErr error
+	// SchemaErr is returned by CheckSchema, to simulate missing migrations
+	SchemaErr error
 }
 
 // NewMemStorage returns an empty MemStorage ready for use in tests.
 func NewMemStorage() *MemStorage {
 	return &MemStorage{
-		outages: make(map[uuid.UUID]*domain.Outage),
-		notes:   make(map[uuid.UUID]*domain.Note),
-		tags:    make(map[uuid.UUID]*domain.Tag),
-		alerts:  make(map[uuid.UUID]*domain.Alert),
+		outages:       make(map[uuid.UUID]*domain.Outage),
+		notes:         make(map[uuid.UUID]*domain.Note),
+		noteRevisions: make(map[uuid.UUID][]*domain.NoteRevision),
+		tags:          make(map[uuid.UUID]*domain.Tag),
+		alerts:        make(map[uuid.UUID]*domain.Alert),
+		alertEvents:   make(map[uuid.UUID]*domain.AlertEvent),
+		statusChanges: make(map[uuid.UUID]*domain.StatusChange),
+		preferences:   make(map[string]*domain.UserPreferences),
+		reviews:       make(map[uuid.UUID]*domain.OutageReview),
+		syncCursors:   make(map[string]*domain.SyncCursor),
+		watermarks:    make(map[string]*domain.ExportWatermark),
+		ingestion:     make(map[string]*domain.IngestionRecord),
+		configs:       make(map[string]*domain.ConfigResource),
+		attachments:   make(map[uuid.UUID]*domain.Attachment),
+		responders:    make(map[uuid.UUID]*domain.ResponderAssignment),
+		events:        make(map[[2]string]time.Time),
+		importRuns:    make(map[uuid.UUID]*domain.ImportRun),
+		savedViews:    make(map[uuid.UUID]*domain.SavedView),
+		actionItems:   make(map[uuid.UUID]*domain.ActionItem),
+		services:      make(map[string]*domain.Service),
+		watchers:      make(map[[2]string]*domain.Watcher),
+		retention:     make(map[uuid.UUID]*domain.RetentionRun),
+		relations:     make(map[uuid.UUID]*domain.OutageRelation),
 	}
 }
 
 func (m *MemStorage) Close() error { return nil }
 
+func (m *MemStorage) Ping(context.Context) error { return m.PingErr }
+
+func (m *MemStorage) CheckSchema(context.Context) error { return m.SchemaErr }
+
 // --- Outage ---
 
 func (m *MemStorage) CreateOutage(_ context.Context, o *domain.Outage) error {
@@ -63,7 +119,11 @@ func (m *MemStorage) CreateOutage(_ context.Context, o *domain.Outage) error {
 	return nil
 }
 
-func (m *MemStorage) GetOutage(_ context.Context, id uuid.UUID) (*domain.Outage, error) {
+func (m *MemStorage) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
+	return m.GetOutageWith(ctx, id, domain.AllOutageAssociations)
+}
+
+func (m *MemStorage) GetOutageWith(_ context.Context, id uuid.UUID, associations domain.OutageAssociations) (*domain.Outage, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	o, ok := m.outages[id]
@@ -71,25 +131,125 @@ func (m *MemStorage) GetOutage(_ context.Context, id uuid.UUID) (*domain.Outage,
 		return nil, domain.ErrNotFound
 	}
 	cp := clone(*o)
-	for _, n := range m.notes {
-		if n.OutageID == id {
-			cp.Notes = append(cp.Notes, *n)
+	m.loadAssociations(&cp, associations)
+	return &cp, nil
+}
+
+// LoadOutageAssociations sets the selected alerts, live notes and tags of
+// outages.
+func (m *MemStorage) LoadOutageAssociations(_ context.Context, outages []*domain.Outage, associations domain.OutageAssociations) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, o := range outages {
+		m.loadAssociations(o, associations)
+	}
+	return nil
+}
+
+// loadAssociations rebuilds an outage's selected associations from the
+// child maps so that an outage previously round-tripped through
+// UpdateOutage does not duplicate them, newest first as the SQL backends
+// order them. Associations not selected are cleared. The caller must hold
+// m.mu.
+func (m *MemStorage) loadAssociations(o *domain.Outage, associations domain.OutageAssociations) {
+	o.Alerts, o.Notes, o.Tags = nil, nil, nil
+	if associations.Alerts {
+		o.Alerts = []domain.Alert{}
+		for _, a := range m.alerts {
+			if a.OutageID == o.ID {
+				o.Alerts = append(o.Alerts, *a)
+			}
 		}
+		sort.Slice(o.Alerts, func(i, j int) bool { return o.Alerts[i].TriggeredAt.After(o.Alerts[j].TriggeredAt) })
 	}
-	for _, t := range m.tags {
-		if t.OutageID == id {
-			cp.Tags = append(cp.Tags, *t)
+	if associations.Notes {
+		o.Notes = []domain.Note{}
+		for _, n := range m.notes {
+			if n.OutageID == o.ID && n.DeletedAt == nil {
+				o.Notes = append(o.Notes, *n)
+			}
 		}
+		sort.Slice(o.Notes, func(i, j int) bool { return o.Notes[i].CreatedAt.After(o.Notes[j].CreatedAt) })
+	}
+	if associations.Tags {
+		o.Tags = []domain.Tag{}
+		for _, t := range m.tags {
+			if t.OutageID == o.ID {
+				o.Tags = append(o.Tags, *t)
+			}
+		}
+		sort.Slice(o.Tags, func(i, j int) bool { return o.Tags[i].CreatedAt.After(o.Tags[j].CreatedAt) })
 	}
-	return &cp, nil
 }
 
 // ListOutages returns outages sorted by ID for deterministic pagination.
-func (m *MemStorage) ListOutages(_ context.Context, limit, offset int) ([]*domain.Outage, error) {
+func (m *MemStorage) ListOutages(_ context.Context, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	return m.listOutages(func(*domain.Outage) bool { return true }, limit, offset, includeDeleted)
+}
+
+// ListOutagesByTeams returns the outages owned by any of teams, sorted like
+// ListOutages.
+func (m *MemStorage) ListOutagesByTeams(_ context.Context, teams []string, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	return m.listOutages(func(o *domain.Outage) bool {
+		for _, team := range teams {
+			if o.OwningTeam == team {
+				return true
+			}
+		}
+		return false
+	}, limit, offset, includeDeleted)
+}
+
+// ListChildOutages returns the outages whose parent is parentID, oldest
+// first, leaving out outages in the trash.
+func (m *MemStorage) ListChildOutages(_ context.Context, parentID uuid.UUID) ([]*domain.Outage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Outage
+	for _, o := range m.outages {
+		if o.DeletedAt == nil && o.ParentID != nil && *o.ParentID == parentID {
+			cp := clone(*o)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID.String() < out[j].ID.String()
+	})
+	return out, nil
+}
+
+// ListPublicOutages returns the open outages marked public in their
+// metadata, oldest first.
+func (m *MemStorage) ListPublicOutages(_ context.Context) ([]*domain.Outage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Outage
+	for _, o := range m.outages {
+		if o.DeletedAt == nil && o.Metadata[domain.MetadataPublic] == "true" && o.Status != "resolved" && o.Status != "closed" {
+			cp := clone(*o)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID.String() < out[j].ID.String()
+	})
+	return out, nil
+}
+
+func (m *MemStorage) listOutages(match func(*domain.Outage) bool, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	all := make([]*domain.Outage, 0, len(m.outages))
 	for _, o := range m.outages {
+		if (o.DeletedAt != nil && !includeDeleted) || !match(o) {
+			continue
+		}
 		cp := clone(*o)
 		all = append(all, &cp)
 	}
@@ -107,17 +267,54 @@ func (m *MemStorage) ListOutages(_ context.Context, limit, offset int) ([]*domai
 	return all[offset:end], nil
 }
 
+// UpdateOutage saves the outage, keeping its parent as the SQL backends do
 func (m *MemStorage) UpdateOutage(_ context.Context, o *domain.Outage) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if _, ok := m.outages[o.ID]; !ok {
+	existing, ok := m.outages[o.ID]
+	if !ok {
 		return domain.ErrNotFound
 	}
 	cp := clone(*o)
+	cp.ParentID = existing.ParentID
 	m.outages[o.ID] = &cp
 	return nil
 }
 
+// SetOutageParent links or unlinks the outage's parent, making the SQL
+// backends' checks under m.mu
+func (m *MemStorage) SetOutageParent(_ context.Context, id uuid.UUID, parentID *uuid.UUID, updatedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.outages[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if parentID != nil {
+		parent, ok := m.outages[*parentID]
+		switch {
+		case id == *parentID:
+			return fmt.Errorf("an outage cannot be its own parent: %w", domain.ErrInvalidInput)
+		case !ok:
+			return fmt.Errorf("parent outage %s does not exist: %w", *parentID, domain.ErrInvalidInput)
+		case parent.DeletedAt != nil:
+			return fmt.Errorf("parent outage %s is in the trash: %w", *parentID, domain.ErrInvalidInput)
+		case parent.ParentID != nil:
+			return fmt.Errorf("outage %s is itself a child of %s and cannot be a parent: %w", *parentID, *parent.ParentID, domain.ErrInvalidInput)
+		}
+		for _, child := range m.outages {
+			if child.DeletedAt == nil && child.ParentID != nil && *child.ParentID == id {
+				return fmt.Errorf("outage %s has child outages and cannot be a child: %w", id, domain.ErrInvalidInput)
+			}
+		}
+		p := *parentID
+		parentID = &p
+	}
+	o.ParentID = parentID
+	o.UpdatedAt = updatedAt
+	return nil
+}
+
 // DeleteOutage removes the outage and cascades to associated notes, tags, and alerts,
 // matching the FK-cascade behaviour of the Postgres schema.
 func (m *MemStorage) DeleteOutage(_ context.Context, id uuid.UUID) error {
@@ -126,10 +323,24 @@ func (m *MemStorage) DeleteOutage(_ context.Context, id uuid.UUID) error {
 	if _, ok := m.outages[id]; !ok {
 		return domain.ErrNotFound
 	}
+	m.deleteOutage(id)
+	return nil
+}
+
+// deleteOutage removes an outage and its associations. Callers hold m.mu.
+func (m *MemStorage) deleteOutage(id uuid.UUID) {
 	delete(m.outages, id)
+	delete(m.reviews, id)
+	// Children are unlinked rather than deleted, as ON DELETE SET NULL does
+	for _, o := range m.outages {
+		if o.ParentID != nil && *o.ParentID == id {
+			o.ParentID = nil
+		}
+	}
 	for nid, n := range m.notes {
 		if n.OutageID == id {
 			delete(m.notes, nid)
+			delete(m.noteRevisions, nid)
 		}
 	}
 	for tid, t := range m.tags {
@@ -140,21 +351,135 @@ func (m *MemStorage) DeleteOutage(_ context.Context, id uuid.UUID) error {
 	for aid, a := range m.alerts {
 		if a.OutageID == id {
 			delete(m.alerts, aid)
+			for eid, e := range m.alertEvents {
+				if e.AlertID == aid {
+					delete(m.alertEvents, eid)
+				}
+			}
+		}
+	}
+	for cid, c := range m.statusChanges {
+		if c.OutageID == id {
+			delete(m.statusChanges, cid)
+		}
+	}
+	for rid, r := range m.responders {
+		if r.OutageID == id {
+			delete(m.responders, rid)
+		}
+	}
+	for aid, a := range m.attachments {
+		if a.OutageID == id {
+			delete(m.attachments, aid)
+		}
+	}
+	for aid, a := range m.actionItems {
+		if a.OutageID == id {
+			delete(m.actionItems, aid)
+		}
+	}
+	for key, w := range m.watchers {
+		if w.OutageID == id {
+			delete(m.watchers, key)
+		}
+	}
+	for rid, r := range m.relations {
+		if r.OutageID == id || r.RelatedOutageID == id {
+			delete(m.relations, rid)
 		}
 	}
+}
+
+func (m *MemStorage) TrashOutage(_ context.Context, id uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.outages[id]
+	if !ok || o.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+	o.DeletedAt = &at
+	return nil
+}
+
+func (m *MemStorage) RestoreOutage(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.outages[id]
+	if !ok || o.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+	o.DeletedAt = nil
 	return nil
 }
 
+func (m *MemStorage) PurgeOutages(_ context.Context, deletedBefore time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	purged := 0
+	for id, o := range m.outages {
+		if o.DeletedAt != nil && o.DeletedAt.Before(deletedBefore) {
+			m.deleteOutage(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 // --- Alert ---
 
-func (m *MemStorage) CreateAlert(_ context.Context, a *domain.Alert) error {
+func (m *MemStorage) CreateAlert(ctx context.Context, a *domain.Alert) error {
+	stored, created, err := m.UpsertAlert(ctx, a)
+	if err == nil && !created {
+		*a = *stored
+	}
+	return err
+}
+
+func (m *MemStorage) UpsertAlert(_ context.Context, a *domain.Alert) (*domain.Alert, bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if stored := m.fillStoredAlert(a); stored != nil {
+		return stored, false, nil
+	}
 	cp := clone(*a)
 	m.alerts[a.ID] = &cp
+	return a, true, nil
+}
+
+// fillStoredAlert fills in the lifecycle times of the alert stored with a's
+// source and external ID and returns a copy of it, or nil when there is
+// none. Callers hold m.mu.
+func (m *MemStorage) fillStoredAlert(a *domain.Alert) *domain.Alert {
+	for _, existing := range m.alerts {
+		if existing.ExternalID == a.ExternalID && existing.Source == a.Source {
+			if existing.AcknowledgedAt == nil {
+				existing.AcknowledgedAt = a.AcknowledgedAt
+			}
+			if existing.ResolvedAt == nil {
+				existing.ResolvedAt = a.ResolvedAt
+			}
+			cp := clone(*existing)
+			return &cp
+		}
+	}
 	return nil
 }
 
+// CreateOutageWithAlert stores the outage and its alert, or, when the
+// alert is already stored, neither, as the SQL backends' transaction does
+func (m *MemStorage) CreateOutageWithAlert(_ context.Context, o *domain.Outage, a *domain.Alert) (*domain.Alert, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a.OutageID = o.ID
+	if stored := m.fillStoredAlert(a); stored != nil {
+		return stored, false, nil
+	}
+	outage, alert := clone(*o), clone(*a)
+	m.outages[o.ID] = &outage
+	m.alerts[a.ID] = &alert
+	return a, true, nil
+}
+
 func (m *MemStorage) GetAlert(_ context.Context, id uuid.UUID) (*domain.Alert, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -191,6 +516,55 @@ func (m *MemStorage) ListAlertsByOutage(_ context.Context, outageID uuid.UUID) (
 	return out, nil
 }
 
+func (m *MemStorage) ListOpenAlerts(_ context.Context, triggeredBefore time.Time) ([]*domain.Alert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Alert
+	for _, a := range m.alerts {
+		if a.ResolvedAt == nil && a.TriggeredAt.Before(triggeredBefore) {
+			cp := clone(*a)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TriggeredAt.Before(out[j].TriggeredAt) })
+	return out, nil
+}
+
+func (m *MemStorage) ListAlertsTriggeredBetween(_ context.Context, since, until time.Time) ([]*domain.Alert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Alert
+	for _, a := range m.alerts {
+		if !a.TriggeredAt.Before(since) && a.TriggeredAt.Before(until) {
+			cp := clone(*a)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TriggeredAt.Before(out[j].TriggeredAt) })
+	return out, nil
+}
+
+func (m *MemStorage) ListAlerts(_ context.Context, q domain.AlertQuery) ([]*domain.Alert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Alert
+	for _, a := range m.alerts {
+		if q.Matches(a) {
+			cp := clone(*a)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TriggeredAt.After(out[j].TriggeredAt) })
+	if q.Offset >= len(out) {
+		return nil, nil
+	}
+	out = out[q.Offset:]
+	if len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
 func (m *MemStorage) UpdateAlert(_ context.Context, a *domain.Alert) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -223,16 +597,17 @@ func (m *MemStorage) GetNote(_ context.Context, id uuid.UUID) (*domain.Note, err
 	return &cp, nil
 }
 
-func (m *MemStorage) ListNotesByOutage(_ context.Context, outageID uuid.UUID) ([]*domain.Note, error) {
+func (m *MemStorage) ListNotesByOutage(_ context.Context, outageID uuid.UUID, includeDeleted bool) ([]*domain.Note, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	var out []*domain.Note
 	for _, n := range m.notes {
-		if n.OutageID == outageID {
+		if n.OutageID == outageID && (includeDeleted || n.DeletedAt == nil) {
 			cp := clone(*n)
 			out = append(out, &cp)
 		}
 	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
 	return out, nil
 }
 
@@ -253,67 +628,1099 @@ func (m *MemStorage) DeleteNote(_ context.Context, id uuid.UUID) error {
 	if _, ok := m.notes[id]; !ok {
 		return domain.ErrNotFound
 	}
+	m.deleteNote(id)
+	return nil
+}
+
+// deleteNote removes a note and its revisions and clears the references to
+// it from attachments and replies. Callers hold m.mu.
+func (m *MemStorage) deleteNote(id uuid.UUID) {
 	delete(m.notes, id)
+	delete(m.noteRevisions, id)
+	for _, a := range m.attachments {
+		if a.NoteID != nil && *a.NoteID == id {
+			a.NoteID = nil
+		}
+	}
+	for _, n := range m.notes {
+		if n.ParentNoteID != nil && *n.ParentNoteID == id {
+			n.ParentNoteID = nil
+		}
+	}
+}
+
+func (m *MemStorage) TrashNote(_ context.Context, id uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.notes[id]
+	if !ok || n.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+	n.DeletedAt = &at
 	return nil
 }
 
-// --- Tag ---
+func (m *MemStorage) RestoreNote(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.notes[id]
+	if !ok || n.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+	n.DeletedAt = nil
+	return nil
+}
 
-func (m *MemStorage) CreateTag(_ context.Context, t *domain.Tag) error {
+func (m *MemStorage) PurgeNotes(_ context.Context, deletedBefore time.Time) (int, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	cp := clone(*t)
-	m.tags[t.ID] = &cp
+	purged := 0
+	for id, n := range m.notes {
+		if n.DeletedAt != nil && n.DeletedAt.Before(deletedBefore) {
+			m.deleteNote(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// --- Note revisions ---
+
+func (m *MemStorage) CreateNoteRevision(_ context.Context, r *domain.NoteRevision) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.notes[r.NoteID]; !ok {
+		return domain.ErrNotFound
+	}
+	for _, existing := range m.noteRevisions[r.NoteID] {
+		if existing.Version == r.Version {
+			return domain.ErrConflict
+		}
+	}
+	cp := clone(*r)
+	m.noteRevisions[r.NoteID] = append(m.noteRevisions[r.NoteID], &cp)
 	return nil
 }
 
-func (m *MemStorage) GetTag(_ context.Context, id uuid.UUID) (*domain.Tag, error) {
+// ListNoteRevisions returns revisions oldest version first, matching the SQL
+// backends.
+func (m *MemStorage) ListNoteRevisions(_ context.Context, noteID uuid.UUID) ([]*domain.NoteRevision, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	t, ok := m.tags[id]
-	if !ok {
-		return nil, domain.ErrNotFound
+	var out []*domain.NoteRevision
+	for _, r := range m.noteRevisions[noteID] {
+		cp := clone(*r)
+		out = append(out, &cp)
 	}
-	cp := clone(*t)
-	return &cp, nil
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Version < out[j].Version
+	})
+	return out, nil
 }
 
-func (m *MemStorage) ListTagsByOutage(_ context.Context, outageID uuid.UUID) ([]*domain.Tag, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	var out []*domain.Tag
-	for _, t := range m.tags {
-		if t.OutageID == outageID {
-			cp := clone(*t)
-			out = append(out, &cp)
+// --- Responder assignments ---
+
+func (m *MemStorage) CreateResponderAssignment(_ context.Context, a *domain.ResponderAssignment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.outages[a.OutageID]; !ok {
+		return domain.ErrNotFound
+	}
+	if a.Active() {
+		for _, existing := range m.responders {
+			if existing.OutageID == a.OutageID && existing.Role == a.Role && existing.Active() {
+				return domain.ErrConflict
+			}
 		}
 	}
-	return out, nil
+	cp := clone(*a)
+	m.responders[a.ID] = &cp
+	return nil
 }
 
-func (m *MemStorage) DeleteTag(_ context.Context, id uuid.UUID) error {
+func (m *MemStorage) EndResponderAssignment(_ context.Context, id uuid.UUID, by string, at time.Time) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if _, ok := m.tags[id]; !ok {
+	a, ok := m.responders[id]
+	if !ok || !a.Active() {
 		return domain.ErrNotFound
 	}
-	delete(m.tags, id)
+	a.UnassignedBy = by
+	a.UnassignedAt = &at
 	return nil
 }
 
-func (m *MemStorage) FindOutagesByTag(_ context.Context, key, value string) ([]*domain.Outage, error) {
+// ListResponderAssignments returns assignments oldest first, matching the
+// SQL backends.
+func (m *MemStorage) ListResponderAssignments(_ context.Context, outageID uuid.UUID) ([]*domain.ResponderAssignment, error) {
+	return m.listResponderAssignments(func(a *domain.ResponderAssignment) bool {
+		return a.OutageID == outageID
+	}), nil
+}
+
+func (m *MemStorage) ListResponderAssignmentsBetween(_ context.Context, since, until time.Time) ([]*domain.ResponderAssignment, error) {
+	return m.listResponderAssignments(func(a *domain.ResponderAssignment) bool {
+		return !a.AssignedAt.Before(since) && a.AssignedAt.Before(until)
+	}), nil
+}
+
+func (m *MemStorage) listResponderAssignments(match func(*domain.ResponderAssignment) bool) []*domain.ResponderAssignment {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	seen := make(map[uuid.UUID]bool)
-	var out []*domain.Outage
-	for _, t := range m.tags {
-		if t.Key == key && t.Value == value && !seen[t.OutageID] {
-			seen[t.OutageID] = true
-			if o, ok := m.outages[t.OutageID]; ok {
-				cp := clone(*o)
-				out = append(out, &cp)
-			}
+	var out []*domain.ResponderAssignment
+	for _, a := range m.responders {
+		if match(a) {
+			cp := clone(*a)
+			out = append(out, &cp)
 		}
 	}
-	return out, nil
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].AssignedAt.Before(out[j].AssignedAt)
+	})
+	return out
+}
+
+// --- Tag ---
+
+func (m *MemStorage) CreateTag(_ context.Context, t *domain.Tag) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.uniqueTagTaken(t) {
+		return domain.ErrConflict
+	}
+	cp := cloneTag(t)
+	m.tags[t.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) GetTag(_ context.Context, id uuid.UUID) (*domain.Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.tags[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := cloneTag(t)
+	return &cp, nil
+}
+
+// UpdateTag saves the key, value, custom fields and unique key flag,
+// keeping the outage and creation time as the SQL backends do.
+func (m *MemStorage) UpdateTag(_ context.Context, tag *domain.Tag) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tags[tag.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	cp := cloneTag(t)
+	cp.Key, cp.Value, cp.CustomFields, cp.UniqueKey = tag.Key, tag.Value, clone(tag.CustomFields), tag.UniqueKey
+	if m.uniqueTagTaken(&cp) {
+		return domain.ErrConflict
+	}
+	m.tags[tag.ID] = &cp
+	return nil
+}
+
+// uniqueTagTaken reports whether t has a unique key another tag of its
+// outage already has, as the SQL backends' unique index does. Callers hold
+// m.mu.
+func (m *MemStorage) uniqueTagTaken(t *domain.Tag) bool {
+	if !t.UniqueKey {
+		return false
+	}
+	for _, other := range m.tags {
+		if other.ID != t.ID && other.UniqueKey && other.OutageID == t.OutageID && other.Key == t.Key {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemStorage) ListTagsByOutage(_ context.Context, outageID uuid.UUID) ([]*domain.Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Tag
+	for _, t := range m.tags {
+		if t.OutageID == outageID {
+			cp := cloneTag(t)
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+// ListTagsByKey returns tags with the given key oldest first, matching the SQL backends.
+func (m *MemStorage) ListTagsByKey(_ context.Context, key string) ([]*domain.Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Tag
+	for _, t := range m.tags {
+		if t.Key == key {
+			cp := cloneTag(t)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+func (m *MemStorage) DeleteTag(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.tags[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.tags, id)
+	return nil
+}
+
+func (m *MemStorage) FindOutagesByTag(_ context.Context, key, value string) ([]*domain.Outage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := make(map[uuid.UUID]bool)
+	var out []*domain.Outage
+	for _, t := range m.tags {
+		if t.Key == key && t.Value == value && !seen[t.OutageID] {
+			seen[t.OutageID] = true
+			if o, ok := m.outages[t.OutageID]; ok && o.DeletedAt == nil {
+				cp := clone(*o)
+				out = append(out, &cp)
+			}
+		}
+	}
+	return out, nil
+}
+
+// FindOutagesByTagQuery returns the outages matching the query newest first,
+// matching the SQL backends.
+func (m *MemStorage) FindOutagesByTagQuery(_ context.Context, q domain.TagQuery) ([]*domain.Outage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tags := make(map[uuid.UUID][]domain.Tag)
+	for _, t := range m.tags {
+		tags[t.OutageID] = append(tags[t.OutageID], *t)
+	}
+	var out []*domain.Outage
+	for _, o := range m.outages {
+		if o.DeletedAt == nil && q.Matches(tags[o.ID]) {
+			cp := clone(*o)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.After(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+// ListTagKeys counts tags by key, most used first, matching the SQL backends.
+func (m *MemStorage) ListTagKeys(_ context.Context) ([]domain.TagUsage, error) {
+	return m.tagUsage(func(t *domain.Tag) (domain.TagUsage, bool) {
+		return domain.TagUsage{Key: t.Key}, true
+	}), nil
+}
+
+// ListTagValues counts the tags with key by value, most used first.
+func (m *MemStorage) ListTagValues(_ context.Context, key string) ([]domain.TagUsage, error) {
+	return m.tagUsage(func(t *domain.Tag) (domain.TagUsage, bool) {
+		return domain.TagUsage{Key: t.Key, Value: t.Value}, t.Key == key
+	}), nil
+}
+
+// tagUsage counts the tags of outages not in the trash, grouped by what
+// group returns for each tag it selects
+func (m *MemStorage) tagUsage(group func(*domain.Tag) (domain.TagUsage, bool)) []domain.TagUsage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	counts := make(map[domain.TagUsage]int)
+	for _, t := range m.tags {
+		if o, ok := m.outages[t.OutageID]; !ok || o.DeletedAt != nil {
+			continue
+		}
+		if u, ok := group(t); ok {
+			counts[u]++
+		}
+	}
+	var out []domain.TagUsage
+	for u, n := range counts {
+		u.Count = n
+		out = append(out, u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		if out[i].Key != out[j].Key {
+			return out[i].Key < out[j].Key
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// --- Alert events ---
+
+// CreateAlertEvent ignores an event whose external ID is already recorded
+// for the alert, matching the unique constraint in the SQL backends.
+func (m *MemStorage) CreateAlertEvent(_ context.Context, e *domain.AlertEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.alertEvents {
+		if existing.AlertID == e.AlertID && existing.ExternalID == e.ExternalID {
+			return nil
+		}
+	}
+	cp := clone(*e)
+	m.alertEvents[e.ID] = &cp
+	return nil
+}
+
+// ListAlertEventsByOutage returns alert events oldest first, matching the
+// SQL backends.
+func (m *MemStorage) ListAlertEventsByOutage(_ context.Context, outageID uuid.UUID) ([]*domain.AlertEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.AlertEvent
+	for _, e := range m.alertEvents {
+		if a, ok := m.alerts[e.AlertID]; ok && a.OutageID == outageID {
+			cp := clone(*e)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].OccurredAt.Before(out[j].OccurredAt)
+	})
+	return out, nil
+}
+
+// --- Status changes ---
+
+func (m *MemStorage) CreateStatusChange(_ context.Context, c *domain.StatusChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := clone(*c)
+	m.statusChanges[c.ID] = &cp
+	return nil
+}
+
+// ListStatusChangesByOutage returns status changes oldest first, matching the
+// SQL backends.
+func (m *MemStorage) ListStatusChangesByOutage(_ context.Context, outageID uuid.UUID) ([]*domain.StatusChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.StatusChange
+	for _, c := range m.statusChanges {
+		if c.OutageID == outageID {
+			cp := clone(*c)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ChangedAt.Before(out[j].ChangedAt)
+	})
+	return out, nil
+}
+
+// --- Preferences ---
+
+func (m *MemStorage) GetUserPreferences(_ context.Context, subject string) (*domain.UserPreferences, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.preferences[subject]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := clone(*p)
+	return &cp, nil
+}
+
+func (m *MemStorage) UpsertUserPreferences(_ context.Context, p *domain.UserPreferences) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := clone(*p)
+	m.preferences[p.Subject] = &cp
+	return nil
+}
+
+// --- Reviews ---
+
+func (m *MemStorage) GetOutageReview(_ context.Context, outageID uuid.UUID) (*domain.OutageReview, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.reviews[outageID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := clone(*r)
+	return &cp, nil
+}
+
+func (m *MemStorage) UpsertOutageReview(_ context.Context, r *domain.OutageReview) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := clone(*r)
+	if existing, ok := m.reviews[r.OutageID]; ok {
+		cp.CreatedAt = existing.CreatedAt
+	}
+	m.reviews[r.OutageID] = &cp
+	return nil
+}
+
+// ListOutageReviews returns reviews oldest first, matching the SQL backends.
+func (m *MemStorage) ListOutageReviews(_ context.Context, status string) ([]*domain.OutageReview, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.OutageReview
+	for _, r := range m.reviews {
+		if status == "" || r.Status == status {
+			cp := clone(*r)
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+// --- Sync cursors ---
+
+func (m *MemStorage) GetSyncCursor(_ context.Context, source string) (*domain.SyncCursor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.syncCursors[source]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (m *MemStorage) UpsertSyncCursor(_ context.Context, c *domain.SyncCursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *c
+	m.syncCursors[c.Source] = &cp
+	return nil
+}
+
+// --- Export watermarks ---
+
+func (m *MemStorage) GetExportWatermark(_ context.Context, table string) (*domain.ExportWatermark, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	w, ok := m.watermarks[table]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *w
+	return &cp, nil
+}
+
+func (m *MemStorage) UpsertExportWatermark(_ context.Context, w *domain.ExportWatermark) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *w
+	m.watermarks[w.Table] = &cp
+	return nil
+}
+
+// --- Processed events ---
+
+func (m *MemStorage) CreateProcessedEvent(_ context.Context, e *domain.ProcessedEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [2]string{e.Source, e.EventID}
+	if _, ok := m.events[key]; ok {
+		return domain.ErrConflict
+	}
+	m.events[key] = e.ProcessedAt
+	return nil
+}
+
+func (m *MemStorage) PurgeProcessedEvents(_ context.Context, source string, processedBefore time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for key, at := range m.events {
+		if key[0] == source && at.Before(processedBefore) {
+			delete(m.events, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// --- Import runs ---
+
+// copyImportRun returns a copy of r that shares no slices with it
+func copyImportRun(r *domain.ImportRun) *domain.ImportRun {
+	cp := *r
+	cp.TeamIDs = append([]string(nil), r.TeamIDs...)
+	if len(cp.TeamIDs) == 0 {
+		cp.TeamIDs = nil
+	}
+	return &cp
+}
+
+func (m *MemStorage) CreateImportRun(_ context.Context, r *domain.ImportRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.importRuns[r.ID] = copyImportRun(r)
+	return nil
+}
+
+func (m *MemStorage) UpdateImportRun(_ context.Context, r *domain.ImportRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.importRuns[r.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	existing.Status = r.Status
+	existing.Offset = r.Offset
+	existing.LastItemAt = r.LastItemAt
+	existing.Stats = r.Stats
+	existing.Error = r.Error
+	existing.UpdatedAt = r.UpdatedAt
+	existing.FinishedAt = r.FinishedAt
+	return nil
+}
+
+func (m *MemStorage) GetImportRun(_ context.Context, id uuid.UUID) (*domain.ImportRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.importRuns[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return copyImportRun(r), nil
+}
+
+func (m *MemStorage) ListImportRuns(_ context.Context, provider string) ([]*domain.ImportRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var runs []*domain.ImportRun
+	for _, r := range m.importRuns {
+		if provider == "" || r.Provider == provider {
+			runs = append(runs, copyImportRun(r))
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.After(runs[j].StartedAt) })
+	return runs, nil
+}
+
+// --- Retention runs ---
+
+func (m *MemStorage) CreateRetentionRun(_ context.Context, r *domain.RetentionRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *r
+	m.retention[r.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) UpdateRetentionRun(_ context.Context, r *domain.RetentionRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.retention[r.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	existing.Status = r.Status
+	existing.ArchivedOutages = r.ArchivedOutages
+	existing.ArchiveKey = r.ArchiveKey
+	existing.ArchivePurgedAt = r.ArchivePurgedAt
+	existing.PurgedArchives = r.PurgedArchives
+	existing.Error = r.Error
+	existing.FinishedAt = r.FinishedAt
+	return nil
+}
+
+func (m *MemStorage) GetRetentionRun(_ context.Context, id uuid.UUID) (*domain.RetentionRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.retention[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *r
+	return &cp, nil
+}
+
+func (m *MemStorage) ListRetentionRuns(_ context.Context) ([]*domain.RetentionRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var runs []*domain.RetentionRun
+	for _, r := range m.retention {
+		cp := *r
+		runs = append(runs, &cp)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.After(runs[j].StartedAt) })
+	return runs, nil
+}
+
+// --- Saved views ---
+
+// savedViewNamed reports whether a view other than id is called name.
+// Callers must hold m.mu.
+func (m *MemStorage) savedViewNamed(name string, id uuid.UUID) bool {
+	for _, v := range m.savedViews {
+		if v.Name == name && v.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemStorage) CreateSavedView(_ context.Context, v *domain.SavedView) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.savedViewNamed(v.Name, v.ID) {
+		return domain.ErrConflict
+	}
+	cp := clone(*v)
+	m.savedViews[v.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) GetSavedView(_ context.Context, id uuid.UUID) (*domain.SavedView, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.savedViews[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := clone(*v)
+	return &cp, nil
+}
+
+func (m *MemStorage) ListSavedViews(_ context.Context) ([]*domain.SavedView, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var views []*domain.SavedView
+	for _, v := range m.savedViews {
+		cp := clone(*v)
+		views = append(views, &cp)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views, nil
+}
+
+func (m *MemStorage) UpdateSavedView(_ context.Context, v *domain.SavedView) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.savedViews[v.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if m.savedViewNamed(v.Name, v.ID) {
+		return domain.ErrConflict
+	}
+	cp := clone(*v)
+	cp.Owner = existing.Owner
+	cp.CreatedAt = existing.CreatedAt
+	m.savedViews[v.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) DeleteSavedView(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.savedViews[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.savedViews, id)
+	return nil
+}
+
+// --- Action items ---
+
+func (m *MemStorage) CreateActionItem(_ context.Context, item *domain.ActionItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := clone(*item)
+	m.actionItems[item.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) GetActionItem(_ context.Context, id uuid.UUID) (*domain.ActionItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, ok := m.actionItems[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := clone(*item)
+	return &cp, nil
+}
+
+func (m *MemStorage) ListActionItemsByOutage(_ context.Context, outageID uuid.UUID) ([]*domain.ActionItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var items []*domain.ActionItem
+	for _, item := range m.actionItems {
+		if item.OutageID == outageID {
+			cp := clone(*item)
+			items = append(items, &cp)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
+	return items, nil
+}
+
+func (m *MemStorage) ListOverdueActionItems(_ context.Context, before time.Time) ([]*domain.ActionItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var items []*domain.ActionItem
+	for _, item := range m.actionItems {
+		if item.Overdue(before) {
+			cp := clone(*item)
+			items = append(items, &cp)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DueDate.Before(*items[j].DueDate) })
+	return items, nil
+}
+
+func (m *MemStorage) UpdateActionItem(_ context.Context, item *domain.ActionItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.actionItems[item.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	cp := clone(*item)
+	cp.OutageID = existing.OutageID
+	cp.CreatedBy = existing.CreatedBy
+	cp.CreatedAt = existing.CreatedAt
+	m.actionItems[item.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) DeleteActionItem(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.actionItems[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.actionItems, id)
+	return nil
+}
+
+// --- Service catalogue ---
+
+func (m *MemStorage) CreateService(_ context.Context, svc *domain.Service) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.services[svc.Name]; ok {
+		return domain.ErrConflict
+	}
+	cp := clone(*svc)
+	m.services[svc.Name] = &cp
+	return nil
+}
+
+func (m *MemStorage) GetService(_ context.Context, name string) (*domain.Service, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	svc, ok := m.services[name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := clone(*svc)
+	return &cp, nil
+}
+
+func (m *MemStorage) ListServices(_ context.Context) ([]*domain.Service, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var services []*domain.Service
+	for _, svc := range m.services {
+		cp := clone(*svc)
+		services = append(services, &cp)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+func (m *MemStorage) UpdateService(_ context.Context, svc *domain.Service) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.services[svc.Name]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	cp := clone(*svc)
+	cp.CreatedAt = existing.CreatedAt
+	m.services[svc.Name] = &cp
+	return nil
+}
+
+func (m *MemStorage) DeleteService(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.services[name]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.services, name)
+	return nil
+}
+
+// --- Watchers ---
+
+func (m *MemStorage) SaveWatcher(_ context.Context, watcher *domain.Watcher) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [2]string{watcher.OutageID.String(), watcher.Email}
+	cp := clone(*watcher)
+	if existing, ok := m.watchers[key]; ok {
+		cp.CreatedAt = existing.CreatedAt
+	}
+	m.watchers[key] = &cp
+	return nil
+}
+
+func (m *MemStorage) RemoveWatcher(_ context.Context, outageID uuid.UUID, email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [2]string{outageID.String(), email}
+	if _, ok := m.watchers[key]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.watchers, key)
+	return nil
+}
+
+func (m *MemStorage) ListWatchers(_ context.Context, outageID uuid.UUID) ([]*domain.Watcher, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var watchers []*domain.Watcher
+	for _, w := range m.watchers {
+		if w.OutageID == outageID {
+			cp := clone(*w)
+			watchers = append(watchers, &cp)
+		}
+	}
+	sort.Slice(watchers, func(i, j int) bool {
+		if !watchers[i].CreatedAt.Equal(watchers[j].CreatedAt) {
+			return watchers[i].CreatedAt.Before(watchers[j].CreatedAt)
+		}
+		return watchers[i].Email < watchers[j].Email
+	})
+	return watchers, nil
+}
+
+// --- Outage relations ---
+
+func (m *MemStorage) CreateOutageRelation(_ context.Context, relation *domain.OutageRelation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.relations {
+		if r.OutageID == relation.OutageID && r.RelatedOutageID == relation.RelatedOutageID && r.Type == relation.Type {
+			return domain.ErrConflict
+		}
+	}
+	cp := clone(*relation)
+	m.relations[relation.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) GetOutageRelation(_ context.Context, id uuid.UUID) (*domain.OutageRelation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.relations[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := clone(*r)
+	return &cp, nil
+}
+
+func (m *MemStorage) ListOutageRelations(_ context.Context, outageID uuid.UUID) ([]*domain.OutageRelation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var relations []*domain.OutageRelation
+	for _, r := range m.relations {
+		if r.OutageID != outageID && r.RelatedOutageID != outageID {
+			continue
+		}
+		otherID := r.RelatedOutageID
+		if r.RelatedOutageID == outageID {
+			otherID = r.OutageID
+		}
+		other, ok := m.outages[otherID]
+		if !ok || other.DeletedAt != nil {
+			continue
+		}
+		cp := clone(*r)
+		cp.RelatedOutageTitle = other.Title
+		relations = append(relations, &cp)
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		if !relations[i].CreatedAt.Equal(relations[j].CreatedAt) {
+			return relations[i].CreatedAt.Before(relations[j].CreatedAt)
+		}
+		return relations[i].ID.String() < relations[j].ID.String()
+	})
+	return relations, nil
+}
+
+func (m *MemStorage) DeleteOutageRelation(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.relations[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.relations, id)
+	return nil
+}
+
+// --- Source ingestion ---
+
+// ingestionRecord returns the record for source, creating it if needed.
+// Callers must hold m.mu for writing.
+func (m *MemStorage) ingestionRecord(source string) *domain.IngestionRecord {
+	r, ok := m.ingestion[source]
+	if !ok {
+		r = &domain.IngestionRecord{Source: source}
+		m.ingestion[source] = r
+	}
+	return r
+}
+
+func (m *MemStorage) RecordIngestionSuccess(_ context.Context, source string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := m.ingestionRecord(source)
+	r.LastSuccessAt = &at
+	r.UpdatedAt = at
+	return nil
+}
+
+func (m *MemStorage) RecordIngestionFailure(_ context.Context, source string, at time.Time, message string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := m.ingestionRecord(source)
+	r.LastFailureAt = &at
+	r.LastError = message
+	r.UpdatedAt = at
+	return nil
+}
+
+func (m *MemStorage) ListIngestionRecords(_ context.Context) ([]*domain.IngestionRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.IngestionRecord
+	for _, r := range m.ingestion {
+		cp := *r
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out, nil
+}
+
+// --- Config resources ---
+
+// ListConfigResources returns resources ordered by kind and name, matching the SQL backends.
+func (m *MemStorage) ListConfigResources(_ context.Context, kind string) ([]*domain.ConfigResource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.ConfigResource
+	for _, r := range m.configs {
+		if kind == "" || r.Kind == kind {
+			cp := *r
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+func (m *MemStorage) GetConfigResource(_ context.Context, kind, name string) (*domain.ConfigResource, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.configs[kind+"/"+name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *r
+	return &cp, nil
+}
+
+func (m *MemStorage) UpsertConfigResource(_ context.Context, r *domain.ConfigResource) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *r
+	cp.Spec = append([]byte(nil), r.Spec...)
+	m.configs[r.Kind+"/"+r.Name] = &cp
+	return nil
+}
+
+func (m *MemStorage) DeleteConfigResource(_ context.Context, kind, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.configs[kind+"/"+name]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.configs, kind+"/"+name)
+	return nil
+}
+
+// --- Attachments ---
+
+func (m *MemStorage) CreateAttachment(_ context.Context, a *domain.Attachment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := clone(*a)
+	cp.StorageKey = a.StorageKey // excluded from JSON
+	m.attachments[a.ID] = &cp
+	return nil
+}
+
+func (m *MemStorage) GetAttachment(_ context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.attachments[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := clone(*a)
+	cp.StorageKey = a.StorageKey
+	return &cp, nil
+}
+
+// ListAttachmentsByOutage returns attachments oldest first, matching the SQL
+// backends.
+func (m *MemStorage) ListAttachmentsByOutage(_ context.Context, outageID uuid.UUID) ([]*domain.Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*domain.Attachment
+	for _, a := range m.attachments {
+		if a.OutageID == outageID {
+			cp := clone(*a)
+			cp.StorageKey = a.StorageKey
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (m *MemStorage) DeleteAttachment(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.attachments[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.attachments, id)
+	return nil
 }
@@ -0,0 +1,14 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/conall/outalator/storage"
+	"github.com/conall/outalator/storage/storagetest"
+)
+
+func TestMemStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(*testing.T) storage.Storage {
+		return NewMemStorage()
+	})
+}
@@ -0,0 +1,59 @@
+// Package tlsconfig builds the server TLS configuration shared by the HTTP
+// and gRPC listeners, so deployments can serve TLS, and require client
+// certificates (mTLS), without terminating it in a proxy.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Files names the PEM files of a server's TLS configuration
+type Files struct {
+	CertFile string // Certificate chain, leaf first
+	KeyFile  string // Private key of the leaf certificate
+	// ClientCAFile, when set, holds the CAs client certificates must chain
+	// to. Clients without a valid certificate are refused.
+	ClientCAFile string
+}
+
+// Enabled reports whether TLS is configured
+func (f Files) Enabled() bool {
+	return f.CertFile != "" || f.KeyFile != "" || f.ClientCAFile != ""
+}
+
+// Server loads the files into a server TLS configuration. It returns nil
+// when TLS is not configured, and an error when it is configured only
+// partly or a file cannot be loaded.
+func Server(f Files) (*tls.Config, error) {
+	if !f.Enabled() {
+		return nil, nil
+	}
+	if f.CertFile == "" || f.KeyFile == "" {
+		return nil, errors.New("TLS needs both a certificate and a key file")
+	}
+	cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if f.ClientCAFile != "" {
+		pem, err := os.ReadFile(f.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", f.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
@@ -0,0 +1,174 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCert is a certificate and key issued for a test
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+// issue creates a certificate signed by parent, or self-signed when parent
+// is nil
+func issue(t *testing.T, name string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// write saves c as PEM certificate and key files in dir
+func (c *testCert) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	ca := issue(t, "test CA", nil, true)
+	caFile, _ := ca.write(t, dir, "ca")
+	server := issue(t, "server", ca, false)
+	certFile, keyFile := server.write(t, dir, "server")
+	client := issue(t, "client", ca, false)
+	stranger := issue(t, "stranger", nil, false)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(cfg *tls.Config, clientCert *testCert) error {
+		t.Helper()
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		srv.TLS = cfg
+		srv.StartTLS()
+		defer srv.Close()
+
+		clientTLS := &tls.Config{RootCAs: roots}
+		if clientCert != nil {
+			clientTLS.Certificates = []tls.Certificate{clientCert.tlsCertificate()}
+		}
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+		resp, err := httpClient.Get(srv.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	cfg, err := Server(Files{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := get(cfg, nil); err != nil {
+		t.Errorf("TLS without client CA: %v", err)
+	}
+
+	cfg, err = Server(Files{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("ClientAuth = %v", cfg.ClientAuth)
+	}
+	if err := get(cfg, client); err != nil {
+		t.Errorf("mTLS with a client certificate: %v", err)
+	}
+	if err := get(cfg, nil); err == nil {
+		t.Error("mTLS without a client certificate succeeded")
+	}
+	if err := get(cfg, stranger); err == nil {
+		t.Error("mTLS with a certificate from another CA succeeded")
+	}
+}
+
+func TestServerConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := issue(t, "server", nil, false).write(t, dir, "server")
+	notPEM := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Server(Files{})
+	if cfg != nil || err != nil {
+		t.Errorf("no files: cfg = %v, err = %v", cfg, err)
+	}
+
+	tests := []struct {
+		name  string
+		files Files
+		want  string
+	}{
+		{"key missing", Files{CertFile: certFile}, "needs both a certificate and a key file"},
+		{"only client CA", Files{ClientCAFile: certFile}, "needs both a certificate and a key file"},
+		{"unreadable certificate", Files{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile}, "failed to load TLS certificate"},
+		{"unreadable client CA", Files{CertFile: certFile, KeyFile: keyFile, ClientCAFile: filepath.Join(dir, "missing.pem")}, "failed to read client CA file"},
+		{"empty client CA", Files{CertFile: certFile, KeyFile: keyFile, ClientCAFile: notPEM}, "no certificates found in client CA file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Server(tt.files)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
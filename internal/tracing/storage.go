@@ -0,0 +1,731 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/conall/outalator/storage")
+
+// tracedStorage creates a client span for every call to the wrapped
+// storage backend
+type tracedStorage struct {
+	next   storage.Storage
+	system string
+}
+
+// InstrumentStorage wraps s so that each storage operation is recorded as a
+// span. system names the backend (e.g. "postgresql") for the db.system
+// attribute.
+func InstrumentStorage(s storage.Storage, system string) storage.Storage {
+	return &tracedStorage{next: s, system: system}
+}
+
+// start begins a span for a storage operation
+func (s *tracedStorage) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", s.system),
+			attribute.String("db.operation", operation),
+		))
+}
+
+// end records err on span, unless it is a not-found, and ends the span
+func end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Outage operations
+
+func (s *tracedStorage) CreateOutage(ctx context.Context, outage *domain.Outage) (err error) {
+	ctx, span := s.start(ctx, "CreateOutage")
+	defer func() { end(span, err) }()
+	return s.next.CreateOutage(ctx, outage)
+}
+
+func (s *tracedStorage) GetOutage(ctx context.Context, id uuid.UUID) (_ *domain.Outage, err error) {
+	ctx, span := s.start(ctx, "GetOutage")
+	defer func() { end(span, err) }()
+	return s.next.GetOutage(ctx, id)
+}
+
+func (s *tracedStorage) GetOutageWith(ctx context.Context, id uuid.UUID, associations domain.OutageAssociations) (_ *domain.Outage, err error) {
+	ctx, span := s.start(ctx, "GetOutageWith")
+	defer func() { end(span, err) }()
+	return s.next.GetOutageWith(ctx, id, associations)
+}
+
+func (s *tracedStorage) LoadOutageAssociations(ctx context.Context, outages []*domain.Outage, associations domain.OutageAssociations) (err error) {
+	ctx, span := s.start(ctx, "LoadOutageAssociations")
+	defer func() { end(span, err) }()
+	return s.next.LoadOutageAssociations(ctx, outages, associations)
+}
+
+func (s *tracedStorage) ListOutages(ctx context.Context, limit, offset int, includeDeleted bool) (_ []*domain.Outage, err error) {
+	ctx, span := s.start(ctx, "ListOutages")
+	defer func() { end(span, err) }()
+	return s.next.ListOutages(ctx, limit, offset, includeDeleted)
+}
+
+func (s *tracedStorage) ListOutagesByTeams(ctx context.Context, teams []string, limit, offset int, includeDeleted bool) (_ []*domain.Outage, err error) {
+	ctx, span := s.start(ctx, "ListOutagesByTeams")
+	defer func() { end(span, err) }()
+	return s.next.ListOutagesByTeams(ctx, teams, limit, offset, includeDeleted)
+}
+
+func (s *tracedStorage) ListChildOutages(ctx context.Context, parentID uuid.UUID) (_ []*domain.Outage, err error) {
+	ctx, span := s.start(ctx, "ListChildOutages")
+	defer func() { end(span, err) }()
+	return s.next.ListChildOutages(ctx, parentID)
+}
+
+func (s *tracedStorage) ListPublicOutages(ctx context.Context) (_ []*domain.Outage, err error) {
+	ctx, span := s.start(ctx, "ListPublicOutages")
+	defer func() { end(span, err) }()
+	return s.next.ListPublicOutages(ctx)
+}
+
+func (s *tracedStorage) UpdateOutage(ctx context.Context, outage *domain.Outage) (err error) {
+	ctx, span := s.start(ctx, "UpdateOutage")
+	defer func() { end(span, err) }()
+	return s.next.UpdateOutage(ctx, outage)
+}
+
+func (s *tracedStorage) SetOutageParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, updatedAt time.Time) (err error) {
+	ctx, span := s.start(ctx, "SetOutageParent")
+	defer func() { end(span, err) }()
+	return s.next.SetOutageParent(ctx, id, parentID, updatedAt)
+}
+
+func (s *tracedStorage) DeleteOutage(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteOutage")
+	defer func() { end(span, err) }()
+	return s.next.DeleteOutage(ctx, id)
+}
+
+func (s *tracedStorage) TrashOutage(ctx context.Context, id uuid.UUID, at time.Time) (err error) {
+	ctx, span := s.start(ctx, "TrashOutage")
+	defer func() { end(span, err) }()
+	return s.next.TrashOutage(ctx, id, at)
+}
+
+func (s *tracedStorage) RestoreOutage(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "RestoreOutage")
+	defer func() { end(span, err) }()
+	return s.next.RestoreOutage(ctx, id)
+}
+
+func (s *tracedStorage) PurgeOutages(ctx context.Context, deletedBefore time.Time) (_ int, err error) {
+	ctx, span := s.start(ctx, "PurgeOutages")
+	defer func() { end(span, err) }()
+	return s.next.PurgeOutages(ctx, deletedBefore)
+}
+
+// Alert operations
+
+func (s *tracedStorage) CreateAlert(ctx context.Context, alert *domain.Alert) (err error) {
+	ctx, span := s.start(ctx, "CreateAlert")
+	defer func() { end(span, err) }()
+	return s.next.CreateAlert(ctx, alert)
+}
+
+func (s *tracedStorage) UpsertAlert(ctx context.Context, alert *domain.Alert) (_ *domain.Alert, _ bool, err error) {
+	ctx, span := s.start(ctx, "UpsertAlert")
+	defer func() { end(span, err) }()
+	return s.next.UpsertAlert(ctx, alert)
+}
+
+func (s *tracedStorage) CreateOutageWithAlert(ctx context.Context, outage *domain.Outage, alert *domain.Alert) (_ *domain.Alert, _ bool, err error) {
+	ctx, span := s.start(ctx, "CreateOutageWithAlert")
+	defer func() { end(span, err) }()
+	return s.next.CreateOutageWithAlert(ctx, outage, alert)
+}
+
+func (s *tracedStorage) GetAlert(ctx context.Context, id uuid.UUID) (_ *domain.Alert, err error) {
+	ctx, span := s.start(ctx, "GetAlert")
+	defer func() { end(span, err) }()
+	return s.next.GetAlert(ctx, id)
+}
+
+func (s *tracedStorage) GetAlertByExternalID(ctx context.Context, externalID, source string) (_ *domain.Alert, err error) {
+	ctx, span := s.start(ctx, "GetAlertByExternalID")
+	defer func() { end(span, err) }()
+	return s.next.GetAlertByExternalID(ctx, externalID, source)
+}
+
+func (s *tracedStorage) ListAlertsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.Alert, err error) {
+	ctx, span := s.start(ctx, "ListAlertsByOutage")
+	defer func() { end(span, err) }()
+	return s.next.ListAlertsByOutage(ctx, outageID)
+}
+
+func (s *tracedStorage) ListOpenAlerts(ctx context.Context, triggeredBefore time.Time) (_ []*domain.Alert, err error) {
+	ctx, span := s.start(ctx, "ListOpenAlerts")
+	defer func() { end(span, err) }()
+	return s.next.ListOpenAlerts(ctx, triggeredBefore)
+}
+
+func (s *tracedStorage) ListAlertsTriggeredBetween(ctx context.Context, since, until time.Time) (_ []*domain.Alert, err error) {
+	ctx, span := s.start(ctx, "ListAlertsTriggeredBetween")
+	defer func() { end(span, err) }()
+	return s.next.ListAlertsTriggeredBetween(ctx, since, until)
+}
+
+func (s *tracedStorage) ListAlerts(ctx context.Context, query domain.AlertQuery) (_ []*domain.Alert, err error) {
+	ctx, span := s.start(ctx, "ListAlerts")
+	defer func() { end(span, err) }()
+	return s.next.ListAlerts(ctx, query)
+}
+
+func (s *tracedStorage) UpdateAlert(ctx context.Context, alert *domain.Alert) (err error) {
+	ctx, span := s.start(ctx, "UpdateAlert")
+	defer func() { end(span, err) }()
+	return s.next.UpdateAlert(ctx, alert)
+}
+
+// Alert event operations
+
+func (s *tracedStorage) CreateAlertEvent(ctx context.Context, event *domain.AlertEvent) (err error) {
+	ctx, span := s.start(ctx, "CreateAlertEvent")
+	defer func() { end(span, err) }()
+	return s.next.CreateAlertEvent(ctx, event)
+}
+
+func (s *tracedStorage) ListAlertEventsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.AlertEvent, err error) {
+	ctx, span := s.start(ctx, "ListAlertEventsByOutage")
+	defer func() { end(span, err) }()
+	return s.next.ListAlertEventsByOutage(ctx, outageID)
+}
+
+// Note operations
+
+func (s *tracedStorage) CreateNote(ctx context.Context, note *domain.Note) (err error) {
+	ctx, span := s.start(ctx, "CreateNote")
+	defer func() { end(span, err) }()
+	return s.next.CreateNote(ctx, note)
+}
+
+func (s *tracedStorage) GetNote(ctx context.Context, id uuid.UUID) (_ *domain.Note, err error) {
+	ctx, span := s.start(ctx, "GetNote")
+	defer func() { end(span, err) }()
+	return s.next.GetNote(ctx, id)
+}
+
+func (s *tracedStorage) ListNotesByOutage(ctx context.Context, outageID uuid.UUID, includeDeleted bool) (_ []*domain.Note, err error) {
+	ctx, span := s.start(ctx, "ListNotesByOutage")
+	defer func() { end(span, err) }()
+	return s.next.ListNotesByOutage(ctx, outageID, includeDeleted)
+}
+
+func (s *tracedStorage) UpdateNote(ctx context.Context, note *domain.Note) (err error) {
+	ctx, span := s.start(ctx, "UpdateNote")
+	defer func() { end(span, err) }()
+	return s.next.UpdateNote(ctx, note)
+}
+
+func (s *tracedStorage) DeleteNote(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteNote")
+	defer func() { end(span, err) }()
+	return s.next.DeleteNote(ctx, id)
+}
+
+func (s *tracedStorage) TrashNote(ctx context.Context, id uuid.UUID, at time.Time) (err error) {
+	ctx, span := s.start(ctx, "TrashNote")
+	defer func() { end(span, err) }()
+	return s.next.TrashNote(ctx, id, at)
+}
+
+func (s *tracedStorage) RestoreNote(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "RestoreNote")
+	defer func() { end(span, err) }()
+	return s.next.RestoreNote(ctx, id)
+}
+
+func (s *tracedStorage) PurgeNotes(ctx context.Context, deletedBefore time.Time) (_ int, err error) {
+	ctx, span := s.start(ctx, "PurgeNotes")
+	defer func() { end(span, err) }()
+	return s.next.PurgeNotes(ctx, deletedBefore)
+}
+
+// Note revision operations
+
+func (s *tracedStorage) CreateNoteRevision(ctx context.Context, revision *domain.NoteRevision) (err error) {
+	ctx, span := s.start(ctx, "CreateNoteRevision")
+	defer func() { end(span, err) }()
+	return s.next.CreateNoteRevision(ctx, revision)
+}
+
+func (s *tracedStorage) ListNoteRevisions(ctx context.Context, noteID uuid.UUID) (_ []*domain.NoteRevision, err error) {
+	ctx, span := s.start(ctx, "ListNoteRevisions")
+	defer func() { end(span, err) }()
+	return s.next.ListNoteRevisions(ctx, noteID)
+}
+
+// Responder assignment operations
+
+func (s *tracedStorage) CreateResponderAssignment(ctx context.Context, assignment *domain.ResponderAssignment) (err error) {
+	ctx, span := s.start(ctx, "CreateResponderAssignment")
+	defer func() { end(span, err) }()
+	return s.next.CreateResponderAssignment(ctx, assignment)
+}
+
+func (s *tracedStorage) EndResponderAssignment(ctx context.Context, id uuid.UUID, by string, at time.Time) (err error) {
+	ctx, span := s.start(ctx, "EndResponderAssignment")
+	defer func() { end(span, err) }()
+	return s.next.EndResponderAssignment(ctx, id, by, at)
+}
+
+func (s *tracedStorage) ListResponderAssignments(ctx context.Context, outageID uuid.UUID) (_ []*domain.ResponderAssignment, err error) {
+	ctx, span := s.start(ctx, "ListResponderAssignments")
+	defer func() { end(span, err) }()
+	return s.next.ListResponderAssignments(ctx, outageID)
+}
+
+func (s *tracedStorage) ListResponderAssignmentsBetween(ctx context.Context, since, until time.Time) (_ []*domain.ResponderAssignment, err error) {
+	ctx, span := s.start(ctx, "ListResponderAssignmentsBetween")
+	defer func() { end(span, err) }()
+	return s.next.ListResponderAssignmentsBetween(ctx, since, until)
+}
+
+// Tag operations
+
+func (s *tracedStorage) CreateTag(ctx context.Context, tag *domain.Tag) (err error) {
+	ctx, span := s.start(ctx, "CreateTag")
+	defer func() { end(span, err) }()
+	return s.next.CreateTag(ctx, tag)
+}
+
+func (s *tracedStorage) GetTag(ctx context.Context, id uuid.UUID) (_ *domain.Tag, err error) {
+	ctx, span := s.start(ctx, "GetTag")
+	defer func() { end(span, err) }()
+	return s.next.GetTag(ctx, id)
+}
+
+func (s *tracedStorage) UpdateTag(ctx context.Context, tag *domain.Tag) (err error) {
+	ctx, span := s.start(ctx, "UpdateTag")
+	defer func() { end(span, err) }()
+	return s.next.UpdateTag(ctx, tag)
+}
+
+func (s *tracedStorage) ListTagsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.Tag, err error) {
+	ctx, span := s.start(ctx, "ListTagsByOutage")
+	defer func() { end(span, err) }()
+	return s.next.ListTagsByOutage(ctx, outageID)
+}
+
+func (s *tracedStorage) ListTagsByKey(ctx context.Context, key string) (_ []*domain.Tag, err error) {
+	ctx, span := s.start(ctx, "ListTagsByKey")
+	defer func() { end(span, err) }()
+	return s.next.ListTagsByKey(ctx, key)
+}
+
+func (s *tracedStorage) DeleteTag(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteTag")
+	defer func() { end(span, err) }()
+	return s.next.DeleteTag(ctx, id)
+}
+
+func (s *tracedStorage) FindOutagesByTag(ctx context.Context, key, value string) (_ []*domain.Outage, err error) {
+	ctx, span := s.start(ctx, "FindOutagesByTag")
+	defer func() { end(span, err) }()
+	return s.next.FindOutagesByTag(ctx, key, value)
+}
+
+func (s *tracedStorage) FindOutagesByTagQuery(ctx context.Context, query domain.TagQuery) (_ []*domain.Outage, err error) {
+	ctx, span := s.start(ctx, "FindOutagesByTagQuery")
+	defer func() { end(span, err) }()
+	return s.next.FindOutagesByTagQuery(ctx, query)
+}
+
+func (s *tracedStorage) ListTagKeys(ctx context.Context) (_ []domain.TagUsage, err error) {
+	ctx, span := s.start(ctx, "ListTagKeys")
+	defer func() { end(span, err) }()
+	return s.next.ListTagKeys(ctx)
+}
+
+func (s *tracedStorage) ListTagValues(ctx context.Context, key string) (_ []domain.TagUsage, err error) {
+	ctx, span := s.start(ctx, "ListTagValues")
+	defer func() { end(span, err) }()
+	return s.next.ListTagValues(ctx, key)
+}
+
+// Status change operations
+
+func (s *tracedStorage) CreateStatusChange(ctx context.Context, change *domain.StatusChange) (err error) {
+	ctx, span := s.start(ctx, "CreateStatusChange")
+	defer func() { end(span, err) }()
+	return s.next.CreateStatusChange(ctx, change)
+}
+
+func (s *tracedStorage) ListStatusChangesByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.StatusChange, err error) {
+	ctx, span := s.start(ctx, "ListStatusChangesByOutage")
+	defer func() { end(span, err) }()
+	return s.next.ListStatusChangesByOutage(ctx, outageID)
+}
+
+// Preference operations
+
+func (s *tracedStorage) GetUserPreferences(ctx context.Context, subject string) (_ *domain.UserPreferences, err error) {
+	ctx, span := s.start(ctx, "GetUserPreferences")
+	defer func() { end(span, err) }()
+	return s.next.GetUserPreferences(ctx, subject)
+}
+
+func (s *tracedStorage) UpsertUserPreferences(ctx context.Context, prefs *domain.UserPreferences) (err error) {
+	ctx, span := s.start(ctx, "UpsertUserPreferences")
+	defer func() { end(span, err) }()
+	return s.next.UpsertUserPreferences(ctx, prefs)
+}
+
+// Review operations
+
+func (s *tracedStorage) GetOutageReview(ctx context.Context, outageID uuid.UUID) (_ *domain.OutageReview, err error) {
+	ctx, span := s.start(ctx, "GetOutageReview")
+	defer func() { end(span, err) }()
+	return s.next.GetOutageReview(ctx, outageID)
+}
+
+func (s *tracedStorage) UpsertOutageReview(ctx context.Context, review *domain.OutageReview) (err error) {
+	ctx, span := s.start(ctx, "UpsertOutageReview")
+	defer func() { end(span, err) }()
+	return s.next.UpsertOutageReview(ctx, review)
+}
+
+func (s *tracedStorage) ListOutageReviews(ctx context.Context, status string) (_ []*domain.OutageReview, err error) {
+	ctx, span := s.start(ctx, "ListOutageReviews")
+	defer func() { end(span, err) }()
+	return s.next.ListOutageReviews(ctx, status)
+}
+
+// Sync cursor operations
+
+func (s *tracedStorage) GetSyncCursor(ctx context.Context, source string) (_ *domain.SyncCursor, err error) {
+	ctx, span := s.start(ctx, "GetSyncCursor")
+	defer func() { end(span, err) }()
+	return s.next.GetSyncCursor(ctx, source)
+}
+
+func (s *tracedStorage) UpsertSyncCursor(ctx context.Context, cursor *domain.SyncCursor) (err error) {
+	ctx, span := s.start(ctx, "UpsertSyncCursor")
+	defer func() { end(span, err) }()
+	return s.next.UpsertSyncCursor(ctx, cursor)
+}
+
+// Export watermark operations
+
+func (s *tracedStorage) GetExportWatermark(ctx context.Context, table string) (_ *domain.ExportWatermark, err error) {
+	ctx, span := s.start(ctx, "GetExportWatermark")
+	defer func() { end(span, err) }()
+	return s.next.GetExportWatermark(ctx, table)
+}
+
+func (s *tracedStorage) UpsertExportWatermark(ctx context.Context, watermark *domain.ExportWatermark) (err error) {
+	ctx, span := s.start(ctx, "UpsertExportWatermark")
+	defer func() { end(span, err) }()
+	return s.next.UpsertExportWatermark(ctx, watermark)
+}
+
+// Retention run operations
+
+func (s *tracedStorage) CreateRetentionRun(ctx context.Context, run *domain.RetentionRun) (err error) {
+	ctx, span := s.start(ctx, "CreateRetentionRun")
+	defer func() { end(span, err) }()
+	return s.next.CreateRetentionRun(ctx, run)
+}
+
+func (s *tracedStorage) UpdateRetentionRun(ctx context.Context, run *domain.RetentionRun) (err error) {
+	ctx, span := s.start(ctx, "UpdateRetentionRun")
+	defer func() { end(span, err) }()
+	return s.next.UpdateRetentionRun(ctx, run)
+}
+
+func (s *tracedStorage) GetRetentionRun(ctx context.Context, id uuid.UUID) (_ *domain.RetentionRun, err error) {
+	ctx, span := s.start(ctx, "GetRetentionRun")
+	defer func() { end(span, err) }()
+	return s.next.GetRetentionRun(ctx, id)
+}
+
+func (s *tracedStorage) ListRetentionRuns(ctx context.Context) (_ []*domain.RetentionRun, err error) {
+	ctx, span := s.start(ctx, "ListRetentionRuns")
+	defer func() { end(span, err) }()
+	return s.next.ListRetentionRuns(ctx)
+}
+
+// Source ingestion operations
+
+func (s *tracedStorage) RecordIngestionSuccess(ctx context.Context, source string, at time.Time) (err error) {
+	ctx, span := s.start(ctx, "RecordIngestionSuccess")
+	defer func() { end(span, err) }()
+	return s.next.RecordIngestionSuccess(ctx, source, at)
+}
+
+func (s *tracedStorage) RecordIngestionFailure(ctx context.Context, source string, at time.Time, message string) (err error) {
+	ctx, span := s.start(ctx, "RecordIngestionFailure")
+	defer func() { end(span, err) }()
+	return s.next.RecordIngestionFailure(ctx, source, at, message)
+}
+
+func (s *tracedStorage) ListIngestionRecords(ctx context.Context) (_ []*domain.IngestionRecord, err error) {
+	ctx, span := s.start(ctx, "ListIngestionRecords")
+	defer func() { end(span, err) }()
+	return s.next.ListIngestionRecords(ctx)
+}
+
+// Config resource operations
+
+func (s *tracedStorage) ListConfigResources(ctx context.Context, kind string) (_ []*domain.ConfigResource, err error) {
+	ctx, span := s.start(ctx, "ListConfigResources")
+	defer func() { end(span, err) }()
+	return s.next.ListConfigResources(ctx, kind)
+}
+
+func (s *tracedStorage) GetConfigResource(ctx context.Context, kind, name string) (_ *domain.ConfigResource, err error) {
+	ctx, span := s.start(ctx, "GetConfigResource")
+	defer func() { end(span, err) }()
+	return s.next.GetConfigResource(ctx, kind, name)
+}
+
+func (s *tracedStorage) UpsertConfigResource(ctx context.Context, resource *domain.ConfigResource) (err error) {
+	ctx, span := s.start(ctx, "UpsertConfigResource")
+	defer func() { end(span, err) }()
+	return s.next.UpsertConfigResource(ctx, resource)
+}
+
+func (s *tracedStorage) DeleteConfigResource(ctx context.Context, kind, name string) (err error) {
+	ctx, span := s.start(ctx, "DeleteConfigResource")
+	defer func() { end(span, err) }()
+	return s.next.DeleteConfigResource(ctx, kind, name)
+}
+
+// Attachment operations
+
+func (s *tracedStorage) CreateAttachment(ctx context.Context, attachment *domain.Attachment) (err error) {
+	ctx, span := s.start(ctx, "CreateAttachment")
+	defer func() { end(span, err) }()
+	return s.next.CreateAttachment(ctx, attachment)
+}
+
+func (s *tracedStorage) GetAttachment(ctx context.Context, id uuid.UUID) (_ *domain.Attachment, err error) {
+	ctx, span := s.start(ctx, "GetAttachment")
+	defer func() { end(span, err) }()
+	return s.next.GetAttachment(ctx, id)
+}
+
+func (s *tracedStorage) ListAttachmentsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.Attachment, err error) {
+	ctx, span := s.start(ctx, "ListAttachmentsByOutage")
+	defer func() { end(span, err) }()
+	return s.next.ListAttachmentsByOutage(ctx, outageID)
+}
+
+func (s *tracedStorage) DeleteAttachment(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteAttachment")
+	defer func() { end(span, err) }()
+	return s.next.DeleteAttachment(ctx, id)
+}
+
+// Processed event operations
+
+func (s *tracedStorage) CreateProcessedEvent(ctx context.Context, event *domain.ProcessedEvent) (err error) {
+	ctx, span := s.start(ctx, "CreateProcessedEvent")
+	defer func() { end(span, err) }()
+	return s.next.CreateProcessedEvent(ctx, event)
+}
+
+func (s *tracedStorage) PurgeProcessedEvents(ctx context.Context, source string, processedBefore time.Time) (_ int, err error) {
+	ctx, span := s.start(ctx, "PurgeProcessedEvents")
+	defer func() { end(span, err) }()
+	return s.next.PurgeProcessedEvents(ctx, source, processedBefore)
+}
+
+func (s *tracedStorage) CreateImportRun(ctx context.Context, run *domain.ImportRun) (err error) {
+	ctx, span := s.start(ctx, "CreateImportRun")
+	defer func() { end(span, err) }()
+	return s.next.CreateImportRun(ctx, run)
+}
+
+func (s *tracedStorage) UpdateImportRun(ctx context.Context, run *domain.ImportRun) (err error) {
+	ctx, span := s.start(ctx, "UpdateImportRun")
+	defer func() { end(span, err) }()
+	return s.next.UpdateImportRun(ctx, run)
+}
+
+func (s *tracedStorage) GetImportRun(ctx context.Context, id uuid.UUID) (_ *domain.ImportRun, err error) {
+	ctx, span := s.start(ctx, "GetImportRun")
+	defer func() { end(span, err) }()
+	return s.next.GetImportRun(ctx, id)
+}
+
+func (s *tracedStorage) ListImportRuns(ctx context.Context, provider string) (_ []*domain.ImportRun, err error) {
+	ctx, span := s.start(ctx, "ListImportRuns")
+	defer func() { end(span, err) }()
+	return s.next.ListImportRuns(ctx, provider)
+}
+
+func (s *tracedStorage) CreateSavedView(ctx context.Context, view *domain.SavedView) (err error) {
+	ctx, span := s.start(ctx, "CreateSavedView")
+	defer func() { end(span, err) }()
+	return s.next.CreateSavedView(ctx, view)
+}
+
+func (s *tracedStorage) GetSavedView(ctx context.Context, id uuid.UUID) (_ *domain.SavedView, err error) {
+	ctx, span := s.start(ctx, "GetSavedView")
+	defer func() { end(span, err) }()
+	return s.next.GetSavedView(ctx, id)
+}
+
+func (s *tracedStorage) ListSavedViews(ctx context.Context) (_ []*domain.SavedView, err error) {
+	ctx, span := s.start(ctx, "ListSavedViews")
+	defer func() { end(span, err) }()
+	return s.next.ListSavedViews(ctx)
+}
+
+func (s *tracedStorage) UpdateSavedView(ctx context.Context, view *domain.SavedView) (err error) {
+	ctx, span := s.start(ctx, "UpdateSavedView")
+	defer func() { end(span, err) }()
+	return s.next.UpdateSavedView(ctx, view)
+}
+
+func (s *tracedStorage) DeleteSavedView(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteSavedView")
+	defer func() { end(span, err) }()
+	return s.next.DeleteSavedView(ctx, id)
+}
+
+func (s *tracedStorage) CreateService(ctx context.Context, svc *domain.Service) (err error) {
+	ctx, span := s.start(ctx, "CreateService")
+	defer func() { end(span, err) }()
+	return s.next.CreateService(ctx, svc)
+}
+
+func (s *tracedStorage) GetService(ctx context.Context, name string) (_ *domain.Service, err error) {
+	ctx, span := s.start(ctx, "GetService")
+	defer func() { end(span, err) }()
+	return s.next.GetService(ctx, name)
+}
+
+func (s *tracedStorage) ListServices(ctx context.Context) (_ []*domain.Service, err error) {
+	ctx, span := s.start(ctx, "ListServices")
+	defer func() { end(span, err) }()
+	return s.next.ListServices(ctx)
+}
+
+func (s *tracedStorage) UpdateService(ctx context.Context, svc *domain.Service) (err error) {
+	ctx, span := s.start(ctx, "UpdateService")
+	defer func() { end(span, err) }()
+	return s.next.UpdateService(ctx, svc)
+}
+
+func (s *tracedStorage) DeleteService(ctx context.Context, name string) (err error) {
+	ctx, span := s.start(ctx, "DeleteService")
+	defer func() { end(span, err) }()
+	return s.next.DeleteService(ctx, name)
+}
+
+func (s *tracedStorage) CreateActionItem(ctx context.Context, item *domain.ActionItem) (err error) {
+	ctx, span := s.start(ctx, "CreateActionItem")
+	defer func() { end(span, err) }()
+	return s.next.CreateActionItem(ctx, item)
+}
+
+func (s *tracedStorage) GetActionItem(ctx context.Context, id uuid.UUID) (_ *domain.ActionItem, err error) {
+	ctx, span := s.start(ctx, "GetActionItem")
+	defer func() { end(span, err) }()
+	return s.next.GetActionItem(ctx, id)
+}
+
+func (s *tracedStorage) ListActionItemsByOutage(ctx context.Context, outageID uuid.UUID) (_ []*domain.ActionItem, err error) {
+	ctx, span := s.start(ctx, "ListActionItemsByOutage")
+	defer func() { end(span, err) }()
+	return s.next.ListActionItemsByOutage(ctx, outageID)
+}
+
+func (s *tracedStorage) ListOverdueActionItems(ctx context.Context, before time.Time) (_ []*domain.ActionItem, err error) {
+	ctx, span := s.start(ctx, "ListOverdueActionItems")
+	defer func() { end(span, err) }()
+	return s.next.ListOverdueActionItems(ctx, before)
+}
+
+func (s *tracedStorage) UpdateActionItem(ctx context.Context, item *domain.ActionItem) (err error) {
+	ctx, span := s.start(ctx, "UpdateActionItem")
+	defer func() { end(span, err) }()
+	return s.next.UpdateActionItem(ctx, item)
+}
+
+func (s *tracedStorage) DeleteActionItem(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteActionItem")
+	defer func() { end(span, err) }()
+	return s.next.DeleteActionItem(ctx, id)
+}
+
+func (s *tracedStorage) SaveWatcher(ctx context.Context, watcher *domain.Watcher) (err error) {
+	ctx, span := s.start(ctx, "SaveWatcher")
+	defer func() { end(span, err) }()
+	return s.next.SaveWatcher(ctx, watcher)
+}
+
+func (s *tracedStorage) RemoveWatcher(ctx context.Context, outageID uuid.UUID, email string) (err error) {
+	ctx, span := s.start(ctx, "RemoveWatcher")
+	defer func() { end(span, err) }()
+	return s.next.RemoveWatcher(ctx, outageID, email)
+}
+
+func (s *tracedStorage) ListWatchers(ctx context.Context, outageID uuid.UUID) (_ []*domain.Watcher, err error) {
+	ctx, span := s.start(ctx, "ListWatchers")
+	defer func() { end(span, err) }()
+	return s.next.ListWatchers(ctx, outageID)
+}
+
+func (s *tracedStorage) CreateOutageRelation(ctx context.Context, relation *domain.OutageRelation) (err error) {
+	ctx, span := s.start(ctx, "CreateOutageRelation")
+	defer func() { end(span, err) }()
+	return s.next.CreateOutageRelation(ctx, relation)
+}
+
+func (s *tracedStorage) GetOutageRelation(ctx context.Context, id uuid.UUID) (_ *domain.OutageRelation, err error) {
+	ctx, span := s.start(ctx, "GetOutageRelation")
+	defer func() { end(span, err) }()
+	return s.next.GetOutageRelation(ctx, id)
+}
+
+func (s *tracedStorage) ListOutageRelations(ctx context.Context, outageID uuid.UUID) (_ []*domain.OutageRelation, err error) {
+	ctx, span := s.start(ctx, "ListOutageRelations")
+	defer func() { end(span, err) }()
+	return s.next.ListOutageRelations(ctx, outageID)
+}
+
+func (s *tracedStorage) DeleteOutageRelation(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := s.start(ctx, "DeleteOutageRelation")
+	defer func() { end(span, err) }()
+	return s.next.DeleteOutageRelation(ctx, id)
+}
+
+func (s *tracedStorage) Ping(ctx context.Context) (err error) {
+	ctx, span := s.start(ctx, "Ping")
+	defer func() { end(span, err) }()
+	return s.next.Ping(ctx)
+}
+
+func (s *tracedStorage) CheckSchema(ctx context.Context) (err error) {
+	ctx, span := s.start(ctx, "CheckSchema")
+	defer func() { end(span, err) }()
+	return s.next.CheckSchema(ctx)
+}
+
+// Close closes the wrapped storage
+func (s *tracedStorage) Close() error {
+	return s.next.Close()
+}
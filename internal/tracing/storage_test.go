@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentStorage_RecordsSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	store := InstrumentStorage(testutil.NewMemStorage(), "postgresql")
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	if _, err := store.GetOutage(ctx, uuid.New()); err == nil {
+		t.Fatal("expected not-found error")
+	}
+	if _, err := store.ListOutages(ctx, 10, 0, false); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 ended spans, got %d", len(spans))
+	}
+	get := spans[0]
+	if get.Name() != "storage.GetOutage" {
+		t.Errorf("span name = %q, want storage.GetOutage", get.Name())
+	}
+	if get.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("storage span is not a child of the request span")
+	}
+	if get.Status().Code == codes.Error {
+		t.Error("not-found should not mark the span as an error")
+	}
+	want := attribute.String("db.system", "postgresql")
+	found := false
+	for _, kv := range get.Attributes() {
+		if kv == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("attributes %v missing %v", get.Attributes(), want)
+	}
+}
@@ -0,0 +1,68 @@
+// Package tracing configures OpenTelemetry tracing for the Outalator server
+// and provides span instrumentation for the storage layer.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Config holds the OTLP exporter settings
+type Config struct {
+	// Endpoint is the OTLP gRPC collector address (e.g. localhost:4317).
+	// When empty the exporter falls back to OTEL_EXPORTER_OTLP_ENDPOINT.
+	Endpoint    string
+	Insecure    bool    // Disable TLS to the collector
+	ServiceName string  // Defaults to "outalator"
+	SampleRatio float64 // Fraction of new traces sampled; 0 samples everything
+}
+
+// Setup installs a global tracer provider that exports spans over OTLP and
+// a W3C trace context propagator. The returned function flushes and shuts
+// down the provider and must be called before the process exits.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	var opts []otlptracegrpc.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "outalator"
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp.Shutdown, nil
+}
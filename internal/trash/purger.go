@@ -0,0 +1,67 @@
+// Package trash periodically purges outages and notes that have stayed in
+// the trash longer than the retention period, so deleted records do not
+// accumulate forever.
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// defaultInterval is the time between purges when none is configured
+const defaultInterval = time.Hour
+
+// Emptier is the subset of the service layer the purger drives
+type Emptier interface {
+	PurgeTrash(ctx context.Context, now time.Time) (*domain.PurgeResult, error)
+}
+
+// Purger empties expired items from the trash on a fixed interval
+type Purger struct {
+	emptier  Emptier
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewPurger creates a purger for the given service. A zero interval falls
+// back to the package default.
+func NewPurger(emptier Emptier, interval time.Duration, logger *slog.Logger) *Purger {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Purger{emptier: emptier, interval: interval, logger: logger}
+}
+
+// Run purges immediately and then every interval until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	p.PurgeOnce(ctx)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.PurgeOnce(ctx)
+		}
+	}
+}
+
+// PurgeOnce runs a single purge, logging what it deleted
+func (p *Purger) PurgeOnce(ctx context.Context) {
+	result, err := p.emptier.PurgeTrash(ctx, time.Now())
+	if err != nil {
+		p.logger.ErrorContext(ctx, "trash purge failed", "error", err)
+		return
+	}
+	if result.Outages == 0 && result.Notes == 0 {
+		return
+	}
+	p.logger.InfoContext(ctx, "trash purge complete",
+		"outages", result.Outages,
+		"notes", result.Notes)
+}
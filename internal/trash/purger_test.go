@@ -0,0 +1,53 @@
+package trash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeEmptier struct {
+	purged chan time.Time
+}
+
+func (f *fakeEmptier) PurgeTrash(_ context.Context, now time.Time) (*domain.PurgeResult, error) {
+	select {
+	case f.purged <- now:
+	default:
+	}
+	return &domain.PurgeResult{}, nil
+}
+
+func TestRun_PurgesImmediatelyAndStopsOnCancel(t *testing.T) {
+	emptier := &fakeEmptier{purged: make(chan time.Time, 1)}
+	p := NewPurger(emptier, time.Hour, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-emptier.purged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not purge on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewPurger_DefaultInterval(t *testing.T) {
+	p := NewPurger(&fakeEmptier{}, 0, logging.Discard())
+	if p.interval != defaultInterval {
+		t.Errorf("interval = %v, want %v", p.interval, defaultInterval)
+	}
+}
@@ -0,0 +1,47 @@
+// Package updatereminder periodically checks active outages against their
+// per-severity status update interval and sends reminders for outages whose
+// update is overdue.
+package updatereminder
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/internal/schedule"
+)
+
+// defaultInterval is the time between checks when none is configured
+const defaultInterval = time.Minute
+
+// Sender is the subset of the service layer the scheduler drives
+type Sender interface {
+	SendUpdateReminders(ctx context.Context, now time.Time) (int, error)
+}
+
+// Scheduler checks for overdue updates on a fixed interval
+type Scheduler struct {
+	*schedule.Runner
+	sender Sender
+	logger *slog.Logger
+}
+
+// NewScheduler creates a scheduler for the given service. A zero interval
+// falls back to the package default.
+func NewScheduler(sender Sender, interval time.Duration, logger *slog.Logger) *Scheduler {
+	s := &Scheduler{sender: sender, logger: logger}
+	s.Runner = schedule.New(s.CheckOnce, interval, defaultInterval)
+	return s
+}
+
+// CheckOnce runs a single check, logging the reminders it sent
+func (s *Scheduler) CheckOnce(ctx context.Context) {
+	sent, err := s.sender.SendUpdateReminders(ctx, time.Now())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "update reminder check failed", "error", err)
+		return
+	}
+	if sent > 0 {
+		s.logger.InfoContext(ctx, "sent overdue update reminders", "outages", sent)
+	}
+}
@@ -0,0 +1,47 @@
+// Package viewsummary periodically posts the summaries of saved views
+// subscribed to a Slack channel once their summary period has passed.
+package viewsummary
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/conall/outalator/internal/schedule"
+)
+
+// defaultInterval is the time between checks when none is configured
+const defaultInterval = 5 * time.Minute
+
+// Sender is the subset of the service layer the scheduler drives
+type Sender interface {
+	SendViewSummaries(ctx context.Context, now time.Time) (int, error)
+}
+
+// Scheduler checks for due view summaries on a fixed interval
+type Scheduler struct {
+	*schedule.Runner
+	sender Sender
+	logger *slog.Logger
+}
+
+// NewScheduler creates a scheduler for the given service. A zero interval
+// falls back to the package default.
+func NewScheduler(sender Sender, interval time.Duration, logger *slog.Logger) *Scheduler {
+	s := &Scheduler{sender: sender, logger: logger}
+	s.Runner = schedule.New(s.CheckOnce, interval, defaultInterval)
+	return s
+}
+
+// CheckOnce sends the view summaries that are due, logging how many were
+// sent
+func (s *Scheduler) CheckOnce(ctx context.Context) {
+	sent, err := s.sender.SendViewSummaries(ctx, time.Now())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "view summary check failed", "error", err)
+		return
+	}
+	if sent > 0 {
+		s.logger.InfoContext(ctx, "sent saved view summaries", "views", sent)
+	}
+}
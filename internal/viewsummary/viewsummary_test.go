@@ -0,0 +1,52 @@
+package viewsummary
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/internal/logging"
+)
+
+type fakeSender struct {
+	checked chan time.Time
+}
+
+func (f *fakeSender) SendViewSummaries(_ context.Context, now time.Time) (int, error) {
+	select {
+	case f.checked <- now:
+	default:
+	}
+	return 0, nil
+}
+
+func TestRun_ChecksImmediatelyAndStopsOnCancel(t *testing.T) {
+	sender := &fakeSender{checked: make(chan time.Time, 1)}
+	s := NewScheduler(sender, time.Hour, logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-sender.checked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not check on start")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewScheduler_DefaultInterval(t *testing.T) {
+	s := NewScheduler(&fakeSender{}, 0, logging.Discard())
+	if s.Interval() != defaultInterval {
+		t.Errorf("Interval() = %v, want %v", s.Interval(), defaultInterval)
+	}
+}
@@ -0,0 +1,155 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	defaultBigQueryURL = "https://bigquery.googleapis.com/bigquery/v2"
+	// bigQueryInsertScope allows streaming inserts and nothing else
+	bigQueryInsertScope = "https://www.googleapis.com/auth/bigquery.insertdata"
+	defaultTokenURL     = "https://oauth2.googleapis.com/token"
+)
+
+// BigQueryConfig holds the BigQuery table events are streamed into
+type BigQueryConfig struct {
+	Project string
+	Dataset string
+	Table   string
+	// CredentialsFile is a service account key file in JSON. Optional for
+	// endpoints that need no authentication, such as the BigQuery emulator.
+	CredentialsFile string
+	// URL is the BigQuery API base URL. Optional, defaults to
+	// https://bigquery.googleapis.com/bigquery/v2.
+	URL string
+	// Transport is the HTTP transport used for API calls. Optional,
+	// defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// BigQuery streams rows into a BigQuery table with the tabledata.insertAll
+// API. Each row's event ID is sent as its insert ID, so retried batches are
+// deduplicated on a best-effort basis.
+type BigQuery struct {
+	insertURL string
+	client    *http.Client
+}
+
+// serviceAccountKey is the subset of a service account key file needed to
+// sign token requests
+type serviceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// NewBigQuery creates a writer for the configured table
+func NewBigQuery(cfg BigQueryConfig) (*BigQuery, error) {
+	if cfg.Project == "" || cfg.Dataset == "" || cfg.Table == "" {
+		return nil, fmt.Errorf("warehouse: bigquery project, dataset and table are required")
+	}
+	if cfg.URL == "" {
+		cfg.URL = defaultBigQueryURL
+	}
+	client := &http.Client{Timeout: 30 * time.Second, Transport: cfg.Transport}
+	if cfg.CredentialsFile != "" {
+		data, err := os.ReadFile(cfg.CredentialsFile) //nolint:gosec // path comes from operator config
+		if err != nil {
+			return nil, fmt.Errorf("warehouse: failed to read bigquery credentials: %w", err)
+		}
+		var key serviceAccountKey
+		if err := json.Unmarshal(data, &key); err != nil || key.ClientEmail == "" || key.PrivateKey == "" {
+			return nil, fmt.Errorf("warehouse: %s is not a service account key file", cfg.CredentialsFile)
+		}
+		if key.TokenURI == "" {
+			key.TokenURI = defaultTokenURL
+		}
+		jwtConfig := &jwt.Config{
+			Email:        key.ClientEmail,
+			PrivateKey:   []byte(key.PrivateKey),
+			PrivateKeyID: key.PrivateKeyID,
+			Scopes:       []string{bigQueryInsertScope},
+			TokenURL:     key.TokenURI,
+		}
+		// Token requests go through the same transport as API calls
+		tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: 30 * time.Second, Transport: cfg.Transport})
+		client.Transport = &oauth2.Transport{Source: jwtConfig.TokenSource(tokenCtx), Base: cfg.Transport}
+	}
+	return &BigQuery{
+		insertURL: fmt.Sprintf("%s/projects/%s/datasets/%s/tables/%s/insertAll", strings.TrimSuffix(cfg.URL, "/"),
+			url.PathEscape(cfg.Project), url.PathEscape(cfg.Dataset), url.PathEscape(cfg.Table)),
+		client: client,
+	}, nil
+}
+
+type insertAllRow struct {
+	InsertID string `json:"insertId"`
+	JSON     Row    `json:"json"`
+}
+
+type insertAllResponse struct {
+	InsertErrors []struct {
+		Index  int `json:"index"`
+		Errors []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"insertErrors"`
+}
+
+// Write streams rows into the table. The whole batch fails if any row is
+// rejected, so it can be retried once the table is fixed.
+func (b *BigQuery) Write(ctx context.Context, rows []Row) error {
+	body := struct {
+		Rows []insertAllRow `json:"rows"`
+	}{Rows: make([]insertAllRow, len(rows))}
+	for i, row := range rows {
+		body.Rows[i] = insertAllRow{InsertID: row.EventID, JSON: row}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.insertURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bigquery insert: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bigquery insert: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result insertAllResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("bigquery insert: %w", err)
+	}
+	if len(result.InsertErrors) > 0 {
+		first := result.InsertErrors[0]
+		reason := "rejected"
+		if len(first.Errors) > 0 {
+			reason = first.Errors[0].Reason + ": " + first.Errors[0].Message
+		}
+		return fmt.Errorf("bigquery insert: %d rows failed, row %d %s", len(result.InsertErrors), first.Index, reason)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package warehouse
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBigQueryWrite(t *testing.T) {
+	var path, auth string
+	var body struct {
+		Rows []struct {
+			InsertID string `json:"insertId"`
+			JSON     Row    `json:"json"`
+		} `json:"rows"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"ya29.token","token_type":"Bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("POST /bigquery/v2/", func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write([]byte(`{"kind":"bigquery#tableDataInsertAllResponse"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	writer, err := NewBigQuery(BigQueryConfig{
+		Project: "acme", Dataset: "incidents", Table: "outage_events",
+		CredentialsFile: writeServiceAccountKey(t, server.URL+"/token"),
+		URL:             server.URL + "/bigquery/v2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(context.Background(), []Row{{EventID: "e1", EventType: "outage.created", OutageID: "a"}}); err != nil {
+		t.Fatalf("Write() err = %v", err)
+	}
+	if path != "/bigquery/v2/projects/acme/datasets/incidents/tables/outage_events/insertAll" {
+		t.Errorf("path = %s", path)
+	}
+	if auth != "Bearer ya29.token" {
+		t.Errorf("Authorization = %q, want the service account token", auth)
+	}
+	if len(body.Rows) != 1 || body.Rows[0].InsertID != "e1" || body.Rows[0].JSON.EventType != "outage.created" {
+		t.Errorf("rows = %+v, want the event with its ID as insert ID", body.Rows)
+	}
+}
+
+func TestBigQueryWriteInsertErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"insertErrors":[{"index":0,"errors":[{"reason":"invalid","message":"no such field: title"}]}]}`))
+	}))
+	defer server.Close()
+
+	writer, err := NewBigQuery(BigQueryConfig{Project: "acme", Dataset: "incidents", Table: "outage_events", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.Write(context.Background(), []Row{{EventID: "e1"}})
+	if err == nil || !strings.Contains(err.Error(), "no such field: title") {
+		t.Errorf("Write() err = %v, want the rejected row's reason", err)
+	}
+}
+
+// writeServiceAccountKey writes a service account key file whose tokens are
+// issued by tokenURL
+func writeServiceAccountKey(t *testing.T, tokenURL string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "outalator@acme.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
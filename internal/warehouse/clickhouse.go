@@ -0,0 +1,93 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClickHouseConfig holds the ClickHouse table events are inserted into
+type ClickHouseConfig struct {
+	URL      string // HTTP interface, e.g. http://clickhouse:8123
+	Database string // Default "default"
+	Table    string
+	Username string
+	Password string
+	// Transport is the HTTP transport used for inserts. Optional, defaults
+	// to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// ClickHouse inserts rows into a ClickHouse table over its HTTP interface,
+// as JSONEachRow
+type ClickHouse struct {
+	cfg      ClickHouseConfig
+	endpoint string
+	client   *http.Client
+}
+
+// NewClickHouse creates a writer for the configured table
+func NewClickHouse(cfg ClickHouseConfig) (*ClickHouse, error) {
+	if cfg.URL == "" || cfg.Table == "" {
+		return nil, fmt.Errorf("warehouse: clickhouse url and table are required")
+	}
+	if cfg.Database == "" {
+		cfg.Database = "default"
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.URL, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("warehouse: invalid clickhouse url %q", cfg.URL)
+	}
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf("INSERT INTO %s.%s FORMAT JSONEachRow", quoteIdentifier(cfg.Database), quoteIdentifier(cfg.Table)))
+	// Accept RFC 3339 timestamps with a zone, as encoding/json writes them
+	query.Set("date_time_input_format", "best_effort")
+	endpoint.RawQuery = query.Encode()
+	return &ClickHouse{
+		cfg:      cfg,
+		endpoint: endpoint.String(),
+		client:   &http.Client{Timeout: 30 * time.Second, Transport: cfg.Transport},
+	}, nil
+}
+
+// quoteIdentifier quotes a database or table name for a ClickHouse query
+func quoteIdentifier(name string) string {
+	return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(name) + "`"
+}
+
+// Write inserts rows into the table in one request
+func (c *ClickHouse) Write(ctx context.Context, rows []Row) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if c.cfg.Username != "" {
+		req.Header.Set("X-ClickHouse-User", c.cfg.Username)
+		req.Header.Set("X-ClickHouse-Key", c.cfg.Password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("clickhouse insert: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("clickhouse insert: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package warehouse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClickHouseWrite(t *testing.T) {
+	var query, user, key string
+	var got []Row
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		user, key = r.Header.Get("X-ClickHouse-User"), r.Header.Get("X-ClickHouse-Key")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var row Row
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				t.Errorf("line %q: %v", scanner.Text(), err)
+			}
+			got = append(got, row)
+		}
+	}))
+	defer server.Close()
+
+	writer, err := NewClickHouse(ClickHouseConfig{URL: server.URL, Database: "incidents", Table: "outage_events", Username: "outalator", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []Row{
+		{EventID: "1", EventType: "outage.created", EventTime: time.Now().UTC(), OutageID: "a", AffectedServices: []string{}},
+		{EventID: "2", EventType: "note.added", EventTime: time.Now().UTC(), OutageID: "a", AffectedServices: []string{}},
+	}
+	if err := writer.Write(context.Background(), rows); err != nil {
+		t.Fatalf("Write() err = %v", err)
+	}
+	if query != "INSERT INTO `incidents`.`outage_events` FORMAT JSONEachRow" {
+		t.Errorf("query = %q", query)
+	}
+	if user != "outalator" || key != "secret" {
+		t.Errorf("credentials = %q, %q", user, key)
+	}
+	if len(got) != 2 || got[1].EventType != "note.added" {
+		t.Errorf("rows = %+v, want both rows", got)
+	}
+}
+
+func TestClickHouseWriteError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Code: 60. DB::Exception: Table incidents.outage_events does not exist", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	writer, err := NewClickHouse(ClickHouseConfig{URL: server.URL, Table: "outage_events"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.Write(context.Background(), []Row{{EventID: "1"}})
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Write() err = %v, want the ClickHouse error", err)
+	}
+}
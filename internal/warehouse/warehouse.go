@@ -0,0 +1,221 @@
+// Package warehouse streams outage lifecycle events into a BigQuery or
+// ClickHouse table as they occur, so long-term trend dashboards can query
+// the warehouse instead of the operational database. The Sink is registered
+// with the service as an OutageListener and writes events in small batches
+// from its own goroutine, so a slow warehouse never delays a request.
+package warehouse
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/events"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultFlushInterval is the longest an event waits before it is written
+	defaultFlushInterval = 5 * time.Second
+	// defaultBatchSize is the most events written in one insert
+	defaultBatchSize = 500
+	// maxPending is the most events held while the warehouse is unreachable;
+	// later events are dropped until it recovers
+	maxPending = 10000
+	// shutdownTimeout bounds the final flush when the sink stops
+	shutdownTimeout = 10 * time.Second
+)
+
+// Row is one outage lifecycle event, as written to the warehouse table. The
+// event type is one of the events package types, e.g. outage.created.
+// Fields that do not apply to an event are left out, and stored as null or
+// the column default.
+type Row struct {
+	EventID          string     `json:"event_id"`
+	EventType        string     `json:"event_type"`
+	EventTime        time.Time  `json:"event_time"`
+	OutageID         string     `json:"outage_id"`
+	Title            string     `json:"title"`
+	Status           string     `json:"status"`
+	PreviousStatus   string     `json:"previous_status,omitempty"`
+	Severity         string     `json:"severity"`
+	OwningTeam       string     `json:"owning_team,omitempty"`
+	CustomerImpact   bool       `json:"customer_impact"`
+	AffectedServices []string   `json:"affected_services"`
+	OutageCreatedAt  time.Time  `json:"outage_created_at"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	AlertID          string     `json:"alert_id,omitempty"`
+	AlertSource      string     `json:"alert_source,omitempty"`
+	AlertSeverity    string     `json:"alert_severity,omitempty"`
+	NoteID           string     `json:"note_id,omitempty"`
+	NoteAuthor       string     `json:"note_author,omitempty"`
+}
+
+// Writer inserts rows into a warehouse table
+type Writer interface {
+	Write(ctx context.Context, rows []Row) error
+}
+
+// Sink buffers outage events and writes them to a Writer in batches. It
+// implements service.OutageListener.
+type Sink struct {
+	writer        Writer
+	flushInterval time.Duration
+	batchSize     int
+	logger        *slog.Logger
+
+	mu      sync.Mutex
+	pending []Row
+	full    chan struct{} // Signalled when a batch is ready before the next flush
+	now     func() time.Time
+}
+
+// NewSink creates a sink writing to writer every flushInterval, or sooner
+// once batchSize events are waiting. Zero values fall back to the package
+// defaults of 5s and 500 events.
+func NewSink(writer Writer, flushInterval time.Duration, batchSize int, logger *slog.Logger) *Sink {
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &Sink{
+		writer:        writer,
+		flushInterval: flushInterval,
+		batchSize:     batchSize,
+		logger:        logger,
+		full:          make(chan struct{}, 1),
+		now:           time.Now,
+	}
+}
+
+// Run writes buffered events until ctx is cancelled, then writes whatever is
+// left
+func (s *Sink) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+			s.Flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			s.Flush(ctx)
+		case <-s.full:
+			s.Flush(ctx)
+		}
+	}
+}
+
+// Flush writes the buffered events in batches. Events that fail to write
+// stay buffered and are retried on the next flush.
+func (s *Sink) Flush(ctx context.Context) {
+	for {
+		s.mu.Lock()
+		n := min(len(s.pending), s.batchSize)
+		batch := s.pending[:n:n]
+		s.mu.Unlock()
+		if n == 0 {
+			return
+		}
+
+		if err := s.writer.Write(ctx, batch); err != nil {
+			s.logger.ErrorContext(ctx, "warehouse write failed", "events", n, "error", err)
+			return
+		}
+
+		s.mu.Lock()
+		s.pending = s.pending[n:]
+		s.mu.Unlock()
+	}
+}
+
+// add buffers a row, dropping it when the warehouse has been unreachable
+// for too long
+func (s *Sink) add(ctx context.Context, row Row) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) >= maxPending {
+		s.logger.WarnContext(ctx, "dropping warehouse event", "event_type", row.EventType,
+			"outage_id", row.OutageID, "pending", len(s.pending))
+		return
+	}
+	s.pending = append(s.pending, row)
+	if len(s.pending) >= s.batchSize {
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// newRow builds the row for an event about outage
+func (s *Sink) newRow(eventType string, outage *domain.Outage) Row {
+	services := outage.AffectedServices
+	if services == nil {
+		services = []string{}
+	}
+	var resolvedAt *time.Time
+	if outage.ResolvedAt != nil {
+		t := outage.ResolvedAt.UTC()
+		resolvedAt = &t
+	}
+	return Row{
+		EventID:          uuid.NewString(),
+		EventType:        eventType,
+		EventTime:        s.now().UTC(),
+		OutageID:         outage.ID.String(),
+		Title:            outage.Title,
+		Status:           outage.Status,
+		Severity:         outage.Severity,
+		OwningTeam:       outage.OwningTeam,
+		CustomerImpact:   outage.CustomerImpact,
+		AffectedServices: services,
+		OutageCreatedAt:  outage.CreatedAt.UTC(),
+		ResolvedAt:       resolvedAt,
+	}
+}
+
+// OutageCreated implements service.OutageListener
+func (s *Sink) OutageCreated(ctx context.Context, outage *domain.Outage) error {
+	s.add(ctx, s.newRow(events.TypeOutageCreated, outage))
+	return nil
+}
+
+// NoteAdded implements service.OutageListener
+func (s *Sink) NoteAdded(ctx context.Context, outage *domain.Outage, note *domain.Note) error {
+	row := s.newRow(events.TypeNoteAdded, outage)
+	row.NoteID = note.ID.String()
+	row.NoteAuthor = note.Author
+	s.add(ctx, row)
+	return nil
+}
+
+// AlertAdded implements service.OutageListener
+func (s *Sink) AlertAdded(ctx context.Context, outage *domain.Outage, alert *domain.Alert) error {
+	row := s.newRow(events.TypeAlertAdded, outage)
+	row.AlertID = alert.ID.String()
+	row.AlertSource = alert.Source
+	row.AlertSeverity = alert.Severity
+	s.add(ctx, row)
+	return nil
+}
+
+// OutageStatusChanged implements service.OutageListener
+func (s *Sink) OutageStatusChanged(ctx context.Context, outage *domain.Outage, previous string) error {
+	row := s.newRow(events.TypeOutageStatusChanged, outage)
+	row.PreviousStatus = previous
+	s.add(ctx, row)
+	return nil
+}
+
+// OutageResolved implements service.OutageListener
+func (s *Sink) OutageResolved(ctx context.Context, outage *domain.Outage) error {
+	s.add(ctx, s.newRow(events.TypeOutageResolved, outage))
+	return nil
+}
@@ -0,0 +1,137 @@
+package warehouse
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/events"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/google/uuid"
+)
+
+// fakeWriter records the batches written, failing while err is set
+type fakeWriter struct {
+	mu      sync.Mutex
+	batches [][]Row
+	err     error
+}
+
+func (f *fakeWriter) Write(_ context.Context, rows []Row) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.batches = append(f.batches, append([]Row(nil), rows...))
+	return nil
+}
+
+func TestSinkRows(t *testing.T) {
+	writer := &fakeWriter{}
+	sink := NewSink(writer, time.Hour, 0, logging.Discard())
+	ctx := context.Background()
+
+	resolved := time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)
+	outage := &domain.Outage{ID: uuid.New(), Title: "DB down", Status: "resolved", Severity: "critical",
+		OwningTeam: "payments", CustomerImpact: true, ResolvedAt: &resolved}
+	_ = sink.OutageCreated(ctx, outage)
+	_ = sink.AlertAdded(ctx, outage, &domain.Alert{ID: uuid.New(), Source: "pagerduty", Severity: "high"})
+	_ = sink.NoteAdded(ctx, outage, &domain.Note{ID: uuid.New(), Author: "alice"})
+	_ = sink.OutageStatusChanged(ctx, outage, "mitigated")
+	_ = sink.OutageResolved(ctx, outage)
+	sink.Flush(ctx)
+
+	if len(writer.batches) != 1 || len(writer.batches[0]) != 5 {
+		t.Fatalf("batches = %v, want one batch of 5 rows", writer.batches)
+	}
+	rows := writer.batches[0]
+	wantTypes := []string{events.TypeOutageCreated, events.TypeAlertAdded, events.TypeNoteAdded,
+		events.TypeOutageStatusChanged, events.TypeOutageResolved}
+	for i, row := range rows {
+		if row.EventType != wantTypes[i] || row.OutageID != outage.ID.String() || row.Severity != "critical" || row.OwningTeam != "payments" {
+			t.Errorf("row %d = %+v, want %s for the outage", i, row, wantTypes[i])
+		}
+		if row.AffectedServices == nil {
+			t.Errorf("row %d affected_services is nil, want an empty list", i)
+		}
+	}
+	if rows[1].AlertSource != "pagerduty" || rows[1].AlertSeverity != "high" {
+		t.Errorf("alert row = %+v", rows[1])
+	}
+	if rows[2].NoteAuthor != "alice" {
+		t.Errorf("note row = %+v", rows[2])
+	}
+	if rows[3].PreviousStatus != "mitigated" {
+		t.Errorf("status row previous = %q, want mitigated", rows[3].PreviousStatus)
+	}
+	if rows[4].ResolvedAt == nil || !rows[4].ResolvedAt.Equal(resolved) {
+		t.Errorf("resolved row resolved_at = %v, want %v", rows[4].ResolvedAt, resolved)
+	}
+}
+
+func TestSinkBatchesAndRetries(t *testing.T) {
+	writer := &fakeWriter{err: errors.New("warehouse down")}
+	sink := NewSink(writer, time.Hour, 2, logging.Discard())
+	ctx := context.Background()
+
+	outage := &domain.Outage{ID: uuid.New(), Title: "DB down"}
+	for range 3 {
+		_ = sink.OutageCreated(ctx, outage)
+	}
+	sink.Flush(ctx)
+	if len(writer.batches) != 0 || len(sink.pending) != 3 {
+		t.Fatalf("after failed flush: batches = %d, pending = %d; want 0 and 3", len(writer.batches), len(sink.pending))
+	}
+
+	writer.err = nil
+	sink.Flush(ctx)
+	if len(writer.batches) != 2 || len(writer.batches[0]) != 2 || len(writer.batches[1]) != 1 {
+		t.Errorf("batches = %v, want batches of 2 and 1", writer.batches)
+	}
+	if len(sink.pending) != 0 {
+		t.Errorf("pending = %d after flush, want 0", len(sink.pending))
+	}
+}
+
+func TestSinkRunFlushesFullBatchAndOnStop(t *testing.T) {
+	writer := &fakeWriter{}
+	sink := NewSink(writer, time.Hour, 2, logging.Discard())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sink.Run(ctx)
+		close(done)
+	}()
+
+	outage := &domain.Outage{ID: uuid.New(), Title: "DB down"}
+	_ = sink.OutageCreated(ctx, outage)
+	_ = sink.OutageCreated(ctx, outage)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		writer.mu.Lock()
+		n := len(writer.batches)
+		writer.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("full batch was not written before the flush interval")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_ = sink.OutageCreated(ctx, outage)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	if len(writer.batches) != 2 {
+		t.Errorf("batches = %d, want the last event written on stop", len(writer.batches))
+	}
+}
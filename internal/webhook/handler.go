@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/conall/outalator/internal/bodylimit"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+// Sources knows which sources accept webhooks and authenticates their
+// deliveries. It is satisfied by *service.Service.
+type Sources interface {
+	SupportsWebhooks(source string) bool
+	VerifyWebhook(source string, header http.Header, payload []byte) bool
+}
+
+// Receiver exposes the HTTP endpoint providers deliver webhooks to
+type Receiver struct {
+	queue   *Queue
+	sources Sources
+	logger  *slog.Logger
+}
+
+// NewReceiver creates a receiver that enqueues verified deliveries for any
+// source sources supports.
+func NewReceiver(queue *Queue, sources Sources, logger *slog.Logger) *Receiver {
+	return &Receiver{queue: queue, sources: sources, logger: logger}
+}
+
+// RegisterHandlers registers the webhook routes
+func (rc *Receiver) RegisterHandlers(r *mux.Router) {
+	r.HandleFunc("/api/v1/webhooks/{source}", rc.HandleWebhook).Methods("POST")
+}
+
+// HandleWebhook handles POST /api/v1/webhooks/{source}. The delivery is
+// verified, then queued and acknowledged with 202 before any processing
+// happens. Payload size is capped by the bodylimit middleware.
+func (rc *Receiver) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	source := mux.Vars(r)["source"]
+	if !rc.sources.SupportsWebhooks(source) {
+		respond(w, http.StatusNotFound, map[string]string{"error": "Unknown webhook source"})
+		return
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		if bodylimit.TooLarge(err) {
+			bodylimit.RespondTooLarge(w)
+			return
+		}
+		respond(w, http.StatusBadRequest, map[string]string{"error": "Failed to read request body"})
+		return
+	}
+	if !rc.sources.VerifyWebhook(source, r.Header, payload) {
+		rc.logger.WarnContext(r.Context(), "rejected unverified webhook delivery", "source", source)
+		respond(w, http.StatusUnauthorized, map[string]string{"error": "Invalid webhook signature"})
+		return
+	}
+
+	job := Job{Source: source, Payload: payload, ReceivedAt: time.Now(), RequestID: logging.RequestID(r.Context())}
+	if err := rc.queue.Enqueue(job); err != nil {
+		if errors.Is(err, ErrQueueFull) || errors.Is(err, ErrQueueStopped) {
+			// Providers retry on 5xx, so ask them to come back later.
+			w.Header().Set("Retry-After", "30")
+			respond(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+			return
+		}
+		rc.logger.ErrorContext(r.Context(), "failed to enqueue webhook delivery", "source", source, "error", err)
+		respond(w, http.StatusInternalServerError, map[string]string{"error": "Failed to queue webhook"})
+		return
+	}
+
+	respond(w, http.StatusAccepted, map[string]string{"status": "queued"})
+}
+
+func respond(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/internal/logging"
+	"github.com/gorilla/mux"
+)
+
+// fakeSources supports the pagerduty source and verifies deliveries that
+// carry a token header
+type fakeSources struct{}
+
+func (fakeSources) SupportsWebhooks(source string) bool { return source == "pagerduty" }
+
+func (fakeSources) VerifyWebhook(_ string, header http.Header, _ []byte) bool {
+	return header.Get("X-Token") == "secret"
+}
+
+func TestReceiver_HandleWebhook(t *testing.T) {
+	q := NewQueue(Config{Workers: 1, QueueSize: 10}, func(context.Context, Job) error { return nil }, logging.Discard())
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer q.Stop(context.Background())
+	router := mux.NewRouter()
+	NewReceiver(q, fakeSources{}, logging.Discard()).RegisterHandlers(router)
+
+	tests := []struct {
+		name   string
+		source string
+		token  string
+		want   int
+	}{
+		{"verified", "pagerduty", "secret", http.StatusAccepted},
+		{"unsigned", "pagerduty", "", http.StatusUnauthorized},
+		{"wrong token", "pagerduty", "guess", http.StatusUnauthorized},
+		{"unknown source", "nagios", "secret", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/"+tt.source, strings.NewReader(`{}`))
+			if tt.token != "" {
+				req.Header.Set("X-Token", tt.token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Errorf("POST = %d, want %d; body: %s", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
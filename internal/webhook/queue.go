@@ -0,0 +1,294 @@
+// Package webhook accepts inbound webhook deliveries from notification
+// services and processes them asynchronously on a bounded worker pool, so
+// providers are acknowledged immediately and alert storms cannot exhaust the
+// database connection pool.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/internal/logging"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWorkers   = 4
+	defaultQueueSize = 1000
+)
+
+// ErrQueueFull is returned by Enqueue when every queue slot is taken.
+var ErrQueueFull = errors.New("webhook queue is full")
+
+// ErrQueueStopped is returned by Enqueue after Stop has been called.
+var ErrQueueStopped = errors.New("webhook queue is stopped")
+
+// Job is a single webhook delivery awaiting processing
+type Job struct {
+	ID         string    `json:"id"`
+	Source     string    `json:"source"`
+	Payload    []byte    `json:"payload"`
+	ReceivedAt time.Time `json:"received_at"`
+	// RequestID is the ID of the HTTP request that delivered the job, so
+	// log lines written while processing it can be correlated.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ProcessFunc handles a single job. Errors are logged and the job dropped;
+// providers have already been acknowledged so there is nobody to report to.
+type ProcessFunc func(ctx context.Context, job Job) error
+
+// Config holds webhook queue configuration
+type Config struct {
+	Workers   int // Concurrent jobs, defaults to 4
+	QueueSize int // Jobs buffered before deliveries are rejected, defaults to 1000
+	// SpoolDir, when set, persists each job to disk until it has been
+	// processed so that queued deliveries survive a restart.
+	SpoolDir string
+}
+
+// Queue buffers webhook jobs and processes them on a fixed number of workers
+type Queue struct {
+	cfg     Config
+	process ProcessFunc
+	logger  *slog.Logger
+	jobs    chan Job
+	quit    chan struct{}
+
+	mu      sync.RWMutex
+	stopped bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewQueue creates a queue; call Start before enqueueing jobs
+func NewQueue(cfg Config, process ProcessFunc, logger *slog.Logger) *Queue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	return &Queue{
+		cfg:     cfg,
+		process: process,
+		logger:  logger,
+		jobs:    make(chan Job, cfg.QueueSize),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start launches the workers and replays any jobs left in the spool
+// directory by a previous run.
+func (q *Queue) Start(ctx context.Context) error {
+	var pending []Job
+	if q.cfg.SpoolDir != "" {
+		if err := os.MkdirAll(q.cfg.SpoolDir, 0o750); err != nil {
+			return fmt.Errorf("failed to create webhook spool directory: %w", err)
+		}
+		var err error
+		if pending, err = q.loadSpool(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	q.cancel = cancel
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx)
+	}
+
+	if len(pending) > 0 {
+		q.logger.InfoContext(ctx, "replaying spooled webhook deliveries", "count", len(pending))
+		// Replay may exceed the channel capacity, so feed it from a
+		// goroutine rather than blocking startup.
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for _, job := range pending {
+				select {
+				case q.jobs <- job:
+				case <-q.quit:
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return nil
+}
+
+// Enqueue accepts a job without waiting for it to be processed. When a spool
+// directory is configured the job is on disk before Enqueue returns.
+func (q *Queue) Enqueue(job Job) error {
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if job.ReceivedAt.IsZero() {
+		job.ReceivedAt = time.Now()
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.stopped {
+		return ErrQueueStopped
+	}
+
+	if err := q.spool(job); err != nil {
+		return err
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		q.unspool(job)
+		return ErrQueueFull
+	}
+}
+
+// Stop stops accepting jobs and waits for the workers to finish what is
+// already queued, or for ctx to expire. Jobs still queued at expiry remain
+// in the spool directory and are replayed on the next Start.
+func (q *Queue) Stop(ctx context.Context) {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.stopped = true
+	close(q.quit)
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if q.cancel != nil {
+			q.cancel()
+		}
+		<-done
+	}
+}
+
+func (q *Queue) worker(ctx context.Context) {
+	defer q.wg.Done()
+	for {
+		select {
+		case job := <-q.jobs:
+			q.handle(ctx, job)
+		case <-q.quit:
+			// Drain whatever was accepted before Stop.
+			for {
+				select {
+				case job := <-q.jobs:
+					q.handle(ctx, job)
+				default:
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *Queue) handle(ctx context.Context, job Job) {
+	if ctx.Err() != nil {
+		return
+	}
+	if job.RequestID != "" {
+		ctx = logging.WithRequestID(ctx, job.RequestID)
+	}
+	if err := q.process(ctx, job); err != nil {
+		if ctx.Err() != nil {
+			// Interrupted by shutdown; leave it spooled for the next run.
+			return
+		}
+		q.logger.ErrorContext(ctx, "failed to process webhook delivery",
+			"source", job.Source, "job_id", job.ID, "error", err)
+	}
+	q.unspool(job)
+}
+
+func (q *Queue) spoolPath(job Job) string {
+	// Prefix with the receive time so replay preserves arrival order.
+	name := fmt.Sprintf("%020d-%s.json", job.ReceivedAt.UnixNano(), job.ID)
+	return filepath.Join(q.cfg.SpoolDir, name)
+}
+
+func (q *Queue) spool(job Job) error {
+	if q.cfg.SpoolDir == "" {
+		return nil
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook job: %w", err)
+	}
+	path := q.spoolPath(job)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to spool webhook job: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to spool webhook job: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) unspool(job Job) {
+	if q.cfg.SpoolDir == "" {
+		return
+	}
+	if err := os.Remove(q.spoolPath(job)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		q.logger.Warn("failed to remove spooled webhook delivery", "job_id", job.ID, "error", err)
+	}
+}
+
+func (q *Queue) loadSpool() ([]Job, error) {
+	entries, err := os.ReadDir(q.cfg.SpoolDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook spool directory: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	jobs := make([]Job, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(q.cfg.SpoolDir, name)
+		data, err := os.ReadFile(path) //nolint:gosec // path is built from the operator-configured spool directory
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spooled webhook job: %w", err)
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			q.logger.Warn("discarding unreadable spooled webhook delivery", "file", name, "error", err)
+			_ = os.Remove(path)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
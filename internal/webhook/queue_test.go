@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/internal/logging"
+)
+
+func TestQueue_ProcessesWithBoundedConcurrency(t *testing.T) {
+	const workers = 2
+	var running, peak int32
+	var wg sync.WaitGroup
+
+	q := NewQueue(Config{Workers: workers, QueueSize: 10}, func(ctx context.Context, job Job) error {
+		defer wg.Done()
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}, logging.Discard())
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Add(6)
+	for i := 0; i < 6; i++ {
+		if err := q.Enqueue(Job{Source: "pagerduty"}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	wg.Wait()
+	q.Stop(context.Background())
+
+	if peak > workers {
+		t.Errorf("peak concurrency = %d, want <= %d", peak, workers)
+	}
+}
+
+func TestQueue_RejectsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	q := NewQueue(Config{Workers: 1, QueueSize: 1}, func(ctx context.Context, job Job) error {
+		<-release
+		return nil
+	}, logging.Discard())
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		close(release)
+		q.Stop(context.Background())
+	}()
+
+	// One job occupies the worker, one fills the buffer; keep going until
+	// the queue pushes back.
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = q.Enqueue(Job{Source: "opsgenie"})
+	}
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestQueue_EnqueueAfterStop(t *testing.T) {
+	q := NewQueue(Config{}, func(ctx context.Context, job Job) error { return nil }, logging.Discard())
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	q.Stop(context.Background())
+
+	if err := q.Enqueue(Job{Source: "pagerduty"}); !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("expected ErrQueueStopped, got %v", err)
+	}
+}
+
+func TestQueue_SpoolReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	// First run: a worker that never finishes leaves the job spooled.
+	block := make(chan struct{})
+	first := NewQueue(Config{Workers: 1, SpoolDir: dir}, func(ctx context.Context, job Job) error {
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+		return ctx.Err()
+	}, logging.Discard())
+	if err := first.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Enqueue(Job{Source: "pagerduty", Payload: []byte(`{"id":1}`)}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	first.Stop(ctx)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 spooled job after interrupted shutdown, got %d", len(entries))
+	}
+
+	// Second run replays and removes it.
+	got := make(chan Job, 1)
+	second := NewQueue(Config{Workers: 1, SpoolDir: dir}, func(ctx context.Context, job Job) error {
+		got <- job
+		return nil
+	}, logging.Discard())
+	if err := second.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case job := <-got:
+		if string(job.Payload) != `{"id":1}` {
+			t.Errorf("replayed payload = %s", job.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("spooled job was not replayed")
+	}
+	second.Stop(context.Background())
+
+	entries, err = os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected spool to be empty after replay, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,13 @@
+-- Record every outage status transition so the timeline can show when an
+-- outage moved between open, investigating, resolved and closed.
+CREATE TABLE IF NOT EXISTS outage_status_changes (
+    id UUID PRIMARY KEY,
+    outage_id UUID NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL DEFAULT '',
+    to_status VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outage_status_changes_outage_id ON outage_status_changes(outage_id, changed_at);
+
+COMMENT ON COLUMN outage_status_changes.from_status IS 'Previous status; empty for the initial status recorded at creation';
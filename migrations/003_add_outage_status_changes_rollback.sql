@@ -0,0 +1,5 @@
+-- Rollback migration for outage status history
+-- This script reverses the changes made in 003_add_outage_status_changes.sql
+
+DROP INDEX IF EXISTS idx_outage_status_changes_outage_id;
+DROP TABLE IF EXISTS outage_status_changes;
@@ -0,0 +1,12 @@
+-- Per-user preferences keyed by OIDC subject. Read by the UI, Slack DMs and
+-- report delivery to decide timezone, default filters and notification routing.
+CREATE TABLE IF NOT EXISTS user_preferences (
+    subject VARCHAR(255) PRIMARY KEY,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    default_team_filter VARCHAR(255) NOT NULL DEFAULT '',
+    notifications JSONB NOT NULL DEFAULT '{}'::jsonb,
+    digest_opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON COLUMN user_preferences.subject IS 'OIDC subject (sub claim) of the user';
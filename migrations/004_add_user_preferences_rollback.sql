@@ -0,0 +1,4 @@
+-- Rollback migration for user preferences
+-- This script reverses the changes made in 004_add_user_preferences.sql
+
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,16 @@
+-- Track the post-resolution review (postmortem) of each outage separately
+-- from its operational status so the review backlog can be filtered and
+-- chased independently.
+CREATE TABLE IF NOT EXISTS outage_reviews (
+    outage_id UUID PRIMARY KEY REFERENCES outages(id) ON DELETE CASCADE,
+    status VARCHAR(32) NOT NULL,
+    scheduled_for TIMESTAMP,
+    reviewed_at TIMESTAMP,
+    reviewer VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outage_reviews_status ON outage_reviews(status, created_at);
+
+COMMENT ON COLUMN outage_reviews.status IS 'needs-review, review-scheduled or reviewed';
@@ -0,0 +1,4 @@
+-- Rollback migration for outage reviews
+-- This script reverses the changes made in 005_add_outage_reviews.sql
+
+DROP TABLE IF EXISTS outage_reviews;
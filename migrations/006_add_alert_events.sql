@@ -0,0 +1,20 @@
+-- Record provider-side alert events (responders notified, escalations,
+-- reassignments) fetched from PagerDuty and OpsGenie logs so the outage
+-- timeline shows who was paged when.
+CREATE TABLE IF NOT EXISTS alert_events (
+    id UUID PRIMARY KEY,
+    alert_id UUID NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    external_id VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    target VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (alert_id, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_alert_id ON alert_events(alert_id, occurred_at);
+
+COMMENT ON COLUMN alert_events.external_id IS 'Provider log entry ID; re-fetched entries are ignored';
+COMMENT ON COLUMN alert_events.type IS 'notified, escalated or reassigned';
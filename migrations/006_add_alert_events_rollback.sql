@@ -0,0 +1,5 @@
+-- Rollback migration for alert events
+-- This script reverses the changes made in 006_add_alert_events.sql
+
+DROP INDEX IF EXISTS idx_alert_events_alert_id;
+DROP TABLE IF EXISTS alert_events;
@@ -0,0 +1,10 @@
+-- Track how far each notification service has been synced by the background
+-- alert sync poller so restarts resume instead of re-fetching all history.
+CREATE TABLE IF NOT EXISTS alert_sync_cursors (
+    source VARCHAR(50) PRIMARY KEY,
+    synced_until TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON COLUMN alert_sync_cursors.source IS 'Notification service name, e.g. pagerduty or opsgenie';
+COMMENT ON COLUMN alert_sync_cursors.synced_until IS 'Start time of the last sync pass that stored every fetched alert';
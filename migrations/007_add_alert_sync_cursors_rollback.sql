@@ -0,0 +1,4 @@
+-- Rollback migration for alert sync cursors
+-- This script reverses the changes made in 007_add_alert_sync_cursors.sql
+
+DROP TABLE IF EXISTS alert_sync_cursors;
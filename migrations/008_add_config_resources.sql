@@ -0,0 +1,12 @@
+-- Store declaratively managed operational config (teams, tag schemas,
+-- routing rules and outage templates) applied with outalatorctl.
+CREATE TABLE IF NOT EXISTS config_resources (
+    kind VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    spec JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (kind, name)
+);
+
+COMMENT ON COLUMN config_resources.kind IS 'team, tag_schema, routing_rule or template';
+COMMENT ON COLUMN config_resources.spec IS 'JSON encoding of the resource for its kind';
@@ -0,0 +1,4 @@
+-- Rollback migration for config resources
+-- This script reverses the changes made in 008_add_config_resources.sql
+
+DROP TABLE IF EXISTS config_resources;
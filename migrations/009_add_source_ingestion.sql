@@ -0,0 +1,12 @@
+-- Track the latest successful and failed alert ingestion per source so a
+-- silently broken webhook subscription or sync can be detected.
+CREATE TABLE IF NOT EXISTS source_ingestion (
+    source VARCHAR(50) PRIMARY KEY,
+    last_success_at TIMESTAMP,
+    last_failure_at TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON COLUMN source_ingestion.source IS 'Notification service or mail gateway name, e.g. pagerduty';
+COMMENT ON COLUMN source_ingestion.last_error IS 'Error from the last failed webhook delivery or sync pass';
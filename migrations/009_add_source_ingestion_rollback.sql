@@ -0,0 +1,4 @@
+-- Rollback migration for source ingestion tracking
+-- This script reverses the changes made in 009_add_source_ingestion.sql
+
+DROP TABLE IF EXISTS source_ingestion;
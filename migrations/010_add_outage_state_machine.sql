@@ -0,0 +1,12 @@
+-- Record when outages first entered each working status of the outage
+-- state machine, and who took each status transition and why.
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS investigating_at TIMESTAMP;
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS mitigated_at TIMESTAMP;
+
+ALTER TABLE outage_status_changes ADD COLUMN IF NOT EXISTS action VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE outage_status_changes ADD COLUMN IF NOT EXISTS actor VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE outage_status_changes ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN outages.investigating_at IS 'First time the outage entered investigating';
+COMMENT ON COLUMN outages.mitigated_at IS 'First time the outage entered mitigated';
+COMMENT ON COLUMN outage_status_changes.action IS 'State machine action taken, e.g. mitigate or reopen; empty for changes made before the state machine';
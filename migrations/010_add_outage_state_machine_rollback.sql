@@ -0,0 +1,9 @@
+-- Rollback migration for the outage state machine
+-- This script reverses the changes made in 010_add_outage_state_machine.sql
+
+ALTER TABLE outage_status_changes DROP COLUMN IF EXISTS reason;
+ALTER TABLE outage_status_changes DROP COLUMN IF EXISTS actor;
+ALTER TABLE outage_status_changes DROP COLUMN IF EXISTS action;
+
+ALTER TABLE outages DROP COLUMN IF EXISTS mitigated_at;
+ALTER TABLE outages DROP COLUMN IF EXISTS investigating_at;
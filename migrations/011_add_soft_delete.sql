@@ -0,0 +1,11 @@
+-- Move deleted outages and notes to a trash instead of removing them, so
+-- they can be restored until the retention job purges them.
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- The retention job looks up trashed rows by deletion time
+CREATE INDEX IF NOT EXISTS idx_outages_deleted_at ON outages(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes(deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN outages.deleted_at IS 'When the outage was moved to the trash; NULL for live outages';
+COMMENT ON COLUMN notes.deleted_at IS 'When the note was moved to the trash; NULL for live notes';
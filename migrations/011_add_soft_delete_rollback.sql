@@ -0,0 +1,9 @@
+-- Rollback migration for soft delete
+-- This script reverses the changes made in 011_add_soft_delete.sql.
+-- Trashed outages and notes become visible again.
+
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+DROP INDEX IF EXISTS idx_outages_deleted_at;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE outages DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,19 @@
+-- Files (graphs, log snippets, screenshots) uploaded to outages. The content
+-- lives in blob storage; this table holds what is needed to find and serve it.
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY,
+    outage_id UUID NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    note_id UUID REFERENCES notes(id) ON DELETE SET NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 VARCHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL,
+    uploaded_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_outage_id ON attachments(outage_id, created_at);
+
+COMMENT ON COLUMN attachments.storage_key IS 'Object key in the configured blob store';
+COMMENT ON COLUMN attachments.note_id IS 'Note that references the attachment through its metadata, if any';
@@ -0,0 +1,5 @@
+-- Rollback migration for attachments
+-- This script reverses the changes made in 012_add_attachments.sql.
+-- Uploaded content is left in the blob store.
+
+DROP TABLE IF EXISTS attachments;
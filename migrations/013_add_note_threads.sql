@@ -0,0 +1,7 @@
+-- Let notes reply to other notes on the same outage so an investigation can
+-- be read as threads. Replies whose parent is purged become top-level notes.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS parent_note_id UUID REFERENCES notes(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notes_parent_note_id ON notes(parent_note_id) WHERE parent_note_id IS NOT NULL;
+
+COMMENT ON COLUMN notes.parent_note_id IS 'Top-level note this note replies to; NULL for top-level notes';
@@ -0,0 +1,7 @@
+-- Rollback migration for note threads
+-- This script reverses the changes made in 013_add_note_threads.sql.
+-- Replies become top-level notes.
+
+DROP INDEX IF EXISTS idx_notes_parent_note_id;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS parent_note_id;
@@ -0,0 +1,15 @@
+-- Keep the content a note had before each edit so changes made during and
+-- after an outage can be traced in its postmortem.
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id UUID PRIMARY KEY,
+    note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    format VARCHAR(50) NOT NULL,
+    edited_by VARCHAR(255) NOT NULL DEFAULT '',
+    edited_at TIMESTAMP NOT NULL,
+    UNIQUE (note_id, version)
+);
+
+COMMENT ON COLUMN note_revisions.version IS 'Version of the note replaced by the edit; 1 is the content the note was created with';
+COMMENT ON COLUMN note_revisions.edited_by IS 'Signed-in user who made the edit; empty when authentication is off';
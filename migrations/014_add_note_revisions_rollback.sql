@@ -0,0 +1,5 @@
+-- Rollback migration for note revisions
+-- This script reverses the changes made in 014_add_note_revisions.sql.
+-- Earlier versions of edited notes are lost.
+
+DROP TABLE IF EXISTS note_revisions;
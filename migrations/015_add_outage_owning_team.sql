@@ -0,0 +1,8 @@
+-- Record which team owns an outage so on-call engineers can list their own
+-- team's incidents and only the owning team can change them.
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS owning_team VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Team-scoped listings filter on the owner
+CREATE INDEX IF NOT EXISTS idx_outages_owning_team ON outages(owning_team) WHERE owning_team <> '';
+
+COMMENT ON COLUMN outages.owning_team IS 'Name of the team resource that owns the outage; empty when unowned';
@@ -0,0 +1,7 @@
+-- Rollback migration for outage ownership
+-- This script reverses the changes made in 015_add_outage_owning_team.sql.
+-- Outage owners are lost; teams themselves are kept in config_resources.
+
+DROP INDEX IF EXISTS idx_outages_owning_team;
+
+ALTER TABLE outages DROP COLUMN IF EXISTS owning_team;
@@ -0,0 +1,25 @@
+-- Record who held each responder role (incident commander, comms lead,
+-- scribe) on an outage. Ended assignments are kept so the outage's responder
+-- history can be shown in its timeline and counted in load reports.
+CREATE TABLE IF NOT EXISTS responder_assignments (
+    id UUID PRIMARY KEY,
+    outage_id UUID NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL,
+    responder VARCHAR(255) NOT NULL,
+    source VARCHAR(100) NOT NULL DEFAULT '',
+    schedule_id VARCHAR(255) NOT NULL DEFAULT '',
+    assigned_by VARCHAR(255) NOT NULL DEFAULT '',
+    assigned_at TIMESTAMP NOT NULL,
+    unassigned_by VARCHAR(255) NOT NULL DEFAULT '',
+    unassigned_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_responder_assignments_outage_id ON responder_assignments(outage_id, assigned_at);
+CREATE INDEX IF NOT EXISTS idx_responder_assignments_assigned_at ON responder_assignments(assigned_at);
+
+-- At most one responder holds a role on an outage at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_responder_assignments_active_role
+    ON responder_assignments(outage_id, role) WHERE unassigned_at IS NULL;
+
+COMMENT ON COLUMN responder_assignments.source IS 'Notification service whose on-call schedule the responder was taken from; empty when named directly';
+COMMENT ON COLUMN responder_assignments.unassigned_at IS 'When the responder stopped holding the role; NULL while they still hold it';
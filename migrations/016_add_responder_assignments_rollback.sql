@@ -0,0 +1,5 @@
+-- Rollback migration for responder assignments
+-- This script reverses the changes made in 016_add_responder_assignments.sql.
+-- Every outage's responder history is lost.
+
+DROP TABLE IF EXISTS responder_assignments;
@@ -0,0 +1,14 @@
+-- Record the IDs of events delivered by external systems such as Slack once
+-- they have been processed, so retried deliveries are ignored. Rows are
+-- purged once the sender can no longer retry them.
+CREATE TABLE IF NOT EXISTS processed_events (
+    source VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (source, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events(source, processed_at);
+
+COMMENT ON COLUMN processed_events.source IS 'Sender of the event, e.g. slack';
+COMMENT ON COLUMN processed_events.event_id IS 'The sender''s ID for the event, e.g. a Slack event_id';
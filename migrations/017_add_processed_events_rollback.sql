@@ -0,0 +1,4 @@
+-- Rollback migration for processed events
+-- This script reverses the changes made in 017_add_processed_events.sql
+
+DROP TABLE IF EXISTS processed_events;
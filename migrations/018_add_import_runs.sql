@@ -0,0 +1,26 @@
+-- Record the progress and statistics of historical imports run by
+-- cmd/import-history, so an interrupted import can be resumed and past runs
+-- inspected through the API
+CREATE TABLE IF NOT EXISTS import_runs (
+    id UUID PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    filter_hash VARCHAR(64) NOT NULL,
+    since TIMESTAMP NOT NULL,
+    until TIMESTAMP NOT NULL,
+    team_ids JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL,
+    resume_offset INTEGER NOT NULL DEFAULT 0,
+    last_item_at TIMESTAMP,
+    stats JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_runs_provider ON import_runs(provider, started_at DESC);
+
+COMMENT ON COLUMN import_runs.filter_hash IS 'Hash of the kind, date range and teams imported; a run is resumed only with the same filters';
+COMMENT ON COLUMN import_runs.resume_offset IS 'Alerts or incidents fetched and processed so far, where a resumed run continues';
+COMMENT ON COLUMN import_runs.stats IS 'Counts of what the run has done: fetched, created, skipped and failed';
@@ -0,0 +1,4 @@
+-- Rollback migration for import runs
+-- This script reverses the changes made in 018_add_import_runs.sql
+
+DROP TABLE IF EXISTS import_runs;
@@ -0,0 +1,11 @@
+-- Keep every team an alert was routed to, rather than only the first, so
+-- team filters match alerts raised for several teams. team_name stays the
+-- alert's primary team.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS team_names JSONB NOT NULL DEFAULT '[]';
+
+UPDATE alerts SET team_names = jsonb_build_array(team_name)
+WHERE team_names = '[]' AND team_name IS NOT NULL AND team_name <> '';
+
+CREATE INDEX IF NOT EXISTS idx_alerts_team_names ON alerts USING GIN (team_names);
+
+COMMENT ON COLUMN alerts.team_names IS 'Every team the alert was routed to, team_name first';
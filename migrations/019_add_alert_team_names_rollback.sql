@@ -0,0 +1,7 @@
+-- Rollback migration for alert team names
+-- This script reverses the changes made in 019_add_alert_team_names.sql.
+-- Alerts keep only their primary team in team_name.
+
+DROP INDEX IF EXISTS idx_alerts_team_names;
+
+ALTER TABLE alerts DROP COLUMN IF EXISTS team_names;
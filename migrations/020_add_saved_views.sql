@@ -0,0 +1,16 @@
+-- Store named outage filters users save to list and execute later, and the
+-- Slack channel each one's periodic summary is sent to
+CREATE TABLE IF NOT EXISTS saved_views (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    owner VARCHAR(255) NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    slack_channel VARCHAR(255) NOT NULL DEFAULT '',
+    summary_period VARCHAR(20) NOT NULL DEFAULT '',
+    last_summary_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON COLUMN saved_views.filter IS 'Statuses, minimum severity, tags and teams an outage must match';
+COMMENT ON COLUMN saved_views.summary_period IS 'How often a summary is sent to slack_channel: daily or weekly';
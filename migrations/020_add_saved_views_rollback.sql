@@ -0,0 +1,4 @@
+-- Rollback migration for saved views
+-- This script reverses the changes made in 020_add_saved_views.sql
+
+DROP TABLE IF EXISTS saved_views;
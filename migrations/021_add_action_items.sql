@@ -0,0 +1,20 @@
+-- Track follow-up action items on outages as first-class records with an
+-- assignee, due date and status, rather than as notes marked action_item
+CREATE TABLE IF NOT EXISTS action_items (
+    id UUID PRIMARY KEY,
+    outage_id UUID NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    assignee VARCHAR(255) NOT NULL DEFAULT '',
+    due_date TIMESTAMP,
+    status VARCHAR(50) NOT NULL DEFAULT 'open',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_action_items_outage_id ON action_items(outage_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_action_items_due_date ON action_items(due_date) WHERE status IN ('open', 'in_progress');
+
+COMMENT ON COLUMN action_items.status IS 'open, in_progress, done or cancelled';
+COMMENT ON COLUMN action_items.closed_at IS 'When the item was last done or cancelled; NULL while it is open';
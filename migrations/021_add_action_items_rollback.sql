@@ -0,0 +1,5 @@
+-- Rollback migration for action items
+-- This script reverses the changes made in 021_add_action_items.sql.
+-- Every outage's action items are lost.
+
+DROP TABLE IF EXISTS action_items;
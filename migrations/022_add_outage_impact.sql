@@ -0,0 +1,22 @@
+-- Record the impact of outages: which catalogued services they affected,
+-- whether customers noticed, and when impact started and ended, which can
+-- differ from when the outage was opened and resolved.
+CREATE TABLE IF NOT EXISTS services (
+    name VARCHAR(255) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    statuspage_component VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS affected_services JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS customer_impact BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS impact_started_at TIMESTAMP;
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS impact_ended_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_outages_affected_services ON outages USING GIN (affected_services);
+
+COMMENT ON TABLE services IS 'Service catalogue; outages name the services they affect';
+COMMENT ON COLUMN outages.affected_services IS 'Names of the catalogued services the outage affects';
+COMMENT ON COLUMN outages.impact_started_at IS 'When impact began; NULL to use created_at';
+COMMENT ON COLUMN outages.impact_ended_at IS 'When impact ended; NULL to use resolved_at';
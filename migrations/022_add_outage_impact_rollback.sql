@@ -0,0 +1,12 @@
+-- Rollback migration for outage impact
+-- This script reverses the changes made in 022_add_outage_impact.sql.
+-- The service catalogue and every outage's impact fields are lost.
+
+DROP INDEX IF EXISTS idx_outages_affected_services;
+
+ALTER TABLE outages DROP COLUMN IF EXISTS impact_ended_at;
+ALTER TABLE outages DROP COLUMN IF EXISTS impact_started_at;
+ALTER TABLE outages DROP COLUMN IF EXISTS customer_impact;
+ALTER TABLE outages DROP COLUMN IF EXISTS affected_services;
+
+DROP TABLE IF EXISTS services;
@@ -0,0 +1,16 @@
+-- Extend the service catalogue with ownership and operational details, and
+-- record which service an alert is about. Services synced from a YAML file
+-- or Backstage record the catalogue they came from.
+ALTER TABLE services ADD COLUMN IF NOT EXISTS owning_team VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS tier INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS runbook_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS repo TEXT NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS source VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS service VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_alerts_service ON alerts(service);
+
+COMMENT ON COLUMN services.tier IS 'Criticality, 1 being the most critical; 0 when untiered';
+COMMENT ON COLUMN services.source IS 'Catalogue the service is synced from; empty when managed through the API';
+COMMENT ON COLUMN alerts.service IS 'Name of the catalogued service the alert is about';
@@ -0,0 +1,14 @@
+-- Rollback migration for the extended service catalogue
+-- This script reverses the changes made in 023_extend_service_catalogue.sql.
+-- Service owners, tiers, runbooks and repos, and the services alerts are
+-- linked to, are lost.
+
+DROP INDEX IF EXISTS idx_alerts_service;
+
+ALTER TABLE alerts DROP COLUMN IF EXISTS service;
+
+ALTER TABLE services DROP COLUMN IF EXISTS source;
+ALTER TABLE services DROP COLUMN IF EXISTS repo;
+ALTER TABLE services DROP COLUMN IF EXISTS runbook_url;
+ALTER TABLE services DROP COLUMN IF EXISTS tier;
+ALTER TABLE services DROP COLUMN IF EXISTS owning_team;
@@ -0,0 +1,9 @@
+-- Record a fingerprint on each alert identifying the monitor that raised it:
+-- a hash of the alert's source and its title with IDs, addresses, host names
+-- and numbers stripped. Alerts stored before this migration have an empty
+-- fingerprint, which the noisy alerts report computes as it reads them.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_alerts_fingerprint ON alerts(fingerprint);
+
+COMMENT ON COLUMN alerts.fingerprint IS 'Hash of the source and normalized title, shared by alerts from the same monitor';
@@ -0,0 +1,7 @@
+-- Rollback migration for alert fingerprints
+-- This script reverses the changes made in 024_add_alert_fingerprints.sql.
+-- Alerts stored since are fingerprinted again as the report reads them.
+
+DROP INDEX IF EXISTS idx_alerts_fingerprint;
+
+ALTER TABLE alerts DROP COLUMN IF EXISTS fingerprint;
@@ -0,0 +1,11 @@
+-- Let users watch individual outages and be notified by Slack direct
+-- message or email when their status changes or a note is added
+CREATE TABLE IF NOT EXISTS watchers (
+    outage_id UUID NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    channel VARCHAR(50) NOT NULL DEFAULT 'email',
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (outage_id, email)
+);
+
+COMMENT ON COLUMN watchers.channel IS 'slack or email';
@@ -0,0 +1,5 @@
+-- Rollback migration for outage watchers
+-- This script reverses the changes made in 025_add_watchers.sql.
+-- Every outage's watchers are lost.
+
+DROP TABLE IF EXISTS watchers;
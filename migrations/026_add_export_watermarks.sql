@@ -0,0 +1,10 @@
+-- Record how far each table has been exported to object storage by the
+-- scheduled analytics export, so each export only writes the rows changed
+-- since the last one
+CREATE TABLE IF NOT EXISTS export_watermarks (
+    table_name VARCHAR(50) PRIMARY KEY,
+    exported_until TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON COLUMN export_watermarks.exported_until IS 'Rows changed before this time have been exported';
@@ -0,0 +1,5 @@
+-- Rollback migration for analytics export watermarks
+-- This script reverses the changes made in 026_add_export_watermarks.sql.
+-- The next analytics export writes every row again.
+
+DROP TABLE IF EXISTS export_watermarks;
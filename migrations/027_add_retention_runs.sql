@@ -0,0 +1,23 @@
+-- Record each pass of the retention policy, which archives old resolved
+-- outages to export files and later deletes the files, so runs can be
+-- inspected through the admin API
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id UUID PRIMARY KEY,
+    run_trigger VARCHAR(20) NOT NULL,
+    triggered_by VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    archived_before TIMESTAMP NOT NULL,
+    archived_outages INTEGER NOT NULL DEFAULT 0,
+    archive_key TEXT NOT NULL DEFAULT '',
+    archive_purged_at TIMESTAMP,
+    purged_archives INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_runs_started_at ON retention_runs(started_at DESC);
+
+COMMENT ON COLUMN retention_runs.archived_before IS 'Outages resolved or closed before this time were archived by the run';
+COMMENT ON COLUMN retention_runs.archive_key IS 'Archive file written by the run, an outage export; empty when nothing was archived';
+COMMENT ON COLUMN retention_runs.archive_purged_at IS 'When a later run deleted this run''s archive file';
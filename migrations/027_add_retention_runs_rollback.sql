@@ -0,0 +1,6 @@
+-- Rollback migration for retention runs
+-- This script reverses the changes made in 027_add_retention_runs.sql.
+-- Archive files already written are left in place.
+
+DROP INDEX IF EXISTS idx_retention_runs_started_at;
+DROP TABLE IF EXISTS retention_runs;
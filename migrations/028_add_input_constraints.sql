@@ -0,0 +1,60 @@
+-- Back the service's input validation with checks on the values stored, so
+-- a record written around the service cannot be left unusable: outages need
+-- a title, a known status and an Outalator severity or none, notes a known
+-- format and tags a key. Lengths are already bounded by the column sizes.
+--
+-- Notes may also be stored as log+gzip, the stored format of compressed
+-- log notes. Outages opened from alerts whose severity the severity mapping
+-- has no entry for are left unclassified, with an empty severity.
+--
+-- Rows the checks would reject are not changed: the migration stops and
+-- names them, so an operator can decide how to fix them. Native alert
+-- severities left on outages by earlier versions can be mapped with the
+-- recompute-severity tool before re-running the migration. The migration
+-- runs in one transaction, so it adds every check or none of them.
+
+BEGIN;
+
+DO $$
+DECLARE
+    titles integer;
+    statuses integer;
+    severities integer;
+    formats integer;
+    keys integer;
+BEGIN
+    SELECT count(*) INTO titles FROM outages WHERE btrim(title) = '';
+    SELECT count(*) INTO statuses FROM outages
+        WHERE status NOT IN ('open', 'investigating', 'mitigated', 'resolved', 'closed');
+    SELECT count(*) INTO severities FROM outages
+        WHERE severity NOT IN ('', 'critical', 'high', 'medium', 'low');
+    SELECT count(*) INTO formats FROM notes
+        WHERE format NOT IN ('plaintext', 'markdown', 'log', 'log+gzip');
+    SELECT count(*) INTO keys FROM tags WHERE btrim(key) = '';
+
+    IF titles + statuses + severities + formats + keys > 0 THEN
+        RAISE EXCEPTION 'rows violate the input constraints: % outages with a blank title, % with an unknown status, % with an unknown severity, % notes with an unknown format, % tags with a blank key',
+            titles, statuses, severities, formats, keys
+            USING HINT = 'Fix or remove these rows and re-run the migration. Run recompute-severity to map native alert severities on outages.';
+    END IF;
+END
+$$;
+
+ALTER TABLE outages DROP CONSTRAINT IF EXISTS outages_title_not_blank;
+ALTER TABLE outages ADD CONSTRAINT outages_title_not_blank CHECK (btrim(title) <> '');
+
+ALTER TABLE outages DROP CONSTRAINT IF EXISTS outages_status_known;
+ALTER TABLE outages ADD CONSTRAINT outages_status_known
+    CHECK (status IN ('open', 'investigating', 'mitigated', 'resolved', 'closed'));
+
+ALTER TABLE outages DROP CONSTRAINT IF EXISTS outages_severity_known;
+ALTER TABLE outages ADD CONSTRAINT outages_severity_known
+    CHECK (severity IN ('', 'critical', 'high', 'medium', 'low'));
+
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_format_known;
+ALTER TABLE notes ADD CONSTRAINT notes_format_known CHECK (format IN ('plaintext', 'markdown', 'log', 'log+gzip'));
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_key_not_blank;
+ALTER TABLE tags ADD CONSTRAINT tags_key_not_blank CHECK (btrim(key) <> '');
+
+COMMIT;
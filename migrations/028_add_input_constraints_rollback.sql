@@ -0,0 +1,8 @@
+-- Rollback migration for input constraints
+-- This script reverses the changes made in 028_add_input_constraints.sql.
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_key_not_blank;
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_format_known;
+ALTER TABLE outages DROP CONSTRAINT IF EXISTS outages_severity_known;
+ALTER TABLE outages DROP CONSTRAINT IF EXISTS outages_status_known;
+ALTER TABLE outages DROP CONSTRAINT IF EXISTS outages_title_not_blank;
@@ -0,0 +1,9 @@
+-- Link outages as parent and child, such as a regional outage with an
+-- outage per affected service under it. Children outlive their parent and
+-- are simply unlinked when it is deleted.
+ALTER TABLE outages ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES outages(id) ON DELETE SET NULL;
+
+-- The parent view lists its children
+CREATE INDEX IF NOT EXISTS idx_outages_parent_id ON outages(parent_id) WHERE parent_id IS NOT NULL;
+
+COMMENT ON COLUMN outages.parent_id IS 'Outage this outage is a child of; NULL for top-level outages';
@@ -0,0 +1,7 @@
+-- Rollback migration for parent and child outages
+-- This script reverses the changes made in 029_add_outage_parents.sql.
+-- Links between parent and child outages are lost; the outages are kept.
+
+DROP INDEX IF EXISTS idx_outages_parent_id;
+
+ALTER TABLE outages DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,19 @@
+-- Record typed links between outages, such as duplicate reports and
+-- cascading failures, so related incidents can be analysed together.
+CREATE TABLE IF NOT EXISTS outage_relations (
+    id UUID PRIMARY KEY,
+    outage_id UUID NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    related_outage_id UUID NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (outage_id, related_outage_id, type),
+    CHECK (outage_id <> related_outage_id)
+);
+
+-- Relations are listed from either end
+CREATE INDEX IF NOT EXISTS idx_outage_relations_outage_id ON outage_relations(outage_id);
+CREATE INDEX IF NOT EXISTS idx_outage_relations_related_outage_id ON outage_relations(related_outage_id);
+
+COMMENT ON COLUMN outage_relations.type IS 'duplicate_of, caused_by or related_to, read from outage_id to related_outage_id';
@@ -0,0 +1,8 @@
+-- Rollback migration for outage relations
+-- This script reverses the changes made in 030_add_outage_relations.sql.
+-- Every link between outages is lost; the outages are kept.
+
+DROP INDEX IF EXISTS idx_outage_relations_related_outage_id;
+DROP INDEX IF EXISTS idx_outage_relations_outage_id;
+
+DROP TABLE IF EXISTS outage_relations;
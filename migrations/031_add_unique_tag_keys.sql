@@ -0,0 +1,8 @@
+-- Enforce tag schemas with unique: true in the database, so two requests
+-- adding the same unique key to an outage at once cannot both insert a tag.
+-- unique_key is set on tags written while their key's schema is unique; the
+-- partial index allows an outage one such tag per key. Tags written before
+-- are left as they are.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS unique_key BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_outage_unique_key ON tags(outage_id, key) WHERE unique_key;
@@ -0,0 +1,7 @@
+-- Rollback migration for unique tag keys
+-- This script reverses the changes made in 031_add_unique_tag_keys.sql.
+-- Unique tag keys are then only enforced by the service.
+
+DROP INDEX IF EXISTS idx_tags_outage_unique_key;
+
+ALTER TABLE tags DROP COLUMN IF EXISTS unique_key;
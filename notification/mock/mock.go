@@ -0,0 +1,401 @@
+// Package mock implements a notification service that serves fake alerts,
+// teams and on-call schedules from a fixture file. It stands in for PagerDuty or OpsGenie in
+// demos and integration tests that have no provider credentials.
+//
+// Responses depend only on the fixture: fetching an alert returns it as the
+// fixture describes it, and webhook deliveries name a fixture alert and an
+// action to apply to it.
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSource is the notification source name alerts are recorded under
+// when Config.Source is empty
+const DefaultSource = "mock"
+
+// Webhook actions
+const (
+	ActionTrigger     = "trigger"
+	ActionAcknowledge = "acknowledge"
+	ActionResolve     = "resolve"
+)
+
+// Fixture is the content served by the mock provider
+type Fixture struct {
+	Teams     []Team         `yaml:"teams"`
+	Schedules []Schedule     `yaml:"schedules"`
+	Alerts    []FixtureAlert `yaml:"alerts"`
+}
+
+// Team is a team known to the mock provider
+type Team struct {
+	ID      string   `yaml:"id"`
+	Name    string   `yaml:"name"`
+	Members []string `yaml:"members"` // Email addresses
+}
+
+// Schedule is an on-call schedule known to the mock provider. Its on-call
+// users are on call indefinitely.
+type Schedule struct {
+	ID     string   `yaml:"id"`
+	Name   string   `yaml:"name"`
+	OnCall []string `yaml:"on_call"` // Email addresses
+}
+
+// FixtureAlert is an alert in a fixture
+type FixtureAlert struct {
+	ID             string            `yaml:"id"`
+	Team           string            `yaml:"team"`
+	Title          string            `yaml:"title"`
+	Description    string            `yaml:"description"`
+	Severity       string            `yaml:"severity"`
+	TriggeredAt    time.Time         `yaml:"triggered_at"`
+	AcknowledgedAt *time.Time        `yaml:"acknowledged_at"`
+	ResolvedAt     *time.Time        `yaml:"resolved_at"`
+	Log            []FixtureLogEntry `yaml:"log"`
+}
+
+// FixtureLogEntry is an entry in a fixture alert's log
+type FixtureLogEntry struct {
+	ID         string    `yaml:"id"`
+	Type       string    `yaml:"type"` // One of the notification.LogEntry* constants
+	Summary    string    `yaml:"summary"`
+	Actor      string    `yaml:"actor"`
+	Target     string    `yaml:"target"`
+	OccurredAt time.Time `yaml:"occurred_at"`
+}
+
+// LoadFixture reads a YAML fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock fixture: %w", err)
+	}
+	var f Fixture
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse mock fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Config holds mock provider configuration
+type Config struct {
+	Source  string // Alert source name, default "mock"
+	Fixture *Fixture
+}
+
+// Service implements the notification.Service interface from a fixture
+type Service struct {
+	source    string
+	teams     []Team
+	schedules []Schedule
+	alerts    []FixtureAlert
+	byID      map[string]*FixtureAlert
+}
+
+// New creates a mock provider, checking that every fixture alert has a
+// unique ID and a title
+func New(cfg Config) (*Service, error) {
+	if cfg.Source == "" {
+		cfg.Source = DefaultSource
+	}
+	if cfg.Fixture == nil {
+		return nil, errors.New("mock provider needs a fixture")
+	}
+
+	s := &Service{
+		source:    cfg.Source,
+		teams:     cfg.Fixture.Teams,
+		schedules: cfg.Fixture.Schedules,
+		alerts:    cfg.Fixture.Alerts,
+		byID:      make(map[string]*FixtureAlert, len(cfg.Fixture.Alerts)),
+	}
+	for i := range s.alerts {
+		a := &s.alerts[i]
+		if a.ID == "" || a.Title == "" {
+			return nil, fmt.Errorf("mock alert #%d needs an id and a title", i+1)
+		}
+		if _, ok := s.byID[a.ID]; ok {
+			return nil, fmt.Errorf("duplicate mock alert id %q", a.ID)
+		}
+		s.byID[a.ID] = a
+	}
+	return s, nil
+}
+
+// Name implements notification.Service
+func (s *Service) Name() string {
+	return s.source
+}
+
+// FetchAlert implements notification.Service
+func (s *Service) FetchAlert(_ context.Context, alertID string) (*notification.Alert, error) {
+	a, ok := s.byID[alertID]
+	if !ok {
+		return nil, fmt.Errorf("mock alert %s: %w", alertID, domain.ErrNotFound)
+	}
+	return s.alert(a), nil
+}
+
+// FetchRecentAlerts implements notification.Service. It returns the alerts
+// triggered, acknowledged or resolved since the given time, in fixture
+// order.
+func (s *Service) FetchRecentAlerts(_ context.Context, since time.Time) ([]*notification.Alert, error) {
+	var alerts []*notification.Alert
+	for i := range s.alerts {
+		a := &s.alerts[i]
+		if a.TriggeredAt.After(since) || after(a.AcknowledgedAt, since) || after(a.ResolvedAt, since) {
+			alerts = append(alerts, s.alert(a))
+		}
+	}
+	return alerts, nil
+}
+
+// FetchHistoricalAlerts implements notification.HistoricalFetcher. It pages
+// through the alerts triggered in the range, oldest first, keeping those of
+// the given teams when any are named. Fixture teams are matched by ID or
+// name.
+func (s *Service) FetchHistoricalAlerts(_ context.Context, opts notification.HistoricalFetchOptions) ([]*notification.Alert, bool, error) {
+	var matched []*FixtureAlert
+	for i := range s.alerts {
+		a := &s.alerts[i]
+		if a.TriggeredAt.Before(opts.Since) || !opts.Until.IsZero() && !a.TriggeredAt.Before(opts.Until) {
+			continue
+		}
+		if len(opts.TeamIDs) > 0 && !s.inTeams(a.Team, opts.TeamIDs) {
+			continue
+		}
+		matched = append(matched, a)
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].TriggeredAt.Before(matched[j].TriggeredAt) })
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	start := min(opts.Offset, len(matched))
+	end := min(start+limit, len(matched))
+	alerts := make([]*notification.Alert, 0, end-start)
+	for _, a := range matched[start:end] {
+		alerts = append(alerts, s.alert(a))
+	}
+	return alerts, end < len(matched), nil
+}
+
+// inTeams reports whether the team named team is one of ids, given as
+// fixture team IDs or names
+func (s *Service) inTeams(team string, ids []string) bool {
+	for _, id := range ids {
+		if id == team {
+			return true
+		}
+		for _, t := range s.teams {
+			if t.ID == id && t.Name == team {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func after(t *time.Time, since time.Time) bool {
+	return t != nil && t.After(since)
+}
+
+// FetchLogEntries implements notification.LogEntryFetcher
+func (s *Service) FetchLogEntries(_ context.Context, alertID string) ([]*notification.LogEntry, error) {
+	a, ok := s.byID[alertID]
+	if !ok {
+		return nil, fmt.Errorf("mock alert %s: %w", alertID, domain.ErrNotFound)
+	}
+	entries := make([]*notification.LogEntry, len(a.Log))
+	for i, e := range a.Log {
+		entries[i] = &notification.LogEntry{
+			ExternalID: e.ID,
+			Type:       e.Type,
+			Summary:    e.Summary,
+			Actor:      e.Actor,
+			Target:     e.Target,
+			OccurredAt: e.OccurredAt,
+		}
+	}
+	return entries, nil
+}
+
+// ListTeams returns the fixture's teams
+func (s *Service) ListTeams(context.Context) ([]Team, error) {
+	return s.teams, nil
+}
+
+// FetchTeams implements notification.TeamFetcher with the fixture's teams
+func (s *Service) FetchTeams(context.Context) ([]*notification.Team, error) {
+	teams := make([]*notification.Team, len(s.teams))
+	for i, t := range s.teams {
+		teams[i] = &notification.Team{ExternalID: t.ID, Name: t.Name, Members: t.Members}
+	}
+	return teams, nil
+}
+
+// FetchOnCall implements notification.OnCallFetcher with the fixture's
+// schedules. An unknown schedule is an error.
+func (s *Service) FetchOnCall(_ context.Context, scheduleID string) ([]*notification.OnCall, error) {
+	var oncalls []*notification.OnCall
+	found := false
+	for _, sched := range s.schedules {
+		if scheduleID != "" && sched.ID != scheduleID {
+			continue
+		}
+		found = true
+		for _, email := range sched.OnCall {
+			oncalls = append(oncalls, &notification.OnCall{
+				ScheduleID:   sched.ID,
+				ScheduleName: sched.Name,
+				Name:         email,
+				Email:        email,
+			})
+		}
+	}
+	if scheduleID != "" && !found {
+		return nil, fmt.Errorf("mock schedule %s: %w", scheduleID, domain.ErrNotFound)
+	}
+	return oncalls, nil
+}
+
+// CreateIncident implements notification.Service. The mock provider pages
+// nobody: it returns a triggered alert for the request's team, identified by
+// the dedup key, without adding it to the fixture.
+func (s *Service) CreateIncident(_ context.Context, req notification.IncidentRequest) (*notification.Alert, error) {
+	if req.DedupKey == "" {
+		return nil, fmt.Errorf("mock incidents need a dedup key: %w", domain.ErrInvalidInput)
+	}
+	team := req.Team
+	if team == "" {
+		team = req.Service
+	}
+	return &notification.Alert{
+		ExternalID:  pagePrefix + req.DedupKey,
+		Source:      s.source,
+		TeamName:    team,
+		Title:       req.Title,
+		Description: req.Description,
+		Severity:    req.Severity,
+		TriggeredAt: time.Now(),
+	}, nil
+}
+
+// pagePrefix starts the external IDs of alerts opened by CreateIncident
+const pagePrefix = "page-"
+
+// AcknowledgeAlert implements notification.Service. Like ResolveAlert it
+// only checks that the alert exists; the fixture is left unchanged.
+func (s *Service) AcknowledgeAlert(_ context.Context, alertID, _ string) error {
+	return s.checkAlert(alertID)
+}
+
+// ResolveAlert implements notification.Service
+func (s *Service) ResolveAlert(_ context.Context, alertID, _ string) error {
+	return s.checkAlert(alertID)
+}
+
+// checkAlert reports whether alertID names a fixture alert or one opened by
+// CreateIncident
+func (s *Service) checkAlert(alertID string) error {
+	if _, ok := s.byID[alertID]; ok || strings.HasPrefix(alertID, pagePrefix) {
+		return nil
+	}
+	return fmt.Errorf("mock alert %s: %w", alertID, domain.ErrNotFound)
+}
+
+// WebhookHandler implements notification.Service
+func (s *Service) WebhookHandler() interface{} {
+	return nil
+}
+
+// VerifyWebhook implements notification.WebhookParser. The mock provider
+// only replays fixture alerts, so every delivery is accepted.
+func (s *Service) VerifyWebhook(http.Header, []byte) bool {
+	return true
+}
+
+// ParseWebhook implements notification.WebhookParser. A delivery names a
+// fixture alert and an action:
+//
+//	{"alert_id": "MOCK-1", "action": "acknowledge"}
+//
+// The trigger action returns the alert with its fixture state and triggers
+// it at receivedAt if the fixture has no trigger time. Acknowledge and
+// resolve set the matching timestamp to receivedAt unless the fixture
+// already has one.
+func (s *Service) ParseWebhook(payload []byte, receivedAt time.Time) ([]*notification.Alert, error) {
+	var delivery struct {
+		AlertID string `json:"alert_id"`
+		Action  string `json:"action"`
+	}
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return nil, fmt.Errorf("failed to decode mock webhook: %w", err)
+	}
+	if delivery.AlertID == "" {
+		return nil, nil
+	}
+
+	a, ok := s.byID[delivery.AlertID]
+	if !ok {
+		return nil, fmt.Errorf("mock alert %s: %w", delivery.AlertID, domain.ErrNotFound)
+	}
+	alert := s.alert(a)
+	if alert.TriggeredAt.IsZero() {
+		alert.TriggeredAt = receivedAt
+	}
+	switch delivery.Action {
+	case ActionTrigger, "":
+	case ActionAcknowledge:
+		if alert.AcknowledgedAt == nil {
+			alert.AcknowledgedAt = &receivedAt
+		}
+	case ActionResolve:
+		if alert.ResolvedAt == nil {
+			alert.ResolvedAt = &receivedAt
+		}
+	default:
+		return nil, fmt.Errorf("unknown mock webhook action %q: %w", delivery.Action, domain.ErrInvalidInput)
+	}
+	return []*notification.Alert{alert}, nil
+}
+
+// alert converts a fixture alert, copying its timestamps so callers cannot
+// change the fixture
+func (s *Service) alert(a *FixtureAlert) *notification.Alert {
+	return &notification.Alert{
+		ExternalID:     a.ID,
+		Source:         s.source,
+		TeamName:       a.Team,
+		Title:          a.Title,
+		Description:    a.Description,
+		Severity:       a.Severity,
+		TriggeredAt:    a.TriggeredAt,
+		AcknowledgedAt: copyTime(a.AcknowledgedAt),
+		ResolvedAt:     copyTime(a.ResolvedAt),
+	}
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
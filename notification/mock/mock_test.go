@@ -0,0 +1,231 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func newService(t *testing.T) *Service {
+	t.Helper()
+	fixture, err := LoadFixture("testdata/fixture.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(Config{Fixture: fixture})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFetch(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+
+	alert, err := s.FetchAlert(ctx, "MOCK-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.Source != DefaultSource || alert.TeamName != "payments" || alert.AcknowledgedAt == nil || alert.ResolvedAt != nil {
+		t.Errorf("alert = %+v, want acknowledged payments alert from %s", alert, DefaultSource)
+	}
+	if _, err := s.FetchAlert(ctx, "MOCK-99"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FetchAlert(unknown) error = %v, want ErrNotFound", err)
+	}
+
+	// MOCK-1 was acknowledged at 09:04 and MOCK-2 resolved at 11:00
+	recent, err := s.FetchRecentAlerts(ctx, time.Date(2024, 3, 1, 9, 2, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].ExternalID != "MOCK-1" || recent[1].ExternalID != "MOCK-2" {
+		t.Errorf("recent alerts = %v, want MOCK-1 and MOCK-2", recent)
+	}
+
+	entries, err := s.FetchLogEntries(ctx, "MOCK-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Type != notification.LogEntryEscalated {
+		t.Errorf("log entries = %+v, want notified then escalated", entries)
+	}
+
+	teams, err := s.ListTeams(ctx)
+	if err != nil || len(teams) != 2 {
+		t.Errorf("ListTeams() = %v, %v, want two teams", teams, err)
+	}
+
+	synced, err := s.FetchTeams(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(synced) != 2 || synced[0].ExternalID != "team-payments" || len(synced[0].Members) != 2 {
+		t.Errorf("FetchTeams() = %+v, want payments with two members first", synced)
+	}
+}
+
+func TestFetchHistoricalAlerts(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// MOCK-3 has not been triggered, so it has no history
+	var ids []string
+	opts := notification.HistoricalFetchOptions{Since: since, Limit: 1}
+	for {
+		page, more, err := s.FetchHistoricalAlerts(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range page {
+			ids = append(ids, a.ExternalID)
+		}
+		if !more {
+			break
+		}
+		opts.Offset += len(page)
+	}
+	if strings.Join(ids, ",") != "MOCK-1,MOCK-2" {
+		t.Errorf("paged alerts = %v, want MOCK-1 and MOCK-2", ids)
+	}
+
+	byTeam, _, err := s.FetchHistoricalAlerts(ctx, notification.HistoricalFetchOptions{Since: since, TeamIDs: []string{"team-payments"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byTeam) != 1 || byTeam[0].ExternalID != "MOCK-1" {
+		t.Errorf("payments alerts = %v, want MOCK-1", byTeam)
+	}
+
+	until := time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC)
+	early, _, err := s.FetchHistoricalAlerts(ctx, notification.HistoricalFetchOptions{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(early) != 1 || early[0].ExternalID != "MOCK-1" {
+		t.Errorf("alerts before %s = %v, want MOCK-1", until, early)
+	}
+}
+
+func TestFetchOnCall(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+
+	all, err := s.FetchOnCall(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("FetchOnCall(all) = %+v, want one on-call per schedule", all)
+	}
+
+	oncalls, err := s.FetchOnCall(ctx, "sched-platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(oncalls) != 1 || oncalls[0].Email != "carol@example.com" || oncalls[0].ScheduleName != "Platform primary" {
+		t.Errorf("FetchOnCall(sched-platform) = %+v, want carol", oncalls)
+	}
+
+	if _, err := s.FetchOnCall(ctx, "sched-unknown"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FetchOnCall(unknown) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIncidentActions(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+
+	paged, err := s.CreateIncident(ctx, notification.IncidentRequest{Title: "Checkout down", Team: "payments", DedupKey: "k1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paged.ExternalID != "page-k1" || paged.TeamName != "payments" || paged.Source != DefaultSource {
+		t.Errorf("CreateIncident = %+v, want page-k1 for payments", paged)
+	}
+	if _, err := s.CreateIncident(ctx, notification.IncidentRequest{Title: "t"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("CreateIncident without dedup key error = %v, want ErrInvalidInput", err)
+	}
+
+	for _, id := range []string{"MOCK-1", paged.ExternalID} {
+		if err := s.AcknowledgeAlert(ctx, id, ""); err != nil {
+			t.Errorf("AcknowledgeAlert(%s) = %v", id, err)
+		}
+		if err := s.ResolveAlert(ctx, id, ""); err != nil {
+			t.Errorf("ResolveAlert(%s) = %v", id, err)
+		}
+	}
+	if err := s.ResolveAlert(ctx, "MOCK-404", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("ResolveAlert(unknown) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	s := newService(t)
+	received := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		payload     string
+		wantTrigger time.Time
+		wantAck     bool
+		wantResolve bool
+		wantErr     error
+	}{
+		{"trigger without fixture time", `{"alert_id":"MOCK-3","action":"trigger"}`, received, false, false, nil},
+		{"acknowledge", `{"alert_id":"MOCK-3","action":"acknowledge"}`, received, true, false, nil},
+		{"resolve keeps fixture times", `{"alert_id":"MOCK-1","action":"resolve"}`, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), true, true, nil},
+		{"unknown alert", `{"alert_id":"MOCK-99"}`, time.Time{}, false, false, domain.ErrNotFound},
+		{"unknown action", `{"alert_id":"MOCK-1","action":"snooze"}`, time.Time{}, false, false, domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, err := s.ParseWebhook([]byte(tt.payload), received)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(alerts) != 1 {
+				t.Fatalf("got %d alerts, want 1", len(alerts))
+			}
+			a := alerts[0]
+			if !a.TriggeredAt.Equal(tt.wantTrigger) || (a.AcknowledgedAt != nil) != tt.wantAck || (a.ResolvedAt != nil) != tt.wantResolve {
+				t.Errorf("alert = %+v, want triggered %v, acknowledged %v, resolved %v", a, tt.wantTrigger, tt.wantAck, tt.wantResolve)
+			}
+		})
+	}
+
+	// Deliveries do not change what is fetched
+	if a, _ := s.FetchAlert(context.Background(), "MOCK-3"); a.AcknowledgedAt != nil || !a.TriggeredAt.IsZero() {
+		t.Errorf("fixture alert changed by webhook: %+v", a)
+	}
+}
+
+func TestNewRejectsBadFixture(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture *Fixture
+	}{
+		{"missing", nil},
+		{"no title", &Fixture{Alerts: []FixtureAlert{{ID: "A"}}}},
+		{"duplicate id", &Fixture{Alerts: []FixtureAlert{{ID: "A", Title: "a"}, {ID: "A", Title: "b"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(Config{Fixture: tt.fixture}); err == nil {
+				t.Error("New() succeeded, want error")
+			}
+		})
+	}
+}
@@ -2,20 +2,43 @@ package notification
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
 	"time"
 )
 
+// ErrUnsupported is wrapped by errors from services asked for something
+// their provider cannot do, such as resolving an alert that only arrived by
+// mail. Callers acting on many alerts skip those services.
+var ErrUnsupported = errors.New("not supported by this notification service")
+
 // Alert represents a notification alert from an oncall service
 type Alert struct {
 	ExternalID     string
-	Source         string // pagerduty, opsgenie, etc.
-	TeamName       string
+	Source         string   // pagerduty, opsgenie, etc.
+	TeamName       string   // Primary team, the first the alert was routed to
+	TeamNames      []string // Every team the alert was routed to, TeamName first; nil if only TeamName is known
 	Title          string
 	Description    string
 	Severity       string
+	IncidentKey    string // Key the provider groups repeats of the alert by (PagerDuty incident key, OpsGenie alias), if known
+	Service        string // Service or entity the alert was raised for, if known
 	TriggeredAt    time.Time
 	AcknowledgedAt *time.Time
 	ResolvedAt     *time.Time
+	SourceMetadata map[string]any // Provider-specific details, keyed as in an alert's source_metadata
+}
+
+// Teams returns every team the alert was routed to, its primary team first
+func (a *Alert) Teams() []string {
+	if len(a.TeamNames) > 0 {
+		return a.TeamNames
+	}
+	if a.TeamName == "" {
+		return nil
+	}
+	return []string{a.TeamName}
 }
 
 // Service defines the interface for oncall notification services
@@ -32,4 +55,160 @@ type Service interface {
 	// WebhookHandler returns an HTTP handler function for receiving webhooks
 	// This allows each service to implement its own webhook format
 	WebhookHandler() interface{}
+
+	// CreateIncident opens an incident with the provider, paging the
+	// responders of the request's service or team, and returns it as an
+	// alert. Services that cannot page anyone return an error wrapping
+	// domain.ErrInvalidInput.
+	CreateIncident(ctx context.Context, req IncidentRequest) (*Alert, error)
+
+	// AcknowledgeAlert acknowledges an alert with the provider on behalf of
+	// requester, the email address of the acting user if known
+	AcknowledgeAlert(ctx context.Context, alertID, requester string) error
+
+	// ResolveAlert resolves an alert with the provider on behalf of
+	// requester, the email address of the acting user if known
+	ResolveAlert(ctx context.Context, alertID, requester string) error
+}
+
+// IncidentRequest describes an incident to open with a notification service.
+// Which of Service and Team a provider needs depends on how it routes pages.
+type IncidentRequest struct {
+	Title       string
+	Description string
+	Severity    string // Outalator severity: critical, high, medium or low
+	Service     string // Provider service to open the incident on, e.g. a PagerDuty service ID
+	Team        string // Provider team to page, e.g. an OpsGenie team name
+	DedupKey    string // Lets the provider recognise a repeated request for the same incident
+	Requester   string // Email address of the user paging, if known
+}
+
+// WebhookParser is implemented by services whose webhook deliveries carry
+// enough data to build alerts without a round trip to the provider's API.
+// receivedAt is used for state changes the payload does not timestamp itself.
+// Payloads that describe no alert (e.g. test pings) return no alerts and no error.
+type WebhookParser interface {
+	// VerifyWebhook reports whether a delivery was sent by the provider,
+	// from its request headers and raw body. Deliveries that fail are
+	// rejected before they are queued.
+	VerifyWebhook(header http.Header, payload []byte) bool
+	ParseWebhook(payload []byte, receivedAt time.Time) ([]*Alert, error)
+}
+
+// WebhookTokenHeader carries the shared secret of providers that cannot
+// sign their webhook deliveries
+const WebhookTokenHeader = "X-Outalator-Webhook-Token"
+
+// VerifyWebhookToken reports whether header carries token in
+// WebhookTokenHeader. An empty token verifies nothing.
+func VerifyWebhookToken(header http.Header, token string) bool {
+	got := header.Get(WebhookTokenHeader)
+	if token == "" || got == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// Log entry types recorded from provider-side alert logs. Trigger,
+// acknowledge and resolve entries are not reported as log entries because
+// the alert's own timestamps already cover them.
+const (
+	LogEntryNotified   = "notified"   // A responder was paged
+	LogEntryEscalated  = "escalated"  // The alert moved to the next escalation level
+	LogEntryReassigned = "reassigned" // The alert was assigned to someone else
+	LogEntryResponders = "responders" // More responders were asked to help
+)
+
+// LogEntry is a single event from a provider's log for an alert, such as a
+// responder being notified or the alert being escalated.
+type LogEntry struct {
+	ExternalID string // Provider identifier for the entry, unique per alert
+	Type       string // One of the LogEntry* constants
+	Summary    string
+	Actor      string // Who or what caused the event, if known
+	Target     string // Who was notified or assigned, if applicable
+	OccurredAt time.Time
+}
+
+// LogEntryFetcher is implemented by services that expose a per-alert event
+// log. alertID is the provider's external ID for the alert. Entries are
+// returned oldest first.
+type LogEntryFetcher interface {
+	FetchLogEntries(ctx context.Context, alertID string) ([]*LogEntry, error)
+}
+
+// AlertNote is a note left on an alert with its provider, such as a
+// responder's comment
+type AlertNote struct {
+	ExternalID string // Provider identifier for the note
+	Author     string
+	Content    string
+	CreatedAt  time.Time
+}
+
+// NoteFetcher is implemented by services whose alerts can carry notes.
+// alertID is the provider's external ID for the alert. Notes are returned
+// oldest first.
+type NoteFetcher interface {
+	FetchNotes(ctx context.Context, alertID string) ([]*AlertNote, error)
+}
+
+// HistoricalFetchOptions selects a page of past alerts
+type HistoricalFetchOptions struct {
+	Since   time.Time
+	Until   time.Time // Zero for no end
+	TeamIDs []string  // Provider team identifiers; empty for every team
+	Limit   int       // Page size; zero for the provider's default
+	Offset  int
+}
+
+// HistoricalFetcher is implemented by services that can page through past
+// alerts, so their history can be imported. Alerts are returned oldest
+// first, and the returned flag reports whether there are more after the
+// page.
+type HistoricalFetcher interface {
+	FetchHistoricalAlerts(ctx context.Context, opts HistoricalFetchOptions) ([]*Alert, bool, error)
+}
+
+// Team is a team as listed by a notification service
+type Team struct {
+	ExternalID  string // Provider identifier for the team
+	Name        string
+	Description string
+	Members     []string // Email addresses of the team's members, if known
+}
+
+// TeamFetcher is implemented by services that can list their teams, so
+// Outalator's teams can be kept in sync with the provider's.
+type TeamFetcher interface {
+	FetchTeams(ctx context.Context) ([]*Team, error)
+}
+
+// OnCall is a user on call for a schedule as listed by a notification
+// service
+type OnCall struct {
+	ScheduleID   string // Provider identifier for the schedule
+	ScheduleName string
+	Name         string
+	Email        string
+	Start        time.Time // When the shift started; zero if unknown
+	End          time.Time // When the shift ends; zero if open-ended
+}
+
+// OnCallFetcher is implemented by services that can report who is on call.
+// An empty scheduleID lists who is on call for every schedule.
+type OnCallFetcher interface {
+	FetchOnCall(ctx context.Context, scheduleID string) ([]*OnCall, error)
+}
+
+// ErrInvalidCredentials is wrapped by errors from services whose provider
+// rejected their API key
+var ErrInvalidCredentials = errors.New("notification service credentials rejected")
+
+// CredentialChecker is implemented by services that can check their API key
+// with the provider. CheckCredentials returns an error wrapping
+// ErrInvalidCredentials when the provider rejects the key, and other errors
+// when the provider cannot be asked.
+type CredentialChecker interface {
+	CheckCredentials(ctx context.Context) error
 }
@@ -0,0 +1,60 @@
+package opsgenie
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/conall/outalator/domain"
+)
+
+// AcknowledgeAlert acknowledges an OpsGenie alert, crediting requester when
+// known
+func (s *Service) AcknowledgeAlert(ctx context.Context, alertID, requester string) error {
+	return s.alertAction(ctx, alertID, "acknowledge", requester)
+}
+
+// ResolveAlert closes an OpsGenie alert, crediting requester when known
+func (s *Service) ResolveAlert(ctx context.Context, alertID, requester string) error {
+	return s.alertAction(ctx, alertID, "close", requester)
+}
+
+// alertAction requests an action on an alert by its ID. OpsGenie accepts
+// actions asynchronously, so a nil error means the request was queued.
+func (s *Service) alertAction(ctx context.Context, alertID, action, requester string) error {
+	payload := struct {
+		User   string `json:"user,omitempty"`
+		Source string `json:"source"`
+	}{User: requester, Source: "outalator"}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", action, err)
+	}
+
+	path := fmt.Sprintf("/v2/alerts/%s/%s?identifierType=id", url.PathEscape(alertID), action)
+	req, err := http.NewRequestWithContext(ctx, "POST", s.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s alert: %w", action, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("OpsGenie alert %s: %w", alertID, domain.ErrNotFound)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("OpsGenie API error: %s (status: %d)", string(respBody), resp.StatusCode)
+}
@@ -0,0 +1,129 @@
+package opsgenie
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// OpsGenie processes alert creation asynchronously. The created alert's ID
+// is polled for this many times, this far apart.
+const (
+	createPollAttempts = 5
+	createPollInterval = time.Second
+)
+
+// priorities maps Outalator severities to OpsGenie priorities
+var priorities = map[string]string{
+	"critical": "P1",
+	"high":     "P2",
+	"medium":   "P3",
+	"low":      "P4",
+}
+
+// CreateIncident opens an OpsGenie alert routed to the request's team, which
+// pages the team's on-call. OpsGenie alerts rather than incidents are
+// created because alerts are what Outalator tracks from OpsGenie. The dedup
+// key is sent as the alert's alias, so repeating a request while its alert
+// is open adds to the existing alert instead of paging again.
+func (s *Service) CreateIncident(ctx context.Context, r notification.IncidentRequest) (*notification.Alert, error) {
+	if r.Team == "" {
+		return nil, fmt.Errorf("OpsGenie pages a team; a team name is required: %w", domain.ErrInvalidInput)
+	}
+
+	type responder struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	payload := struct {
+		Message     string      `json:"message"`
+		Description string      `json:"description,omitempty"`
+		Alias       string      `json:"alias,omitempty"`
+		Priority    string      `json:"priority,omitempty"`
+		Responders  []responder `json:"responders"`
+		User        string      `json:"user,omitempty"`
+		Source      string      `json:"source"`
+	}{
+		Message:     r.Title,
+		Description: r.Description,
+		Alias:       r.DedupKey,
+		Priority:    priorities[r.Severity],
+		Responders:  []responder{{Name: r.Team, Type: "team"}},
+		User:        r.Requester,
+		Source:      "outalator",
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.apiURL+"/v2/alerts", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("OpsGenie API error: %s (status: %d)", string(respBody), resp.StatusCode)
+	}
+	var accepted struct {
+		RequestID string `json:"requestId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&accepted); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	alertID, err := s.createdAlertID(ctx, accepted.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	return s.FetchAlert(ctx, alertID)
+}
+
+// createdAlertID waits for OpsGenie to process an alert creation request
+// and returns the ID of the alert it created
+func (s *Service) createdAlertID(ctx context.Context, requestID string) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt < createPollAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(createPollInterval):
+			}
+		}
+
+		var status struct {
+			Data struct {
+				Success bool   `json:"success"`
+				Status  string `json:"status"`
+				AlertID string `json:"alertId"`
+			} `json:"data"`
+		}
+		// Requests that are still being processed are reported as not found
+		if lastErr = s.get(ctx, "/v2/alerts/requests/"+url.PathEscape(requestID), &status); lastErr != nil {
+			continue
+		}
+		if !status.Data.Success {
+			return "", fmt.Errorf("OpsGenie did not create the alert: %s", status.Data.Status)
+		}
+		return status.Data.AlertID, nil
+	}
+	return "", fmt.Errorf("OpsGenie did not process alert request %s in time: %w", requestID, lastErr)
+}
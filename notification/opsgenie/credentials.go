@@ -0,0 +1,37 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/conall/outalator/notification"
+)
+
+// CheckCredentials checks the API key by listing a single alert, which any
+// key with read access can do
+func (s *Service) CheckCredentials(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.apiURL+"/v2/alerts?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to check credentials: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("OpsGenie API status %d: %w", resp.StatusCode, notification.ErrInvalidCredentials)
+	default:
+		return fmt.Errorf("OpsGenie API error (status: %d)", resp.StatusCode)
+	}
+}
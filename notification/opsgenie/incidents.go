@@ -0,0 +1,203 @@
+package opsgenie
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// Incident is an OpsGenie incident, which groups the alerts raised for one
+// problem. It corresponds to an outage rather than an alert.
+type Incident struct {
+	ID          string
+	TinyID      string
+	Message     string
+	Description string
+	Status      string // open, resolved or closed
+	Priority    string // P1 to P5
+	OwnerTeam   string // Team ID
+	Tags        []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TimelineEntry is an entry from an incident's timeline, such as a
+// responder's note or a status update
+type TimelineEntry struct {
+	ID        string
+	Group     string // e.g. "notes" or "status-updates"
+	Type      string
+	Actor     string
+	Content   string
+	EventTime time.Time
+}
+
+// FetchHistoricalIncidents retrieves incidents created in a time range from
+// OpsGenie. Team filtering uses the incident's owner team.
+func (s *Service) FetchHistoricalIncidents(ctx context.Context, opts notification.HistoricalFetchOptions) ([]*Incident, bool, error) {
+	query := fmt.Sprintf("createdAt > %d", opts.Since.Unix()*1000)
+	if !opts.Until.IsZero() {
+		query += fmt.Sprintf(" AND createdAt < %d", opts.Until.Unix()*1000)
+	}
+
+	limit := opts.Limit
+	if limit == 0 {
+		limit = 100 // Default limit
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("sort", "createdAt")
+	params.Set("order", "desc")
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("offset", strconv.Itoa(opts.Offset))
+
+	var result struct {
+		Data []struct {
+			ID          string    `json:"id"`
+			TinyID      string    `json:"tinyId"`
+			Message     string    `json:"message"`
+			Description string    `json:"description"`
+			Status      string    `json:"status"`
+			Priority    string    `json:"priority"`
+			OwnerTeam   string    `json:"ownerTeam"`
+			Tags        []string  `json:"tags"`
+			CreatedAt   time.Time `json:"createdAt"`
+			UpdatedAt   time.Time `json:"updatedAt"`
+		} `json:"data"`
+		Paging struct {
+			Next string `json:"next"`
+		} `json:"paging"`
+	}
+	if err := s.get(ctx, "/v1/incidents?"+params.Encode(), &result); err != nil {
+		return nil, false, fmt.Errorf("failed to fetch incidents: %w", err)
+	}
+
+	incidents := make([]*Incident, 0, len(result.Data))
+	for _, inc := range result.Data {
+		if len(opts.TeamIDs) > 0 && !slices.Contains(opts.TeamIDs, inc.OwnerTeam) {
+			continue
+		}
+		incidents = append(incidents, &Incident{
+			ID:          inc.ID,
+			TinyID:      inc.TinyID,
+			Message:     inc.Message,
+			Description: inc.Description,
+			Status:      inc.Status,
+			Priority:    inc.Priority,
+			OwnerTeam:   inc.OwnerTeam,
+			Tags:        inc.Tags,
+			CreatedAt:   inc.CreatedAt,
+			UpdatedAt:   inc.UpdatedAt,
+		})
+	}
+
+	return incidents, result.Paging.Next != "", nil
+}
+
+// FetchIncidentAlertIDs retrieves the IDs of the alerts associated with an
+// incident. Alerts can be fetched with FetchAlert.
+func (s *Service) FetchIncidentAlertIDs(ctx context.Context, incidentID string) ([]string, error) {
+	endpoint := fmt.Sprintf("/v1/incidents/%s/associated-alert-ids?identifierType=id", url.PathEscape(incidentID))
+
+	var result struct {
+		Data []string `json:"data"`
+	}
+	if err := s.get(ctx, endpoint, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch incident alerts: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// FetchIncidentTimeline retrieves the visible entries of an incident's
+// timeline, oldest first
+func (s *Service) FetchIncidentTimeline(ctx context.Context, incidentID string) ([]*TimelineEntry, error) {
+	var entries []*TimelineEntry
+	offset := ""
+	for {
+		params := url.Values{}
+		params.Set("order", "asc")
+		if offset != "" {
+			params.Set("offset", offset)
+		}
+		endpoint := fmt.Sprintf("/v2/incident-timelines/%s/entries?%s", url.PathEscape(incidentID), params.Encode())
+
+		var result struct {
+			Data struct {
+				Entries []struct {
+					ID        string    `json:"id"`
+					Group     string    `json:"group"`
+					Type      string    `json:"type"`
+					EventTime time.Time `json:"eventTime"`
+					Hidden    bool      `json:"hidden"`
+					Actor     struct {
+						Name string `json:"name"`
+					} `json:"actor"`
+					Description struct {
+						Content string `json:"content"`
+					} `json:"description"`
+				} `json:"entries"`
+				NextOffset string `json:"nextOffset"`
+			} `json:"data"`
+		}
+		if err := s.get(ctx, endpoint, &result); err != nil {
+			return nil, fmt.Errorf("failed to fetch incident timeline: %w", err)
+		}
+
+		for _, e := range result.Data.Entries {
+			if e.Hidden || e.Description.Content == "" {
+				continue
+			}
+			entries = append(entries, &TimelineEntry{
+				ID:        e.ID,
+				Group:     e.Group,
+				Type:      e.Type,
+				Actor:     e.Actor.Name,
+				Content:   e.Description.Content,
+				EventTime: e.EventTime,
+			})
+		}
+
+		if result.Data.NextOffset == "" || result.Data.NextOffset == offset {
+			break
+		}
+		offset = result.Data.NextOffset
+	}
+
+	return entries, nil
+}
+
+// get fetches path from the OpsGenie API and decodes the JSON response into v
+func (s *Service) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.apiURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("OpsGenie API error: %s (status: %d)", string(body), resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
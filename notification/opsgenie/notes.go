@@ -0,0 +1,81 @@
+package opsgenie
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// FetchNotes retrieves the notes added to an alert in OpsGenie
+func (s *Service) FetchNotes(ctx context.Context, alertID string) ([]*notification.AlertNote, error) {
+	const pageSize = 100
+
+	var notes []*notification.AlertNote
+	offset := ""
+	for {
+		params := url.Values{}
+		params.Set("identifierType", "id")
+		params.Set("order", "asc")
+		params.Set("direction", "next")
+		params.Set("limit", strconv.Itoa(pageSize))
+		if offset != "" {
+			params.Set("offset", offset)
+		}
+		endpoint := fmt.Sprintf("%s/v2/alerts/%s/notes?%s", s.apiURL, alertID, params.Encode())
+
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch alert notes: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("OpsGenie API error: %s (status: %d)", string(body), resp.StatusCode)
+		}
+
+		var result struct {
+			Data []struct {
+				Note      string    `json:"note"`
+				Owner     string    `json:"owner"`
+				CreatedAt time.Time `json:"createdAt"`
+				Offset    string    `json:"offset"`
+			} `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		// Notes have no ID of their own; their offset is unique per alert
+		for _, n := range result.Data {
+			notes = append(notes, &notification.AlertNote{
+				ExternalID: n.Offset,
+				Author:     n.Owner,
+				Content:    n.Note,
+				CreatedAt:  n.CreatedAt,
+			})
+		}
+
+		if len(result.Data) < pageSize {
+			break
+		}
+		offset = result.Data[len(result.Data)-1].Offset
+	}
+
+	return notes, nil
+}
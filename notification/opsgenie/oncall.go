@@ -0,0 +1,67 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/conall/outalator/notification"
+)
+
+// FetchOnCall retrieves who is currently on call for an OpsGenie schedule,
+// or for every schedule when scheduleID is empty. scheduleID may be a
+// schedule's ID or its name. OpsGenie identifies on-call users by username,
+// which it requires to be an email address.
+func (s *Service) FetchOnCall(ctx context.Context, scheduleID string) ([]*notification.OnCall, error) {
+	type schedule struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	var schedules []schedule
+	if scheduleID != "" {
+		var detail struct {
+			Data schedule `json:"data"`
+		}
+		// OpsGenie takes either form of identifier as long as it is told
+		// which, so the ID is tried first
+		err := s.get(ctx, fmt.Sprintf("/v2/schedules/%s?identifierType=id", url.PathEscape(scheduleID)), &detail)
+		if err != nil {
+			err = s.get(ctx, fmt.Sprintf("/v2/schedules/%s?identifierType=name", url.PathEscape(scheduleID)), &detail)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch schedule %s: %w", scheduleID, err)
+		}
+		schedules = append(schedules, detail.Data)
+	} else {
+		var list struct {
+			Data []schedule `json:"data"`
+		}
+		if err := s.get(ctx, "/v2/schedules", &list); err != nil {
+			return nil, fmt.Errorf("failed to fetch schedules: %w", err)
+		}
+		schedules = list.Data
+	}
+
+	var oncalls []*notification.OnCall
+	for _, sched := range schedules {
+		var result struct {
+			Data struct {
+				OnCallRecipients []string `json:"onCallRecipients"`
+			} `json:"data"`
+		}
+		path := fmt.Sprintf("/v2/schedules/%s/on-calls?scheduleIdentifierType=id&flat=true", url.PathEscape(sched.ID))
+		if err := s.get(ctx, path, &result); err != nil {
+			return nil, fmt.Errorf("failed to fetch on-calls of schedule %s: %w", sched.ID, err)
+		}
+		for _, username := range result.Data.OnCallRecipients {
+			oncalls = append(oncalls, &notification.OnCall{
+				ScheduleID:   sched.ID,
+				ScheduleName: sched.Name,
+				Name:         username,
+				Email:        username,
+			})
+		}
+	}
+	return oncalls, nil
+}
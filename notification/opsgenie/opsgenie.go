@@ -6,22 +6,37 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/conall/outalator/internal/httpclient"
 	"github.com/conall/outalator/notification"
 )
 
 // Service implements the notification.Service interface for OpsGenie
 type Service struct {
-	apiKey   string
-	apiURL   string
-	client   *http.Client
+	apiKey       string
+	apiURL       string
+	webhookToken string
+	client       *httpclient.Client
 }
 
 // Config holds OpsGenie configuration
 type Config struct {
 	APIKey string
 	APIURL string // Optional, defaults to OpsGenie API
+	// WebhookToken is the shared secret outgoing webhooks send in
+	// notification.WebhookTokenHeader, as OpsGenie does not sign them.
+	// Deliveries without it are rejected.
+	WebhookToken string
+	// Transport is the HTTP transport used for API calls. Optional,
+	// defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Retry configures how rate-limited and failed API calls are retried.
+	// Optional, defaults to the httpclient defaults.
+	Retry httpclient.RetryPolicy
 }
 
 // New creates a new OpsGenie notification service
@@ -33,9 +48,12 @@ func New(cfg Config) *Service {
 	return &Service{
 		apiKey: cfg.APIKey,
 		apiURL: cfg.APIURL,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+
+		webhookToken: cfg.WebhookToken,
+		client: httpclient.New(&http.Client{
+			Timeout:   30 * time.Second,
+			Transport: cfg.Transport,
+		}, cfg.Retry),
 	}
 }
 
@@ -67,273 +85,302 @@ func (s *Service) FetchAlert(ctx context.Context, alertID string) (*notification
 	}
 
 	var result struct {
-		Data struct {
-			ID            string    `json:"id"`
-			Message       string    `json:"message"`
-			Description   string    `json:"description"`
-			Status        string    `json:"status"`
-			Priority      string    `json:"priority"`
-			CreatedAt     time.Time `json:"createdAt"`
-			AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
-			ClosedAt      *time.Time `json:"closedAt,omitempty"`
-			Teams         []struct {
-				Name string `json:"name"`
-			} `json:"teams"`
-		} `json:"data"`
+		Data apiAlert `json:"data"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	teamName := "unknown"
-	if len(result.Data.Teams) > 0 {
-		teamName = result.Data.Teams[0].Name
-	}
+	return result.Data.alert(), nil
+}
 
-	return &notification.Alert{
-		ExternalID:     result.Data.ID,
-		Source:         "opsgenie",
-		TeamName:       teamName,
-		Title:          result.Data.Message,
-		Description:    result.Data.Description,
-		Severity:       result.Data.Priority,
-		TriggeredAt:    result.Data.CreatedAt,
-		AcknowledgedAt: result.Data.AcknowledgedAt,
-		ResolvedAt:     result.Data.ClosedAt,
-	}, nil
+// apiAlert is an alert as returned by the OpsGenie Alert API
+type apiAlert struct {
+	ID             string     `json:"id"`
+	Message        string     `json:"message"`
+	Description    string     `json:"description"`
+	Status         string     `json:"status"`
+	Priority       string     `json:"priority"`
+	Alias          string     `json:"alias"`
+	Entity         string     `json:"entity"`
+	Owner          string     `json:"owner"`
+	Actions        []string   `json:"actions"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	ClosedAt       *time.Time `json:"closedAt,omitempty"`
+	Teams          []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"teams"`
+	Responders []apiRecipient `json:"responders"`
+	VisibleTo  []apiRecipient `json:"visibleTo"`
 }
 
-// FetchRecentAlerts retrieves recent alerts from OpsGenie
-func (s *Service) FetchRecentAlerts(ctx context.Context, since time.Time) ([]*notification.Alert, error) {
-	// OpsGenie uses a query parameter for filtering by creation time
-	query := fmt.Sprintf("createdAt > %d", since.Unix()*1000)
-	url := fmt.Sprintf("%s/v2/alerts?query=%s&order=desc", s.apiURL, query)
+// apiRecipient is a team, user, escalation or schedule an alert is routed
+// or visible to
+type apiRecipient struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// label names the recipient, falling back to its ID when the API omits
+// the name
+func (r apiRecipient) label() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Username != "":
+		return r.Username
 	}
+	return r.ID
+}
 
-	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch alerts: %w", err)
+// labels names each of recipients
+func labels(recipients []apiRecipient) []string {
+	var names []string
+	for _, r := range recipients {
+		if name := r.label(); name != "" {
+			names = append(names, name)
+		}
 	}
-	defer func() { _ = resp.Body.Close() }()
+	return names
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("OpsGenie API error: %s (status: %d)", string(body), resp.StatusCode)
+// alert converts the alert to a notification alert, keeping its alias,
+// entity, responders, owner and priority as source metadata
+func (a *apiAlert) alert() *notification.Alert {
+	teamName := "unknown"
+	var teamNames []string
+	for _, t := range a.Teams {
+		teamNames = append(teamNames, t.Name)
+	}
+	if len(teamNames) > 0 {
+		teamName = teamNames[0]
 	}
 
-	var result struct {
-		Data []struct {
-			ID            string    `json:"id"`
-			Message       string    `json:"message"`
-			Description   string    `json:"description"`
-			Status        string    `json:"status"`
-			Priority      string    `json:"priority"`
-			CreatedAt     time.Time `json:"createdAt"`
-			AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
-			ClosedAt      *time.Time `json:"closedAt,omitempty"`
-			Teams         []struct {
-				Name string `json:"name"`
-			} `json:"teams"`
-		} `json:"data"`
+	metadata := map[string]any{}
+	for key, value := range map[string]string{
+		"alias":    a.Alias,
+		"entity":   a.Entity,
+		"priority": a.Priority,
+		"owner":    a.Owner,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	for key, values := range map[string][]string{
+		"responders": labels(a.Responders),
+		"visible_to": labels(a.VisibleTo),
+		"actions":    a.Actions,
+	} {
+		if len(values) > 0 {
+			metadata[key] = values
+		}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return &notification.Alert{
+		ExternalID:     a.ID,
+		Source:         "opsgenie",
+		TeamName:       teamName,
+		TeamNames:      teamNames,
+		Title:          a.Message,
+		Description:    a.Description,
+		Severity:       a.Priority,
+		IncidentKey:    a.Alias,
+		Service:        a.Entity,
+		TriggeredAt:    a.CreatedAt,
+		AcknowledgedAt: a.AcknowledgedAt,
+		ResolvedAt:     a.ClosedAt,
+		SourceMetadata: metadata,
 	}
+}
 
-	alerts := make([]*notification.Alert, 0, len(result.Data))
-	for _, alert := range result.Data {
-		teamName := "unknown"
-		if len(alert.Teams) > 0 {
-			teamName = alert.Teams[0].Name
+// FetchRecentAlerts retrieves every alert created since the given time from
+// OpsGenie, following the API's links from page to page
+func (s *Service) FetchRecentAlerts(ctx context.Context, since time.Time) ([]*notification.Alert, error) {
+	var alerts []*notification.Alert
+	path := alertsPath(notification.HistoricalFetchOptions{Since: since})
+	for path != "" {
+		page, next, err := s.fetchAlertPage(ctx, path)
+		if err != nil {
+			return nil, err
 		}
-
-		alerts = append(alerts, &notification.Alert{
-			ExternalID:     alert.ID,
-			Source:         "opsgenie",
-			TeamName:       teamName,
-			Title:          alert.Message,
-			Description:    alert.Description,
-			Severity:       alert.Priority,
-			TriggeredAt:    alert.CreatedAt,
-			AcknowledgedAt: alert.AcknowledgedAt,
-			ResolvedAt:     alert.ClosedAt,
-		})
+		alerts = append(alerts, page...)
+		path = next
 	}
 
 	return alerts, nil
 }
 
-// HistoricalFetchOptions holds options for fetching historical alerts
-type HistoricalFetchOptions struct {
-	Since   time.Time
-	Until   time.Time
-	TeamIDs []string
-	Limit   int
-	Offset  int
-}
-
-// Team represents a team from OpsGenie
-type Team struct {
-	ID   string
-	Name string
+// FetchHistoricalAlerts retrieves alerts from OpsGenie with advanced filtering and pagination.
+// Teams are matched against each alert's responders by OpsGenie itself, so
+// every page is full until the last. Pages are addressed by offset rather
+// than by the API's next link: the link only advances the offset by the
+// page size, and with the filter applied server-side and alerts sorted
+// oldest first, an offset keeps selecting the same alerts as new ones
+// arrive, so it can be saved and resumed from.
+func (s *Service) FetchHistoricalAlerts(ctx context.Context, opts notification.HistoricalFetchOptions) ([]*notification.Alert, bool, error) {
+	alerts, next, err := s.fetchAlertPage(ctx, alertsPath(opts))
+	if err != nil {
+		return nil, false, err
+	}
+	return alerts, next != "", nil
 }
 
-// FetchHistoricalAlerts retrieves alerts from OpsGenie with advanced filtering and pagination
-func (s *Service) FetchHistoricalAlerts(ctx context.Context, opts HistoricalFetchOptions) ([]*notification.Alert, bool, error) {
+// alertsPath returns the path listing the page of alerts opts selects,
+// oldest first
+func alertsPath(opts notification.HistoricalFetchOptions) string {
 	// OpsGenie uses createdAt for filtering
 	query := fmt.Sprintf("createdAt > %d", opts.Since.Unix()*1000)
-
 	if !opts.Until.IsZero() {
 		query += fmt.Sprintf(" AND createdAt < %d", opts.Until.Unix()*1000)
 	}
-
-	url := fmt.Sprintf("%s/v2/alerts?query=%s&order=desc", s.apiURL, query)
+	if len(opts.TeamIDs) > 0 {
+		teams := make([]string, len(opts.TeamIDs))
+		for i, id := range opts.TeamIDs {
+			teams[i] = fmt.Sprintf("responders: %q", id)
+		}
+		query += " AND (" + strings.Join(teams, " OR ") + ")"
+	}
 
 	limit := opts.Limit
 	if limit == 0 {
 		limit = 100 // Default limit
 	}
-	url += fmt.Sprintf("&limit=%d&offset=%d", limit, opts.Offset)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, false, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, false, fmt.Errorf("failed to fetch alerts: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, false, fmt.Errorf("OpsGenie API error: %s (status: %d)", string(body), resp.StatusCode)
-	}
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("sort", "createdAt")
+	params.Set("order", "asc")
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("offset", strconv.Itoa(opts.Offset))
+	return "/v2/alerts?" + params.Encode()
+}
 
+// fetchAlertPage retrieves the page of alerts at path, returning the path
+// of the next page, or "" if it is the last
+func (s *Service) fetchAlertPage(ctx context.Context, path string) ([]*notification.Alert, string, error) {
 	var result struct {
-		Data []struct {
-			ID            string    `json:"id"`
-			Message       string    `json:"message"`
-			Description   string    `json:"description"`
-			Status        string    `json:"status"`
-			Priority      string    `json:"priority"`
-			CreatedAt     time.Time `json:"createdAt"`
-			AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
-			ClosedAt      *time.Time `json:"closedAt,omitempty"`
-			Teams         []struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			} `json:"teams"`
-		} `json:"data"`
+		Data   []apiAlert `json:"data"`
 		Paging struct {
-			Next  string `json:"next"`
-			First string `json:"first"`
-			Last  string `json:"last"`
+			Next string `json:"next"`
 		} `json:"paging"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, false, fmt.Errorf("failed to decode response: %w", err)
+	if err := s.get(ctx, path, &result); err != nil {
+		return nil, "", fmt.Errorf("failed to fetch alerts: %w", err)
 	}
 
 	alerts := make([]*notification.Alert, 0, len(result.Data))
-	for _, alert := range result.Data {
-		// Apply team filter if specified
-		if len(opts.TeamIDs) > 0 {
-			found := false
-			for _, team := range alert.Teams {
-				for _, filterID := range opts.TeamIDs {
-					if team.ID == filterID {
-						found = true
-						break
-					}
-				}
-				if found {
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
+	for i := range result.Data {
+		alerts = append(alerts, result.Data[i].alert())
+	}
 
-		teamName := "unknown"
-		if len(alert.Teams) > 0 {
-			teamName = alert.Teams[0].Name
+	// The next link is absolute; only its query is kept, so the request
+	// goes to the configured API URL with the service's credentials
+	var next string
+	if result.Paging.Next != "" && len(result.Data) > 0 {
+		u, err := url.Parse(result.Paging.Next)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid next page link %q: %w", result.Paging.Next, err)
 		}
-
-		alerts = append(alerts, &notification.Alert{
-			ExternalID:     alert.ID,
-			Source:         "opsgenie",
-			TeamName:       teamName,
-			Title:          alert.Message,
-			Description:    alert.Description,
-			Severity:       alert.Priority,
-			TriggeredAt:    alert.CreatedAt,
-			AcknowledgedAt: alert.AcknowledgedAt,
-			ResolvedAt:     alert.ClosedAt,
-		})
+		next = "/v2/alerts?" + u.RawQuery
 	}
-
-	// Check if there are more results
-	hasMore := result.Paging.Next != ""
-
-	return alerts, hasMore, nil
+	return alerts, next, nil
 }
 
-// ListTeams retrieves all teams from OpsGenie
-func (s *Service) ListTeams(ctx context.Context) ([]Team, error) {
-	url := fmt.Sprintf("%s/v2/teams", s.apiURL)
+// logEntryTypes maps OpsGenie alert log types (compared case-insensitively)
+// to notification log entry types. Entries of other types, including the
+// generic "system" log, are not reported.
+var logEntryTypes = map[string]string{
+	"alertrecipient":  notification.LogEntryNotified,
+	"escalate":        notification.LogEntryEscalated,
+	"escalation":      notification.LogEntryEscalated,
+	"assignownership": notification.LogEntryReassigned,
+	"addresponder":    notification.LogEntryResponders,
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// FetchLogEntries retrieves the notification, escalation, ownership and
+// responder history of an alert from OpsGenie
+func (s *Service) FetchLogEntries(ctx context.Context, alertID string) ([]*notification.LogEntry, error) {
+	const pageSize = 100
+
+	var entries []*notification.LogEntry
+	offset := ""
+	for {
+		params := url.Values{}
+		params.Set("identifierType", "id")
+		params.Set("order", "asc")
+		params.Set("direction", "next")
+		params.Set("limit", strconv.Itoa(pageSize))
+		if offset != "" {
+			params.Set("offset", offset)
+		}
+		endpoint := fmt.Sprintf("%s/v2/alerts/%s/logs?%s", s.apiURL, alertID, params.Encode())
 
-	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch teams: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
+		req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("OpsGenie API error: %s (status: %d)", string(body), resp.StatusCode)
-	}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch alert logs: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("OpsGenie API error: %s (status: %d)", string(body), resp.StatusCode)
+		}
 
-	var result struct {
-		Data []struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-		} `json:"data"`
-	}
+		var result struct {
+			Data []struct {
+				Log       string    `json:"log"`
+				Type      string    `json:"type"`
+				Owner     string    `json:"owner"`
+				CreatedAt time.Time `json:"createdAt"`
+				Offset    string    `json:"offset"`
+			} `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+		for _, e := range result.Data {
+			entryType, ok := logEntryTypes[strings.ToLower(e.Type)]
+			if !ok {
+				continue
+			}
+			entry := &notification.LogEntry{
+				ExternalID: e.Offset,
+				Type:       entryType,
+				Summary:    e.Log,
+				OccurredAt: e.CreatedAt,
+			}
+			// The owner of a recipient log is the user notified; for other
+			// types it is the user who acted.
+			if entryType == notification.LogEntryNotified {
+				entry.Target = e.Owner
+			} else {
+				entry.Actor = e.Owner
+			}
+			entries = append(entries, entry)
+		}
 
-	teams := make([]Team, len(result.Data))
-	for i, team := range result.Data {
-		teams[i] = Team{
-			ID:   team.ID,
-			Name: team.Name,
+		if len(result.Data) < pageSize {
+			break
 		}
+		offset = result.Data[len(result.Data)-1].Offset
 	}
 
-	return teams, nil
+	return entries, nil
 }
 
 // WebhookHandler returns an HTTP handler for OpsGenie webhooks
@@ -345,3 +392,76 @@ func (s *Service) WebhookHandler() interface{} {
 		_ = json.NewEncoder(w).Encode(map[string]string{"status": "received"})
 	})
 }
+
+// VerifyWebhook implements notification.WebhookParser by checking the
+// shared secret added to the outgoing webhook's headers
+func (s *Service) VerifyWebhook(header http.Header, _ []byte) bool {
+	return notification.VerifyWebhookToken(header, s.webhookToken)
+}
+
+// ParseWebhook converts an OpsGenie outgoing webhook delivery into alerts.
+// The Create, Acknowledge and Close actions set the alert's lifecycle
+// timestamps. Escalate, AssignOwnership and AddRecipient carry no state
+// change but are returned so the alert's log can be re-synced. Other actions
+// are ignored.
+func (s *Service) ParseWebhook(payload []byte, receivedAt time.Time) ([]*notification.Alert, error) {
+	var delivery struct {
+		Action string `json:"action"`
+		Alert  struct {
+			AlertID     string `json:"alertId"`
+			Message     string `json:"message"`
+			Description string `json:"description"`
+			Priority    string `json:"priority"`
+			CreatedAt   int64  `json:"createdAt"` // Unix milliseconds
+			Responders  []struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"responders"`
+		} `json:"alert"`
+	}
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return nil, fmt.Errorf("failed to decode OpsGenie webhook: %w", err)
+	}
+
+	if delivery.Alert.AlertID == "" {
+		return nil, nil
+	}
+
+	teamName := "unknown"
+	var teamNames []string
+	for _, r := range delivery.Alert.Responders {
+		if r.Type == "team" && r.Name != "" {
+			teamNames = append(teamNames, r.Name)
+		}
+	}
+	if len(teamNames) > 0 {
+		teamName = teamNames[0]
+	}
+
+	alert := &notification.Alert{
+		ExternalID:  delivery.Alert.AlertID,
+		Source:      "opsgenie",
+		TeamName:    teamName,
+		TeamNames:   teamNames,
+		Title:       delivery.Alert.Message,
+		Description: delivery.Alert.Description,
+		Severity:    delivery.Alert.Priority,
+		TriggeredAt: receivedAt,
+	}
+	if delivery.Alert.CreatedAt > 0 {
+		alert.TriggeredAt = time.UnixMilli(delivery.Alert.CreatedAt)
+	}
+
+	switch delivery.Action {
+	case "Create":
+	case "Acknowledge":
+		alert.AcknowledgedAt = &receivedAt
+	case "Close":
+		alert.ResolvedAt = &receivedAt
+	case "Escalate", "AssignOwnership", "AddRecipient":
+	default:
+		return nil, nil
+	}
+
+	return []*notification.Alert{alert}, nil
+}
@@ -0,0 +1,216 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+func TestFetchAlertsPaging(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		// The next link points at OpsGenie itself, so following it must keep
+		// to the configured API URL
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			fmt.Fprint(w, `{"data":[{"id":"a1","createdAt":"2024-07-01T09:00:00Z"},{"id":"a2","createdAt":"2024-07-01T09:05:00Z"}],
+				"paging":{"next":"https://api.opsgenie.com/v2/alerts?query=q&offset=2&limit=2"}}`)
+		case "2":
+			fmt.Fprint(w, `{"data":[{"id":"a3","createdAt":"2024-07-01T09:10:00Z"}],"paging":{}}`)
+		default:
+			http.Error(w, "unexpected offset", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+	s := New(Config{APIKey: "key", APIURL: srv.URL})
+	ctx := context.Background()
+
+	alerts, more, err := s.FetchHistoricalAlerts(ctx, notification.HistoricalFetchOptions{
+		Since:   time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		TeamIDs: []string{"team-1", "team-2"},
+		Limit:   2,
+	})
+	if err != nil || len(alerts) != 2 || !more {
+		t.Fatalf("FetchHistoricalAlerts = %d alerts, more %v, %v, want 2 and more", len(alerts), more, err)
+	}
+	if want := `AND (responders: "team-1" OR responders: "team-2")`; !strings.HasSuffix(queries[0], want) {
+		t.Errorf("query = %q, want teams filtered by OpsGenie with %q", queries[0], want)
+	}
+
+	// The next page by offset is the page the next link points at
+	alerts, more, err = s.FetchHistoricalAlerts(ctx, notification.HistoricalFetchOptions{
+		Since:   time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		TeamIDs: []string{"team-1", "team-2"},
+		Limit:   2,
+		Offset:  2,
+	})
+	if err != nil || len(alerts) != 1 || alerts[0].ExternalID != "a3" || more {
+		t.Fatalf("FetchHistoricalAlerts at offset 2 = %d alerts, more %v, %v, want a3 and no more", len(alerts), more, err)
+	}
+	if queries[1] != queries[0] {
+		t.Errorf("query at offset 2 = %q, want the first page's %q", queries[1], queries[0])
+	}
+
+	alerts, err = s.FetchRecentAlerts(ctx, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("FetchRecentAlerts: %v", err)
+	}
+	var ids []string
+	for _, a := range alerts {
+		ids = append(ids, a.ExternalID)
+	}
+	if strings.Join(ids, ",") != "a1,a2,a3" {
+		t.Errorf("FetchRecentAlerts = %v, want every page", ids)
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	svc := New(Config{APIKey: "key", WebhookToken: "token"})
+	header := http.Header{}
+	if svc.VerifyWebhook(header, nil) {
+		t.Error("unsigned delivery verified")
+	}
+	header.Set(notification.WebhookTokenHeader, "wrong")
+	if svc.VerifyWebhook(header, nil) {
+		t.Error("delivery with the wrong token verified")
+	}
+	header.Set(notification.WebhookTokenHeader, "token")
+	if !svc.VerifyWebhook(header, nil) {
+		t.Error("delivery with the configured token did not verify")
+	}
+	if New(Config{APIKey: "key"}).VerifyWebhook(header, nil) {
+		t.Error("delivery verified with no token configured")
+	}
+}
+
+// pagedLogServer serves count entries of an alert's notes or logs in pages
+// of 100, continuing after the offset of the last entry a page returned.
+// entry renders the i'th entry, whose offset is its index.
+func pagedLogServer(t *testing.T, path string, count int, entry func(i int) string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.Error(w, "unexpected path", http.StatusNotFound)
+			return
+		}
+		start := 0
+		if offset := r.URL.Query().Get("offset"); offset != "" {
+			if _, err := fmt.Sscan(offset, &start); err != nil {
+				http.Error(w, "bad offset", http.StatusBadRequest)
+				return
+			}
+			start++
+		}
+		var data []string
+		for i := start; i < count && i < start+100; i++ {
+			data = append(data, entry(i))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+}
+
+func TestFetchNotesPaging(t *testing.T) {
+	srv := pagedLogServer(t, "/v2/alerts/a1/notes", 101, func(i int) string {
+		return fmt.Sprintf(`{"note":"note %d","owner":"alice@example.com","createdAt":"2024-07-01T09:00:00Z","offset":"%d"}`, i, i)
+	})
+	defer srv.Close()
+
+	notes, err := New(Config{APIKey: "key", APIURL: srv.URL}).FetchNotes(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("FetchNotes: %v", err)
+	}
+	if len(notes) != 101 {
+		t.Fatalf("FetchNotes = %d notes, want 101 across two pages", len(notes))
+	}
+	last := notes[100]
+	if last.ExternalID != "100" || last.Content != "note 100" || last.Author != "alice@example.com" {
+		t.Errorf("last note = %+v, want note 100 by alice@example.com", last)
+	}
+}
+
+func TestFetchLogEntries(t *testing.T) {
+	// The first page fills up with system logs, so the typed entries only
+	// arrive on the second
+	typed := []string{
+		`{"log":"Notified bob","type":"AlertRecipient","owner":"bob@example.com"}`,
+		`{"log":"Escalated to ops","type":"Escalation","owner":"System"}`,
+		`{"log":"Ownership taken","type":"assignOwnership","owner":"carol@example.com"}`,
+		`{"log":"Added responder","type":"AddResponder","owner":"dave@example.com"}`,
+	}
+	srv := pagedLogServer(t, "/v2/alerts/a1/logs", 100+len(typed), func(i int) string {
+		entry := `{"log":"Alert created","type":"system","owner":"System"}`
+		if i >= 100 {
+			entry = typed[i-100]
+		}
+		return fmt.Sprintf(`%s,"createdAt":"2024-07-01T09:00:00Z","offset":"%d"}`, strings.TrimSuffix(entry, "}"), i)
+	})
+	defer srv.Close()
+
+	entries, err := New(Config{APIKey: "key", APIURL: srv.URL}).FetchLogEntries(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("FetchLogEntries: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%s:%s:%s>%s", e.ExternalID, e.Type, e.Actor, e.Target))
+	}
+	want := []string{
+		"100:" + notification.LogEntryNotified + ":>bob@example.com",
+		"101:" + notification.LogEntryEscalated + ":System>",
+		"102:" + notification.LogEntryReassigned + ":carol@example.com>",
+		"103:" + notification.LogEntryResponders + ":dave@example.com>",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("FetchLogEntries = %v, want %v", got, want)
+	}
+}
+
+func TestFetchAlertSourceMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"a1","message":"Disk full","priority":"P2",
+			"alias":"disk-db1","entity":"db1","owner":"carol@example.com",
+			"actions":["Restart"],"createdAt":"2024-07-01T09:00:00Z",
+			"teams":[{"id":"t1","name":"Storage"}],
+			"responders":[{"type":"team","id":"t1","name":"Storage"},{"type":"user","id":"u1","username":"bob@example.com"},{"type":"schedule","id":"s1"}],
+			"visibleTo":[{"type":"team","id":"t2","name":"Ops"}]}}`)
+	}))
+	defer srv.Close()
+
+	alert, err := New(Config{APIKey: "key", APIURL: srv.URL}).FetchAlert(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("FetchAlert: %v", err)
+	}
+	want := map[string]string{
+		"alias":      "disk-db1",
+		"entity":     "db1",
+		"priority":   "P2",
+		"owner":      "carol@example.com",
+		"responders": "[Storage bob@example.com s1]",
+		"visible_to": "[Ops]",
+		"actions":    "[Restart]",
+	}
+	if len(alert.SourceMetadata) != len(want) {
+		t.Errorf("SourceMetadata = %v, want keys %v", alert.SourceMetadata, want)
+	}
+	for key, value := range want {
+		if got := fmt.Sprint(alert.SourceMetadata[key]); got != value {
+			t.Errorf("SourceMetadata[%q] = %s, want %s", key, got, value)
+		}
+	}
+	if alert.Severity != "P2" || alert.TeamName != "Storage" {
+		t.Errorf("alert = severity %q team %q, want P2 and Storage", alert.Severity, alert.TeamName)
+	}
+
+	// Empty fields are left out rather than stored as empty values
+	empty := (&apiAlert{ID: "a2"}).alert()
+	if len(empty.SourceMetadata) != 0 {
+		t.Errorf("SourceMetadata = %v, want none for an alert without metadata", empty.SourceMetadata)
+	}
+}
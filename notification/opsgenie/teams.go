@@ -0,0 +1,50 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/conall/outalator/notification"
+)
+
+// FetchTeams retrieves every OpsGenie team along with the usernames of its
+// members, which OpsGenie requires to be email addresses
+func (s *Service) FetchTeams(ctx context.Context) ([]*notification.Team, error) {
+	var list struct {
+		Data []struct {
+			ID          string `json:"id"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"data"`
+	}
+	if err := s.get(ctx, "/v2/teams", &list); err != nil {
+		return nil, fmt.Errorf("failed to fetch teams: %w", err)
+	}
+
+	teams := make([]*notification.Team, 0, len(list.Data))
+	for _, t := range list.Data {
+		// The team list leaves out members, so each team is fetched too
+		var detail struct {
+			Data struct {
+				Members []struct {
+					User struct {
+						Username string `json:"username"`
+					} `json:"user"`
+				} `json:"members"`
+			} `json:"data"`
+		}
+		if err := s.get(ctx, fmt.Sprintf("/v2/teams/%s?identifierType=id", url.PathEscape(t.ID)), &detail); err != nil {
+			return nil, fmt.Errorf("failed to fetch team %s: %w", t.ID, err)
+		}
+
+		team := &notification.Team{ExternalID: t.ID, Name: t.Name, Description: t.Description}
+		for _, m := range detail.Data.Members {
+			if m.User.Username != "" {
+				team.Members = append(team.Members, m.User.Username)
+			}
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
@@ -0,0 +1,38 @@
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/conall/outalator/notification"
+)
+
+// CheckCredentials checks the API key by listing the account's abilities,
+// which any valid key can read
+func (s *Service) CheckCredentials(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.apiURL+"/abilities", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to check credentials: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("PagerDuty API status %d: %w", resp.StatusCode, notification.ErrInvalidCredentials)
+	default:
+		return fmt.Errorf("PagerDuty API error (status: %d)", resp.StatusCode)
+	}
+}
@@ -0,0 +1,173 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// CreateIncident opens a PagerDuty incident on the request's service, which
+// pages the service's escalation policy. PagerDuty records incidents as
+// created by a user, so either the request's requester or the configured
+// From address must belong to a PagerDuty user. Critical and high
+// severities open high-urgency incidents; anything else opens low-urgency
+// ones. The dedup key is sent as the incident key, so repeating a request
+// while its incident is open is rejected by PagerDuty rather than paging
+// twice.
+func (s *Service) CreateIncident(ctx context.Context, r notification.IncidentRequest) (*notification.Alert, error) {
+	if r.Service == "" {
+		return nil, fmt.Errorf("PagerDuty pages a service; a service ID is required: %w", domain.ErrInvalidInput)
+	}
+	from, err := s.fromAddress(r.Requester)
+	if err != nil {
+		return nil, err
+	}
+
+	urgency := "low"
+	if r.Severity == "critical" || r.Severity == "high" {
+		urgency = "high"
+	}
+	var payload struct {
+		Incident struct {
+			Type    string `json:"type"`
+			Title   string `json:"title"`
+			Urgency string `json:"urgency"`
+			Service struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			} `json:"service"`
+			IncidentKey string `json:"incident_key,omitempty"`
+			Body        *struct {
+				Type    string `json:"type"`
+				Details string `json:"details"`
+			} `json:"body,omitempty"`
+		} `json:"incident"`
+	}
+	payload.Incident.Type = "incident"
+	payload.Incident.Title = r.Title
+	payload.Incident.Urgency = urgency
+	payload.Incident.Service.ID = r.Service
+	payload.Incident.Service.Type = "service_reference"
+	payload.Incident.IncidentKey = r.DedupKey
+	if r.Description != "" {
+		payload.Incident.Body = &struct {
+			Type    string `json:"type"`
+			Details string `json:"details"`
+		}{Type: "incident_body", Details: r.Description}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode incident: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.apiURL+"/incidents", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("From", from)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create incident: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("PagerDuty API error: %s (status: %d)", string(respBody), resp.StatusCode)
+	}
+
+	var result struct {
+		Incident struct {
+			ID string `json:"id"`
+		} `json:"incident"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// The created incident is fetched again so it is converted the same way
+	// as incidents PagerDuty reports by webhook or sync
+	return s.FetchAlert(ctx, result.Incident.ID)
+}
+
+// AcknowledgeAlert acknowledges a PagerDuty incident as requester, or as the
+// configured From user when the requester is unknown
+func (s *Service) AcknowledgeAlert(ctx context.Context, alertID, requester string) error {
+	return s.setIncidentStatus(ctx, alertID, "acknowledged", requester)
+}
+
+// ResolveAlert resolves a PagerDuty incident as requester, or as the
+// configured From user when the requester is unknown
+func (s *Service) ResolveAlert(ctx context.Context, alertID, requester string) error {
+	return s.setIncidentStatus(ctx, alertID, "resolved", requester)
+}
+
+// setIncidentStatus moves an incident to status. PagerDuty accepts the
+// change for incidents already in that status.
+func (s *Service) setIncidentStatus(ctx context.Context, incidentID, status, requester string) error {
+	from, err := s.fromAddress(requester)
+	if err != nil {
+		return err
+	}
+
+	var payload struct {
+		Incident struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"incident"`
+	}
+	payload.Incident.Type = "incident_reference"
+	payload.Incident.Status = status
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode incident: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", s.apiURL+"/incidents/"+url.PathEscape(incidentID), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("From", from)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update incident: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("PagerDuty incident %s: %w", incidentID, domain.ErrNotFound)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("PagerDuty API error: %s (status: %d)", string(respBody), resp.StatusCode)
+}
+
+// fromAddress returns the email address of the PagerDuty user a change is
+// made as: the requester, falling back to the configured From address.
+// PagerDuty rejects changes to incidents that name no user.
+func (s *Service) fromAddress(requester string) (string, error) {
+	if requester != "" {
+		return requester, nil
+	}
+	if s.from != "" {
+		return s.from, nil
+	}
+	return "", fmt.Errorf("PagerDuty needs the email address of the user making the change; configure pagerduty.from: %w", domain.ErrInvalidInput)
+}
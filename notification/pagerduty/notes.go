@@ -0,0 +1,66 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// FetchNotes retrieves the notes responders left on an incident in
+// PagerDuty
+func (s *Service) FetchNotes(ctx context.Context, alertID string) ([]*notification.AlertNote, error) {
+	url := fmt.Sprintf("%s/incidents/%s/notes", s.apiURL, alertID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notes: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("PagerDuty API error: %s (status: %d)", string(body), resp.StatusCode)
+	}
+
+	var result struct {
+		Notes []struct {
+			ID        string    `json:"id"`
+			Content   string    `json:"content"`
+			CreatedAt time.Time `json:"created_at"`
+			User      struct {
+				Summary string `json:"summary"`
+			} `json:"user"`
+		} `json:"notes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	notes := make([]*notification.AlertNote, 0, len(result.Notes))
+	for _, n := range result.Notes {
+		notes = append(notes, &notification.AlertNote{
+			ExternalID: n.ID,
+			Author:     n.User.Summary,
+			Content:    n.Content,
+			CreatedAt:  n.CreatedAt,
+		})
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+	})
+	return notes, nil
+}
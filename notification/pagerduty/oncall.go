@@ -0,0 +1,85 @@
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// FetchOnCall retrieves who is currently on call for a PagerDuty schedule,
+// or for every schedule when scheduleID is empty. On-calls that come from an
+// escalation policy rule targeting a user directly have no schedule and are
+// left out.
+func (s *Service) FetchOnCall(ctx context.Context, scheduleID string) ([]*notification.OnCall, error) {
+	const pageSize = 100
+
+	// A user on call for a schedule that several escalation policies use is
+	// listed once per policy
+	seen := make(map[string]bool)
+	var oncalls []*notification.OnCall
+	for offset := 0; ; offset += pageSize {
+		var result struct {
+			Oncalls []struct {
+				User struct {
+					Summary string `json:"summary"`
+					Name    string `json:"name"`
+					Email   string `json:"email"`
+				} `json:"user"`
+				Schedule *struct {
+					ID      string `json:"id"`
+					Summary string `json:"summary"`
+				} `json:"schedule"`
+				Start *time.Time `json:"start"`
+				End   *time.Time `json:"end"`
+			} `json:"oncalls"`
+			More bool `json:"more"`
+		}
+		params := url.Values{}
+		params.Set("limit", strconv.Itoa(pageSize))
+		params.Set("offset", strconv.Itoa(offset))
+		params.Add("include[]", "users")
+		if scheduleID != "" {
+			params.Add("schedule_ids[]", scheduleID)
+		}
+		if err := s.get(ctx, "/oncalls?"+params.Encode(), &result); err != nil {
+			return nil, fmt.Errorf("failed to fetch on-calls: %w", err)
+		}
+
+		for _, o := range result.Oncalls {
+			if o.Schedule == nil {
+				continue
+			}
+			key := o.Schedule.ID + "\x00" + o.User.Email
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			oncall := &notification.OnCall{
+				ScheduleID:   o.Schedule.ID,
+				ScheduleName: o.Schedule.Summary,
+				Name:         o.User.Name,
+				Email:        o.User.Email,
+			}
+			if oncall.Name == "" {
+				oncall.Name = o.User.Summary
+			}
+			if o.Start != nil {
+				oncall.Start = *o.Start
+			}
+			if o.End != nil {
+				oncall.End = *o.End
+			}
+			oncalls = append(oncalls, oncall)
+		}
+
+		if !result.More {
+			break
+		}
+	}
+	return oncalls, nil
+}
@@ -2,26 +2,46 @@ package pagerduty
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/conall/outalator/internal/httpclient"
 	"github.com/conall/outalator/notification"
 )
 
 // Service implements the notification.Service interface for PagerDuty
 type Service struct {
-	apiKey   string
-	apiURL   string
-	client   *http.Client
+	apiKey        string
+	apiURL        string
+	from          string
+	webhookSecret string
+	client        *httpclient.Client
 }
 
 // Config holds PagerDuty configuration
 type Config struct {
 	APIKey string
 	APIURL string // Optional, defaults to PagerDuty API
+	// From is the email address of a PagerDuty user to create incidents as
+	// when the request does not name one. Optional.
+	From string
+	// WebhookSecret is the signing secret of the V3 webhook subscription.
+	// Deliveries without a valid X-PagerDuty-Signature for it are rejected.
+	WebhookSecret string
+	// Transport is the HTTP transport used for API calls. Optional,
+	// defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Retry configures how rate-limited and failed API calls are retried.
+	// Optional, defaults to the httpclient defaults.
+	Retry httpclient.RetryPolicy
 }
 
 // New creates a new PagerDuty notification service
@@ -33,9 +53,13 @@ func New(cfg Config) *Service {
 	return &Service{
 		apiKey: cfg.APIKey,
 		apiURL: cfg.APIURL,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		from:   cfg.From,
+
+		webhookSecret: cfg.WebhookSecret,
+		client: httpclient.New(&http.Client{
+			Timeout:   30 * time.Second,
+			Transport: cfg.Transport,
+		}, cfg.Retry),
 	}
 }
 
@@ -68,127 +92,126 @@ func (s *Service) FetchAlert(ctx context.Context, alertID string) (*notification
 	}
 
 	var result struct {
-		Incident struct {
-			ID             string    `json:"id"`
-			Title          string    `json:"title"`
-			Description    string    `json:"description"`
-			Status         string    `json:"status"`
-			Urgency        string    `json:"urgency"`
-			CreatedAt      time.Time `json:"created_at"`
-			AcknowledgedAt *time.Time `json:"acknowledged_at"`
-			ResolvedAt     *time.Time `json:"resolved_at"`
-			Service        struct {
-				Summary string `json:"summary"`
-			} `json:"service"`
-			Teams []struct {
-				Summary string `json:"summary"`
-			} `json:"teams"`
-		} `json:"incident"`
+		Incident apiIncident `json:"incident"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	teamName := "unknown"
-	if len(result.Incident.Teams) > 0 {
-		teamName = result.Incident.Teams[0].Summary
-	}
-
-	return &notification.Alert{
-		ExternalID:     result.Incident.ID,
-		Source:         "pagerduty",
-		TeamName:       teamName,
-		Title:          result.Incident.Title,
-		Description:    result.Incident.Description,
-		Severity:       result.Incident.Urgency,
-		TriggeredAt:    result.Incident.CreatedAt,
-		AcknowledgedAt: result.Incident.AcknowledgedAt,
-		ResolvedAt:     result.Incident.ResolvedAt,
-	}, nil
+	return result.Incident.alert(), nil
 }
 
-// FetchRecentAlerts retrieves recent alerts from PagerDuty
-func (s *Service) FetchRecentAlerts(ctx context.Context, since time.Time) ([]*notification.Alert, error) {
-	url := fmt.Sprintf("%s/incidents?since=%s", s.apiURL, since.Format(time.RFC3339))
+// apiIncident is an incident as returned by the PagerDuty REST API
+type apiIncident struct {
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Status         string     `json:"status"`
+	Urgency        string     `json:"urgency"`
+	IncidentKey    string     `json:"incident_key"`
+	HTMLURL        string     `json:"html_url"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	Service        struct {
+		ID      string `json:"id"`
+		Summary string `json:"summary"`
+	} `json:"service"`
+	EscalationPolicy struct {
+		Summary string `json:"summary"`
+	} `json:"escalation_policy"`
+	Assignments []struct {
+		Assignee struct {
+			Summary string `json:"summary"`
+		} `json:"assignee"`
+	} `json:"assignments"`
+	Teams []struct {
+		ID      string `json:"id"`
+		Summary string `json:"summary"`
+	} `json:"teams"`
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// alert converts the incident to a notification alert, keeping the
+// incident's service, escalation policy, assignees and link as source
+// metadata
+func (i *apiIncident) alert() *notification.Alert {
+	teamName := "unknown"
+	var teamNames []string
+	for _, t := range i.Teams {
+		teamNames = append(teamNames, t.Summary)
 	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
-	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch alerts: %w", err)
+	if len(teamNames) > 0 {
+		teamName = teamNames[0]
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("PagerDuty API error: %s (status: %d)", string(body), resp.StatusCode)
+	metadata := map[string]any{}
+	for key, value := range map[string]string{
+		"incident_key":      i.IncidentKey,
+		"service_id":        i.Service.ID,
+		"service_name":      i.Service.Summary,
+		"escalation_policy": i.EscalationPolicy.Summary,
+		"urgency":           i.Urgency,
+		"html_url":          i.HTMLURL,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
 	}
-
-	var result struct {
-		Incidents []struct {
-			ID             string    `json:"id"`
-			Title          string    `json:"title"`
-			Description    string    `json:"description"`
-			Status         string    `json:"status"`
-			Urgency        string    `json:"urgency"`
-			CreatedAt      time.Time `json:"created_at"`
-			AcknowledgedAt *time.Time `json:"acknowledged_at"`
-			ResolvedAt     *time.Time `json:"resolved_at"`
-			Service        struct {
-				Summary string `json:"summary"`
-			} `json:"service"`
-			Teams []struct {
-				Summary string `json:"summary"`
-			} `json:"teams"`
-		} `json:"incidents"`
+	var assignees []string
+	for _, a := range i.Assignments {
+		if a.Assignee.Summary != "" {
+			assignees = append(assignees, a.Assignee.Summary)
+		}
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if len(assignees) > 0 {
+		metadata["assignees"] = assignees
 	}
 
-	alerts := make([]*notification.Alert, 0, len(result.Incidents))
-	for _, incident := range result.Incidents {
-		teamName := "unknown"
-		if len(incident.Teams) > 0 {
-			teamName = incident.Teams[0].Summary
-		}
+	return &notification.Alert{
+		ExternalID:     i.ID,
+		Source:         "pagerduty",
+		TeamName:       teamName,
+		TeamNames:      teamNames,
+		Title:          i.Title,
+		Description:    i.Description,
+		Severity:       i.Urgency,
+		IncidentKey:    i.IncidentKey,
+		Service:        i.Service.Summary,
+		TriggeredAt:    i.CreatedAt,
+		AcknowledgedAt: i.AcknowledgedAt,
+		ResolvedAt:     i.ResolvedAt,
+		SourceMetadata: metadata,
+	}
+}
 
-		alerts = append(alerts, &notification.Alert{
-			ExternalID:     incident.ID,
-			Source:         "pagerduty",
-			TeamName:       teamName,
-			Title:          incident.Title,
-			Description:    incident.Description,
-			Severity:       incident.Urgency,
-			TriggeredAt:    incident.CreatedAt,
-			AcknowledgedAt: incident.AcknowledgedAt,
-			ResolvedAt:     incident.ResolvedAt,
+// FetchRecentAlerts retrieves every alert created since the given time from
+// PagerDuty, paging through the results
+func (s *Service) FetchRecentAlerts(ctx context.Context, since time.Time) ([]*notification.Alert, error) {
+	const pageSize = 100
+
+	var alerts []*notification.Alert
+	for offset := 0; ; offset += pageSize {
+		page, more, err := s.FetchHistoricalAlerts(ctx, notification.HistoricalFetchOptions{
+			Since:  since,
+			Limit:  pageSize,
+			Offset: offset,
 		})
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, page...)
+		if !more || len(page) == 0 {
+			break
+		}
 	}
 
 	return alerts, nil
 }
 
-// HistoricalFetchOptions holds options for fetching historical incidents
-type HistoricalFetchOptions struct {
-	Since     time.Time
-	Until     time.Time
-	TeamIDs   []string
-	Limit     int
-	Offset    int
-}
-
-// FetchHistoricalIncidents retrieves incidents from PagerDuty with advanced filtering and pagination
-func (s *Service) FetchHistoricalIncidents(ctx context.Context, opts HistoricalFetchOptions) ([]*notification.Alert, bool, error) {
-	url := fmt.Sprintf("%s/incidents?since=%s", s.apiURL, opts.Since.Format(time.RFC3339))
+// FetchHistoricalAlerts retrieves incidents from PagerDuty with advanced filtering and pagination
+func (s *Service) FetchHistoricalAlerts(ctx context.Context, opts notification.HistoricalFetchOptions) ([]*notification.Alert, bool, error) {
+	url := fmt.Sprintf("%s/incidents?since=%s&sort_by=created_at:asc", s.apiURL, opts.Since.Format(time.RFC3339))
 
 	if !opts.Until.IsZero() {
 		url += fmt.Sprintf("&until=%s", opts.Until.Format(time.RFC3339))
@@ -226,26 +249,10 @@ func (s *Service) FetchHistoricalIncidents(ctx context.Context, opts HistoricalF
 	}
 
 	var result struct {
-		Incidents []struct {
-			ID             string    `json:"id"`
-			Title          string    `json:"title"`
-			Description    string    `json:"description"`
-			Status         string    `json:"status"`
-			Urgency        string    `json:"urgency"`
-			CreatedAt      time.Time `json:"created_at"`
-			AcknowledgedAt *time.Time `json:"acknowledged_at"`
-			ResolvedAt     *time.Time `json:"resolved_at"`
-			Service        struct {
-				Summary string `json:"summary"`
-			} `json:"service"`
-			Teams []struct {
-				ID      string `json:"id"`
-				Summary string `json:"summary"`
-			} `json:"teams"`
-		} `json:"incidents"`
-		More   bool `json:"more"`
-		Limit  int  `json:"limit"`
-		Offset int  `json:"offset"`
+		Incidents []apiIncident `json:"incidents"`
+		More      bool          `json:"more"`
+		Limit     int           `json:"limit"`
+		Offset    int           `json:"offset"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -253,77 +260,107 @@ func (s *Service) FetchHistoricalIncidents(ctx context.Context, opts HistoricalF
 	}
 
 	alerts := make([]*notification.Alert, 0, len(result.Incidents))
-	for _, incident := range result.Incidents {
-		teamName := "unknown"
-		if len(incident.Teams) > 0 {
-			teamName = incident.Teams[0].Summary
-		}
-
-		alerts = append(alerts, &notification.Alert{
-			ExternalID:     incident.ID,
-			Source:         "pagerduty",
-			TeamName:       teamName,
-			Title:          incident.Title,
-			Description:    incident.Description,
-			Severity:       incident.Urgency,
-			TriggeredAt:    incident.CreatedAt,
-			AcknowledgedAt: incident.AcknowledgedAt,
-			ResolvedAt:     incident.ResolvedAt,
-		})
+	for i := range result.Incidents {
+		alerts = append(alerts, result.Incidents[i].alert())
 	}
 
 	return alerts, result.More, nil
 }
 
-// ListTeams retrieves all teams from PagerDuty
-func (s *Service) ListTeams(ctx context.Context) ([]Team, error) {
-	url := fmt.Sprintf("%s/teams", s.apiURL)
+// logEntryTypes maps PagerDuty log entry types to notification log entry
+// types. Entries of other types are not reported.
+var logEntryTypes = map[string]string{
+	"notify_log_entry":            notification.LogEntryNotified,
+	"escalate_log_entry":          notification.LogEntryEscalated,
+	"assign_log_entry":            notification.LogEntryReassigned,
+	"responder_request_log_entry": notification.LogEntryResponders,
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// FetchLogEntries retrieves the notification, escalation, assignment and
+// responder request history of an incident from PagerDuty
+func (s *Service) FetchLogEntries(ctx context.Context, alertID string) ([]*notification.LogEntry, error) {
+	const pageSize = 100
 
-	req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
-	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	var entries []*notification.LogEntry
+	for offset := 0; ; offset += pageSize {
+		url := fmt.Sprintf("%s/incidents/%s/log_entries?limit=%d&offset=%d", s.apiURL, alertID, pageSize, offset)
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch teams: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("PagerDuty API error: %s (status: %d)", string(body), resp.StatusCode)
-	}
+		req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
+		req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
 
-	var result struct {
-		Teams []struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-		} `json:"teams"`
-	}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch log entries: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("PagerDuty API error: %s (status: %d)", string(body), resp.StatusCode)
+		}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+		var result struct {
+			LogEntries []struct {
+				ID        string    `json:"id"`
+				Type      string    `json:"type"`
+				Summary   string    `json:"summary"`
+				CreatedAt time.Time `json:"created_at"`
+				Agent     struct {
+					Summary string `json:"summary"`
+				} `json:"agent"`
+				User struct {
+					Summary string `json:"summary"`
+				} `json:"user"`
+				Assignees []struct {
+					Summary string `json:"summary"`
+				} `json:"assignees"`
+			} `json:"log_entries"`
+			More bool `json:"more"`
+		}
 
-	teams := make([]Team, len(result.Teams))
-	for i, team := range result.Teams {
-		teams[i] = Team{
-			ID:   team.ID,
-			Name: team.Name,
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
 		}
-	}
 
-	return teams, nil
-}
+		for _, e := range result.LogEntries {
+			entryType, ok := logEntryTypes[e.Type]
+			if !ok {
+				continue
+			}
+			target := e.User.Summary
+			if len(e.Assignees) > 0 {
+				names := make([]string, len(e.Assignees))
+				for i, a := range e.Assignees {
+					names[i] = a.Summary
+				}
+				target = strings.Join(names, ", ")
+			}
+			entries = append(entries, &notification.LogEntry{
+				ExternalID: e.ID,
+				Type:       entryType,
+				Summary:    e.Summary,
+				Actor:      e.Agent.Summary,
+				Target:     target,
+				OccurredAt: e.CreatedAt,
+			})
+		}
+
+		if !result.More {
+			break
+		}
+	}
 
-// Team represents a team from PagerDuty or OpsGenie
-type Team struct {
-	ID   string
-	Name string
+	// PagerDuty lists entries newest first
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].OccurredAt.Before(entries[j].OccurredAt)
+	})
+	return entries, nil
 }
 
 // WebhookHandler returns an HTTP handler for PagerDuty webhooks
@@ -335,3 +372,95 @@ func (s *Service) WebhookHandler() interface{} {
 		_ = json.NewEncoder(w).Encode(map[string]string{"status": "received"})
 	})
 }
+
+// VerifyWebhook implements notification.WebhookParser. V3 deliveries carry
+// an HMAC-SHA256 of the body in X-PagerDuty-Signature as one or more
+// comma-separated v1= signatures, more than one while a secret is rotated.
+func (s *Service) VerifyWebhook(header http.Header, payload []byte) bool {
+	if s.webhookSecret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write(payload)
+	expected := "v1=" + hex.EncodeToString(mac.Sum(nil))
+	for _, sig := range strings.Split(header.Get("X-PagerDuty-Signature"), ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(sig)), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWebhook converts a PagerDuty V3 webhook delivery into alerts.
+// incident.triggered, incident.acknowledged and incident.resolved events set
+// the alert's lifecycle timestamps. incident.escalated and
+// incident.reassigned carry no state change but are returned so the
+// incident's log can be re-synced. Other event types are ignored.
+func (s *Service) ParseWebhook(payload []byte, receivedAt time.Time) ([]*notification.Alert, error) {
+	var delivery struct {
+		Event struct {
+			EventType  string     `json:"event_type"`
+			OccurredAt *time.Time `json:"occurred_at"`
+			Data       struct {
+				ID          string    `json:"id"`
+				Type        string    `json:"type"`
+				Title       string    `json:"title"`
+				Description string    `json:"description"`
+				Urgency     string    `json:"urgency"`
+				CreatedAt   time.Time `json:"created_at"`
+				Teams       []struct {
+					Summary string `json:"summary"`
+				} `json:"teams"`
+			} `json:"data"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return nil, fmt.Errorf("failed to decode PagerDuty webhook: %w", err)
+	}
+
+	event := delivery.Event
+	if event.Data.Type != "incident" || event.Data.ID == "" {
+		return nil, nil
+	}
+
+	occurredAt := receivedAt
+	if event.OccurredAt != nil {
+		occurredAt = *event.OccurredAt
+	}
+
+	teamName := "unknown"
+	var teamNames []string
+	for _, t := range event.Data.Teams {
+		teamNames = append(teamNames, t.Summary)
+	}
+	if len(teamNames) > 0 {
+		teamName = teamNames[0]
+	}
+
+	alert := &notification.Alert{
+		ExternalID:  event.Data.ID,
+		Source:      "pagerduty",
+		TeamName:    teamName,
+		TeamNames:   teamNames,
+		Title:       event.Data.Title,
+		Description: event.Data.Description,
+		Severity:    event.Data.Urgency,
+		TriggeredAt: event.Data.CreatedAt,
+	}
+	if alert.TriggeredAt.IsZero() {
+		alert.TriggeredAt = occurredAt
+	}
+
+	switch event.EventType {
+	case "incident.triggered":
+	case "incident.acknowledged":
+		alert.AcknowledgedAt = &occurredAt
+	case "incident.resolved":
+		alert.ResolvedAt = &occurredAt
+	case "incident.escalated", "incident.reassigned":
+	default:
+		return nil, nil
+	}
+
+	return []*notification.Alert{alert}, nil
+}
@@ -0,0 +1,43 @@
+package pagerduty
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	payload := []byte(`{"event":{"event_type":"incident.triggered"}}`)
+	sign := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		return "v1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		want      bool
+	}{
+		{"valid", "secret", sign("secret"), true},
+		{"rotating secrets", "secret", sign("old") + "," + sign("secret"), true},
+		{"wrong secret", "secret", sign("other"), false},
+		{"unsigned", "secret", "", false},
+		{"no secret configured", "", sign(""), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(Config{APIKey: "key", WebhookSecret: tt.secret})
+			header := http.Header{}
+			if tt.signature != "" {
+				header.Set("X-PagerDuty-Signature", tt.signature)
+			}
+			if got := svc.VerifyWebhook(header, payload); got != tt.want {
+				t.Errorf("VerifyWebhook = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
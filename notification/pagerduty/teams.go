@@ -0,0 +1,117 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/conall/outalator/notification"
+)
+
+// FetchTeams retrieves every PagerDuty team along with the email addresses
+// of its members
+func (s *Service) FetchTeams(ctx context.Context) ([]*notification.Team, error) {
+	const pageSize = 100
+
+	var teams []*notification.Team
+	for offset := 0; ; offset += pageSize {
+		var result struct {
+			Teams []struct {
+				ID          string `json:"id"`
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"teams"`
+			More bool `json:"more"`
+		}
+		params := url.Values{}
+		params.Set("limit", strconv.Itoa(pageSize))
+		params.Set("offset", strconv.Itoa(offset))
+		if err := s.get(ctx, "/teams?"+params.Encode(), &result); err != nil {
+			return nil, fmt.Errorf("failed to fetch teams: %w", err)
+		}
+
+		for _, t := range result.Teams {
+			members, err := s.fetchTeamMembers(ctx, t.ID)
+			if err != nil {
+				return nil, err
+			}
+			teams = append(teams, &notification.Team{
+				ExternalID:  t.ID,
+				Name:        t.Name,
+				Description: t.Description,
+				Members:     members,
+			})
+		}
+
+		if !result.More {
+			break
+		}
+	}
+	return teams, nil
+}
+
+// fetchTeamMembers returns the email addresses of a team's members
+func (s *Service) fetchTeamMembers(ctx context.Context, teamID string) ([]string, error) {
+	const pageSize = 100
+
+	var emails []string
+	for offset := 0; ; offset += pageSize {
+		var result struct {
+			Members []struct {
+				User struct {
+					Email string `json:"email"`
+				} `json:"user"`
+			} `json:"members"`
+			More bool `json:"more"`
+		}
+		params := url.Values{}
+		params.Set("limit", strconv.Itoa(pageSize))
+		params.Set("offset", strconv.Itoa(offset))
+		params.Add("include[]", "users")
+		if err := s.get(ctx, fmt.Sprintf("/teams/%s/members?%s", url.PathEscape(teamID), params.Encode()), &result); err != nil {
+			return nil, fmt.Errorf("failed to fetch members of team %s: %w", teamID, err)
+		}
+
+		for _, m := range result.Members {
+			if m.User.Email != "" {
+				emails = append(emails, m.User.Email)
+			}
+		}
+
+		if !result.More {
+			break
+		}
+	}
+	return emails, nil
+}
+
+// get fetches path from the PagerDuty API and decodes the JSON response into v
+func (s *Service) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.apiURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%s", s.apiKey))
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("PagerDuty API error: %s (status: %d)", string(body), resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
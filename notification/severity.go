@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severities lists the Outalator severities, most severe first
+var Severities = []string{"critical", "high", "medium", "low"}
+
+// OutageSeverity returns the severity of an outage opened from an alert or
+// incident of the given severity: the severity itself when it is an
+// Outalator severity, and "" otherwise, leaving the outage unclassified
+// rather than storing a source's native value the mapping has no entry for
+func OutageSeverity(severity string) string {
+	if slices.Contains(Severities, severity) {
+		return severity
+	}
+	return ""
+}
+
+// RawSeverityKey is the alert source_metadata key that preserves a source's
+// native severity once it has been mapped, so the mapping can be re-applied
+// after it changes
+const RawSeverityKey = "raw_severity"
+
+// SeverityMapping maps the native severity values reported by each source
+// (e.g. PagerDuty urgency, OpsGenie priority) to Outalator severities. It is
+// keyed by source name and then by native value. Values without an entry are
+// kept as reported.
+type SeverityMapping map[string]map[string]string
+
+// DefaultSeverityMapping returns the mapping used for the built-in providers
+// unless configuration overrides it: OpsGenie priorities P1-P5 and PagerDuty
+// urgencies
+func DefaultSeverityMapping() SeverityMapping {
+	return SeverityMapping{
+		"opsgenie":  {"P1": "critical", "P2": "high", "P3": "medium", "P4": "low", "P5": "low"},
+		"pagerduty": {"high": "high", "low": "low"},
+	}
+}
+
+// WithDefaults returns m layered over DefaultSeverityMapping. Each native
+// value m maps replaces the default for it; the other defaults of the same
+// source still apply.
+func (m SeverityMapping) WithDefaults() SeverityMapping {
+	merged := DefaultSeverityMapping()
+	for source, values := range m {
+		if merged[source] == nil {
+			merged[source] = make(map[string]string, len(values))
+		}
+		for native, severity := range values {
+			merged[source][native] = severity
+		}
+	}
+	return merged
+}
+
+// Check verifies that every mapping targets a known Outalator severity
+func (m SeverityMapping) Check() error {
+	for source, values := range m {
+		for native, severity := range values {
+			if !slices.Contains(Severities, severity) {
+				return fmt.Errorf("severity mapping for %s: %q maps to unknown severity %q (want one of %s)",
+					source, native, severity, strings.Join(Severities, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// Normalize returns the Outalator severity for a native severity reported by
+// source, or severity unchanged if there is no mapping for it
+func (m SeverityMapping) Normalize(source, severity string) string {
+	if mapped, ok := m[source][severity]; ok {
+		return mapped
+	}
+	return severity
+}
+
+// Apply maps alert's severity in place, keeping the native value under
+// RawSeverityKey in its source metadata when the mapping changes it
+func (m SeverityMapping) Apply(alert *Alert) {
+	mapped := m.Normalize(alert.Source, alert.Severity)
+	if mapped == alert.Severity {
+		return
+	}
+	if alert.SourceMetadata == nil {
+		alert.SourceMetadata = make(map[string]any)
+	}
+	alert.SourceMetadata[RawSeverityKey] = alert.Severity
+	alert.Severity = mapped
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// maxActionItemDescription is the longest action item description accepted
+const maxActionItemDescription = 2000
+
+// CreateActionItem adds an action item to an outage outside the trash.
+// createdBy is the email of the user adding it, if known. Items start open
+// unless req.Status says otherwise.
+func (s *Service) CreateActionItem(ctx context.Context, outageID uuid.UUID, createdBy string, req domain.CreateActionItemRequest) (*domain.ActionItem, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateActionItem")
+	defer span.End()
+
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return nil, err
+	}
+	description, err := checkActionItemDescription(req.Description)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status == "" {
+		req.Status = domain.ActionItemOpen
+	}
+	if err := checkActionItemStatus(req.Status); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	item := &domain.ActionItem{
+		ID:          uuid.New(),
+		OutageID:    outageID,
+		Description: description,
+		Assignee:    strings.TrimSpace(req.Assignee),
+		DueDate:     req.DueDate,
+		Status:      req.Status,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if item.Closed() {
+		item.ClosedAt = &now
+	}
+	if err := s.storage.CreateActionItem(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to create action item: %w", err)
+	}
+	return item, nil
+}
+
+// GetActionItem retrieves an action item by ID
+func (s *Service) GetActionItem(ctx context.Context, id uuid.UUID) (*domain.ActionItem, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetActionItem")
+	defer span.End()
+
+	return s.storage.GetActionItem(ctx, id)
+}
+
+// ListActionItems lists an outage's action items, oldest first
+func (s *Service) ListActionItems(ctx context.Context, outageID uuid.UUID) ([]*domain.ActionItem, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListActionItems")
+	defer span.End()
+
+	if _, err := s.storage.GetOutage(ctx, outageID); err != nil {
+		return nil, err
+	}
+	items, err := s.storage.ListActionItemsByOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []*domain.ActionItem{}
+	}
+	return items, nil
+}
+
+// UpdateActionItem changes the fields set in req. Closing an item records
+// when it was closed; reopening it clears that again.
+func (s *Service) UpdateActionItem(ctx context.Context, id uuid.UUID, req domain.UpdateActionItemRequest) (*domain.ActionItem, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateActionItem")
+	defer span.End()
+
+	item, err := s.storage.GetActionItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeOutageChange(ctx, item.OutageID); err != nil {
+		return nil, err
+	}
+	if req.ClearDueDate && req.DueDate != nil {
+		return nil, fmt.Errorf("due_date and clear_due_date cannot both be set: %w", domain.ErrInvalidInput)
+	}
+
+	if req.Description != nil {
+		if item.Description, err = checkActionItemDescription(*req.Description); err != nil {
+			return nil, err
+		}
+	}
+	if req.Assignee != nil {
+		item.Assignee = strings.TrimSpace(*req.Assignee)
+	}
+	if req.DueDate != nil {
+		item.DueDate = req.DueDate
+	}
+	if req.ClearDueDate {
+		item.DueDate = nil
+	}
+
+	now := time.Now()
+	if req.Status != nil && *req.Status != item.Status {
+		if err := checkActionItemStatus(*req.Status); err != nil {
+			return nil, err
+		}
+		wasClosed := item.Closed()
+		item.Status = *req.Status
+		switch {
+		case item.Closed() && !wasClosed:
+			item.ClosedAt = &now
+		case !item.Closed():
+			item.ClosedAt = nil
+		}
+	}
+	item.UpdatedAt = now
+
+	if err := s.storage.UpdateActionItem(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update action item: %w", err)
+	}
+	return item, nil
+}
+
+// DeleteActionItem deletes an action item
+func (s *Service) DeleteActionItem(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteActionItem")
+	defer span.End()
+
+	if item, err := s.storage.GetActionItem(ctx, id); err == nil {
+		if err := s.authorizeOutageChange(ctx, item.OutageID); err != nil {
+			return err
+		}
+	}
+	return s.storage.DeleteActionItem(ctx, id)
+}
+
+// GetOverdueActionItems reports the open action items due before now,
+// grouped by the team owning their outage and most overdue first. A
+// non-empty team limits the report to that team's outages. Items on outages
+// in the trash are left out.
+func (s *Service) GetOverdueActionItems(ctx context.Context, team string, now time.Time) (*domain.OverdueActionItems, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetOverdueActionItems")
+	defer span.End()
+
+	items, err := s.storage.ListOverdueActionItems(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overdue action items: %w", err)
+	}
+
+	report := &domain.OverdueActionItems{
+		GeneratedAt: now,
+		Teams:       []domain.TeamOverdueActionItems{},
+	}
+	outages := make(map[uuid.UUID]*domain.Outage)
+	teams := make(map[string]*domain.TeamOverdueActionItems)
+	for _, item := range items {
+		outage, ok := outages[item.OutageID]
+		if !ok {
+			if outage, err = s.storage.GetOutage(ctx, item.OutageID); err != nil {
+				return nil, fmt.Errorf("failed to get outage %s: %w", item.OutageID, err)
+			}
+			outages[item.OutageID] = outage
+		}
+		if outage.DeletedAt != nil || (team != "" && outage.OwningTeam != team) {
+			continue
+		}
+
+		entry, ok := teams[outage.OwningTeam]
+		if !ok {
+			entry = &domain.TeamOverdueActionItems{Team: outage.OwningTeam}
+			teams[outage.OwningTeam] = entry
+		}
+		entry.Items = append(entry.Items, domain.OverdueActionItem{
+			ActionItem:  *item,
+			OutageTitle: outage.Title,
+			DaysOverdue: int(now.Sub(*item.DueDate) / (24 * time.Hour)),
+		})
+		report.Total++
+	}
+
+	for _, entry := range teams {
+		// Storage lists earliest due first, which is most overdue first
+		report.Teams = append(report.Teams, *entry)
+	}
+	sort.Slice(report.Teams, func(i, j int) bool { return report.Teams[i].Team < report.Teams[j].Team })
+	return report, nil
+}
+
+// checkActionItemDescription trims an action item description and checks it
+// is present and not too long
+func checkActionItemDescription(description string) (string, error) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return "", fmt.Errorf("description is required: %w", domain.ErrInvalidInput)
+	}
+	if len(description) > maxActionItemDescription {
+		return "", fmt.Errorf("description is longer than %d bytes: %w", maxActionItemDescription, domain.ErrInvalidInput)
+	}
+	return description, nil
+}
+
+// checkActionItemStatus checks status is a known action item status
+func checkActionItemStatus(status string) error {
+	if !slices.Contains(domain.ActionItemStatuses, status) {
+		return fmt.Errorf("unknown status %q (want one of %s): %w", status, strings.Join(domain.ActionItemStatuses, ", "), domain.ErrInvalidInput)
+	}
+	return nil
+}
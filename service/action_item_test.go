@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+func TestActionItemLifecycle(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Card errors", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []domain.CreateActionItemRequest{
+		{Description: "  "},
+		{Description: "add retries", Status: "finished"},
+	}
+	for _, req := range invalid {
+		if _, err := svc.CreateActionItem(ctx, outage.ID, "", req); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("CreateActionItem(%+v) error = %v, want ErrInvalidInput", req, err)
+		}
+	}
+	if _, err := svc.CreateActionItem(ctx, uuid.New(), "", domain.CreateActionItemRequest{Description: "x"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("CreateActionItem on a missing outage error = %v, want ErrNotFound", err)
+	}
+
+	item, err := svc.CreateActionItem(ctx, outage.ID, "alice@example.com", domain.CreateActionItemRequest{Description: " add retries "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Description != "add retries" || item.Status != domain.ActionItemOpen || item.ClosedAt != nil {
+		t.Errorf("item = %+v, want an open item with a trimmed description", item)
+	}
+
+	due := time.Now().Add(24 * time.Hour)
+	done := domain.ActionItemDone
+	item, err = svc.UpdateActionItem(ctx, item.ID, domain.UpdateActionItemRequest{DueDate: &due, Status: &done})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ClosedAt == nil || item.DueDate == nil {
+		t.Errorf("closed item = %+v, want a due date and closed time", item)
+	}
+	if _, err := svc.UpdateActionItem(ctx, item.ID, domain.UpdateActionItemRequest{DueDate: &due, ClearDueDate: true}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("setting and clearing the due date error = %v, want ErrInvalidInput", err)
+	}
+	open := domain.ActionItemInProgress
+	item, err = svc.UpdateActionItem(ctx, item.ID, domain.UpdateActionItemRequest{Status: &open, ClearDueDate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ClosedAt != nil || item.DueDate != nil {
+		t.Errorf("reopened item = %+v, want no closed time or due date", item)
+	}
+
+	events, err := svc.GetOutageTimeline(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := 0
+	for _, e := range events {
+		if e.Type == domain.TimelineActionItemAdded && e.EntityID == item.ID && e.Actor == "alice@example.com" {
+			added++
+		}
+	}
+	if added != 1 {
+		t.Errorf("timeline = %+v, want one action_item_added event", events)
+	}
+}
+
+func TestGetOverdueActionItems(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}, {Name: "search"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	addItem := func(outage *domain.Outage, desc string, due time.Time) {
+		t.Helper()
+		if _, err := svc.CreateActionItem(ctx, outage.ID, "", domain.CreateActionItemRequest{Description: desc, DueDate: &due}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	create := func(title, team string) *domain.Outage {
+		t.Helper()
+		outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: "high", OwningTeam: team})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return outage
+	}
+
+	cards := create("Card errors", "payments")
+	addItem(cards, "add retries", now.Add(-26*time.Hour))
+	addItem(cards, "write runbook", now.Add(-5*24*time.Hour))
+	addItem(cards, "not due yet", now.Add(time.Hour))
+	addItem(create("Search down", "search"), "tune alerts", now.Add(-time.Hour))
+	addItem(create("Unowned", ""), "find an owner", now.Add(-time.Hour))
+	trashed := create("Trashed", "search")
+	addItem(trashed, "ignored", now.Add(-time.Hour))
+	if err := svc.DeleteOutage(ctx, trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := svc.GetOverdueActionItems(ctx, "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 4 || len(report.Teams) != 3 {
+		t.Fatalf("report = %+v, want 4 items across 3 teams", report)
+	}
+	if report.Teams[0].Team != "" || report.Teams[1].Team != "payments" || report.Teams[2].Team != "search" {
+		t.Errorf("teams = %+v, want unowned, payments, search", report.Teams)
+	}
+	payments := report.Teams[1].Items
+	if len(payments) != 2 || payments[0].Description != "write runbook" || payments[0].DaysOverdue != 5 ||
+		payments[1].DaysOverdue != 1 || payments[1].OutageTitle != "Card errors" {
+		t.Errorf("payments items = %+v, want the runbook 5 days overdue then retries 1 day overdue", payments)
+	}
+
+	report, err = svc.GetOverdueActionItems(ctx, "search", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 1 || len(report.Teams) != 1 || report.Teams[0].Items[0].Description != "tune alerts" {
+		t.Errorf("search report = %+v, want only the search item", report)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// AcknowledgeAlert acknowledges an alert with the provider it came from on
+// behalf of actor, then records the acknowledgement unless the alert was
+// already acknowledged. Alerts from sources that cannot be acknowledged
+// upstream are rejected as invalid input.
+func (s *Service) AcknowledgeAlert(ctx context.Context, alertID uuid.UUID, actor string) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.AcknowledgeAlert")
+	defer span.End()
+
+	alert, provider, err := s.alertWithProvider(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeOutageChange(ctx, alert.OutageID); err != nil {
+		return nil, err
+	}
+	if err := provider.AcknowledgeAlert(ctx, alert.ExternalID, actor); err != nil {
+		return nil, upstreamActionError("acknowledge", alert, err)
+	}
+	s.logger.InfoContext(ctx, "alert acknowledged upstream", "alert_id", alertID, "source", alert.Source, "actor", actor)
+
+	if alert.AcknowledgedAt != nil {
+		return alert, nil
+	}
+	now := time.Now()
+	return s.UpdateAlert(ctx, alertID, domain.UpdateAlertRequest{AcknowledgedAt: &now})
+}
+
+// ResolveAlert resolves an alert with the provider it came from on behalf of
+// actor, then records the resolution unless the alert was already resolved.
+// Recording it may resolve the outage under the alert resolution policy.
+func (s *Service) ResolveAlert(ctx context.Context, alertID uuid.UUID, actor string) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.ResolveAlert")
+	defer span.End()
+
+	alert, provider, err := s.alertWithProvider(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeOutageChange(ctx, alert.OutageID); err != nil {
+		return nil, err
+	}
+	if err := provider.ResolveAlert(ctx, alert.ExternalID, actor); err != nil {
+		return nil, upstreamActionError("resolve", alert, err)
+	}
+	s.logger.InfoContext(ctx, "alert resolved upstream", "alert_id", alertID, "source", alert.Source, "actor", actor)
+
+	if alert.ResolvedAt != nil {
+		return alert, nil
+	}
+	now := time.Now()
+	return s.UpdateAlert(ctx, alertID, domain.UpdateAlertRequest{ResolvedAt: &now})
+}
+
+// alertWithProvider loads an alert and the notification service of its
+// source
+func (s *Service) alertWithProvider(ctx context.Context, alertID uuid.UUID) (*domain.Alert, notification.Service, error) {
+	alert, err := s.storage.GetAlert(ctx, alertID)
+	if err != nil {
+		return nil, nil, err
+	}
+	provider, ok := s.notificationServices[alert.Source]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: alert source %s is not a configured notification service", domain.ErrInvalidInput, alert.Source)
+	}
+	return alert, provider, nil
+}
+
+// upstreamActionError reports a failed provider action. Sources that do not
+// support the action are reported as invalid input; other failures keep the
+// provider's error.
+func upstreamActionError(action string, alert *domain.Alert, err error) error {
+	if errors.Is(err, notification.ErrUnsupported) {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	return fmt.Errorf("failed to %s alert %s with %s: %w", action, alert.ExternalID, alert.Source, err)
+}
+
+// resolveAlertsUpstream resolves an outage's open alerts with their
+// providers and records them as resolved. Alerts whose source is not
+// configured or cannot resolve alerts are left open. Failures are logged
+// rather than returned, since the outage itself is already resolved.
+func (s *Service) resolveAlertsUpstream(ctx context.Context, outageID uuid.UUID, actor string) {
+	alerts, err := s.storage.ListAlertsByOutage(ctx, outageID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to list alerts to resolve upstream", "outage_id", outageID, "error", err)
+		return
+	}
+
+	for _, alert := range alerts {
+		if alert.ResolvedAt != nil {
+			continue
+		}
+		provider, ok := s.notificationServices[alert.Source]
+		if !ok {
+			continue
+		}
+		if err := provider.ResolveAlert(ctx, alert.ExternalID, actor); err != nil {
+			if !errors.Is(err, notification.ErrUnsupported) {
+				s.logger.WarnContext(ctx, "failed to resolve alert upstream",
+					"outage_id", outageID, "alert_id", alert.ID, "source", alert.Source, "error", err)
+			}
+			continue
+		}
+
+		now := time.Now()
+		alert.ResolvedAt = &now
+		if err := s.storage.UpdateAlert(ctx, alert); err != nil {
+			s.logger.WarnContext(ctx, "failed to record upstream resolution",
+				"outage_id", outageID, "alert_id", alert.ID, "error", err)
+			continue
+		}
+		s.logger.InfoContext(ctx, "alert resolved upstream", "outage_id", outageID, "alert_id", alert.ID, "source", alert.Source, "actor", actor)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// fakeActionSource records the alerts acknowledged and resolved through it.
+// When unsupported is set it refuses both actions.
+type fakeActionSource struct {
+	fakeWebhookSource
+	unsupported bool
+	acked       []string
+	resolved    []string
+	requesters  []string
+}
+
+func (f *fakeActionSource) AcknowledgeAlert(_ context.Context, alertID, requester string) error {
+	if f.unsupported {
+		return notification.ErrUnsupported
+	}
+	f.acked = append(f.acked, alertID)
+	f.requesters = append(f.requesters, requester)
+	return nil
+}
+
+func (f *fakeActionSource) ResolveAlert(_ context.Context, alertID, requester string) error {
+	if f.unsupported {
+		return notification.ErrUnsupported
+	}
+	f.resolved = append(f.resolved, alertID)
+	f.requesters = append(f.requesters, requester)
+	return nil
+}
+
+func TestAcknowledgeAndResolveAlert(t *testing.T) {
+	svc := newSvc()
+	source := &fakeActionSource{}
+	svc.RegisterNotificationService(source)
+	ctx := context.Background()
+
+	alert, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "fake", Title: "db down", Severity: "high", TriggeredAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acked, err := svc.AcknowledgeAlert(ctx, alert.ID, "alice@example.com")
+	if err != nil {
+		t.Fatalf("AcknowledgeAlert: %v", err)
+	}
+	if acked.AcknowledgedAt == nil || len(source.acked) != 1 || source.acked[0] != "A1" || source.requesters[0] != "alice@example.com" {
+		t.Errorf("acknowledged alert = %+v, upstream %v by %v", acked, source.acked, source.requesters)
+	}
+
+	resolved, err := svc.ResolveAlert(ctx, alert.ID, "alice@example.com")
+	if err != nil {
+		t.Fatalf("ResolveAlert: %v", err)
+	}
+	if resolved.ResolvedAt == nil || len(source.resolved) != 1 {
+		t.Errorf("resolved alert = %+v, upstream %v", resolved, source.resolved)
+	}
+
+	source.unsupported = true
+	if _, err := svc.ResolveAlert(ctx, alert.ID, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unsupported source error = %v, want ErrInvalidInput", err)
+	}
+
+	imported, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "B1", Source: "nagios", Title: "disk full", TriggeredAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AcknowledgeAlert(ctx, imported.ID, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unconfigured source error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestResolveOutageResolvesAlertsUpstream(t *testing.T) {
+	svc := newSvc()
+	source := &fakeActionSource{}
+	svc.RegisterNotificationService(source)
+	ctx := context.Background()
+
+	first, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "fake", Title: "db down", TriggeredAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outageID := first.OutageID
+	resolvedAt := time.Now()
+	for _, a := range []*notification.Alert{
+		{ExternalID: "A2", Source: "fake", Title: "db replica down", TriggeredAt: time.Now()},
+		{ExternalID: "A3", Source: "fake", Title: "db flapping", TriggeredAt: time.Now(), ResolvedAt: &resolvedAt},
+		{ExternalID: "B1", Source: "nagios", Title: "disk full", TriggeredAt: time.Now()},
+	} {
+		if _, _, err := svc.storeAlert(ctx, a, &outageID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := svc.TransitionOutage(ctx, outageID, domain.TransitionRequest{Action: "mitigate"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(source.resolved) != 0 {
+		t.Fatalf("resolved upstream before the outage was resolved: %v", source.resolved)
+	}
+
+	if _, err := svc.TransitionOutage(ctx, outageID, domain.TransitionRequest{Action: "resolve", Actor: "alice@example.com", ResolveAlerts: true}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(source.resolved)
+	if len(source.resolved) != 2 || source.resolved[0] != "A1" || source.resolved[1] != "A2" {
+		t.Errorf("resolved upstream = %v, want the open fake alerts A1 and A2", source.resolved)
+	}
+	alerts, err := svc.ListAlertsByOutage(ctx, outageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range alerts {
+		if wantResolved := a.Source == "fake"; (a.ResolvedAt != nil) != wantResolved {
+			t.Errorf("alert %s resolved = %v, want %v", a.ExternalID, a.ResolvedAt != nil, wantResolved)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// SyncAlertEvents fetches the provider's log for an alert and records the
+// notification, escalation and reassignment events it has not seen before.
+// It returns the number of log entries fetched. Sources that do not expose
+// an alert log are skipped.
+func (s *Service) SyncAlertEvents(ctx context.Context, alert *domain.Alert) (int, error) {
+	ctx, span := tracer.Start(ctx, "Service.SyncAlertEvents")
+	defer span.End()
+
+	fetcher, ok := s.notificationServices[alert.Source].(notification.LogEntryFetcher)
+	if !ok {
+		return 0, nil
+	}
+
+	entries, err := fetcher.FetchLogEntries(ctx, alert.ExternalID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s log entries for alert %s: %w", alert.Source, alert.ExternalID, err)
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		event := &domain.AlertEvent{
+			ID:         uuid.New(),
+			AlertID:    alert.ID,
+			ExternalID: entry.ExternalID,
+			Type:       entry.Type,
+			Summary:    entry.Summary,
+			Actor:      entry.Actor,
+			Target:     entry.Target,
+			OccurredAt: entry.OccurredAt,
+			CreatedAt:  now,
+		}
+		if err := s.storage.CreateAlertEvent(ctx, event); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+// syncAlertEventsBestEffort syncs an alert's provider log, logging rather
+// than returning failures so a provider API outage does not block alert
+// ingestion.
+func (s *Service) syncAlertEventsBestEffort(ctx context.Context, alert *domain.Alert) {
+	if _, err := s.SyncAlertEvents(ctx, alert); err != nil {
+		s.logger.WarnContext(ctx, "failed to sync alert events",
+			"alert_id", alert.ID, "source", alert.Source, "error", err)
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// fakeLogSource is a webhook source that also exposes a per-alert log
+type fakeLogSource struct {
+	fakeWebhookSource
+	entries []*notification.LogEntry
+	err     error
+}
+
+func (f *fakeLogSource) FetchLogEntries(context.Context, string) ([]*notification.LogEntry, error) {
+	return f.entries, f.err
+}
+
+func TestProcessWebhook_SyncsAlertEvents(t *testing.T) {
+	svc := newSvc()
+	triggered := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	source := &fakeLogSource{entries: []*notification.LogEntry{
+		{ExternalID: "L1", Type: notification.LogEntryNotified, Summary: "Notified Alice via SMS", Target: "Alice", OccurredAt: triggered.Add(time.Minute)},
+		{ExternalID: "L2", Type: notification.LogEntryEscalated, Summary: "Escalated to level 2", Actor: "Escalation policy", OccurredAt: triggered.Add(10 * time.Minute)},
+	}}
+	svc.RegisterNotificationService(source)
+	ctx := context.Background()
+
+	payload, err := json.Marshal(notification.Alert{ExternalID: "A1", Source: "fake", Title: "disk full", TriggeredAt: triggered})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Redelivery re-syncs the log without duplicating events.
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+			t.Fatalf("ProcessWebhook: %v", err)
+		}
+	}
+
+	outages, err := svc.ListOutages(ctx, 10, 0)
+	if err != nil || len(outages) != 1 {
+		t.Fatalf("ListOutages = %d outages, %v; want 1", len(outages), err)
+	}
+	events, err := svc.GetOutageTimeline(ctx, outages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var alertEvents []domain.TimelineEvent
+	for _, e := range events {
+		if e.Type == domain.TimelineAlertEvent {
+			alertEvents = append(alertEvents, e)
+		}
+	}
+	if len(alertEvents) != 2 {
+		t.Fatalf("expected 2 alert events in timeline, got %d: %+v", len(alertEvents), events)
+	}
+	if alertEvents[0].Details["target"] != "Alice" || alertEvents[0].Details["event_type"] != notification.LogEntryNotified {
+		t.Errorf("first alert event = %+v, want notification of Alice", alertEvents[0])
+	}
+	if alertEvents[1].Actor != "Escalation policy" {
+		t.Errorf("escalation actor = %q, want Escalation policy", alertEvents[1].Actor)
+	}
+}
+
+func TestProcessWebhook_AlertEventFailureDoesNotBlockIngestion(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(&fakeLogSource{err: errors.New("provider unavailable")})
+	ctx := context.Background()
+
+	payload, err := json.Marshal(notification.Alert{ExternalID: "A1", Source: "fake", Title: "disk full", TriggeredAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+
+	alert, err := svc.storage.GetAlertByExternalID(ctx, "A1", "fake")
+	if err != nil {
+		t.Fatalf("alert was not stored: %v", err)
+	}
+	if _, err := svc.SyncAlertEvents(ctx, alert); err == nil {
+		t.Error("expected SyncAlertEvents to report the provider error")
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/tabular"
+)
+
+// analyticsExport is the installed analytics export: where files are
+// written, in which format, and which tables
+type analyticsExport struct {
+	store  BlobStore
+	format string
+	tables []string
+}
+
+// SetAnalyticsExport enables ExportAnalytics, writing files to store. An
+// empty Format writes Parquet and empty Tables exports every table.
+func (s *Service) SetAnalyticsExport(store BlobStore, cfg domain.AnalyticsExportConfig) error {
+	format := strings.ToLower(cfg.Format)
+	if format == "" {
+		format = domain.ExportFormatParquet
+	}
+	if format != domain.ExportFormatCSV && format != domain.ExportFormatParquet {
+		return fmt.Errorf("%w: unknown analytics export format %q", domain.ErrInvalidInput, cfg.Format)
+	}
+	tables := cfg.Tables
+	if len(tables) == 0 {
+		tables = domain.ExportTables
+	}
+	for _, table := range tables {
+		if !slices.Contains(domain.ExportTables, table) {
+			return fmt.Errorf("%w: unknown analytics export table %q", domain.ErrInvalidInput, table)
+		}
+	}
+	s.analytics = &analyticsExport{store: store, format: format, tables: slices.Clone(tables)}
+	return nil
+}
+
+// ExportAnalytics writes the rows of each configured table that changed
+// since its watermark and before now to one file per table, then advances
+// the watermarks to now. Rows that change again are written again by a
+// later export, so loaders should keep the latest row for each id. A table
+// whose export fails keeps its watermark and is retried in full next time;
+// the tables exported before it are returned with the error.
+func (s *Service) ExportAnalytics(ctx context.Context, now time.Time) ([]domain.TableExport, error) {
+	ctx, span := tracer.Start(ctx, "Service.ExportAnalytics")
+	defer span.End()
+
+	if s.analytics == nil {
+		return nil, fmt.Errorf("%w: analytics export is not configured", domain.ErrInvalidInput)
+	}
+
+	now = now.UTC()
+	var exports []domain.TableExport
+	for _, table := range s.analytics.tables {
+		export, err := s.exportTable(ctx, table, now)
+		if err != nil {
+			return exports, fmt.Errorf("failed to export %s: %w", table, err)
+		}
+		exports = append(exports, *export)
+	}
+	return exports, nil
+}
+
+// exportTable writes one table's changed rows and advances its watermark
+func (s *Service) exportTable(ctx context.Context, table string, now time.Time) (*domain.TableExport, error) {
+	export := &domain.TableExport{Table: table, Until: now}
+	watermark, err := s.storage.GetExportWatermark(ctx, table)
+	switch {
+	case err == nil:
+		export.Since = watermark.ExportedUntil.UTC()
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, err
+	}
+	if !export.Since.Before(now) {
+		return export, nil
+	}
+
+	changed := func(t time.Time) bool {
+		return !t.Before(export.Since) && t.Before(now)
+	}
+	var rows *tabular.Table
+	switch table {
+	case domain.ExportTableOutages:
+		rows = &tabular.Table{Columns: outageColumns}
+	case domain.ExportTableAlerts:
+		rows = &tabular.Table{Columns: alertColumns}
+	case domain.ExportTableNotes:
+		rows = &tabular.Table{Columns: noteColumns}
+	}
+	err = s.ExportOutages(ctx, domain.OutageExportQuery{Until: now}, func(outage *domain.Outage) error {
+		switch table {
+		case domain.ExportTableOutages:
+			if changed(outage.UpdatedAt) {
+				return appendOutageRow(rows, outage)
+			}
+		case domain.ExportTableAlerts:
+			for i := range outage.Alerts {
+				if changed(alertChangedAt(&outage.Alerts[i])) {
+					appendAlertRow(rows, &outage.Alerts[i])
+				}
+			}
+		case domain.ExportTableNotes:
+			for i := range outage.Notes {
+				if changed(outage.Notes[i].UpdatedAt) {
+					appendNoteRow(rows, &outage.Notes[i])
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	export.Rows = len(rows.Rows)
+	if export.Rows > 0 {
+		export.Key = fmt.Sprintf("%s/%s-%s.%s", table, table, now.Format("20060102T150405Z"), s.analytics.format)
+		var buf bytes.Buffer
+		contentType := "text/csv"
+		if s.analytics.format == domain.ExportFormatParquet {
+			contentType = "application/vnd.apache.parquet"
+			err = tabular.WriteParquet(&buf, rows)
+		} else {
+			err = tabular.WriteCSV(&buf, rows)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := s.analytics.store.Put(ctx, export.Key, &buf, int64(buf.Len()), contentType); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", export.Key, err)
+		}
+	}
+
+	if err := s.storage.UpsertExportWatermark(ctx, &domain.ExportWatermark{Table: table, ExportedUntil: now, UpdatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "analytics export written", "table", table, "rows", export.Rows, "key", export.Key)
+	return export, nil
+}
+
+var outageColumns = []tabular.Column{
+	{Name: "id", Type: tabular.String},
+	{Name: "title", Type: tabular.String},
+	{Name: "description", Type: tabular.String},
+	{Name: "status", Type: tabular.String},
+	{Name: "severity", Type: tabular.String},
+	{Name: "owning_team", Type: tabular.String},
+	{Name: "affected_services", Type: tabular.String},
+	{Name: "customer_impact", Type: tabular.Bool},
+	{Name: "alert_count", Type: tabular.Int64},
+	{Name: "note_count", Type: tabular.Int64},
+	{Name: "created_at", Type: tabular.Timestamp},
+	{Name: "updated_at", Type: tabular.Timestamp},
+	{Name: "investigating_at", Type: tabular.Timestamp},
+	{Name: "mitigated_at", Type: tabular.Timestamp},
+	{Name: "resolved_at", Type: tabular.Timestamp},
+	{Name: "impact_started_at", Type: tabular.Timestamp},
+	{Name: "impact_ended_at", Type: tabular.Timestamp},
+	{Name: "metadata", Type: tabular.String},
+	{Name: "custom_fields", Type: tabular.String},
+}
+
+var alertColumns = []tabular.Column{
+	{Name: "id", Type: tabular.String},
+	{Name: "outage_id", Type: tabular.String},
+	{Name: "external_id", Type: tabular.String},
+	{Name: "source", Type: tabular.String},
+	{Name: "team_name", Type: tabular.String},
+	{Name: "title", Type: tabular.String},
+	{Name: "severity", Type: tabular.String},
+	{Name: "service", Type: tabular.String},
+	{Name: "fingerprint", Type: tabular.String},
+	{Name: "triggered_at", Type: tabular.Timestamp},
+	{Name: "acknowledged_at", Type: tabular.Timestamp},
+	{Name: "resolved_at", Type: tabular.Timestamp},
+	{Name: "created_at", Type: tabular.Timestamp},
+}
+
+var noteColumns = []tabular.Column{
+	{Name: "id", Type: tabular.String},
+	{Name: "outage_id", Type: tabular.String},
+	{Name: "parent_note_id", Type: tabular.String},
+	{Name: "format", Type: tabular.String},
+	{Name: "author", Type: tabular.String},
+	{Name: "content", Type: tabular.String},
+	{Name: "created_at", Type: tabular.Timestamp},
+	{Name: "updated_at", Type: tabular.Timestamp},
+}
+
+// appendOutageRow adds an outage to a table of outageColumns. Affected
+// services are comma-separated; metadata and custom fields are JSON.
+func appendOutageRow(t *tabular.Table, o *domain.Outage) error {
+	metadata, err := jsonColumn(o.Metadata)
+	if err != nil {
+		return err
+	}
+	customFields, err := jsonColumn(o.CustomFields)
+	if err != nil {
+		return err
+	}
+	t.Append(o.ID.String(), o.Title, o.Description, o.Status, o.Severity, o.OwningTeam,
+		strings.Join(o.AffectedServices, ","), o.CustomerImpact,
+		int64(len(o.Alerts)), int64(len(o.Notes)),
+		o.CreatedAt, o.UpdatedAt, optionalTime(o.InvestigatingAt), optionalTime(o.MitigatedAt),
+		optionalTime(o.ResolvedAt), optionalTime(o.ImpactStartedAt), optionalTime(o.ImpactEndedAt),
+		metadata, customFields)
+	return nil
+}
+
+// appendAlertRow adds an alert to a table of alertColumns
+func appendAlertRow(t *tabular.Table, a *domain.Alert) {
+	t.Append(a.ID.String(), a.OutageID.String(), a.ExternalID, a.Source, a.TeamName,
+		a.Title, a.Severity, a.Service, a.Fingerprint,
+		a.TriggeredAt, optionalTime(a.AcknowledgedAt), optionalTime(a.ResolvedAt), a.CreatedAt)
+}
+
+// appendNoteRow adds a note to a table of noteColumns
+func appendNoteRow(t *tabular.Table, n *domain.Note) {
+	var parent any
+	if n.ParentNoteID != nil {
+		parent = n.ParentNoteID.String()
+	}
+	t.Append(n.ID.String(), n.OutageID.String(), parent, n.Format, n.Author, n.Content, n.CreatedAt, n.UpdatedAt)
+}
+
+// alertChangedAt is the last time an alert was recorded, acknowledged or
+// resolved. Alerts have no update time of their own.
+func alertChangedAt(a *domain.Alert) time.Time {
+	changed := a.CreatedAt
+	for _, t := range []*time.Time{a.AcknowledgedAt, a.ResolvedAt} {
+		if t != nil && t.After(changed) {
+			changed = *t
+		}
+	}
+	return changed
+}
+
+// optionalTime returns t, or nil for a null column when t is unset
+func optionalTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// jsonColumn encodes a map as a JSON string column, or nil when it is empty
+func jsonColumn[M ~map[string]V, V any](m M) (any, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
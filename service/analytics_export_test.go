@@ -0,0 +1,108 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func TestExportAnalyticsIncremental(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	blobs := newMemBlobStore()
+	if err := svc.SetAnalyticsExport(blobs, domain.AnalyticsExportConfig{Format: "csv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.storeAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "pagerduty", Title: "db down", TriggeredAt: time.Now()}, &outage.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "Failing over", Author: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := time.Now().Add(time.Second)
+	exports, err := svc.ExportAnalytics(ctx, first)
+	if err != nil {
+		t.Fatalf("ExportAnalytics() err = %v", err)
+	}
+	if len(exports) != 3 {
+		t.Fatalf("exported %d tables, want 3", len(exports))
+	}
+	for _, export := range exports {
+		if export.Rows != 1 || !export.Since.IsZero() {
+			t.Errorf("%s export = %+v, want 1 row from the beginning", export.Table, export)
+		}
+	}
+
+	key := "outages/outages-" + first.UTC().Format("20060102T150405Z") + ".csv"
+	records, err := csv.NewReader(bytes.NewReader(blobs.blobs[key])).ReadAll()
+	if err != nil {
+		t.Fatalf("reading %s: %v", key, err)
+	}
+	if len(records) != 2 || records[0][0] != "id" || records[1][0] != outage.ID.String() || records[1][1] != "DB down" {
+		t.Errorf("%s = %q, want a header and the outage", key, records)
+	}
+
+	// Nothing changed since the first export, so no files are written
+	second := first.Add(time.Hour)
+	exports, err = svc.ExportAnalytics(ctx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, export := range exports {
+		if export.Rows != 0 || export.Key != "" || !export.Since.Equal(first.UTC()) {
+			t.Errorf("%s export = %+v, want no rows since %v", export.Table, export, first)
+		}
+	}
+	if len(blobs.blobs) != 3 {
+		t.Errorf("wrote %d files, want 3 from the first export", len(blobs.blobs))
+	}
+}
+
+func TestExportAnalyticsParquet(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	blobs := newMemBlobStore()
+	if err := svc.SetAnalyticsExport(blobs, domain.AnalyticsExportConfig{Tables: []string{domain.ExportTableOutages}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "critical"}); err != nil {
+		t.Fatal(err)
+	}
+
+	exports, err := svc.ExportAnalytics(ctx, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exports) != 1 || exports[0].Table != domain.ExportTableOutages {
+		t.Fatalf("exports = %+v, want only outages", exports)
+	}
+	data := blobs.blobs[exports[0].Key]
+	if len(data) < 8 || string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Errorf("%s is not a Parquet file", exports[0].Key)
+	}
+}
+
+func TestSetAnalyticsExportRejectsUnknownSettings(t *testing.T) {
+	svc := newSvc()
+	if err := svc.SetAnalyticsExport(newMemBlobStore(), domain.AnalyticsExportConfig{Format: "xlsx"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("format xlsx err = %v, want ErrInvalidInput", err)
+	}
+	if err := svc.SetAnalyticsExport(newMemBlobStore(), domain.AnalyticsExportConfig{Tables: []string{"tags"}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("table tags err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.ExportAnalytics(context.Background(), time.Now()); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("ExportAnalytics unconfigured err = %v, want ErrInvalidInput", err)
+	}
+}
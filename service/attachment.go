@@ -0,0 +1,292 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/validation"
+	"github.com/google/uuid"
+)
+
+// defaultAttachmentMaxBytes matches the default attachment request body limit
+const defaultAttachmentMaxBytes = 25 << 20
+
+// maxAttachmentFilename is the longest filename kept with an attachment
+const maxAttachmentFilename = 255
+
+// defaultAttachmentTypes covers graphs, screenshots and log snippets. Types
+// that browsers render as active content, such as HTML and SVG, are left out.
+var defaultAttachmentTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp", "text/plain", "application/pdf",
+}
+
+// errAttachmentsDisabled is returned for uploads and downloads when no blob
+// store is configured
+var errAttachmentsDisabled = fmt.Errorf("attachment storage is not configured: %w", domain.ErrNotFound)
+
+// BlobStore holds attachment content under caller-chosen keys. Get and
+// Delete return domain.ErrNotFound for unknown keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// SetAttachmentStore enables attachments, keeping their content in store.
+// A zero MaxBytes or empty AllowedTypes in policy uses the defaults: 25 MiB
+// of images, plain text or PDF.
+func (s *Service) SetAttachmentStore(store BlobStore, policy domain.AttachmentPolicy) {
+	if policy.MaxBytes <= 0 {
+		policy.MaxBytes = defaultAttachmentMaxBytes
+	}
+	if len(policy.AllowedTypes) == 0 {
+		policy.AllowedTypes = defaultAttachmentTypes
+	}
+	allowed := make([]string, len(policy.AllowedTypes))
+	for i, t := range policy.AllowedTypes {
+		allowed[i] = strings.ToLower(strings.TrimSpace(t))
+	}
+	policy.AllowedTypes = allowed
+
+	s.blobs = store
+	s.attachmentPolicy = policy
+}
+
+// UploadAttachment stores content as an attachment on an outage. The content
+// type is sniffed from the content and must be allowed by the attachment
+// policy. When req.NoteID is set the note must belong to the outage, and
+// the attachment is recorded in its metadata.
+func (s *Service) UploadAttachment(ctx context.Context, req domain.UploadAttachmentRequest, content io.Reader) (*domain.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "Service.UploadAttachment")
+	defer span.End()
+
+	if s.blobs == nil {
+		return nil, errAttachmentsDisabled
+	}
+	filename, err := cleanAttachmentFilename(req.Filename)
+	if err != nil {
+		return nil, err
+	}
+	outage, err := s.liveOutage(ctx, req.OutageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return nil, err
+	}
+
+	var note *domain.Note
+	if req.NoteID != nil {
+		if note, err = s.liveNote(ctx, *req.NoteID); err != nil {
+			return nil, err
+		}
+		if note.OutageID != req.OutageID {
+			return nil, fmt.Errorf("%w: note %s belongs to another outage", domain.ErrInvalidInput, note.ID)
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, s.attachmentPolicy.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if int64(len(data)) > s.attachmentPolicy.MaxBytes {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", domain.ErrAttachmentTooLarge, s.attachmentPolicy.MaxBytes)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: attachment is empty", domain.ErrInvalidInput)
+	}
+	contentType := http.DetectContentType(data)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || !slices.Contains(s.attachmentPolicy.AllowedTypes, mediaType) {
+		return nil, fmt.Errorf("%w: content type %s is not allowed", domain.ErrInvalidInput, contentType)
+	}
+
+	sum := sha256.Sum256(data)
+	attachment := &domain.Attachment{
+		ID:          uuid.New(),
+		OutageID:    req.OutageID,
+		NoteID:      req.NoteID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		UploadedBy:  req.UploadedBy,
+		CreatedAt:   time.Now(),
+	}
+	attachment.StorageKey = fmt.Sprintf("outages/%s/%s", attachment.OutageID, attachment.ID)
+
+	var noteMetadata map[string]string
+	if note != nil {
+		noteMetadata = withAttachment(note.Metadata, attachment.ID)
+		if err := validation.ValidateMetadata(noteMetadata); err != nil {
+			return nil, fmt.Errorf("%w: note metadata: %v", domain.ErrInvalidInput, err)
+		}
+	}
+
+	if err := s.blobs.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if err := s.storage.CreateAttachment(ctx, attachment); err != nil {
+		s.deleteBlob(ctx, attachment)
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	if note != nil {
+		note.Metadata = noteMetadata
+		note.UpdatedAt = attachment.CreatedAt
+		if err := s.storage.UpdateNote(ctx, note); err != nil {
+			return nil, fmt.Errorf("failed to reference attachment from note: %w", err)
+		}
+	}
+
+	s.logger.InfoContext(ctx, "attachment uploaded", "outage_id", attachment.OutageID,
+		"attachment_id", attachment.ID, "content_type", contentType, "size", attachment.Size)
+	return attachment, nil
+}
+
+// GetAttachment returns an attachment's metadata. Attachments of outages in
+// the trash are reported as not found.
+func (s *Service) GetAttachment(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetAttachment")
+	defer span.End()
+
+	attachment, err := s.storage.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.liveOutage(ctx, attachment.OutageID); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// OpenAttachment returns an attachment's metadata and content. The caller
+// closes the content.
+func (s *Service) OpenAttachment(ctx context.Context, id uuid.UUID) (*domain.Attachment, io.ReadCloser, error) {
+	ctx, span := tracer.Start(ctx, "Service.OpenAttachment")
+	defer span.End()
+
+	if s.blobs == nil {
+		return nil, nil, errAttachmentsDisabled
+	}
+	attachment, err := s.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+// ListAttachments returns the attachments of an outage, oldest first
+func (s *Service) ListAttachments(ctx context.Context, outageID uuid.UUID) ([]*domain.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAttachments")
+	defer span.End()
+
+	if _, err := s.liveOutage(ctx, outageID); err != nil {
+		return nil, err
+	}
+	return s.storage.ListAttachmentsByOutage(ctx, outageID)
+}
+
+// DeleteAttachment removes an attachment, its content and the reference to
+// it from its note
+func (s *Service) DeleteAttachment(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteAttachment")
+	defer span.End()
+
+	attachment, err := s.GetAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeOutageChange(ctx, attachment.OutageID); err != nil {
+		return err
+	}
+	if err := s.storage.DeleteAttachment(ctx, id); err != nil {
+		return err
+	}
+	if s.blobs != nil {
+		s.deleteBlob(ctx, attachment)
+	}
+
+	if attachment.NoteID != nil {
+		note, err := s.storage.GetNote(ctx, *attachment.NoteID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load note: %w", err)
+		}
+		note.Metadata = withoutAttachment(note.Metadata, id)
+		note.UpdatedAt = time.Now()
+		if err := s.storage.UpdateNote(ctx, note); err != nil {
+			return fmt.Errorf("failed to remove attachment from note: %w", err)
+		}
+	}
+	return nil
+}
+
+// deleteBlob removes an attachment's content. Failures leave an orphaned
+// blob behind and are only logged.
+func (s *Service) deleteBlob(ctx context.Context, attachment *domain.Attachment) {
+	if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		s.logger.WarnContext(ctx, "failed to delete attachment content",
+			"attachment_id", attachment.ID, "key", attachment.StorageKey, "error", err)
+	}
+}
+
+// cleanAttachmentFilename strips any directory from an uploaded filename
+func cleanAttachmentFilename(name string) (string, error) {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("%w: filename is required", domain.ErrInvalidInput)
+	}
+	if len(name) > maxAttachmentFilename {
+		return "", fmt.Errorf("%w: filename exceeds %d bytes", domain.ErrInvalidInput, maxAttachmentFilename)
+	}
+	return name, nil
+}
+
+// withAttachment returns metadata with id added to the attachments key.
+// metadata itself is not modified.
+func withAttachment(metadata map[string]string, id uuid.UUID) map[string]string {
+	ids := (&domain.Note{Metadata: metadata}).AttachmentIDs()
+	return withAttachmentIDs(metadata, append(ids, id))
+}
+
+// withoutAttachment returns metadata with id removed from the attachments
+// key. metadata itself is not modified.
+func withoutAttachment(metadata map[string]string, id uuid.UUID) map[string]string {
+	ids := (&domain.Note{Metadata: metadata}).AttachmentIDs()
+	return withAttachmentIDs(metadata, slices.DeleteFunc(ids, func(a uuid.UUID) bool { return a == id }))
+}
+
+// withAttachmentIDs returns metadata with the attachments key set to ids, or
+// removed when there are none
+func withAttachmentIDs(metadata map[string]string, ids []uuid.UUID) map[string]string {
+	metadata = maps.Clone(metadata)
+	if len(ids) == 0 {
+		delete(metadata, domain.NoteMetadataAttachments)
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	encoded, _ := json.Marshal(ids) // Cannot fail for UUIDs
+	metadata[domain.NoteMetadataAttachments] = string(encoded)
+	return metadata
+}
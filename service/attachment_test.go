@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// pngHeader is enough of a PNG file for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// memBlobStore is an in-memory BlobStore
+type memBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func newMemBlobStore() *memBlobStore {
+	return &memBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (m *memBlobStore) Put(_ context.Context, key string, content io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = data
+	return nil
+}
+
+func (m *memBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memBlobStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.blobs[key]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.blobs, key)
+	return nil
+}
+
+func TestUploadAndDownloadAttachment(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	blobs := newMemBlobStore()
+	svc.SetAttachmentStore(blobs, domain.AttachmentPolicy{})
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachment, err := svc.UploadAttachment(ctx, domain.UploadAttachmentRequest{
+		OutageID: o.ID, Filename: `C:\Users\alice\latency.png`, UploadedBy: "alice@example.com",
+	}, bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("UploadAttachment() err = %v", err)
+	}
+	if attachment.Filename != "latency.png" || attachment.ContentType != "image/png" || attachment.Size != int64(len(pngHeader)) {
+		t.Errorf("attachment = %+v, want latency.png, image/png, %d bytes", attachment, len(pngHeader))
+	}
+
+	got, content, err := svc.OpenAttachment(ctx, attachment.ID)
+	if err != nil {
+		t.Fatalf("OpenAttachment() err = %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	_ = content.Close()
+	if got.ID != attachment.ID || !bytes.Equal(data, pngHeader) {
+		t.Errorf("OpenAttachment = %+v, %q; want the uploaded PNG", got, data)
+	}
+
+	list, err := svc.ListAttachments(ctx, o.ID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListAttachments = %v, %v; want one attachment", list, err)
+	}
+
+	if err := svc.DeleteAttachment(ctx, attachment.ID); err != nil {
+		t.Fatalf("DeleteAttachment() err = %v", err)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Errorf("blobs = %d after delete, want 0", len(blobs.blobs))
+	}
+	if _, err := svc.GetAttachment(ctx, attachment.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAttachment after delete err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUploadAttachmentReferencedFromNote(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	svc.SetAttachmentStore(newMemBlobStore(), domain.AttachmentPolicy{})
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "error rate graph", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachment, err := svc.UploadAttachment(ctx, domain.UploadAttachmentRequest{
+		OutageID: o.ID, NoteID: &note.ID, Filename: "errors.log",
+	}, strings.NewReader("500 Internal Server Error\n"))
+	if err != nil {
+		t.Fatalf("UploadAttachment() err = %v", err)
+	}
+
+	note, err = svc.GetNote(ctx, note.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := note.AttachmentIDs(); len(ids) != 1 || ids[0] != attachment.ID {
+		t.Errorf("AttachmentIDs = %v, want [%s]", ids, attachment.ID)
+	}
+
+	// Replacing metadata keeps the attachment reference
+	content := "error rate graph, see log"
+	note, err = svc.UpdateNote(ctx, note.ID, &content, nil, map[string]string{"source": "web"}, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := note.AttachmentIDs(); len(ids) != 1 || note.Metadata["source"] != "web" {
+		t.Errorf("metadata after update = %v, want source and the attachment", note.Metadata)
+	}
+
+	if err := svc.DeleteAttachment(ctx, attachment.ID); err != nil {
+		t.Fatal(err)
+	}
+	note, err = svc.GetNote(ctx, note.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := note.Metadata[domain.NoteMetadataAttachments]; ok {
+		t.Errorf("metadata after attachment delete = %v, want no attachments key", note.Metadata)
+	}
+}
+
+func TestUploadAttachmentRejects(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	svc.SetAttachmentStore(newMemBlobStore(), domain.AttachmentPolicy{MaxBytes: 32})
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "other", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherNote, err := svc.AddNote(ctx, other.ID, domain.AddNoteRequest{Content: "n", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		req     domain.UploadAttachmentRequest
+		content string
+		want    error
+	}{
+		{"too large", domain.UploadAttachmentRequest{OutageID: o.ID, Filename: "big.log"}, strings.Repeat("x", 33), domain.ErrAttachmentTooLarge},
+		{"disallowed type", domain.UploadAttachmentRequest{OutageID: o.ID, Filename: "page.html"}, "<html><body>hi</body></html>", domain.ErrInvalidInput},
+		{"empty", domain.UploadAttachmentRequest{OutageID: o.ID, Filename: "empty.log"}, "", domain.ErrInvalidInput},
+		{"no filename", domain.UploadAttachmentRequest{OutageID: o.ID}, "log", domain.ErrInvalidInput},
+		{"note on another outage", domain.UploadAttachmentRequest{OutageID: o.ID, NoteID: &otherNote.ID, Filename: "a.log"}, "log", domain.ErrInvalidInput},
+		{"unknown outage", domain.UploadAttachmentRequest{OutageID: uuid.New(), Filename: "a.log"}, "log", domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.UploadAttachment(ctx, tt.req, strings.NewReader(tt.content)); !errors.Is(err, tt.want) {
+				t.Errorf("UploadAttachment() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachmentsDisabledWithoutStore(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.UploadAttachment(ctx, domain.UploadAttachmentRequest{OutageID: o.ID, Filename: "a.log"}, strings.NewReader("log"))
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UploadAttachment() err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// serviceChangeKind is the kind of the changes a catalogue sync reports
+const serviceChangeKind = "service"
+
+// ServiceCatalogue is an external service catalogue, such as a YAML file or
+// Backstage, that services are synced from
+type ServiceCatalogue interface {
+	// Name identifies the catalogue, and is recorded as the source of the
+	// services synced from it
+	Name() string
+	// FetchServices lists every service in the catalogue. Timestamps and
+	// source are ignored.
+	FetchServices(ctx context.Context) ([]domain.Service, error)
+}
+
+// RegisterServiceCatalogue adds a catalogue for SyncServices to copy
+// services from
+func (s *Service) RegisterServiceCatalogue(c ServiceCatalogue) {
+	s.serviceCatalogues = append(s.serviceCatalogues, c)
+}
+
+// SyncServices copies the services of every registered catalogue into the
+// service catalogue, and returns the changes it made. Synced services
+// record their source, and are updated or deleted as the catalogue's
+// listing changes. Services managed through the API are never overwritten:
+// a catalogue service with the same name as one of them is skipped, as are
+// catalogue services with invalid fields. A catalogue whose listing fails
+// is logged and left unchanged.
+func (s *Service) SyncServices(ctx context.Context) (*domain.OpsConfigPlan, error) {
+	ctx, span := tracer.Start(ctx, "Service.SyncServices")
+	defer span.End()
+
+	existing, err := s.storage.ListServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*domain.Service, len(existing))
+	for _, svc := range existing {
+		current[svc.Name] = svc
+	}
+
+	plan := &domain.OpsConfigPlan{Changes: []domain.ConfigChange{}}
+	now := time.Now()
+	for _, catalogue := range s.serviceCatalogues {
+		source := catalogue.Name()
+		fetched, err := catalogue.FetchServices(ctx)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to fetch services", "source", source, "error", err)
+			continue
+		}
+
+		listed := make(map[string]bool, len(fetched))
+		for _, svc := range fetched {
+			if err := checkServiceFields(&svc); err != nil {
+				s.logger.WarnContext(ctx, "invalid catalogue service; not synced", "service", svc.Name, "source", source, "error", err)
+				continue
+			}
+			if listed[svc.Name] {
+				continue
+			}
+			old, ok := current[svc.Name]
+			if ok && old.Source != source {
+				s.logger.DebugContext(ctx, "service already managed elsewhere; not synced", "service", svc.Name, "source", source, "managed_by", old.Source)
+				continue
+			}
+			listed[svc.Name] = true
+
+			svc.Source = source
+			svc.UpdatedAt = now
+			action := domain.ConfigCreate
+			if ok {
+				if sameService(old, &svc) {
+					continue
+				}
+				svc.CreatedAt = old.CreatedAt
+				action = domain.ConfigUpdate
+				err = s.storage.UpdateService(ctx, &svc)
+			} else {
+				svc.CreatedAt = now
+				err = s.storage.CreateService(ctx, &svc)
+			}
+			if err != nil {
+				return nil, err
+			}
+			current[svc.Name] = &svc
+			plan.Changes = append(plan.Changes, domain.ConfigChange{Action: action, Kind: serviceChangeKind, Name: svc.Name})
+		}
+
+		for _, svc := range existing {
+			if svc.Source != source || listed[svc.Name] {
+				continue
+			}
+			if err := s.storage.DeleteService(ctx, svc.Name); err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return nil, err
+			}
+			plan.Changes = append(plan.Changes, domain.ConfigChange{Action: domain.ConfigDelete, Kind: serviceChangeKind, Name: svc.Name})
+		}
+	}
+
+	plan.Applied = true
+	if len(plan.Changes) > 0 {
+		s.logger.InfoContext(ctx, "services synced", "changes", len(plan.Changes))
+	}
+	return plan, nil
+}
+
+// sameService reports whether two versions of a service differ only in
+// their timestamps
+func sameService(a, b *domain.Service) bool {
+	x, y := *a, *b
+	x.CreatedAt, x.UpdatedAt = time.Time{}, time.Time{}
+	y.CreatedAt, y.UpdatedAt = time.Time{}, time.Time{}
+	return x == y
+}
+
+// checkServiceFields trims a service's fields and checks its name is
+// present, its tier is not negative and its links are web URLs
+func checkServiceFields(svc *domain.Service) error {
+	svc.Name = strings.TrimSpace(svc.Name)
+	if svc.Name == "" {
+		return fmt.Errorf("service name is required: %w", domain.ErrInvalidInput)
+	}
+	svc.Description = strings.TrimSpace(svc.Description)
+	svc.OwningTeam = strings.TrimSpace(svc.OwningTeam)
+	svc.RunbookURL = strings.TrimSpace(svc.RunbookURL)
+	svc.Repo = strings.TrimSpace(svc.Repo)
+	svc.StatuspageComponent = strings.TrimSpace(svc.StatuspageComponent)
+	if svc.Tier < 0 {
+		return fmt.Errorf("tier must not be negative: %w", domain.ErrInvalidInput)
+	}
+	if err := checkWebURL("runbook_url", svc.RunbookURL); err != nil {
+		return err
+	}
+	return checkWebURL("repo", svc.Repo)
+}
+
+// checkWebURL checks a link, if set, is an absolute http or https URL
+func checkWebURL(field, link string) error {
+	if link == "" {
+		return nil
+	}
+	if u, err := url.Parse(link); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s %q is not an http or https URL: %w", field, link, domain.ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// fakeCatalogue is a service catalogue listing services, or failing with
+// err
+type fakeCatalogue struct {
+	name     string
+	services []domain.Service
+	err      error
+}
+
+func (f *fakeCatalogue) Name() string { return f.name }
+
+func (f *fakeCatalogue) FetchServices(context.Context) ([]domain.Service, error) {
+	return f.services, f.err
+}
+
+func TestSaveServiceValidation(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		svc  domain.Service
+	}{
+		{"no name", domain.Service{Name: " "}},
+		{"unknown team", domain.Service{Name: "checkout", OwningTeam: "search"}},
+		{"negative tier", domain.Service{Name: "checkout", Tier: -1}},
+		{"relative runbook", domain.Service{Name: "checkout", RunbookURL: "/runbooks/checkout"}},
+		{"non-web repo", domain.Service{Name: "checkout", Repo: "git@github.com:example/checkout.git"}},
+	}
+	for _, tt := range tests {
+		if _, _, err := svc.SaveService(ctx, tt.svc); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: error = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+
+	saved, created, err := svc.SaveService(ctx, domain.Service{
+		Name: "checkout", OwningTeam: "payments", Tier: 1, Source: "backstage",
+		RunbookURL: " https://runbooks.example.com/checkout ",
+	})
+	if err != nil || !created {
+		t.Fatalf("SaveService = %v, created %v", err, created)
+	}
+	if saved.Source != "" || saved.RunbookURL != "https://runbooks.example.com/checkout" {
+		t.Errorf("saved = %+v, want an API-managed service with the runbook trimmed", saved)
+	}
+}
+
+func TestSyncServices(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, _, err := svc.SaveService(ctx, domain.Service{Name: "search", Description: "Managed locally"}); err != nil {
+		t.Fatal(err)
+	}
+	backstage := &fakeCatalogue{name: "backstage", services: []domain.Service{
+		{Name: "checkout", OwningTeam: "payments", Tier: 1, RunbookURL: "https://runbooks.example.com/checkout"},
+		{Name: "search", Description: "From Backstage"},
+		{Name: "billing", RunbookURL: "not a url"},
+		{Name: "ledger"},
+	}}
+	failing := &fakeCatalogue{name: "file", err: errors.New("unreadable")}
+	svc.RegisterServiceCatalogue(failing)
+	svc.RegisterServiceCatalogue(backstage)
+
+	plan, err := svc.SyncServices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.ConfigChange{
+		{Action: domain.ConfigCreate, Kind: "service", Name: "checkout"},
+		{Action: domain.ConfigCreate, Kind: "service", Name: "ledger"},
+	}
+	if !plan.Applied || !slices.Equal(plan.Changes, want) {
+		t.Errorf("plan = %+v, want %+v", plan, want)
+	}
+	if local, err := svc.GetService(ctx, "search"); err != nil || local.Description != "Managed locally" || local.Source != "" {
+		t.Errorf("local service = %+v, %v, want it left alone", local, err)
+	}
+	checkout, err := svc.GetService(ctx, "checkout")
+	if err != nil || checkout.Source != "backstage" || checkout.OwningTeam != "payments" {
+		t.Fatalf("synced service = %+v, %v, want it owned by payments and sourced from backstage", checkout, err)
+	}
+	if _, _, err := svc.SaveService(ctx, domain.Service{Name: "checkout"}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("SaveService of a synced service error = %v, want ErrConflict", err)
+	}
+
+	// Unchanged services are left alone; changed ones are updated and
+	// vanished ones deleted
+	if plan, err := svc.SyncServices(ctx); err != nil || len(plan.Changes) != 0 {
+		t.Errorf("second sync = %+v, %v, want no changes", plan, err)
+	}
+	backstage.services = []domain.Service{{Name: "checkout", OwningTeam: "payments", Tier: 2}}
+	plan, err = svc.SyncServices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []domain.ConfigChange{
+		{Action: domain.ConfigUpdate, Kind: "service", Name: "checkout"},
+		{Action: domain.ConfigDelete, Kind: "service", Name: "ledger"},
+	}
+	if !slices.Equal(plan.Changes, want) {
+		t.Errorf("plan = %+v, want %+v", plan.Changes, want)
+	}
+	if updated, err := svc.GetService(ctx, "checkout"); err != nil || updated.Tier != 2 || !updated.CreatedAt.Equal(checkout.CreatedAt) {
+		t.Errorf("updated service = %+v, %v, want tier 2 and the original creation time", updated, err)
+	}
+}
+
+func TestRoutingToService(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	rules := domain.OpsConfig{RoutingRules: []domain.RoutingRule{
+		{Name: "checkout", Match: domain.RoutingMatch{Metadata: map[string]string{"service_name": "checkout-api"}}, Service: "checkout"},
+	}}
+	if _, err := svc.ApplyOpsConfig(ctx, rules, false, false); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("rule naming an uncatalogued service error = %v, want ErrInvalidInput", err)
+	}
+	if _, _, err := svc.SaveService(ctx, domain.Service{Name: "checkout", OwningTeam: "payments"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ApplyOpsConfig(ctx, rules, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := json.Marshal(notification.Alert{
+		ExternalID: "A1", Source: "fake", Title: "5xx", Severity: "high", TriggeredAt: time.Now(),
+		SourceMetadata: map[string]any{"service_name": "checkout-api"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	alert, err := svc.GetAlertByExternalID(ctx, "A1", "fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.Service != "checkout" {
+		t.Errorf("alert service = %q, want checkout", alert.Service)
+	}
+	outage, err := svc.GetOutage(ctx, alert.OutageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outage.OwningTeam != "payments" || !slices.Equal(outage.AffectedServices, []string{"checkout"}) {
+		t.Errorf("outage owner %q, affected %v, want the service's owner and the service", outage.OwningTeam, outage.AffectedServices)
+	}
+
+	// Alerts can be relinked by hand, to catalogued services only
+	unknown, none := "search", ""
+	if _, err := svc.UpdateAlert(ctx, alert.ID, domain.UpdateAlertRequest{Service: &unknown}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateAlert to an uncatalogued service error = %v, want ErrInvalidInput", err)
+	}
+	if updated, err := svc.UpdateAlert(ctx, alert.ID, domain.UpdateAlertRequest{Service: &none}); err != nil || updated.Service != "" {
+		t.Errorf("UpdateAlert unlinking = %+v, %v, want no service", updated, err)
+	}
+}
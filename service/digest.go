@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// digestPageSize is the number of outages read per page when building a
+// digest
+const digestPageSize = 200
+
+// digestTrendPeriods is how many periods the MTTR trend in a digest covers
+const digestTrendPeriods = 4
+
+// digestSendWindow is how late a digest may be sent. A digest whose send
+// time passed longer ago, for example while the server was down, is
+// skipped rather than sent late.
+const digestSendWindow = time.Hour
+
+// defaultDigestTemplate renders a domain.Digest as plain text that reads
+// well in both email and Slack
+const defaultDigestTemplate = `{{if .Team}}{{.Team}}: {{end}}{{.Period}} outage digest for {{date .Since}} to {{date .Until}}
+
+Open outages ({{len .Open}}):
+{{- range .Open}}
+• [{{.Severity}}] {{.Title}} ({{.Status}} since {{date .CreatedAt}})
+{{- else}}
+• None
+{{- end}}
+
+New outages ({{len .New}}):
+{{- range .New}}
+• [{{.Severity}}] {{.Title}} ({{.Status}}, opened {{date .CreatedAt}})
+{{- else}}
+• None
+{{- end}}
+
+Outages missing a postmortem ({{len .MissingPostmortems}}):
+{{- range .MissingPostmortems}}
+• {{.Title}} (resolved {{with .ResolvedAt}}{{date .}}{{end}}, {{.ReviewStatus}})
+{{- else}}
+• None
+{{- end}}
+
+Mean time to resolve:
+{{- range .MTTR}}
+• {{date .Since}} to {{date .Until}}: {{if .Resolved}}{{duration .MTTRSeconds}} over {{.Resolved}} outage(s){{else}}nothing resolved{{end}}
+{{- end}}
+`
+
+// DigestMessage is a rendered digest and where it should be sent
+type DigestMessage struct {
+	Digest       *domain.Digest
+	Subject      string
+	Text         string
+	Emails       []string
+	SlackChannel string
+}
+
+// DigestNotifier delivers scheduled digests. Notifiers skip messages with
+// no recipients of their kind.
+type DigestNotifier interface {
+	SendDigest(ctx context.Context, msg DigestMessage) error
+}
+
+// digestSchedule is a validated domain.DigestSchedule
+type digestSchedule struct {
+	domain.DigestSchedule
+	weekday  time.Weekday
+	location *time.Location
+	template *template.Template
+}
+
+// RegisterDigestNotifier adds a notifier that is called for every digest
+// sent
+func (s *Service) RegisterDigestNotifier(n DigestNotifier) {
+	s.digestNotifiers = append(s.digestNotifiers, n)
+}
+
+// SetDigestSchedules installs the digests sent by SendDigests. Names must
+// be unique, periods daily or weekly, hours 0-23, and weekdays, time zones
+// and templates valid.
+func (s *Service) SetDigestSchedules(schedules []domain.DigestSchedule) error {
+	parsed := make([]digestSchedule, 0, len(schedules))
+	names := make(map[string]bool, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.Name == "" {
+			return fmt.Errorf("digest name is required: %w", domain.ErrInvalidInput)
+		}
+		if names[schedule.Name] {
+			return fmt.Errorf("duplicate digest %q: %w", schedule.Name, domain.ErrInvalidInput)
+		}
+		names[schedule.Name] = true
+		if _, err := digestLength(schedule.Period); err != nil {
+			return fmt.Errorf("digest %q: %w", schedule.Name, err)
+		}
+		if schedule.Hour < 0 || schedule.Hour > 23 {
+			return fmt.Errorf("digest %q hour must be 0-23: %w", schedule.Name, domain.ErrInvalidInput)
+		}
+
+		d := digestSchedule{DigestSchedule: schedule, weekday: time.Monday, location: time.UTC}
+		if schedule.Weekday != "" {
+			weekday, ok := parseWeekday(schedule.Weekday)
+			if !ok {
+				return fmt.Errorf("digest %q has unknown weekday %q: %w", schedule.Name, schedule.Weekday, domain.ErrInvalidInput)
+			}
+			d.weekday = weekday
+		}
+		if schedule.Timezone != "" {
+			loc, err := time.LoadLocation(schedule.Timezone)
+			if err != nil {
+				return fmt.Errorf("digest %q has unknown time zone %q: %w", schedule.Name, schedule.Timezone, domain.ErrInvalidInput)
+			}
+			d.location = loc
+		}
+		text := schedule.Template
+		if text == "" {
+			text = defaultDigestTemplate
+		}
+		tmpl, err := template.New(schedule.Name).Funcs(digestFuncs(d.location)).Parse(text)
+		if err != nil {
+			return fmt.Errorf("digest %q template: %v: %w", schedule.Name, err, domain.ErrInvalidInput)
+		}
+		d.template = tmpl
+		parsed = append(parsed, d)
+	}
+	s.digestSchedules = parsed
+	return nil
+}
+
+// BuildDigest summarises the outages of a team, or of every team when team
+// is empty, over the daily or weekly period ending at until
+func (s *Service) BuildDigest(ctx context.Context, team, period string, until time.Time) (*domain.Digest, error) {
+	ctx, span := tracer.Start(ctx, "Service.BuildDigest")
+	defer span.End()
+
+	length, err := digestLength(period)
+	if err != nil {
+		return nil, err
+	}
+	since := until.Add(-length)
+	digest := &domain.Digest{
+		Team:               team,
+		Period:             period,
+		Since:              since,
+		Until:              until,
+		Open:               []domain.DigestOutage{},
+		New:                []domain.DigestOutage{},
+		MissingPostmortems: []domain.DigestOutage{},
+		MTTR:               make([]domain.MTTRPeriod, digestTrendPeriods),
+	}
+	for i := range digest.MTTR {
+		end := until.Add(-time.Duration(digestTrendPeriods-1-i) * length)
+		digest.MTTR[i] = domain.MTTRPeriod{Since: end.Add(-length), Until: end}
+	}
+	resolveTotals := make([]time.Duration, digestTrendPeriods)
+
+	reviews, err := s.storage.ListOutageReviews(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	unreviewed := make(map[uuid.UUID]string, len(reviews))
+	for _, review := range reviews {
+		if review.Status != domain.ReviewReviewed {
+			unreviewed[review.OutageID] = review.Status
+		}
+	}
+
+	for offset := 0; ; offset += digestPageSize {
+		var outages []*domain.Outage
+		if team != "" {
+			outages, err = s.storage.ListOutagesByTeams(ctx, []string{team}, digestPageSize, offset, false)
+		} else {
+			outages, err = s.storage.ListOutages(ctx, digestPageSize, offset, false)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list outages: %w", err)
+		}
+		for _, outage := range outages {
+			if !outage.CreatedAt.Before(until) {
+				continue
+			}
+			summary := digestOutage(outage)
+			if !isResolved(outage.Status) {
+				digest.Open = append(digest.Open, summary)
+			}
+			if !outage.CreatedAt.Before(since) {
+				digest.New = append(digest.New, summary)
+			}
+			if status, ok := unreviewed[outage.ID]; ok {
+				summary.ReviewStatus = status
+				digest.MissingPostmortems = append(digest.MissingPostmortems, summary)
+			}
+			if outage.ResolvedAt != nil {
+				for i, p := range digest.MTTR {
+					if !outage.ResolvedAt.Before(p.Since) && outage.ResolvedAt.Before(p.Until) {
+						digest.MTTR[i].Resolved++
+						resolveTotals[i] += outage.ResolvedAt.Sub(outage.CreatedAt)
+					}
+				}
+			}
+		}
+		if len(outages) < digestPageSize {
+			break
+		}
+	}
+
+	for i, p := range digest.MTTR {
+		if p.Resolved > 0 {
+			digest.MTTR[i].MTTRSeconds = int64((resolveTotals[i] / time.Duration(p.Resolved)).Seconds())
+		}
+	}
+	for _, list := range [][]domain.DigestOutage{digest.Open, digest.New, digest.MissingPostmortems} {
+		sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	}
+	return digest, nil
+}
+
+// SendDigests sends every scheduled digest whose send time passed within
+// digestSendWindow of now and has not been sent yet, returning how many
+// were sent. Delivery is best effort: notifier failures are logged.
+func (s *Service) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "Service.SendDigests")
+	defer span.End()
+
+	if len(s.digestNotifiers) == 0 {
+		return 0, nil
+	}
+
+	sent := 0
+	for _, schedule := range s.digestSchedules {
+		due := schedule.lastSendTime(now)
+		if now.Sub(due) >= digestSendWindow || !s.sentDigests.claim(schedule.Name, due) {
+			continue
+		}
+
+		msg, err := s.digestMessage(ctx, schedule, due)
+		if err != nil {
+			return sent, err
+		}
+		for _, n := range s.digestNotifiers {
+			if err := n.SendDigest(ctx, *msg); err != nil {
+				s.logger.WarnContext(ctx, "failed to send digest", "digest", schedule.Name, "error", err)
+			}
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// digestMessage builds and renders a scheduled digest and addresses it to
+// the schedule's recipients or, when it has none, to its team
+func (s *Service) digestMessage(ctx context.Context, schedule digestSchedule, until time.Time) (*DigestMessage, error) {
+	digest, err := s.BuildDigest(ctx, schedule.Team, schedule.Period, until)
+	if err != nil {
+		return nil, err
+	}
+	digest.Name = schedule.Name
+
+	var text strings.Builder
+	if err := schedule.template.Execute(&text, digest); err != nil {
+		return nil, fmt.Errorf("failed to render digest %q: %w", schedule.Name, err)
+	}
+
+	scope := "all teams"
+	if schedule.Team != "" {
+		scope = schedule.Team
+	}
+	msg := &DigestMessage{
+		Digest:       digest,
+		Subject:      fmt.Sprintf("Outage digest (%s, %s): %d open, %d new", scope, schedule.Period, len(digest.Open), len(digest.New)),
+		Text:         text.String(),
+		Emails:       schedule.Emails,
+		SlackChannel: schedule.SlackChannel,
+	}
+	if schedule.Team == "" || len(msg.Emails) > 0 || msg.SlackChannel != "" {
+		return msg, nil
+	}
+
+	var team domain.Team
+	found, err := s.configResource(ctx, domain.ResourceTeam, schedule.Team, &team)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to load team for digest", "digest", schedule.Name, "team", schedule.Team, "error", err)
+		return msg, nil
+	}
+	if found {
+		msg.Emails = team.Members
+		msg.SlackChannel = team.SlackChannel
+	}
+	return msg, nil
+}
+
+// lastSendTime returns the most recent time at or before now that the
+// digest is scheduled to be sent
+func (d digestSchedule) lastSendTime(now time.Time) time.Time {
+	local := now.In(d.location)
+	due := time.Date(local.Year(), local.Month(), local.Day(), d.Hour, 0, 0, 0, d.location)
+	if d.Period == domain.DigestWeekly {
+		due = due.AddDate(0, 0, -((int(local.Weekday()) - int(d.weekday) + 7) % 7))
+	}
+	if due.After(now) {
+		if d.Period == domain.DigestWeekly {
+			due = due.AddDate(0, 0, -7)
+		} else {
+			due = due.AddDate(0, 0, -1)
+		}
+	}
+	return due
+}
+
+// digestLength returns the length of a digest period
+func digestLength(period string) (time.Duration, error) {
+	switch period {
+	case domain.DigestDaily:
+		return 24 * time.Hour, nil
+	case domain.DigestWeekly:
+		return 7 * 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("digest period must be daily or weekly, not %q: %w", period, domain.ErrInvalidInput)
+}
+
+// parseWeekday parses a weekday name such as "monday" or "Mon"
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(name)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// digestOutage summarises an outage for a digest
+func digestOutage(outage *domain.Outage) domain.DigestOutage {
+	return domain.DigestOutage{
+		ID:         outage.ID,
+		Title:      outage.Title,
+		Severity:   outage.Severity,
+		Status:     outage.Status,
+		OwningTeam: outage.OwningTeam,
+		CreatedAt:  outage.CreatedAt,
+		ResolvedAt: outage.ResolvedAt,
+	}
+}
+
+// digestFuncs are the functions available to digest templates: date
+// formats a time in the digest's time zone and duration formats a number
+// of seconds
+func digestFuncs(loc *time.Location) template.FuncMap {
+	return template.FuncMap{
+		"date": func(t time.Time) string {
+			return t.In(loc).Format("Mon 2 Jan 15:04 MST")
+		},
+		"duration": func(seconds int64) string {
+			return (time.Duration(seconds) * time.Second).String()
+		},
+	}
+}
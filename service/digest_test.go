@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// recordingDigestNotifier records the digests sent through it
+type recordingDigestNotifier struct {
+	sent []DigestMessage
+}
+
+func (r *recordingDigestNotifier) SendDigest(_ context.Context, msg DigestMessage) error {
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+func TestBuildDigest(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}, {Name: "search"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	create := func(title, team string) *domain.Outage {
+		t.Helper()
+		outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: "high", OwningTeam: team})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return outage
+	}
+
+	open := create("Card errors", "payments")
+	resolved := create("Checkout slow", "payments")
+	if _, err := svc.TransitionOutage(ctx, resolved.ID, domain.TransitionRequest{Action: "resolve"}); err != nil {
+		t.Fatal(err)
+	}
+	create("Search down", "search")
+
+	digest, err := svc.BuildDigest(ctx, "payments", domain.DigestWeekly, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digest.Open) != 1 || digest.Open[0].ID != open.ID {
+		t.Errorf("open = %+v, want only the open payments outage", digest.Open)
+	}
+	if len(digest.New) != 2 {
+		t.Errorf("new = %+v, want both payments outages", digest.New)
+	}
+	if len(digest.MissingPostmortems) != 1 || digest.MissingPostmortems[0].ID != resolved.ID ||
+		digest.MissingPostmortems[0].ReviewStatus != domain.ReviewNeedsReview {
+		t.Errorf("missing postmortems = %+v, want the resolved outage awaiting review", digest.MissingPostmortems)
+	}
+	if len(digest.MTTR) != digestTrendPeriods {
+		t.Fatalf("mttr = %+v, want %d periods", digest.MTTR, digestTrendPeriods)
+	}
+	if last := digest.MTTR[digestTrendPeriods-1]; last.Resolved != 1 || !last.Until.Equal(digest.Until) {
+		t.Errorf("last mttr period = %+v, want one resolved outage", last)
+	}
+	if first := digest.MTTR[0]; first.Resolved != 0 || !first.Until.Equal(digest.Until.Add(-21*24*time.Hour)) {
+		t.Errorf("first mttr period = %+v", first)
+	}
+
+	all, err := svc.BuildDigest(ctx, "", domain.DigestDaily, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Open) != 2 {
+		t.Errorf("open across teams = %d, want 2", len(all.Open))
+	}
+
+	if _, err := svc.BuildDigest(ctx, "", "monthly", time.Now()); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("BuildDigest(monthly) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{
+		Name: "payments", Members: []string{"bob@example.com"}, SlackChannel: "#payments",
+	}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Card errors", Severity: "high", OwningTeam: "payments"}); err != nil {
+		t.Fatal(err)
+	}
+	notifier := &recordingDigestNotifier{}
+	svc.RegisterDigestNotifier(notifier)
+
+	// Schedule both digests for the start of an hour after the outage
+	due := time.Now().UTC().Add(2 * time.Hour).Truncate(time.Hour)
+	if err := svc.SetDigestSchedules([]domain.DigestSchedule{
+		{Name: "payments-weekly", Team: "payments", Period: domain.DigestWeekly, Weekday: due.Weekday().String(), Hour: due.Hour()},
+		{Name: "ops-daily", Period: domain.DigestDaily, Hour: due.Hour(), Emails: []string{"ops@example.com"},
+			Template: "{{len .Open}} open"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := due.Add(30 * time.Minute)
+	sent, err := svc.SendDigests(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 2 || len(notifier.sent) != 2 {
+		t.Fatalf("sent %d digests (%d delivered), want 2", sent, len(notifier.sent))
+	}
+	byName := map[string]DigestMessage{}
+	for _, msg := range notifier.sent {
+		byName[msg.Digest.Name] = msg
+	}
+	weekly := byName["payments-weekly"]
+	if weekly.SlackChannel != "#payments" || len(weekly.Emails) != 1 || weekly.Emails[0] != "bob@example.com" {
+		t.Errorf("weekly digest addressed to %v and %q, want the team's members and channel", weekly.Emails, weekly.SlackChannel)
+	}
+	if !strings.Contains(weekly.Text, "Open outages (1)") || !strings.Contains(weekly.Subject, "payments") {
+		t.Errorf("weekly digest = %q: %q", weekly.Subject, weekly.Text)
+	}
+	if daily := byName["ops-daily"]; daily.Text != "1 open" || daily.SlackChannel != "" {
+		t.Errorf("daily digest = %+v, want the custom template and only its own recipients", daily)
+	}
+
+	// Each send time is used once, and late digests are skipped
+	if sent, _ := svc.SendDigests(ctx, now.Add(10*time.Minute)); sent != 0 {
+		t.Errorf("sent %d digests again in the same period", sent)
+	}
+	if sent, _ := svc.SendDigests(ctx, now.Add(24*time.Hour+2*time.Hour)); sent != 0 {
+		t.Errorf("sent %d digests more than the send window late", sent)
+	}
+	if sent, _ := svc.SendDigests(ctx, now.Add(24*time.Hour)); sent != 1 {
+		t.Errorf("sent %d digests the next day, want only the daily one", sent)
+	}
+}
+
+func TestSetDigestSchedules(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule domain.DigestSchedule
+	}{
+		{"missing name", domain.DigestSchedule{Period: domain.DigestDaily}},
+		{"unknown period", domain.DigestSchedule{Name: "x", Period: "monthly"}},
+		{"hour out of range", domain.DigestSchedule{Name: "x", Period: domain.DigestDaily, Hour: 24}},
+		{"unknown weekday", domain.DigestSchedule{Name: "x", Period: domain.DigestWeekly, Weekday: "someday"}},
+		{"unknown time zone", domain.DigestSchedule{Name: "x", Period: domain.DigestDaily, Timezone: "Mars/Olympus"}},
+		{"invalid template", domain.DigestSchedule{Name: "x", Period: domain.DigestDaily, Template: "{{.Open"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := newSvc().SetDigestSchedules([]domain.DigestSchedule{tt.schedule}); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("SetDigestSchedules() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+
+	dup := domain.DigestSchedule{Name: "x", Period: domain.DigestDaily}
+	if err := newSvc().SetDigestSchedules([]domain.DigestSchedule{dup, dup}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("duplicate names error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestDigestLastSendTime(t *testing.T) {
+	dublin, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		t.Skip("time zone data unavailable")
+	}
+	weekly := digestSchedule{DigestSchedule: domain.DigestSchedule{Period: domain.DigestWeekly, Hour: 9}, weekday: time.Monday, location: dublin}
+	daily := digestSchedule{DigestSchedule: domain.DigestSchedule{Period: domain.DigestDaily, Hour: 9}, weekday: time.Monday, location: time.UTC}
+
+	tests := []struct {
+		name     string
+		schedule digestSchedule
+		now      time.Time
+		want     time.Time
+	}{
+		// 2024-07-08 is a Monday; Dublin is UTC+1 in summer
+		{"weekly on the day", weekly, time.Date(2024, 7, 8, 8, 30, 0, 0, time.UTC), time.Date(2024, 7, 8, 8, 0, 0, 0, time.UTC)},
+		{"weekly before the hour", weekly, time.Date(2024, 7, 8, 7, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)},
+		{"weekly later in the week", weekly, time.Date(2024, 7, 11, 12, 0, 0, 0, time.UTC), time.Date(2024, 7, 8, 8, 0, 0, 0, time.UTC)},
+		{"daily before the hour", daily, time.Date(2024, 7, 8, 8, 0, 0, 0, time.UTC), time.Date(2024, 7, 7, 9, 0, 0, 0, time.UTC)},
+		{"daily after the hour", daily, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC), time.Date(2024, 7, 8, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.lastSendTime(tt.now); !got.Equal(tt.want) {
+				t.Errorf("lastSendTime(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/fingerprint"
+	"github.com/conall/outalator/validation"
+	"github.com/google/uuid"
+)
+
+// exportPageSize is how many outages are read from storage at a time while
+// exporting
+const exportPageSize = 100
+
+// ExportOutages calls fn with each outage in the query's range, in the
+// order storage lists them, with its alerts, notes and tags loaded. Outages in the trash are
+// left out. Outages are loaded a page at a time, so callers can stream them
+// without holding the whole export in memory; an error from fn stops the
+// export and is returned.
+func (s *Service) ExportOutages(ctx context.Context, q domain.OutageExportQuery, fn func(*domain.Outage) error) error {
+	ctx, span := tracer.Start(ctx, "Service.ExportOutages")
+	defer span.End()
+
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return fmt.Errorf("%w: since must be before until", domain.ErrInvalidInput)
+	}
+
+	// Outages created while exporting shift later pages, so remember what
+	// has been written rather than repeat an outage
+	seen := make(map[uuid.UUID]bool)
+	for offset := 0; ; offset += exportPageSize {
+		page, err := s.storage.ListOutages(ctx, exportPageSize, offset, false)
+		if err != nil {
+			return fmt.Errorf("failed to list outages: %w", err)
+		}
+		var outages []*domain.Outage
+		for _, outage := range page {
+			if !q.Until.IsZero() && !outage.CreatedAt.Before(q.Until) {
+				continue
+			}
+			if !q.Since.IsZero() && outage.CreatedAt.Before(q.Since) {
+				continue
+			}
+			if seen[outage.ID] {
+				continue
+			}
+			seen[outage.ID] = true
+			outages = append(outages, outage)
+		}
+		if err := s.storage.LoadOutageAssociations(ctx, outages, domain.AllOutageAssociations); err != nil {
+			return err
+		}
+		for _, outage := range outages {
+			if err := fn(outage); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+// ImportOutages adds the outages in an export, keeping their IDs and
+// timestamps. Every outage is checked before anything is written, so an
+// invalid document imports nothing. Outages that already exist are
+// skipped, as are alerts already tracked from the same source. Imported
+// outages do not notify anyone and their owning teams are kept as written.
+func (s *Service) ImportOutages(ctx context.Context, export domain.OutageExport) (*domain.OutageImportResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.ImportOutages")
+	defer span.End()
+
+	if export.Version < 1 || export.Version > domain.OutageExportVersion {
+		return nil, fmt.Errorf("%w: unsupported export version %d", domain.ErrInvalidInput, export.Version)
+	}
+	for i, outage := range export.Outages {
+		if err := checkImportedOutage(outage); err != nil {
+			return nil, fmt.Errorf("outage %d: %w", i, err)
+		}
+	}
+
+	// Children reference their parent, so top-level outages go first
+	outages := slices.Clone(export.Outages)
+	slices.SortStableFunc(outages, func(a, b *domain.Outage) int {
+		return boolOrder(a.ParentID != nil) - boolOrder(b.ParentID != nil)
+	})
+	result := &domain.OutageImportResult{}
+	for _, outage := range outages {
+		if err := s.importOutage(ctx, outage, result); err != nil {
+			return result, err
+		}
+	}
+
+	s.logger.InfoContext(ctx, "outages imported",
+		"outages", result.Outages, "skipped", result.SkippedOutages,
+		"alerts", result.Alerts, "notes", result.Notes, "tags", result.Tags)
+	return result, nil
+}
+
+// checkImportedOutage validates an outage from an export, including that
+// every reply's parent note is part of the same outage
+func checkImportedOutage(outage *domain.Outage) error {
+	if outage == nil {
+		return fmt.Errorf("%w: outage is null", domain.ErrInvalidInput)
+	}
+	if err := checkTitle(outage.Title); err != nil {
+		return err
+	}
+	if err := checkDescription(outage.Description); err != nil {
+		return err
+	}
+	if err := checkStatus(outage.Status); err != nil {
+		return err
+	}
+	if err := validation.ValidateMetadata(outage.Metadata); err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+	if err := validation.ValidateCustomFields(outage.CustomFields); err != nil {
+		return fmt.Errorf("invalid custom_fields: %w", err)
+	}
+
+	for _, alert := range outage.Alerts {
+		if alert.Source == "" || alert.ExternalID == "" {
+			return fmt.Errorf("%w: alerts need a source and external_id", domain.ErrInvalidInput)
+		}
+	}
+
+	noteIDs := make(map[uuid.UUID]bool, len(outage.Notes))
+	for _, note := range outage.Notes {
+		noteIDs[note.ID] = true
+	}
+	for _, note := range outage.Notes {
+		if note.ParentNoteID != nil && !noteIDs[*note.ParentNoteID] {
+			return fmt.Errorf("%w: note %s replies to a note that is not in the outage", domain.ErrInvalidInput, note.ID)
+		}
+	}
+
+	for _, note := range outage.Notes {
+		if note.Format == "" {
+			continue // Imported as plaintext
+		}
+		if err := checkNoteFormat(note.Format); err != nil {
+			return fmt.Errorf("note %s: %w", note.ID, err)
+		}
+	}
+
+	for _, tag := range outage.Tags {
+		if err := checkTagInput(tag.Key, tag.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importOutage writes one checked outage with its alerts, notes and tags
+func (s *Service) importOutage(ctx context.Context, outage *domain.Outage, result *domain.OutageImportResult) error {
+	if outage.ID == uuid.Nil {
+		outage.ID = uuid.New()
+	} else if _, err := s.storage.GetOutage(ctx, outage.ID); err == nil {
+		result.SkippedOutages++
+		return nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	if outage.CreatedAt.IsZero() {
+		outage.CreatedAt = now
+	}
+	if outage.UpdatedAt.IsZero() {
+		outage.UpdatedAt = outage.CreatedAt
+	}
+	outage.DeletedAt = nil
+	outage.Rollup = nil
+	if outage.ParentID != nil {
+		// A parent left out of the export imports the child as top-level
+		if _, err := s.storage.GetOutageWith(ctx, *outage.ParentID, domain.OutageAssociations{}); errors.Is(err, domain.ErrNotFound) {
+			outage.ParentID = nil
+		} else if err != nil {
+			return err
+		}
+	}
+	if err := s.storage.CreateOutage(ctx, outage); err != nil {
+		return fmt.Errorf("failed to import outage %s: %w", outage.ID, err)
+	}
+	result.Outages++
+
+	for i := range outage.Alerts {
+		alert := &outage.Alerts[i]
+		if _, err := s.storage.GetAlertByExternalID(ctx, alert.ExternalID, alert.Source); err == nil {
+			result.SkippedAlerts++
+			continue
+		} else if !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if alert.ID == uuid.Nil {
+			alert.ID = uuid.New()
+		}
+		alert.OutageID = outage.ID
+		if alert.CreatedAt.IsZero() {
+			alert.CreatedAt = now
+		}
+		if alert.Fingerprint == "" {
+			alert.Fingerprint = fingerprint.Alert(alert.Source, alert.Title)
+		}
+		if err := s.storage.CreateAlert(ctx, alert); err != nil {
+			return fmt.Errorf("failed to import alert %s: %w", alert.ID, err)
+		}
+		result.Alerts++
+	}
+
+	// Replies reference their parent, so top-level notes go first
+	notes := slices.Clone(outage.Notes)
+	slices.SortStableFunc(notes, func(a, b domain.Note) int {
+		return boolOrder(a.ParentNoteID != nil) - boolOrder(b.ParentNoteID != nil)
+	})
+	for i := range notes {
+		note := &notes[i]
+		if note.ID == uuid.Nil {
+			note.ID = uuid.New()
+		}
+		note.OutageID = outage.ID
+		note.DeletedAt = nil
+		if note.Format == "" {
+			note.Format = domain.NoteFormatPlaintext
+		}
+		if note.CreatedAt.IsZero() {
+			note.CreatedAt = now
+		}
+		if note.UpdatedAt.IsZero() {
+			note.UpdatedAt = note.CreatedAt
+		}
+		if err := s.storage.CreateNote(ctx, note); err != nil {
+			return fmt.Errorf("failed to import note %s: %w", note.ID, err)
+		}
+		result.Notes++
+	}
+
+	for i := range outage.Tags {
+		tag := &outage.Tags[i]
+		if tag.ID == uuid.Nil {
+			tag.ID = uuid.New()
+		}
+		tag.OutageID = outage.ID
+		if tag.CreatedAt.IsZero() {
+			tag.CreatedAt = now
+		}
+		if err := s.storage.CreateTag(ctx, tag); err != nil {
+			return fmt.Errorf("failed to import tag %s: %w", tag.ID, err)
+		}
+		result.Tags++
+	}
+	return nil
+}
+
+// boolOrder sorts false before true
+func boolOrder(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// exportAll collects an export into a document
+func exportAll(t *testing.T, svc *Service, q domain.OutageExportQuery) domain.OutageExport {
+	t.Helper()
+	export := domain.OutageExport{Version: domain.OutageExportVersion, ExportedAt: time.Now()}
+	err := svc.ExportOutages(context.Background(), q, func(outage *domain.Outage) error {
+		export.Outages = append(export.Outages, outage)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return export
+}
+
+func TestExportImportOutages(t *testing.T) {
+	src := newSvc()
+	ctx := context.Background()
+
+	outage, err := src.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "critical",
+		Tags: []domain.TagInput{{Key: "jira", Value: "OPS-1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := src.storeAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "pagerduty", Title: "db down", TriggeredAt: time.Now()}, &outage.ID); err != nil {
+		t.Fatal(err)
+	}
+	root, err := src.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "Failing over", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "Done", Author: "bob", ParentNoteID: &root.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Slow search", Severity: "low"}); err != nil {
+		t.Fatal(err)
+	}
+	trashed, err := src.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Typo", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.DeleteOutage(ctx, trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	export := exportAll(t, src, domain.OutageExportQuery{})
+	if len(export.Outages) != 2 {
+		t.Fatalf("exported %d outages, want 2 without the trashed one", len(export.Outages))
+	}
+	if got := exportAll(t, src, domain.OutageExportQuery{Since: time.Now().Add(time.Hour)}); len(got.Outages) != 0 {
+		t.Errorf("exported %d outages created after since, want 0", len(got.Outages))
+	}
+	if got := exportAll(t, src, domain.OutageExportQuery{Until: time.Now().Add(time.Hour)}); len(got.Outages) != 2 {
+		t.Errorf("exported %d outages created before until, want 2", len(got.Outages))
+	}
+	err = src.ExportOutages(ctx, domain.OutageExportQuery{Since: time.Now(), Until: time.Now().Add(-time.Hour)}, func(*domain.Outage) error { return nil })
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("ExportOutages(since after until) error = %v, want ErrInvalidInput", err)
+	}
+
+	dst := newSvc()
+	result, err := dst.ImportOutages(ctx, export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.OutageImportResult{Outages: 2, Alerts: 1, Notes: 2, Tags: 1}
+	if *result != want {
+		t.Errorf("import result = %+v, want %+v", *result, want)
+	}
+	imported, err := dst.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Title != "DB down" || !imported.CreatedAt.Equal(outage.CreatedAt) {
+		t.Errorf("imported outage = %+v", imported)
+	}
+	if len(imported.Alerts) != 1 || imported.Alerts[0].ExternalID != "A1" {
+		t.Errorf("imported alerts = %+v", imported.Alerts)
+	}
+	if len(imported.Tags) != 1 || imported.Tags[0].Value != "OPS-1" {
+		t.Errorf("imported tags = %+v", imported.Tags)
+	}
+	threads, _, err := dst.ListNoteThreadsPage(ctx, outage.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 1 || threads[0].Note.ID != root.ID || len(threads[0].Replies) != 1 {
+		t.Errorf("imported threads = %+v, want the reply under its parent", threads)
+	}
+
+	// Importing again adds nothing
+	again, err := dst.ImportOutages(ctx, export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *again != (domain.OutageImportResult{SkippedOutages: 2}) {
+		t.Errorf("second import result = %+v, want both outages skipped", *again)
+	}
+}
+
+func TestImportOutagesRejectsInvalid(t *testing.T) {
+	orphan := uuid.New()
+	tests := []struct {
+		name   string
+		export domain.OutageExport
+	}{
+		{"unknown version", domain.OutageExport{Version: domain.OutageExportVersion + 1}},
+		{"missing version", domain.OutageExport{}},
+		{"missing title", domain.OutageExport{Version: 1, Outages: []*domain.Outage{{Status: domain.StatusOpen}}}},
+		{"unknown status", domain.OutageExport{Version: 1, Outages: []*domain.Outage{{Title: "x", Status: "broken"}}}},
+		{"alert without source", domain.OutageExport{Version: 1, Outages: []*domain.Outage{{Title: "x", Status: domain.StatusOpen,
+			Alerts: []domain.Alert{{ExternalID: "A1"}}}}}},
+		{"reply to missing note", domain.OutageExport{Version: 1, Outages: []*domain.Outage{{Title: "x", Status: domain.StatusOpen,
+			Notes: []domain.Note{{ID: uuid.New(), Content: "hi", ParentNoteID: &orphan}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newSvc()
+			// A valid outage ahead of the invalid one must not be written
+			tt.export.Outages = append([]*domain.Outage{{ID: uuid.New(), Title: "ok", Status: domain.StatusOpen}}, tt.export.Outages...)
+			if _, err := svc.ImportOutages(context.Background(), tt.export); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Fatalf("ImportOutages() error = %v, want ErrInvalidInput", err)
+			}
+			if outages, _ := svc.ListOutages(context.Background(), 10, 0); len(outages) != 0 {
+				t.Errorf("imported %d outages from an invalid export", len(outages))
+			}
+		})
+	}
+}
+
+func TestImportOutagesLinksParents(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	parent := &domain.Outage{ID: uuid.New(), Title: "eu-west down", Status: domain.StatusOpen}
+	missing := uuid.New()
+	// Children come ahead of their parent, and one names a parent left out
+	// of the export
+	child := &domain.Outage{ID: uuid.New(), Title: "checkout down", Status: domain.StatusOpen, ParentID: &parent.ID}
+	orphan := &domain.Outage{ID: uuid.New(), Title: "search down", Status: domain.StatusOpen, ParentID: &missing}
+
+	export := domain.OutageExport{Version: domain.OutageExportVersion, Outages: []*domain.Outage{child, orphan, parent}}
+	if _, err := svc.ImportOutages(ctx, export); err != nil {
+		t.Fatalf("ImportOutages() error = %v", err)
+	}
+	got, err := svc.GetOutage(ctx, child.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ParentID == nil || *got.ParentID != parent.ID {
+		t.Errorf("imported child ParentID = %v, want %s", got.ParentID, parent.ID)
+	}
+	if got, err = svc.GetOutage(ctx, orphan.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got.ParentID != nil {
+		t.Errorf("imported orphan ParentID = %s, want nil", got.ParentID)
+	}
+}
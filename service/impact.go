@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// impactPageSize is the number of outages read per page when building a
+// downtime report
+const impactPageSize = 200
+
+// ListServices lists the service catalogue ordered by name
+func (s *Service) ListServices(ctx context.Context) ([]*domain.Service, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListServices")
+	defer span.End()
+
+	services, err := s.storage.ListServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if services == nil {
+		services = []*domain.Service{}
+	}
+	return services, nil
+}
+
+// GetService retrieves a catalogued service by name
+func (s *Service) GetService(ctx context.Context, name string) (*domain.Service, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetService")
+	defer span.End()
+
+	return s.storage.GetService(ctx, name)
+}
+
+// SaveService adds svc to the catalogue or replaces the catalogued service
+// of the same name, reporting whether it was added. Services saved this
+// way are managed through the API; services synced from a catalogue
+// cannot be replaced, as the next sync would undo the change.
+func (s *Service) SaveService(ctx context.Context, svc domain.Service) (*domain.Service, bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.SaveService")
+	defer span.End()
+
+	svc.Source = ""
+	if err := checkServiceFields(&svc); err != nil {
+		return nil, false, err
+	}
+	if err := s.checkOwningTeam(ctx, svc.OwningTeam); err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	svc.UpdatedAt = now
+	existing, err := s.storage.GetService(ctx, svc.Name)
+	if err == nil && existing.Source != "" {
+		return nil, false, fmt.Errorf("service %q is synced from the %s catalogue; change it there: %w", svc.Name, existing.Source, domain.ErrConflict)
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		svc.CreatedAt = now
+		if err := s.storage.CreateService(ctx, &svc); err != nil {
+			return nil, false, err
+		}
+		return &svc, true, nil
+	case err != nil:
+		return nil, false, err
+	}
+	svc.CreatedAt = existing.CreatedAt
+	if err := s.storage.UpdateService(ctx, &svc); err != nil {
+		return nil, false, err
+	}
+	return &svc, false, nil
+}
+
+// DeleteService removes a service from the catalogue. Outages already
+// naming it keep it as an affected service. A service synced from a
+// catalogue is added again by the next sync unless removed there too.
+func (s *Service) DeleteService(ctx context.Context, name string) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteService")
+	defer span.End()
+
+	return s.storage.DeleteService(ctx, name)
+}
+
+// GetServiceDowntime reports how long each catalogued service was affected
+// by outages between q.Since and q.Until. An outage affects its services
+// for its impact window, clipped to the range; outages in the trash are
+// left out. q.OwningTeam and q.MaxTier limit which services are reported;
+// untiered services are left out once a tier is given.
+func (s *Service) GetServiceDowntime(ctx context.Context, q domain.ServiceDowntimeQuery) (*domain.ServiceDowntime, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetServiceDowntime")
+	defer span.End()
+
+	if !q.Until.After(q.Since) {
+		return nil, fmt.Errorf("until must be after since: %w", domain.ErrInvalidInput)
+	}
+	if q.MaxTier < 0 {
+		return nil, fmt.Errorf("tier must not be negative: %w", domain.ErrInvalidInput)
+	}
+	catalogue, err := s.storage.ListServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	services := catalogue[:0]
+	for _, svc := range catalogue {
+		if (q.OwningTeam == "" || svc.OwningTeam == q.OwningTeam) &&
+			(q.MaxTier == 0 || (svc.Tier > 0 && svc.Tier <= q.MaxTier)) {
+			services = append(services, svc)
+		}
+	}
+
+	type serviceImpact struct {
+		entry   domain.ServiceDowntimeEntry
+		windows []impactInterval
+	}
+	impacts := make(map[string]*serviceImpact, len(services))
+	for _, svc := range services {
+		impacts[svc.Name] = &serviceImpact{entry: domain.ServiceDowntimeEntry{
+			Service:    svc.Name,
+			OwningTeam: svc.OwningTeam,
+			Tier:       svc.Tier,
+		}}
+	}
+
+	now := time.Now()
+	for offset := 0; ; offset += impactPageSize {
+		outages, err := s.storage.ListOutages(ctx, impactPageSize, offset, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list outages: %w", err)
+		}
+		for _, outage := range outages {
+			if len(outage.AffectedServices) == 0 || (q.CustomerImpact && !outage.CustomerImpact) {
+				continue
+			}
+			start, end := outage.ImpactWindow(now)
+			if start.Before(q.Since) {
+				start = q.Since
+			}
+			if end.After(q.Until) {
+				end = q.Until
+			}
+			if !end.After(start) {
+				continue
+			}
+			for _, name := range outage.AffectedServices {
+				impact, ok := impacts[name]
+				if !ok {
+					// Removed from the catalogue since, or filtered out
+					continue
+				}
+				impact.entry.Outages++
+				if outage.CustomerImpact {
+					impact.entry.CustomerImpact++
+				}
+				impact.windows = append(impact.windows, impactInterval{start, end})
+			}
+		}
+		if len(outages) < impactPageSize {
+			break
+		}
+	}
+
+	report := &domain.ServiceDowntime{
+		Since:          q.Since,
+		Until:          q.Until,
+		CustomerImpact: q.CustomerImpact,
+		Services:       make([]domain.ServiceDowntimeEntry, 0, len(services)),
+	}
+	length := q.Until.Sub(q.Since)
+	for _, svc := range services {
+		impact := impacts[svc.Name]
+		downtime := mergedLength(impact.windows)
+		impact.entry.DowntimeSeconds = int64(downtime / time.Second)
+		impact.entry.Availability = 1 - float64(downtime)/float64(length)
+		report.Services = append(report.Services, impact.entry)
+	}
+	sort.SliceStable(report.Services, func(i, j int) bool {
+		return report.Services[i].DowntimeSeconds > report.Services[j].DowntimeSeconds
+	})
+	return report, nil
+}
+
+// impactInterval is a span of time a service was affected
+type impactInterval struct {
+	start, end time.Time
+}
+
+// mergedLength returns the time covered by intervals, counting overlaps
+// once
+func mergedLength(intervals []impactInterval) time.Duration {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+	var total time.Duration
+	var current impactInterval
+	for i, iv := range intervals {
+		if i > 0 && !iv.start.After(current.end) {
+			if iv.end.After(current.end) {
+				current.end = iv.end
+			}
+			continue
+		}
+		total += current.end.Sub(current.start)
+		current = iv
+	}
+	return total + current.end.Sub(current.start)
+}
+
+// checkAffectedServices removes duplicate and blank names from services
+// and checks the rest are catalogued
+func (s *Service) checkAffectedServices(ctx context.Context, services []string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool, len(services))
+	for _, name := range services {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, err := s.storage.GetService(ctx, name); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, fmt.Errorf("unknown affected service %q: %w", name, domain.ErrInvalidInput)
+			}
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// checkService trims a service name and checks it is catalogued. An empty
+// name is allowed, for unlinking.
+func (s *Service) checkService(ctx context.Context, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	if _, err := s.storage.GetService(ctx, name); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", fmt.Errorf("unknown service %q: %w", name, domain.ErrInvalidInput)
+		}
+		return "", err
+	}
+	return name, nil
+}
+
+// checkImpactWindow checks an impact window does not end before it starts
+func checkImpactWindow(start, end *time.Time) error {
+	if start != nil && end != nil && end.Before(*start) {
+		return fmt.Errorf("impact_ended_at is before impact_started_at: %w", domain.ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestOutageImpactValidation(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, _, err := svc.SaveService(ctx, domain.Service{Name: "checkout"}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	tests := []struct {
+		name string
+		req  domain.CreateOutageRequest
+	}{
+		{"unknown service", domain.CreateOutageRequest{Title: "t", Severity: "low", AffectedServices: []string{"checkout", "search"}}},
+		{"ends before it starts", domain.CreateOutageRequest{Title: "t", Severity: "low", ImpactStartedAt: &end, ImpactEndedAt: &start}},
+	}
+	for _, tt := range tests {
+		if _, err := svc.CreateOutage(ctx, tt.req); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: error = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "t", Severity: "low", AffectedServices: []string{"checkout", " checkout", ""}, ImpactStartedAt: &start,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outage.AffectedServices) != 1 || outage.AffectedServices[0] != "checkout" {
+		t.Errorf("affected services = %q, want checkout once", outage.AffectedServices)
+	}
+
+	early := start.Add(-time.Minute)
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{ImpactEndedAt: &early}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("ending impact before it started: error = %v, want ErrInvalidInput", err)
+	}
+	impact, none := true, []string{}
+	updated, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{CustomerImpact: &impact, AffectedServices: &none, ImpactEndedAt: &end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.CustomerImpact || updated.AffectedServices != nil || updated.ImpactEndedAt == nil || !updated.ImpactEndedAt.Equal(end) {
+		t.Errorf("updated outage = %+v, want customer impact, no services and the impact end", updated)
+	}
+}
+
+func TestGetServiceDowntime(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []domain.Service{
+		{Name: "checkout", OwningTeam: "payments", Tier: 1},
+		{Name: "search", Tier: 2},
+		{Name: "web"},
+	} {
+		if _, _, err := svc.SaveService(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	at := func(hours float64) *time.Time {
+		t := since.Add(time.Duration(hours * float64(time.Hour)))
+		return &t
+	}
+	create := func(customer bool, start, end *time.Time, services ...string) {
+		t.Helper()
+		if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+			Title: "t", Severity: "high", AffectedServices: services, CustomerImpact: customer,
+			ImpactStartedAt: start, ImpactEndedAt: end,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	create(true, at(1), at(3), "checkout", "web")
+	create(false, at(2), at(4), "checkout")
+	create(true, at(-2), at(1), "search")  // Started before the range
+	create(true, at(30), at(31), "search") // After the range
+
+	report, err := svc.GetServiceDowntime(ctx, domain.ServiceDowntimeQuery{Since: since, Until: until})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.ServiceDowntimeEntry{
+		{Service: "checkout", OwningTeam: "payments", Tier: 1, Outages: 2, CustomerImpact: 1, DowntimeSeconds: 3 * 3600, Availability: 1 - 3.0/24},
+		{Service: "web", Outages: 1, CustomerImpact: 1, DowntimeSeconds: 2 * 3600, Availability: 1 - 2.0/24},
+		{Service: "search", Tier: 2, Outages: 1, CustomerImpact: 1, DowntimeSeconds: 3600, Availability: 1 - 1.0/24},
+	}
+	if len(report.Services) != len(want) {
+		t.Fatalf("services = %+v, want %+v", report.Services, want)
+	}
+	for i, got := range report.Services {
+		if got != want[i] {
+			t.Errorf("services[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	report, err = svc.GetServiceDowntime(ctx, domain.ServiceDowntimeQuery{Since: since, Until: until, CustomerImpact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Services[0]; got.Service != "checkout" || got.Outages != 1 || got.DowntimeSeconds != 2*3600 {
+		t.Errorf("customer-impacting downtime = %+v, want the one customer-impacting outage", got)
+	}
+
+	for _, q := range []domain.ServiceDowntimeQuery{
+		{Since: since, Until: until, OwningTeam: "payments"},
+		{Since: since, Until: until, MaxTier: 1},
+	} {
+		report, err = svc.GetServiceDowntime(ctx, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Services) != 1 || report.Services[0].Service != "checkout" {
+			t.Errorf("downtime for %+v = %+v, want only checkout", q, report.Services)
+		}
+	}
+
+	if _, err := svc.GetServiceDowntime(ctx, domain.ServiceDowntimeQuery{Since: until, Until: since}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("reversed range: error = %v, want ErrInvalidInput", err)
+	}
+}
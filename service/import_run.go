@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// GetImportRun retrieves a historical import run, with its progress and
+// statistics
+func (s *Service) GetImportRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetImportRun")
+	defer span.End()
+
+	return s.storage.GetImportRun(ctx, id)
+}
+
+// ListImportRuns lists historical import runs, optionally only those from
+// provider, most recently started first
+func (s *Service) ListImportRuns(ctx context.Context, provider string) ([]*domain.ImportRun, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListImportRuns")
+	defer span.End()
+
+	return s.storage.ListImportRuns(ctx, provider)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// Limits on user input, in characters. Titles and tags match the database
+// column sizes, which migration 028 backs with checks on the values.
+const (
+	maxTitleLength       = 255
+	maxDescriptionLength = 65536
+	maxNoteLength        = 65536
+	maxTagKeyLength      = 100
+	maxTagValueLength    = 255
+)
+
+// maxTagQueryTerms bounds the tag subqueries one search runs
+const maxTagQueryTerms = 20
+
+// checkOutageInput validates the fields of a new outage. Every caller that
+// creates outages from user input, whether over REST, gRPC, Slack or MCP,
+// goes through CreateOutage and so through this check. An empty severity is
+// allowed and leaves the outage unclassified.
+func checkOutageInput(title, description, severity string) error {
+	if err := checkTitle(title); err != nil {
+		return err
+	}
+	if err := checkDescription(description); err != nil {
+		return err
+	}
+	if severity != "" {
+		return checkSeverity(severity)
+	}
+	return nil
+}
+
+// checkTitle requires a title that is not blank and fits in the title column
+func checkTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("title is required: %w", domain.ErrInvalidInput)
+	}
+	if n := utf8.RuneCountInString(title); n > maxTitleLength {
+		return fmt.Errorf("title is %d characters, longer than %d: %w", n, maxTitleLength, domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkDescription limits the length of an outage description
+func checkDescription(description string) error {
+	if n := utf8.RuneCountInString(description); n > maxDescriptionLength {
+		return fmt.Errorf("description is %d characters, longer than %d: %w", n, maxDescriptionLength, domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkSeverity rejects severities other than the Outalator severities.
+// Outages opened from alerts the severity mapping has no entry for are left
+// unclassified instead, see notification.OutageSeverity.
+func checkSeverity(severity string) error {
+	if !slices.Contains(notification.Severities, severity) {
+		return fmt.Errorf("unknown severity %q (want one of %s): %w",
+			severity, strings.Join(notification.Severities, ", "), domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkStatus rejects statuses that are not part of the outage lifecycle
+func checkStatus(status string) error {
+	if !slices.Contains(domain.OutageStatuses, status) {
+		return fmt.Errorf("unknown status %q (want one of %s): %w",
+			status, strings.Join(domain.OutageStatuses, ", "), domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkNoteInput requires note content within the length limit and a
+// known format
+func checkNoteInput(content, format string) error {
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("note content is required: %w", domain.ErrInvalidInput)
+	}
+	if n := utf8.RuneCountInString(content); n > maxNoteLength {
+		return fmt.Errorf("note content is %d characters, longer than %d: %w", n, maxNoteLength, domain.ErrInvalidInput)
+	}
+	return checkNoteFormat(format)
+}
+
+// checkTagInput requires a tag key, and a key and value that fit in their
+// columns
+func checkTagInput(key, value string) error {
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("tag key is required: %w", domain.ErrInvalidInput)
+	}
+	if n := utf8.RuneCountInString(key); n > maxTagKeyLength {
+		return fmt.Errorf("tag key is %d characters, longer than %d: %w", n, maxTagKeyLength, domain.ErrInvalidInput)
+	}
+	if n := utf8.RuneCountInString(value); n > maxTagValueLength {
+		return fmt.Errorf("tag %q value is %d characters, longer than %d: %w", key, n, maxTagValueLength, domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkTagQuery requires between one and maxTagQueryTerms terms, each with
+// a key
+func checkTagQuery(q domain.TagQuery) error {
+	if len(q.Terms) == 0 {
+		return fmt.Errorf("tag query has no terms: %w", domain.ErrInvalidInput)
+	}
+	if len(q.Terms) > maxTagQueryTerms {
+		return fmt.Errorf("tag query has %d terms, more than %d: %w", len(q.Terms), maxTagQueryTerms, domain.ErrInvalidInput)
+	}
+	for _, term := range q.Terms {
+		if strings.TrimSpace(term.Key) == "" {
+			return fmt.Errorf("tag query term has no key: %w", domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// alertOutageTitle is the title of an outage opened for an alert: the
+// alert's title, cut to fit, or its source and ID when it has none
+func alertOutageTitle(alert *notification.Alert) string {
+	title := strings.TrimSpace(alert.Title)
+	if title == "" {
+		return fmt.Sprintf("%s alert %s", alert.Source, alert.ExternalID)
+	}
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		title = string([]rune(title)[:maxTitleLength])
+	}
+	return title
+}
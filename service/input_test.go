@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func TestCreateOutageValidatesInput(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	for _, tc := range []struct {
+		name string
+		req  domain.CreateOutageRequest
+	}{
+		{"blank title", domain.CreateOutageRequest{Title: "  ", Severity: "high"}},
+		{"long title", domain.CreateOutageRequest{Title: strings.Repeat("x", maxTitleLength+1)}},
+		{"long description", domain.CreateOutageRequest{Title: "t", Description: strings.Repeat("x", maxDescriptionLength+1)}},
+		{"unknown severity", domain.CreateOutageRequest{Title: "t", Severity: "sev1"}},
+		{"tag without key", domain.CreateOutageRequest{Title: "t", Tags: []domain.TagInput{{Value: "api"}}}},
+		{"long tag value", domain.CreateOutageRequest{Title: "t", Tags: []domain.TagInput{{Key: "service", Value: strings.Repeat("x", maxTagValueLength+1)}}}},
+	} {
+		if _, err := svc.CreateOutage(ctx, tc.req); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want ErrInvalidInput", tc.name, err)
+		}
+	}
+
+	// Titles are limited in characters, not bytes
+	if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: strings.Repeat("é", maxTitleLength)}); err != nil {
+		t.Errorf("title of %d two-byte characters: %v", maxTitleLength, err)
+	}
+}
+
+func TestUpdatesValidateInput(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "Failing over", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blank, severity, status := "", "urgent", "paused"
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Title: &blank}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateOutage blank title err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Severity: &severity}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateOutage severity %q err = %v, want ErrInvalidInput", severity, err)
+	}
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &status}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateOutage status %q err = %v, want ErrInvalidInput", status, err)
+	}
+
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: " \n", Author: "alice"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddNote blank content err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "x", Format: "html", Author: "alice"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddNote format html err = %v, want ErrInvalidInput", err)
+	}
+	long := strings.Repeat("x", maxNoteLength+1)
+	if _, err := svc.UpdateNote(ctx, note.ID, &long, nil, nil, nil, "alice"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateNote long content err = %v, want ErrInvalidInput", err)
+	}
+
+	if _, err := svc.AddTag(ctx, outage.ID, "", "api"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddTag without key err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.AddTag(ctx, outage.ID, strings.Repeat("k", maxTagKeyLength+1), "api"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddTag long key err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestAlertOutageTitle(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	alert, _, err := svc.storeAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "pagerduty", TriggeredAt: time.Now()}, nil)
+	if err != nil {
+		t.Fatalf("storeAlert() err = %v", err)
+	}
+	outage, err := svc.GetOutage(ctx, alert.OutageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outage.Title != "pagerduty alert A1" {
+		t.Errorf("title of outage for untitled alert = %q, want %q", outage.Title, "pagerduty alert A1")
+	}
+
+	long := &notification.Alert{Title: strings.Repeat("x", maxTitleLength+10)}
+	if got := alertOutageTitle(long); len(got) != maxTitleLength {
+		t.Errorf("alertOutageTitle(long) is %d characters, want %d", len(got), maxTitleLength)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+
+	"github.com/conall/outalator/domain"
+)
+
+// OutageListener is told about outage changes after they are stored, so
+// integrations can mirror them elsewhere. Listeners run synchronously in the
+// request that made the change; errors are logged and never fail it.
+type OutageListener interface {
+	// OutageCreated is called after an outage is created, whether by request
+	// or by an alert that matched no open outage
+	OutageCreated(ctx context.Context, outage *domain.Outage) error
+	// NoteAdded is called after a note is added to outage
+	NoteAdded(ctx context.Context, outage *domain.Outage, note *domain.Note) error
+	// AlertAdded is called after an alert is attached to an existing outage.
+	// Alerts that open a new outage are reported by OutageCreated instead.
+	AlertAdded(ctx context.Context, outage *domain.Outage, alert *domain.Alert) error
+	// OutageStatusChanged is called after outage's status changes from
+	// previous
+	OutageStatusChanged(ctx context.Context, outage *domain.Outage, previous string) error
+	// OutageResolved is called when an outage moves to resolved or closed
+	// from an unresolved status, after OutageStatusChanged
+	OutageResolved(ctx context.Context, outage *domain.Outage) error
+}
+
+// RegisterOutageListener adds a listener for outage changes
+func (s *Service) RegisterOutageListener(l OutageListener) {
+	s.outageListeners = append(s.outageListeners, l)
+}
+
+func (s *Service) notifyOutageCreated(ctx context.Context, outage *domain.Outage) {
+	for _, l := range s.outageListeners {
+		if err := l.OutageCreated(ctx, outage); err != nil {
+			s.logger.WarnContext(ctx, "outage listener failed", "event", "outage_created",
+				"outage_id", outage.ID, "error", err)
+		}
+	}
+}
+
+func (s *Service) notifyNoteAdded(ctx context.Context, outage *domain.Outage, note *domain.Note) {
+	for _, l := range s.outageListeners {
+		if err := l.NoteAdded(ctx, outage, note); err != nil {
+			s.logger.WarnContext(ctx, "outage listener failed", "event", "note_added",
+				"outage_id", outage.ID, "note_id", note.ID, "error", err)
+		}
+	}
+	s.notifyWatchers(ctx, WatchEvent{Outage: outage, Note: note}, note.Author)
+}
+
+func (s *Service) notifyAlertAdded(ctx context.Context, outage *domain.Outage, alert *domain.Alert) {
+	for _, l := range s.outageListeners {
+		if err := l.AlertAdded(ctx, outage, alert); err != nil {
+			s.logger.WarnContext(ctx, "outage listener failed", "event", "alert_added",
+				"outage_id", outage.ID, "alert_id", alert.ID, "error", err)
+		}
+	}
+}
+
+// notifyOutageStatusChanged tells listeners and watchers about a status
+// change. actor, who made the change, is not notified as a watcher.
+func (s *Service) notifyOutageStatusChanged(ctx context.Context, outage *domain.Outage, previous, actor string) {
+	for _, l := range s.outageListeners {
+		if err := l.OutageStatusChanged(ctx, outage, previous); err != nil {
+			s.logger.WarnContext(ctx, "outage listener failed", "event", "outage_status_changed",
+				"outage_id", outage.ID, "error", err)
+		}
+	}
+	s.notifyWatchers(ctx, WatchEvent{Outage: outage, PreviousStatus: previous}, actor)
+}
+
+func (s *Service) notifyOutageResolved(ctx context.Context, outage *domain.Outage) {
+	for _, l := range s.outageListeners {
+		if err := l.OutageResolved(ctx, outage); err != nil {
+			s.logger.WarnContext(ctx, "outage listener failed", "event", "outage_resolved",
+				"outage_id", outage.ID, "error", err)
+		}
+	}
+}
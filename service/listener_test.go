@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// recordingListener records the outage events it is told about
+type recordingListener struct {
+	events []string
+}
+
+func (r *recordingListener) OutageCreated(_ context.Context, outage *domain.Outage) error {
+	r.events = append(r.events, "created:"+outage.Title)
+	return nil
+}
+
+func (r *recordingListener) NoteAdded(_ context.Context, _ *domain.Outage, note *domain.Note) error {
+	r.events = append(r.events, "note:"+note.Content)
+	return nil
+}
+
+func (r *recordingListener) AlertAdded(_ context.Context, _ *domain.Outage, alert *domain.Alert) error {
+	r.events = append(r.events, "alert:"+alert.Title)
+	return nil
+}
+
+func (r *recordingListener) OutageStatusChanged(_ context.Context, outage *domain.Outage, previous string) error {
+	r.events = append(r.events, "status:"+previous+"->"+outage.Status)
+	return nil
+}
+
+func (r *recordingListener) OutageResolved(_ context.Context, outage *domain.Outage) error {
+	r.events = append(r.events, "resolved")
+	return nil
+}
+
+// fakeFetchSource returns a fixed alert from FetchAlert
+type fakeFetchSource struct {
+	fakeWebhookSource
+	alert *notification.Alert
+}
+
+func (f fakeFetchSource) FetchAlert(context.Context, string) (*notification.Alert, error) {
+	return f.alert, nil
+}
+
+func TestOutageListener(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	listener := &recordingListener{}
+	svc.RegisterOutageListener(listener)
+	svc.RegisterNotificationService(fakeFetchSource{alert: &notification.Alert{
+		ExternalID: "A1", Source: "fake", Title: "disk full", Severity: "high", TriggeredAt: time.Now(),
+	}})
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "looking", Format: "plaintext", Author: "alice"}); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	if _, err := svc.ImportAlert(ctx, "fake", "A1", &outage.ID); err != nil {
+		t.Fatalf("ImportAlert: %v", err)
+	}
+	// Updates that leave the status unchanged are not reported
+	title := "renamed"
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Title: &title}); err != nil {
+		t.Fatalf("UpdateOutage: %v", err)
+	}
+	for _, status := range []string{"investigating", "resolved"} {
+		if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &status}); err != nil {
+			t.Fatalf("UpdateOutage(%s): %v", status, err)
+		}
+	}
+
+	want := []string{
+		"created:t",
+		"note:looking",
+		"alert:disk full",
+		"status:open->investigating",
+		"status:investigating->resolved",
+		"resolved",
+	}
+	if len(listener.events) != len(want) {
+		t.Fatalf("events = %v, want %v", listener.events, want)
+	}
+	for i := range want {
+		if listener.events[i] != want[i] {
+			t.Errorf("events[%d] = %q, want %q", i, listener.events[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// mentionPattern matches @name and @user@example.com, but not the domain part
+// of an email address written in the text
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9][\w.+-]*(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// MentionRecipient is who a mention notification should reach
+type MentionRecipient struct {
+	Mention      domain.Mention
+	Emails       []string // The mentioned user, or every member of the mentioned team
+	SlackChannel string   // The mentioned team's channel, if it has one
+}
+
+// MentionNotifier delivers notifications for @mentions in notes
+type MentionNotifier interface {
+	NotifyMention(ctx context.Context, outage *domain.Outage, note *domain.Note, recipient MentionRecipient) error
+}
+
+// RegisterMentionNotifier adds a notifier that is called for every @mention
+// in a new or edited note
+func (s *Service) RegisterMentionNotifier(n MentionNotifier) {
+	s.mentionNotifiers = append(s.mentionNotifiers, n)
+}
+
+// resolveMentions finds the @mentions in content that name a known team or
+// team member. Teams take precedence over users, and users can be mentioned
+// by email address or, when it is unambiguous, by its local part. Unknown
+// names are ignored.
+func (s *Service) resolveMentions(ctx context.Context, content string) ([]MentionRecipient, error) {
+	matches := mentionPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	resources, err := s.storage.ListConfigResources(ctx, domain.ResourceTeam)
+	if err != nil {
+		return nil, err
+	}
+	teams := make(map[string]domain.Team, len(resources))
+	users := make(map[string]string)
+	for _, r := range resources {
+		var team domain.Team
+		if err := json.Unmarshal(r.Spec, &team); err != nil {
+			s.logger.WarnContext(ctx, "invalid team config", "team", r.Name, "error", err)
+			continue
+		}
+		teams[strings.ToLower(team.Name)] = team
+		for _, email := range team.Members {
+			email = strings.ToLower(email)
+			users[email] = email
+			local, _, _ := strings.Cut(email, "@")
+			if existing, ok := users[local]; ok && existing != email {
+				users[local] = "" // Ambiguous, so only the full address resolves
+			} else {
+				users[local] = email
+			}
+		}
+	}
+
+	var recipients []MentionRecipient
+	seen := make(map[domain.Mention]bool)
+	for _, m := range matches {
+		name := strings.ToLower(strings.TrimRight(m[1], ".-"))
+		var r MentionRecipient
+		if team, ok := teams[name]; ok {
+			r = MentionRecipient{
+				Mention:      domain.Mention{Type: domain.MentionTeam, Name: team.Name},
+				Emails:       team.Members,
+				SlackChannel: team.SlackChannel,
+			}
+		} else if email := users[name]; email != "" {
+			r = MentionRecipient{
+				Mention: domain.Mention{Type: domain.MentionUser, Name: email},
+				Emails:  []string{email},
+			}
+		} else {
+			continue
+		}
+		if !seen[r.Mention] {
+			seen[r.Mention] = true
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients, nil
+}
+
+// withMentions returns metadata with the mentions key set to recipients, or
+// removed when there are none. metadata itself is not modified.
+func withMentions(metadata map[string]string, recipients []MentionRecipient) map[string]string {
+	if len(recipients) == 0 {
+		if _, ok := metadata[domain.NoteMetadataMentions]; !ok {
+			return metadata
+		}
+		metadata = maps.Clone(metadata)
+		delete(metadata, domain.NoteMetadataMentions)
+		return metadata
+	}
+
+	mentions := make([]domain.Mention, len(recipients))
+	for i, r := range recipients {
+		mentions[i] = r.Mention
+	}
+	encoded, _ := json.Marshal(mentions) // Cannot fail for plain strings
+
+	metadata = maps.Clone(metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	metadata[domain.NoteMetadataMentions] = string(encoded)
+	return metadata
+}
+
+// notifyMentions sends each recipient to every registered notifier.
+// Notification is best effort: failures are logged and never fail the note
+// write that triggered them.
+func (s *Service) notifyMentions(ctx context.Context, outageID uuid.UUID, note *domain.Note, recipients []MentionRecipient) {
+	if len(recipients) == 0 || len(s.mentionNotifiers) == 0 {
+		return
+	}
+
+	outage, err := s.storage.GetOutage(ctx, outageID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to load outage for mention notifications", "outage_id", outageID, "error", err)
+		return
+	}
+	for _, r := range recipients {
+		for _, n := range s.mentionNotifiers {
+			if err := n.NotifyMention(ctx, outage, note, r); err != nil {
+				s.logger.WarnContext(ctx, "failed to send mention notification",
+					"note_id", note.ID, "mention_type", r.Mention.Type, "mention", r.Mention.Name, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+type recordingNotifier struct {
+	got []MentionRecipient
+}
+
+func (r *recordingNotifier) NotifyMention(_ context.Context, _ *domain.Outage, _ *domain.Note, recipient MentionRecipient) error {
+	r.got = append(r.got, recipient)
+	return nil
+}
+
+func TestAddNote_Mentions(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	notifier := &recordingNotifier{}
+	svc.RegisterMentionNotifier(notifier)
+
+	_, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{
+		{Name: "payments", SlackChannel: "#payments", Members: []string{"alice@example.com", "bob@example.com"}},
+		{Name: "search", Members: []string{"bob@other.example.com"}},
+	}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{
+		Content: "@Payments and @alice, see logs. @bob is ambiguous, @bob@other.example.com is not. @nobody, mail me at carol@example.com",
+		Format:  "plaintext",
+		Author:  "carol",
+	})
+	if err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+
+	want := []domain.Mention{
+		{Type: domain.MentionTeam, Name: "payments"},
+		{Type: domain.MentionUser, Name: "alice@example.com"},
+		{Type: domain.MentionUser, Name: "bob@other.example.com"},
+	}
+	got := note.Mentions()
+	if len(got) != len(want) {
+		t.Fatalf("Mentions() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Mentions()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(notifier.got) != 3 {
+		t.Fatalf("notified %d recipients, want 3", len(notifier.got))
+	}
+	if team := notifier.got[0]; team.SlackChannel != "#payments" || len(team.Emails) != 2 {
+		t.Errorf("team recipient = %+v", team)
+	}
+
+	// Editing notifies only newly mentioned people, and mentions survive a
+	// metadata replacement
+	notifier.got = nil
+	content := note.Content + " cc @search"
+	updated, err := svc.UpdateNote(ctx, note.ID, &content, nil, map[string]string{"source": "edit"}, nil, "")
+	if err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	if len(updated.Mentions()) != 4 || updated.Metadata["source"] != "edit" {
+		t.Errorf("updated metadata = %v", updated.Metadata)
+	}
+	if len(notifier.got) != 1 || notifier.got[0].Mention.Name != "search" {
+		t.Errorf("notified on edit = %+v, want only search", notifier.got)
+	}
+
+	plain, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "no mentions", Format: "plaintext", Author: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plain.Metadata[domain.NoteMetadataMentions]; ok {
+		t.Errorf("note without mentions has metadata %v", plain.Metadata)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/fingerprint"
+	"github.com/google/uuid"
+)
+
+// Number of fingerprints in a noisy alerts report when no limit is given,
+// and the most that can be asked for
+const (
+	defaultNoisyAlertsLimit = 20
+	maxNoisyAlertsLimit     = 100
+)
+
+// GetNoisyAlerts ranks the fingerprints of the alerts triggered in the
+// query's range by how many alerts share them, noisiest first, so teams can
+// find their flappiest monitors. Alerts stored before fingerprinting are
+// fingerprinted as they are read.
+func (s *Service) GetNoisyAlerts(ctx context.Context, q domain.NoisyAlertsQuery) (*domain.NoisyAlerts, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetNoisyAlerts")
+	defer span.End()
+
+	if !q.Since.Before(q.Until) {
+		return nil, fmt.Errorf("since must be before until: %w", domain.ErrInvalidInput)
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultNoisyAlertsLimit
+	}
+	if limit > maxNoisyAlertsLimit {
+		limit = maxNoisyAlertsLimit
+	}
+
+	alerts, err := s.storage.ListAlertsTriggeredBetween(ctx, q.Since, q.Until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	report := &domain.NoisyAlerts{Since: q.Since, Until: q.Until, Alerts: []domain.NoisyAlert{}}
+	type group struct {
+		entry   domain.NoisyAlert
+		outages map[uuid.UUID]bool
+	}
+	groups := make(map[string]*group)
+	for _, a := range alerts {
+		if q.Team != "" && !a.HasTeam(q.Team) {
+			continue
+		}
+		key := a.Fingerprint
+		if key == "" {
+			key = fingerprint.Alert(a.Source, a.Title)
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				entry: domain.NoisyAlert{
+					Fingerprint:      key,
+					Source:           a.Source,
+					FirstTriggeredAt: a.TriggeredAt,
+				},
+				outages: make(map[uuid.UUID]bool),
+			}
+			groups[key] = g
+		}
+		g.entry.Count++
+		g.outages[a.OutageID] = true
+		for _, team := range a.Teams() {
+			if !slices.Contains(g.entry.Teams, team) {
+				g.entry.Teams = append(g.entry.Teams, team)
+			}
+		}
+		if a.TriggeredAt.Before(g.entry.FirstTriggeredAt) {
+			g.entry.FirstTriggeredAt = a.TriggeredAt
+		}
+		if !a.TriggeredAt.Before(g.entry.LastTriggeredAt) {
+			g.entry.LastTriggeredAt = a.TriggeredAt
+			g.entry.Title = a.Title
+		}
+		report.Total++
+	}
+
+	for _, g := range groups {
+		g.entry.Outages = len(g.outages)
+		sort.Strings(g.entry.Teams)
+		report.Alerts = append(report.Alerts, g.entry)
+	}
+	sort.Slice(report.Alerts, func(i, j int) bool {
+		a, b := report.Alerts[i], report.Alerts[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if !a.LastTriggeredAt.Equal(b.LastTriggeredAt) {
+			return a.LastTriggeredAt.After(b.LastTriggeredAt)
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	if len(report.Alerts) > limit {
+		report.Alerts = report.Alerts[:limit]
+	}
+	return report, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/fingerprint"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+func TestGetNoisyAlerts(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	base := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	ingest := func(id, team, title string, at time.Time) *domain.Alert {
+		t.Helper()
+		alert, err := svc.IngestAlert(ctx, &notification.Alert{
+			ExternalID: id, Source: "fake", TeamName: team, Title: title, Severity: "high", TriggeredAt: at,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return alert
+	}
+	first := ingest("a", "storage", "Disk 91% full on db-3.prod.example.com", base)
+	if want := fingerprint.Alert("fake", first.Title); first.Fingerprint != want {
+		t.Errorf("ingested alert fingerprint = %q, want %q", first.Fingerprint, want)
+	}
+	ingest("b", "storage", "Disk 97% full on db-7.prod.example.com", base.Add(time.Hour))
+	ingest("c", "search", "Disk 93% full on search-1.prod.example.com", base.Add(2*time.Hour))
+	ingest("d", "payments", "Checkout latency above 2s", base.Add(3*time.Hour))
+	ingest("e", "payments", "Checkout latency above 2s", base.Add(40*24*time.Hour)) // After the range
+
+	// Alerts stored before fingerprinting are fingerprinted as they are read
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := &domain.Alert{ID: uuid.New(), OutageID: outage.ID, ExternalID: "f", Source: "fake", TeamName: "payments",
+		Title: "Checkout latency above 3s", TriggeredAt: base.Add(4 * time.Hour), CreatedAt: base}
+	if err := svc.storage.CreateAlert(ctx, legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	since, until := base.Add(-time.Hour), base.Add(30*24*time.Hour)
+	report, err := svc.GetNoisyAlerts(ctx, domain.NoisyAlertsQuery{Since: since, Until: until})
+	if err != nil {
+		t.Fatalf("GetNoisyAlerts: %v", err)
+	}
+	if report.Total != 5 || len(report.Alerts) != 2 {
+		t.Fatalf("report = total %d, %+v; want 5 alerts in 2 fingerprints", report.Total, report.Alerts)
+	}
+	disk := report.Alerts[0]
+	if disk.Fingerprint != first.Fingerprint || disk.Count != 3 || disk.Title != "Disk 93% full on search-1.prod.example.com" {
+		t.Errorf("noisiest = %+v, want the 3 disk alerts titled after the latest", disk)
+	}
+	if len(disk.Teams) != 2 || disk.Teams[0] != "search" || disk.Teams[1] != "storage" {
+		t.Errorf("disk teams = %v, want search and storage", disk.Teams)
+	}
+	if !disk.FirstTriggeredAt.Equal(base) || !disk.LastTriggeredAt.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("disk triggered %s to %s", disk.FirstTriggeredAt, disk.LastTriggeredAt)
+	}
+	if latency := report.Alerts[1]; latency.Count != 2 || latency.Outages != 2 {
+		t.Errorf("latency = %+v, want 2 alerts in 2 outages", latency)
+	}
+
+	report, err = svc.GetNoisyAlerts(ctx, domain.NoisyAlertsQuery{Since: since, Until: until, Team: "storage", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 2 || len(report.Alerts) != 1 || report.Alerts[0].Count != 2 {
+		t.Errorf("storage report = total %d, %+v; want the 2 storage disk alerts", report.Total, report.Alerts)
+	}
+
+	if _, err := svc.GetNoisyAlerts(ctx, domain.NoisyAlertsQuery{Since: until, Until: since}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("reversed range error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// teamTagKey is the tag routing rules use to record an outage's team
+const teamTagKey = "team"
+
+// GetOpsConfig returns the stored operational config as a declarative
+// document, in the same shape ApplyOpsConfig accepts
+func (s *Service) GetOpsConfig(ctx context.Context) (*domain.OpsConfig, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetOpsConfig")
+	defer span.End()
+
+	resources, err := s.storage.ListConfigResources(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &domain.OpsConfig{}
+	for _, r := range resources {
+		var err error
+		switch r.Kind {
+		case domain.ResourceTeam:
+			cfg.Teams, err = appendSpec(cfg.Teams, r)
+		case domain.ResourceTagSchema:
+			cfg.TagSchemas, err = appendSpec(cfg.TagSchemas, r)
+		case domain.ResourceRoutingRule:
+			cfg.RoutingRules, err = appendSpec(cfg.RoutingRules, r)
+		case domain.ResourceTemplate:
+			cfg.Templates, err = appendSpec(cfg.Templates, r)
+		case domain.ResourceCustomField:
+			cfg.CustomFields, err = appendSpec(cfg.CustomFields, r)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// ApplyOpsConfig reconciles the stored operational config with desired and
+// returns the changes it made. With dryRun the changes are only planned.
+// Resources missing from desired are deleted only when prune is set, so a
+// document can manage a subset of the config. Teams synced from
+// notification services are left to SyncTeams and never pruned.
+func (s *Service) ApplyOpsConfig(ctx context.Context, desired domain.OpsConfig, dryRun, prune bool) (*domain.OpsConfigPlan, error) {
+	ctx, span := tracer.Start(ctx, "Service.ApplyOpsConfig")
+	defer span.End()
+
+	wanted, err := opsConfigResources(desired)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.storage.ListConfigResources(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]*domain.ConfigResource, len(existing))
+	for _, r := range existing {
+		current[resourceKey(r.Kind, r.Name)] = r
+	}
+
+	// Routing rules may refer to teams that are already stored, unless this
+	// apply prunes them. Synced teams are never pruned.
+	teams := make(map[string]bool)
+	for _, t := range desired.Teams {
+		teams[t.Name] = true
+	}
+	for _, r := range existing {
+		if r.Kind == domain.ResourceTeam && (!prune || isSyncedTeam(r)) {
+			teams[r.Name] = true
+		}
+	}
+	if err := validateOpsConfig(desired, teams); err != nil {
+		return nil, err
+	}
+	if err := s.checkRoutingServices(ctx, desired.RoutingRules); err != nil {
+		return nil, err
+	}
+	if err := s.checkCustomFields(desired.CustomFields); err != nil {
+		return nil, err
+	}
+
+	plan := &domain.OpsConfigPlan{Changes: []domain.ConfigChange{}}
+	var upserts []*domain.ConfigResource
+	for _, r := range wanted {
+		key := resourceKey(r.Kind, r.Name)
+		old, ok := current[key]
+		delete(current, key)
+		switch {
+		case !ok:
+			plan.Changes = append(plan.Changes, domain.ConfigChange{Action: domain.ConfigCreate, Kind: r.Kind, Name: r.Name})
+		case !sameSpec(old.Spec, r.Spec):
+			plan.Changes = append(plan.Changes, domain.ConfigChange{Action: domain.ConfigUpdate, Kind: r.Kind, Name: r.Name})
+		default:
+			continue
+		}
+		upserts = append(upserts, r)
+	}
+
+	var deletes []*domain.ConfigResource
+	if prune {
+		for _, r := range existing {
+			if isSyncedTeam(r) {
+				continue // Owned by SyncTeams
+			}
+			if _, ok := current[resourceKey(r.Kind, r.Name)]; ok {
+				plan.Changes = append(plan.Changes, domain.ConfigChange{Action: domain.ConfigDelete, Kind: r.Kind, Name: r.Name})
+				deletes = append(deletes, r)
+			}
+		}
+	}
+
+	if dryRun || len(plan.Changes) == 0 {
+		return plan, nil
+	}
+
+	now := time.Now()
+	for _, r := range upserts {
+		r.UpdatedAt = now
+		if err := s.storage.UpsertConfigResource(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range deletes {
+		if err := s.storage.DeleteConfigResource(ctx, r.Kind, r.Name); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+	}
+	plan.Applied = true
+	s.logger.InfoContext(ctx, "operational config applied", "changes", len(plan.Changes))
+	return plan, nil
+}
+
+// isSyncedTeam reports whether r is a team synced from a notification
+// service
+func isSyncedTeam(r *domain.ConfigResource) bool {
+	if r.Kind != domain.ResourceTeam {
+		return false
+	}
+	var team domain.Team
+	return json.Unmarshal(r.Spec, &team) == nil && team.Source != ""
+}
+
+// validateOpsConfig checks names, severities and team references. teams
+// holds every team name routing rules may refer to.
+func validateOpsConfig(cfg domain.OpsConfig, teams map[string]bool) error {
+	seen := make(map[string]bool)
+	unique := func(kind, name string) error {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%s name is required: %w", kind, domain.ErrInvalidInput)
+		}
+		if seen[resourceKey(kind, name)] {
+			return fmt.Errorf("duplicate %s %q: %w", kind, name, domain.ErrInvalidInput)
+		}
+		seen[resourceKey(kind, name)] = true
+		return nil
+	}
+
+	for _, t := range cfg.Teams {
+		if err := unique(domain.ResourceTeam, t.Name); err != nil {
+			return err
+		}
+	}
+	for _, ts := range cfg.TagSchemas {
+		if err := unique(domain.ResourceTagSchema, ts.Key); err != nil {
+			return err
+		}
+	}
+	for _, rule := range cfg.RoutingRules {
+		if err := unique(domain.ResourceRoutingRule, rule.Name); err != nil {
+			return err
+		}
+		if err := validateRoutingRule(rule); err != nil {
+			return err
+		}
+		if rule.Team != "" && !teams[rule.Team] {
+			return fmt.Errorf("routing rule %q refers to unknown team %q: %w", rule.Name, rule.Team, domain.ErrInvalidInput)
+		}
+	}
+	for _, tmpl := range cfg.Templates {
+		if err := unique(domain.ResourceTemplate, tmpl.Name); err != nil {
+			return err
+		}
+		if err := validateTemplate(tmpl); err != nil {
+			return err
+		}
+	}
+	for _, f := range cfg.CustomFields {
+		if strings.TrimSpace(f.Name) == "" {
+			return fmt.Errorf("%s name is required: %w", domain.ResourceCustomField, domain.ErrInvalidInput)
+		}
+		if err := unique(domain.ResourceCustomField, f.ResourceName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTagSchema rejects tag values not allowed by the key's tag schema.
+// Keys without a schema accept any value.
+func (s *Service) checkTagSchema(ctx context.Context, key, value string) error {
+	var schema domain.TagSchema
+	found, err := s.configResource(ctx, domain.ResourceTagSchema, key, &schema)
+	if err != nil || !found || len(schema.AllowedValues) == 0 {
+		return err
+	}
+	if !slices.Contains(schema.AllowedValues, value) {
+		return fmt.Errorf("tag %s=%q is not one of %s: %w", key, value, strings.Join(schema.AllowedValues, ", "), domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// uniqueTagKey reports whether the key's tag schema allows an outage only
+// one tag with the key
+func (s *Service) uniqueTagKey(ctx context.Context, key string) (bool, error) {
+	var schema domain.TagSchema
+	found, err := s.configResource(ctx, domain.ResourceTagSchema, key, &schema)
+	return found && schema.Unique, err
+}
+
+// dedupeUniqueTags keeps only the last of the tags with each unique key,
+// as adding them one after another would
+func (s *Service) dedupeUniqueTags(ctx context.Context, tags []domain.TagInput) ([]domain.TagInput, error) {
+	last := make(map[string]int)
+	for i, tag := range tags {
+		unique, err := s.uniqueTagKey(ctx, tag.Key)
+		if err != nil {
+			return nil, err
+		}
+		if unique {
+			last[tag.Key] = i
+		}
+	}
+	kept := tags[:0:0]
+	for i, tag := range tags {
+		if j, ok := last[tag.Key]; !ok || j == i {
+			kept = append(kept, tag)
+		}
+	}
+	return kept, nil
+}
+
+// configResource decodes the named resource into spec, reporting whether it
+// exists
+func (s *Service) configResource(ctx context.Context, kind, name string, spec any) (bool, error) {
+	r, err := s.storage.GetConfigResource(ctx, kind, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(r.Spec, spec); err != nil {
+		return false, fmt.Errorf("failed to decode %s %s: %w", kind, name, err)
+	}
+	return true, nil
+}
+
+// opsConfigResources encodes every resource in cfg for storage
+func opsConfigResources(cfg domain.OpsConfig) ([]*domain.ConfigResource, error) {
+	var resources []*domain.ConfigResource
+	add := func(kind, name string, spec any) error {
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s %s: %w", kind, name, err)
+		}
+		resources = append(resources, &domain.ConfigResource{Kind: kind, Name: name, Spec: data})
+		return nil
+	}
+	for _, t := range cfg.Teams {
+		if err := add(domain.ResourceTeam, t.Name, t); err != nil {
+			return nil, err
+		}
+	}
+	for _, ts := range cfg.TagSchemas {
+		if err := add(domain.ResourceTagSchema, ts.Key, ts); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range cfg.RoutingRules {
+		if err := add(domain.ResourceRoutingRule, rule.Name, rule); err != nil {
+			return nil, err
+		}
+	}
+	for _, tmpl := range cfg.Templates {
+		if err := add(domain.ResourceTemplate, tmpl.Name, tmpl); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range cfg.CustomFields {
+		if err := add(domain.ResourceCustomField, f.ResourceName(), f); err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+func appendSpec[T any](list []T, r *domain.ConfigResource) ([]T, error) {
+	var v T
+	if err := json.Unmarshal(r.Spec, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %w", r.Kind, r.Name, err)
+	}
+	return append(list, v), nil
+}
+
+// sameSpec compares specs by value, since JSONB does not preserve the
+// encoded key order or whitespace
+func sameSpec(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func resourceKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func testOpsConfig() domain.OpsConfig {
+	return domain.OpsConfig{
+		Teams: []domain.Team{{Name: "payments", SlackChannel: "#payments-oncall"}},
+		TagSchemas: []domain.TagSchema{
+			{Key: "env", AllowedValues: []string{"prod", "staging"}},
+		},
+		RoutingRules: []domain.RoutingRule{{
+			Name:  "payments-pages",
+			Match: domain.RoutingMatch{Source: "fake", TeamName: "Payments"},
+			Team:  "payments",
+			Tags:  map[string]string{"env": "prod"},
+		}},
+		Templates: []domain.OutageTemplate{{
+			Name: "db-failover", Title: "Database failover", Severity: "high",
+			Tags: map[string]string{"component": "database"},
+		}},
+	}
+}
+
+func TestApplyOpsConfig(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	cfg := testOpsConfig()
+
+	plan, err := svc.ApplyOpsConfig(ctx, cfg, true, false)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if plan.Applied || len(plan.Changes) != 4 {
+		t.Fatalf("dry run plan = %+v, want 4 unapplied changes", plan)
+	}
+	for _, c := range plan.Changes {
+		if c.Action != domain.ConfigCreate {
+			t.Errorf("change %+v: want create", c)
+		}
+	}
+	if got, _ := svc.GetOpsConfig(ctx); len(got.Teams) != 0 {
+		t.Fatalf("dry run stored config: %+v", got)
+	}
+
+	if plan, err = svc.ApplyOpsConfig(ctx, cfg, false, false); err != nil || !plan.Applied {
+		t.Fatalf("apply: plan %+v, err %v", plan, err)
+	}
+	got, err := svc.GetOpsConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Teams) != 1 || len(got.TagSchemas) != 1 || len(got.RoutingRules) != 1 || len(got.Templates) != 1 {
+		t.Fatalf("stored config = %+v", got)
+	}
+	if got.RoutingRules[0].Tags["env"] != "prod" {
+		t.Errorf("routing rule round trip = %+v", got.RoutingRules[0])
+	}
+
+	// Re-applying the same document is a no-op
+	if plan, err = svc.ApplyOpsConfig(ctx, cfg, false, false); err != nil || len(plan.Changes) != 0 {
+		t.Fatalf("re-apply: plan %+v, err %v", plan, err)
+	}
+
+	cfg.Teams[0].SlackChannel = "#payments"
+	cfg.Templates = nil
+	plan, err = svc.ApplyOpsConfig(ctx, cfg, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0] != (domain.ConfigChange{Action: domain.ConfigUpdate, Kind: domain.ResourceTeam, Name: "payments"}) {
+		t.Fatalf("update without prune: %+v", plan.Changes)
+	}
+
+	plan, err = svc.ApplyOpsConfig(ctx, cfg, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0] != (domain.ConfigChange{Action: domain.ConfigDelete, Kind: domain.ResourceTemplate, Name: "db-failover"}) {
+		t.Fatalf("prune: %+v", plan.Changes)
+	}
+	if got, _ = svc.GetOpsConfig(ctx); len(got.Templates) != 0 {
+		t.Errorf("template not pruned: %+v", got.Templates)
+	}
+}
+
+func TestApplyOpsConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*domain.OpsConfig)
+	}{
+		{"missing team name", func(c *domain.OpsConfig) { c.Teams = append(c.Teams, domain.Team{}) }},
+		{"duplicate template", func(c *domain.OpsConfig) { c.Templates = append(c.Templates, c.Templates[0]) }},
+		{"unknown team", func(c *domain.OpsConfig) { c.RoutingRules[0].Team = "search" }},
+		{"rule without effect", func(c *domain.OpsConfig) { c.RoutingRules[0].Team, c.RoutingRules[0].Tags = "", nil }},
+		{"bad rule severity", func(c *domain.OpsConfig) { c.RoutingRules[0].Match.Severity = "urgent" }},
+		{"bad set_severity", func(c *domain.OpsConfig) { c.RoutingRules[0].SetSeverity = "urgent" }},
+		{"bad title pattern", func(c *domain.OpsConfig) { c.RoutingRules[0].Match.Title = "card (errors" }},
+		{"suppress and route", func(c *domain.OpsConfig) { c.RoutingRules[0].Suppress = true }},
+		{"bad template severity", func(c *domain.OpsConfig) { c.Templates[0].Severity = "urgent" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newSvc()
+			cfg := testOpsConfig()
+			tt.mutate(&cfg)
+			if _, err := svc.ApplyOpsConfig(context.Background(), cfg, true, false); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("got %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestOpsConfig_TemplatesAndTagSchemas(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, testOpsConfig(), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Template: "db-failover",
+		Tags:     []domain.TagInput{{Key: "env", Value: "staging"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateOutage from template: %v", err)
+	}
+	if outage.Title != "Database failover" || outage.Severity != "high" {
+		t.Errorf("template fields not applied: %+v", outage)
+	}
+	tags := make(map[string]string)
+	for _, tag := range outage.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	if tags["component"] != "database" || tags["env"] != "staging" {
+		t.Errorf("tags = %v", tags)
+	}
+
+	if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Template: "missing"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unknown template: got %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.AddTag(ctx, outage.ID, "env", "dev"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddTag outside schema: got %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.AddTag(ctx, outage.ID, "region", "anything"); err != nil {
+		t.Errorf("AddTag without schema: %v", err)
+	}
+}
+
+func TestOpsConfig_UniqueTagKeys(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	cfg := domain.OpsConfig{TagSchemas: []domain.TagSchema{{Key: "jira", Unique: true}}}
+	if _, err := svc.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the last of a unique key's tags is created with the outage
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "DB down",
+		Tags:  []domain.TagInput{{Key: "jira", Value: "OPS-1"}, {Key: "env", Value: "prod"}, {Key: "jira", Value: "OPS-2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outage.Tags) != 2 {
+		t.Fatalf("tags = %+v, want env and one jira tag", outage.Tags)
+	}
+
+	// Re-adding the key updates the tag rather than adding another
+	first, err := svc.AddTag(ctx, outage.ID, "jira", "OPS-3", map[string]any{"url": "https://jira/OPS-3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := svc.ListTagsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jira []*domain.Tag
+	for _, tag := range tags {
+		if tag.Key == "jira" {
+			jira = append(jira, tag)
+		}
+	}
+	if len(jira) != 1 || jira[0].ID != first.ID || jira[0].Value != "OPS-3" || jira[0].CustomFields["url"] != "https://jira/OPS-3" {
+		t.Errorf("jira tags = %+v, want the one tag updated to OPS-3", jira)
+	}
+
+	// Keys without the mode still allow duplicates
+	for _, value := range []string{"a", "b"} {
+		if _, err := svc.AddTag(ctx, outage.ID, "note", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tags, _ := svc.ListTagsByOutage(ctx, outage.ID); len(tags) != 4 {
+		t.Errorf("outage has %d tags, want 4", len(tags))
+	}
+
+	// Renaming another tag to the unique key conflicts
+	jiraKey := "jira"
+	for _, tag := range tags {
+		if tag.Key == "env" {
+			if _, err := svc.UpdateTag(ctx, tag.ID, domain.UpdateTagRequest{Key: &jiraKey}); !errors.Is(err, domain.ErrConflict) {
+				t.Errorf("UpdateTag(env -> jira) err = %v, want ErrConflict", err)
+			}
+		}
+	}
+
+	// Concurrent adds of the key leave the outage one tag
+	other, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Cache down"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.AddTag(ctx, other.ID, "jira", fmt.Sprintf("OPS-%d", i)); err != nil {
+				t.Errorf("concurrent AddTag: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if tags, _ := svc.ListTagsByOutage(ctx, other.ID); len(tags) != 1 {
+		t.Errorf("outage has %d jira tags after concurrent adds, want 1", len(tags))
+	}
+}
+
+func TestProcessWebhook_AppliesRoutingRules(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, testOpsConfig(), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	deliver := func(a notification.Alert) {
+		t.Helper()
+		payload, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+			t.Fatalf("ProcessWebhook: %v", err)
+		}
+	}
+	deliver(notification.Alert{ExternalID: "A1", Source: "fake", TeamName: "payments", Title: "card errors", Severity: "high", TriggeredAt: time.Now()})
+	deliver(notification.Alert{ExternalID: "A2", Source: "fake", TeamName: "search", Title: "slow queries", Severity: "low", TriggeredAt: time.Now()})
+	// Routed to payments as a secondary team
+	deliver(notification.Alert{
+		ExternalID: "A3", Source: "fake", TeamName: "search", TeamNames: []string{"search", "payments"},
+		Title: "checkout latency", Severity: "high", TriggeredAt: time.Now(),
+	})
+
+	routed, err := svc.FindOutagesByTag(ctx, "team", "payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routed) != 2 {
+		t.Fatalf("routed outages = %+v, want card errors and checkout latency", routed)
+	}
+	for _, o := range routed {
+		if o.Title == "slow queries" {
+			t.Errorf("search-only alert was routed to payments")
+		}
+		if o.OwningTeam != "payments" {
+			t.Errorf("routed outage %q owner = %q, want payments", o.Title, o.OwningTeam)
+		}
+	}
+	envTagged, err := svc.FindOutagesByTag(ctx, "env", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envTagged) != 2 {
+		t.Errorf("expected rule tags on the routed outages, got %d outages", len(envTagged))
+	}
+}
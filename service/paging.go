@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// GetPagingLoad counts the alerts triggered in the query's range per team,
+// bucketed by day of week and hour of day in the query's time zone. An alert
+// routed to several teams counts once towards each of them, and once towards
+// the overall totals. Teams are ordered by total alerts, busiest first.
+func (s *Service) GetPagingLoad(ctx context.Context, q domain.PagingLoadQuery) (*domain.PagingLoad, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetPagingLoad")
+	defer span.End()
+
+	if !q.Since.Before(q.Until) {
+		return nil, fmt.Errorf("since must be before until: %w", domain.ErrInvalidInput)
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	alerts, err := s.storage.ListAlertsTriggeredBetween(ctx, q.Since, q.Until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	load := &domain.PagingLoad{
+		Since:    q.Since,
+		Until:    q.Until,
+		Timezone: loc.String(),
+		Teams:    []domain.TeamPagingLoad{},
+	}
+	teams := make(map[string]*domain.TeamPagingLoad)
+	for _, a := range alerts {
+		names := a.Teams()
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		if q.Team != "" {
+			if !a.HasTeam(q.Team) {
+				continue
+			}
+			names = []string{q.Team}
+		}
+
+		t := a.TriggeredAt.In(loc)
+		offHours := domain.IsOffHours(t)
+		for _, name := range names {
+			team, ok := teams[name]
+			if !ok {
+				team = &domain.TeamPagingLoad{Team: name}
+				teams[name] = team
+			}
+			team.Counts[t.Weekday()][t.Hour()]++
+			team.Total++
+			if offHours {
+				team.OffHours++
+			}
+		}
+		load.Total++
+		if offHours {
+			load.OffHours++
+		}
+	}
+
+	for _, team := range teams {
+		load.Teams = append(load.Teams, *team)
+	}
+	sort.Slice(load.Teams, func(i, j int) bool {
+		if load.Teams[i].Total != load.Teams[j].Total {
+			return load.Teams[i].Total > load.Teams[j].Total
+		}
+		return load.Teams[i].Team < load.Teams[j].Team
+	})
+	return load, nil
+}
+
+// PageOutage opens an incident for an outage with a paging provider, paging
+// the responders of the request's service or team, and links the provider's
+// alert to the outage. Without a service or team the outage's owning team is
+// paged. Pages carry a dedup key derived from the outage and target, so a
+// provider returning an incident that is already recorded yields that alert.
+func (s *Service) PageOutage(ctx context.Context, outageID uuid.UUID, req domain.PageRequest, requester string) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.PageOutage")
+	defer span.End()
+
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return nil, err
+	}
+	svc, ok := s.notificationServices[req.Source]
+	if !ok {
+		return nil, fmt.Errorf("notification service %s: %w", req.Source, domain.ErrNotFound)
+	}
+	if req.Service == "" && req.Team == "" {
+		req.Team = outage.OwningTeam
+	}
+	target := req.Service
+	if target == "" {
+		target = req.Team
+	}
+	if target == "" {
+		return nil, fmt.Errorf("a service or team to page is required when the outage has no owning team: %w", domain.ErrInvalidInput)
+	}
+
+	notifAlert, err := svc.CreateIncident(ctx, notification.IncidentRequest{
+		Title:       outage.Title,
+		Description: outage.Description,
+		Severity:    outage.Severity,
+		Service:     req.Service,
+		Team:        req.Team,
+		DedupKey:    fmt.Sprintf("outalator/%s/%s", outageID, target),
+		Requester:   requester,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to page %s through %s: %w", target, req.Source, err)
+	}
+	if notifAlert.Source == "" {
+		notifAlert.Source = req.Source
+	}
+
+	// The provider's webhook may have delivered the new incident already,
+	// or the page may have been deduplicated into an earlier one
+	existing, err := s.storage.GetAlertByExternalID(ctx, notifAlert.ExternalID, notifAlert.Source)
+	switch {
+	case err == nil:
+		return existing, nil
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, fmt.Errorf("failed to look up paged alert: %w", err)
+	}
+
+	alert, _, err := s.storeAlert(ctx, notifAlert, &outageID)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "outage paged", "outage_id", outageID, "source", req.Source, "target", target, "external_id", alert.ExternalID)
+	return alert, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+func TestGetPagingLoad(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2030-01-07 is a Monday
+	for i, a := range []struct {
+		team      string
+		triggered string
+	}{
+		{"payments", "2030-01-07T10:00:00Z"}, // Monday, in hours
+		{"payments", "2030-01-07T10:30:00Z"}, // Monday, in hours
+		{"payments", "2030-01-08T02:00:00Z"}, // Tuesday, night
+		{"search", "2030-01-12T12:00:00Z"},   // Saturday
+		{"", "2030-01-09T17:00:00Z"},         // Wednesday, after hours
+		{"payments", "2029-12-01T10:00:00Z"}, // Before the range
+		{"search", "2030-02-01T10:00:00Z"},   // After the range
+	} {
+		triggered, _ := time.Parse(time.RFC3339, a.triggered)
+		alert := &domain.Alert{
+			ID: uuid.New(), OutageID: outage.ID, ExternalID: string(rune('A' + i)), Source: "fake",
+			TeamName: a.team, TriggeredAt: triggered, CreatedAt: triggered,
+		}
+		if err := svc.storage.CreateAlert(ctx, alert); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	since := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	load, err := svc.GetPagingLoad(ctx, domain.PagingLoadQuery{Since: since, Until: until})
+	if err != nil {
+		t.Fatalf("GetPagingLoad: %v", err)
+	}
+	if load.Timezone != "UTC" || load.Total != 5 || load.OffHours != 3 {
+		t.Errorf("load = %s total %d off-hours %d, want UTC 5 3", load.Timezone, load.Total, load.OffHours)
+	}
+	if len(load.Teams) != 3 || load.Teams[0].Team != "payments" || load.Teams[1].Team != "" || load.Teams[2].Team != "search" {
+		t.Fatalf("teams = %+v, want payments, unnamed, search", load.Teams)
+	}
+	payments := load.Teams[0]
+	if payments.Total != 3 || payments.OffHours != 1 {
+		t.Errorf("payments total %d off-hours %d, want 3 1", payments.Total, payments.OffHours)
+	}
+	if payments.Counts[time.Monday][10] != 2 || payments.Counts[time.Tuesday][2] != 1 {
+		t.Errorf("payments counts Monday 10:00 = %d, Tuesday 02:00 = %d, want 2 1",
+			payments.Counts[time.Monday][10], payments.Counts[time.Tuesday][2])
+	}
+
+	// Buckets follow the requested time zone: 02:00 UTC Tuesday is 21:00
+	// Monday at UTC-5
+	load, err = svc.GetPagingLoad(ctx, domain.PagingLoadQuery{
+		Since: since, Until: until, Team: "payments", Location: time.FixedZone("UTC-5", -5*60*60),
+	})
+	if err != nil {
+		t.Fatalf("GetPagingLoad: %v", err)
+	}
+	if len(load.Teams) != 1 || load.Teams[0].Counts[time.Monday][21] != 1 || load.Teams[0].Counts[time.Monday][5] != 2 {
+		t.Errorf("payments at UTC-5 = %+v", load.Teams)
+	}
+
+	if _, err := svc.GetPagingLoad(ctx, domain.PagingLoadQuery{Since: until, Until: since}); err == nil {
+		t.Error("GetPagingLoad with since after until succeeded, want error")
+	}
+}
+
+func TestGetPagingLoadMultiTeam(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	triggered := time.Date(2030, 1, 9, 11, 0, 0, 0, time.UTC)
+	alert := &domain.Alert{
+		ID: uuid.New(), OutageID: outage.ID, ExternalID: "A", Source: "fake",
+		TeamName: "payments", TeamNames: []string{"payments", "search"},
+		TriggeredAt: triggered, CreatedAt: triggered,
+	}
+	if err := svc.storage.CreateAlert(ctx, alert); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	// The alert counts towards both teams but once overall
+	load, err := svc.GetPagingLoad(ctx, domain.PagingLoadQuery{Since: since, Until: until})
+	if err != nil {
+		t.Fatalf("GetPagingLoad: %v", err)
+	}
+	if load.Total != 1 || len(load.Teams) != 2 || load.Teams[0].Total != 1 || load.Teams[1].Total != 1 {
+		t.Errorf("load total %d, teams %+v, want 1 alert counted for payments and search", load.Total, load.Teams)
+	}
+
+	// Filtering by a secondary team matches the alert
+	load, err = svc.GetPagingLoad(ctx, domain.PagingLoadQuery{Since: since, Until: until, Team: "search"})
+	if err != nil {
+		t.Fatalf("GetPagingLoad: %v", err)
+	}
+	if load.Total != 1 || len(load.Teams) != 1 || load.Teams[0].Team != "search" {
+		t.Errorf("search load total %d, teams %+v, want the alert under search only", load.Total, load.Teams)
+	}
+}
+
+// fakePagingSource records the incidents it is asked to create and names
+// each one after its dedup key, as deduplicating providers do
+type fakePagingSource struct {
+	fakeWebhookSource
+	requests []notification.IncidentRequest
+}
+
+func (f *fakePagingSource) CreateIncident(_ context.Context, req notification.IncidentRequest) (*notification.Alert, error) {
+	f.requests = append(f.requests, req)
+	return &notification.Alert{
+		ExternalID:  req.DedupKey,
+		Source:      "fake",
+		TeamName:    req.Team,
+		Title:       req.Title,
+		Severity:    req.Severity,
+		TriggeredAt: time.Now(),
+	}, nil
+}
+
+func TestPageOutage(t *testing.T) {
+	svc := newSvc()
+	source := &fakePagingSource{}
+	svc.RegisterNotificationService(source)
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Checkout down", Severity: "critical", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alert, err := svc.PageOutage(ctx, outage.ID, domain.PageRequest{Source: "fake"}, "alice@example.com")
+	if err != nil {
+		t.Fatalf("PageOutage: %v", err)
+	}
+	if alert.OutageID != outage.ID || alert.TeamName != "payments" {
+		t.Errorf("alert = %+v, want payments alert on the outage", alert)
+	}
+	req := source.requests[0]
+	if req.Team != "payments" || req.Requester != "alice@example.com" || req.Severity != "critical" {
+		t.Errorf("incident request = %+v, want the owning team paged by alice", req)
+	}
+
+	again, err := svc.PageOutage(ctx, outage.ID, domain.PageRequest{Source: "fake", Team: "payments"}, "bob@example.com")
+	if err != nil || again.ID != alert.ID {
+		t.Errorf("paging again = %+v, %v, want the existing alert", again, err)
+	}
+	other, err := svc.PageOutage(ctx, outage.ID, domain.PageRequest{Source: "fake", Service: "PSVC1"}, "")
+	if err != nil || other.ID == alert.ID || source.requests[2].Service != "PSVC1" {
+		t.Errorf("paging a service = %+v, %v, want a new alert", other, err)
+	}
+
+	unowned, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PageOutage(ctx, unowned.ID, domain.PageRequest{Source: "fake"}, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("paging without a target error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.PageOutage(ctx, outage.ID, domain.PageRequest{Source: "unknown", Team: "x"}, ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown source error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+func TestSetOutageParent(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	create := func(title, severity string) *domain.Outage {
+		t.Helper()
+		o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: severity})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+	parent := create("eu-west down", "medium")
+	checkout := create("checkout down", "high")
+	search := create("search down", "critical")
+	other := create("other", "low")
+
+	for _, child := range []*domain.Outage{checkout, search} {
+		linked, err := svc.SetOutageParent(ctx, child.ID, parent.ID)
+		if err != nil {
+			t.Fatalf("SetOutageParent(%s) err = %v", child.Title, err)
+		}
+		if linked.ParentID == nil || *linked.ParentID != parent.ID {
+			t.Errorf("SetOutageParent(%s) ParentID = %v, want %s", child.Title, linked.ParentID, parent.ID)
+		}
+	}
+	// Linking again is a no-op
+	if _, err := svc.SetOutageParent(ctx, checkout.ID, parent.ID); err != nil {
+		t.Errorf("SetOutageParent again err = %v", err)
+	}
+
+	invalid := []struct {
+		name         string
+		id, parentID uuid.UUID
+	}{
+		{"itself", other.ID, other.ID},
+		{"a child", other.ID, checkout.ID},
+		{"a missing outage", other.ID, uuid.New()},
+	}
+	for _, tt := range invalid {
+		if _, err := svc.SetOutageParent(ctx, tt.id, tt.parentID); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("SetOutageParent to %s err = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+	if _, err := svc.SetOutageParent(ctx, parent.ID, other.ID); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("SetOutageParent of a parent err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.SetOutageParent(ctx, uuid.New(), parent.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SetOutageParent of a missing outage err = %v, want ErrNotFound", err)
+	}
+
+	resolved := domain.StatusResolved
+	if _, err := svc.UpdateOutage(ctx, search.ID, domain.UpdateOutageRequest{Status: &resolved}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.GetOutage(ctx, parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := got.Rollup
+	if r == nil || len(r.Children) != 2 {
+		t.Fatalf("GetOutage(parent) Rollup = %+v, want two children", r)
+	}
+	if r.OpenChildren != 1 || r.Status != domain.StatusOpen || r.Severity != "critical" {
+		t.Errorf("Rollup = %d open, %s, %s; want 1 open, open, critical", r.OpenChildren, r.Status, r.Severity)
+	}
+	if got, err = svc.GetOutage(ctx, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got.Rollup != nil {
+		t.Errorf("GetOutage(top-level outage) Rollup = %+v, want nil", got.Rollup)
+	}
+
+	if _, err := svc.RemoveOutageParent(ctx, checkout.ID); err != nil {
+		t.Fatalf("RemoveOutageParent err = %v", err)
+	}
+	children, err := svc.ListChildOutages(ctx, parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 1 || children[0].ID != search.ID {
+		t.Errorf("ListChildOutages after unlink = %d outages, want only %s", len(children), search.Title)
+	}
+	got, err = svc.GetOutage(ctx, parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Rollup == nil || got.Rollup.OpenChildren != 0 || got.Rollup.Status != domain.StatusResolved {
+		t.Errorf("Rollup after unlink = %+v, want one resolved child", got.Rollup)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// validSeverities lists the severities accepted as a notification threshold
+var validSeverities = map[string]bool{
+	"critical": true,
+	"high":     true,
+	"medium":   true,
+	"low":      true,
+}
+
+// defaultPreferences are returned for users who have never saved preferences
+func defaultPreferences(subject string) *domain.UserPreferences {
+	return &domain.UserPreferences{
+		Subject:  subject,
+		Timezone: "UTC",
+	}
+}
+
+// GetUserPreferences returns the preferences for an OIDC subject, falling
+// back to defaults when none have been saved.
+func (s *Service) GetUserPreferences(ctx context.Context, subject string) (*domain.UserPreferences, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetUserPreferences")
+	defer span.End()
+
+	prefs, err := s.storage.GetUserPreferences(ctx, subject)
+	if errors.Is(err, domain.ErrNotFound) {
+		return defaultPreferences(subject), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// UpdateUserPreferences applies the non-nil fields of req to the user's
+// preferences and saves them.
+func (s *Service) UpdateUserPreferences(ctx context.Context, subject string, req domain.UpdatePreferencesRequest) (*domain.UserPreferences, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateUserPreferences")
+	defer span.End()
+
+	if subject == "" {
+		return nil, fmt.Errorf("subject is required: %w", domain.ErrInvalidInput)
+	}
+
+	prefs, err := s.GetUserPreferences(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); *req.Timezone == "" || err != nil {
+			return nil, fmt.Errorf("unknown timezone %q: %w", *req.Timezone, domain.ErrInvalidInput)
+		}
+		prefs.Timezone = *req.Timezone
+	}
+	if req.DefaultTeamFilter != nil {
+		prefs.DefaultTeamFilter = *req.DefaultTeamFilter
+	}
+	if req.Notifications != nil {
+		if sev := req.Notifications.MinSeverity; sev != "" && !validSeverities[sev] {
+			return nil, fmt.Errorf("invalid min_severity %q: %w", sev, domain.ErrInvalidInput)
+		}
+		prefs.Notifications = *req.Notifications
+	}
+	if req.DigestOptIn != nil {
+		prefs.DigestOptIn = *req.DigestOptIn
+	}
+
+	prefs.UpdatedAt = time.Now()
+
+	if err := s.storage.UpsertUserPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// presenceTracker holds the latest heartbeat per user for each outage. It
+// lives in memory only, so with several replicas each one sees just the
+// heartbeats it received.
+type presenceTracker struct {
+	mu      sync.Mutex
+	outages map[uuid.UUID]map[string]domain.Presence
+	now     func() time.Time
+}
+
+func newPresenceTracker() *presenceTracker {
+	return &presenceTracker{
+		outages: make(map[uuid.UUID]map[string]domain.Presence),
+		now:     time.Now,
+	}
+}
+
+// pruneLocked drops heartbeats older than domain.PresenceTTL
+func (t *presenceTracker) pruneLocked(now time.Time) {
+	for outageID, users := range t.outages {
+		for user, p := range users {
+			if now.Sub(p.LastSeen) >= domain.PresenceTTL {
+				delete(users, user)
+			}
+		}
+		if len(users) == 0 {
+			delete(t.outages, outageID)
+		}
+	}
+}
+
+func (t *presenceTracker) record(outageID uuid.UUID, p domain.Presence) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.pruneLocked(now)
+	p.LastSeen = now
+	users := t.outages[outageID]
+	if users == nil {
+		users = make(map[string]domain.Presence)
+		t.outages[outageID] = users
+	}
+	users[p.User] = p
+}
+
+func (t *presenceTracker) remove(outageID uuid.UUID, user string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if users := t.outages[outageID]; users != nil {
+		delete(users, user)
+		if len(users) == 0 {
+			delete(t.outages, outageID)
+		}
+	}
+}
+
+// list returns the users present on outageID, most recently seen first
+func (t *presenceTracker) list(outageID uuid.UUID) []domain.Presence {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pruneLocked(t.now())
+	present := make([]domain.Presence, 0, len(t.outages[outageID]))
+	for _, p := range t.outages[outageID] {
+		present = append(present, p)
+	}
+	sort.Slice(present, func(i, j int) bool {
+		if !present[i].LastSeen.Equal(present[j].LastSeen) {
+			return present[i].LastSeen.After(present[j].LastSeen)
+		}
+		return present[i].User < present[j].User
+	})
+	return present
+}
+
+// RecordPresence records a heartbeat from a user viewing or working an
+// outage and returns everyone currently present on it
+func (s *Service) RecordPresence(ctx context.Context, outageID uuid.UUID, hb domain.PresenceHeartbeat) ([]domain.Presence, error) {
+	ctx, span := tracer.Start(ctx, "Service.RecordPresence")
+	defer span.End()
+
+	if hb.User == "" {
+		return nil, fmt.Errorf("user is required: %w", domain.ErrInvalidInput)
+	}
+	switch hb.Activity {
+	case "":
+		hb.Activity = domain.PresenceViewing
+	case domain.PresenceViewing, domain.PresenceWorking:
+	default:
+		return nil, fmt.Errorf("activity must be %q or %q: %w", domain.PresenceViewing, domain.PresenceWorking, domain.ErrInvalidInput)
+	}
+	if _, err := s.liveOutage(ctx, outageID); err != nil {
+		return nil, err
+	}
+
+	s.presence.record(outageID, domain.Presence{User: hb.User, Name: hb.Name, Activity: hb.Activity})
+	return s.presence.list(outageID), nil
+}
+
+// LeavePresence removes a user from an outage before their heartbeat expires
+func (s *Service) LeavePresence(ctx context.Context, outageID uuid.UUID, user string) {
+	_, span := tracer.Start(ctx, "Service.LeavePresence")
+	defer span.End()
+
+	s.presence.remove(outageID, user)
+}
+
+// ListPresence returns the users currently viewing or working an outage,
+// most recently seen first
+func (s *Service) ListPresence(ctx context.Context, outageID uuid.UUID) []domain.Presence {
+	_, span := tracer.Start(ctx, "Service.ListPresence")
+	defer span.End()
+
+	return s.presence.list(outageID)
+}
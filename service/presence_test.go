@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+func TestPresence(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	svc.presence.now = func() time.Time { return now }
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.RecordPresence(ctx, outage.ID, domain.PresenceHeartbeat{User: "alice@example.com"}); err != nil {
+		t.Fatalf("RecordPresence: %v", err)
+	}
+	now = now.Add(30 * time.Second)
+	present, err := svc.RecordPresence(ctx, outage.ID, domain.PresenceHeartbeat{User: "bob@example.com", Activity: domain.PresenceWorking})
+	if err != nil {
+		t.Fatalf("RecordPresence: %v", err)
+	}
+	if len(present) != 2 || present[0].User != "bob@example.com" || present[0].Activity != domain.PresenceWorking ||
+		present[1].User != "alice@example.com" || present[1].Activity != domain.PresenceViewing {
+		t.Fatalf("present = %+v, want bob working then alice viewing", present)
+	}
+
+	// Alice's heartbeat expires first
+	now = now.Add(domain.PresenceTTL - 10*time.Second)
+	present = svc.ListPresence(ctx, outage.ID)
+	if len(present) != 1 || present[0].User != "bob@example.com" {
+		t.Errorf("after alice's TTL present = %+v, want only bob", present)
+	}
+
+	svc.LeavePresence(ctx, outage.ID, "bob@example.com")
+	if present := svc.ListPresence(ctx, outage.ID); len(present) != 0 {
+		t.Errorf("after leaving present = %+v, want none", present)
+	}
+}
+
+func TestRecordPresenceInvalid(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.RecordPresence(ctx, outage.ID, domain.PresenceHeartbeat{User: "a@example.com", Activity: "typing"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unknown activity: err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.RecordPresence(ctx, outage.ID, domain.PresenceHeartbeat{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("no user: err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.RecordPresence(ctx, uuid.New(), domain.PresenceHeartbeat{User: "a@example.com"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown outage: err = %v, want ErrNotFound", err)
+	}
+}
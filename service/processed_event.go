@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// eventPurgeInterval is the least time between purges of a source's
+// expired processed events
+const eventPurgeInterval = 10 * time.Minute
+
+// eventPurges records when each source's processed events were last purged
+type eventPurges struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// ClaimEvent records that the event eventID delivered by source is being
+// processed, reporting false when it was claimed before, so an event its
+// sender redelivers is processed once. Claims are kept for ttl, which
+// should cover the time over which the sender retries; older claims are
+// purged as new ones are made.
+func (s *Service) ClaimEvent(ctx context.Context, source, eventID string, ttl time.Duration) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.ClaimEvent")
+	defer span.End()
+
+	now := time.Now()
+	if s.eventPurgeDue(source, now) {
+		if n, err := s.storage.PurgeProcessedEvents(ctx, source, now.Add(-ttl)); err != nil {
+			s.logger.WarnContext(ctx, "failed to purge processed events", "source", source, "error", err)
+		} else if n > 0 {
+			s.logger.DebugContext(ctx, "purged processed events", "source", source, "count", n)
+		}
+	}
+
+	err := s.storage.CreateProcessedEvent(ctx, &domain.ProcessedEvent{Source: source, EventID: eventID, ProcessedAt: now})
+	if errors.Is(err, domain.ErrConflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim event: %w", err)
+	}
+	return true, nil
+}
+
+// eventPurgeDue reports whether source's processed events are due a purge,
+// recording the purge as done when they are
+func (s *Service) eventPurgeDue(source string, now time.Time) bool {
+	s.eventPurges.mu.Lock()
+	defer s.eventPurges.mu.Unlock()
+	if now.Sub(s.eventPurges.last[source]) < eventPurgeInterval {
+		return false
+	}
+	s.eventPurges.last[source] = now
+	return true
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+)
+
+func TestClaimEvent(t *testing.T) {
+	ctx := context.Background()
+	svc := New(testutil.NewMemStorage(), logging.Discard())
+
+	for i, want := range []bool{true, false} {
+		claimed, err := svc.ClaimEvent(ctx, "slack", "Ev01", time.Hour)
+		if err != nil {
+			t.Fatalf("ClaimEvent #%d: %v", i+1, err)
+		}
+		if claimed != want {
+			t.Errorf("ClaimEvent #%d = %v, want %v", i+1, claimed, want)
+		}
+	}
+	if claimed, err := svc.ClaimEvent(ctx, "other", "Ev01", time.Hour); err != nil || !claimed {
+		t.Errorf("ClaimEvent(other source) = %v, %v, want claimed", claimed, err)
+	}
+}
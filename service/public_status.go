@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// PublicStatus returns the open outages marked public, most severe first and
+// then longest running, each with its latest public note. Nothing else about
+// an outage is included, so the result can be served without sign-in.
+func (s *Service) PublicStatus(ctx context.Context, now time.Time) (*domain.PublicStatus, error) {
+	ctx, span := tracer.Start(ctx, "Service.PublicStatus")
+	defer span.End()
+
+	outages, err := s.storage.ListPublicOutages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public outages: %w", err)
+	}
+	status := &domain.PublicStatus{Outages: make([]domain.PublicOutage, 0, len(outages)), UpdatedAt: now}
+	for _, outage := range outages {
+		public, err := s.publicOutage(ctx, outage)
+		if err != nil {
+			return nil, err
+		}
+		status.Outages = append(status.Outages, public)
+	}
+
+	sort.SliceStable(status.Outages, func(i, j int) bool {
+		a, b := status.Outages[i], status.Outages[j]
+		if ra, rb := rank(notification.Severities, a.Severity), rank(notification.Severities, b.Severity); ra != rb {
+			return ra < rb
+		}
+		return a.StartedAt.Before(b.StartedAt)
+	})
+	return status, nil
+}
+
+// publicOutage builds the public view of an outage
+func (s *Service) publicOutage(ctx context.Context, outage *domain.Outage) (domain.PublicOutage, error) {
+	public := domain.PublicOutage{
+		Title:     outage.Title,
+		Severity:  outage.Severity,
+		StartedAt: outage.CreatedAt,
+	}
+	if outage.ImpactStartedAt != nil {
+		public.StartedAt = *outage.ImpactStartedAt
+	}
+
+	notes, err := s.storage.ListNotesByOutage(ctx, outage.ID, false)
+	if err != nil {
+		return domain.PublicOutage{}, fmt.Errorf("failed to list notes for outage %s: %w", outage.ID, err)
+	}
+	for _, n := range notes {
+		if n.Metadata[domain.MetadataPublic] != "true" {
+			continue
+		}
+		if public.LatestUpdate == nil || n.CreatedAt.After(public.LatestUpdate.PostedAt) {
+			public.LatestUpdate = &domain.PublicUpdate{Content: n.Content, PostedAt: n.CreatedAt}
+		}
+	}
+	return public, nil
+}
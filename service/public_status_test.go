@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestPublicStatus(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	public := map[string]string{domain.MetadataPublic: "true"}
+	impact := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+
+	create := func(req domain.CreateOutageRequest) *domain.Outage {
+		t.Helper()
+		o, err := svc.CreateOutage(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+	high := create(domain.CreateOutageRequest{Title: "search slow", Severity: "high", Metadata: public})
+	critical := create(domain.CreateOutageRequest{Title: "checkout down", Severity: "critical", Metadata: public, ImpactStartedAt: &impact})
+	create(domain.CreateOutageRequest{Title: "internal only", Severity: "critical"})
+	resolved := create(domain.CreateOutageRequest{Title: "fixed", Severity: "critical", Metadata: public})
+	status := domain.StatusResolved
+	if _, err := svc.UpdateOutage(ctx, resolved.ID, domain.UpdateOutageRequest{Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []domain.AddNoteRequest{
+		{Content: "Failing over payments", Author: "alice", Metadata: public},
+		{Content: "db-7 is out of disk", Author: "alice"},
+	} {
+		if _, err := svc.AddNote(ctx, critical.ID, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := svc.PublicStatus(ctx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Outages) != 2 {
+		t.Fatalf("PublicStatus = %d outages, want the two open public ones: %+v", len(got.Outages), got.Outages)
+	}
+	first, second := got.Outages[0], got.Outages[1]
+	if first.Title != critical.Title || second.Title != high.Title {
+		t.Errorf("PublicStatus order = %q, %q; want the critical outage first", first.Title, second.Title)
+	}
+	if !first.StartedAt.Equal(impact) {
+		t.Errorf("StartedAt = %s, want the impact start %s", first.StartedAt, impact)
+	}
+	if first.LatestUpdate == nil || first.LatestUpdate.Content != "Failing over payments" {
+		t.Errorf("LatestUpdate = %+v, want the public note", first.LatestUpdate)
+	}
+	if second.LatestUpdate != nil {
+		t.Errorf("LatestUpdate without public notes = %+v, want nil", second.LatestUpdate)
+	}
+}
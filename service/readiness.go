@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// credentialCheckInterval is how long a notification service's credential
+// check is reused before the provider is asked again, so frequent readiness
+// probes do not spend its API rate limit
+const credentialCheckInterval = 5 * time.Minute
+
+// ReadinessCheck is the outcome of one dependency check
+type ReadinessCheck struct {
+	Name string // database, schema, or a notification service name
+	Err  error  // Nil when the check passed
+}
+
+// credentialChecks remembers the latest credential check per notification
+// service
+type credentialChecks struct {
+	mu      sync.Mutex
+	results map[string]credentialCheck
+}
+
+type credentialCheck struct {
+	err error
+	at  time.Time
+}
+
+// Readiness checks the dependencies needed to serve requests: the database
+// can be reached, every migration has been applied, and each notification
+// service's provider accepts its credentials. Checks are returned in that
+// order, notification services sorted by name. A provider that cannot be
+// reached passes, so a provider outage does not take every replica out of
+// service at once; only rejected credentials fail.
+func (s *Service) Readiness(ctx context.Context) []ReadinessCheck {
+	ctx, span := tracer.Start(ctx, "Service.Readiness")
+	defer span.End()
+
+	checks := []ReadinessCheck{{Name: "database"}, {Name: "schema"}}
+	if err := s.storage.Ping(ctx); err != nil {
+		checks[0].Err = fmt.Errorf("storage unreachable: %w", err)
+		checks[1].Err = errors.New("not checked: storage unreachable")
+	} else if err := s.storage.CheckSchema(ctx); err != nil {
+		checks[1].Err = err
+	}
+
+	names := make([]string, 0, len(s.notificationServices))
+	for name, svc := range s.notificationServices {
+		if _, ok := svc.(notification.CredentialChecker); ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		checks = append(checks, ReadinessCheck{Name: name, Err: s.checkCredentials(ctx, name)})
+	}
+	return checks
+}
+
+// Ready returns an error naming the failed readiness checks, or nil when
+// every check passed
+func (s *Service) Ready(ctx context.Context) error {
+	var failed []string
+	var errs []error
+	for _, check := range s.Readiness(ctx) {
+		if check.Err != nil {
+			failed = append(failed, check.Name)
+			errs = append(errs, fmt.Errorf("%s: %w", check.Name, check.Err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("not ready: %s failed: %w", strings.Join(failed, ", "), errors.Join(errs...))
+	}
+	return nil
+}
+
+// checkCredentials returns the credential check for the named notification
+// service, asking the provider when the last check is older than
+// credentialCheckInterval
+func (s *Service) checkCredentials(ctx context.Context, name string) error {
+	s.credentials.mu.Lock()
+	defer s.credentials.mu.Unlock()
+
+	if last, ok := s.credentials.results[name]; ok && time.Since(last.at) < credentialCheckInterval {
+		return last.err
+	}
+
+	err := s.notificationServices[name].(notification.CredentialChecker).CheckCredentials(ctx)
+	if err != nil && !errors.Is(err, notification.ErrInvalidCredentials) {
+		s.logger.WarnContext(ctx, "could not check notification service credentials",
+			"source", name, "error", err)
+		err = nil
+	}
+	s.credentials.results[name] = credentialCheck{err: err, at: time.Now()}
+	return err
+}
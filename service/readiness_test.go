@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/notification"
+)
+
+// fakeCredentialSource is a notification service whose credential check
+// returns err, counting the checks
+type fakeCredentialSource struct {
+	fakeWebhookSource
+	name   string
+	err    error
+	checks int
+}
+
+func (f *fakeCredentialSource) Name() string { return f.name }
+
+func (f *fakeCredentialSource) CheckCredentials(context.Context) error {
+	f.checks++
+	return f.err
+}
+
+func TestReadiness(t *testing.T) {
+	ctx := context.Background()
+	store := testutil.NewMemStorage()
+	svc := New(store, logging.Discard())
+	rejected := &fakeCredentialSource{name: "pagerduty", err: fmt.Errorf("status 401: %w", notification.ErrInvalidCredentials)}
+	unreachable := &fakeCredentialSource{name: "opsgenie", err: errors.New("connection refused")}
+	svc.RegisterNotificationService(rejected)
+	svc.RegisterNotificationService(unreachable)
+	svc.RegisterNotificationService(namedSource("mail"))
+
+	checks := svc.Readiness(ctx)
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.Name)
+	}
+	if fmt.Sprint(names) != "[database schema opsgenie pagerduty]" {
+		t.Fatalf("checks = %v, want database, schema, then services that can check credentials", names)
+	}
+	for _, c := range checks {
+		if failed := c.Err != nil; failed != (c.Name == "pagerduty") {
+			t.Errorf("%s check err = %v, want only rejected credentials to fail", c.Name, c.Err)
+		}
+	}
+	if err := svc.Ready(ctx); err == nil || !errors.Is(err, notification.ErrInvalidCredentials) {
+		t.Errorf("Ready() = %v, want the rejected credentials", err)
+	}
+
+	// Credential checks are reused between probes
+	svc.Readiness(ctx)
+	if rejected.checks != 1 || unreachable.checks != 1 {
+		t.Errorf("credential checks = %d and %d, want 1 each", rejected.checks, unreachable.checks)
+	}
+
+	store.PingErr = errors.New("connection refused")
+	checks = svc.Readiness(ctx)
+	if checks[0].Err == nil || checks[1].Err == nil {
+		t.Errorf("database and schema checks = %v, %v with an unreachable database, want both failed", checks[0].Err, checks[1].Err)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// relationSummaries describes a relation in an outage's timeline, reading
+// from the outage to the related outage's title
+var relationSummaries = map[string]string{
+	domain.RelationDuplicateOf:  "Marked as a duplicate of %s",
+	domain.RelationDuplicatedBy: "Duplicated by %s",
+	domain.RelationCausedBy:     "Caused by %s",
+	domain.RelationCauses:       "Caused %s",
+	domain.RelationRelatedTo:    "Related to %s",
+}
+
+// CreateOutageRelation links an outage to another with a typed relation.
+// duplicated_by and causes are stored as duplicate_of and caused_by from
+// the related outage, so a link reads the same whichever end it was made
+// from. Making a link that already exists, from either end, returns
+// domain.ErrConflict.
+func (s *Service) CreateOutageRelation(ctx context.Context, outageID uuid.UUID, req domain.CreateOutageRelationRequest) (*domain.OutageRelation, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateOutageRelation")
+	defer span.End()
+
+	if err := s.authorizeOutageChange(ctx, outageID); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(domain.OutageRelationTypes, req.Type) {
+		return nil, fmt.Errorf("unknown relation type %q (want one of %s): %w",
+			req.Type, strings.Join(domain.OutageRelationTypes, ", "), domain.ErrInvalidInput)
+	}
+	if req.RelatedOutageID == uuid.Nil {
+		return nil, fmt.Errorf("related_outage_id is required: %w", domain.ErrInvalidInput)
+	}
+	if req.RelatedOutageID == outageID {
+		return nil, fmt.Errorf("an outage cannot be related to itself: %w", domain.ErrInvalidInput)
+	}
+	if err := checkDescription(req.Description); err != nil {
+		return nil, err
+	}
+	if _, err := s.liveOutage(ctx, outageID); err != nil {
+		return nil, err
+	}
+	related, err := s.storage.GetOutageWith(ctx, req.RelatedOutageID, domain.OutageAssociations{})
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && related.DeletedAt != nil) {
+		return nil, fmt.Errorf("related outage %s does not exist: %w", req.RelatedOutageID, domain.ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.storage.ListOutageRelations(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range existing {
+		if view := relationFrom(r, outageID); view.RelatedOutageID == req.RelatedOutageID && view.Type == req.Type {
+			return nil, fmt.Errorf("outage %s is already %s %s: %w", outageID, req.Type, req.RelatedOutageID, domain.ErrConflict)
+		}
+	}
+
+	relation := &domain.OutageRelation{
+		ID:              uuid.New(),
+		OutageID:        outageID,
+		RelatedOutageID: req.RelatedOutageID,
+		Type:            req.Type,
+		Description:     req.Description,
+		CreatedBy:       req.CreatedBy,
+		CreatedAt:       time.Now(),
+	}
+	if req.Type == domain.RelationDuplicatedBy || req.Type == domain.RelationCauses {
+		relation.OutageID, relation.RelatedOutageID = relation.RelatedOutageID, relation.OutageID
+		relation.Type = domain.InverseRelation(relation.Type)
+	}
+	if err := s.storage.CreateOutageRelation(ctx, relation); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "outages related",
+		"outage_id", relation.OutageID, "related_outage_id", relation.RelatedOutageID, "type", relation.Type)
+
+	view := relationFrom(relation, outageID)
+	view.RelatedOutageTitle = related.Title
+	return &view, nil
+}
+
+// ListOutageRelations returns an outage's links to other outages, oldest
+// first, each read from the outage. Links to outages in the trash are left
+// out.
+func (s *Service) ListOutageRelations(ctx context.Context, outageID uuid.UUID) ([]*domain.OutageRelation, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListOutageRelations")
+	defer span.End()
+
+	outage, err := s.storage.GetOutageWith(ctx, outageID, domain.OutageAssociations{})
+	if err != nil {
+		return nil, err
+	}
+	if outage.DeletedAt != nil {
+		return nil, fmt.Errorf("outage %s is in the trash: %w", outageID, domain.ErrNotFound)
+	}
+	return s.relationsOf(ctx, outageID)
+}
+
+// DeleteOutageRelation removes a link between two outages. It can be
+// removed from either end; relations that do not involve the outage are
+// reported as not found.
+func (s *Service) DeleteOutageRelation(ctx context.Context, outageID, relationID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteOutageRelation")
+	defer span.End()
+
+	if err := s.authorizeOutageChange(ctx, outageID); err != nil {
+		return err
+	}
+	relation, err := s.storage.GetOutageRelation(ctx, relationID)
+	if err != nil {
+		return err
+	}
+	if relation.OutageID != outageID && relation.RelatedOutageID != outageID {
+		return fmt.Errorf("outage %s has no relation %s: %w", outageID, relationID, domain.ErrNotFound)
+	}
+	return s.storage.DeleteOutageRelation(ctx, relationID)
+}
+
+// relationsOf lists the relations of an outage read from it, with the
+// related outages' titles, leaving out relations to outages in the trash
+func (s *Service) relationsOf(ctx context.Context, outageID uuid.UUID) ([]*domain.OutageRelation, error) {
+	stored, err := s.storage.ListOutageRelations(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	relations := make([]*domain.OutageRelation, 0, len(stored))
+	for _, r := range stored {
+		view := relationFrom(r, outageID)
+		relations = append(relations, &view)
+	}
+	return relations, nil
+}
+
+// relationFrom reads a stored relation from one of its outages, swapping
+// its ends and inverting its type when that outage is the related one
+func relationFrom(r *domain.OutageRelation, outageID uuid.UUID) domain.OutageRelation {
+	view := *r
+	if r.OutageID != outageID {
+		view.OutageID, view.RelatedOutageID = r.RelatedOutageID, r.OutageID
+		view.Type = domain.InverseRelation(r.Type)
+	}
+	return view
+}
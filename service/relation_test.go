@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+func TestCreateOutageRelation(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	create := func(title string) *domain.Outage {
+		t.Helper()
+		o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: "high"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+	gateway := create("gateway down")
+	dns := create("dns down")
+	report := create("site unreachable")
+
+	cause, err := svc.CreateOutageRelation(ctx, gateway.ID, domain.CreateOutageRelationRequest{
+		RelatedOutageID: dns.ID, Type: domain.RelationCausedBy, CreatedBy: "alice",
+	})
+	if err != nil {
+		t.Fatalf("CreateOutageRelation(caused_by) err = %v", err)
+	}
+	if cause.OutageID != gateway.ID || cause.RelatedOutageID != dns.ID || cause.RelatedOutageTitle != dns.Title {
+		t.Errorf("CreateOutageRelation = %+v, want caused_by %s", cause, dns.Title)
+	}
+	// duplicated_by is stored as duplicate_of from the other end
+	dup, err := svc.CreateOutageRelation(ctx, gateway.ID, domain.CreateOutageRelationRequest{
+		RelatedOutageID: report.ID, Type: domain.RelationDuplicatedBy,
+	})
+	if err != nil {
+		t.Fatalf("CreateOutageRelation(duplicated_by) err = %v", err)
+	}
+	if dup.Type != domain.RelationDuplicatedBy || dup.RelatedOutageID != report.ID {
+		t.Errorf("CreateOutageRelation(duplicated_by) = %+v, read from the wrong end", dup)
+	}
+
+	// The same links made from the other end conflict
+	for from, typ := range map[uuid.UUID]string{dns.ID: domain.RelationCauses, report.ID: domain.RelationDuplicateOf} {
+		req := domain.CreateOutageRelationRequest{RelatedOutageID: gateway.ID, Type: typ}
+		if _, err := svc.CreateOutageRelation(ctx, from, req); !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("CreateOutageRelation(%s) from the other end err = %v, want ErrConflict", typ, err)
+		}
+	}
+
+	invalid := []struct {
+		name string
+		req  domain.CreateOutageRelationRequest
+	}{
+		{"an unknown type", domain.CreateOutageRelationRequest{RelatedOutageID: dns.ID, Type: "blames"}},
+		{"no related outage", domain.CreateOutageRelationRequest{Type: domain.RelationRelatedTo}},
+		{"itself", domain.CreateOutageRelationRequest{RelatedOutageID: gateway.ID, Type: domain.RelationRelatedTo}},
+		{"a missing outage", domain.CreateOutageRelationRequest{RelatedOutageID: uuid.New(), Type: domain.RelationRelatedTo}},
+	}
+	for _, tt := range invalid {
+		if _, err := svc.CreateOutageRelation(ctx, gateway.ID, tt.req); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("CreateOutageRelation to %s err = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+
+	// Each end reads the relations from itself
+	got, err := svc.GetOutage(ctx, dns.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Relations) != 1 || got.Relations[0].Type != domain.RelationCauses || got.Relations[0].RelatedOutageID != gateway.ID {
+		t.Errorf("GetOutage(dns) Relations = %+v, want causes %s", got.Relations, gateway.Title)
+	}
+	relations, err := svc.ListOutageRelations(ctx, report.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(relations) != 1 || relations[0].Type != domain.RelationDuplicateOf || relations[0].RelatedOutageTitle != gateway.Title {
+		t.Errorf("ListOutageRelations(report) = %+v, want duplicate_of %s", relations, gateway.Title)
+	}
+
+	events, err := svc.GetOutageTimeline(ctx, gateway.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summaries []string
+	for _, e := range events {
+		if e.Type == domain.TimelineRelationAdded {
+			summaries = append(summaries, e.Summary)
+		}
+	}
+	if len(summaries) != 2 || summaries[0] != "Caused by dns down" || summaries[1] != "Duplicated by site unreachable" {
+		t.Errorf("relation timeline events = %q, want the cause then the duplicate", summaries)
+	}
+
+	// Either end can remove a relation; other outages cannot
+	if err := svc.DeleteOutageRelation(ctx, report.ID, cause.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteOutageRelation from an unrelated outage err = %v, want ErrNotFound", err)
+	}
+	if err := svc.DeleteOutageRelation(ctx, dns.ID, cause.ID); err != nil {
+		t.Fatalf("DeleteOutageRelation err = %v", err)
+	}
+	if relations, err = svc.ListOutageRelations(ctx, gateway.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(relations) != 1 || relations[0].ID != dup.ID {
+		t.Errorf("ListOutageRelations after delete = %+v, want only the duplicate", relations)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// SetAlertResolutionPolicy enables automatic resolution of alerts that stay
+// open too long and, optionally, of outages whose alerts are all resolved.
+// Without a policy nothing is resolved automatically.
+func (s *Service) SetAlertResolutionPolicy(policy domain.AlertResolutionPolicy) {
+	s.resolutionPolicy = &policy
+}
+
+// ExpireStaleAlerts resolves every alert that has been open for longer than
+// the policy's maximum open duration at now, recording an audit note on its
+// outage. When the policy resolves outages, outages left with only resolved
+// alerts are resolved too. An alert that cannot be updated is counted as
+// failed and retried on the next pass.
+func (s *Service) ExpireStaleAlerts(ctx context.Context, now time.Time) (*domain.AlertExpiryResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.ExpireStaleAlerts")
+	defer span.End()
+
+	result := &domain.AlertExpiryResult{}
+	if s.resolutionPolicy == nil || s.resolutionPolicy.MaxOpen <= 0 {
+		return result, nil
+	}
+	maxOpen := s.resolutionPolicy.MaxOpen
+
+	alerts, err := s.storage.ListOpenAlerts(ctx, now.Add(-maxOpen))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open alerts: %w", err)
+	}
+
+	var outageIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, alert := range alerts {
+		alert.ResolvedAt = &now
+		if err := s.storage.UpdateAlert(ctx, alert); err != nil {
+			result.Failed++
+			s.logger.WarnContext(ctx, "failed to resolve stale alert",
+				"alert_id", alert.ID, "outage_id", alert.OutageID, "error", err)
+			continue
+		}
+		result.Expired++
+		s.logger.InfoContext(ctx, "resolved stale alert",
+			"alert_id", alert.ID, "outage_id", alert.OutageID, "source", alert.Source, "triggered_at", alert.TriggeredAt)
+		s.addAutoResolveNote(ctx, alert.OutageID, "alert", fmt.Sprintf(
+			"Alert %q was resolved automatically: it had been open for more than %s without %s reporting it resolved.",
+			alert.Title, maxOpen, alert.Source))
+
+		if !seen[alert.OutageID] {
+			seen[alert.OutageID] = true
+			outageIDs = append(outageIDs, alert.OutageID)
+		}
+	}
+
+	for _, id := range outageIDs {
+		resolved, err := s.resolveOutageIfAlertsResolved(ctx, id,
+			fmt.Sprintf("all of its alerts are resolved, including alerts that exceeded the maximum open duration of %s", maxOpen))
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to auto-resolve outage", "outage_id", id, "error", err)
+			continue
+		}
+		if resolved {
+			result.OutagesResolved++
+		}
+	}
+	return result, nil
+}
+
+// autoResolveOutage resolves an outage whose alerts are all resolved when
+// the policy allows it. Failures are logged rather than returned so they do
+// not fail alert ingestion.
+func (s *Service) autoResolveOutage(ctx context.Context, outageID uuid.UUID, reason string) {
+	if _, err := s.resolveOutageIfAlertsResolved(ctx, outageID, reason); err != nil {
+		s.logger.WarnContext(ctx, "failed to auto-resolve outage", "outage_id", outageID, "error", err)
+	}
+}
+
+// resolveOutageIfAlertsResolved resolves the outage, recording reason in an
+// audit note, if the policy resolves outages, the outage is still open and
+// it has at least one alert with every alert resolved.
+func (s *Service) resolveOutageIfAlertsResolved(ctx context.Context, outageID uuid.UUID, reason string) (bool, error) {
+	if s.resolutionPolicy == nil || !s.resolutionPolicy.ResolveOutages {
+		return false, nil
+	}
+
+	outage, err := s.storage.GetOutage(ctx, outageID)
+	if err != nil {
+		return false, err
+	}
+	if isResolved(outage.Status) {
+		return false, nil
+	}
+	alerts, err := s.storage.ListAlertsByOutage(ctx, outageID)
+	if err != nil {
+		return false, err
+	}
+	if len(alerts) == 0 {
+		return false, nil
+	}
+	for _, a := range alerts {
+		if a.ResolvedAt == nil {
+			return false, nil
+		}
+	}
+
+	status := domain.StatusResolved
+	transition := domain.TransitionRequest{Actor: domain.AutoResolveAuthor, Reason: reason}
+	if _, err := s.updateOutage(ctx, outageID, domain.UpdateOutageRequest{Status: &status}, transition); err != nil {
+		return false, err
+	}
+	s.addAutoResolveNote(ctx, outageID, "outage", "Outage resolved automatically: "+reason+".")
+	return true, nil
+}
+
+// addAutoResolveNote records an automatic resolution on the outage. kind is
+// "alert" or "outage".
+func (s *Service) addAutoResolveNote(ctx context.Context, outageID uuid.UUID, kind, content string) {
+	_, err := s.AddNote(ctx, outageID, domain.AddNoteRequest{
+		Content:  content,
+		Format:   "plaintext",
+		Author:   domain.AutoResolveAuthor,
+		Metadata: map[string]string{domain.NoteMetadataAutoResolved: kind},
+	})
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to record auto-resolution note", "outage_id", outageID, "error", err)
+	}
+}
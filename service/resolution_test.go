@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// autoResolveNotes returns the sorted kinds of the outage's auto-resolution
+// notes
+func autoResolveNotes(t *testing.T, svc *Service, outageID uuid.UUID) []string {
+	t.Helper()
+	notes, err := svc.ListNotesByOutage(context.Background(), outageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, n := range notes {
+		if kind := n.Metadata[domain.NoteMetadataAutoResolved]; kind != "" {
+			if n.Author != domain.AutoResolveAuthor {
+				t.Errorf("auto-resolution note author = %q, want %q", n.Author, domain.AutoResolveAuthor)
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+	// Storage order of notes created in the same instant is not defined
+	sort.Strings(kinds)
+	return kinds
+}
+
+func TestExpireStaleAlerts(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		policy        *domain.AlertResolutionPolicy
+		wantExpired   int
+		wantOutages   int
+		wantStatus    string
+		wantNoteKinds []string
+	}{
+		{
+			name:       "no policy",
+			wantStatus: "open",
+		},
+		{
+			name:          "alerts only",
+			policy:        &domain.AlertResolutionPolicy{MaxOpen: time.Hour},
+			wantExpired:   1,
+			wantStatus:    "open",
+			wantNoteKinds: []string{"alert"},
+		},
+		{
+			name:          "alerts and outages",
+			policy:        &domain.AlertResolutionPolicy{MaxOpen: time.Hour, ResolveOutages: true},
+			wantExpired:   1,
+			wantOutages:   1,
+			wantStatus:    "resolved",
+			wantNoteKinds: []string{"alert", "outage"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newSvc()
+			ctx := context.Background()
+			if tt.policy != nil {
+				svc.SetAlertResolutionPolicy(*tt.policy)
+			}
+
+			stale, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "old", Source: "fake", Title: "disk full", Severity: "high", TriggeredAt: now.Add(-2 * time.Hour)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			fresh, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "new", Source: "fake", Title: "cpu hot", Severity: "high", TriggeredAt: now.Add(-10 * time.Minute)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := svc.ExpireStaleAlerts(ctx, now)
+			if err != nil {
+				t.Fatalf("ExpireStaleAlerts: %v", err)
+			}
+			if result.Expired != tt.wantExpired || result.OutagesResolved != tt.wantOutages || result.Failed != 0 {
+				t.Errorf("result = %+v, want %d expired, %d outages resolved", result, tt.wantExpired, tt.wantOutages)
+			}
+
+			outage, err := svc.GetOutage(ctx, stale.OutageID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if outage.Status != tt.wantStatus {
+				t.Errorf("stale outage status = %q, want %q", outage.Status, tt.wantStatus)
+			}
+			kinds := autoResolveNotes(t, svc, outage.ID)
+			if len(kinds) != len(tt.wantNoteKinds) {
+				t.Fatalf("auto-resolution notes = %v, want %v", kinds, tt.wantNoteKinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tt.wantNoteKinds[i] {
+					t.Errorf("auto-resolution notes = %v, want %v", kinds, tt.wantNoteKinds)
+				}
+			}
+
+			freshAlerts, err := svc.ListAlertsByOutage(ctx, fresh.OutageID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if freshAlerts[0].ResolvedAt != nil {
+				t.Error("alert younger than max_open should stay open")
+			}
+
+			// A second pass finds nothing left to do
+			again, err := svc.ExpireStaleAlerts(ctx, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.Expired != 0 || again.OutagesResolved != 0 {
+				t.Errorf("second pass = %+v, want nothing expired", again)
+			}
+		})
+	}
+}
+
+func TestIngestAlert_ResolvesOutageWhenSourceResolvesAlert(t *testing.T) {
+	for _, resolveOutages := range []bool{false, true} {
+		svc := newSvc()
+		ctx := context.Background()
+		svc.SetAlertResolutionPolicy(domain.AlertResolutionPolicy{ResolveOutages: resolveOutages})
+
+		triggered := time.Now().Add(-time.Hour)
+		a := &notification.Alert{ExternalID: "A1", Source: "fake", Title: "disk full", Severity: "high", TriggeredAt: triggered}
+		alert, err := svc.IngestAlert(ctx, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A second, still-open alert on the same outage holds it open
+		other, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "A2", Source: "fake", Title: "disk full", Severity: "high", TriggeredAt: triggered})
+		if err != nil {
+			t.Fatal(err)
+		}
+		other.OutageID = alert.OutageID
+		if err := svc.storage.UpdateAlert(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+
+		resolved := triggered.Add(30 * time.Minute)
+		a.ResolvedAt = &resolved
+		if _, err := svc.IngestAlert(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+		outage, err := svc.GetOutage(ctx, alert.OutageID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if outage.Status != "open" {
+			t.Fatalf("resolve_outages=%v: outage with an open alert has status %q, want open", resolveOutages, outage.Status)
+		}
+
+		if _, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "A2", Source: "fake", Title: "disk full", Severity: "high", TriggeredAt: triggered, ResolvedAt: &resolved}); err != nil {
+			t.Fatal(err)
+		}
+		outage, err = svc.GetOutage(ctx, alert.OutageID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "open"
+		if resolveOutages {
+			want = "resolved"
+		}
+		if outage.Status != want {
+			t.Errorf("resolve_outages=%v: status = %q, want %q", resolveOutages, outage.Status, want)
+		}
+		if kinds := autoResolveNotes(t, svc, outage.ID); resolveOutages != (len(kinds) == 1) {
+			t.Errorf("resolve_outages=%v: auto-resolution notes = %v", resolveOutages, kinds)
+		}
+	}
+}
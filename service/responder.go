@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// ListOnCall returns who is currently on call. source names a notification
+// service and schedule one of its schedules; either may be empty to look up
+// every service that can report on-calls, or every schedule.
+func (s *Service) ListOnCall(ctx context.Context, source, schedule string) ([]*domain.OnCall, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListOnCall")
+	defer span.End()
+
+	sources := s.onCallSources()
+	if source != "" {
+		svc, ok := s.notificationServices[source]
+		if !ok {
+			return nil, fmt.Errorf("notification service %s: %w", source, domain.ErrNotFound)
+		}
+		if _, ok := svc.(notification.OnCallFetcher); !ok {
+			return nil, fmt.Errorf("notification service %s cannot look up on-call schedules: %w", source, domain.ErrInvalidInput)
+		}
+		sources = []string{source}
+	}
+
+	oncalls := []*domain.OnCall{}
+	for _, name := range sources {
+		fetched, err := s.notificationServices[name].(notification.OnCallFetcher).FetchOnCall(ctx, schedule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch on-call from %s: %w", name, err)
+		}
+		for _, o := range fetched {
+			oncall := &domain.OnCall{
+				Source:       name,
+				ScheduleID:   o.ScheduleID,
+				ScheduleName: o.ScheduleName,
+				Name:         o.Name,
+				Email:        o.Email,
+			}
+			if !o.Start.IsZero() {
+				start := o.Start
+				oncall.Start = &start
+			}
+			if !o.End.IsZero() {
+				end := o.End
+				oncall.End = &end
+			}
+			oncalls = append(oncalls, oncall)
+		}
+	}
+	return oncalls, nil
+}
+
+// onCallSources returns the names of the registered notification services
+// that can report who is on call, sorted
+func (s *Service) onCallSources() []string {
+	var names []string
+	for name, svc := range s.notificationServices {
+		if _, ok := svc.(notification.OnCallFetcher); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AssignResponder makes a responder the holder of a role on an outage,
+// ending the assignment of whoever held it before. Without a responder in
+// the request, whoever is on call for the request's schedule is assigned;
+// if several people are, the first one the provider lists is. Assigning the
+// current holder again returns their existing assignment unchanged.
+func (s *Service) AssignResponder(ctx context.Context, outageID uuid.UUID, req domain.AssignResponderRequest, assignedBy string) (*domain.ResponderAssignment, error) {
+	ctx, span := tracer.Start(ctx, "Service.AssignResponder")
+	defer span.End()
+
+	if !domain.IsValidResponderRole(req.Role) {
+		return nil, fmt.Errorf("unknown responder role %q: %w", req.Role, domain.ErrInvalidInput)
+	}
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return nil, err
+	}
+
+	assignment := &domain.ResponderAssignment{
+		ID:         uuid.New(),
+		OutageID:   outageID,
+		Role:       req.Role,
+		Responder:  strings.TrimSpace(req.Responder),
+		AssignedBy: assignedBy,
+		AssignedAt: time.Now(),
+	}
+	if assignment.Responder == "" {
+		oncall, err := s.currentOnCall(ctx, req.Source, req.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		assignment.Responder = oncall.Email
+		if assignment.Responder == "" {
+			assignment.Responder = oncall.Name
+		}
+		assignment.Source = oncall.Source
+		assignment.ScheduleID = oncall.ScheduleID
+	}
+
+	current, err := s.currentResponder(ctx, outageID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		if strings.EqualFold(current.Responder, assignment.Responder) {
+			return current, nil
+		}
+		if err := s.storage.EndResponderAssignment(ctx, current.ID, assignedBy, assignment.AssignedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.storage.CreateResponderAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "responder assigned", "outage_id", outageID, "role", req.Role, "responder", assignment.Responder)
+	return assignment, nil
+}
+
+// currentOnCall returns the first person on call for a schedule, for
+// assigning them as a responder
+func (s *Service) currentOnCall(ctx context.Context, source, schedule string) (*domain.OnCall, error) {
+	if source == "" || schedule == "" {
+		return nil, fmt.Errorf("a responder, or a source and schedule to take the on-call from, is required: %w", domain.ErrInvalidInput)
+	}
+	oncalls, err := s.ListOnCall(ctx, source, schedule)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range oncalls {
+		if o.Email != "" || o.Name != "" {
+			return o, nil
+		}
+	}
+	return nil, fmt.Errorf("nobody is on call for %s schedule %s: %w", source, schedule, domain.ErrInvalidInput)
+}
+
+// UnassignResponder ends the current assignment of a role on an outage. It
+// returns domain.ErrNotFound when nobody holds the role.
+func (s *Service) UnassignResponder(ctx context.Context, outageID uuid.UUID, role, unassignedBy string) error {
+	ctx, span := tracer.Start(ctx, "Service.UnassignResponder")
+	defer span.End()
+
+	if !domain.IsValidResponderRole(role) {
+		return fmt.Errorf("unknown responder role %q: %w", role, domain.ErrInvalidInput)
+	}
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return err
+	}
+
+	current, err := s.currentResponder(ctx, outageID, role)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("outage %s has no %s: %w", outageID, roleLabel(role), domain.ErrNotFound)
+	}
+	return s.storage.EndResponderAssignment(ctx, current.ID, unassignedBy, time.Now())
+}
+
+// ListResponders returns an outage's current responders, in the order of
+// domain.ValidResponderRoles, and its full assignment history
+func (s *Service) ListResponders(ctx context.Context, outageID uuid.UUID) (*domain.OutageResponders, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListResponders")
+	defer span.End()
+
+	if _, err := s.liveOutage(ctx, outageID); err != nil {
+		return nil, err
+	}
+	assignments, err := s.storage.ListResponderAssignments(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+
+	responders := &domain.OutageResponders{
+		Current: []*domain.ResponderAssignment{},
+		History: []*domain.ResponderAssignment{},
+	}
+	responders.History = append(responders.History, assignments...)
+	for _, role := range domain.ValidResponderRoles {
+		for _, a := range assignments {
+			if a.Role == role && a.Active() {
+				responders.Current = append(responders.Current, a)
+			}
+		}
+	}
+	return responders, nil
+}
+
+// currentResponder returns the active assignment of role on an outage, or
+// nil when nobody holds it
+func (s *Service) currentResponder(ctx context.Context, outageID uuid.UUID, role string) (*domain.ResponderAssignment, error) {
+	assignments, err := s.storage.ListResponderAssignments(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assignments {
+		if a.Role == role && a.Active() {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetResponderLoad counts the responder assignments made in a time range
+// per responder, busiest first. Responders are matched case-insensitively
+// and reported as first assigned.
+func (s *Service) GetResponderLoad(ctx context.Context, since, until time.Time) (*domain.ResponderLoad, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetResponderLoad")
+	defer span.End()
+
+	if !since.Before(until) {
+		return nil, fmt.Errorf("since must be before until: %w", domain.ErrInvalidInput)
+	}
+	assignments, err := s.storage.ListResponderAssignmentsBetween(ctx, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list responder assignments: %w", err)
+	}
+
+	load := &domain.ResponderLoad{
+		Since:      since,
+		Until:      until,
+		Responders: []domain.ResponderLoadEntry{},
+	}
+	entries := make(map[string]*domain.ResponderLoadEntry)
+	outages := make(map[string]map[uuid.UUID]bool)
+	var order []string
+	for _, a := range assignments {
+		key := strings.ToLower(a.Responder)
+		entry, ok := entries[key]
+		if !ok {
+			entry = &domain.ResponderLoadEntry{Responder: a.Responder, Roles: map[string]int{}}
+			entries[key] = entry
+			outages[key] = make(map[uuid.UUID]bool)
+			order = append(order, key)
+		}
+		entry.Assignments++
+		entry.Roles[a.Role]++
+		outages[key][a.OutageID] = true
+		load.Total++
+	}
+
+	for _, key := range order {
+		entry := entries[key]
+		entry.Outages = len(outages[key])
+		load.Responders = append(load.Responders, *entry)
+	}
+	sort.SliceStable(load.Responders, func(i, j int) bool {
+		if load.Responders[i].Assignments != load.Responders[j].Assignments {
+			return load.Responders[i].Assignments > load.Responders[j].Assignments
+		}
+		return load.Responders[i].Responder < load.Responders[j].Responder
+	})
+	return load, nil
+}
+
+// roleLabel returns a responder role as words, e.g. "incident commander"
+func roleLabel(role string) string {
+	return strings.ReplaceAll(role, "_", " ")
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// fakeOnCallSource is a notification service with fixed on-call schedules
+type fakeOnCallSource struct {
+	fakeWebhookSource
+	oncalls []*notification.OnCall
+}
+
+func (f *fakeOnCallSource) FetchOnCall(_ context.Context, scheduleID string) ([]*notification.OnCall, error) {
+	var out []*notification.OnCall
+	for _, o := range f.oncalls {
+		if scheduleID == "" || o.ScheduleID == scheduleID {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func TestListOnCall(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(&fakeOnCallSource{oncalls: []*notification.OnCall{
+		{ScheduleID: "S1", ScheduleName: "Primary", Name: "Alice", Email: "alice@example.com", Start: time.Now().Add(-time.Hour)},
+		{ScheduleID: "S2", ScheduleName: "Secondary", Name: "Bob", Email: "bob@example.com"},
+	}})
+	ctx := context.Background()
+
+	all, err := svc.ListOnCall(ctx, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Source != "fake" || all[0].Start == nil || all[1].End != nil {
+		t.Errorf("ListOnCall() = %+v, want both schedules from fake", all)
+	}
+
+	primary, err := svc.ListOnCall(ctx, "fake", "S1")
+	if err != nil || len(primary) != 1 || primary[0].Email != "alice@example.com" {
+		t.Errorf("ListOnCall(fake, S1) = %+v, %v, want alice", primary, err)
+	}
+
+	if _, err := svc.ListOnCall(ctx, "unknown", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown source error = %v, want ErrNotFound", err)
+	}
+	svc.RegisterNotificationService(namedSource("plain"))
+	if _, err := svc.ListOnCall(ctx, "plain", ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("source without schedules error = %v, want ErrInvalidInput", err)
+	}
+}
+
+// namedSource is a notification service that cannot look up on-calls
+type namedSource string
+
+func (f namedSource) Name() string { return string(f) }
+
+func (namedSource) FetchAlert(context.Context, string) (*notification.Alert, error) {
+	return nil, nil
+}
+
+func (namedSource) FetchRecentAlerts(context.Context, time.Time) ([]*notification.Alert, error) {
+	return nil, nil
+}
+
+func (namedSource) CreateIncident(context.Context, notification.IncidentRequest) (*notification.Alert, error) {
+	return nil, nil
+}
+
+func (namedSource) AcknowledgeAlert(context.Context, string, string) error { return nil }
+
+func (namedSource) ResolveAlert(context.Context, string, string) error { return nil }
+
+func (namedSource) WebhookHandler() interface{} { return nil }
+
+func TestAssignResponder(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(&fakeOnCallSource{oncalls: []*notification.OnCall{
+		{ScheduleID: "S1", Name: "Alice", Email: "alice@example.com"},
+	}})
+	ctx := context.Background()
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a responder, the on-call for the schedule is assigned
+	ic, err := svc.AssignResponder(ctx, o.ID, domain.AssignResponderRequest{
+		Role: domain.RoleIncidentCommander, Source: "fake", Schedule: "S1",
+	}, "bob@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ic.Responder != "alice@example.com" || ic.Source != "fake" || ic.ScheduleID != "S1" || ic.AssignedBy != "bob@example.com" {
+		t.Errorf("assignment = %+v, want alice from fake S1", ic)
+	}
+
+	// Assigning the holder again keeps the existing assignment
+	again, err := svc.AssignResponder(ctx, o.ID, domain.AssignResponderRequest{
+		Role: domain.RoleIncidentCommander, Responder: "Alice@example.com",
+	}, "bob@example.com")
+	if err != nil || again.ID != ic.ID {
+		t.Errorf("reassigning alice = %+v, %v, want the existing assignment", again, err)
+	}
+
+	// Handing the role over ends the previous assignment
+	if _, err := svc.AssignResponder(ctx, o.ID, domain.AssignResponderRequest{
+		Role: domain.RoleIncidentCommander, Responder: "carol@example.com",
+	}, "carol@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AssignResponder(ctx, o.ID, domain.AssignResponderRequest{
+		Role: domain.RoleCommsLead, Responder: "dave@example.com",
+	}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	responders, err := svc.ListResponders(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responders.History) != 3 {
+		t.Fatalf("history = %+v, want three assignments", responders.History)
+	}
+	if responders.History[0].Active() || responders.History[0].UnassignedBy != "carol@example.com" {
+		t.Errorf("alice's assignment = %+v, want ended by carol", responders.History[0])
+	}
+	if len(responders.Current) != 2 || responders.Current[0].Responder != "carol@example.com" || responders.Current[1].Role != domain.RoleCommsLead {
+		t.Errorf("current = %+v, want carol as incident commander then dave as comms lead", responders.Current)
+	}
+
+	if err := svc.UnassignResponder(ctx, o.ID, domain.RoleCommsLead, "carol@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UnassignResponder(ctx, o.ID, domain.RoleCommsLead, "carol@example.com"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unassigning an empty role error = %v, want ErrNotFound", err)
+	}
+
+	events, err := svc.GetOutageTimeline(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, e := range events {
+		counts[e.Type]++
+	}
+	if counts[domain.TimelineResponderAssigned] != 3 || counts[domain.TimelineResponderUnassigned] != 2 {
+		t.Errorf("timeline responder events = %v, want 3 assigned and 2 unassigned", counts)
+	}
+}
+
+func TestAssignResponder_Invalid(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(&fakeOnCallSource{})
+	ctx := context.Background()
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  domain.AssignResponderRequest
+	}{
+		{"unknown role", domain.AssignResponderRequest{Role: "hero", Responder: "alice@example.com"}},
+		{"no responder or schedule", domain.AssignResponderRequest{Role: domain.RoleScribe}},
+		{"nobody on call", domain.AssignResponderRequest{Role: domain.RoleScribe, Source: "fake", Schedule: "S9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.AssignResponder(ctx, o.ID, tt.req, ""); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestGetResponderLoad(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	assign := func(outage *domain.Outage, role, responder string) {
+		t.Helper()
+		if _, err := svc.AssignResponder(ctx, outage.ID, domain.AssignResponderRequest{Role: role, Responder: responder}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var outages []*domain.Outage
+	for range 2 {
+		o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "high"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		outages = append(outages, o)
+	}
+	assign(outages[0], domain.RoleIncidentCommander, "alice@example.com")
+	assign(outages[0], domain.RoleCommsLead, "bob@example.com")
+	assign(outages[1], domain.RoleIncidentCommander, "ALICE@example.com")
+	assign(outages[1], domain.RoleScribe, "alice@example.com")
+
+	now := time.Now()
+	load, err := svc.GetResponderLoad(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if load.Total != 4 || len(load.Responders) != 2 {
+		t.Fatalf("load = %+v, want four assignments to two responders", load)
+	}
+	alice := load.Responders[0]
+	if alice.Responder != "alice@example.com" || alice.Assignments != 3 || alice.Outages != 2 || alice.Roles[domain.RoleIncidentCommander] != 2 {
+		t.Errorf("busiest responder = %+v, want alice with 3 assignments on 2 outages", alice)
+	}
+
+	if _, err := svc.GetResponderLoad(ctx, now, now); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("empty range error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// retentionBatchSize is the most outages one retention run archives, which
+// bounds the size of each archive file. Outages left over are archived by
+// the next run.
+const retentionBatchSize = 1000
+
+// retention is the installed retention policy and where archives are kept
+type retention struct {
+	policy   domain.RetentionPolicy
+	archives BlobStore
+	running  sync.Mutex // Held for the duration of a run
+}
+
+// SetRetentionPolicy enables retention runs, writing archive files to
+// archives. ArchiveAfter must be positive, and PurgeAfter zero or longer
+// than ArchiveAfter.
+func (s *Service) SetRetentionPolicy(archives BlobStore, policy domain.RetentionPolicy) error {
+	if policy.ArchiveAfter <= 0 {
+		return fmt.Errorf("%w: retention archive_after must be positive", domain.ErrInvalidInput)
+	}
+	if policy.PurgeAfter < 0 || (policy.PurgeAfter > 0 && policy.PurgeAfter <= policy.ArchiveAfter) {
+		return fmt.Errorf("%w: retention purge_after must be longer than archive_after", domain.ErrInvalidInput)
+	}
+	s.retention = &retention{policy: policy, archives: archives}
+	return nil
+}
+
+// RunRetention applies the retention policy and waits for it to finish.
+// Outages resolved or closed more than ArchiveAfter before now are written
+// to an archive file, an outage export that can be imported again, and
+// deleted from the database along with their attachments. Archives whose
+// outages were all resolved more than PurgeAfter before now are deleted.
+// The run is recorded, and returned even when it fails.
+func (s *Service) RunRetention(ctx context.Context, now time.Time, trigger, triggeredBy string) (*domain.RetentionRun, error) {
+	ctx, span := tracer.Start(ctx, "Service.RunRetention")
+	defer span.End()
+
+	run, err := s.beginRetentionRun(ctx, now, trigger, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+	defer s.retention.running.Unlock()
+	err = s.applyRetention(ctx, run, now)
+	return run, err
+}
+
+// StartRetentionRun starts a manual retention run in the background and
+// returns it while it is running. Only one run happens at a time; starting
+// another returns domain.ErrConflict.
+func (s *Service) StartRetentionRun(ctx context.Context, triggeredBy string) (*domain.RetentionRun, error) {
+	ctx, span := tracer.Start(ctx, "Service.StartRetentionRun")
+	defer span.End()
+
+	now := time.Now()
+	run, err := s.beginRetentionRun(ctx, now, domain.RetentionTriggerManual, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+	started := *run
+	go func() {
+		defer s.retention.running.Unlock()
+		// The run outlives the request that started it
+		_ = s.applyRetention(context.WithoutCancel(ctx), run, now)
+	}()
+	return &started, nil
+}
+
+// GetRetentionRun retrieves a retention run by ID
+func (s *Service) GetRetentionRun(ctx context.Context, id uuid.UUID) (*domain.RetentionRun, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetRetentionRun")
+	defer span.End()
+
+	return s.storage.GetRetentionRun(ctx, id)
+}
+
+// ListRetentionRuns lists retention runs, most recently started first
+func (s *Service) ListRetentionRuns(ctx context.Context) ([]*domain.RetentionRun, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListRetentionRuns")
+	defer span.End()
+
+	return s.storage.ListRetentionRuns(ctx)
+}
+
+// beginRetentionRun takes the run lock and records a running run. The
+// caller releases the lock once the run has been applied.
+func (s *Service) beginRetentionRun(ctx context.Context, now time.Time, trigger, triggeredBy string) (*domain.RetentionRun, error) {
+	if s.retention == nil {
+		return nil, fmt.Errorf("%w: no retention policy is configured", domain.ErrInvalidInput)
+	}
+	if !s.retention.running.TryLock() {
+		return nil, fmt.Errorf("%w: a retention run is already in progress", domain.ErrConflict)
+	}
+	run := &domain.RetentionRun{
+		ID:             uuid.New(),
+		Trigger:        trigger,
+		TriggeredBy:    triggeredBy,
+		Status:         domain.RetentionRunRunning,
+		ArchivedBefore: now.Add(-s.retention.policy.ArchiveAfter).UTC(),
+		StartedAt:      now.UTC(),
+	}
+	if err := s.storage.CreateRetentionRun(ctx, run); err != nil {
+		s.retention.running.Unlock()
+		return nil, err
+	}
+	return run, nil
+}
+
+// applyRetention archives and purges for a recorded run, then saves its
+// outcome
+func (s *Service) applyRetention(ctx context.Context, run *domain.RetentionRun, now time.Time) error {
+	err := s.archiveOutages(ctx, run, now)
+	if err == nil {
+		err = s.purgeArchives(ctx, run, now)
+	}
+
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.Status = domain.RetentionRunCompleted
+	if err != nil {
+		run.Status = domain.RetentionRunFailed
+		run.Error = err.Error()
+	}
+	if updateErr := s.storage.UpdateRetentionRun(ctx, run); updateErr != nil && err == nil {
+		err = updateErr
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "retention run failed", "run_id", run.ID, "error", err)
+		return err
+	}
+	s.logger.InfoContext(ctx, "retention run completed", "run_id", run.ID,
+		"archived_outages", run.ArchivedOutages, "archive_key", run.ArchiveKey, "purged_archives", run.PurgedArchives)
+	return nil
+}
+
+// archiveOutages writes the outages due for archiving to an archive file and
+// deletes them from the database
+func (s *Service) archiveOutages(ctx context.Context, run *domain.RetentionRun, now time.Time) error {
+	var outages []*domain.Outage
+	for offset := 0; len(outages) < retentionBatchSize; offset += exportPageSize {
+		page, err := s.storage.ListOutages(ctx, exportPageSize, offset, false)
+		if err != nil {
+			return fmt.Errorf("failed to list outages: %w", err)
+		}
+		for _, outage := range page {
+			if len(outages) < retentionBatchSize && isResolved(outage.Status) && resolvedTime(outage).Before(run.ArchivedBefore) {
+				outages = append(outages, outage)
+			}
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+	if len(outages) == 0 {
+		return nil
+	}
+	if err := s.storage.LoadOutageAssociations(ctx, outages, domain.AllOutageAssociations); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(domain.OutageExport{Version: domain.OutageExportVersion, ExportedAt: now.UTC(), Outages: outages})
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("retention/outages-%s.json", run.StartedAt.Format("20060102T150405Z"))
+	if err := s.retention.archives.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/json"); err != nil {
+		return fmt.Errorf("failed to write archive %s: %w", key, err)
+	}
+	run.ArchiveKey = key
+
+	for _, outage := range outages {
+		if err := s.deleteArchivedOutage(ctx, outage.ID); err != nil {
+			return err
+		}
+		run.ArchivedOutages++
+	}
+	return nil
+}
+
+// deleteArchivedOutage deletes an archived outage and its attachments'
+// content, which is not kept in the archive
+func (s *Service) deleteArchivedOutage(ctx context.Context, id uuid.UUID) error {
+	if s.blobs != nil {
+		attachments, err := s.storage.ListAttachmentsByOutage(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to list attachments of outage %s: %w", id, err)
+		}
+		for _, attachment := range attachments {
+			s.deleteBlob(ctx, attachment)
+		}
+	}
+	if err := s.storage.DeleteOutage(ctx, id); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("failed to delete archived outage %s: %w", id, err)
+	}
+	return nil
+}
+
+// purgeArchives deletes the archive files of earlier runs whose outages
+// were all resolved more than PurgeAfter before now
+func (s *Service) purgeArchives(ctx context.Context, run *domain.RetentionRun, now time.Time) error {
+	if s.retention.policy.PurgeAfter <= 0 {
+		return nil
+	}
+	cutoff := now.Add(-s.retention.policy.PurgeAfter)
+	runs, err := s.storage.ListRetentionRuns(ctx)
+	if err != nil {
+		return err
+	}
+	for _, earlier := range runs {
+		if earlier.ID == run.ID || earlier.ArchiveKey == "" || earlier.ArchivePurgedAt != nil || !earlier.ArchivedBefore.Before(cutoff) {
+			continue
+		}
+		if err := s.retention.archives.Delete(ctx, earlier.ArchiveKey); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("failed to delete archive %s: %w", earlier.ArchiveKey, err)
+		}
+		purged := now.UTC()
+		earlier.ArchivePurgedAt = &purged
+		if err := s.storage.UpdateRetentionRun(ctx, earlier); err != nil {
+			return err
+		}
+		run.PurgedArchives++
+	}
+	return nil
+}
+
+// resolvedTime is when an outage was resolved, or last updated for outages
+// closed without being resolved first
+func resolvedTime(outage *domain.Outage) time.Time {
+	if outage.ResolvedAt != nil {
+		return *outage.ResolvedAt
+	}
+	return outage.UpdatedAt
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+const year = 365 * 24 * time.Hour
+
+func TestRunRetentionArchivesAndPurges(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	archives := newMemBlobStore()
+	if err := svc.SetRetentionPolicy(archives, domain.RetentionPolicy{ArchiveAfter: 2 * year, PurgeAfter: 5 * year}); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "DB down", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddNote(ctx, resolved.ID, domain.AddNoteRequest{Content: "Failed over", Author: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	status := domain.StatusResolved
+	if _, err := svc.UpdateOutage(ctx, resolved.ID, domain.UpdateOutageRequest{Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+	open, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Slow search", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing has been resolved for long enough yet
+	run, err := svc.RunRetention(ctx, time.Now(), domain.RetentionTriggerScheduled, "")
+	if err != nil {
+		t.Fatalf("RunRetention() err = %v", err)
+	}
+	if run.Status != domain.RetentionRunCompleted || run.ArchivedOutages != 0 || run.ArchiveKey != "" {
+		t.Errorf("first run = %+v, want completed with nothing archived", run)
+	}
+
+	start := time.Now()
+	run, err = svc.RunRetention(ctx, start.Add(3*year), domain.RetentionTriggerScheduled, "")
+	if err != nil {
+		t.Fatalf("RunRetention() err = %v", err)
+	}
+	if run.ArchivedOutages != 1 || run.ArchiveKey == "" {
+		t.Fatalf("run = %+v, want the resolved outage archived", run)
+	}
+	var archive domain.OutageExport
+	if err := json.Unmarshal(archives.blobs[run.ArchiveKey], &archive); err != nil {
+		t.Fatalf("archive %s: %v", run.ArchiveKey, err)
+	}
+	if len(archive.Outages) != 1 || archive.Outages[0].ID != resolved.ID || len(archive.Outages[0].Notes) != 1 {
+		t.Errorf("archive = %+v, want the resolved outage with its note", archive.Outages)
+	}
+	if _, err := svc.GetOutage(ctx, resolved.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutage(archived) err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.GetOutage(ctx, open.ID); err != nil {
+		t.Errorf("GetOutage(open) err = %v, want it kept", err)
+	}
+
+	// The archive holds outages resolved up to a year after start, so it is
+	// deleted once that is more than five years ago
+	purging, err := svc.RunRetention(ctx, start.Add(6*year+time.Hour), domain.RetentionTriggerScheduled, "")
+	if err != nil {
+		t.Fatalf("RunRetention() err = %v", err)
+	}
+	if purging.PurgedArchives != 1 || len(archives.blobs) != 0 {
+		t.Errorf("purging run = %+v with %d archives left, want the archive purged", purging, len(archives.blobs))
+	}
+	archived, err := svc.GetRetentionRun(ctx, run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived.ArchivePurgedAt == nil {
+		t.Errorf("archiving run = %+v, want archive_purged_at set", archived)
+	}
+
+	runs, err := svc.ListRetentionRuns(ctx)
+	if err != nil || len(runs) != 3 || runs[0].ID != purging.ID {
+		t.Errorf("ListRetentionRuns = %d runs, %v; want 3, most recent first", len(runs), err)
+	}
+}
+
+func TestStartRetentionRun(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	if _, err := svc.StartRetentionRun(ctx, "admin@example.com"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("StartRetentionRun without a policy err = %v, want ErrInvalidInput", err)
+	}
+	if err := svc.SetRetentionPolicy(newMemBlobStore(), domain.RetentionPolicy{ArchiveAfter: year}); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := svc.StartRetentionRun(ctx, "admin@example.com")
+	if err != nil {
+		t.Fatalf("StartRetentionRun() err = %v", err)
+	}
+	if run.Trigger != domain.RetentionTriggerManual || run.TriggeredBy != "admin@example.com" || run.Status != domain.RetentionRunRunning {
+		t.Errorf("run = %+v, want a running manual run", run)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := svc.GetRetentionRun(ctx, run.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status == domain.RetentionRunCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run = %+v, want it completed", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	svc.retention.running.Lock()
+	defer svc.retention.running.Unlock()
+	if _, err := svc.StartRetentionRun(ctx, "admin@example.com"); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("StartRetentionRun during a run err = %v, want ErrConflict", err)
+	}
+}
+
+func TestSetRetentionPolicyValidates(t *testing.T) {
+	svc := newSvc()
+	for _, policy := range []domain.RetentionPolicy{
+		{},
+		{ArchiveAfter: 2 * year, PurgeAfter: year},
+		{ArchiveAfter: year, PurgeAfter: -year},
+	} {
+		if err := svc.SetRetentionPolicy(newMemBlobStore(), policy); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("SetRetentionPolicy(%+v) err = %v, want ErrInvalidInput", policy, err)
+		}
+	}
+}
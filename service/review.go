@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// validReviewStatuses lists the accepted post-resolution review states
+var validReviewStatuses = map[string]bool{
+	domain.ReviewNeedsReview: true,
+	domain.ReviewScheduled:   true,
+	domain.ReviewReviewed:    true,
+}
+
+// isResolved reports whether an operational status ends the outage
+func isResolved(status string) bool {
+	return status == "resolved" || status == "closed"
+}
+
+// openReview puts a newly resolved outage into the review backlog. Outages
+// that already have a review (e.g. reopened and resolved again) keep it.
+func (s *Service) openReview(ctx context.Context, outageID uuid.UUID, at time.Time) error {
+	_, err := s.storage.GetOutageReview(ctx, outageID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	return s.storage.UpsertOutageReview(ctx, &domain.OutageReview{
+		OutageID:  outageID,
+		Status:    domain.ReviewNeedsReview,
+		CreatedAt: at,
+		UpdatedAt: at,
+	})
+}
+
+// GetOutageReview returns the review state of an outage. It returns
+// domain.ErrNotFound if the outage has not been resolved yet.
+func (s *Service) GetOutageReview(ctx context.Context, outageID uuid.UUID) (*domain.OutageReview, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetOutageReview")
+	defer span.End()
+
+	if _, err := s.liveOutage(ctx, outageID); err != nil {
+		return nil, err
+	}
+	return s.storage.GetOutageReview(ctx, outageID)
+}
+
+// UpdateOutageReview moves a resolved outage through the review workflow.
+// reviewer is recorded when the outage is marked reviewed.
+func (s *Service) UpdateOutageReview(ctx context.Context, outageID uuid.UUID, req domain.UpdateReviewRequest, reviewer string) (*domain.OutageReview, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateOutageReview")
+	defer span.End()
+
+	if err := s.authorizeOutageChange(ctx, outageID); err != nil {
+		return nil, err
+	}
+	if !validReviewStatuses[req.Status] {
+		return nil, fmt.Errorf("invalid review status %q: %w", req.Status, domain.ErrInvalidInput)
+	}
+	if req.Status == domain.ReviewScheduled && req.ScheduledFor == nil {
+		return nil, fmt.Errorf("scheduled_for is required for %s: %w", domain.ReviewScheduled, domain.ErrInvalidInput)
+	}
+
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if !isResolved(outage.Status) {
+		return nil, fmt.Errorf("outage must be resolved before it can be reviewed: %w", domain.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	review, err := s.storage.GetOutageReview(ctx, outageID)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Outages resolved before the review workflow existed
+		review = &domain.OutageReview{OutageID: outageID, CreatedAt: now}
+		if outage.ResolvedAt != nil {
+			review.CreatedAt = *outage.ResolvedAt
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	review.Status = req.Status
+	switch req.Status {
+	case domain.ReviewNeedsReview:
+		review.ScheduledFor, review.ReviewedAt, review.Reviewer = nil, nil, ""
+	case domain.ReviewScheduled:
+		review.ScheduledFor = req.ScheduledFor
+		review.ReviewedAt, review.Reviewer = nil, ""
+	case domain.ReviewReviewed:
+		review.ReviewedAt = &now
+		review.Reviewer = reviewer
+	}
+	review.UpdatedAt = now
+
+	if err := s.storage.UpsertOutageReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// ListOutageReviews returns outage reviews in the given state, oldest first.
+// An empty status lists every review.
+func (s *Service) ListOutageReviews(ctx context.Context, status string) ([]*domain.OutageReview, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListOutageReviews")
+	defer span.End()
+
+	if status != "" && !validReviewStatuses[status] {
+		return nil, fmt.Errorf("invalid review status %q: %w", status, domain.ErrInvalidInput)
+	}
+	return s.storage.ListOutageReviews(ctx, status)
+}
+
+// OverdueReviews returns reviews that need chasing: outages that have waited
+// longer than needsReviewAfter for a review to be scheduled, and scheduled
+// reviews whose date has passed without the outage being marked reviewed.
+func (s *Service) OverdueReviews(ctx context.Context, now time.Time, needsReviewAfter time.Duration) ([]*domain.OutageReview, error) {
+	ctx, span := tracer.Start(ctx, "Service.OverdueReviews")
+	defer span.End()
+
+	pending, err := s.storage.ListOutageReviews(ctx, domain.ReviewNeedsReview)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := s.storage.ListOutageReviews(ctx, domain.ReviewScheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	var overdue []*domain.OutageReview
+	for _, r := range pending {
+		if now.Sub(r.CreatedAt) >= needsReviewAfter {
+			overdue = append(overdue, r)
+		}
+	}
+	for _, r := range scheduled {
+		if r.ScheduledFor != nil && r.ScheduledFor.Before(now) {
+			overdue = append(overdue, r)
+		}
+	}
+	return overdue, nil
+}
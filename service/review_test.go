@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestReviewWorkflow(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.GetOutageReview(ctx, outage.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("open outage should have no review, got %v", err)
+	}
+	_, err = svc.UpdateOutageReview(ctx, outage.ID, domain.UpdateReviewRequest{Status: domain.ReviewReviewed}, "alice@example.com")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("reviewing an open outage: got %v, want ErrInvalidInput", err)
+	}
+
+	resolved := "resolved"
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &resolved}); err != nil {
+		t.Fatal(err)
+	}
+	review, err := svc.GetOutageReview(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("resolved outage should enter review: %v", err)
+	}
+	if review.Status != domain.ReviewNeedsReview {
+		t.Errorf("Status = %q, want %q", review.Status, domain.ReviewNeedsReview)
+	}
+
+	_, err = svc.UpdateOutageReview(ctx, outage.ID, domain.UpdateReviewRequest{Status: domain.ReviewScheduled}, "")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("scheduling without a date: got %v, want ErrInvalidInput", err)
+	}
+	yesterday := time.Now().Add(-24 * time.Hour)
+	if _, err := svc.UpdateOutageReview(ctx, outage.ID, domain.UpdateReviewRequest{Status: domain.ReviewScheduled, ScheduledFor: &yesterday}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	overdue, err := svc.OverdueReviews(ctx, time.Now(), 72*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overdue) != 1 {
+		t.Errorf("expected scheduled review in the past to be overdue, got %d", len(overdue))
+	}
+
+	review, err = svc.UpdateOutageReview(ctx, outage.ID, domain.UpdateReviewRequest{Status: domain.ReviewReviewed}, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.ReviewedAt == nil || review.Reviewer != "alice@example.com" {
+		t.Errorf("reviewed review = %+v", review)
+	}
+
+	reviewed, err := svc.ListOutageReviews(ctx, domain.ReviewReviewed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviewed) != 1 {
+		t.Errorf("ListOutageReviews(reviewed) returned %d, want 1", len(reviewed))
+	}
+	if _, err := svc.ListOutageReviews(ctx, "bogus"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unknown status filter: got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestOverdueReviews_NeedsReviewThreshold(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Description: "d", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "closed"
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &closed}); err != nil {
+		t.Fatal(err)
+	}
+
+	overdue, err := svc.OverdueReviews(ctx, time.Now(), 72*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overdue) != 0 {
+		t.Errorf("fresh review should not be overdue, got %d", len(overdue))
+	}
+	overdue, err = svc.OverdueReviews(ctx, time.Now().Add(73*time.Hour), 72*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overdue) != 1 {
+		t.Errorf("review past threshold should be overdue, got %d", len(overdue))
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// ListNoteRevisions returns the versions of a note that edits replaced,
+// oldest first. Notes in the trash are not found.
+func (s *Service) ListNoteRevisions(ctx context.Context, noteID uuid.UUID) ([]*domain.NoteRevision, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListNoteRevisions")
+	defer span.End()
+
+	if _, err := s.liveNote(ctx, noteID); err != nil {
+		return nil, err
+	}
+	return s.storage.ListNoteRevisions(ctx, noteID)
+}
+
+// recordRevision keeps the content and format a note had before an edit by
+// editor. Concurrent edits of the same note fail with domain.ErrConflict for
+// all but one editor.
+func (s *Service) recordRevision(ctx context.Context, noteID uuid.UUID, content, format, editor string, at time.Time) error {
+	revisions, err := s.storage.ListNoteRevisions(ctx, noteID)
+	if err != nil {
+		return err
+	}
+	version := 1
+	if len(revisions) > 0 {
+		version = revisions[len(revisions)-1].Version + 1
+	}
+	return s.storage.CreateNoteRevision(ctx, &domain.NoteRevision{
+		ID:       uuid.New(),
+		NoteID:   noteID,
+		Version:  version,
+		Content:  content,
+		Format:   format,
+		EditedBy: editor,
+		EditedAt: at,
+	})
+}
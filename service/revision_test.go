@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestUpdateNoteRecordsRevisions(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "root cause: DNS", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := "root cause: expired certificate"
+	if _, err := svc.UpdateNote(ctx, note.ID, &content, nil, nil, nil, "bob@example.com"); err != nil {
+		t.Fatalf("UpdateNote(content) err = %v", err)
+	}
+	format := domain.NoteFormatMarkdown
+	if _, err := svc.UpdateNote(ctx, note.ID, nil, &format, nil, nil, "carol@example.com"); err != nil {
+		t.Fatalf("UpdateNote(format) err = %v", err)
+	}
+	// Edits that leave content and format alone are not revisions
+	if _, err := svc.UpdateNote(ctx, note.ID, &content, nil, map[string]string{"reviewed": "true"}, nil, "dave@example.com"); err != nil {
+		t.Fatalf("UpdateNote(metadata) err = %v", err)
+	}
+
+	revisions, err := svc.ListNoteRevisions(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("ListNoteRevisions() err = %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(revisions))
+	}
+	want := []domain.NoteRevision{
+		{Version: 1, Content: "root cause: DNS", Format: domain.NoteFormatPlaintext, EditedBy: "bob@example.com"},
+		{Version: 2, Content: content, Format: domain.NoteFormatPlaintext, EditedBy: "carol@example.com"},
+	}
+	for i, r := range revisions {
+		if r.NoteID != note.ID || r.Version != want[i].Version || r.Content != want[i].Content ||
+			r.Format != want[i].Format || r.EditedBy != want[i].EditedBy || r.EditedAt.IsZero() {
+			t.Errorf("revision %d = %+v, want %+v", i, r, want[i])
+		}
+	}
+
+	if err := svc.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ListNoteRevisions(ctx, note.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("ListNoteRevisions(trashed) err = %v, want ErrNotFound", err)
+	}
+}
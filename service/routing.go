@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// routingEvaluationWindow is the range a routing dry run covers when no
+// since is given
+const routingEvaluationWindow = 7 * 24 * time.Hour
+
+// errSuppressed is returned by storeAlert for an alert a routing rule drops
+var errSuppressed = fmt.Errorf("alert suppressed by a routing rule: %w", domain.ErrConflict)
+
+// routingRule is a routing rule with its title pattern compiled
+type routingRule struct {
+	domain.RoutingRule
+	title *regexp.Regexp
+}
+
+// compileRoutingRule compiles the title pattern of rule, which is matched
+// case-insensitively
+func compileRoutingRule(rule domain.RoutingRule) (routingRule, error) {
+	compiled := routingRule{RoutingRule: rule}
+	if rule.Match.Title != "" {
+		re, err := regexp.Compile("(?i)" + rule.Match.Title)
+		if err != nil {
+			return compiled, fmt.Errorf("routing rule %q has invalid title pattern: %v: %w", rule.Name, err, domain.ErrInvalidInput)
+		}
+		compiled.title = re
+	}
+	return compiled, nil
+}
+
+// validateRoutingRule checks that a rule does something, that its
+// severities are known and that its title pattern compiles. Team names
+// are checked against the config by validateOpsConfig, and services
+// against the catalogue by checkRoutingServices.
+func validateRoutingRule(rule domain.RoutingRule) error {
+	if rule.Team == "" && rule.Service == "" && len(rule.Tags) == 0 && rule.SetSeverity == "" && !rule.AttachToOpenOutage && !rule.Suppress {
+		return fmt.Errorf("routing rule %q must set a team, service, tags, set_severity, attach_to_open_outage or suppress: %w", rule.Name, domain.ErrInvalidInput)
+	}
+	if rule.Suppress && (rule.Team != "" || rule.Service != "" || len(rule.Tags) > 0 || rule.SetSeverity != "" || rule.AttachToOpenOutage) {
+		return fmt.Errorf("routing rule %q suppresses alerts, so it cannot also route them: %w", rule.Name, domain.ErrInvalidInput)
+	}
+	if sev := rule.Match.Severity; sev != "" && !validSeverities[strings.ToLower(sev)] {
+		return fmt.Errorf("routing rule %q has invalid severity %q: %w", rule.Name, sev, domain.ErrInvalidInput)
+	}
+	if sev := rule.SetSeverity; sev != "" && !validSeverities[sev] {
+		return fmt.Errorf("routing rule %q has invalid set_severity %q: %w", rule.Name, sev, domain.ErrInvalidInput)
+	}
+	_, err := compileRoutingRule(rule)
+	return err
+}
+
+// checkRoutingServices checks the services routing rules link alerts to
+// are catalogued
+func (s *Service) checkRoutingServices(ctx context.Context, rules []domain.RoutingRule) error {
+	for _, rule := range rules {
+		if rule.Service == "" {
+			continue
+		}
+		if _, err := s.storage.GetService(ctx, rule.Service); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("routing rule %q refers to unknown service %q: %w", rule.Name, rule.Service, domain.ErrInvalidInput)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRoutingRules returns the stored routing rules in name order. Rules
+// that cannot be decoded or compiled are logged and skipped.
+func (s *Service) loadRoutingRules(ctx context.Context) ([]routingRule, error) {
+	resources, err := s.storage.ListConfigResources(ctx, domain.ResourceRoutingRule)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]routingRule, 0, len(resources))
+	for _, r := range resources {
+		var rule domain.RoutingRule
+		if err := json.Unmarshal(r.Spec, &rule); err != nil {
+			s.logger.WarnContext(ctx, "invalid routing rule", "rule", r.Name, "error", err)
+			continue
+		}
+		compiled, err := compileRoutingRule(rule)
+		if err != nil {
+			s.logger.WarnContext(ctx, "invalid routing rule", "rule", r.Name, "error", err)
+			continue
+		}
+		rules = append(rules, compiled)
+	}
+	return rules, nil
+}
+
+// routeAlert decides what the stored routing rules do with an alert that
+// arrived without an outage. Routing is best effort: when the rules cannot
+// be loaded the failure is logged and the alert is left alone, so it never
+// blocks alert ingestion.
+func (s *Service) routeAlert(ctx context.Context, notifAlert *notification.Alert, severity string) domain.RoutingDecision {
+	rules, err := s.loadRoutingRules(ctx)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to load routing rules",
+			"source", notifAlert.Source, "external_id", notifAlert.ExternalID, "error", err)
+		return domain.RoutingDecision{}
+	}
+	decision := decideRouting(rules, notifAlert, severity)
+	s.routeToServiceOwner(ctx, &decision)
+	return decision
+}
+
+// decideRouting combines the actions of every rule the alert matches.
+// severity is the alert's mapped severity; rules match it before any rule
+// changes it.
+func decideRouting(rules []routingRule, notifAlert *notification.Alert, severity string) domain.RoutingDecision {
+	decision := domain.RoutingDecision{Rules: []string{}}
+	for _, rule := range rules {
+		if !routingMatches(rule, notifAlert, severity) {
+			continue
+		}
+		decision.Rules = append(decision.Rules, rule.Name)
+		if rule.Team != "" || len(rule.Tags) > 0 {
+			if decision.Tags == nil {
+				decision.Tags = make(map[string]string)
+			}
+			if rule.Team != "" {
+				decision.Tags[teamTagKey] = rule.Team
+			}
+			for k, v := range rule.Tags {
+				decision.Tags[k] = v
+			}
+		}
+		if rule.SetSeverity != "" {
+			decision.Severity = rule.SetSeverity
+		}
+		if rule.Service != "" {
+			decision.Service = rule.Service
+		}
+		decision.AttachToOpenOutage = decision.AttachToOpenOutage || rule.AttachToOpenOutage
+		decision.Suppress = decision.Suppress || rule.Suppress
+	}
+	decision.Team = decision.Tags[teamTagKey]
+	return decision
+}
+
+// routeToServiceOwner routes an alert linked to a catalogued service, but
+// to no team, to the team owning the service. Lookup failures are logged
+// and leave the decision as it was.
+func (s *Service) routeToServiceOwner(ctx context.Context, decision *domain.RoutingDecision) {
+	if decision.Service == "" || decision.Team != "" {
+		return
+	}
+	svc, err := s.storage.GetService(ctx, decision.Service)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to get routed service", "service", decision.Service, "error", err)
+		return
+	}
+	if svc.OwningTeam == "" {
+		return
+	}
+	decision.Team = svc.OwningTeam
+	if decision.Tags == nil {
+		decision.Tags = make(map[string]string)
+	}
+	decision.Tags[teamTagKey] = svc.OwningTeam
+}
+
+// routingMatches reports whether an alert satisfies every field set on the
+// rule's match. A team name matches any team the alert was routed to.
+func routingMatches(rule routingRule, notifAlert *notification.Alert, severity string) bool {
+	m := rule.Match
+	if (m.Source != "" && !strings.EqualFold(m.Source, notifAlert.Source)) ||
+		(m.TeamName != "" && !slices.ContainsFunc(notifAlert.Teams(), func(team string) bool {
+			return strings.EqualFold(m.TeamName, team)
+		})) ||
+		(m.Severity != "" && !strings.EqualFold(m.Severity, severity)) ||
+		(rule.title != nil && !rule.title.MatchString(notifAlert.Title)) {
+		return false
+	}
+	for key, want := range m.Metadata {
+		if !metadataMatches(notifAlert.SourceMetadata[key], want) {
+			return false
+		}
+	}
+	return true
+}
+
+// metadataMatches reports whether a source metadata value, or any element
+// of a list value, equals want case-insensitively
+func metadataMatches(value any, want string) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.EqualFold(v, want)
+	case []string:
+		return slices.ContainsFunc(v, func(s string) bool { return strings.EqualFold(s, want) })
+	case []any:
+		return slices.ContainsFunc(v, func(e any) bool { return metadataMatches(e, want) })
+	default:
+		return strings.EqualFold(fmt.Sprint(v), want)
+	}
+}
+
+// routeOutage tags an outage opened from an alert with the tags routing
+// decided on, makes the routed team the outage's owner and records the
+// routed service as affected. Failures are logged so they never block
+// alert ingestion.
+func (s *Service) routeOutage(ctx context.Context, outage *domain.Outage, decision domain.RoutingDecision) {
+	outageID := outage.ID
+	now := time.Now()
+	for _, key := range sortedKeys(decision.Tags) {
+		tag := &domain.Tag{ID: uuid.New(), OutageID: outageID, Key: key, Value: decision.Tags[key], CreatedAt: now}
+		unique, err := s.uniqueTagKey(ctx, key)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to add routing tag", "outage_id", outageID, "key", key, "error", err)
+			continue
+		}
+		tag.UniqueKey = unique
+		if err := s.storage.CreateTag(ctx, tag); err != nil {
+			s.logger.WarnContext(ctx, "failed to add routing tag", "outage_id", outageID, "key", key, "error", err)
+		}
+	}
+
+	changed := false
+	if team := decision.Team; team != "" && outage.OwningTeam == "" {
+		outage.OwningTeam = team
+		changed = true
+	}
+	if svc := decision.Service; svc != "" && !slices.Contains(outage.AffectedServices, svc) {
+		outage.AffectedServices = append(outage.AffectedServices, svc)
+		changed = true
+	}
+	if changed {
+		if err := s.storage.UpdateOutage(ctx, outage); err != nil {
+			s.logger.WarnContext(ctx, "failed to route outage", "outage_id", outageID, "team", decision.Team, "service", decision.Service, "error", err)
+		}
+	}
+}
+
+// openOutageFor returns the most recently created outage owned by team
+// that is not yet resolved or closed, or nil when there is none. An empty
+// team matches outages of any team.
+func (s *Service) openOutageFor(ctx context.Context, team string) (*domain.Outage, error) {
+	for offset := 0; ; offset += viewPageSize {
+		var outages []*domain.Outage
+		var err error
+		if team != "" {
+			outages, err = s.storage.ListOutagesByTeams(ctx, []string{team}, viewPageSize, offset, false)
+		} else {
+			outages, err = s.storage.ListOutages(ctx, viewPageSize, offset, false)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list outages: %w", err)
+		}
+		for _, outage := range outages {
+			if outage.Status != domain.StatusResolved && outage.Status != domain.StatusClosed {
+				return outage, nil
+			}
+		}
+		if len(outages) < viewPageSize {
+			return nil, nil
+		}
+	}
+}
+
+// EvaluateRoutingRules reports what routing rules would do with the alerts
+// triggered in a time range, without changing anything. It evaluates the
+// request's rules, in name order, or the stored rules when it has none.
+// Alerts are matched on their current title, teams and source metadata,
+// and on the severity the severity mapping gives their native severity.
+func (s *Service) EvaluateRoutingRules(ctx context.Context, req domain.RoutingEvaluationRequest) (*domain.RoutingEvaluationResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.EvaluateRoutingRules")
+	defer span.End()
+
+	until := req.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	since := req.Since
+	if since.IsZero() {
+		since = until.Add(-routingEvaluationWindow)
+	}
+	if !since.Before(until) {
+		return nil, fmt.Errorf("since must be before until: %w", domain.ErrInvalidInput)
+	}
+
+	var rules []routingRule
+	if len(req.Rules) > 0 {
+		drafts := slices.Clone(req.Rules)
+		sort.SliceStable(drafts, func(i, j int) bool { return drafts[i].Name < drafts[j].Name })
+		for _, rule := range drafts {
+			if strings.TrimSpace(rule.Name) == "" {
+				return nil, fmt.Errorf("routing_rule name is required: %w", domain.ErrInvalidInput)
+			}
+			if err := validateRoutingRule(rule); err != nil {
+				return nil, err
+			}
+			compiled, _ := compileRoutingRule(rule)
+			rules = append(rules, compiled)
+		}
+		if err := s.checkRoutingServices(ctx, drafts); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if rules, err = s.loadRoutingRules(ctx); err != nil {
+			return nil, fmt.Errorf("failed to load routing rules: %w", err)
+		}
+	}
+
+	alerts, err := s.storage.ListAlertsTriggeredBetween(ctx, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	result := &domain.RoutingEvaluationResult{Since: since, Until: until, Alerts: []domain.RoutingEvaluation{}}
+	for _, alert := range alerts {
+		if req.Source != "" && !strings.EqualFold(alert.Source, req.Source) {
+			continue
+		}
+		result.Evaluated++
+
+		notifAlert := &notification.Alert{
+			ExternalID:     alert.ExternalID,
+			Source:         alert.Source,
+			TeamName:       alert.TeamName,
+			TeamNames:      alert.TeamNames,
+			Title:          alert.Title,
+			Description:    alert.Description,
+			SourceMetadata: alert.SourceMetadata,
+		}
+		decision := decideRouting(rules, notifAlert, s.severityMapping.Normalize(alert.Source, rawSeverity(alert)))
+		if len(decision.Rules) == 0 {
+			continue
+		}
+		s.routeToServiceOwner(ctx, &decision)
+		result.Matched++
+		if decision.Suppress {
+			result.Suppressed++
+		}
+		result.Alerts = append(result.Alerts, domain.RoutingEvaluation{
+			AlertID:     alert.ID,
+			OutageID:    alert.OutageID,
+			Source:      alert.Source,
+			ExternalID:  alert.ExternalID,
+			Title:       alert.Title,
+			Severity:    alert.Severity,
+			TriggeredAt: alert.TriggeredAt,
+			Decision:    decision,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func routingActionsConfig() domain.OpsConfig {
+	return domain.OpsConfig{
+		Teams: []domain.Team{{Name: "payments"}},
+		RoutingRules: []domain.RoutingRule{
+			{Name: "drop-heartbeats", Match: domain.RoutingMatch{Title: "^heartbeat"}, Suppress: true},
+			{Name: "escalate-eu", Match: domain.RoutingMatch{Metadata: map[string]string{"region": "EU"}}, SetSeverity: "critical"},
+			{Name: "payments", Match: domain.RoutingMatch{TeamName: "payments"}, Team: "payments", AttachToOpenOutage: true},
+		},
+	}
+}
+
+func TestProcessWebhook_RoutingActions(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, routingActionsConfig(), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	deliver := func(a notification.Alert) {
+		t.Helper()
+		a.Source, a.TriggeredAt = "fake", time.Now()
+		payload, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+			t.Fatalf("ProcessWebhook: %v", err)
+		}
+	}
+	outageOf := func(externalID string) *domain.Outage {
+		t.Helper()
+		alert, err := svc.GetAlertByExternalID(ctx, externalID, "fake")
+		if err != nil {
+			t.Fatalf("alert %s: %v", externalID, err)
+		}
+		outage, err := svc.GetOutage(ctx, alert.OutageID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return outage
+	}
+
+	deliver(notification.Alert{ExternalID: "A1", TeamName: "payments", Title: "card errors", Severity: "high"})
+	deliver(notification.Alert{ExternalID: "A2", TeamName: "payments", Title: "refund errors", Severity: "low"})
+	deliver(notification.Alert{ExternalID: "A3", TeamName: "payments", Title: "Heartbeat missed", Severity: "low"})
+	deliver(notification.Alert{
+		ExternalID: "A4", TeamName: "search", Title: "slow queries", Severity: "low",
+		SourceMetadata: map[string]any{"region": []any{"us", "eu"}},
+	})
+
+	first := outageOf("A1")
+	if first.OwningTeam != "payments" {
+		t.Errorf("first payments outage owner = %q, want payments", first.OwningTeam)
+	}
+	if attached := outageOf("A2"); attached.ID != first.ID {
+		t.Errorf("second payments alert opened outage %q, want it attached to the open one", attached.Title)
+	}
+	if _, err := svc.GetAlertByExternalID(ctx, "A3", "fake"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("suppressed alert lookup error = %v, want ErrNotFound", err)
+	}
+	if escalated := outageOf("A4"); escalated.Severity != "critical" {
+		t.Errorf("EU outage severity = %q, want critical", escalated.Severity)
+	}
+
+	// Once resolved, the payments outage no longer takes new alerts
+	if _, err := svc.TransitionOutage(ctx, first.ID, domain.TransitionRequest{Action: "resolve"}); err != nil {
+		t.Fatal(err)
+	}
+	deliver(notification.Alert{ExternalID: "A5", TeamName: "payments", Title: "card errors", Severity: "high"})
+	if reopened := outageOf("A5"); reopened.ID == first.ID {
+		t.Errorf("alert attached to a resolved outage")
+	}
+}
+
+func TestEvaluateRoutingRules(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	ctx := context.Background()
+	for _, a := range []notification.Alert{
+		{ExternalID: "A1", TeamName: "payments", Title: "card errors", Severity: "high"},
+		{ExternalID: "A2", TeamName: "search", Title: "Heartbeat missed", Severity: "low"},
+		{ExternalID: "A3", TeamName: "search", Title: "slow queries", Severity: "low"},
+	} {
+		a.Source, a.TriggeredAt = "fake", time.Now().Add(-time.Hour)
+		payload, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No rules are stored yet, so nothing matches
+	result, err := svc.EvaluateRoutingRules(ctx, domain.RoutingEvaluationRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Evaluated != 3 || result.Matched != 0 {
+		t.Errorf("stored rules: evaluated %d, matched %d, want 3 and 0", result.Evaluated, result.Matched)
+	}
+
+	rules := routingActionsConfig().RoutingRules
+	result, err = svc.EvaluateRoutingRules(ctx, domain.RoutingEvaluationRequest{Rules: rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matched != 2 || result.Suppressed != 1 {
+		t.Fatalf("draft rules: matched %d, suppressed %d, want 2 and 1", result.Matched, result.Suppressed)
+	}
+	for _, eval := range result.Alerts {
+		switch eval.ExternalID {
+		case "A1":
+			if eval.Decision.Team != "payments" || !eval.Decision.AttachToOpenOutage {
+				t.Errorf("A1 decision = %+v, want attached to a payments outage", eval.Decision)
+			}
+		case "A2":
+			if !eval.Decision.Suppress || len(eval.Decision.Rules) != 1 || eval.Decision.Rules[0] != "drop-heartbeats" {
+				t.Errorf("A2 decision = %+v, want suppressed by drop-heartbeats", eval.Decision)
+			}
+		default:
+			t.Errorf("unexpected match for %s", eval.ExternalID)
+		}
+	}
+	// A dry run changes nothing
+	if _, err := svc.GetAlertByExternalID(ctx, "A2", "fake"); err != nil {
+		t.Errorf("dry run removed the suppressed alert: %v", err)
+	}
+
+	invalid := []domain.RoutingEvaluationRequest{
+		{Rules: []domain.RoutingRule{{Match: domain.RoutingMatch{Source: "fake"}, Suppress: true}}},
+		{Rules: []domain.RoutingRule{{Name: "noop", Match: domain.RoutingMatch{Source: "fake"}}}},
+		{Since: time.Now(), Until: time.Now().Add(-time.Hour)},
+	}
+	for i, req := range invalid {
+		if _, err := svc.EvaluateRoutingRules(ctx, req); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("invalid request %d: error = %v, want ErrInvalidInput", i, err)
+		}
+	}
+}
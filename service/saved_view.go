@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// viewPageSize is the number of outages read per page when executing a
+// saved view
+const viewPageSize = 200
+
+// viewSummaryOutages is the most outages listed in a view summary; the
+// rest are only counted
+const viewSummaryOutages = 20
+
+// ViewSummaryMessage is a rendered summary of a saved view's outages
+type ViewSummaryMessage struct {
+	View         *domain.SavedView
+	Total        int // Outages matching the view, including those not listed
+	Subject      string
+	Text         string
+	SlackChannel string
+}
+
+// ViewSummaryNotifier delivers the periodic summaries of saved views
+// subscribed to a Slack channel
+type ViewSummaryNotifier interface {
+	SendViewSummary(ctx context.Context, msg ViewSummaryMessage) error
+}
+
+// RegisterViewSummaryNotifier adds a notifier that is called for every
+// view summary sent
+func (s *Service) RegisterViewSummaryNotifier(n ViewSummaryNotifier) {
+	s.viewSummaryNotifiers = append(s.viewSummaryNotifiers, n)
+}
+
+// CreateSavedView saves a named outage filter for owner. A view subscribed
+// to a Slack channel is summarised there daily unless SummaryPeriod is
+// weekly.
+func (s *Service) CreateSavedView(ctx context.Context, owner string, req domain.CreateSavedViewRequest) (*domain.SavedView, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateSavedView")
+	defer span.End()
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("view name is required: %w", domain.ErrInvalidInput)
+	}
+	if err := checkViewFilter(req.Filter); err != nil {
+		return nil, err
+	}
+	if req.SlackChannel == "" && req.SummaryPeriod != "" {
+		return nil, fmt.Errorf("summary_period needs a slack_channel to send summaries to: %w", domain.ErrInvalidInput)
+	}
+	if req.SlackChannel != "" && req.SummaryPeriod == "" {
+		req.SummaryPeriod = domain.DigestDaily
+	}
+	if req.SummaryPeriod != "" {
+		if _, err := digestLength(req.SummaryPeriod); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	view := &domain.SavedView{
+		ID:            uuid.New(),
+		Name:          req.Name,
+		Owner:         owner,
+		Filter:        req.Filter,
+		SlackChannel:  req.SlackChannel,
+		SummaryPeriod: req.SummaryPeriod,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.storage.CreateSavedView(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// GetSavedView retrieves a saved view by ID
+func (s *Service) GetSavedView(ctx context.Context, id uuid.UUID) (*domain.SavedView, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetSavedView")
+	defer span.End()
+
+	return s.storage.GetSavedView(ctx, id)
+}
+
+// ListSavedViews lists every saved view ordered by name
+func (s *Service) ListSavedViews(ctx context.Context) ([]*domain.SavedView, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListSavedViews")
+	defer span.End()
+
+	return s.storage.ListSavedViews(ctx)
+}
+
+// DeleteSavedView deletes a saved view, ending its Slack summaries
+func (s *Service) DeleteSavedView(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteSavedView")
+	defer span.End()
+
+	return s.storage.DeleteSavedView(ctx, id)
+}
+
+// ExecuteSavedView returns a page of the outages matching a saved view,
+// most recently created first. Outages in the trash never match.
+func (s *Service) ExecuteSavedView(ctx context.Context, id uuid.UUID, limit, offset int) (*domain.SavedView, []*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ExecuteSavedView")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	view, err := s.storage.GetSavedView(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outages := []*domain.Outage{}
+	skipped := 0
+	err = s.eachViewOutage(ctx, view.Filter, func(outage *domain.Outage) bool {
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		outages = append(outages, outage)
+		return len(outages) < limit
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return view, outages, nil
+}
+
+// SendViewSummaries sends a summary of every saved view subscribed to a
+// Slack channel whose last summary is at least its summary period old,
+// returning how many were sent. Delivery is best effort: notifier failures
+// are logged and the view is summarised again at the next check.
+func (s *Service) SendViewSummaries(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "Service.SendViewSummaries")
+	defer span.End()
+
+	if len(s.viewSummaryNotifiers) == 0 {
+		return 0, nil
+	}
+	views, err := s.storage.ListSavedViews(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list saved views: %w", err)
+	}
+
+	sent := 0
+	for _, view := range views {
+		if view.SlackChannel == "" {
+			continue
+		}
+		period, err := digestLength(view.SummaryPeriod)
+		if err != nil {
+			period = 24 * time.Hour
+		}
+		if view.LastSummaryAt != nil && now.Sub(*view.LastSummaryAt) < period {
+			continue
+		}
+
+		msg, err := s.viewSummary(ctx, view)
+		if err != nil {
+			return sent, err
+		}
+		delivered := true
+		for _, n := range s.viewSummaryNotifiers {
+			if err := n.SendViewSummary(ctx, *msg); err != nil {
+				s.logger.WarnContext(ctx, "failed to send view summary", "view", view.Name, "error", err)
+				delivered = false
+			}
+		}
+		if !delivered {
+			continue
+		}
+		view.LastSummaryAt = &now
+		if err := s.storage.UpdateSavedView(ctx, view); err != nil {
+			return sent, fmt.Errorf("failed to record summary of view %q: %w", view.Name, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// viewSummary renders the summary of a saved view's current outages
+func (s *Service) viewSummary(ctx context.Context, view *domain.SavedView) (*ViewSummaryMessage, error) {
+	var listed []*domain.Outage
+	total := 0
+	err := s.eachViewOutage(ctx, view.Filter, func(outage *domain.Outage) bool {
+		if total < viewSummaryOutages {
+			listed = append(listed, outage)
+		}
+		total++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	if total == 0 {
+		text.WriteString("No outages match this view.\n")
+	}
+	for _, outage := range listed {
+		fmt.Fprintf(&text, "• [%s] %s (%s since %s)\n", outage.Severity, outage.Title, outage.Status,
+			outage.CreatedAt.UTC().Format("Mon 2 Jan 15:04 MST"))
+	}
+	if total > len(listed) {
+		fmt.Fprintf(&text, "…and %d more\n", total-len(listed))
+	}
+	return &ViewSummaryMessage{
+		View:         view,
+		Total:        total,
+		Subject:      fmt.Sprintf("Saved view %q: %d outage(s)", view.Name, total),
+		Text:         text.String(),
+		SlackChannel: view.SlackChannel,
+	}, nil
+}
+
+// eachViewOutage calls fn with every outage outside the trash matching
+// filter, most recently created first, until fn returns false
+func (s *Service) eachViewOutage(ctx context.Context, filter domain.ViewFilter, fn func(*domain.Outage) bool) error {
+	tagged, err := s.outagesWithTags(ctx, filter.Tags)
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += viewPageSize {
+		var outages []*domain.Outage
+		if len(filter.Teams) > 0 {
+			outages, err = s.storage.ListOutagesByTeams(ctx, filter.Teams, viewPageSize, offset, false)
+		} else {
+			outages, err = s.storage.ListOutages(ctx, viewPageSize, offset, false)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list outages: %w", err)
+		}
+		for _, outage := range outages {
+			if tagged != nil && !tagged[outage.ID] {
+				continue
+			}
+			if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, outage.Status) {
+				continue
+			}
+			if !atLeastSeverity(outage.Severity, filter.MinSeverity) {
+				continue
+			}
+			if !fn(outage) {
+				return nil
+			}
+		}
+		if len(outages) < viewPageSize {
+			return nil
+		}
+	}
+}
+
+// outagesWithTags returns the IDs of the outages carrying every tag, or nil
+// when there are no tags to match
+func (s *Service) outagesWithTags(ctx context.Context, tags []domain.TagFilter) (map[uuid.UUID]bool, error) {
+	var matched map[uuid.UUID]bool
+	for _, tag := range tags {
+		ids := make(map[uuid.UUID]bool)
+		if tag.Value != "" {
+			outages, err := s.storage.FindOutagesByTag(ctx, tag.Key, tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find outages tagged %s: %w", tag.Key, err)
+			}
+			for _, outage := range outages {
+				ids[outage.ID] = true
+			}
+		} else {
+			found, err := s.storage.ListTagsByKey(ctx, tag.Key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find outages tagged %s: %w", tag.Key, err)
+			}
+			for _, t := range found {
+				ids[t.OutageID] = true
+			}
+		}
+		if matched == nil {
+			matched = ids
+			continue
+		}
+		for id := range matched {
+			if !ids[id] {
+				delete(matched, id)
+			}
+		}
+	}
+	return matched, nil
+}
+
+// atLeastSeverity reports whether severity is min or more severe. An empty
+// min matches every severity; an unknown severity matches only then.
+func atLeastSeverity(severity, min string) bool {
+	if min == "" {
+		return true
+	}
+	rank := slices.Index(notification.Severities, severity)
+	return rank >= 0 && rank <= slices.Index(notification.Severities, min)
+}
+
+// checkViewFilter checks a view filter names known statuses and
+// severities and only non-empty tag keys
+func checkViewFilter(filter domain.ViewFilter) error {
+	for _, status := range filter.Statuses {
+		if !slices.Contains(domain.OutageStatuses, status) {
+			return fmt.Errorf("unknown status %q (want one of %s): %w", status, strings.Join(domain.OutageStatuses, ", "), domain.ErrInvalidInput)
+		}
+	}
+	if filter.MinSeverity != "" && !validSeverities[filter.MinSeverity] {
+		return fmt.Errorf("unknown min_severity %q (want one of %s): %w", filter.MinSeverity, strings.Join(notification.Severities, ", "), domain.ErrInvalidInput)
+	}
+	for _, tag := range filter.Tags {
+		if strings.TrimSpace(tag.Key) == "" {
+			return fmt.Errorf("tag filters need a key: %w", domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
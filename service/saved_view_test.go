@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// recordingViewSummaryNotifier records the view summaries sent through it
+type recordingViewSummaryNotifier struct {
+	sent []ViewSummaryMessage
+}
+
+func (r *recordingViewSummaryNotifier) SendViewSummary(_ context.Context, msg ViewSummaryMessage) error {
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+func TestCreateSavedViewValidation(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  domain.CreateSavedViewRequest
+	}{
+		{"no name", domain.CreateSavedViewRequest{Name: "  "}},
+		{"unknown status", domain.CreateSavedViewRequest{Name: "v", Filter: domain.ViewFilter{Statuses: []string{"ongoing"}}}},
+		{"unknown severity", domain.CreateSavedViewRequest{Name: "v", Filter: domain.ViewFilter{MinSeverity: "sev1"}}},
+		{"tag without key", domain.CreateSavedViewRequest{Name: "v", Filter: domain.ViewFilter{Tags: []domain.TagFilter{{Value: "x"}}}}},
+		{"period without channel", domain.CreateSavedViewRequest{Name: "v", SummaryPeriod: domain.DigestWeekly}},
+		{"unknown period", domain.CreateSavedViewRequest{Name: "v", SlackChannel: "#ops", SummaryPeriod: "monthly"}},
+	}
+	for _, tt := range tests {
+		if _, err := svc.CreateSavedView(ctx, "a@example.com", tt.req); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: error = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+
+	view, err := svc.CreateSavedView(ctx, "a@example.com", domain.CreateSavedViewRequest{Name: "Mine", SlackChannel: "#ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.SummaryPeriod != domain.DigestDaily || view.Owner != "a@example.com" {
+		t.Errorf("view = %+v, want a daily summary owned by a@example.com", view)
+	}
+	if _, err := svc.CreateSavedView(ctx, "b@example.com", domain.CreateSavedViewRequest{Name: "Mine"}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("duplicate name error = %v, want ErrConflict", err)
+	}
+}
+
+func TestExecuteSavedView(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}, {Name: "search"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	create := func(title, severity, team string, tags ...domain.TagInput) *domain.Outage {
+		t.Helper()
+		outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: severity, OwningTeam: team, Tags: tags})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return outage
+	}
+
+	cards := create("Card errors", "critical", "payments", domain.TagInput{Key: "region", Value: "eu"})
+	create("Refunds slow", "low", "payments", domain.TagInput{Key: "region", Value: "eu"})
+	resolved := create("Checkout slow", "high", "payments", domain.TagInput{Key: "region", Value: "us"})
+	if _, err := svc.TransitionOutage(ctx, resolved.ID, domain.TransitionRequest{Action: "resolve"}); err != nil {
+		t.Fatal(err)
+	}
+	create("Search down", "critical", "search", domain.TagInput{Key: "region", Value: "eu"})
+
+	view, err := svc.CreateSavedView(ctx, "a@example.com", domain.CreateSavedViewRequest{
+		Name: "EU payments",
+		Filter: domain.ViewFilter{
+			Statuses:    []string{domain.StatusOpen, domain.StatusInvestigating},
+			MinSeverity: "high",
+			Tags:        []domain.TagFilter{{Key: "region", Value: "eu"}},
+			Teams:       []string{"payments"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, outages, err := svc.ExecuteSavedView(ctx, view.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 1 || outages[0].ID != cards.ID {
+		t.Errorf("outages = %+v, want only the critical EU payments outage", outages)
+	}
+
+	anyRegion, err := svc.CreateSavedView(ctx, "a@example.com", domain.CreateSavedViewRequest{
+		Name:   "Tagged",
+		Filter: domain.ViewFilter{Tags: []domain.TagFilter{{Key: "region"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, outages, err = svc.ExecuteSavedView(ctx, anyRegion.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 4 {
+		t.Errorf("outages tagged with any region = %d, want 4", len(outages))
+	}
+	_, page, err := svc.ExecuteSavedView(ctx, anyRegion.ID, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].ID != outages[3].ID {
+		t.Errorf("page = %+v, want the last outage", page)
+	}
+}
+
+func TestSendViewSummaries(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	notifier := &recordingViewSummaryNotifier{}
+	svc.RegisterViewSummaryNotifier(notifier)
+
+	if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Card errors", Severity: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateSavedView(ctx, "a@example.com", domain.CreateSavedViewRequest{Name: "Unsubscribed"}); err != nil {
+		t.Fatal(err)
+	}
+	weekly, err := svc.CreateSavedView(ctx, "a@example.com", domain.CreateSavedViewRequest{
+		Name: "Weekly", SlackChannel: "#ops", SummaryPeriod: domain.DigestWeekly,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	sent, err := svc.SendViewSummaries(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || len(notifier.sent) != 1 {
+		t.Fatalf("sent = %d (%d messages), want 1", sent, len(notifier.sent))
+	}
+	msg := notifier.sent[0]
+	if msg.SlackChannel != "#ops" || msg.Total != 1 || !strings.Contains(msg.Text, "Card errors") {
+		t.Errorf("summary = %+v, want the outage sent to #ops", msg)
+	}
+
+	if sent, err := svc.SendViewSummaries(ctx, now.Add(24*time.Hour)); err != nil || sent != 0 {
+		t.Errorf("SendViewSummaries a day later = %d, %v, want nothing sent", sent, err)
+	}
+	if sent, err := svc.SendViewSummaries(ctx, now.Add(7*24*time.Hour)); err != nil || sent != 1 {
+		t.Errorf("SendViewSummaries a week later = %d, %v, want the weekly summary sent", sent, err)
+	}
+	stored, err := svc.GetSavedView(ctx, weekly.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.LastSummaryAt == nil || !stored.LastSummaryAt.Equal(now.Add(7*24*time.Hour)) {
+		t.Errorf("last summary at = %v, want the latest send", stored.LastSummaryAt)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/validation"
+)
+
+// SetCustomFieldSchemas installs the custom field schemas from the server
+// config that outage, note and tag writes are validated against
+func (s *Service) SetCustomFieldSchemas(schemas validation.Schemas) error {
+	if err := schemas.Check(); err != nil {
+		return err
+	}
+	s.customFieldSchemas = schemas
+	return nil
+}
+
+// CustomFieldSchemas returns the custom field schemas writes are validated
+// against: those from the server config plus the custom fields admins have
+// registered in the operational config
+func (s *Service) CustomFieldSchemas(ctx context.Context) (validation.Schemas, error) {
+	resources, err := s.storage.ListConfigResources(ctx, domain.ResourceCustomField)
+	if err != nil {
+		return nil, err
+	}
+	schemas := s.customFieldSchemas
+	if schemas == nil {
+		schemas = validation.Schemas{}
+	}
+	for _, r := range resources {
+		var f domain.CustomField
+		if err := json.Unmarshal(r.Spec, &f); err != nil {
+			return nil, fmt.Errorf("failed to decode %s %s: %w", r.Kind, r.Name, err)
+		}
+		schemas = schemas.With(f.Entity, fieldDefinition(f))
+	}
+	return schemas, nil
+}
+
+// checkCustomFieldSchema validates fields against the schema for entity.
+// severity is the severity of the outage being written, or empty for notes
+// and tags.
+func (s *Service) checkCustomFieldSchema(ctx context.Context, entity string, fields map[string]any, severity string) error {
+	schemas, err := s.CustomFieldSchemas(ctx)
+	if err != nil {
+		return err
+	}
+	if err := schemas.Validate(entity, fields, severity); err != nil {
+		return fmt.Errorf("invalid custom_fields: %v: %w", err, domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkCustomFields checks registered custom field definitions, which must
+// be well formed and must not redefine a field of the server config
+func (s *Service) checkCustomFields(fields []domain.CustomField) error {
+	for _, f := range fields {
+		def := fieldDefinition(f)
+		if err := (validation.Schemas{f.Entity: {Fields: []validation.FieldDefinition{def}}}).Check(); err != nil {
+			return fmt.Errorf("%v: %w", err, domain.ErrInvalidInput)
+		}
+		for _, sev := range f.RequiredForSeverities {
+			if !validSeverities[sev] {
+				return fmt.Errorf("custom field %s has invalid severity %q: %w", f.ResourceName(), sev, domain.ErrInvalidInput)
+			}
+		}
+		if slices.ContainsFunc(s.customFieldSchemas[f.Entity].Fields, func(d validation.FieldDefinition) bool {
+			return d.Name == f.Name
+		}) {
+			return fmt.Errorf("custom field %s is already defined in the server config: %w", f.ResourceName(), domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+func fieldDefinition(f domain.CustomField) validation.FieldDefinition {
+	return validation.FieldDefinition{
+		Name:                  f.Name,
+		Type:                  f.Type,
+		Required:              f.Required,
+		AllowedValues:         f.AllowedValues,
+		Description:           f.Description,
+		RequiredForSeverities: f.RequiredForSeverities,
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/validation"
+)
+
+func TestCustomFieldSchemaEnforcement(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	if err := svc.SetCustomFieldSchemas(validation.Schemas{"widget": {}}); err == nil {
+		t.Fatal("expected malformed schema to be rejected")
+	}
+	err := svc.SetCustomFieldSchemas(validation.Schemas{
+		validation.EntityOutage: {Fields: []validation.FieldDefinition{
+			{Name: "impact", Type: validation.FieldTypeString, AllowedValues: []string{"partial", "full"}},
+		}},
+		validation.EntityTag: {Fields: []validation.FieldDefinition{
+			{Name: "owner", Type: validation.FieldTypeString, Required: true},
+		}},
+		validation.EntityNote: {Fields: []validation.FieldDefinition{
+			{Name: "minutes", Type: validation.FieldTypeNumber},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "t", Description: "d", Severity: "high",
+		CustomFields: map[string]any{"impact": "full"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOutage with conforming fields: %v", err)
+	}
+
+	_, err = svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "t", Description: "d", Severity: "high",
+		Tags: []domain.TagInput{{Key: "team", Value: "core"}},
+	})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("CreateOutage with tag missing required field: got %v, want ErrInvalidInput", err)
+	}
+
+	_, err = svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{
+		CustomFields: map[string]any{"impact": "total"},
+	})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateOutage with disallowed value: got %v, want ErrInvalidInput", err)
+	}
+
+	_, err = svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{
+		Content: "c", Format: "plaintext", Author: "a",
+		CustomFields: map[string]any{"minutes": "ten"},
+	})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddNote with wrong type: got %v, want ErrInvalidInput", err)
+	}
+
+	if _, err := svc.AddTag(ctx, outage.ID, "team", "core"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddTag without required field: got %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.AddTag(ctx, outage.ID, "team", "core", map[string]any{"owner": "alice"}); err != nil {
+		t.Errorf("AddTag with required field: %v", err)
+	}
+}
+
+func TestRegisteredCustomFields(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	err := svc.SetCustomFieldSchemas(validation.Schemas{
+		validation.EntityOutage: {Fields: []validation.FieldDefinition{
+			{Name: "impact", Type: validation.FieldTypeString},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := domain.OpsConfig{CustomFields: []domain.CustomField{
+		{Entity: "outage", Name: "incident_commander", Type: "string", RequiredForSeverities: []string{"critical"}},
+		{Entity: "note", Name: "kind", Type: "string", AllowedValues: []string{"update", "action"}},
+	}}
+	if _, err := svc.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err := svc.CustomFieldSchemas(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(schemas[validation.EntityOutage].Fields); got != 2 {
+		t.Errorf("outage fields = %+v, want impact and incident_commander", schemas[validation.EntityOutage].Fields)
+	}
+	if note := schemas[validation.EntityNote]; len(note.Fields) != 1 || !note.AllowUnknown {
+		t.Errorf("note schema = %+v, want kind and unknown fields allowed", note)
+	}
+
+	_, err = svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Severity: "critical"})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("critical outage without incident_commander: got %v, want ErrInvalidInput", err)
+	}
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "t", Severity: "high"})
+	if err != nil {
+		t.Fatalf("high outage without incident_commander: %v", err)
+	}
+
+	// Raising the severity requires the field too
+	critical := "critical"
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Severity: &critical}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("escalating without incident_commander: got %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{
+		Severity:     &critical,
+		CustomFields: map[string]any{"incident_commander": "alice"},
+	}); err != nil {
+		t.Errorf("escalating with incident_commander: %v", err)
+	}
+
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{
+		Content: "c", Format: "plaintext", Author: "a", CustomFields: map[string]any{"kind": "gossip"},
+	}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("note kind outside allowed values: got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestRegisteredCustomFields_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		field domain.CustomField
+	}{
+		{"unknown entity", domain.CustomField{Entity: "widget", Name: "x", Type: "string"}},
+		{"unknown type", domain.CustomField{Entity: "outage", Name: "x", Type: "date"}},
+		{"missing name", domain.CustomField{Entity: "outage", Type: "string"}},
+		{"bad severity", domain.CustomField{Entity: "outage", Name: "x", Type: "string", RequiredForSeverities: []string{"urgent"}}},
+		{"severity on tag", domain.CustomField{Entity: "tag", Name: "x", Type: "string", RequiredForSeverities: []string{"high"}}},
+		{"defined in server config", domain.CustomField{Entity: "outage", Name: "impact", Type: "string"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newSvc()
+			err := svc.SetCustomFieldSchemas(validation.Schemas{
+				validation.EntityOutage: {Fields: []validation.FieldDefinition{{Name: "impact", Type: validation.FieldTypeString}}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := domain.OpsConfig{CustomFields: []domain.CustomField{tt.field}}
+			if _, err := svc.ApplyOpsConfig(context.Background(), cfg, true, false); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("got %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/fingerprint"
+	"github.com/conall/outalator/internal/render"
 	"github.com/conall/outalator/notification"
 	"github.com/conall/outalator/storage"
 	"github.com/conall/outalator/validation"
@@ -14,15 +20,65 @@ import (
 
 // Service provides business logic for the application
 type Service struct {
-	storage             storage.Storage
+	storage              storage.Storage
 	notificationServices map[string]notification.Service
+	customFieldSchemas   validation.Schemas
+	severityMapping      notification.SeverityMapping
+	mentionNotifiers     []MentionNotifier
+	watchNotifiers       map[string]WatchNotifier
+	outageListeners      []OutageListener
+	resolutionPolicy     *domain.AlertResolutionPolicy
+	similarPolicy        *domain.SimilarOutagesPolicy
+	presence             *presenceTracker
+	logger               *slog.Logger
+
+	updatePolicy            domain.UpdatePolicy
+	updateReminderNotifiers []UpdateReminderNotifier
+	sentUpdateReminders     *reminderLog[uuid.UUID]
+
+	startedAt            time.Time
+	stalenessPolicy      domain.StalenessPolicy
+	sourceStaleNotifiers []SourceStaleNotifier
+	sentStaleAlarms      *reminderLog[string]
+
+	transitions    []domain.OutageTransition
+	trashRetention time.Duration
+
+	blobs            BlobStore
+	attachmentPolicy domain.AttachmentPolicy
+	analytics        *analyticsExport
+	retention        *retention
+
+	digestSchedules []digestSchedule
+	digestNotifiers []DigestNotifier
+	sentDigests     *reminderLog[string]
+
+	viewSummaryNotifiers []ViewSummaryNotifier
+	serviceCatalogues    []ServiceCatalogue
+	summarizer           OutageSummarizer
+
+	credentials *credentialChecks
+	eventPurges *eventPurges
+
+	admins map[string]bool
 }
 
 // New creates a new service instance
-func New(storage storage.Storage) *Service {
+func New(storage storage.Storage, logger *slog.Logger) *Service {
 	return &Service{
-		storage:             storage,
+		storage:              storage,
 		notificationServices: make(map[string]notification.Service),
+		watchNotifiers:       make(map[string]WatchNotifier),
+		presence:             newPresenceTracker(),
+		logger:               logger,
+		sentUpdateReminders:  newReminderLog[uuid.UUID](),
+		startedAt:            time.Now(),
+		sentStaleAlarms:      newReminderLog[string](),
+		sentDigests:          newReminderLog[string](),
+		transitions:          domain.DefaultOutageTransitions(),
+		severityMapping:      notification.DefaultSeverityMapping(),
+		credentials:          &credentialChecks{results: make(map[string]credentialCheck)},
+		eventPurges:          &eventPurges{last: make(map[string]time.Time)},
 	}
 }
 
@@ -33,6 +89,22 @@ func (s *Service) RegisterNotificationService(svc notification.Service) {
 
 // CreateOutage creates a new outage with associated alerts
 func (s *Service) CreateOutage(ctx context.Context, req domain.CreateOutageRequest) (*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateOutage")
+	defer span.End()
+
+	var tmpl *domain.OutageTemplate
+	if req.Template != "" {
+		var err error
+		if tmpl, err = s.applyTemplate(ctx, &req); err != nil {
+			return nil, err
+		}
+	} else if len(req.Variables) > 0 {
+		return nil, fmt.Errorf("variables need a template to fill: %w", domain.ErrInvalidInput)
+	}
+
+	if err := checkOutageInput(req.Title, req.Description, req.Severity); err != nil {
+		return nil, err
+	}
 	// Validate metadata and custom fields
 	if err := validation.ValidateMetadata(req.Metadata); err != nil {
 		return nil, fmt.Errorf("invalid metadata: %w", err)
@@ -40,25 +112,62 @@ func (s *Service) CreateOutage(ctx context.Context, req domain.CreateOutageReque
 	if err := validation.ValidateCustomFields(req.CustomFields); err != nil {
 		return nil, fmt.Errorf("invalid custom_fields: %w", err)
 	}
+	if err := s.checkCustomFieldSchema(ctx, validation.EntityOutage, req.CustomFields, req.Severity); err != nil {
+		return nil, err
+	}
+	tags, err := s.dedupeUniqueTags(ctx, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	req.Tags = tags
+	for _, tagReq := range req.Tags {
+		if err := checkTagInput(tagReq.Key, tagReq.Value); err != nil {
+			return nil, err
+		}
+		if err := s.checkCustomFieldSchema(ctx, validation.EntityTag, tagReq.CustomFields, ""); err != nil {
+			return nil, err
+		}
+		if err := s.checkTagSchema(ctx, tagReq.Key, tagReq.Value); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkOwningTeam(ctx, req.OwningTeam); err != nil {
+		return nil, err
+	}
+	affected, err := s.checkAffectedServices(ctx, req.AffectedServices)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImpactWindow(req.ImpactStartedAt, req.ImpactEndedAt); err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	outageID := uuid.New()
 
 	outage := &domain.Outage{
-		ID:           outageID,
-		Title:        req.Title,
-		Description:  req.Description,
-		Status:       "open",
-		Severity:     req.Severity,
-		CreatedAt:    now,
-		UpdatedAt:    now,
-		Metadata:     req.Metadata,
-		CustomFields: req.CustomFields,
+		ID:               outageID,
+		Title:            req.Title,
+		Description:      req.Description,
+		Status:           domain.StatusOpen,
+		Severity:         req.Severity,
+		OwningTeam:       req.OwningTeam,
+		AffectedServices: affected,
+		CustomerImpact:   req.CustomerImpact,
+		ImpactStartedAt:  req.ImpactStartedAt,
+		ImpactEndedAt:    req.ImpactEndedAt,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Metadata:         req.Metadata,
+		CustomFields:     req.CustomFields,
 	}
 
 	if err := s.storage.CreateOutage(ctx, outage); err != nil {
 		return nil, fmt.Errorf("failed to create outage: %w", err)
 	}
+	if err := s.recordStatusChange(ctx, outageID, "", outage.Status, domain.TransitionRequest{}, now); err != nil {
+		return nil, err
+	}
 
 	// Import alerts if provided
 	for _, alertID := range req.AlertIDs {
@@ -66,24 +175,12 @@ func (s *Service) CreateOutage(ctx context.Context, req domain.CreateOutageReque
 		for _, svc := range s.notificationServices {
 			notifAlert, err := svc.FetchAlert(ctx, alertID)
 			if err != nil {
+				s.logger.DebugContext(ctx, "alert not found in notification service",
+					"alert_id", alertID, "source", svc.Name(), "error", err)
 				continue // Try next service
 			}
 
-			alert := &domain.Alert{
-				ID:             uuid.New(),
-				OutageID:       outageID,
-				ExternalID:     notifAlert.ExternalID,
-				Source:         notifAlert.Source,
-				TeamName:       notifAlert.TeamName,
-				Title:          notifAlert.Title,
-				Description:    notifAlert.Description,
-				Severity:       notifAlert.Severity,
-				TriggeredAt:    notifAlert.TriggeredAt,
-				AcknowledgedAt: notifAlert.AcknowledgedAt,
-				ResolvedAt:     notifAlert.ResolvedAt,
-				CreatedAt:      now,
-			}
-
+			alert := s.newAlert(notifAlert, outageID, now)
 			if err := s.storage.CreateAlert(ctx, alert); err != nil {
 				return nil, fmt.Errorf("failed to create alert: %w", err)
 			}
@@ -98,6 +195,10 @@ func (s *Service) CreateOutage(ctx context.Context, req domain.CreateOutageReque
 			return nil, fmt.Errorf("invalid tag custom_fields: %w", err)
 		}
 
+		unique, err := s.uniqueTagKey(ctx, tagReq.Key)
+		if err != nil {
+			return nil, err
+		}
 		tag := &domain.Tag{
 			ID:           uuid.New(),
 			OutageID:     outageID,
@@ -105,55 +206,185 @@ func (s *Service) CreateOutage(ctx context.Context, req domain.CreateOutageReque
 			Value:        tagReq.Value,
 			CreatedAt:    now,
 			CustomFields: tagReq.CustomFields,
+			UniqueKey:    unique,
 		}
 		if err := s.storage.CreateTag(ctx, tag); err != nil {
 			return nil, fmt.Errorf("failed to create tag: %w", err)
 		}
 	}
+	if tmpl != nil && len(tmpl.Checklist) > 0 {
+		if err := s.addTemplateChecklist(ctx, outageID, tmpl, now); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.InfoContext(ctx, "outage created", "outage_id", outageID, "severity", outage.Severity)
 
 	// Reload outage with all associations
-	return s.storage.GetOutage(ctx, outageID)
+	created, err := s.storage.GetOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyOutageCreated(ctx, created)
+	s.noteSimilarOutages(ctx, created)
+	return created, nil
 }
 
 // GetOutage retrieves an outage by ID
 func (s *Service) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
-	return s.storage.GetOutage(ctx, id)
+	ctx, span := tracer.Start(ctx, "Service.GetOutage")
+	defer span.End()
+
+	outage, err := s.liveOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setRollup(ctx, outage); err != nil {
+		return nil, err
+	}
+	relations, err := s.relationsOf(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range relations {
+		outage.Relations = append(outage.Relations, *r)
+	}
+	return outage, nil
 }
 
-// ListOutages retrieves a paginated list of outages
+// GetOutageWith is GetOutage loading only the selected associations, for
+// callers that do not need an outage's alerts, notes or tags
+func (s *Service) GetOutageWith(ctx context.Context, id uuid.UUID, associations domain.OutageAssociations) (*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetOutageWith")
+	defer span.End()
+
+	if associations == domain.AllOutageAssociations {
+		return s.liveOutage(ctx, id)
+	}
+	outage, err := s.storage.GetOutageWith(ctx, id, associations)
+	if err != nil {
+		return nil, err
+	}
+	if outage.DeletedAt != nil {
+		return nil, fmt.Errorf("outage %s is in the trash: %w", id, domain.ErrNotFound)
+	}
+	return outage, nil
+}
+
+// ListOutages retrieves a paginated list of outages, leaving out those in
+// the trash
 func (s *Service) ListOutages(ctx context.Context, limit, offset int) ([]*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListOutages")
+	defer span.End()
+
+	return s.listOutages(ctx, limit, offset, false)
+}
+
+// ListOutagesIncludingDeleted is ListOutages with outages in the trash
+// included
+func (s *Service) ListOutagesIncludingDeleted(ctx context.Context, limit, offset int) ([]*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListOutagesIncludingDeleted")
+	defer span.End()
+
+	return s.listOutages(ctx, limit, offset, true)
+}
+
+func (s *Service) listOutages(ctx context.Context, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 	if limit > 100 {
 		limit = 100
 	}
-	return s.storage.ListOutages(ctx, limit, offset)
+	return s.storage.ListOutages(ctx, limit, offset, includeDeleted)
+}
+
+// LoadOutageAssociations sets the selected alerts, notes and tags of
+// outages from ListOutages or ListTeamOutages, which leave them out, in a
+// fixed number of storage queries
+func (s *Service) LoadOutageAssociations(ctx context.Context, outages []*domain.Outage, associations domain.OutageAssociations) error {
+	ctx, span := tracer.Start(ctx, "Service.LoadOutageAssociations")
+	defer span.End()
+
+	return s.storage.LoadOutageAssociations(ctx, outages, associations)
 }
 
-// UpdateOutage updates an outage
+// UpdateOutage updates an outage. A status change must be allowed by a
+// non-explicit transition of the outage state machine.
 func (s *Service) UpdateOutage(ctx context.Context, id uuid.UUID, req domain.UpdateOutageRequest) (*domain.Outage, error) {
-	outage, err := s.storage.GetOutage(ctx, id)
+	ctx, span := tracer.Start(ctx, "Service.UpdateOutage")
+	defer span.End()
+
+	return s.updateOutage(ctx, id, req, domain.TransitionRequest{})
+}
+
+// updateOutage applies req to an outage. A status change is validated
+// against the state machine: by name when transition names an action,
+// otherwise by finding a non-explicit transition to the new status. The
+// transition's action, actor and reason are recorded in the status history.
+func (s *Service) updateOutage(ctx context.Context, id uuid.UUID, req domain.UpdateOutageRequest, transition domain.TransitionRequest) (*domain.Outage, error) {
+	outage, err := s.liveOutage(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return nil, err
+	}
 
+	now := time.Now()
 	if req.Title != nil {
+		if err := checkTitle(*req.Title); err != nil {
+			return nil, err
+		}
 		outage.Title = *req.Title
 	}
 	if req.Description != nil {
+		if err := checkDescription(*req.Description); err != nil {
+			return nil, err
+		}
 		outage.Description = *req.Description
 	}
-	if req.Status != nil {
-		outage.Status = *req.Status
-		if *req.Status == "resolved" || *req.Status == "closed" {
-			now := time.Now()
-			outage.ResolvedAt = &now
+	previousStatus := outage.Status
+	if req.Status != nil && (*req.Status != outage.Status || transition.Action != "") {
+		if err := checkStatus(*req.Status); err != nil {
+			return nil, err
 		}
+		t, err := s.findTransition(outage.Status, *req.Status, transition.Action)
+		if err != nil {
+			return nil, err
+		}
+		transition.Action = t.Action
+		applyStatus(outage, t.To, now)
 	}
 	if req.Severity != nil {
+		if err := checkSeverity(*req.Severity); err != nil {
+			return nil, err
+		}
 		outage.Severity = *req.Severity
 	}
+	if req.OwningTeam != nil {
+		if err := s.checkOwningTeam(ctx, *req.OwningTeam); err != nil {
+			return nil, err
+		}
+		outage.OwningTeam = *req.OwningTeam
+	}
+	if req.AffectedServices != nil {
+		if outage.AffectedServices, err = s.checkAffectedServices(ctx, *req.AffectedServices); err != nil {
+			return nil, err
+		}
+	}
+	if req.CustomerImpact != nil {
+		outage.CustomerImpact = *req.CustomerImpact
+	}
+	if req.ImpactStartedAt != nil {
+		outage.ImpactStartedAt = req.ImpactStartedAt
+	}
+	if req.ImpactEndedAt != nil {
+		outage.ImpactEndedAt = req.ImpactEndedAt
+	}
+	if err := checkImpactWindow(outage.ImpactStartedAt, outage.ImpactEndedAt); err != nil {
+		return nil, err
+	}
 
 	// Handle metadata and custom_fields updates (FULL REPLACEMENT)
 	if req.Metadata != nil {
@@ -168,40 +399,238 @@ func (s *Service) UpdateOutage(ctx context.Context, id uuid.UUID, req domain.Upd
 		}
 		outage.CustomFields = req.CustomFields
 	}
+	// A new severity may require fields the outage does not have yet
+	if req.CustomFields != nil || req.Severity != nil {
+		if err := s.checkCustomFieldSchema(ctx, validation.EntityOutage, outage.CustomFields, outage.Severity); err != nil {
+			return nil, err
+		}
+	}
 
-	outage.UpdatedAt = time.Now()
+	outage.UpdatedAt = now
 
 	if err := s.storage.UpdateOutage(ctx, outage); err != nil {
 		return nil, err
 	}
+	if outage.Status != previousStatus {
+		if err := s.recordStatusChange(ctx, id, previousStatus, outage.Status, transition, outage.UpdatedAt); err != nil {
+			return nil, err
+		}
+		s.logger.InfoContext(ctx, "outage status changed",
+			"outage_id", id, "from", previousStatus, "to", outage.Status, "action", transition.Action, "actor", transition.Actor)
+		if isResolved(outage.Status) {
+			if err := s.openReview(ctx, id, outage.UpdatedAt); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	return s.storage.GetOutage(ctx, id)
+	updated, err := s.storage.GetOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if updated.Status != previousStatus {
+		s.notifyOutageStatusChanged(ctx, updated, previousStatus, transition.Actor)
+	}
+	if isResolved(updated.Status) && !isResolved(previousStatus) {
+		s.notifyOutageResolved(ctx, updated)
+		if req.ResolveAlerts || transition.ResolveAlerts {
+			s.resolveAlertsUpstream(ctx, id, transition.Actor)
+		}
+	}
+	return updated, nil
 }
 
-// DeleteOutage deletes an outage by ID.
+// DeleteOutage moves an outage to the trash, where it stays until restored
+// or purged. Deleting an outage already in the trash returns
+// domain.ErrNotFound.
 func (s *Service) DeleteOutage(ctx context.Context, id uuid.UUID) error {
-	return s.storage.DeleteOutage(ctx, id)
+	ctx, span := tracer.Start(ctx, "Service.DeleteOutage")
+	defer span.End()
+
+	if err := s.authorizeOutageChange(ctx, id); err != nil {
+		return err
+	}
+	if err := s.storage.TrashOutage(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	s.logger.InfoContext(ctx, "outage moved to trash", "outage_id", id)
+	return nil
 }
 
-// DeleteNote deletes a note by ID.
+// DeleteNote moves a note to the trash, where it stays until restored or
+// purged.
 func (s *Service) DeleteNote(ctx context.Context, noteID uuid.UUID) error {
-	return s.storage.DeleteNote(ctx, noteID)
+	ctx, span := tracer.Start(ctx, "Service.DeleteNote")
+	defer span.End()
+
+	if note, err := s.storage.GetNote(ctx, noteID); err == nil {
+		if err := s.authorizeOutageChange(ctx, note.OutageID); err != nil {
+			return err
+		}
+	}
+	return s.storage.TrashNote(ctx, noteID, time.Now())
+}
+
+// GetNote retrieves a note by ID.
+func (s *Service) GetNote(ctx context.Context, noteID uuid.UUID) (*domain.Note, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetNote")
+	defer span.End()
+
+	return s.liveNote(ctx, noteID)
 }
 
-// ListNotesByOutage returns all notes for the given outage.
+// ListNotesByOutage returns the notes for the given outage that are not in
+// the trash.
 func (s *Service) ListNotesByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Note, error) {
-	return s.storage.ListNotesByOutage(ctx, outageID)
+	ctx, span := tracer.Start(ctx, "Service.ListNotesByOutage")
+	defer span.End()
+
+	return s.storage.ListNotesByOutage(ctx, outageID, false)
+}
+
+// ListNotesByOutagePage returns up to limit of an outage's notes starting at
+// offset, along with the total number of notes. A non-positive limit
+// returns every note from offset on. Notes in the trash are left out unless
+// includeDeleted is set.
+func (s *Service) ListNotesByOutagePage(ctx context.Context, outageID uuid.UUID, limit, offset int, includeDeleted bool) ([]*domain.Note, int, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListNotesByOutagePage")
+	defer span.End()
+
+	if offset < 0 {
+		return nil, 0, fmt.Errorf("%w: offset must not be negative", domain.ErrInvalidInput)
+	}
+	notes, err := s.storage.ListNotesByOutage(ctx, outageID, includeDeleted)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(notes)
+	if offset >= total {
+		return []*domain.Note{}, total, nil
+	}
+	notes = notes[offset:]
+	if limit > 0 && limit < len(notes) {
+		notes = notes[:limit]
+	}
+	return notes, total, nil
+}
+
+// GetAlert retrieves an alert by ID.
+func (s *Service) GetAlert(ctx context.Context, alertID uuid.UUID) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetAlert")
+	defer span.End()
+
+	return s.storage.GetAlert(ctx, alertID)
+}
+
+// GetAlertByExternalID retrieves an alert by its ID in the source that
+// raised it.
+func (s *Service) GetAlertByExternalID(ctx context.Context, externalID, source string) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetAlertByExternalID")
+	defer span.End()
+
+	return s.storage.GetAlertByExternalID(ctx, externalID, source)
+}
+
+// UpdateAlert updates an alert. Resolving the last open alert of an outage
+// resolves the outage when the alert resolution policy allows it.
+func (s *Service) UpdateAlert(ctx context.Context, alertID uuid.UUID, req domain.UpdateAlertRequest) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateAlert")
+	defer span.End()
+
+	alert, err := s.storage.GetAlert(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeOutageChange(ctx, alert.OutageID); err != nil {
+		return nil, err
+	}
+	wasResolved := alert.ResolvedAt != nil
+
+	if req.Title != nil {
+		alert.Title = *req.Title
+	}
+	if req.Description != nil {
+		alert.Description = *req.Description
+	}
+	if req.Severity != nil {
+		alert.Severity = *req.Severity
+	}
+	if req.Service != nil {
+		if alert.Service, err = s.checkService(ctx, *req.Service); err != nil {
+			return nil, err
+		}
+	}
+	if req.AcknowledgedAt != nil {
+		alert.AcknowledgedAt = req.AcknowledgedAt
+	}
+	if req.ResolvedAt != nil {
+		alert.ResolvedAt = req.ResolvedAt
+	}
+
+	// Handle metadata and custom_fields updates (FULL REPLACEMENT)
+	if req.Metadata != nil {
+		if err := validation.ValidateMetadata(req.Metadata); err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+		alert.Metadata = req.Metadata
+	}
+	if req.CustomFields != nil {
+		if err := validation.ValidateCustomFields(req.CustomFields); err != nil {
+			return nil, fmt.Errorf("invalid custom_fields: %w", err)
+		}
+		alert.CustomFields = req.CustomFields
+	}
+
+	if err := s.storage.UpdateAlert(ctx, alert); err != nil {
+		return nil, err
+	}
+	if alert.ResolvedAt != nil && !wasResolved {
+		s.autoResolveOutage(ctx, alert.OutageID, "all of its alerts are resolved")
+	}
+	return alert, nil
 }
 
 // ListAlertsByOutage returns all alerts linked to the given outage.
 func (s *Service) ListAlertsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAlertsByOutage")
+	defer span.End()
+
 	return s.storage.ListAlertsByOutage(ctx, outageID)
 }
 
+// ListAlerts returns a page of the alerts matching q across all outages,
+// most recently triggered first. The limit defaults to 50 and is capped at
+// 100.
+func (s *Service) ListAlerts(ctx context.Context, q domain.AlertQuery) ([]*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAlerts")
+	defer span.End()
+
+	if q.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", domain.ErrInvalidInput)
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return nil, fmt.Errorf("since must be before until: %w", domain.ErrInvalidInput)
+	}
+	if q.Limit <= 0 {
+		q.Limit = 50
+	}
+	if q.Limit > 100 {
+		q.Limit = 100
+	}
+	return s.storage.ListAlerts(ctx, q)
+}
+
 // AddNote adds a note to an outage
 func (s *Service) AddNote(ctx context.Context, outageID uuid.UUID, req domain.AddNoteRequest) (*domain.Note, error) {
-	// Verify outage exists
-	if _, err := s.storage.GetOutage(ctx, outageID); err != nil {
+	ctx, span := tracer.Start(ctx, "Service.AddNote")
+	defer span.End()
+
+	// Verify outage exists and is not in the trash
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
 		return nil, err
 	}
 
@@ -212,17 +641,39 @@ func (s *Service) AddNote(ctx context.Context, outageID uuid.UUID, req domain.Ad
 	if err := validation.ValidateCustomFields(req.CustomFields); err != nil {
 		return nil, fmt.Errorf("invalid custom_fields: %w", err)
 	}
+	if err := s.checkCustomFieldSchema(ctx, validation.EntityNote, req.CustomFields, ""); err != nil {
+		return nil, err
+	}
+	if req.Format == "" {
+		req.Format = domain.NoteFormatPlaintext
+	}
+	if err := checkNoteInput(req.Content, req.Format); err != nil {
+		return nil, err
+	}
+	if err := checkNoteContent(req.Format, req.Content); err != nil {
+		return nil, err
+	}
+	parentID, err := s.threadRoot(ctx, outageID, req.ParentNoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := s.resolveMentions(ctx, req.Content)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	note := &domain.Note{
 		ID:           uuid.New(),
 		OutageID:     outageID,
+		ParentNoteID: parentID,
 		Content:      req.Content,
 		Format:       req.Format,
 		Author:       req.Author,
 		CreatedAt:    now,
 		UpdatedAt:    now,
-		Metadata:     req.Metadata,
+		Metadata:     withAttachmentIDs(withMentions(req.Metadata, mentions), nil),
 		CustomFields: req.CustomFields,
 	}
 
@@ -230,16 +681,51 @@ func (s *Service) AddNote(ctx context.Context, outageID uuid.UUID, req domain.Ad
 		return nil, err
 	}
 
+	s.notifyMentions(ctx, outageID, note, mentions)
+	s.notifyNoteAdded(ctx, outage, note)
 	return note, nil
 }
 
-// UpdateNote updates an existing note
-func (s *Service) UpdateNote(ctx context.Context, noteID uuid.UUID, content, format *string, metadata map[string]string, customFields map[string]any) (*domain.Note, error) {
+// checkNoteFormat rejects note formats other than plaintext, markdown and log
+func checkNoteFormat(format string) error {
+	switch format {
+	case domain.NoteFormatPlaintext, domain.NoteFormatMarkdown, domain.NoteFormatLog:
+		return nil
+	}
+	return fmt.Errorf("format must be %s, %s or %s: %w",
+		domain.NoteFormatPlaintext, domain.NoteFormatMarkdown, domain.NoteFormatLog, domain.ErrInvalidInput)
+}
+
+// checkNoteContent rejects markdown notes that embed disallowed HTML or
+// link to unsafe URLs
+func checkNoteContent(format, content string) error {
+	if format != domain.NoteFormatMarkdown {
+		return nil
+	}
+	return render.Validate(content)
+}
+
+// UpdateNote updates an existing note. When its content or format changes,
+// the version it replaces is kept as a revision edited by editor.
+func (s *Service) UpdateNote(ctx context.Context, noteID uuid.UUID, content, format *string, metadata map[string]string, customFields map[string]any, editor string) (*domain.Note, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateNote")
+	defer span.End()
+
 	// Get existing note
-	note, err := s.storage.GetNote(ctx, noteID)
+	note, err := s.liveNote(ctx, noteID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorizeOutageChange(ctx, note.OutageID); err != nil {
+		return nil, err
+	}
+
+	previous := make(map[domain.Mention]bool)
+	for _, m := range note.Mentions() {
+		previous[m] = true
+	}
+	attachments := note.AttachmentIDs()
+	previousContent, previousFormat := note.Content, note.Format
 
 	// Update fields if provided
 	if content != nil {
@@ -248,6 +734,14 @@ func (s *Service) UpdateNote(ctx context.Context, noteID uuid.UUID, content, for
 	if format != nil {
 		note.Format = *format
 	}
+	if content != nil || format != nil {
+		if err := checkNoteInput(note.Content, note.Format); err != nil {
+			return nil, err
+		}
+		if err := checkNoteContent(note.Format, note.Content); err != nil {
+			return nil, err
+		}
+	}
 
 	// Handle metadata and custom_fields updates (FULL REPLACEMENT)
 	if metadata != nil {
@@ -260,22 +754,58 @@ func (s *Service) UpdateNote(ctx context.Context, noteID uuid.UUID, content, for
 		if err := validation.ValidateCustomFields(customFields); err != nil {
 			return nil, fmt.Errorf("invalid custom_fields: %w", err)
 		}
+		if err := s.checkCustomFieldSchema(ctx, validation.EntityNote, customFields, ""); err != nil {
+			return nil, err
+		}
 		note.CustomFields = customFields
 	}
 
+	// Mentions always reflect the current content, so they survive a
+	// metadata replacement
+	mentions, err := s.resolveMentions(ctx, note.Content)
+	if err != nil {
+		return nil, err
+	}
+	note.Metadata = withMentions(note.Metadata, mentions)
+	// Attachment references are kept up to date by uploads and deletes
+	note.Metadata = withAttachmentIDs(note.Metadata, attachments)
+
 	note.UpdatedAt = time.Now()
 
+	if note.Content != previousContent || note.Format != previousFormat {
+		if err := s.recordRevision(ctx, note.ID, previousContent, previousFormat, editor, note.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
 	if err := s.storage.UpdateNote(ctx, note); err != nil {
 		return nil, err
 	}
 
+	// Only notify people newly mentioned by this edit
+	var added []MentionRecipient
+	for _, r := range mentions {
+		if !previous[r.Mention] {
+			added = append(added, r)
+		}
+	}
+	s.notifyMentions(ctx, note.OutageID, note, added)
 	return note, nil
 }
 
 // AddTag adds a tag to an outage
 func (s *Service) AddTag(ctx context.Context, outageID uuid.UUID, key, value string, customFields ...map[string]any) (*domain.Tag, error) {
-	// Verify outage exists
-	if _, err := s.storage.GetOutage(ctx, outageID); err != nil {
+	ctx, span := tracer.Start(ctx, "Service.AddTag")
+	defer span.End()
+
+	if err := checkTagInput(key, value); err != nil {
+		return nil, err
+	}
+	// Verify outage exists and is not in the trash
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
 		return nil, err
 	}
 
@@ -287,6 +817,27 @@ func (s *Service) AddTag(ctx context.Context, outageID uuid.UUID, key, value str
 			return nil, fmt.Errorf("invalid custom_fields: %w", err)
 		}
 	}
+	if err := s.checkCustomFieldSchema(ctx, validation.EntityTag, fields, ""); err != nil {
+		return nil, err
+	}
+	if err := s.checkTagSchema(ctx, key, value); err != nil {
+		return nil, err
+	}
+
+	// Re-adding a unique key updates the outage's tag with that key
+	unique, err := s.uniqueTagKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if unique {
+		existing, err := s.outageTag(ctx, outageID, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return s.updateUniqueTag(ctx, existing, value, fields)
+		}
+	}
 
 	tag := &domain.Tag{
 		ID:           uuid.New(),
@@ -295,25 +846,212 @@ func (s *Service) AddTag(ctx context.Context, outageID uuid.UUID, key, value str
 		Value:        value,
 		CreatedAt:    time.Now(),
 		CustomFields: fields,
+		UniqueKey:    unique,
 	}
 
 	if err := s.storage.CreateTag(ctx, tag); err != nil {
-		return nil, err
+		// Another request added the unique tag first; update it instead
+		if !unique || !errors.Is(err, domain.ErrConflict) {
+			return nil, err
+		}
+		existing, err := s.outageTag(ctx, outageID, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("outage %s already has a %s tag: %w", outageID, key, domain.ErrConflict)
+		}
+		return s.updateUniqueTag(ctx, existing, value, fields)
 	}
 
 	return tag, nil
 }
 
+// updateUniqueTag sets the value, and the custom fields when given, of the
+// outage's tag with a unique key
+func (s *Service) updateUniqueTag(ctx context.Context, tag *domain.Tag, value string, fields map[string]any) (*domain.Tag, error) {
+	tag.Value = value
+	if fields != nil {
+		tag.CustomFields = fields
+	}
+	if err := s.storage.UpdateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
 // FindOutagesByTag finds outages with a specific tag
 func (s *Service) FindOutagesByTag(ctx context.Context, key, value string) ([]*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.FindOutagesByTag")
+	defer span.End()
+
 	return s.storage.FindOutagesByTag(ctx, key, value)
 }
 
-// ImportAlert imports an alert from a notification service
+// FindOutagesByTagQuery finds outages matching every term of a tag query,
+// newest first
+func (s *Service) FindOutagesByTagQuery(ctx context.Context, query domain.TagQuery) ([]*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.FindOutagesByTagQuery")
+	defer span.End()
+
+	if err := checkTagQuery(query); err != nil {
+		return nil, err
+	}
+	return s.storage.FindOutagesByTagQuery(ctx, query)
+}
+
+// ListTagsByKey returns every tag with the given key across all outages,
+// oldest first
+func (s *Service) ListTagsByKey(ctx context.Context, key string) ([]*domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTagsByKey")
+	defer span.End()
+
+	return s.storage.ListTagsByKey(ctx, key)
+}
+
+// ListTagKeys returns each tag key in use with the number of tags that have
+// it, most used first
+func (s *Service) ListTagKeys(ctx context.Context) ([]domain.TagUsage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTagKeys")
+	defer span.End()
+
+	return s.storage.ListTagKeys(ctx)
+}
+
+// ListTagValues returns each value in use for a tag key with the number of
+// tags that have it, most used first
+func (s *Service) ListTagValues(ctx context.Context, key string) ([]domain.TagUsage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTagValues")
+	defer span.End()
+
+	if key == "" {
+		return nil, fmt.Errorf("tag key is required: %w", domain.ErrInvalidInput)
+	}
+	return s.storage.ListTagValues(ctx, key)
+}
+
+// GetTag retrieves a tag by ID.
+func (s *Service) GetTag(ctx context.Context, tagID uuid.UUID) (*domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTag")
+	defer span.End()
+
+	return s.storage.GetTag(ctx, tagID)
+}
+
+// ListTagsByOutage returns all tags on the given outage.
+func (s *Service) ListTagsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTagsByOutage")
+	defer span.End()
+
+	return s.storage.ListTagsByOutage(ctx, outageID)
+}
+
+// UpdateTag changes a tag's key, value or custom fields. Moving a tag to a
+// unique key the outage already has another tag with is a conflict, which
+// storage also enforces for concurrent updates.
+func (s *Service) UpdateTag(ctx context.Context, tagID uuid.UUID, req domain.UpdateTagRequest) (*domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateTag")
+	defer span.End()
+
+	tag, err := s.storage.GetTag(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	outage, err := s.liveOutage(ctx, tag.OutageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return nil, err
+	}
+
+	if req.Key != nil {
+		tag.Key = *req.Key
+	}
+	if req.Value != nil {
+		tag.Value = *req.Value
+	}
+	if err := checkTagInput(tag.Key, tag.Value); err != nil {
+		return nil, err
+	}
+	if req.CustomFields != nil {
+		if err := validation.ValidateCustomFields(req.CustomFields); err != nil {
+			return nil, fmt.Errorf("invalid custom_fields: %w", err)
+		}
+		if err := s.checkCustomFieldSchema(ctx, validation.EntityTag, req.CustomFields, ""); err != nil {
+			return nil, err
+		}
+		tag.CustomFields = req.CustomFields
+	}
+	if err := s.checkTagSchema(ctx, tag.Key, tag.Value); err != nil {
+		return nil, err
+	}
+
+	if req.Key != nil {
+		unique, err := s.uniqueTagKey(ctx, tag.Key)
+		if err != nil {
+			return nil, err
+		}
+		tag.UniqueKey = unique
+		if unique {
+			other, err := s.outageTag(ctx, tag.OutageID, tag.Key)
+			if err != nil {
+				return nil, err
+			}
+			if other != nil && other.ID != tag.ID {
+				return nil, fmt.Errorf("outage already has a %s tag %s: %w", tag.Key, other.ID, domain.ErrConflict)
+			}
+		}
+	}
+
+	if err := s.storage.UpdateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// outageTag returns the outage's most recent tag with the key, or nil when
+// it has none
+func (s *Service) outageTag(ctx context.Context, outageID uuid.UUID, key string) (*domain.Tag, error) {
+	tags, err := s.storage.ListTagsByOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if tag.Key == key {
+			return tag, nil
+		}
+	}
+	return nil, nil
+}
+
+// DeleteTag deletes a tag by ID.
+func (s *Service) DeleteTag(ctx context.Context, tagID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteTag")
+	defer span.End()
+
+	if tag, err := s.storage.GetTag(ctx, tagID); err == nil {
+		if err := s.authorizeOutageChange(ctx, tag.OutageID); err != nil {
+			return err
+		}
+	}
+	return s.storage.DeleteTag(ctx, tagID)
+}
+
+// ImportAlert imports an alert from a notification service, along with its
+// provider log events
 func (s *Service) ImportAlert(ctx context.Context, source, externalID string, outageID *uuid.UUID) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.ImportAlert")
+	defer span.End()
+
+	if outageID != nil {
+		if err := s.authorizeOutageChange(ctx, *outageID); err != nil {
+			return nil, err
+		}
+	}
 	svc, ok := s.notificationServices[source]
 	if !ok {
-		return nil, fmt.Errorf("notification service %s not found", source)
+		return nil, fmt.Errorf("notification service %s: %w", source, domain.ErrNotFound)
 	}
 
 	notifAlert, err := svc.FetchAlert(ctx, externalID)
@@ -322,50 +1060,156 @@ func (s *Service) ImportAlert(ctx context.Context, source, externalID string, ou
 	}
 
 	// Check if alert already exists
-	existing, err := s.storage.GetAlertByExternalID(ctx, externalID, source)
-	if err == nil {
-		return existing, nil
+	alert, err := s.storage.GetAlertByExternalID(ctx, externalID, source)
+	if err != nil {
+		if alert, _, err = s.storeAlert(ctx, notifAlert, outageID); err != nil {
+			return nil, err
+		}
 	}
 
+	s.syncAlertEventsBestEffort(ctx, alert)
+	return alert, nil
+}
+
+// storeAlert persists an alert fetched or received from a notification
+// service. An alert without an outageID goes through the routing rules,
+// which may drop it (returning errSuppressed) or link it to an open outage;
+// otherwise a new outage is opened for it. When a concurrent delivery
+// stored the alert first, the stored alert is returned with its lifecycle
+// times filled in, no outage is opened and created is false.
+func (s *Service) storeAlert(ctx context.Context, notifAlert *notification.Alert, outageID *uuid.UUID) (alert *domain.Alert, created bool, err error) {
+	alert = s.newAlert(notifAlert, uuid.Nil, time.Now())
+
 	// Determine outage ID
-	var finalOutageID uuid.UUID
+	var decision domain.RoutingDecision
 	if outageID != nil {
-		finalOutageID = *outageID
+		alert.OutageID = *outageID
 	} else {
-		// Create a new outage for this alert
-		outage := &domain.Outage{
-			ID:          uuid.New(),
-			Title:       notifAlert.Title,
-			Description: notifAlert.Description,
-			Status:      "open",
-			Severity:    notifAlert.Severity,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+		decision = s.routeAlert(ctx, notifAlert, alert.Severity)
+		if decision.Suppress {
+			s.logger.InfoContext(ctx, "alert suppressed by routing rules",
+				"source", notifAlert.Source, "external_id", notifAlert.ExternalID, "rules", decision.Rules)
+			return nil, false, errSuppressed
+		}
+		alert.Service = decision.Service
+		if decision.Severity != "" && decision.Severity != alert.Severity {
+			if alert.SourceMetadata == nil {
+				alert.SourceMetadata = map[string]any{}
+			}
+			alert.SourceMetadata[rawSeverityKey] = notifAlert.Severity
+			alert.Severity = decision.Severity
 		}
-		if err := s.storage.CreateOutage(ctx, outage); err != nil {
-			return nil, fmt.Errorf("failed to create outage: %w", err)
+
+		if decision.AttachToOpenOutage {
+			open, err := s.openOutageFor(ctx, decision.Team)
+			if err != nil {
+				return nil, false, err
+			}
+			if open != nil {
+				alert.OutageID = open.ID
+				s.logger.InfoContext(ctx, "alert linked to open outage by routing rules",
+					"outage_id", open.ID, "source", notifAlert.Source, "external_id", notifAlert.ExternalID, "rules", decision.Rules)
+				s.routeOutage(ctx, open, domain.RoutingDecision{Service: decision.Service})
+			}
 		}
-		finalOutageID = outage.ID
+
 	}
 
+	// An alert without an outage gets a new one, created with the alert so
+	// a concurrent delivery that stored the alert first leaves no outage
+	opened := alert.OutageID == uuid.Nil
+	var stored *domain.Alert
+	if opened {
+		stored, created, err = s.openAlertOutage(ctx, notifAlert, alert, decision)
+	} else {
+		stored, created, err = s.storage.UpsertAlert(ctx, alert)
+		if err != nil {
+			err = fmt.Errorf("failed to create alert: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		s.logger.InfoContext(ctx, "alert already stored by a concurrent delivery",
+			"alert_id", stored.ID, "source", notifAlert.Source, "external_id", notifAlert.ExternalID)
+		return stored, false, nil
+	}
+
+	if len(s.outageListeners) > 0 {
+		outage, err := s.storage.GetOutage(ctx, alert.OutageID)
+		switch {
+		case err != nil:
+			s.logger.WarnContext(ctx, "failed to load outage for listeners", "outage_id", alert.OutageID, "error", err)
+		case opened:
+			s.notifyOutageCreated(ctx, outage)
+		default:
+			s.notifyAlertAdded(ctx, outage, alert)
+		}
+	}
+	if opened && s.similarPolicy != nil {
+		if outage, err := s.storage.GetOutage(ctx, alert.OutageID); err == nil {
+			s.noteSimilarOutages(ctx, outage)
+		}
+	}
+
+	return alert, true, nil
+}
+
+// openAlertOutage opens a new outage for an alert, storing the two
+// together. The outage's status history and routing are only recorded once
+// the alert is known to be new.
+func (s *Service) openAlertOutage(ctx context.Context, notifAlert *notification.Alert, alert *domain.Alert, decision domain.RoutingDecision) (*domain.Alert, bool, error) {
+	outage := &domain.Outage{
+		ID:          uuid.New(),
+		Title:       alertOutageTitle(notifAlert),
+		Description: notifAlert.Description,
+		Status:      domain.StatusOpen,
+		Severity:    notification.OutageSeverity(alert.Severity),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	stored, created, err := s.storage.CreateOutageWithAlert(ctx, outage, alert)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create outage: %w", err)
+	}
+	if !created {
+		return stored, false, nil
+	}
+	if err := s.recordStatusChange(ctx, outage.ID, "", outage.Status, domain.TransitionRequest{}, outage.CreatedAt); err != nil {
+		return nil, false, err
+	}
+	s.logger.InfoContext(ctx, "outage opened from alert",
+		"outage_id", outage.ID, "source", notifAlert.Source, "external_id", notifAlert.ExternalID)
+	s.routeOutage(ctx, outage, decision)
+	return stored, true, nil
+}
+
+// newAlert builds a domain alert from a notification alert, mapping its
+// severity and keeping the source's native value when the mapping changes it.
+func (s *Service) newAlert(notifAlert *notification.Alert, outageID uuid.UUID, createdAt time.Time) *domain.Alert {
 	alert := &domain.Alert{
 		ID:             uuid.New(),
-		OutageID:       finalOutageID,
+		OutageID:       outageID,
 		ExternalID:     notifAlert.ExternalID,
 		Source:         notifAlert.Source,
 		TeamName:       notifAlert.TeamName,
+		TeamNames:      slices.Clone(notifAlert.TeamNames),
 		Title:          notifAlert.Title,
 		Description:    notifAlert.Description,
-		Severity:       notifAlert.Severity,
+		Severity:       s.severityMapping.Normalize(notifAlert.Source, notifAlert.Severity),
+		Fingerprint:    fingerprint.Alert(notifAlert.Source, notifAlert.Title),
 		TriggeredAt:    notifAlert.TriggeredAt,
 		AcknowledgedAt: notifAlert.AcknowledgedAt,
 		ResolvedAt:     notifAlert.ResolvedAt,
-		CreatedAt:      time.Now(),
+		CreatedAt:      createdAt,
+		SourceMetadata: maps.Clone(notifAlert.SourceMetadata),
 	}
-
-	if err := s.storage.CreateAlert(ctx, alert); err != nil {
-		return nil, fmt.Errorf("failed to create alert: %w", err)
+	if alert.Severity != notifAlert.Severity {
+		if alert.SourceMetadata == nil {
+			alert.SourceMetadata = map[string]any{}
+		}
+		alert.SourceMetadata[rawSeverityKey] = notifAlert.Severity
 	}
-
-	return alert, nil
+	return alert
 }
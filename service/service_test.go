@@ -3,10 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
 	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/notification"
 	"github.com/conall/outalator/storage"
 	"github.com/google/uuid"
 )
@@ -15,7 +19,7 @@ import (
 var _ storage.Storage = (*testutil.MemStorage)(nil)
 
 func newSvc() *Service {
-	return New(testutil.NewMemStorage())
+	return New(testutil.NewMemStorage(), logging.Discard())
 }
 
 func TestCreateOutage(t *testing.T) {
@@ -225,6 +229,33 @@ func TestAddNote(t *testing.T) {
 			req:      domain.AddNoteRequest{Content: "test", Format: "plaintext", Author: "bob"},
 			wantErr:  true,
 		},
+		{
+			name:     "log note",
+			outageID: created.ID,
+			req:      domain.AddNoteRequest{Content: "panic: nil map\n\tmain.go:12\n", Format: "log", Author: "alice"},
+		},
+		{
+			name:     "unknown format",
+			outageID: created.ID,
+			req:      domain.AddNoteRequest{Content: "test", Format: "html", Author: "bob"},
+			wantErr:  true,
+		},
+		{
+			name:     "markdown note",
+			outageID: created.ID,
+			req:      domain.AddNoteRequest{Content: "**Mitigated** via [runbook](https://runbooks.example.com/db)", Format: "markdown", Author: "alice"},
+		},
+		{
+			name:     "markdown with embedded script",
+			outageID: created.ID,
+			req:      domain.AddNoteRequest{Content: "done <script>alert(1)</script>", Format: "markdown", Author: "bob"},
+			wantErr:  true,
+		},
+		{
+			name:     "plaintext with html",
+			outageID: created.ID,
+			req:      domain.AddNoteRequest{Content: "saw <script> in the logs", Format: "plaintext", Author: "bob"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -244,6 +275,36 @@ func TestAddNote(t *testing.T) {
 	}
 }
 
+func TestUpdateNoteValidatesMarkdown(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "click [here](javascript:alert(1))", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	markdown := domain.NoteFormatMarkdown
+	if _, err := svc.UpdateNote(ctx, note.ID, nil, &markdown, nil, nil, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateNote(format) err = %v, want ErrInvalidInput", err)
+	}
+	content := "click [here](https://status.example.com)"
+	updated, err := svc.UpdateNote(ctx, note.ID, &content, &markdown, nil, nil, "")
+	if err != nil {
+		t.Fatalf("UpdateNote(content, format): %v", err)
+	}
+	if updated.Format != domain.NoteFormatMarkdown {
+		t.Errorf("Format = %q, want markdown", updated.Format)
+	}
+	unsafe := "<img src=x onerror=alert(1)>"
+	if _, err := svc.UpdateNote(ctx, note.ID, &unsafe, nil, nil, nil, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateNote(content) err = %v, want ErrInvalidInput", err)
+	}
+}
+
 func TestAddTag(t *testing.T) {
 	svc := newSvc()
 	created, err := svc.CreateOutage(context.Background(), domain.CreateOutageRequest{
@@ -323,6 +384,73 @@ func TestFindOutagesByTag(t *testing.T) {
 	}
 }
 
+func TestFindOutagesByTagQuery(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	tagged := map[string][][2]string{
+		"api":      {{"service", "api"}, {"env", "prod"}},
+		"web":      {{"service", "web"}, {"env", "prod"}},
+		"staging":  {{"service", "api"}, {"env", "staging"}},
+		"untagged": nil,
+	}
+	for title, tags := range tagged {
+		outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: title, Severity: "low"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range tags {
+			if _, err := svc.AddTag(ctx, outage.ID, tag[0], tag[1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		terms []string
+		want  []string
+	}{
+		{[]string{"service:api,web"}, []string{"api", "staging", "web"}},
+		{[]string{"service:api", "env:prod"}, []string{"api"}},
+		{[]string{"service", "!env:staging"}, []string{"api", "web"}},
+		{[]string{"!service"}, []string{"untagged"}},
+		{[]string{"service:api", "!env:prod,staging"}, nil},
+	}
+	for _, tt := range tests {
+		var query domain.TagQuery
+		for _, raw := range tt.terms {
+			term, err := domain.ParseTagTerm(raw)
+			if err != nil {
+				t.Fatalf("ParseTagTerm(%q) err = %v", raw, err)
+			}
+			if term.String() != raw {
+				t.Errorf("ParseTagTerm(%q).String() = %q", raw, term.String())
+			}
+			query.Terms = append(query.Terms, term)
+		}
+		outages, err := svc.FindOutagesByTagQuery(ctx, query)
+		if err != nil {
+			t.Fatalf("FindOutagesByTagQuery(%v) err = %v", tt.terms, err)
+		}
+		var got []string
+		for _, o := range outages {
+			got = append(got, o.Title)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("FindOutagesByTagQuery(%v) = %v, want %v", tt.terms, got, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"", "!", ":api", "service:", "service: , "} {
+		if _, err := domain.ParseTagTerm(raw); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("ParseTagTerm(%q) err = %v, want ErrInvalidInput", raw, err)
+		}
+	}
+	if _, err := svc.FindOutagesByTagQuery(ctx, domain.TagQuery{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("FindOutagesByTagQuery(no terms) err = %v, want ErrInvalidInput", err)
+	}
+}
+
 func TestErrNotFound(t *testing.T) {
 	svc := newSvc()
 	_, err := svc.GetOutage(context.Background(), uuid.New())
@@ -428,3 +556,161 @@ func TestListAlertsByOutage(t *testing.T) {
 		t.Errorf("expected 0 alerts, got %d", len(alerts))
 	}
 }
+
+func TestListNotesByOutagePage(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "note", Format: "plaintext", Author: "alice"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		wantLen       int
+		wantErr       bool
+	}{
+		{name: "all", wantLen: 5},
+		{name: "first page", limit: 2, wantLen: 2},
+		{name: "last page", limit: 2, offset: 4, wantLen: 1},
+		{name: "past the end", limit: 2, offset: 9, wantLen: 0},
+		{name: "negative offset", offset: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, total, err := svc.ListNotesByOutagePage(ctx, o.ID, tt.limit, tt.offset, false)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidInput) {
+					t.Fatalf("got %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(notes) != tt.wantLen || total != 5 {
+				t.Errorf("got %d notes of %d, want %d of 5", len(notes), total, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestGetAndListTags(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := svc.AddTag(ctx, o.ID, "service", "db", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.GetTag(ctx, tag.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Key != "service" || got.Value != "db" {
+		t.Errorf("GetTag = %+v", got)
+	}
+	tags, err := svc.ListTagsByOutage(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 {
+		t.Errorf("ListTagsByOutage len = %d, want 1", len(tags))
+	}
+
+	if err := svc.DeleteTag(ctx, tag.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetTag(ctx, tag.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTag after delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateTag(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := svc.AddTag(ctx, o.ID, "jira", "OPS-1", map[string]any{"status": "open"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := "OPS-2"
+	updated, err := svc.UpdateTag(ctx, tag.ID, domain.UpdateTagRequest{Value: &value})
+	if err != nil {
+		t.Fatalf("UpdateTag() err = %v", err)
+	}
+	got, err := svc.GetTag(ctx, tag.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Value != "OPS-2" || got.Value != "OPS-2" || got.Key != "jira" || got.OutageID != o.ID || got.CustomFields["status"] != "open" {
+		t.Errorf("GetTag after update = %+v, want only the value changed", got)
+	}
+
+	blank := " "
+	if _, err := svc.UpdateTag(ctx, tag.ID, domain.UpdateTagRequest{Key: &blank}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateTag blank key err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.UpdateTag(ctx, uuid.New(), domain.UpdateTagRequest{Value: &value}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateTag unknown tag err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateAlert(t *testing.T) {
+	svc := newSvc()
+	svc.SetAlertResolutionPolicy(domain.AlertResolutionPolicy{ResolveOutages: true})
+	ctx := context.Background()
+	alert, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "fake", Title: "db down", Severity: "high", TriggeredAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.GetAlertByExternalID(ctx, "A1", "fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != alert.ID {
+		t.Errorf("GetAlertByExternalID returned alert %s, want %s", got.ID, alert.ID)
+	}
+
+	title := "db down in eu-west"
+	acked := time.Now()
+	updated, err := svc.UpdateAlert(ctx, alert.ID, domain.UpdateAlertRequest{Title: &title, AcknowledgedAt: &acked, Metadata: map[string]string{"region": "eu-west"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Title != title || updated.AcknowledgedAt == nil || updated.Metadata["region"] != "eu-west" {
+		t.Errorf("UpdateAlert = %+v", updated)
+	}
+
+	// Resolving the outage's only alert resolves the outage
+	resolved := time.Now()
+	if _, err := svc.UpdateAlert(ctx, alert.ID, domain.UpdateAlertRequest{ResolvedAt: &resolved}); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := svc.GetOutage(ctx, alert.OutageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outage.Status != domain.StatusResolved {
+		t.Errorf("outage status = %q, want resolved", outage.Status)
+	}
+
+	if _, err := svc.UpdateAlert(ctx, uuid.New(), domain.UpdateAlertRequest{Title: &title}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown alert: got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// rawSeverityKey is the alert source_metadata key that preserves a source's
+// native severity once it has been mapped
+const rawSeverityKey = notification.RawSeverityKey
+
+// recomputePageSize is the number of outages read per page during a recompute
+const recomputePageSize = 100
+
+// SetSeverityMapping installs the mapping from source-native severities to
+// Outalator severities applied to incoming alerts, layered over
+// notification.DefaultSeverityMapping
+func (s *Service) SetSeverityMapping(mapping notification.SeverityMapping) error {
+	if err := mapping.Check(); err != nil {
+		return err
+	}
+	s.severityMapping = mapping.WithDefaults()
+	return nil
+}
+
+// rawSeverity returns the source-native severity of a stored alert
+func rawSeverity(alert *domain.Alert) string {
+	if raw, ok := alert.SourceMetadata[rawSeverityKey].(string); ok {
+		return raw
+	}
+	return alert.Severity
+}
+
+// RecomputeSeverities re-applies the severity mapping to every stored alert,
+// and to outages created from alerts whose severity is not yet an Outalator
+// severity, including those left unclassified because the mapping had no
+// entry for their alert's severity. Outages with a recognised severity are
+// left alone so manual changes survive. With dryRun set nothing is written and the returned
+// changes describe what would be updated.
+func (s *Service) RecomputeSeverities(ctx context.Context, dryRun bool) ([]domain.SeverityChange, error) {
+	ctx, span := tracer.Start(ctx, "Service.RecomputeSeverities")
+	defer span.End()
+
+	var changes []domain.SeverityChange
+	for offset := 0; ; offset += recomputePageSize {
+		outages, err := s.storage.ListOutages(ctx, recomputePageSize, offset, false)
+		if err != nil {
+			return changes, fmt.Errorf("failed to list outages: %w", err)
+		}
+		if err := s.storage.LoadOutageAssociations(ctx, outages, domain.AllOutageAssociations); err != nil {
+			return changes, fmt.Errorf("failed to load alerts: %w", err)
+		}
+
+		for _, outage := range outages {
+			alerts := outage.Alerts
+			for i := range alerts {
+				alert := &alerts[i]
+				raw := rawSeverity(alert)
+				mapped := s.severityMapping.Normalize(alert.Source, raw)
+				if mapped == alert.Severity {
+					continue
+				}
+				changes = append(changes, domain.SeverityChange{
+					Entity: "alert", ID: alert.ID, OutageID: outage.ID,
+					Source: alert.Source, From: alert.Severity, To: mapped,
+				})
+				if dryRun {
+					continue
+				}
+				if alert.SourceMetadata == nil {
+					alert.SourceMetadata = make(map[string]any)
+				}
+				alert.SourceMetadata[rawSeverityKey] = raw
+				alert.Severity = mapped
+				if err := s.storage.UpdateAlert(ctx, alert); err != nil {
+					return changes, fmt.Errorf("failed to update alert %s: %w", alert.ID, err)
+				}
+			}
+
+			if len(alerts) == 0 || slices.Contains(notification.Severities, outage.Severity) {
+				continue
+			}
+			raw := outage.Severity
+			if raw == "" {
+				raw = rawSeverity(&alerts[0])
+			}
+			mapped := notification.OutageSeverity(s.severityMapping.Normalize(alerts[0].Source, raw))
+			if mapped == outage.Severity {
+				continue
+			}
+			changes = append(changes, domain.SeverityChange{
+				Entity: "outage", ID: outage.ID, OutageID: outage.ID,
+				Source: alerts[0].Source, From: outage.Severity, To: mapped,
+			})
+			if dryRun {
+				continue
+			}
+			outage.Severity = mapped
+			outage.UpdatedAt = time.Now()
+			if err := s.storage.UpdateOutage(ctx, outage); err != nil {
+				return changes, fmt.Errorf("failed to update outage %s: %w", outage.ID, err)
+			}
+		}
+
+		if len(outages) < recomputePageSize {
+			break
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+func TestSetSeverityMapping_RejectsUnknownTarget(t *testing.T) {
+	svc := newSvc()
+	err := svc.SetSeverityMapping(notification.SeverityMapping{"opsgenie": {"P1": "sev1"}})
+	if err == nil {
+		t.Fatal("expected mapping to an unknown severity to be rejected")
+	}
+}
+
+func TestSetSeverityMapping_LayersOverDefaults(t *testing.T) {
+	svc := newSvc()
+	if got := svc.severityMapping.Normalize("opsgenie", "P2"); got != "high" {
+		t.Errorf("default OpsGenie P2 = %q, want high", got)
+	}
+	if err := svc.SetSeverityMapping(notification.SeverityMapping{"opsgenie": {"P5": "medium"}}); err != nil {
+		t.Fatal(err)
+	}
+	for native, want := range map[string]string{"P1": "critical", "P5": "medium", "P9": "P9"} {
+		if got := svc.severityMapping.Normalize("opsgenie", native); got != want {
+			t.Errorf("OpsGenie %s = %q, want %q", native, got, want)
+		}
+	}
+}
+
+func TestRecomputeSeverities(t *testing.T) {
+	store := testutil.NewMemStorage()
+	svc := New(store, logging.Discard())
+	ctx := context.Background()
+	now := time.Now()
+
+	// Historical data ingested before any mapping existed.
+	outage := &domain.Outage{ID: uuid.New(), Title: "t", Status: "resolved", Severity: "P1", CreatedAt: now, UpdatedAt: now}
+	if err := store.CreateOutage(ctx, outage); err != nil {
+		t.Fatal(err)
+	}
+	manual := &domain.Outage{ID: uuid.New(), Title: "m", Status: "open", Severity: "medium", CreatedAt: now, UpdatedAt: now}
+	if err := store.CreateOutage(ctx, manual); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []*domain.Alert{
+		{ID: uuid.New(), OutageID: outage.ID, Source: "opsgenie", ExternalID: "a1", Severity: "P1", TriggeredAt: now},
+		{ID: uuid.New(), OutageID: outage.ID, Source: "opsgenie", ExternalID: "a2", Severity: "P3", TriggeredAt: now},
+		{ID: uuid.New(), OutageID: manual.ID, Source: "opsgenie", ExternalID: "a3", Severity: "P1", TriggeredAt: now},
+	} {
+		if err := store.CreateAlert(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := svc.SetSeverityMapping(notification.SeverityMapping{
+		"opsgenie": {"P1": "critical", "P3": "medium"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := svc.RecomputeSeverities(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Three alerts plus the outage still carrying a native severity; the
+	// manually classified outage is left alone.
+	if len(changes) != 4 {
+		t.Fatalf("dry run proposed %d changes, want 4: %+v", len(changes), changes)
+	}
+	got, err := store.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Severity != "P1" {
+		t.Errorf("dry run modified outage severity to %q", got.Severity)
+	}
+
+	if _, err := svc.RecomputeSeverities(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	got, err = store.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Severity != "critical" {
+		t.Errorf("outage severity = %q, want critical", got.Severity)
+	}
+	alerts, err := store.ListAlertsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range alerts {
+		if a.SourceMetadata[rawSeverityKey] == nil {
+			t.Errorf("alert %s lost its native severity", a.ExternalID)
+		}
+	}
+
+	// Re-running after the mapping changes works from the preserved native value.
+	if err := svc.SetSeverityMapping(notification.SeverityMapping{
+		"opsgenie": {"P1": "high", "P3": "medium"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	changes, err = svc.RecomputeSeverities(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Errorf("remap proposed %d changes, want 2 (the P1 alerts): %+v", len(changes), changes)
+	}
+}
+
+func TestProcessWebhook_AppliesSeverityMapping(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	if err := svc.SetSeverityMapping(notification.SeverityMapping{"fake": {"urgent": "critical"}}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	payload := []byte(`{"ExternalID":"X1","Source":"fake","Title":"t","Severity":"urgent","TriggeredAt":"2024-01-01T00:00:00Z","SourceMetadata":{"html_url":"https://fake.example/X1"}}`)
+	if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	outages, err := svc.ListOutages(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 1 || outages[0].Severity != "critical" {
+		t.Fatalf("outages = %+v, want one critical outage", outages)
+	}
+	alerts, err := svc.ListAlertsByOutage(ctx, outages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts[0].Severity != "critical" || alerts[0].SourceMetadata[rawSeverityKey] != "urgent" ||
+		alerts[0].SourceMetadata["html_url"] != "https://fake.example/X1" {
+		t.Errorf("alert = %+v", alerts[0])
+	}
+}
+
+func TestProcessWebhook_UnmappedSeverityLeavesOutageUnclassified(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	ctx := context.Background()
+
+	payload := []byte(`{"ExternalID":"X1","Source":"fake","Title":"t","Severity":"sev9","TriggeredAt":"2024-01-01T00:00:00Z"}`)
+	if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	outages, err := svc.ListOutages(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 1 || outages[0].Severity != "" {
+		t.Fatalf("outages = %+v, want one unclassified outage", outages)
+	}
+	alerts, err := svc.ListAlertsByOutage(ctx, outages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts[0].Severity != "sev9" {
+		t.Errorf("alert severity = %q, want the native sev9", alerts[0].Severity)
+	}
+
+	// Once the mapping has an entry, recomputing classifies the outage
+	if err := svc.SetSeverityMapping(notification.SeverityMapping{"fake": {"sev9": "high"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RecomputeSeverities(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.GetOutage(ctx, outages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Severity != "high" {
+		t.Errorf("outage severity after recompute = %q, want high", got.Severity)
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/similarity"
+	"github.com/google/uuid"
+)
+
+// Weights of the parts of a similarity score, adding up to 1. A matching
+// title counts most; a shared owning team only tips the balance.
+const (
+	similarTitleWeight       = 0.5
+	similarDescriptionWeight = 0.15
+	similarTagsWeight        = 0.25
+	similarTeamWeight        = 0.1
+)
+
+// similarTextThreshold is the text similarity from which a title or
+// description is given as a reason
+const similarTextThreshold = 0.3
+
+// similarCandidates caps how many of the most recent outages are compared.
+// Older outages sharing a tag are compared too.
+const similarCandidates = 500
+
+// Number of similar outages returned when no limit is given, and the most
+// that can be asked for
+const (
+	defaultSimilarLimit = 5
+	maxSimilarLimit     = 50
+)
+
+// SetSimilarOutagesPolicy enables the note listing possibly related
+// outages on each new outage. Without a policy no note is added.
+func (s *Service) SetSimilarOutagesPolicy(policy domain.SimilarOutagesPolicy) {
+	s.similarPolicy = &policy
+}
+
+// FindSimilarOutages returns past outages resembling an outage, most
+// similar first. Outages score by how alike their titles and descriptions
+// are, how many of the outage's tags they share and whether they have the
+// same owning team. Only outages scoring at least minScore are returned;
+// zero means domain.DefaultSimilarMinScore.
+func (s *Service) FindSimilarOutages(ctx context.Context, id uuid.UUID, limit int, minScore float64) ([]*domain.SimilarOutage, error) {
+	ctx, span := tracer.Start(ctx, "Service.FindSimilarOutages")
+	defer span.End()
+
+	if minScore < 0 || minScore > 1 {
+		return nil, fmt.Errorf("%w: min_score must be between 0 and 1", domain.ErrInvalidInput)
+	}
+	outage, err := s.liveOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.similarOutages(ctx, outage, limit, minScore)
+}
+
+// similarOutages scores the candidate outages against outage
+func (s *Service) similarOutages(ctx context.Context, outage *domain.Outage, limit int, minScore float64) ([]*domain.SimilarOutage, error) {
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+	if limit > maxSimilarLimit {
+		limit = maxSimilarLimit
+	}
+	if minScore == 0 {
+		minScore = domain.DefaultSimilarMinScore
+	}
+
+	candidates := make(map[uuid.UUID]*domain.Outage)
+	recent, err := s.storage.ListOutages(ctx, similarCandidates, 0, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outages: %w", err)
+	}
+	for _, o := range recent {
+		candidates[o.ID] = o
+	}
+
+	sharedTags := make(map[uuid.UUID][]string)
+	tags := make(map[string]bool)
+	for _, tag := range outage.Tags {
+		tagText := tag.Key + "=" + tag.Value
+		if tags[tagText] {
+			continue
+		}
+		tags[tagText] = true
+		tagged, err := s.storage.FindOutagesByTag(ctx, tag.Key, tag.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find outages by tag: %w", err)
+		}
+		for _, o := range tagged {
+			if o.DeletedAt != nil {
+				continue
+			}
+			candidates[o.ID] = o
+			sharedTags[o.ID] = append(sharedTags[o.ID], tagText)
+		}
+	}
+	delete(candidates, outage.ID)
+
+	var similar []*domain.SimilarOutage
+	for id, candidate := range candidates {
+		match := scoreSimilarity(outage, candidate, sharedTags[id], len(tags))
+		if match.Score >= minScore {
+			similar = append(similar, match)
+		}
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		return similar[i].Outage.CreatedAt.After(similar[j].Outage.CreatedAt)
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+// scoreSimilarity scores how alike candidate is to outage. shared lists the
+// tags they have in common out of outage's tagCount distinct tags. Parts
+// that cannot apply, such as tags when outage has none, are left out of the
+// score rather than counted as differences.
+func scoreSimilarity(outage, candidate *domain.Outage, shared []string, tagCount int) *domain.SimilarOutage {
+	match := &domain.SimilarOutage{Outage: candidate, Reasons: []string{}}
+	var score, weights float64
+
+	title := similarity.TrigramSimilarity(outage.Title, candidate.Title)
+	score += similarTitleWeight * title
+	weights += similarTitleWeight
+	if title >= similarTextThreshold {
+		match.Reasons = append(match.Reasons, domain.SimilarTitle)
+	}
+
+	if outage.Description != "" && candidate.Description != "" {
+		description := similarity.WordOverlap(outage.Description, candidate.Description)
+		score += similarDescriptionWeight * description
+		weights += similarDescriptionWeight
+		if description >= similarTextThreshold {
+			match.Reasons = append(match.Reasons, domain.SimilarDescription)
+		}
+	}
+
+	if tagCount > 0 {
+		score += similarTagsWeight * float64(len(shared)) / float64(tagCount)
+		weights += similarTagsWeight
+		if len(shared) > 0 {
+			sort.Strings(shared)
+			match.SharedTags = shared
+			match.Reasons = append(match.Reasons, domain.SimilarTags)
+		}
+	}
+
+	if outage.OwningTeam != "" {
+		weights += similarTeamWeight
+		if outage.OwningTeam == candidate.OwningTeam {
+			score += similarTeamWeight
+			match.Reasons = append(match.Reasons, domain.SimilarTeam)
+		}
+	}
+
+	match.Score = score / weights
+	return match
+}
+
+// noteSimilarOutages adds a note listing past outages that may be related
+// to a new outage, when the policy asks for it and any are found. Failures
+// are logged rather than returned so they do not fail outage creation.
+func (s *Service) noteSimilarOutages(ctx context.Context, outage *domain.Outage) {
+	if s.similarPolicy == nil {
+		return
+	}
+	similar, err := s.similarOutages(ctx, outage, s.similarPolicy.Limit, s.similarPolicy.MinScore)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to find similar outages", "outage_id", outage.ID, "error", err)
+		return
+	}
+	if len(similar) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Possibly related past outages:\n\n")
+	for _, m := range similar {
+		fmt.Fprintf(&sb, "- %s (%s, %s, %.0f%% similar", m.Outage.Title, m.Outage.ID, m.Outage.Status, m.Score*100)
+		if len(m.SharedTags) > 0 {
+			fmt.Fprintf(&sb, ", shares %s", strings.Join(m.SharedTags, ", "))
+		}
+		sb.WriteString(")\n")
+	}
+
+	_, err = s.AddNote(ctx, outage.ID, domain.AddNoteRequest{
+		Content:  sb.String(),
+		Format:   domain.NoteFormatPlaintext,
+		Author:   domain.SimilarOutagesAuthor,
+		Metadata: map[string]string{domain.NoteMetadataSimilarOutages: fmt.Sprint(len(similar))},
+	})
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to add similar outages note", "outage_id", outage.ID, "error", err)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+func TestFindSimilarOutages(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{{Name: "payments"}, {Name: "search"}}}, false, false); err != nil {
+		t.Fatal(err)
+	}
+	create := func(req domain.CreateOutageRequest) *domain.Outage {
+		t.Helper()
+		req.Severity = "high"
+		outage, err := svc.CreateOutage(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return outage
+	}
+
+	timeouts := create(domain.CreateOutageRequest{Title: "Checkout API timeouts", OwningTeam: "payments",
+		Tags: []domain.TagInput{{Key: "service", Value: "checkout"}}})
+	tagged := create(domain.CreateOutageRequest{Title: "Card declines spiking", OwningTeam: "payments",
+		Tags: []domain.TagInput{{Key: "service", Value: "checkout"}, {Key: "region", Value: "eu"}}})
+	create(domain.CreateOutageRequest{Title: "Search index rebuild stuck", OwningTeam: "search"})
+	trashed := create(domain.CreateOutageRequest{Title: "Checkout API timeouts again"})
+	if err := svc.DeleteOutage(ctx, trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	outage := create(domain.CreateOutageRequest{Title: "Checkout API timing out", OwningTeam: "payments",
+		Tags: []domain.TagInput{{Key: "service", Value: "checkout"}, {Key: "region", Value: "eu"}}})
+
+	similar, err := svc.FindSimilarOutages(ctx, outage.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(similar) != 2 || similar[0].Outage.ID != timeouts.ID || similar[1].Outage.ID != tagged.ID {
+		t.Fatalf("similar = %+v, want the timeouts outage then the tagged one", similar)
+	}
+	best := similar[0]
+	for _, reason := range []string{domain.SimilarTitle, domain.SimilarTags, domain.SimilarTeam} {
+		if !slices.Contains(best.Reasons, reason) {
+			t.Errorf("reasons = %v, want %s", best.Reasons, reason)
+		}
+	}
+	if len(best.SharedTags) != 1 || best.SharedTags[0] != "service=checkout" {
+		t.Errorf("shared tags = %v, want service=checkout", best.SharedTags)
+	}
+
+	if only, err := svc.FindSimilarOutages(ctx, outage.ID, 1, 0.9); err != nil || len(only) != 0 {
+		t.Errorf("FindSimilarOutages(min 0.9) = %+v, %v, want none", only, err)
+	}
+	if _, err := svc.FindSimilarOutages(ctx, outage.ID, 0, 2); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("min score 2 error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSimilarOutagesNote(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	past, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Checkout API timeouts", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc.SetSimilarOutagesPolicy(domain.SimilarOutagesPolicy{})
+	alert, err := svc.IngestAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "fake", Title: "Checkout API timeouts", TriggeredAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := svc.ListNotesByOutage(ctx, alert.OutageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || notes[0].Metadata[domain.NoteMetadataSimilarOutages] != "1" || !strings.Contains(notes[0].Content, past.ID.String()) {
+		t.Fatalf("notes = %+v, want one listing the past outage", notes)
+	}
+
+	unrelated, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Search index rebuild stuck", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notes, err := svc.ListNotesByOutage(ctx, unrelated.ID); err != nil || len(notes) != 0 {
+		t.Errorf("notes on unrelated outage = %+v, %v, want none", notes, err)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// SourceStaleNotifier raises an alarm for an alert source that has gone
+// without a successful ingestion for longer than its staleness threshold
+type SourceStaleNotifier interface {
+	NotifySourceStale(ctx context.Context, health domain.SourceHealth) error
+}
+
+// RegisterSourceStaleNotifier adds a notifier that is called once each time
+// an alert source becomes stale
+func (s *Service) RegisterSourceStaleNotifier(n SourceStaleNotifier) {
+	s.sourceStaleNotifiers = append(s.sourceStaleNotifiers, n)
+}
+
+// SetStalenessPolicy installs the per-source staleness thresholds. Every
+// threshold must be zero, which disables the check, or positive.
+func (s *Service) SetStalenessPolicy(policy domain.StalenessPolicy) error {
+	if policy.Default < 0 {
+		return fmt.Errorf("default max silence must not be negative: %w", domain.ErrInvalidInput)
+	}
+	for source, d := range policy.Sources {
+		if d < 0 {
+			return fmt.Errorf("max silence for %s must not be negative: %w", source, domain.ErrInvalidInput)
+		}
+	}
+	s.stalenessPolicy = policy
+	return nil
+}
+
+// ListSourceHealth reports when alerts were last ingested from every known
+// source, sorted by name: the registered notification services, sources
+// with a staleness threshold and any source ingestion was recorded for. A
+// source is stale at now when its threshold has passed since its last
+// successful ingestion, or since the service started if it has none.
+func (s *Service) ListSourceHealth(ctx context.Context, now time.Time) ([]domain.SourceHealth, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListSourceHealth")
+	defer span.End()
+
+	records, err := s.storage.ListIngestionRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bySource := make(map[string]domain.IngestionRecord, len(records))
+	for _, r := range records {
+		bySource[r.Source] = *r
+	}
+	for _, name := range s.NotificationSources() {
+		if _, ok := bySource[name]; !ok {
+			bySource[name] = domain.IngestionRecord{Source: name}
+		}
+	}
+	for name := range s.stalenessPolicy.Sources {
+		if _, ok := bySource[name]; !ok {
+			bySource[name] = domain.IngestionRecord{Source: name}
+		}
+	}
+
+	health := make([]domain.SourceHealth, 0, len(bySource))
+	for _, record := range bySource {
+		h := domain.SourceHealth{IngestionRecord: record}
+		if maxSilence := s.stalenessPolicy.MaxSilence(record.Source); maxSilence > 0 {
+			h.MaxSilenceSeconds = int(maxSilence.Seconds())
+			h.Stale = now.Sub(s.lastIngestion(record)) > maxSilence
+		}
+		health = append(health, h)
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].Source < health[j].Source })
+	return health, nil
+}
+
+// lastIngestion returns the time staleness is measured from
+func (s *Service) lastIngestion(record domain.IngestionRecord) time.Time {
+	if record.LastSuccessAt != nil {
+		return *record.LastSuccessAt
+	}
+	return s.startedAt
+}
+
+// CheckSourceHealth alarms the registered notifiers about every source that
+// is stale at now and returns how many sources were alarmed about. A source
+// is alarmed about once until it next ingests successfully. Delivery is
+// best effort: notifier failures are logged.
+func (s *Service) CheckSourceHealth(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "Service.CheckSourceHealth")
+	defer span.End()
+
+	health, err := s.ListSourceHealth(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	alarmed := 0
+	stale := make(map[string]bool)
+	for _, h := range health {
+		if !h.Stale {
+			continue
+		}
+		stale[h.Source] = true
+		if !s.sentStaleAlarms.claim(h.Source, s.lastIngestion(h.IngestionRecord)) {
+			continue
+		}
+		s.logger.WarnContext(ctx, "alert source is stale",
+			"source", h.Source, "last_success_at", h.LastSuccessAt, "last_error", h.LastError)
+		for _, n := range s.sourceStaleNotifiers {
+			if err := n.NotifySourceStale(ctx, h); err != nil {
+				s.logger.WarnContext(ctx, "failed to send stale source alarm", "source", h.Source, "error", err)
+			}
+		}
+		alarmed++
+	}
+	s.sentStaleAlarms.retain(stale)
+	return alarmed, nil
+}
+
+// recordIngestion records the outcome of a webhook delivery or sync pass
+// for a source. Failures to record are logged rather than returned so they
+// never fail ingestion itself.
+func (s *Service) recordIngestion(ctx context.Context, source string, at time.Time, ingestErr error) {
+	var err error
+	if ingestErr == nil {
+		err = s.storage.RecordIngestionSuccess(ctx, source, at)
+	} else {
+		err = s.storage.RecordIngestionFailure(ctx, source, at, ingestErr.Error())
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to record source ingestion", "source", source, "error", err)
+	}
+}
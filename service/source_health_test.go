@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+type recordingStaleNotifier struct {
+	got []domain.SourceHealth
+}
+
+func (r *recordingStaleNotifier) NotifySourceStale(_ context.Context, health domain.SourceHealth) error {
+	r.got = append(r.got, health)
+	return nil
+}
+
+func TestSetStalenessPolicy_Validation(t *testing.T) {
+	svc := newSvc()
+	if err := svc.SetStalenessPolicy(domain.StalenessPolicy{Default: -time.Minute}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("negative default: got %v, want ErrInvalidInput", err)
+	}
+	if err := svc.SetStalenessPolicy(domain.StalenessPolicy{Sources: map[string]time.Duration{"fake": -time.Minute}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("negative source threshold: got %v, want ErrInvalidInput", err)
+	}
+	if err := svc.SetStalenessPolicy(domain.StalenessPolicy{Default: time.Hour, Sources: map[string]time.Duration{"fake": 0}}); err != nil {
+		t.Errorf("SetStalenessPolicy: %v", err)
+	}
+}
+
+func TestListSourceHealth_WebhookOutcomes(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	ctx := context.Background()
+	if err := svc.SetStalenessPolicy(domain.StalenessPolicy{Default: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := time.Now().UTC().Truncate(time.Second)
+	payload, err := json.Marshal(notification.Alert{ExternalID: "A1", Source: "fake", Title: "disk full", TriggeredAt: delivered})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ProcessWebhook(ctx, "fake", payload, delivered); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+	failed := delivered.Add(time.Minute)
+	if err := svc.ProcessWebhook(ctx, "fake", []byte("not json"), failed); err == nil {
+		t.Fatal("ProcessWebhook with bad payload: expected error")
+	}
+
+	health, err := svc.ListSourceHealth(ctx, delivered.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("ListSourceHealth: %v", err)
+	}
+	if len(health) != 1 {
+		t.Fatalf("ListSourceHealth = %+v, want one source", health)
+	}
+	h := health[0]
+	if h.Source != "fake" || h.LastSuccessAt == nil || !h.LastSuccessAt.Equal(delivered) {
+		t.Errorf("health = %+v, want last success at %v", h, delivered)
+	}
+	if h.LastFailureAt == nil || !h.LastFailureAt.Equal(failed) || h.LastError == "" {
+		t.Errorf("health = %+v, want last failure at %v with an error", h, failed)
+	}
+	if h.Stale || h.MaxSilenceSeconds != 3600 {
+		t.Errorf("health after 30m = %+v, want not stale with a 1h threshold", h)
+	}
+
+	health, err = svc.ListSourceHealth(ctx, delivered.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("ListSourceHealth: %v", err)
+	}
+	if !health[0].Stale {
+		t.Errorf("health after 2h = %+v, want stale", health[0])
+	}
+}
+
+func TestListSourceHealth_SyncFailure(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(&failingSyncSource{})
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if _, err := svc.SyncAlerts(ctx, "fake", now, time.Minute, time.Hour); err == nil {
+		t.Fatal("SyncAlerts: expected error")
+	}
+	health, err := svc.ListSourceHealth(ctx, now)
+	if err != nil {
+		t.Fatalf("ListSourceHealth: %v", err)
+	}
+	if len(health) != 1 || health[0].LastSuccessAt != nil || health[0].LastFailureAt == nil {
+		t.Fatalf("ListSourceHealth = %+v, want only a failure recorded", health)
+	}
+	if health[0].Stale || health[0].MaxSilenceSeconds != 0 {
+		t.Errorf("health without a policy = %+v, want no staleness check", health[0])
+	}
+}
+
+// failingSyncSource fails every fetch of recent alerts
+type failingSyncSource struct {
+	fakeWebhookSource
+}
+
+func (failingSyncSource) FetchRecentAlerts(context.Context, time.Time) ([]*notification.Alert, error) {
+	return nil, errors.New("401 unauthorized")
+}
+
+func TestCheckSourceHealth(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	notifier := &recordingStaleNotifier{}
+	svc.RegisterSourceStaleNotifier(notifier)
+	if err := svc.SetStalenessPolicy(domain.StalenessPolicy{Sources: map[string]time.Duration{"mailgw": time.Hour}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A source that has never ingested anything is measured from startup
+	if n, err := svc.CheckSourceHealth(ctx, svc.startedAt.Add(30*time.Minute)); err != nil || n != 0 {
+		t.Fatalf("CheckSourceHealth before threshold = %d, %v; want 0", n, err)
+	}
+	stale := svc.startedAt.Add(2 * time.Hour)
+	if n, err := svc.CheckSourceHealth(ctx, stale); err != nil || n != 1 {
+		t.Fatalf("CheckSourceHealth = %d, %v; want 1", n, err)
+	}
+	if n, _ := svc.CheckSourceHealth(ctx, stale.Add(time.Minute)); n != 0 {
+		t.Errorf("repeat CheckSourceHealth = %d, want 0 (already alarmed)", n)
+	}
+	if len(notifier.got) != 1 || notifier.got[0].Source != "mailgw" || !notifier.got[0].Stale {
+		t.Fatalf("alarms = %+v, want one for mailgw", notifier.got)
+	}
+
+	// Recovering and going quiet again raises a new alarm
+	recovered := stale.Add(5 * time.Minute)
+	svc.recordIngestion(ctx, "mailgw", recovered, nil)
+	if n, _ := svc.CheckSourceHealth(ctx, recovered.Add(time.Minute)); n != 0 {
+		t.Errorf("CheckSourceHealth after recovery = %d, want 0", n)
+	}
+	if n, _ := svc.CheckSourceHealth(ctx, recovered.Add(2*time.Hour)); n != 1 {
+		t.Errorf("CheckSourceHealth after going quiet again = %d, want 1", n)
+	}
+	if len(notifier.got) != 2 {
+		t.Errorf("alarms = %d, want 2", len(notifier.got))
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// SetOutageTransitions replaces the outage state machine. Every action must
+// be named and unique, and every status must be a known outage status.
+func (s *Service) SetOutageTransitions(transitions []domain.OutageTransition) error {
+	seen := make(map[string]bool, len(transitions))
+	for _, t := range transitions {
+		if t.Action == "" {
+			return fmt.Errorf("%w: transition action is required", domain.ErrInvalidInput)
+		}
+		if seen[t.Action] {
+			return fmt.Errorf("%w: duplicate transition action %q", domain.ErrInvalidInput, t.Action)
+		}
+		seen[t.Action] = true
+		if !slices.Contains(domain.OutageStatuses, t.To) {
+			return fmt.Errorf("%w: transition %q has unknown target status %q", domain.ErrInvalidInput, t.Action, t.To)
+		}
+		if len(t.From) == 0 {
+			return fmt.Errorf("%w: transition %q has no source statuses", domain.ErrInvalidInput, t.Action)
+		}
+		for _, from := range t.From {
+			if !slices.Contains(domain.OutageStatuses, from) {
+				return fmt.Errorf("%w: transition %q has unknown source status %q", domain.ErrInvalidInput, t.Action, from)
+			}
+		}
+	}
+	s.transitions = transitions
+	return nil
+}
+
+// TransitionOutage moves an outage through the named action of the state
+// machine, recording the actor and reason in its status history.
+func (s *Service) TransitionOutage(ctx context.Context, id uuid.UUID, req domain.TransitionRequest) (*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.TransitionOutage")
+	defer span.End()
+
+	if req.Action == "" {
+		return nil, fmt.Errorf("%w: action is required", domain.ErrInvalidInput)
+	}
+	t, ok := s.transitionByAction(req.Action)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown action %q", domain.ErrInvalidInput, req.Action)
+	}
+	to := t.To
+	return s.updateOutage(ctx, id, domain.UpdateOutageRequest{Status: &to}, req)
+}
+
+// transitionByAction looks up a transition by its action name
+func (s *Service) transitionByAction(action string) (domain.OutageTransition, bool) {
+	for _, t := range s.transitions {
+		if t.Action == action {
+			return t, true
+		}
+	}
+	return domain.OutageTransition{}, false
+}
+
+// findTransition returns the transition that moves an outage from one
+// status to another. When action is empty only non-explicit transitions are
+// considered.
+func (s *Service) findTransition(from, to, action string) (domain.OutageTransition, error) {
+	if action != "" {
+		t, ok := s.transitionByAction(action)
+		if !ok {
+			return t, fmt.Errorf("%w: unknown action %q", domain.ErrInvalidInput, action)
+		}
+		if t.To != to || !slices.Contains(t.From, from) {
+			return t, fmt.Errorf("%w: action %q is not allowed from status %q", domain.ErrInvalidInput, action, from)
+		}
+		return t, nil
+	}
+	for _, t := range s.transitions {
+		if !t.Explicit && t.To == to && slices.Contains(t.From, from) {
+			return t, nil
+		}
+	}
+	return domain.OutageTransition{}, fmt.Errorf("%w: cannot change status from %q to %q", domain.ErrInvalidInput, from, to)
+}
+
+// applyStatus sets an outage's status and the matching lifecycle
+// timestamps. Investigating and mitigated times record the first time the
+// outage reached that status.
+func applyStatus(outage *domain.Outage, status string, now time.Time) {
+	switch {
+	case status == domain.StatusInvestigating && outage.InvestigatingAt == nil:
+		outage.InvestigatingAt = &now
+	case status == domain.StatusMitigated && outage.MitigatedAt == nil:
+		outage.MitigatedAt = &now
+	}
+	if isResolved(status) && !isResolved(outage.Status) {
+		outage.ResolvedAt = &now
+	} else if !isResolved(status) {
+		outage.ResolvedAt = nil
+	}
+	outage.Status = status
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestSetOutageTransitions_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		transitions []domain.OutageTransition
+		wantErr     bool
+	}{
+		{name: "default", transitions: domain.DefaultOutageTransitions()},
+		{name: "missing action", transitions: []domain.OutageTransition{{From: []string{"open"}, To: "closed"}}, wantErr: true},
+		{name: "duplicate action", transitions: []domain.OutageTransition{
+			{Action: "close", From: []string{"open"}, To: "closed"},
+			{Action: "close", From: []string{"resolved"}, To: "closed"},
+		}, wantErr: true},
+		{name: "unknown target", transitions: []domain.OutageTransition{{Action: "park", From: []string{"open"}, To: "parked"}}, wantErr: true},
+		{name: "unknown source", transitions: []domain.OutageTransition{{Action: "close", From: []string{"parked"}, To: "closed"}}, wantErr: true},
+		{name: "no source", transitions: []domain.OutageTransition{{Action: "close", To: "closed"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newSvc().SetOutageTransitions(tt.transitions)
+			if tt.wantErr && !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("got %v, want ErrInvalidInput", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("SetOutageTransitions: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransitionOutage_Lifecycle(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, err = svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{Action: "investigate", Actor: "alice"})
+	if err != nil {
+		t.Fatalf("investigate: %v", err)
+	}
+	if o.Status != domain.StatusInvestigating || o.InvestigatingAt == nil {
+		t.Fatalf("after investigate: status %q, investigating_at %v", o.Status, o.InvestigatingAt)
+	}
+	investigatingAt := *o.InvestigatingAt
+
+	o, err = svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{Action: "mitigate", Actor: "alice"})
+	if err != nil {
+		t.Fatalf("mitigate: %v", err)
+	}
+	if o.MitigatedAt == nil {
+		t.Fatal("mitigated_at not set")
+	}
+	// Going back to investigating keeps the first investigating time
+	o, err = svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{Action: "investigate"})
+	if err != nil {
+		t.Fatalf("investigate again: %v", err)
+	}
+	if !o.InvestigatingAt.Equal(investigatingAt) {
+		t.Errorf("investigating_at changed from %v to %v", investigatingAt, *o.InvestigatingAt)
+	}
+
+	o, err = svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{Action: "close", Actor: "bob"})
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if o.ResolvedAt == nil {
+		t.Fatal("resolved_at not set on close")
+	}
+
+	// Closed outages only leave through the explicit reopen action
+	open := domain.StatusOpen
+	if _, err := svc.UpdateOutage(ctx, o.ID, domain.UpdateOutageRequest{Status: &open}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("status edit out of closed: got %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{Action: "mitigate"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("mitigate from closed: got %v, want ErrInvalidInput", err)
+	}
+	o, err = svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{Action: "reopen", Actor: "bob", Reason: "recurred"})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if o.Status != domain.StatusOpen || o.ResolvedAt != nil {
+		t.Errorf("after reopen: status %q, resolved_at %v", o.Status, o.ResolvedAt)
+	}
+
+	changes, err := svc.storage.ListStatusChangesByOutage(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := changes[len(changes)-1]
+	if last.Action != "reopen" || last.Actor != "bob" || last.Reason != "recurred" {
+		t.Errorf("last status change = %+v, want reopen by bob", last)
+	}
+}
+
+func TestTransitionOutage_UnknownAction(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{Action: "explode"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("got %v, want ErrInvalidInput", err)
+	}
+	if _, err := svc.TransitionOutage(ctx, o.ID, domain.TransitionRequest{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("empty action: got %v, want ErrInvalidInput", err)
+	}
+}
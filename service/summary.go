@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// errSummariesDisabled is returned for summary requests when no summarizer
+// is configured
+var errSummariesDisabled = fmt.Errorf("outage summaries are not configured: %w", domain.ErrNotFound)
+
+// OutageSummarizer writes an executive summary of an outage from its
+// record, such as with a language model
+type OutageSummarizer interface {
+	Summarize(ctx context.Context, outage *domain.Outage, timeline []domain.TimelineEvent) (string, error)
+}
+
+// SetOutageSummarizer enables generated outage summaries. Without a
+// summarizer SummarizeOutage returns domain.ErrNotFound.
+func (s *Service) SetOutageSummarizer(summarizer OutageSummarizer) {
+	s.summarizer = summarizer
+}
+
+// SummarizeOutage writes an executive summary of an outage from its
+// details, timeline and notes, and adds it to the outage as a markdown note
+// marked with domain.NoteMetadataSummary. Earlier summaries are left out of
+// what the summarizer sees, so asking again summarizes the outage afresh.
+func (s *Service) SummarizeOutage(ctx context.Context, id uuid.UUID, requestedBy string) (*domain.Note, error) {
+	ctx, span := tracer.Start(ctx, "Service.SummarizeOutage")
+	defer span.End()
+
+	if s.summarizer == nil {
+		return nil, errSummariesDisabled
+	}
+	outage, err := s.liveOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeChange(ctx, outage); err != nil {
+		return nil, err
+	}
+	timeline, err := s.GetOutageTimeline(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[uuid.UUID]bool)
+	notes := outage.Notes[:0]
+	for _, n := range outage.Notes {
+		if _, ok := n.Metadata[domain.NoteMetadataSummary]; ok {
+			summaries[n.ID] = true
+			continue
+		}
+		notes = append(notes, n)
+	}
+	outage.Notes = notes
+	events := timeline[:0]
+	for _, e := range timeline {
+		if e.Type != domain.TimelineNoteAdded || !summaries[e.EntityID] {
+			events = append(events, e)
+		}
+	}
+
+	summary, err := s.summarizer.Summarize(ctx, outage, events)
+	if err != nil {
+		return nil, err
+	}
+	return s.AddNote(ctx, id, domain.AddNoteRequest{
+		Content:  summary,
+		Format:   domain.NoteFormatMarkdown,
+		Author:   domain.SummaryAuthor,
+		Metadata: map[string]string{domain.NoteMetadataSummary: requestedBy},
+	})
+}
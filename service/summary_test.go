@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// fakeSummarizer records the notes it is shown and summarizes the outage
+// as its note count
+type fakeSummarizer struct {
+	seen []string
+	err  error
+}
+
+func (f *fakeSummarizer) Summarize(_ context.Context, outage *domain.Outage, timeline []domain.TimelineEvent) (string, error) {
+	f.seen = nil
+	for _, e := range timeline {
+		if e.Type == domain.TimelineNoteAdded {
+			f.seen = append(f.seen, e.Details["content"].(string))
+		}
+	}
+	if f.err != nil {
+		return "", f.err
+	}
+	return fmt.Sprintf("%d notes", len(outage.Notes)), nil
+}
+
+func TestSummarizeOutage(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Checkout down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SummarizeOutage(ctx, outage.ID, "alice@example.com"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("SummarizeOutage without a summarizer error = %v, want ErrNotFound", err)
+	}
+
+	summarizer := &fakeSummarizer{}
+	svc.SetOutageSummarizer(summarizer)
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "failed over the database", Author: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.SummarizeOutage(ctx, outage.ID, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Author != domain.SummaryAuthor || note.Format != domain.NoteFormatMarkdown ||
+		note.Metadata[domain.NoteMetadataSummary] != "alice@example.com" || note.Content != "1 notes" {
+		t.Errorf("summary note = %+v", note)
+	}
+
+	// A second summary does not see the first
+	if _, err := svc.SummarizeOutage(ctx, outage.ID, "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(summarizer.seen) != 1 || summarizer.seen[0] != "failed over the database" {
+		t.Errorf("summarizer saw notes %q, want only the responder's note", summarizer.seen)
+	}
+
+	summarizer.err = errors.New("model unavailable")
+	if _, err := svc.SummarizeOutage(ctx, outage.ID, ""); err == nil {
+		t.Error("SummarizeOutage with a failing summarizer succeeded")
+	}
+	if _, err := svc.SummarizeOutage(ctx, uuid.New(), ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SummarizeOutage of a missing outage error = %v, want ErrNotFound", err)
+	}
+}
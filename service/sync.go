@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// NotificationSources returns the names of the registered notification
+// services in sorted order.
+func (s *Service) NotificationSources() []string {
+	names := make([]string, 0, len(s.notificationServices))
+	for name := range s.notificationServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SyncAlerts pulls recent alerts from the named notification service and
+// ingests them, opening outages for new alerts and filling in acknowledged
+// and resolved times on existing ones. The fetch window starts lookback
+// before the source's stored cursor so alerts that changed state since the
+// last pass are picked up again; a source with no cursor is fetched from
+// initialLookback before now. The cursor only advances to now when every
+// fetched alert was stored, so failed alerts are retried on the next pass.
+func (s *Service) SyncAlerts(ctx context.Context, source string, now time.Time, lookback, initialLookback time.Duration) (*domain.SyncResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.SyncAlerts")
+	defer span.End()
+
+	svc, ok := s.notificationServices[source]
+	if !ok {
+		return nil, fmt.Errorf("notification service %s: %w", source, domain.ErrNotFound)
+	}
+
+	since := now.Add(-initialLookback)
+	cursor, err := s.storage.GetSyncCursor(ctx, source)
+	switch {
+	case err == nil:
+		since = cursor.SyncedUntil.Add(-lookback)
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, err
+	}
+
+	alerts, err := svc.FetchRecentAlerts(ctx, since)
+	if err != nil {
+		err = fmt.Errorf("failed to fetch recent %s alerts: %w", source, err)
+		s.recordIngestion(ctx, source, now, err)
+		return nil, err
+	}
+
+	result := &domain.SyncResult{Source: source, Since: since, Fetched: len(alerts)}
+	for _, notifAlert := range alerts {
+		alert, outcome, err := s.ingestAlert(ctx, notifAlert)
+		if err != nil {
+			result.Failed++
+			s.logger.WarnContext(ctx, "failed to sync alert",
+				"source", source, "external_id", notifAlert.ExternalID, "error", err)
+			continue
+		}
+		switch outcome {
+		case ingestCreated:
+			result.Created++
+		case ingestUpdated:
+			result.Updated++
+		case ingestSuppressed:
+			result.Suppressed++
+			continue
+		default:
+			continue
+		}
+		s.syncAlertEventsBestEffort(ctx, alert)
+	}
+
+	if result.Failed > 0 {
+		s.recordIngestion(ctx, source, now, fmt.Errorf("%d of %d fetched alerts could not be stored", result.Failed, result.Fetched))
+		if cursor != nil {
+			result.SyncedUntil = cursor.SyncedUntil
+		}
+		return result, nil
+	}
+
+	if err := s.storage.UpsertSyncCursor(ctx, &domain.SyncCursor{Source: source, SyncedUntil: now, UpdatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+	s.recordIngestion(ctx, source, now, nil)
+	result.SyncedUntil = now
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// fakeSyncSource returns a fixed set of recent alerts and records the
+// window it was asked for and how often its alert log was fetched.
+type fakeSyncSource struct {
+	fakeWebhookSource
+	alerts     []*notification.Alert
+	since      time.Time
+	logFetches int
+}
+
+func (f *fakeSyncSource) FetchRecentAlerts(_ context.Context, since time.Time) ([]*notification.Alert, error) {
+	f.since = since
+	return f.alerts, nil
+}
+
+func (f *fakeSyncSource) FetchLogEntries(context.Context, string) ([]*notification.LogEntry, error) {
+	f.logFetches++
+	return nil, nil
+}
+
+func TestSyncAlerts(t *testing.T) {
+	svc := newSvc()
+	now := time.Now().UTC().Truncate(time.Second)
+	triggered := now.Add(-10 * time.Minute)
+	source := &fakeSyncSource{alerts: []*notification.Alert{
+		{ExternalID: "A1", Source: "fake", Title: "disk full", TriggeredAt: triggered},
+		{ExternalID: "A2", Source: "fake", Title: "cpu hot", TriggeredAt: triggered},
+	}}
+	svc.RegisterNotificationService(source)
+	ctx := context.Background()
+
+	if got := svc.NotificationSources(); len(got) != 1 || got[0] != "fake" {
+		t.Fatalf("NotificationSources() = %v, want [fake]", got)
+	}
+
+	// First pass: no cursor, so the window starts initialLookback before now.
+	result, err := svc.SyncAlerts(ctx, "fake", now, 5*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("SyncAlerts: %v", err)
+	}
+	if !source.since.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("first pass since = %v, want %v", source.since, now.Add(-24*time.Hour))
+	}
+	if result.Fetched != 2 || result.Created != 2 || result.Updated != 0 || result.Failed != 0 {
+		t.Errorf("first pass result = %+v, want 2 fetched and created", result)
+	}
+	if !result.SyncedUntil.Equal(now) {
+		t.Errorf("SyncedUntil = %v, want %v", result.SyncedUntil, now)
+	}
+	if source.logFetches != 2 {
+		t.Errorf("log fetches after first pass = %d, want 2", source.logFetches)
+	}
+
+	// Second pass resumes from the cursor and only updates the acknowledged alert.
+	acked := now.Add(time.Minute)
+	source.alerts[0].AcknowledgedAt = &acked
+	later := now.Add(2 * time.Minute)
+	result, err = svc.SyncAlerts(ctx, "fake", later, 5*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("SyncAlerts: %v", err)
+	}
+	if !source.since.Equal(now.Add(-5 * time.Minute)) {
+		t.Errorf("second pass since = %v, want %v", source.since, now.Add(-5*time.Minute))
+	}
+	if result.Created != 0 || result.Updated != 1 {
+		t.Errorf("second pass result = %+v, want 0 created and 1 updated", result)
+	}
+	if source.logFetches != 3 {
+		t.Errorf("log fetches after second pass = %d, want 3 (unchanged alerts are skipped)", source.logFetches)
+	}
+
+	outages, err := svc.ListOutages(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 2 {
+		t.Errorf("expected 2 outages, got %d", len(outages))
+	}
+	alert, err := svc.storage.GetAlertByExternalID(ctx, "A1", "fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.AcknowledgedAt == nil || !alert.AcknowledgedAt.Equal(acked) {
+		t.Errorf("AcknowledgedAt = %v, want %v", alert.AcknowledgedAt, acked)
+	}
+}
+
+func TestSyncAlerts_UnknownSource(t *testing.T) {
+	if _, err := newSvc().SyncAlerts(context.Background(), "missing", time.Now(), time.Minute, time.Hour); err == nil {
+		t.Fatal("expected error for unregistered source")
+	}
+}
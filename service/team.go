@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/notification"
+	"github.com/google/uuid"
+)
+
+// ListTeams returns every team, local and synced, sorted by name
+func (s *Service) ListTeams(ctx context.Context) ([]*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTeams")
+	defer span.End()
+
+	return s.teams(ctx)
+}
+
+// GetTeam returns the named team
+func (s *Service) GetTeam(ctx context.Context, name string) (*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTeam")
+	defer span.End()
+
+	var team domain.Team
+	found, err := s.configResource(ctx, domain.ResourceTeam, name, &team)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("team %q: %w", name, domain.ErrNotFound)
+	}
+	return &team, nil
+}
+
+// UserTeams returns the names of the teams email is a member of, sorted.
+// Members are matched case-insensitively.
+func (s *Service) UserTeams(ctx context.Context, email string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "Service.UserTeams")
+	defer span.End()
+
+	if email == "" {
+		return nil, nil
+	}
+	teams, err := s.teams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, team := range teams {
+		if isTeamMember(team, email) {
+			names = append(names, team.Name)
+		}
+	}
+	return names, nil
+}
+
+// ListTeamOutages is ListOutages restricted to outages owned by any of
+// teams
+func (s *Service) ListTeamOutages(ctx context.Context, teams []string, limit, offset int) ([]*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTeamOutages")
+	defer span.End()
+
+	return s.listTeamOutages(ctx, teams, limit, offset, false)
+}
+
+// ListTeamOutagesIncludingDeleted is ListTeamOutages with outages in the
+// trash included
+func (s *Service) ListTeamOutagesIncludingDeleted(ctx context.Context, teams []string, limit, offset int) ([]*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTeamOutagesIncludingDeleted")
+	defer span.End()
+
+	return s.listTeamOutages(ctx, teams, limit, offset, true)
+}
+
+func (s *Service) listTeamOutages(ctx context.Context, teams []string, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return s.storage.ListOutagesByTeams(ctx, teams, limit, offset, includeDeleted)
+}
+
+// SyncTeams copies the teams of every registered notification service that
+// can list them into Outalator's teams, and returns the changes it made.
+// Synced teams record their source, and are updated or deleted as the
+// provider's listing changes. Locally managed teams are never overwritten:
+// a provider team with the same name as a local team is skipped. A source
+// whose listing fails is logged and left unchanged.
+func (s *Service) SyncTeams(ctx context.Context) (*domain.OpsConfigPlan, error) {
+	ctx, span := tracer.Start(ctx, "Service.SyncTeams")
+	defer span.End()
+
+	existing, err := s.teams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*domain.Team, len(existing))
+	for _, team := range existing {
+		current[team.Name] = team
+	}
+
+	plan := &domain.OpsConfigPlan{Changes: []domain.ConfigChange{}}
+	now := time.Now()
+	for _, source := range s.teamSources() {
+		fetched, err := s.notificationServices[source].(notification.TeamFetcher).FetchTeams(ctx)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to fetch teams", "source", source, "error", err)
+			continue
+		}
+
+		listed := make(map[string]bool, len(fetched))
+		for _, t := range fetched {
+			if t.Name == "" || listed[t.Name] {
+				continue
+			}
+			old, ok := current[t.Name]
+			if ok && old.Source != source {
+				s.logger.DebugContext(ctx, "team already managed elsewhere; not synced", "team", t.Name, "source", source, "managed_by", old.Source)
+				continue
+			}
+			listed[t.Name] = true
+
+			team := &domain.Team{
+				Name:        t.Name,
+				Description: t.Description,
+				Members:     t.Members,
+				Source:      source,
+				ExternalID:  t.ExternalID,
+			}
+			action := domain.ConfigCreate
+			if ok {
+				// The Slack channel is not known to providers, so an
+				// admin's choice is kept
+				team.SlackChannel = old.SlackChannel
+				action = domain.ConfigUpdate
+			}
+			changed, err := s.putTeam(ctx, team, old, now)
+			if err != nil {
+				return nil, err
+			}
+			current[team.Name] = team
+			if changed {
+				plan.Changes = append(plan.Changes, domain.ConfigChange{Action: action, Kind: domain.ResourceTeam, Name: team.Name})
+			}
+		}
+
+		for _, team := range existing {
+			if team.Source != source || listed[team.Name] {
+				continue
+			}
+			if err := s.storage.DeleteConfigResource(ctx, domain.ResourceTeam, team.Name); err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return nil, err
+			}
+			plan.Changes = append(plan.Changes, domain.ConfigChange{Action: domain.ConfigDelete, Kind: domain.ResourceTeam, Name: team.Name})
+		}
+	}
+
+	plan.Applied = true
+	if len(plan.Changes) > 0 {
+		s.logger.InfoContext(ctx, "teams synced", "changes", len(plan.Changes))
+	}
+	return plan, nil
+}
+
+// putTeam stores team unless it matches old, reporting whether it did
+func (s *Service) putTeam(ctx context.Context, team, old *domain.Team, now time.Time) (bool, error) {
+	spec, err := json.Marshal(team)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode team %s: %w", team.Name, err)
+	}
+	if old != nil {
+		oldSpec, err := json.Marshal(old)
+		if err == nil && sameSpec(oldSpec, spec) {
+			return false, nil
+		}
+	}
+	r := &domain.ConfigResource{Kind: domain.ResourceTeam, Name: team.Name, Spec: spec, UpdatedAt: now}
+	if err := s.storage.UpsertConfigResource(ctx, r); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// teamSources returns the names of the registered notification services
+// that can list their teams, sorted so syncs are deterministic
+func (s *Service) teamSources() []string {
+	var names []string
+	for name, svc := range s.notificationServices {
+		if _, ok := svc.(notification.TeamFetcher); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// teams decodes every stored team. Teams that fail to decode are logged
+// and skipped.
+func (s *Service) teams(ctx context.Context) ([]*domain.Team, error) {
+	resources, err := s.storage.ListConfigResources(ctx, domain.ResourceTeam)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]*domain.Team, 0, len(resources))
+	for _, r := range resources {
+		var team domain.Team
+		if err := json.Unmarshal(r.Spec, &team); err != nil {
+			s.logger.WarnContext(ctx, "invalid team config", "team", r.Name, "error", err)
+			continue
+		}
+		teams = append(teams, &team)
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Name < teams[j].Name })
+	return teams, nil
+}
+
+// checkOwningTeam rejects an owning team that does not exist. An empty
+// name, meaning no owner, is always allowed.
+func (s *Service) checkOwningTeam(ctx context.Context, name string) error {
+	if name == "" {
+		return nil
+	}
+	_, err := s.storage.GetConfigResource(ctx, domain.ResourceTeam, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("unknown owning team %q: %w", name, domain.ErrInvalidInput)
+	}
+	return err
+}
+
+// SetAdmins sets the emails of users who may change any outage, whichever
+// team owns it, and work with the trash. Emails are matched
+// case-insensitively.
+func (s *Service) SetAdmins(emails []string) {
+	s.admins = make(map[string]bool, len(emails))
+	for _, email := range emails {
+		s.admins[strings.ToLower(email)] = true
+	}
+}
+
+// IsAdmin reports whether the caller is an admin: a user in the admins list
+// or granted the admin role by their IdP groups. Calls without a signed-in
+// user come from outalator itself or a server with authentication
+// disabled, and are admins too, except calls let in anonymously by the auth
+// policy.
+func (s *Service) IsAdmin(ctx context.Context) bool {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return !auth.IsAnonymous(ctx)
+	}
+	return s.admins[strings.ToLower(user.Email)] || user.HasRole(auth.RoleAdmin)
+}
+
+// authorizeOutageChange is authorizeChange for the outage with id. A
+// missing outage is left for the change itself to report.
+func (s *Service) authorizeOutageChange(ctx context.Context, id uuid.UUID) error {
+	if s.IsAdmin(ctx) {
+		return nil
+	}
+	outage, err := s.storage.GetOutage(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.authorizeChange(ctx, outage)
+}
+
+// authorizeChange returns domain.ErrForbidden when the caller may not
+// change the outage, or its notes, tags, alerts, attachments, responders,
+// relations, action items and review. Admins may change any outage, and
+// otherwise only members of the owning team, listed in the team or through
+// an IdP group mapped to it, can change an owned outage. Callers let in
+// anonymously may only change outages no team with members owns.
+func (s *Service) authorizeChange(ctx context.Context, outage *domain.Outage) error {
+	if outage.OwningTeam == "" || s.IsAdmin(ctx) {
+		return nil
+	}
+	var email string
+	if user, err := auth.GetUserFromContext(ctx); err == nil {
+		if user.InTeam(outage.OwningTeam) {
+			return nil
+		}
+		email = user.Email
+	}
+	ok, err := s.CanModifyOutage(ctx, outage, email)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("only members of team %s can change outage %s: %w", outage.OwningTeam, outage.ID, domain.ErrForbidden)
+	}
+	return nil
+}
+
+// CanModifyOutage reports whether the user with email may change an
+// outage. Unowned outages, and outages owned by a team that is unknown or
+// has no members, can be changed by anyone; otherwise only members of the
+// owning team can. Admins and IdP team mappings are applied separately, by
+// the checks every change to an outage makes.
+func (s *Service) CanModifyOutage(ctx context.Context, outage *domain.Outage, email string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.CanModifyOutage")
+	defer span.End()
+
+	if outage.OwningTeam == "" {
+		return true, nil
+	}
+	var team domain.Team
+	found, err := s.configResource(ctx, domain.ResourceTeam, outage.OwningTeam, &team)
+	if err != nil {
+		return false, err
+	}
+	if !found || len(team.Members) == 0 {
+		return true, nil
+	}
+	return isTeamMember(&team, email), nil
+}
+
+func isTeamMember(team *domain.Team, email string) bool {
+	for _, member := range team.Members {
+		if strings.EqualFold(member, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/auth"
+	"github.com/conall/outalator/notification"
+)
+
+// fakeTeamSource is a notification service whose teams can be changed
+// between syncs
+type fakeTeamSource struct {
+	fakeWebhookSource
+	teams []*notification.Team
+}
+
+func (f *fakeTeamSource) FetchTeams(context.Context) ([]*notification.Team, error) {
+	return f.teams, nil
+}
+
+func TestSyncTeams(t *testing.T) {
+	svc := newSvc()
+	source := &fakeTeamSource{teams: []*notification.Team{
+		{ExternalID: "P1", Name: "payments", Members: []string{"alice@example.com"}},
+		{ExternalID: "P2", Name: "search"},
+		{ExternalID: "P3", Name: "platform"},
+	}}
+	svc.RegisterNotificationService(source)
+	ctx := context.Background()
+
+	// A local team with a provider team's name is left alone
+	local := domain.OpsConfig{Teams: []domain.Team{{Name: "platform", Members: []string{"carol@example.com"}}}}
+	if _, err := svc.ApplyOpsConfig(ctx, local, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := svc.SyncTeams(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 2 {
+		t.Fatalf("first sync changes = %+v, want payments and search created", plan.Changes)
+	}
+	payments, err := svc.GetTeam(ctx, "payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payments.Source != "fake" || payments.ExternalID != "P1" {
+		t.Errorf("payments = %+v, want synced from fake as P1", payments)
+	}
+	platform, err := svc.GetTeam(ctx, "platform")
+	if err != nil || platform.Source != "" {
+		t.Errorf("platform = %+v, %v, want the local team", platform, err)
+	}
+
+	if plan, err = svc.SyncTeams(ctx); err != nil || len(plan.Changes) != 0 {
+		t.Errorf("unchanged sync: plan %+v, err %v, want no changes", plan, err)
+	}
+
+	// Pruning the local config keeps synced teams
+	if _, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{}, false, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetTeam(ctx, "platform"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("pruned local team: got %v, want ErrNotFound", err)
+	}
+	if _, err := svc.GetTeam(ctx, "payments"); err != nil {
+		t.Errorf("synced team after prune: %v", err)
+	}
+
+	source.teams = source.teams[:1]
+	source.teams[0].Members = append(source.teams[0].Members, "bob@example.com")
+	plan, err = svc.SyncTeams(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.ConfigChange{
+		{Action: domain.ConfigUpdate, Kind: domain.ResourceTeam, Name: "payments"},
+		{Action: domain.ConfigDelete, Kind: domain.ResourceTeam, Name: "search"},
+	}
+	if len(plan.Changes) != len(want) || plan.Changes[0] != want[0] || plan.Changes[1] != want[1] {
+		t.Errorf("second sync changes = %+v, want %+v", plan.Changes, want)
+	}
+
+	teams, err := svc.UserTeams(ctx, "BOB@example.com")
+	if err != nil || len(teams) != 1 || teams[0] != "payments" {
+		t.Errorf("UserTeams = %v, %v, want [payments]", teams, err)
+	}
+}
+
+func TestOutageOwningTeam(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	cfg := domain.OpsConfig{Teams: []domain.Team{
+		{Name: "payments", Members: []string{"alice@example.com"}},
+		{Name: "search"},
+	}}
+	if _, err := svc.ApplyOpsConfig(ctx, cfg, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "x", Severity: "low", OwningTeam: "billing"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unknown owning team: got %v, want ErrInvalidInput", err)
+	}
+	owned, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unowned, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "slow search", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := svc.ListTeamOutages(ctx, []string{"payments"}, 10, 0)
+	if err != nil || len(list) != 1 || list[0].ID != owned.ID {
+		t.Errorf("ListTeamOutages(payments) = %v, %v, want only %s", list, err, owned.ID)
+	}
+
+	for _, tt := range []struct {
+		outage *domain.Outage
+		email  string
+		want   bool
+	}{
+		{owned, "alice@example.com", true},
+		{owned, "mallory@example.com", false},
+		{unowned, "mallory@example.com", true},
+	} {
+		got, err := svc.CanModifyOutage(ctx, tt.outage, tt.email)
+		if err != nil || got != tt.want {
+			t.Errorf("CanModifyOutage(%s, %s) = %v, %v, want %v", tt.outage.Title, tt.email, got, err, tt.want)
+		}
+	}
+
+	search := "search"
+	updated, err := svc.UpdateOutage(ctx, unowned.ID, domain.UpdateOutageRequest{OwningTeam: &search})
+	if err != nil || updated.OwningTeam != "search" {
+		t.Fatalf("UpdateOutage owning team: %+v, %v", updated, err)
+	}
+	// search has no members, so anyone may change its outages
+	if ok, err := svc.CanModifyOutage(ctx, updated, "mallory@example.com"); err != nil || !ok {
+		t.Errorf("CanModifyOutage(memberless team) = %v, %v, want true", ok, err)
+	}
+	unknown := "billing"
+	if _, err := svc.UpdateOutage(ctx, unowned.ID, domain.UpdateOutageRequest{OwningTeam: &unknown}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateOutage unknown team: got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestOutageChangesRequireOwningTeam(t *testing.T) {
+	svc := newSvc()
+	svc.SetAdmins([]string{"Admin@example.com"})
+	ctx := context.Background()
+	teams := domain.OpsConfig{Teams: []domain.Team{{Name: "payments", Members: []string{"alice@example.com"}}}}
+	if _, err := svc.ApplyOpsConfig(ctx, teams, false, false); err != nil {
+		t.Fatal(err)
+	}
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Card errors", Severity: "high", OwningTeam: "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"member", auth.WithUser(ctx, &auth.UserInfo{Email: "Alice@example.com"}), nil},
+		{"IdP team", auth.WithUser(ctx, &auth.UserInfo{Email: "dave@example.com", Teams: []string{"payments"}}), nil},
+		{"admin", auth.WithUser(ctx, &auth.UserInfo{Email: "admin@example.com"}), nil},
+		{"admin role", auth.WithUser(ctx, &auth.UserInfo{Email: "erin@example.com", Roles: []string{auth.RoleAdmin}}), nil},
+		{"internal", ctx, nil},
+		{"non-member", auth.WithUser(ctx, &auth.UserInfo{Email: "bob@example.com"}), domain.ErrForbidden},
+		{"no email", auth.WithUser(ctx, &auth.UserInfo{Sub: "slack:U2"}), domain.ErrForbidden},
+		{"service", auth.WithUser(ctx, &auth.UserInfo{Service: "service:ci:admin@example.com"}), domain.ErrForbidden},
+		{"anonymous", auth.WithAnonymous(ctx, auth.AccessOpen), domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		_, err := svc.AddNote(tt.ctx, outage.ID, domain.AddNoteRequest{Content: "update", Author: tt.name})
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: AddNote() error = %v, want %v", tt.name, err, tt.want)
+		}
+		_, err = svc.AddTag(tt.ctx, outage.ID, "by", tt.name)
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: AddTag() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	bob := auth.WithUser(ctx, &auth.UserInfo{Email: "bob@example.com"})
+	status := domain.StatusResolved
+	if _, err := svc.UpdateOutage(bob, outage.ID, domain.UpdateOutageRequest{Status: &status}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("UpdateOutage() by a non-member error = %v, want ErrForbidden", err)
+	}
+	if err := svc.DeleteOutage(bob, outage.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("DeleteOutage() by a non-member error = %v, want ErrForbidden", err)
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// templatePlaceholder matches a {variable} placeholder in a template's
+// title or description
+var templatePlaceholder = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// ListOutageTemplates returns every outage template sorted by name
+func (s *Service) ListOutageTemplates(ctx context.Context) ([]domain.OutageTemplate, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListOutageTemplates")
+	defer span.End()
+
+	resources, err := s.storage.ListConfigResources(ctx, domain.ResourceTemplate)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]domain.OutageTemplate, 0, len(resources))
+	for _, r := range resources {
+		if templates, err = appendSpec(templates, r); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// GetOutageTemplate returns the named outage template
+func (s *Service) GetOutageTemplate(ctx context.Context, name string) (*domain.OutageTemplate, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetOutageTemplate")
+	defer span.End()
+
+	var tmpl domain.OutageTemplate
+	found, err := s.configResource(ctx, domain.ResourceTemplate, name, &tmpl)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("template %q: %w", name, domain.ErrNotFound)
+	}
+	return &tmpl, nil
+}
+
+// SaveOutageTemplate creates or replaces an outage template. Templates are
+// operational config resources, so they are also managed by ApplyOpsConfig.
+func (s *Service) SaveOutageTemplate(ctx context.Context, tmpl domain.OutageTemplate) (*domain.OutageTemplate, error) {
+	ctx, span := tracer.Start(ctx, "Service.SaveOutageTemplate")
+	defer span.End()
+
+	tmpl.Name = strings.TrimSpace(tmpl.Name)
+	if tmpl.Name == "" {
+		return nil, fmt.Errorf("template name is required: %w", domain.ErrInvalidInput)
+	}
+	if err := validateTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	resources, err := opsConfigResources(domain.OpsConfig{Templates: []domain.OutageTemplate{tmpl}})
+	if err != nil {
+		return nil, err
+	}
+	resource := resources[0]
+	resource.UpdatedAt = time.Now()
+	if err := s.storage.UpsertConfigResource(ctx, resource); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "outage template saved", "template", tmpl.Name)
+	return &tmpl, nil
+}
+
+// DeleteOutageTemplate deletes the named outage template. Outages created
+// from it are unaffected.
+func (s *Service) DeleteOutageTemplate(ctx context.Context, name string) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteOutageTemplate")
+	defer span.End()
+
+	err := s.storage.DeleteConfigResource(ctx, domain.ResourceTemplate, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("template %q: %w", name, domain.ErrNotFound)
+	}
+	return err
+}
+
+// CreateOutageFromTemplate creates an outage from the named template, with
+// req's fields taking precedence over the template's. Unlike CreateOutage
+// with req.Template set, an unknown template is reported as not found.
+func (s *Service) CreateOutageFromTemplate(ctx context.Context, name string, req domain.CreateOutageRequest) (*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateOutageFromTemplate")
+	defer span.End()
+
+	if _, err := s.GetOutageTemplate(ctx, name); err != nil {
+		return nil, err
+	}
+	req.Template = name
+	return s.CreateOutage(ctx, req)
+}
+
+// validateTemplate checks a template's severity and checklist
+func validateTemplate(tmpl domain.OutageTemplate) error {
+	if tmpl.Severity != "" && !validSeverities[tmpl.Severity] {
+		return fmt.Errorf("template %q has invalid severity %q: %w", tmpl.Name, tmpl.Severity, domain.ErrInvalidInput)
+	}
+	for _, step := range tmpl.Checklist {
+		if strings.TrimSpace(step) == "" {
+			return fmt.Errorf("template %q has an empty checklist step: %w", tmpl.Name, domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// applyTemplate fills unset fields of req from the named outage template and
+// adds the template's tags that req does not already set. The template's
+// placeholders are filled from req.Variables, and every one must be given.
+func (s *Service) applyTemplate(ctx context.Context, req *domain.CreateOutageRequest) (*domain.OutageTemplate, error) {
+	var tmpl domain.OutageTemplate
+	found, err := s.configResource(ctx, domain.ResourceTemplate, req.Template, &tmpl)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown outage template %q: %w", req.Template, domain.ErrInvalidInput)
+	}
+
+	var missing []string
+	fill := func(text string) string {
+		return templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+			name := placeholder[1 : len(placeholder)-1]
+			value, ok := req.Variables[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+	}
+	if req.Title == "" {
+		req.Title = fill(tmpl.Title)
+	}
+	if req.Description == "" {
+		req.Description = fill(tmpl.Description)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %q needs variables %s: %w", tmpl.Name, strings.Join(missing, ", "), domain.ErrInvalidInput)
+	}
+	if req.Severity == "" {
+		req.Severity = tmpl.Severity
+	}
+	set := make(map[string]bool, len(req.Tags))
+	for _, t := range req.Tags {
+		set[t.Key] = true
+	}
+	for _, key := range sortedKeys(tmpl.Tags) {
+		if !set[key] {
+			req.Tags = append(req.Tags, domain.TagInput{Key: key, Value: tmpl.Tags[key]})
+		}
+	}
+	return &tmpl, nil
+}
+
+// addTemplateChecklist adds a template's checklist to a new outage as a
+// markdown task list note
+func (s *Service) addTemplateChecklist(ctx context.Context, outageID uuid.UUID, tmpl *domain.OutageTemplate, now time.Time) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Checklist from template %s:\n\n", tmpl.Name)
+	for _, step := range tmpl.Checklist {
+		fmt.Fprintf(&sb, "- [ ] %s\n", strings.TrimSpace(step))
+	}
+	note := &domain.Note{
+		ID:        uuid.New(),
+		OutageID:  outageID,
+		Content:   sb.String(),
+		Format:    domain.NoteFormatMarkdown,
+		Author:    domain.TemplateAuthor,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Metadata:  map[string]string{domain.NoteMetadataTemplateChecklist: tmpl.Name},
+	}
+	if err := s.storage.CreateNote(ctx, note); err != nil {
+		return fmt.Errorf("failed to add template checklist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestSaveOutageTemplate(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	invalid := []domain.OutageTemplate{
+		{Name: " "},
+		{Name: "t", Severity: "urgent"},
+		{Name: "t", Checklist: []string{"Page the DBA", " "}},
+	}
+	for _, tmpl := range invalid {
+		if _, err := svc.SaveOutageTemplate(ctx, tmpl); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("SaveOutageTemplate(%+v) error = %v, want ErrInvalidInput", tmpl, err)
+		}
+	}
+
+	if _, err := svc.SaveOutageTemplate(ctx, domain.OutageTemplate{Name: "db-failover", Severity: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SaveOutageTemplate(ctx, domain.OutageTemplate{Name: "db-failover", Severity: "critical"}); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := svc.ListOutageTemplates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Severity != "critical" {
+		t.Errorf("templates = %+v, want the replaced template", templates)
+	}
+	cfg, err := svc.GetOpsConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Templates) != 1 {
+		t.Errorf("ops config templates = %+v, want the saved template", cfg.Templates)
+	}
+
+	if err := svc.DeleteOutageTemplate(ctx, "db-failover"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetOutageTemplate(ctx, "db-failover"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutageTemplate after delete error = %v, want ErrNotFound", err)
+	}
+	if err := svc.DeleteOutageTemplate(ctx, "db-failover"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("second delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCreateOutageFromTemplate(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	_, err := svc.SaveOutageTemplate(ctx, domain.OutageTemplate{
+		Name:        "db-failover",
+		Title:       "Database failover on {cluster}",
+		Description: "{cluster} failed over in {region}",
+		Severity:    "high",
+		Tags:        map[string]string{"component": "database"},
+		Checklist:   []string{"Confirm the new primary", "Check replication lag"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.CreateOutageFromTemplate(ctx, "missing", domain.CreateOutageRequest{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown template error = %v, want ErrNotFound", err)
+	}
+	_, err = svc.CreateOutageFromTemplate(ctx, "db-failover", domain.CreateOutageRequest{Variables: map[string]string{"cluster": "main"}})
+	if !errors.Is(err, domain.ErrInvalidInput) || !strings.Contains(err.Error(), "region") {
+		t.Errorf("missing variable error = %v, want ErrInvalidInput naming region", err)
+	}
+	if _, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "x", Variables: map[string]string{"a": "b"}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("variables without template error = %v, want ErrInvalidInput", err)
+	}
+
+	outage, err := svc.CreateOutageFromTemplate(ctx, "db-failover", domain.CreateOutageRequest{
+		Severity:  "critical",
+		Variables: map[string]string{"cluster": "main", "region": "eu-west-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outage.Title != "Database failover on main" || outage.Description != "main failed over in eu-west-1" || outage.Severity != "critical" {
+		t.Errorf("outage = %q %q %q, want the filled template with the requested severity", outage.Title, outage.Description, outage.Severity)
+	}
+	if len(outage.Notes) != 1 {
+		t.Fatalf("notes = %+v, want the checklist note", outage.Notes)
+	}
+	note := outage.Notes[0]
+	if note.Metadata[domain.NoteMetadataTemplateChecklist] != "db-failover" || !strings.Contains(note.Content, "- [ ] Check replication lag") {
+		t.Errorf("checklist note = %+v", note)
+	}
+}
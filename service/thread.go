@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// threadRoot returns the top-level note a new note on outageID replies to
+// when it names parentID. Replies to a reply join the reply's thread, so
+// threads stay one level deep.
+func (s *Service) threadRoot(ctx context.Context, outageID uuid.UUID, parentID *uuid.UUID) (*uuid.UUID, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+	parent, err := s.liveNote(ctx, *parentID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("%w: parent note %s not found", domain.ErrInvalidInput, *parentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if parent.OutageID != outageID {
+		return nil, fmt.Errorf("%w: parent note %s belongs to another outage", domain.ErrInvalidInput, parent.ID)
+	}
+	if parent.ParentNoteID != nil {
+		return parent.ParentNoteID, nil
+	}
+	return &parent.ID, nil
+}
+
+// ListNoteThreadsPage returns up to limit of an outage's note threads
+// starting at offset, along with the total number of threads. Threads are
+// ordered by their top-level note, newest first. A reply whose top-level note
+// is in the trash is listed as a thread of its own. A non-positive limit
+// returns every thread from offset on.
+func (s *Service) ListNoteThreadsPage(ctx context.Context, outageID uuid.UUID, limit, offset int) ([]*domain.NoteThread, int, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListNoteThreadsPage")
+	defer span.End()
+
+	if offset < 0 {
+		return nil, 0, fmt.Errorf("%w: offset must not be negative", domain.ErrInvalidInput)
+	}
+	if _, err := s.liveOutage(ctx, outageID); err != nil {
+		return nil, 0, err
+	}
+	notes, err := s.storage.ListNotesByOutage(ctx, outageID, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	threads := noteThreads(notes)
+	total := len(threads)
+	if offset >= total {
+		return []*domain.NoteThread{}, total, nil
+	}
+	threads = threads[offset:]
+	if limit > 0 && limit < len(threads) {
+		threads = threads[:limit]
+	}
+	return threads, total, nil
+}
+
+// noteThreads groups notes, listed newest first, into threads
+func noteThreads(notes []*domain.Note) []*domain.NoteThread {
+	byRoot := make(map[uuid.UUID]*domain.NoteThread)
+	for _, note := range notes {
+		if note.ParentNoteID == nil {
+			byRoot[note.ID] = &domain.NoteThread{Note: note, Replies: []*domain.Note{}}
+		}
+	}
+
+	var threads []*domain.NoteThread
+	for _, note := range notes {
+		if note.ParentNoteID != nil {
+			if thread, ok := byRoot[*note.ParentNoteID]; ok {
+				thread.Replies = append(thread.Replies, note)
+				continue
+			}
+			threads = append(threads, &domain.NoteThread{Note: note, Replies: []*domain.Note{}})
+			continue
+		}
+		threads = append(threads, byRoot[note.ID])
+	}
+	for _, thread := range threads {
+		slices.Reverse(thread.Replies)
+	}
+	return threads
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+func TestAddNoteReply(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "checking the load balancer", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "LB looks fine", Author: "bob", ParentNoteID: &root.ID})
+	if err != nil {
+		t.Fatalf("AddNote(reply) err = %v", err)
+	}
+	if reply.ParentNoteID == nil || *reply.ParentNoteID != root.ID {
+		t.Errorf("reply ParentNoteID = %v, want %s", reply.ParentNoteID, root.ID)
+	}
+
+	// A reply to a reply joins the same thread
+	nested, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "agreed", Author: "carol", ParentNoteID: &reply.ID})
+	if err != nil {
+		t.Fatalf("AddNote(nested reply) err = %v", err)
+	}
+	if nested.ParentNoteID == nil || *nested.ParentNoteID != root.ID {
+		t.Errorf("nested reply ParentNoteID = %v, want %s", nested.ParentNoteID, root.ID)
+	}
+
+	other, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "other", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := uuid.New()
+	for name, parentID := range map[string]*uuid.UUID{"another outage's note": &root.ID, "unknown note": &missing} {
+		if _, err := svc.AddNote(ctx, other.ID, domain.AddNoteRequest{Content: "x", Author: "bob", ParentNoteID: parentID}); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("AddNote(reply to %s) err = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
+
+func TestListNoteThreadsPage(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(content string, parentID *uuid.UUID) *domain.Note {
+		t.Helper()
+		note, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: content, Author: "alice", ParentNoteID: parentID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return note
+	}
+	first := add("first", nil)
+	second := add("second", nil)
+	firstReply := add("first reply", &first.ID)
+	secondReply := add("second reply", &first.ID)
+
+	threads, total, err := svc.ListNoteThreadsPage(ctx, o.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("ListNoteThreadsPage() err = %v", err)
+	}
+	if total != 2 || len(threads) != 2 {
+		t.Fatalf("ListNoteThreadsPage = %d threads of %d, want 2 of 2", len(threads), total)
+	}
+	if threads[0].Note.ID != second.ID || len(threads[0].Replies) != 0 {
+		t.Errorf("threads[0] = %s with %d replies, want the second note with none", threads[0].Note.Content, len(threads[0].Replies))
+	}
+	if threads[1].Note.ID != first.ID || len(threads[1].Replies) != 2 ||
+		threads[1].Replies[0].ID != firstReply.ID || threads[1].Replies[1].ID != secondReply.ID {
+		t.Errorf("threads[1] = %+v, want the first note with its replies oldest first", threads[1])
+	}
+
+	threads, total, err = svc.ListNoteThreadsPage(ctx, o.ID, 1, 1)
+	if err != nil || total != 2 || len(threads) != 1 || threads[0].Note.ID != first.ID {
+		t.Errorf("ListNoteThreadsPage(limit 1, offset 1) = %v, %d, %v; want the first thread", threads, total, err)
+	}
+
+	// A reply to a trashed note is listed on its own
+	if err := svc.DeleteNote(ctx, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, total, err := svc.ListNoteThreadsPage(ctx, o.ID, 0, 0); err != nil || total != 3 {
+		t.Errorf("ListNoteThreadsPage after trashing the root = %d threads, %v; want 3", total, err)
+	}
+}
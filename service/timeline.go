@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// recordStatusChange appends a status transition to the outage's history
+func (s *Service) recordStatusChange(ctx context.Context, outageID uuid.UUID, from, to string, transition domain.TransitionRequest, at time.Time) error {
+	change := &domain.StatusChange{
+		ID:         uuid.New(),
+		OutageID:   outageID,
+		FromStatus: from,
+		ToStatus:   to,
+		Action:     transition.Action,
+		Actor:      transition.Actor,
+		Reason:     transition.Reason,
+		ChangedAt:  at,
+	}
+	if err := s.storage.CreateStatusChange(ctx, change); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
+
+// GetOutageTimeline returns every event recorded against an outage (creation,
+// status changes, alert lifecycle, provider alert events, responder
+// assignments, action items, notes and tags) in chronological order. A
+// parent outage's timeline includes its children's events, marked with the
+// child they happened to.
+func (s *Service) GetOutageTimeline(ctx context.Context, id uuid.UUID) ([]domain.TimelineEvent, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetOutageTimeline")
+	defer span.End()
+
+	outage, err := s.liveOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.outageEvents(ctx, outage)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.storage.ListChildOutages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.LoadOutageAssociations(ctx, children, domain.OutageAssociations{Notes: true, Tags: true}); err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		childEvents, err := s.outageEvents(ctx, child)
+		if err != nil {
+			return nil, err
+		}
+		for i := range childEvents {
+			childEvents[i].ChildOutageID = &child.ID
+			childEvents[i].Summary = fmt.Sprintf("[%s] %s", child.Title, childEvents[i].Summary)
+		}
+		events = append(events, childEvents...)
+	}
+
+	// Stable sort keeps the outage_created event ahead of anything that
+	// happened in the same instant (e.g. tags supplied at creation), and the
+	// parent's events ahead of its children's.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return events, nil
+}
+
+// outageEvents builds the timeline events of a single outage, unsorted.
+// The outage must have its notes and tags loaded.
+func (s *Service) outageEvents(ctx context.Context, outage *domain.Outage) ([]domain.TimelineEvent, error) {
+	id := outage.ID
+	events := []domain.TimelineEvent{{
+		Timestamp: outage.CreatedAt,
+		Type:      domain.TimelineOutageCreated,
+		Summary:   fmt.Sprintf("Outage created: %s", outage.Title),
+		EntityID:  outage.ID,
+		Details:   map[string]any{"severity": outage.Severity},
+	}}
+
+	changes, err := s.storage.ListStatusChangesByOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		// The initial status is already covered by the outage_created event
+		if c.FromStatus == "" {
+			continue
+		}
+		details := map[string]any{"from": c.FromStatus, "to": c.ToStatus}
+		if c.Action != "" {
+			details["action"] = c.Action
+		}
+		if c.Actor != "" {
+			details["actor"] = c.Actor
+		}
+		if c.Reason != "" {
+			details["reason"] = c.Reason
+		}
+		events = append(events, domain.TimelineEvent{
+			Timestamp: c.ChangedAt,
+			Type:      domain.TimelineStatusChanged,
+			Summary:   fmt.Sprintf("Status changed from %s to %s", c.FromStatus, c.ToStatus),
+			EntityID:  c.ID,
+			Details:   details,
+		})
+	}
+
+	alerts, err := s.storage.ListAlertsByOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range alerts {
+		details := map[string]any{"source": a.Source, "external_id": a.ExternalID}
+		events = append(events, domain.TimelineEvent{
+			Timestamp: a.TriggeredAt,
+			Type:      domain.TimelineAlertTriggered,
+			Summary:   fmt.Sprintf("Alert triggered: %s", a.Title),
+			Actor:     a.TeamName,
+			EntityID:  a.ID,
+			Details:   details,
+		})
+		if a.AcknowledgedAt != nil {
+			events = append(events, domain.TimelineEvent{
+				Timestamp: *a.AcknowledgedAt,
+				Type:      domain.TimelineAlertAcknowledged,
+				Summary:   fmt.Sprintf("Alert acknowledged: %s", a.Title),
+				Actor:     a.TeamName,
+				EntityID:  a.ID,
+				Details:   details,
+			})
+		}
+		if a.ResolvedAt != nil {
+			events = append(events, domain.TimelineEvent{
+				Timestamp: *a.ResolvedAt,
+				Type:      domain.TimelineAlertResolved,
+				Summary:   fmt.Sprintf("Alert resolved: %s", a.Title),
+				Actor:     a.TeamName,
+				EntityID:  a.ID,
+				Details:   details,
+			})
+		}
+	}
+
+	alertEvents, err := s.storage.ListAlertEventsByOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range alertEvents {
+		details := map[string]any{"alert_id": e.AlertID.String(), "event_type": e.Type}
+		if e.Target != "" {
+			details["target"] = e.Target
+		}
+		summary := e.Summary
+		if summary == "" {
+			summary = fmt.Sprintf("Alert %s", e.Type)
+		}
+		events = append(events, domain.TimelineEvent{
+			Timestamp: e.OccurredAt,
+			Type:      domain.TimelineAlertEvent,
+			Summary:   summary,
+			Actor:     e.Actor,
+			EntityID:  e.ID,
+			Details:   details,
+		})
+	}
+
+	assignments, err := s.storage.ListResponderAssignments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assignments {
+		details := map[string]any{"role": a.Role, "responder": a.Responder}
+		if a.ScheduleID != "" {
+			details["source"] = a.Source
+			details["schedule_id"] = a.ScheduleID
+		}
+		events = append(events, domain.TimelineEvent{
+			Timestamp: a.AssignedAt,
+			Type:      domain.TimelineResponderAssigned,
+			Summary:   fmt.Sprintf("%s assigned as %s", a.Responder, roleLabel(a.Role)),
+			Actor:     a.AssignedBy,
+			EntityID:  a.ID,
+			Details:   details,
+		})
+		if a.UnassignedAt != nil {
+			events = append(events, domain.TimelineEvent{
+				Timestamp: *a.UnassignedAt,
+				Type:      domain.TimelineResponderUnassigned,
+				Summary:   fmt.Sprintf("%s is no longer %s", a.Responder, roleLabel(a.Role)),
+				Actor:     a.UnassignedBy,
+				EntityID:  a.ID,
+				Details:   details,
+			})
+		}
+	}
+
+	actionItems, err := s.storage.ListActionItemsByOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range actionItems {
+		details := map[string]any{"description": a.Description}
+		if a.Assignee != "" {
+			details["assignee"] = a.Assignee
+		}
+		if a.DueDate != nil {
+			details["due_date"] = a.DueDate.UTC().Format(time.RFC3339)
+		}
+		events = append(events, domain.TimelineEvent{
+			Timestamp: a.CreatedAt,
+			Type:      domain.TimelineActionItemAdded,
+			Summary:   fmt.Sprintf("Action item added: %s", a.Description),
+			Actor:     a.CreatedBy,
+			EntityID:  a.ID,
+			Details:   details,
+		})
+		if a.ClosedAt != nil {
+			events = append(events, domain.TimelineEvent{
+				Timestamp: *a.ClosedAt,
+				Type:      domain.TimelineActionItemClosed,
+				Summary:   fmt.Sprintf("Action item %s: %s", a.Status, a.Description),
+				EntityID:  a.ID,
+				Details:   map[string]any{"description": a.Description, "status": a.Status},
+			})
+		}
+	}
+
+	relations, err := s.relationsOf(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range relations {
+		details := map[string]any{"type": r.Type, "related_outage_id": r.RelatedOutageID.String()}
+		if r.Description != "" {
+			details["description"] = r.Description
+		}
+		events = append(events, domain.TimelineEvent{
+			Timestamp: r.CreatedAt,
+			Type:      domain.TimelineRelationAdded,
+			Summary:   fmt.Sprintf(relationSummaries[r.Type], r.RelatedOutageTitle),
+			Actor:     r.CreatedBy,
+			EntityID:  r.ID,
+			Details:   details,
+		})
+	}
+
+	for _, n := range outage.Notes {
+		events = append(events, domain.TimelineEvent{
+			Timestamp: n.CreatedAt,
+			Type:      domain.TimelineNoteAdded,
+			Summary:   "Note added",
+			Actor:     n.Author,
+			EntityID:  n.ID,
+			Details:   map[string]any{"content": n.Content, "format": n.Format},
+		})
+	}
+
+	for _, t := range outage.Tags {
+		events = append(events, domain.TimelineEvent{
+			Timestamp: t.CreatedAt,
+			Type:      domain.TimelineTagAdded,
+			Summary:   fmt.Sprintf("Tag added: %s=%s", t.Key, t.Value),
+			EntityID:  t.ID,
+			Details:   map[string]any{"key": t.Key, "value": t.Value},
+		})
+	}
+	return events, nil
+}
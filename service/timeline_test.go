@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/internal/logging"
+	"github.com/conall/outalator/internal/testutil"
+	"github.com/google/uuid"
+)
+
+func TestUpdateOutage_RecordsStatusChanges(t *testing.T) {
+	store := testutil.NewMemStorage()
+	svc := New(store, logging.Discard())
+	ctx := context.Background()
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	investigating := "investigating"
+	title := "renamed"
+	for _, req := range []domain.UpdateOutageRequest{
+		{Status: &investigating},
+		{Status: &investigating}, // unchanged status must not be recorded
+		{Title: &title},
+	} {
+		if _, err := svc.UpdateOutage(ctx, o.ID, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, err := store.ListStatusChangesByOutage(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 status changes, got %d", len(changes))
+	}
+	if changes[0].FromStatus != "" || changes[0].ToStatus != "open" {
+		t.Errorf("initial change = %q -> %q, want \"\" -> open", changes[0].FromStatus, changes[0].ToStatus)
+	}
+	if changes[1].FromStatus != "open" || changes[1].ToStatus != investigating {
+		t.Errorf("second change = %q -> %q, want open -> investigating", changes[1].FromStatus, changes[1].ToStatus)
+	}
+}
+
+func TestGetOutageTimeline(t *testing.T) {
+	store := testutil.NewMemStorage()
+	svc := New(store, logging.Discard())
+	ctx := context.Background()
+
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Alerts are backdated relative to the outage so ordering is exercised.
+	triggered := o.CreatedAt.Add(-10 * time.Minute)
+	acked := o.CreatedAt.Add(-5 * time.Minute)
+	if err := store.CreateAlert(ctx, &domain.Alert{
+		ID: uuid.New(), OutageID: o.ID, ExternalID: "PD1", Source: "pagerduty",
+		Title: "db down", TriggeredAt: triggered, AcknowledgedAt: &acked, CreatedAt: o.CreatedAt,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "looking", Author: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	resolved := "resolved"
+	if _, err := svc.UpdateOutage(ctx, o.ID, domain.UpdateOutageRequest{Status: &resolved}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := svc.GetOutageTimeline(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantTypes := []string{
+		domain.TimelineAlertTriggered,
+		domain.TimelineAlertAcknowledged,
+		domain.TimelineOutageCreated,
+		domain.TimelineNoteAdded,
+		domain.TimelineStatusChanged,
+	}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d: %+v", len(wantTypes), len(events), events)
+	}
+	for i, want := range wantTypes {
+		if events[i].Type != want {
+			t.Errorf("events[%d].Type = %q, want %q", i, events[i].Type, want)
+		}
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Timestamp.Before(events[i-1].Timestamp) {
+			t.Errorf("events not chronological at index %d", i)
+		}
+	}
+	if events[3].Actor != "alice" {
+		t.Errorf("note actor = %q, want alice", events[3].Actor)
+	}
+}
+
+func TestGetOutageTimeline_ChildEvents(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+
+	parent, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "eu-west down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "checkout down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetOutageParent(ctx, child.ID, parent.ID); err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.AddNote(ctx, child.ID, domain.AddNoteRequest{Content: "failing over", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := svc.GetOutageTimeline(ctx, parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var childEvents []domain.TimelineEvent
+	for _, e := range events {
+		if e.ChildOutageID != nil {
+			if *e.ChildOutageID != child.ID {
+				t.Errorf("event %s ChildOutageID = %s, want %s", e.Type, e.ChildOutageID, child.ID)
+			}
+			childEvents = append(childEvents, e)
+		}
+	}
+	if len(childEvents) != 2 || childEvents[1].EntityID != note.ID {
+		t.Fatalf("child events = %+v, want the child's creation and note", childEvents)
+	}
+	if want := "[checkout down] Note added"; childEvents[1].Summary != want {
+		t.Errorf("child note summary = %q, want %q", childEvents[1].Summary, want)
+	}
+
+	// The child's own timeline is unmarked
+	events, err = svc.GetOutageTimeline(ctx, child.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range events {
+		if e.ChildOutageID != nil {
+			t.Errorf("child timeline event %s has ChildOutageID %s", e.Type, e.ChildOutageID)
+		}
+	}
+}
+
+func TestGetOutageTimeline_NotFound(t *testing.T) {
+	svc := newSvc()
+	_, err := svc.GetOutageTimeline(context.Background(), uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
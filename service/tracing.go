@@ -0,0 +1,7 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer creates service-layer spans. It uses the global tracer provider,
+// which is a no-op unless tracing is enabled in the server configuration.
+var tracer = otel.Tracer("github.com/conall/outalator/service")
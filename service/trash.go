@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// SetTrashRetention sets how long outages and notes stay in the trash
+// before PurgeTrash deletes them for good. Zero keeps them forever.
+func (s *Service) SetTrashRetention(retention time.Duration) {
+	s.trashRetention = retention
+}
+
+// RestoreOutage takes an outage out of the trash. Restoring an outage that
+// is not in the trash returns domain.ErrNotFound.
+func (s *Service) RestoreOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
+	ctx, span := tracer.Start(ctx, "Service.RestoreOutage")
+	defer span.End()
+
+	if err := s.storage.RestoreOutage(ctx, id); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "outage restored from trash", "outage_id", id)
+	return s.storage.GetOutage(ctx, id)
+}
+
+// RestoreNote takes a note out of the trash. Restoring a note that is not
+// in the trash returns domain.ErrNotFound.
+func (s *Service) RestoreNote(ctx context.Context, noteID uuid.UUID) (*domain.Note, error) {
+	ctx, span := tracer.Start(ctx, "Service.RestoreNote")
+	defer span.End()
+
+	if err := s.storage.RestoreNote(ctx, noteID); err != nil {
+		return nil, err
+	}
+	return s.storage.GetNote(ctx, noteID)
+}
+
+// PurgeTrash permanently deletes outages and notes trashed more than the
+// retention period before now. Without a retention period nothing is
+// purged.
+func (s *Service) PurgeTrash(ctx context.Context, now time.Time) (*domain.PurgeResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.PurgeTrash")
+	defer span.End()
+
+	result := &domain.PurgeResult{}
+	if s.trashRetention <= 0 {
+		return result, nil
+	}
+	cutoff := now.Add(-s.trashRetention)
+
+	// Outages go first so their trashed notes are removed by the cascade
+	// and only notes trashed from live outages are counted separately.
+	var err error
+	if result.Outages, err = s.storage.PurgeOutages(ctx, cutoff); err != nil {
+		return nil, fmt.Errorf("failed to purge outages: %w", err)
+	}
+	if result.Notes, err = s.storage.PurgeNotes(ctx, cutoff); err != nil {
+		return result, fmt.Errorf("failed to purge notes: %w", err)
+	}
+	return result, nil
+}
+
+// liveOutage loads an outage, reporting one in the trash as not found.
+// Storage returns trashed outages so they can be restored; callers acting on
+// a user's request go through this instead.
+func (s *Service) liveOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
+	outage, err := s.storage.GetOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if outage.DeletedAt != nil {
+		return nil, fmt.Errorf("outage %s is in the trash: %w", id, domain.ErrNotFound)
+	}
+	return outage, nil
+}
+
+// liveNote loads a note, reporting one in the trash as not found
+func (s *Service) liveNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
+	note, err := s.storage.GetNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if note.DeletedAt != nil {
+		return nil, fmt.Errorf("note %s is in the trash: %w", id, domain.ErrNotFound)
+	}
+	return note, nil
+}
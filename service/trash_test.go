@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestDeleteAndRestoreOutage(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "trashed", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteOutage(ctx, o.ID); err != nil {
+		t.Fatalf("DeleteOutage() err = %v", err)
+	}
+
+	title := "edited"
+	if _, err := svc.UpdateOutage(ctx, o.ID, domain.UpdateOutageRequest{Title: &title}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateOutage(trashed) err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "n", Format: "plaintext", Author: "alice"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("AddNote(trashed) err = %v, want ErrNotFound", err)
+	}
+	if list, _ := svc.ListOutages(ctx, 10, 0); len(list) != 0 {
+		t.Errorf("ListOutages = %d outages, want trashed outage left out", len(list))
+	}
+	list, err := svc.ListOutagesIncludingDeleted(ctx, 10, 0)
+	if err != nil || len(list) != 1 || list[0].DeletedAt == nil {
+		t.Fatalf("ListOutagesIncludingDeleted = %v, %v; want the trashed outage", list, err)
+	}
+
+	restored, err := svc.RestoreOutage(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("RestoreOutage() err = %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("DeletedAt = %v after restore, want nil", restored.DeletedAt)
+	}
+	if _, err := svc.GetOutage(ctx, o.ID); err != nil {
+		t.Errorf("GetOutage after restore err = %v", err)
+	}
+	if _, err := svc.RestoreOutage(ctx, o.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("RestoreOutage(live) err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeleteAndRestoreNote(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	o, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "outage", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.AddNote(ctx, o.ID, domain.AddNoteRequest{Content: "text", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatalf("DeleteNote() err = %v", err)
+	}
+
+	if _, err := svc.GetNote(ctx, note.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetNote(trashed) err = %v, want ErrNotFound", err)
+	}
+	content := "edited"
+	if _, err := svc.UpdateNote(ctx, note.ID, &content, nil, nil, nil, ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateNote(trashed) err = %v, want ErrNotFound", err)
+	}
+	if _, total, _ := svc.ListNotesByOutagePage(ctx, o.ID, 0, 0, false); total != 0 {
+		t.Errorf("ListNotesByOutagePage total = %d, want 0", total)
+	}
+	if _, total, _ := svc.ListNotesByOutagePage(ctx, o.ID, 0, 0, true); total != 1 {
+		t.Errorf("ListNotesByOutagePage(includeDeleted) total = %d, want 1", total)
+	}
+
+	if _, err := svc.RestoreNote(ctx, note.ID); err != nil {
+		t.Fatalf("RestoreNote() err = %v", err)
+	}
+	if _, err := svc.GetNote(ctx, note.ID); err != nil {
+		t.Errorf("GetNote after restore err = %v", err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	trashed, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "trashed", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "live", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := svc.AddNote(ctx, live.ID, domain.AddNoteRequest{Content: "text", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteOutage(ctx, trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a retention period nothing is purged
+	later := time.Now().Add(48 * time.Hour)
+	result, err := svc.PurgeTrash(ctx, later)
+	if err != nil || result.Outages != 0 || result.Notes != 0 {
+		t.Fatalf("PurgeTrash without retention = %+v, %v; want nothing purged", result, err)
+	}
+
+	svc.SetTrashRetention(24 * time.Hour)
+	if result, err = svc.PurgeTrash(ctx, time.Now()); err != nil || result.Outages != 0 || result.Notes != 0 {
+		t.Fatalf("PurgeTrash within retention = %+v, %v; want nothing purged", result, err)
+	}
+	if result, err = svc.PurgeTrash(ctx, later); err != nil {
+		t.Fatal(err)
+	}
+	if result.Outages != 1 || result.Notes != 1 {
+		t.Errorf("PurgeTrash = %+v, want 1 outage and 1 note", result)
+	}
+	if _, err := svc.RestoreOutage(ctx, trashed.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("RestoreOutage after purge err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.GetOutage(ctx, live.ID); err != nil {
+		t.Errorf("GetOutage(live) after purge err = %v", err)
+	}
+}
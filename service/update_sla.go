@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// updateSLAPageSize is the number of outages read per page when listing
+// update SLAs
+const updateSLAPageSize = 200
+
+// UpdateReminder is an overdue status update and who should be reminded
+// about it: the team routing assigned the outage to, if any
+type UpdateReminder struct {
+	SLA          domain.UpdateSLA
+	Team         string   // The outage's team tag; empty when it has none
+	Emails       []string // Members of the team
+	SlackChannel string   // The team's channel, if it has one
+}
+
+// UpdateReminderNotifier delivers reminders for outages whose status update
+// is overdue
+type UpdateReminderNotifier interface {
+	NotifyUpdateOverdue(ctx context.Context, outage *domain.Outage, reminder UpdateReminder) error
+}
+
+// RegisterUpdateReminderNotifier adds a notifier that is called once for
+// every missed status update deadline
+func (s *Service) RegisterUpdateReminderNotifier(n UpdateReminderNotifier) {
+	s.updateReminderNotifiers = append(s.updateReminderNotifiers, n)
+}
+
+// SetUpdatePolicy installs the per-severity status update intervals. Every
+// key must be a known severity and every interval positive.
+func (s *Service) SetUpdatePolicy(policy domain.UpdatePolicy) error {
+	normalized := make(domain.UpdatePolicy, len(policy))
+	for severity, interval := range policy {
+		severity = strings.ToLower(severity)
+		if !validSeverities[severity] {
+			return fmt.Errorf("update interval for unknown severity %q: %w", severity, domain.ErrInvalidInput)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("update interval for %s must be positive: %w", severity, domain.ErrInvalidInput)
+		}
+		normalized[severity] = interval
+	}
+	s.updatePolicy = normalized
+	return nil
+}
+
+// GetUpdateSLA returns the status update deadline of an outage at now. It
+// returns domain.ErrNotFound if the outage does not exist, is resolved or
+// has a severity without an update interval.
+func (s *Service) GetUpdateSLA(ctx context.Context, outageID uuid.UUID, now time.Time) (*domain.UpdateSLA, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetUpdateSLA")
+	defer span.End()
+
+	outage, err := s.liveOutage(ctx, outageID)
+	if err != nil {
+		return nil, err
+	}
+	sla, err := s.updateSLA(ctx, outage, now)
+	if err != nil {
+		return nil, err
+	}
+	if sla == nil {
+		return nil, fmt.Errorf("no update SLA applies to outage %s: %w", outageID, domain.ErrNotFound)
+	}
+	return sla, nil
+}
+
+// ListUpdateSLAs returns the status update deadlines of every active outage
+// with an update interval at now, soonest due first. With overdueOnly set
+// only missed deadlines are returned.
+func (s *Service) ListUpdateSLAs(ctx context.Context, now time.Time, overdueOnly bool) ([]domain.UpdateSLA, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListUpdateSLAs")
+	defer span.End()
+
+	slas := []domain.UpdateSLA{}
+	if len(s.updatePolicy) == 0 {
+		return slas, nil
+	}
+	for offset := 0; ; offset += updateSLAPageSize {
+		outages, err := s.storage.ListOutages(ctx, updateSLAPageSize, offset, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list outages: %w", err)
+		}
+		for _, outage := range outages {
+			sla, err := s.updateSLA(ctx, outage, now)
+			if err != nil {
+				return nil, err
+			}
+			if sla != nil && (sla.Overdue || !overdueOnly) {
+				slas = append(slas, *sla)
+			}
+		}
+		if len(outages) < updateSLAPageSize {
+			break
+		}
+	}
+
+	sort.Slice(slas, func(i, j int) bool { return slas[i].DueAt.Before(slas[j].DueAt) })
+	return slas, nil
+}
+
+// updateSLA computes the deadline of an outage, or returns nil when it has
+// no update SLA
+func (s *Service) updateSLA(ctx context.Context, outage *domain.Outage, now time.Time) (*domain.UpdateSLA, error) {
+	interval := s.updatePolicy[strings.ToLower(outage.Severity)]
+	if interval <= 0 || isResolved(outage.Status) {
+		return nil, nil
+	}
+
+	last := outage.CreatedAt
+	notes, err := s.storage.ListNotesByOutage(ctx, outage.ID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes for outage %s: %w", outage.ID, err)
+	}
+	for _, n := range notes {
+		if _, auto := n.Metadata[domain.NoteMetadataAutoResolved]; !auto && n.CreatedAt.After(last) {
+			last = n.CreatedAt
+		}
+	}
+	changes, err := s.storage.ListStatusChangesByOutage(ctx, outage.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status changes for outage %s: %w", outage.ID, err)
+	}
+	for _, c := range changes {
+		if c.ChangedAt.After(last) {
+			last = c.ChangedAt
+		}
+	}
+
+	due := last.Add(interval)
+	return &domain.UpdateSLA{
+		OutageID:        outage.ID,
+		Title:           outage.Title,
+		Severity:        outage.Severity,
+		IntervalSeconds: int(interval.Seconds()),
+		LastUpdateAt:    last,
+		DueAt:           due,
+		Overdue:         !now.Before(due),
+	}, nil
+}
+
+// SendUpdateReminders notifies the registered notifiers about every outage
+// whose status update is overdue at now and returns how many outages were
+// reminded about. Each missed deadline is reminded about once; an update
+// sets a new deadline. Delivery is best effort: notifier failures are
+// logged.
+func (s *Service) SendUpdateReminders(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "Service.SendUpdateReminders")
+	defer span.End()
+
+	if len(s.updateReminderNotifiers) == 0 {
+		return 0, nil
+	}
+	overdue, err := s.ListUpdateSLAs(ctx, now, true)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	current := make(map[uuid.UUID]bool, len(overdue))
+	for _, sla := range overdue {
+		current[sla.OutageID] = true
+		if !s.sentUpdateReminders.claim(sla.OutageID, sla.DueAt) {
+			continue
+		}
+		outage, err := s.storage.GetOutage(ctx, sla.OutageID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to load outage for update reminder", "outage_id", sla.OutageID, "error", err)
+			continue
+		}
+
+		reminder := s.routeUpdateReminder(ctx, outage, sla)
+		for _, n := range s.updateReminderNotifiers {
+			if err := n.NotifyUpdateOverdue(ctx, outage, reminder); err != nil {
+				s.logger.WarnContext(ctx, "failed to send update reminder",
+					"outage_id", outage.ID, "team", reminder.Team, "error", err)
+			}
+		}
+		sent++
+	}
+	s.sentUpdateReminders.retain(current)
+	return sent, nil
+}
+
+// routeUpdateReminder addresses a reminder to the outage's owning team or,
+// for unowned outages, the team recorded in its team tag. Outages without a
+// known team get a reminder with no recipients, which notifiers may send to
+// a default destination.
+func (s *Service) routeUpdateReminder(ctx context.Context, outage *domain.Outage, sla domain.UpdateSLA) UpdateReminder {
+	reminder := UpdateReminder{SLA: sla, Team: outage.OwningTeam}
+	for _, tag := range outage.Tags {
+		if tag.Key == teamTagKey && reminder.Team == "" {
+			reminder.Team = tag.Value
+		}
+	}
+	if reminder.Team == "" {
+		return reminder
+	}
+
+	var team domain.Team
+	found, err := s.configResource(ctx, domain.ResourceTeam, reminder.Team, &team)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to load team for update reminder", "outage_id", outage.ID, "team", reminder.Team, "error", err)
+		return reminder
+	}
+	if found {
+		reminder.Emails = team.Members
+		reminder.SlackChannel = team.SlackChannel
+	}
+	return reminder
+}
+
+// reminderLog records the deadline each key (an outage, or an alert source)
+// was last reminded about
+type reminderLog[K comparable] struct {
+	mu   sync.Mutex
+	sent map[K]time.Time
+}
+
+func newReminderLog[K comparable]() *reminderLog[K] {
+	return &reminderLog[K]{sent: make(map[K]time.Time)}
+}
+
+// claim records a reminder for the key's deadline, reporting false if one
+// was already sent for it
+func (l *reminderLog[K]) claim(key K, due time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.sent[key]; ok && last.Equal(due) {
+		return false
+	}
+	l.sent[key] = due
+	return true
+}
+
+// retain forgets keys that are no longer overdue
+func (l *reminderLog[K]) retain(overdue map[K]bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key := range l.sent {
+		if !overdue[key] {
+			delete(l.sent, key)
+		}
+	}
+}
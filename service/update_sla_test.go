@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+type recordingReminderNotifier struct {
+	got []UpdateReminder
+}
+
+func (r *recordingReminderNotifier) NotifyUpdateOverdue(_ context.Context, _ *domain.Outage, reminder UpdateReminder) error {
+	r.got = append(r.got, reminder)
+	return nil
+}
+
+func TestSetUpdatePolicy_Validation(t *testing.T) {
+	svc := newSvc()
+	if err := svc.SetUpdatePolicy(domain.UpdatePolicy{"sev1": time.Minute}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unknown severity: got %v, want ErrInvalidInput", err)
+	}
+	if err := svc.SetUpdatePolicy(domain.UpdatePolicy{"critical": 0}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("zero interval: got %v, want ErrInvalidInput", err)
+	}
+	if err := svc.SetUpdatePolicy(domain.UpdatePolicy{"Critical": time.Minute}); err != nil {
+		t.Errorf("SetUpdatePolicy: %v", err)
+	}
+}
+
+func TestUpdateSLA(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	if err := svc.SetUpdatePolicy(domain.UpdatePolicy{"critical": 30 * time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+
+	critical, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "db down", Description: "d", Severity: "critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	low, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "slow page", Description: "d", Severity: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.GetUpdateSLA(ctx, low.ID, time.Now()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("severity without an interval: got %v, want ErrNotFound", err)
+	}
+
+	sla, err := svc.GetUpdateSLA(ctx, critical.ID, critical.CreatedAt.Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("GetUpdateSLA: %v", err)
+	}
+	if sla.Overdue || !sla.DueAt.Equal(critical.CreatedAt.Add(30*time.Minute)) || sla.IntervalSeconds != 1800 {
+		t.Errorf("SLA after 10m = %+v, want due 30m after creation and not overdue", sla)
+	}
+
+	later := critical.CreatedAt.Add(31 * time.Minute)
+	overdue, err := svc.ListUpdateSLAs(ctx, later, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overdue) != 1 || overdue[0].OutageID != critical.ID || !overdue[0].Overdue {
+		t.Fatalf("overdue SLAs after 31m = %+v, want the critical outage", overdue)
+	}
+
+	// Automatic resolution notes are not status updates
+	svc.addAutoResolveNote(ctx, critical.ID, "alert", "Alert resolved automatically.")
+	sla, err = svc.GetUpdateSLA(ctx, critical.ID, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sla.LastUpdateAt.Equal(critical.CreatedAt) {
+		t.Errorf("LastUpdateAt = %v after an automatic note, want creation time", sla.LastUpdateAt)
+	}
+
+	note, err := svc.AddNote(ctx, critical.ID, domain.AddNoteRequest{Content: "Failing over", Format: "plaintext", Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sla, err = svc.GetUpdateSLA(ctx, critical.ID, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sla.LastUpdateAt.Equal(note.CreatedAt) || !sla.DueAt.Equal(note.CreatedAt.Add(30*time.Minute)) {
+		t.Errorf("SLA after a note = %+v, want due 30m after the note", sla)
+	}
+
+	resolved := "resolved"
+	if _, err := svc.UpdateOutage(ctx, critical.ID, domain.UpdateOutageRequest{Status: &resolved}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetUpdateSLA(ctx, critical.ID, later); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("resolved outage: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestSendUpdateReminders(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	notifier := &recordingReminderNotifier{}
+	svc.RegisterUpdateReminderNotifier(notifier)
+	if err := svc.SetUpdatePolicy(domain.UpdatePolicy{"critical": 30 * time.Minute, "high": time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := svc.ApplyOpsConfig(ctx, domain.OpsConfig{Teams: []domain.Team{
+		{Name: "payments", SlackChannel: "#payments", Members: []string{"alice@example.com"}},
+	}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routed, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{
+		Title: "card declines", Description: "d", Severity: "critical",
+		Tags: []domain.TagInput{{Key: "team", Value: "payments"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unrouted, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "search errors", Description: "d", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent, err := svc.SendUpdateReminders(ctx, routed.CreatedAt.Add(45*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || len(notifier.got) != 1 {
+		t.Fatalf("sent %d reminders (%d notified) after 45m, want 1", sent, len(notifier.got))
+	}
+	got := notifier.got[0]
+	if got.SLA.OutageID != routed.ID || got.Team != "payments" || got.SlackChannel != "#payments" || len(got.Emails) != 1 {
+		t.Errorf("reminder = %+v, want the payments team", got)
+	}
+
+	// A missed deadline is reminded about once
+	if sent, _ := svc.SendUpdateReminders(ctx, routed.CreatedAt.Add(50*time.Minute)); sent != 0 {
+		t.Errorf("repeat check sent %d reminders, want 0", sent)
+	}
+
+	sent, err = svc.SendUpdateReminders(ctx, unrouted.CreatedAt.Add(61*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || len(notifier.got) != 2 {
+		t.Fatalf("sent %d reminders after 61m, want 1 for the unrouted outage", sent)
+	}
+	if got := notifier.got[1]; got.SLA.OutageID != unrouted.ID || got.Team != "" || got.SlackChannel != "" {
+		t.Errorf("reminder = %+v, want no team", got)
+	}
+}
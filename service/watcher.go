@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// WatchEvent is a change to a watched outage: a status change or a new note
+type WatchEvent struct {
+	Outage         *domain.Outage
+	PreviousStatus string       // Set for status changes
+	Note           *domain.Note // Set for new notes
+}
+
+// WatchNotifier notifies the watchers of an outage who chose its channel
+type WatchNotifier interface {
+	NotifyWatchers(ctx context.Context, event WatchEvent, emails []string) error
+}
+
+// RegisterWatchNotifier sets the notifier for watchers using channel, one of
+// domain.WatchChannelSlack and domain.WatchChannelEmail. Users can only
+// watch outages through channels with a notifier.
+func (s *Service) RegisterWatchNotifier(channel string, n WatchNotifier) {
+	s.watchNotifiers[channel] = n
+}
+
+// WatchOutage subscribes the user with the given email to an outage's
+// status changes and new notes. Watching an outage again changes the
+// channel. The channel defaults to email.
+func (s *Service) WatchOutage(ctx context.Context, id uuid.UUID, email string, req domain.WatchOutageRequest) (*domain.Watcher, error) {
+	ctx, span := tracer.Start(ctx, "Service.WatchOutage")
+	defer span.End()
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, fmt.Errorf("an email address is required to watch an outage: %w", domain.ErrInvalidInput)
+	}
+	if req.Channel == "" {
+		req.Channel = domain.WatchChannelEmail
+	}
+	if _, ok := s.watchNotifiers[req.Channel]; !ok {
+		return nil, fmt.Errorf("%s notifications are not configured: %w", req.Channel, domain.ErrInvalidInput)
+	}
+	if _, err := s.liveOutage(ctx, id); err != nil {
+		return nil, err
+	}
+
+	watcher := &domain.Watcher{OutageID: id, Email: email, Channel: req.Channel, CreatedAt: time.Now()}
+	if err := s.storage.SaveWatcher(ctx, watcher); err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// UnwatchOutage unsubscribes a user from an outage. It returns
+// domain.ErrNotFound when they are not watching it.
+func (s *Service) UnwatchOutage(ctx context.Context, id uuid.UUID, email string) error {
+	ctx, span := tracer.Start(ctx, "Service.UnwatchOutage")
+	defer span.End()
+
+	return s.storage.RemoveWatcher(ctx, id, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// ListWatchers returns the users watching an outage, oldest first
+func (s *Service) ListWatchers(ctx context.Context, id uuid.UUID) ([]*domain.Watcher, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListWatchers")
+	defer span.End()
+
+	if _, err := s.storage.GetOutage(ctx, id); err != nil {
+		return nil, err
+	}
+	watchers, err := s.storage.ListWatchers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if watchers == nil {
+		watchers = []*domain.Watcher{}
+	}
+	return watchers, nil
+}
+
+// notifyWatchers sends event to the outage's watchers, grouped by channel.
+// The user who made the change is not told about it. Like the other
+// notifications this is best effort: failures are logged and never fail
+// the change.
+func (s *Service) notifyWatchers(ctx context.Context, event WatchEvent, actor string) {
+	if len(s.watchNotifiers) == 0 {
+		return
+	}
+	watchers, err := s.storage.ListWatchers(ctx, event.Outage.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to list outage watchers", "outage_id", event.Outage.ID, "error", err)
+		return
+	}
+
+	byChannel := make(map[string][]string)
+	var channels []string
+	for _, w := range watchers {
+		if strings.EqualFold(w.Email, actor) {
+			continue
+		}
+		if _, ok := byChannel[w.Channel]; !ok {
+			channels = append(channels, w.Channel)
+		}
+		byChannel[w.Channel] = append(byChannel[w.Channel], w.Email)
+	}
+	for _, channel := range channels {
+		n, ok := s.watchNotifiers[channel]
+		if !ok {
+			continue // The channel has been unconfigured since they watched
+		}
+		if err := n.NotifyWatchers(ctx, event, byChannel[channel]); err != nil {
+			s.logger.WarnContext(ctx, "failed to notify outage watchers",
+				"outage_id", event.Outage.ID, "channel", channel, "error", err)
+		}
+	}
+}
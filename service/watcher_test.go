@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// recordingWatchNotifier records the events it is sent and who to
+type recordingWatchNotifier struct {
+	events []WatchEvent
+	emails [][]string
+}
+
+func (r *recordingWatchNotifier) NotifyWatchers(_ context.Context, event WatchEvent, emails []string) error {
+	r.events = append(r.events, event)
+	r.emails = append(r.emails, emails)
+	return nil
+}
+
+func TestWatchOutage(t *testing.T) {
+	svc := newSvc()
+	ctx := context.Background()
+	outage, err := svc.CreateOutage(ctx, domain.CreateOutageRequest{Title: "Checkout down", Severity: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.WatchOutage(ctx, outage.ID, "alice@example.com", domain.WatchOutageRequest{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("WatchOutage without notifiers error = %v, want ErrInvalidInput", err)
+	}
+
+	email, slack := &recordingWatchNotifier{}, &recordingWatchNotifier{}
+	svc.RegisterWatchNotifier(domain.WatchChannelEmail, email)
+	svc.RegisterWatchNotifier(domain.WatchChannelSlack, slack)
+
+	watcher, err := svc.WatchOutage(ctx, outage.ID, " Alice@Example.com", domain.WatchOutageRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watcher.Email != "alice@example.com" || watcher.Channel != domain.WatchChannelEmail {
+		t.Errorf("watcher = %+v, want alice by email", watcher)
+	}
+	if _, err := svc.WatchOutage(ctx, outage.ID, "bob@example.com", domain.WatchOutageRequest{Channel: domain.WatchChannelSlack}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name  string
+		id    uuid.UUID
+		email string
+		req   domain.WatchOutageRequest
+		want  error
+	}{
+		{"no email", outage.ID, "", domain.WatchOutageRequest{}, domain.ErrInvalidInput},
+		{"unknown channel", outage.ID, "carol@example.com", domain.WatchOutageRequest{Channel: "pager"}, domain.ErrInvalidInput},
+		{"missing outage", uuid.New(), "carol@example.com", domain.WatchOutageRequest{}, domain.ErrNotFound},
+	} {
+		if _, err := svc.WatchOutage(ctx, tt.id, tt.email, tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("WatchOutage %s error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	watchers, err := svc.ListWatchers(ctx, outage.ID)
+	if err != nil || len(watchers) != 2 {
+		t.Fatalf("ListWatchers = %v, %v; want 2 watchers", watchers, err)
+	}
+
+	// Watchers hear about notes and status changes, except their own
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "failing over", Author: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(email.events) != 0 || len(slack.events) != 1 || slack.events[0].Note == nil || slack.emails[0][0] != "bob@example.com" {
+		t.Fatalf("after alice's note: email %v, slack %v", email.emails, slack.emails)
+	}
+	status := domain.StatusResolved
+	if _, err := svc.UpdateOutage(ctx, outage.ID, domain.UpdateOutageRequest{Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+	if len(email.events) != 1 || email.events[0].PreviousStatus != domain.StatusOpen || email.events[0].Outage.Status != status ||
+		email.emails[0][0] != "alice@example.com" {
+		t.Errorf("email status change events = %+v to %v", email.events, email.emails)
+	}
+	if len(slack.events) != 2 || slack.events[1].Note != nil {
+		t.Errorf("slack events = %+v, want the note and the status change", slack.events)
+	}
+
+	if err := svc.UnwatchOutage(ctx, outage.ID, "ALICE@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UnwatchOutage(ctx, outage.ID, "alice@example.com"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UnwatchOutage twice error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.AddNote(ctx, outage.ID, domain.AddNoteRequest{Content: "all clear", Author: "carol@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(email.events) != 1 || len(slack.events) != 3 {
+		t.Errorf("after unwatching: %d email and %d slack events, want 1 and 3", len(email.events), len(slack.events))
+	}
+	if _, err := svc.ListWatchers(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("ListWatchers of a missing outage error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/notification"
+)
+
+// SupportsWebhooks reports whether the named notification service is
+// registered and can parse inbound webhook deliveries.
+func (s *Service) SupportsWebhooks(source string) bool {
+	svc, ok := s.notificationServices[source]
+	if !ok {
+		return false
+	}
+	_, ok = svc.(notification.WebhookParser)
+	return ok
+}
+
+// VerifyWebhook reports whether a delivery to the named notification
+// service's webhook was sent by its provider
+func (s *Service) VerifyWebhook(source string, header http.Header, payload []byte) bool {
+	parser, ok := s.notificationServices[source].(notification.WebhookParser)
+	return ok && parser.VerifyWebhook(header, payload)
+}
+
+// ProcessWebhook parses a webhook delivery from the named notification
+// service and ingests every alert it describes, recording the outcome for
+// the source's health.
+func (s *Service) ProcessWebhook(ctx context.Context, source string, payload []byte, receivedAt time.Time) error {
+	ctx, span := tracer.Start(ctx, "Service.ProcessWebhook")
+	defer span.End()
+
+	svc, ok := s.notificationServices[source]
+	if !ok {
+		return fmt.Errorf("notification service %s: %w", source, domain.ErrNotFound)
+	}
+	parser, ok := svc.(notification.WebhookParser)
+	if !ok {
+		return fmt.Errorf("notification service %s does not support webhooks: %w", source, domain.ErrInvalidInput)
+	}
+
+	err := s.ingestWebhook(ctx, parser, payload, receivedAt)
+	s.recordIngestion(ctx, source, receivedAt, err)
+	return err
+}
+
+// ingestWebhook parses a webhook delivery and ingests its alerts
+func (s *Service) ingestWebhook(ctx context.Context, parser notification.WebhookParser, payload []byte, receivedAt time.Time) error {
+	alerts, err := parser.ParseWebhook(payload, receivedAt)
+	if err != nil {
+		return err
+	}
+	for _, a := range alerts {
+		if _, err := s.IngestAlert(ctx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ingestOutcome reports what ingesting an alert changed in storage.
+type ingestOutcome int
+
+const (
+	ingestUnchanged ingestOutcome = iota
+	ingestCreated
+	ingestUpdated
+	ingestSuppressed
+)
+
+// IngestAlert records an alert pushed by a notification service. An alert
+// seen for the first time opens a new outage; later deliveries for the same
+// external ID only fill in acknowledgement and resolution times. Under an
+// alert resolution policy, resolving an outage's last open alert resolves
+// the outage. Every delivery re-syncs the alert's provider log (notifications, escalations,
+// reassignments) when the source exposes one. An alert a routing rule
+// suppresses is not stored, and a nil alert is returned.
+func (s *Service) IngestAlert(ctx context.Context, notifAlert *notification.Alert) (*domain.Alert, error) {
+	ctx, span := tracer.Start(ctx, "Service.IngestAlert")
+	defer span.End()
+
+	alert, outcome, err := s.ingestAlert(ctx, notifAlert)
+	if err != nil || outcome == ingestSuppressed {
+		return nil, err
+	}
+	s.syncAlertEventsBestEffort(ctx, alert)
+	return alert, nil
+}
+
+// ingestAlert stores a new alert or fills in the acknowledged and resolved
+// times of an existing one, without touching its provider log. Webhook
+// deliveries and the sync poller can race to store the same new alert;
+// storeAlert upserts it, so the loser updates the alert the winner stored.
+func (s *Service) ingestAlert(ctx context.Context, notifAlert *notification.Alert) (*domain.Alert, ingestOutcome, error) {
+	existing, err := s.storage.GetAlertByExternalID(ctx, notifAlert.ExternalID, notifAlert.Source)
+	if errors.Is(err, domain.ErrNotFound) {
+		alert, created, err := s.storeAlert(ctx, notifAlert, nil)
+		if errors.Is(err, errSuppressed) {
+			return nil, ingestSuppressed, nil
+		}
+		if err != nil {
+			return nil, ingestUnchanged, err
+		}
+		if alert.ResolvedAt != nil {
+			s.autoResolveOutage(ctx, alert.OutageID, resolvedBySourceReason(alert))
+		}
+		if !created {
+			return alert, ingestUpdated, nil
+		}
+		return alert, ingestCreated, nil
+	}
+	if err != nil {
+		return nil, ingestUnchanged, err
+	}
+
+	changed, resolved := false, false
+	if existing.AcknowledgedAt == nil && notifAlert.AcknowledgedAt != nil {
+		existing.AcknowledgedAt = notifAlert.AcknowledgedAt
+		changed = true
+	}
+	if existing.ResolvedAt == nil && notifAlert.ResolvedAt != nil {
+		existing.ResolvedAt = notifAlert.ResolvedAt
+		changed, resolved = true, true
+	}
+	if !changed {
+		return existing, ingestUnchanged, nil
+	}
+	if err := s.storage.UpdateAlert(ctx, existing); err != nil {
+		return nil, ingestUnchanged, fmt.Errorf("failed to update alert: %w", err)
+	}
+	if resolved {
+		s.autoResolveOutage(ctx, existing.OutageID, resolvedBySourceReason(existing))
+	}
+	return existing, ingestUpdated, nil
+}
+
+// resolvedBySourceReason explains an outage resolution triggered by the
+// source resolving alert
+func resolvedBySourceReason(alert *domain.Alert) string {
+	return fmt.Sprintf("all of its alerts are resolved, the last reported by %s (%q)", alert.Source, alert.Title)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/notification"
+)
+
+// fakeWebhookSource is a notification service whose webhook payload is a
+// JSON-encoded notification.Alert.
+type fakeWebhookSource struct{}
+
+func (fakeWebhookSource) Name() string { return "fake" }
+
+func (fakeWebhookSource) FetchAlert(context.Context, string) (*notification.Alert, error) {
+	return nil, nil
+}
+
+func (fakeWebhookSource) FetchRecentAlerts(context.Context, time.Time) ([]*notification.Alert, error) {
+	return nil, nil
+}
+
+func (fakeWebhookSource) CreateIncident(context.Context, notification.IncidentRequest) (*notification.Alert, error) {
+	return nil, nil
+}
+
+func (fakeWebhookSource) AcknowledgeAlert(context.Context, string, string) error { return nil }
+
+func (fakeWebhookSource) ResolveAlert(context.Context, string, string) error { return nil }
+
+func (fakeWebhookSource) WebhookHandler() interface{} { return nil }
+
+func (fakeWebhookSource) VerifyWebhook(header http.Header, _ []byte) bool {
+	return notification.VerifyWebhookToken(header, "fake-token")
+}
+
+func (fakeWebhookSource) ParseWebhook(payload []byte, _ time.Time) ([]*notification.Alert, error) {
+	var a notification.Alert
+	if err := json.Unmarshal(payload, &a); err != nil {
+		return nil, err
+	}
+	return []*notification.Alert{&a}, nil
+}
+
+func TestProcessWebhook(t *testing.T) {
+	svc := newSvc()
+	svc.RegisterNotificationService(fakeWebhookSource{})
+	ctx := context.Background()
+
+	if !svc.SupportsWebhooks("fake") {
+		t.Fatal("expected fake source to support webhooks")
+	}
+	if svc.SupportsWebhooks("unknown") {
+		t.Error("unregistered source should not support webhooks")
+	}
+
+	signed := http.Header{}
+	signed.Set(notification.WebhookTokenHeader, "fake-token")
+	if !svc.VerifyWebhook("fake", signed, nil) {
+		t.Error("delivery with the source's token should verify")
+	}
+	if svc.VerifyWebhook("fake", http.Header{}, nil) {
+		t.Error("unsigned delivery should not verify")
+	}
+	if svc.VerifyWebhook("unknown", signed, nil) {
+		t.Error("delivery to an unregistered source should not verify")
+	}
+
+	triggered := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	acked := triggered.Add(30 * time.Second)
+	deliver := func(a notification.Alert) {
+		t.Helper()
+		payload, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ProcessWebhook(ctx, "fake", payload, time.Now()); err != nil {
+			t.Fatalf("ProcessWebhook: %v", err)
+		}
+	}
+
+	alert := notification.Alert{ExternalID: "A1", Source: "fake", Title: "disk full", Severity: "high", TriggeredAt: triggered}
+	deliver(alert)
+	alert.AcknowledgedAt = &acked
+	deliver(alert)
+
+	outages, err := svc.ListOutages(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 1 {
+		t.Fatalf("expected 1 outage from repeated deliveries, got %d", len(outages))
+	}
+	alerts, err := svc.ListAlertsByOutage(ctx, outages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].AcknowledgedAt == nil || !alerts[0].AcknowledgedAt.Equal(acked) {
+		t.Errorf("AcknowledgedAt = %v, want %v", alerts[0].AcknowledgedAt, acked)
+	}
+
+	if err := svc.ProcessWebhook(ctx, "unknown", nil, time.Now()); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
+
+// A delivery that finds no alert but loses the race to store it updates the
+// alert the winning delivery stored, and keeps no outage of its own
+func TestStoreAlertConcurrentDelivery(t *testing.T) {
+	ctx := context.Background()
+	svc := newSvc()
+	triggered := time.Now().Add(-time.Minute)
+	resolved := time.Now()
+
+	first, created, err := svc.storeAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "fake", Title: "disk full", TriggeredAt: triggered}, nil)
+	if err != nil || !created {
+		t.Fatalf("storeAlert() = %v created %v, want created", err, created)
+	}
+	second, created, err := svc.storeAlert(ctx, &notification.Alert{ExternalID: "A1", Source: "fake", Title: "disk full", TriggeredAt: triggered, ResolvedAt: &resolved}, nil)
+	if err != nil {
+		t.Fatalf("storeAlert() again err = %v", err)
+	}
+	if created || second.ID != first.ID || second.ResolvedAt == nil {
+		t.Errorf("storeAlert() again = %+v created %v, want the first alert, resolved", second, created)
+	}
+
+	outages, err := svc.ListOutages(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 1 || outages[0].ID != first.OutageID {
+		t.Errorf("got %d outages, want only the one opened for the first delivery", len(outages))
+	}
+	// Nor is one left in the trash
+	if all, err := svc.storage.ListOutages(ctx, 10, 0, true); err != nil || len(all) != 1 {
+		t.Errorf("got %d outages including the trash, %v; want only the first delivery's", len(all), err)
+	}
+}
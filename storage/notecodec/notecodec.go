@@ -0,0 +1,67 @@
+// Package notecodec converts note content to and from its stored form.
+// Large log notes are gzip-compressed and base64-encoded so pasted log
+// excerpts and stack traces take less space; every other note is stored as
+// written. Compressed notes are marked by their stored format, so the notes
+// table needs no extra column and older rows decode unchanged.
+package notecodec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/conall/outalator/domain"
+)
+
+// CompressThreshold is the size in bytes from which log notes are compressed
+const CompressThreshold = 4 << 10
+
+// compressedLogFormat is the stored format of a compressed log note
+const compressedLogFormat = domain.NoteFormatLog + "+gzip"
+
+// Encode returns the format and content to store for a note. Content is
+// compressed only when the encoded form is smaller.
+func Encode(format, content string) (storedFormat, storedContent string, err error) {
+	if format != domain.NoteFormatLog || len(content) < CompressThreshold {
+		return format, content, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(zw, content); err != nil {
+		return "", "", fmt.Errorf("failed to compress note: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to compress note: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+	if len(encoded) >= len(content) {
+		return format, content, nil
+	}
+	return compressedLogFormat, encoded, nil
+}
+
+// Decode returns the format and content of a stored note, exactly as they
+// were passed to Encode
+func Decode(storedFormat, storedContent string) (format, content string, err error) {
+	if storedFormat != compressedLogFormat {
+		return storedFormat, storedContent, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(storedContent)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode compressed note: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decompress note: %w", err)
+	}
+	decompressed, err := io.ReadAll(zr)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decompress note: %w", err)
+	}
+	return domain.NoteFormatLog, string(decompressed), nil
+}
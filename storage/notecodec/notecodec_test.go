@@ -0,0 +1,72 @@
+package notecodec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/conall/outalator/domain"
+)
+
+func TestRoundTrip(t *testing.T) {
+	trace := "panic: runtime error: index out of range [3] with length 3\n\n" +
+		strings.Repeat("goroutine 1 [running]:\nmain.handler(...)\n\t/app/main.go:42 +0x1d\r\n", 200) +
+		"  trailing whitespace\t \n"
+
+	tests := []struct {
+		name           string
+		format         string
+		content        string
+		wantCompressed bool
+	}{
+		{"large log", domain.NoteFormatLog, trace, true},
+		{"small log", domain.NoteFormatLog, "ERROR: connection refused\n", false},
+		{"large markdown", domain.NoteFormatMarkdown, trace, false},
+		{"empty format", "", "hello", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storedFormat, storedContent, err := Encode(tt.format, tt.content)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if compressed := storedFormat == compressedLogFormat; compressed != tt.wantCompressed {
+				t.Fatalf("stored format = %q, want compressed %v", storedFormat, tt.wantCompressed)
+			}
+			if tt.wantCompressed && len(storedContent) >= len(tt.content) {
+				t.Errorf("stored %d bytes for %d bytes of content", len(storedContent), len(tt.content))
+			}
+
+			format, content, err := Decode(storedFormat, storedContent)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if format != tt.format || content != tt.content {
+				t.Errorf("round trip changed the note: format %q, content equal %v", format, content == tt.content)
+			}
+		})
+	}
+}
+
+func TestEncodeIncompressible(t *testing.T) {
+	// Random printable text compresses too little to pay for base64
+	var sb strings.Builder
+	x := uint32(1)
+	for sb.Len() < 2*CompressThreshold {
+		x = x*1664525 + 1013904223
+		sb.WriteByte(byte(' ' + x>>24%95))
+	}
+
+	storedFormat, storedContent, err := Encode(domain.NoteFormatLog, sb.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storedFormat != domain.NoteFormatLog || storedContent != sb.String() {
+		t.Errorf("incompressible log stored as %q, want it unchanged", storedFormat)
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	if _, _, err := Decode(compressedLogFormat, "not base64!"); err == nil {
+		t.Error("Decode of corrupt content succeeded, want error")
+	}
+}
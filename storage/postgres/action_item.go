@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const actionItemColumns = `id, outage_id, description, assignee, due_date, status, created_by,
+		created_at, updated_at, closed_at`
+
+// CreateActionItem adds an action item to an outage
+func (s *PostgresStorage) CreateActionItem(ctx context.Context, item *domain.ActionItem) error {
+	query := `
+		INSERT INTO action_items (` + actionItemColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		item.ID, item.OutageID, item.Description, item.Assignee, item.DueDate, item.Status, item.CreatedBy,
+		item.CreatedAt, item.UpdatedAt, item.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create action item: %w", err)
+	}
+	return nil
+}
+
+// GetActionItem retrieves an action item by ID
+func (s *PostgresStorage) GetActionItem(ctx context.Context, id uuid.UUID) (*domain.ActionItem, error) {
+	query := `SELECT ` + actionItemColumns + ` FROM action_items WHERE id = $1`
+	item, err := scanActionItem(s.db.QueryRowContext(ctx, query, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("action item %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action item: %w", err)
+	}
+	return item, nil
+}
+
+// ListActionItemsByOutage retrieves an outage's action items, oldest first
+func (s *PostgresStorage) ListActionItemsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.ActionItem, error) {
+	query := `SELECT ` + actionItemColumns + `
+		FROM action_items
+		WHERE outage_id = $1
+		ORDER BY created_at ASC
+	`
+	return s.queryActionItems(ctx, query, outageID)
+}
+
+// ListOverdueActionItems retrieves the open and in-progress action items due
+// before the given time, earliest due first
+func (s *PostgresStorage) ListOverdueActionItems(ctx context.Context, before time.Time) ([]*domain.ActionItem, error) {
+	query := `SELECT ` + actionItemColumns + `
+		FROM action_items
+		WHERE status IN ($1, $2) AND due_date < $3
+		ORDER BY due_date ASC
+	`
+	return s.queryActionItems(ctx, query, domain.ActionItemOpen, domain.ActionItemInProgress, before)
+}
+
+// UpdateActionItem saves an action item's description, assignee, due date,
+// status and closing time
+func (s *PostgresStorage) UpdateActionItem(ctx context.Context, item *domain.ActionItem) error {
+	query := `
+		UPDATE action_items
+		SET description = $2, assignee = $3, due_date = $4, status = $5, updated_at = $6, closed_at = $7
+		WHERE id = $1
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		item.ID, item.Description, item.Assignee, item.DueDate, item.Status, item.UpdatedAt, item.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update action item: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("action item %s: %w", item.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteActionItem deletes an action item
+func (s *PostgresStorage) DeleteActionItem(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM action_items WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete action item: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("action item %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (s *PostgresStorage) queryActionItems(ctx context.Context, query string, args ...any) ([]*domain.ActionItem, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list action items: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []*domain.ActionItem
+	for rows.Next() {
+		item, err := scanActionItem(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan action item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating action items: %w", err)
+	}
+	return items, nil
+}
+
+// scanActionItem scans a row of actionItemColumns
+func scanActionItem(scan func(dest ...any) error) (*domain.ActionItem, error) {
+	item := &domain.ActionItem{}
+	if err := scan(
+		&item.ID, &item.OutageID, &item.Description, &item.Assignee, &item.DueDate, &item.Status, &item.CreatedBy,
+		&item.CreatedAt, &item.UpdatedAt, &item.ClosedAt,
+	); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
@@ -6,43 +6,120 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-// CreateAlert creates a new alert in the database
+// CreateAlert stores an alert, setting alert to the stored one when it
+// was already there
 func (s *PostgresStorage) CreateAlert(ctx context.Context, alert *domain.Alert) error {
+	stored, created, err := s.UpsertAlert(ctx, alert)
+	if err != nil {
+		return err
+	}
+	if !created {
+		*alert = *stored
+	}
+	return nil
+}
+
+// UpsertAlert inserts an alert, or fills in the lifecycle times of the
+// alert already stored with its source and external ID
+func (s *PostgresStorage) UpsertAlert(ctx context.Context, alert *domain.Alert) (*domain.Alert, bool, error) {
+	id, err := upsertAlert(ctx, s.db, alert)
+	if err != nil {
+		return nil, false, err
+	}
+	if id == alert.ID {
+		return alert, true, nil
+	}
+	stored, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, false, nil
+}
+
+// CreateOutageWithAlert creates an outage and upserts its alert in one
+// transaction. When the alert was already stored the outage is deleted
+// before the transaction commits, so it is never seen.
+func (s *PostgresStorage) CreateOutageWithAlert(ctx context.Context, outage *domain.Outage, alert *domain.Alert) (*domain.Alert, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create outage: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := createOutage(ctx, tx, outage); err != nil {
+		return nil, false, err
+	}
+	alert.OutageID = outage.ID
+	id, err := upsertAlert(ctx, tx, alert)
+	if err != nil {
+		return nil, false, err
+	}
+	if id != alert.ID {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM outages WHERE id = $1`, outage.ID); err != nil {
+			return nil, false, fmt.Errorf("failed to delete outage: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to create outage: %w", err)
+	}
+	if id == alert.ID {
+		return alert, true, nil
+	}
+	stored, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, false, nil
+}
+
+// upsertAlert upserts an alert with q, returning the stored alert's ID
+func upsertAlert(ctx context.Context, q querier, alert *domain.Alert) (uuid.UUID, error) {
 	// Marshal JSON fields
 	sourceMetadataJSON, err := marshalJSONAny(alert.SourceMetadata)
 	if err != nil {
-		return fmt.Errorf("failed to marshal source_metadata: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to marshal source_metadata: %w", err)
 	}
 	metadataJSON, err := marshalJSONMap(alert.Metadata)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 	customFieldsJSON, err := marshalJSONAny(alert.CustomFields)
 	if err != nil {
-		return fmt.Errorf("failed to marshal custom_fields: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to marshal custom_fields: %w", err)
+	}
+	teamNamesJSON, err := marshalNames(alert.TeamNames)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to marshal team_names: %w", err)
 	}
 
 	query := `
 		INSERT INTO alerts (id, outage_id, external_id, source, team_name, title, description,
 		                    severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		                    source_metadata, metadata, custom_fields)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		                    source_metadata, metadata, custom_fields, team_names, service, fingerprint)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (external_id, source) DO UPDATE SET
+			acknowledged_at = COALESCE(alerts.acknowledged_at, EXCLUDED.acknowledged_at),
+			resolved_at = COALESCE(alerts.resolved_at, EXCLUDED.resolved_at)
+		RETURNING id
 	`
-	_, err = s.db.ExecContext(ctx, query,
+	var id uuid.UUID
+	err = q.QueryRowContext(ctx, query,
 		alert.ID, alert.OutageID, alert.ExternalID, alert.Source, alert.TeamName,
 		alert.Title, alert.Description, alert.Severity, alert.TriggeredAt,
 		alert.AcknowledgedAt, alert.ResolvedAt, alert.CreatedAt,
-		sourceMetadataJSON, metadataJSON, customFieldsJSON,
-	)
+		sourceMetadataJSON, metadataJSON, customFieldsJSON, teamNamesJSON, alert.Service, alert.Fingerprint,
+	).Scan(&id)
 	if err != nil {
-		return fmt.Errorf("failed to create alert: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to upsert alert: %w", err)
 	}
-	return nil
+	return id, nil
 }
 
 // GetAlert retrieves an alert by ID
@@ -50,17 +127,17 @@ func (s *PostgresStorage) GetAlert(ctx context.Context, id uuid.UUID) (*domain.A
 	query := `
 		SELECT id, outage_id, external_id, source, team_name, title, description,
 		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		       source_metadata, metadata, custom_fields
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
 		FROM alerts
 		WHERE id = $1
 	`
 	alert := &domain.Alert{}
-	var sourceMetadataJSON, metadataJSON, customFieldsJSON []byte
+	var sourceMetadataJSON, metadataJSON, customFieldsJSON, teamNamesJSON []byte
 	err := s.db.QueryRowContext(ctx, query, id).Scan(
 		&alert.ID, &alert.OutageID, &alert.ExternalID, &alert.Source, &alert.TeamName,
 		&alert.Title, &alert.Description, &alert.Severity, &alert.TriggeredAt,
 		&alert.AcknowledgedAt, &alert.ResolvedAt, &alert.CreatedAt,
-		&sourceMetadataJSON, &metadataJSON, &customFieldsJSON,
+		&sourceMetadataJSON, &metadataJSON, &customFieldsJSON, &teamNamesJSON, &alert.Service, &alert.Fingerprint,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("alert %s: %w", id, domain.ErrNotFound)
@@ -85,6 +162,9 @@ func (s *PostgresStorage) GetAlert(ctx context.Context, id uuid.UUID) (*domain.A
 			return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
 		}
 	}
+	if alert.TeamNames, err = unmarshalNames(teamNamesJSON, "team_names"); err != nil {
+		return nil, err
+	}
 
 	return alert, nil
 }
@@ -94,17 +174,17 @@ func (s *PostgresStorage) GetAlertByExternalID(ctx context.Context, externalID,
 	query := `
 		SELECT id, outage_id, external_id, source, team_name, title, description,
 		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		       source_metadata, metadata, custom_fields
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
 		FROM alerts
 		WHERE external_id = $1 AND source = $2
 	`
 	alert := &domain.Alert{}
-	var sourceMetadataJSON, metadataJSON, customFieldsJSON []byte
+	var sourceMetadataJSON, metadataJSON, customFieldsJSON, teamNamesJSON []byte
 	err := s.db.QueryRowContext(ctx, query, externalID, source).Scan(
 		&alert.ID, &alert.OutageID, &alert.ExternalID, &alert.Source, &alert.TeamName,
 		&alert.Title, &alert.Description, &alert.Severity, &alert.TriggeredAt,
 		&alert.AcknowledgedAt, &alert.ResolvedAt, &alert.CreatedAt,
-		&sourceMetadataJSON, &metadataJSON, &customFieldsJSON,
+		&sourceMetadataJSON, &metadataJSON, &customFieldsJSON, &teamNamesJSON, &alert.Service, &alert.Fingerprint,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("alert external_id=%s source=%s: %w", externalID, source, domain.ErrNotFound)
@@ -129,6 +209,9 @@ func (s *PostgresStorage) GetAlertByExternalID(ctx context.Context, externalID,
 			return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
 		}
 	}
+	if alert.TeamNames, err = unmarshalNames(teamNamesJSON, "team_names"); err != nil {
+		return nil, err
+	}
 
 	return alert, nil
 }
@@ -138,12 +221,84 @@ func (s *PostgresStorage) ListAlertsByOutage(ctx context.Context, outageID uuid.
 	query := `
 		SELECT id, outage_id, external_id, source, team_name, title, description,
 		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		       source_metadata, metadata, custom_fields
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
 		FROM alerts
 		WHERE outage_id = $1
 		ORDER BY triggered_at DESC
 	`
-	rows, err := s.db.QueryContext(ctx, query, outageID)
+	return s.queryAlerts(ctx, query, outageID)
+}
+
+// listAlertsByOutages retrieves the alerts of several outages in one query,
+// ordered as ListAlertsByOutage orders them
+func (s *PostgresStorage) listAlertsByOutages(ctx context.Context, outageIDs []uuid.UUID) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+		WHERE outage_id = ANY($1)
+		ORDER BY triggered_at DESC
+	`
+	return s.queryAlerts(ctx, query, pq.Array(outageIDs))
+}
+
+// ListOpenAlerts retrieves unresolved alerts triggered before the given time, oldest first
+func (s *PostgresStorage) ListOpenAlerts(ctx context.Context, triggeredBefore time.Time) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+		WHERE resolved_at IS NULL AND triggered_at < $1
+		ORDER BY triggered_at ASC
+	`
+	return s.queryAlerts(ctx, query, triggeredBefore)
+}
+
+// ListAlertsTriggeredBetween retrieves alerts triggered at or after since and before until, oldest first
+func (s *PostgresStorage) ListAlertsTriggeredBetween(ctx context.Context, since, until time.Time) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+		WHERE triggered_at >= $1 AND triggered_at < $2
+		ORDER BY triggered_at ASC
+	`
+	return s.queryAlerts(ctx, query, since, until)
+}
+
+// ListAlerts retrieves a page of the alerts matching q, most recently
+// triggered first
+func (s *PostgresStorage) ListAlerts(ctx context.Context, q domain.AlertQuery) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+		WHERE ($1 = '' OR source = $1)
+		  AND ($2 = '' OR team_name = $2 OR team_names @> jsonb_build_array($2::text))
+		  AND ($3 = '' OR severity = $3)
+		  AND ($4::timestamp IS NULL OR triggered_at >= $4)
+		  AND ($5::timestamp IS NULL OR triggered_at < $5)
+		  AND ($6::boolean IS NULL OR (resolved_at IS NOT NULL) = $6)
+		ORDER BY triggered_at DESC, id
+		LIMIT $7 OFFSET $8
+	`
+	var resolved sql.NullBool
+	if q.Resolved != nil {
+		resolved = sql.NullBool{Bool: *q.Resolved, Valid: true}
+	}
+	return s.queryAlerts(ctx, query, q.Source, q.Team, q.Severity,
+		sql.NullTime{Time: q.Since, Valid: !q.Since.IsZero()},
+		sql.NullTime{Time: q.Until, Valid: !q.Until.IsZero()},
+		resolved, q.Limit, q.Offset)
+}
+
+// queryAlerts runs a query selecting alert columns and scans the results
+func (s *PostgresStorage) queryAlerts(ctx context.Context, query string, args ...any) ([]*domain.Alert, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list alerts: %w", err)
 	}
@@ -152,12 +307,12 @@ func (s *PostgresStorage) ListAlertsByOutage(ctx context.Context, outageID uuid.
 	var alerts []*domain.Alert
 	for rows.Next() {
 		alert := &domain.Alert{}
-		var sourceMetadataJSON, metadataJSON, customFieldsJSON []byte
+		var sourceMetadataJSON, metadataJSON, customFieldsJSON, teamNamesJSON []byte
 		err := rows.Scan(
 			&alert.ID, &alert.OutageID, &alert.ExternalID, &alert.Source, &alert.TeamName,
 			&alert.Title, &alert.Description, &alert.Severity, &alert.TriggeredAt,
 			&alert.AcknowledgedAt, &alert.ResolvedAt, &alert.CreatedAt,
-			&sourceMetadataJSON, &metadataJSON, &customFieldsJSON,
+			&sourceMetadataJSON, &metadataJSON, &customFieldsJSON, &teamNamesJSON, &alert.Service, &alert.Fingerprint,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan alert: %w", err)
@@ -179,9 +334,15 @@ func (s *PostgresStorage) ListAlertsByOutage(ctx context.Context, outageID uuid.
 				return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
 			}
 		}
+		if alert.TeamNames, err = unmarshalNames(teamNamesJSON, "team_names"); err != nil {
+			return nil, err
+		}
 
 		alerts = append(alerts, alert)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
 
 	return alerts, nil
 }
@@ -201,19 +362,24 @@ func (s *PostgresStorage) UpdateAlert(ctx context.Context, alert *domain.Alert)
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	teamNamesJSON, err := marshalNames(alert.TeamNames)
+	if err != nil {
+		return fmt.Errorf("failed to marshal team_names: %w", err)
+	}
 
 	query := `
 		UPDATE alerts
 		SET outage_id = $2, external_id = $3, source = $4, team_name = $5, title = $6,
 		    description = $7, severity = $8, triggered_at = $9, acknowledged_at = $10,
-		    resolved_at = $11, source_metadata = $12, metadata = $13, custom_fields = $14
+		    resolved_at = $11, source_metadata = $12, metadata = $13, custom_fields = $14,
+		    team_names = $15, service = $16, fingerprint = $17
 		WHERE id = $1
 	`
 	result, err := s.db.ExecContext(ctx, query,
 		alert.ID, alert.OutageID, alert.ExternalID, alert.Source, alert.TeamName,
 		alert.Title, alert.Description, alert.Severity, alert.TriggeredAt,
 		alert.AcknowledgedAt, alert.ResolvedAt,
-		sourceMetadataJSON, metadataJSON, customFieldsJSON,
+		sourceMetadataJSON, metadataJSON, customFieldsJSON, teamNamesJSON, alert.Service, alert.Fingerprint,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update alert: %w", err)
@@ -229,3 +395,28 @@ func (s *PostgresStorage) UpdateAlert(ctx context.Context, alert *domain.Alert)
 
 	return nil
 }
+
+// marshalNames marshals a list of names, such as an alert's teams or an
+// outage's affected services, to a JSON array, empty rather than null when
+// there are none
+func marshalNames(names []string) ([]byte, error) {
+	if names == nil {
+		names = []string{}
+	}
+	return json.Marshal(names)
+}
+
+// unmarshalNames unmarshals a list of names stored by marshalNames in
+// column, returning nil when there are none
+func unmarshalNames(data []byte, column string) ([]string, error) {
+	var names []string
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &names); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", column, err)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return names, nil
+}
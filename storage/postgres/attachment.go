@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// CreateAttachment records the metadata of an uploaded attachment
+func (s *PostgresStorage) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, outage_id, note_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		attachment.ID, attachment.OutageID, attachment.NoteID, attachment.Filename, attachment.ContentType,
+		attachment.Size, attachment.SHA256, attachment.StorageKey, attachment.UploadedBy, attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+// GetAttachment retrieves an attachment's metadata by ID
+func (s *PostgresStorage) GetAttachment(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	query := `
+		SELECT id, outage_id, note_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at
+		FROM attachments
+		WHERE id = $1
+	`
+	attachment, err := scanAttachment(s.db.QueryRowContext(ctx, query, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("attachment %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// ListAttachmentsByOutage retrieves the attachments of an outage, oldest first
+func (s *PostgresStorage) ListAttachmentsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Attachment, error) {
+	query := `
+		SELECT id, outage_id, note_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at
+		FROM attachments
+		WHERE outage_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var attachments []*domain.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// DeleteAttachment removes an attachment's metadata
+func (s *PostgresStorage) DeleteAttachment(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("attachment %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanAttachment populates an Attachment from a row selected in column order
+// using the provided scan function
+func scanAttachment(scan func(dest ...any) error) (*domain.Attachment, error) {
+	attachment := &domain.Attachment{}
+	var noteID uuid.NullUUID
+	if err := scan(
+		&attachment.ID, &attachment.OutageID, &noteID, &attachment.Filename, &attachment.ContentType,
+		&attachment.Size, &attachment.SHA256, &attachment.StorageKey, &attachment.UploadedBy, &attachment.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if noteID.Valid {
+		attachment.NoteID = &noteID.UUID
+	}
+	return attachment, nil
+}
@@ -0,0 +1,44 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/conall/outalator/storage"
+	"github.com/conall/outalator/storage/postgres"
+	"github.com/conall/outalator/storage/storagetest"
+)
+
+// Compile-time check: PostgresStorage must satisfy the storage.Storage interface.
+var _ storage.Storage = (*postgres.PostgresStorage)(nil)
+
+// TestConformance runs the storage conformance suite against the database
+// named by POSTGRES_TEST_DSN, e.g.
+// "host=localhost user=outalator dbname=outalator_test sslmode=disable".
+// The database must have the migrations applied; every table is emptied
+// before each test.
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		t.Helper()
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Fatalf("sql.Open: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		_, err = db.ExecContext(context.Background(), `
+			TRUNCATE outages, alerts, alert_events, notes, tags, outage_status_changes,
+			         user_preferences, outage_reviews, alert_sync_cursors, source_ingestion, config_resources, processed_events,
+			         import_runs CASCADE`)
+		if err != nil {
+			t.Fatalf("failed to empty database: %v", err)
+		}
+		return postgres.NewFromDB(db)
+	})
+}
@@ -0,0 +1,8 @@
+package postgres
+
+import "database/sql"
+
+// NewFromDB wraps an open database for tests outside the package
+func NewFromDB(db *sql.DB) *PostgresStorage {
+	return &PostgresStorage{db: db}
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+)
+
+// GetExportWatermark retrieves how far a table has been exported for analytics
+func (s *PostgresStorage) GetExportWatermark(ctx context.Context, table string) (*domain.ExportWatermark, error) {
+	query := `
+		SELECT table_name, exported_until, updated_at
+		FROM export_watermarks
+		WHERE table_name = $1
+	`
+	watermark := &domain.ExportWatermark{}
+	err := s.db.QueryRowContext(ctx, query, table).Scan(&watermark.Table, &watermark.ExportedUntil, &watermark.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("export watermark for %s: %w", table, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export watermark: %w", err)
+	}
+	return watermark, nil
+}
+
+// UpsertExportWatermark creates or replaces the analytics export watermark for a table
+func (s *PostgresStorage) UpsertExportWatermark(ctx context.Context, watermark *domain.ExportWatermark) error {
+	query := `
+		INSERT INTO export_watermarks (table_name, exported_until, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (table_name) DO UPDATE SET
+			exported_until = EXCLUDED.exported_until,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, watermark.Table, watermark.ExportedUntil, watermark.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save export watermark: %w", err)
+	}
+	return nil
+}
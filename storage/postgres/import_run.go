@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const importRunColumns = `id, provider, kind, filter_hash, since, until, team_ids, status,
+		resume_offset, last_item_at, stats, error, started_at, updated_at, finished_at`
+
+// CreateImportRun records a new historical import run
+func (s *PostgresStorage) CreateImportRun(ctx context.Context, run *domain.ImportRun) error {
+	teamIDs, stats, err := marshalImportRun(run)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO import_runs (` + importRunColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	_, err = s.db.ExecContext(ctx, query,
+		run.ID, run.Provider, run.Kind, run.FilterHash, run.Since, run.Until, teamIDs, run.Status,
+		run.Offset, run.LastItemAt, stats, run.Error, run.StartedAt, run.UpdatedAt, run.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create import run: %w", err)
+	}
+	return nil
+}
+
+// UpdateImportRun saves the progress, statistics and status of an import run
+func (s *PostgresStorage) UpdateImportRun(ctx context.Context, run *domain.ImportRun) error {
+	_, stats, err := marshalImportRun(run)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE import_runs
+		SET status = $2, resume_offset = $3, last_item_at = $4, stats = $5, error = $6,
+			updated_at = $7, finished_at = $8
+		WHERE id = $1
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		run.ID, run.Status, run.Offset, run.LastItemAt, stats, run.Error, run.UpdatedAt, run.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import run: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("import run %s: %w", run.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// GetImportRun retrieves an import run by ID
+func (s *PostgresStorage) GetImportRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error) {
+	query := `SELECT ` + importRunColumns + ` FROM import_runs WHERE id = $1`
+	run, err := scanImportRun(s.db.QueryRowContext(ctx, query, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("import run %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import run: %w", err)
+	}
+	return run, nil
+}
+
+// ListImportRuns lists import runs, optionally only those from provider,
+// most recently started first
+func (s *PostgresStorage) ListImportRuns(ctx context.Context, provider string) ([]*domain.ImportRun, error) {
+	query := `
+		SELECT ` + importRunColumns + `
+		FROM import_runs
+		WHERE $1 = '' OR provider = $1
+		ORDER BY started_at DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []*domain.ImportRun
+	for rows.Next() {
+		run, err := scanImportRun(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating import runs: %w", err)
+	}
+	return runs, nil
+}
+
+// marshalImportRun marshals an import run's team IDs and statistics to JSON
+func marshalImportRun(run *domain.ImportRun) (teamIDs, stats []byte, err error) {
+	teams := run.TeamIDs
+	if teams == nil {
+		teams = []string{}
+	}
+	if teamIDs, err = json.Marshal(teams); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal team ids: %w", err)
+	}
+	if stats, err = json.Marshal(run.Stats); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal import stats: %w", err)
+	}
+	return teamIDs, stats, nil
+}
+
+// scanImportRun scans a row of importRunColumns
+func scanImportRun(scan func(dest ...any) error) (*domain.ImportRun, error) {
+	run := &domain.ImportRun{}
+	var teamIDs, stats []byte
+	if err := scan(
+		&run.ID, &run.Provider, &run.Kind, &run.FilterHash, &run.Since, &run.Until, &teamIDs, &run.Status,
+		&run.Offset, &run.LastItemAt, &stats, &run.Error, &run.StartedAt, &run.UpdatedAt, &run.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(teamIDs, &run.TeamIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal team ids: %w", err)
+	}
+	if len(run.TeamIDs) == 0 {
+		run.TeamIDs = nil
+	}
+	if err := json.Unmarshal(stats, &run.Stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal import stats: %w", err)
+	}
+	return run, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// RecordIngestionSuccess records a successful alert ingestion from a source
+func (s *PostgresStorage) RecordIngestionSuccess(ctx context.Context, source string, at time.Time) error {
+	query := `
+		INSERT INTO source_ingestion (source, last_success_at, updated_at)
+		VALUES ($1, $2, $2)
+		ON CONFLICT (source) DO UPDATE SET
+			last_success_at = EXCLUDED.last_success_at,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, source, at); err != nil {
+		return fmt.Errorf("failed to record ingestion success: %w", err)
+	}
+	return nil
+}
+
+// RecordIngestionFailure records a failed alert ingestion from a source
+func (s *PostgresStorage) RecordIngestionFailure(ctx context.Context, source string, at time.Time, message string) error {
+	query := `
+		INSERT INTO source_ingestion (source, last_failure_at, last_error, updated_at)
+		VALUES ($1, $2, $3, $2)
+		ON CONFLICT (source) DO UPDATE SET
+			last_failure_at = EXCLUDED.last_failure_at,
+			last_error = EXCLUDED.last_error,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, source, at, message); err != nil {
+		return fmt.Errorf("failed to record ingestion failure: %w", err)
+	}
+	return nil
+}
+
+// ListIngestionRecords retrieves the ingestion record of every source
+func (s *PostgresStorage) ListIngestionRecords(ctx context.Context) ([]*domain.IngestionRecord, error) {
+	query := `
+		SELECT source, last_success_at, last_failure_at, last_error, updated_at
+		FROM source_ingestion
+		ORDER BY source
+	`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingestion records: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var records []*domain.IngestionRecord
+	for rows.Next() {
+		record := &domain.IngestionRecord{}
+		if err := rows.Scan(&record.Source, &record.LastSuccessAt, &record.LastFailureAt, &record.LastError, &record.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ingestion record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ingestion records: %w", err)
+	}
+
+	return records, nil
+}
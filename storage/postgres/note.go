@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage/notecodec"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // CreateNote creates a new note in the database
@@ -22,13 +25,17 @@ func (s *PostgresStorage) CreateNote(ctx context.Context, note *domain.Note) err
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	format, content, err := notecodec.Encode(note.Format, note.Content)
+	if err != nil {
+		return err
+	}
 
 	query := `
-		INSERT INTO notes (id, outage_id, content, format, author, created_at, updated_at, metadata, custom_fields)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO notes (id, outage_id, parent_note_id, content, format, author, created_at, updated_at, metadata, custom_fields)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	_, err = s.db.ExecContext(ctx, query,
-		note.ID, note.OutageID, note.Content, note.Format,
+		note.ID, note.OutageID, note.ParentNoteID, content, format,
 		note.Author, note.CreatedAt, note.UpdatedAt,
 		metadataJSON, customFieldsJSON,
 	)
@@ -41,15 +48,16 @@ func (s *PostgresStorage) CreateNote(ctx context.Context, note *domain.Note) err
 // GetNote retrieves a note by ID
 func (s *PostgresStorage) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
 	query := `
-		SELECT id, outage_id, content, format, author, created_at, updated_at, metadata, custom_fields
+		SELECT id, outage_id, parent_note_id, content, format, author, created_at, updated_at, deleted_at, metadata, custom_fields
 		FROM notes
 		WHERE id = $1
 	`
 	note := &domain.Note{}
+	var parentNoteID uuid.NullUUID
 	var metadataJSON, customFieldsJSON []byte
 	err := s.db.QueryRowContext(ctx, query, id).Scan(
-		&note.ID, &note.OutageID, &note.Content, &note.Format,
-		&note.Author, &note.CreatedAt, &note.UpdatedAt,
+		&note.ID, &note.OutageID, &parentNoteID, &note.Content, &note.Format,
+		&note.Author, &note.CreatedAt, &note.UpdatedAt, &note.DeletedAt,
 		&metadataJSON, &customFieldsJSON,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
@@ -58,6 +66,13 @@ func (s *PostgresStorage) GetNote(ctx context.Context, id uuid.UUID) (*domain.No
 	if err != nil {
 		return nil, fmt.Errorf("failed to get note: %w", err)
 	}
+	if parentNoteID.Valid {
+		note.ParentNoteID = &parentNoteID.UUID
+	}
+	note.Format, note.Content, err = notecodec.Decode(note.Format, note.Content)
+	if err != nil {
+		return nil, err
+	}
 
 	// Unmarshal JSON fields
 	if len(metadataJSON) > 0 {
@@ -74,15 +89,34 @@ func (s *PostgresStorage) GetNote(ctx context.Context, id uuid.UUID) (*domain.No
 	return note, nil
 }
 
-// ListNotesByOutage retrieves all notes for a specific outage
-func (s *PostgresStorage) ListNotesByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Note, error) {
+// ListNotesByOutage retrieves the notes for a specific outage, leaving out
+// notes in the trash unless includeDeleted is set
+func (s *PostgresStorage) ListNotesByOutage(ctx context.Context, outageID uuid.UUID, includeDeleted bool) ([]*domain.Note, error) {
+	query := `
+		SELECT id, outage_id, parent_note_id, content, format, author, created_at, updated_at, deleted_at, metadata, custom_fields
+		FROM notes
+		WHERE outage_id = $1 AND ($2 OR deleted_at IS NULL)
+		ORDER BY created_at DESC
+	`
+	return s.queryNotes(ctx, query, outageID, includeDeleted)
+}
+
+// listNotesByOutages retrieves the notes of several outages in one query,
+// leaving out notes in the trash and ordered as ListNotesByOutage orders
+// them
+func (s *PostgresStorage) listNotesByOutages(ctx context.Context, outageIDs []uuid.UUID) ([]*domain.Note, error) {
 	query := `
-		SELECT id, outage_id, content, format, author, created_at, updated_at, metadata, custom_fields
+		SELECT id, outage_id, parent_note_id, content, format, author, created_at, updated_at, deleted_at, metadata, custom_fields
 		FROM notes
-		WHERE outage_id = $1
+		WHERE outage_id = ANY($1) AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
-	rows, err := s.db.QueryContext(ctx, query, outageID)
+	return s.queryNotes(ctx, query, pq.Array(outageIDs))
+}
+
+// queryNotes runs a query selecting note columns and scans the results
+func (s *PostgresStorage) queryNotes(ctx context.Context, query string, args ...any) ([]*domain.Note, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list notes: %w", err)
 	}
@@ -91,15 +125,23 @@ func (s *PostgresStorage) ListNotesByOutage(ctx context.Context, outageID uuid.U
 	var notes []*domain.Note
 	for rows.Next() {
 		note := &domain.Note{}
+		var parentNoteID uuid.NullUUID
 		var metadataJSON, customFieldsJSON []byte
 		err := rows.Scan(
-			&note.ID, &note.OutageID, &note.Content, &note.Format,
-			&note.Author, &note.CreatedAt, &note.UpdatedAt,
+			&note.ID, &note.OutageID, &parentNoteID, &note.Content, &note.Format,
+			&note.Author, &note.CreatedAt, &note.UpdatedAt, &note.DeletedAt,
 			&metadataJSON, &customFieldsJSON,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan note: %w", err)
 		}
+		if parentNoteID.Valid {
+			note.ParentNoteID = &parentNoteID.UUID
+		}
+		note.Format, note.Content, err = notecodec.Decode(note.Format, note.Content)
+		if err != nil {
+			return nil, err
+		}
 
 		// Unmarshal JSON fields
 		if len(metadataJSON) > 0 {
@@ -115,6 +157,9 @@ func (s *PostgresStorage) ListNotesByOutage(ctx context.Context, outageID uuid.U
 
 		notes = append(notes, note)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notes: %w", err)
+	}
 
 	return notes, nil
 }
@@ -130,6 +175,10 @@ func (s *PostgresStorage) UpdateNote(ctx context.Context, note *domain.Note) err
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	format, content, err := notecodec.Encode(note.Format, note.Content)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		UPDATE notes
@@ -137,7 +186,7 @@ func (s *PostgresStorage) UpdateNote(ctx context.Context, note *domain.Note) err
 		WHERE id = $1
 	`
 	result, err := s.db.ExecContext(ctx, query,
-		note.ID, note.Content, note.Format, note.UpdatedAt,
+		note.ID, content, format, note.UpdatedAt,
 		metadataJSON, customFieldsJSON,
 	)
 	if err != nil {
@@ -173,3 +222,45 @@ func (s *PostgresStorage) DeleteNote(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// TrashNote moves a note to the trash
+func (s *PostgresStorage) TrashNote(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE notes SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
+	return s.setNoteDeletedAt(ctx, query, id, at)
+}
+
+// RestoreNote takes a note out of the trash
+func (s *PostgresStorage) RestoreNote(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE notes SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	return s.setNoteDeletedAt(ctx, query, id)
+}
+
+// setNoteDeletedAt runs a trash or restore query, reporting
+// domain.ErrNotFound when it matched no note
+func (s *PostgresStorage) setNoteDeletedAt(ctx context.Context, query string, id uuid.UUID, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, append([]any{id}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update note deleted_at: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("note %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// PurgeNotes permanently deletes notes trashed before deletedBefore
+func (s *PostgresStorage) PurgeNotes(ctx context.Context, deletedBefore time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM notes WHERE deleted_at < $1`, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge notes: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
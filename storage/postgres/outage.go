@@ -6,13 +6,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// outageColumns are the outage columns every outage query selects, in the
+// order scanOutage reads them
+const outageColumns = `id, title, description, status, severity, owning_team, created_at, updated_at, investigating_at, mitigated_at, resolved_at, deleted_at, metadata, custom_fields,
+		affected_services, customer_impact, impact_started_at, impact_ended_at, parent_id`
+
 // CreateOutage creates a new outage in the database
 func (s *PostgresStorage) CreateOutage(ctx context.Context, outage *domain.Outage) error {
+	return createOutage(ctx, s.db, outage)
+}
+
+// createOutage inserts an outage with q
+func createOutage(ctx context.Context, q querier, outage *domain.Outage) error {
 	// Marshal metadata and custom_fields to JSON
 	metadataJSON, err := marshalJSONMap(outage.Metadata)
 	if err != nil {
@@ -22,15 +34,22 @@ func (s *PostgresStorage) CreateOutage(ctx context.Context, outage *domain.Outag
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	servicesJSON, err := marshalNames(outage.AffectedServices)
+	if err != nil {
+		return fmt.Errorf("failed to marshal affected_services: %w", err)
+	}
 
 	query := `
-		INSERT INTO outages (id, title, description, status, severity, created_at, updated_at, resolved_at, metadata, custom_fields)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO outages (id, title, description, status, severity, owning_team, created_at, updated_at, investigating_at, mitigated_at, resolved_at, metadata, custom_fields,
+		                     affected_services, customer_impact, impact_started_at, impact_ended_at, parent_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`
-	_, err = s.db.ExecContext(ctx, query,
+	_, err = q.ExecContext(ctx, query,
 		outage.ID, outage.Title, outage.Description, outage.Status,
-		outage.Severity, outage.CreatedAt, outage.UpdatedAt, outage.ResolvedAt,
+		outage.Severity, outage.OwningTeam, outage.CreatedAt, outage.UpdatedAt,
+		outage.InvestigatingAt, outage.MitigatedAt, outage.ResolvedAt,
 		metadataJSON, customFieldsJSON,
+		servicesJSON, outage.CustomerImpact, outage.ImpactStartedAt, outage.ImpactEndedAt, outage.ParentID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create outage: %w", err)
@@ -38,20 +57,15 @@ func (s *PostgresStorage) CreateOutage(ctx context.Context, outage *domain.Outag
 	return nil
 }
 
-// GetOutage retrieves an outage by ID with all related data
+// GetOutage retrieves an outage by ID with its alerts, notes and tags
 func (s *PostgresStorage) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
-	query := `
-		SELECT id, title, description, status, severity, created_at, updated_at, resolved_at, metadata, custom_fields
-		FROM outages
-		WHERE id = $1
-	`
-	outage := &domain.Outage{}
-	var metadataJSON, customFieldsJSON []byte
-	err := s.db.QueryRowContext(ctx, query, id).Scan(
-		&outage.ID, &outage.Title, &outage.Description, &outage.Status,
-		&outage.Severity, &outage.CreatedAt, &outage.UpdatedAt, &outage.ResolvedAt,
-		&metadataJSON, &customFieldsJSON,
-	)
+	return s.GetOutageWith(ctx, id, domain.AllOutageAssociations)
+}
+
+// GetOutageWith retrieves an outage by ID with the selected associations
+func (s *PostgresStorage) GetOutageWith(ctx context.Context, id uuid.UUID, associations domain.OutageAssociations) (*domain.Outage, error) {
+	query := `SELECT ` + outageColumns + ` FROM outages WHERE id = $1`
+	outage, err := scanOutage(s.db.QueryRowContext(ctx, query, id).Scan)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
 	}
@@ -59,60 +73,127 @@ func (s *PostgresStorage) GetOutage(ctx context.Context, id uuid.UUID) (*domain.
 		return nil, fmt.Errorf("failed to get outage: %w", err)
 	}
 
-	// Unmarshal metadata and custom_fields
-	if len(metadataJSON) > 0 {
-		if err := json.Unmarshal(metadataJSON, &outage.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-	}
-	if len(customFieldsJSON) > 0 {
-		if err := json.Unmarshal(customFieldsJSON, &outage.CustomFields); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
-		}
+	if err := s.LoadOutageAssociations(ctx, []*domain.Outage{outage}, associations); err != nil {
+		return nil, err
 	}
 
-	// Load related alerts
-	alerts, err := s.ListAlertsByOutage(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load alerts: %w", err)
+	return outage, nil
+}
+
+// LoadOutageAssociations sets the selected alerts, notes and tags of
+// outages with one query for each, however many outages there are. Notes in
+// the trash are left out.
+func (s *PostgresStorage) LoadOutageAssociations(ctx context.Context, outages []*domain.Outage, associations domain.OutageAssociations) error {
+	if len(outages) == 0 {
+		return nil
 	}
-	outage.Alerts = make([]domain.Alert, len(alerts))
-	for i, alert := range alerts {
-		outage.Alerts[i] = *alert
+	byID := make(map[uuid.UUID]*domain.Outage, len(outages))
+	ids := make([]uuid.UUID, 0, len(outages))
+	for _, outage := range outages {
+		byID[outage.ID] = outage
+		ids = append(ids, outage.ID)
 	}
 
-	// Load related notes
-	notes, err := s.ListNotesByOutage(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load notes: %w", err)
-	}
-	outage.Notes = make([]domain.Note, len(notes))
-	for i, note := range notes {
-		outage.Notes[i] = *note
+	if associations.Alerts {
+		for _, outage := range outages {
+			outage.Alerts = []domain.Alert{}
+		}
+		alerts, err := s.listAlertsByOutages(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to load alerts: %w", err)
+		}
+		for _, alert := range alerts {
+			if outage := byID[alert.OutageID]; outage != nil {
+				outage.Alerts = append(outage.Alerts, *alert)
+			}
+		}
 	}
 
-	// Load related tags
-	tags, err := s.ListTagsByOutage(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load tags: %w", err)
+	if associations.Notes {
+		for _, outage := range outages {
+			outage.Notes = []domain.Note{}
+		}
+		notes, err := s.listNotesByOutages(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to load notes: %w", err)
+		}
+		for _, note := range notes {
+			if outage := byID[note.OutageID]; outage != nil {
+				outage.Notes = append(outage.Notes, *note)
+			}
+		}
 	}
-	outage.Tags = make([]domain.Tag, len(tags))
-	for i, tag := range tags {
-		outage.Tags[i] = *tag
+
+	if associations.Tags {
+		for _, outage := range outages {
+			outage.Tags = []domain.Tag{}
+		}
+		tags, err := s.listTagsByOutages(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to load tags: %w", err)
+		}
+		for _, tag := range tags {
+			if outage := byID[tag.OutageID]; outage != nil {
+				outage.Tags = append(outage.Tags, *tag)
+			}
+		}
 	}
+	return nil
+}
 
-	return outage, nil
+// ListOutages retrieves a list of outages with pagination, leaving out
+// outages in the trash unless includeDeleted is set
+func (s *PostgresStorage) ListOutages(ctx context.Context, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	query := `
+		SELECT ` + outageColumns + `
+		FROM outages
+		WHERE $3 OR deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+	return s.queryOutages(ctx, query, limit, offset, includeDeleted)
 }
 
-// ListOutages retrieves a list of outages with pagination
-func (s *PostgresStorage) ListOutages(ctx context.Context, limit, offset int) ([]*domain.Outage, error) {
+// ListOutagesByTeams retrieves a page of the outages owned by any of the
+// given teams, leaving out outages in the trash unless includeDeleted is set
+func (s *PostgresStorage) ListOutagesByTeams(ctx context.Context, teams []string, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
 	query := `
-		SELECT id, title, description, status, severity, created_at, updated_at, resolved_at, metadata, custom_fields
+		SELECT ` + outageColumns + `
 		FROM outages
+		WHERE owning_team = ANY($4) AND ($3 OR deleted_at IS NULL)
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
-	rows, err := s.db.QueryContext(ctx, query, limit, offset)
+	return s.queryOutages(ctx, query, limit, offset, includeDeleted, pq.Array(teams))
+}
+
+// ListChildOutages retrieves the outages whose parent is parentID, oldest
+// first, leaving out outages in the trash
+func (s *PostgresStorage) ListChildOutages(ctx context.Context, parentID uuid.UUID) ([]*domain.Outage, error) {
+	query := `
+		SELECT ` + outageColumns + `
+		FROM outages
+		WHERE parent_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+	return s.queryOutages(ctx, query, parentID)
+}
+
+// ListPublicOutages retrieves the open outages marked public in their
+// metadata, oldest first
+func (s *PostgresStorage) ListPublicOutages(ctx context.Context) ([]*domain.Outage, error) {
+	query := `
+		SELECT ` + outageColumns + `
+		FROM outages
+		WHERE metadata->>$1 = 'true' AND status NOT IN ('resolved', 'closed') AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+	return s.queryOutages(ctx, query, domain.MetadataPublic)
+}
+
+// queryOutages runs a query selecting outage columns and scans the results
+func (s *PostgresStorage) queryOutages(ctx context.Context, query string, args ...any) ([]*domain.Outage, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list outages: %w", err)
 	}
@@ -120,35 +201,55 @@ func (s *PostgresStorage) ListOutages(ctx context.Context, limit, offset int) ([
 
 	var outages []*domain.Outage
 	for rows.Next() {
-		outage := &domain.Outage{}
-		var metadataJSON, customFieldsJSON []byte
-		err := rows.Scan(
-			&outage.ID, &outage.Title, &outage.Description, &outage.Status,
-			&outage.Severity, &outage.CreatedAt, &outage.UpdatedAt, &outage.ResolvedAt,
-			&metadataJSON, &customFieldsJSON,
-		)
+		outage, err := scanOutage(rows.Scan)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan outage: %w", err)
 		}
-
-		// Unmarshal metadata and custom_fields
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &outage.Metadata); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-			}
-		}
-		if len(customFieldsJSON) > 0 {
-			if err := json.Unmarshal(customFieldsJSON, &outage.CustomFields); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
-			}
-		}
-
 		outages = append(outages, outage)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outages: %w", err)
+	}
 
 	return outages, nil
 }
 
+// scanOutage scans a row of outageColumns
+func scanOutage(scan func(dest ...any) error) (*domain.Outage, error) {
+	outage := &domain.Outage{}
+	var metadataJSON, customFieldsJSON, servicesJSON []byte
+	var parentID uuid.NullUUID
+	err := scan(
+		&outage.ID, &outage.Title, &outage.Description, &outage.Status,
+		&outage.Severity, &outage.OwningTeam, &outage.CreatedAt, &outage.UpdatedAt,
+		&outage.InvestigatingAt, &outage.MitigatedAt, &outage.ResolvedAt, &outage.DeletedAt,
+		&metadataJSON, &customFieldsJSON,
+		&servicesJSON, &outage.CustomerImpact, &outage.ImpactStartedAt, &outage.ImpactEndedAt, &parentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		outage.ParentID = &parentID.UUID
+	}
+
+	// Unmarshal metadata and custom_fields
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &outage.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+	if len(customFieldsJSON) > 0 {
+		if err := json.Unmarshal(customFieldsJSON, &outage.CustomFields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
+		}
+	}
+	if outage.AffectedServices, err = unmarshalNames(servicesJSON, "affected_services"); err != nil {
+		return nil, err
+	}
+	return outage, nil
+}
+
 // UpdateOutage updates an existing outage
 func (s *PostgresStorage) UpdateOutage(ctx context.Context, outage *domain.Outage) error {
 	// Marshal metadata and custom_fields to JSON
@@ -160,17 +261,25 @@ func (s *PostgresStorage) UpdateOutage(ctx context.Context, outage *domain.Outag
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	servicesJSON, err := marshalNames(outage.AffectedServices)
+	if err != nil {
+		return fmt.Errorf("failed to marshal affected_services: %w", err)
+	}
 
 	query := `
 		UPDATE outages
-		SET title = $2, description = $3, status = $4, severity = $5, updated_at = $6, resolved_at = $7,
-		    metadata = $8, custom_fields = $9
+		SET title = $2, description = $3, status = $4, severity = $5, updated_at = $6,
+		    investigating_at = $7, mitigated_at = $8, resolved_at = $9,
+		    metadata = $10, custom_fields = $11, owning_team = $12,
+		    affected_services = $13, customer_impact = $14, impact_started_at = $15, impact_ended_at = $16
 		WHERE id = $1
 	`
 	result, err := s.db.ExecContext(ctx, query,
 		outage.ID, outage.Title, outage.Description, outage.Status,
-		outage.Severity, outage.UpdatedAt, outage.ResolvedAt,
-		metadataJSON, customFieldsJSON,
+		outage.Severity, outage.UpdatedAt,
+		outage.InvestigatingAt, outage.MitigatedAt, outage.ResolvedAt,
+		metadataJSON, customFieldsJSON, outage.OwningTeam,
+		servicesJSON, outage.CustomerImpact, outage.ImpactStartedAt, outage.ImpactEndedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update outage: %w", err)
@@ -187,6 +296,90 @@ func (s *PostgresStorage) UpdateOutage(ctx context.Context, outage *domain.Outag
 	return nil
 }
 
+// SetOutageParent links or unlinks an outage's parent in one transaction.
+// The outage and its new parent are locked in id order first, so a link
+// touching either waits for this one and then sees it.
+func (s *PostgresStorage) SetOutageParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, updatedAt time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set outage parent: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	locked := []uuid.UUID{id}
+	if parentID != nil {
+		locked = append(locked, *parentID)
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM outages WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(locked))
+	if err != nil {
+		return fmt.Errorf("failed to lock outages: %w", err)
+	}
+	_ = rows.Close()
+
+	if parentID != nil {
+		var parent outageParentCheck
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM outages WHERE id = $1),
+			       EXISTS (SELECT 1 FROM outages WHERE id = $2),
+			       (SELECT deleted_at IS NOT NULL FROM outages WHERE id = $2),
+			       (SELECT parent_id FROM outages WHERE id = $2),
+			       EXISTS (SELECT 1 FROM outages WHERE parent_id = $1 AND deleted_at IS NULL)
+		`, id, *parentID).Scan(&parent.outageExists, &parent.exists, &parent.trashed, &parent.parentID, &parent.hasChildren)
+		if err != nil {
+			return fmt.Errorf("failed to check outage parent: %w", err)
+		}
+		if err := parent.check(id, *parentID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE outages SET parent_id = $2, updated_at = $3 WHERE id = $1`, id, parentID, updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set outage parent: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to set outage parent: %w", err)
+	}
+	return nil
+}
+
+// outageParentCheck holds what SetOutageParent reads about an outage and
+// its new parent
+type outageParentCheck struct {
+	outageExists bool
+	exists       bool
+	trashed      sql.NullBool
+	parentID     uuid.NullUUID
+	hasChildren  bool
+}
+
+// check returns why the outage cannot become a child of parentID, if it
+// cannot
+func (c outageParentCheck) check(id, parentID uuid.UUID) error {
+	switch {
+	case !c.outageExists:
+		return fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
+	case id == parentID:
+		return fmt.Errorf("an outage cannot be its own parent: %w", domain.ErrInvalidInput)
+	case !c.exists:
+		return fmt.Errorf("parent outage %s does not exist: %w", parentID, domain.ErrInvalidInput)
+	case c.trashed.Bool:
+		return fmt.Errorf("parent outage %s is in the trash: %w", parentID, domain.ErrInvalidInput)
+	case c.parentID.Valid:
+		return fmt.Errorf("outage %s is itself a child of %s and cannot be a parent: %w", parentID, c.parentID.UUID, domain.ErrInvalidInput)
+	case c.hasChildren:
+		return fmt.Errorf("outage %s has child outages and cannot be a child: %w", id, domain.ErrInvalidInput)
+	}
+	return nil
+}
+
 // DeleteOutage deletes an outage by ID
 func (s *PostgresStorage) DeleteOutage(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM outages WHERE id = $1`
@@ -205,3 +398,45 @@ func (s *PostgresStorage) DeleteOutage(ctx context.Context, id uuid.UUID) error
 
 	return nil
 }
+
+// TrashOutage moves an outage to the trash
+func (s *PostgresStorage) TrashOutage(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE outages SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
+	return s.setOutageDeletedAt(ctx, query, id, at)
+}
+
+// RestoreOutage takes an outage out of the trash
+func (s *PostgresStorage) RestoreOutage(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE outages SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	return s.setOutageDeletedAt(ctx, query, id)
+}
+
+// setOutageDeletedAt runs a trash or restore query, reporting
+// domain.ErrNotFound when it matched no outage
+func (s *PostgresStorage) setOutageDeletedAt(ctx context.Context, query string, id uuid.UUID, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, append([]any{id}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update outage deleted_at: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// PurgeOutages permanently deletes outages trashed before deletedBefore
+func (s *PostgresStorage) PurgeOutages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM outages WHERE deleted_at < $1`, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outages: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
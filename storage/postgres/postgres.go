@@ -4,18 +4,39 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 
 	"github.com/conall/outalator/config"
 )
 
+// uniqueViolation is the PostgreSQL error code for a unique constraint
+// violation
+const uniqueViolation = "23505"
+
+// Connection pool and query limits used when the configuration leaves them
+// unset
+const (
+	defaultMaxOpenConns     = 25
+	defaultMaxIdleConns     = 5
+	defaultConnMaxLifetime  = 30 * time.Minute
+	defaultStatementTimeout = 30 * time.Second
+)
+
 // PostgresStorage implements the Storage interface using PostgreSQL
 type PostgresStorage struct {
 	db *sql.DB
 }
 
+// querier runs statements on the database or within a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // Config holds PostgreSQL connection configuration
 type Config struct {
 	Host     string
@@ -24,6 +45,11 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	MaxOpenConns     int           // Zero uses defaultMaxOpenConns
+	MaxIdleConns     int           // Zero uses defaultMaxIdleConns
+	ConnMaxLifetime  time.Duration // Zero uses defaultConnMaxLifetime
+	StatementTimeout time.Duration // Zero uses defaultStatementTimeout; negative disables the timeout
 }
 
 // NewStorage creates a PostgresStorage from an application DatabaseConfig.
@@ -35,20 +61,25 @@ func NewStorage(cfg config.DatabaseConfig) (*PostgresStorage, error) {
 		Password: cfg.Password,
 		DBName:   cfg.DBName,
 		SSLMode:  cfg.SSLMode,
+
+		MaxOpenConns:     cfg.MaxOpenConns,
+		MaxIdleConns:     cfg.MaxIdleConns,
+		ConnMaxLifetime:  cfg.ConnMaxLifetime,
+		StatementTimeout: cfg.StatementTimeout,
 	})
 }
 
 // New creates a new PostgreSQL storage instance
 func New(cfg Config) (*PostgresStorage, error) {
-	connStr := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
-	)
+	cfg = cfg.withDefaults()
 
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", cfg.connString())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	if err := db.PingContext(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
@@ -57,11 +88,163 @@ func New(cfg Config) (*PostgresStorage, error) {
 	return &PostgresStorage{db: db}, nil
 }
 
+// withDefaults fills in the pool and query limits left unset. Idle
+// connections are capped at the open connection limit, as database/sql
+// would otherwise do silently.
+func (cfg Config) withDefaults() Config {
+	if cfg.MaxOpenConns <= 0 {
+		cfg.MaxOpenConns = defaultMaxOpenConns
+	}
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = defaultMaxIdleConns
+	}
+	if cfg.MaxIdleConns > cfg.MaxOpenConns {
+		cfg.MaxIdleConns = cfg.MaxOpenConns
+	}
+	if cfg.ConnMaxLifetime <= 0 {
+		cfg.ConnMaxLifetime = defaultConnMaxLifetime
+	}
+	if cfg.StatementTimeout == 0 {
+		cfg.StatementTimeout = defaultStatementTimeout
+	}
+	return cfg
+}
+
+// connString builds the lib/pq connection string. The statement timeout is
+// set as a session parameter so the server cancels queries that run too
+// long on every pooled connection.
+func (cfg Config) connString() string {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
+	)
+	if cfg.StatementTimeout > 0 {
+		connStr += fmt.Sprintf(" statement_timeout=%d", cfg.StatementTimeout.Milliseconds())
+	}
+	return connStr
+}
+
+// Ping checks the database can be reached
+func (s *PostgresStorage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// migrationColumns names a column each migration adds, so CheckSchema can
+// tell whether it has been applied. New migrations add an entry here.
+var migrationColumns = []struct{ migration, table, column string }{
+	{"001_initial_schema", "tags", "outage_id"},
+	{"002_add_custom_fields", "tags", "custom_fields"},
+	{"003_add_outage_status_changes", "outage_status_changes", "outage_id"},
+	{"004_add_user_preferences", "user_preferences", "subject"},
+	{"005_add_outage_reviews", "outage_reviews", "outage_id"},
+	{"006_add_alert_events", "alert_events", "alert_id"},
+	{"007_add_alert_sync_cursors", "alert_sync_cursors", "source"},
+	{"008_add_config_resources", "config_resources", "kind"},
+	{"009_add_source_ingestion", "source_ingestion", "source"},
+	{"010_add_outage_state_machine", "outage_status_changes", "reason"},
+	{"011_add_soft_delete", "notes", "deleted_at"},
+	{"012_add_attachments", "attachments", "outage_id"},
+	{"013_add_note_threads", "notes", "parent_note_id"},
+	{"014_add_note_revisions", "note_revisions", "note_id"},
+	{"015_add_outage_owning_team", "outages", "owning_team"},
+	{"016_add_responder_assignments", "responder_assignments", "unassigned_at"},
+	{"017_add_processed_events", "processed_events", "processed_at"},
+	{"018_add_import_runs", "import_runs", "resume_offset"},
+	{"019_add_alert_team_names", "alerts", "team_names"},
+	{"020_add_saved_views", "saved_views", "summary_period"},
+	{"021_add_action_items", "action_items", "closed_at"},
+	{"022_add_outage_impact", "outages", "impact_started_at"},
+	{"023_extend_service_catalogue", "services", "owning_team"},
+	{"024_add_alert_fingerprints", "alerts", "fingerprint"},
+	{"025_add_watchers", "watchers", "channel"},
+	{"026_add_export_watermarks", "export_watermarks", "exported_until"},
+	{"027_add_retention_runs", "retention_runs", "archive_key"},
+	{"029_add_outage_parents", "outages", "parent_id"},
+	{"030_add_outage_relations", "outage_relations", "related_outage_id"},
+	{"031_add_unique_tag_keys", "tags", "unique_key"},
+}
+
+// migrationConstraints names a constraint each migration that adds no
+// columns creates, for CheckSchema. Such migrations add an entry here.
+var migrationConstraints = []struct{ migration, table, constraint string }{
+	{"028_add_input_constraints", "tags", "tags_key_not_blank"},
+}
+
+// CheckSchema checks every migration has been applied, returning an error
+// naming the first that has not
+func (s *PostgresStorage) CheckSchema(ctx context.Context) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = current_schema()`)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns := make(map[[2]string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return fmt.Errorf("failed to read schema: %w", err)
+		}
+		columns[[2]string{table, column}] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	for _, m := range migrationColumns {
+		if !columns[[2]string{m.table, m.column}] {
+			return fmt.Errorf("migration %s not applied: %s.%s is missing", m.migration, m.table, m.column)
+		}
+	}
+
+	constraints, err := s.schemaConstraints(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrationConstraints {
+		if !constraints[[2]string{m.table, m.constraint}] {
+			return fmt.Errorf("migration %s not applied: constraint %s on %s is missing", m.migration, m.constraint, m.table)
+		}
+	}
+	return nil
+}
+
+// schemaConstraints lists the named constraints of the current schema's
+// tables
+func (s *PostgresStorage) schemaConstraints(ctx context.Context) (map[[2]string]bool, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT table_name, constraint_name FROM information_schema.table_constraints WHERE table_schema = current_schema()`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	constraints := make(map[[2]string]bool)
+	for rows.Next() {
+		var table, constraint string
+		if err := rows.Scan(&table, &constraint); err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		constraints[[2]string{table, constraint}] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	return constraints, nil
+}
+
 // Close closes the database connection
 func (s *PostgresStorage) Close() error {
 	return s.db.Close()
 }
 
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
 // marshalJSONMap safely marshals a map, returning {} for nil maps instead of null
 func marshalJSONMap(m map[string]string) ([]byte, error) {
 	if m == nil {
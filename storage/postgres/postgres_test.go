@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMarshalJSONMap(t *testing.T) {
@@ -168,3 +170,34 @@ func TestMarshalConsistency(t *testing.T) {
 		}
 	})
 }
+
+func TestConfigWithDefaults(t *testing.T) {
+	cfg := Config{}.withDefaults()
+	if cfg.MaxOpenConns != defaultMaxOpenConns || cfg.MaxIdleConns != defaultMaxIdleConns ||
+		cfg.ConnMaxLifetime != defaultConnMaxLifetime || cfg.StatementTimeout != defaultStatementTimeout {
+		t.Errorf("withDefaults() = %+v", cfg)
+	}
+
+	cfg = Config{MaxOpenConns: 3, MaxIdleConns: 10, StatementTimeout: -1}.withDefaults()
+	if cfg.MaxIdleConns != 3 {
+		t.Errorf("MaxIdleConns = %d, want it capped at MaxOpenConns", cfg.MaxIdleConns)
+	}
+	if cfg.StatementTimeout >= 0 {
+		t.Errorf("StatementTimeout = %v, want a negative timeout left disabled", cfg.StatementTimeout)
+	}
+}
+
+func TestConfigConnString(t *testing.T) {
+	cfg := Config{Host: "db", Port: 5432, User: "u", Password: "p", DBName: "outalator", SSLMode: "disable"}
+
+	withTimeout := cfg
+	withTimeout.StatementTimeout = 1500 * time.Millisecond
+	if got := withTimeout.connString(); !strings.HasSuffix(got, " statement_timeout=1500") {
+		t.Errorf("connString() = %q, want the statement timeout in milliseconds", got)
+	}
+
+	cfg.StatementTimeout = -1
+	if got := cfg.connString(); strings.Contains(got, "statement_timeout") {
+		t.Errorf("connString() = %q, want no statement timeout when disabled", got)
+	}
+}
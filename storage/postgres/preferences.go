@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+)
+
+// GetUserPreferences retrieves the saved preferences for an OIDC subject
+func (s *PostgresStorage) GetUserPreferences(ctx context.Context, subject string) (*domain.UserPreferences, error) {
+	query := `
+		SELECT subject, timezone, default_team_filter, notifications, digest_opt_in, updated_at
+		FROM user_preferences
+		WHERE subject = $1
+	`
+	prefs := &domain.UserPreferences{}
+	var notificationsJSON []byte
+	err := s.db.QueryRowContext(ctx, query, subject).Scan(
+		&prefs.Subject, &prefs.Timezone, &prefs.DefaultTeamFilter,
+		&notificationsJSON, &prefs.DigestOptIn, &prefs.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("preferences for %s: %w", subject, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	if len(notificationsJSON) > 0 {
+		if err := json.Unmarshal(notificationsJSON, &prefs.Notifications); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notifications: %w", err)
+		}
+	}
+
+	return prefs, nil
+}
+
+// UpsertUserPreferences creates or replaces the preferences for an OIDC subject
+func (s *PostgresStorage) UpsertUserPreferences(ctx context.Context, prefs *domain.UserPreferences) error {
+	notificationsJSON, err := json.Marshal(prefs.Notifications)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notifications: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_preferences (subject, timezone, default_team_filter, notifications, digest_opt_in, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (subject) DO UPDATE SET
+			timezone = EXCLUDED.timezone,
+			default_team_filter = EXCLUDED.default_team_filter,
+			notifications = EXCLUDED.notifications,
+			digest_opt_in = EXCLUDED.digest_opt_in,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err = s.db.ExecContext(ctx, query,
+		prefs.Subject, prefs.Timezone, prefs.DefaultTeamFilter,
+		notificationsJSON, prefs.DigestOptIn, prefs.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
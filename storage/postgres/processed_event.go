@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+)
+
+// CreateProcessedEvent records that an event has been processed
+func (s *PostgresStorage) CreateProcessedEvent(ctx context.Context, event *domain.ProcessedEvent) error {
+	query := `
+		INSERT INTO processed_events (source, event_id, processed_at)
+		VALUES ($1, $2, $3)
+	`
+	_, err := s.db.ExecContext(ctx, query, event.Source, event.EventID, event.ProcessedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("event %s from %s already processed: %w", event.EventID, event.Source, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record processed event: %w", err)
+	}
+	return nil
+}
+
+// PurgeProcessedEvents deletes the events from source processed before
+// processedBefore
+func (s *PostgresStorage) PurgeProcessedEvents(ctx context.Context, source string, processedBefore time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM processed_events WHERE source = $1 AND processed_at < $2`, source, processedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge processed events: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const outageRelationColumns = `id, outage_id, related_outage_id, type, description, created_by, created_at`
+
+// CreateOutageRelation links two outages
+func (s *PostgresStorage) CreateOutageRelation(ctx context.Context, relation *domain.OutageRelation) error {
+	query := `INSERT INTO outage_relations (` + outageRelationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		relation.ID, relation.OutageID, relation.RelatedOutageID, relation.Type,
+		relation.Description, relation.CreatedBy, relation.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage %s is already %s %s: %w", relation.OutageID, relation.Type, relation.RelatedOutageID, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create outage relation: %w", err)
+	}
+	return nil
+}
+
+// GetOutageRelation retrieves an outage relation by ID
+func (s *PostgresStorage) GetOutageRelation(ctx context.Context, id uuid.UUID) (*domain.OutageRelation, error) {
+	query := `SELECT ` + outageRelationColumns + ` FROM outage_relations WHERE id = $1`
+	relation, err := scanOutageRelation(s.db.QueryRowContext(ctx, query, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("outage relation %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outage relation: %w", err)
+	}
+	return relation, nil
+}
+
+// ListOutageRelations retrieves the relations with the outage at either
+// end, oldest first, joining the outage at the other end for its title and
+// leaving out relations to outages in the trash
+func (s *PostgresStorage) ListOutageRelations(ctx context.Context, outageID uuid.UUID) ([]*domain.OutageRelation, error) {
+	query := `SELECT r.id, r.outage_id, r.related_outage_id, r.type, r.description, r.created_by, r.created_at, o.title
+		FROM outage_relations r
+		JOIN outages o ON o.id = CASE WHEN r.outage_id = $1 THEN r.related_outage_id ELSE r.outage_id END
+		WHERE (r.outage_id = $1 OR r.related_outage_id = $1) AND o.deleted_at IS NULL
+		ORDER BY r.created_at ASC, r.id ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outage relations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var relations []*domain.OutageRelation
+	for rows.Next() {
+		var title string
+		relation, err := scanOutageRelation(func(dest ...any) error {
+			return rows.Scan(append(dest, &title)...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outage relation: %w", err)
+		}
+		relation.RelatedOutageTitle = title
+		relations = append(relations, relation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outage relations: %w", err)
+	}
+
+	return relations, nil
+}
+
+// DeleteOutageRelation removes a link between two outages
+func (s *PostgresStorage) DeleteOutageRelation(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM outage_relations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete outage relation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("outage relation %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// scanOutageRelation scans a row of outageRelationColumns
+func scanOutageRelation(scan func(dest ...any) error) (*domain.OutageRelation, error) {
+	relation := &domain.OutageRelation{}
+	if err := scan(
+		&relation.ID, &relation.OutageID, &relation.RelatedOutageID, &relation.Type,
+		&relation.Description, &relation.CreatedBy, &relation.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return relation, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const responderAssignmentColumns = `
+	id, outage_id, role, responder, source, schedule_id,
+	assigned_by, assigned_at, unassigned_by, unassigned_at
+`
+
+// CreateResponderAssignment records a responder taking a role on an outage
+func (s *PostgresStorage) CreateResponderAssignment(ctx context.Context, a *domain.ResponderAssignment) error {
+	query := `INSERT INTO responder_assignments (` + responderAssignmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		a.ID, a.OutageID, a.Role, a.Responder, a.Source, a.ScheduleID,
+		a.AssignedBy, a.AssignedAt, a.UnassignedBy, a.UnassignedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage %s already has a %s: %w", a.OutageID, a.Role, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create responder assignment: %w", err)
+	}
+	return nil
+}
+
+// EndResponderAssignment records that a responder stopped holding a role
+func (s *PostgresStorage) EndResponderAssignment(ctx context.Context, id uuid.UUID, by string, at time.Time) error {
+	query := `
+		UPDATE responder_assignments SET unassigned_by = $2, unassigned_at = $3
+		WHERE id = $1 AND unassigned_at IS NULL
+	`
+	result, err := s.db.ExecContext(ctx, query, id, by, at)
+	if err != nil {
+		return fmt.Errorf("failed to end responder assignment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("responder assignment %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// ListResponderAssignments retrieves an outage's responder assignments,
+// oldest first
+func (s *PostgresStorage) ListResponderAssignments(ctx context.Context, outageID uuid.UUID) ([]*domain.ResponderAssignment, error) {
+	query := `SELECT ` + responderAssignmentColumns + `
+		FROM responder_assignments
+		WHERE outage_id = $1
+		ORDER BY assigned_at ASC
+	`
+	return s.queryResponderAssignments(ctx, query, outageID)
+}
+
+// ListResponderAssignmentsBetween retrieves the responder assignments made
+// at or after since and before until, oldest first
+func (s *PostgresStorage) ListResponderAssignmentsBetween(ctx context.Context, since, until time.Time) ([]*domain.ResponderAssignment, error) {
+	query := `SELECT ` + responderAssignmentColumns + `
+		FROM responder_assignments
+		WHERE assigned_at >= $1 AND assigned_at < $2
+		ORDER BY assigned_at ASC
+	`
+	return s.queryResponderAssignments(ctx, query, since, until)
+}
+
+func (s *PostgresStorage) queryResponderAssignments(ctx context.Context, query string, args ...any) ([]*domain.ResponderAssignment, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list responder assignments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var assignments []*domain.ResponderAssignment
+	for rows.Next() {
+		a := &domain.ResponderAssignment{}
+		if err := rows.Scan(
+			&a.ID, &a.OutageID, &a.Role, &a.Responder, &a.Source, &a.ScheduleID,
+			&a.AssignedBy, &a.AssignedAt, &a.UnassignedBy, &a.UnassignedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan responder assignment: %w", err)
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating responder assignments: %w", err)
+	}
+
+	return assignments, nil
+}
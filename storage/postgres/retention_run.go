@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const retentionRunColumns = `id, run_trigger, triggered_by, status, archived_before, archived_outages,
+		archive_key, archive_purged_at, purged_archives, error, started_at, finished_at`
+
+// CreateRetentionRun records a new retention run
+func (s *PostgresStorage) CreateRetentionRun(ctx context.Context, run *domain.RetentionRun) error {
+	query := `
+		INSERT INTO retention_runs (` + retentionRunColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		run.ID, run.Trigger, run.TriggeredBy, run.Status, run.ArchivedBefore, run.ArchivedOutages,
+		run.ArchiveKey, run.ArchivePurgedAt, run.PurgedArchives, run.Error, run.StartedAt, run.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create retention run: %w", err)
+	}
+	return nil
+}
+
+// UpdateRetentionRun saves the outcome of a retention run, and whether its
+// archive has been purged
+func (s *PostgresStorage) UpdateRetentionRun(ctx context.Context, run *domain.RetentionRun) error {
+	query := `
+		UPDATE retention_runs
+		SET status = $2, archived_outages = $3, archive_key = $4, archive_purged_at = $5,
+			purged_archives = $6, error = $7, finished_at = $8
+		WHERE id = $1
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		run.ID, run.Status, run.ArchivedOutages, run.ArchiveKey, run.ArchivePurgedAt,
+		run.PurgedArchives, run.Error, run.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update retention run: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("retention run %s: %w", run.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// GetRetentionRun retrieves a retention run by ID
+func (s *PostgresStorage) GetRetentionRun(ctx context.Context, id uuid.UUID) (*domain.RetentionRun, error) {
+	query := `SELECT ` + retentionRunColumns + ` FROM retention_runs WHERE id = $1`
+	run, err := scanRetentionRun(s.db.QueryRowContext(ctx, query, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("retention run %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retention run: %w", err)
+	}
+	return run, nil
+}
+
+// ListRetentionRuns lists retention runs, most recently started first
+func (s *PostgresStorage) ListRetentionRuns(ctx context.Context) ([]*domain.RetentionRun, error) {
+	query := `SELECT ` + retentionRunColumns + ` FROM retention_runs ORDER BY started_at DESC`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []*domain.RetentionRun
+	for rows.Next() {
+		run, err := scanRetentionRun(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan retention run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating retention runs: %w", err)
+	}
+	return runs, nil
+}
+
+// scanRetentionRun scans a row of retentionRunColumns
+func scanRetentionRun(scan func(dest ...any) error) (*domain.RetentionRun, error) {
+	run := &domain.RetentionRun{}
+	if err := scan(
+		&run.ID, &run.Trigger, &run.TriggeredBy, &run.Status, &run.ArchivedBefore, &run.ArchivedOutages,
+		&run.ArchiveKey, &run.ArchivePurgedAt, &run.PurgedArchives, &run.Error, &run.StartedAt, &run.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// GetOutageReview retrieves the review state for an outage
+func (s *PostgresStorage) GetOutageReview(ctx context.Context, outageID uuid.UUID) (*domain.OutageReview, error) {
+	query := `
+		SELECT outage_id, status, scheduled_for, reviewed_at, reviewer, created_at, updated_at
+		FROM outage_reviews
+		WHERE outage_id = $1
+	`
+	review := &domain.OutageReview{}
+	err := s.db.QueryRowContext(ctx, query, outageID).Scan(
+		&review.OutageID, &review.Status, &review.ScheduledFor, &review.ReviewedAt,
+		&review.Reviewer, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("review for outage %s: %w", outageID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outage review: %w", err)
+	}
+	return review, nil
+}
+
+// UpsertOutageReview creates or replaces the review state for an outage
+func (s *PostgresStorage) UpsertOutageReview(ctx context.Context, review *domain.OutageReview) error {
+	query := `
+		INSERT INTO outage_reviews (outage_id, status, scheduled_for, reviewed_at, reviewer, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (outage_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			scheduled_for = EXCLUDED.scheduled_for,
+			reviewed_at = EXCLUDED.reviewed_at,
+			reviewer = EXCLUDED.reviewer,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		review.OutageID, review.Status, review.ScheduledFor, review.ReviewedAt,
+		review.Reviewer, review.CreatedAt, review.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save outage review: %w", err)
+	}
+	return nil
+}
+
+// ListOutageReviews retrieves outage reviews, optionally filtered by status,
+// oldest first
+func (s *PostgresStorage) ListOutageReviews(ctx context.Context, status string) ([]*domain.OutageReview, error) {
+	query := `
+		SELECT outage_id, status, scheduled_for, reviewed_at, reviewer, created_at, updated_at
+		FROM outage_reviews
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outage reviews: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reviews []*domain.OutageReview
+	for rows.Next() {
+		review := &domain.OutageReview{}
+		if err := rows.Scan(
+			&review.OutageID, &review.Status, &review.ScheduledFor, &review.ReviewedAt,
+			&review.Reviewer, &review.CreatedAt, &review.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan outage review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outage reviews: %w", err)
+	}
+
+	return reviews, nil
+}
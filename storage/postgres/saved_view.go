@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const savedViewColumns = `id, name, owner, filter, slack_channel, summary_period, last_summary_at,
+		created_at, updated_at`
+
+// CreateSavedView saves a new view
+func (s *PostgresStorage) CreateSavedView(ctx context.Context, view *domain.SavedView) error {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal view filter: %w", err)
+	}
+	query := `
+		INSERT INTO saved_views (` + savedViewColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err = s.db.ExecContext(ctx, query,
+		view.ID, view.Name, view.Owner, filter, view.SlackChannel, view.SummaryPeriod, view.LastSummaryAt,
+		view.CreatedAt, view.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("view %q already exists: %w", view.Name, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create saved view: %w", err)
+	}
+	return nil
+}
+
+// GetSavedView retrieves a saved view by ID
+func (s *PostgresStorage) GetSavedView(ctx context.Context, id uuid.UUID) (*domain.SavedView, error) {
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views WHERE id = $1`
+	view, err := scanSavedView(s.db.QueryRowContext(ctx, query, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("view %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+	return view, nil
+}
+
+// ListSavedViews lists every saved view ordered by name
+func (s *PostgresStorage) ListSavedViews(ctx context.Context) ([]*domain.SavedView, error) {
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views ORDER BY name`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var views []*domain.SavedView
+	for rows.Next() {
+		view, err := scanSavedView(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved view: %w", err)
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating saved views: %w", err)
+	}
+	return views, nil
+}
+
+// UpdateSavedView saves a view's name, filter, subscription and last
+// summary time
+func (s *PostgresStorage) UpdateSavedView(ctx context.Context, view *domain.SavedView) error {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal view filter: %w", err)
+	}
+	query := `
+		UPDATE saved_views
+		SET name = $2, filter = $3, slack_channel = $4, summary_period = $5, last_summary_at = $6,
+			updated_at = $7
+		WHERE id = $1
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		view.ID, view.Name, filter, view.SlackChannel, view.SummaryPeriod, view.LastSummaryAt, view.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("view %q already exists: %w", view.Name, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update saved view: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("view %s: %w", view.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteSavedView deletes a saved view
+func (s *PostgresStorage) DeleteSavedView(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("view %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// scanSavedView scans a row of savedViewColumns
+func scanSavedView(scan func(dest ...any) error) (*domain.SavedView, error) {
+	view := &domain.SavedView{}
+	var filter []byte
+	if err := scan(
+		&view.ID, &view.Name, &view.Owner, &filter, &view.SlackChannel, &view.SummaryPeriod, &view.LastSummaryAt,
+		&view.CreatedAt, &view.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filter, &view.Filter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal view filter: %w", err)
+	}
+	return view, nil
+}
@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // CreateTag creates a new tag in the database
@@ -20,13 +22,16 @@ func (s *PostgresStorage) CreateTag(ctx context.Context, tag *domain.Tag) error
 	}
 
 	query := `
-		INSERT INTO tags (id, outage_id, key, value, created_at, custom_fields)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO tags (id, outage_id, key, value, created_at, custom_fields, unique_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 	_, err = s.db.ExecContext(ctx, query,
 		tag.ID, tag.OutageID, tag.Key, tag.Value, tag.CreatedAt,
-		customFieldsJSON,
+		customFieldsJSON, tag.UniqueKey,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage %s already has a %s tag: %w", tag.OutageID, tag.Key, domain.ErrConflict)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create tag: %w", err)
 	}
@@ -36,7 +41,7 @@ func (s *PostgresStorage) CreateTag(ctx context.Context, tag *domain.Tag) error
 // GetTag retrieves a tag by ID
 func (s *PostgresStorage) GetTag(ctx context.Context, id uuid.UUID) (*domain.Tag, error) {
 	query := `
-		SELECT id, outage_id, key, value, created_at, custom_fields
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
 		FROM tags
 		WHERE id = $1
 	`
@@ -44,7 +49,7 @@ func (s *PostgresStorage) GetTag(ctx context.Context, id uuid.UUID) (*domain.Tag
 	var customFieldsJSON []byte
 	err := s.db.QueryRowContext(ctx, query, id).Scan(
 		&tag.ID, &tag.OutageID, &tag.Key, &tag.Value, &tag.CreatedAt,
-		&customFieldsJSON,
+		&customFieldsJSON, &tag.UniqueKey,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("tag %s: %w", id, domain.ErrNotFound)
@@ -63,15 +68,70 @@ func (s *PostgresStorage) GetTag(ctx context.Context, id uuid.UUID) (*domain.Tag
 	return tag, nil
 }
 
+// UpdateTag updates a tag's key, value, custom fields and unique key flag
+func (s *PostgresStorage) UpdateTag(ctx context.Context, tag *domain.Tag) error {
+	customFieldsJSON, err := marshalJSONAny(tag.CustomFields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom_fields: %w", err)
+	}
+
+	query := `UPDATE tags SET key = $2, value = $3, custom_fields = $4, unique_key = $5 WHERE id = $1`
+	result, err := s.db.ExecContext(ctx, query, tag.ID, tag.Key, tag.Value, customFieldsJSON, tag.UniqueKey)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage already has a %s tag: %w", tag.Key, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("tag %s: %w", tag.ID, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
 // ListTagsByOutage retrieves all tags for a specific outage
 func (s *PostgresStorage) ListTagsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Tag, error) {
 	query := `
-		SELECT id, outage_id, key, value, created_at, custom_fields
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
 		FROM tags
 		WHERE outage_id = $1
 		ORDER BY created_at DESC
 	`
-	rows, err := s.db.QueryContext(ctx, query, outageID)
+	return s.queryTags(ctx, query, outageID)
+}
+
+// listTagsByOutages retrieves the tags of several outages in one query,
+// ordered as ListTagsByOutage orders them
+func (s *PostgresStorage) listTagsByOutages(ctx context.Context, outageIDs []uuid.UUID) ([]*domain.Tag, error) {
+	query := `
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
+		FROM tags
+		WHERE outage_id = ANY($1)
+		ORDER BY created_at DESC
+	`
+	return s.queryTags(ctx, query, pq.Array(outageIDs))
+}
+
+// ListTagsByKey retrieves all tags with the given key across outages, oldest first
+func (s *PostgresStorage) ListTagsByKey(ctx context.Context, key string) ([]*domain.Tag, error) {
+	query := `
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
+		FROM tags
+		WHERE key = $1
+		ORDER BY created_at ASC
+	`
+	return s.queryTags(ctx, query, key)
+}
+
+// queryTags runs a query selecting tag columns and scans the results
+func (s *PostgresStorage) queryTags(ctx context.Context, query string, args ...any) ([]*domain.Tag, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tags: %w", err)
 	}
@@ -83,7 +143,7 @@ func (s *PostgresStorage) ListTagsByOutage(ctx context.Context, outageID uuid.UU
 		var customFieldsJSON []byte
 		err := rows.Scan(
 			&tag.ID, &tag.OutageID, &tag.Key, &tag.Value, &tag.CreatedAt,
-			&customFieldsJSON,
+			&customFieldsJSON, &tag.UniqueKey,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan tag: %w", err)
@@ -98,6 +158,9 @@ func (s *PostgresStorage) ListTagsByOutage(ctx context.Context, outageID uuid.UU
 
 		tags = append(tags, tag)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
 
 	return tags, nil
 }
@@ -124,14 +187,50 @@ func (s *PostgresStorage) DeleteTag(ctx context.Context, id uuid.UUID) error {
 // FindOutagesByTag finds outages that have a specific tag key-value pair
 func (s *PostgresStorage) FindOutagesByTag(ctx context.Context, key, value string) ([]*domain.Outage, error) {
 	query := `
-		SELECT DISTINCT o.id, o.title, o.description, o.status, o.severity,
-		       o.created_at, o.updated_at, o.resolved_at, o.metadata, o.custom_fields
+		SELECT DISTINCT o.id, o.title, o.description, o.status, o.severity, o.owning_team,
+		       o.created_at, o.updated_at, o.investigating_at, o.mitigated_at, o.resolved_at, o.deleted_at, o.metadata, o.custom_fields,
+		       o.affected_services, o.customer_impact, o.impact_started_at, o.impact_ended_at, o.parent_id
 		FROM outages o
 		INNER JOIN tags t ON o.id = t.outage_id
-		WHERE t.key = $1 AND t.value = $2
+		WHERE t.key = $1 AND t.value = $2 AND o.deleted_at IS NULL
+		ORDER BY o.created_at DESC
+	`
+	return s.queryTaggedOutages(ctx, query, key, value)
+}
+
+// FindOutagesByTagQuery finds the outages matching every term of the query.
+// Each term is an EXISTS subquery served by the tags(key, value) index.
+func (s *PostgresStorage) FindOutagesByTagQuery(ctx context.Context, q domain.TagQuery) ([]*domain.Outage, error) {
+	where := []string{"o.deleted_at IS NULL"}
+	var args []any
+	for _, term := range q.Terms {
+		args = append(args, term.Key)
+		cond := fmt.Sprintf("t.key = $%d", len(args))
+		if len(term.Values) > 0 {
+			args = append(args, pq.Array(term.Values))
+			cond += fmt.Sprintf(" AND t.value = ANY($%d)", len(args))
+		}
+		exists := "EXISTS (SELECT 1 FROM tags t WHERE t.outage_id = o.id AND " + cond + ")"
+		if term.Negate {
+			exists = "NOT " + exists
+		}
+		where = append(where, exists)
+	}
+	query := `
+		SELECT o.id, o.title, o.description, o.status, o.severity, o.owning_team,
+		       o.created_at, o.updated_at, o.investigating_at, o.mitigated_at, o.resolved_at, o.deleted_at, o.metadata, o.custom_fields,
+		       o.affected_services, o.customer_impact, o.impact_started_at, o.impact_ended_at, o.parent_id
+		FROM outages o
+		WHERE ` + strings.Join(where, " AND ") + `
 		ORDER BY o.created_at DESC
 	`
-	rows, err := s.db.QueryContext(ctx, query, key, value)
+	return s.queryTaggedOutages(ctx, query, args...)
+}
+
+// queryTaggedOutages runs a query selecting outage columns and scans the
+// results
+func (s *PostgresStorage) queryTaggedOutages(ctx context.Context, query string, args ...any) ([]*domain.Outage, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find outages by tag: %w", err)
 	}
@@ -139,31 +238,66 @@ func (s *PostgresStorage) FindOutagesByTag(ctx context.Context, key, value strin
 
 	var outages []*domain.Outage
 	for rows.Next() {
-		outage := &domain.Outage{}
-		var metadataJSON, customFieldsJSON []byte
-		err := rows.Scan(
-			&outage.ID, &outage.Title, &outage.Description, &outage.Status,
-			&outage.Severity, &outage.CreatedAt, &outage.UpdatedAt, &outage.ResolvedAt,
-			&metadataJSON, &customFieldsJSON,
-		)
+		outage, err := scanOutage(rows.Scan)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan outage: %w", err)
 		}
-
-		// Unmarshal JSON fields
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &outage.Metadata); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-			}
-		}
-		if len(customFieldsJSON) > 0 {
-			if err := json.Unmarshal(customFieldsJSON, &outage.CustomFields); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
-			}
-		}
-
 		outages = append(outages, outage)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outages: %w", err)
+	}
 
 	return outages, nil
 }
+
+// ListTagKeys counts the tags with each key on outages not in the trash
+func (s *PostgresStorage) ListTagKeys(ctx context.Context) ([]domain.TagUsage, error) {
+	query := `
+		SELECT t.key, '', COUNT(*)
+		FROM tags t
+		INNER JOIN outages o ON o.id = t.outage_id
+		WHERE o.deleted_at IS NULL
+		GROUP BY t.key
+		ORDER BY COUNT(*) DESC, t.key
+	`
+	return s.queryTagUsage(ctx, query)
+}
+
+// ListTagValues counts the tags with each value of key on outages not in
+// the trash
+func (s *PostgresStorage) ListTagValues(ctx context.Context, key string) ([]domain.TagUsage, error) {
+	query := `
+		SELECT t.key, t.value, COUNT(*)
+		FROM tags t
+		INNER JOIN outages o ON o.id = t.outage_id
+		WHERE t.key = $1 AND o.deleted_at IS NULL
+		GROUP BY t.key, t.value
+		ORDER BY COUNT(*) DESC, t.value
+	`
+	return s.queryTagUsage(ctx, query, key)
+}
+
+// queryTagUsage runs a query selecting a key, value and count and scans the
+// results
+func (s *PostgresStorage) queryTagUsage(ctx context.Context, query string, args ...any) ([]domain.TagUsage, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var usage []domain.TagUsage
+	for rows.Next() {
+		var u domain.TagUsage
+		if err := rows.Scan(&u.Key, &u.Value, &u.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tag counts: %w", err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// SaveWatcher subscribes a user to an outage, or changes the channel of an
+// existing subscription
+func (s *PostgresStorage) SaveWatcher(ctx context.Context, watcher *domain.Watcher) error {
+	query := `
+		INSERT INTO watchers (outage_id, email, channel, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (outage_id, email) DO UPDATE SET channel = EXCLUDED.channel
+	`
+	_, err := s.db.ExecContext(ctx, query, watcher.OutageID, watcher.Email, watcher.Channel, watcher.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save watcher: %w", err)
+	}
+	return nil
+}
+
+// RemoveWatcher unsubscribes a user from an outage
+func (s *PostgresStorage) RemoveWatcher(ctx context.Context, outageID uuid.UUID, email string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM watchers WHERE outage_id = $1 AND email = $2`, outageID, email)
+	if err != nil {
+		return fmt.Errorf("failed to remove watcher: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("watcher %s of outage %s: %w", email, outageID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// ListWatchers retrieves an outage's watchers, oldest first
+func (s *PostgresStorage) ListWatchers(ctx context.Context, outageID uuid.UUID) ([]*domain.Watcher, error) {
+	query := `
+		SELECT outage_id, email, channel, created_at
+		FROM watchers
+		WHERE outage_id = $1
+		ORDER BY created_at ASC, email ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var watchers []*domain.Watcher
+	for rows.Next() {
+		w := &domain.Watcher{}
+		if err := rows.Scan(&w.OutageID, &w.Email, &w.Channel, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watcher: %w", err)
+		}
+		watchers = append(watchers, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watchers: %w", err)
+	}
+
+	return watchers, nil
+}
@@ -0,0 +1,165 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const actionItemColumns = `id, outage_id, description, assignee, due_date, status, created_by,
+		created_at, updated_at, closed_at`
+
+// CreateActionItem adds an action item to an outage.
+func (s *SQLiteStorage) CreateActionItem(ctx context.Context, item *domain.ActionItem) error {
+	query := `
+		INSERT INTO action_items (` + actionItemColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		item.ID.String(), item.OutageID.String(), item.Description, item.Assignee, item.DueDate, item.Status,
+		item.CreatedBy, item.CreatedAt, item.UpdatedAt, item.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create action item: %w", err)
+	}
+	return nil
+}
+
+// GetActionItem retrieves an action item by ID.
+func (s *SQLiteStorage) GetActionItem(ctx context.Context, id uuid.UUID) (*domain.ActionItem, error) {
+	query := `SELECT ` + actionItemColumns + ` FROM action_items WHERE id = ?`
+	item, err := scanActionItemRow(s.db.QueryRowContext(ctx, query, id.String()).Scan)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("action item %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action item: %w", err)
+	}
+	return item, nil
+}
+
+// ListActionItemsByOutage retrieves an outage's action items, oldest first.
+func (s *SQLiteStorage) ListActionItemsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.ActionItem, error) {
+	query := `SELECT ` + actionItemColumns + `
+		FROM action_items
+		WHERE outage_id = ?
+		ORDER BY created_at ASC
+	`
+	return s.queryActionItems(ctx, query, outageID.String())
+}
+
+// ListOverdueActionItems retrieves the open and in-progress action items due
+// before the given time, earliest due first. As in
+// ListAlertsTriggeredBetween, the time is compared after scanning.
+func (s *SQLiteStorage) ListOverdueActionItems(ctx context.Context, before time.Time) ([]*domain.ActionItem, error) {
+	query := `SELECT ` + actionItemColumns + `
+		FROM action_items
+		WHERE status IN (?, ?) AND due_date IS NOT NULL
+		ORDER BY due_date ASC
+	`
+	all, err := s.queryActionItems(ctx, query, domain.ActionItemOpen, domain.ActionItemInProgress)
+	if err != nil {
+		return nil, err
+	}
+	var items []*domain.ActionItem
+	for _, item := range all {
+		if item.DueDate.Before(before) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// UpdateActionItem saves an action item's description, assignee, due date,
+// status and closing time.
+func (s *SQLiteStorage) UpdateActionItem(ctx context.Context, item *domain.ActionItem) error {
+	query := `
+		UPDATE action_items
+		SET description = ?, assignee = ?, due_date = ?, status = ?, updated_at = ?, closed_at = ?
+		WHERE id = ?
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		item.Description, item.Assignee, item.DueDate, item.Status, item.UpdatedAt, item.ClosedAt, item.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update action item: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("action item %s: %w", item.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteActionItem deletes an action item.
+func (s *SQLiteStorage) DeleteActionItem(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM action_items WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete action item: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("action item %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) queryActionItems(ctx context.Context, query string, args ...any) ([]*domain.ActionItem, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list action items: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []*domain.ActionItem
+	for rows.Next() {
+		item, err := scanActionItemRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan action item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating action items: %w", err)
+	}
+	return items, nil
+}
+
+// scanActionItemRow populates an ActionItem from a single row of
+// actionItemColumns. Returns domain.ErrNotFound when the underlying error is
+// sql.ErrNoRows.
+func scanActionItemRow(scan scanFunc) (*domain.ActionItem, error) {
+	item := &domain.ActionItem{}
+	var idStr, outageIDStr string
+	if err := scan(
+		&idStr, &outageIDStr, &item.Description, &item.Assignee, &item.DueDate, &item.Status, &item.CreatedBy,
+		&item.CreatedAt, &item.UpdatedAt, &item.ClosedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var err error
+	if item.ID, err = uuid.Parse(idStr); err != nil {
+		return nil, fmt.Errorf("failed to parse action item id: %w", err)
+	}
+	if item.OutageID, err = uuid.Parse(outageIDStr); err != nil {
+		return nil, fmt.Errorf("failed to parse outage id: %w", err)
+	}
+	return item, nil
+}
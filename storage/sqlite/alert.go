@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
@@ -17,37 +19,116 @@ import (
 // in internal/storage/storage.go) and is therefore not implemented here,
 // consistent with the postgres backend.
 
-// CreateAlert creates a new alert in the database.
+// CreateAlert stores an alert, setting alert to the stored one when it
+// was already there.
 func (s *SQLiteStorage) CreateAlert(ctx context.Context, alert *domain.Alert) error {
+	stored, created, err := s.UpsertAlert(ctx, alert)
+	if err != nil {
+		return err
+	}
+	if !created {
+		*alert = *stored
+	}
+	return nil
+}
+
+// UpsertAlert inserts an alert, or fills in the lifecycle times of the
+// alert already stored with its source and external ID.
+func (s *SQLiteStorage) UpsertAlert(ctx context.Context, alert *domain.Alert) (*domain.Alert, bool, error) {
+	id, err := upsertAlert(ctx, s.db, alert)
+	if err != nil {
+		return nil, false, err
+	}
+	if id == alert.ID {
+		return alert, true, nil
+	}
+	stored, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, false, nil
+}
+
+// CreateOutageWithAlert creates an outage and upserts its alert in one
+// transaction. When the alert was already stored the outage is deleted
+// before the transaction commits, so it is never seen.
+func (s *SQLiteStorage) CreateOutageWithAlert(ctx context.Context, outage *domain.Outage, alert *domain.Alert) (*domain.Alert, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create outage: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := createOutage(ctx, tx, outage); err != nil {
+		return nil, false, err
+	}
+	alert.OutageID = outage.ID
+	id, err := upsertAlert(ctx, tx, alert)
+	if err != nil {
+		return nil, false, err
+	}
+	if id != alert.ID {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM outages WHERE id = ?`, outage.ID.String()); err != nil {
+			return nil, false, fmt.Errorf("failed to delete outage: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to create outage: %w", err)
+	}
+	if id == alert.ID {
+		return alert, true, nil
+	}
+	stored, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, false, nil
+}
+
+// upsertAlert upserts an alert with q, returning the stored alert's ID.
+func upsertAlert(ctx context.Context, q querier, alert *domain.Alert) (uuid.UUID, error) {
 	sourceMetadataJSON, err := marshalJSONAny(alert.SourceMetadata)
 	if err != nil {
-		return fmt.Errorf("failed to marshal source_metadata: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to marshal source_metadata: %w", err)
 	}
 	metadataJSON, err := marshalJSONMap(alert.Metadata)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 	customFieldsJSON, err := marshalJSONAny(alert.CustomFields)
 	if err != nil {
-		return fmt.Errorf("failed to marshal custom_fields: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to marshal custom_fields: %w", err)
+	}
+	teamNamesJSON, err := marshalNames(alert.TeamNames)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to marshal team_names: %w", err)
 	}
 
 	query := `
 		INSERT INTO alerts (id, outage_id, external_id, source, team_name, title, description,
 		                    severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		                    source_metadata, metadata, custom_fields)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		                    source_metadata, metadata, custom_fields, team_names, service, fingerprint)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (external_id, source) DO UPDATE SET
+			acknowledged_at = COALESCE(alerts.acknowledged_at, excluded.acknowledged_at),
+			resolved_at = COALESCE(alerts.resolved_at, excluded.resolved_at)
+		RETURNING id
 	`
-	_, err = s.db.ExecContext(ctx, query,
+	var idStr string
+	err = q.QueryRowContext(ctx, query,
 		alert.ID.String(), alert.OutageID.String(), alert.ExternalID, alert.Source, alert.TeamName,
 		alert.Title, alert.Description, alert.Severity, alert.TriggeredAt,
 		alert.AcknowledgedAt, alert.ResolvedAt, alert.CreatedAt,
-		string(sourceMetadataJSON), string(metadataJSON), string(customFieldsJSON),
-	)
+		string(sourceMetadataJSON), string(metadataJSON), string(customFieldsJSON), string(teamNamesJSON), alert.Service, alert.Fingerprint,
+	).Scan(&idStr)
 	if err != nil {
-		return fmt.Errorf("failed to create alert: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to upsert alert: %w", err)
 	}
-	return nil
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to parse alert id: %w", err)
+	}
+	return id, nil
 }
 
 // GetAlert retrieves an alert by ID.
@@ -55,7 +136,7 @@ func (s *SQLiteStorage) GetAlert(ctx context.Context, id uuid.UUID) (*domain.Ale
 	query := `
 		SELECT id, outage_id, external_id, source, team_name, title, description,
 		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		       source_metadata, metadata, custom_fields
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
 		FROM alerts
 		WHERE id = ?
 	`
@@ -72,7 +153,7 @@ func (s *SQLiteStorage) GetAlertByExternalID(ctx context.Context, externalID, so
 	query := `
 		SELECT id, outage_id, external_id, source, team_name, title, description,
 		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		       source_metadata, metadata, custom_fields
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
 		FROM alerts
 		WHERE external_id = ? AND source = ?
 	`
@@ -89,7 +170,7 @@ func (s *SQLiteStorage) ListAlertsByOutage(ctx context.Context, outageID uuid.UU
 	query := `
 		SELECT id, outage_id, external_id, source, team_name, title, description,
 		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
-		       source_metadata, metadata, custom_fields
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
 		FROM alerts
 		WHERE outage_id = ?
 		ORDER BY triggered_at DESC
@@ -114,6 +195,145 @@ func (s *SQLiteStorage) ListAlertsByOutage(ctx context.Context, outageID uuid.UU
 	return alerts, nil
 }
 
+// listAlertsByOutages retrieves the alerts of the outages whose IDs are
+// bound to the placeholders in, ordered as ListAlertsByOutage orders them.
+func (s *SQLiteStorage) listAlertsByOutages(ctx context.Context, in string, outageIDs []any) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+		WHERE outage_id IN ` + in + `
+		ORDER BY triggered_at DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		alert, err := scanAlertRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// ListOpenAlerts retrieves unresolved alerts triggered before the given
+// time, oldest first. Timestamps are stored as text, so the cutoff is
+// applied after scanning rather than compared in SQL.
+func (s *SQLiteStorage) ListOpenAlerts(ctx context.Context, triggeredBefore time.Time) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+		WHERE resolved_at IS NULL
+	`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open alerts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		alert, err := scanAlertRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		if alert.TriggeredAt.Before(triggeredBefore) {
+			alerts = append(alerts, alert)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].TriggeredAt.Before(alerts[j].TriggeredAt) })
+	return alerts, nil
+}
+
+// ListAlertsTriggeredBetween retrieves alerts triggered at or after since
+// and before until, oldest first. As in ListOpenAlerts, the range is applied
+// after scanning.
+func (s *SQLiteStorage) ListAlertsTriggeredBetween(ctx context.Context, since, until time.Time) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+	`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		alert, err := scanAlertRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		if !alert.TriggeredAt.Before(since) && alert.TriggeredAt.Before(until) {
+			alerts = append(alerts, alert)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].TriggeredAt.Before(alerts[j].TriggeredAt) })
+	return alerts, nil
+}
+
+// ListAlerts retrieves a page of the alerts matching q, most recently
+// triggered first. As in ListOpenAlerts, the team and time range are
+// applied after scanning.
+func (s *SQLiteStorage) ListAlerts(ctx context.Context, q domain.AlertQuery) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, outage_id, external_id, source, team_name, title, description,
+		       severity, triggered_at, acknowledged_at, resolved_at, created_at,
+		       source_metadata, metadata, custom_fields, team_names, service, fingerprint
+		FROM alerts
+		WHERE (? = '' OR source = ?) AND (? = '' OR severity = ?)
+	`
+	rows, err := s.db.QueryContext(ctx, query, q.Source, q.Source, q.Severity, q.Severity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		alert, err := scanAlertRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		if q.Matches(alert) {
+			alerts = append(alerts, alert)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].TriggeredAt.After(alerts[j].TriggeredAt) })
+	if q.Offset >= len(alerts) {
+		return nil, nil
+	}
+	alerts = alerts[q.Offset:]
+	if len(alerts) > q.Limit {
+		alerts = alerts[:q.Limit]
+	}
+	return alerts, nil
+}
+
 // UpdateAlert updates an existing alert.
 func (s *SQLiteStorage) UpdateAlert(ctx context.Context, alert *domain.Alert) error {
 	sourceMetadataJSON, err := marshalJSONAny(alert.SourceMetadata)
@@ -128,19 +348,24 @@ func (s *SQLiteStorage) UpdateAlert(ctx context.Context, alert *domain.Alert) er
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	teamNamesJSON, err := marshalNames(alert.TeamNames)
+	if err != nil {
+		return fmt.Errorf("failed to marshal team_names: %w", err)
+	}
 
 	query := `
 		UPDATE alerts
 		SET outage_id = ?, external_id = ?, source = ?, team_name = ?, title = ?,
 		    description = ?, severity = ?, triggered_at = ?, acknowledged_at = ?,
-		    resolved_at = ?, source_metadata = ?, metadata = ?, custom_fields = ?
+		    resolved_at = ?, source_metadata = ?, metadata = ?, custom_fields = ?,
+		    team_names = ?, service = ?, fingerprint = ?
 		WHERE id = ?
 	`
 	result, err := s.db.ExecContext(ctx, query,
 		alert.OutageID.String(), alert.ExternalID, alert.Source, alert.TeamName,
 		alert.Title, alert.Description, alert.Severity, alert.TriggeredAt,
 		alert.AcknowledgedAt, alert.ResolvedAt,
-		string(sourceMetadataJSON), string(metadataJSON), string(customFieldsJSON),
+		string(sourceMetadataJSON), string(metadataJSON), string(customFieldsJSON), string(teamNamesJSON), alert.Service, alert.Fingerprint,
 		alert.ID.String(),
 	)
 	if err != nil {
@@ -162,12 +387,12 @@ func (s *SQLiteStorage) UpdateAlert(ctx context.Context, alert *domain.Alert) er
 // sql.ErrNoRows.
 func scanAlertRow(scan scanFunc) (*domain.Alert, error) {
 	alert := &domain.Alert{}
-	var idStr, outageIDStr, sourceMetadataJSON, metadataJSON, customFieldsJSON string
+	var idStr, outageIDStr, sourceMetadataJSON, metadataJSON, customFieldsJSON, teamNamesJSON string
 	err := scan(
 		&idStr, &outageIDStr, &alert.ExternalID, &alert.Source, &alert.TeamName,
 		&alert.Title, &alert.Description, &alert.Severity, &alert.TriggeredAt,
 		&alert.AcknowledgedAt, &alert.ResolvedAt, &alert.CreatedAt,
-		&sourceMetadataJSON, &metadataJSON, &customFieldsJSON,
+		&sourceMetadataJSON, &metadataJSON, &customFieldsJSON, &teamNamesJSON, &alert.Service, &alert.Fingerprint,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain.ErrNotFound
@@ -195,5 +420,21 @@ func scanAlertRow(scan scanFunc) (*domain.Alert, error) {
 	if err := json.Unmarshal([]byte(customFieldsJSON), &alert.CustomFields); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
 	}
+	if err := json.Unmarshal([]byte(teamNamesJSON), &alert.TeamNames); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal team_names: %w", err)
+	}
+	if len(alert.TeamNames) == 0 {
+		alert.TeamNames = nil
+	}
 	return alert, nil
 }
+
+// marshalNames marshals a list of names, such as an alert's teams or an
+// outage's affected services, to a JSON array, empty rather than null when
+// there are none.
+func marshalNames(names []string) ([]byte, error) {
+	if names == nil {
+		names = []string{}
+	}
+	return json.Marshal(names)
+}
@@ -0,0 +1,70 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// CreateAlertEvent records a provider-side alert event, ignoring events
+// already recorded for the alert.
+func (s *SQLiteStorage) CreateAlertEvent(ctx context.Context, event *domain.AlertEvent) error {
+	query := `
+		INSERT INTO alert_events (id, alert_id, external_id, type, summary, actor, target, occurred_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (alert_id, external_id) DO NOTHING
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		event.ID.String(), event.AlertID.String(), event.ExternalID, event.Type, event.Summary,
+		event.Actor, event.Target, event.OccurredAt, event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create alert event: %w", err)
+	}
+	return nil
+}
+
+// ListAlertEventsByOutage retrieves the events of every alert linked to an
+// outage, oldest first.
+func (s *SQLiteStorage) ListAlertEventsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.AlertEvent, error) {
+	query := `
+		SELECT e.id, e.alert_id, e.external_id, e.type, e.summary, e.actor, e.target, e.occurred_at, e.created_at
+		FROM alert_events e
+		JOIN alerts a ON a.id = e.alert_id
+		WHERE a.outage_id = ?
+		ORDER BY e.occurred_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []*domain.AlertEvent
+	for rows.Next() {
+		event := &domain.AlertEvent{}
+		var idStr, alertIDStr string
+		if err := rows.Scan(
+			&idStr, &alertIDStr, &event.ExternalID, &event.Type, &event.Summary,
+			&event.Actor, &event.Target, &event.OccurredAt, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan alert event: %w", err)
+		}
+		if event.ID, err = uuid.Parse(idStr); err != nil {
+			return nil, fmt.Errorf("failed to parse alert event id: %w", err)
+		}
+		if event.AlertID, err = uuid.Parse(alertIDStr); err != nil {
+			return nil, fmt.Errorf("failed to parse alert id: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert events: %w", err)
+	}
+
+	return events, nil
+}
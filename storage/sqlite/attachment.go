@@ -0,0 +1,133 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// CreateAttachment records the metadata of an uploaded attachment.
+func (s *SQLiteStorage) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, outage_id, note_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	var noteID sql.NullString
+	if attachment.NoteID != nil {
+		noteID = sql.NullString{String: attachment.NoteID.String(), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, query,
+		attachment.ID.String(), attachment.OutageID.String(), noteID, attachment.Filename, attachment.ContentType,
+		attachment.Size, attachment.SHA256, attachment.StorageKey, attachment.UploadedBy, attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+// GetAttachment retrieves an attachment's metadata by ID.
+func (s *SQLiteStorage) GetAttachment(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	query := `
+		SELECT id, outage_id, note_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at
+		FROM attachments
+		WHERE id = ?
+	`
+	attachment, err := scanAttachmentRow(s.db.QueryRowContext(ctx, query, id.String()).Scan)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("attachment %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// ListAttachmentsByOutage retrieves the attachments of an outage, oldest first.
+func (s *SQLiteStorage) ListAttachmentsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Attachment, error) {
+	query := `
+		SELECT id, outage_id, note_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at
+		FROM attachments
+		WHERE outage_id = ?
+		ORDER BY created_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var attachments []*domain.Attachment
+	for rows.Next() {
+		attachment, parseErr := scanAttachmentRow(rows.Scan)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", parseErr)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// DeleteAttachment removes an attachment's metadata.
+func (s *SQLiteStorage) DeleteAttachment(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("attachment %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanAttachmentRow populates an Attachment from a single row using the
+// provided scan function. Returns domain.ErrNotFound when the underlying
+// error is sql.ErrNoRows.
+func scanAttachmentRow(scan scanFunc) (*domain.Attachment, error) {
+	attachment := &domain.Attachment{}
+	var idStr, outageIDStr string
+	var noteIDStr sql.NullString
+	if err := scan(
+		&idStr, &outageIDStr, &noteIDStr, &attachment.Filename, &attachment.ContentType,
+		&attachment.Size, &attachment.SHA256, &attachment.StorageKey, &attachment.UploadedBy, &attachment.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var parseErr error
+	attachment.ID, parseErr = uuid.Parse(idStr)
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to parse attachment id: %w", parseErr)
+	}
+	attachment.OutageID, parseErr = uuid.Parse(outageIDStr)
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to parse outage id: %w", parseErr)
+	}
+	if noteIDStr.Valid {
+		noteID, err := uuid.Parse(noteIDStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse note id: %w", err)
+		}
+		attachment.NoteID = &noteID
+	}
+	return attachment, nil
+}
@@ -0,0 +1,96 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+)
+
+// ListConfigResources retrieves operational config resources, optionally
+// filtered by kind, ordered by kind and name.
+func (s *SQLiteStorage) ListConfigResources(ctx context.Context, kind string) ([]*domain.ConfigResource, error) {
+	query := `
+		SELECT kind, name, spec, updated_at
+		FROM config_resources
+		WHERE ? = '' OR kind = ?
+		ORDER BY kind, name
+	`
+	rows, err := s.db.QueryContext(ctx, query, kind, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config resources: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var resources []*domain.ConfigResource
+	for rows.Next() {
+		resource := &domain.ConfigResource{}
+		var spec []byte
+		if err := rows.Scan(&resource.Kind, &resource.Name, &spec, &resource.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan config resource: %w", err)
+		}
+		resource.Spec = spec
+		resources = append(resources, resource)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating config resources: %w", err)
+	}
+	return resources, nil
+}
+
+// GetConfigResource retrieves a single operational config resource.
+func (s *SQLiteStorage) GetConfigResource(ctx context.Context, kind, name string) (*domain.ConfigResource, error) {
+	query := `
+		SELECT kind, name, spec, updated_at
+		FROM config_resources
+		WHERE kind = ? AND name = ?
+	`
+	resource := &domain.ConfigResource{}
+	var spec []byte
+	err := s.db.QueryRowContext(ctx, query, kind, name).Scan(&resource.Kind, &resource.Name, &spec, &resource.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s %s: %w", kind, name, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config resource: %w", err)
+	}
+	resource.Spec = spec
+	return resource, nil
+}
+
+// UpsertConfigResource creates or replaces an operational config resource.
+func (s *SQLiteStorage) UpsertConfigResource(ctx context.Context, resource *domain.ConfigResource) error {
+	query := `
+		INSERT INTO config_resources (kind, name, spec, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (kind, name) DO UPDATE SET
+			spec = excluded.spec,
+			updated_at = excluded.updated_at
+	`
+	_, err := s.db.ExecContext(ctx, query, resource.Kind, resource.Name, string(resource.Spec), resource.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save config resource: %w", err)
+	}
+	return nil
+}
+
+// DeleteConfigResource deletes an operational config resource.
+func (s *SQLiteStorage) DeleteConfigResource(ctx context.Context, kind, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM config_resources WHERE kind = ? AND name = ?`, kind, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete config resource: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s %s: %w", kind, name, domain.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const importRunColumns = `id, provider, kind, filter_hash, since, until, team_ids, status,
+		resume_offset, last_item_at, stats, error, started_at, updated_at, finished_at`
+
+// CreateImportRun records a new historical import run.
+func (s *SQLiteStorage) CreateImportRun(ctx context.Context, run *domain.ImportRun) error {
+	teamIDs, stats, err := marshalImportRun(run)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO import_runs (` + importRunColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = s.db.ExecContext(ctx, query,
+		run.ID.String(), run.Provider, run.Kind, run.FilterHash, run.Since, run.Until, string(teamIDs), run.Status,
+		run.Offset, run.LastItemAt, string(stats), run.Error, run.StartedAt, run.UpdatedAt, run.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create import run: %w", err)
+	}
+	return nil
+}
+
+// UpdateImportRun saves the progress, statistics and status of an import run.
+func (s *SQLiteStorage) UpdateImportRun(ctx context.Context, run *domain.ImportRun) error {
+	_, stats, err := marshalImportRun(run)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE import_runs
+		SET status = ?, resume_offset = ?, last_item_at = ?, stats = ?, error = ?,
+			updated_at = ?, finished_at = ?
+		WHERE id = ?
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		run.Status, run.Offset, run.LastItemAt, string(stats), run.Error, run.UpdatedAt, run.FinishedAt, run.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import run: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("import run %s: %w", run.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// GetImportRun retrieves an import run by ID.
+func (s *SQLiteStorage) GetImportRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error) {
+	query := `SELECT ` + importRunColumns + ` FROM import_runs WHERE id = ?`
+	run, err := scanImportRunRow(s.db.QueryRowContext(ctx, query, id.String()).Scan)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("import run %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import run: %w", err)
+	}
+	return run, nil
+}
+
+// ListImportRuns lists import runs, optionally only those from provider,
+// most recently started first.
+func (s *SQLiteStorage) ListImportRuns(ctx context.Context, provider string) ([]*domain.ImportRun, error) {
+	query := `
+		SELECT ` + importRunColumns + `
+		FROM import_runs
+		WHERE ? = '' OR provider = ?
+		ORDER BY started_at DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, provider, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []*domain.ImportRun
+	for rows.Next() {
+		run, err := scanImportRunRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating import runs: %w", err)
+	}
+	return runs, nil
+}
+
+// marshalImportRun marshals an import run's team IDs and statistics to JSON.
+func marshalImportRun(run *domain.ImportRun) (teamIDs, stats []byte, err error) {
+	teams := run.TeamIDs
+	if teams == nil {
+		teams = []string{}
+	}
+	if teamIDs, err = json.Marshal(teams); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal team ids: %w", err)
+	}
+	if stats, err = json.Marshal(run.Stats); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal import stats: %w", err)
+	}
+	return teamIDs, stats, nil
+}
+
+// scanImportRunRow populates an ImportRun from a single row of
+// importRunColumns. Returns domain.ErrNotFound when the underlying error is
+// sql.ErrNoRows.
+func scanImportRunRow(scan scanFunc) (*domain.ImportRun, error) {
+	run := &domain.ImportRun{}
+	var idStr, teamIDs, stats string
+	if err := scan(
+		&idStr, &run.Provider, &run.Kind, &run.FilterHash, &run.Since, &run.Until, &teamIDs, &run.Status,
+		&run.Offset, &run.LastItemAt, &stats, &run.Error, &run.StartedAt, &run.UpdatedAt, &run.FinishedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var err error
+	if run.ID, err = uuid.Parse(idStr); err != nil {
+		return nil, fmt.Errorf("failed to parse import run id: %w", err)
+	}
+	if err := json.Unmarshal([]byte(teamIDs), &run.TeamIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal team ids: %w", err)
+	}
+	if len(run.TeamIDs) == 0 {
+		run.TeamIDs = nil
+	}
+	if err := json.Unmarshal([]byte(stats), &run.Stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal import stats: %w", err)
+	}
+	return run, nil
+}
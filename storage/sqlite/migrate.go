@@ -0,0 +1,197 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// upgrades change the tables of databases created by an earlier schema.sql,
+// in order. PRAGMA user_version counts the upgrades a database has had; a
+// new database starts with all of them, as schema.sql already makes their
+// changes. New upgrades are appended, with schema.sql changed to match.
+var upgrades = []struct {
+	name  string
+	apply func(ctx context.Context, tx *sql.Tx) error
+}{
+	{"028_add_input_constraints", addInputConstraints},
+	{"031_add_unique_tag_keys", addUniqueTagKeys},
+}
+
+// setSchemaVersion records that the database has had the first n upgrades
+func (s *SQLiteStorage) setSchemaVersion(ctx context.Context, n int) error {
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, n)); err != nil {
+		return fmt.Errorf("schema migration: %w", err)
+	}
+	return nil
+}
+
+// upgrade applies the upgrades the database has not had yet, each in its own
+// transaction. Foreign keys are off while they run, as rebuilding a table
+// drops it, and checked before each upgrade commits.
+func (s *SQLiteStorage) upgrade(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("schema migration: %w", err)
+	}
+	if version >= len(upgrades) {
+		return nil
+	}
+
+	// PRAGMA foreign_keys applies to a connection and cannot change inside
+	// a transaction, so the upgrades run on one connection outside of one
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("schema migration: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return fmt.Errorf("schema migration: %w", err)
+	}
+	defer func() { _, _ = conn.ExecContext(context.WithoutCancel(ctx), `PRAGMA foreign_keys = ON`) }()
+
+	for i := version; i < len(upgrades); i++ {
+		if err := applyUpgrade(ctx, conn, i); err != nil {
+			return fmt.Errorf("schema upgrade %s: %w", upgrades[i].name, err)
+		}
+	}
+	return nil
+}
+
+// applyUpgrade applies upgrades[i]. Indexes it drops are recreated from
+// schema.sql once every upgrade has run.
+func applyUpgrade(ctx context.Context, conn *sql.Conn, i int) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := upgrades[i].apply(ctx, tx); err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return err
+	}
+	violated := rows.Next()
+	_ = rows.Close()
+	if violated {
+		return fmt.Errorf("rows reference missing rows; find them with PRAGMA foreign_key_check")
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// addInputConstraints adds the checks of migrations/028_add_input_constraints.sql.
+// Like that migration it changes no rows: if any would fail the checks, it
+// stops and counts them so they can be fixed by hand.
+func addInputConstraints(ctx context.Context, tx *sql.Tx) error {
+	var titles, statuses, severities, formats, keys int
+	err := tx.QueryRowContext(ctx, `SELECT
+		(SELECT count(*) FROM outages WHERE trim(title) = ''),
+		(SELECT count(*) FROM outages WHERE status NOT IN ('open', 'investigating', 'mitigated', 'resolved', 'closed')),
+		(SELECT count(*) FROM outages WHERE severity NOT IN ('', 'critical', 'high', 'medium', 'low')),
+		(SELECT count(*) FROM notes WHERE format NOT IN ('plaintext', 'markdown', 'log', 'log+gzip')),
+		(SELECT count(*) FROM tags WHERE trim(key) = '')
+	`).Scan(&titles, &statuses, &severities, &formats, &keys)
+	if err != nil {
+		return err
+	}
+	if titles+statuses+severities+formats+keys > 0 {
+		return fmt.Errorf("rows violate the input constraints: %d outages with a blank title, %d with an unknown status, "+
+			"%d with an unknown severity, %d notes with an unknown format, %d tags with a blank key; fix or remove them and restart",
+			titles, statuses, severities, formats, keys)
+	}
+
+	for _, table := range []string{"outages", "notes", "tags"} {
+		if err := rebuildTable(ctx, tx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildTable recreates table from its definition in schema.sql, copying
+// the columns it shares with the existing table, for changes ALTER TABLE
+// cannot make such as adding a CHECK. The table's indexes are dropped with
+// it, and recreated from schema.sql after the upgrades.
+func rebuildTable(ctx context.Context, tx *sql.Tx, table string) error {
+	create := "CREATE TABLE IF NOT EXISTS " + table + " ("
+	var definition string
+	for _, stmt := range schemaStatements() {
+		if i := strings.Index(stmt, create); i >= 0 {
+			definition = stmt[i+len(create):]
+			break
+		}
+	}
+	if definition == "" {
+		return fmt.Errorf("schema.sql does not create table %s", table)
+	}
+
+	rebuilt := table + "_rebuild"
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE "+rebuilt+" ("+definition); err != nil {
+		return fmt.Errorf("failed to create %s: %w", rebuilt, err)
+	}
+	existing, err := tableColumns(ctx, tx, table)
+	if err != nil {
+		return err
+	}
+	columns, err := tableColumns(ctx, tx, rebuilt)
+	if err != nil {
+		return err
+	}
+	columns = slices.DeleteFunc(columns, func(c string) bool { return !slices.Contains(existing, c) })
+
+	list := strings.Join(columns, ", ")
+	for _, stmt := range []string{
+		"INSERT INTO " + rebuilt + " (" + list + ") SELECT " + list + " FROM " + table,
+		"DROP TABLE " + table,
+		"ALTER TABLE " + rebuilt + " RENAME TO " + table,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to rebuild %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// addUniqueTagKeys adds the unique_key column of
+// migrations/031_add_unique_tag_keys.sql, unless the tags table was rebuilt
+// with it by an earlier upgrade. schema.sql then creates its index.
+func addUniqueTagKeys(ctx context.Context, tx *sql.Tx) error {
+	columns, err := tableColumns(ctx, tx, "tags")
+	if err != nil {
+		return err
+	}
+	if slices.Contains(columns, "unique_key") {
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, `ALTER TABLE tags ADD COLUMN unique_key BOOLEAN NOT NULL DEFAULT 0`)
+	return err
+}
+
+// tableColumns lists the columns of table
+func tableColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
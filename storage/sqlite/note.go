@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage/notecodec"
 	"github.com/google/uuid"
 )
 
@@ -23,13 +25,21 @@ func (s *SQLiteStorage) CreateNote(ctx context.Context, note *domain.Note) error
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	format, content, err := notecodec.Encode(note.Format, note.Content)
+	if err != nil {
+		return err
+	}
 
 	query := `
-		INSERT INTO notes (id, outage_id, content, format, author, created_at, updated_at, metadata, custom_fields)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO notes (id, outage_id, parent_note_id, content, format, author, created_at, updated_at, metadata, custom_fields)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
+	var parentNoteID sql.NullString
+	if note.ParentNoteID != nil {
+		parentNoteID = sql.NullString{String: note.ParentNoteID.String(), Valid: true}
+	}
 	_, err = s.db.ExecContext(ctx, query,
-		note.ID.String(), note.OutageID.String(), note.Content, note.Format,
+		note.ID.String(), note.OutageID.String(), parentNoteID, content, format,
 		note.Author, note.CreatedAt, note.UpdatedAt,
 		string(metadataJSON), string(customFieldsJSON),
 	)
@@ -42,7 +52,7 @@ func (s *SQLiteStorage) CreateNote(ctx context.Context, note *domain.Note) error
 // GetNote retrieves a note by ID.
 func (s *SQLiteStorage) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error) {
 	query := `
-		SELECT id, outage_id, content, format, author, created_at, updated_at, metadata, custom_fields
+		SELECT id, outage_id, parent_note_id, content, format, author, created_at, updated_at, deleted_at, metadata, custom_fields
 		FROM notes
 		WHERE id = ?
 	`
@@ -53,15 +63,47 @@ func (s *SQLiteStorage) GetNote(ctx context.Context, id uuid.UUID) (*domain.Note
 	return note, err
 }
 
-// ListNotesByOutage retrieves all notes for a specific outage.
-func (s *SQLiteStorage) ListNotesByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Note, error) {
+// ListNotesByOutage retrieves the notes for a specific outage, leaving out
+// notes in the trash unless includeDeleted is set.
+func (s *SQLiteStorage) ListNotesByOutage(ctx context.Context, outageID uuid.UUID, includeDeleted bool) ([]*domain.Note, error) {
+	query := `
+		SELECT id, outage_id, parent_note_id, content, format, author, created_at, updated_at, deleted_at, metadata, custom_fields
+		FROM notes
+		WHERE outage_id = ? AND (? OR deleted_at IS NULL)
+		ORDER BY created_at DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageID.String(), includeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var notes []*domain.Note
+	for rows.Next() {
+		note, parseErr := scanNoteRow(rows.Scan)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", parseErr)
+		}
+		notes = append(notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// listNotesByOutages retrieves the notes of the outages whose IDs are bound
+// to the placeholders in, leaving out notes in the trash and ordered as
+// ListNotesByOutage orders them.
+func (s *SQLiteStorage) listNotesByOutages(ctx context.Context, in string, outageIDs []any) ([]*domain.Note, error) {
 	query := `
-		SELECT id, outage_id, content, format, author, created_at, updated_at, metadata, custom_fields
+		SELECT id, outage_id, parent_note_id, content, format, author, created_at, updated_at, deleted_at, metadata, custom_fields
 		FROM notes
-		WHERE outage_id = ?
+		WHERE outage_id IN ` + in + ` AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
-	rows, err := s.db.QueryContext(ctx, query, outageID.String())
+	rows, err := s.db.QueryContext(ctx, query, outageIDs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list notes: %w", err)
 	}
@@ -94,6 +136,10 @@ func (s *SQLiteStorage) UpdateNote(ctx context.Context, note *domain.Note) error
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	format, content, err := notecodec.Encode(note.Format, note.Content)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		UPDATE notes
@@ -101,7 +147,7 @@ func (s *SQLiteStorage) UpdateNote(ctx context.Context, note *domain.Note) error
 		WHERE id = ?
 	`
 	result, err := s.db.ExecContext(ctx, query,
-		note.Content, note.Format, note.UpdatedAt,
+		content, format, note.UpdatedAt,
 		string(metadataJSON), string(customFieldsJSON),
 		note.ID.String(),
 	)
@@ -136,15 +182,58 @@ func (s *SQLiteStorage) DeleteNote(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// TrashNote moves a note to the trash.
+func (s *SQLiteStorage) TrashNote(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE notes SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	return s.setNoteDeletedAt(ctx, id, query, at, id.String())
+}
+
+// RestoreNote takes a note out of the trash.
+func (s *SQLiteStorage) RestoreNote(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE notes SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`
+	return s.setNoteDeletedAt(ctx, id, query, id.String())
+}
+
+// setNoteDeletedAt runs a trash or restore query, reporting
+// domain.ErrNotFound when it matched no note.
+func (s *SQLiteStorage) setNoteDeletedAt(ctx context.Context, id uuid.UUID, query string, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update note deleted_at: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("note %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// PurgeNotes permanently deletes notes trashed before deletedBefore.
+func (s *SQLiteStorage) PurgeNotes(ctx context.Context, deletedBefore time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM notes WHERE deleted_at < ?`, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge notes: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
 // scanNoteRow populates a Note from a single row using the provided scan
 // function. Returns domain.ErrNotFound when the underlying error is
 // sql.ErrNoRows.
 func scanNoteRow(scan scanFunc) (*domain.Note, error) {
 	note := &domain.Note{}
 	var idStr, outageIDStr, metadataJSON, customFieldsJSON string
+	var parentNoteIDStr sql.NullString
 	if err := scan(
-		&idStr, &outageIDStr, &note.Content, &note.Format,
-		&note.Author, &note.CreatedAt, &note.UpdatedAt,
+		&idStr, &outageIDStr, &parentNoteIDStr, &note.Content, &note.Format,
+		&note.Author, &note.CreatedAt, &note.UpdatedAt, &note.DeletedAt,
 		&metadataJSON, &customFieldsJSON,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -154,6 +243,10 @@ func scanNoteRow(scan scanFunc) (*domain.Note, error) {
 	}
 
 	var parseErr error
+	note.Format, note.Content, parseErr = notecodec.Decode(note.Format, note.Content)
+	if parseErr != nil {
+		return nil, parseErr
+	}
 	note.ID, parseErr = uuid.Parse(idStr)
 	if parseErr != nil {
 		return nil, fmt.Errorf("failed to parse note id: %w", parseErr)
@@ -162,6 +255,13 @@ func scanNoteRow(scan scanFunc) (*domain.Note, error) {
 	if parseErr != nil {
 		return nil, fmt.Errorf("failed to parse outage id: %w", parseErr)
 	}
+	if parentNoteIDStr.Valid {
+		parentNoteID, err := uuid.Parse(parentNoteIDStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse parent note id: %w", err)
+		}
+		note.ParentNoteID = &parentNoteID
+	}
 	if parseErr = json.Unmarshal([]byte(metadataJSON), &note.Metadata); parseErr != nil {
 		return nil, fmt.Errorf("failed to unmarshal metadata: %w", parseErr)
 	}
@@ -0,0 +1,79 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage/notecodec"
+	"github.com/google/uuid"
+)
+
+// CreateNoteRevision records a version of a note that an edit replaced.
+func (s *SQLiteStorage) CreateNoteRevision(ctx context.Context, revision *domain.NoteRevision) error {
+	format, content, err := notecodec.Encode(revision.Format, revision.Content)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO note_revisions (id, note_id, version, content, format, edited_by, edited_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = s.db.ExecContext(ctx, query,
+		revision.ID.String(), revision.NoteID.String(), revision.Version, content, format,
+		revision.EditedBy, revision.EditedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("note %s revision %d already exists: %w", revision.NoteID, revision.Version, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create note revision: %w", err)
+	}
+	return nil
+}
+
+// ListNoteRevisions retrieves the revisions of a note, oldest version first.
+func (s *SQLiteStorage) ListNoteRevisions(ctx context.Context, noteID uuid.UUID) ([]*domain.NoteRevision, error) {
+	query := `
+		SELECT id, note_id, version, content, format, edited_by, edited_at
+		FROM note_revisions
+		WHERE note_id = ?
+		ORDER BY version ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, noteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list note revisions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var revisions []*domain.NoteRevision
+	for rows.Next() {
+		revision := &domain.NoteRevision{}
+		var idStr, noteIDStr string
+		if err := rows.Scan(
+			&idStr, &noteIDStr, &revision.Version, &revision.Content,
+			&revision.Format, &revision.EditedBy, &revision.EditedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan note revision: %w", err)
+		}
+		if revision.ID, err = uuid.Parse(idStr); err != nil {
+			return nil, fmt.Errorf("failed to parse note revision id: %w", err)
+		}
+		if revision.NoteID, err = uuid.Parse(noteIDStr); err != nil {
+			return nil, fmt.Errorf("failed to parse note id: %w", err)
+		}
+		revision.Format, revision.Content, err = notecodec.Decode(revision.Format, revision.Content)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating note revisions: %w", err)
+	}
+
+	return revisions, nil
+}
@@ -8,13 +8,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
 )
 
+// outageColumns are the outage columns every outage query selects, in the
+// order scanOutageRow reads them.
+const outageColumns = `id, title, description, status, severity, owning_team, created_at, updated_at, investigating_at, mitigated_at, resolved_at, deleted_at, metadata, custom_fields,
+		affected_services, customer_impact, impact_started_at, impact_ended_at, parent_id`
+
 // CreateOutage creates a new outage in the database.
 func (s *SQLiteStorage) CreateOutage(ctx context.Context, outage *domain.Outage) error {
+	return createOutage(ctx, s.db, outage)
+}
+
+// createOutage inserts an outage with q.
+func createOutage(ctx context.Context, q querier, outage *domain.Outage) error {
 	metadataJSON, err := marshalJSONMap(outage.Metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
@@ -23,15 +35,22 @@ func (s *SQLiteStorage) CreateOutage(ctx context.Context, outage *domain.Outage)
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	servicesJSON, err := marshalNames(outage.AffectedServices)
+	if err != nil {
+		return fmt.Errorf("failed to marshal affected_services: %w", err)
+	}
 
 	query := `
-		INSERT INTO outages (id, title, description, status, severity, created_at, updated_at, resolved_at, metadata, custom_fields)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO outages (id, title, description, status, severity, owning_team, created_at, updated_at, investigating_at, mitigated_at, resolved_at, metadata, custom_fields,
+		                     affected_services, customer_impact, impact_started_at, impact_ended_at, parent_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err = s.db.ExecContext(ctx, query,
+	_, err = q.ExecContext(ctx, query,
 		outage.ID.String(), outage.Title, outage.Description, outage.Status,
-		outage.Severity, outage.CreatedAt, outage.UpdatedAt, outage.ResolvedAt,
+		outage.Severity, outage.OwningTeam, outage.CreatedAt, outage.UpdatedAt,
+		outage.InvestigatingAt, outage.MitigatedAt, outage.ResolvedAt,
 		string(metadataJSON), string(customFieldsJSON),
+		string(servicesJSON), outage.CustomerImpact, outage.ImpactStartedAt, outage.ImpactEndedAt, parentIDString(outage.ParentID),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create outage: %w", err)
@@ -39,20 +58,15 @@ func (s *SQLiteStorage) CreateOutage(ctx context.Context, outage *domain.Outage)
 	return nil
 }
 
-// GetOutage retrieves an outage by ID with all related data (alerts, notes, tags).
+// GetOutage retrieves an outage by ID with its alerts, notes and tags
 func (s *SQLiteStorage) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error) {
-	query := `
-		SELECT id, title, description, status, severity, created_at, updated_at, resolved_at, metadata, custom_fields
-		FROM outages
-		WHERE id = ?
-	`
-	outage := &domain.Outage{}
-	var idStr, metadataJSON, customFieldsJSON string
-	err := s.db.QueryRowContext(ctx, query, id.String()).Scan(
-		&idStr, &outage.Title, &outage.Description, &outage.Status,
-		&outage.Severity, &outage.CreatedAt, &outage.UpdatedAt, &outage.ResolvedAt,
-		&metadataJSON, &customFieldsJSON,
-	)
+	return s.GetOutageWith(ctx, id, domain.AllOutageAssociations)
+}
+
+// GetOutageWith retrieves an outage by ID with the selected associations
+func (s *SQLiteStorage) GetOutageWith(ctx context.Context, id uuid.UUID, associations domain.OutageAssociations) (*domain.Outage, error) {
+	query := `SELECT ` + outageColumns + ` FROM outages WHERE id = ?`
+	outage, err := scanOutageRow(s.db.QueryRowContext(ctx, query, id.String()).Scan)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
 	}
@@ -60,56 +74,80 @@ func (s *SQLiteStorage) GetOutage(ctx context.Context, id uuid.UUID) (*domain.Ou
 		return nil, fmt.Errorf("failed to get outage: %w", err)
 	}
 
-	outage.ID, err = uuid.Parse(idStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse outage id: %w", err)
+	if err := s.LoadOutageAssociations(ctx, []*domain.Outage{outage}, associations); err != nil {
+		return nil, err
 	}
 
-	if err := json.Unmarshal([]byte(metadataJSON), &outage.Metadata); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-	}
-	if err := json.Unmarshal([]byte(customFieldsJSON), &outage.CustomFields); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
-	}
+	return outage, nil
+}
 
-	// Eagerly load related data with three additional queries (N+1 by design,
-	// consistent with the postgres backend). Use ListOutages for lightweight
-	// pagination; call GetOutage only when the full record is needed.
-	alerts, err := s.ListAlertsByOutage(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load alerts: %w", err)
+// LoadOutageAssociations sets the selected alerts, notes and tags of
+// outages with one query for each, however many outages there are. Notes in
+// the trash are left out.
+func (s *SQLiteStorage) LoadOutageAssociations(ctx context.Context, outages []*domain.Outage, associations domain.OutageAssociations) error {
+	if len(outages) == 0 {
+		return nil
 	}
-	outage.Alerts = make([]domain.Alert, len(alerts))
-	for i, a := range alerts {
-		outage.Alerts[i] = *a
+	byID := make(map[uuid.UUID]*domain.Outage, len(outages))
+	args := make([]any, 0, len(outages))
+	for _, outage := range outages {
+		byID[outage.ID] = outage
+		args = append(args, outage.ID.String())
 	}
+	in := "(?" + strings.Repeat(", ?", len(args)-1) + ")"
 
-	notes, err := s.ListNotesByOutage(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load notes: %w", err)
-	}
-	outage.Notes = make([]domain.Note, len(notes))
-	for i, n := range notes {
-		outage.Notes[i] = *n
+	if associations.Alerts {
+		for _, outage := range outages {
+			outage.Alerts = []domain.Alert{}
+		}
+		alerts, err := s.listAlertsByOutages(ctx, in, args)
+		if err != nil {
+			return fmt.Errorf("failed to load alerts: %w", err)
+		}
+		for _, alert := range alerts {
+			if outage := byID[alert.OutageID]; outage != nil {
+				outage.Alerts = append(outage.Alerts, *alert)
+			}
+		}
 	}
 
-	tags, err := s.ListTagsByOutage(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load tags: %w", err)
-	}
-	outage.Tags = make([]domain.Tag, len(tags))
-	for i, t := range tags {
-		outage.Tags[i] = *t
+	if associations.Notes {
+		for _, outage := range outages {
+			outage.Notes = []domain.Note{}
+		}
+		notes, err := s.listNotesByOutages(ctx, in, args)
+		if err != nil {
+			return fmt.Errorf("failed to load notes: %w", err)
+		}
+		for _, note := range notes {
+			if outage := byID[note.OutageID]; outage != nil {
+				outage.Notes = append(outage.Notes, *note)
+			}
+		}
 	}
 
-	return outage, nil
+	if associations.Tags {
+		for _, outage := range outages {
+			outage.Tags = []domain.Tag{}
+		}
+		tags, err := s.listTagsByOutages(ctx, in, args)
+		if err != nil {
+			return fmt.Errorf("failed to load tags: %w", err)
+		}
+		for _, tag := range tags {
+			if outage := byID[tag.OutageID]; outage != nil {
+				outage.Tags = append(outage.Tags, *tag)
+			}
+		}
+	}
+	return nil
 }
 
-// ListOutages retrieves a paginated list of outages. Returned outages contain
-// only the core outage fields; related alerts, notes, and tags are not
-// eagerly loaded (consistent with the postgres backend). Call GetOutage for
-// a fully-populated record.
-func (s *SQLiteStorage) ListOutages(ctx context.Context, limit, offset int) ([]*domain.Outage, error) {
+// ListOutages retrieves a paginated list of outages, leaving out outages in
+// the trash unless includeDeleted is set. Returned outages contain only the
+// core outage fields; call LoadOutageAssociations or GetOutage for their
+// alerts, notes and tags.
+func (s *SQLiteStorage) ListOutages(ctx context.Context, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
 	// SQLite treats LIMIT -1 as "no limit"; clamp to 0 so callers get an empty
 	// result rather than a full table scan on a negative limit.
 	if limit < 0 {
@@ -119,12 +157,69 @@ func (s *SQLiteStorage) ListOutages(ctx context.Context, limit, offset int) ([]*
 		offset = 0
 	}
 	query := `
-		SELECT id, title, description, status, severity, created_at, updated_at, resolved_at, metadata, custom_fields
+		SELECT ` + outageColumns + `
+		FROM outages
+		WHERE ? OR deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+	return s.queryOutages(ctx, query, includeDeleted, limit, offset)
+}
+
+// ListOutagesByTeams retrieves a page of the outages owned by any of the
+// given teams, leaving out outages in the trash unless includeDeleted is
+// set. Like ListOutages, related data is not eagerly loaded.
+func (s *SQLiteStorage) ListOutagesByTeams(ctx context.Context, teams []string, limit, offset int, includeDeleted bool) ([]*domain.Outage, error) {
+	if len(teams) == 0 {
+		return nil, nil
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	query := `
+		SELECT ` + outageColumns + `
 		FROM outages
+		WHERE owning_team IN (?` + strings.Repeat(", ?", len(teams)-1) + `) AND (? OR deleted_at IS NULL)
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`
-	rows, err := s.db.QueryContext(ctx, query, limit, offset)
+	args := make([]any, 0, len(teams)+3)
+	for _, team := range teams {
+		args = append(args, team)
+	}
+	return s.queryOutages(ctx, query, append(args, includeDeleted, limit, offset)...)
+}
+
+// ListChildOutages retrieves the outages whose parent is parentID, oldest
+// first, leaving out outages in the trash.
+func (s *SQLiteStorage) ListChildOutages(ctx context.Context, parentID uuid.UUID) ([]*domain.Outage, error) {
+	query := `
+		SELECT ` + outageColumns + `
+		FROM outages
+		WHERE parent_id = ? AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+	return s.queryOutages(ctx, query, parentID.String())
+}
+
+// ListPublicOutages retrieves the open outages marked public in their
+// metadata, oldest first.
+func (s *SQLiteStorage) ListPublicOutages(ctx context.Context) ([]*domain.Outage, error) {
+	query := `
+		SELECT ` + outageColumns + `
+		FROM outages
+		WHERE json_extract(metadata, '$.' || ?) = 'true' AND status NOT IN ('resolved', 'closed') AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+	return s.queryOutages(ctx, query, domain.MetadataPublic)
+}
+
+// queryOutages runs a query selecting outage columns and scans the results.
+func (s *SQLiteStorage) queryOutages(ctx context.Context, query string, args ...any) ([]*domain.Outage, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list outages: %w", err)
 	}
@@ -155,17 +250,25 @@ func (s *SQLiteStorage) UpdateOutage(ctx context.Context, outage *domain.Outage)
 	if err != nil {
 		return fmt.Errorf("failed to marshal custom_fields: %w", err)
 	}
+	servicesJSON, err := marshalNames(outage.AffectedServices)
+	if err != nil {
+		return fmt.Errorf("failed to marshal affected_services: %w", err)
+	}
 
 	query := `
 		UPDATE outages
-		SET title = ?, description = ?, status = ?, severity = ?, updated_at = ?, resolved_at = ?,
-		    metadata = ?, custom_fields = ?
+		SET title = ?, description = ?, status = ?, severity = ?, updated_at = ?,
+		    investigating_at = ?, mitigated_at = ?, resolved_at = ?,
+		    metadata = ?, custom_fields = ?, owning_team = ?,
+		    affected_services = ?, customer_impact = ?, impact_started_at = ?, impact_ended_at = ?
 		WHERE id = ?
 	`
 	result, err := s.db.ExecContext(ctx, query,
 		outage.Title, outage.Description, outage.Status,
-		outage.Severity, outage.UpdatedAt, outage.ResolvedAt,
-		string(metadataJSON), string(customFieldsJSON),
+		outage.Severity, outage.UpdatedAt,
+		outage.InvestigatingAt, outage.MitigatedAt, outage.ResolvedAt,
+		string(metadataJSON), string(customFieldsJSON), outage.OwningTeam,
+		string(servicesJSON), outage.CustomerImpact, outage.ImpactStartedAt, outage.ImpactEndedAt,
 		outage.ID.String(),
 	)
 	if err != nil {
@@ -182,6 +285,81 @@ func (s *SQLiteStorage) UpdateOutage(ctx context.Context, outage *domain.Outage)
 	return nil
 }
 
+// SetOutageParent links or unlinks an outage's parent in one transaction.
+// The database has a single connection, so the transaction also keeps
+// other links from running between the checks and the update.
+func (s *SQLiteStorage) SetOutageParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, updatedAt time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set outage parent: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if parentID != nil {
+		var parent outageParentCheck
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM outages WHERE id = ?1),
+			       EXISTS (SELECT 1 FROM outages WHERE id = ?2),
+			       (SELECT deleted_at IS NOT NULL FROM outages WHERE id = ?2),
+			       (SELECT parent_id FROM outages WHERE id = ?2),
+			       EXISTS (SELECT 1 FROM outages WHERE parent_id = ?1 AND deleted_at IS NULL)
+		`, id.String(), parentID.String()).Scan(&parent.outageExists, &parent.exists, &parent.trashed, &parent.parentID, &parent.hasChildren)
+		if err != nil {
+			return fmt.Errorf("failed to check outage parent: %w", err)
+		}
+		if err := parent.check(id, *parentID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE outages SET parent_id = ?, updated_at = ? WHERE id = ?`,
+		parentIDString(parentID), updatedAt, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to set outage parent: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to set outage parent: %w", err)
+	}
+	return nil
+}
+
+// outageParentCheck holds what SetOutageParent reads about an outage and
+// its new parent
+type outageParentCheck struct {
+	outageExists bool
+	exists       bool
+	trashed      sql.NullBool
+	parentID     uuid.NullUUID
+	hasChildren  bool
+}
+
+// check returns why the outage cannot become a child of parentID, if it
+// cannot
+func (c outageParentCheck) check(id, parentID uuid.UUID) error {
+	switch {
+	case !c.outageExists:
+		return fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
+	case id == parentID:
+		return fmt.Errorf("an outage cannot be its own parent: %w", domain.ErrInvalidInput)
+	case !c.exists:
+		return fmt.Errorf("parent outage %s does not exist: %w", parentID, domain.ErrInvalidInput)
+	case c.trashed.Bool:
+		return fmt.Errorf("parent outage %s is in the trash: %w", parentID, domain.ErrInvalidInput)
+	case c.parentID.Valid:
+		return fmt.Errorf("outage %s is itself a child of %s and cannot be a parent: %w", parentID, c.parentID.UUID, domain.ErrInvalidInput)
+	case c.hasChildren:
+		return fmt.Errorf("outage %s has child outages and cannot be a child: %w", id, domain.ErrInvalidInput)
+	}
+	return nil
+}
+
 // DeleteOutage deletes an outage by ID.
 func (s *SQLiteStorage) DeleteOutage(ctx context.Context, id uuid.UUID) error {
 	result, err := s.db.ExecContext(ctx, `DELETE FROM outages WHERE id = ?`, id.String())
@@ -199,15 +377,60 @@ func (s *SQLiteStorage) DeleteOutage(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// TrashOutage moves an outage to the trash.
+func (s *SQLiteStorage) TrashOutage(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE outages SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	return s.setOutageDeletedAt(ctx, id, query, at, id.String())
+}
+
+// RestoreOutage takes an outage out of the trash.
+func (s *SQLiteStorage) RestoreOutage(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE outages SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`
+	return s.setOutageDeletedAt(ctx, id, query, id.String())
+}
+
+// setOutageDeletedAt runs a trash or restore query, reporting
+// domain.ErrNotFound when it matched no outage.
+func (s *SQLiteStorage) setOutageDeletedAt(ctx context.Context, id uuid.UUID, query string, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update outage deleted_at: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("outage %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// PurgeOutages permanently deletes outages trashed before deletedBefore.
+func (s *SQLiteStorage) PurgeOutages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM outages WHERE deleted_at < ?`, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outages: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
 // scanOutageRow populates an Outage from a single row using the provided scan
 // function.
 func scanOutageRow(scan scanFunc) (*domain.Outage, error) {
 	outage := &domain.Outage{}
-	var idStr, metadataJSON, customFieldsJSON string
+	var idStr, metadataJSON, customFieldsJSON, servicesJSON string
+	var parentIDStr sql.NullString
 	if err := scan(
 		&idStr, &outage.Title, &outage.Description, &outage.Status,
-		&outage.Severity, &outage.CreatedAt, &outage.UpdatedAt, &outage.ResolvedAt,
+		&outage.Severity, &outage.OwningTeam, &outage.CreatedAt, &outage.UpdatedAt,
+		&outage.InvestigatingAt, &outage.MitigatedAt, &outage.ResolvedAt, &outage.DeletedAt,
 		&metadataJSON, &customFieldsJSON,
+		&servicesJSON, &outage.CustomerImpact, &outage.ImpactStartedAt, &outage.ImpactEndedAt, &parentIDStr,
 	); err != nil {
 		return nil, err
 	}
@@ -223,5 +446,27 @@ func scanOutageRow(scan scanFunc) (*domain.Outage, error) {
 	if err := json.Unmarshal([]byte(customFieldsJSON), &outage.CustomFields); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
 	}
+	if err := json.Unmarshal([]byte(servicesJSON), &outage.AffectedServices); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal affected_services: %w", err)
+	}
+	if len(outage.AffectedServices) == 0 {
+		outage.AffectedServices = nil
+	}
+	if parentIDStr.Valid {
+		parentID, err := uuid.Parse(parentIDStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse outage parent_id: %w", err)
+		}
+		outage.ParentID = &parentID
+	}
 	return outage, nil
 }
+
+// parentIDString converts an outage's parent ID for storing, NULL when the
+// outage has no parent.
+func parentIDString(id *uuid.UUID) sql.NullString {
+	if id == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: id.String(), Valid: true}
+}
@@ -0,0 +1,69 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+)
+
+// GetUserPreferences retrieves the saved preferences for an OIDC subject.
+func (s *SQLiteStorage) GetUserPreferences(ctx context.Context, subject string) (*domain.UserPreferences, error) {
+	query := `
+		SELECT subject, timezone, default_team_filter, notifications, digest_opt_in, updated_at
+		FROM user_preferences
+		WHERE subject = ?
+	`
+	prefs := &domain.UserPreferences{}
+	var notificationsJSON string
+	err := s.db.QueryRowContext(ctx, query, subject).Scan(
+		&prefs.Subject, &prefs.Timezone, &prefs.DefaultTeamFilter,
+		&notificationsJSON, &prefs.DigestOptIn, &prefs.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("preferences for %s: %w", subject, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	if notificationsJSON != "" {
+		if err := json.Unmarshal([]byte(notificationsJSON), &prefs.Notifications); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notifications: %w", err)
+		}
+	}
+
+	return prefs, nil
+}
+
+// UpsertUserPreferences creates or replaces the preferences for an OIDC subject.
+func (s *SQLiteStorage) UpsertUserPreferences(ctx context.Context, prefs *domain.UserPreferences) error {
+	notificationsJSON, err := json.Marshal(prefs.Notifications)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notifications: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_preferences (subject, timezone, default_team_filter, notifications, digest_opt_in, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (subject) DO UPDATE SET
+			timezone = excluded.timezone,
+			default_team_filter = excluded.default_team_filter,
+			notifications = excluded.notifications,
+			digest_opt_in = excluded.digest_opt_in,
+			updated_at = excluded.updated_at
+	`
+	_, err = s.db.ExecContext(ctx, query,
+		prefs.Subject, prefs.Timezone, prefs.DefaultTeamFilter,
+		string(notificationsJSON), prefs.DigestOptIn, prefs.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return nil
+}
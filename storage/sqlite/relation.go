@@ -0,0 +1,121 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const outageRelationColumns = `id, outage_id, related_outage_id, type, description, created_by, created_at`
+
+// CreateOutageRelation links two outages.
+func (s *SQLiteStorage) CreateOutageRelation(ctx context.Context, relation *domain.OutageRelation) error {
+	query := `INSERT INTO outage_relations (` + outageRelationColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		relation.ID.String(), relation.OutageID.String(), relation.RelatedOutageID.String(), relation.Type,
+		relation.Description, relation.CreatedBy, relation.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage %s is already %s %s: %w", relation.OutageID, relation.Type, relation.RelatedOutageID, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create outage relation: %w", err)
+	}
+	return nil
+}
+
+// GetOutageRelation retrieves an outage relation by ID.
+func (s *SQLiteStorage) GetOutageRelation(ctx context.Context, id uuid.UUID) (*domain.OutageRelation, error) {
+	query := `SELECT ` + outageRelationColumns + ` FROM outage_relations WHERE id = ?`
+	relation, err := scanOutageRelationRow(s.db.QueryRowContext(ctx, query, id.String()).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("outage relation %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outage relation: %w", err)
+	}
+	return relation, nil
+}
+
+// ListOutageRelations retrieves the relations with the outage at either
+// end, oldest first, joining the outage at the other end for its title and
+// leaving out relations to outages in the trash.
+func (s *SQLiteStorage) ListOutageRelations(ctx context.Context, outageID uuid.UUID) ([]*domain.OutageRelation, error) {
+	query := `SELECT r.id, r.outage_id, r.related_outage_id, r.type, r.description, r.created_by, r.created_at, o.title
+		FROM outage_relations r
+		JOIN outages o ON o.id = CASE WHEN r.outage_id = ? THEN r.related_outage_id ELSE r.outage_id END
+		WHERE (r.outage_id = ? OR r.related_outage_id = ?) AND o.deleted_at IS NULL
+		ORDER BY r.created_at ASC, r.id ASC
+	`
+	id := outageID.String()
+	rows, err := s.db.QueryContext(ctx, query, id, id, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outage relations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var relations []*domain.OutageRelation
+	for rows.Next() {
+		var title string
+		relation, err := scanOutageRelationRow(func(dest ...any) error {
+			return rows.Scan(append(dest, &title)...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outage relation: %w", err)
+		}
+		relation.RelatedOutageTitle = title
+		relations = append(relations, relation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outage relations: %w", err)
+	}
+
+	return relations, nil
+}
+
+// DeleteOutageRelation removes a link between two outages.
+func (s *SQLiteStorage) DeleteOutageRelation(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM outage_relations WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete outage relation: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("outage relation %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// scanOutageRelationRow scans a row of outageRelationColumns.
+func scanOutageRelationRow(scan scanFunc) (*domain.OutageRelation, error) {
+	relation := &domain.OutageRelation{}
+	var idStr, outageIDStr, relatedIDStr string
+	if err := scan(
+		&idStr, &outageIDStr, &relatedIDStr, &relation.Type,
+		&relation.Description, &relation.CreatedBy, &relation.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	var err error
+	if relation.ID, err = uuid.Parse(idStr); err != nil {
+		return nil, fmt.Errorf("failed to parse outage relation id: %w", err)
+	}
+	if relation.OutageID, err = uuid.Parse(outageIDStr); err != nil {
+		return nil, fmt.Errorf("failed to parse outage id: %w", err)
+	}
+	if relation.RelatedOutageID, err = uuid.Parse(relatedIDStr); err != nil {
+		return nil, fmt.Errorf("failed to parse related outage id: %w", err)
+	}
+	return relation, nil
+}
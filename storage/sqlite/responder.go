@@ -0,0 +1,119 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const responderAssignmentColumns = `
+	id, outage_id, role, responder, source, schedule_id,
+	assigned_by, assigned_at, unassigned_by, unassigned_at
+`
+
+// CreateResponderAssignment records a responder taking a role on an outage.
+func (s *SQLiteStorage) CreateResponderAssignment(ctx context.Context, a *domain.ResponderAssignment) error {
+	query := `INSERT INTO responder_assignments (` + responderAssignmentColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		a.ID.String(), a.OutageID.String(), a.Role, a.Responder, a.Source, a.ScheduleID,
+		a.AssignedBy, a.AssignedAt, a.UnassignedBy, a.UnassignedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage %s already has a %s: %w", a.OutageID, a.Role, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create responder assignment: %w", err)
+	}
+	return nil
+}
+
+// EndResponderAssignment records that a responder stopped holding a role.
+func (s *SQLiteStorage) EndResponderAssignment(ctx context.Context, id uuid.UUID, by string, at time.Time) error {
+	query := `
+		UPDATE responder_assignments SET unassigned_by = ?, unassigned_at = ?
+		WHERE id = ? AND unassigned_at IS NULL
+	`
+	result, err := s.db.ExecContext(ctx, query, by, at, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to end responder assignment: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("responder assignment %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// ListResponderAssignments retrieves an outage's responder assignments,
+// oldest first.
+func (s *SQLiteStorage) ListResponderAssignments(ctx context.Context, outageID uuid.UUID) ([]*domain.ResponderAssignment, error) {
+	query := `SELECT ` + responderAssignmentColumns + `
+		FROM responder_assignments
+		WHERE outage_id = ?
+		ORDER BY assigned_at ASC
+	`
+	return s.queryResponderAssignments(ctx, query, outageID.String())
+}
+
+// ListResponderAssignmentsBetween retrieves the responder assignments made
+// at or after since and before until, oldest first. As in
+// ListAlertsTriggeredBetween, the range is applied after scanning.
+func (s *SQLiteStorage) ListResponderAssignmentsBetween(ctx context.Context, since, until time.Time) ([]*domain.ResponderAssignment, error) {
+	query := `SELECT ` + responderAssignmentColumns + `
+		FROM responder_assignments
+		ORDER BY assigned_at ASC
+	`
+	all, err := s.queryResponderAssignments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var assignments []*domain.ResponderAssignment
+	for _, a := range all {
+		if !a.AssignedAt.Before(since) && a.AssignedAt.Before(until) {
+			assignments = append(assignments, a)
+		}
+	}
+	return assignments, nil
+}
+
+func (s *SQLiteStorage) queryResponderAssignments(ctx context.Context, query string, args ...any) ([]*domain.ResponderAssignment, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list responder assignments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var assignments []*domain.ResponderAssignment
+	for rows.Next() {
+		a := &domain.ResponderAssignment{}
+		var idStr, outageIDStr string
+		if err := rows.Scan(
+			&idStr, &outageIDStr, &a.Role, &a.Responder, &a.Source, &a.ScheduleID,
+			&a.AssignedBy, &a.AssignedAt, &a.UnassignedBy, &a.UnassignedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan responder assignment: %w", err)
+		}
+		if a.ID, err = uuid.Parse(idStr); err != nil {
+			return nil, fmt.Errorf("failed to parse responder assignment id: %w", err)
+		}
+		if a.OutageID, err = uuid.Parse(outageIDStr); err != nil {
+			return nil, fmt.Errorf("failed to parse outage id: %w", err)
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating responder assignments: %w", err)
+	}
+
+	return assignments, nil
+}
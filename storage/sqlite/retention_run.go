@@ -0,0 +1,117 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const retentionRunColumns = `id, run_trigger, triggered_by, status, archived_before, archived_outages,
+		archive_key, archive_purged_at, purged_archives, error, started_at, finished_at`
+
+// CreateRetentionRun records a new retention run.
+func (s *SQLiteStorage) CreateRetentionRun(ctx context.Context, run *domain.RetentionRun) error {
+	query := `
+		INSERT INTO retention_runs (` + retentionRunColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		run.ID.String(), run.Trigger, run.TriggeredBy, run.Status, run.ArchivedBefore, run.ArchivedOutages,
+		run.ArchiveKey, run.ArchivePurgedAt, run.PurgedArchives, run.Error, run.StartedAt, run.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create retention run: %w", err)
+	}
+	return nil
+}
+
+// UpdateRetentionRun saves the outcome of a retention run, and whether its
+// archive has been purged.
+func (s *SQLiteStorage) UpdateRetentionRun(ctx context.Context, run *domain.RetentionRun) error {
+	query := `
+		UPDATE retention_runs
+		SET status = ?, archived_outages = ?, archive_key = ?, archive_purged_at = ?,
+			purged_archives = ?, error = ?, finished_at = ?
+		WHERE id = ?
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		run.Status, run.ArchivedOutages, run.ArchiveKey, run.ArchivePurgedAt,
+		run.PurgedArchives, run.Error, run.FinishedAt, run.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update retention run: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("retention run %s: %w", run.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// GetRetentionRun retrieves a retention run by ID.
+func (s *SQLiteStorage) GetRetentionRun(ctx context.Context, id uuid.UUID) (*domain.RetentionRun, error) {
+	query := `SELECT ` + retentionRunColumns + ` FROM retention_runs WHERE id = ?`
+	run, err := scanRetentionRunRow(s.db.QueryRowContext(ctx, query, id.String()).Scan)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("retention run %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retention run: %w", err)
+	}
+	return run, nil
+}
+
+// ListRetentionRuns lists retention runs, most recently started first.
+func (s *SQLiteStorage) ListRetentionRuns(ctx context.Context) ([]*domain.RetentionRun, error) {
+	query := `SELECT ` + retentionRunColumns + ` FROM retention_runs ORDER BY started_at DESC`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []*domain.RetentionRun
+	for rows.Next() {
+		run, err := scanRetentionRunRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan retention run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating retention runs: %w", err)
+	}
+	return runs, nil
+}
+
+// scanRetentionRunRow populates a RetentionRun from a single row of
+// retentionRunColumns. Returns domain.ErrNotFound when the underlying error
+// is sql.ErrNoRows.
+func scanRetentionRunRow(scan scanFunc) (*domain.RetentionRun, error) {
+	run := &domain.RetentionRun{}
+	var idStr string
+	if err := scan(
+		&idStr, &run.Trigger, &run.TriggeredBy, &run.Status, &run.ArchivedBefore, &run.ArchivedOutages,
+		&run.ArchiveKey, &run.ArchivePurgedAt, &run.PurgedArchives, &run.Error, &run.StartedAt, &run.FinishedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var err error
+	if run.ID, err = uuid.Parse(idStr); err != nil {
+		return nil, fmt.Errorf("failed to parse retention run id: %w", err)
+	}
+	return run, nil
+}
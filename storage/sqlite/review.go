@@ -0,0 +1,97 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// GetOutageReview retrieves the review state for an outage.
+func (s *SQLiteStorage) GetOutageReview(ctx context.Context, outageID uuid.UUID) (*domain.OutageReview, error) {
+	query := `
+		SELECT outage_id, status, scheduled_for, reviewed_at, reviewer, created_at, updated_at
+		FROM outage_reviews
+		WHERE outage_id = ?
+	`
+	review := &domain.OutageReview{}
+	var outageIDStr string
+	err := s.db.QueryRowContext(ctx, query, outageID.String()).Scan(
+		&outageIDStr, &review.Status, &review.ScheduledFor, &review.ReviewedAt,
+		&review.Reviewer, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("review for outage %s: %w", outageID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outage review: %w", err)
+	}
+	if review.OutageID, err = uuid.Parse(outageIDStr); err != nil {
+		return nil, fmt.Errorf("failed to parse outage id: %w", err)
+	}
+	return review, nil
+}
+
+// UpsertOutageReview creates or replaces the review state for an outage.
+func (s *SQLiteStorage) UpsertOutageReview(ctx context.Context, review *domain.OutageReview) error {
+	query := `
+		INSERT INTO outage_reviews (outage_id, status, scheduled_for, reviewed_at, reviewer, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (outage_id) DO UPDATE SET
+			status = excluded.status,
+			scheduled_for = excluded.scheduled_for,
+			reviewed_at = excluded.reviewed_at,
+			reviewer = excluded.reviewer,
+			updated_at = excluded.updated_at
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		review.OutageID.String(), review.Status, review.ScheduledFor, review.ReviewedAt,
+		review.Reviewer, review.CreatedAt, review.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save outage review: %w", err)
+	}
+	return nil
+}
+
+// ListOutageReviews retrieves outage reviews, optionally filtered by status,
+// oldest first.
+func (s *SQLiteStorage) ListOutageReviews(ctx context.Context, status string) ([]*domain.OutageReview, error) {
+	query := `
+		SELECT outage_id, status, scheduled_for, reviewed_at, reviewer, created_at, updated_at
+		FROM outage_reviews
+		WHERE ? = '' OR status = ?
+		ORDER BY created_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outage reviews: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reviews []*domain.OutageReview
+	for rows.Next() {
+		review := &domain.OutageReview{}
+		var outageIDStr string
+		if err := rows.Scan(
+			&outageIDStr, &review.Status, &review.ScheduledFor, &review.ReviewedAt,
+			&review.Reviewer, &review.CreatedAt, &review.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan outage review: %w", err)
+		}
+		if review.OutageID, err = uuid.Parse(outageIDStr); err != nil {
+			return nil, fmt.Errorf("failed to parse outage id: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outage reviews: %w", err)
+	}
+
+	return reviews, nil
+}
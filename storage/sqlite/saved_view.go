@@ -0,0 +1,151 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+const savedViewColumns = `id, name, owner, filter, slack_channel, summary_period, last_summary_at,
+		created_at, updated_at`
+
+// CreateSavedView saves a new view.
+func (s *SQLiteStorage) CreateSavedView(ctx context.Context, view *domain.SavedView) error {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal view filter: %w", err)
+	}
+	query := `
+		INSERT INTO saved_views (` + savedViewColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = s.db.ExecContext(ctx, query,
+		view.ID.String(), view.Name, view.Owner, string(filter), view.SlackChannel, view.SummaryPeriod,
+		view.LastSummaryAt, view.CreatedAt, view.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("view %q already exists: %w", view.Name, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create saved view: %w", err)
+	}
+	return nil
+}
+
+// GetSavedView retrieves a saved view by ID.
+func (s *SQLiteStorage) GetSavedView(ctx context.Context, id uuid.UUID) (*domain.SavedView, error) {
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views WHERE id = ?`
+	view, err := scanSavedViewRow(s.db.QueryRowContext(ctx, query, id.String()).Scan)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("view %s: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+	return view, nil
+}
+
+// ListSavedViews lists every saved view ordered by name.
+func (s *SQLiteStorage) ListSavedViews(ctx context.Context) ([]*domain.SavedView, error) {
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views ORDER BY name`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var views []*domain.SavedView
+	for rows.Next() {
+		view, err := scanSavedViewRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved view: %w", err)
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating saved views: %w", err)
+	}
+	return views, nil
+}
+
+// UpdateSavedView saves a view's name, filter, subscription and last
+// summary time.
+func (s *SQLiteStorage) UpdateSavedView(ctx context.Context, view *domain.SavedView) error {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal view filter: %w", err)
+	}
+	query := `
+		UPDATE saved_views
+		SET name = ?, filter = ?, slack_channel = ?, summary_period = ?, last_summary_at = ?,
+			updated_at = ?
+		WHERE id = ?
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		view.Name, string(filter), view.SlackChannel, view.SummaryPeriod, view.LastSummaryAt, view.UpdatedAt,
+		view.ID.String(),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("view %q already exists: %w", view.Name, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update saved view: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("view %s: %w", view.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteSavedView deletes a saved view.
+func (s *SQLiteStorage) DeleteSavedView(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("view %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// scanSavedViewRow populates a SavedView from a single row of
+// savedViewColumns. Returns domain.ErrNotFound when the underlying error is
+// sql.ErrNoRows.
+func scanSavedViewRow(scan scanFunc) (*domain.SavedView, error) {
+	view := &domain.SavedView{}
+	var idStr, filter string
+	if err := scan(
+		&idStr, &view.Name, &view.Owner, &filter, &view.SlackChannel, &view.SummaryPeriod, &view.LastSummaryAt,
+		&view.CreatedAt, &view.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var err error
+	if view.ID, err = uuid.Parse(idStr); err != nil {
+		return nil, fmt.Errorf("failed to parse saved view id: %w", err)
+	}
+	if err := json.Unmarshal([]byte(filter), &view.Filter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal view filter: %w", err)
+	}
+	return view, nil
+}
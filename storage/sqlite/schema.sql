@@ -5,23 +5,63 @@
 -- This schema mirrors:
 --   migrations/001_initial_schema.sql
 --   migrations/002_add_custom_fields.sql
--- Keep this file in sync when adding new PostgreSQL migration files.
+--   migrations/003_add_outage_status_changes.sql
+--   migrations/004_add_user_preferences.sql
+--   migrations/005_add_outage_reviews.sql
+--   migrations/006_add_alert_events.sql
+--   migrations/007_add_alert_sync_cursors.sql
+--   migrations/008_add_config_resources.sql
+--   migrations/009_add_source_ingestion.sql
+--   migrations/010_add_outage_state_machine.sql
+--   migrations/011_add_soft_delete.sql
+--   migrations/012_add_attachments.sql
+--   migrations/013_add_note_threads.sql
+--   migrations/014_add_note_revisions.sql
+--   migrations/015_add_outage_owning_team.sql
+--   migrations/016_add_responder_assignments.sql
+--   migrations/017_add_processed_events.sql
+--   migrations/018_add_import_runs.sql
+--   migrations/019_add_alert_team_names.sql
+--   migrations/020_add_saved_views.sql
+--   migrations/021_add_action_items.sql
+--   migrations/022_add_outage_impact.sql
+--   migrations/023_extend_service_catalogue.sql
+--   migrations/024_add_alert_fingerprints.sql
+--   migrations/025_add_watchers.sql
+--   migrations/026_add_export_watermarks.sql
+--   migrations/027_add_retention_runs.sql
+--   migrations/028_add_input_constraints.sql
+--   migrations/029_add_outage_parents.sql
+--   migrations/030_add_outage_relations.sql
+--   migrations/031_add_unique_tag_keys.sql
+-- Keep this file in sync when adding new PostgreSQL migration files. Changes
+-- to tables that already exist also need an upgrade in migrate.go, as
+-- CREATE TABLE IF NOT EXISTS leaves existing tables as they were.
 --
 -- Note: SQLite DATETIME stores timestamps with second precision. PostgreSQL
 -- stores microseconds via timestamptz. Tests truncate to the second
 -- (time.Truncate(time.Second)) to avoid spurious precision-related failures.
 
 CREATE TABLE IF NOT EXISTS outages (
-    id            TEXT PRIMARY KEY,
-    title         TEXT NOT NULL,
-    description   TEXT,
-    status        TEXT NOT NULL,
-    severity      TEXT NOT NULL,
-    created_at    DATETIME NOT NULL,
-    updated_at    DATETIME NOT NULL,
-    resolved_at   DATETIME,
-    metadata      TEXT NOT NULL DEFAULT '{}',
-    custom_fields TEXT NOT NULL DEFAULT '{}'
+    id                TEXT PRIMARY KEY,
+    title             TEXT NOT NULL CHECK (trim(title) <> ''),
+    description       TEXT,
+    status            TEXT NOT NULL CHECK (status IN ('open', 'investigating', 'mitigated', 'resolved', 'closed')),
+    severity          TEXT NOT NULL CHECK (severity IN ('', 'critical', 'high', 'medium', 'low')),
+    owning_team       TEXT NOT NULL DEFAULT '',
+    created_at        DATETIME NOT NULL,
+    updated_at        DATETIME NOT NULL,
+    investigating_at  DATETIME,
+    mitigated_at      DATETIME,
+    resolved_at       DATETIME,
+    deleted_at        DATETIME,
+    metadata          TEXT NOT NULL DEFAULT '{}',
+    custom_fields     TEXT NOT NULL DEFAULT '{}',
+    affected_services TEXT NOT NULL DEFAULT '[]',
+    customer_impact   BOOLEAN NOT NULL DEFAULT 0,
+    impact_started_at DATETIME,
+    impact_ended_at   DATETIME,
+    parent_id         TEXT REFERENCES outages(id) ON DELETE SET NULL
 );
 
 CREATE TABLE IF NOT EXISTS alerts (
@@ -40,17 +80,22 @@ CREATE TABLE IF NOT EXISTS alerts (
     source_metadata TEXT NOT NULL DEFAULT '{}',
     metadata        TEXT NOT NULL DEFAULT '{}',
     custom_fields   TEXT NOT NULL DEFAULT '{}',
+    team_names      TEXT NOT NULL DEFAULT '[]',
+    service         TEXT NOT NULL DEFAULT '',
+    fingerprint     TEXT NOT NULL DEFAULT '',
     UNIQUE(external_id, source)
 );
 
 CREATE TABLE IF NOT EXISTS notes (
     id            TEXT PRIMARY KEY,
     outage_id     TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    parent_note_id TEXT REFERENCES notes(id) ON DELETE SET NULL,
     content       TEXT NOT NULL,
-    format        TEXT NOT NULL DEFAULT 'plaintext',
+    format        TEXT NOT NULL DEFAULT 'plaintext' CHECK (format IN ('plaintext', 'markdown', 'log', 'log+gzip')),
     author        TEXT NOT NULL,
     created_at    DATETIME NOT NULL,
     updated_at    DATETIME NOT NULL,
+    deleted_at    DATETIME,
     metadata      TEXT NOT NULL DEFAULT '{}',
     custom_fields TEXT NOT NULL DEFAULT '{}'
 );
@@ -58,22 +103,262 @@ CREATE TABLE IF NOT EXISTS notes (
 CREATE TABLE IF NOT EXISTS tags (
     id            TEXT PRIMARY KEY,
     outage_id     TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
-    key           TEXT NOT NULL,
+    key           TEXT NOT NULL CHECK (trim(key) <> ''),
     value         TEXT NOT NULL,
     created_at    DATETIME NOT NULL,
-    custom_fields TEXT NOT NULL DEFAULT '{}'
+    custom_fields TEXT NOT NULL DEFAULT '{}',
+    unique_key    BOOLEAN NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS outage_status_changes (
+    id          TEXT PRIMARY KEY,
+    outage_id   TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status   TEXT NOT NULL,
+    action      TEXT NOT NULL DEFAULT '',
+    actor       TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL DEFAULT '',
+    changed_at  DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    subject             TEXT PRIMARY KEY,
+    timezone            TEXT NOT NULL DEFAULT 'UTC',
+    default_team_filter TEXT NOT NULL DEFAULT '',
+    notifications       TEXT NOT NULL DEFAULT '{}',
+    digest_opt_in       INTEGER NOT NULL DEFAULT 0,
+    updated_at          DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS outage_reviews (
+    outage_id     TEXT PRIMARY KEY REFERENCES outages(id) ON DELETE CASCADE,
+    status        TEXT NOT NULL,
+    scheduled_for DATETIME,
+    reviewed_at   DATETIME,
+    reviewer      TEXT NOT NULL DEFAULT '',
+    created_at    DATETIME NOT NULL,
+    updated_at    DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS alert_events (
+    id          TEXT PRIMARY KEY,
+    alert_id    TEXT NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    external_id TEXT NOT NULL,
+    type        TEXT NOT NULL,
+    summary     TEXT NOT NULL DEFAULT '',
+    actor       TEXT NOT NULL DEFAULT '',
+    target      TEXT NOT NULL DEFAULT '',
+    occurred_at DATETIME NOT NULL,
+    created_at  DATETIME NOT NULL,
+    UNIQUE(alert_id, external_id)
+);
+
+CREATE TABLE IF NOT EXISTS alert_sync_cursors (
+    source       TEXT PRIMARY KEY,
+    synced_until DATETIME NOT NULL,
+    updated_at   DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS config_resources (
+    kind       TEXT NOT NULL,
+    name       TEXT NOT NULL,
+    spec       TEXT NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (kind, name)
+);
+
+CREATE TABLE IF NOT EXISTS source_ingestion (
+    source          TEXT PRIMARY KEY,
+    last_success_at DATETIME,
+    last_failure_at DATETIME,
+    last_error      TEXT NOT NULL DEFAULT '',
+    updated_at      DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id           TEXT PRIMARY KEY,
+    outage_id    TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    note_id      TEXT REFERENCES notes(id) ON DELETE SET NULL,
+    filename     TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes   INTEGER NOT NULL,
+    sha256       TEXT NOT NULL,
+    storage_key  TEXT NOT NULL,
+    uploaded_by  TEXT NOT NULL DEFAULT '',
+    created_at   DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id        TEXT PRIMARY KEY,
+    note_id   TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    version   INTEGER NOT NULL,
+    content   TEXT NOT NULL,
+    format    TEXT NOT NULL,
+    edited_by TEXT NOT NULL DEFAULT '',
+    edited_at DATETIME NOT NULL,
+    UNIQUE (note_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS responder_assignments (
+    id            TEXT PRIMARY KEY,
+    outage_id     TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    role          TEXT NOT NULL,
+    responder     TEXT NOT NULL,
+    source        TEXT NOT NULL DEFAULT '',
+    schedule_id   TEXT NOT NULL DEFAULT '',
+    assigned_by   TEXT NOT NULL DEFAULT '',
+    assigned_at   DATETIME NOT NULL,
+    unassigned_by TEXT NOT NULL DEFAULT '',
+    unassigned_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS processed_events (
+    source       TEXT NOT NULL,
+    event_id     TEXT NOT NULL,
+    processed_at DATETIME NOT NULL,
+    PRIMARY KEY (source, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS import_runs (
+    id            TEXT PRIMARY KEY,
+    provider      TEXT NOT NULL,
+    kind          TEXT NOT NULL,
+    filter_hash   TEXT NOT NULL,
+    since         DATETIME NOT NULL,
+    until         DATETIME NOT NULL,
+    team_ids      TEXT NOT NULL DEFAULT '[]',
+    status        TEXT NOT NULL,
+    resume_offset INTEGER NOT NULL DEFAULT 0,
+    last_item_at  DATETIME,
+    stats         TEXT NOT NULL DEFAULT '{}',
+    error         TEXT NOT NULL DEFAULT '',
+    started_at    DATETIME NOT NULL,
+    updated_at    DATETIME NOT NULL,
+    finished_at   DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS saved_views (
+    id              TEXT PRIMARY KEY,
+    name            TEXT NOT NULL UNIQUE,
+    owner           TEXT NOT NULL,
+    filter          TEXT NOT NULL DEFAULT '{}',
+    slack_channel   TEXT NOT NULL DEFAULT '',
+    summary_period  TEXT NOT NULL DEFAULT '',
+    last_summary_at DATETIME,
+    created_at      DATETIME NOT NULL,
+    updated_at      DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS action_items (
+    id          TEXT PRIMARY KEY,
+    outage_id   TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    assignee    TEXT NOT NULL DEFAULT '',
+    due_date    DATETIME,
+    status      TEXT NOT NULL DEFAULT 'open',
+    created_by  TEXT NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL,
+    updated_at  DATETIME NOT NULL,
+    closed_at   DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS services (
+    name                 TEXT PRIMARY KEY,
+    description          TEXT NOT NULL DEFAULT '',
+    statuspage_component TEXT NOT NULL DEFAULT '',
+    owning_team          TEXT NOT NULL DEFAULT '',
+    tier                 INTEGER NOT NULL DEFAULT 0,
+    runbook_url          TEXT NOT NULL DEFAULT '',
+    repo                 TEXT NOT NULL DEFAULT '',
+    source               TEXT NOT NULL DEFAULT '',
+    created_at           DATETIME NOT NULL,
+    updated_at           DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS watchers (
+    outage_id  TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    email      TEXT NOT NULL,
+    channel    TEXT NOT NULL DEFAULT 'email',
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (outage_id, email)
 );
 
+CREATE TABLE IF NOT EXISTS export_watermarks (
+    table_name     TEXT PRIMARY KEY,
+    exported_until DATETIME NOT NULL,
+    updated_at     DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id                TEXT PRIMARY KEY,
+    run_trigger       TEXT NOT NULL,
+    triggered_by      TEXT NOT NULL DEFAULT '',
+    status            TEXT NOT NULL,
+    archived_before   DATETIME NOT NULL,
+    archived_outages  INTEGER NOT NULL DEFAULT 0,
+    archive_key       TEXT NOT NULL DEFAULT '',
+    archive_purged_at DATETIME,
+    purged_archives   INTEGER NOT NULL DEFAULT 0,
+    error             TEXT NOT NULL DEFAULT '',
+    started_at        DATETIME NOT NULL,
+    finished_at       DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_runs_started_at ON retention_runs(started_at DESC);
+
+CREATE TABLE IF NOT EXISTS outage_relations (
+    id                TEXT PRIMARY KEY,
+    outage_id         TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    related_outage_id TEXT NOT NULL REFERENCES outages(id) ON DELETE CASCADE,
+    type              TEXT NOT NULL,
+    description       TEXT NOT NULL DEFAULT '',
+    created_by        TEXT NOT NULL DEFAULT '',
+    created_at        DATETIME NOT NULL,
+    UNIQUE (outage_id, related_outage_id, type),
+    CHECK (outage_id <> related_outage_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outage_relations_outage_id ON outage_relations(outage_id);
+CREATE INDEX IF NOT EXISTS idx_outage_relations_related_outage_id ON outage_relations(related_outage_id);
+
 CREATE INDEX IF NOT EXISTS idx_outages_created_at ON outages(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_outages_status     ON outages(status);
 CREATE INDEX IF NOT EXISTS idx_outages_severity   ON outages(severity);
+CREATE INDEX IF NOT EXISTS idx_outages_deleted_at ON outages(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_outages_owning_team ON outages(owning_team) WHERE owning_team <> '';
+CREATE INDEX IF NOT EXISTS idx_outages_parent_id ON outages(parent_id) WHERE parent_id IS NOT NULL;
 
 CREATE INDEX IF NOT EXISTS idx_alerts_outage_id    ON alerts(outage_id);
 CREATE INDEX IF NOT EXISTS idx_alerts_external_id  ON alerts(external_id, source);
 CREATE INDEX IF NOT EXISTS idx_alerts_triggered_at ON alerts(triggered_at DESC);
+CREATE INDEX IF NOT EXISTS idx_alerts_service      ON alerts(service);
+CREATE INDEX IF NOT EXISTS idx_alerts_fingerprint  ON alerts(fingerprint);
 
 CREATE INDEX IF NOT EXISTS idx_notes_outage_id  ON notes(outage_id);
 CREATE INDEX IF NOT EXISTS idx_notes_created_at ON notes(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_parent_note_id ON notes(parent_note_id) WHERE parent_note_id IS NOT NULL;
 
 CREATE INDEX IF NOT EXISTS idx_tags_outage_id ON tags(outage_id);
 CREATE INDEX IF NOT EXISTS idx_tags_key_value ON tags(key, value);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_outage_unique_key ON tags(outage_id, key) WHERE unique_key;
+
+CREATE INDEX IF NOT EXISTS idx_outage_status_changes_outage_id ON outage_status_changes(outage_id, changed_at);
+
+CREATE INDEX IF NOT EXISTS idx_outage_reviews_status ON outage_reviews(status, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_alert_id ON alert_events(alert_id, occurred_at);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_outage_id ON attachments(outage_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_responder_assignments_outage_id ON responder_assignments(outage_id, assigned_at);
+CREATE INDEX IF NOT EXISTS idx_responder_assignments_assigned_at ON responder_assignments(assigned_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_responder_assignments_active_role
+    ON responder_assignments(outage_id, role) WHERE unassigned_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events(source, processed_at);
+
+CREATE INDEX IF NOT EXISTS idx_import_runs_provider ON import_runs(provider, started_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_action_items_outage_id ON action_items(outage_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_action_items_due_date ON action_items(due_date) WHERE status IN ('open', 'in_progress');
@@ -0,0 +1,129 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+)
+
+const serviceColumns = `name, description, owning_team, tier, runbook_url, repo,
+	statuspage_component, source, created_at, updated_at`
+
+// CreateService adds a service to the catalogue.
+func (s *SQLiteStorage) CreateService(ctx context.Context, svc *domain.Service) error {
+	query := `
+		INSERT INTO services (` + serviceColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		svc.Name, svc.Description, svc.OwningTeam, svc.Tier, svc.RunbookURL, svc.Repo,
+		svc.StatuspageComponent, svc.Source, svc.CreatedAt, svc.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("service %q already exists: %w", svc.Name, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	return nil
+}
+
+// GetService retrieves a catalogued service by name.
+func (s *SQLiteStorage) GetService(ctx context.Context, name string) (*domain.Service, error) {
+	query := `SELECT ` + serviceColumns + ` FROM services WHERE name = ?`
+	svc, err := scanServiceRow(s.db.QueryRowContext(ctx, query, name).Scan)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("service %q: %w", name, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	return svc, nil
+}
+
+// ListServices lists every catalogued service ordered by name.
+func (s *SQLiteStorage) ListServices(ctx context.Context) ([]*domain.Service, error) {
+	query := `SELECT ` + serviceColumns + ` FROM services ORDER BY name`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var services []*domain.Service
+	for rows.Next() {
+		svc, err := scanServiceRow(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, svc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating services: %w", err)
+	}
+	return services, nil
+}
+
+// UpdateService saves every field of a service but its name and creation
+// time.
+func (s *SQLiteStorage) UpdateService(ctx context.Context, svc *domain.Service) error {
+	query := `
+		UPDATE services
+		SET description = ?, owning_team = ?, tier = ?, runbook_url = ?, repo = ?,
+		    statuspage_component = ?, source = ?, updated_at = ?
+		WHERE name = ?
+	`
+	result, err := s.db.ExecContext(ctx, query,
+		svc.Description, svc.OwningTeam, svc.Tier, svc.RunbookURL, svc.Repo,
+		svc.StatuspageComponent, svc.Source, svc.UpdatedAt, svc.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update service: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("service %q: %w", svc.Name, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteService removes a service from the catalogue. Outages naming it
+// keep the name.
+func (s *SQLiteStorage) DeleteService(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM services WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("service %q: %w", name, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// scanServiceRow populates a Service from a single row of serviceColumns.
+// Returns domain.ErrNotFound when the underlying error is sql.ErrNoRows.
+func scanServiceRow(scan scanFunc) (*domain.Service, error) {
+	svc := &domain.Service{}
+	if err := scan(
+		&svc.Name, &svc.Description, &svc.OwningTeam, &svc.Tier, &svc.RunbookURL, &svc.Repo,
+		&svc.StatuspageComponent, &svc.Source, &svc.CreatedAt, &svc.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return svc, nil
+}
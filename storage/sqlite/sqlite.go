@@ -7,12 +7,14 @@ import (
 	"database/sql"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 
 	"github.com/conall/outalator/config"
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 // SQLiteStorage implements the Storage interface using SQLite.
@@ -25,8 +27,15 @@ type SQLiteStorage struct {
 // used by all per-entity scan helpers in this package.
 type scanFunc func(dest ...any) error
 
-// schema is the DDL applied automatically on first open (all statements are
-// idempotent via CREATE IF NOT EXISTS).
+// querier runs statements on the database or within a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// schema is the DDL applied automatically on open (all statements are
+// idempotent via CREATE IF NOT EXISTS). Changes to the tables it creates are
+// also made to existing databases by an upgrade in migrate.go.
 //
 //go:embed schema.sql
 var schema string
@@ -110,7 +119,30 @@ func buildDSN(path string) string {
 	return "file:" + path + "?" + pragmas
 }
 
-// migrate runs the embedded schema DDL (idempotent CREATE IF NOT EXISTS).
+// migrate brings the database up to date. A new database gets the whole of
+// schema.sql and is marked as needing none of the upgrades. CREATE IF NOT
+// EXISTS leaves the tables of an existing database as they were, so changes
+// to them are made by the upgrades in migrate.go, each run once, before
+// schema.sql adds new tables and indexes.
+func (s *SQLiteStorage) migrate(ctx context.Context) error {
+	var tables int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'outages'`).Scan(&tables); err != nil {
+		return fmt.Errorf("schema migration: %w", err)
+	}
+	if tables == 0 {
+		if err := s.applySchema(ctx); err != nil {
+			return err
+		}
+		return s.setSchemaVersion(ctx, len(upgrades))
+	}
+	if err := s.upgrade(ctx); err != nil {
+		return err
+	}
+	return s.applySchema(ctx)
+}
+
+// applySchema runs the embedded schema DDL (idempotent CREATE IF NOT EXISTS).
 // Statements are executed one at a time because database/sql's ExecContext does
 // not guarantee multi-statement support across all drivers.
 //
@@ -118,12 +150,8 @@ func buildDSN(path string) string {
 // a semicolon inside a string literal (e.g. DEFAULT 'a;b') or an inline
 // comment (e.g. -- deprecated; use X). Keep schema.sql free of semicolons
 // outside statement terminators.
-func (s *SQLiteStorage) migrate(ctx context.Context) error {
-	for _, stmt := range strings.Split(schema, ";") {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
-		}
+func (s *SQLiteStorage) applySchema(ctx context.Context) error {
+	for _, stmt := range schemaStatements() {
 		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
 			return fmt.Errorf("schema migration: %w", err)
 		}
@@ -131,11 +159,43 @@ func (s *SQLiteStorage) migrate(ctx context.Context) error {
 	return nil
 }
 
+// schemaStatements splits the embedded schema DDL into statements
+func schemaStatements() []string {
+	var stmts []string
+	for _, stmt := range strings.Split(schema, ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
+
+// CheckSchema always succeeds, as New applies the schema on open.
+func (s *SQLiteStorage) CheckSchema(context.Context) error {
+	return nil
+}
+
+// Ping checks the database can be reached.
+func (s *SQLiteStorage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 // Close closes the database connection.
 func (s *SQLiteStorage) Close() error {
 	return s.db.Close()
 }
 
+// isUniqueViolation reports whether err is a unique or primary key
+// constraint violation.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
 // marshalJSONMap safely marshals a string map, returning {} for nil maps.
 func marshalJSONMap(m map[string]string) ([]byte, error) {
 	if m == nil {
@@ -0,0 +1,67 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+	"github.com/google/uuid"
+)
+
+// CreateStatusChange records an outage status transition.
+func (s *SQLiteStorage) CreateStatusChange(ctx context.Context, change *domain.StatusChange) error {
+	query := `
+		INSERT INTO outage_status_changes (id, outage_id, from_status, to_status, action, actor, reason, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		change.ID.String(), change.OutageID.String(), change.FromStatus, change.ToStatus,
+		change.Action, change.Actor, change.Reason, change.ChangedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create status change: %w", err)
+	}
+	return nil
+}
+
+// ListStatusChangesByOutage retrieves the status history for an outage,
+// oldest first.
+func (s *SQLiteStorage) ListStatusChangesByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.StatusChange, error) {
+	query := `
+		SELECT id, outage_id, from_status, to_status, action, actor, reason, changed_at
+		FROM outage_status_changes
+		WHERE outage_id = ?
+		ORDER BY changed_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status changes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var changes []*domain.StatusChange
+	for rows.Next() {
+		change := &domain.StatusChange{}
+		var idStr, outageIDStr string
+		if err := rows.Scan(
+			&idStr, &outageIDStr, &change.FromStatus, &change.ToStatus,
+			&change.Action, &change.Actor, &change.Reason, &change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		if change.ID, err = uuid.Parse(idStr); err != nil {
+			return nil, fmt.Errorf("failed to parse status change id: %w", err)
+		}
+		if change.OutageID, err = uuid.Parse(outageIDStr); err != nil {
+			return nil, fmt.Errorf("failed to parse outage id: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status changes: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,45 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/conall/outalator/domain"
+)
+
+// GetSyncCursor retrieves the alert sync cursor for a notification service.
+func (s *SQLiteStorage) GetSyncCursor(ctx context.Context, source string) (*domain.SyncCursor, error) {
+	query := `
+		SELECT source, synced_until, updated_at
+		FROM alert_sync_cursors
+		WHERE source = ?
+	`
+	cursor := &domain.SyncCursor{}
+	err := s.db.QueryRowContext(ctx, query, source).Scan(&cursor.Source, &cursor.SyncedUntil, &cursor.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("sync cursor for %s: %w", source, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// UpsertSyncCursor creates or replaces the alert sync cursor for a notification service.
+func (s *SQLiteStorage) UpsertSyncCursor(ctx context.Context, cursor *domain.SyncCursor) error {
+	query := `
+		INSERT INTO alert_sync_cursors (source, synced_until, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (source) DO UPDATE SET
+			synced_until = excluded.synced_until,
+			updated_at = excluded.updated_at
+	`
+	if _, err := s.db.ExecContext(ctx, query, cursor.Source, cursor.SyncedUntil, cursor.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save sync cursor: %w", err)
+	}
+	return nil
+}
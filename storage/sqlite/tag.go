@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
@@ -21,23 +22,52 @@ func (s *SQLiteStorage) CreateTag(ctx context.Context, tag *domain.Tag) error {
 	}
 
 	query := `
-		INSERT INTO tags (id, outage_id, key, value, created_at, custom_fields)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO tags (id, outage_id, key, value, created_at, custom_fields, unique_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err = s.db.ExecContext(ctx, query,
 		tag.ID.String(), tag.OutageID.String(), tag.Key, tag.Value, tag.CreatedAt,
-		string(customFieldsJSON),
+		string(customFieldsJSON), tag.UniqueKey,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage %s already has a %s tag: %w", tag.OutageID, tag.Key, domain.ErrConflict)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create tag: %w", err)
 	}
 	return nil
 }
 
+// UpdateTag updates a tag's key, value, custom fields and unique key flag.
+func (s *SQLiteStorage) UpdateTag(ctx context.Context, tag *domain.Tag) error {
+	customFieldsJSON, err := marshalJSONAny(tag.CustomFields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom_fields: %w", err)
+	}
+
+	query := `UPDATE tags SET key = ?, value = ?, custom_fields = ?, unique_key = ? WHERE id = ?`
+	result, err := s.db.ExecContext(ctx, query, tag.Key, tag.Value, string(customFieldsJSON), tag.UniqueKey, tag.ID.String())
+	if isUniqueViolation(err) {
+		return fmt.Errorf("outage already has a %s tag: %w", tag.Key, domain.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("tag %s: %w", tag.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
 // GetTag retrieves a tag by ID.
 func (s *SQLiteStorage) GetTag(ctx context.Context, id uuid.UUID) (*domain.Tag, error) {
 	query := `
-		SELECT id, outage_id, key, value, created_at, custom_fields
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
 		FROM tags
 		WHERE id = ?
 	`
@@ -51,7 +81,7 @@ func (s *SQLiteStorage) GetTag(ctx context.Context, id uuid.UUID) (*domain.Tag,
 // ListTagsByOutage retrieves all tags for a specific outage.
 func (s *SQLiteStorage) ListTagsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Tag, error) {
 	query := `
-		SELECT id, outage_id, key, value, created_at, custom_fields
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
 		FROM tags
 		WHERE outage_id = ?
 		ORDER BY created_at DESC
@@ -77,6 +107,65 @@ func (s *SQLiteStorage) ListTagsByOutage(ctx context.Context, outageID uuid.UUID
 	return tags, nil
 }
 
+// listTagsByOutages retrieves the tags of the outages whose IDs are bound
+// to the placeholders in, ordered as ListTagsByOutage orders them.
+func (s *SQLiteStorage) listTagsByOutages(ctx context.Context, in string, outageIDs []any) ([]*domain.Tag, error) {
+	query := `
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
+		FROM tags
+		WHERE outage_id IN ` + in + `
+		ORDER BY created_at DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, outageIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tags []*domain.Tag
+	for rows.Next() {
+		tag, parseErr := scanTagRow(rows.Scan)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", parseErr)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// ListTagsByKey retrieves all tags with the given key across outages, oldest first.
+func (s *SQLiteStorage) ListTagsByKey(ctx context.Context, key string) ([]*domain.Tag, error) {
+	query := `
+		SELECT id, outage_id, key, value, created_at, custom_fields, unique_key
+		FROM tags
+		WHERE key = ?
+		ORDER BY created_at ASC
+	`
+	rows, err := s.db.QueryContext(ctx, query, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tags []*domain.Tag
+	for rows.Next() {
+		tag, parseErr := scanTagRow(rows.Scan)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", parseErr)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
 // DeleteTag deletes a tag by ID.
 func (s *SQLiteStorage) DeleteTag(ctx context.Context, id uuid.UUID) error {
 	result, err := s.db.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, id.String())
@@ -99,14 +188,52 @@ func (s *SQLiteStorage) DeleteTag(ctx context.Context, id uuid.UUID) error {
 // loaded. Call GetOutage for a fully-populated record.
 func (s *SQLiteStorage) FindOutagesByTag(ctx context.Context, key, value string) ([]*domain.Outage, error) {
 	query := `
-		SELECT DISTINCT o.id, o.title, o.description, o.status, o.severity,
-		       o.created_at, o.updated_at, o.resolved_at, o.metadata, o.custom_fields
+		SELECT DISTINCT o.id, o.title, o.description, o.status, o.severity, o.owning_team,
+		       o.created_at, o.updated_at, o.investigating_at, o.mitigated_at, o.resolved_at, o.deleted_at, o.metadata, o.custom_fields,
+		       o.affected_services, o.customer_impact, o.impact_started_at, o.impact_ended_at, o.parent_id
 		FROM outages o
 		INNER JOIN tags t ON o.id = t.outage_id
-		WHERE t.key = ? AND t.value = ?
+		WHERE t.key = ? AND t.value = ? AND o.deleted_at IS NULL
+		ORDER BY o.created_at DESC
+	`
+	return s.queryTaggedOutages(ctx, query, key, value)
+}
+
+// FindOutagesByTagQuery finds the outages matching every term of the query.
+// Returned outages contain only core fields, as for FindOutagesByTag.
+func (s *SQLiteStorage) FindOutagesByTagQuery(ctx context.Context, q domain.TagQuery) ([]*domain.Outage, error) {
+	where := []string{"o.deleted_at IS NULL"}
+	var args []any
+	for _, term := range q.Terms {
+		cond := "t.key = ?"
+		args = append(args, term.Key)
+		if len(term.Values) > 0 {
+			cond += " AND t.value IN (?" + strings.Repeat(", ?", len(term.Values)-1) + ")"
+			for _, v := range term.Values {
+				args = append(args, v)
+			}
+		}
+		exists := "EXISTS (SELECT 1 FROM tags t WHERE t.outage_id = o.id AND " + cond + ")"
+		if term.Negate {
+			exists = "NOT " + exists
+		}
+		where = append(where, exists)
+	}
+	query := `
+		SELECT o.id, o.title, o.description, o.status, o.severity, o.owning_team,
+		       o.created_at, o.updated_at, o.investigating_at, o.mitigated_at, o.resolved_at, o.deleted_at, o.metadata, o.custom_fields,
+		       o.affected_services, o.customer_impact, o.impact_started_at, o.impact_ended_at, o.parent_id
+		FROM outages o
+		WHERE ` + strings.Join(where, " AND ") + `
 		ORDER BY o.created_at DESC
 	`
-	rows, err := s.db.QueryContext(ctx, query, key, value)
+	return s.queryTaggedOutages(ctx, query, args...)
+}
+
+// queryTaggedOutages runs a query selecting outage columns and scans the
+// results.
+func (s *SQLiteStorage) queryTaggedOutages(ctx context.Context, query string, args ...any) ([]*domain.Outage, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find outages by tag: %w", err)
 	}
@@ -127,6 +254,57 @@ func (s *SQLiteStorage) FindOutagesByTag(ctx context.Context, key, value string)
 	return outages, nil
 }
 
+// ListTagKeys counts the tags with each key on outages not in the trash.
+func (s *SQLiteStorage) ListTagKeys(ctx context.Context) ([]domain.TagUsage, error) {
+	query := `
+		SELECT t.key, '', COUNT(*)
+		FROM tags t
+		INNER JOIN outages o ON o.id = t.outage_id
+		WHERE o.deleted_at IS NULL
+		GROUP BY t.key
+		ORDER BY COUNT(*) DESC, t.key
+	`
+	return s.queryTagUsage(ctx, query)
+}
+
+// ListTagValues counts the tags with each value of key on outages not in
+// the trash.
+func (s *SQLiteStorage) ListTagValues(ctx context.Context, key string) ([]domain.TagUsage, error) {
+	query := `
+		SELECT t.key, t.value, COUNT(*)
+		FROM tags t
+		INNER JOIN outages o ON o.id = t.outage_id
+		WHERE t.key = ? AND o.deleted_at IS NULL
+		GROUP BY t.key, t.value
+		ORDER BY COUNT(*) DESC, t.value
+	`
+	return s.queryTagUsage(ctx, query, key)
+}
+
+// queryTagUsage runs a query selecting a key, value and count and scans the
+// results.
+func (s *SQLiteStorage) queryTagUsage(ctx context.Context, query string, args ...any) ([]domain.TagUsage, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var usage []domain.TagUsage
+	for rows.Next() {
+		var u domain.TagUsage
+		if err := rows.Scan(&u.Key, &u.Value, &u.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tag counts: %w", err)
+	}
+
+	return usage, nil
+}
+
 // scanTagRow populates a Tag from a single row using the provided scan
 // function. Returns domain.ErrNotFound when the underlying error is
 // sql.ErrNoRows.
@@ -135,7 +313,7 @@ func scanTagRow(scan scanFunc) (*domain.Tag, error) {
 	var idStr, outageIDStr, customFieldsJSON string
 	if err := scan(
 		&idStr, &outageIDStr, &tag.Key, &tag.Value, &tag.CreatedAt,
-		&customFieldsJSON,
+		&customFieldsJSON, &tag.UniqueKey,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
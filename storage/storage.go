@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/conall/outalator/domain"
 	"github.com/google/uuid"
@@ -14,45 +15,359 @@ import (
 type Storage interface {
 	OutageStorage
 	AlertStorage
+	AlertEventStorage
 	NoteStorage
+	NoteRevisionStorage
+	ResponderStorage
 	TagStorage
+	StatusChangeStorage
+	PreferenceStorage
+	ReviewStorage
+	SyncCursorStorage
+	IngestionStorage
+	ConfigResourceStorage
+	AttachmentStorage
+	ProcessedEventStorage
+	ImportRunStorage
+	SavedViewStorage
+	ActionItemStorage
+	ServiceStorage
+	WatcherStorage
+	ExportWatermarkStorage
+	RetentionRunStorage
+	RelationStorage
+	// Ping checks the backing database can be reached
+	Ping(ctx context.Context) error
+	// CheckSchema checks the database schema is up to date, i.e. every
+	// migration has been applied
+	CheckSchema(ctx context.Context) error
 	Close() error
 }
 
-// OutageStorage defines methods for outage persistence
+// OutageStorage defines methods for outage persistence. GetOutage returns
+// outages in the trash too; ListOutages leaves them out unless asked and
+// FindOutagesByTag always does.
 type OutageStorage interface {
 	CreateOutage(ctx context.Context, outage *domain.Outage) error
 	GetOutage(ctx context.Context, id uuid.UUID) (*domain.Outage, error)
-	ListOutages(ctx context.Context, limit, offset int) ([]*domain.Outage, error)
+	// GetOutageWith is GetOutage loading only the selected associations,
+	// skipping the queries for the others
+	GetOutageWith(ctx context.Context, id uuid.UUID, associations domain.OutageAssociations) (*domain.Outage, error)
+	ListOutages(ctx context.Context, limit, offset int, includeDeleted bool) ([]*domain.Outage, error)
+	// ListOutagesByTeams is ListOutages restricted to outages owned by any
+	// of the given teams; it returns nothing when teams is empty
+	ListOutagesByTeams(ctx context.Context, teams []string, limit, offset int, includeDeleted bool) ([]*domain.Outage, error)
+	// LoadOutageAssociations sets the selected alerts, notes and tags of
+	// listed outages as GetOutageWith would, in a fixed number of queries
+	// rather than one set per outage
+	LoadOutageAssociations(ctx context.Context, outages []*domain.Outage, associations domain.OutageAssociations) error
+	// ListChildOutages returns the outages whose parent is parentID, oldest
+	// first, leaving out those in the trash
+	ListChildOutages(ctx context.Context, parentID uuid.UUID) ([]*domain.Outage, error)
+	// ListPublicOutages returns the outages marked public in their metadata
+	// that are neither resolved, closed nor in the trash, oldest first
+	ListPublicOutages(ctx context.Context) ([]*domain.Outage, error)
+	// UpdateOutage saves an outage, leaving its parent to SetOutageParent
 	UpdateOutage(ctx context.Context, outage *domain.Outage) error
+	// SetOutageParent links an outage as a child of parentID, or unlinks it
+	// when parentID is nil, and sets its updated_at. Outages nest one level
+	// deep, so linking returns domain.ErrInvalidInput unless the parent is a
+	// top-level outage outside the trash and the outage has no children.
+	// Both outages are locked while this is checked, so concurrent links
+	// cannot nest outages deeper or form a cycle.
+	SetOutageParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, updatedAt time.Time) error
+	// DeleteOutage removes an outage permanently, along with its alerts,
+	// notes, tags, status history, review and responder assignments
 	DeleteOutage(ctx context.Context, id uuid.UUID) error
+	// TrashOutage and RestoreOutage set and clear an outage's deleted_at.
+	// They return domain.ErrNotFound when the outage does not exist or is
+	// already in, or not in, the trash.
+	TrashOutage(ctx context.Context, id uuid.UUID, at time.Time) error
+	RestoreOutage(ctx context.Context, id uuid.UUID) error
+	// PurgeOutages permanently deletes outages trashed before the given
+	// time and returns how many were deleted
+	PurgeOutages(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
 // AlertStorage defines methods for alert persistence
 type AlertStorage interface {
+	// CreateAlert stores an alert as UpsertAlert does. When an alert with
+	// the same source and external ID is already stored, alert is set to
+	// that alert.
 	CreateAlert(ctx context.Context, alert *domain.Alert) error
+	// UpsertAlert stores an alert unless one with the same source and
+	// external ID already is, in which case that alert's acknowledged and
+	// resolved times are filled in from alert where it has none. It returns
+	// the stored alert and whether alert was inserted, so concurrent
+	// deliveries of one alert store it once.
+	UpsertAlert(ctx context.Context, alert *domain.Alert) (*domain.Alert, bool, error)
+	// CreateOutageWithAlert creates an outage opened for an alert and
+	// upserts the alert into it in one transaction. When the alert is
+	// already stored, the outage is not kept and the stored alert is
+	// returned with created false, as UpsertAlert returns it.
+	CreateOutageWithAlert(ctx context.Context, outage *domain.Outage, alert *domain.Alert) (*domain.Alert, bool, error)
 	GetAlert(ctx context.Context, id uuid.UUID) (*domain.Alert, error)
 	GetAlertByExternalID(ctx context.Context, externalID, source string) (*domain.Alert, error)
 	ListAlertsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Alert, error)
+	// ListOpenAlerts returns unresolved alerts triggered before the given
+	// time, oldest first.
+	ListOpenAlerts(ctx context.Context, triggeredBefore time.Time) ([]*domain.Alert, error)
+	// ListAlertsTriggeredBetween returns alerts triggered at or after since
+	// and before until, oldest first.
+	ListAlertsTriggeredBetween(ctx context.Context, since, until time.Time) ([]*domain.Alert, error)
+	// ListAlerts returns the alerts matching query across all outages, most
+	// recently triggered first, one page of query.Limit from query.Offset
+	ListAlerts(ctx context.Context, query domain.AlertQuery) ([]*domain.Alert, error)
 	UpdateAlert(ctx context.Context, alert *domain.Alert) error
 }
 
-// NoteStorage defines methods for note persistence
+// AlertEventStorage defines methods for provider-side alert event
+// persistence. CreateAlertEvent ignores an event whose external ID is
+// already recorded for the same alert, so provider logs can be re-fetched
+// without duplicating events. Events are removed along with their alert.
+type AlertEventStorage interface {
+	CreateAlertEvent(ctx context.Context, event *domain.AlertEvent) error
+	// ListAlertEventsByOutage returns the events of every alert linked to
+	// the outage, oldest first.
+	ListAlertEventsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.AlertEvent, error)
+}
+
+// NoteStorage defines methods for note persistence. Trashed notes follow
+// the same rules as trashed outages.
 type NoteStorage interface {
 	CreateNote(ctx context.Context, note *domain.Note) error
 	GetNote(ctx context.Context, id uuid.UUID) (*domain.Note, error)
-	ListNotesByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Note, error)
+	ListNotesByOutage(ctx context.Context, outageID uuid.UUID, includeDeleted bool) ([]*domain.Note, error)
 	UpdateNote(ctx context.Context, note *domain.Note) error
 	DeleteNote(ctx context.Context, id uuid.UUID) error
+	TrashNote(ctx context.Context, id uuid.UUID, at time.Time) error
+	RestoreNote(ctx context.Context, id uuid.UUID) error
+	PurgeNotes(ctx context.Context, deletedBefore time.Time) (int, error)
+}
+
+// NoteRevisionStorage defines methods for note edit history persistence.
+// Revisions are append-only and are removed along with their note.
+// CreateNoteRevision returns domain.ErrConflict when the note already has a
+// revision with the same version.
+type NoteRevisionStorage interface {
+	CreateNoteRevision(ctx context.Context, revision *domain.NoteRevision) error
+	// ListNoteRevisions returns a note's revisions, oldest version first
+	ListNoteRevisions(ctx context.Context, noteID uuid.UUID) ([]*domain.NoteRevision, error)
+}
+
+// ResponderStorage defines methods for responder assignment persistence.
+// Assignments are removed along with their outage. CreateResponderAssignment
+// returns domain.ErrConflict when another responder already holds the role
+// on the outage.
+type ResponderStorage interface {
+	CreateResponderAssignment(ctx context.Context, assignment *domain.ResponderAssignment) error
+	// EndResponderAssignment records that an assignment ended. It returns
+	// domain.ErrNotFound when the assignment does not exist or has already
+	// ended.
+	EndResponderAssignment(ctx context.Context, id uuid.UUID, by string, at time.Time) error
+	// ListResponderAssignments returns an outage's assignments, oldest first
+	ListResponderAssignments(ctx context.Context, outageID uuid.UUID) ([]*domain.ResponderAssignment, error)
+	// ListResponderAssignmentsBetween returns the assignments made at or
+	// after since and before until, oldest first
+	ListResponderAssignmentsBetween(ctx context.Context, since, until time.Time) ([]*domain.ResponderAssignment, error)
 }
 
 // TagStorage defines methods for tag persistence.
-// Tags are intentionally immutable after creation: there is no UpdateTag.
-// To change a tag's key or value, delete the old tag and create a new one.
 type TagStorage interface {
 	CreateTag(ctx context.Context, tag *domain.Tag) error
 	GetTag(ctx context.Context, id uuid.UUID) (*domain.Tag, error)
+	// UpdateTag saves a tag's key, value and custom fields; its outage and
+	// creation time never change
+	UpdateTag(ctx context.Context, tag *domain.Tag) error
 	ListTagsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Tag, error)
+	ListTagsByKey(ctx context.Context, key string) ([]*domain.Tag, error)
 	DeleteTag(ctx context.Context, id uuid.UUID) error
 	FindOutagesByTag(ctx context.Context, key, value string) ([]*domain.Outage, error)
+	// FindOutagesByTagQuery finds the outages not in the trash that match
+	// every term of the query, newest first. A query without terms matches
+	// every such outage.
+	FindOutagesByTagQuery(ctx context.Context, query domain.TagQuery) ([]*domain.Outage, error)
+	// ListTagKeys counts the tags with each key, and ListTagValues the tags
+	// with each value of a key, skipping outages in the trash. Both order by
+	// count, most used first, then by name.
+	ListTagKeys(ctx context.Context) ([]domain.TagUsage, error)
+	ListTagValues(ctx context.Context, key string) ([]domain.TagUsage, error)
+}
+
+// StatusChangeStorage defines methods for outage status history persistence.
+// Status changes are append-only and are removed along with their outage.
+type StatusChangeStorage interface {
+	CreateStatusChange(ctx context.Context, change *domain.StatusChange) error
+	ListStatusChangesByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.StatusChange, error)
+}
+
+// PreferenceStorage defines methods for user preference persistence.
+// GetUserPreferences returns domain.ErrNotFound for users who have never
+// saved preferences.
+type PreferenceStorage interface {
+	GetUserPreferences(ctx context.Context, subject string) (*domain.UserPreferences, error)
+	UpsertUserPreferences(ctx context.Context, prefs *domain.UserPreferences) error
+}
+
+// ReviewStorage defines methods for outage review persistence.
+// GetOutageReview returns domain.ErrNotFound for outages that have not
+// entered the review workflow.
+type ReviewStorage interface {
+	GetOutageReview(ctx context.Context, outageID uuid.UUID) (*domain.OutageReview, error)
+	UpsertOutageReview(ctx context.Context, review *domain.OutageReview) error
+	// ListOutageReviews returns reviews oldest first. An empty status lists
+	// reviews in every state.
+	ListOutageReviews(ctx context.Context, status string) ([]*domain.OutageReview, error)
+}
+
+// SyncCursorStorage defines methods for alert sync cursor persistence.
+// GetSyncCursor returns domain.ErrNotFound for sources that have never been
+// synced.
+type SyncCursorStorage interface {
+	GetSyncCursor(ctx context.Context, source string) (*domain.SyncCursor, error)
+	UpsertSyncCursor(ctx context.Context, cursor *domain.SyncCursor) error
+}
+
+// ExportWatermarkStorage defines methods for analytics export watermark
+// persistence. GetExportWatermark returns domain.ErrNotFound for tables that
+// have never been exported.
+type ExportWatermarkStorage interface {
+	GetExportWatermark(ctx context.Context, table string) (*domain.ExportWatermark, error)
+	UpsertExportWatermark(ctx context.Context, watermark *domain.ExportWatermark) error
+}
+
+// RetentionRunStorage defines methods for retention run persistence.
+// GetRetentionRun and UpdateRetentionRun return domain.ErrNotFound for
+// unknown runs.
+type RetentionRunStorage interface {
+	CreateRetentionRun(ctx context.Context, run *domain.RetentionRun) error
+	UpdateRetentionRun(ctx context.Context, run *domain.RetentionRun) error
+	GetRetentionRun(ctx context.Context, id uuid.UUID) (*domain.RetentionRun, error)
+	// ListRetentionRuns lists retention runs, most recently started first
+	ListRetentionRuns(ctx context.Context) ([]*domain.RetentionRun, error)
+}
+
+// ProcessedEventStorage defines methods for recording processed event
+// deliveries. CreateProcessedEvent returns domain.ErrConflict when the event
+// has already been recorded.
+type ProcessedEventStorage interface {
+	CreateProcessedEvent(ctx context.Context, event *domain.ProcessedEvent) error
+	// PurgeProcessedEvents deletes the events from source processed before
+	// processedBefore, returning how many were deleted
+	PurgeProcessedEvents(ctx context.Context, source string, processedBefore time.Time) (int, error)
+}
+
+// ImportRunStorage defines methods for historical import run persistence.
+// GetImportRun and UpdateImportRun return domain.ErrNotFound for unknown runs.
+type ImportRunStorage interface {
+	CreateImportRun(ctx context.Context, run *domain.ImportRun) error
+	UpdateImportRun(ctx context.Context, run *domain.ImportRun) error
+	GetImportRun(ctx context.Context, id uuid.UUID) (*domain.ImportRun, error)
+	// ListImportRuns lists import runs, optionally only those from
+	// provider, most recently started first
+	ListImportRuns(ctx context.Context, provider string) ([]*domain.ImportRun, error)
+}
+
+// SavedViewStorage defines methods for saved view persistence.
+// CreateSavedView returns domain.ErrConflict when a view with the same name
+// exists; the other methods return domain.ErrNotFound for unknown views.
+type SavedViewStorage interface {
+	CreateSavedView(ctx context.Context, view *domain.SavedView) error
+	GetSavedView(ctx context.Context, id uuid.UUID) (*domain.SavedView, error)
+	// ListSavedViews returns every saved view ordered by name
+	ListSavedViews(ctx context.Context) ([]*domain.SavedView, error)
+	// UpdateSavedView saves a view's name, filter, subscription and last
+	// summary time
+	UpdateSavedView(ctx context.Context, view *domain.SavedView) error
+	DeleteSavedView(ctx context.Context, id uuid.UUID) error
+}
+
+// ServiceStorage defines methods for service catalogue persistence.
+// CreateService returns domain.ErrConflict when a service with the same name
+// exists; the other methods return domain.ErrNotFound for unknown services.
+type ServiceStorage interface {
+	CreateService(ctx context.Context, svc *domain.Service) error
+	GetService(ctx context.Context, name string) (*domain.Service, error)
+	// ListServices returns every catalogued service ordered by name
+	ListServices(ctx context.Context) ([]*domain.Service, error)
+	// UpdateService saves every field of a service but its name and
+	// creation time
+	UpdateService(ctx context.Context, svc *domain.Service) error
+	DeleteService(ctx context.Context, name string) error
+}
+
+// ActionItemStorage defines methods for action item persistence. Action
+// items are removed along with their outage. GetActionItem, UpdateActionItem
+// and DeleteActionItem return domain.ErrNotFound for unknown items.
+type ActionItemStorage interface {
+	CreateActionItem(ctx context.Context, item *domain.ActionItem) error
+	GetActionItem(ctx context.Context, id uuid.UUID) (*domain.ActionItem, error)
+	// ListActionItemsByOutage returns an outage's action items, oldest first
+	ListActionItemsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.ActionItem, error)
+	// ListOverdueActionItems returns the open and in-progress action items
+	// due before the given time, earliest due first
+	ListOverdueActionItems(ctx context.Context, before time.Time) ([]*domain.ActionItem, error)
+	// UpdateActionItem saves an item's description, assignee, due date,
+	// status and closing time
+	UpdateActionItem(ctx context.Context, item *domain.ActionItem) error
+	DeleteActionItem(ctx context.Context, id uuid.UUID) error
+}
+
+// WatcherStorage defines methods for outage watcher persistence. Watchers
+// are removed along with their outage. SaveWatcher replaces the channel of
+// an existing watcher; RemoveWatcher returns domain.ErrNotFound when the
+// user is not watching the outage.
+type WatcherStorage interface {
+	SaveWatcher(ctx context.Context, watcher *domain.Watcher) error
+	RemoveWatcher(ctx context.Context, outageID uuid.UUID, email string) error
+	// ListWatchers returns an outage's watchers, oldest first
+	ListWatchers(ctx context.Context, outageID uuid.UUID) ([]*domain.Watcher, error)
+}
+
+// RelationStorage defines methods for outage relation persistence.
+// Relations are stored as created, from OutageID to RelatedOutageID, and
+// removed along with either outage. CreateOutageRelation returns
+// domain.ErrConflict when the same link already exists.
+type RelationStorage interface {
+	CreateOutageRelation(ctx context.Context, relation *domain.OutageRelation) error
+	GetOutageRelation(ctx context.Context, id uuid.UUID) (*domain.OutageRelation, error)
+	// ListOutageRelations returns the relations with the outage at either
+	// end, oldest first, with RelatedOutageTitle set to the title of the
+	// outage at the other end. Relations to outages in the trash are left
+	// out.
+	ListOutageRelations(ctx context.Context, outageID uuid.UUID) ([]*domain.OutageRelation, error)
+	DeleteOutageRelation(ctx context.Context, id uuid.UUID) error
+}
+
+// IngestionStorage defines methods for tracking the latest alert ingestion
+// attempts per source. Recording a success leaves the last failure intact
+// and vice versa.
+type IngestionStorage interface {
+	RecordIngestionSuccess(ctx context.Context, source string, at time.Time) error
+	RecordIngestionFailure(ctx context.Context, source string, at time.Time, message string) error
+	// ListIngestionRecords returns records ordered by source
+	ListIngestionRecords(ctx context.Context) ([]*domain.IngestionRecord, error)
+}
+
+// ConfigResourceStorage defines methods for declaratively managed
+// operational config (teams, tag schemas, routing rules and templates),
+// keyed by kind and name.
+type ConfigResourceStorage interface {
+	// ListConfigResources returns resources ordered by kind and name. An
+	// empty kind lists every kind.
+	ListConfigResources(ctx context.Context, kind string) ([]*domain.ConfigResource, error)
+	GetConfigResource(ctx context.Context, kind, name string) (*domain.ConfigResource, error)
+	UpsertConfigResource(ctx context.Context, resource *domain.ConfigResource) error
+	DeleteConfigResource(ctx context.Context, kind, name string) error
+}
+
+// AttachmentStorage defines methods for attachment metadata persistence. The
+// content itself lives in blob storage. Attachments are removed along with
+// their outage; deleting the referencing note only clears NoteID.
+type AttachmentStorage interface {
+	CreateAttachment(ctx context.Context, attachment *domain.Attachment) error
+	GetAttachment(ctx context.Context, id uuid.UUID) (*domain.Attachment, error)
+	// ListAttachmentsByOutage returns attachments oldest first
+	ListAttachmentsByOutage(ctx context.Context, outageID uuid.UUID) ([]*domain.Attachment, error)
+	DeleteAttachment(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,3229 @@
+// Package storagetest is a conformance suite for storage.Storage
+// implementations. Backends run it from their own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.Storage {
+//			return newStore(t)
+//		})
+//	}
+//
+// The suite checks the behaviour callers rely on regardless of backend:
+// domain.ErrNotFound for missing records, domain.ErrConflict for duplicate
+// note revisions and processed events, alerts stored once per source and
+// external ID, deletes that cascade from an outage to its alerts, notes,
+// tags, status changes, alert events and review, a trash that hides
+// outages and notes from lists until they are restored or purged, JSON
+// metadata, custom fields and import statistics that survive a round-trip,
+// list ordering, and upserts.
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conall/outalator/domain"
+	"github.com/conall/outalator/storage"
+	"github.com/google/uuid"
+)
+
+// Factory returns an empty store for one test. It registers any cleanup,
+// such as closing the store, with t.Cleanup.
+type Factory func(t *testing.T) storage.Storage
+
+// Run runs the conformance suite against stores created by newStorage,
+// each as a subtest with a fresh store
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, Factory)
+	}{
+		{"Outage/CRUD", testOutageCRUD},
+		{"Outage/NotFound", testOutageNotFound},
+		{"Outage/ListPagination", testOutageListPagination},
+		{"Outage/ListLimitZero", testOutageListLimitZero},
+		{"Outage/ListByTeams", testOutageListByTeams},
+		{"Outage/NilMetadata", testOutageNilMetadata},
+		{"Outage/EagerLoadsRelations", testGetOutageEagerLoadsRelations},
+		{"Outage/LoadAssociations", testLoadOutageAssociations},
+		{"Outage/GetWithAssociations", testGetOutageWith},
+		{"Outage/CascadeDelete", testOutageCascadeDelete},
+		{"Outage/TrashRestorePurge", testOutageTrashRestorePurge},
+		{"Outage/ImpactRoundTrip", testOutageImpactRoundTrip},
+		{"Outage/Children", testOutageChildren},
+		{"Outage/SetParent", testSetOutageParent},
+		{"Outage/ListPublic", testOutageListPublic},
+		{"Outage/Relations", testOutageRelations},
+		{"Alert/CRUD", testAlertCRUD},
+		{"Alert/ListOpen", testListOpenAlerts},
+		{"Alert/ListTriggeredBetween", testListAlertsTriggeredBetween},
+		{"Alert/List", testListAlerts},
+		{"Alert/NotFound", testAlertNotFound},
+		{"Alert/DuplicateExternalID", testAlertDuplicateExternalID},
+		{"Alert/Upsert", testAlertUpsert},
+		{"Alert/CreateOutageWithAlert", testCreateOutageWithAlert},
+		{"Alert/CascadeDeleteWithOutage", testAlertCascadeDeleteWithOutage},
+		{"StatusChange/ListAndCascade", testStatusChangeListAndCascade},
+		{"AlertEvent/IdempotentListAndCascade", testAlertEventIdempotentListAndCascade},
+		{"Note/CRUD", testNoteCRUD},
+		{"Note/LogRoundTrip", testNoteLogRoundTrip},
+		{"Note/NotFound", testNoteNotFound},
+		{"Note/TrashRestorePurge", testNoteTrashRestorePurge},
+		{"Note/Replies", testNoteReplies},
+		{"NoteRevision/ListAndCascade", testNoteRevisionListAndCascade},
+		{"ResponderAssignment/HistoryAndCascade", testResponderAssignmentHistoryAndCascade},
+		{"Tag/CRUD", testTagCRUD},
+		{"Tag/NotFound", testTagNotFound},
+		{"Tag/UniqueKey", testTagUniqueKey},
+		{"Tag/ListByKey", testListTagsByKey},
+		{"Tag/FindOutages", testFindOutagesByTag},
+		{"Tag/FindOutagesByQuery", testFindOutagesByTagQuery},
+		{"Tag/Usage", testTagUsage},
+		{"UserPreferences/Upsert", testUserPreferencesUpsert},
+		{"OutageReview/UpsertAndList", testOutageReviewUpsertAndList},
+		{"SyncCursor/Upsert", testSyncCursorUpsert},
+		{"ExportWatermark/Upsert", testExportWatermarkUpsert},
+		{"ProcessedEvent/ConflictAndPurge", testProcessedEventConflictAndPurge},
+		{"ImportRun/CRUD", testImportRunCRUD},
+		{"RetentionRun/CRUD", testRetentionRunCRUD},
+		{"SavedView/CRUD", testSavedViewCRUD},
+		{"ActionItem/CRUD", testActionItemCRUD},
+		{"Service/CRUD", testServiceCRUD},
+		{"Watcher/SaveAndRemove", testWatcherSaveAndRemove},
+		{"Ingestion/RecordAndList", testIngestionRecordAndList},
+		{"ConfigResource/CRUD", testConfigResourceCRUD},
+		{"Attachment/CRUD", testAttachmentCRUD},
+		{"Attachment/NoteAndOutageDelete", testAttachmentNoteAndOutageDelete},
+		{"CustomFieldsRoundTrip", testCustomFieldsRoundTrip},
+		{"Schema/UpToDate", testSchemaUpToDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage)
+		})
+	}
+}
+
+// now returns the current time at second precision, which every backend
+// stores exactly
+func now() time.Time { return time.Now().UTC().Truncate(time.Second) }
+
+// createOutage stores a minimal open outage
+func createOutage(t *testing.T, s storage.Storage) *domain.Outage {
+	t.Helper()
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(context.Background(), outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+	return outage
+}
+
+// customFields is structured data as it decodes from JSON, so stored
+// values compare equal after a round-trip
+func customFields() map[string]any {
+	return map[string]any{
+		"impact":  map[string]any{"users": float64(1200), "revenue": true},
+		"regions": []any{"eu-west-1", "us-east-1"},
+		"owner":   "payments",
+	}
+}
+
+// ── Outage ────────────────────────────────────────────────────────────────────
+
+func testOutageCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID:          uuid.New(),
+		Title:       "Database latency spike",
+		Description: "P99 latency > 5 s on primary",
+		Status:      "open",
+		Severity:    "high",
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+		Metadata:    map[string]string{"team": "platform"},
+	}
+
+	// Create
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	// Get — full record with eager-loaded slices present (empty, not nil)
+	got, err := s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if got.Title != outage.Title {
+		t.Errorf("Title: got %q, want %q", got.Title, outage.Title)
+	}
+	if got.Metadata["team"] != "platform" {
+		t.Errorf("Metadata[team]: got %q, want %q", got.Metadata["team"], "platform")
+	}
+
+	if got.InvestigatingAt != nil || got.MitigatedAt != nil || got.ResolvedAt != nil {
+		t.Errorf("transition times set on a new outage: %+v", got)
+	}
+
+	// Update
+	investigating, mitigated := now().Add(time.Minute), now().Add(2*time.Minute)
+	outage.Title = "Database latency spike — resolved"
+	outage.Status = "resolved"
+	outage.UpdatedAt = now()
+	outage.InvestigatingAt = &investigating
+	outage.MitigatedAt = &mitigated
+	if err := s.UpdateOutage(ctx, outage); err != nil {
+		t.Fatalf("UpdateOutage: %v", err)
+	}
+	got, err = s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage after update: %v", err)
+	}
+	if got.Status != "resolved" {
+		t.Errorf("Status: got %q, want %q", got.Status, "resolved")
+	}
+	if got.InvestigatingAt == nil || !got.InvestigatingAt.Equal(investigating) {
+		t.Errorf("InvestigatingAt: got %v, want %v", got.InvestigatingAt, investigating)
+	}
+	if got.MitigatedAt == nil || !got.MitigatedAt.Equal(mitigated) {
+		t.Errorf("MitigatedAt: got %v, want %v", got.MitigatedAt, mitigated)
+	}
+
+	// List
+	list, err := s.ListOutages(ctx, 10, 0, false)
+	if err != nil {
+		t.Fatalf("ListOutages: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("ListOutages count: got %d, want 1", len(list))
+	}
+
+	// Delete
+	if err := s.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+
+	// Not-found after delete
+	_, err = s.GetOutage(ctx, outage.ID)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutage after delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testOutageNotFound(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	_, err := s.GetOutage(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutage missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	err = s.UpdateOutage(ctx, &domain.Outage{ID: uuid.New(), UpdatedAt: now()})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateOutage missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	err = s.DeleteOutage(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteOutage missing: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testOutageListPagination(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	for i := 0; i < 5; i++ {
+		o := &domain.Outage{
+			ID:        uuid.New(),
+			Title:     "outage",
+			Status:    "open",
+			Severity:  "low",
+			CreatedAt: now(),
+			UpdatedAt: now(),
+		}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage %d: %v", i, err)
+		}
+	}
+
+	page1, err := s.ListOutages(ctx, 3, 0, false)
+	if err != nil {
+		t.Fatalf("ListOutages page 1: %v", err)
+	}
+	if len(page1) != 3 {
+		t.Errorf("page 1 count: got %d, want 3", len(page1))
+	}
+
+	page2, err := s.ListOutages(ctx, 3, 3, false)
+	if err != nil {
+		t.Fatalf("ListOutages page 2: %v", err)
+	}
+	if len(page2) != 2 {
+		t.Errorf("page 2 count: got %d, want 2", len(page2))
+	}
+}
+
+func testOutageListLimitZero(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	for i := 0; i < 3; i++ {
+		o := &domain.Outage{
+			ID:        uuid.New(),
+			Title:     "outage",
+			Status:    "open",
+			Severity:  "low",
+			CreatedAt: now(),
+			UpdatedAt: now(),
+		}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage %d: %v", i, err)
+		}
+	}
+
+	results, err := s.ListOutages(ctx, 0, 0, false)
+	if err != nil {
+		t.Fatalf("ListOutages(limit=0): %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("ListOutages(limit=0): got %d rows, want 0", len(results))
+	}
+}
+
+func testOutageListByTeams(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	owners := []string{"payments", "payments", "search", ""}
+	ids := make([]uuid.UUID, len(owners))
+	for i, team := range owners {
+		o := &domain.Outage{
+			ID:         uuid.New(),
+			Title:      "outage",
+			Status:     "open",
+			Severity:   "low",
+			OwningTeam: team,
+			CreatedAt:  now(),
+			UpdatedAt:  now(),
+		}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage %d: %v", i, err)
+		}
+		ids[i] = o.ID
+	}
+	if err := s.TrashOutage(ctx, ids[1], now()); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+
+	got, err := s.GetOutage(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if got.OwningTeam != "payments" {
+		t.Errorf("OwningTeam: got %q, want %q", got.OwningTeam, "payments")
+	}
+
+	list, err := s.ListOutagesByTeams(ctx, []string{"payments"}, 10, 0, false)
+	if err != nil {
+		t.Fatalf("ListOutagesByTeams: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != ids[0] {
+		t.Errorf("ListOutagesByTeams(payments): got %d outages, want only %s", len(list), ids[0])
+	}
+
+	list, err = s.ListOutagesByTeams(ctx, []string{"payments", "search"}, 10, 0, true)
+	if err != nil {
+		t.Fatalf("ListOutagesByTeams including deleted: %v", err)
+	}
+	if len(list) != 3 {
+		t.Errorf("ListOutagesByTeams(payments, search) including deleted: got %d outages, want 3", len(list))
+	}
+
+	list, err = s.ListOutagesByTeams(ctx, nil, 10, 0, true)
+	if err != nil {
+		t.Fatalf("ListOutagesByTeams(nil): %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("ListOutagesByTeams(nil): got %d outages, want 0", len(list))
+	}
+
+	got.OwningTeam = ""
+	if err := s.UpdateOutage(ctx, got); err != nil {
+		t.Fatalf("UpdateOutage: %v", err)
+	}
+	list, err = s.ListOutagesByTeams(ctx, []string{"payments"}, 10, 0, false)
+	if err != nil {
+		t.Fatalf("ListOutagesByTeams after clearing owner: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("ListOutagesByTeams after clearing owner: got %d outages, want 0", len(list))
+	}
+}
+
+func testOutageListPublic(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	create := func(status, public string, age time.Duration) *domain.Outage {
+		t.Helper()
+		o := &domain.Outage{
+			ID:        uuid.New(),
+			Title:     "outage",
+			Status:    status,
+			Severity:  "high",
+			Metadata:  map[string]string{},
+			CreatedAt: now().Add(-age),
+			UpdatedAt: now(),
+		}
+		if public != "" {
+			o.Metadata[domain.MetadataPublic] = public
+		}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage: %v", err)
+		}
+		return o
+	}
+	newer := create("investigating", "true", time.Hour)
+	older := create("open", "true", 2*time.Hour)
+	create("open", "", time.Hour)
+	create("open", "false", time.Hour)
+	create("resolved", "true", time.Hour)
+	create("closed", "true", time.Hour)
+	trashed := create("open", "true", time.Hour)
+	if err := s.TrashOutage(ctx, trashed.ID, now()); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+
+	list, err := s.ListPublicOutages(ctx)
+	if err != nil {
+		t.Fatalf("ListPublicOutages: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != older.ID || list[1].ID != newer.ID {
+		t.Errorf("ListPublicOutages = %v, want %s then %s", outageIDs(list), older.ID, newer.ID)
+	}
+}
+
+// ── Alert ─────────────────────────────────────────────────────────────────────
+
+func testAlertCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	alert := &domain.Alert{
+		ID:          uuid.New(),
+		OutageID:    outage.ID,
+		ExternalID:  "pd-12345",
+		Source:      "pagerduty",
+		TeamName:    "payments",
+		TeamNames:   []string{"payments", "search"},
+		Title:       "Latency alert",
+		Severity:    "high",
+		Service:     "checkout",
+		Fingerprint: "3f2c9a1e8b7d4c6e",
+		TriggeredAt: now(),
+		CreatedAt:   now(),
+		Metadata:    map[string]string{"service": "api"},
+	}
+
+	// Create
+	if err := s.CreateAlert(ctx, alert); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+
+	// Get by ID
+	got, err := s.GetAlert(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetAlert: %v", err)
+	}
+	if got.ExternalID != alert.ExternalID {
+		t.Errorf("ExternalID: got %q, want %q", got.ExternalID, alert.ExternalID)
+	}
+	if got.Metadata["service"] != "api" {
+		t.Errorf("Metadata[service]: got %q, want %q", got.Metadata["service"], "api")
+	}
+	if !reflect.DeepEqual(got.TeamNames, alert.TeamNames) {
+		t.Errorf("TeamNames: got %v, want %v", got.TeamNames, alert.TeamNames)
+	}
+	if got.Service != "checkout" {
+		t.Errorf("Service: got %q, want %q", got.Service, "checkout")
+	}
+	if got.Fingerprint != alert.Fingerprint {
+		t.Errorf("Fingerprint: got %q, want %q", got.Fingerprint, alert.Fingerprint)
+	}
+
+	// Get by external ID
+	got, err = s.GetAlertByExternalID(ctx, alert.ExternalID, alert.Source)
+	if err != nil {
+		t.Fatalf("GetAlertByExternalID: %v", err)
+	}
+	if got.ID != alert.ID {
+		t.Errorf("ID mismatch: got %s, want %s", got.ID, alert.ID)
+	}
+
+	// List by outage
+	list, err := s.ListAlertsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListAlertsByOutage: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("list count: got %d, want 1", len(list))
+	}
+
+	// Update
+	alert.Title = "Latency alert — ack"
+	alert.TeamNames = append(alert.TeamNames, "storage")
+	alert.Service = ""
+	alert.Fingerprint = "9fceb02d0ae598e9"
+	if err := s.UpdateAlert(ctx, alert); err != nil {
+		t.Fatalf("UpdateAlert: %v", err)
+	}
+	got, err = s.GetAlert(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetAlert after update: %v", err)
+	}
+	if got.Title != "Latency alert — ack" {
+		t.Errorf("Title after update: got %q, want %q", got.Title, "Latency alert — ack")
+	}
+	if !reflect.DeepEqual(got.TeamNames, alert.TeamNames) {
+		t.Errorf("TeamNames after update: got %v, want %v", got.TeamNames, alert.TeamNames)
+	}
+	if got.Service != "" {
+		t.Errorf("Service after update: got %q, want it cleared", got.Service)
+	}
+	if got.Fingerprint != "9fceb02d0ae598e9" {
+		t.Errorf("Fingerprint after update: got %q, want %q", got.Fingerprint, "9fceb02d0ae598e9")
+	}
+}
+
+func testListOpenAlerts(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	resolved := now()
+	for _, a := range []struct {
+		externalID string
+		age        time.Duration
+		resolvedAt *time.Time
+	}{
+		{"newer-stale", 2 * time.Hour, nil},
+		{"oldest-stale", 5 * time.Hour, nil},
+		{"fresh", 10 * time.Minute, nil},
+		{"resolved", 5 * time.Hour, &resolved},
+	} {
+		alert := &domain.Alert{
+			ID: uuid.New(), OutageID: outage.ID, ExternalID: a.externalID, Source: "pagerduty",
+			TriggeredAt: now().Add(-a.age), ResolvedAt: a.resolvedAt, CreatedAt: now(),
+		}
+		if err := s.CreateAlert(ctx, alert); err != nil {
+			t.Fatalf("CreateAlert: %v", err)
+		}
+	}
+
+	open, err := s.ListOpenAlerts(ctx, now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListOpenAlerts: %v", err)
+	}
+	var got []string
+	for _, a := range open {
+		got = append(got, a.ExternalID)
+	}
+	if len(got) != 2 || got[0] != "oldest-stale" || got[1] != "newer-stale" {
+		t.Errorf("ListOpenAlerts = %v, want [oldest-stale newer-stale]", got)
+	}
+}
+
+func testListAlertsTriggeredBetween(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	resolved := now()
+	for _, a := range []struct {
+		externalID string
+		age        time.Duration
+		resolvedAt *time.Time
+	}{
+		{"too-old", 5 * time.Hour, nil},
+		{"newer", time.Hour, nil},
+		{"older-resolved", 3 * time.Hour, &resolved},
+		{"too-new", 0, nil},
+	} {
+		alert := &domain.Alert{
+			ID: uuid.New(), OutageID: outage.ID, ExternalID: a.externalID, Source: "pagerduty",
+			TriggeredAt: now().Add(-a.age), ResolvedAt: a.resolvedAt, CreatedAt: now(),
+		}
+		if err := s.CreateAlert(ctx, alert); err != nil {
+			t.Fatalf("CreateAlert: %v", err)
+		}
+	}
+
+	alerts, err := s.ListAlertsTriggeredBetween(ctx, now().Add(-4*time.Hour), now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("ListAlertsTriggeredBetween: %v", err)
+	}
+	var got []string
+	for _, a := range alerts {
+		got = append(got, a.ExternalID)
+	}
+	if len(got) != 2 || got[0] != "older-resolved" || got[1] != "newer" {
+		t.Errorf("ListAlertsTriggeredBetween = %v, want [older-resolved newer]", got)
+	}
+}
+
+func testListAlerts(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	resolved := now()
+	for _, a := range []struct {
+		externalID, source, severity string
+		teams                        []string
+		age                          time.Duration
+		resolvedAt                   *time.Time
+	}{
+		{"old", "pagerduty", "high", []string{"payments", "core"}, 5 * time.Hour, nil},
+		{"resolved", "pagerduty", "low", []string{"search"}, 3 * time.Hour, &resolved},
+		{"recent", "opsgenie", "high", []string{"core"}, time.Hour, nil},
+	} {
+		alert := &domain.Alert{
+			ID: uuid.New(), OutageID: outage.ID, ExternalID: a.externalID, Source: a.source, Severity: a.severity,
+			TeamName: a.teams[0], TeamNames: a.teams, TriggeredAt: now().Add(-a.age), ResolvedAt: a.resolvedAt, CreatedAt: now(),
+		}
+		if err := s.CreateAlert(ctx, alert); err != nil {
+			t.Fatalf("CreateAlert: %v", err)
+		}
+	}
+
+	yes, no := true, false
+	for _, tc := range []struct {
+		name  string
+		query domain.AlertQuery
+		want  []string
+	}{
+		{"all", domain.AlertQuery{}, []string{"recent", "resolved", "old"}},
+		{"source", domain.AlertQuery{Source: "pagerduty"}, []string{"resolved", "old"}},
+		{"secondary team", domain.AlertQuery{Team: "core"}, []string{"recent", "old"}},
+		{"severity", domain.AlertQuery{Severity: "high"}, []string{"recent", "old"}},
+		{"range", domain.AlertQuery{Since: now().Add(-4 * time.Hour), Until: now().Add(-2 * time.Hour)}, []string{"resolved"}},
+		{"resolved", domain.AlertQuery{Resolved: &yes}, []string{"resolved"}},
+		{"unresolved", domain.AlertQuery{Resolved: &no, Source: "pagerduty"}, []string{"old"}},
+		{"page", domain.AlertQuery{Limit: 1, Offset: 1}, []string{"resolved"}},
+		{"past the end", domain.AlertQuery{Offset: 3}, nil},
+	} {
+		q := tc.query
+		if q.Limit == 0 {
+			q.Limit = 10
+		}
+		alerts, err := s.ListAlerts(ctx, q)
+		if err != nil {
+			t.Fatalf("ListAlerts %s: %v", tc.name, err)
+		}
+		var got []string
+		for _, a := range alerts {
+			got = append(got, a.ExternalID)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("ListAlerts %s = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func testAlertNotFound(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	_, err := s.GetAlert(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAlert missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	_, err = s.GetAlertByExternalID(ctx, "nope", "pagerduty")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAlertByExternalID missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	err = s.UpdateAlert(ctx, &domain.Alert{
+		ID: uuid.New(), OutageID: uuid.New(),
+		TriggeredAt: now(), CreatedAt: now(),
+	})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateAlert missing: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testAlertDuplicateExternalID(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	newAlert := func(source string) *domain.Alert {
+		return &domain.Alert{
+			ID: uuid.New(), OutageID: outage.ID, ExternalID: "PD-1", Source: source,
+			Title: "a", Severity: "high", TriggeredAt: now(), CreatedAt: now(),
+		}
+	}
+	first := newAlert("pagerduty")
+	if err := s.CreateAlert(ctx, first); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	// A duplicate leaves the stored alert in place and is set to it
+	duplicate := newAlert("pagerduty")
+	if err := s.CreateAlert(ctx, duplicate); err != nil {
+		t.Fatalf("CreateAlert duplicate: %v", err)
+	}
+	if duplicate.ID != first.ID {
+		t.Errorf("CreateAlert duplicate ID = %s, want the stored alert %s", duplicate.ID, first.ID)
+	}
+	// The same external ID from another source is a different alert
+	if err := s.CreateAlert(ctx, newAlert("opsgenie")); err != nil {
+		t.Errorf("CreateAlert from another source: %v", err)
+	}
+	alerts, err := s.ListAlertsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListAlertsByOutage: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Errorf("ListAlertsByOutage = %d alerts, want 2", len(alerts))
+	}
+}
+
+func testAlertUpsert(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	acked := now().Add(-time.Minute)
+	alert := &domain.Alert{
+		ID: uuid.New(), OutageID: outage.ID, ExternalID: "PD-1", Source: "pagerduty",
+		Title: "a", Severity: "high", TriggeredAt: now(), CreatedAt: now(), AcknowledgedAt: &acked,
+	}
+	stored, created, err := s.UpsertAlert(ctx, alert)
+	if err != nil {
+		t.Fatalf("UpsertAlert: %v", err)
+	}
+	if !created || stored.ID != alert.ID {
+		t.Fatalf("UpsertAlert new = %s created %v, want %s created", stored.ID, created, alert.ID)
+	}
+
+	// A later delivery fills in the resolved time but keeps the first
+	// acknowledgement and everything else
+	later, resolved := now(), now()
+	redelivered := &domain.Alert{
+		ID: uuid.New(), OutageID: outage.ID, ExternalID: "PD-1", Source: "pagerduty",
+		Title: "changed", Severity: "low", TriggeredAt: now(), CreatedAt: now(),
+		AcknowledgedAt: &later, ResolvedAt: &resolved,
+	}
+	stored, created, err = s.UpsertAlert(ctx, redelivered)
+	if err != nil {
+		t.Fatalf("UpsertAlert again: %v", err)
+	}
+	if created || stored.ID != alert.ID {
+		t.Fatalf("UpsertAlert again = %s created %v, want %s not created", stored.ID, created, alert.ID)
+	}
+	if stored.Title != "a" || stored.AcknowledgedAt == nil || !stored.AcknowledgedAt.Equal(acked) ||
+		stored.ResolvedAt == nil || !stored.ResolvedAt.Equal(resolved) {
+		t.Errorf("upserted alert = %+v, want the first title and acknowledgement and the new resolved time", stored)
+	}
+	if got, err := s.GetAlert(ctx, alert.ID); err != nil || got.ResolvedAt == nil {
+		t.Errorf("GetAlert after upsert = %+v, %v; want the resolved time stored", got, err)
+	}
+	if _, err := s.GetAlert(ctx, redelivered.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAlert(redelivered ID) err = %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testCreateOutageWithAlert(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	newOutage := func() *domain.Outage {
+		return &domain.Outage{ID: uuid.New(), Title: "disk full", Status: "open", Severity: "high", CreatedAt: now(), UpdatedAt: now()}
+	}
+	newAlert := func(resolved *time.Time) *domain.Alert {
+		return &domain.Alert{
+			ID: uuid.New(), ExternalID: "PD-1", Source: "pagerduty",
+			Title: "disk full", Severity: "high", TriggeredAt: now(), CreatedAt: now(), ResolvedAt: resolved,
+		}
+	}
+
+	outage, alert := newOutage(), newAlert(nil)
+	stored, created, err := s.CreateOutageWithAlert(ctx, outage, alert)
+	if err != nil {
+		t.Fatalf("CreateOutageWithAlert: %v", err)
+	}
+	if !created || stored.ID != alert.ID || stored.OutageID != outage.ID {
+		t.Fatalf("CreateOutageWithAlert new = %+v created %v, want %s on %s", stored, created, alert.ID, outage.ID)
+	}
+	if alerts, err := s.ListAlertsByOutage(ctx, outage.ID); err != nil || len(alerts) != 1 || alerts[0].ID != alert.ID {
+		t.Errorf("ListAlertsByOutage = %d alerts, %v; want the new alert", len(alerts), err)
+	}
+
+	// A second delivery of the alert fills in its resolved time and keeps
+	// no outage, not even in the trash
+	resolved := now()
+	duplicate := newOutage()
+	stored, created, err = s.CreateOutageWithAlert(ctx, duplicate, newAlert(&resolved))
+	if err != nil {
+		t.Fatalf("CreateOutageWithAlert again: %v", err)
+	}
+	if created || stored.ID != alert.ID || stored.OutageID != outage.ID || stored.ResolvedAt == nil {
+		t.Errorf("CreateOutageWithAlert again = %+v created %v, want %s on %s, resolved", stored, created, alert.ID, outage.ID)
+	}
+	if _, err := s.GetOutage(ctx, duplicate.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutage(duplicate's outage) err = %v, want domain.ErrNotFound", err)
+	}
+	if all, err := s.ListOutages(ctx, 10, 0, true); err != nil || len(all) != 1 {
+		t.Errorf("ListOutages including the trash = %d, %v; want 1", len(all), err)
+	}
+}
+
+func testAlertCascadeDeleteWithOutage(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	alert := &domain.Alert{
+		ID: uuid.New(), OutageID: outage.ID,
+		ExternalID: "x", Source: "opsgenie",
+		Title: "t", TriggeredAt: now(), CreatedAt: now(),
+	}
+	if err := s.CreateAlert(ctx, alert); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+
+	// Deleting the outage should cascade-delete the alert.
+	if err := s.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	_, err := s.GetAlert(ctx, alert.ID)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAlert after cascade delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+// ── Status changes ────────────────────────────────────────────────────────────
+
+func testStatusChangeListAndCascade(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	// Insert out of order to check ListStatusChangesByOutage sorts by time.
+	later := &domain.StatusChange{
+		ID: uuid.New(), OutageID: outage.ID,
+		FromStatus: "open", ToStatus: "resolved", ChangedAt: now().Add(time.Hour),
+		Action: "resolve", Actor: "alice@example.com", Reason: "rolled back deploy",
+	}
+	initial := &domain.StatusChange{
+		ID: uuid.New(), OutageID: outage.ID,
+		ToStatus: "open", ChangedAt: now(),
+	}
+	for _, c := range []*domain.StatusChange{later, initial} {
+		if err := s.CreateStatusChange(ctx, c); err != nil {
+			t.Fatalf("CreateStatusChange: %v", err)
+		}
+	}
+
+	changes, err := s.ListStatusChangesByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListStatusChangesByOutage: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 status changes, got %d", len(changes))
+	}
+	if changes[0].ID != initial.ID || changes[1].ID != later.ID {
+		t.Errorf("status changes not ordered by changed_at")
+	}
+	if changes[1].FromStatus != "open" || changes[1].ToStatus != "resolved" {
+		t.Errorf("change = %q -> %q, want open -> resolved", changes[1].FromStatus, changes[1].ToStatus)
+	}
+	if changes[1].Action != "resolve" || changes[1].Actor != "alice@example.com" || changes[1].Reason != "rolled back deploy" {
+		t.Errorf("change audit = %q by %q (%q), want resolve by alice@example.com", changes[1].Action, changes[1].Actor, changes[1].Reason)
+	}
+
+	if err := s.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	changes, err = s.ListStatusChangesByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListStatusChangesByOutage after delete: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected status changes to cascade-delete, got %d", len(changes))
+	}
+}
+
+// ── Alert events ─────────────────────────────────────────────────────────────
+
+func testAlertEventIdempotentListAndCascade(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+	alert := &domain.Alert{
+		ID: uuid.New(), OutageID: outage.ID,
+		ExternalID: "P1", Source: "pagerduty",
+		Title: "t", TriggeredAt: now(), CreatedAt: now(),
+	}
+	if err := s.CreateAlert(ctx, alert); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+
+	escalated := &domain.AlertEvent{
+		ID: uuid.New(), AlertID: alert.ID, ExternalID: "L2", Type: "escalated",
+		Summary: "Escalated to level 2", OccurredAt: now().Add(time.Minute), CreatedAt: now(),
+	}
+	notified := &domain.AlertEvent{
+		ID: uuid.New(), AlertID: alert.ID, ExternalID: "L1", Type: "notified",
+		Summary: "Notified Alice via SMS", Target: "Alice", OccurredAt: now(), CreatedAt: now(),
+	}
+	// The second copy of L1 has a new ID but the same external ID and must be ignored.
+	duplicate := *notified
+	duplicate.ID = uuid.New()
+	for _, e := range []*domain.AlertEvent{escalated, notified, &duplicate} {
+		if err := s.CreateAlertEvent(ctx, e); err != nil {
+			t.Fatalf("CreateAlertEvent: %v", err)
+		}
+	}
+
+	events, err := s.ListAlertEventsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListAlertEventsByOutage: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 alert events, got %d", len(events))
+	}
+	if events[0].ID != notified.ID || events[1].ID != escalated.ID {
+		t.Errorf("alert events not ordered by occurred_at")
+	}
+	if events[0].Target != "Alice" || events[0].AlertID != alert.ID {
+		t.Errorf("event = %+v, want target Alice on alert %s", events[0], alert.ID)
+	}
+
+	if err := s.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	events, err = s.ListAlertEventsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListAlertEventsByOutage after delete: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected alert events to cascade-delete, got %d", len(events))
+	}
+}
+
+// ── Note ──────────────────────────────────────────────────────────────────────
+
+func testNoteCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	note := &domain.Note{
+		ID:        uuid.New(),
+		OutageID:  outage.ID,
+		Content:   "Checked dashboards — latency from CDN edge.",
+		Format:    "plaintext",
+		Author:    "alice",
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	}
+
+	// Create
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	// Get
+	got, err := s.GetNote(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if got.Content != note.Content {
+		t.Errorf("Content: got %q, want %q", got.Content, note.Content)
+	}
+	if got.Author != "alice" {
+		t.Errorf("Author: got %q, want %q", got.Author, "alice")
+	}
+
+	// List
+	list, err := s.ListNotesByOutage(ctx, outage.ID, false)
+	if err != nil {
+		t.Fatalf("ListNotesByOutage: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("list count: got %d, want 1", len(list))
+	}
+
+	// Update — content changes, author must stay unchanged
+	note.Content = "Root cause: misconfigured CDN TTL."
+	note.UpdatedAt = now()
+	if err := s.UpdateNote(ctx, note); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	got, err = s.GetNote(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("GetNote after update: %v", err)
+	}
+	if got.Content != "Root cause: misconfigured CDN TTL." {
+		t.Errorf("Content after update: got %q", got.Content)
+	}
+	if got.Author != "alice" {
+		t.Errorf("Author changed unexpectedly: got %q", got.Author)
+	}
+
+	// Delete
+	if err := s.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	_, err = s.GetNote(ctx, note.ID)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetNote after delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testNoteLogRoundTrip(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	// Large enough that a backend may store it compressed; tabs, CRLF and
+	// trailing whitespace must survive
+	trace := strings.Repeat("java.lang.NullPointerException\r\n\tat com.example.Api.handle(Api.java:42)  \n", 200)
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, Content: trace, Format: domain.NoteFormatLog,
+		Author: "alice", CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	list, err := s.ListNotesByOutage(ctx, outage.ID, false)
+	if err != nil {
+		t.Fatalf("ListNotesByOutage: %v", err)
+	}
+	if len(list) != 1 || list[0].Format != domain.NoteFormatLog || list[0].Content != trace {
+		t.Fatalf("listed note does not match the log that was stored")
+	}
+
+	note.Content = trace + "Caused by: java.io.IOException\n"
+	if err := s.UpdateNote(ctx, note); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	got, err := s.GetNote(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if got.Format != domain.NoteFormatLog || got.Content != note.Content {
+		t.Errorf("updated note does not match the log that was stored")
+	}
+}
+
+func testNoteNotFound(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	_, err := s.GetNote(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetNote missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	err = s.UpdateNote(ctx, &domain.Note{ID: uuid.New(), OutageID: uuid.New(), UpdatedAt: now()})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateNote missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	err = s.DeleteNote(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteNote missing: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+// ── Tag ───────────────────────────────────────────────────────────────────────
+
+func testNoteReplies(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	root := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, Content: "root", Format: "plaintext",
+		Author: "alice", CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, root); err != nil {
+		t.Fatalf("CreateNote(root): %v", err)
+	}
+	reply := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, ParentNoteID: &root.ID, Content: "reply", Format: "plaintext",
+		Author: "bob", CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, reply); err != nil {
+		t.Fatalf("CreateNote(reply): %v", err)
+	}
+
+	got, err := s.GetNote(ctx, reply.ID)
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if got.ParentNoteID == nil || *got.ParentNoteID != root.ID {
+		t.Errorf("ParentNoteID: got %v, want %s", got.ParentNoteID, root.ID)
+	}
+	list, err := s.ListNotesByOutage(ctx, outage.ID, false)
+	if err != nil {
+		t.Fatalf("ListNotesByOutage: %v", err)
+	}
+	for _, n := range list {
+		if n.ID == root.ID && n.ParentNoteID != nil {
+			t.Errorf("root ParentNoteID: got %v, want nil", n.ParentNoteID)
+		}
+		if n.ID == reply.ID && (n.ParentNoteID == nil || *n.ParentNoteID != root.ID) {
+			t.Errorf("listed reply ParentNoteID: got %v, want %s", n.ParentNoteID, root.ID)
+		}
+	}
+
+	// Purging the parent leaves the reply as a top-level note
+	if err := s.DeleteNote(ctx, root.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	got, err = s.GetNote(ctx, reply.ID)
+	if err != nil {
+		t.Fatalf("GetNote after parent delete: %v", err)
+	}
+	if got.ParentNoteID != nil {
+		t.Errorf("ParentNoteID after parent delete: got %v, want nil", got.ParentNoteID)
+	}
+}
+
+func testNoteRevisionListAndCascade(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, Content: "v3", Format: "markdown",
+		Author: "alice", CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	trace := strings.Repeat("panic: runtime error\n\tmain.go:12\n", 300)
+	revisions := []*domain.NoteRevision{
+		{ID: uuid.New(), NoteID: note.ID, Version: 2, Content: trace, Format: domain.NoteFormatLog, EditedBy: "alice@example.com", EditedAt: now()},
+		{ID: uuid.New(), NoteID: note.ID, Version: 1, Content: "v1", Format: "plaintext", EditedBy: "bob@example.com", EditedAt: now()},
+	}
+	for _, r := range revisions {
+		if err := s.CreateNoteRevision(ctx, r); err != nil {
+			t.Fatalf("CreateNoteRevision(%d): %v", r.Version, err)
+		}
+	}
+	duplicate := &domain.NoteRevision{ID: uuid.New(), NoteID: note.ID, Version: 1, Content: "x", Format: "plaintext", EditedAt: now()}
+	if err := s.CreateNoteRevision(ctx, duplicate); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateNoteRevision duplicate version: got %v, want domain.ErrConflict", err)
+	}
+
+	got, err := s.ListNoteRevisions(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("ListNoteRevisions: %v", err)
+	}
+	if len(got) != 2 || got[0].Version != 1 || got[1].Version != 2 {
+		t.Fatalf("ListNoteRevisions: got %d revisions, want versions 1 and 2", len(got))
+	}
+	if got[0].Content != "v1" || got[0].EditedBy != "bob@example.com" || !got[0].EditedAt.Equal(revisions[1].EditedAt) {
+		t.Errorf("revision 1: got %+v", got[0])
+	}
+	if got[1].Content != trace || got[1].Format != domain.NoteFormatLog {
+		t.Errorf("revision 2 did not round-trip: format %q, %d bytes", got[1].Format, len(got[1].Content))
+	}
+
+	if err := s.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	got, err = s.ListNoteRevisions(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("ListNoteRevisions after delete: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ListNoteRevisions after delete: got %d, want 0", len(got))
+	}
+}
+
+func testResponderAssignmentHistoryAndCascade(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	base := now().Add(-time.Hour)
+	first := &domain.ResponderAssignment{
+		ID: uuid.New(), OutageID: outage.ID, Role: domain.RoleIncidentCommander,
+		Responder: "alice@example.com", Source: "pagerduty", ScheduleID: "PSCHED1",
+		AssignedBy: "bob@example.com", AssignedAt: base,
+	}
+	if err := s.CreateResponderAssignment(ctx, first); err != nil {
+		t.Fatalf("CreateResponderAssignment: %v", err)
+	}
+	clash := &domain.ResponderAssignment{
+		ID: uuid.New(), OutageID: outage.ID, Role: domain.RoleIncidentCommander,
+		Responder: "carol@example.com", AssignedAt: base.Add(time.Minute),
+	}
+	if err := s.CreateResponderAssignment(ctx, clash); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateResponderAssignment for a held role: got %v, want domain.ErrConflict", err)
+	}
+
+	ended := base.Add(10 * time.Minute)
+	if err := s.EndResponderAssignment(ctx, first.ID, "bob@example.com", ended); err != nil {
+		t.Fatalf("EndResponderAssignment: %v", err)
+	}
+	if err := s.EndResponderAssignment(ctx, first.ID, "bob@example.com", ended); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("EndResponderAssignment twice: got %v, want domain.ErrNotFound", err)
+	}
+	clash.AssignedAt = ended
+	if err := s.CreateResponderAssignment(ctx, clash); err != nil {
+		t.Fatalf("CreateResponderAssignment after the role was freed: %v", err)
+	}
+
+	got, err := s.ListResponderAssignments(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListResponderAssignments: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != first.ID || got[1].ID != clash.ID {
+		t.Fatalf("ListResponderAssignments: got %d assignments, want alice then carol", len(got))
+	}
+	if got[0].Active() || !got[0].UnassignedAt.Equal(ended) || got[0].UnassignedBy != "bob@example.com" {
+		t.Errorf("ended assignment: got %+v", got[0])
+	}
+	if got[0].Source != "pagerduty" || got[0].ScheduleID != "PSCHED1" || !got[0].AssignedAt.Equal(base) {
+		t.Errorf("assignment did not round-trip: got %+v", got[0])
+	}
+	if !got[1].Active() {
+		t.Errorf("current assignment has ended: got %+v", got[1])
+	}
+
+	between, err := s.ListResponderAssignmentsBetween(ctx, base.Add(time.Minute), base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListResponderAssignmentsBetween: %v", err)
+	}
+	if len(between) != 1 || between[0].ID != clash.ID {
+		t.Errorf("ListResponderAssignmentsBetween: got %d, want only carol's assignment", len(between))
+	}
+
+	if err := s.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	got, err = s.ListResponderAssignments(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListResponderAssignments after delete: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ListResponderAssignments after delete: got %d, want 0", len(got))
+	}
+}
+
+func testNoteTrashRestorePurge(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, Content: "n", Format: "plaintext",
+		Author: "bob", CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	trashedAt := now()
+	if err := s.TrashNote(ctx, note.ID, trashedAt); err != nil {
+		t.Fatalf("TrashNote: %v", err)
+	}
+	if err := s.TrashNote(ctx, note.ID, trashedAt); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("TrashNote twice: got %v, want domain.ErrNotFound", err)
+	}
+
+	got, err := s.GetNote(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("GetNote(trashed): %v", err)
+	}
+	if got.DeletedAt == nil || !got.DeletedAt.Equal(trashedAt) {
+		t.Errorf("DeletedAt: got %v, want %v", got.DeletedAt, trashedAt)
+	}
+	if list, err := s.ListNotesByOutage(ctx, outage.ID, false); err != nil || len(list) != 0 {
+		t.Errorf("ListNotesByOutage = %d notes, %v; want none", len(list), err)
+	}
+	if list, err := s.ListNotesByOutage(ctx, outage.ID, true); err != nil || len(list) != 1 {
+		t.Errorf("ListNotesByOutage(includeDeleted) = %d notes, %v; want 1", len(list), err)
+	}
+	withNotes, err := s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if len(withNotes.Notes) != 0 {
+		t.Errorf("GetOutage notes: got %d, want trashed note left out", len(withNotes.Notes))
+	}
+
+	if err := s.RestoreNote(ctx, note.ID); err != nil {
+		t.Fatalf("RestoreNote: %v", err)
+	}
+	if err := s.RestoreNote(ctx, note.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("RestoreNote twice: got %v, want domain.ErrNotFound", err)
+	}
+	if list, err := s.ListNotesByOutage(ctx, outage.ID, false); err != nil || len(list) != 1 {
+		t.Errorf("ListNotesByOutage after restore = %d notes, %v; want 1", len(list), err)
+	}
+
+	if err := s.TrashNote(ctx, note.ID, trashedAt); err != nil {
+		t.Fatalf("TrashNote: %v", err)
+	}
+	if n, err := s.PurgeNotes(ctx, trashedAt.Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("PurgeNotes = %d, %v; want 1", n, err)
+	}
+	if _, err := s.GetNote(ctx, note.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetNote after purge: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testTagCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	tag := &domain.Tag{
+		ID:        uuid.New(),
+		OutageID:  outage.ID,
+		Key:       "env",
+		Value:     "production",
+		CreatedAt: now(),
+	}
+
+	// Create
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	// Get
+	got, err := s.GetTag(ctx, tag.ID)
+	if err != nil {
+		t.Fatalf("GetTag: %v", err)
+	}
+	if got.Key != "env" || got.Value != "production" {
+		t.Errorf("Key/Value: got %q=%q, want env=production", got.Key, got.Value)
+	}
+
+	// List
+	list, err := s.ListTagsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListTagsByOutage: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("list count: got %d, want 1", len(list))
+	}
+
+	// Update; the outage and creation time are kept
+	update := &domain.Tag{ID: tag.ID, OutageID: uuid.New(), Key: "region", Value: "eu-west-1", CreatedAt: now().Add(time.Hour),
+		CustomFields: map[string]any{"primary": true}}
+	if err := s.UpdateTag(ctx, update); err != nil {
+		t.Fatalf("UpdateTag: %v", err)
+	}
+	got, err = s.GetTag(ctx, tag.ID)
+	if err != nil {
+		t.Fatalf("GetTag after update: %v", err)
+	}
+	if got.Key != "region" || got.Value != "eu-west-1" || got.CustomFields["primary"] != true ||
+		got.OutageID != outage.ID || !got.CreatedAt.Equal(tag.CreatedAt) {
+		t.Errorf("GetTag after update = %+v, want region=eu-west-1 on the same outage", got)
+	}
+
+	// Delete
+	if err := s.DeleteTag(ctx, tag.ID); err != nil {
+		t.Fatalf("DeleteTag: %v", err)
+	}
+	_, err = s.GetTag(ctx, tag.ID)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTag after delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testTagNotFound(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	_, err := s.GetTag(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTag missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	err = s.DeleteTag(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteTag missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	err = s.UpdateTag(ctx, &domain.Tag{ID: uuid.New(), Key: "env"})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateTag missing: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testTagUniqueKey(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+	newTag := func(key string, unique bool) *domain.Tag {
+		return &domain.Tag{ID: uuid.New(), OutageID: outage.ID, Key: key, Value: "v", CreatedAt: now(), UniqueKey: unique}
+	}
+
+	// Keys without a unique schema may repeat, even alongside a unique tag
+	for _, tag := range []*domain.Tag{newTag("service", false), newTag("service", false), newTag("team", true), newTag("team", false)} {
+		if err := s.CreateTag(ctx, tag); err != nil {
+			t.Fatalf("CreateTag %s: %v", tag.Key, err)
+		}
+	}
+	got, err := s.ListTagsByKey(ctx, "team")
+	if err != nil {
+		t.Fatalf("ListTagsByKey: %v", err)
+	}
+	if len(got) != 2 || got[0].UniqueKey == got[1].UniqueKey {
+		t.Errorf("ListTagsByKey(team) = %+v, want one unique and one other tag", got)
+	}
+
+	// A second unique tag with the key is a conflict
+	if err := s.CreateTag(ctx, newTag("team", true)); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateTag second unique team: got %v, want domain.ErrConflict", err)
+	}
+
+	// So is moving a tag onto the key
+	moved := newTag("owner", true)
+	if err := s.CreateTag(ctx, moved); err != nil {
+		t.Fatalf("CreateTag owner: %v", err)
+	}
+	moved.Key = "team"
+	if err := s.UpdateTag(ctx, moved); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("UpdateTag onto unique team: got %v, want domain.ErrConflict", err)
+	}
+	moved.UniqueKey = false
+	if err := s.UpdateTag(ctx, moved); err != nil {
+		t.Errorf("UpdateTag onto team without the unique flag: %v", err)
+	}
+}
+
+func testListTagsByKey(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	var outages []*domain.Outage
+	for i := 0; i < 2; i++ {
+		o := &domain.Outage{ID: uuid.New(), Title: "o", Status: "open", Severity: "low", CreatedAt: now(), UpdatedAt: now()}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage: %v", err)
+		}
+		outages = append(outages, o)
+	}
+	tags := []*domain.Tag{
+		{ID: uuid.New(), OutageID: outages[0].ID, Key: "jira", Value: "OPS-1", CreatedAt: now().Add(-time.Minute)},
+		{ID: uuid.New(), OutageID: outages[1].ID, Key: "jira", Value: "OPS-2", CreatedAt: now()},
+		{ID: uuid.New(), OutageID: outages[1].ID, Key: "env", Value: "prod", CreatedAt: now()},
+	}
+	for _, tag := range tags {
+		if err := s.CreateTag(ctx, tag); err != nil {
+			t.Fatalf("CreateTag: %v", err)
+		}
+	}
+
+	got, err := s.ListTagsByKey(ctx, "jira")
+	if err != nil {
+		t.Fatalf("ListTagsByKey: %v", err)
+	}
+	if len(got) != 2 || got[0].Value != "OPS-1" || got[1].Value != "OPS-2" {
+		t.Errorf("ListTagsByKey(jira) = %+v, want OPS-1 then OPS-2", got)
+	}
+}
+
+func testFindOutagesByTagQuery(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	byTags := make(map[string]*domain.Outage)
+	for i, tags := range [][]string{{"service:api", "env:prod"}, {"service:web", "env:prod"}, {"service:api", "env:staging"}, {}, {"service:api"}} {
+		o := &domain.Outage{ID: uuid.New(), Title: "o", Status: "open", Severity: "low", CreatedAt: now().Add(time.Duration(i) * time.Minute), UpdatedAt: now()}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage: %v", err)
+		}
+		for _, kv := range tags {
+			key, value, _ := strings.Cut(kv, ":")
+			if err := s.CreateTag(ctx, &domain.Tag{ID: uuid.New(), OutageID: o.ID, Key: key, Value: value, CreatedAt: now()}); err != nil {
+				t.Fatalf("CreateTag: %v", err)
+			}
+		}
+		byTags[strings.Join(tags, " ")] = o
+	}
+	// The last outage is in the trash, so it never matches
+	if err := s.TrashOutage(ctx, byTags["service:api"].ID, now()); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		terms []domain.TagTerm
+		want  []string // Newest first
+	}{
+		{"any of values", []domain.TagTerm{{Key: "service", Values: []string{"api", "web"}}},
+			[]string{"service:api env:staging", "service:web env:prod", "service:api env:prod"}},
+		{"all of terms", []domain.TagTerm{{Key: "service", Values: []string{"api"}}, {Key: "env", Values: []string{"prod"}}},
+			[]string{"service:api env:prod"}},
+		{"any value", []domain.TagTerm{{Key: "env"}, {Key: "env", Values: []string{"staging"}, Negate: true}},
+			[]string{"service:web env:prod", "service:api env:prod"}},
+		{"negated key", []domain.TagTerm{{Key: "service", Negate: true}}, []string{""}},
+		{"no terms", nil,
+			[]string{"", "service:api env:staging", "service:web env:prod", "service:api env:prod"}},
+	}
+	for _, tt := range tests {
+		outages, err := s.FindOutagesByTagQuery(ctx, domain.TagQuery{Terms: tt.terms})
+		if err != nil {
+			t.Fatalf("%s: FindOutagesByTagQuery: %v", tt.name, err)
+		}
+		var want []uuid.UUID
+		for _, tags := range tt.want {
+			want = append(want, byTags[tags].ID)
+		}
+		var got []uuid.UUID
+		for _, o := range outages {
+			got = append(got, o.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: FindOutagesByTagQuery = %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+func testTagUsage(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	var outages []*domain.Outage
+	for i := 0; i < 3; i++ {
+		o := &domain.Outage{ID: uuid.New(), Title: "o", Status: "open", Severity: "low", CreatedAt: now(), UpdatedAt: now()}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage: %v", err)
+		}
+		outages = append(outages, o)
+	}
+	for _, tag := range []*domain.Tag{
+		{OutageID: outages[0].ID, Key: "service", Value: "api"},
+		{OutageID: outages[1].ID, Key: "service", Value: "api"},
+		{OutageID: outages[1].ID, Key: "service", Value: "db"},
+		{OutageID: outages[1].ID, Key: "region", Value: "eu"},
+		{OutageID: outages[0].ID, Key: "jira", Value: "OPS-1"},
+		{OutageID: outages[2].ID, Key: "service", Value: "db"},
+		{OutageID: outages[2].ID, Key: "service", Value: "db"},
+	} {
+		tag.ID, tag.CreatedAt = uuid.New(), now()
+		if err := s.CreateTag(ctx, tag); err != nil {
+			t.Fatalf("CreateTag: %v", err)
+		}
+	}
+	// Tags on trashed outages are not counted
+	if err := s.TrashOutage(ctx, outages[2].ID, now()); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+
+	keys, err := s.ListTagKeys(ctx)
+	if err != nil {
+		t.Fatalf("ListTagKeys: %v", err)
+	}
+	wantKeys := []domain.TagUsage{{Key: "service", Count: 3}, {Key: "jira", Count: 1}, {Key: "region", Count: 1}}
+	if !slices.Equal(keys, wantKeys) {
+		t.Errorf("ListTagKeys = %+v, want %+v", keys, wantKeys)
+	}
+
+	values, err := s.ListTagValues(ctx, "service")
+	if err != nil {
+		t.Fatalf("ListTagValues: %v", err)
+	}
+	wantValues := []domain.TagUsage{{Key: "service", Value: "api", Count: 2}, {Key: "service", Value: "db", Count: 1}}
+	if !slices.Equal(values, wantValues) {
+		t.Errorf("ListTagValues(service) = %+v, want %+v", values, wantValues)
+	}
+
+	if values, err := s.ListTagValues(ctx, "team"); err != nil || len(values) != 0 {
+		t.Errorf("ListTagValues(team) = %+v, %v; want none", values, err)
+	}
+}
+
+func testFindOutagesByTag(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	// Create two outages; tag only one.
+	o1 := &domain.Outage{
+		ID: uuid.New(), Title: "o1", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	o2 := &domain.Outage{
+		ID: uuid.New(), Title: "o2", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	for _, o := range []*domain.Outage{o1, o2} {
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage: %v", err)
+		}
+	}
+
+	tag := &domain.Tag{
+		ID:        uuid.New(),
+		OutageID:  o1.ID,
+		Key:       "region",
+		Value:     "us-east-1",
+		CreatedAt: now(),
+	}
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	results, err := s.FindOutagesByTag(ctx, "region", "us-east-1")
+	if err != nil {
+		t.Fatalf("FindOutagesByTag: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("FindOutagesByTag count: got %d, want 1", len(results))
+	}
+	if results[0].ID != o1.ID {
+		t.Errorf("FindOutagesByTag: got outage %s, want %s", results[0].ID, o1.ID)
+	}
+
+	// Non-matching tag returns empty, not error.
+	results, err = s.FindOutagesByTag(ctx, "region", "eu-west-1")
+	if err != nil {
+		t.Fatalf("FindOutagesByTag (no match): %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("FindOutagesByTag (no match) count: got %d, want 0", len(results))
+	}
+}
+
+// ── GetOutage eager-loading ───────────────────────────────────────────────────
+
+func testGetOutageEagerLoadsRelations(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	alert := &domain.Alert{
+		ID: uuid.New(), OutageID: outage.ID,
+		ExternalID: "x", Source: "pagerduty",
+		Title: "a", TriggeredAt: now(), CreatedAt: now(),
+	}
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID,
+		Content: "n", Format: "plaintext", Author: "bob",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	tag := &domain.Tag{
+		ID: uuid.New(), OutageID: outage.ID,
+		Key: "k", Value: "v", CreatedAt: now(),
+	}
+
+	if err := s.CreateAlert(ctx, alert); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	got, err := s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if len(got.Alerts) != 1 {
+		t.Errorf("Alerts: got %d, want 1", len(got.Alerts))
+	}
+	if len(got.Notes) != 1 {
+		t.Errorf("Notes: got %d, want 1", len(got.Notes))
+	}
+	if len(got.Tags) != 1 {
+		t.Errorf("Tags: got %d, want 1", len(got.Tags))
+	}
+}
+
+func testLoadOutageAssociations(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	withAll := createOutage(t, s)
+	bare := createOutage(t, s)
+	for i, triggered := range []time.Time{now().Add(-time.Hour), now()} {
+		alert := &domain.Alert{
+			ID: uuid.New(), OutageID: withAll.ID,
+			ExternalID: fmt.Sprintf("a%d", i), Source: "pagerduty",
+			Title: "a", TriggeredAt: triggered, CreatedAt: now(),
+		}
+		if err := s.CreateAlert(ctx, alert); err != nil {
+			t.Fatalf("CreateAlert: %v", err)
+		}
+	}
+	live := &domain.Note{
+		ID: uuid.New(), OutageID: withAll.ID,
+		Content: "live", Format: "plaintext", Author: "bob",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	trashed := &domain.Note{
+		ID: uuid.New(), OutageID: withAll.ID,
+		Content: "trashed", Format: "plaintext", Author: "bob",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	for _, n := range []*domain.Note{live, trashed} {
+		if err := s.CreateNote(ctx, n); err != nil {
+			t.Fatalf("CreateNote: %v", err)
+		}
+	}
+	if err := s.TrashNote(ctx, trashed.ID, now()); err != nil {
+		t.Fatalf("TrashNote: %v", err)
+	}
+	tag := &domain.Tag{ID: uuid.New(), OutageID: withAll.ID, Key: "k", Value: "v", CreatedAt: now()}
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	outages, err := s.ListOutages(ctx, 10, 0, false)
+	if err != nil {
+		t.Fatalf("ListOutages: %v", err)
+	}
+	if len(outages) != 2 {
+		t.Fatalf("ListOutages count: got %d, want 2", len(outages))
+	}
+	if err := s.LoadOutageAssociations(ctx, outages, domain.AllOutageAssociations); err != nil {
+		t.Fatalf("LoadOutageAssociations: %v", err)
+	}
+	for _, o := range outages {
+		switch o.ID {
+		case withAll.ID:
+			if len(o.Alerts) != 2 || !o.Alerts[0].TriggeredAt.After(o.Alerts[1].TriggeredAt) {
+				t.Errorf("Alerts: got %+v, want both alerts, latest first", o.Alerts)
+			}
+			if len(o.Notes) != 1 || o.Notes[0].ID != live.ID {
+				t.Errorf("Notes: got %+v, want only the note outside the trash", o.Notes)
+			}
+			if len(o.Tags) != 1 || o.Tags[0].ID != tag.ID {
+				t.Errorf("Tags: got %+v, want the tag", o.Tags)
+			}
+		case bare.ID:
+			if o.Alerts == nil || o.Notes == nil || o.Tags == nil ||
+				len(o.Alerts)+len(o.Notes)+len(o.Tags) != 0 {
+				t.Errorf("outage without associations: got %+v, want empty, non-nil slices", o)
+			}
+		}
+	}
+
+	if err := s.LoadOutageAssociations(ctx, nil, domain.AllOutageAssociations); err != nil {
+		t.Errorf("LoadOutageAssociations(nil): %v", err)
+	}
+}
+
+func testGetOutageWith(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID,
+		Content: "n", Format: "plaintext", Author: "bob",
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	tag := &domain.Tag{ID: uuid.New(), OutageID: outage.ID, Key: "k", Value: "v", CreatedAt: now()}
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	got, err := s.GetOutageWith(ctx, outage.ID, domain.OutageAssociations{Notes: true})
+	if err != nil {
+		t.Fatalf("GetOutageWith: %v", err)
+	}
+	if got.Title != outage.Title || len(got.Notes) != 1 || got.Notes[0].ID != note.ID {
+		t.Errorf("got %+v, want the outage with its note", got)
+	}
+	if got.Alerts != nil || got.Tags != nil {
+		t.Errorf("Alerts, Tags: got %+v, %+v; want nil when not selected", got.Alerts, got.Tags)
+	}
+
+	got, err = s.GetOutageWith(ctx, outage.ID, domain.OutageAssociations{})
+	if err != nil {
+		t.Fatalf("GetOutageWith(none): %v", err)
+	}
+	if got.Alerts != nil || got.Notes != nil || got.Tags != nil {
+		t.Errorf("got %+v, want no associations", got)
+	}
+
+	if _, err := s.GetOutageWith(ctx, uuid.New(), domain.AllOutageAssociations); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutageWith(unknown): got %v, want ErrNotFound", err)
+	}
+}
+
+// ── Nil maps ──────────────────────────────────────────────────────────────────
+
+func testOutageNilMetadata(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID:        uuid.New(),
+		Title:     "nil-meta",
+		Status:    "open",
+		Severity:  "low",
+		CreatedAt: now(),
+		UpdatedAt: now(),
+		Metadata:  nil, // explicitly nil
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage with nil metadata: %v", err)
+	}
+	got, err := s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	// Backends may return nil or an empty map; see storage.Storage.
+	if len(got.Metadata) != 0 {
+		t.Errorf("Metadata: expected empty map, got %v", got.Metadata)
+	}
+}
+
+func testOutageImpactRoundTrip(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	started, ended := now().Add(-time.Hour), now().Add(-time.Minute)
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "checkout down", Status: "open", Severity: "high",
+		CreatedAt: now(), UpdatedAt: now(),
+		AffectedServices: []string{"checkout", "payments"}, CustomerImpact: true,
+		ImpactStartedAt: &started, ImpactEndedAt: &ended,
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+	if err := s.CreateTag(ctx, &domain.Tag{ID: uuid.New(), OutageID: outage.ID, Key: "region", Value: "eu", CreatedAt: now()}); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	checkImpact := func(what string, got *domain.Outage) {
+		t.Helper()
+		if !reflect.DeepEqual(got.AffectedServices, outage.AffectedServices) || got.CustomerImpact != outage.CustomerImpact ||
+			!equalTimes(got.ImpactStartedAt, outage.ImpactStartedAt) || !equalTimes(got.ImpactEndedAt, outage.ImpactEndedAt) {
+			t.Errorf("%s impact = %v, %v, %v, %v; want %v, %v, %v, %v", what,
+				got.AffectedServices, got.CustomerImpact, got.ImpactStartedAt, got.ImpactEndedAt,
+				outage.AffectedServices, outage.CustomerImpact, outage.ImpactStartedAt, outage.ImpactEndedAt)
+		}
+	}
+
+	got, err := s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	checkImpact("GetOutage", got)
+	list, err := s.ListOutages(ctx, 10, 0, false)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListOutages = %d outages, %v; want 1", len(list), err)
+	}
+	checkImpact("ListOutages", list[0])
+	tagged, err := s.FindOutagesByTag(ctx, "region", "eu")
+	if err != nil || len(tagged) != 1 {
+		t.Fatalf("FindOutagesByTag = %d outages, %v; want 1", len(tagged), err)
+	}
+	checkImpact("FindOutagesByTag", tagged[0])
+
+	outage.AffectedServices = nil
+	outage.CustomerImpact = false
+	outage.ImpactStartedAt = nil
+	outage.ImpactEndedAt = nil
+	if err := s.UpdateOutage(ctx, outage); err != nil {
+		t.Fatalf("UpdateOutage: %v", err)
+	}
+	got, err = s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage after update: %v", err)
+	}
+	checkImpact("updated", got)
+}
+
+func testOutageChildren(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	newOutage := func(title string, age time.Duration, parentID *uuid.UUID) *domain.Outage {
+		t.Helper()
+		o := &domain.Outage{
+			ID: uuid.New(), Title: title, Status: "open", Severity: "high",
+			CreatedAt: now().Add(-age), UpdatedAt: now(), ParentID: parentID,
+		}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage(%s): %v", title, err)
+		}
+		return o
+	}
+	parent := newOutage("eu-west down", 3*time.Hour, nil)
+	newer := newOutage("checkout down", time.Hour, &parent.ID)
+	older := newOutage("search down", 2*time.Hour, &parent.ID)
+	trashed := newOutage("login down", time.Hour, &parent.ID)
+	if err := s.TrashOutage(ctx, trashed.ID, now()); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+	newOutage("unrelated", time.Hour, nil)
+
+	got, err := s.GetOutage(ctx, newer.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if got.ParentID == nil || *got.ParentID != parent.ID {
+		t.Errorf("GetOutage ParentID = %v, want %s", got.ParentID, parent.ID)
+	}
+	if got, err = s.GetOutage(ctx, parent.ID); err != nil {
+		t.Fatalf("GetOutage(parent): %v", err)
+	}
+	if got.ParentID != nil {
+		t.Errorf("GetOutage(parent) ParentID = %s, want nil", got.ParentID)
+	}
+
+	children, err := s.ListChildOutages(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("ListChildOutages: %v", err)
+	}
+	if len(children) != 2 || children[0].ID != older.ID || children[1].ID != newer.ID {
+		t.Fatalf("ListChildOutages = %v, want %s then %s", outageIDs(children), older.ID, newer.ID)
+	}
+	if children, err := s.ListChildOutages(ctx, newer.ID); err != nil || len(children) != 0 {
+		t.Errorf("ListChildOutages(child) = %v, %v; want none", outageIDs(children), err)
+	}
+
+	// UpdateOutage leaves the parent alone; SetOutageParent unlinks it
+	older.ParentID = nil
+	if err := s.UpdateOutage(ctx, older); err != nil {
+		t.Fatalf("UpdateOutage: %v", err)
+	}
+	if got, err := s.GetOutage(ctx, older.ID); err != nil {
+		t.Fatalf("GetOutage after UpdateOutage: %v", err)
+	} else if got.ParentID == nil || *got.ParentID != parent.ID {
+		t.Errorf("ParentID after UpdateOutage = %v, want %s kept", got.ParentID, parent.ID)
+	}
+	if err := s.SetOutageParent(ctx, older.ID, nil, now()); err != nil {
+		t.Fatalf("SetOutageParent(nil): %v", err)
+	}
+	if children, err := s.ListChildOutages(ctx, parent.ID); err != nil || len(children) != 1 || children[0].ID != newer.ID {
+		t.Errorf("ListChildOutages after unlink = %v, %v; want %s", outageIDs(children), err, newer.ID)
+	}
+
+	// Deleting the parent keeps its children as top-level outages
+	if err := s.DeleteOutage(ctx, parent.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	got, err = s.GetOutage(ctx, newer.ID)
+	if err != nil {
+		t.Fatalf("GetOutage after deleting the parent: %v", err)
+	}
+	if got.ParentID != nil {
+		t.Errorf("ParentID after deleting the parent = %s, want nil", got.ParentID)
+	}
+}
+
+func testSetOutageParent(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	newOutage := func(title string) *domain.Outage {
+		t.Helper()
+		o := &domain.Outage{ID: uuid.New(), Title: title, Status: "open", Severity: "high", CreatedAt: now(), UpdatedAt: now()}
+		if err := s.CreateOutage(ctx, o); err != nil {
+			t.Fatalf("CreateOutage(%s): %v", title, err)
+		}
+		return o
+	}
+	region, checkout, search := newOutage("eu-west down"), newOutage("checkout down"), newOutage("search down")
+
+	linkedAt := now().Add(time.Minute)
+	if err := s.SetOutageParent(ctx, checkout.ID, &region.ID, linkedAt); err != nil {
+		t.Fatalf("SetOutageParent: %v", err)
+	}
+	got, err := s.GetOutage(ctx, checkout.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if got.ParentID == nil || *got.ParentID != region.ID || !got.UpdatedAt.Equal(linkedAt) {
+		t.Errorf("outage after SetOutageParent = parent %v updated %s, want %s at %s", got.ParentID, got.UpdatedAt, region.ID, linkedAt)
+	}
+
+	missing := uuid.New()
+	trashed := newOutage("login down")
+	if err := s.TrashOutage(ctx, trashed.ID, now()); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+	for name, link := range map[string][2]uuid.UUID{
+		"own parent":        {search.ID, search.ID},
+		"missing parent":    {search.ID, missing},
+		"trashed parent":    {search.ID, trashed.ID},
+		"child as parent":   {search.ID, checkout.ID},
+		"parent as a child": {region.ID, search.ID},
+	} {
+		if err := s.SetOutageParent(ctx, link[0], &link[1], now()); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("SetOutageParent %s: got %v, want domain.ErrInvalidInput", name, err)
+		}
+	}
+	if err := s.SetOutageParent(ctx, missing, &region.ID, now()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SetOutageParent missing outage: got %v, want domain.ErrNotFound", err)
+	}
+
+	// Linking two outages to each other at once links at most one
+	a, b := newOutage("a"), newOutage("b")
+	errs := make(chan error, 2)
+	go func() { errs <- s.SetOutageParent(ctx, a.ID, &b.ID, now()) }()
+	go func() { errs <- s.SetOutageParent(ctx, b.ID, &a.ID, now()) }()
+	linked := 0
+	for range 2 {
+		if err := <-errs; err == nil {
+			linked++
+		} else if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("concurrent SetOutageParent: %v", err)
+		}
+	}
+	if linked != 1 {
+		t.Errorf("concurrent links to each other: %d succeeded, want 1", linked)
+	}
+}
+
+func testOutageRelations(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+	a, b, c := createOutage(t, s), createOutage(t, s), createOutage(t, s)
+	for _, o := range []*domain.Outage{a, b, c} {
+		o.Title = "outage " + o.ID.String()
+		if err := s.UpdateOutage(ctx, o); err != nil {
+			t.Fatalf("UpdateOutage: %v", err)
+		}
+	}
+
+	duplicate := &domain.OutageRelation{
+		ID: uuid.New(), OutageID: a.ID, RelatedOutageID: b.ID, Type: domain.RelationDuplicateOf,
+		Description: "same alert", CreatedBy: "alice@example.com", CreatedAt: now().Add(-time.Hour),
+	}
+	cause := &domain.OutageRelation{
+		ID: uuid.New(), OutageID: c.ID, RelatedOutageID: a.ID, Type: domain.RelationCausedBy, CreatedAt: now(),
+	}
+	for _, r := range []*domain.OutageRelation{duplicate, cause} {
+		if err := s.CreateOutageRelation(ctx, r); err != nil {
+			t.Fatalf("CreateOutageRelation(%s): %v", r.Type, err)
+		}
+	}
+	again := *duplicate
+	again.ID = uuid.New()
+	if err := s.CreateOutageRelation(ctx, &again); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateOutageRelation duplicate: got %v, want domain.ErrConflict", err)
+	}
+
+	got, err := s.GetOutageRelation(ctx, duplicate.ID)
+	if err != nil {
+		t.Fatalf("GetOutageRelation: %v", err)
+	}
+	if got.OutageID != a.ID || got.RelatedOutageID != b.ID || got.Type != duplicate.Type ||
+		got.Description != duplicate.Description || got.CreatedBy != duplicate.CreatedBy || !got.CreatedAt.Equal(duplicate.CreatedAt) {
+		t.Errorf("GetOutageRelation = %+v, want %+v", got, duplicate)
+	}
+	if _, err := s.GetOutageRelation(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutageRelation missing: got %v, want domain.ErrNotFound", err)
+	}
+
+	// Relations are listed from either end, oldest first
+	relations, err := s.ListOutageRelations(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("ListOutageRelations: %v", err)
+	}
+	if len(relations) != 2 || relations[0].ID != duplicate.ID || relations[1].ID != cause.ID {
+		t.Fatalf("ListOutageRelations = %d relations, want %s then %s", len(relations), duplicate.ID, cause.ID)
+	}
+	if relations[0].RelatedOutageTitle != b.Title || relations[1].RelatedOutageTitle != c.Title {
+		t.Errorf("ListOutageRelations titles = %q, %q; want %q, %q",
+			relations[0].RelatedOutageTitle, relations[1].RelatedOutageTitle, b.Title, c.Title)
+	}
+	if relations, err := s.ListOutageRelations(ctx, b.ID); err != nil || len(relations) != 1 || relations[0].ID != duplicate.ID {
+		t.Errorf("ListOutageRelations(related end) = %d relations, %v; want %s", len(relations), err, duplicate.ID)
+	} else if relations[0].RelatedOutageTitle != a.Title {
+		t.Errorf("ListOutageRelations(related end) title = %q, want %q", relations[0].RelatedOutageTitle, a.Title)
+	}
+
+	// Relations to outages in the trash are left out until they are restored
+	if err := s.TrashOutage(ctx, c.ID, now()); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+	if relations, err := s.ListOutageRelations(ctx, a.ID); err != nil || len(relations) != 1 || relations[0].ID != duplicate.ID {
+		t.Errorf("ListOutageRelations with the cause trashed = %d relations, %v; want %s", len(relations), err, duplicate.ID)
+	}
+	if err := s.RestoreOutage(ctx, c.ID); err != nil {
+		t.Fatalf("RestoreOutage: %v", err)
+	}
+
+	if err := s.DeleteOutageRelation(ctx, duplicate.ID); err != nil {
+		t.Fatalf("DeleteOutageRelation: %v", err)
+	}
+	if err := s.DeleteOutageRelation(ctx, duplicate.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteOutageRelation again: got %v, want domain.ErrNotFound", err)
+	}
+
+	// Deleting an outage removes its relations at either end
+	if err := s.DeleteOutage(ctx, a.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	if relations, err := s.ListOutageRelations(ctx, c.ID); err != nil || len(relations) != 0 {
+		t.Errorf("ListOutageRelations after deleting the related outage = %d relations, %v; want none", len(relations), err)
+	}
+}
+
+// equalTimes reports whether two optional times are both unset or equal
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// ── Preferences ───────────────────────────────────────────────────────────────
+
+func testUserPreferencesUpsert(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	_, err := s.GetUserPreferences(ctx, "sub-1")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetUserPreferences before save: got %v, want domain.ErrNotFound", err)
+	}
+
+	prefs := &domain.UserPreferences{
+		Subject:  "sub-1",
+		Timezone: "Europe/Dublin",
+		Notifications: domain.NotificationPreferences{
+			SlackDM: true, MinSeverity: "high",
+		},
+		UpdatedAt: now(),
+	}
+	if err := s.UpsertUserPreferences(ctx, prefs); err != nil {
+		t.Fatalf("UpsertUserPreferences: %v", err)
+	}
+
+	prefs.DigestOptIn = true
+	prefs.DefaultTeamFilter = "platform"
+	if err := s.UpsertUserPreferences(ctx, prefs); err != nil {
+		t.Fatalf("UpsertUserPreferences (update): %v", err)
+	}
+
+	got, err := s.GetUserPreferences(ctx, "sub-1")
+	if err != nil {
+		t.Fatalf("GetUserPreferences: %v", err)
+	}
+	if got.Timezone != "Europe/Dublin" || !got.DigestOptIn || got.DefaultTeamFilter != "platform" {
+		t.Errorf("got %+v", got)
+	}
+	if !got.Notifications.SlackDM || got.Notifications.MinSeverity != "high" {
+		t.Errorf("Notifications = %+v", got.Notifications)
+	}
+}
+
+func testOutageReviewUpsertAndList(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{ID: uuid.New(), Title: "t", Status: "resolved", Severity: "high", CreatedAt: now(), UpdatedAt: now()}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+
+	if _, err := s.GetOutageReview(ctx, outage.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetOutageReview before save: got %v, want domain.ErrNotFound", err)
+	}
+
+	review := &domain.OutageReview{OutageID: outage.ID, Status: domain.ReviewNeedsReview, CreatedAt: now(), UpdatedAt: now()}
+	if err := s.UpsertOutageReview(ctx, review); err != nil {
+		t.Fatalf("UpsertOutageReview: %v", err)
+	}
+
+	scheduled := now().Add(48 * time.Hour)
+	review.Status = domain.ReviewScheduled
+	review.ScheduledFor = &scheduled
+	if err := s.UpsertOutageReview(ctx, review); err != nil {
+		t.Fatalf("UpsertOutageReview (update): %v", err)
+	}
+
+	got, err := s.GetOutageReview(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutageReview: %v", err)
+	}
+	if got.Status != domain.ReviewScheduled || got.ScheduledFor == nil || !got.ScheduledFor.Equal(scheduled) {
+		t.Errorf("got %+v", got)
+	}
+
+	pending, err := s.ListOutageReviews(ctx, domain.ReviewNeedsReview)
+	if err != nil {
+		t.Fatalf("ListOutageReviews: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no needs-review entries, got %d", len(pending))
+	}
+	all, err := s.ListOutageReviews(ctx, "")
+	if err != nil {
+		t.Fatalf("ListOutageReviews: %v", err)
+	}
+	if len(all) != 1 || all[0].OutageID != outage.ID {
+		t.Errorf("ListOutageReviews(\"\") = %+v", all)
+	}
+}
+
+func testSyncCursorUpsert(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetSyncCursor(ctx, "pagerduty"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetSyncCursor before save: got %v, want domain.ErrNotFound", err)
+	}
+
+	latest := now()
+	first := latest.Add(-time.Hour)
+	if err := s.UpsertSyncCursor(ctx, &domain.SyncCursor{Source: "pagerduty", SyncedUntil: first, UpdatedAt: first}); err != nil {
+		t.Fatalf("UpsertSyncCursor: %v", err)
+	}
+	if err := s.UpsertSyncCursor(ctx, &domain.SyncCursor{Source: "pagerduty", SyncedUntil: latest, UpdatedAt: latest}); err != nil {
+		t.Fatalf("UpsertSyncCursor (update): %v", err)
+	}
+
+	got, err := s.GetSyncCursor(ctx, "pagerduty")
+	if err != nil {
+		t.Fatalf("GetSyncCursor: %v", err)
+	}
+	if !got.SyncedUntil.Equal(latest) {
+		t.Errorf("SyncedUntil = %v, want %v", got.SyncedUntil, latest)
+	}
+	if _, err := s.GetSyncCursor(ctx, "opsgenie"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetSyncCursor(opsgenie): got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testExportWatermarkUpsert(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetExportWatermark(ctx, domain.ExportTableOutages); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetExportWatermark before save: got %v, want domain.ErrNotFound", err)
+	}
+
+	latest := now()
+	first := latest.Add(-time.Hour)
+	if err := s.UpsertExportWatermark(ctx, &domain.ExportWatermark{Table: domain.ExportTableOutages, ExportedUntil: first, UpdatedAt: first}); err != nil {
+		t.Fatalf("UpsertExportWatermark: %v", err)
+	}
+	if err := s.UpsertExportWatermark(ctx, &domain.ExportWatermark{Table: domain.ExportTableOutages, ExportedUntil: latest, UpdatedAt: latest}); err != nil {
+		t.Fatalf("UpsertExportWatermark (update): %v", err)
+	}
+
+	got, err := s.GetExportWatermark(ctx, domain.ExportTableOutages)
+	if err != nil {
+		t.Fatalf("GetExportWatermark: %v", err)
+	}
+	if !got.ExportedUntil.Equal(latest) {
+		t.Errorf("ExportedUntil = %v, want %v", got.ExportedUntil, latest)
+	}
+	if _, err := s.GetExportWatermark(ctx, domain.ExportTableAlerts); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetExportWatermark(alerts): got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testProcessedEventConflictAndPurge(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	old := now().Add(-2 * time.Hour)
+	for _, e := range []*domain.ProcessedEvent{
+		{Source: "slack", EventID: "Ev1", ProcessedAt: old},
+		{Source: "slack", EventID: "Ev2", ProcessedAt: now()},
+		{Source: "other", EventID: "Ev1", ProcessedAt: old},
+	} {
+		if err := s.CreateProcessedEvent(ctx, e); err != nil {
+			t.Fatalf("CreateProcessedEvent(%s/%s): %v", e.Source, e.EventID, err)
+		}
+	}
+	err := s.CreateProcessedEvent(ctx, &domain.ProcessedEvent{Source: "slack", EventID: "Ev1", ProcessedAt: now()})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("CreateProcessedEvent duplicate: got %v, want domain.ErrConflict", err)
+	}
+
+	n, err := s.PurgeProcessedEvents(ctx, "slack", now().Add(-time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeProcessedEvents = %d, %v; want 1", n, err)
+	}
+	if err := s.CreateProcessedEvent(ctx, &domain.ProcessedEvent{Source: "slack", EventID: "Ev1", ProcessedAt: now()}); err != nil {
+		t.Errorf("CreateProcessedEvent after purge: %v", err)
+	}
+	// Other sources are left alone
+	err = s.CreateProcessedEvent(ctx, &domain.ProcessedEvent{Source: "other", EventID: "Ev1", ProcessedAt: now()})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateProcessedEvent(other) after purging slack: got %v, want domain.ErrConflict", err)
+	}
+}
+
+func testRetentionRunCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetRetentionRun(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetRetentionRun(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.UpdateRetentionRun(ctx, &domain.RetentionRun{ID: uuid.New()}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("UpdateRetentionRun(missing): got %v, want domain.ErrNotFound", err)
+	}
+
+	older := &domain.RetentionRun{
+		ID: uuid.New(), Trigger: domain.RetentionTriggerScheduled, Status: domain.RetentionRunCompleted,
+		ArchivedBefore: now().Add(-48 * time.Hour), StartedAt: now().Add(-time.Hour),
+	}
+	run := &domain.RetentionRun{
+		ID: uuid.New(), Trigger: domain.RetentionTriggerManual, TriggeredBy: "admin@example.com",
+		Status: domain.RetentionRunRunning, ArchivedBefore: now().Add(-24 * time.Hour), StartedAt: now(),
+	}
+	for _, r := range []*domain.RetentionRun{older, run} {
+		if err := s.CreateRetentionRun(ctx, r); err != nil {
+			t.Fatalf("CreateRetentionRun: %v", err)
+		}
+	}
+
+	finished := now()
+	run.Status = domain.RetentionRunCompleted
+	run.ArchivedOutages = 12
+	run.ArchiveKey = "retention/outages-20240708T090000Z.json"
+	run.PurgedArchives = 1
+	run.FinishedAt = &finished
+	if err := s.UpdateRetentionRun(ctx, run); err != nil {
+		t.Fatalf("UpdateRetentionRun: %v", err)
+	}
+	older.ArchivePurgedAt = &finished
+	if err := s.UpdateRetentionRun(ctx, older); err != nil {
+		t.Fatalf("UpdateRetentionRun(older): %v", err)
+	}
+
+	got, err := s.GetRetentionRun(ctx, run.ID)
+	if err != nil {
+		t.Fatalf("GetRetentionRun: %v", err)
+	}
+	if got.Status != domain.RetentionRunCompleted || got.ArchivedOutages != 12 || got.ArchiveKey != run.ArchiveKey ||
+		got.PurgedArchives != 1 || got.Trigger != domain.RetentionTriggerManual || got.TriggeredBy != "admin@example.com" ||
+		got.FinishedAt == nil || !got.FinishedAt.Equal(finished) || !got.ArchivedBefore.Equal(run.ArchivedBefore) ||
+		got.ArchivePurgedAt != nil {
+		t.Errorf("GetRetentionRun = %+v, want the updated run %+v", got, run)
+	}
+
+	runs, err := s.ListRetentionRuns(ctx)
+	if err != nil || len(runs) != 2 || runs[0].ID != run.ID {
+		t.Fatalf("ListRetentionRuns = %d runs, %v; want 2, most recently started first", len(runs), err)
+	}
+	if runs[1].ArchivePurgedAt == nil || !runs[1].ArchivePurgedAt.Equal(finished) {
+		t.Errorf("older run ArchivePurgedAt = %v, want %v", runs[1].ArchivePurgedAt, finished)
+	}
+}
+
+func testImportRunCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetImportRun(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetImportRun(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.UpdateImportRun(ctx, &domain.ImportRun{ID: uuid.New()}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("UpdateImportRun(missing): got %v, want domain.ErrNotFound", err)
+	}
+
+	older := &domain.ImportRun{
+		ID: uuid.New(), Provider: "opsgenie", Kind: "alerts", FilterHash: "a",
+		Since: now().Add(-48 * time.Hour), Until: now(), Status: domain.ImportRunCompleted,
+		StartedAt: now().Add(-time.Hour), UpdatedAt: now().Add(-time.Hour),
+	}
+	run := &domain.ImportRun{
+		ID: uuid.New(), Provider: "pagerduty", Kind: "alerts", FilterHash: "b",
+		Since: now().Add(-24 * time.Hour), Until: now(), TeamIDs: []string{"T1", "T2"},
+		Status: domain.ImportRunRunning, StartedAt: now(), UpdatedAt: now(),
+	}
+	for _, r := range []*domain.ImportRun{older, run} {
+		if err := s.CreateImportRun(ctx, r); err != nil {
+			t.Fatalf("CreateImportRun: %v", err)
+		}
+	}
+
+	lastItem := now().Add(-time.Hour)
+	finished := now()
+	run.Offset = 200
+	run.LastItemAt = &lastItem
+	run.Stats = domain.ImportRunStats{TotalFetched: 200, NewOutages: 180, NewAlerts: 180, Skipped: 15, Errors: 5}
+	run.Status = domain.ImportRunFailed
+	run.Error = "connection reset"
+	run.FinishedAt = &finished
+	if err := s.UpdateImportRun(ctx, run); err != nil {
+		t.Fatalf("UpdateImportRun: %v", err)
+	}
+
+	got, err := s.GetImportRun(ctx, run.ID)
+	if err != nil {
+		t.Fatalf("GetImportRun: %v", err)
+	}
+	if got.Offset != 200 || got.Status != domain.ImportRunFailed || got.Error != "connection reset" ||
+		got.Stats != run.Stats || !reflect.DeepEqual(got.TeamIDs, run.TeamIDs) ||
+		got.LastItemAt == nil || !got.LastItemAt.Equal(lastItem) ||
+		got.FinishedAt == nil || !got.FinishedAt.Equal(finished) || !got.Since.Equal(run.Since) {
+		t.Errorf("GetImportRun = %+v, want the updated run %+v", got, run)
+	}
+
+	runs, err := s.ListImportRuns(ctx, "")
+	if err != nil || len(runs) != 2 || runs[0].ID != run.ID {
+		t.Fatalf("ListImportRuns = %d runs, %v; want 2, most recently started first", len(runs), err)
+	}
+	if runs[1].TeamIDs != nil {
+		t.Errorf("TeamIDs of a run without a team filter = %v, want nil", runs[1].TeamIDs)
+	}
+	runs, err = s.ListImportRuns(ctx, "opsgenie")
+	if err != nil || len(runs) != 1 || runs[0].ID != older.ID {
+		t.Errorf("ListImportRuns(opsgenie) = %d runs, %v; want the opsgenie run", len(runs), err)
+	}
+}
+
+func testSavedViewCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetSavedView(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetSavedView(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.UpdateSavedView(ctx, &domain.SavedView{ID: uuid.New(), Name: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("UpdateSavedView(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.DeleteSavedView(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("DeleteSavedView(missing): got %v, want domain.ErrNotFound", err)
+	}
+
+	view := &domain.SavedView{
+		ID: uuid.New(), Name: "open api outages", Owner: "alice@example.com",
+		Filter: domain.ViewFilter{
+			Statuses: []string{"open", "investigating"}, MinSeverity: "high",
+			Tags: []domain.TagFilter{{Key: "service", Value: "api"}}, Teams: []string{"payments"},
+		},
+		SlackChannel: "C123", SummaryPeriod: domain.DigestDaily,
+		CreatedAt: now(), UpdatedAt: now(),
+	}
+	other := &domain.SavedView{ID: uuid.New(), Name: "everything", Owner: "bob@example.com", CreatedAt: now(), UpdatedAt: now()}
+	for _, v := range []*domain.SavedView{view, other} {
+		if err := s.CreateSavedView(ctx, v); err != nil {
+			t.Fatalf("CreateSavedView: %v", err)
+		}
+	}
+	dup := &domain.SavedView{ID: uuid.New(), Name: view.Name, Owner: "bob@example.com", CreatedAt: now(), UpdatedAt: now()}
+	if err := s.CreateSavedView(ctx, dup); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateSavedView(duplicate name): got %v, want domain.ErrConflict", err)
+	}
+
+	got, err := s.GetSavedView(ctx, view.ID)
+	if err != nil {
+		t.Fatalf("GetSavedView: %v", err)
+	}
+	if got.Name != view.Name || got.Owner != view.Owner || !reflect.DeepEqual(got.Filter, view.Filter) ||
+		got.SlackChannel != "C123" || got.SummaryPeriod != domain.DigestDaily || got.LastSummaryAt != nil ||
+		!got.CreatedAt.Equal(view.CreatedAt) {
+		t.Errorf("GetSavedView = %+v, want %+v", got, view)
+	}
+
+	summarised := now()
+	view.LastSummaryAt = &summarised
+	view.Filter.MinSeverity = "critical"
+	view.UpdatedAt = now()
+	if err := s.UpdateSavedView(ctx, view); err != nil {
+		t.Fatalf("UpdateSavedView: %v", err)
+	}
+	other.Name = view.Name
+	if err := s.UpdateSavedView(ctx, other); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("UpdateSavedView(duplicate name): got %v, want domain.ErrConflict", err)
+	}
+
+	views, err := s.ListSavedViews(ctx)
+	if err != nil || len(views) != 2 {
+		t.Fatalf("ListSavedViews = %d views, %v; want 2", len(views), err)
+	}
+	if views[0].Name != "everything" || views[1].ID != view.ID {
+		t.Errorf("ListSavedViews = %q, %q; want ordered by name", views[0].Name, views[1].Name)
+	}
+	if views[0].Filter.Statuses != nil || views[0].Filter.Tags != nil {
+		t.Errorf("Filter of a view without conditions = %+v, want empty", views[0].Filter)
+	}
+	if got := views[1]; got.Filter.MinSeverity != "critical" || got.LastSummaryAt == nil || !got.LastSummaryAt.Equal(summarised) {
+		t.Errorf("updated view = %+v, want critical minimum severity and last summary time", got)
+	}
+
+	if err := s.DeleteSavedView(ctx, view.ID); err != nil {
+		t.Fatalf("DeleteSavedView: %v", err)
+	}
+	if _, err := s.GetSavedView(ctx, view.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetSavedView after delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testActionItemCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetActionItem(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetActionItem(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.UpdateActionItem(ctx, &domain.ActionItem{ID: uuid.New(), Status: domain.ActionItemOpen}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("UpdateActionItem(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.DeleteActionItem(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("DeleteActionItem(missing): got %v, want domain.ErrNotFound", err)
+	}
+
+	outage := createOutage(t, s)
+	other := createOutage(t, s)
+	lastWeek := now().Add(-7 * 24 * time.Hour)
+	yesterday := now().Add(-24 * time.Hour)
+	tomorrow := now().Add(24 * time.Hour)
+	newItem := func(outageID uuid.UUID, desc, status string, due *time.Time, age time.Duration) *domain.ActionItem {
+		t.Helper()
+		item := &domain.ActionItem{
+			ID: uuid.New(), OutageID: outageID, Description: desc, Assignee: "alice@example.com",
+			DueDate: due, Status: status, CreatedBy: "bob@example.com",
+			CreatedAt: now().Add(-age), UpdatedAt: now().Add(-age),
+		}
+		if err := s.CreateActionItem(ctx, item); err != nil {
+			t.Fatalf("CreateActionItem: %v", err)
+		}
+		return item
+	}
+	late := newItem(outage.ID, "add alerting", domain.ActionItemOpen, &yesterday, time.Hour)
+	first := newItem(outage.ID, "write runbook", domain.ActionItemInProgress, &lastWeek, 2*time.Hour)
+	newItem(outage.ID, "not due", domain.ActionItemOpen, &tomorrow, 0)
+	newItem(outage.ID, "no due date", domain.ActionItemOpen, nil, 0)
+	done := newItem(other.ID, "already done", domain.ActionItemDone, &lastWeek, 0)
+
+	got, err := s.GetActionItem(ctx, late.ID)
+	if err != nil {
+		t.Fatalf("GetActionItem: %v", err)
+	}
+	if got.OutageID != outage.ID || got.Description != "add alerting" || got.Assignee != "alice@example.com" ||
+		got.DueDate == nil || !got.DueDate.Equal(yesterday) || got.Status != domain.ActionItemOpen ||
+		got.CreatedBy != "bob@example.com" || got.ClosedAt != nil || !got.CreatedAt.Equal(late.CreatedAt) {
+		t.Errorf("GetActionItem = %+v, want %+v", got, late)
+	}
+
+	items, err := s.ListActionItemsByOutage(ctx, outage.ID)
+	if err != nil || len(items) != 4 {
+		t.Fatalf("ListActionItemsByOutage = %d items, %v; want 4", len(items), err)
+	}
+	if items[0].ID != first.ID || items[1].ID != late.ID {
+		t.Errorf("ListActionItemsByOutage = %q, %q, ...; want oldest first", items[0].Description, items[1].Description)
+	}
+
+	overdue, err := s.ListOverdueActionItems(ctx, now())
+	if err != nil || len(overdue) != 2 {
+		t.Fatalf("ListOverdueActionItems = %d items, %v; want 2", len(overdue), err)
+	}
+	if overdue[0].ID != first.ID || overdue[1].ID != late.ID {
+		t.Errorf("ListOverdueActionItems = %q, %q; want earliest due first", overdue[0].Description, overdue[1].Description)
+	}
+
+	closed := now()
+	late.Status = domain.ActionItemDone
+	late.ClosedAt = &closed
+	late.DueDate = nil
+	late.Assignee = ""
+	late.UpdatedAt = now()
+	if err := s.UpdateActionItem(ctx, late); err != nil {
+		t.Fatalf("UpdateActionItem: %v", err)
+	}
+	got, err = s.GetActionItem(ctx, late.ID)
+	if err != nil {
+		t.Fatalf("GetActionItem after update: %v", err)
+	}
+	if got.Status != domain.ActionItemDone || got.ClosedAt == nil || !got.ClosedAt.Equal(closed) ||
+		got.DueDate != nil || got.Assignee != "" {
+		t.Errorf("updated action item = %+v, want done, unassigned and without a due date", got)
+	}
+	if overdue, err := s.ListOverdueActionItems(ctx, now()); err != nil || len(overdue) != 1 {
+		t.Errorf("ListOverdueActionItems after closing = %d items, %v; want 1", len(overdue), err)
+	}
+
+	if err := s.DeleteActionItem(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteActionItem: %v", err)
+	}
+	if _, err := s.GetActionItem(ctx, first.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetActionItem after delete: got %v, want domain.ErrNotFound", err)
+	}
+
+	if err := s.DeleteOutage(ctx, other.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	if _, err := s.GetActionItem(ctx, done.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetActionItem after outage delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testWatcherSaveAndRemove(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	other := createOutage(t, s)
+	if err := s.RemoveWatcher(ctx, outage.ID, "alice@example.com"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("RemoveWatcher(missing): got %v, want domain.ErrNotFound", err)
+	}
+
+	watch := func(outageID uuid.UUID, email, channel string, age time.Duration) {
+		t.Helper()
+		w := &domain.Watcher{OutageID: outageID, Email: email, Channel: channel, CreatedAt: now().Add(-age)}
+		if err := s.SaveWatcher(ctx, w); err != nil {
+			t.Fatalf("SaveWatcher: %v", err)
+		}
+	}
+	watch(outage.ID, "bob@example.com", domain.WatchChannelEmail, 0)
+	watch(outage.ID, "alice@example.com", domain.WatchChannelEmail, time.Hour)
+	watch(other.ID, "alice@example.com", domain.WatchChannelSlack, 0)
+	// Watching again changes the channel but keeps the watcher's place
+	watch(outage.ID, "alice@example.com", domain.WatchChannelSlack, -time.Hour)
+
+	watchers, err := s.ListWatchers(ctx, outage.ID)
+	if err != nil || len(watchers) != 2 {
+		t.Fatalf("ListWatchers = %d watchers, %v; want 2", len(watchers), err)
+	}
+	if w := watchers[0]; w.Email != "alice@example.com" || w.Channel != domain.WatchChannelSlack ||
+		w.OutageID != outage.ID || !w.CreatedAt.Equal(now().Add(-time.Hour)) {
+		t.Errorf("first watcher = %+v, want alice by slack since an hour ago", w)
+	}
+	if watchers[1].Email != "bob@example.com" {
+		t.Errorf("second watcher = %+v, want bob", watchers[1])
+	}
+
+	if err := s.RemoveWatcher(ctx, outage.ID, "alice@example.com"); err != nil {
+		t.Fatalf("RemoveWatcher: %v", err)
+	}
+	if watchers, err := s.ListWatchers(ctx, outage.ID); err != nil || len(watchers) != 1 {
+		t.Errorf("ListWatchers after remove = %d watchers, %v; want 1", len(watchers), err)
+	}
+	if watchers, err := s.ListWatchers(ctx, other.ID); err != nil || len(watchers) != 1 {
+		t.Errorf("ListWatchers of other outage = %d watchers, %v; want 1", len(watchers), err)
+	}
+
+	if err := s.DeleteOutage(ctx, other.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	if watchers, err := s.ListWatchers(ctx, other.ID); err != nil || len(watchers) != 0 {
+		t.Errorf("ListWatchers after outage delete = %d watchers, %v; want none", len(watchers), err)
+	}
+}
+
+func testServiceCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetService(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetService(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.UpdateService(ctx, &domain.Service{Name: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("UpdateService(missing): got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.DeleteService(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("DeleteService(missing): got %v, want domain.ErrNotFound", err)
+	}
+
+	checkout := &domain.Service{
+		Name: "checkout", Description: "Checkout API", OwningTeam: "payments", Tier: 1,
+		RunbookURL: "https://runbooks.example.com/checkout", Repo: "https://github.com/example/checkout",
+		StatuspageComponent: "cmp1", CreatedAt: now().Add(-time.Hour), UpdatedAt: now().Add(-time.Hour),
+	}
+	search := &domain.Service{Name: "search", CreatedAt: now(), UpdatedAt: now()}
+	for _, svc := range []*domain.Service{search, checkout} {
+		if err := s.CreateService(ctx, svc); err != nil {
+			t.Fatalf("CreateService: %v", err)
+		}
+	}
+	if err := s.CreateService(ctx, &domain.Service{Name: "search", CreatedAt: now(), UpdatedAt: now()}); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateService(duplicate name): got %v, want domain.ErrConflict", err)
+	}
+
+	got, err := s.GetService(ctx, "checkout")
+	if err != nil {
+		t.Fatalf("GetService: %v", err)
+	}
+	if got.Description != "Checkout API" || got.OwningTeam != "payments" || got.Tier != 1 ||
+		got.RunbookURL != checkout.RunbookURL || got.Repo != checkout.Repo ||
+		got.StatuspageComponent != "cmp1" || got.Source != "" || !got.CreatedAt.Equal(checkout.CreatedAt) {
+		t.Errorf("GetService = %+v, want %+v", got, checkout)
+	}
+
+	checkout.Description = ""
+	checkout.StatuspageComponent = "cmp2"
+	checkout.Tier = 2
+	checkout.Source = "backstage"
+	checkout.UpdatedAt = now()
+	if err := s.UpdateService(ctx, checkout); err != nil {
+		t.Fatalf("UpdateService: %v", err)
+	}
+	services, err := s.ListServices(ctx)
+	if err != nil || len(services) != 2 {
+		t.Fatalf("ListServices = %d services, %v; want 2", len(services), err)
+	}
+	if services[0].Name != "checkout" || services[1].Name != "search" {
+		t.Errorf("ListServices = %q, %q; want ordered by name", services[0].Name, services[1].Name)
+	}
+	if got := services[0]; got.Description != "" || got.StatuspageComponent != "cmp2" || got.Tier != 2 ||
+		got.Source != "backstage" || got.OwningTeam != "payments" || !got.UpdatedAt.Equal(checkout.UpdatedAt) {
+		t.Errorf("updated service = %+v, want no description, component cmp2, tier 2 and source backstage", got)
+	}
+
+	if err := s.DeleteService(ctx, "search"); err != nil {
+		t.Fatalf("DeleteService: %v", err)
+	}
+	if _, err := s.GetService(ctx, "search"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetService after delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testIngestionRecordAndList(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	records, err := s.ListIngestionRecords(ctx)
+	if err != nil {
+		t.Fatalf("ListIngestionRecords: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("ListIngestionRecords before record = %d records, want 0", len(records))
+	}
+
+	latest := now()
+	earlier := latest.Add(-time.Hour)
+	if err := s.RecordIngestionSuccess(ctx, "pagerduty", earlier); err != nil {
+		t.Fatalf("RecordIngestionSuccess: %v", err)
+	}
+	if err := s.RecordIngestionFailure(ctx, "pagerduty", latest, "bad payload"); err != nil {
+		t.Fatalf("RecordIngestionFailure: %v", err)
+	}
+	if err := s.RecordIngestionFailure(ctx, "opsgenie", earlier, "timeout"); err != nil {
+		t.Fatalf("RecordIngestionFailure: %v", err)
+	}
+
+	records, err = s.ListIngestionRecords(ctx)
+	if err != nil {
+		t.Fatalf("ListIngestionRecords: %v", err)
+	}
+	if len(records) != 2 || records[0].Source != "opsgenie" || records[1].Source != "pagerduty" {
+		t.Fatalf("ListIngestionRecords = %+v, want opsgenie then pagerduty", records)
+	}
+	if og := records[0]; og.LastSuccessAt != nil || og.LastFailureAt == nil || og.LastError != "timeout" {
+		t.Errorf("opsgenie record = %+v, want only a failure", og)
+	}
+	pd := records[1]
+	if pd.LastSuccessAt == nil || !pd.LastSuccessAt.Equal(earlier) {
+		t.Errorf("pagerduty LastSuccessAt = %v, want %v", pd.LastSuccessAt, earlier)
+	}
+	if pd.LastFailureAt == nil || !pd.LastFailureAt.Equal(latest) || pd.LastError != "bad payload" {
+		t.Errorf("pagerduty failure = %v %q, want %v \"bad payload\"", pd.LastFailureAt, pd.LastError, latest)
+	}
+	if !pd.UpdatedAt.Equal(latest) {
+		t.Errorf("pagerduty UpdatedAt = %v, want %v", pd.UpdatedAt, latest)
+	}
+}
+
+func testConfigResourceCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	if _, err := s.GetConfigResource(ctx, domain.ResourceTeam, "payments"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetConfigResource before save: got %v, want domain.ErrNotFound", err)
+	}
+
+	resources := []*domain.ConfigResource{
+		{Kind: domain.ResourceTeam, Name: "search", Spec: json.RawMessage(`{"name":"search"}`), UpdatedAt: now()},
+		{Kind: domain.ResourceTeam, Name: "payments", Spec: json.RawMessage(`{"name":"payments"}`), UpdatedAt: now()},
+		{Kind: domain.ResourceTemplate, Name: "db", Spec: json.RawMessage(`{"name":"db"}`), UpdatedAt: now()},
+	}
+	for _, r := range resources {
+		if err := s.UpsertConfigResource(ctx, r); err != nil {
+			t.Fatalf("UpsertConfigResource: %v", err)
+		}
+	}
+	updated := &domain.ConfigResource{Kind: domain.ResourceTeam, Name: "payments", Spec: json.RawMessage(`{"name":"payments","slack_channel":"#pay"}`), UpdatedAt: now()}
+	if err := s.UpsertConfigResource(ctx, updated); err != nil {
+		t.Fatalf("UpsertConfigResource (update): %v", err)
+	}
+
+	got, err := s.GetConfigResource(ctx, domain.ResourceTeam, "payments")
+	if err != nil {
+		t.Fatalf("GetConfigResource: %v", err)
+	}
+	if string(got.Spec) != string(updated.Spec) {
+		t.Errorf("Spec = %s, want %s", got.Spec, updated.Spec)
+	}
+
+	teams, err := s.ListConfigResources(ctx, domain.ResourceTeam)
+	if err != nil {
+		t.Fatalf("ListConfigResources: %v", err)
+	}
+	if len(teams) != 2 || teams[0].Name != "payments" || teams[1].Name != "search" {
+		t.Errorf("teams = %+v, want payments then search", teams)
+	}
+	all, err := s.ListConfigResources(ctx, "")
+	if err != nil {
+		t.Fatalf("ListConfigResources(all): %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("len(all) = %d, want 3", len(all))
+	}
+
+	if err := s.DeleteConfigResource(ctx, domain.ResourceTemplate, "db"); err != nil {
+		t.Fatalf("DeleteConfigResource: %v", err)
+	}
+	if err := s.DeleteConfigResource(ctx, domain.ResourceTemplate, "db"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("second DeleteConfigResource: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testAttachmentCRUD(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	first := &domain.Attachment{
+		ID: uuid.New(), OutageID: outage.ID, Filename: "latency.png", ContentType: "image/png",
+		Size: 2048, SHA256: strings.Repeat("a", 64), StorageKey: "outages/a/latency.png",
+		UploadedBy: "alice@example.com", CreatedAt: now().Add(-time.Minute),
+	}
+	second := &domain.Attachment{
+		ID: uuid.New(), OutageID: outage.ID, Filename: "api.log", ContentType: "text/plain; charset=utf-8",
+		Size: 12, SHA256: strings.Repeat("b", 64), StorageKey: "outages/a/api.log", CreatedAt: now(),
+	}
+	for _, a := range []*domain.Attachment{second, first} {
+		if err := s.CreateAttachment(ctx, a); err != nil {
+			t.Fatalf("CreateAttachment: %v", err)
+		}
+	}
+
+	got, err := s.GetAttachment(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetAttachment: %v", err)
+	}
+	if got.Filename != first.Filename || got.ContentType != first.ContentType || got.Size != first.Size ||
+		got.SHA256 != first.SHA256 || got.StorageKey != first.StorageKey || got.UploadedBy != first.UploadedBy ||
+		got.NoteID != nil || !got.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("GetAttachment = %+v, want %+v", got, first)
+	}
+
+	list, err := s.ListAttachmentsByOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("ListAttachmentsByOutage: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != first.ID || list[1].ID != second.ID {
+		t.Errorf("ListAttachmentsByOutage = %+v, want oldest first", list)
+	}
+
+	if err := s.DeleteAttachment(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteAttachment: %v", err)
+	}
+	if _, err := s.GetAttachment(ctx, first.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAttachment after delete: got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.DeleteAttachment(ctx, first.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("second DeleteAttachment: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+func testAttachmentNoteAndOutageDelete(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, Content: "see graph", Format: "plaintext",
+		Author: "bob", CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	attachment := &domain.Attachment{
+		ID: uuid.New(), OutageID: outage.ID, NoteID: &note.ID, Filename: "graph.png",
+		ContentType: "image/png", Size: 1, SHA256: strings.Repeat("c", 64), StorageKey: "k", CreatedAt: now(),
+	}
+	if err := s.CreateAttachment(ctx, attachment); err != nil {
+		t.Fatalf("CreateAttachment: %v", err)
+	}
+	got, err := s.GetAttachment(ctx, attachment.ID)
+	if err != nil {
+		t.Fatalf("GetAttachment: %v", err)
+	}
+	if got.NoteID == nil || *got.NoteID != note.ID {
+		t.Errorf("NoteID = %v, want %s", got.NoteID, note.ID)
+	}
+
+	// Deleting the note keeps the attachment on the outage
+	if err := s.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	got, err = s.GetAttachment(ctx, attachment.ID)
+	if err != nil {
+		t.Fatalf("GetAttachment after note delete: %v", err)
+	}
+	if got.NoteID != nil {
+		t.Errorf("NoteID after note delete = %v, want nil", got.NoteID)
+	}
+
+	if err := s.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+	if _, err := s.GetAttachment(ctx, attachment.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAttachment after outage delete: got %v, want domain.ErrNotFound", err)
+	}
+}
+
+// ── Cascades ──────────────────────────────────────────────────────────────────
+
+func testOutageCascadeDelete(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	other := createOutage(t, s)
+
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, Content: "n", Format: "plaintext",
+		Author: "bob", CreatedAt: now(), UpdatedAt: now(),
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	tag := &domain.Tag{ID: uuid.New(), OutageID: outage.ID, Key: "jira", Value: "OPS-1", CreatedAt: now()}
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	otherTag := &domain.Tag{ID: uuid.New(), OutageID: other.ID, Key: "jira", Value: "OPS-2", CreatedAt: now()}
+	if err := s.CreateTag(ctx, otherTag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	review := &domain.OutageReview{OutageID: outage.ID, Status: domain.ReviewNeedsReview, CreatedAt: now(), UpdatedAt: now()}
+	if err := s.UpsertOutageReview(ctx, review); err != nil {
+		t.Fatalf("UpsertOutageReview: %v", err)
+	}
+
+	if err := s.DeleteOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("DeleteOutage: %v", err)
+	}
+
+	if _, err := s.GetNote(ctx, note.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetNote after cascade delete: got %v, want domain.ErrNotFound", err)
+	}
+	if _, err := s.GetTag(ctx, tag.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTag after cascade delete: got %v, want domain.ErrNotFound", err)
+	}
+	if _, err := s.GetOutageReview(ctx, outage.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutageReview after cascade delete: got %v, want domain.ErrNotFound", err)
+	}
+	if reviews, err := s.ListOutageReviews(ctx, ""); err != nil || len(reviews) != 0 {
+		t.Errorf("ListOutageReviews after cascade delete = %d reviews, %v; want none", len(reviews), err)
+	}
+	tags, err := s.ListTagsByKey(ctx, "jira")
+	if err != nil {
+		t.Fatalf("ListTagsByKey: %v", err)
+	}
+	if len(tags) != 1 || tags[0].ID != otherTag.ID {
+		t.Errorf("ListTagsByKey(jira) = %+v, want only the other outage's tag", tags)
+	}
+
+	// The other outage is untouched
+	got, err := s.GetOutage(ctx, other.ID)
+	if err != nil {
+		t.Fatalf("GetOutage(other): %v", err)
+	}
+	if len(got.Tags) != 1 {
+		t.Errorf("other outage tags: got %d, want 1", len(got.Tags))
+	}
+}
+
+func testOutageTrashRestorePurge(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := createOutage(t, s)
+	live := createOutage(t, s)
+	tag := &domain.Tag{ID: uuid.New(), OutageID: outage.ID, Key: "jira", Value: "OPS-1", CreatedAt: now()}
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	trashedAt := now()
+	if err := s.TrashOutage(ctx, outage.ID, trashedAt); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+	if err := s.TrashOutage(ctx, outage.ID, trashedAt); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("TrashOutage twice: got %v, want domain.ErrNotFound", err)
+	}
+	if err := s.TrashOutage(ctx, uuid.New(), trashedAt); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("TrashOutage(missing): got %v, want domain.ErrNotFound", err)
+	}
+
+	got, err := s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage(trashed): %v", err)
+	}
+	if got.DeletedAt == nil || !got.DeletedAt.Equal(trashedAt) {
+		t.Errorf("DeletedAt: got %v, want %v", got.DeletedAt, trashedAt)
+	}
+	list, err := s.ListOutages(ctx, 10, 0, false)
+	if err != nil {
+		t.Fatalf("ListOutages: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != live.ID {
+		t.Errorf("ListOutages = %d outages, want only the live one", len(list))
+	}
+	if list, err = s.ListOutages(ctx, 10, 0, true); err != nil || len(list) != 2 {
+		t.Errorf("ListOutages(includeDeleted) = %d outages, %v; want 2", len(list), err)
+	}
+	if found, err := s.FindOutagesByTag(ctx, "jira", "OPS-1"); err != nil || len(found) != 0 {
+		t.Errorf("FindOutagesByTag(trashed) = %d outages, %v; want none", len(found), err)
+	}
+
+	if err := s.RestoreOutage(ctx, outage.ID); err != nil {
+		t.Fatalf("RestoreOutage: %v", err)
+	}
+	if err := s.RestoreOutage(ctx, outage.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("RestoreOutage twice: got %v, want domain.ErrNotFound", err)
+	}
+	if got, err = s.GetOutage(ctx, outage.ID); err != nil || got.DeletedAt != nil {
+		t.Errorf("GetOutage after restore: DeletedAt %v, err %v; want nil", got.DeletedAt, err)
+	}
+
+	// Only outages trashed before the cutoff are purged, with their
+	// associations; live outages are never touched
+	if err := s.TrashOutage(ctx, outage.ID, trashedAt); err != nil {
+		t.Fatalf("TrashOutage: %v", err)
+	}
+	if n, err := s.PurgeOutages(ctx, trashedAt); err != nil || n != 0 {
+		t.Errorf("PurgeOutages(at trash time) = %d, %v; want 0", n, err)
+	}
+	if n, err := s.PurgeOutages(ctx, trashedAt.Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("PurgeOutages = %d, %v; want 1", n, err)
+	}
+	if _, err := s.GetOutage(ctx, outage.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetOutage after purge: got %v, want domain.ErrNotFound", err)
+	}
+	if _, err := s.GetTag(ctx, tag.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTag after purge: got %v, want domain.ErrNotFound", err)
+	}
+	if _, err := s.GetOutage(ctx, live.ID); err != nil {
+		t.Errorf("GetOutage(live) after purge: %v", err)
+	}
+}
+
+// ── JSON round-trips ──────────────────────────────────────────────────────────
+
+func testCustomFieldsRoundTrip(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	outage := &domain.Outage{
+		ID: uuid.New(), Title: "o", Status: "open", Severity: "low",
+		CreatedAt: now(), UpdatedAt: now(),
+		Metadata:     map[string]string{"team": "payments"},
+		CustomFields: customFields(),
+	}
+	if err := s.CreateOutage(ctx, outage); err != nil {
+		t.Fatalf("CreateOutage: %v", err)
+	}
+	alert := &domain.Alert{
+		ID: uuid.New(), OutageID: outage.ID, ExternalID: "P1", Source: "pagerduty",
+		Title: "a", TriggeredAt: now(), CreatedAt: now(),
+		SourceMetadata: map[string]any{"urgency": "high", "escalation_level": float64(2)},
+		Metadata:       map[string]string{"service": "api"},
+		CustomFields:   customFields(),
+	}
+	if err := s.CreateAlert(ctx, alert); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	note := &domain.Note{
+		ID: uuid.New(), OutageID: outage.ID, Content: "n", Format: "plaintext",
+		Author: "bob", CreatedAt: now(), UpdatedAt: now(),
+		Metadata:     map[string]string{"source": "slack"},
+		CustomFields: customFields(),
+	}
+	if err := s.CreateNote(ctx, note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	tag := &domain.Tag{
+		ID: uuid.New(), OutageID: outage.ID, Key: "jira", Value: "OPS-1", CreatedAt: now(),
+		CustomFields: customFields(),
+	}
+	if err := s.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+
+	gotOutage, err := s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage: %v", err)
+	}
+	if !reflect.DeepEqual(gotOutage.Metadata, outage.Metadata) || !reflect.DeepEqual(gotOutage.CustomFields, outage.CustomFields) {
+		t.Errorf("outage = %v %v, want %v %v", gotOutage.Metadata, gotOutage.CustomFields, outage.Metadata, outage.CustomFields)
+	}
+	gotAlert, err := s.GetAlert(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetAlert: %v", err)
+	}
+	if !reflect.DeepEqual(gotAlert.SourceMetadata, alert.SourceMetadata) ||
+		!reflect.DeepEqual(gotAlert.Metadata, alert.Metadata) ||
+		!reflect.DeepEqual(gotAlert.CustomFields, alert.CustomFields) {
+		t.Errorf("alert = %v %v %v, want %v %v %v",
+			gotAlert.SourceMetadata, gotAlert.Metadata, gotAlert.CustomFields,
+			alert.SourceMetadata, alert.Metadata, alert.CustomFields)
+	}
+	gotNote, err := s.GetNote(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if !reflect.DeepEqual(gotNote.Metadata, note.Metadata) || !reflect.DeepEqual(gotNote.CustomFields, note.CustomFields) {
+		t.Errorf("note = %v %v, want %v %v", gotNote.Metadata, gotNote.CustomFields, note.Metadata, note.CustomFields)
+	}
+	gotTag, err := s.GetTag(ctx, tag.ID)
+	if err != nil {
+		t.Fatalf("GetTag: %v", err)
+	}
+	if !reflect.DeepEqual(gotTag.CustomFields, tag.CustomFields) {
+		t.Errorf("tag custom fields = %v, want %v", gotTag.CustomFields, tag.CustomFields)
+	}
+
+	// Updates replace the stored maps
+	outage.CustomFields = map[string]any{"owner": "search"}
+	outage.UpdatedAt = now()
+	if err := s.UpdateOutage(ctx, outage); err != nil {
+		t.Fatalf("UpdateOutage: %v", err)
+	}
+	gotOutage, err = s.GetOutage(ctx, outage.ID)
+	if err != nil {
+		t.Fatalf("GetOutage after update: %v", err)
+	}
+	if !reflect.DeepEqual(gotOutage.CustomFields, outage.CustomFields) {
+		t.Errorf("outage custom fields after update = %v, want %v", gotOutage.CustomFields, outage.CustomFields)
+	}
+	alert.CustomFields = map[string]any{"owner": "search"}
+	if err := s.UpdateAlert(ctx, alert); err != nil {
+		t.Fatalf("UpdateAlert: %v", err)
+	}
+	gotAlert, err = s.GetAlert(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetAlert after update: %v", err)
+	}
+	if !reflect.DeepEqual(gotAlert.CustomFields, alert.CustomFields) {
+		t.Errorf("alert custom fields after update = %v, want %v", gotAlert.CustomFields, alert.CustomFields)
+	}
+}
+
+// testSchemaUpToDate checks a store the suite can run against, which has
+// every migration applied, reports its schema as up to date
+func testSchemaUpToDate(t *testing.T, newStorage Factory) {
+	s := newStorage(t)
+	if err := s.CheckSchema(context.Background()); err != nil {
+		t.Errorf("CheckSchema: %v", err)
+	}
+}
+
+// outageIDs lists the IDs of outages, for failure messages
+func outageIDs(outages []*domain.Outage) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(outages))
+	for _, o := range outages {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
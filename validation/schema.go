@@ -0,0 +1,175 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Entities whose custom_fields can be constrained by a schema
+const (
+	EntityOutage = "outage"
+	EntityNote   = "note"
+	EntityTag    = "tag"
+)
+
+// Field types supported in custom field schemas. They mirror the JSON types a
+// custom_fields value can decode to.
+const (
+	FieldTypeString  = "string"
+	FieldTypeNumber  = "number"
+	FieldTypeBoolean = "boolean"
+	FieldTypeObject  = "object"
+	FieldTypeArray   = "array"
+)
+
+var validEntities = []string{EntityOutage, EntityNote, EntityTag}
+
+var validFieldTypes = []string{FieldTypeString, FieldTypeNumber, FieldTypeBoolean, FieldTypeObject, FieldTypeArray}
+
+// FieldDefinition describes a single named custom field
+type FieldDefinition struct {
+	Name          string   `yaml:"name" json:"name"`
+	Type          string   `yaml:"type" json:"type"`
+	Required      bool     `yaml:"required,omitempty" json:"required"`
+	AllowedValues []string `yaml:"allowed_values,omitempty" json:"allowed_values,omitempty"` // Only valid for string fields
+	Description   string   `yaml:"description,omitempty" json:"description,omitempty"`
+	// RequiredForSeverities makes the field required on outages of these
+	// severities only. Only valid for outage fields.
+	RequiredForSeverities []string `yaml:"required_for_severities,omitempty" json:"required_for_severities,omitempty"`
+}
+
+// EntitySchema lists the custom fields accepted for one entity type.
+// Fields not listed are rejected unless AllowUnknown is set.
+type EntitySchema struct {
+	Fields       []FieldDefinition `yaml:"fields" json:"fields"`
+	AllowUnknown bool              `yaml:"allow_unknown,omitempty" json:"allow_unknown"`
+}
+
+// Schemas maps an entity name (outage, note, tag) to its custom field schema.
+// Entities without a schema accept any custom_fields.
+type Schemas map[string]EntitySchema
+
+// Check verifies that the schema definitions themselves are well formed
+func (s Schemas) Check() error {
+	for entity, schema := range s {
+		if !slices.Contains(validEntities, entity) {
+			return fmt.Errorf("custom field schema: unknown entity %q (want one of %s)",
+				entity, strings.Join(validEntities, ", "))
+		}
+		seen := make(map[string]bool, len(schema.Fields))
+		for _, f := range schema.Fields {
+			if f.Name == "" {
+				return fmt.Errorf("custom field schema for %s: field name cannot be empty", entity)
+			}
+			if seen[f.Name] {
+				return fmt.Errorf("custom field schema for %s: duplicate field %q", entity, f.Name)
+			}
+			seen[f.Name] = true
+			if !slices.Contains(validFieldTypes, f.Type) {
+				return fmt.Errorf("custom field schema for %s: field %q has unknown type %q (want one of %s)",
+					entity, f.Name, f.Type, strings.Join(validFieldTypes, ", "))
+			}
+			if len(f.AllowedValues) > 0 && f.Type != FieldTypeString {
+				return fmt.Errorf("custom field schema for %s: field %q: allowed_values is only supported for string fields",
+					entity, f.Name)
+			}
+			if len(f.RequiredForSeverities) > 0 && entity != EntityOutage {
+				return fmt.Errorf("custom field schema for %s: field %q: required_for_severities is only supported for outage fields",
+					entity, f.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks customFields against the schema for entity. severity is
+// the severity of the outage being written, which decides the fields
+// required by RequiredForSeverities; it is empty for notes and tags. It
+// returns nil when no schema is defined for the entity.
+func (s Schemas) Validate(entity string, customFields map[string]any, severity string) error {
+	schema, ok := s[entity]
+	if !ok {
+		return nil
+	}
+
+	known := make(map[string]bool, len(schema.Fields))
+	for _, f := range schema.Fields {
+		known[f.Name] = true
+		value, present := customFields[f.Name]
+		if !present || value == nil {
+			if f.Required {
+				return fmt.Errorf("field '%s' is required", f.Name)
+			}
+			if severity != "" && slices.Contains(f.RequiredForSeverities, severity) {
+				return fmt.Errorf("field '%s' is required for %s severity", f.Name, severity)
+			}
+			continue
+		}
+		if !matchesType(value, f.Type) {
+			return fmt.Errorf("field '%s' must be of type %s", f.Name, f.Type)
+		}
+		if len(f.AllowedValues) > 0 && !slices.Contains(f.AllowedValues, value.(string)) {
+			return fmt.Errorf("field '%s' must be one of: %s", f.Name, strings.Join(f.AllowedValues, ", "))
+		}
+	}
+
+	if !schema.AllowUnknown {
+		var unknown []string
+		for name := range customFields {
+			if !known[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("unknown field(s): %s", strings.Join(unknown, ", "))
+		}
+	}
+
+	return nil
+}
+
+// With returns a copy of s with fields added to the schema for entity. An
+// entity without a schema gets one that still accepts unknown fields, so
+// defining one field does not restrict the others.
+func (s Schemas) With(entity string, fields ...FieldDefinition) Schemas {
+	merged := make(Schemas, len(s)+1)
+	for name, schema := range s {
+		merged[name] = schema
+	}
+	schema, ok := merged[entity]
+	if !ok {
+		schema.AllowUnknown = true
+	}
+	schema.Fields = append(slices.Clip(schema.Fields), fields...)
+	merged[entity] = schema
+	return merged
+}
+
+// matchesType reports whether v, as decoded from JSON (or built in Go), has
+// the given schema type.
+func matchesType(v any, fieldType string) bool {
+	switch fieldType {
+	case FieldTypeString:
+		_, ok := v.(string)
+		return ok
+	case FieldTypeNumber:
+		switch v.(type) {
+		case float64, float32, int, int32, int64, uint, uint32, uint64:
+			return true
+		}
+		return false
+	case FieldTypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case FieldTypeObject:
+		_, ok := v.(map[string]any)
+		return ok
+	case FieldTypeArray:
+		_, ok := v.([]any)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemasCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas Schemas
+		errMsg  string
+	}{
+		{
+			name:    "nil schemas are valid",
+			schemas: nil,
+		},
+		{
+			name: "valid schema",
+			schemas: Schemas{EntityOutage: {Fields: []FieldDefinition{
+				{Name: "impact", Type: FieldTypeString, AllowedValues: []string{"none", "full"}},
+				{Name: "users", Type: FieldTypeNumber},
+			}}},
+		},
+		{
+			name:    "unknown entity",
+			schemas: Schemas{"widget": {}},
+			errMsg:  "unknown entity",
+		},
+		{
+			name:    "unknown type",
+			schemas: Schemas{EntityNote: {Fields: []FieldDefinition{{Name: "x", Type: "date"}}}},
+			errMsg:  "unknown type",
+		},
+		{
+			name: "duplicate field",
+			schemas: Schemas{EntityTag: {Fields: []FieldDefinition{
+				{Name: "x", Type: FieldTypeString},
+				{Name: "x", Type: FieldTypeNumber},
+			}}},
+			errMsg: "duplicate field",
+		},
+		{
+			name:    "allowed values on non-string",
+			schemas: Schemas{EntityOutage: {Fields: []FieldDefinition{{Name: "x", Type: FieldTypeNumber, AllowedValues: []string{"1"}}}}},
+			errMsg:  "only supported for string fields",
+		},
+		{
+			name:    "required for severity on a note",
+			schemas: Schemas{EntityNote: {Fields: []FieldDefinition{{Name: "x", Type: FieldTypeString, RequiredForSeverities: []string{"critical"}}}}},
+			errMsg:  "only supported for outage fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schemas.Check()
+			if (err != nil) != (tt.errMsg != "") {
+				t.Fatalf("Check() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Check() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestSchemasValidate(t *testing.T) {
+	schemas := Schemas{
+		EntityOutage: {Fields: []FieldDefinition{
+			{Name: "impact", Type: FieldTypeString, Required: true, AllowedValues: []string{"none", "partial", "full"}},
+			{Name: "users", Type: FieldTypeNumber},
+			{Name: "customer_facing", Type: FieldTypeBoolean},
+			{Name: "links", Type: FieldTypeArray},
+			{Name: "postmortem_owner", Type: FieldTypeString, RequiredForSeverities: []string{"critical", "high"}},
+		}},
+		EntityNote: {AllowUnknown: true, Fields: []FieldDefinition{
+			{Name: "attachment", Type: FieldTypeObject},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		entity   string
+		input    map[string]any
+		severity string
+		errMsg   string
+	}{
+		{
+			name:   "valid outage fields",
+			entity: EntityOutage,
+			input:  map[string]any{"impact": "partial", "users": float64(120), "customer_facing": true, "links": []any{"a"}},
+		},
+		{
+			name:   "missing required field",
+			entity: EntityOutage,
+			input:  map[string]any{"users": 1},
+			errMsg: "'impact' is required",
+		},
+		{
+			name:   "nil map with required field",
+			entity: EntityOutage,
+			input:  nil,
+			errMsg: "'impact' is required",
+		},
+		{
+			name:     "missing field required for severity",
+			entity:   EntityOutage,
+			input:    map[string]any{"impact": "full"},
+			severity: "critical",
+			errMsg:   "'postmortem_owner' is required for critical severity",
+		},
+		{
+			name:     "field required for other severities",
+			entity:   EntityOutage,
+			input:    map[string]any{"impact": "full"},
+			severity: "low",
+		},
+		{
+			name:     "field required for severity present",
+			entity:   EntityOutage,
+			input:    map[string]any{"impact": "full", "postmortem_owner": "alice"},
+			severity: "high",
+		},
+		{
+			name:   "wrong type",
+			entity: EntityOutage,
+			input:  map[string]any{"impact": "full", "users": "many"},
+			errMsg: "must be of type number",
+		},
+		{
+			name:   "value not allowed",
+			entity: EntityOutage,
+			input:  map[string]any{"impact": "catastrophic"},
+			errMsg: "must be one of",
+		},
+		{
+			name:   "unknown field rejected",
+			entity: EntityOutage,
+			input:  map[string]any{"impact": "none", "colour": "red"},
+			errMsg: "unknown field(s): colour",
+		},
+		{
+			name:   "unknown field allowed",
+			entity: EntityNote,
+			input:  map[string]any{"anything": 1, "attachment": map[string]any{"url": "x"}},
+		},
+		{
+			name:   "entity without schema accepts anything",
+			entity: EntityTag,
+			input:  map[string]any{"whatever": []any{1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schemas.Validate(tt.entity, tt.input, tt.severity)
+			if (err != nil) != (tt.errMsg != "") {
+				t.Fatalf("Validate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestSchemasWith(t *testing.T) {
+	schemas := Schemas{EntityOutage: {Fields: []FieldDefinition{{Name: "impact", Type: FieldTypeString}}}}
+
+	merged := schemas.With(EntityOutage, FieldDefinition{Name: "users", Type: FieldTypeNumber}).
+		With(EntityNote, FieldDefinition{Name: "kind", Type: FieldTypeString, AllowedValues: []string{"update", "action"}})
+	if len(schemas[EntityOutage].Fields) != 1 {
+		t.Errorf("With modified the original schemas: %+v", schemas)
+	}
+	if err := merged.Validate(EntityOutage, map[string]any{"impact": "none", "users": 3.0}, ""); err != nil {
+		t.Errorf("outage with added field: %v", err)
+	}
+	if err := merged.Validate(EntityOutage, map[string]any{"colour": "red"}, ""); err == nil {
+		t.Error("outage schema from config no longer rejects unknown fields")
+	}
+	if err := merged.Validate(EntityNote, map[string]any{"kind": "update", "other": 1}, ""); err != nil {
+		t.Errorf("new note schema should accept unknown fields: %v", err)
+	}
+	if err := merged.Validate(EntityNote, map[string]any{"kind": "gossip"}, ""); err == nil {
+		t.Error("new note schema accepted a value outside allowed_values")
+	}
+}
@@ -1,16 +1,28 @@
 // API Base URL
 const API_BASE = '/api/v1';
 
+// How often to send a presence heartbeat while an outage is open. The server
+// forgets users 90 seconds after their last heartbeat.
+const PRESENCE_INTERVAL_MS = 30000;
+
 // State management
 const state = {
     currentView: 'list',
     currentOutageId: null,
+    replyToNoteId: null,
+    renderedNotes: {},
     outages: [],
     selectedOutageForMerge: null,
+    presence: {
+        outageId: null,
+        activity: 'viewing',
+        timer: null
+    },
     filters: {
         status: '',
         severity: '',
-        search: ''
+        search: '',
+        team: '' // Empty lists the signed-in user's teams; 'all' lists every outage
     }
 };
 
@@ -27,6 +39,16 @@ function showMessage(message, type = 'info') {
     }, 5000);
 }
 
+// responseError turns a failed API response into an Error. The API rejects
+// changes from users who have not signed in.
+async function responseError(response, fallback) {
+    if (response.status === 401) {
+        return new Error('Sign in at /auth/login to make changes');
+    }
+    const error = await response.json().catch(() => ({}));
+    return new Error(error.error || fallback);
+}
+
 function formatDate(dateString) {
     if (!dateString) return 'N/A';
     const date = new Date(dateString);
@@ -55,6 +77,7 @@ async function fetchOutages() {
         const params = new URLSearchParams();
         params.append('limit', '100');
         params.append('offset', '0');
+        if (state.filters.team) params.append('team', state.filters.team);
 
         const response = await fetch(`${API_BASE}/outages?${params}`);
         if (!response.ok) throw new Error('Failed to fetch outages');
@@ -68,6 +91,23 @@ async function fetchOutages() {
     }
 }
 
+// fetchTeams fills the team filter and the owning team choice on the create
+// form. Listing teams is best effort: without them only the default filter
+// is offered.
+async function fetchTeams() {
+    try {
+        const response = await fetch(`${API_BASE}/teams`);
+        if (!response.ok) throw new Error('Failed to fetch teams');
+        const data = await response.json();
+        const options = (data.teams || []).map(team =>
+            `<option value="${escapeHtml(team.name)}">${escapeHtml(team.name)}</option>`).join('');
+        document.getElementById('filter-team').insertAdjacentHTML('beforeend', options);
+        document.getElementById('new-owning-team').insertAdjacentHTML('beforeend', options);
+    } catch (error) {
+        console.warn('Error fetching teams:', error);
+    }
+}
+
 async function fetchOutageById(id) {
     try {
         const response = await fetch(`${API_BASE}/outages/${id}`);
@@ -79,6 +119,63 @@ async function fetchOutageById(id) {
     }
 }
 
+// Rendering failures are not shown: the note stays visible as plain text
+async function fetchRenderedNote(id) {
+    try {
+        const response = await fetch(`${API_BASE}/notes/${id}/rendered`);
+        if (!response.ok) return null;
+        return await response.json();
+    } catch (error) {
+        return null;
+    }
+}
+
+async function fetchTimeline(id) {
+    try {
+        const response = await fetch(`${API_BASE}/outages/${id}/timeline`);
+        if (!response.ok) throw new Error('Failed to fetch timeline');
+        const data = await response.json();
+        return data.events || [];
+    } catch (error) {
+        showMessage('Error fetching timeline: ' + error.message, 'error');
+        return [];
+    }
+}
+
+// Responder failures are not shown: the timeline still records assignments
+async function fetchResponders(id) {
+    try {
+        const response = await fetch(`${API_BASE}/outages/${id}/responders`);
+        if (!response.ok) return [];
+        const data = await response.json();
+        return data.current || [];
+    } catch (error) {
+        return [];
+    }
+}
+
+// Presence failures are not shown: the outage works without them
+async function sendPresence(outageId, activity) {
+    try {
+        const response = await fetch(`${API_BASE}/outages/${outageId}/presence`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ activity })
+        });
+        if (!response.ok) return null;
+        return await response.json();
+    } catch (error) {
+        return null;
+    }
+}
+
+function leavePresence(outageId) {
+    fetch(`${API_BASE}/outages/${outageId}/presence`, {
+        method: 'DELETE',
+        keepalive: true
+    }).catch(() => {});
+}
+
 async function createOutage(data) {
     try {
         const response = await fetch(`${API_BASE}/outages`, {
@@ -87,10 +184,7 @@ async function createOutage(data) {
             body: JSON.stringify(data)
         });
 
-        if (!response.ok) {
-            const error = await response.json();
-            throw new Error(error.error || 'Failed to create outage');
-        }
+        if (!response.ok) throw await responseError(response, 'Failed to create outage');
 
         return await response.json();
     } catch (error) {
@@ -107,10 +201,7 @@ async function updateOutage(id, data) {
             body: JSON.stringify(data)
         });
 
-        if (!response.ok) {
-            const error = await response.json();
-            throw new Error(error.error || 'Failed to update outage');
-        }
+        if (!response.ok) throw await responseError(response, 'Failed to update outage');
 
         return await response.json();
     } catch (error) {
@@ -119,18 +210,18 @@ async function updateOutage(id, data) {
     }
 }
 
-async function addNote(outageId, content, format = 'plaintext', author = 'Anonymous') {
+// The server records the signed-in user as the note's author
+async function addNote(outageId, content, format = 'plaintext', parentNoteId = null) {
     try {
+        const body = { content, format };
+        if (parentNoteId) body.parent_note_id = parentNoteId;
         const response = await fetch(`${API_BASE}/outages/${outageId}/notes`, {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ content, format, author })
+            body: JSON.stringify(body)
         });
 
-        if (!response.ok) {
-            const error = await response.json();
-            throw new Error(error.error || 'Failed to add note');
-        }
+        if (!response.ok) throw await responseError(response, 'Failed to add note');
 
         return await response.json();
     } catch (error) {
@@ -147,10 +238,7 @@ async function addTag(outageId, key, value) {
             body: JSON.stringify({ key, value })
         });
 
-        if (!response.ok) {
-            const error = await response.json();
-            throw new Error(error.error || 'Failed to add tag');
-        }
+        if (!response.ok) throw await responseError(response, 'Failed to add tag');
 
         return await response.json();
     } catch (error) {
@@ -164,6 +252,7 @@ async function searchByTag(key, value) {
         const params = new URLSearchParams();
         if (key) params.append('key', key);
         if (value) params.append('value', value);
+        if (state.filters.team && state.filters.team !== 'all') params.append('team', state.filters.team);
 
         const response = await fetch(`${API_BASE}/tags/search?${params}`);
         if (!response.ok) throw new Error('Failed to search by tag');
@@ -196,6 +285,7 @@ function renderOutagesList(outages) {
             <div class="meta">
                 <span class="badge status-${outage.status}">${outage.status}</span>
                 <span class="badge severity-${outage.severity}">${outage.severity}</span>
+                ${outage.owning_team ? `<span class="badge team">${escapeHtml(outage.owning_team)}</span>` : ''}
                 <span class="timestamp">Created ${formatRelativeTime(outage.created_at)}</span>
             </div>
             ${outage.description ? `<div class="description">${escapeHtml(truncate(outage.description, 150))}</div>` : ''}
@@ -208,7 +298,7 @@ function renderOutagesList(outages) {
     `).join('');
 }
 
-function renderOutageDetail(outage) {
+function renderOutageDetail(outage, timeline, responders) {
     const detailContainer = document.getElementById('outage-detail');
 
     detailContainer.innerHTML = `
@@ -218,7 +308,10 @@ function renderOutageDetail(outage) {
                 <div class="detail-meta">
                     <span class="badge status-${outage.status}">${outage.status}</span>
                     <span class="badge severity-${outage.severity}">${outage.severity}</span>
+                    ${outage.owning_team ? `<span class="badge team" title="Owning team">${escapeHtml(outage.owning_team)}</span>` : ''}
                 </div>
+                ${renderResponders(responders)}
+                <div class="presence-list" id="presence-container"></div>
                 <div class="timestamp">
                     <div>Created: ${formatDate(outage.created_at)}</div>
                     <div>Updated: ${formatDate(outage.updated_at)}</div>
@@ -230,6 +323,7 @@ function renderOutageDetail(outage) {
                     <select id="update-status-select">
                         <option value="open" ${outage.status === 'open' ? 'selected' : ''}>Open</option>
                         <option value="investigating" ${outage.status === 'investigating' ? 'selected' : ''}>Investigating</option>
+                        <option value="mitigated" ${outage.status === 'mitigated' ? 'selected' : ''}>Mitigated</option>
                         <option value="resolved" ${outage.status === 'resolved' ? 'selected' : ''}>Resolved</option>
                         <option value="closed" ${outage.status === 'closed' ? 'selected' : ''}>Closed</option>
                     </select>
@@ -265,12 +359,16 @@ function renderOutageDetail(outage) {
                     ${renderNotes(outage.notes)}
                 </div>
                 <div class="add-note-form">
-                    <textarea id="note-content" placeholder="Add troubleshooting notes..."></textarea>
+                    <div id="note-reply-to" class="note-reply-to" style="display: none;">
+                        <span>Replying to a note</span>
+                        <button class="btn-secondary" onclick="cancelReply()">Cancel</button>
+                    </div>
+                    <textarea id="note-content" placeholder="Add troubleshooting notes..." oninput="setPresenceActivity('working')"></textarea>
                     <div class="form-row">
-                        <input type="text" id="note-author" placeholder="Your name (optional)" value="Anonymous">
                         <select id="note-format">
                             <option value="plaintext">Plain Text</option>
                             <option value="markdown">Markdown</option>
+                            <option value="log">Log / Stack Trace</option>
                         </select>
                         <button class="btn-primary" onclick="submitAddNote()">Add Note</button>
                     </div>
@@ -285,8 +383,16 @@ function renderOutageDetail(outage) {
                     </div>
                 </div>
             ` : ''}
+
+            <div class="section">
+                <h3>Timeline</h3>
+                <ol class="timeline">
+                    ${renderTimeline(timeline)}
+                </ol>
+            </div>
         </div>
     `;
+    renderMarkdownNotes();
 }
 
 function renderTags(tags) {
@@ -304,22 +410,124 @@ function renderTags(tags) {
     `).join('');
 }
 
+// Notes are shown as threads: top-level notes newest first, each followed by
+// its replies oldest first. A reply whose parent is not listed stands alone.
 function renderNotes(notes) {
     if (!notes || notes.length === 0) {
         return '<div class="empty-state" style="padding: 20px;">No notes yet</div>';
     }
 
-    return notes.sort((a, b) => new Date(b.created_at) - new Date(a.created_at))
-        .map(note => `
+    const ids = new Set(notes.map(note => note.id));
+    const replies = {};
+    for (const note of notes) {
+        if (note.parent_note_id && ids.has(note.parent_note_id)) {
+            (replies[note.parent_note_id] ||= []).push(note);
+        }
+    }
+
+    return notes.filter(note => !note.parent_note_id || !ids.has(note.parent_note_id))
+        .sort((a, b) => new Date(b.created_at) - new Date(a.created_at))
+        .map(note => {
+            const thread = (replies[note.id] || [])
+                .sort((a, b) => new Date(a.created_at) - new Date(b.created_at));
+            return `
         <div class="note">
+            ${renderNoteBody(note)}
+            ${thread.length > 0 ? `
+                <div class="note-replies">
+                    ${thread.map(reply => `<div class="note note-reply">${renderNoteBody(reply)}</div>`).join('')}
+                </div>
+            ` : ''}
+        </div>
+    `;
+        }).join('');
+}
+
+function renderNoteBody(note) {
+    const threadId = note.parent_note_id || note.id;
+    return `
             <div class="note-header">
                 <span><strong>${escapeHtml(note.author || 'Anonymous')}</strong></span>
-                <span>${formatRelativeTime(note.created_at)}</span>
+                <span>
+                    ${formatRelativeTime(note.created_at)}
+                    <button class="note-reply-btn" onclick="replyToNote('${threadId}')">Reply</button>
+                </span>
             </div>
-            <div class="note-content ${note.format === 'markdown' ? 'markdown' : ''}">
-                ${note.format === 'markdown' ? renderMarkdown(note.content) : escapeHtml(note.content)}
-            </div>
-        </div>
+            ${renderNoteContent(note)}`;
+}
+
+function renderNoteContent(note) {
+    // Logs keep their whitespace exactly, so nothing may sit between the
+    // tags and the content
+    if (note.format === 'log') {
+        return `<pre class="note-content note-log"><code>${escapeHtml(note.content)}</code></pre>`;
+    }
+    // Markdown is shown as text until the server has rendered it
+    if (note.format === 'markdown') {
+        const key = `${note.id}@${note.updated_at}`;
+        const rendered = state.renderedNotes[key];
+        if (rendered !== undefined) {
+            return `<div class="note-content markdown rendered">${rendered}</div>`;
+        }
+        return `<div class="note-content markdown" data-render-note="${note.id}" data-render-key="${escapeHtml(key)}">${escapeHtml(note.content)}</div>`;
+    }
+    return `
+            <div class="note-content">
+                ${escapeHtml(note.content)}
+            </div>`;
+}
+
+// renderMarkdownNotes swaps markdown notes shown as text for their sanitized
+// HTML, remembering it so live refreshes do not fetch it again
+async function renderMarkdownNotes() {
+    const pending = document.querySelectorAll('[data-render-note]');
+    await Promise.all(Array.from(pending).map(async element => {
+        const rendered = await fetchRenderedNote(element.dataset.renderNote);
+        if (!rendered) return;
+        state.renderedNotes[element.dataset.renderKey] = rendered.html;
+        element.innerHTML = rendered.html;
+        element.classList.add('rendered');
+        element.removeAttribute('data-render-note');
+    }));
+}
+
+function renderTimeline(events) {
+    if (!events || events.length === 0) {
+        return '<li class="empty-state" style="padding: 20px;">Nothing has happened yet</li>';
+    }
+
+    return events.map(event => `
+        <li class="timeline-event timeline-${escapeHtml(event.type)}">
+            <span class="timestamp" title="${formatDate(event.timestamp)}">${formatRelativeTime(event.timestamp)}</span>
+            <span>${escapeHtml(event.summary)}</span>
+            ${event.actor ? `<span class="timeline-actor">${escapeHtml(event.actor)}</span>` : ''}
+        </li>
+    `).join('');
+}
+
+function renderResponders(responders) {
+    if (!responders || responders.length === 0) return '';
+
+    return `<div class="presence-list"><span class="presence-label">Responders:</span>` + responders.map(r => `
+        <span class="responder" title="${escapeHtml(r.role.replace(/_/g, ' '))} since ${formatDate(r.assigned_at)}">
+            ${escapeHtml(r.role.replace(/_/g, ' '))}: ${escapeHtml(r.responder)}
+        </span>
+    `).join('') + '</div>';
+}
+
+function renderPresence(present) {
+    const container = document.getElementById('presence-container');
+    if (!container) return;
+
+    if (!present || present.length === 0) {
+        container.innerHTML = '';
+        return;
+    }
+
+    container.innerHTML = `<span class="presence-label">Engaged now:</span>` + present.map(p => `
+        <span class="presence presence-${p.activity}" title="${escapeHtml(p.user)} · seen ${formatRelativeTime(p.last_seen)}">
+            ${p.activity === 'working' ? '✍️' : '👀'} ${escapeHtml(p.name || p.user)}
+        </span>
     `).join('');
 }
 
@@ -346,6 +554,8 @@ function showView(viewName) {
     document.querySelectorAll('.view').forEach(view => view.classList.remove('active'));
     document.querySelectorAll('.nav-btn').forEach(btn => btn.classList.remove('active'));
 
+    if (viewName !== 'detail') stopPresence();
+
     document.getElementById(`${viewName}-view`).classList.add('active');
     const navBtn = document.getElementById(`nav-${viewName}`);
     if (navBtn) navBtn.classList.add('active');
@@ -355,13 +565,91 @@ function showView(viewName) {
 
 async function showOutageDetail(id) {
     state.currentOutageId = id;
+    state.replyToNoteId = null;
     showView('detail');
 
     document.getElementById('outage-detail').innerHTML = '<div class="loading">Loading outage details...</div>';
 
-    const outage = await fetchOutageById(id);
+    const [outage, timeline, responders] = await Promise.all([fetchOutageById(id), fetchTimeline(id), fetchResponders(id)]);
     if (outage) {
-        renderOutageDetail(outage);
+        renderOutageDetail(outage, timeline, responders);
+        startPresence(id);
+    }
+}
+
+// Presence heartbeats let other responders see who is on an outage
+function startPresence(outageId) {
+    if (state.presence.outageId !== outageId) {
+        stopPresence();
+        state.presence.outageId = outageId;
+        state.presence.timer = setInterval(sendPresenceHeartbeat, PRESENCE_INTERVAL_MS);
+    }
+    sendPresenceHeartbeat();
+}
+
+function stopPresence() {
+    if (state.presence.timer) clearInterval(state.presence.timer);
+    if (state.presence.outageId) leavePresence(state.presence.outageId);
+    state.presence = { outageId: null, activity: 'viewing', timer: null };
+}
+
+function setPresenceActivity(activity) {
+    if (state.presence.activity === activity) return;
+    state.presence.activity = activity;
+    sendPresenceHeartbeat();
+}
+
+async function sendPresenceHeartbeat() {
+    const outageId = state.presence.outageId;
+    if (!outageId) return;
+
+    const data = await sendPresence(outageId, state.presence.activity);
+    if (data && state.presence.outageId === outageId) {
+        renderPresence(data.present);
+    }
+}
+
+// Live updates: the server pushes outage changes over a server-sent event
+// stream. Missed events are not replayed, so the view is refetched after the
+// browser reconnects.
+const LIVE_EVENT_TYPES = ['outage.created', 'outage.status_changed', 'outage.resolved', 'note.added', 'alert.added'];
+
+function startEventStream() {
+    if (!window.EventSource) return;
+
+    const source = new EventSource(`${API_BASE}/events/stream`);
+    let connected = false;
+    source.onopen = () => {
+        if (connected) refreshLiveView();
+        connected = true;
+    };
+    LIVE_EVENT_TYPES.forEach(type => source.addEventListener(type, handleLiveEvent));
+}
+
+function handleLiveEvent(e) {
+    const event = JSON.parse(e.data);
+    if (state.currentView === 'list' || event.outage.id === state.currentOutageId) {
+        refreshLiveView();
+    }
+}
+
+// refreshLiveView redraws the current view in place, keeping any note being
+// written and leaving tag search results alone
+async function refreshLiveView() {
+    if (state.currentView === 'list') {
+        if (document.getElementById('tag-key').value || document.getElementById('tag-value').value) return;
+        const outages = await fetchOutages();
+        if (state.currentView === 'list') renderOutagesList(filterOutages(outages));
+    } else if (state.currentView === 'detail' && state.currentOutageId) {
+        const id = state.currentOutageId;
+        const [outage, timeline, responders] = await Promise.all([fetchOutageById(id), fetchTimeline(id), fetchResponders(id)]);
+        if (!outage || state.currentView !== 'detail' || state.currentOutageId !== id) return;
+
+        const draftFields = ['note-content', 'note-format'];
+        const draft = draftFields.map(field => document.getElementById(field).value);
+        renderOutageDetail(outage, timeline, responders);
+        draftFields.forEach((field, i) => { document.getElementById(field).value = draft[i]; });
+        sendPresenceHeartbeat();
     }
 }
 
@@ -408,25 +696,39 @@ async function updateOutageStatus() {
 }
 
 async function submitAddNote() {
-    const content = document.getElementById('note-content').value.trim();
-    const author = document.getElementById('note-author').value.trim() || 'Anonymous';
+    const raw = document.getElementById('note-content').value;
     const format = document.getElementById('note-format').value;
+    // Logs are sent untouched so indentation and trailing lines survive
+    const content = format === 'log' ? raw : raw.trim();
 
-    if (!content) {
+    if (!content.trim()) {
         showMessage('Please enter note content', 'error');
         return;
     }
 
     try {
-        await addNote(state.currentOutageId, content, format, author);
+        await addNote(state.currentOutageId, content, format, state.replyToNoteId);
         showMessage('Note added successfully', 'success');
         document.getElementById('note-content').value = '';
+        state.replyToNoteId = null;
+        state.presence.activity = 'viewing';
         await refreshCurrentView();
     } catch (error) {
         // Error already shown by addNote
     }
 }
 
+function replyToNote(noteId) {
+    state.replyToNoteId = noteId;
+    document.getElementById('note-reply-to').style.display = 'flex';
+    document.getElementById('note-content').focus();
+}
+
+function cancelReply() {
+    state.replyToNoteId = null;
+    document.getElementById('note-reply-to').style.display = 'none';
+}
+
 function toggleAddTagForm() {
     const form = document.getElementById('add-tag-form');
     form.style.display = form.style.display === 'none' ? 'block' : 'none';
@@ -545,6 +847,7 @@ async function handleCreateForm(e) {
     const description = document.getElementById('new-description').value.trim();
     const status = document.getElementById('new-status').value;
     const severity = document.getElementById('new-severity').value;
+    const owning_team = document.getElementById('new-owning-team').value;
 
     if (!title) {
         showMessage('Please enter a title', 'error');
@@ -552,7 +855,7 @@ async function handleCreateForm(e) {
     }
 
     try {
-        const outage = await createOutage({ title, description, status, severity });
+        const outage = await createOutage({ title, description, status, severity, owning_team });
         showMessage('Outage created successfully', 'success');
 
         // Reset form
@@ -592,13 +895,15 @@ async function handleTagSearch() {
 function handleFilterChange() {
     state.filters.status = document.getElementById('filter-status').value;
     state.filters.severity = document.getElementById('filter-severity').value;
+    state.filters.team = document.getElementById('filter-team').value;
     loadOutages();
 }
 
 function clearFilters() {
-    state.filters = { status: '', severity: '', search: '' };
+    state.filters = { status: '', severity: '', search: '', team: '' };
     document.getElementById('filter-status').value = '';
     document.getElementById('filter-severity').value = '';
+    document.getElementById('filter-team').value = '';
     document.getElementById('search-input').value = '';
     document.getElementById('tag-key').value = '';
     document.getElementById('tag-value').value = '';
@@ -618,15 +923,6 @@ function truncate(text, length) {
     return text.substring(0, length) + '...';
 }
 
-function renderMarkdown(text) {
-    // Basic markdown rendering (could be enhanced with a library)
-    return escapeHtml(text)
-        .replace(/\*\*(.+?)\*\*/g, '<strong>$1</strong>')
-        .replace(/\*(.+?)\*/g, '<em>$1</em>')
-        .replace(/`(.+?)`/g, '<code>$1</code>')
-        .replace(/\n/g, '<br>');
-}
-
 // Initialize app
 document.addEventListener('DOMContentLoaded', () => {
     // Navigation
@@ -653,6 +949,7 @@ document.addEventListener('DOMContentLoaded', () => {
     document.getElementById('tag-search-btn').addEventListener('click', handleTagSearch);
     document.getElementById('filter-status').addEventListener('change', handleFilterChange);
     document.getElementById('filter-severity').addEventListener('change', handleFilterChange);
+    document.getElementById('filter-team').addEventListener('change', handleFilterChange);
     document.getElementById('clear-filters').addEventListener('click', clearFilters);
 
     // Create form
@@ -673,6 +970,10 @@ document.addEventListener('DOMContentLoaded', () => {
         }
     });
 
+    window.addEventListener('beforeunload', stopPresence);
+
     // Load initial data
+    fetchTeams();
     loadOutages();
+    startEventStream();
 });
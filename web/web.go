@@ -0,0 +1,22 @@
+// Package web embeds the single-page web UI so the outalator binary can
+// serve it without any files on disk.
+package web
+
+import (
+	"embed"
+	"net/http"
+)
+
+//go:embed static
+var files embed.FS
+
+// Handler serves the UI: index.html at / and its scripts and styles under
+// /static/
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /static/", http.FileServerFS(files))
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, files, "static/index.html")
+	})
+	return mux
+}
@@ -0,0 +1,34 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantCode int
+		wantType string
+	}{
+		{"/", http.StatusOK, "text/html"},
+		{"/static/js/app.js", http.StatusOK, "text/javascript"},
+		{"/static/css/styles.css", http.StatusOK, "text/css"},
+		{"/static/missing.js", http.StatusNotFound, ""},
+		{"/outages", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantCode)
+			}
+			if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %s", ct, tt.wantType)
+			}
+		})
+	}
+}